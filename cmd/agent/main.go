@@ -11,6 +11,7 @@ import (
 	"github.com/dushixiang/pika/pkg/agent/config"
 	"github.com/dushixiang/pika/pkg/agent/service"
 	"github.com/dushixiang/pika/pkg/agent/updater"
+	"github.com/dushixiang/pika/pkg/logging"
 	"github.com/spf13/cobra"
 )
 
@@ -121,6 +122,14 @@ var configShowCmd = &cobra.Command{
 	},
 }
 
+// configEncryptCmd 加密配置命令
+var configEncryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "加密配置文件中的敏感字段",
+	Long:  `使用本机密钥加密配置文件中的 API Key 等敏感字段，加密后的配置只能在本机解密。已加密的配置会重新加密`,
+	Run:   encryptConfig,
+}
+
 // updateCmd 更新命令
 var updateCmd = &cobra.Command{
 	Use:   "update",
@@ -178,6 +187,7 @@ func init() {
 	// 配置命令
 	configCmd.AddCommand(configInitCmd)
 	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configEncryptCmd)
 	rootCmd.AddCommand(configCmd)
 
 	if configPath == "" {
@@ -200,6 +210,13 @@ func runAgent(cmd *cobra.Command, args []string) {
 		log.Fatalf("❌ 加载配置失败: %v", err)
 	}
 
+	// 初始化日志
+	logManager := logging.Default()
+	logManager.SetOutput(logging.NewOutput(cfg.Log.Format))
+	if err := logManager.ApplyLevels(logging.Levels{Default: cfg.Log.Level, Modules: cfg.Log.Modules}); err != nil {
+		log.Fatalf("❌ 日志配置错误: %v", err)
+	}
+
 	// 创建服务管理器
 	mgr, err := service.NewServiceManager(cfg)
 	if err != nil {
@@ -364,6 +381,23 @@ func initConfig(cmd *cobra.Command, args []string) {
 	log.Println("   请编辑配置文件，设置 server.api_key 等必要参数")
 }
 
+// encryptConfig 加密配置文件中的敏感字段
+func encryptConfig(cmd *cobra.Command, args []string) {
+	// 加载配置（已加密的字段会先解密）
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		log.Fatalf("❌ 加载配置失败: %v", err)
+	}
+
+	cfg.EnableSecretEncryption()
+	if err := cfg.Save(cfg.Path); err != nil {
+		log.Fatalf("❌ 保存配置文件失败: %v", err)
+	}
+
+	log.Printf("✅ 配置文件敏感字段已加密: %s", cfg.Path)
+	log.Println("   加密后的配置只能在本机使用，如需迁移请重新填写明文后再次执行本命令")
+}
+
 // updateAgent 检查并更新
 func updateAgent(cmd *cobra.Command, args []string) {
 	// 加载配置
@@ -547,6 +581,7 @@ func showInfo(cmd *cobra.Command, args []string) {
 	fmt.Println("🔧 基本配置:")
 	fmt.Printf("   配置文件路径: %s\n", configPath)
 	fmt.Printf("   探针名称: %s\n", cfg.Agent.Name)
+	fmt.Printf("   运行模式: %s\n", cfg.Agent.Profile)
 	fmt.Printf("   当前版本: %s\n", service.GetVersion())
 	fmt.Println()
 
@@ -554,12 +589,17 @@ func showInfo(cmd *cobra.Command, args []string) {
 	fmt.Println("🌐 服务端配置:")
 	fmt.Printf("   服务端地址: %s\n", cfg.Server.Endpoint)
 	fmt.Printf("   API Token: %s\n", maskToken(cfg.Server.APIKey))
+	if cfg.SecretsEncrypted() {
+		fmt.Printf("   敏感字段: 已加密\n")
+	} else {
+		fmt.Printf("   敏感字段: 未加密（可执行 agent config encrypt 加密）\n")
+	}
 	fmt.Println()
 
 	// 采集器配置
 	fmt.Println("📊 采集器配置:")
-	fmt.Printf("   采集间隔: %d 秒\n", cfg.Collector.Interval)
-	fmt.Printf("   心跳间隔: %d 秒\n", cfg.Collector.HeartbeatInterval)
+	fmt.Printf("   采集间隔: %s\n", cfg.GetCollectorInterval())
+	fmt.Printf("   心跳间隔: %s\n", cfg.GetHeartbeatInterval())
 	if len(cfg.Collector.NetworkExclude) > 0 {
 		fmt.Printf("   网卡过滤规则: %v\n", cfg.Collector.NetworkExclude)
 	}
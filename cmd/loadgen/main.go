@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/dushixiang/pika/pkg/loadgen"
+	"github.com/spf13/cobra"
+)
+
+var cfg loadgen.Config
+
+var reportInterval time.Duration
+
+// rootCmd 根命令
+var rootCmd = &cobra.Command{
+	Use:   "loadgen",
+	Short: "Pika 服务端压测工具",
+	Long: `模拟大量虚拟探针按真实协议连接服务端并持续上报指标，用于在扩容前评估数据写入、批量处理和告警判断的性能。
+
+虚拟探针会注册为真实探针（ID 以 --prefix 开头），压测结束后可在探针管理中删除。
+请勿对生产环境使用。`,
+	Run: runLoadgen,
+}
+
+func init() {
+	flags := rootCmd.Flags()
+	flags.StringVarP(&cfg.Endpoint, "endpoint", "e", "http://localhost:8080", "服务端地址")
+	flags.StringVarP(&cfg.APIKey, "api-key", "k", "", "API Key")
+	flags.BoolVar(&cfg.InsecureSkipVerify, "insecure", false, "跳过 TLS 证书验证")
+	flags.IntVarP(&cfg.Agents, "agents", "n", 100, "虚拟探针数量")
+	flags.StringVar(&cfg.IDPrefix, "prefix", "loadgen", "虚拟探针 ID 前缀")
+	flags.DurationVarP(&cfg.Interval, "interval", "i", 5*time.Second, "指标上报间隔")
+	flags.DurationVar(&cfg.HeartbeatInterval, "heartbeat", 30*time.Second, "心跳间隔")
+	flags.DurationVar(&cfg.RampUp, "ramp-up", 30*time.Second, "全部虚拟探针上线所用的时间")
+	flags.DurationVarP(&cfg.Duration, "duration", "d", 0, "压测持续时间，为 0 时一直运行直到 Ctrl+C")
+	flags.Float64Var(&cfg.SpikeProbability, "spike-probability", 0, "每个上报周期触发负载尖峰的概率（0~1），用于验证告警判断")
+	flags.IntVar(&cfg.SpikeCycles, "spike-cycles", 6, "负载尖峰持续的上报周期数")
+	flags.Float64Var(&cfg.DisconnectProbability, "disconnect-probability", 0, "每个上报周期主动断线的概率（0~1），用于验证断线重连")
+	flags.Int64Var(&cfg.Seed, "seed", 0, "随机数种子，为 0 时使用当前时间")
+	flags.DurationVar(&reportInterval, "report", 10*time.Second, "统计输出间隔")
+	_ = rootCmd.MarkFlagRequired("api-key")
+}
+
+func main() {
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func runLoadgen(cmd *cobra.Command, args []string) {
+	runner, err := loadgen.NewRunner(cfg)
+	if err != nil {
+		log.Fatalf("❌ 配置错误: %v", err)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	log.Printf("🚀 开始压测: %s，%d 个虚拟探针，上报间隔 %v，%v 内全部上线", cfg.Endpoint, cfg.Agents, cfg.Interval, cfg.RampUp)
+
+	stats := runner.Stats()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := runner.Run(ctx); err != nil {
+			log.Printf("❌ 压测失败: %v", err)
+		}
+	}()
+
+	ticker := time.NewTicker(reportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			log.Println(stats.Snapshot())
+		case <-done:
+			snap := stats.Snapshot()
+			fmt.Println()
+			log.Println("✅ 压测结束")
+			log.Println(snap)
+			log.Printf("   注册耗时: p50 %s, p99 %s, max %s", snap.RegisterP50, snap.RegisterP99, snap.RegisterMax)
+			log.Printf("   平均消息速率: %.0f/s", float64(snap.Messages)/snap.Elapsed.Seconds())
+			return
+		}
+	}
+}
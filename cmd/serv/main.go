@@ -1,7 +1,15 @@
 package main
 
-import "github.com/dushixiang/pika/internal"
+import (
+	"flag"
+
+	"github.com/dushixiang/pika/internal"
+)
 
 func main() {
-	internal.Run("./config.yaml")
+	configPath := flag.String("config", "./config.yaml", "配置文件路径")
+	demo := flag.Bool("demo", false, "演示模式：使用内存数据库并预置模拟的探针、指标、服务监控和告警，无需部署探针")
+	flag.Parse()
+
+	internal.Run(*configPath, *demo)
 }
@@ -6,6 +6,7 @@ import (
 	"html/template"
 	"log"
 	"net/http"
+	"os"
 	"strings"
 	"time"
 
@@ -13,7 +14,9 @@ import (
 	"github.com/dushixiang/pika/internal/handler"
 	"github.com/dushixiang/pika/internal/models"
 	"github.com/dushixiang/pika/internal/scheduler"
+	"github.com/dushixiang/pika/pkg/logging"
 	"github.com/dushixiang/pika/pkg/replace"
+	"github.com/dushixiang/pika/pkg/tracing"
 	"github.com/dushixiang/pika/pkg/version"
 	"github.com/dushixiang/pika/web"
 	"github.com/google/uuid"
@@ -22,33 +25,92 @@ import (
 	"github.com/go-errors/errors"
 	"github.com/go-orz/orz"
 	"github.com/go-playground/validator/v10"
+	"github.com/gorilla/websocket"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"gorm.io/gorm"
 )
 
-func Run(configPath string) {
-	err := orz.Quick(configPath, setup)
+// Run 启动服务端，demo 为 true 时以演示模式运行：忽略配置文件，使用内存数据库并预置模拟数据
+func Run(configPath string, demo bool) {
+	logManager := logging.Default()
+
+	// 配置文件可选，容器部署时可以只使用环境变量
+	var options []orz.Option
+	if demo {
+		log.Printf("以演示模式启动，忽略配置文件 %s，数据保存在内存中，退出后丢失", configPath)
+	} else if _, err := os.Stat(configPath); err == nil {
+		options = append(options, orz.WithConfig(configPath))
+	} else {
+		log.Printf("未找到配置文件 %s，使用默认配置和环境变量", configPath)
+	}
+	if overrides := config.FrameworkEnvOverrides(); len(overrides) > 0 {
+		options = append(options, orz.WithConfigMap(overrides))
+	}
+	if demo {
+		// 演示模式始终使用内存数据库，避免误连生产库
+		options = append(options, orz.WithConfigMap(demoFrameworkConfig()))
+	}
+	options = append(options,
+		withLogManager(logManager),
+		orz.WithDatabase(),
+		orz.WithHTTP(),
+		orz.WithApplication(orz.NewSimpleApp(func(app *orz.App) error {
+			return setup(app, logManager, configPath, demo)
+		})),
+	)
+
+	framework, err := orz.NewFramework(options...)
 	if err != nil {
 		log.Fatal(err)
 	}
+	if err := framework.Run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// withLogManager 使用配置文件中的日志输出初始化日志管理器，日志级别交由管理器按模块控制
+func withLogManager(logManager *logging.Manager) orz.Option {
+	return func(f *orz.Framework) error {
+		logConfig := f.App().GetConfig().Log
+		level, err := zapcore.ParseLevel(logConfig.Level)
+		if err != nil {
+			level = zapcore.InfoLevel
+		}
+
+		// 输出本身不过滤级别，由日志管理器按模块判断
+		logConfig.Level = "debug"
+		output := orz.NewLoggerFromConfig(logConfig).Core()
+		logManager.SetOutput(output)
+		if err := logManager.SetLevel("", level.String()); err != nil {
+			return err
+		}
+
+		f.App().SetLogger(logManager.Logger(""))
+		return nil
+	}
 }
 
-func setup(app *orz.App) error {
+func setup(app *orz.App, logManager *logging.Manager, configPath string, demo bool) error {
 	// 数据库迁移
 	if err := autoMigrate(app.GetDatabase()); err != nil {
 		return err
 	}
 
 	// 读取应用配置
-	var appConfig config.AppConfig
-	_config := app.GetConfig()
-	if _config != nil {
-		if err := _config.App.Unmarshal(&appConfig); err != nil {
-			app.Logger().Error("读取配置失败", zap.Error(err))
+	appConfig, err := loadAppConfig(app)
+	if err != nil {
+		app.Logger().Error("读取配置失败", zap.Error(err))
+		return err
+	}
+
+	if demo {
+		if err := applyDemoConfig(appConfig); err != nil {
 			return err
 		}
+		app.Logger().Warn("演示模式已启用", zap.String("username", demoUsername), zap.String("password", demoPassword))
 	}
 
 	// 设置默认值
@@ -60,8 +122,20 @@ func setup(app *orz.App) error {
 		appConfig.JWT.ExpiresHours = 168 // 7天
 	}
 
+	// 启用链路追踪
+	if err := setupTracing(app, appConfig.Tracing); err != nil {
+		app.Logger().Error("初始化链路追踪失败", zap.Error(err))
+		return err
+	}
+
+	// 应用模块日志级别
+	if err := logManager.ApplyLevels(logging.Levels{Modules: appConfig.LogLevels}); err != nil {
+		app.Logger().Error("读取模块日志级别配置失败", zap.Error(err))
+		return err
+	}
+
 	// 初始化应用组件
-	components, err := InitializeApp(app.Logger(), app.GetDatabase(), &appConfig)
+	components, err := InitializeApp(app.Logger(), logManager, app.GetDatabase(), appConfig)
 	if err != nil {
 		return err
 	}
@@ -78,15 +152,43 @@ func setup(app *orz.App) error {
 		// 不返回错误，继续启动
 	}
 
+	// 服务重启后，上次运行时未结束的终端会话已经中断
+	if err := components.TerminalService.CloseStaleSessions(ctx); err != nil {
+		app.Logger().Error("清理中断的终端会话失败", zap.Error(err))
+		// 不返回错误，继续启动
+	}
+	if err := components.CommandService.CloseStaleExecutions(ctx); err != nil {
+		app.Logger().Error("清理中断的远程命令执行记录失败", zap.Error(err))
+		// 不返回错误，继续启动
+	}
+	if err := components.ProcessService.CloseStaleKills(ctx); err != nil {
+		app.Logger().Error("清理中断的结束进程记录失败", zap.Error(err))
+		// 不返回错误，继续启动
+	}
+	if err := components.DockerService.CloseStaleOperations(ctx); err != nil {
+		app.Logger().Error("清理中断的容器操作记录失败", zap.Error(err))
+		// 不返回错误，继续启动
+	}
+
 	// 启动WebSocket管理器
 	go components.WSManager.Run(ctx)
 
+	// 启动指标批量写入任务
+	go components.MetricService.StartMetricFlush(ctx)
+
 	// 启动数据清理任务
 	go components.MetricService.StartCleanupTask(ctx)
 
 	// 启动聚合下采样任务
 	go components.MetricService.StartAggregationTask(ctx)
 
+	// 启动 remote_write 指标转发（未启用时直接返回）
+	go components.MetricService.StartRemoteWrite(ctx)
+	go components.MetricService.StartInfluxDBExport(ctx)
+
+	// 启动指标归档任务（未启用时直接返回）
+	go components.MetricService.StartArchive(ctx)
+
 	// 启动指标监控任务（用于告警检测）
 	go startMetricsMonitoring(ctx, components, app.Logger())
 
@@ -99,23 +201,61 @@ func setup(app *orz.App) error {
 	// 启动监控统计计算任务
 	go startMonitorStatsCalculation(ctx, components, app.Logger())
 
+	// 启动探针连接会话清理任务
+	go startAgentSessionCleanup(ctx, components, app.Logger())
+
 	// 启动 DDNS 定时任务
 	go components.DDNSService.Run(ctx)
 
+	// 启动漏洞匹配定时任务
+	go components.VulnerabilityService.Run(ctx)
+
+	// 启动 SQLite 维护任务（完整性检查、增量回收、WAL 检查点）
+	go components.DatabaseService.Run(ctx)
+
+	// 启动通知发送队列
+	go components.NotificationQueueService.Run(ctx)
+
+	// 启动计划重启任务
+	go components.PowerService.Run(ctx)
+
+	if demo {
+		// 预置演示数据并模拟探针上报
+		go components.DemoService.Run(ctx)
+	} else {
+		// 监听 SIGHUP 重新加载配置
+		go watchConfigReload(ctx, app, configPath, components)
+	}
+
 	// 设置API
-	setupApi(app, components)
+	setupApi(app, components, appConfig)
 
 	return nil
 }
 
-func setupApi(app *orz.App, components *AppComponents) {
+// indexPage index.html 模板数据
+type indexPage struct {
+	*models.SystemConfig
+	BasePath string
+}
+
+func setupApi(app *orz.App, components *AppComponents, appConfig *config.AppConfig) {
 	logger := app.Logger()
 	e := app.GetEcho()
+	httpConfig := appConfig.HTTP
 
+	setupHTTPMiddleware(e, httpConfig)
 	e.Use(middleware.Recover())
+	e.Use(TracingMiddleware())
 	e.Use(ErrorHandler(logger))
 
-	indexTemplate, err := template.New("index").Parse(web.IndexHtml())
+	var basePath string
+	if httpConfig != nil {
+		basePath = normalizeBasePath(httpConfig.BasePath)
+	}
+	// 前端资源使用相对路径构建，部署在子路径下时改写为带前缀的绝对路径，保证前端路由的深层页面也能加载资源
+	indexHtml := strings.ReplaceAll(web.IndexHtml(), `"./assets/`, `"{{.BasePath}}/assets/`)
+	indexTemplate, err := template.New("index").Parse(indexHtml)
 	if err != nil {
 		logger.Fatal("failed to parse index.html", zap.Error(err))
 	}
@@ -130,6 +270,10 @@ func setupApi(app *orz.App, components *AppComponents) {
 			if strings.HasPrefix(c.Request().RequestURI, "/ws") {
 				return true
 			}
+			// 不处理 Prometheus 指标接口
+			if c.Request().URL.Path == "/metrics" {
+				return true
+			}
 			return false
 		},
 		Index:      "index.html",
@@ -147,7 +291,7 @@ func setupApi(app *orz.App, components *AppComponents) {
 				}
 
 				var buf bytes.Buffer
-				err = indexTemplate.Execute(&buf, systemConfig)
+				err = indexTemplate.Execute(&buf, indexPage{SystemConfig: systemConfig, BasePath: basePath})
 				if err != nil {
 					return file, err
 				}
@@ -179,27 +323,45 @@ func setupApi(app *orz.App, components *AppComponents) {
 		publicApi.GET("/agent/version", components.AgentHandler.GetAgentVersion)
 		publicApi.GET("/agent/downloads/:filename", components.AgentHandler.DownloadAgent)
 		publicApi.GET("/agent/install.sh", components.AgentHandler.GetInstallScript)
+
+		// 监控项状态和可用率徽章（只支持匿名可见的监控项）- 用于嵌入 README、Wiki 等页面
+		publicApi.GET("/monitors/:id/badge/status", components.MonitorHandler.GetStatusBadge)
+		publicApi.GET("/monitors/:id/badge/uptime", components.MonitorHandler.GetUptimeBadge)
+
+		// 被动心跳监控的推送地址
+		publicApi.GET("/push/:token", components.PushHandler.Push)
+		publicApi.POST("/push/:token", components.PushHandler.Push)
 	}
 
 	// 公开接口（支持可选认证）- 已登录返回全部数据，未登录只返回公开数据
 	publicApiWithOptionalAuth := e.Group("/api")
 	publicApiWithOptionalAuth.Use(OptionalJWTAuthMiddleware(components.AccountHandler))
 	{
+		// 健康检查（公开访问，支持可选认证）- 已登录返回数据库错误详情
+		publicApiWithOptionalAuth.GET("/health", components.HealthHandler.Health)
+
+		// 全局概览（公开访问，支持可选认证）- 用于首页仪表盘
+		publicApiWithOptionalAuth.GET("/overview", components.OverviewHandler.GetOverview)
+
 		// 探针信息（公开访问，支持可选认证）- 用于公共展示页面
 		publicApiWithOptionalAuth.GET("/agents", components.AgentHandler.GetAgents)
 		publicApiWithOptionalAuth.GET("/agents/tags", components.AgentHandler.GetTags)
 		publicApiWithOptionalAuth.GET("/agents/:id", components.AgentHandler.Get)
 		publicApiWithOptionalAuth.GET("/agents/:id/metrics", components.AgentHandler.GetMetrics)
 		publicApiWithOptionalAuth.GET("/agents/:id/metrics/latest", components.AgentHandler.GetLatestMetrics)
+		publicApiWithOptionalAuth.GET("/agents/:id/availability", components.AgentHandler.GetAvailability)
 		publicApiWithOptionalAuth.GET("/agents/:id/network-interfaces", components.AgentHandler.GetAvailableNetworkInterfaces)
 		// 指标配置（公开访问）- 用于获取时间范围选项等配置
 		publicApiWithOptionalAuth.GET("/metrics-config", components.PropertyHandler.GetMetricsConfig)
 
 		// 监控统计数据（公开访问，支持可选认证）- 用于公共展示页面
 		publicApiWithOptionalAuth.GET("/monitors", components.MonitorHandler.GetMonitors)
+		publicApiWithOptionalAuth.GET("/monitor-groups", components.MonitorHandler.GetGroups)
 		publicApiWithOptionalAuth.GET("/monitors/:id/stats", components.MonitorHandler.GetStatsByID)
 		publicApiWithOptionalAuth.GET("/monitors/:id/agents", components.MonitorHandler.GetAgentStatsByID)
 		publicApiWithOptionalAuth.GET("/monitors/:id/history", components.MonitorHandler.GetHistoryByID)
+		publicApiWithOptionalAuth.GET("/monitors/:id/certs", components.MonitorHandler.GetCertsByID)
+		publicApiWithOptionalAuth.GET("/monitors/:id/traces", components.MonitorHandler.GetTracesByID)
 
 		// Logo（公开访问）- 用于公共页面只获取 Logo
 		publicApiWithOptionalAuth.GET("/logo", components.PropertyHandler.GetLogo)
@@ -208,6 +370,20 @@ func setupApi(app *orz.App, components *AppComponents) {
 	// WebSocket 路由（探针连接）
 	e.GET("/ws/agent", components.AgentHandler.HandleWebSocket)
 
+	// WebSocket 路由（远程终端、进程列表、日志查看）- 浏览器无法携带认证请求头，使用管理员接口签发的一次性凭证认证
+	e.GET("/ws/terminal", components.TerminalHandler.Connect)
+	e.GET("/ws/processes", components.ProcessHandler.Connect)
+	e.GET("/ws/logs", components.LogTailHandler.Connect)
+
+	// Prometheus 指标导出（配置中启用后通过 Bearer Token 访问）
+	e.GET("/metrics", components.PrometheusHandler.Metrics)
+
+	// 探针状态挂件（配置中启用后通过令牌访问，独立跨域和限流）- 用于嵌入外部网站
+	widgetApi := e.Group("/api/widget", widgetMiddlewares(appConfig.Widget)...)
+	{
+		widgetApi.GET("/agents/:id", components.WidgetHandler.GetAgent)
+	}
+
 	// 管理员 API 路由（需要认证）
 	adminApi := e.Group("/api/admin")
 	adminApi.Use(JWTAuthMiddleware(components.AccountHandler))
@@ -235,15 +411,59 @@ func setupApi(app *orz.App, components *AppComponents) {
 		adminApi.GET("/agents", components.AgentHandler.Paging)
 		adminApi.GET("/agents/statistics", components.AgentHandler.GetStatistics)
 		adminApi.GET("/agents/tags", components.AgentHandler.GetTags)
+		adminApi.GET("/agents/export", components.AgentHandler.ExportInventory)
 		adminApi.GET("/agents/:id", components.AgentHandler.GetForAdmin)
 		adminApi.PUT("/agents/:id", components.AgentHandler.UpdateInfo)
 		adminApi.DELETE("/agents/:id", components.AgentHandler.Delete)
 		adminApi.POST("/agents/:id/command", components.AgentHandler.SendCommand)
+		adminApi.GET("/agents/:id/logs/tail", components.LogTailHandler.Tail)
+		adminApi.POST("/agents/:id/logs/ticket", components.LogTailHandler.CreateTicket)
+		adminApi.POST("/agents/:id/terminal", components.TerminalHandler.CreateTicket)
+		adminApi.GET("/terminal-sessions", components.TerminalHandler.Paging)
+		adminApi.GET("/terminal-sessions/:id/recording", components.TerminalHandler.DownloadRecording)
+		adminApi.GET("/remote-commands", components.CommandHandler.Options)
+		adminApi.POST("/agents/:id/remote-commands", components.CommandHandler.Execute)
+		adminApi.GET("/remote-command-executions", components.CommandHandler.Paging)
+		adminApi.POST("/agents/:id/processes/ticket", components.ProcessHandler.CreateTicket)
+		adminApi.POST("/agents/:id/processes/kill", components.ProcessHandler.Kill)
+		adminApi.GET("/process-kills", components.ProcessHandler.PagingKills)
+		adminApi.GET("/agents/:id/docker/containers", components.DockerHandler.GetContainers)
+		adminApi.POST("/agents/:id/docker/containers/refresh", components.DockerHandler.RefreshContainers)
+		adminApi.POST("/agents/:id/docker/actions", components.DockerHandler.Execute)
+		adminApi.GET("/docker-operations", components.DockerHandler.PagingOperations)
+		adminApi.GET("/agents/:id/data/export", components.AgentDataHandler.Export)
+		adminApi.GET("/agents/:id/metrics/export", components.AgentHandler.ExportMetrics)
+		adminApi.POST("/agents/:id/data/purge", components.AgentDataHandler.Purge)
+
+		// Ping 目标（管理员功能）
+		adminApi.GET("/agents/:id/ping-targets", components.PingHandler.List)
+		adminApi.POST("/agents/:id/ping-targets", components.PingHandler.Create)
+		adminApi.PUT("/agents/:id/ping-targets/:targetId", components.PingHandler.Update)
+		adminApi.DELETE("/agents/:id/ping-targets/:targetId", components.PingHandler.Delete)
+
+		// 探针间延迟网格（管理员功能）
+		adminApi.GET("/latency-mesh", components.PingHandler.GetMeshConfig)
+		adminApi.PUT("/latency-mesh", components.PingHandler.SaveMeshConfig)
+		adminApi.GET("/latency-mesh/matrix", components.PingHandler.GetMeshMatrix)
+
+		// 软件清单（管理员访问）
+		adminApi.GET("/agents/:id/software", components.SoftwareHandler.GetAgentSoftware)
+		adminApi.GET("/agents/:id/container-images", components.SoftwareHandler.GetAgentContainerImages)
+		adminApi.GET("/software", components.SoftwareHandler.Search)
+
+		// 安全漏洞发现（管理员访问）
+		adminApi.GET("/security-findings", components.VulnerabilityHandler.ListFindings)
+		adminApi.POST("/security-findings/scan", components.VulnerabilityHandler.Scan)
 
 		// VPS审计结果（管理员访问）
 		adminApi.GET("/agents/:id/audit/result", components.AgentHandler.GetAuditResult)
 		adminApi.GET("/agents/:id/audit/results", components.AgentHandler.ListAuditResults)
 
+		// 探针分组（以标签作为分组）
+		adminApi.GET("/groups", components.GroupHandler.List)
+		adminApi.GET("/groups/:tag/metrics", components.GroupHandler.GetMetrics)
+		adminApi.GET("/groups/:tag/metrics/history", components.GroupHandler.GetMetricsHistory)
+
 		// 防篡改管理（管理员功能）
 		adminApi.GET("/agents/:id/tamper/config", components.TamperHandler.GetTamperConfig)
 		adminApi.PUT("/agents/:id/tamper/config", components.TamperHandler.UpdateTamperConfig)
@@ -256,10 +476,35 @@ func setupApi(app *orz.App, components *AppComponents) {
 
 		// 通知渠道测试（从数据库读取配置测试）
 		adminApi.POST("/notification-channels/:type/test", components.PropertyHandler.TestNotificationChannel)
+		adminApi.POST("/notifications/channels/:id/test", components.PropertyHandler.SendSampleNotification)
+
+		// 通知发送队列（死信查询与重试）
+		adminApi.GET("/notification-jobs", components.NotificationJobHandler.Paging)
+		adminApi.DELETE("/notification-jobs/dead", components.NotificationJobHandler.ClearDead)
+		adminApi.POST("/notification-jobs/:id/retry", components.NotificationJobHandler.Retry)
+		adminApi.DELETE("/notification-jobs/:id", components.NotificationJobHandler.Delete)
+		adminApi.GET("/notification-logs", components.NotificationJobHandler.PagingLogs)
 
 		// 告警记录查询
 		adminApi.GET("/alert-records", components.AlertHandler.ListAlertRecords)
+		adminApi.GET("/alert-records/export", components.AlertHandler.ExportAlertRecords)
 		adminApi.DELETE("/alert-records", components.AlertHandler.ClearAlertRecords)
+		adminApi.GET("/alert-records/:id/correlation", components.AlertHandler.GetAlertCorrelation)
+		adminApi.GET("/alert-records/:id/remediations", components.RemediationHandler.ListByAlertRecord)
+
+		// 告警修复动作
+		adminApi.GET("/remediations", components.RemediationHandler.Paging)
+		adminApi.POST("/remediations/:id/approve", components.RemediationHandler.Approve)
+		adminApi.POST("/remediations/:id/reject", components.RemediationHandler.Reject)
+
+		// 电源操作（计划重启、网络唤醒）
+		adminApi.GET("/agents/:id/power-tasks", components.PowerHandler.List)
+		adminApi.POST("/agents/:id/reboot", components.PowerHandler.Reboot)
+		adminApi.POST("/agents/:id/wol", components.PowerHandler.WakeOnLAN)
+		adminApi.GET("/agents/:id/disk-usage", components.DiskUsageHandler.Get)
+		adminApi.POST("/agents/:id/disk-usage", components.DiskUsageHandler.Scan)
+		adminApi.POST("/power-tasks/:id/confirm", components.PowerHandler.Confirm)
+		adminApi.POST("/power-tasks/:id/cancel", components.PowerHandler.Cancel)
 
 		// 服务监控配置
 		adminApi.GET("/monitors", components.MonitorHandler.List)
@@ -267,6 +512,9 @@ func setupApi(app *orz.App, components *AppComponents) {
 		adminApi.GET("/monitors/:id", components.MonitorHandler.Get)
 		adminApi.PUT("/monitors/:id", components.MonitorHandler.Update)
 		adminApi.DELETE("/monitors/:id", components.MonitorHandler.Delete)
+		adminApi.GET("/monitors/:id/records", components.MonitorHandler.ListRecords)
+		adminApi.GET("/monitors/:id/records/export", components.MonitorHandler.ExportRecords)
+		adminApi.GET("/monitors/:id/sla", components.MonitorHandler.GetSLAReport)
 
 		// DNS Provider 管理
 		adminApi.GET("/dns-providers", components.DNSProviderHandler.GetAll)
@@ -282,6 +530,15 @@ func setupApi(app *orz.App, components *AppComponents) {
 		adminApi.POST("/ddns/:id/enable", components.DDNSHandler.Enable)
 		adminApi.POST("/ddns/:id/disable", components.DDNSHandler.Disable)
 		adminApi.GET("/ddns/:id/records", components.DDNSHandler.GetRecords)
+
+		// 日志级别（管理员功能）
+		adminApi.GET("/logging/levels", components.LoggingHandler.GetLevels)
+		adminApi.PUT("/logging/levels", components.LoggingHandler.SetLevel)
+		adminApi.DELETE("/logging/levels/:module", components.LoggingHandler.ResetLevel)
+		adminApi.PUT("/agents/:id/logging/levels", components.LoggingHandler.SetAgentLevel)
+
+		// GraphQL 查询
+		adminApi.POST("/graphql", components.GraphQLHandler.Query)
 	}
 
 	// OIDC 认证路由（如果启用）
@@ -297,6 +554,9 @@ func autoMigrate(database *gorm.DB) error {
 		&models.Agent{},
 		&models.ApiKey{},
 		&models.CPUMetric{},
+		&models.CPUCoreMetric{},
+		&models.CPUTimesMetric{},
+		&models.PressureMetric{},
 		&models.MemoryMetric{},
 		&models.DiskMetric{},
 		&models.NetworkMetric{},
@@ -304,6 +564,7 @@ func autoMigrate(database *gorm.DB) error {
 		&models.DiskIOMetric{},
 		&models.GPUMetric{},
 		&models.TemperatureMetric{},
+		&models.PingMetric{},
 		&models.HostMetric{},
 		&models.AuditResult{},
 		&models.Property{},
@@ -312,13 +573,32 @@ func autoMigrate(database *gorm.DB) error {
 		&models.MonitorMetric{},
 		&models.MonitorTask{},
 		&models.MonitorStats{},
+		&models.MonitorCert{},
+		&models.MonitorTrace{},
 		&models.TamperProtectConfig{},
 		&models.TamperEvent{},
 		&models.TamperAlert{},
 		&models.DDNSConfig{},
 		&models.DDNSRecord{},
+		&models.SoftwareInventory{},
+		&models.SecurityFinding{},
+		&models.ContainerImage{},
+		&models.RemediationRecord{},
+		&models.PowerTask{},
+		&models.PingTarget{},
+		&models.NotificationJob{},
+		&models.NotificationLog{},
+		&models.AgentSession{},
+		&models.TerminalSession{},
+		&models.TerminalRecordChunk{},
+		&models.CommandExecution{},
+		&models.ProcessKillRecord{},
+		&models.DockerOperation{},
 		// 聚合表
 		&models.AggregatedCPUMetricModel{},
+		&models.AggregatedCPUCoreMetricModel{},
+		&models.AggregatedCPUTimesMetricModel{},
+		&models.AggregatedPressureMetricModel{},
 		&models.AggregatedMemoryMetricModel{},
 		&models.AggregatedDiskMetricModel{},
 		&models.AggregatedNetworkMetricModel{},
@@ -370,6 +650,88 @@ func ErrorHandler(logger *zap.Logger) func(next echo.HandlerFunc) echo.HandlerFu
 	return a
 }
 
+// setupTracing 根据配置创建全局 Tracer，并为数据库操作注册追踪插件
+func setupTracing(app *orz.App, tracingConfig *config.TracingConfig) error {
+	if tracingConfig == nil || !tracingConfig.Enabled {
+		return nil
+	}
+
+	endpoint := tracingConfig.Endpoint
+	if endpoint == "" {
+		endpoint = os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	}
+	if endpoint == "" {
+		return errors.New("已启用链路追踪但未配置 Endpoint")
+	}
+
+	logger := app.Logger().Named("tracing")
+	tracer, err := tracing.New(tracing.Config{
+		ServiceName:    tracingConfig.ServiceName,
+		ServiceVersion: version.GetVersion(),
+		Endpoint:       endpoint,
+		Headers:        tracingConfig.Headers,
+		SampleRatio:    tracingConfig.SampleRatio,
+		OnError: func(err error) {
+			logger.Warn("导出追踪数据失败", zap.Error(err))
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := app.GetDatabase().Use(tracing.GormPlugin{}); err != nil {
+		return err
+	}
+	tracing.SetDefault(tracer)
+
+	logger.Info("链路追踪已启用", zap.String("endpoint", endpoint), zap.Float64("sampleRatio", tracingConfig.SampleRatio))
+	return nil
+}
+
+// TracingMiddleware 为接口请求创建 Server Span，注册在 ErrorHandler 之前以记录最终的响应状态码
+// WebSocket 连接持续时间很长，不记录整个连接，由各处理器按握手和消息单独记录
+func TracingMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if !tracing.Enabled() || !tracedRequest(req) {
+				return next(c)
+			}
+
+			ctx := tracing.Extract(req.Context(), req.Header)
+			ctx, span := tracing.Start(ctx, req.Method+" "+c.Path(),
+				tracing.WithKind(tracing.SpanKindServer),
+				tracing.WithAttributes(
+					tracing.String("http.request.method", req.Method),
+					tracing.String("http.route", c.Path()),
+					tracing.String("url.path", req.URL.Path),
+					tracing.String("client.address", c.RealIP()),
+				),
+			)
+			defer span.End()
+			c.SetRequest(req.WithContext(ctx))
+
+			err := next(c)
+
+			status := c.Response().Status
+			span.SetAttributes(tracing.Int("http.response.status_code", status))
+			if err != nil {
+				span.RecordError(err)
+			} else if status >= http.StatusInternalServerError {
+				span.SetStatus(tracing.StatusError, http.StatusText(status))
+			}
+			return err
+		}
+	}
+}
+
+// tracedRequest 判断请求是否记录 Server Span，静态资源和 WebSocket 升级请求不记录
+func tracedRequest(req *http.Request) bool {
+	if websocket.IsWebSocketUpgrade(req) {
+		return false
+	}
+	return strings.HasPrefix(req.URL.Path, "/api/") || req.URL.Path == "/metrics"
+}
+
 // startMetricsMonitoring 启动指标监控任务（用于告警检测）
 func startMetricsMonitoring(ctx context.Context, components *AppComponents, logger *zap.Logger) {
 	logger.Info("启动指标监控任务")
@@ -426,6 +788,58 @@ func startMetricsMonitoring(ctx context.Context, components *AppComponents, logg
 				if err := components.AlertService.CheckMetrics(ctx, agent.ID, cpuUsage, memoryUsage, diskUsage, networkSpeed); err != nil {
 					logger.Error("检查告警规则失败", zap.String("agentId", agent.ID), zap.Error(err))
 				}
+
+				// 检查 WireGuard 握手超时告警
+				if len(latest.WireGuard) > 0 {
+					if err := components.AlertService.CheckWireGuard(ctx, agent.ID, latest.WireGuard); err != nil {
+						logger.Error("检查WireGuard告警失败", zap.String("agentId", agent.ID), zap.Error(err))
+					}
+				}
+
+				// 检查网络挂载点告警
+				if len(latest.Mounts) > 0 {
+					if err := components.AlertService.CheckMounts(ctx, agent.ID, latest.Mounts); err != nil {
+						logger.Error("检查挂载点告警失败", zap.String("agentId", agent.ID), zap.Error(err))
+					}
+				}
+
+				// 检查非预期监听端口告警（上报了空列表时也需要检查，以便恢复已关闭端口的告警）
+				if latest.ListeningPorts != nil {
+					if err := components.AlertService.CheckListeningPorts(ctx, agent.ID, latest.ListeningPorts); err != nil {
+						logger.Error("检查监听端口告警失败", zap.String("agentId", agent.ID), zap.Error(err))
+					}
+				}
+
+				// 检查 CPU steal 告警
+				if latest.CPUTimes != nil {
+					if err := components.AlertService.CheckCPUSteal(ctx, agent.ID, latest.CPUTimes); err != nil {
+						logger.Error("检查CPU steal告警失败", zap.String("agentId", agent.ID), zap.Error(err))
+					}
+				}
+
+				// 检查时钟偏差告警
+				if latest.TimeSync != nil {
+					if err := components.AlertService.CheckTimeSync(ctx, agent.ID, latest.TimeSync); err != nil {
+						logger.Error("检查时钟偏差告警失败", zap.String("agentId", agent.ID), zap.Error(err))
+					}
+				}
+
+				// 检查 inode 和文件描述符告警
+				if latest.FileUsage != nil {
+					if err := components.AlertService.CheckFileUsage(ctx, agent.ID, latest.FileUsage); err != nil {
+						logger.Error("检查文件描述符和inode告警失败", zap.String("agentId", agent.ID), zap.Error(err))
+					}
+				}
+			}
+
+			// 恢复长时间未再出现的内核事件告警
+			if err := components.AlertService.CheckKernelEventRecovery(ctx); err != nil {
+				logger.Error("检查内核事件告警恢复失败", zap.Error(err))
+			}
+
+			// 检查分组告警
+			if err := components.AlertService.CheckGroupAlerts(ctx); err != nil {
+				logger.Error("检查分组告警失败", zap.Error(err))
 			}
 
 			// 检查监控相关告警（证书和服务下线）
@@ -465,6 +879,22 @@ func startMonitorStatsCalculation(ctx context.Context, components *AppComponents
 	}
 }
 
+// startAgentSessionCleanup 定期清理过期的探针连接会话
+func startAgentSessionCleanup(ctx context.Context, components *AppComponents, logger *zap.Logger) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("探针连接会话清理任务已停止")
+			return
+		case <-ticker.C:
+			components.AgentService.CleanupSessions(ctx)
+		}
+	}
+}
+
 // JWTAuthMiddleware JWT 认证中间件（必须登录）
 func JWTAuthMiddleware(accountHandler *handler.AccountHandler) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
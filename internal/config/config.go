@@ -7,6 +7,28 @@ type AppConfig struct {
 	OIDC   *OIDCConfig        `json:"OIDC"`   // OIDC配置（可选）
 	GitHub *GitHubOAuthConfig `json:"GitHub"` // GitHub OAuth配置（可选）
 	GeoIP  *GeoIPConfig       `json:"GeoIP"`  // GeoIP配置（可选）
+
+	// 加密数据库中敏感字段（如 HTTP 监控的认证密码）的密钥（可选），未配置时使用首次启动生成并保存在数据库中的密钥；
+	// 配置后不要修改，否则已保存的敏感字段无法解密，需要重新填写
+	SecretKey string `json:"SecretKey"`
+
+	WebSocket *WebSocketConfig `json:"WebSocket"` // 探针连接保活配置（可选）
+	HTTP      *HTTPConfig      `json:"HTTP"`      // 跨域、安全响应头和反向代理配置（可选）
+
+	Tracing     *TracingConfig     `json:"Tracing"`     // 链路追踪配置（可选）
+	Prometheus  *PrometheusConfig  `json:"Prometheus"`  // Prometheus 指标导出配置（可选）
+	Widget      *WidgetConfig      `json:"Widget"`      // 探针状态挂件接口配置（可选）
+	RemoteWrite *RemoteWriteConfig `json:"RemoteWrite"` // remote_write 指标转发配置（可选）
+	ClickHouse  *ClickHouseConfig  `json:"ClickHouse"`  // ClickHouse 指标存储配置（可选）
+	TimescaleDB *TimescaleDBConfig `json:"TimescaleDB"` // TimescaleDB 配置（可选），仅在 PostgreSQL 上生效
+	Archive     *ArchiveConfig     `json:"Archive"`     // 指标归档到对象存储配置（可选）
+	Terminal    *TerminalConfig    `json:"Terminal"`    // 远程终端配置（可选）
+	Commands    *CommandsConfig    `json:"Commands"`    // 远程命令配置（可选）
+	Processes   *ProcessesConfig   `json:"Processes"`   // 进程管理配置（可选）
+	LogTail     *LogTailConfig     `json:"LogTail"`     // 远程日志查看配置（可选）
+	Docker      *DockerConfig      `json:"Docker"`      // Docker 容器管理配置（可选）
+
+	LogLevels map[string]string `json:"LogLevels"` // 模块日志级别（可选），如 alert: debug，未配置的模块使用 log.level
 }
 
 // JWTConfig JWT配置
@@ -33,6 +55,145 @@ type GitHubOAuthConfig struct {
 	AllowedUsers []string `json:"AllowedUsers"` // 允许登录的GitHub用户名白名单（为空则允许所有用户）
 }
 
+// WebSocketConfig 探针 WebSocket 连接保活配置，单位为秒，未配置或为 0 时使用默认值
+type WebSocketConfig struct {
+	PingInterval int `json:"PingInterval"` // 服务端发送 Ping 的间隔，默认 30
+	PongTimeout  int `json:"PongTimeout"`  // 超过该时间未收到 Pong 或任何消息即断开连接，默认 60，需大于 PingInterval
+	WriteTimeout int `json:"WriteTimeout"` // 单条消息的写入超时，默认 10
+	// 禁用 permessage-deflate 压缩，默认在探针请求时启用；压缩能显著减少 JSON 指标的流量，但会增加服务端 CPU 开销
+	DisableCompression bool `json:"DisableCompression"`
+}
+
+// HTTPConfig Web 服务的跨域、安全响应头和反向代理配置
+type HTTPConfig struct {
+	// 子路径部署时的路径前缀（如 /pika），反向代理转发时保留或去掉该前缀均可
+	BasePath string `json:"BasePath"`
+	// 信任反向代理传入的 X-Forwarded-For、X-Forwarded-Host、X-Forwarded-Proto，用于获取客户端 IP 和生成安装脚本等对外地址，
+	// 仅在服务只能通过可信代理访问时开启，否则客户端可以伪造 IP 绕过审计和限流
+	TrustForwardedHeaders bool `json:"TrustForwardedHeaders"`
+
+	CORS *CORSConfig `json:"CORS"` // 跨域配置，未配置时不允许跨域访问接口
+
+	ContentSecurityPolicy string   `json:"ContentSecurityPolicy"` // Content-Security-Policy 响应头，为空时不设置
+	FrameAncestors        []string `json:"FrameAncestors"`        // 允许以 iframe 嵌入页面的来源（如 'self'、https://portal.example.com），为空时不限制
+	HSTSMaxAge            int      `json:"HSTSMaxAge"`            // HTTPS 请求的 Strict-Transport-Security 有效期（秒），为 0 时不设置
+
+	Headers map[string]string `json:"Headers"` // 附加到所有响应的自定义响应头
+}
+
+// CORSConfig 接口跨域配置
+type CORSConfig struct {
+	AllowOrigins     []string `json:"AllowOrigins"`     // 允许的来源，如 https://dashboard.example.com，* 表示任意来源
+	AllowCredentials bool     `json:"AllowCredentials"` // 是否允许携带 Cookie 等凭据，AllowOrigins 为 * 时不生效
+	MaxAge           int      `json:"MaxAge"`           // 预检请求结果的缓存时间（秒）
+}
+
+// TracingConfig 链路追踪配置，Span 以 OTLP/HTTP 协议导出
+type TracingConfig struct {
+	Enabled     bool              `json:"Enabled"`     // 是否启用链路追踪
+	Endpoint    string            `json:"Endpoint"`    // OTLP/HTTP 地址（如：http://otel-collector:4318），为空时使用 OTEL_EXPORTER_OTLP_ENDPOINT
+	Headers     map[string]string `json:"Headers"`     // 导出请求附加的请求头（如鉴权信息）
+	ServiceName string            `json:"ServiceName"` // 服务名，默认 pika
+	SampleRatio float64           `json:"SampleRatio"` // 采样率 0~1，默认 1
+}
+
+// PrometheusConfig Prometheus 指标导出配置，启用后通过 /metrics 接口导出所有探针的最新指标
+type PrometheusConfig struct {
+	Enabled bool   `json:"Enabled"` // 是否启用 /metrics 接口
+	Token   string `json:"Token"`   // 访问令牌，抓取时通过 Authorization: Bearer <Token> 请求头携带，未配置时接口不可用
+}
+
+// WidgetConfig 探针状态挂件接口配置，启用后可通过 /api/widget/agents/:id 获取单个公开探针的精简状态，
+// 用于嵌入外部网站；令牌会暴露在嵌入页面中，只能访问可见性为 public 的探针
+type WidgetConfig struct {
+	Enabled      bool     `json:"Enabled"`      // 是否启用挂件接口
+	Tokens       []string `json:"Tokens"`       // 访问令牌，通过 ?token=<Token> 或 Authorization: Bearer <Token> 携带，未配置时接口不可用
+	AllowOrigins []string `json:"AllowOrigins"` // 允许跨域调用的来源，默认 *
+	RateLimit    int      `json:"RateLimit"`    // 每个 IP 每分钟最多请求次数，默认 30
+	CacheSeconds int      `json:"CacheSeconds"` // 响应缓存时间（秒），默认 30
+}
+
+// RemoteWriteConfig remote_write 指标转发配置，启用后探针上报的指标会同时转发到外部时序库
+type RemoteWriteConfig struct {
+	Enabled        bool              `json:"Enabled"`        // 是否启用转发
+	URL            string            `json:"URL"`            // remote_write 地址（如：http://victoriametrics:8428/api/v1/write）
+	Headers        map[string]string `json:"Headers"`        // 请求附加的请求头（如鉴权信息、租户 ID）
+	ExternalLabels map[string]string `json:"ExternalLabels"` // 附加到所有样本的标签（如 cluster: prod）
+}
+
+// ClickHouseConfig ClickHouse 指标存储配置，启用后时序指标写入 ClickHouse，其他业务数据仍保存在 database 配置的数据库中
+type ClickHouseConfig struct {
+	Enabled  bool   `json:"Enabled"`  // 是否启用
+	URL      string `json:"URL"`      // HTTP 接口地址（如：http://clickhouse:8123）
+	Database string `json:"Database"` // 数据库名，默认 pika，不存在时自动创建
+	Username string `json:"Username"` // 用户名，默认 default
+	Password string `json:"Password"` // 密码
+}
+
+// TimescaleDBConfig TimescaleDB 配置，PostgreSQL 已安装 TimescaleDB 扩展时默认启用，指标表会转换为 hypertable
+type TimescaleDBConfig struct {
+	Disabled          bool `json:"Disabled"`          // 禁用 TimescaleDB，指标表保持普通表
+	CompressAfterDays int  `json:"CompressAfterDays"` // 超过多少天的数据压缩，默认 7
+}
+
+// ArchiveConfig 指标归档配置，每天将前一天的指标导出为 gzip 压缩的 CSV 上传到 S3 兼容的对象存储，
+// 启用后原始数据和预聚合数据在归档完成前不会被清理
+type ArchiveConfig struct {
+	Enabled   bool   `json:"Enabled"`   // 是否启用
+	Endpoint  string `json:"Endpoint"`  // 对象存储地址（如：https://s3.us-east-1.amazonaws.com、http://minio:9000）
+	Region    string `json:"Region"`    // 区域，默认 us-east-1
+	Bucket    string `json:"Bucket"`    // 存储桶
+	AccessKey string `json:"AccessKey"` // Access Key
+	SecretKey string `json:"SecretKey"` // Secret Key
+	Prefix    string `json:"Prefix"`    // 对象路径前缀，默认 pika/metrics
+	PathStyle bool   `json:"PathStyle"` // 使用路径风格访问（bucket 放在路径中），MinIO 等需要开启
+	Step      int    `json:"Step"`      // 归档数据的聚合粒度（秒），默认 60
+}
+
+// TerminalConfig 远程终端配置，启用后管理员可在后台打开探针的交互式终端（探针也需开启 terminal.enabled），
+// 会话的输入和输出全部录像保存，可在终端会话记录中下载回放
+type TerminalConfig struct {
+	Enabled       bool     `json:"Enabled"`       // 是否启用
+	AllowedUsers  []string `json:"AllowedUsers"`  // 允许使用终端和查看录像的用户名，为空时所有管理员都可以使用
+	IdleTimeout   int      `json:"IdleTimeout"`   // 空闲超时（秒），超过该时间没有键盘输入即结束会话，默认 600，最长 3600
+	RecordMaxSize int      `json:"RecordMaxSize"` // 单个会话录像的大小上限（MB），默认 10，超出后不再记录
+}
+
+// CommandsConfig 远程命令配置，启用后管理员可向探针下发白名单内的命令（探针也需开启 commands.enabled 并配置对应的白名单），
+// 每次执行都会保存执行记录
+type CommandsConfig struct {
+	Enabled      bool          `json:"Enabled"`      // 是否启用
+	AllowedUsers []string      `json:"AllowedUsers"` // 允许下发命令和查看执行记录的用户名，为空时所有管理员都可以使用
+	Allowed      []CommandRule `json:"Allowed"`      // 允许下发的命令，未配置的命令不能下发
+}
+
+// CommandRule 允许下发的远程命令
+type CommandRule struct {
+	Name    string   `json:"Name"`    // 命令名称: restart_service, clear_cache, run_script
+	Targets []string `json:"Targets"` // 允许的目标（服务名、目录或脚本名称），为空时不限制，仍需在探针的白名单中
+	Timeout int      `json:"Timeout"` // 超时时间上限（秒），默认 60，最长 3600
+}
+
+// ProcessesConfig 进程管理配置，所有管理员都可以在探针详情中查看实时进程列表，
+// 结束进程需要开启 AllowKill（探针也需开启 processes.allow_kill），每次结束进程都会保存记录
+type ProcessesConfig struct {
+	AllowKill    bool     `json:"AllowKill"`    // 是否允许结束进程
+	AllowedUsers []string `json:"AllowedUsers"` // 允许结束进程的用户名，为空时所有管理员都可以结束进程
+}
+
+// LogTailConfig 远程日志查看配置，探针需开启 log_tail.enabled 并配置白名单，
+// 服务端限制每秒转发给浏览器的行数和每个探针同时查看的会话数，超出速率的日志行会被丢弃
+type LogTailConfig struct {
+	LinesPerSecond int `json:"LinesPerSecond"` // 每个会话每秒最多转发的行数，默认 200
+	MaxSessions    int `json:"MaxSessions"`    // 每个探针同时查看日志的会话数上限，默认 3
+}
+
+// DockerConfig Docker 容器管理配置，探针开启 docker.enabled 后可在探针详情中查看容器列表，
+// 并启动、停止、重启容器，每次容器操作都会保存记录
+type DockerConfig struct {
+	AllowedUsers []string `json:"AllowedUsers"` // 允许操作容器的用户名，为空时所有管理员都可以操作
+}
+
 // GeoIPConfig GeoIP配置
 type GeoIPConfig struct {
 	Enabled    bool   `json:"Enabled"`    // 是否启用GeoIP查询
@@ -0,0 +1,403 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix 环境变量前缀
+const EnvPrefix = "PIKA_"
+
+// frameworkEnvKeys 日志、数据库和 Web 服务配置项，环境变量名为前缀加上大写的键名，点号替换为下划线，
+// 如 database.sqlite.path 对应 PIKA_DATABASE_SQLITE_PATH
+var frameworkEnvKeys = []string{
+	"log.level",
+	"log.filename",
+	"log.encode",
+	"log.console",
+	"log.max_size",
+	"log.max_age",
+	"log.compress",
+
+	"database.type",
+	"database.url",
+	"database.show_sql",
+	"database.sqlite.path",
+	"database.mysql.hostname",
+	"database.mysql.port",
+	"database.mysql.username",
+	"database.mysql.password",
+	"database.mysql.database",
+	"database.postgres.hostname",
+	"database.postgres.port",
+	"database.postgres.username",
+	"database.postgres.password",
+	"database.postgres.database",
+
+	"server.addr",
+	"server.tls.enabled",
+	"server.tls.auto",
+	"server.tls.cert",
+	"server.tls.key",
+	"server.ip_extractor",
+	"server.ip_trust_list",
+}
+
+// frameworkEnvListKeys 以逗号分隔的列表配置项
+var frameworkEnvListKeys = map[string]bool{
+	"server.ip_trust_list": true,
+}
+
+// FrameworkEnvOverrides 读取日志、数据库和 Web 服务配置的环境变量，返回可合并到配置文件之上的嵌套 map
+func FrameworkEnvOverrides() map[string]interface{} {
+	overrides := make(map[string]interface{})
+	for _, key := range frameworkEnvKeys {
+		value, ok := os.LookupEnv(EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_")))
+		if !ok {
+			continue
+		}
+
+		var v interface{} = value
+		if frameworkEnvListKeys[key] {
+			v = splitEnvList(value)
+		}
+
+		// 按层级写入嵌套 map
+		path := strings.Split(key, ".")
+		node := overrides
+		for _, name := range path[:len(path)-1] {
+			child, ok := node[name].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				node[name] = child
+			}
+			node = child
+		}
+		node[path[len(path)-1]] = v
+	}
+	return overrides
+}
+
+// ApplyEnv 使用环境变量覆盖应用配置，环境变量优先于配置文件
+//
+//	PIKA_JWT_SECRET, PIKA_JWT_EXPIRES_HOURS
+//	PIKA_SECRET_KEY             数据库中敏感字段的加密密钥
+//	PIKA_USERS                  用户名:bcrypt密码，多个用户以逗号分隔
+//	PIKA_OIDC_ENABLED, PIKA_OIDC_ISSUER, PIKA_OIDC_CLIENT_ID, PIKA_OIDC_CLIENT_SECRET, PIKA_OIDC_REDIRECT_URL
+//	PIKA_GITHUB_ENABLED, PIKA_GITHUB_CLIENT_ID, PIKA_GITHUB_CLIENT_SECRET, PIKA_GITHUB_REDIRECT_URL
+//	PIKA_GITHUB_ALLOWED_USERS   以逗号分隔
+//	PIKA_GEOIP_ENABLED, PIKA_GEOIP_DB_PATH, PIKA_GEOIP_DB_LANGUAGE
+//	PIKA_WEBSOCKET_PING_INTERVAL, PIKA_WEBSOCKET_PONG_TIMEOUT, PIKA_WEBSOCKET_WRITE_TIMEOUT  单位为秒
+//	PIKA_HTTP_BASE_PATH, PIKA_HTTP_TRUST_FORWARDED_HEADERS, PIKA_HTTP_CONTENT_SECURITY_POLICY, PIKA_HTTP_HSTS_MAX_AGE
+//	PIKA_HTTP_FRAME_ANCESTORS, PIKA_HTTP_CORS_ALLOW_ORIGINS  以逗号分隔
+//	PIKA_HTTP_CORS_ALLOW_CREDENTIALS, PIKA_HTTP_CORS_MAX_AGE
+//	PIKA_HTTP_HEADERS           名称=值，多个响应头以逗号分隔
+//	PIKA_LOG_LEVELS             模块=级别，多个模块以逗号分隔，如 alert=debug,ws=warn
+//	PIKA_TRACING_ENABLED, PIKA_TRACING_ENDPOINT, PIKA_TRACING_SERVICE_NAME, PIKA_TRACING_SAMPLE_RATIO
+//	PIKA_TRACING_HEADERS        名称=值，多个请求头以逗号分隔
+//	PIKA_PROMETHEUS_ENABLED, PIKA_PROMETHEUS_TOKEN
+//	PIKA_WIDGET_ENABLED, PIKA_WIDGET_RATE_LIMIT, PIKA_WIDGET_CACHE_SECONDS
+//	PIKA_WIDGET_TOKENS, PIKA_WIDGET_ALLOW_ORIGINS  多个以逗号分隔
+//	PIKA_REMOTE_WRITE_ENABLED, PIKA_REMOTE_WRITE_URL
+//	PIKA_REMOTE_WRITE_HEADERS, PIKA_REMOTE_WRITE_EXTERNAL_LABELS  名称=值，多个以逗号分隔
+//	PIKA_CLICKHOUSE_ENABLED, PIKA_CLICKHOUSE_URL, PIKA_CLICKHOUSE_DATABASE, PIKA_CLICKHOUSE_USERNAME, PIKA_CLICKHOUSE_PASSWORD
+//	PIKA_TIMESCALEDB_DISABLED, PIKA_TIMESCALEDB_COMPRESS_AFTER_DAYS
+//	PIKA_ARCHIVE_ENABLED, PIKA_ARCHIVE_ENDPOINT, PIKA_ARCHIVE_REGION, PIKA_ARCHIVE_BUCKET, PIKA_ARCHIVE_PREFIX
+//	PIKA_ARCHIVE_ACCESS_KEY, PIKA_ARCHIVE_SECRET_KEY, PIKA_ARCHIVE_PATH_STYLE, PIKA_ARCHIVE_STEP
+//	PIKA_TERMINAL_ENABLED, PIKA_TERMINAL_IDLE_TIMEOUT, PIKA_TERMINAL_RECORD_MAX_SIZE
+//	PIKA_TERMINAL_ALLOWED_USERS  以逗号分隔
+//	PIKA_COMMANDS_ENABLED, PIKA_COMMANDS_ALLOWED_USERS  以逗号分隔，允许下发的命令只能在配置文件中配置
+//	PIKA_PROCESSES_ALLOW_KILL, PIKA_PROCESSES_ALLOWED_USERS  以逗号分隔
+//	PIKA_LOG_TAIL_LINES_PER_SECOND, PIKA_LOG_TAIL_MAX_SESSIONS
+//	PIKA_DOCKER_ALLOWED_USERS  以逗号分隔
+func (c *AppConfig) ApplyEnv() error {
+	var r envReader
+
+	r.string("JWT_SECRET", &c.JWT.Secret)
+	r.int("JWT_EXPIRES_HOURS", &c.JWT.ExpiresHours)
+	r.string("SECRET_KEY", &c.SecretKey)
+	r.pairs("USERS", ":", &c.Users)
+
+	if hasEnvPrefix("OIDC_") {
+		if c.OIDC == nil {
+			c.OIDC = &OIDCConfig{}
+		}
+		r.bool("OIDC_ENABLED", &c.OIDC.Enabled)
+		r.string("OIDC_ISSUER", &c.OIDC.Issuer)
+		r.string("OIDC_CLIENT_ID", &c.OIDC.ClientID)
+		r.string("OIDC_CLIENT_SECRET", &c.OIDC.ClientSecret)
+		r.string("OIDC_REDIRECT_URL", &c.OIDC.RedirectURL)
+	}
+
+	if hasEnvPrefix("GITHUB_") {
+		if c.GitHub == nil {
+			c.GitHub = &GitHubOAuthConfig{}
+		}
+		r.bool("GITHUB_ENABLED", &c.GitHub.Enabled)
+		r.string("GITHUB_CLIENT_ID", &c.GitHub.ClientID)
+		r.string("GITHUB_CLIENT_SECRET", &c.GitHub.ClientSecret)
+		r.string("GITHUB_REDIRECT_URL", &c.GitHub.RedirectURL)
+		r.list("GITHUB_ALLOWED_USERS", &c.GitHub.AllowedUsers)
+	}
+
+	if hasEnvPrefix("GEOIP_") {
+		if c.GeoIP == nil {
+			c.GeoIP = &GeoIPConfig{}
+		}
+		r.bool("GEOIP_ENABLED", &c.GeoIP.Enabled)
+		r.string("GEOIP_DB_PATH", &c.GeoIP.DBPath)
+		r.string("GEOIP_DB_LANGUAGE", &c.GeoIP.DBLanguage)
+	}
+
+	if hasEnvPrefix("WEBSOCKET_") {
+		if c.WebSocket == nil {
+			c.WebSocket = &WebSocketConfig{}
+		}
+		r.int("WEBSOCKET_PING_INTERVAL", &c.WebSocket.PingInterval)
+		r.int("WEBSOCKET_PONG_TIMEOUT", &c.WebSocket.PongTimeout)
+		r.int("WEBSOCKET_WRITE_TIMEOUT", &c.WebSocket.WriteTimeout)
+		r.bool("WEBSOCKET_DISABLE_COMPRESSION", &c.WebSocket.DisableCompression)
+	}
+
+	r.pairs("LOG_LEVELS", "=", &c.LogLevels)
+
+	if hasEnvPrefix("TRACING_") {
+		if c.Tracing == nil {
+			c.Tracing = &TracingConfig{}
+		}
+		r.bool("TRACING_ENABLED", &c.Tracing.Enabled)
+		r.string("TRACING_ENDPOINT", &c.Tracing.Endpoint)
+		r.string("TRACING_SERVICE_NAME", &c.Tracing.ServiceName)
+		r.float("TRACING_SAMPLE_RATIO", &c.Tracing.SampleRatio)
+		r.pairs("TRACING_HEADERS", "=", &c.Tracing.Headers)
+	}
+
+	if hasEnvPrefix("PROMETHEUS_") {
+		if c.Prometheus == nil {
+			c.Prometheus = &PrometheusConfig{}
+		}
+		r.bool("PROMETHEUS_ENABLED", &c.Prometheus.Enabled)
+		r.string("PROMETHEUS_TOKEN", &c.Prometheus.Token)
+	}
+
+	if hasEnvPrefix("WIDGET_") {
+		if c.Widget == nil {
+			c.Widget = &WidgetConfig{}
+		}
+		r.bool("WIDGET_ENABLED", &c.Widget.Enabled)
+		r.list("WIDGET_TOKENS", &c.Widget.Tokens)
+		r.list("WIDGET_ALLOW_ORIGINS", &c.Widget.AllowOrigins)
+		r.int("WIDGET_RATE_LIMIT", &c.Widget.RateLimit)
+		r.int("WIDGET_CACHE_SECONDS", &c.Widget.CacheSeconds)
+	}
+
+	if hasEnvPrefix("REMOTE_WRITE_") {
+		if c.RemoteWrite == nil {
+			c.RemoteWrite = &RemoteWriteConfig{}
+		}
+		r.bool("REMOTE_WRITE_ENABLED", &c.RemoteWrite.Enabled)
+		r.string("REMOTE_WRITE_URL", &c.RemoteWrite.URL)
+		r.pairs("REMOTE_WRITE_HEADERS", "=", &c.RemoteWrite.Headers)
+		r.pairs("REMOTE_WRITE_EXTERNAL_LABELS", "=", &c.RemoteWrite.ExternalLabels)
+	}
+
+	if hasEnvPrefix("CLICKHOUSE_") {
+		if c.ClickHouse == nil {
+			c.ClickHouse = &ClickHouseConfig{}
+		}
+		r.bool("CLICKHOUSE_ENABLED", &c.ClickHouse.Enabled)
+		r.string("CLICKHOUSE_URL", &c.ClickHouse.URL)
+		r.string("CLICKHOUSE_DATABASE", &c.ClickHouse.Database)
+		r.string("CLICKHOUSE_USERNAME", &c.ClickHouse.Username)
+		r.string("CLICKHOUSE_PASSWORD", &c.ClickHouse.Password)
+	}
+
+	if hasEnvPrefix("HTTP_") {
+		if c.HTTP == nil {
+			c.HTTP = &HTTPConfig{}
+		}
+		r.string("HTTP_BASE_PATH", &c.HTTP.BasePath)
+		r.bool("HTTP_TRUST_FORWARDED_HEADERS", &c.HTTP.TrustForwardedHeaders)
+		r.string("HTTP_CONTENT_SECURITY_POLICY", &c.HTTP.ContentSecurityPolicy)
+		r.list("HTTP_FRAME_ANCESTORS", &c.HTTP.FrameAncestors)
+		r.int("HTTP_HSTS_MAX_AGE", &c.HTTP.HSTSMaxAge)
+		r.pairs("HTTP_HEADERS", "=", &c.HTTP.Headers)
+		if hasEnvPrefix("HTTP_CORS_") {
+			if c.HTTP.CORS == nil {
+				c.HTTP.CORS = &CORSConfig{}
+			}
+			r.list("HTTP_CORS_ALLOW_ORIGINS", &c.HTTP.CORS.AllowOrigins)
+			r.bool("HTTP_CORS_ALLOW_CREDENTIALS", &c.HTTP.CORS.AllowCredentials)
+			r.int("HTTP_CORS_MAX_AGE", &c.HTTP.CORS.MaxAge)
+		}
+	}
+
+	if hasEnvPrefix("TIMESCALEDB_") {
+		if c.TimescaleDB == nil {
+			c.TimescaleDB = &TimescaleDBConfig{}
+		}
+		r.bool("TIMESCALEDB_DISABLED", &c.TimescaleDB.Disabled)
+		r.int("TIMESCALEDB_COMPRESS_AFTER_DAYS", &c.TimescaleDB.CompressAfterDays)
+	}
+
+	if hasEnvPrefix("ARCHIVE_") {
+		if c.Archive == nil {
+			c.Archive = &ArchiveConfig{}
+		}
+		r.bool("ARCHIVE_ENABLED", &c.Archive.Enabled)
+		r.string("ARCHIVE_ENDPOINT", &c.Archive.Endpoint)
+		r.string("ARCHIVE_REGION", &c.Archive.Region)
+		r.string("ARCHIVE_BUCKET", &c.Archive.Bucket)
+		r.string("ARCHIVE_PREFIX", &c.Archive.Prefix)
+		r.string("ARCHIVE_ACCESS_KEY", &c.Archive.AccessKey)
+		r.string("ARCHIVE_SECRET_KEY", &c.Archive.SecretKey)
+		r.bool("ARCHIVE_PATH_STYLE", &c.Archive.PathStyle)
+		r.int("ARCHIVE_STEP", &c.Archive.Step)
+	}
+
+	if hasEnvPrefix("TERMINAL_") {
+		if c.Terminal == nil {
+			c.Terminal = &TerminalConfig{}
+		}
+		r.bool("TERMINAL_ENABLED", &c.Terminal.Enabled)
+		r.list("TERMINAL_ALLOWED_USERS", &c.Terminal.AllowedUsers)
+		r.int("TERMINAL_IDLE_TIMEOUT", &c.Terminal.IdleTimeout)
+		r.int("TERMINAL_RECORD_MAX_SIZE", &c.Terminal.RecordMaxSize)
+	}
+
+	if hasEnvPrefix("COMMANDS_") {
+		if c.Commands == nil {
+			c.Commands = &CommandsConfig{}
+		}
+		r.bool("COMMANDS_ENABLED", &c.Commands.Enabled)
+		r.list("COMMANDS_ALLOWED_USERS", &c.Commands.AllowedUsers)
+	}
+
+	if hasEnvPrefix("PROCESSES_") {
+		if c.Processes == nil {
+			c.Processes = &ProcessesConfig{}
+		}
+		r.bool("PROCESSES_ALLOW_KILL", &c.Processes.AllowKill)
+		r.list("PROCESSES_ALLOWED_USERS", &c.Processes.AllowedUsers)
+	}
+
+	if hasEnvPrefix("LOG_TAIL_") {
+		if c.LogTail == nil {
+			c.LogTail = &LogTailConfig{}
+		}
+		r.int("LOG_TAIL_LINES_PER_SECOND", &c.LogTail.LinesPerSecond)
+		r.int("LOG_TAIL_MAX_SESSIONS", &c.LogTail.MaxSessions)
+	}
+
+	if hasEnvPrefix("DOCKER_") {
+		if c.Docker == nil {
+			c.Docker = &DockerConfig{}
+		}
+		r.list("DOCKER_ALLOWED_USERS", &c.Docker.AllowedUsers)
+	}
+
+	return errors.Join(r.errs...)
+}
+
+// envReader 读取带前缀的环境变量，未设置的变量保持原值，解析失败的错误统一返回
+type envReader struct {
+	errs []error
+}
+
+func (r *envReader) lookup(key string) (string, bool) {
+	return os.LookupEnv(EnvPrefix + key)
+}
+
+func (r *envReader) string(key string, dst *string) {
+	if value, ok := r.lookup(key); ok {
+		*dst = value
+	}
+}
+
+func (r *envReader) bool(key string, dst *bool) {
+	value, ok := r.lookup(key)
+	if !ok {
+		return
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("环境变量 %s%s 不是有效的布尔值: %s", EnvPrefix, key, value))
+		return
+	}
+	*dst = b
+}
+
+func (r *envReader) int(key string, dst *int) {
+	value, ok := r.lookup(key)
+	if !ok {
+		return
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("环境变量 %s%s 不是有效的整数: %s", EnvPrefix, key, value))
+		return
+	}
+	*dst = n
+}
+
+func (r *envReader) float(key string, dst *float64) {
+	value, ok := r.lookup(key)
+	if !ok {
+		return
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("环境变量 %s%s 不是有效的数字: %s", EnvPrefix, key, value))
+		return
+	}
+	*dst = f
+}
+
+func (r *envReader) list(key string, dst *[]string) {
+	if value, ok := r.lookup(key); ok {
+		*dst = splitEnvList(value)
+	}
+}
+
+// pairs 解析以逗号分隔的键值对，键和值之间使用 sep 分隔
+func (r *envReader) pairs(key, sep string, dst *map[string]string) {
+	value, ok := r.lookup(key)
+	if !ok {
+		return
+	}
+	result := make(map[string]string)
+	for _, item := range splitEnvList(value) {
+		k, v, found := strings.Cut(item, sep)
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if !found || k == "" {
+			r.errs = append(r.errs, fmt.Errorf("环境变量 %s%s 格式错误: %s", EnvPrefix, key, item))
+			return
+		}
+		result[k] = v
+	}
+	*dst = result
+}
+
+// hasEnvPrefix 是否设置了指定前缀的环境变量
+func hasEnvPrefix(prefix string) bool {
+	for _, env := range os.Environ() {
+		if strings.HasPrefix(env, EnvPrefix+prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitEnvList 按逗号分隔并去除空白项
+func splitEnvList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
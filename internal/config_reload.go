@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/dushixiang/pika/internal/config"
+	"github.com/go-orz/orz"
+	"go.uber.org/zap"
+)
+
+// loadAppConfig 读取配置文件中的应用配置并使用环境变量覆盖
+func loadAppConfig(app *orz.App) (*config.AppConfig, error) {
+	var appConfig config.AppConfig
+	if _config := app.GetConfig(); _config != nil {
+		if err := _config.App.Unmarshal(&appConfig); err != nil {
+			return nil, err
+		}
+	}
+	if err := appConfig.ApplyEnv(); err != nil {
+		return nil, err
+	}
+	return &appConfig, nil
+}
+
+// watchConfigReload 收到 SIGHUP 后重新加载配置
+func watchConfigReload(ctx context.Context, app *orz.App, configPath string, components *AppComponents) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			reloadConfig(app, configPath, components)
+		}
+	}
+}
+
+// reloadConfig 重新读取配置文件和环境变量，只热更新 OIDC、GitHub 登录和 JWT 有效期，
+// 数据库、监听地址、JWT 密钥等其他配置需要重启后生效
+func reloadConfig(app *orz.App, configPath string, components *AppComponents) {
+	logger := app.Logger()
+	logger.Info("收到 SIGHUP，重新加载配置")
+
+	if _, err := os.Stat(configPath); err == nil {
+		if err := app.LoadConfigFromFile(configPath); err != nil {
+			logger.Error("重新读取配置文件失败，保持当前配置", zap.Error(err))
+			return
+		}
+	}
+
+	appConfig, err := loadAppConfig(app)
+	if err != nil {
+		logger.Error("解析配置失败，保持当前配置", zap.Error(err))
+		return
+	}
+
+	components.OIDCService.Reload(appConfig.OIDC)
+	components.GitHubOAuthService.Reload(appConfig.GitHub)
+	components.AccountService.SetTokenExpireHours(appConfig.JWT.ExpiresHours)
+
+	logger.Info("配置重新加载完成", zap.Int("jwtExpiresHours", appConfig.JWT.ExpiresHours))
+}
@@ -0,0 +1,40 @@
+package internal
+
+import (
+	"github.com/dushixiang/pika/internal/config"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	demoUsername = "demo"
+	demoPassword = "demo"
+)
+
+// demoFrameworkConfig 演示模式的框架配置，使用 SQLite 内存数据库（共享缓存，保证连接池内的连接看到同一个库）
+func demoFrameworkConfig() map[string]interface{} {
+	return map[string]interface{}{
+		"database": map[string]interface{}{
+			"enabled": true,
+			"type":    "sqlite",
+			"url":     "file:pika-demo?mode=memory&cache=shared",
+		},
+		"log": map[string]interface{}{
+			"filename": "",
+			"console":  true,
+		},
+	}
+}
+
+// applyDemoConfig 演示模式只保留一个固定的演示账号，关闭第三方登录和对外推送
+func applyDemoConfig(appConfig *config.AppConfig) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(demoPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	appConfig.Users = map[string]string{demoUsername: string(hash)}
+	appConfig.OIDC = nil
+	appConfig.GitHub = nil
+	appConfig.RemoteWrite = nil
+	appConfig.ClickHouse = nil
+	return nil
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/dushixiang/pika/internal/service"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type AgentDataHandler struct {
+	logger           *zap.Logger
+	agentDataService *service.AgentDataService
+}
+
+func NewAgentDataHandler(logger *zap.Logger, agentDataService *service.AgentDataService) *AgentDataHandler {
+	return &AgentDataHandler{
+		logger:           logger,
+		agentDataService: agentDataService,
+	}
+}
+
+// Export 导出探针的所有数据（zip 包）
+// GET /api/admin/agents/:id/data/export
+func (h *AgentDataHandler) Export(c echo.Context) error {
+	agentID := c.Param("id")
+	return h.sendArchive(c, agentID, func(f *os.File) error {
+		_, err := h.agentDataService.Export(c.Request().Context(), agentID, f)
+		return err
+	})
+}
+
+// Purge 导出并清除探针的所有数据，导出包作为响应返回
+// POST /api/admin/agents/:id/data/purge?confirm=<探针ID>
+func (h *AgentDataHandler) Purge(c echo.Context) error {
+	agentID := c.Param("id")
+	confirm := c.QueryParam("confirm")
+	return h.sendArchive(c, agentID, func(f *os.File) error {
+		_, err := h.agentDataService.ExportAndPurge(c.Request().Context(), agentID, confirm, f)
+		return err
+	})
+}
+
+// sendArchive 先将导出包完整写入临时文件再返回，避免导出中途失败时返回不完整的文件
+func (h *AgentDataHandler) sendArchive(c echo.Context, agentID string, write func(f *os.File) error) error {
+	f, err := os.CreateTemp("", "pika-agent-data-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if err := write(f); err != nil {
+		return err
+	}
+	if _, err := f.Seek(0, 0); err != nil {
+		return err
+	}
+
+	filename := fmt.Sprintf("agent-%s-%s.zip", agentID, time.Now().Format("20060102150405"))
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	return c.Stream(http.StatusOK, "application/zip", f)
+}
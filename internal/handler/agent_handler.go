@@ -2,6 +2,7 @@ package handler
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -16,6 +17,7 @@ import (
 	"github.com/dushixiang/pika/internal/service"
 	"github.com/dushixiang/pika/internal/utils"
 	ws "github.com/dushixiang/pika/internal/websocket"
+	"github.com/dushixiang/pika/pkg/tracing"
 	"github.com/dushixiang/pika/pkg/version"
 	"github.com/go-orz/orz"
 	"github.com/gorilla/websocket"
@@ -30,20 +32,36 @@ type AgentHandler struct {
 	monitorSvc    *service.MonitorService
 	tamperService *service.TamperService
 	ddnsService   *service.DDNSService
+	softwareSvc   *service.SoftwareService
+	logTailSvc    *service.LogTailService
+	terminalSvc   *service.TerminalService
+	processSvc    *service.ProcessService
+	pingSvc       *service.PingService
+	collectorSvc  *service.CollectorConfigService
+	alertSvc      *service.AlertService
 	wsManager     *ws.Manager
 	upgrader      websocket.Upgrader
 }
 
 func NewAgentHandler(logger *zap.Logger, agentService *service.AgentService, metricService *service.MetricService,
-	monitorService *service.MonitorService, tamperService *service.TamperService, ddnsService *service.DDNSService, wsManager *ws.Manager) *AgentHandler {
+	monitorService *service.MonitorService, tamperService *service.TamperService, ddnsService *service.DDNSService,
+	softwareService *service.SoftwareService, logTailService *service.LogTailService, terminalService *service.TerminalService,
+	processService *service.ProcessService, pingService *service.PingService, collectorConfigService *service.CollectorConfigService, alertService *service.AlertService, wsManager *ws.Manager) *AgentHandler {
 
 	h := &AgentHandler{
-		logger:        logger,
+		logger:        logger.Named("agent"),
 		agentService:  agentService,
 		metricService: metricService,
 		monitorSvc:    monitorService,
 		tamperService: tamperService,
 		ddnsService:   ddnsService,
+		softwareSvc:   softwareService,
+		logTailSvc:    logTailService,
+		terminalSvc:   terminalService,
+		processSvc:    processService,
+		pingSvc:       pingService,
+		collectorSvc:  collectorConfigService,
+		alertSvc:      alertService,
 		wsManager:     wsManager,
 	}
 
@@ -51,10 +69,13 @@ func NewAgentHandler(logger *zap.Logger, agentService *service.AgentService, met
 	h.upgrader = websocket.Upgrader{
 		ReadBufferSize:  1024 * 32,
 		WriteBufferSize: 1024 * 32,
+		// 仅在探针请求 permessage-deflate 时启用压缩，未开启压缩的探针不受影响
+		EnableCompression: wsManager.CompressionEnabled(),
 	}
 
 	// 设置WebSocket消息处理器
 	wsManager.SetMessageHandler(h.handleWebSocketMessage)
+	wsManager.SetBinaryHandler(h.handleBinaryMessage)
 
 	return h
 }
@@ -67,11 +88,20 @@ func (h *AgentHandler) HandleWebSocket(c echo.Context) error {
 		return err
 	}
 
+	// 握手和注册记录为一个 Server Span，连接建立后的消息由 handleWebSocketMessage 按条记录
+	// 使用独立的context,不依赖HTTP请求的context
+	ctx, span := tracing.Start(tracing.Extract(context.Background(), c.Request().Header), "ws.connect agent",
+		tracing.WithKind(tracing.SpanKindServer),
+		tracing.WithAttributes(tracing.String("client.address", c.RealIP())),
+	)
+	defer span.End()
+
 	// 等待探针发送注册信息
 	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
 	_, message, err := conn.ReadMessage()
 	if err != nil {
 		h.logger.Error("failed to read register message", zap.Error(err))
+		span.RecordError(err)
 		conn.Close()
 		return err
 	}
@@ -83,12 +113,14 @@ func (h *AgentHandler) HandleWebSocket(c echo.Context) error {
 	var msg protocol.Message
 	if err := json.Unmarshal(message, &msg); err != nil {
 		h.logger.Error("failed to parse register message", zap.Error(err))
+		span.RecordError(err)
 		conn.Close()
 		return err
 	}
 
 	if msg.Type != protocol.MessageTypeRegister {
 		h.logger.Error("first message must be register", zap.String("type", string(msg.Type)))
+		span.SetStatus(tracing.StatusError, "first message must be register")
 		conn.Close()
 		return echo.NewHTTPError(http.StatusBadRequest, "首条消息必须是注册消息")
 	}
@@ -97,14 +129,16 @@ func (h *AgentHandler) HandleWebSocket(c echo.Context) error {
 	var registerReq protocol.RegisterRequest
 	if err := json.Unmarshal(msg.Data, &registerReq); err != nil {
 		h.logger.Error("failed to parse register request", zap.Error(err))
+		span.RecordError(err)
 		conn.Close()
 		return err
 	}
 
-	// 注册探针 - 使用独立的context,不依赖HTTP请求的context
-	agent, err := h.agentService.RegisterAgent(context.Background(), c.RealIP(), &registerReq.AgentInfo, registerReq.ApiKey)
+	// 注册探针
+	agent, err := h.agentService.RegisterAgent(ctx, c.RealIP(), &registerReq.AgentInfo, registerReq.ApiKey)
 	if err != nil {
 		h.logger.Error("failed to register agent", zap.Error(err))
+		span.RecordError(err)
 
 		// 发送注册失败响应
 		h.sendRegisterError(conn, err.Error())
@@ -112,14 +146,40 @@ func (h *AgentHandler) HandleWebSocket(c echo.Context) error {
 		return err
 	}
 
+	// 记录连接会话，用于统计探针可用率
+	span.SetAttributes(tracing.String("agent.id", agent.ID))
+	session, err := h.agentService.OpenSession(ctx, agent)
+	if err != nil {
+		h.logger.Error("failed to open agent session", zap.String("agentID", agent.ID), zap.Error(err))
+	}
+
+	var client *ws.Client
 	defer func() {
-		// 设置探针状态为离线
-		_ = h.agentService.UpdateAgentStatus(context.Background(), agent.ID, 0)
+		// 以最后一次收到消息的时间作为离线时间，半开连接被回收时不会把等待超时的时长算作在线
+		disconnectedAt := time.Now().UnixMilli()
+		reconnected := false
+		if client != nil {
+			disconnectedAt = client.LastActive().UnixMilli()
+			current, ok := h.wsManager.GetClient(agent.ID)
+			reconnected = ok && current != client
+		}
+		// 探针已用新连接重连时，旧连接断开不能把探针标记为离线
+		if !reconnected {
+			_ = h.agentService.MarkAgentOffline(context.Background(), agent.ID, disconnectedAt)
+		}
+		if session != nil {
+			if err := h.agentService.CloseSession(context.Background(), session.ID, disconnectedAt); err != nil {
+				h.logger.Error("failed to close agent session", zap.String("agentID", agent.ID), zap.Error(err))
+			}
+		}
 	}()
 
 	// 发送注册成功响应
-	if err := h.sendRegisterSuccess(conn, agent.ID); err != nil {
+	// 协商指标编码，旧版本探针不声明编码，使用 JSON
+	encoding := protocol.NegotiateEncoding(registerReq.Encodings)
+	if err := h.sendRegisterSuccess(conn, agent.ID, encoding); err != nil {
 		h.logger.Error("failed to send register ack", zap.Error(err))
+		span.RecordError(err)
 		conn.Close()
 		return err
 	}
@@ -130,16 +190,20 @@ func (h *AgentHandler) HandleWebSocket(c echo.Context) error {
 		// 配置下发失败不中断连接，只记录日志
 	}
 
-	// 创建客户端并注册到管理器
-	client := &ws.Client{
-		ID:         agent.ID,
-		Conn:       conn,
-		Send:       make(chan []byte, 256),
-		Manager:    h.wsManager,
-		LastActive: time.Now(),
+	// 下发 Ping 目标配置
+	if err := h.sendPingConfig(conn, agent.ID); err != nil {
+		h.logger.Error("failed to send ping config", zap.Error(err))
 	}
 
+	// 下发采集器配置
+	if err := h.sendCollectorConfig(conn); err != nil {
+		h.logger.Error("failed to send collector config", zap.Error(err))
+	}
+
+	// 创建客户端并注册到管理器
+	client = ws.NewClient(agent.ID, conn, h.wsManager)
 	h.wsManager.Register(client)
+	span.End()
 
 	// 启动读写协程
 	go client.WritePump()
@@ -147,8 +211,68 @@ func (h *AgentHandler) HandleWebSocket(c echo.Context) error {
 	return nil
 }
 
-// handleWebSocketMessage 处理WebSocket消息
+// handleWebSocketMessage 处理WebSocket消息，每条消息记录为一个 Consumer Span
 func (h *AgentHandler) handleWebSocketMessage(ctx context.Context, agentID string, messageType string, data json.RawMessage) error {
+	ctx, span := tracing.Start(ctx, "ws.message "+messageType,
+		tracing.WithKind(tracing.SpanKindConsumer),
+		tracing.WithAttributes(
+			tracing.String("agent.id", agentID),
+			tracing.String("messaging.operation.type", "process"),
+			tracing.String("pika.message.type", messageType),
+			tracing.Int("messaging.message.body.size", len(data)),
+		),
+	)
+	defer span.End()
+
+	err := h.dispatchWebSocketMessage(ctx, agentID, messageType, data)
+	span.RecordError(err)
+	return err
+}
+
+// handleBinaryMessage 处理二进制消息，目前只有 MessagePack 编码的指标使用二进制帧
+func (h *AgentHandler) handleBinaryMessage(ctx context.Context, agentID string, data []byte) error {
+	ctx, span := tracing.Start(ctx, "ws.message "+string(protocol.MessageTypeMetrics),
+		tracing.WithKind(tracing.SpanKindConsumer),
+		tracing.WithAttributes(
+			tracing.String("agent.id", agentID),
+			tracing.String("messaging.operation.type", "process"),
+			tracing.String("pika.message.type", string(protocol.MessageTypeMetrics)),
+			tracing.String("pika.message.encoding", protocol.EncodingMsgpack),
+			tracing.Int("messaging.message.body.size", len(data)),
+		),
+	)
+	defer span.End()
+
+	err := h.handleBinaryMetrics(ctx, agentID, data)
+	span.RecordError(err)
+	return err
+}
+
+// handleBinaryMetrics 解码 MessagePack 编码的指标，处理逻辑与 JSON 指标一致
+func (h *AgentHandler) handleBinaryMetrics(ctx context.Context, agentID string, data []byte) error {
+	var metrics protocol.BinaryMetrics
+	if err := protocol.UnmarshalMsgpack(data, &metrics); err != nil {
+		return err
+	}
+	if len(metrics.Batch) > 0 {
+		return h.metricService.HandleBinaryMetricBatch(ctx, agentID, &metrics)
+	}
+	if err := h.metricService.HandleBinaryMetricData(ctx, agentID, &metrics); err != nil {
+		return err
+	}
+	if metrics.Type == protocol.MetricTypeMonitor {
+		// 监控结果需要额外判断状态变化，触发监控项的回调
+		var results []protocol.MonitorData
+		if err := protocol.UnmarshalMsgpack(metrics.Data, &results); err != nil {
+			return err
+		}
+		h.monitorSvc.HandleMonitorResults(ctx, agentID, results)
+	}
+	return nil
+}
+
+// dispatchWebSocketMessage 按消息类型分发WebSocket消息
+func (h *AgentHandler) dispatchWebSocketMessage(ctx context.Context, agentID string, messageType string, data json.RawMessage) error {
 	switch protocol.MessageType(messageType) {
 	case protocol.MessageTypeHeartbeat:
 		// 心跳消息，更新探针状态
@@ -160,7 +284,34 @@ func (h *AgentHandler) handleWebSocketMessage(ctx context.Context, agentID strin
 		if err := json.Unmarshal(data, &metricsWrapper); err != nil {
 			return err
 		}
-		return h.metricService.HandleMetricData(ctx, agentID, string(metricsWrapper.Type), metricsWrapper.Data)
+		if err := h.metricService.HandleMetricData(ctx, agentID, string(metricsWrapper.Type), metricsWrapper.Data); err != nil {
+			return err
+		}
+		if metricsWrapper.Type == protocol.MetricTypeMonitor {
+			// 监控结果需要额外判断状态变化，触发监控项的回调
+			var results []protocol.MonitorData
+			if err := json.Unmarshal(metricsWrapper.Data, &results); err != nil {
+				return err
+			}
+			h.monitorSvc.HandleMonitorResults(ctx, agentID, results)
+		}
+		return nil
+
+	case protocol.MessageTypeMetricsBatch:
+		// 一轮采集合并发送的指标，服务监控结果单独发送，不会出现在合并消息中
+		var metricsBatch protocol.MetricsBatch
+		if err := json.Unmarshal(data, &metricsBatch); err != nil {
+			return err
+		}
+		return h.metricService.HandleMetricBatch(ctx, agentID, &metricsBatch)
+
+	case protocol.MessageTypeMetricsReplay:
+		// 断线期间缓存的指标，按原始采集时间写入历史数据
+		var metricsWrapper protocol.MetricsWrapper
+		if err := json.Unmarshal(data, &metricsWrapper); err != nil {
+			return err
+		}
+		return h.metricService.HandleReplayedMetricData(ctx, agentID, &metricsWrapper)
 
 	case protocol.MessageTypeCommandResp:
 		// 指令响应
@@ -188,6 +339,24 @@ func (h *AgentHandler) handleWebSocketMessage(ctx context.Context, agentID strin
 		}
 		return h.tamperService.CreateAlert(agentID, alertData.Path, alertData.Details, alertData.Restored, alertData.Timestamp)
 
+	case protocol.MessageTypeKernelEvent:
+		// 内核日志事件
+		var event protocol.KernelEventData
+		if err := json.Unmarshal(data, &event); err != nil {
+			h.logger.Error("failed to unmarshal kernel event", zap.Error(err))
+			return err
+		}
+		return h.alertSvc.HandleKernelEvent(ctx, agentID, &event)
+
+	case protocol.MessageTypeMonitorTrace:
+		// 监控项变为离线时的路径追踪
+		var trace protocol.MonitorTraceData
+		if err := json.Unmarshal(data, &trace); err != nil {
+			h.logger.Error("failed to unmarshal monitor trace", zap.Error(err))
+			return err
+		}
+		return h.monitorSvc.HandleMonitorTrace(ctx, agentID, &trace)
+
 	case protocol.MessageTypeDDNSIPReport:
 		// DDNS IP 上报 - 异步处理，避免阻塞 WebSocket 消息循环
 		var ipReport protocol.DDNSIPReportData
@@ -197,6 +366,42 @@ func (h *AgentHandler) handleWebSocketMessage(ctx context.Context, agentID strin
 		}
 		return h.ddnsService.HandleIPReport(ctx, agentID, &ipReport)
 
+	case protocol.MessageTypeSoftwareInventory:
+		// 软件清单上报
+		var inventory protocol.SoftwareInventoryData
+		if err := json.Unmarshal(data, &inventory); err != nil {
+			h.logger.Error("failed to unmarshal software inventory", zap.Error(err))
+			return err
+		}
+		return h.softwareSvc.HandleInventoryReport(ctx, agentID, &inventory)
+
+	case protocol.MessageTypeLogTailChunk:
+		// 日志查看输出片段
+		var chunk protocol.LogTailChunk
+		if err := json.Unmarshal(data, &chunk); err != nil {
+			h.logger.Error("failed to unmarshal log tail chunk", zap.Error(err))
+			return err
+		}
+		return h.logTailSvc.HandleChunk(agentID, &chunk)
+
+	case protocol.MessageTypeTerminalOutput:
+		// 远程终端输出
+		var output protocol.TerminalOutput
+		if err := json.Unmarshal(data, &output); err != nil {
+			h.logger.Error("failed to unmarshal terminal output", zap.Error(err))
+			return err
+		}
+		return h.terminalSvc.HandleOutput(agentID, &output)
+
+	case protocol.MessageTypeProcessSnapshot:
+		// 进程列表快照
+		var snapshot protocol.ProcessSnapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			h.logger.Error("failed to unmarshal process snapshot", zap.Error(err))
+			return err
+		}
+		return h.processSvc.HandleSnapshot(agentID, &snapshot)
+
 	case protocol.MessageTypeTamperProtect:
 		// 防篡改配置响应
 		var protectResp protocol.TamperProtectResponse
@@ -227,10 +432,12 @@ func (h *AgentHandler) handleWebSocketMessage(ctx context.Context, agentID strin
 }
 
 // sendRegisterSuccess 发送注册成功响应
-func (h *AgentHandler) sendRegisterSuccess(conn *websocket.Conn, agentID string) error {
+func (h *AgentHandler) sendRegisterSuccess(conn *websocket.Conn, agentID string, encoding string) error {
 	resp := protocol.RegisterResponse{
-		AgentID: agentID,
-		Status:  "success",
+		AgentID:  agentID,
+		Status:   "success",
+		Encoding: encoding,
+		Batch:    true,
 	}
 	respData, err := json.Marshal(resp)
 	if err != nil {
@@ -249,6 +456,24 @@ func (h *AgentHandler) sendRegisterSuccess(conn *websocket.Conn, agentID string)
 	return conn.WriteMessage(websocket.TextMessage, msgData)
 }
 
+// sendPingConfig 发送 Ping 目标配置
+func (h *AgentHandler) sendPingConfig(conn *websocket.Conn, agentID string) error {
+	msgData, err := h.pingSvc.BuildConfigMessage(context.Background(), agentID)
+	if err != nil {
+		return err
+	}
+	return conn.WriteMessage(websocket.TextMessage, msgData)
+}
+
+// sendCollectorConfig 发送采集器配置
+func (h *AgentHandler) sendCollectorConfig(conn *websocket.Conn) error {
+	msgData, err := h.collectorSvc.BuildConfigMessage(context.Background())
+	if err != nil {
+		return err
+	}
+	return conn.WriteMessage(websocket.TextMessage, msgData)
+}
+
 // sendRegisterError 发送注册失败响应
 func (h *AgentHandler) sendRegisterError(conn *websocket.Conn, errMsg string) error {
 	resp := protocol.RegisterResponse{
@@ -368,6 +593,12 @@ func (h *AgentHandler) GetForAdmin(c echo.Context) error {
 	return orz.Ok(c, agent)
 }
 
+// validMetricTypes 支持查询的指标类型
+var validMetricTypes = map[string]bool{
+	"cpu": true, "cpu_core": true, "cpu_times": true, "pressure": true, "memory": true, "disk": true, "network": true, "network_connection": true,
+	"disk_io": true, "gpu": true, "temperature": true, "ping": true,
+}
+
 // parseTimeRange 解析时间范围参数，返回起始和结束时间（毫秒）
 func parseTimeRange(rangeParam string) (start, end int64, err error) {
 	end = time.Now().UnixMilli()
@@ -426,16 +657,21 @@ func (h *AgentHandler) GetMetrics(c echo.Context) error {
 	}
 
 	// 验证指标类型
-	validTypes := map[string]bool{
-		"cpu": true, "memory": true, "disk": true, "network": true, "network_connection": true,
-		"disk_io": true, "gpu": true, "temperature": true,
-	}
 	if metricType == "" {
 		return orz.NewError(400, "指标类型不能为空")
 	}
-	if !validTypes[metricType] {
+	if !validMetricTypes[metricType] {
 		return orz.NewError(400, "无效的指标类型")
 	}
+	// Ping 目标可能包含内网地址，仅登录用户可查看
+	if metricType == "ping" && !utils.IsAuthenticated(c) {
+		return orz.NewError(403, "无权查看该指标")
+	}
+
+	// 指定 agg 时按 step 返回每个区间一个值的序列
+	if agg := c.QueryParam("agg"); agg != "" {
+		return h.getMetricSeries(c, agentID, metricType, agg, interfaceName)
+	}
 
 	// 解析时间范围
 	start, end, err := parseTimeRange(rangeParam)
@@ -460,6 +696,138 @@ func (h *AgentHandler) GetMetrics(c echo.Context) error {
 	})
 }
 
+// getMetricSeries 按 from/to（毫秒时间戳）、step（如 60s、5m，或秒数）和 agg（avg、max、p95）返回聚合后的序列
+// 未指定 from/to 时使用 range 参数，未指定 step 时自动计算
+func (h *AgentHandler) getMetricSeries(c echo.Context, agentID, metricType, agg, interfaceName string) error {
+	if !service.IsValidMetricAgg(agg) {
+		return orz.NewError(400, "无效的聚合方式，支持: avg, max, p95")
+	}
+	if !service.SupportsMetricSeries(metricType) {
+		return orz.NewError(400, "该指标类型不支持 agg 参数")
+	}
+
+	start, end, err := parseQueryTimeRange(c)
+	if err != nil {
+		return err
+	}
+	step, err := parseStep(c.QueryParam("step"))
+	if err != nil {
+		return orz.NewError(400, "step 格式错误，示例: 60s、5m、1h")
+	}
+
+	result, err := h.metricService.GetMetricSeries(c.Request().Context(), agentID, metricType, start, end, step, agg, interfaceName)
+	if err != nil {
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{
+		"agentId":   agentID,
+		"type":      metricType,
+		"interface": interfaceName,
+		"start":     result.Start,
+		"end":       result.End,
+		"step":      result.Step,
+		"agg":       result.Agg,
+		"series":    result.Series,
+	})
+}
+
+// parseQueryTimeRange 解析 from/to（毫秒时间戳）参数，未指定时使用 range 参数
+func parseQueryTimeRange(c echo.Context) (start, end int64, err error) {
+	start, err = parseOptionalMillis(c.QueryParam("from"))
+	if err != nil {
+		return 0, 0, orz.NewError(400, "开始时间格式错误")
+	}
+	end, err = parseOptionalMillis(c.QueryParam("to"))
+	if err != nil {
+		return 0, 0, orz.NewError(400, "结束时间格式错误")
+	}
+	if start == 0 || end == 0 {
+		rangeStart, rangeEnd, err := parseTimeRange(c.QueryParam("range"))
+		if err != nil {
+			return 0, 0, orz.NewError(400, err.Error())
+		}
+		if start == 0 {
+			start = rangeStart
+		}
+		if end == 0 {
+			end = rangeEnd
+		}
+	}
+	if end <= start {
+		return 0, 0, orz.NewError(400, "结束时间必须晚于开始时间")
+	}
+	return start, end, nil
+}
+
+// ExportMetrics 导出探针的历史指标（管理员接口），以 CSV 或 JSON Lines 流式返回
+// GET /api/admin/agents/:id/metrics/export?type=cpu&from=&to=&step=5m&format=csv
+func (h *AgentHandler) ExportMetrics(c echo.Context) error {
+	agentID := c.Param("id")
+	metricType := c.QueryParam("type")
+	interfaceName := c.QueryParam("interface")
+	ctx := c.Request().Context()
+
+	if !service.SupportsMetricExport(metricType) {
+		return orz.NewError(400, "该指标类型不支持导出")
+	}
+	format := c.QueryParam("format")
+	if format == "" {
+		format = service.MetricExportCSV
+	}
+	if !service.IsValidMetricExportFormat(format) {
+		return orz.NewError(400, "无效的导出格式，支持: csv, json")
+	}
+	start, end, err := parseQueryTimeRange(c)
+	if err != nil {
+		return err
+	}
+	step, err := parseStep(c.QueryParam("step"))
+	if err != nil {
+		return orz.NewError(400, "step 格式错误，示例: 60s、5m、1h")
+	}
+	if _, err := h.agentService.GetAgent(ctx, agentID); err != nil {
+		return err
+	}
+
+	contentType, ext := "text/csv; charset=utf-8", "csv"
+	if format == service.MetricExportJSON {
+		contentType, ext = "application/x-ndjson", "jsonl"
+	}
+	filename := fmt.Sprintf("agent-%s-%s-%s.%s", agentID, metricType, time.Now().Format("20060102150405"), ext)
+	c.Response().Header().Set(echo.HeaderContentType, contentType)
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%s", filename))
+	c.Response().WriteHeader(http.StatusOK)
+	if format == service.MetricExportCSV {
+		// 写入 UTF-8 BOM，避免 Excel 打开时中文乱码
+		_, _ = c.Response().Write([]byte("\xEF\xBB\xBF"))
+	}
+
+	// 响应头已发送，导出中途出错只能记录日志并中断输出
+	if err := h.metricService.ExportMetrics(ctx, agentID, metricType, start, end, step, interfaceName, format, c.Response()); err != nil {
+		h.logger.Error("failed to export metrics",
+			zap.String("agentID", agentID),
+			zap.String("type", metricType),
+			zap.Error(err))
+	}
+	return nil
+}
+
+// parseStep 解析 step 参数，支持时长（60s、5m）或秒数，为空时返回 0
+func parseStep(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return seconds, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < time.Second {
+		return 0, fmt.Errorf("invalid step: %s", value)
+	}
+	return int(duration / time.Second), nil
+}
+
 // GetLatestMetrics 获取探针最新指标（公开接口，已登录返回全部，未登录返回公开可见）
 func (h *AgentHandler) GetLatestMetrics(c echo.Context) error {
 	id := c.Param("id")
@@ -474,6 +842,12 @@ func (h *AgentHandler) GetLatestMetrics(c echo.Context) error {
 	if err != nil {
 		return err
 	}
+	// 采集器状态包含本机错误信息，仅登录后可见；缓存中的对象是共享的，复制后再修改
+	if metrics != nil && len(metrics.Collectors) > 0 && !utils.IsAuthenticated(c) {
+		public := *metrics
+		public.Collectors = nil
+		metrics = &public
+	}
 
 	return orz.Ok(c, metrics)
 }
@@ -652,13 +1026,14 @@ func (h *AgentHandler) ListAuditResults(c echo.Context) error {
 	})
 }
 
-// UpdateInfo 更新探针信息（名称、标签、到期时间、可见性）
+// UpdateInfo 更新探针信息（名称、标签、地区、到期时间、可见性）
 func (h *AgentHandler) UpdateInfo(c echo.Context) error {
 	agentID := c.Param("id")
 
 	var req struct {
 		Name       string   `json:"name"`
 		Tags       []string `json:"tags"`
+		Region     string   `json:"region"`
 		ExpireTime int64    `json:"expireTime"`
 		Visibility string   `json:"visibility"`
 	}
@@ -671,6 +1046,7 @@ func (h *AgentHandler) UpdateInfo(c echo.Context) error {
 		ID:         agentID,
 		Name:       req.Name,
 		Tags:       req.Tags,
+		Region:     strings.TrimSpace(req.Region),
 		ExpireTime: req.ExpireTime,
 		Visibility: req.Visibility,
 		UpdatedAt:  time.Now().UnixMilli(),
@@ -697,6 +1073,32 @@ func (h *AgentHandler) GetStatistics(c echo.Context) error {
 	return orz.Ok(c, stats)
 }
 
+// GetAvailability 获取探针在线率和离线时间线（公开接口，已登录返回全部，未登录返回公开可见）
+func (h *AgentHandler) GetAvailability(c echo.Context) error {
+	agentID := c.Param("id")
+	ctx := c.Request().Context()
+
+	// 验证探针访问权限
+	if _, err := h.agentService.GetAgentByAuth(ctx, agentID, utils.IsAuthenticated(c)); err != nil {
+		return err
+	}
+
+	rangeParam := c.QueryParam("range")
+	if rangeParam == "" {
+		rangeParam = "7d"
+	}
+	start, end, err := parseTimeRange(rangeParam)
+	if err != nil {
+		return orz.NewError(400, err.Error())
+	}
+
+	availability, err := h.agentService.GetAvailability(ctx, agentID, start, end)
+	if err != nil {
+		return err
+	}
+	return orz.Ok(c, availability)
+}
+
 // GetMonitorMetrics 获取监控指标数据
 func (h *AgentHandler) GetMonitorMetrics(c echo.Context) error {
 	agentID := c.Param("id")
@@ -773,6 +1175,67 @@ func (h *AgentHandler) GetTags(c echo.Context) error {
 	})
 }
 
+// ExportInventory 导出探针资产清单（支持 csv 和 json 格式）
+func (h *AgentHandler) ExportInventory(c echo.Context) error {
+	format := c.QueryParam("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		return orz.NewError(400, "导出格式仅支持 csv 和 json")
+	}
+
+	ctx := c.Request().Context()
+	items, err := h.agentService.ListAgentInventory(ctx)
+	if err != nil {
+		h.logger.Error("failed to export agent inventory", zap.Error(err))
+		return err
+	}
+
+	filename := fmt.Sprintf("agents-%s.%s", time.Now().Format("20060102150405"), format)
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+
+	if format == "json" {
+		return c.JSON(http.StatusOK, items)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	c.Response().WriteHeader(http.StatusOK)
+
+	// 写入 UTF-8 BOM，避免 Excel 打开时中文乱码
+	_, _ = c.Response().Write([]byte("\xEF\xBB\xBF"))
+	w := csv.NewWriter(c.Response())
+	_ = w.Write([]string{"id", "name", "hostname", "ip", "os", "arch", "version", "tags", "status", "lastSeenAt", "expireTime", "monthlySentBytes", "monthlyRecvBytes"})
+	for _, item := range items {
+		_ = w.Write(inventoryCSVRecord(item))
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// inventoryCSVRecord 生成资产清单的一行 CSV，探针上报的字符串字段均做公式转义
+func inventoryCSVRecord(item service.AgentInventory) []string {
+	status := "offline"
+	if item.Status == 1 {
+		status = "online"
+	}
+	return []string{
+		utils.EscapeCSVCell(item.ID),
+		utils.EscapeCSVCell(item.Name),
+		utils.EscapeCSVCell(item.Hostname),
+		utils.EscapeCSVCell(item.IP),
+		utils.EscapeCSVCell(item.OS),
+		utils.EscapeCSVCell(item.Arch),
+		utils.EscapeCSVCell(item.Version),
+		utils.EscapeCSVCell(strings.Join(item.Tags, ";")),
+		status,
+		formatMillis(item.LastSeenAt),
+		formatMillis(item.ExpireTime),
+		strconv.FormatFloat(item.MonthlySentBytes, 'f', 0, 64),
+		strconv.FormatFloat(item.MonthlyRecvBytes, 'f', 0, 64),
+	}
+}
+
 // GetInstallScript 生成自动安装脚本
 func (h *AgentHandler) GetInstallScript(c echo.Context) error {
 	token := c.QueryParam("token")
@@ -780,7 +1243,7 @@ func (h *AgentHandler) GetInstallScript(c echo.Context) error {
 		return orz.NewError(400, "token不能为空")
 	}
 
-	serverUrl := c.Scheme() + "://" + c.Request().Host
+	serverUrl := utils.BaseURL(c)
 
 	script := `#!/bin/bash
 set -e
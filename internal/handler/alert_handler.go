@@ -1,14 +1,23 @@
 package handler
 
 import (
+	"encoding/csv"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/dushixiang/pika/internal/repo"
 	"github.com/dushixiang/pika/internal/service"
+	"github.com/dushixiang/pika/internal/utils"
 	"github.com/go-orz/orz"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
 )
 
+// alertExportMaxRecords 单次导出的告警记录上限
+const alertExportMaxRecords = 50000
+
 type AlertHandler struct {
 	logger       *zap.Logger
 	alertService *service.AlertService
@@ -44,6 +53,22 @@ func (h *AlertHandler) ListAlertRecords(c echo.Context) error {
 	return orz.Ok(c, page)
 }
 
+// GetAlertCorrelation 获取告警的关联上下文（前后 15 分钟的其他告警、指标快照、修复动作等）
+func (h *AlertHandler) GetAlertCorrelation(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return orz.NewError(400, "告警记录ID格式错误")
+	}
+
+	ctx := c.Request().Context()
+	correlation, err := h.alertService.GetAlertCorrelation(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return orz.Ok(c, correlation)
+}
+
 // ClearAlertRecords 清空告警记录
 func (h *AlertHandler) ClearAlertRecords(c echo.Context) error {
 	if err := h.alertService.Clear(c.Request().Context()); err != nil {
@@ -57,3 +82,85 @@ func (h *AlertHandler) ClearAlertRecords(c echo.Context) error {
 		"message": "清空成功",
 	})
 }
+
+// ExportAlertRecords 导出告警记录（支持 csv 和 json 格式）
+func (h *AlertHandler) ExportAlertRecords(c echo.Context) error {
+	format := c.QueryParam("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		return orz.NewError(400, "导出格式仅支持 csv 和 json")
+	}
+
+	// 多查询一条用于判断是否超过导出上限
+	query := repo.AlertRecordQuery{
+		AgentID:   c.QueryParam("agentId"),
+		AlertType: c.QueryParam("alertType"),
+		Level:     c.QueryParam("level"),
+		Limit:     alertExportMaxRecords + 1,
+	}
+	var err error
+	if query.Start, err = parseOptionalMillis(c.QueryParam("start")); err != nil {
+		return orz.NewError(400, "开始时间格式错误")
+	}
+	if query.End, err = parseOptionalMillis(c.QueryParam("end")); err != nil {
+		return orz.NewError(400, "结束时间格式错误")
+	}
+
+	ctx := c.Request().Context()
+	records, err := h.alertService.AlertRecordRepo.FindByQuery(ctx, query)
+	if err != nil {
+		h.logger.Error("导出告警记录失败", zap.Error(err))
+		return err
+	}
+	if len(records) > alertExportMaxRecords {
+		return orz.NewError(400, fmt.Sprintf("导出的告警记录超过 %d 条，请缩小时间范围或增加筛选条件", alertExportMaxRecords))
+	}
+
+	filename := fmt.Sprintf("alert-records-%s.%s", time.Now().Format("20060102150405"), format)
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+
+	if format == "json" {
+		return c.JSON(http.StatusOK, records)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	c.Response().WriteHeader(http.StatusOK)
+
+	w := csv.NewWriter(c.Response())
+	_ = w.Write([]string{"id", "agentId", "agentName", "alertType", "level", "status", "message", "threshold", "actualValue", "firedAt", "resolvedAt"})
+	for _, record := range records {
+		_ = w.Write([]string{
+			strconv.FormatInt(record.ID, 10),
+			record.AgentID,
+			utils.EscapeCSVCell(record.AgentName),
+			record.AlertType,
+			record.Level,
+			record.Status,
+			utils.EscapeCSVCell(record.Message),
+			strconv.FormatFloat(record.Threshold, 'f', -1, 64),
+			strconv.FormatFloat(record.ActualValue, 'f', -1, 64),
+			formatMillis(record.FiredAt),
+			formatMillis(record.ResolvedAt),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// parseOptionalMillis 解析可选的毫秒时间戳参数，为空时返回 0
+func parseOptionalMillis(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// formatMillis 将毫秒时间戳格式化为 RFC3339，0 返回空字符串
+func formatMillis(ms int64) string {
+	if ms <= 0 {
+		return ""
+	}
+	return time.UnixMilli(ms).Format(time.RFC3339)
+}
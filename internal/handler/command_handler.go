@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/internal/service"
+	"github.com/go-orz/orz"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type CommandHandler struct {
+	logger         *zap.Logger
+	commandService *service.CommandService
+}
+
+func NewCommandHandler(logger *zap.Logger, commandService *service.CommandService) *CommandHandler {
+	return &CommandHandler{
+		logger:         logger.Named("command"),
+		commandService: commandService,
+	}
+}
+
+// Options 获取允许下发的远程命令
+// GET /api/admin/remote-commands
+func (h *CommandHandler) Options(c echo.Context) error {
+	username, _ := c.Get("username").(string)
+	options, err := h.commandService.Options(username)
+	if err != nil {
+		return err
+	}
+	return orz.Ok(c, options)
+}
+
+// Execute 向探针下发远程命令
+// POST /api/admin/agents/:id/remote-commands
+func (h *CommandHandler) Execute(c echo.Context) error {
+	var req protocol.RemoteCommandRequest
+	if err := c.Bind(&req); err != nil {
+		return orz.NewError(400, "参数错误")
+	}
+	agentID := c.Param("id")
+	username, _ := c.Get("username").(string)
+
+	ctx := c.Request().Context()
+	record, err := h.commandService.Execute(ctx, agentID, username, c.RealIP(), req)
+	if err != nil {
+		return err
+	}
+	return orz.Ok(c, record)
+}
+
+// Paging 分页查询远程命令执行记录
+// GET /api/admin/remote-command-executions?agentId=xxx
+func (h *CommandHandler) Paging(c echo.Context) error {
+	username, _ := c.Get("username").(string)
+	if err := h.commandService.Authorize(username); err != nil {
+		return err
+	}
+
+	pr := orz.GetPageRequest(c, "createdAt")
+	builder := orz.NewPageBuilder(h.commandService.CommandExecutionRepo.Repository).
+		PageRequest(pr)
+	if agentID := c.QueryParam("agentId"); agentID != "" {
+		builder.Equal("agent_id", agentID)
+	}
+
+	ctx := c.Request().Context()
+	page, err := builder.Execute(ctx)
+	if err != nil {
+		return err
+	}
+	return orz.Ok(c, page)
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/internal/service"
+	"github.com/go-orz/orz"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type DiskUsageHandler struct {
+	logger           *zap.Logger
+	diskUsageService *service.DiskUsageService
+}
+
+func NewDiskUsageHandler(logger *zap.Logger, diskUsageService *service.DiskUsageService) *DiskUsageHandler {
+	return &DiskUsageHandler{
+		logger:           logger,
+		diskUsageService: diskUsageService,
+	}
+}
+
+// Get 获取探针最近一次的磁盘占用分析结果
+func (h *DiskUsageHandler) Get(c echo.Context) error {
+	agentID := c.Param("id")
+	return orz.Ok(c, h.diskUsageService.Get(agentID))
+}
+
+// Scan 发起磁盘占用分析，结果通过 Get 轮询获取
+func (h *DiskUsageHandler) Scan(c echo.Context) error {
+	agentID := c.Param("id")
+
+	var req protocol.DiskUsageRequest
+	if err := c.Bind(&req); err != nil {
+		return orz.NewError(400, "请求参数错误")
+	}
+
+	scan, err := h.diskUsageService.Scan(agentID, &req)
+	if err != nil {
+		return err
+	}
+	return orz.Ok(c, scan)
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"github.com/dushixiang/pika/internal/service"
+	"github.com/go-orz/orz"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type DockerHandler struct {
+	logger        *zap.Logger
+	dockerService *service.DockerService
+}
+
+func NewDockerHandler(logger *zap.Logger, dockerService *service.DockerService) *DockerHandler {
+	return &DockerHandler{
+		logger:        logger.Named("docker"),
+		dockerService: dockerService,
+	}
+}
+
+// GetContainers 获取探针最近一次的容器列表
+// GET /api/admin/agents/:id/docker/containers
+func (h *DockerHandler) GetContainers(c echo.Context) error {
+	agentID := c.Param("id")
+	return orz.Ok(c, h.dockerService.GetContainers(agentID))
+}
+
+// RefreshContainers 重新获取探针的容器列表，结果通过 GetContainers 轮询获取
+// POST /api/admin/agents/:id/docker/containers/refresh
+func (h *DockerHandler) RefreshContainers(c echo.Context) error {
+	agentID := c.Param("id")
+	list, err := h.dockerService.Refresh(agentID)
+	if err != nil {
+		return err
+	}
+	return orz.Ok(c, list)
+}
+
+// Execute 启动、停止或重启容器
+// POST /api/admin/agents/:id/docker/actions
+func (h *DockerHandler) Execute(c echo.Context) error {
+	var params service.DockerActionParams
+	if err := c.Bind(&params); err != nil {
+		return orz.NewError(400, "参数错误")
+	}
+	agentID := c.Param("id")
+	username, _ := c.Get("username").(string)
+
+	ctx := c.Request().Context()
+	record, err := h.dockerService.Execute(ctx, agentID, username, c.RealIP(), params)
+	if err != nil {
+		return err
+	}
+	return orz.Ok(c, record)
+}
+
+// PagingOperations 分页查询容器操作记录
+// GET /api/admin/docker-operations?agentId=xxx
+func (h *DockerHandler) PagingOperations(c echo.Context) error {
+	pr := orz.GetPageRequest(c, "createdAt")
+	builder := orz.NewPageBuilder(h.dockerService.DockerOperationRepo.Repository).
+		PageRequest(pr)
+	if agentID := c.QueryParam("agentId"); agentID != "" {
+		builder.Equal("agent_id", agentID)
+	}
+
+	ctx := c.Request().Context()
+	page, err := builder.Execute(ctx)
+	if err != nil {
+		return err
+	}
+	return orz.Ok(c, page)
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/service"
+)
+
+func TestInventoryCSVRecord(t *testing.T) {
+	item := service.AgentInventory{
+		Agent: models.Agent{
+			ID:       "a1",
+			Name:     "=cmd|'/c calc'!A0",
+			Hostname: "+host",
+			IP:       "10.0.0.1",
+			OS:       "-linux",
+			Arch:     "amd64",
+			Version:  "@1.0.0",
+			Tags:     []string{"=tag", "prod"},
+			Status:   1,
+		},
+		MonthlySentBytes: 1024,
+	}
+
+	got := inventoryCSVRecord(item)
+	want := []string{"a1", "'=cmd|'/c calc'!A0", "'+host", "10.0.0.1", "'-linux", "amd64", "'@1.0.0", "'=tag;prod", "online", "", "", "1024", "0"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d cells, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("cell %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
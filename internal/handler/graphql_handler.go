@@ -0,0 +1,276 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/repo"
+	"github.com/dushixiang/pika/internal/service"
+	"github.com/dushixiang/pika/pkg/graphql"
+	"github.com/go-orz/orz"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+const (
+	// graphqlMaxBatch 单次批量请求最多包含的查询数
+	graphqlMaxBatch = 20
+	// graphqlMaxBodySize 请求体大小上限
+	graphqlMaxBodySize = 1 << 20
+
+	defaultAlertLimit = 100
+	maxAlertLimit     = 1000
+)
+
+// GraphQLHandler 面向管理后台的 GraphQL 查询接口，前端组合视图可以一次请求获取探针、指标、监控和告警数据
+type GraphQLHandler struct {
+	logger         *zap.Logger
+	agentService   *service.AgentService
+	metricService  *service.MetricService
+	monitorService *service.MonitorService
+	alertService   *service.AlertService
+	schema         *graphql.Schema
+}
+
+func NewGraphQLHandler(logger *zap.Logger, agentService *service.AgentService, metricService *service.MetricService, monitorService *service.MonitorService, alertService *service.AlertService) *GraphQLHandler {
+	h := &GraphQLHandler{
+		logger:         logger,
+		agentService:   agentService,
+		metricService:  metricService,
+		monitorService: monitorService,
+		alertService:   alertService,
+	}
+	h.schema = h.buildSchema()
+	return h
+}
+
+// Query 执行 GraphQL 查询，请求体为 JSON 数组时按批量查询执行并返回结果数组
+func (h *GraphQLHandler) Query(c echo.Context) error {
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, graphqlMaxBodySize+1))
+	if err != nil {
+		return err
+	}
+	if len(body) > graphqlMaxBodySize {
+		return orz.NewError(413, "请求体过大")
+	}
+
+	// 批量查询共享同一个请求级缓存
+	ctx := graphql.WithBatch(c.Request().Context())
+
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var requests []graphql.Request
+		if err := json.Unmarshal(body, &requests); err != nil {
+			return orz.NewError(400, "请求格式错误")
+		}
+		if len(requests) == 0 {
+			return orz.NewError(400, "批量查询不能为空")
+		}
+		if len(requests) > graphqlMaxBatch {
+			return orz.NewError(400, fmt.Sprintf("单次批量查询最多 %d 个", graphqlMaxBatch))
+		}
+		responses := make([]*graphql.Response, len(requests))
+		for i, req := range requests {
+			responses[i] = h.execute(ctx, req)
+		}
+		return c.JSON(http.StatusOK, responses)
+	}
+
+	var req graphql.Request
+	if err := json.Unmarshal(body, &req); err != nil {
+		return orz.NewError(400, "请求格式错误")
+	}
+	return c.JSON(http.StatusOK, h.execute(ctx, req))
+}
+
+func (h *GraphQLHandler) execute(ctx context.Context, req graphql.Request) *graphql.Response {
+	resp := h.schema.Execute(ctx, req)
+	if len(resp.Errors) > 0 {
+		h.logger.Debug("GraphQL 查询存在错误",
+			zap.String("operationName", req.OperationName),
+			zap.String("error", resp.Errors[0].Message),
+			zap.Int("errors", len(resp.Errors)),
+		)
+	}
+	return resp
+}
+
+// buildSchema 构建查询结构
+//
+//	Query {
+//	  agents(online: Boolean): [Agent]
+//	  agent(id: String!): Agent
+//	  tags: [String]
+//	  statistics: Object
+//	  metrics(agentId: String!, type: String!, range: String, interface: String): [Object]
+//	  monitors: [Monitor]
+//	  monitor(id: String!): Monitor
+//	  alerts(agentId: String, type: String, level: String, start: Int, end: Int, limit: Int): [AlertRecord]
+//	}
+//	Agent       { ...探针字段, latestMetrics, metrics(type, range, interface), networkInterfaces, alerts(type, level, start, end, limit) }
+//	Monitor     { ...监控概览字段, agentStats, history(range: String) }
+//	AlertRecord { ...告警记录字段, agent: Agent }
+//
+// 对象的普通字段与 REST 接口返回的 JSON 字段一致
+func (h *GraphQLHandler) buildSchema() *graphql.Schema {
+	s := graphql.NewSchema()
+
+	s.AddField(graphql.QueryType, "agents", &graphql.FieldConfig{
+		Type: "Agent",
+		Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+			if p.Bool("online") {
+				return h.agentService.ListOnlineAgents(ctx)
+			}
+			return h.agentService.ListAgents(ctx)
+		},
+	})
+	s.AddField(graphql.QueryType, "agent", &graphql.FieldConfig{
+		Type: "Agent",
+		Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+			return h.agentService.GetAgent(ctx, p.String("id"))
+		},
+	})
+	s.AddField(graphql.QueryType, "tags", &graphql.FieldConfig{
+		Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+			return h.agentService.GetAllTags(ctx)
+		},
+	})
+	s.AddField(graphql.QueryType, "statistics", &graphql.FieldConfig{
+		Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+			return h.agentService.GetStatistics(ctx)
+		},
+	})
+	s.AddField(graphql.QueryType, "metrics", &graphql.FieldConfig{
+		Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+			return h.resolveMetrics(ctx, p.String("agentId"), p)
+		},
+	})
+	s.AddField(graphql.QueryType, "monitors", &graphql.FieldConfig{
+		Type: "Monitor",
+		Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+			return h.monitorService.ListByAuth(ctx, true)
+		},
+	})
+	s.AddField(graphql.QueryType, "monitor", &graphql.FieldConfig{
+		Type: "Monitor",
+		Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+			return h.monitorService.GetMonitorStatsByID(ctx, p.String("id"))
+		},
+	})
+	s.AddField(graphql.QueryType, "alerts", &graphql.FieldConfig{
+		Type: "AlertRecord",
+		Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+			return h.resolveAlerts(ctx, p.String("agentId"), p)
+		},
+	})
+
+	s.AddField("Agent", "latestMetrics", &graphql.FieldConfig{
+		Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+			return h.metricService.GetLatestMetrics(ctx, p.Source.(*models.Agent).ID)
+		},
+	})
+	s.AddField("Agent", "metrics", &graphql.FieldConfig{
+		Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+			return h.resolveMetrics(ctx, p.Source.(*models.Agent).ID, p)
+		},
+	})
+	s.AddField("Agent", "networkInterfaces", &graphql.FieldConfig{
+		Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+			return h.metricService.GetAvailableNetworkInterfaces(ctx, p.Source.(*models.Agent).ID)
+		},
+	})
+	s.AddField("Agent", "alerts", &graphql.FieldConfig{
+		Type: "AlertRecord",
+		Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+			return h.resolveAlerts(ctx, p.Source.(*models.Agent).ID, p)
+		},
+	})
+
+	s.AddField("Monitor", "agentStats", &graphql.FieldConfig{
+		Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+			return h.monitorService.GetMonitorAgentStats(ctx, p.Source.(*service.PublicMonitorOverview).ID)
+		},
+	})
+	s.AddField("Monitor", "history", &graphql.FieldConfig{
+		Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+			timeRange := p.String("range")
+			if timeRange == "" {
+				timeRange = "1d"
+			}
+			return h.monitorService.GetMonitorHistory(ctx, p.Source.(*service.PublicMonitorOverview).ID, timeRange)
+		},
+	})
+
+	s.AddField("AlertRecord", "agent", &graphql.FieldConfig{
+		Type: "Agent",
+		Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+			agents, err := h.loadAgentsByID(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return agents[p.Source.(*models.AlertRecord).AgentID], nil
+		},
+	})
+
+	return s
+}
+
+// resolveMetrics 查询指定探针的时序指标，参数与 REST 接口一致
+func (h *GraphQLHandler) resolveMetrics(ctx context.Context, agentID string, p graphql.ResolveParams) (interface{}, error) {
+	if agentID == "" {
+		return nil, fmt.Errorf("探针ID不能为空")
+	}
+	metricType := p.String("type")
+	if !validMetricTypes[metricType] {
+		return nil, fmt.Errorf("无效的指标类型: %s", metricType)
+	}
+	interfaceName := p.String("interface")
+	if interfaceName == "" {
+		interfaceName = "all"
+	}
+	start, end, err := parseTimeRange(p.String("range"))
+	if err != nil {
+		return nil, err
+	}
+	return h.metricService.GetMetrics(ctx, agentID, metricType, start, end, 0, interfaceName)
+}
+
+// resolveAlerts 查询告警记录，按触发时间倒序，默认返回最近 100 条
+func (h *GraphQLHandler) resolveAlerts(ctx context.Context, agentID string, p graphql.ResolveParams) (interface{}, error) {
+	limit := p.Int("limit", defaultAlertLimit)
+	if limit <= 0 || limit > maxAlertLimit {
+		limit = maxAlertLimit
+	}
+	return h.alertService.AlertRecordRepo.FindByQuery(ctx, repo.AlertRecordQuery{
+		AgentID:   agentID,
+		AlertType: p.String("type"),
+		Level:     p.String("level"),
+		Start:     p.Int64("start", 0),
+		End:       p.Int64("end", 0),
+		Limit:     limit,
+	})
+}
+
+// loadAgentsByID 一次性加载全部探针，同一请求内的告警记录共享结果，避免逐条查询探针
+func (h *GraphQLHandler) loadAgentsByID(ctx context.Context) (map[string]*models.Agent, error) {
+	value, err := graphql.Load(ctx, "agents", func() (interface{}, error) {
+		agents, err := h.agentService.ListAgents(ctx)
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[string]*models.Agent, len(agents))
+		for i := range agents {
+			byID[agents[i].ID] = &agents[i]
+		}
+		return byID, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(map[string]*models.Agent), nil
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"github.com/dushixiang/pika/internal/service"
+	"github.com/go-orz/orz"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type GroupHandler struct {
+	logger       *zap.Logger
+	groupService *service.GroupService
+}
+
+func NewGroupHandler(logger *zap.Logger, groupService *service.GroupService) *GroupHandler {
+	return &GroupHandler{
+		logger:       logger,
+		groupService: groupService,
+	}
+}
+
+// List 列出所有分组（以探针标签作为分组）
+func (h *GroupHandler) List(c echo.Context) error {
+	ctx := c.Request().Context()
+	groups, err := h.groupService.ListGroups(ctx)
+	if err != nil {
+		return err
+	}
+	return orz.Ok(c, groups)
+}
+
+// GetMetrics 获取分组的最新聚合指标
+func (h *GroupHandler) GetMetrics(c echo.Context) error {
+	ctx := c.Request().Context()
+	metrics, err := h.groupService.GetGroupMetrics(ctx, c.Param("tag"))
+	if err != nil {
+		return err
+	}
+	return orz.Ok(c, metrics)
+}
+
+// GetMetricsHistory 获取分组的历史聚合指标
+func (h *GroupHandler) GetMetricsHistory(c echo.Context) error {
+	tag := c.Param("tag")
+	metricType := c.QueryParam("type")
+	rangeParam := c.QueryParam("range")
+	if metricType == "" {
+		return orz.NewError(400, "指标类型不能为空")
+	}
+
+	start, end, err := parseTimeRange(rangeParam)
+	if err != nil {
+		return orz.NewError(400, err.Error())
+	}
+
+	ctx := c.Request().Context()
+	points, err := h.groupService.GetGroupMetricsHistory(ctx, tag, metricType, start, end)
+	if err != nil {
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{
+		"tag":     tag,
+		"type":    metricType,
+		"range":   rangeParam,
+		"start":   start,
+		"end":     end,
+		"metrics": points,
+	})
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/dushixiang/pika/internal/service"
+	"github.com/dushixiang/pika/internal/utils"
+	"github.com/dushixiang/pika/pkg/version"
+	"github.com/go-orz/orz"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type HealthHandler struct {
+	logger          *zap.Logger
+	databaseService *service.DatabaseService
+}
+
+func NewHealthHandler(logger *zap.Logger, databaseService *service.DatabaseService) *HealthHandler {
+	return &HealthHandler{
+		logger:          logger,
+		databaseService: databaseService,
+	}
+}
+
+// Health 健康检查（公开接口），数据库异常时返回 503 便于负载均衡和监控系统探测，未登录时隐藏错误详情
+func (h *HealthHandler) Health(c echo.Context) error {
+	database := h.databaseService.GetHealth(c.Request().Context())
+
+	status, code := "ok", http.StatusOK
+	if database.Status != service.DatabaseStatusOK {
+		status, code = "degraded", http.StatusServiceUnavailable
+		h.logger.Warn("健康检查发现数据库异常", zap.String("status", database.Status), zap.String("error", database.Error))
+	}
+
+	if !utils.IsAuthenticated(c) {
+		database.Error = ""
+		if database.Integrity != nil {
+			integrity := *database.Integrity
+			integrity.Errors = nil
+			database.Integrity = &integrity
+		}
+	}
+
+	return c.JSON(code, orz.Map{
+		"status":   status,
+		"version":  version.GetVersion(),
+		"database": database,
+	})
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/internal/service"
+	"github.com/go-orz/orz"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type LoggingHandler struct {
+	logger         *zap.Logger
+	loggingService *service.LoggingService
+}
+
+func NewLoggingHandler(logger *zap.Logger, loggingService *service.LoggingService) *LoggingHandler {
+	return &LoggingHandler{
+		logger:         logger,
+		loggingService: loggingService,
+	}
+}
+
+// GetLevels 获取服务端日志级别
+func (h *LoggingHandler) GetLevels(c echo.Context) error {
+	return orz.Ok(c, h.loggingService.GetLevels())
+}
+
+// SetLevel 调整服务端日志级别
+func (h *LoggingHandler) SetLevel(c echo.Context) error {
+	var req protocol.LogLevelRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	levels, err := h.loggingService.SetLevel(&req)
+	if err != nil {
+		return err
+	}
+	return orz.Ok(c, levels)
+}
+
+// ResetLevel 清除服务端模块单独设置的日志级别
+func (h *LoggingHandler) ResetLevel(c echo.Context) error {
+	levels, err := h.loggingService.SetLevel(&protocol.LogLevelRequest{
+		Module: c.Param("module"),
+		Reset:  true,
+	})
+	if err != nil {
+		return err
+	}
+	return orz.Ok(c, levels)
+}
+
+// SetAgentLevel 调整探针日志级别
+func (h *LoggingHandler) SetAgentLevel(c echo.Context) error {
+	agentID := c.Param("id")
+
+	var req protocol.LogLevelRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	commandID, err := h.loggingService.SetAgentLevel(agentID, &req)
+	if err != nil {
+		return err
+	}
+	return orz.Ok(c, orz.Map{
+		"commandId": commandID,
+		"status":    "sent",
+	})
+}
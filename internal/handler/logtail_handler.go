@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/internal/service"
+	"github.com/go-orz/orz"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+const (
+	// logTailPingInterval 向浏览器发送心跳的间隔，同时检查探针连接是否有效
+	logTailPingInterval = 30 * time.Second
+	// logTailWriteTimeout 向浏览器写入数据的超时时间
+	logTailWriteTimeout = 10 * time.Second
+)
+
+type LogTailHandler struct {
+	logger         *zap.Logger
+	logTailService *service.LogTailService
+	upgrader       websocket.Upgrader
+}
+
+func NewLogTailHandler(logger *zap.Logger, logTailService *service.LogTailService) *LogTailHandler {
+	return &LogTailHandler{
+		logger:         logger,
+		logTailService: logTailService,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024 * 32,
+		},
+	}
+}
+
+// logTailServerMessage 发送给浏览器的日志消息
+type logTailServerMessage struct {
+	Type   string `json:"type"` // log: 日志内容，closed: 查看已结束
+	Data   string `json:"data,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Tail 实时查看探针日志（Server-Sent Events）
+// GET /api/admin/agents/:id/logs/tail?source=file&target=/var/log/syslog&lines=100&duration=60
+func (h *LogTailHandler) Tail(c echo.Context) error {
+	agentID := c.Param("id")
+
+	req := protocol.LogTailRequest{
+		Source: c.QueryParam("source"),
+		Target: c.QueryParam("target"),
+	}
+	var err error
+	if req.Lines, err = parseOptionalInt(c.QueryParam("lines")); err != nil {
+		return orz.NewError(400, "行数格式错误")
+	}
+	if req.Duration, err = parseOptionalInt(c.QueryParam("duration")); err != nil {
+		return orz.NewError(400, "时长格式错误")
+	}
+
+	ctx := c.Request().Context()
+	session, err := h.logTailService.Start(ctx, agentID, req)
+	if err != nil {
+		return err
+	}
+	defer h.logTailService.Stop(session)
+
+	resp := c.Response()
+	resp.Header().Set(echo.HeaderContentType, "text/event-stream")
+	resp.Header().Set("Cache-Control", "no-cache")
+	resp.Header().Set("Connection", "keep-alive")
+	// 禁止 nginx 等反向代理缓冲输出
+	resp.Header().Set("X-Accel-Buffering", "no")
+	resp.WriteHeader(http.StatusOK)
+	resp.Flush()
+
+	// 探针侧会在到期后主动结束，这里额外留出余量兜底
+	timer := time.NewTimer(session.Duration + 10*time.Second)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+			return writeLogTailEvent(resp, "done", protocol.LogTailChunk{ID: session.ID, Done: true})
+		case chunk := <-session.Chunks:
+			event := "log"
+			if chunk.Done {
+				event = "done"
+			}
+			if err := writeLogTailEvent(resp, event, chunk); err != nil {
+				return nil
+			}
+			if chunk.Done {
+				return nil
+			}
+		}
+	}
+}
+
+// CreateTicket 创建查看日志的一次性凭证
+// POST /api/admin/agents/:id/logs/ticket
+func (h *LogTailHandler) CreateTicket(c echo.Context) error {
+	agentID := c.Param("id")
+	username, _ := c.Get("username").(string)
+
+	ticket, err := h.logTailService.CreateTicket(agentID, username, c.RealIP())
+	if err != nil {
+		return err
+	}
+	return orz.Ok(c, orz.Map{
+		"ticket": ticket,
+	})
+}
+
+// Connect 使用凭证建立 WebSocket 连接，实时推送探针的日志
+// GET /ws/logs?ticket=xxx&source=file&target=/var/log/syslog&lines=100&duration=60
+func (h *LogTailHandler) Connect(c echo.Context) error {
+	wsConn, err := h.upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		h.logger.Error("failed to upgrade websocket", zap.Error(err))
+		return nil
+	}
+	defer wsConn.Close()
+	wsConn.SetReadLimit(1024)
+
+	req := protocol.LogTailRequest{
+		Source: c.QueryParam("source"),
+		Target: c.QueryParam("target"),
+	}
+	req.Lines, _ = parseOptionalInt(c.QueryParam("lines"))
+	req.Duration, _ = parseOptionalInt(c.QueryParam("duration"))
+
+	session, err := h.logTailService.Open(c.Request().Context(), c.QueryParam("ticket"), req)
+	if err != nil {
+		h.writeMessage(wsConn, logTailServerMessage{Type: "closed", Reason: err.Error()})
+		return nil
+	}
+	defer h.logTailService.Stop(session)
+
+	reason := h.relay(wsConn, session)
+	h.writeMessage(wsConn, logTailServerMessage{Type: "closed", Reason: reason})
+	_ = wsConn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	return nil
+}
+
+// relay 将探针推送的日志转发给浏览器，返回查看结束的原因
+func (h *LogTailHandler) relay(wsConn *websocket.Conn, session *service.LogTailSession) string {
+	browserClosed := make(chan struct{})
+	go func() {
+		defer close(browserClosed)
+		for {
+			// 浏览器不发送数据，读取只用于感知连接断开
+			if _, _, err := wsConn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	// 探针侧会在到期后主动结束，这里额外留出余量兜底
+	timer := time.NewTimer(session.Duration + 10*time.Second)
+	defer timer.Stop()
+	ping := time.NewTicker(logTailPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-browserClosed:
+			return "浏览器已断开"
+		case <-timer.C:
+			return "已达到最长查看时间"
+		case <-ping.C:
+			if !h.logTailService.AgentConnected(session) {
+				return "探针已断开"
+			}
+			if err := wsConn.WriteControl(websocket.PingMessage, nil, time.Now().Add(logTailWriteTimeout)); err != nil {
+				return "浏览器已断开"
+			}
+		case chunk := <-session.Chunks:
+			if chunk.Data != "" {
+				if err := h.writeMessage(wsConn, logTailServerMessage{Type: "log", Data: chunk.Data}); err != nil {
+					return "浏览器已断开"
+				}
+			}
+			if chunk.Done {
+				if chunk.Error != "" {
+					return chunk.Error
+				}
+				return "日志查看已结束"
+			}
+		}
+	}
+}
+
+func (h *LogTailHandler) writeMessage(wsConn *websocket.Conn, msg logTailServerMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_ = wsConn.SetWriteDeadline(time.Now().Add(logTailWriteTimeout))
+	return wsConn.WriteMessage(websocket.TextMessage, data)
+}
+
+// writeLogTailEvent 写入一条 SSE 事件
+func writeLogTailEvent(resp *echo.Response, event string, chunk protocol.LogTailChunk) error {
+	data, err := json.Marshal(chunk)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(resp, "event: %s\ndata: %s\n\n", event, data); err != nil {
+		return err
+	}
+	resp.Flush()
+	return nil
+}
+
+// parseOptionalInt 解析可选的整数参数，为空时返回 0
+func parseOptionalInt(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
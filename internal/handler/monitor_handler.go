@@ -1,8 +1,18 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/repo"
 	"github.com/dushixiang/pika/internal/service"
 	"github.com/dushixiang/pika/internal/utils"
+	"github.com/dushixiang/pika/pkg/badge"
 	"github.com/go-orz/orz"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
@@ -51,6 +61,9 @@ func (h *MonitorHandler) List(c echo.Context) error {
 			agentIds = append(agentIds, item.AgentIds...)
 		}
 	}
+	for i := range page.Items {
+		service.MaskMonitorSecrets(&page.Items[i])
+	}
 	if len(agentIds) > 0 {
 		agents, err := h.agentService.AgentRepo.FindByIdIn(ctx, agentIds)
 		if err != nil {
@@ -85,6 +98,7 @@ func (h *MonitorHandler) Create(c echo.Context) error {
 	if err != nil {
 		return err
 	}
+	service.MaskMonitorSecrets(item)
 
 	return orz.Ok(c, item)
 }
@@ -97,6 +111,7 @@ func (h *MonitorHandler) Get(c echo.Context) error {
 	if err != nil {
 		return err
 	}
+	service.MaskMonitorSecrets(&item)
 
 	return orz.Ok(c, item)
 }
@@ -114,6 +129,7 @@ func (h *MonitorHandler) Update(c echo.Context) error {
 	if err != nil {
 		return err
 	}
+	service.MaskMonitorSecrets(item)
 
 	return orz.Ok(c, item)
 }
@@ -140,6 +156,17 @@ func (h *MonitorHandler) GetMonitors(c echo.Context) error {
 	return orz.Ok(c, stats)
 }
 
+// GetGroups 获取监控分组的状态和可用率（公开接口，已登录返回全部，未登录只汇总公开可见的监控项）
+func (h *MonitorHandler) GetGroups(c echo.Context) error {
+	ctx := c.Request().Context()
+	groups, err := h.monitorService.ListMonitorGroups(ctx, utils.IsAuthenticated(c))
+	if err != nil {
+		return err
+	}
+
+	return orz.Ok(c, groups)
+}
+
 // GetStatsByID 获取指定监控任务的统计数据（公开接口，已登录返回全部，未登录返回公开可见）
 func (h *MonitorHandler) GetStatsByID(c echo.Context) error {
 	id := c.Param("id")
@@ -176,6 +203,45 @@ func (h *MonitorHandler) GetAgentStatsByID(c echo.Context) error {
 	return orz.Ok(c, stats)
 }
 
+// GetCertsByID 获取指定监控任务各探针检测到的证书详情（公开接口，已登录返回全部，未登录返回公开可见）
+func (h *MonitorHandler) GetCertsByID(c echo.Context) error {
+	id := c.Param("id")
+	ctx := c.Request().Context()
+
+	isAuthenticated := utils.IsAuthenticated(c)
+
+	// 验证监控任务访问权限
+	if _, err := h.monitorService.GetMonitorByAuth(ctx, id, isAuthenticated); err != nil {
+		return err
+	}
+
+	certs, err := h.monitorService.GetMonitorCerts(ctx, id, isAuthenticated)
+	if err != nil {
+		return err
+	}
+
+	return orz.Ok(c, certs)
+}
+
+// GetTracesByID 获取指定监控任务最近的离线路径追踪（公开接口，已登录返回全部，未登录返回公开可见）
+func (h *MonitorHandler) GetTracesByID(c echo.Context) error {
+	id := c.Param("id")
+	ctx := c.Request().Context()
+	isAuthenticated := utils.IsAuthenticated(c)
+
+	// 验证监控任务访问权限
+	if _, err := h.monitorService.GetMonitorByAuth(ctx, id, isAuthenticated); err != nil {
+		return err
+	}
+
+	traces, err := h.monitorService.GetMonitorTraces(ctx, id, isAuthenticated)
+	if err != nil {
+		return err
+	}
+
+	return orz.Ok(c, traces)
+}
+
 // GetHistoryByID 获取指定监控任务的历史响应时间数据（公开接口，已登录返回全部，未登录返回公开可见）
 func (h *MonitorHandler) GetHistoryByID(c echo.Context) error {
 	id := c.Param("id")
@@ -200,3 +266,166 @@ func (h *MonitorHandler) GetHistoryByID(c echo.Context) error {
 
 	return orz.Ok(c, history)
 }
+
+// parseMonitorRecordFilter 解析原始检测记录的筛选条件：探针、状态和时间范围（from/to 或 range）
+func parseMonitorRecordFilter(c echo.Context) (repo.MonitorMetricFilter, error) {
+	status := c.QueryParam("status")
+	if !service.IsValidMonitorRecordStatus(status) {
+		return repo.MonitorMetricFilter{}, orz.NewError(400, "无效的状态，支持: up, down")
+	}
+	start, end, err := parseQueryTimeRange(c)
+	if err != nil {
+		return repo.MonitorMetricFilter{}, err
+	}
+	return repo.MonitorMetricFilter{
+		MonitorID: c.Param("id"),
+		AgentID:   c.QueryParam("agentId"),
+		Status:    status,
+		Start:     start,
+		End:       end,
+	}, nil
+}
+
+// ListRecords 分页查询监控项的原始检测记录（管理员接口）
+// GET /api/admin/monitors/:id/records?agentId=&status=down&from=&to=&pageIndex=1&pageSize=50
+func (h *MonitorHandler) ListRecords(c echo.Context) error {
+	filter, err := parseMonitorRecordFilter(c)
+	if err != nil {
+		return err
+	}
+	pr := orz.GetPageRequest(c)
+
+	ctx := c.Request().Context()
+	records, total, err := h.monitorService.PageMonitorRecords(ctx, filter, pr.PageIndex, pr.PageSize)
+	if err != nil {
+		return err
+	}
+	return orz.Ok(c, orz.Map{
+		"items": records,
+		"total": total,
+	})
+}
+
+// ExportRecords 导出监控项的原始检测记录（管理员接口），以 CSV 或 JSON Lines 流式返回
+// GET /api/admin/monitors/:id/records/export?agentId=&status=&from=&to=&format=csv
+func (h *MonitorHandler) ExportRecords(c echo.Context) error {
+	filter, err := parseMonitorRecordFilter(c)
+	if err != nil {
+		return err
+	}
+	format := c.QueryParam("format")
+	if format == "" {
+		format = service.MetricExportCSV
+	}
+	if !service.IsValidMetricExportFormat(format) {
+		return orz.NewError(400, "无效的导出格式，支持: csv, json")
+	}
+	ctx := c.Request().Context()
+	if _, err := h.monitorService.FindById(ctx, filter.MonitorID); err != nil {
+		return err
+	}
+
+	contentType, ext := "text/csv; charset=utf-8", "csv"
+	if format == service.MetricExportJSON {
+		contentType, ext = "application/x-ndjson", "jsonl"
+	}
+	filename := fmt.Sprintf("monitor-%s-records-%s.%s", filter.MonitorID, time.Now().Format("20060102150405"), ext)
+	c.Response().Header().Set(echo.HeaderContentType, contentType)
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%s", filename))
+	c.Response().WriteHeader(http.StatusOK)
+	if format == service.MetricExportCSV {
+		// 写入 UTF-8 BOM，避免 Excel 打开时中文乱码
+		_, _ = c.Response().Write([]byte("\xEF\xBB\xBF"))
+	}
+
+	// 响应头已发送，导出中途出错只能记录日志并中断输出
+	if err := h.monitorService.ExportMonitorRecords(ctx, filter, format, c.Response()); err != nil {
+		h.logger.Error("failed to export monitor records",
+			zap.String("monitorID", filter.MonitorID),
+			zap.Error(err))
+	}
+	return nil
+}
+
+// GetSLAReport 获取监控项某个自然月的 SLA 达成情况（管理员接口）
+// GET /api/admin/monitors/:id/sla?month=2025-03
+func (h *MonitorHandler) GetSLAReport(c echo.Context) error {
+	ctx := c.Request().Context()
+	report, err := h.monitorService.GetMonitorSLAReport(ctx, c.Param("id"), c.QueryParam("month"))
+	if err != nil {
+		return err
+	}
+	return orz.Ok(c, report)
+}
+
+// badgeCacheSeconds 徽章的缓存时间，README 等页面通过图片代理加载时减少回源
+const badgeCacheSeconds = 60
+
+// GetStatusBadge 获取监控项当前状态的 SVG 徽章（公开接口，只支持匿名可见的监控项）
+func (h *MonitorHandler) GetStatusBadge(c echo.Context) error {
+	data, err := h.monitorService.GetMonitorBadge(c.Request().Context(), c.Param("id"), 0)
+	if err != nil {
+		return h.renderBadgeError(c, err)
+	}
+
+	message, color := data.Status, badge.ColorGrey
+	switch {
+	case data.InMaintenance:
+		message, color = "maintenance", badge.ColorBlue
+	case data.Status == "up":
+		color = badge.ColorBrightGreen
+	case data.Status == "down":
+		color = badge.ColorRed
+	}
+	return h.renderBadge(c, http.StatusOK, badgeLabel(c, data.Name), message, color)
+}
+
+// GetUptimeBadge 获取监控项可用率的 SVG 徽章（公开接口），通过 days 参数指定统计天数，默认 30 天
+func (h *MonitorHandler) GetUptimeBadge(c echo.Context) error {
+	days, _ := strconv.Atoi(c.QueryParam("days"))
+	data, err := h.monitorService.GetMonitorBadge(c.Request().Context(), c.Param("id"), days)
+	if err != nil {
+		return h.renderBadgeError(c, err)
+	}
+
+	message, color := "no data", badge.ColorGrey
+	if data.HasUptime {
+		message, color = formatUptime(data.Uptime), badge.UptimeColor(data.Uptime)
+	}
+	return h.renderBadge(c, http.StatusOK, badgeLabel(c, fmt.Sprintf("uptime %dd", data.Days)), message, color)
+}
+
+// badgeLabel 标签默认为监控项名称等，可通过 label 参数自定义
+func badgeLabel(c echo.Context, fallback string) string {
+	if label := strings.TrimSpace(c.QueryParam("label")); label != "" {
+		if runes := []rune(label); len(runes) > 64 {
+			label = string(runes[:64])
+		}
+		return label
+	}
+	return fallback
+}
+
+// formatUptime 保留两位小数，100% 时不显示小数
+func formatUptime(uptime float64) string {
+	if uptime >= 100 {
+		return "100%"
+	}
+	return strconv.FormatFloat(math.Floor(uptime*100)/100, 'f', 2, 64) + "%"
+}
+
+// renderBadgeError 以徽章形式返回错误，嵌入的图片不会显示为裂图
+func (h *MonitorHandler) renderBadgeError(c echo.Context, err error) error {
+	var oe *orz.Error
+	if errors.As(err, &oe) && oe.Code == http.StatusNotFound {
+		return h.renderBadge(c, http.StatusNotFound, "monitor", "not found", badge.ColorGrey)
+	}
+	h.logger.Error("生成监控徽章失败", zap.String("monitorId", c.Param("id")), zap.Error(err))
+	return h.renderBadge(c, http.StatusInternalServerError, "monitor", "error", badge.ColorGrey)
+}
+
+func (h *MonitorHandler) renderBadge(c echo.Context, code int, label, message, color string) error {
+	header := c.Response().Header()
+	header.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", badgeCacheSeconds))
+	return c.Blob(code, "image/svg+xml; charset=utf-8", badge.Render(label, message, color))
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/dushixiang/pika/internal/service"
+	"github.com/go-orz/orz"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type NotificationJobHandler struct {
+	logger                   *zap.Logger
+	notificationQueueService *service.NotificationQueueService
+}
+
+func NewNotificationJobHandler(logger *zap.Logger, notificationQueueService *service.NotificationQueueService) *NotificationJobHandler {
+	return &NotificationJobHandler{
+		logger:                   logger,
+		notificationQueueService: notificationQueueService,
+	}
+}
+
+// Paging 分页查询通知任务（?status=dead 查询死信列表，?status=pending 查询等待重试的任务）
+func (h *NotificationJobHandler) Paging(c echo.Context) error {
+	status := c.QueryParam("status")
+	channelType := c.QueryParam("channelType")
+
+	pr := orz.GetPageRequest(c, "createdAt", "updatedAt", "nextAttemptAt")
+
+	builder := orz.NewPageBuilder(h.notificationQueueService.JobRepo.Repository).
+		PageRequest(pr)
+
+	if status != "" {
+		builder.Equal("status", status)
+	}
+	if channelType != "" {
+		builder.Equal("channel_type", channelType)
+	}
+
+	ctx := c.Request().Context()
+	page, err := builder.Execute(ctx)
+	if err != nil {
+		h.logger.Error("获取通知任务失败", zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, page)
+}
+
+// PagingLogs 分页查询通知发送日志，可按告警记录、探针、渠道和状态筛选
+func (h *NotificationJobHandler) PagingLogs(c echo.Context) error {
+	pr := orz.GetPageRequest(c, "createdAt", "latencyMs")
+
+	builder := orz.NewPageBuilder(h.notificationQueueService.LogRepo.Repository).
+		PageRequest(pr)
+
+	if alertRecordID := c.QueryParam("alertRecordId"); alertRecordID != "" {
+		id, err := strconv.ParseInt(alertRecordID, 10, 64)
+		if err != nil {
+			return orz.NewError(400, "告警记录ID格式错误")
+		}
+		builder.Equal("alert_record_id", id)
+	}
+	if agentID := c.QueryParam("agentId"); agentID != "" {
+		builder.Equal("agent_id", agentID)
+	}
+	if channelType := c.QueryParam("channelType"); channelType != "" {
+		builder.Equal("channel_type", channelType)
+	}
+	if status := c.QueryParam("status"); status != "" {
+		builder.Equal("status", status)
+	}
+
+	ctx := c.Request().Context()
+	page, err := builder.Execute(ctx)
+	if err != nil {
+		h.logger.Error("获取通知发送日志失败", zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, page)
+}
+
+// Retry 重新发送死信中的通知
+func (h *NotificationJobHandler) Retry(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return orz.NewError(400, "通知任务ID格式错误")
+	}
+
+	ctx := c.Request().Context()
+	if err := h.notificationQueueService.Retry(ctx, id); err != nil {
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{
+		"message": "已重新加入发送队列",
+	})
+}
+
+// Delete 删除通知任务
+func (h *NotificationJobHandler) Delete(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return orz.NewError(400, "通知任务ID格式错误")
+	}
+
+	ctx := c.Request().Context()
+	if err := h.notificationQueueService.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{
+		"message": "删除成功",
+	})
+}
+
+// ClearDead 清空死信列表
+func (h *NotificationJobHandler) ClearDead(c echo.Context) error {
+	ctx := c.Request().Context()
+	if err := h.notificationQueueService.ClearDead(ctx); err != nil {
+		h.logger.Error("清空死信列表失败", zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{
+		"message": "清空成功",
+	})
+}
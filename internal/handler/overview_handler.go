@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"github.com/dushixiang/pika/internal/service"
+	"github.com/dushixiang/pika/internal/utils"
+	"github.com/go-orz/orz"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type OverviewHandler struct {
+	logger          *zap.Logger
+	overviewService *service.OverviewService
+}
+
+func NewOverviewHandler(logger *zap.Logger, overviewService *service.OverviewService) *OverviewHandler {
+	return &OverviewHandler{
+		logger:          logger,
+		overviewService: overviewService,
+	}
+}
+
+// GetOverview 获取全局概览（公开接口，已登录统计全部，未登录统计公开可见）
+func (h *OverviewHandler) GetOverview(c echo.Context) error {
+	ctx := c.Request().Context()
+	overview, err := h.overviewService.GetOverview(ctx, utils.IsAuthenticated(c))
+	if err != nil {
+		h.logger.Error("获取全局概览失败", zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, overview)
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/service"
+	"github.com/go-orz/orz"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type PingHandler struct {
+	logger      *zap.Logger
+	pingService *service.PingService
+}
+
+func NewPingHandler(logger *zap.Logger, pingService *service.PingService) *PingHandler {
+	return &PingHandler{
+		logger:      logger,
+		pingService: pingService,
+	}
+}
+
+// List 获取探针的 Ping 目标
+func (h *PingHandler) List(c echo.Context) error {
+	agentID := c.Param("id")
+	ctx := c.Request().Context()
+
+	targets, err := h.pingService.ListTargets(ctx, agentID)
+	if err != nil {
+		return err
+	}
+
+	return orz.Ok(c, targets)
+}
+
+// Create 创建 Ping 目标
+func (h *PingHandler) Create(c echo.Context) error {
+	agentID := c.Param("id")
+
+	var req service.PingTargetRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	target, err := h.pingService.CreateTarget(ctx, agentID, &req)
+	if err != nil {
+		return err
+	}
+
+	return orz.Ok(c, target)
+}
+
+// Update 更新 Ping 目标
+func (h *PingHandler) Update(c echo.Context) error {
+	agentID := c.Param("id")
+	targetID := c.Param("targetId")
+
+	var req service.PingTargetRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	target, err := h.pingService.UpdateTarget(ctx, agentID, targetID, &req)
+	if err != nil {
+		return err
+	}
+
+	return orz.Ok(c, target)
+}
+
+// Delete 删除 Ping 目标
+func (h *PingHandler) Delete(c echo.Context) error {
+	agentID := c.Param("id")
+	targetID := c.Param("targetId")
+	ctx := c.Request().Context()
+
+	if err := h.pingService.DeleteTarget(ctx, agentID, targetID); err != nil {
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{})
+}
+
+// GetMeshConfig 获取延迟网格配置
+func (h *PingHandler) GetMeshConfig(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	config, err := h.pingService.GetMeshConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	return orz.Ok(c, config)
+}
+
+// SaveMeshConfig 保存延迟网格配置
+func (h *PingHandler) SaveMeshConfig(c echo.Context) error {
+	var config models.LatencyMeshConfig
+	if err := c.Bind(&config); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	if err := h.pingService.SaveMeshConfig(ctx, &config); err != nil {
+		return err
+	}
+
+	return orz.Ok(c, config)
+}
+
+// GetMeshMatrix 获取延迟网格矩阵
+func (h *PingHandler) GetMeshMatrix(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	matrix, err := h.pingService.GetMeshMatrix(ctx)
+	if err != nil {
+		return err
+	}
+
+	return orz.Ok(c, matrix)
+}
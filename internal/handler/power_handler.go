@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/dushixiang/pika/internal/service"
+	"github.com/go-orz/orz"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type PowerHandler struct {
+	logger       *zap.Logger
+	powerService *service.PowerService
+}
+
+func NewPowerHandler(logger *zap.Logger, powerService *service.PowerService) *PowerHandler {
+	return &PowerHandler{
+		logger:       logger,
+		powerService: powerService,
+	}
+}
+
+// RebootRequest 计划重启请求
+type RebootRequest struct {
+	ScheduledAt int64 `json:"scheduledAt"` // 计划执行时间（时间戳毫秒），为 0 表示确认后尽快执行
+}
+
+// WakeOnLANRequest 网络唤醒请求
+type WakeOnLANRequest struct {
+	MAC       string `json:"mac"`
+	Broadcast string `json:"broadcast"`
+}
+
+// List 获取探针的电源操作任务
+func (h *PowerHandler) List(c echo.Context) error {
+	agentID := c.Param("id")
+
+	ctx := c.Request().Context()
+	tasks, err := h.powerService.ListByAgentID(ctx, agentID)
+	if err != nil {
+		h.logger.Error("获取电源操作任务失败", zap.String("agentId", agentID), zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, tasks)
+}
+
+// Reboot 创建计划重启任务，任务需要确认后才会执行
+func (h *PowerHandler) Reboot(c echo.Context) error {
+	agentID := c.Param("id")
+
+	var req RebootRequest
+	if err := c.Bind(&req); err != nil {
+		return orz.NewError(400, "请求参数错误")
+	}
+
+	ctx := c.Request().Context()
+	task, err := h.powerService.ScheduleReboot(ctx, agentID, req.ScheduledAt)
+	if err != nil {
+		return err
+	}
+
+	return orz.Ok(c, task)
+}
+
+// WakeOnLAN 通过探针向局域网发送网络唤醒魔术包
+func (h *PowerHandler) WakeOnLAN(c echo.Context) error {
+	agentID := c.Param("id")
+
+	var req WakeOnLANRequest
+	if err := c.Bind(&req); err != nil {
+		return orz.NewError(400, "请求参数错误")
+	}
+
+	ctx := c.Request().Context()
+	task, err := h.powerService.WakeOnLAN(ctx, agentID, req.MAC, req.Broadcast)
+	if err != nil {
+		h.logger.Error("下发网络唤醒失败", zap.String("agentId", agentID), zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, task)
+}
+
+// Confirm 确认执行重启任务
+func (h *PowerHandler) Confirm(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return orz.NewError(400, "任务ID格式错误")
+	}
+
+	ctx := c.Request().Context()
+	if err := h.powerService.Confirm(ctx, id); err != nil {
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{
+		"message": "重启任务已确认",
+	})
+}
+
+// Cancel 取消尚未下发的任务
+func (h *PowerHandler) Cancel(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return orz.NewError(400, "任务ID格式错误")
+	}
+
+	ctx := c.Request().Context()
+	if err := h.powerService.Cancel(ctx, id); err != nil {
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{
+		"message": "任务已取消",
+	})
+}
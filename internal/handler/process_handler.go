@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/internal/service"
+	"github.com/go-orz/orz"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+const (
+	// processPingInterval 向浏览器发送心跳的间隔，同时检查探针连接是否有效
+	processPingInterval = 30 * time.Second
+	// processWriteTimeout 向浏览器写入数据的超时时间
+	processWriteTimeout = 10 * time.Second
+)
+
+type ProcessHandler struct {
+	logger         *zap.Logger
+	processService *service.ProcessService
+	upgrader       websocket.Upgrader
+}
+
+func NewProcessHandler(logger *zap.Logger, processService *service.ProcessService) *ProcessHandler {
+	return &ProcessHandler{
+		logger:         logger.Named("process"),
+		processService: processService,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024 * 32,
+		},
+	}
+}
+
+// processServerMessage 发送给浏览器的进程列表消息
+type processServerMessage struct {
+	Type     string                    `json:"type"` // snapshot: 进程列表快照，closed: 推送已结束
+	Snapshot *protocol.ProcessSnapshot `json:"snapshot,omitempty"`
+	Reason   string                    `json:"reason,omitempty"`
+}
+
+// CreateTicket 创建查看进程列表的一次性凭证
+// POST /api/admin/agents/:id/processes/ticket
+func (h *ProcessHandler) CreateTicket(c echo.Context) error {
+	agentID := c.Param("id")
+	username, _ := c.Get("username").(string)
+
+	ticket, err := h.processService.CreateTicket(agentID, username, c.RealIP())
+	if err != nil {
+		return err
+	}
+	return orz.Ok(c, orz.Map{
+		"ticket": ticket,
+	})
+}
+
+// Connect 使用凭证建立 WebSocket 连接，实时推送探针的进程列表
+// GET /ws/processes?ticket=xxx&limit=100&interval=3
+func (h *ProcessHandler) Connect(c echo.Context) error {
+	wsConn, err := h.upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		h.logger.Error("failed to upgrade websocket", zap.Error(err))
+		return nil
+	}
+	defer wsConn.Close()
+	wsConn.SetReadLimit(1024)
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	interval, _ := strconv.Atoi(c.QueryParam("interval"))
+	stream, err := h.processService.Open(c.QueryParam("ticket"), protocol.ProcessListRequest{
+		Limit:    limit,
+		Interval: interval,
+	})
+	if err != nil {
+		h.writeMessage(wsConn, processServerMessage{Type: "closed", Reason: err.Error()})
+		return nil
+	}
+	defer h.processService.Stop(stream)
+
+	reason := h.relay(wsConn, stream)
+	h.writeMessage(wsConn, processServerMessage{Type: "closed", Reason: reason})
+	_ = wsConn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	return nil
+}
+
+// relay 将探针推送的快照转发给浏览器，返回推送结束的原因
+func (h *ProcessHandler) relay(wsConn *websocket.Conn, stream *service.ProcessStream) string {
+	browserClosed := make(chan struct{})
+	go func() {
+		defer close(browserClosed)
+		for {
+			// 浏览器不发送数据，读取只用于感知连接断开
+			if _, _, err := wsConn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	// 探针侧会在到期后主动结束，这里额外留出余量兜底
+	timer := time.NewTimer(stream.Duration + 10*time.Second)
+	defer timer.Stop()
+	ping := time.NewTicker(processPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-browserClosed:
+			return "浏览器已断开"
+		case <-timer.C:
+			return "已达到最长查看时间"
+		case <-ping.C:
+			if !h.processService.AgentConnected(stream) {
+				return "探针已断开"
+			}
+			if err := wsConn.WriteControl(websocket.PingMessage, nil, time.Now().Add(processWriteTimeout)); err != nil {
+				return "浏览器已断开"
+			}
+		case snapshot := <-stream.Snapshots:
+			if snapshot.Done {
+				if snapshot.Error != "" {
+					return snapshot.Error
+				}
+				return "已达到最长查看时间"
+			}
+			if err := h.writeMessage(wsConn, processServerMessage{Type: "snapshot", Snapshot: &snapshot}); err != nil {
+				return "浏览器已断开"
+			}
+		}
+	}
+}
+
+func (h *ProcessHandler) writeMessage(wsConn *websocket.Conn, msg processServerMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_ = wsConn.SetWriteDeadline(time.Now().Add(processWriteTimeout))
+	return wsConn.WriteMessage(websocket.TextMessage, data)
+}
+
+// Kill 结束探针上的进程
+// POST /api/admin/agents/:id/processes/kill
+func (h *ProcessHandler) Kill(c echo.Context) error {
+	var params service.ProcessKillParams
+	if err := c.Bind(&params); err != nil {
+		return orz.NewError(400, "参数错误")
+	}
+	agentID := c.Param("id")
+	username, _ := c.Get("username").(string)
+
+	ctx := c.Request().Context()
+	record, err := h.processService.Kill(ctx, agentID, username, c.RealIP(), params)
+	if err != nil {
+		return err
+	}
+	return orz.Ok(c, record)
+}
+
+// PagingKills 分页查询结束进程记录
+// GET /api/admin/process-kills?agentId=xxx
+func (h *ProcessHandler) PagingKills(c echo.Context) error {
+	pr := orz.GetPageRequest(c, "createdAt")
+	builder := orz.NewPageBuilder(h.processService.ProcessKillRepo.Repository).
+		PageRequest(pr)
+	if agentID := c.QueryParam("agentId"); agentID != "" {
+		builder.Equal("agent_id", agentID)
+	}
+
+	ctx := c.Request().Context()
+	page, err := builder.Execute(ctx)
+	if err != nil {
+		return err
+	}
+	return orz.Ok(c, page)
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/dushixiang/pika/internal/config"
+	"github.com/dushixiang/pika/internal/service"
+	"github.com/go-orz/orz"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type PrometheusHandler struct {
+	logger            *zap.Logger
+	prometheusService *service.PrometheusService
+	cfg               *config.AppConfig
+}
+
+func NewPrometheusHandler(logger *zap.Logger, prometheusService *service.PrometheusService, cfg *config.AppConfig) *PrometheusHandler {
+	return &PrometheusHandler{
+		logger:            logger,
+		prometheusService: prometheusService,
+		cfg:               cfg,
+	}
+}
+
+// Metrics 以 Prometheus 文本格式导出指标，需要在配置中启用并通过 Bearer Token 访问
+func (h *PrometheusHandler) Metrics(c echo.Context) error {
+	promConfig := h.cfg.Prometheus
+	if promConfig == nil || !promConfig.Enabled || promConfig.Token == "" {
+		return orz.NewError(404, "未启用 Prometheus 指标导出")
+	}
+
+	token := strings.TrimPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(promConfig.Token)) != 1 {
+		return orz.NewError(401, "访问令牌无效")
+	}
+
+	data, err := h.prometheusService.Render(c.Request().Context())
+	if err != nil {
+		h.logger.Error("导出 Prometheus 指标失败", zap.Error(err))
+		return err
+	}
+	return c.Blob(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", data)
+}
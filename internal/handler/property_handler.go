@@ -13,22 +13,29 @@ import (
 )
 
 type PropertyHandler struct {
-	logger   *zap.Logger
-	service  *service.PropertyService
-	notifier *service.Notifier
+	logger       *zap.Logger
+	service      *service.PropertyService
+	notifier     *service.Notifier
+	collectorSvc *service.CollectorConfigService
 }
 
-func NewPropertyHandler(logger *zap.Logger, service *service.PropertyService, notifier *service.Notifier) *PropertyHandler {
+func NewPropertyHandler(logger *zap.Logger, service *service.PropertyService, notifier *service.Notifier, collectorConfigService *service.CollectorConfigService) *PropertyHandler {
 	return &PropertyHandler{
-		logger:   logger,
-		service:  service,
-		notifier: notifier,
+		logger:       logger,
+		service:      service,
+		notifier:     notifier,
+		collectorSvc: collectorConfigService,
 	}
 }
 
 // GetProperty 获取属性（返回 JSON 值）
 func (h *PropertyHandler) GetProperty(c echo.Context) error {
 	id := c.Param("id")
+	if id == service.PropertyIDSecretKey {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": "不允许读取该属性",
+		})
+	}
 
 	property, err := h.service.Get(c.Request().Context(), id)
 	if err != nil {
@@ -59,6 +66,12 @@ func (h *PropertyHandler) GetProperty(c echo.Context) error {
 // SetProperty 设置属性
 func (h *PropertyHandler) SetProperty(c echo.Context) error {
 	id := c.Param("id")
+	// 加密密钥被覆盖后已保存的敏感字段将无法解密
+	if id == service.PropertyIDSecretKey {
+		return c.JSON(http.StatusForbidden, map[string]string{
+			"error": "不允许修改该属性",
+		})
+	}
 
 	var req struct {
 		Name  string      `json:"name"`
@@ -78,6 +91,11 @@ func (h *PropertyHandler) SetProperty(c echo.Context) error {
 		})
 	}
 
+	// 指标配置中包含探针采集开关，需要同步给在线探针
+	if id == service.PropertyIDMetricsConfig {
+		h.collectorSvc.SyncToAgents(c.Request().Context())
+	}
+
 	return c.JSON(http.StatusOK, map[string]string{
 		"message": "设置成功",
 	})
@@ -211,6 +229,20 @@ func (h *PropertyHandler) TestNotificationChannel(c echo.Context) error {
 		sendErr = h.notifier.SendFeishuByConfig(ctx, targetChannel.Config, message)
 	case "webhook":
 		sendErr = h.notifier.SendWebhookByConfig(ctx, targetChannel.Config, message)
+	case "discord":
+		sendErr = h.notifier.SendDiscordByConfig(ctx, targetChannel.Config, message)
+	case "email":
+		sendErr = h.notifier.SendEmailByConfig(ctx, targetChannel.Config, message)
+	case "ntfy":
+		sendErr = h.notifier.SendNtfyByConfig(ctx, targetChannel.Config, message)
+	case "gotify":
+		sendErr = h.notifier.SendGotifyByConfig(ctx, targetChannel.Config, message)
+	case "pushover":
+		sendErr = h.notifier.SendPushoverByConfig(ctx, targetChannel.Config, message)
+	case "matrix":
+		sendErr = h.notifier.SendMatrixByConfig(ctx, targetChannel.Config, message)
+	case "twilio":
+		sendErr = h.notifier.SendTwilioByConfig(ctx, targetChannel.Config, message)
 	default:
 		return c.JSON(http.StatusBadRequest, map[string]string{
 			"error": "不支持的通知渠道类型",
@@ -228,3 +260,35 @@ func (h *PropertyHandler) TestNotificationChannel(c echo.Context) error {
 		"message": "测试通知已发送",
 	})
 }
+
+// SendSampleNotification 通过指定渠道发送示例告警并返回服务商的响应，渠道以类型作为 ID，未启用的渠道也可以测试
+func (h *PropertyHandler) SendSampleNotification(c echo.Context) error {
+	channelType := c.Param("id")
+	ctx := c.Request().Context()
+
+	channels, err := h.service.GetNotificationChannelConfigs(ctx)
+	if err != nil {
+		return err
+	}
+
+	var channel *models.NotificationChannelConfig
+	for i := range channels {
+		if channels[i].Type == channelType {
+			channel = &channels[i]
+			break
+		}
+	}
+	if channel == nil {
+		return orz.NewError(404, "通知渠道不存在，请先配置")
+	}
+
+	// 配置尚未启用时也允许验证
+	sample := *channel
+	sample.Enabled = true
+
+	result := h.notifier.SendSampleNotification(ctx, &sample)
+	if !result.Success {
+		h.logger.Warn("发送示例告警失败", zap.String("type", channelType), zap.String("error", result.Error))
+	}
+	return orz.Ok(c, result)
+}
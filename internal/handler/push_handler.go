@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/dushixiang/pika/internal/service"
+	"github.com/go-orz/orz"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type PushHandler struct {
+	logger         *zap.Logger
+	monitorService *service.MonitorService
+}
+
+func NewPushHandler(logger *zap.Logger, monitorService *service.MonitorService) *PushHandler {
+	return &PushHandler{
+		logger:         logger,
+		monitorService: monitorService,
+	}
+}
+
+// Push 接收外部任务（定时任务、备份脚本等）的心跳（公开接口，通过令牌识别监控项）
+// 可选参数：status=up|down 上报任务结果，msg 附带说明，ping 任务耗时（毫秒）
+func (h *PushHandler) Push(c echo.Context) error {
+	token := c.Param("token")
+	status := c.QueryParam("status")
+	msg := c.QueryParam("msg")
+	if runes := []rune(msg); len(runes) > 1024 {
+		msg = string(runes[:1024])
+	}
+	var responseTime int64
+	if ping := c.QueryParam("ping"); ping != "" {
+		value, err := strconv.ParseInt(ping, 10, 64)
+		if err != nil {
+			return orz.NewError(400, "ping 参数必须是整数")
+		}
+		responseTime = value
+	}
+
+	if err := h.monitorService.ReceivePush(c.Request().Context(), token, status, msg, responseTime); err != nil {
+		return err
+	}
+	return orz.Ok(c, orz.Map{"ok": true})
+}
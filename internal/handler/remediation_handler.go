@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/dushixiang/pika/internal/service"
+	"github.com/go-orz/orz"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type RemediationHandler struct {
+	logger             *zap.Logger
+	remediationService *service.RemediationService
+}
+
+func NewRemediationHandler(logger *zap.Logger, remediationService *service.RemediationService) *RemediationHandler {
+	return &RemediationHandler{
+		logger:             logger,
+		remediationService: remediationService,
+	}
+}
+
+// Paging 分页查询修复动作（?status=pending 可查询待审批的动作）
+func (h *RemediationHandler) Paging(c echo.Context) error {
+	agentID := c.QueryParam("agentId")
+	status := c.QueryParam("status")
+
+	pr := orz.GetPageRequest(c, "createdAt", "updatedAt")
+
+	builder := orz.NewPageBuilder(h.remediationService.RemediationRepo.Repository).
+		PageRequest(pr)
+
+	if agentID != "" {
+		builder.Equal("agent_id", agentID)
+	}
+	if status != "" {
+		builder.Equal("status", status)
+	}
+
+	ctx := c.Request().Context()
+	page, err := builder.Execute(ctx)
+	if err != nil {
+		h.logger.Error("获取修复动作失败", zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, page)
+}
+
+// ListByAlertRecord 获取告警记录关联的修复动作
+func (h *RemediationHandler) ListByAlertRecord(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return orz.NewError(400, "告警记录ID格式错误")
+	}
+
+	ctx := c.Request().Context()
+	items, err := h.remediationService.ListByAlertRecordID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return orz.Ok(c, items)
+}
+
+// Approve 审批通过并执行修复动作
+func (h *RemediationHandler) Approve(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return orz.NewError(400, "修复动作ID格式错误")
+	}
+
+	ctx := c.Request().Context()
+	if err := h.remediationService.Approve(ctx, id); err != nil {
+		h.logger.Error("执行修复动作失败", zap.Int64("id", id), zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{
+		"message": "修复动作已下发",
+	})
+}
+
+// Reject 拒绝执行修复动作
+func (h *RemediationHandler) Reject(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return orz.NewError(400, "修复动作ID格式错误")
+	}
+
+	ctx := c.Request().Context()
+	if err := h.remediationService.Reject(ctx, id); err != nil {
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{
+		"message": "已拒绝执行修复动作",
+	})
+}
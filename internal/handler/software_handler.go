@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"github.com/dushixiang/pika/internal/service"
+	"github.com/go-orz/orz"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type SoftwareHandler struct {
+	logger          *zap.Logger
+	softwareService *service.SoftwareService
+}
+
+func NewSoftwareHandler(logger *zap.Logger, softwareService *service.SoftwareService) *SoftwareHandler {
+	return &SoftwareHandler{
+		logger:          logger,
+		softwareService: softwareService,
+	}
+}
+
+// GetAgentSoftware 获取探针的软件清单
+func (h *SoftwareHandler) GetAgentSoftware(c echo.Context) error {
+	agentID := c.Param("id")
+
+	ctx := c.Request().Context()
+	items, err := h.softwareService.ListByAgentID(ctx, agentID)
+	if err != nil {
+		return err
+	}
+
+	return orz.Ok(c, items)
+}
+
+// GetAgentContainerImages 获取探针运行中容器的镜像及仓库摘要漂移状态
+func (h *SoftwareHandler) GetAgentContainerImages(c echo.Context) error {
+	agentID := c.Param("id")
+
+	ctx := c.Request().Context()
+	images, err := h.softwareService.ListContainerImages(ctx, agentID)
+	if err != nil {
+		return err
+	}
+
+	return orz.Ok(c, images)
+}
+
+// Search 按软件名称和版本搜索探针，例如 ?name=openssl&op=lt&version=3.0.13
+func (h *SoftwareHandler) Search(c echo.Context) error {
+	name := c.QueryParam("name")
+	op := c.QueryParam("op")
+	version := c.QueryParam("version")
+
+	ctx := c.Request().Context()
+	results, err := h.softwareService.Search(ctx, name, op, version)
+	if err != nil {
+		return err
+	}
+
+	return orz.Ok(c, results)
+}
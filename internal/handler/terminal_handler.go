@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dushixiang/pika/internal/service"
+	"github.com/dushixiang/pika/internal/utils"
+	"github.com/go-orz/orz"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+const (
+	// terminalPingInterval 向浏览器发送心跳的间隔，避免反向代理关闭空闲连接，同时检查探针连接是否有效
+	terminalPingInterval = 30 * time.Second
+	// terminalWriteTimeout 向浏览器写入数据的超时时间
+	terminalWriteTimeout = 10 * time.Second
+)
+
+type TerminalHandler struct {
+	logger          *zap.Logger
+	terminalService *service.TerminalService
+	upgrader        websocket.Upgrader
+}
+
+func NewTerminalHandler(logger *zap.Logger, terminalService *service.TerminalService) *TerminalHandler {
+	return &TerminalHandler{
+		logger:          logger.Named("terminal"),
+		terminalService: terminalService,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024 * 4,
+			WriteBufferSize: 1024 * 32,
+		},
+	}
+}
+
+// terminalClientMessage 浏览器发送的终端消息
+type terminalClientMessage struct {
+	Type string `json:"type"` // input: 键盘输入，resize: 调整窗口大小
+	Data string `json:"data"`
+	Cols int    `json:"cols"`
+	Rows int    `json:"rows"`
+}
+
+// terminalClosedMessage 终端结束时发送给浏览器的消息，终端输出使用二进制帧发送
+type terminalClosedMessage struct {
+	Type   string `json:"type"` // 固定为 closed
+	Reason string `json:"reason"`
+}
+
+// CreateTicket 创建打开终端的一次性凭证
+// POST /api/admin/agents/:id/terminal
+func (h *TerminalHandler) CreateTicket(c echo.Context) error {
+	agentID := c.Param("id")
+	username, _ := c.Get("username").(string)
+
+	// 凭证绑定可信的客户端 IP，未信任代理时客户端伪造的 X-Forwarded-For 不会生效
+	ticket, err := h.terminalService.CreateTicket(agentID, username, utils.ClientIP(c))
+	if err != nil {
+		return err
+	}
+
+	return orz.Ok(c, orz.Map{
+		"ticket":      ticket,
+		"idleTimeout": int(h.terminalService.IdleTimeout().Seconds()),
+	})
+}
+
+// Connect 使用凭证建立浏览器与探针终端之间的 WebSocket 连接
+// GET /ws/terminal?ticket=xxx&cols=120&rows=40&term=xterm-256color
+func (h *TerminalHandler) Connect(c echo.Context) error {
+	wsConn, err := h.upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		h.logger.Error("failed to upgrade websocket", zap.Error(err))
+		return nil
+	}
+	defer wsConn.Close()
+	wsConn.SetReadLimit(64 * 1024)
+
+	cols, _ := strconv.Atoi(c.QueryParam("cols"))
+	rows, _ := strconv.Atoi(c.QueryParam("rows"))
+	conn, err := h.terminalService.Open(c.Request().Context(), c.QueryParam("ticket"), utils.ClientIP(c), cols, rows, c.QueryParam("term"))
+	if err != nil {
+		h.writeClosed(wsConn, err.Error())
+		return nil
+	}
+
+	reason := h.relay(wsConn, conn)
+	h.terminalService.Close(conn, reason)
+	h.writeClosed(wsConn, reason)
+	return nil
+}
+
+// relay 在浏览器和探针之间转发终端数据，返回会话结束的原因
+func (h *TerminalHandler) relay(wsConn *websocket.Conn, conn *service.TerminalConn) string {
+	activity := make(chan struct{}, 1)
+	browserClosed := make(chan string, 1)
+
+	go func() {
+		for {
+			_, message, err := wsConn.ReadMessage()
+			if err != nil {
+				browserClosed <- "浏览器已断开"
+				return
+			}
+			var msg terminalClientMessage
+			if err := json.Unmarshal(message, &msg); err != nil {
+				continue
+			}
+			switch msg.Type {
+			case "input":
+				if err = h.terminalService.Input(conn, []byte(msg.Data)); err == nil {
+					select {
+					case activity <- struct{}{}:
+					default:
+					}
+				}
+			case "resize":
+				err = h.terminalService.Resize(conn, msg.Cols, msg.Rows)
+			}
+			if err != nil {
+				browserClosed <- "探针已断开"
+				return
+			}
+		}
+	}()
+
+	// 探针侧也有空闲超时，这里兜底处理探针异常未结束终端的情况
+	idle := time.NewTimer(conn.IdleTimeout)
+	defer idle.Stop()
+	ping := time.NewTicker(terminalPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case reason := <-browserClosed:
+			return reason
+		case <-activity:
+			idle.Reset(conn.IdleTimeout)
+		case <-idle.C:
+			return fmt.Sprintf("空闲超过 %d 秒，终端已关闭", int(conn.IdleTimeout.Seconds()))
+		case <-ping.C:
+			if !h.terminalService.AgentConnected(conn) {
+				return "探针已断开"
+			}
+			if err := wsConn.WriteControl(websocket.PingMessage, nil, time.Now().Add(terminalWriteTimeout)); err != nil {
+				return "浏览器已断开"
+			}
+		case output := <-conn.Output:
+			if len(output.Data) > 0 {
+				h.terminalService.RecordOutput(conn, output.Data)
+				_ = wsConn.SetWriteDeadline(time.Now().Add(terminalWriteTimeout))
+				if err := wsConn.WriteMessage(websocket.BinaryMessage, output.Data); err != nil {
+					return "浏览器已断开"
+				}
+			}
+			if output.Done {
+				if output.Error != "" {
+					return output.Error
+				}
+				return "终端已退出"
+			}
+		}
+	}
+}
+
+// writeClosed 通知浏览器终端已结束
+func (h *TerminalHandler) writeClosed(wsConn *websocket.Conn, reason string) {
+	data, err := json.Marshal(terminalClosedMessage{Type: "closed", Reason: reason})
+	if err != nil {
+		return
+	}
+	_ = wsConn.SetWriteDeadline(time.Now().Add(terminalWriteTimeout))
+	if err := wsConn.WriteMessage(websocket.TextMessage, data); err != nil {
+		return
+	}
+	_ = wsConn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+}
+
+// Paging 分页查询终端会话记录
+// GET /api/admin/terminal-sessions?agentId=xxx
+func (h *TerminalHandler) Paging(c echo.Context) error {
+	username, _ := c.Get("username").(string)
+	if err := h.terminalService.Authorize(username); err != nil {
+		return err
+	}
+
+	pr := orz.GetPageRequest(c, "startedAt")
+	builder := orz.NewPageBuilder(h.terminalService.TerminalSessionRepo.Repository).
+		PageRequest(pr)
+	if agentID := c.QueryParam("agentId"); agentID != "" {
+		builder.Equal("agent_id", agentID)
+	}
+
+	ctx := c.Request().Context()
+	page, err := builder.Execute(ctx)
+	if err != nil {
+		return err
+	}
+	return orz.Ok(c, page)
+}
+
+// DownloadRecording 下载终端会话的录像（asciicast v2 格式，可使用 asciinema play 回放）
+// GET /api/admin/terminal-sessions/:id/recording
+func (h *TerminalHandler) DownloadRecording(c echo.Context) error {
+	username, _ := c.Get("username").(string)
+	if err := h.terminalService.Authorize(username); err != nil {
+		return err
+	}
+
+	id := c.Param("id")
+	ctx := c.Request().Context()
+	if _, err := h.terminalService.TerminalSessionRepo.FindById(ctx, id); err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "application/x-asciicast")
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=terminal-%s.cast", id))
+	c.Response().WriteHeader(http.StatusOK)
+
+	// 响应头已发送，输出中途出错只能记录日志并中断输出
+	if err := h.terminalService.WriteRecording(ctx, id, c.Response()); err != nil {
+		h.logger.Error("输出终端录像失败", zap.String("sessionId", id), zap.Error(err))
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/dushixiang/pika/internal/service"
+	"github.com/go-orz/orz"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type VulnerabilityHandler struct {
+	logger               *zap.Logger
+	vulnerabilityService *service.VulnerabilityService
+}
+
+func NewVulnerabilityHandler(logger *zap.Logger, vulnerabilityService *service.VulnerabilityService) *VulnerabilityHandler {
+	return &VulnerabilityHandler{
+		logger:               logger,
+		vulnerabilityService: vulnerabilityService,
+	}
+}
+
+// ListFindings 分页查询安全漏洞发现
+func (h *VulnerabilityHandler) ListFindings(c echo.Context) error {
+	agentID := c.QueryParam("agentId")
+	severity := c.QueryParam("severity")
+	software := c.QueryParam("software")
+	keyword := c.QueryParam("keyword")
+
+	pr := orz.GetPageRequest(c, "lastSeenAt", "firstSeenAt", "severity")
+
+	builder := orz.NewPageBuilder(h.vulnerabilityService.FindingRepo.Repository).
+		PageRequest(pr).
+		Keyword([]string{"vuln_id", "aliases", "summary"}, keyword)
+
+	if agentID != "" {
+		builder.Equal("agent_id", agentID)
+	}
+	if severity != "" {
+		builder.Equal("severity", severity)
+	}
+	if software != "" {
+		builder.Equal("software", software)
+	}
+
+	ctx := c.Request().Context()
+	page, err := builder.Execute(ctx)
+	if err != nil {
+		h.logger.Error("获取安全漏洞发现失败", zap.Error(err))
+		return err
+	}
+
+	return orz.Ok(c, page)
+}
+
+// Scan 立即执行一次漏洞匹配（异步执行）
+func (h *VulnerabilityHandler) Scan(c echo.Context) error {
+	go func() {
+		if err := h.vulnerabilityService.Scan(context.Background()); err != nil {
+			h.logger.Error("漏洞匹配失败", zap.Error(err))
+		}
+	}()
+
+	return orz.Ok(c, orz.Map{
+		"message": "漏洞匹配已开始",
+	})
+}
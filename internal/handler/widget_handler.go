@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/dushixiang/pika/internal/config"
+	"github.com/dushixiang/pika/internal/service"
+	"github.com/go-orz/orz"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const defaultWidgetCacheSeconds = 30
+
+// AgentWidget 探针状态挂件数据，只包含适合公开展示的字段
+type AgentWidget struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	Status     string  `json:"status"`               // up, down
+	CPU        float64 `json:"cpu"`                  // CPU 使用率
+	Memory     float64 `json:"memory"`               // 内存使用率
+	Uptime     uint64  `json:"uptime"`               // 运行时间（秒）
+	LastSeenAt int64   `json:"lastSeenAt"`           // 最后上线时间（时间戳毫秒）
+	UpdatedAt  int64   `json:"updatedAt"`            // 指标时间（时间戳毫秒）
+	ExpireTime int64   `json:"expireTime,omitempty"` // 到期时间（时间戳毫秒）
+}
+
+type WidgetHandler struct {
+	logger        *zap.Logger
+	agentService  *service.AgentService
+	metricService *service.MetricService
+	cfg           *config.AppConfig
+}
+
+func NewWidgetHandler(logger *zap.Logger, agentService *service.AgentService, metricService *service.MetricService, cfg *config.AppConfig) *WidgetHandler {
+	return &WidgetHandler{
+		logger:        logger,
+		agentService:  agentService,
+		metricService: metricService,
+		cfg:           cfg,
+	}
+}
+
+// GetAgent 获取单个公开探针的精简状态，用于嵌入外部网站；响应带 Cache-Control 和 ETag，未变化时返回 304
+func (h *WidgetHandler) GetAgent(c echo.Context) error {
+	widgetConfig := h.cfg.Widget
+	if widgetConfig == nil || !widgetConfig.Enabled || len(widgetConfig.Tokens) == 0 {
+		return orz.NewError(404, "未启用探针状态挂件接口")
+	}
+	if !h.validToken(c, widgetConfig.Tokens) {
+		return orz.NewError(401, "访问令牌无效")
+	}
+
+	ctx := c.Request().Context()
+	agent, err := h.agentService.GetAgentByAuth(ctx, c.Param("id"), false)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return orz.NewError(404, "探针不存在")
+		}
+		return err
+	}
+
+	widget := AgentWidget{
+		ID:         agent.ID,
+		Name:       agent.Name,
+		Status:     "down",
+		LastSeenAt: agent.LastSeenAt,
+		ExpireTime: agent.ExpireTime,
+	}
+	if agent.Status == 1 {
+		widget.Status = "up"
+	}
+	if latest, _ := h.metricService.GetLatestMetrics(ctx, agent.ID); latest != nil {
+		if latest.CPU != nil {
+			widget.CPU = latest.CPU.UsagePercent
+			widget.UpdatedAt = latest.CPU.Timestamp
+		}
+		if latest.Memory != nil {
+			widget.Memory = latest.Memory.UsagePercent
+		}
+		if latest.Host != nil {
+			widget.Uptime = latest.Host.Uptime
+		}
+	}
+
+	data, err := json.Marshal(widget)
+	if err != nil {
+		return err
+	}
+
+	cacheSeconds := widgetConfig.CacheSeconds
+	if cacheSeconds <= 0 {
+		cacheSeconds = defaultWidgetCacheSeconds
+	}
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+
+	header := c.Response().Header()
+	header.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", cacheSeconds))
+	header.Set("ETag", etag)
+	if c.Request().Header.Get("If-None-Match") == etag {
+		return c.NoContent(http.StatusNotModified)
+	}
+	return c.Blob(http.StatusOK, echo.MIMEApplicationJSON, data)
+}
+
+// validToken 校验访问令牌，支持查询参数和 Bearer Token，嵌入页面中的脚本和图片通常只能使用查询参数
+func (h *WidgetHandler) validToken(c echo.Context, tokens []string) bool {
+	token := c.QueryParam("token")
+	if token == "" {
+		token = strings.TrimPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+	}
+	if token == "" {
+		return false
+	}
+	for _, t := range tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+			return true
+		}
+	}
+	return false
+}
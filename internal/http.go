@@ -0,0 +1,171 @@
+package internal
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/config"
+	"github.com/dushixiang/pika/internal/utils"
+	"github.com/go-orz/orz"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"golang.org/x/time/rate"
+)
+
+const defaultWidgetRateLimit = 30
+
+// normalizeBasePath 规范化子路径前缀：以 / 开头、不以 / 结尾，根路径返回空字符串
+func normalizeBasePath(basePath string) string {
+	basePath = strings.Trim(strings.TrimSpace(basePath), "/")
+	if basePath == "" {
+		return ""
+	}
+	return "/" + basePath
+}
+
+// setupHTTPMiddleware 注册子路径、跨域和安全响应头中间件，未配置时保持原有行为
+func setupHTTPMiddleware(e *echo.Echo, cfg *config.HTTPConfig) {
+	if cfg == nil {
+		cfg = &config.HTTPConfig{}
+	}
+	basePath := normalizeBasePath(cfg.BasePath)
+
+	// 客户端 IP 默认取连接的对端地址，防止客户端伪造 X-Forwarded-For、X-Real-IP 绕过审计和限流；
+	// 信任代理头时按 server.ip_extractor 和 server.ip_trust_list 的配置获取，未配置时只信任内网和本机代理传入的 X-Forwarded-For
+	if !cfg.TrustForwardedHeaders {
+		e.IPExtractor = echo.ExtractIPDirect()
+	} else if e.IPExtractor == nil {
+		e.IPExtractor = echo.ExtractIPFromXFFHeader()
+	}
+
+	// 子路径处理需要在路由匹配前执行
+	e.Pre(BasePathMiddleware(basePath, cfg.TrustForwardedHeaders))
+
+	if cfg.CORS != nil && len(cfg.CORS.AllowOrigins) > 0 {
+		e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+			AllowOrigins:     cfg.CORS.AllowOrigins,
+			AllowCredentials: cfg.CORS.AllowCredentials,
+			MaxAge:           cfg.CORS.MaxAge,
+			AllowHeaders:     []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
+			ExposeHeaders:    []string{echo.HeaderContentDisposition},
+		}))
+	}
+
+	e.Use(SecurityHeadersMiddleware(cfg))
+}
+
+// BasePathMiddleware 支持部署在子路径下：请求路径带有前缀时去掉前缀再路由，反向代理已去掉前缀时直接处理；
+// 对外的路径前缀和地址记录在上下文中，供安装脚本等生成完整地址
+func BasePathMiddleware(basePath string, trustForwarded bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if basePath != "" && (req.URL.Path == basePath || strings.HasPrefix(req.URL.Path, basePath+"/")) {
+				req.URL.Path = strings.TrimPrefix(req.URL.Path, basePath)
+				if req.URL.Path == "" {
+					req.URL.Path = "/"
+				}
+				req.URL.RawPath = ""
+				req.RequestURI = req.URL.RequestURI()
+			}
+
+			host := req.Host
+			if trustForwarded {
+				if forwardedHost := req.Header.Get("X-Forwarded-Host"); forwardedHost != "" {
+					// 多级代理时取最外层
+					host = strings.TrimSpace(strings.Split(forwardedHost, ",")[0])
+				}
+			}
+			c.Set("basePath", basePath)
+			c.Set("baseURL", fmt.Sprintf("%s://%s%s", requestScheme(c, trustForwarded), host, basePath))
+			return next(c)
+		}
+	}
+}
+
+// requestScheme 返回请求的协议，仅在信任代理时使用 X-Forwarded-Proto 等代理头
+func requestScheme(c echo.Context, trustForwarded bool) string {
+	if c.IsTLS() {
+		return "https"
+	}
+	if trustForwarded {
+		return c.Scheme()
+	}
+	return "http"
+}
+
+// SecurityHeadersMiddleware 设置安全响应头和自定义响应头
+func SecurityHeadersMiddleware(cfg *config.HTTPConfig) echo.MiddlewareFunc {
+	csp := cfg.ContentSecurityPolicy
+	if len(cfg.FrameAncestors) > 0 {
+		// frame-ancestors 只能通过 CSP 设置，X-Frame-Options 无法表达多个来源
+		directive := "frame-ancestors " + strings.Join(cfg.FrameAncestors, " ")
+		if csp == "" {
+			csp = directive
+		} else {
+			csp = strings.TrimRight(csp, "; ") + "; " + directive
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Response().Header()
+			header.Set(echo.HeaderXContentTypeOptions, "nosniff")
+			header.Set("Referrer-Policy", "strict-origin-when-cross-origin")
+			if csp != "" {
+				header.Set(echo.HeaderContentSecurityPolicy, csp)
+			}
+			if cfg.HSTSMaxAge > 0 && requestScheme(c, cfg.TrustForwardedHeaders) == "https" {
+				header.Set(echo.HeaderStrictTransportSecurity, fmt.Sprintf("max-age=%d; includeSubDomains", cfg.HSTSMaxAge))
+			}
+			for name, value := range cfg.Headers {
+				header.Set(name, value)
+			}
+			return next(c)
+		}
+	}
+}
+
+// widgetMiddlewares 探针状态挂件接口的跨域和限流中间件，与全局 CORS 配置独立，按客户端 IP 每分钟限制请求次数
+func widgetMiddlewares(cfg *config.WidgetConfig) []echo.MiddlewareFunc {
+	if cfg == nil {
+		cfg = &config.WidgetConfig{}
+	}
+	allowOrigins := cfg.AllowOrigins
+	if len(allowOrigins) == 0 {
+		allowOrigins = []string{"*"}
+	}
+	perMinute := cfg.RateLimit
+	if perMinute <= 0 {
+		perMinute = defaultWidgetRateLimit
+	}
+
+	return []echo.MiddlewareFunc{
+		middleware.CORSWithConfig(middleware.CORSConfig{
+			AllowOrigins:  allowOrigins,
+			AllowMethods:  []string{echo.GET, echo.OPTIONS},
+			AllowHeaders:  []string{echo.HeaderAuthorization, "If-None-Match"},
+			ExposeHeaders: []string{"ETag"},
+			MaxAge:        86400,
+		}),
+		middleware.RateLimiterWithConfig(middleware.RateLimiterConfig{
+			Store: middleware.NewRateLimiterMemoryStoreWithConfig(middleware.RateLimiterMemoryStoreConfig{
+				Rate:      rate.Limit(float64(perMinute) / 60),
+				Burst:     perMinute,
+				ExpiresIn: 3 * time.Minute,
+			}),
+			// 按可信的客户端 IP 限流，未信任代理时为连接的对端地址，客户端无法通过每次更换 X-Forwarded-For 绕过限流
+			IdentifierExtractor: func(c echo.Context) (string, error) {
+				return utils.ClientIP(c), nil
+			},
+			ErrorHandler: func(c echo.Context, err error) error {
+				return c.JSON(http.StatusForbidden, orz.Map{"code": http.StatusForbidden, "message": "无法识别客户端"})
+			},
+			DenyHandler: func(c echo.Context, identifier string, err error) error {
+				return c.JSON(http.StatusTooManyRequests, orz.Map{"code": http.StatusTooManyRequests, "message": "请求过于频繁，请稍后再试"})
+			},
+		}),
+	}
+}
@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dushixiang/pika/internal/config"
+	"github.com/labstack/echo/v4"
+)
+
+// newForwardedRequest 构造带有代理头的请求，模拟客户端伪造或代理传入的 X-Forwarded-*
+func newForwardedRequest(remoteAddr string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/info", nil)
+	req.RemoteAddr = remoteAddr
+	req.Header.Set(echo.HeaderXForwardedFor, "1.2.3.4")
+	req.Header.Set(echo.HeaderXRealIP, "1.2.3.4")
+	req.Header.Set(echo.HeaderXForwardedProto, "https")
+	return req
+}
+
+func TestForwardedHeaders(t *testing.T) {
+	tests := []struct {
+		name       string
+		trust      bool
+		remoteAddr string
+		wantIP     string
+		wantURL    string
+		wantHSTS   bool
+	}{
+		{name: "默认不信任伪造的代理头", remoteAddr: "203.0.113.5:5000", wantIP: "203.0.113.5", wantURL: "http://example.com"},
+		{name: "信任内网代理传入的代理头", trust: true, remoteAddr: "10.0.0.2:5000", wantIP: "1.2.3.4", wantURL: "https://example.com", wantHSTS: true},
+		{name: "信任代理头时不信任公网客户端直接伪造的 IP", trust: true, remoteAddr: "203.0.113.5:5000", wantIP: "203.0.113.5", wantURL: "https://example.com", wantHSTS: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			setupHTTPMiddleware(e, &config.HTTPConfig{TrustForwardedHeaders: tt.trust, HSTSMaxAge: 3600})
+			var gotIP, gotURL string
+			e.GET("/info", func(c echo.Context) error {
+				gotIP = c.RealIP()
+				gotURL, _ = c.Get("baseURL").(string)
+				return c.NoContent(http.StatusOK)
+			})
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, newForwardedRequest(tt.remoteAddr))
+			if gotIP != tt.wantIP {
+				t.Errorf("RealIP = %s, 期望 %s", gotIP, tt.wantIP)
+			}
+			if gotURL != tt.wantURL {
+				t.Errorf("baseURL = %s, 期望 %s", gotURL, tt.wantURL)
+			}
+			if hsts := rec.Header().Get(echo.HeaderStrictTransportSecurity) != ""; hsts != tt.wantHSTS {
+				t.Errorf("HSTS = %v, 期望 %v", hsts, tt.wantHSTS)
+			}
+		})
+	}
+}
+
+func TestWidgetRateLimitIgnoresSpoofedIP(t *testing.T) {
+	e := echo.New()
+	setupHTTPMiddleware(e, &config.HTTPConfig{})
+	e.GET("/widget", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, widgetMiddlewares(&config.WidgetConfig{RateLimit: 2})...)
+
+	// 同一客户端每次伪造不同的 X-Forwarded-For，仍按连接地址计数
+	codes := make([]int, 0, 3)
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/widget", nil)
+		req.RemoteAddr = "203.0.113.5:5000"
+		req.Header.Set(echo.HeaderXForwardedFor, fmt.Sprintf("198.51.100.%d", i+1))
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		codes = append(codes, rec.Code)
+	}
+	if codes[0] != http.StatusOK || codes[1] != http.StatusOK || codes[2] != http.StatusTooManyRequests {
+		t.Fatalf("伪造 X-Forwarded-For 不应绕过限流, 状态码 %v", codes)
+	}
+}
@@ -12,6 +12,7 @@ type Agent struct {
 	Arch       string                      `json:"arch"`                                  // 架构
 	Version    string                      `json:"version"`                               // 探针版本
 	Tags       datatypes.JSONSlice[string] `json:"tags"`                                  // 标签
+	Region     string                      `gorm:"index" json:"region"`                   // 地区，如 华东、us-west，服务监控按地区统计响应时间
 	ExpireTime int64                       `json:"expireTime"`                            // 到期时间（时间戳毫秒）
 	Status     int                         `json:"status"`                                // 状态: 0-离线, 1-在线
 	Visibility string                      `gorm:"default:public" json:"visibility"`      // 可见性: public-匿名可见, private-登录可见
@@ -0,0 +1,15 @@
+package models
+
+// AgentSession 探针连接会话，记录探针每次连接到断开的时间段，用于统计可用率和离线时间线
+type AgentSession struct {
+	ID             int64  `gorm:"primaryKey;autoIncrement" json:"id"` // 会话ID
+	AgentID        string `gorm:"index" json:"agentId"`               // 探针ID
+	IP             string `json:"ip"`                                 // 连接时的IP地址
+	Version        string `json:"version"`                            // 连接时的探针版本
+	ConnectedAt    int64  `gorm:"index" json:"connectedAt"`           // 连接时间（时间戳毫秒）
+	DisconnectedAt int64  `gorm:"index" json:"disconnectedAt"`        // 断开时间（时间戳毫秒），0 表示仍在连接
+}
+
+func (AgentSession) TableName() string {
+	return "agent_sessions"
+}
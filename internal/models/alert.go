@@ -1,12 +1,14 @@
 package models
 
+import "gorm.io/datatypes"
+
 // AlertRecord 告警记录
 type AlertRecord struct {
 	ID          int64   `gorm:"primaryKey;autoIncrement" json:"id"`    // 记录ID
 	AgentID     string  `gorm:"index" json:"agentId"`                  // 探针ID
 	AgentName   string  `json:"agentName"`                             // 探针名称
 	AlertType   string  `json:"alertType"`                             // 告警类型: cpu, memory, disk, network
-	Message     string  `json:"message"`                               // 告警消息
+	Message     string  `json:"message"`                               // 告警消息（默认语言）
 	Threshold   float64 `json:"threshold"`                             // 告警阈值
 	ActualValue float64 `json:"actualValue"`                           // 实际值
 	Level       string  `json:"level"`                                 // 告警级别: info, warning, critical
@@ -15,6 +17,10 @@ type AlertRecord struct {
 	ResolvedAt  int64   `json:"resolvedAt,omitempty"`                  // 恢复时间（时间戳毫秒）
 	CreatedAt   int64   `json:"createdAt"`                             // 创建时间（时间戳毫秒）
 	UpdatedAt   int64   `json:"updatedAt" gorm:"autoUpdateTime:milli"` // 更新时间（时间戳毫秒）
+
+	MessageKey    string                                `json:"messageKey,omitempty"`   // 消息模板，为空时使用告警类型
+	MessageParams datatypes.JSONType[map[string]string] `json:"messageParams"`          // 消息模板参数，通知时按渠道语言重新生成消息
+	RefiredAfter  int64                                 `json:"refiredAfter,omitempty"` // 冷却期内再次触发时距上次恢复的秒数
 }
 
 func (AlertRecord) TableName() string {
@@ -23,18 +29,20 @@ func (AlertRecord) TableName() string {
 
 // AlertState 告警状态（持久化到数据库，用于判断是否持续超过阈值）
 type AlertState struct {
-	ID            string  `gorm:"primaryKey" json:"id"`                  // 状态ID（格式：agentId:configId:alertType）
-	AgentID       string  `gorm:"index" json:"agentId"`                  // 探针ID
-	AlertType     string  `gorm:"index" json:"alertType"`                // 告警类型
-	Value         float64 `json:"value"`                                 // 当前值
-	Threshold     float64 `json:"threshold"`                             // 阈值
-	StartTime     int64   `json:"startTime"`                             // 开始超过阈值的时间
-	Duration      int     `json:"duration"`                              // 需要持续的时间（秒）
-	LastCheckTime int64   `json:"lastCheckTime"`                         // 上次检查时间
-	IsFiring      bool    `json:"isFiring"`                              // 是否正在告警
-	LastRecordID  int64   `json:"lastRecordId"`                          // 最后一条告警记录ID
-	CreatedAt     int64   `json:"createdAt"`                             // 创建时间（时间戳毫秒）
-	UpdatedAt     int64   `json:"updatedAt" gorm:"autoUpdateTime:milli"` // 更新时间（时间戳毫秒）
+	ID               string  `gorm:"primaryKey" json:"id"`                  // 状态ID（格式：agentId:configId:alertType）
+	AgentID          string  `gorm:"index" json:"agentId"`                  // 探针ID
+	AlertType        string  `gorm:"index" json:"alertType"`                // 告警类型
+	Value            float64 `json:"value"`                                 // 当前值
+	Threshold        float64 `json:"threshold"`                             // 阈值
+	StartTime        int64   `json:"startTime"`                             // 开始超过阈值的时间
+	Duration         int     `json:"duration"`                              // 需要持续的时间（秒）
+	LastCheckTime    int64   `json:"lastCheckTime"`                         // 上次检查时间
+	IsFiring         bool    `json:"isFiring"`                              // 是否正在告警
+	LastRecordID     int64   `json:"lastRecordId"`                          // 最后一条告警记录ID
+	ResolvedRecordID int64   `json:"resolvedRecordId"`                      // 最近一次恢复的告警记录ID，冷却期内再次触发时合并到该记录
+	ResolvedAt       int64   `json:"resolvedAt"`                            // 最近一次恢复的时间（时间戳毫秒）
+	CreatedAt        int64   `json:"createdAt"`                             // 创建时间（时间戳毫秒）
+	UpdatedAt        int64   `json:"updatedAt" gorm:"autoUpdateTime:milli"` // 更新时间（时间戳毫秒）
 }
 
 func (AlertState) TableName() string {
@@ -0,0 +1,32 @@
+package models
+
+// 远程命令执行状态
+const (
+	CommandStatusRunning = "running"
+	CommandStatusSuccess = "success"
+	CommandStatusError   = "error"
+	CommandStatusTimeout = "timeout"
+)
+
+// CommandExecution 远程命令执行记录
+type CommandExecution struct {
+	ID         int64  `gorm:"primaryKey;autoIncrement" json:"id"` // 记录ID
+	AgentID    string `gorm:"index" json:"agentId"`               // 探针ID
+	Name       string `json:"name"`                               // 命令名称: restart_service, clear_cache, run_script
+	Target     string `json:"target"`                             // 命令目标
+	Timeout    int    `json:"timeout"`                            // 超时时间（秒）
+	Status     string `gorm:"index" json:"status"`                // 状态: running, success, error, timeout
+	CommandID  string `gorm:"index" json:"commandId"`             // 下发的指令ID
+	Output     string `gorm:"type:text" json:"output"`            // 执行输出
+	Error      string `json:"error"`                              // 错误信息
+	ExitCode   int    `json:"exitCode"`                           // 脚本的退出码
+	Duration   int64  `json:"duration"`                           // 探针上的执行耗时（毫秒）
+	Username   string `gorm:"index" json:"username"`              // 下发命令的用户
+	ClientIP   string `json:"clientIp"`                           // 用户的 IP 地址
+	CreatedAt  int64  `gorm:"index" json:"createdAt"`             // 下发时间（时间戳毫秒）
+	FinishedAt int64  `json:"finishedAt"`                         // 结束时间（时间戳毫秒）
+}
+
+func (CommandExecution) TableName() string {
+	return "command_executions"
+}
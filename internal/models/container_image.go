@@ -0,0 +1,20 @@
+package models
+
+// ContainerImage 探针上运行中容器的镜像
+type ContainerImage struct {
+	ID             string `gorm:"primaryKey" json:"id"`                  // ID（agentId:containerId）
+	AgentID        string `gorm:"index" json:"agentId"`                  // 探针ID
+	ContainerID    string `json:"containerId"`                           // 容器ID
+	ContainerName  string `json:"containerName"`                         // 容器名称
+	Image          string `gorm:"index" json:"image"`                    // 镜像引用，如 nginx:1.25
+	ImageID        string `json:"imageId"`                               // 本地镜像ID
+	Digest         string `json:"digest"`                                // 运行中镜像的仓库摘要，本地构建的镜像为空
+	RegistryDigest string `json:"registryDigest"`                        // 镜像标签在仓库中的当前摘要
+	Drift          bool   `json:"drift"`                                 // 运行中镜像与仓库中的标签不一致
+	CheckedAt      int64  `json:"checkedAt"`                             // 最后一次查询仓库的时间（时间戳毫秒）
+	UpdatedAt      int64  `json:"updatedAt" gorm:"autoUpdateTime:milli"` // 更新时间（时间戳毫秒）
+}
+
+func (ContainerImage) TableName() string {
+	return "container_images"
+}
@@ -0,0 +1,29 @@
+package models
+
+// 容器操作的执行状态
+const (
+	DockerOperationStatusRunning = "running"
+	DockerOperationStatusSuccess = "success"
+	DockerOperationStatusError   = "error"
+)
+
+// DockerOperation 容器操作记录
+type DockerOperation struct {
+	ID         int64  `gorm:"primaryKey;autoIncrement" json:"id"` // 记录ID
+	AgentID    string `gorm:"index" json:"agentId"`               // 探针ID
+	Action     string `json:"action"`                             // 操作: start, stop, restart
+	Container  string `json:"container"`                          // 容器ID或名称
+	Name       string `json:"name"`                               // 用户操作时看到的容器名称
+	Status     string `gorm:"index" json:"status"`                // 状态: running, success, error
+	CommandID  string `gorm:"index" json:"commandId"`             // 下发的指令ID
+	Output     string `gorm:"type:text" json:"output"`            // docker 命令的输出
+	Error      string `json:"error"`                              // 错误信息
+	Username   string `gorm:"index" json:"username"`              // 操作用户
+	ClientIP   string `json:"clientIp"`                           // 用户的 IP 地址
+	CreatedAt  int64  `gorm:"index" json:"createdAt"`             // 操作时间（时间戳毫秒）
+	FinishedAt int64  `json:"finishedAt"`                         // 结束时间（时间戳毫秒）
+}
+
+func (DockerOperation) TableName() string {
+	return "docker_operations"
+}
@@ -15,6 +15,49 @@ func (CPUMetric) TableName() string {
 	return "cpu_metrics"
 }
 
+// CPUCoreMetric 每核CPU使用率指标，服务端开启每核采集后探针才会上报
+type CPUCoreMetric struct {
+	ID           uint    `gorm:"primaryKey;autoIncrement" json:"id"`
+	AgentID      string  `gorm:"index:idx_cpucore_agent_core_ts,priority:1" json:"agentId"`                        // 探针ID
+	Core         int     `gorm:"index:idx_cpucore_agent_core_ts,priority:2" json:"core"`                           // 逻辑核心序号
+	UsagePercent float64 `json:"usagePercent"`                                                                     // 使用率
+	Timestamp    int64   `gorm:"index:idx_cpucore_agent_core_ts,priority:3;index:idx_cpucore_ts" json:"timestamp"` // 时间戳（毫秒）
+}
+
+func (CPUCoreMetric) TableName() string {
+	return "cpu_core_metrics"
+}
+
+// CPUTimesMetric CPU时间分布指标，为两次采集之间各状态占用的百分比
+type CPUTimesMetric struct {
+	ID            uint    `gorm:"primaryKey;autoIncrement" json:"id"`
+	AgentID       string  `gorm:"index:idx_cputimes_agent_ts,priority:1" json:"agentId"`                         // 探针ID
+	UserPercent   float64 `json:"userPercent"`                                                                   // 用户态（含 nice）
+	SystemPercent float64 `json:"systemPercent"`                                                                 // 内核态（含 irq、softirq）
+	IOWaitPercent float64 `json:"iowaitPercent"`                                                                 // 等待 IO
+	StealPercent  float64 `json:"stealPercent"`                                                                  // 被宿主机其他虚拟机占用
+	IdlePercent   float64 `json:"idlePercent"`                                                                   // 空闲
+	Timestamp     int64   `gorm:"index:idx_cputimes_agent_ts,priority:2;index:idx_cputimes_ts" json:"timestamp"` // 时间戳（毫秒）
+}
+
+func (CPUTimesMetric) TableName() string {
+	return "cpu_times_metrics"
+}
+
+// PressureMetric Linux PSI 压力指标，每种资源一行，记录最近 10 秒的停顿时间占比
+type PressureMetric struct {
+	ID          uint    `gorm:"primaryKey;autoIncrement" json:"id"`
+	AgentID     string  `gorm:"index:idx_pressure_agent_resource_ts,priority:1" json:"agentId"`                         // 探针ID
+	Resource    string  `gorm:"index:idx_pressure_agent_resource_ts,priority:2" json:"resource"`                        // 资源: cpu, memory, io
+	SomePercent float64 `json:"somePercent"`                                                                            // 至少一个任务停顿的时间占比
+	FullPercent float64 `json:"fullPercent"`                                                                            // 所有任务同时停顿的时间占比，旧内核的 cpu 没有该项时为 0
+	Timestamp   int64   `gorm:"index:idx_pressure_agent_resource_ts,priority:3;index:idx_pressure_ts" json:"timestamp"` // 时间戳（毫秒）
+}
+
+func (PressureMetric) TableName() string {
+	return "pressure_metrics"
+}
+
 // MemoryMetric 内存指标
 type MemoryMetric struct {
 	ID           uint    `gorm:"primaryKey;autoIncrement" json:"id"`
@@ -167,20 +210,31 @@ func (HostMetric) TableName() string {
 
 // MonitorMetric 监控指标
 type MonitorMetric struct {
-	ID             uint   `gorm:"primaryKey;autoIncrement" json:"id"`
-	AgentId        string `gorm:"index:idx_mon_agent_monitor_ts,priority:1" json:"agentId"`                                                             // 探针ID
-	MonitorId      string `gorm:"index:idx_mon_agent_monitor_ts,priority:2;index:idx_mon_type_monitor_ts,priority:2" json:"monitorId"`                  // 监控项ID
-	Type           string `gorm:"index:idx_mon_type_monitor_ts,priority:1" json:"type"`                                                                 // 监控类型: http, tcp
-	Target         string `json:"target"`                                                                                                               // 监控目标
-	Status         string `json:"status"`                                                                                                               // 状态: up, down
-	StatusCode     int    `json:"statusCode"`                                                                                                           // HTTP状态码
-	ResponseTime   int64  `json:"responseTime"`                                                                                                         // 响应时间(毫秒)
-	Error          string `json:"error"`                                                                                                                // 错误信息
-	Message        string `json:"message"`                                                                                                              // 附加信息
-	ContentMatch   bool   `json:"contentMatch"`                                                                                                         // 内容匹配结果
-	CertExpiryTime int64  `json:"certExpiryTime"`                                                                                                       // 证书过期时间(毫秒时间戳), 0表示无证书
-	CertDaysLeft   int    `json:"certDaysLeft"`                                                                                                         // 证书剩余天数
-	Timestamp      int64  `gorm:"index:idx_mon_agent_monitor_ts,priority:3;index:idx_mon_type_monitor_ts,priority:3;index:idx_mon_ts" json:"timestamp"` // 时间戳（毫秒）
+	ID                uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	AgentId           string `gorm:"index:idx_mon_agent_monitor_ts,priority:1" json:"agentId"`                                                             // 探针ID
+	MonitorId         string `gorm:"index:idx_mon_agent_monitor_ts,priority:2;index:idx_mon_type_monitor_ts,priority:2" json:"monitorId"`                  // 监控项ID
+	Type              string `gorm:"index:idx_mon_type_monitor_ts,priority:1" json:"type"`                                                                 // 监控类型: http, tcp
+	Target            string `json:"target"`                                                                                                               // 监控目标
+	Status            string `json:"status"`                                                                                                               // 状态: up, down
+	StatusCode        int    `json:"statusCode"`                                                                                                           // HTTP状态码
+	ResponseTime      int64  `json:"responseTime"`                                                                                                         // 响应时间(毫秒)
+	Error             string `json:"error"`                                                                                                                // 错误信息
+	Message           string `json:"message"`                                                                                                              // 附加信息
+	ContentMatch      bool   `json:"contentMatch"`                                                                                                         // 内容匹配结果
+	ContentDetail     string `json:"contentDetail"`                                                                                                        // 内容断言的逐条结果
+	CertExpiryTime    int64  `json:"certExpiryTime"`                                                                                                       // 证书过期时间(毫秒时间戳), 0表示无证书
+	CertDaysLeft      int    `json:"certDaysLeft"`                                                                                                         // 证书剩余天数
+	Checks            int    `gorm:"default:1" json:"checks"`                                                                                              // 本条记录合并的连续检测次数（状态不变时按采样间隔合并保存）
+	ResponseHistogram string `gorm:"type:text" json:"responseHistogram"`                                                                                   // 合并记录中各次检测响应时间的分布，格式为 桶序号:次数，逗号分隔
+	Timestamp         int64  `gorm:"index:idx_mon_agent_monitor_ts,priority:3;index:idx_mon_type_monitor_ts,priority:3;index:idx_mon_ts" json:"timestamp"` // 时间戳（毫秒）
+}
+
+// CheckCount 本条记录代表的检测次数，兼容未记录次数的旧数据
+func (m MonitorMetric) CheckCount() int64 {
+	if m.Checks <= 0 {
+		return 1
+	}
+	return int64(m.Checks)
 }
 
 func (MonitorMetric) TableName() string {
@@ -196,6 +250,8 @@ type AggregatedCPUMetricModel struct {
 	BucketSeconds int     `gorm:"index:idx_cpuagg_agent_bucket,priority:2;uniqueIndex:ux_cpuagg_bucket,priority:2" json:"bucketSeconds"`
 	BucketStart   int64   `gorm:"index:idx_cpuagg_agent_bucket,priority:3;uniqueIndex:ux_cpuagg_bucket,priority:3" json:"bucketStart"` // 毫秒
 	MaxUsage      float64 `json:"maxUsage"`
+	AvgUsage      float64 `json:"avgUsage"`
+	MinUsage      float64 `json:"minUsage"`
 	LogicalCores  int     `json:"logicalCores"`
 }
 
@@ -210,6 +266,8 @@ type AggregatedMemoryMetricModel struct {
 	BucketSeconds int     `gorm:"index:idx_memagg_agent_bucket,priority:2;uniqueIndex:ux_memagg_bucket,priority:2" json:"bucketSeconds"`
 	BucketStart   int64   `gorm:"index:idx_memagg_agent_bucket,priority:3;uniqueIndex:ux_memagg_bucket,priority:3" json:"bucketStart"` // 毫秒
 	MaxUsage      float64 `json:"maxUsage"`
+	AvgUsage      float64 `json:"avgUsage"`
+	MinUsage      float64 `json:"minUsage"`
 	Total         uint64  `json:"total"`
 }
 
@@ -225,6 +283,8 @@ type AggregatedDiskMetricModel struct {
 	BucketStart   int64   `gorm:"index:idx_diskagg_agent_bucket_mp,priority:3;uniqueIndex:ux_diskagg_bucket,priority:3" json:"bucketStart"` // 毫秒
 	MountPoint    string  `gorm:"index:idx_diskagg_agent_bucket_mp,priority:4;uniqueIndex:ux_diskagg_bucket,priority:4" json:"mountPoint"`
 	MaxUsage      float64 `json:"maxUsage"`
+	AvgUsage      float64 `json:"avgUsage"`
+	MinUsage      float64 `json:"minUsage"`
 	Total         uint64  `json:"total"`
 }
 
@@ -241,6 +301,8 @@ type AggregatedNetworkMetricModel struct {
 	Interface     string  `gorm:"index:idx_netagg_agent_bucket_iface,priority:4;uniqueIndex:ux_netagg_bucket,priority:4" json:"interface"`
 	MaxSentRate   float64 `json:"maxSentRate"`
 	MaxRecvRate   float64 `json:"maxRecvRate"`
+	AvgSentRate   float64 `json:"avgSentRate"`
+	AvgRecvRate   float64 `json:"avgRecvRate"`
 }
 
 func (AggregatedNetworkMetricModel) TableName() string {
@@ -305,6 +367,56 @@ func (AggregatedGPUMetricModel) TableName() string {
 	return "gpu_metrics_aggs"
 }
 
+// AggregatedCPUCoreMetricModel 每核CPU聚合表
+type AggregatedCPUCoreMetricModel struct {
+	ID            uint    `gorm:"primaryKey;autoIncrement" json:"id"`
+	AgentID       string  `gorm:"index:idx_cpucoreagg_agent_bucket_core,priority:1;uniqueIndex:ux_cpucoreagg_bucket,priority:1" json:"agentId"`
+	BucketSeconds int     `gorm:"index:idx_cpucoreagg_agent_bucket_core,priority:2;uniqueIndex:ux_cpucoreagg_bucket,priority:2" json:"bucketSeconds"`
+	BucketStart   int64   `gorm:"index:idx_cpucoreagg_agent_bucket_core,priority:3;uniqueIndex:ux_cpucoreagg_bucket,priority:3" json:"bucketStart"` // 毫秒
+	Core          int     `gorm:"index:idx_cpucoreagg_agent_bucket_core,priority:4;uniqueIndex:ux_cpucoreagg_bucket,priority:4" json:"core"`
+	MaxUsage      float64 `json:"maxUsage"`
+	AvgUsage      float64 `json:"avgUsage"`
+}
+
+func (AggregatedCPUCoreMetricModel) TableName() string {
+	return "cpu_core_metrics_aggs"
+}
+
+// AggregatedCPUTimesMetricModel CPU时间分布聚合表
+type AggregatedCPUTimesMetricModel struct {
+	ID            uint    `gorm:"primaryKey;autoIncrement" json:"id"`
+	AgentID       string  `gorm:"index:idx_cputimesagg_agent_bucket,priority:1;uniqueIndex:ux_cputimesagg_bucket,priority:1" json:"agentId"`
+	BucketSeconds int     `gorm:"index:idx_cputimesagg_agent_bucket,priority:2;uniqueIndex:ux_cputimesagg_bucket,priority:2" json:"bucketSeconds"`
+	BucketStart   int64   `gorm:"index:idx_cputimesagg_agent_bucket,priority:3;uniqueIndex:ux_cputimesagg_bucket,priority:3" json:"bucketStart"` // 毫秒
+	AvgUser       float64 `json:"avgUser"`
+	AvgSystem     float64 `json:"avgSystem"`
+	AvgIOWait     float64 `json:"avgIowait"`
+	AvgSteal      float64 `json:"avgSteal"`
+	MaxSteal      float64 `json:"maxSteal"`
+	AvgIdle       float64 `json:"avgIdle"`
+}
+
+func (AggregatedCPUTimesMetricModel) TableName() string {
+	return "cpu_times_metrics_aggs"
+}
+
+// AggregatedPressureMetricModel PSI 压力聚合表
+type AggregatedPressureMetricModel struct {
+	ID            uint    `gorm:"primaryKey;autoIncrement" json:"id"`
+	AgentID       string  `gorm:"index:idx_pressureagg_agent_bucket_resource,priority:1;uniqueIndex:ux_pressureagg_bucket,priority:1" json:"agentId"`
+	BucketSeconds int     `gorm:"index:idx_pressureagg_agent_bucket_resource,priority:2;uniqueIndex:ux_pressureagg_bucket,priority:2" json:"bucketSeconds"`
+	BucketStart   int64   `gorm:"index:idx_pressureagg_agent_bucket_resource,priority:3;uniqueIndex:ux_pressureagg_bucket,priority:3" json:"bucketStart"` // 毫秒
+	Resource      string  `gorm:"index:idx_pressureagg_agent_bucket_resource,priority:4;uniqueIndex:ux_pressureagg_bucket,priority:4" json:"resource"`
+	MaxSome       float64 `json:"maxSome"`
+	AvgSome       float64 `json:"avgSome"`
+	MaxFull       float64 `json:"maxFull"`
+	AvgFull       float64 `json:"avgFull"`
+}
+
+func (AggregatedPressureMetricModel) TableName() string {
+	return "pressure_metrics_aggs"
+}
+
 // AggregatedTemperatureMetricModel 温度聚合表
 type AggregatedTemperatureMetricModel struct {
 	ID             uint    `gorm:"primaryKey;autoIncrement" json:"id"`
@@ -0,0 +1,26 @@
+package models
+
+import "gorm.io/datatypes"
+
+// MonitorCert 探针最近一次检测到的监控目标证书详情
+type MonitorCert struct {
+	ID                 string                      `gorm:"primaryKey" json:"id"`                  // ID，格式为 探针ID:监控项ID
+	AgentID            string                      `gorm:"index" json:"agentId"`                  // 探针ID
+	AgentName          string                      `gorm:"-" json:"agentName,omitempty"`          // 探针名称（不存储在数据库，仅用于 API 返回）
+	MonitorId          string                      `gorm:"index" json:"monitorId"`                // 监控项ID
+	Subject            string                      `json:"subject"`                               // 使用者
+	Issuer             string                      `json:"issuer"`                                // 签发者
+	SANs               datatypes.JSONSlice[string] `json:"sans"`                                  // 备用名称
+	SignatureAlgorithm string                      `json:"signatureAlgorithm"`                    // 签名算法
+	NotBefore          int64                       `json:"notBefore"`                             // 生效时间(毫秒时间戳)
+	NotAfter           int64                       `json:"notAfter"`                              // 过期时间(毫秒时间戳)
+	Fingerprint        string                      `json:"fingerprint"`                           // 证书 SHA-256 指纹
+	ChainLength        int                         `json:"chainLength"`                           // 服务器返回的证书数量
+	ChainValid         bool                        `json:"chainValid"`                            // 证书链是否校验通过
+	ChainError         string                      `json:"chainError"`                            // 证书链校验失败原因
+	UpdatedAt          int64                       `gorm:"autoUpdateTime:milli" json:"updatedAt"` // 更新时间
+}
+
+func (MonitorCert) TableName() string {
+	return "monitor_certs"
+}
@@ -5,12 +5,19 @@ type MonitorStats struct {
 	ID               string  `gorm:"primaryKey" json:"id"`                  // ID
 	AgentID          string  `json:"agentId"`                               // 探针ID
 	AgentName        string  `gorm:"-" json:"agentName,omitempty"`          // 探针名称（不存储在数据库，仅用于 API 返回）
+	Region           string  `json:"region"`                                // 探针所在地区
 	MonitorId        string  `json:"monitorId"`                             // 监控项ID
 	MonitorName      string  `gorm:"-" json:"name"`                         // 监控项名称（不存储在数据库，仅用于 API 返回）
 	MonitorType      string  `json:"type"`                                  // 监控类型
 	Target           string  `json:"target"`                                // 目标地址
 	CurrentResponse  int64   `json:"currentResponse"`                       // 当前响应时间(ms)
 	AvgResponse24h   int64   `json:"avgResponse24h"`                        // 24小时平均响应时间(ms)
+	P50Response24h   int64   `json:"p50Response24h"`                        // 24小时响应时间中位数(ms)
+	P95Response24h   int64   `json:"p95Response24h"`                        // 24小时 p95 响应时间(ms)
+	P99Response24h   int64   `json:"p99Response24h"`                        // 24小时 p99 响应时间(ms)
+	P50Response30d   int64   `json:"p50Response30d"`                        // 30天响应时间中位数(ms)
+	P95Response30d   int64   `json:"p95Response30d"`                        // 30天 p95 响应时间(ms)
+	P99Response30d   int64   `json:"p99Response30d"`                        // 30天 p99 响应时间(ms)
 	Uptime24h        float64 `json:"uptime24h"`                             // 24小时在线率(百分比)
 	Uptime7d         float64 `json:"uptime7d"`                              // 7天在线率(百分比)
 	CertExpiryDate   int64   `json:"certExpiryDate"`                        // 证书过期时间(毫秒时间戳)，0表示无证书
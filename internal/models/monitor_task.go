@@ -7,25 +7,65 @@ import (
 
 // MonitorTask 描述一个服务监控任务
 type MonitorTask struct {
-	ID               string                                         `gorm:"primaryKey" json:"id"`                  // 任务 ID
-	Name             string                                         `gorm:"uniqueIndex" json:"name"`               // 任务名称
-	Type             string                                         `gorm:"index" json:"type"`                     // 监控类型 http/tcp
-	Target           string                                         `json:"target"`                                // 目标地址
-	Description      string                                         `json:"description"`                           // 描述信息
-	Enabled          bool                                           `json:"enabled"`                               // 是否启用
-	ShowTargetPublic bool                                           `json:"showTargetPublic"`                      // 在公开页面是否显示目标地址
-	Visibility       string                                         `gorm:"default:public" json:"visibility"`      // 可见性: public-匿名可见, private-登录可见
-	Interval         int                                            `json:"interval"`                              // 检测频率（秒），默认 60
-	AgentIds         datatypes.JSONSlice[string]                    `json:"agentIds"`                              // 指定的探针 ID 列表（JSON 数组）
-	AgentNames       []string                                       `gorm:"-" json:"agentNames"`                   // 指定的探针名称列表
-	Tags             datatypes.JSONSlice[string]                    `json:"tags"`                                  // 指定的标签列表（JSON 数组），拥有这些标签的探针都会执行此监控
-	HTTPConfig       datatypes.JSONType[protocol.HTTPMonitorConfig] `json:"httpConfig"`                            // HTTP 监控配置
-	TCPConfig        datatypes.JSONType[protocol.TCPMonitorConfig]  `json:"tcpConfig"`                             // TCP 监控配置
-	ICMPConfig       datatypes.JSONType[protocol.ICMPMonitorConfig] `json:"icmpConfig"`                            // ICMP 监控配置
-	CreatedAt        int64                                          `gorm:"autoCreateTime:milli" json:"createdAt"` // 创建时间
-	UpdatedAt        int64                                          `gorm:"autoUpdateTime:milli" json:"updatedAt"` // 更新时间
+	ID                 string                                                `gorm:"primaryKey" json:"id"`                  // 任务 ID
+	Name               string                                                `gorm:"uniqueIndex" json:"name"`               // 任务名称
+	Type               string                                                `gorm:"index" json:"type"`                     // 监控类型 http/tcp/udp/icmp/transaction/smtp/imap/pop3/push
+	Target             string                                                `json:"target"`                                // 目标地址
+	Description        string                                                `json:"description"`                           // 描述信息
+	Enabled            bool                                                  `json:"enabled"`                               // 是否启用
+	ShowTargetPublic   bool                                                  `json:"showTargetPublic"`                      // 在公开页面是否显示目标地址
+	Visibility         string                                                `gorm:"default:public" json:"visibility"`      // 可见性: public-匿名可见, private-登录可见
+	Interval           int                                                   `json:"interval"`                              // 检测频率（秒），默认 60
+	Retries            int                                                   `json:"retries"`                               // 检测失败后的重试次数，全部失败才视为离线
+	AgentIds           datatypes.JSONSlice[string]                           `json:"agentIds"`                              // 指定的探针 ID 列表（JSON 数组）
+	AgentNames         []string                                              `gorm:"-" json:"agentNames"`                   // 指定的探针名称列表
+	Tags               datatypes.JSONSlice[string]                           `json:"tags"`                                  // 指定的标签列表（JSON 数组），拥有这些标签的探针都会执行此监控
+	Group              string                                                `gorm:"index" json:"group"`                    // 监控分组，如 API、Edge，为空表示不分组
+	DownQuorum         int                                                   `json:"downQuorum"`                            // 至少多少个探针检测离线才视为离线，0 表示所有探针都离线才视为离线
+	SLATarget          float64                                               `json:"slaTarget"`                             // SLA 目标可用率（百分比），如 99.9，0 表示未设置
+	HTTPConfig         datatypes.JSONType[protocol.HTTPMonitorConfig]        `json:"httpConfig"`                            // HTTP 监控配置
+	TCPConfig          datatypes.JSONType[protocol.TCPMonitorConfig]         `json:"tcpConfig"`                             // TCP 监控配置
+	UDPConfig          datatypes.JSONType[protocol.UDPMonitorConfig]         `json:"udpConfig"`                             // UDP 监控配置
+	ICMPConfig         datatypes.JSONType[protocol.ICMPMonitorConfig]        `json:"icmpConfig"`                            // ICMP 监控配置
+	TransactionConfig  datatypes.JSONType[protocol.TransactionMonitorConfig] `json:"transactionConfig"`                     // 多步骤事务监控配置
+	MailConfig         datatypes.JSONType[protocol.MailMonitorConfig]        `json:"mailConfig"`                            // 邮件服务监控配置
+	Proxy              datatypes.JSONType[protocol.ProxyConfig]              `json:"proxy"`                                 // 出口代理，用于 HTTP、事务和 TCP 监控
+	PushToken          string                                                `gorm:"index" json:"pushToken"`                // 被动心跳监控的推送令牌
+	PushGracePeriod    int                                                   `json:"pushGracePeriod"`                       // 被动心跳监控的宽限时间（秒），超过检测频率加宽限时间未收到心跳视为离线
+	LastPushAt         int64                                                 `json:"lastPushAt"`                            // 最后一次收到心跳的时间
+	MaintenanceWindows datatypes.JSONSlice[MonitorMaintenanceWindow]         `json:"maintenanceWindows"`                    // 维护时间窗口，期间的离线结果不计入可用率且不触发服务下线告警
+	Webhook            datatypes.JSONType[MonitorWebhookConfig]              `json:"webhook"`                               // 状态变化回调配置
+	CreatedAt          int64                                                 `gorm:"autoCreateTime:milli" json:"createdAt"` // 创建时间
+	UpdatedAt          int64                                                 `gorm:"autoUpdateTime:milli" json:"updatedAt"` // 更新时间
 }
 
 func (MonitorTask) TableName() string {
 	return "monitor_tasks"
 }
+
+// MonitorWebhookConfig 监控项状态变化回调配置，每次状态在 up、down、degraded 之间切换时调用
+type MonitorWebhookConfig struct {
+	Enabled bool              `json:"enabled"`           // 是否启用
+	URL     string            `json:"url"`               // 回调地址
+	Method  string            `json:"method,omitempty"`  // 请求方法，默认 POST
+	Headers map[string]string `json:"headers,omitempty"` // 自定义请求头
+	Secret  string            `json:"secret,omitempty"`  // 签名密钥，配置后请求头 X-Pika-Signature 携带请求体的 HMAC-SHA256 签名
+}
+
+// 维护时间窗口类型
+const (
+	MaintenanceOnce   = "once"   // 一次性
+	MaintenanceDaily  = "daily"  // 每天
+	MaintenanceWeekly = "weekly" // 每周
+)
+
+// MonitorMaintenanceWindow 监控项的维护时间窗口
+// 每天和每周的窗口使用 RecurringWindow 中的时间，结束时间早于开始时间时表示跨天，例如 23:00 - 02:00
+type MonitorMaintenanceWindow struct {
+	Name     string `json:"name,omitempty"`     // 名称
+	Type     string `json:"type"`               // 类型 once/daily/weekly
+	StartAt  int64  `json:"startAt,omitempty"`  // 一次性窗口的开始时间（毫秒）
+	EndAt    int64  `json:"endAt,omitempty"`    // 一次性窗口的结束时间（毫秒）
+	Timezone string `json:"timezone,omitempty"` // 时区，如 Asia/Shanghai，默认使用服务端时区
+	RecurringWindow
+}
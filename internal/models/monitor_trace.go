@@ -0,0 +1,25 @@
+package models
+
+import (
+	"github.com/dushixiang/pika/internal/protocol"
+	"gorm.io/datatypes"
+)
+
+// MonitorTrace 监控项变为离线时探针执行的路径追踪，通过检测时间关联到对应的离线检测记录
+type MonitorTrace struct {
+	ID         int64                                  `gorm:"primaryKey;autoIncrement" json:"id"`    // ID
+	AgentID    string                                 `gorm:"index" json:"agentId"`                  // 探针ID
+	AgentName  string                                 `gorm:"-" json:"agentName,omitempty"`          // 探针名称（不存储在数据库，仅用于 API 返回）
+	MonitorId  string                                 `gorm:"index" json:"monitorId"`                // 监控项ID
+	Host       string                                 `json:"host"`                                  // 追踪的目标主机
+	Tool       string                                 `json:"tool"`                                  // 使用的工具: mtr, traceroute
+	CheckedAt  int64                                  `gorm:"index" json:"checkedAt"`                // 离线检测的时间(毫秒时间戳)
+	CheckError string                                 `json:"checkError"`                            // 离线检测的错误信息
+	Hops       datatypes.JSONSlice[protocol.TraceHop] `json:"hops"`                                  // 逐跳结果
+	Error      string                                 `json:"error"`                                 // 追踪失败原因
+	CreatedAt  int64                                  `gorm:"autoCreateTime:milli" json:"createdAt"` // 创建时间
+}
+
+func (MonitorTrace) TableName() string {
+	return "monitor_traces"
+}
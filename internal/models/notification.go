@@ -0,0 +1,42 @@
+package models
+
+import "gorm.io/datatypes"
+
+// NotificationJob 通知发送任务，发送失败后按指数退避重试，超过最大次数后进入死信列表
+type NotificationJob struct {
+	ID            int64                           `gorm:"primaryKey;autoIncrement" json:"id"`    // 任务ID
+	AlertRecordID int64                           `gorm:"index" json:"alertRecordId"`            // 关联的告警记录ID
+	AgentID       string                          `gorm:"index" json:"agentId"`                  // 探针ID
+	ChannelType   string                          `json:"channelType"`                           // 通知渠道类型
+	Record        datatypes.JSONType[AlertRecord] `json:"record"`                                // 入队时的告警记录快照
+	Agent         datatypes.JSONType[Agent]       `json:"agent"`                                 // 入队时的探针信息快照
+	Status        string                          `gorm:"index" json:"status"`                   // 状态: pending（等待发送）, dead（死信）
+	Attempts      int                             `json:"attempts"`                              // 已尝试次数
+	MaxAttempts   int                             `json:"maxAttempts"`                           // 最大尝试次数
+	NextAttemptAt int64                           `gorm:"index" json:"nextAttemptAt"`            // 下次尝试时间（时间戳毫秒）
+	LastError     string                          `json:"lastError"`                             // 最近一次失败原因
+	CreatedAt     int64                           `json:"createdAt"`                             // 创建时间（时间戳毫秒）
+	UpdatedAt     int64                           `json:"updatedAt" gorm:"autoUpdateTime:milli"` // 更新时间（时间戳毫秒）
+}
+
+func (NotificationJob) TableName() string {
+	return "notification_jobs"
+}
+
+// NotificationLog 通知发送日志，记录每一次发送尝试
+type NotificationLog struct {
+	ID            int64  `gorm:"primaryKey;autoIncrement" json:"id"` // 日志ID
+	JobID         int64  `gorm:"index" json:"jobId"`                 // 通知任务ID
+	AlertRecordID int64  `gorm:"index" json:"alertRecordId"`         // 关联的告警记录ID
+	AgentID       string `gorm:"index" json:"agentId"`               // 探针ID
+	ChannelType   string `gorm:"index" json:"channelType"`           // 通知渠道类型
+	Attempt       int    `json:"attempt"`                            // 第几次尝试
+	Status        string `gorm:"index" json:"status"`                // 状态: success, failed, skipped（渠道已删除或禁用）
+	LatencyMs     int64  `json:"latencyMs"`                          // 发送耗时（毫秒）
+	Error         string `json:"error"`                              // 失败原因
+	CreatedAt     int64  `gorm:"index" json:"createdAt"`             // 发送时间（时间戳毫秒）
+}
+
+func (NotificationLog) TableName() string {
+	return "notification_logs"
+}
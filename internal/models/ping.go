@@ -0,0 +1,41 @@
+package models
+
+// PingTarget 探针的 Ping 目标（网关、上游 DNS、关键服务等），用于跟踪网络质量
+type PingTarget struct {
+	ID        string `gorm:"primaryKey" json:"id"`                  // 目标ID (UUID)
+	AgentID   string `gorm:"index" json:"agentId"`                  // 探针ID
+	Name      string `json:"name"`                                  // 目标名称
+	Target    string `json:"target"`                                // 目标地址（IP 或域名）
+	Enabled   bool   `json:"enabled"`                               // 是否启用
+	CreatedAt int64  `json:"createdAt"`                             // 创建时间（时间戳毫秒）
+	UpdatedAt int64  `json:"updatedAt" gorm:"autoUpdateTime:milli"` // 更新时间（时间戳毫秒）
+}
+
+func (PingTarget) TableName() string {
+	return "ping_targets"
+}
+
+// PingMetric Ping 指标
+type PingMetric struct {
+	ID         uint    `gorm:"primaryKey;autoIncrement" json:"id"`
+	AgentID    string  `gorm:"index:idx_ping_agent_target_ts,priority:1" json:"agentId"`                     // 探针ID
+	TargetID   string  `gorm:"index:idx_ping_agent_target_ts,priority:2" json:"targetId"`                    // 目标ID
+	Target     string  `json:"target"`                                                                       // 目标地址
+	MinLatency float64 `json:"minLatency"`                                                                   // 最小延迟(毫秒)
+	AvgLatency float64 `json:"avgLatency"`                                                                   // 平均延迟(毫秒)
+	MaxLatency float64 `json:"maxLatency"`                                                                   // 最大延迟(毫秒)
+	PacketLoss float64 `json:"packetLoss"`                                                                   // 丢包率(%)
+	Timestamp  int64   `gorm:"index:idx_ping_agent_target_ts,priority:3;index:idx_ping_ts" json:"timestamp"` // 时间戳（毫秒）
+}
+
+func (PingMetric) TableName() string {
+	return "ping_metrics"
+}
+
+// LatencyMeshConfig 探针间延迟网格配置，成员探针之间互相 Ping，用于观察多地域节点之间的网络质量
+type LatencyMeshConfig struct {
+	Enabled  bool     `json:"enabled"`  // 是否启用
+	AgentIDs []string `json:"agentIds"` // 参与网格的探针ID
+	Mode     string   `json:"mode"`     // 探测方式: icmp, tcp
+	Port     int      `json:"port"`     // TCP 探测端口
+}
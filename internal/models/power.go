@@ -0,0 +1,21 @@
+package models
+
+// PowerTask 电源操作任务（计划重启、网络唤醒）
+type PowerTask struct {
+	ID          int64  `gorm:"primaryKey;autoIncrement" json:"id"`    // 任务ID
+	AgentID     string `gorm:"index" json:"agentId"`                  // 执行指令的探针ID（重启为目标探针，网络唤醒为同一局域网内的中继探针）
+	Action      string `json:"action"`                                // 动作: reboot（重启）, wol（网络唤醒）
+	TargetMAC   string `json:"targetMac"`                             // 网络唤醒的目标 MAC 地址
+	Broadcast   string `json:"broadcast"`                             // 网络唤醒的广播地址，默认 255.255.255.255:9
+	ScheduledAt int64  `gorm:"index" json:"scheduledAt"`              // 计划执行时间（时间戳毫秒）
+	Status      string `gorm:"index" json:"status"`                   // 状态: pending（待确认）, scheduled（等待执行）, running, success, error, cancelled
+	CommandID   string `gorm:"index" json:"commandId"`                // 下发的指令ID
+	Output      string `json:"output"`                                // 执行输出
+	Error       string `json:"error"`                                 // 错误信息
+	CreatedAt   int64  `json:"createdAt"`                             // 创建时间（时间戳毫秒）
+	UpdatedAt   int64  `json:"updatedAt" gorm:"autoUpdateTime:milli"` // 更新时间（时间戳毫秒）
+}
+
+func (PowerTask) TableName() string {
+	return "power_tasks"
+}
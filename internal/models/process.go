@@ -0,0 +1,29 @@
+package models
+
+// 结束进程的执行状态
+const (
+	ProcessKillStatusRunning = "running"
+	ProcessKillStatusSuccess = "success"
+	ProcessKillStatusError   = "error"
+)
+
+// ProcessKillRecord 结束进程记录
+type ProcessKillRecord struct {
+	ID         int64  `gorm:"primaryKey;autoIncrement" json:"id"` // 记录ID
+	AgentID    string `gorm:"index" json:"agentId"`               // 探针ID
+	PID        int32  `json:"pid"`                                // 进程ID
+	Name       string `json:"name"`                               // 进程名，以探针返回的为准
+	Cmdline    string `gorm:"type:text" json:"cmdline"`           // 用户结束进程时看到的命令行
+	Signal     string `json:"signal"`                             // 信号: TERM, KILL
+	Status     string `gorm:"index" json:"status"`                // 状态: running, success, error
+	CommandID  string `gorm:"index" json:"commandId"`             // 下发的指令ID
+	Error      string `json:"error"`                              // 错误信息
+	Username   string `gorm:"index" json:"username"`              // 操作用户
+	ClientIP   string `json:"clientIp"`                           // 用户的 IP 地址
+	CreatedAt  int64  `gorm:"index" json:"createdAt"`             // 操作时间（时间戳毫秒）
+	FinishedAt int64  `json:"finishedAt"`                         // 结束时间（时间戳毫秒）
+}
+
+func (ProcessKillRecord) TableName() string {
+	return "process_kill_records"
+}
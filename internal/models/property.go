@@ -15,21 +15,82 @@ func (Property) TableName() string {
 
 // NotificationChannelConfig 通知渠道配置（存储在 Property 中）
 type NotificationChannelConfig struct {
-	Type    string                 `json:"type"`    // 类型: dingtalk, wecom, feishu, webhook
-	Enabled bool                   `json:"enabled"` // 是否启用
-	Config  map[string]interface{} `json:"config"`  // 配置对象
+	Type    string                 `json:"type"`             // 类型: dingtalk, wecom, feishu, webhook, discord, email, ntfy, gotify, pushover, matrix, twilio
+	Enabled bool                   `json:"enabled"`          // 是否启用
+	Config  map[string]interface{} `json:"config"`           // 配置对象
+	Events  []string               `json:"events,omitempty"` // 订阅的事件类型，为空时仅接收告警通知
+}
+
+// 通知事件类型
+const (
+	NotificationEventAlert         = "alert"          // 告警（含恢复通知）
+	NotificationEventDDNS          = "ddns"           // DDNS 记录更新
+	NotificationEventTamper        = "tamper"         // 防篡改告警
+	NotificationEventAudit         = "audit"          // 安全审计发现新漏洞
+	NotificationEventAgentRegister = "agent_register" // 新探针注册
+)
+
+// Subscribes 判断渠道是否订阅了指定事件，未配置订阅时仅接收告警通知，保持与旧配置兼容
+func (c NotificationChannelConfig) Subscribes(event string) bool {
+	if len(c.Events) == 0 {
+		return event == NotificationEventAlert
+	}
+	for _, e := range c.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
 }
 
 // 配置格式说明：
-// dingtalk: { "secretKey": "xxx", "signSecret": "xxx" }
-// wecom:    { "secretKey": "xxx" }
-// feishu:   { "secretKey": "xxx", "signSecret": "xxx" }
+// dingtalk: { "secretKey": "xxx", "signSecret": "xxx", "msgType": "text" }  // msgType 可选：text(默认), markdown
+// wecom:    {
+//   "secretKey": "xxx",
+//   "msgType": "text",  // 可选：text(默认), markdown
+//   "mentionedList": ["userid"],  // 可选：严重告警时 @ 的成员 userid，@all 表示所有人
+//   "mentionedMobileList": ["13800000000"]  // 可选：严重告警时 @ 的成员手机号，仅 text 消息支持
+// }
+// feishu:   { "secretKey": "xxx", "signSecret": "xxx", "dashboardUrl": "https://pika.example.com" }  // dashboardUrl 可选，用于卡片中跳转到探针详情
 // webhook:  {
 //   "url": "https://...",
 //   "method": "POST",  // 可选：GET, POST, PUT, PATCH, DELETE，默认 POST
 //   "headers": {"key": "value"},  // 可选：自定义请求头
 //   "bodyTemplate": "json"  // 可选：json(默认), form, custom
-//   "customBody": ""  // 当 bodyTemplate 为 custom 时使用，支持变量替换
+//   "customBody": "",  // 当 bodyTemplate 为 custom 时使用，支持变量替换
+//   "signSecret": "",  // 可选：签名密钥，设置后对请求体计算 HMAC-SHA256 签名
+//   "signatureHeader": "X-Pika-Signature"  // 可选：签名请求头名称，值格式为 sha256=<hex>
+// }
+// discord:  { "webhookUrl": "https://discord.com/api/webhooks/..." }
+// email:    {
+//   "host": "smtp.example.com",
+//   "port": 465,
+//   "security": "ssl",  // ssl, starttls, none，默认 465 端口为 ssl，其它为 starttls
+//   "username": "",
+//   "password": "",
+//   "from": "pika@example.com",  // 为空时使用 username
+//   "to": ["ops@example.com"]    // 也支持逗号分隔的字符串
+// }
+// ntfy:     {
+//   "serverUrl": "https://ntfy.sh",  // 可选：自建服务地址，默认 https://ntfy.sh
+//   "topic": "pika-alerts",
+//   "token": ""  // 可选：访问令牌，主题开启访问控制时使用
+// }  // 消息优先级按告警级别映射：info=3, warning=4, critical=5，恢复通知为 3
+// gotify:   { "serverUrl": "https://gotify.example.com", "appToken": "xxx" }  // 优先级映射：info=4, warning=6, critical=9，恢复通知为 3
+// pushover: {
+//   "appToken": "xxx",
+//   "userKey": "xxx",
+//   "device": "",  // 可选：只推送到指定设备
+//   "retry": 60,   // 可选：严重告警紧急通知的重复间隔（秒），最小 30
+//   "expire": 3600 // 可选：紧急通知持续重复的时长（秒），最大 10800
+// }  // 优先级映射：info=0, warning=1, critical=2(紧急，需确认)，恢复通知为 0 并取消未确认的紧急通知
+// matrix:   { "homeserverUrl": "https://matrix.org", "accessToken": "xxx", "roomId": "!xxx:matrix.org" }  // 机器人账号需已加入房间，恢复通知以 m.notice 发送
+// twilio:   {
+//   "accountSid": "ACxxx",
+//   "authToken": "xxx",
+//   "from": "+15005550006",         // 已购买的 Twilio 号码，E.164 格式
+//   "to": ["+8613800000000"],       // E.164 格式，也支持逗号分隔的字符串
+//   "levels": ["critical"]          // 可选：发送短信的告警级别，默认只发送 critical，恢复通知跟随原告警级别
 // }
 
 // DNSProviderConfig DNS 服务商配置（存储在 Property 中）
@@ -78,17 +139,53 @@ var TimeRangeOptions = []TimeRangeOption{
 	{Label: "1天", Value: "1d"},
 	{Label: "3天", Value: "3d"},
 	{Label: "7天", Value: "7d"},
+	{Label: "30天", Value: "30d"},
 }
 
 // MetricsConfig 指标数据配置
 type MetricsConfig struct {
-	RetentionHours int `json:"retentionHours"` // 原始数据保留小时数（默认168小时=7天）
+	RetentionHours       int  `json:"retentionHours"`       // 原始数据保留小时数（默认168小时=7天）
+	RollupRetentionHours int  `json:"rollupRetentionHours"` // 预聚合数据保留小时数（默认2160小时=90天），长时间范围的查询读取预聚合数据
+	MonitorSampleSeconds int  `json:"monitorSampleSeconds"` // 服务监控状态不变时的结果保存间隔（秒，默认300），状态变化时立即保存
+	PerCoreCPU           bool `json:"perCoreCpu"`           // 是否让探针采集每个核心的 CPU 使用率（只保留最新数据，不写入历史）
+	// 各类指标的采集间隔（秒，key 为指标类型，如 cpu、disk、host），未配置的类型使用探针的采集间隔
+	CollectIntervals map[string]int `json:"collectIntervals,omitempty"`
 }
 
 // AlertConfig 全局告警配置
 type AlertConfig struct {
-	Enabled bool       `json:"enabled"` // 是否启用全局告警
-	Rules   AlertRules `json:"rules"`   // 告警规则
+	Enabled           bool                    `json:"enabled"`           // 是否启用全局告警
+	Rules             AlertRules              `json:"rules"`             // 告警规则
+	GroupRules        []GroupAlertRule        `json:"groupRules"`        // 分组告警规则
+	MonitorGroupRules []MonitorGroupAlertRule `json:"monitorGroupRules"` // 监控分组告警规则
+	Remediations      []RemediationRule       `json:"remediations"`      // 告警修复动作
+}
+
+// GroupAlertRule 分组告警规则，按标签汇总组内在线探针的指标后与阈值比较（如集群平均 CPU 超过 80%）
+type GroupAlertRule struct {
+	Enabled     bool    `json:"enabled"`     // 是否启用
+	Tag         string  `json:"tag"`         // 分组标签
+	Metric      string  `json:"metric"`      // 指标: cpu, memory, disk（使用率）, network（收发速率之和，MB/s）
+	Aggregation string  `json:"aggregation"` // 聚合方式: avg（默认）, max, min, sum
+	Threshold   float64 `json:"threshold"`   // 阈值
+	Duration    int     `json:"duration"`    // 持续时间（秒）
+}
+
+// MonitorGroupAlertRule 监控分组告警规则，组内离线的监控项数量超过允许值时告警（如 API 分组超过 2 个接口不可用）
+type MonitorGroupAlertRule struct {
+	Enabled  bool   `json:"enabled"`  // 是否启用
+	Group    string `json:"group"`    // 监控分组
+	MaxDown  int    `json:"maxDown"`  // 允许同时离线的监控项数量，超过时告警
+	Duration int    `json:"duration"` // 持续时间（秒）
+}
+
+// RemediationRule 告警修复规则（告警触发时在探针上执行白名单内的修复动作）
+type RemediationRule struct {
+	Enabled   bool   `json:"enabled"`   // 是否启用
+	AlertType string `json:"alertType"` // 告警类型: cpu, memory, disk, network, cert, service, wireguard, mount
+	Action    string `json:"action"`    // 修复动作: restart_service（重启 systemd 服务）, clean_dir（清空目录）
+	Target    string `json:"target"`    // systemd 服务名或目录路径，需在探针白名单中
+	Auto      bool   `json:"auto"`      // 是否自动执行，关闭时需要管理员审批后执行
 }
 
 // AlertRules 告警规则
@@ -116,6 +213,8 @@ type AlertRules struct {
 	// HTTPS 证书告警配置
 	CertEnabled   bool    `json:"certEnabled"`   // 是否启用证书告警
 	CertThreshold float64 `json:"certThreshold"` // 证书剩余天数阈值
+	// 证书链校验失败告警，证书未过期时也会触发
+	CertChainEnabled bool `json:"certChainEnabled"`
 
 	// 服务下线告警配置
 	ServiceEnabled  bool `json:"serviceEnabled"`  // 是否启用服务下线告警
@@ -124,4 +223,75 @@ type AlertRules struct {
 	// 探针离线告警配置
 	AgentOfflineEnabled  bool `json:"agentOfflineEnabled"`  // 是否启用探针离线告警
 	AgentOfflineDuration int  `json:"agentOfflineDuration"` // 持续时间（秒）
+
+	// WireGuard 握手超时告警配置
+	WireGuardEnabled            bool `json:"wireGuardEnabled"`            // 是否启用 WireGuard 告警
+	WireGuardHandshakeThreshold int  `json:"wireGuardHandshakeThreshold"` // 对端未完成握手的时长阈值（秒）
+
+	// 网络挂载点告警配置
+	MountEnabled  bool `json:"mountEnabled"`  // 是否启用挂载点告警（挂起、句柄失效、未挂载）
+	MountDuration int  `json:"mountDuration"` // 持续时间（秒）
+
+	// inode 使用率告警配置，按文件系统分别判断
+	InodeEnabled   bool    `json:"inodeEnabled"`   // 是否启用 inode 告警
+	InodeThreshold float64 `json:"inodeThreshold"` // 使用率阈值（0-100）
+	InodeDuration  int     `json:"inodeDuration"`  // 持续时间（秒）
+
+	// 系统级文件描述符使用率告警配置（仅 Linux）
+	FDEnabled   bool    `json:"fdEnabled"`   // 是否启用文件描述符告警
+	FDThreshold float64 `json:"fdThreshold"` // 使用率阈值（0-100）
+	FDDuration  int     `json:"fdDuration"`  // 持续时间（秒）
+
+	// CPU steal 告警配置，steal 持续偏高通常说明 VPS 宿主机超售或存在吵闹邻居
+	StealEnabled   bool    `json:"stealEnabled"`   // 是否启用 steal 告警
+	StealThreshold float64 `json:"stealThreshold"` // steal 占比阈值（0-100）
+	StealDuration  int     `json:"stealDuration"`  // 持续时间（秒）
+
+	// 时钟偏差告警配置，需要探针配置 ntp_server
+	ClockEnabled   bool    `json:"clockEnabled"`   // 是否启用时钟偏差告警
+	ClockThreshold float64 `json:"clockThreshold"` // 偏差阈值（毫秒，按绝对值判断）
+	ClockDuration  int     `json:"clockDuration"`  // 持续时间（秒）
+
+	// 非预期监听端口告警配置，只检查非回环地址上的监听
+	PortEnabled  bool     `json:"portEnabled"`  // 是否启用监听端口告警
+	PortAllowed  []string `json:"portAllowed"`  // 允许的端口，如 22、tcp/443、udp/53，不带协议时 TCP 和 UDP 都允许
+	PortDuration int      `json:"portDuration"` // 端口持续监听多久后触发告警（秒），避免临时端口误报
+
+	// 内核事件告警配置，需要探针开启 kernel_log；事件没有持续时间，同类事件在恢复时间内不再出现时告警恢复
+	KernelEnabled    bool     `json:"kernelEnabled"`    // 是否启用内核事件告警
+	KernelCategories []string `json:"kernelCategories"` // 触发告警的事件分类: oom, io, hardware，为空表示全部
+	KernelRecovery   int      `json:"kernelRecovery"`   // 恢复时间（秒）
+
+	// 恢复冷却期，按告警类型配置（秒）：告警恢复后冷却期内再次触发时合并到上一条记录，不再新建记录，再次触发时仍会发送通知并注明是再次触发
+	Cooldowns map[string]int `json:"cooldowns"`
+}
+
+// InfluxDBConfig InfluxDB 导出配置，以行协议通过 v2 API 写入探针上报的指标
+type InfluxDBConfig struct {
+	Enabled bool   `json:"enabled"` // 是否启用导出
+	URL     string `json:"url"`     // InfluxDB 地址，如 http://influxdb:8086
+	Token   string `json:"token"`   // API Token，需要有目标 bucket 的写权限
+	Org     string `json:"org"`     // 组织名称或 ID
+	Bucket  string `json:"bucket"`  // 写入的 bucket
+}
+
+// RecurringWindow 每天或每周重复的时间窗口，计划重启的维护窗口和监控项的维护时间窗口共用
+type RecurringWindow struct {
+	Weekdays  []int  `json:"weekdays,omitempty"`  // 窗口开始的星期（0 为周日），为空表示每天
+	StartTime string `json:"startTime,omitempty"` // 开始时间 HH:mm
+	EndTime   string `json:"endTime,omitempty"`   // 结束时间 HH:mm，早于开始时间时表示跨天
+}
+
+// MaintenanceWindowConfig 维护窗口配置，启用后计划重启只会在窗口内执行，时间按服务端时区计算
+type MaintenanceWindowConfig struct {
+	Enabled bool `json:"enabled"` // 是否启用维护窗口
+	RecurringWindow
+}
+
+// VulnerabilityConfig 漏洞匹配配置
+type VulnerabilityConfig struct {
+	Enabled       bool   `json:"enabled"`       // 是否启用漏洞匹配
+	FeedURL       string `json:"feedUrl"`       // 漏洞数据源地址（OSV 格式的 JSON 数组）
+	IntervalHours int    `json:"intervalHours"` // 匹配间隔（小时）
+	RegistryCheck bool   `json:"registryCheck"` // 漏洞匹配时是否查询镜像仓库，检查运行中的容器镜像是否与标签的当前摘要一致
 }
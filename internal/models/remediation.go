@@ -0,0 +1,22 @@
+package models
+
+// RemediationRecord 告警修复动作执行记录
+type RemediationRecord struct {
+	ID            int64  `gorm:"primaryKey;autoIncrement" json:"id"`    // 记录ID
+	AlertRecordID int64  `gorm:"index" json:"alertRecordId"`            // 关联的告警记录ID
+	AgentID       string `gorm:"index" json:"agentId"`                  // 探针ID
+	AlertType     string `json:"alertType"`                             // 告警类型
+	Action        string `json:"action"`                                // 修复动作: restart_service, clean_dir
+	Target        string `json:"target"`                                // systemd 服务名或目录路径
+	Auto          bool   `json:"auto"`                                  // 是否自动执行
+	Status        string `gorm:"index" json:"status"`                   // 状态: pending（待审批）, running, success, error, rejected
+	CommandID     string `gorm:"index" json:"commandId"`                // 下发的指令ID
+	Output        string `json:"output"`                                // 执行输出
+	Error         string `json:"error"`                                 // 错误信息
+	CreatedAt     int64  `json:"createdAt"`                             // 创建时间（时间戳毫秒）
+	UpdatedAt     int64  `json:"updatedAt" gorm:"autoUpdateTime:milli"` // 更新时间（时间戳毫秒）
+}
+
+func (RemediationRecord) TableName() string {
+	return "remediation_records"
+}
@@ -0,0 +1,21 @@
+package models
+
+// SecurityFinding 安全漏洞发现（软件清单与漏洞数据源匹配的结果）
+type SecurityFinding struct {
+	ID               string `gorm:"primaryKey" json:"id"`                  // ID
+	AgentID          string `gorm:"index" json:"agentId"`                  // 探针ID
+	Software         string `gorm:"index" json:"software"`                 // 软件名称
+	InstalledVersion string `json:"installedVersion"`                      // 已安装版本
+	VulnID           string `gorm:"index" json:"vulnId"`                   // 漏洞编号（CVE/OSV ID）
+	Aliases          string `json:"aliases"`                               // 漏洞别名，逗号分隔
+	Summary          string `json:"summary"`                               // 漏洞描述
+	Severity         string `gorm:"index" json:"severity"`                 // 严重程度: critical, high, medium, low, unknown
+	FixedVersion     string `json:"fixedVersion"`                          // 修复版本，为空表示暂无修复
+	FirstSeenAt      int64  `json:"firstSeenAt"`                           // 首次发现时间（时间戳毫秒）
+	LastSeenAt       int64  `json:"lastSeenAt"`                            // 最后一次匹配时间（时间戳毫秒）
+	UpdatedAt        int64  `json:"updatedAt" gorm:"autoUpdateTime:milli"` // 更新时间（时间戳毫秒）
+}
+
+func (SecurityFinding) TableName() string {
+	return "security_findings"
+}
@@ -0,0 +1,14 @@
+package models
+
+// SoftwareInventory 探针软件清单
+type SoftwareInventory struct {
+	ID        string `gorm:"primaryKey" json:"id"`                  // ID（agentId:name）
+	AgentID   string `gorm:"index" json:"agentId"`                  // 探针ID
+	Name      string `gorm:"index" json:"name"`                     // 软件名称
+	Version   string `json:"version"`                               // 版本号
+	UpdatedAt int64  `json:"updatedAt" gorm:"autoUpdateTime:milli"` // 更新时间（时间戳毫秒）
+}
+
+func (SoftwareInventory) TableName() string {
+	return "software_inventories"
+}
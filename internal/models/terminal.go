@@ -0,0 +1,36 @@
+package models
+
+// 远程终端会话状态
+const (
+	TerminalStatusRunning = "running"
+	TerminalStatusClosed  = "closed"
+)
+
+// TerminalSession 远程终端会话记录
+type TerminalSession struct {
+	ID              string `gorm:"primaryKey" json:"id"`   // 会话ID，同时作为下发给探针的指令ID
+	AgentID         string `gorm:"index" json:"agentId"`   // 探针ID
+	Username        string `gorm:"index" json:"username"`  // 打开终端的用户
+	ClientIP        string `json:"clientIp"`               // 用户的 IP 地址
+	Status          string `gorm:"index" json:"status"`    // 状态: running, closed
+	CloseReason     string `json:"closeReason"`            // 结束原因
+	StartedAt       int64  `gorm:"index" json:"startedAt"` // 开始时间（时间戳毫秒）
+	EndedAt         int64  `json:"endedAt"`                // 结束时间（时间戳毫秒）
+	RecordSize      int64  `json:"recordSize"`             // 录像大小（字节）
+	RecordTruncated bool   `json:"recordTruncated"`        // 录像是否因超出大小上限而不完整
+}
+
+func (TerminalSession) TableName() string {
+	return "terminal_sessions"
+}
+
+// TerminalRecordChunk 终端会话录像片段，按顺序拼接为 asciicast v2 格式的录像
+type TerminalRecordChunk struct {
+	ID        int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	SessionID string `gorm:"index" json:"sessionId"` // 会话ID
+	Data      string `gorm:"type:text" json:"data"`  // 若干行完整的录像事件
+}
+
+func (TerminalRecordChunk) TableName() string {
+	return "terminal_record_chunks"
+}
@@ -0,0 +1,67 @@
+package protocol
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// 指标编码方式，注册时协商，未协商（旧版本探针或服务端）时使用 JSON
+const (
+	EncodingJSON    = "json"
+	EncodingMsgpack = "msgpack"
+)
+
+// supportedEncodings 服务端支持的指标编码，按优先级排列
+var supportedEncodings = []string{EncodingMsgpack, EncodingJSON}
+
+// NegotiateEncoding 从探针声明的编码中选出双方都支持的编码，按探针的优先顺序选择
+func NegotiateEncoding(encodings []string) string {
+	for _, encoding := range encodings {
+		if slices.Contains(supportedEncodings, encoding) {
+			return encoding
+		}
+	}
+	return EncodingJSON
+}
+
+// BinaryMetrics MessagePack 编码的指标消息，以 WebSocket 二进制帧发送，
+// 指标数据直接内嵌，省去 JSON 文本中再嵌套 JSON 的多次编解码；
+// 合并的指标消息 Type 为空，一轮采集的各类指标放在 Batch 中
+type BinaryMetrics struct {
+	Type  MetricType         `json:"type,omitempty"`
+	Data  msgpack.RawMessage `json:"data,omitempty"`
+	Batch []BinaryMetrics    `json:"batch,omitempty"`
+}
+
+// MarshalBinaryMetrics 将指标编码为二进制帧
+func MarshalBinaryMetrics(metricType MetricType, data interface{}) ([]byte, error) {
+	dataBytes, err := MarshalMsgpack(data)
+	if err != nil {
+		return nil, err
+	}
+	return MarshalMsgpack(BinaryMetrics{
+		Type: metricType,
+		Data: dataBytes,
+	})
+}
+
+// MarshalMsgpack 以 MessagePack 编码，字段名沿用 json 标签，与 JSON 编码的字段保持一致
+func MarshalMsgpack(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	enc.UseCompactInts(true)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalMsgpack 解码 MessagePack 数据，字段名沿用 json 标签
+func UnmarshalMsgpack(data []byte, v interface{}) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
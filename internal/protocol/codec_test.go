@@ -0,0 +1,170 @@
+package protocol
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// metricSamples 服务端按指标类型解码的数据类型，与 JSON 编码的指标一一对应
+func metricSamples() map[MetricType]interface{} {
+	return map[MetricType]interface{}{
+		MetricTypeCPU:               &CPUData{},
+		MetricTypeMemory:            &MemoryData{},
+		MetricTypeDisk:              &[]DiskData{},
+		MetricTypeDiskIO:            &[]*DiskIOData{},
+		MetricTypeNetwork:           &[]NetworkData{},
+		MetricTypeNetworkConnection: &NetworkConnectionData{},
+		MetricTypeHost:              &HostInfoData{},
+		MetricTypeGPU:               &[]GPUData{},
+		MetricTypeTemperature:       &[]TemperatureData{},
+		MetricTypeMonitor:           &[]MonitorData{},
+		MetricTypeWireGuard:         &[]WireGuardData{},
+		MetricTypePing:              &[]PingData{},
+		MetricTypeCollectorHealth:   &[]CollectorHealth{},
+		MetricTypeMount:             &[]MountData{},
+		MetricTypeFileUsage:         &FileUsageData{},
+		MetricTypeTimeSync:          &TimeSyncData{},
+		MetricTypeListeningPort:     &[]ListeningPort{},
+		MetricTypeCustom:            &[]CustomMetricData{},
+		MetricTypePressure:          &PressureData{},
+	}
+}
+
+// fill 将值的每个字段填充为非零值，新增字段无需修改测试即可覆盖
+func fill(v reflect.Value, name string) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+		fill(v.Elem(), name)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fill(v.Field(i), v.Type().Field(i).Name)
+			}
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes([]byte(name))
+			return
+		}
+		v.Set(reflect.MakeSlice(v.Type(), 2, 2))
+		fill(v.Index(0), name)
+		fill(v.Index(1), name)
+	case reflect.Map:
+		v.Set(reflect.MakeMap(v.Type()))
+		key := reflect.New(v.Type().Key()).Elem()
+		fill(key, name)
+		elem := reflect.New(v.Type().Elem()).Elem()
+		fill(elem, name)
+		v.SetMapIndex(key, elem)
+	case reflect.String:
+		v.SetString("value-" + name)
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(-7)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(200)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1.5)
+	}
+}
+
+func TestMsgpackRoundTripMatchesJSON(t *testing.T) {
+	for metricType, sample := range metricSamples() {
+		t.Run(string(metricType), func(t *testing.T) {
+			fill(reflect.ValueOf(sample).Elem(), string(metricType))
+
+			data, err := MarshalMsgpack(sample)
+			if err != nil {
+				t.Fatalf("MessagePack 编码失败: %v", err)
+			}
+			decoded := reflect.New(reflect.TypeOf(sample).Elem())
+			if err := UnmarshalMsgpack(data, decoded.Interface()); err != nil {
+				t.Fatalf("MessagePack 解码失败: %v", err)
+			}
+			if !reflect.DeepEqual(sample, decoded.Interface()) {
+				t.Fatalf("MessagePack 解码结果不一致:\n原始: %+v\n解码: %+v", sample, decoded.Interface())
+			}
+
+			// 字段名沿用 json 标签：MessagePack 解码为通用结构后应与 JSON 解码的结果一致
+			var fromMsgpack interface{}
+			if err := UnmarshalMsgpack(data, &fromMsgpack); err != nil {
+				t.Fatal(err)
+			}
+			jsonData, err := json.Marshal(sample)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var fromJSON interface{}
+			if err := json.Unmarshal(jsonData, &fromJSON); err != nil {
+				t.Fatal(err)
+			}
+			normalized, err := json.Marshal(fromMsgpack)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var fromMsgpackJSON interface{}
+			if err := json.Unmarshal(normalized, &fromMsgpackJSON); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(fromJSON, fromMsgpackJSON) {
+				t.Fatalf("MessagePack 与 JSON 的字段不一致:\nJSON:    %s\nMsgpack: %s", jsonData, normalized)
+			}
+		})
+	}
+}
+
+func TestBinaryMetricsBatchRoundTrip(t *testing.T) {
+	cpu := CPUData{UsagePercent: 12.5}
+	frame, err := MarshalBinaryMetrics(MetricTypeCPU, cpu)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var single BinaryMetrics
+	if err := UnmarshalMsgpack(frame, &single); err != nil {
+		t.Fatal(err)
+	}
+	if single.Type != MetricTypeCPU || len(single.Batch) != 0 {
+		t.Fatalf("单条指标解码错误: %+v", single)
+	}
+	var decodedCPU CPUData
+	if err := UnmarshalMsgpack(single.Data, &decodedCPU); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decodedCPU, cpu) {
+		t.Fatalf("CPU 指标为 %+v，期望 %+v", decodedCPU, cpu)
+	}
+
+	batchFrame, err := MarshalMsgpack(BinaryMetrics{Batch: []BinaryMetrics{single, single}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var batch BinaryMetrics
+	if err := UnmarshalMsgpack(batchFrame, &batch); err != nil {
+		t.Fatal(err)
+	}
+	if batch.Type != "" || len(batch.Batch) != 2 || batch.Batch[1].Type != MetricTypeCPU {
+		t.Fatalf("合并指标解码错误: %+v", batch)
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		encodings []string
+		want      string
+	}{
+		{encodings: nil, want: EncodingJSON},
+		{encodings: []string{"cbor"}, want: EncodingJSON},
+		{encodings: []string{EncodingMsgpack, EncodingJSON}, want: EncodingMsgpack},
+		{encodings: []string{EncodingJSON, EncodingMsgpack}, want: EncodingJSON},
+		{encodings: []string{"cbor", EncodingMsgpack}, want: EncodingMsgpack},
+	}
+	for _, tt := range tests {
+		if got := NegotiateEncoding(tt.encodings); got != tt.want {
+			t.Errorf("NegotiateEncoding(%v) = %q，期望 %q", tt.encodings, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package protocol
+
+// 远程命令名称，探针按名称查找注册的执行器
+const (
+	RemoteCommandRestartService = "restart_service" // 重启 systemd 服务，Target 为服务名
+	RemoteCommandClearCache     = "clear_cache"     // 清空缓存目录，Target 为目录的绝对路径
+	RemoteCommandRunScript      = "run_script"      // 执行脚本，Target 为探针配置中的脚本名称
+)
+
+const (
+	RemoteCommandDefaultTimeout = 60        // 默认超时时间（秒）
+	RemoteCommandMaxTimeout     = 3600      // 最长超时时间（秒）
+	RemoteCommandMaxOutput      = 32 * 1024 // 执行输出的最大字节数，超出部分丢弃
+)
+
+// RemoteCommandRequest 远程命令参数（作为 remote_command 指令的 Args 下发）
+type RemoteCommandRequest struct {
+	Name    string `json:"name"`    // 命令名称: restart_service, clear_cache, run_script
+	Target  string `json:"target"`  // 命令目标，需同时在服务端和探针的白名单中
+	Timeout int    `json:"timeout"` // 超时时间（秒），超时后终止执行
+}
+
+// RemoteCommandResult 远程命令执行结果
+type RemoteCommandResult struct {
+	Output   string `json:"output"`   // 执行输出
+	ExitCode int    `json:"exitCode"` // 脚本的退出码，其他命令为 0
+	Duration int64  `json:"duration"` // 执行耗时（毫秒）
+}
+
+// ClampRemoteCommandTimeout 将超时时间限制在允许范围内，未设置时使用默认值
+func ClampRemoteCommandTimeout(timeout int) int {
+	if timeout <= 0 {
+		return RemoteCommandDefaultTimeout
+	}
+	return min(timeout, RemoteCommandMaxTimeout)
+}
@@ -0,0 +1,27 @@
+package protocol
+
+// DiskUsageRequest 磁盘占用分析指令参数
+type DiskUsageRequest struct {
+	Path  string `json:"path"`  // 扫描路径
+	Depth int    `json:"depth"` // 统计目录占用的最大深度（相对扫描路径），更深的目录计入上层目录
+	Limit int    `json:"limit"` // 返回占用最大的目录和文件数量
+}
+
+// DiskUsageEntry 目录或文件的占用
+type DiskUsageEntry struct {
+	Path string `json:"path"` // 完整路径
+	Size int64  `json:"size"` // 占用字节数（目录包含所有子项）
+}
+
+// DiskUsageResult 磁盘占用分析结果
+type DiskUsageResult struct {
+	Path        string           `json:"path"`        // 扫描路径
+	TotalSize   int64            `json:"totalSize"`   // 扫描路径的总占用
+	FileCount   int64            `json:"fileCount"`   // 文件数量
+	DirCount    int64            `json:"dirCount"`    // 目录数量
+	Directories []DiskUsageEntry `json:"directories"` // 占用最大的目录（按占用降序）
+	Files       []DiskUsageEntry `json:"files"`       // 占用最大的文件（按占用降序）
+	Errors      int64            `json:"errors"`      // 无权限等无法读取的条目数量
+	Truncated   bool             `json:"truncated"`   // 是否因超时提前结束，结果只包含已扫描部分
+	Duration    int64            `json:"duration"`    // 扫描耗时（毫秒）
+}
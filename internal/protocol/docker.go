@@ -0,0 +1,47 @@
+package protocol
+
+// Docker 容器操作
+const (
+	DockerActionStart   = "start"
+	DockerActionStop    = "stop"
+	DockerActionRestart = "restart"
+)
+
+// DockerActionTimeout 探针执行容器操作的超时时间（秒）
+const DockerActionTimeout = 60
+
+// IsDockerAction 是否为支持的容器操作
+func IsDockerAction(action string) bool {
+	switch action {
+	case DockerActionStart, DockerActionStop, DockerActionRestart:
+		return true
+	}
+	return false
+}
+
+// DockerContainer 容器信息
+type DockerContainer struct {
+	ID        string `json:"id"`        // 容器ID
+	Name      string `json:"name"`      // 容器名称
+	Image     string `json:"image"`     // 镜像
+	State     string `json:"state"`     // 状态: created, running, paused, restarting, exited, dead
+	Status    string `json:"status"`    // 状态描述，如 Up 2 hours
+	Ports     string `json:"ports"`     // 端口映射
+	CreatedAt string `json:"createdAt"` // 创建时间
+}
+
+// DockerListResult 容器列表（作为 docker_list 指令的 Result 返回）
+type DockerListResult struct {
+	Containers []DockerContainer `json:"containers"`
+}
+
+// DockerActionRequest 容器操作参数（作为 docker_action 指令的 Args 下发）
+type DockerActionRequest struct {
+	Action    string `json:"action"`    // 操作: start, stop, restart
+	Container string `json:"container"` // 容器ID或名称
+}
+
+// DockerActionResult 容器操作结果（作为 docker_action 指令的 Result 返回）
+type DockerActionResult struct {
+	Output string `json:"output"` // docker 命令的输出
+}
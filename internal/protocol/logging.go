@@ -0,0 +1,8 @@
+package protocol
+
+// LogLevelRequest 调整日志级别参数（作为 log_level 指令的 Args 下发）
+type LogLevelRequest struct {
+	Module string `json:"module"`          // 模块名，为空表示默认级别
+	Level  string `json:"level,omitempty"` // 日志级别: debug, info, warn, error
+	Reset  bool   `json:"reset,omitempty"` // 为 true 时清除模块单独设置的级别
+}
@@ -0,0 +1,30 @@
+package protocol
+
+// 日志查看限制（服务端和探针两侧都会校验）
+const (
+	LogTailMaxLines    = 500             // 初始输出的最大行数
+	LogTailMaxDuration = 300             // 最长跟踪时间（秒）
+	LogTailMaxBytes    = 2 * 1024 * 1024 // 单次查看最多输出的字节数
+)
+
+// LogTailRequest 日志查看参数（作为 log_tail 指令的 Args 下发）
+type LogTailRequest struct {
+	Source   string `json:"source"`   // 日志来源: file（文件路径）, journal（systemd 服务）
+	Target   string `json:"target"`   // 文件路径或 systemd 服务名称，需在探针白名单中
+	Lines    int    `json:"lines"`    // 初始输出的行数
+	Duration int    `json:"duration"` // 持续跟踪的时间（秒）
+	MaxBytes int    `json:"maxBytes"` // 最多输出的字节数
+}
+
+// LogTailChunk 日志输出片段
+type LogTailChunk struct {
+	ID    string `json:"id"`              // 指令ID
+	Data  string `json:"data,omitempty"`  // 日志内容
+	Done  bool   `json:"done,omitempty"`  // 是否已结束
+	Error string `json:"error,omitempty"` // 错误信息
+}
+
+// LogTailStop 停止日志查看
+type LogTailStop struct {
+	ID string `json:"id"` // 指令ID
+}
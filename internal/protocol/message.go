@@ -12,13 +12,16 @@ type Message struct {
 type RegisterRequest struct {
 	AgentInfo AgentInfo `json:"agentInfo"`
 	ApiKey    string    `json:"apiKey"`
+	Encodings []string  `json:"encodings,omitempty"` // 探针支持的指标编码，按优先级排列
 }
 
 // RegisterResponse 注册响应
 type RegisterResponse struct {
-	AgentID string `json:"agentId"`
-	Status  string `json:"status"`
-	Message string `json:"message,omitempty"`
+	AgentID  string `json:"agentId"`
+	Status   string `json:"status"`
+	Message  string `json:"message,omitempty"`
+	Encoding string `json:"encoding,omitempty"` // 协商后的指标编码，为空表示 JSON
+	Batch    bool   `json:"batch,omitempty"`    // 服务端是否支持合并的指标消息，旧版本服务端不返回
 }
 
 // AgentInfo 探针信息
@@ -33,8 +36,14 @@ type AgentInfo struct {
 
 // MetricsWrapper 指标数据包装
 type MetricsWrapper struct {
-	Type MetricType      `json:"type"`
-	Data json.RawMessage `json:"data"`
+	Type      MetricType      `json:"type"`
+	Data      json.RawMessage `json:"data"`
+	Timestamp int64           `json:"timestamp,omitempty"` // 采集时间(毫秒)，仅离线缓存补发的指标携带，实时指标以服务端接收时间为准
+}
+
+// MetricsBatch 一轮采集的所有指标合并为一条消息，服务端以同一个时间戳写入
+type MetricsBatch struct {
+	Metrics []MetricsWrapper `json:"metrics"`
 }
 
 type MessageType string
@@ -49,14 +58,35 @@ const (
 	MessageTypeCommandResp MessageType = "command_response"
 	// 指标消息
 	MessageTypeMetrics       MessageType = "metrics"
+	MessageTypeMetricsReplay MessageType = "metrics_replay" // 断线期间缓存的指标，重连后按原始采集时间补发
+	MessageTypeMetricsBatch  MessageType = "metrics_batch"  // 一轮采集的所有指标合并为一条消息
 	MessageTypeMonitorConfig MessageType = "monitor_config"
+	MessageTypePingConfig    MessageType = "ping_config"
+	// 采集器配置消息
+	MessageTypeCollectorConfig MessageType = "collector_config"
 	// 防篡改消息
 	MessageTypeTamperProtect MessageType = "tamper_protect"
 	MessageTypeTamperEvent   MessageType = "tamper_event"
 	MessageTypeTamperAlert   MessageType = "tamper_alert"
+	// 内核日志事件消息
+	MessageTypeKernelEvent MessageType = "kernel_event"
 	// DDNS 消息
 	MessageTypeDDNSConfig   MessageType = "ddns_config"
 	MessageTypeDDNSIPReport MessageType = "ddns_ip_report"
+	// 软件清单消息
+	MessageTypeSoftwareInventory MessageType = "software_inventory"
+	// 日志查看消息
+	MessageTypeLogTailChunk MessageType = "log_tail_chunk"
+	MessageTypeLogTailStop  MessageType = "log_tail_stop"
+	// 监控项变为离线时的路径追踪结果
+	MessageTypeMonitorTrace MessageType = "monitor_trace"
+	// 远程终端消息
+	MessageTypeTerminalInput  MessageType = "terminal_input"  // 服务端转发的键盘输入和窗口大小变化
+	MessageTypeTerminalOutput MessageType = "terminal_output" // 探针推送的终端输出
+	MessageTypeTerminalClose  MessageType = "terminal_close"  // 服务端结束终端会话
+	// 进程列表消息
+	MessageTypeProcessSnapshot MessageType = "process_snapshot"  // 探针推送的进程列表快照
+	MessageTypeProcessListStop MessageType = "process_list_stop" // 服务端停止进程列表推送
 )
 
 type MetricType string
@@ -73,8 +103,23 @@ const (
 	MetricTypeGPU               MetricType = "gpu"
 	MetricTypeTemperature       MetricType = "temperature"
 	MetricTypeMonitor           MetricType = "monitor"
+	MetricTypeWireGuard         MetricType = "wireguard"
+	MetricTypePing              MetricType = "ping"
+	MetricTypeCollectorHealth   MetricType = "collector_health"
+	MetricTypeMount             MetricType = "mount"
+	MetricTypeFileUsage         MetricType = "file_usage"
+	MetricTypeTimeSync          MetricType = "time_sync"
+	MetricTypeListeningPort     MetricType = "listening_port"
+	MetricTypeCustom            MetricType = "custom"
+	MetricTypePressure          MetricType = "pressure"
 )
 
+// CollectorConfigPayload 服务端控制的采集器配置，连接建立时及配置变更时下发
+type CollectorConfigPayload struct {
+	PerCoreCPU bool           `json:"perCoreCpu"`          // 是否采集每个核心的 CPU 使用率
+	Intervals  map[string]int `json:"intervals,omitempty"` // 各类指标的采集间隔（秒，key 为指标类型），未配置的类型使用探针的采集间隔
+}
+
 // CPUData CPU数据
 type CPUData struct {
 	// 静态信息(不常变化,但每次都发送)
@@ -82,8 +127,18 @@ type CPUData struct {
 	PhysicalCores int    `json:"physicalCores"`
 	ModelName     string `json:"modelName"`
 	// 动态信息
-	UsagePercent float64   `json:"usagePercent"`
-	PerCore      []float64 `json:"perCore,omitempty"`
+	UsagePercent float64       `json:"usagePercent"`
+	PerCore      []float64     `json:"perCore,omitempty"`
+	Times        *CPUTimesData `json:"times,omitempty"`
+}
+
+// CPUTimesData CPU 时间分布，为两次采集之间各状态占用的百分比
+type CPUTimesData struct {
+	User   float64 `json:"user"`   // 用户态（含 nice）
+	System float64 `json:"system"` // 内核态（含 irq、softirq）
+	IOWait float64 `json:"iowait"` // 等待 IO（仅 Linux）
+	Steal  float64 `json:"steal"`  // 被宿主机其他虚拟机占用（仅 Linux 虚拟机）
+	Idle   float64 `json:"idle"`   // 空闲
 }
 
 // MemoryData 内存数据
@@ -197,10 +252,130 @@ type TemperatureData struct {
 	Type        string  `json:"type"`
 }
 
+// 采集器健康状态
+const (
+	CollectorStatusOK    = "ok"    // 最近一次采集成功
+	CollectorStatusError = "error" // 最近一次采集返回错误
+	CollectorStatusStuck = "stuck" // 采集超时仍未返回
+)
+
+// CollectorHealth 探针采集器健康状态
+type CollectorHealth struct {
+	Name          string `json:"name"`                    // 采集器名称: cpu, memory, disk, ...
+	Status        string `json:"status"`                  // ok, error, stuck
+	LastDuration  int64  `json:"lastDuration"`            // 最近一次完成的采集耗时（毫秒）
+	LastSuccessAt int64  `json:"lastSuccessAt,omitempty"` // 最近一次采集成功时间（毫秒）
+	LastError     string `json:"lastError,omitempty"`     // 最近一次采集错误
+	StuckSince    int64  `json:"stuckSince,omitempty"`    // 卡住的采集开始时间（毫秒）
+	Timeouts      int    `json:"timeouts"`                // 累计超时次数
+	Restarts      int    `json:"restarts"`                // 卡住后恢复并重新调度的次数
+}
+
+// 网络挂载点状态
+const (
+	MountStatusOK        = "ok"
+	MountStatusHung      = "hung"      // 访问挂载点超时未返回（服务端无响应的 hard 挂载）
+	MountStatusStale     = "stale"     // 文件句柄失效（ESTALE），需要重新挂载
+	MountStatusUnmounted = "unmounted" // 挂载点当前未挂载
+	MountStatusError     = "error"     // 其他访问错误，如权限不足
+)
+
+// MountData 网络挂载点（NFS/SMB 等）健康状态
+type MountData struct {
+	MountPoint string `json:"mountPoint"`
+	Device     string `json:"device,omitempty"`
+	Fstype     string `json:"fstype,omitempty"`
+	Status     string `json:"status"`          // ok, hung, stale, unmounted, error
+	Latency    int64  `json:"latency"`         // 探测耗时（毫秒），hung 时为已等待的时长
+	Error      string `json:"error,omitempty"` // 错误信息
+}
+
+// FileUsageData 文件描述符和 inode 使用情况，inode 耗尽时即使磁盘仍有空间也无法创建文件
+type FileUsageData struct {
+	FileDescriptors *FileDescriptorData `json:"fileDescriptors,omitempty"` // 系统级文件描述符，仅 Linux 采集
+	Inodes          []InodeData         `json:"inodes,omitempty"`          // 各文件系统的 inode 使用情况
+}
+
+// FileDescriptorData 系统级文件描述符使用情况（/proc/sys/fs/file-nr）
+type FileDescriptorData struct {
+	Allocated    uint64  `json:"allocated"`    // 已分配的文件描述符数量
+	Max          uint64  `json:"max"`          // 系统上限（fs.file-max）
+	UsagePercent float64 `json:"usagePercent"` // 使用率
+}
+
+// InodeData 文件系统的 inode 使用情况
+type InodeData struct {
+	MountPoint   string  `json:"mountPoint"`
+	Device       string  `json:"device"`
+	Fstype       string  `json:"fstype"`
+	Total        uint64  `json:"total"`
+	Used         uint64  `json:"used"`
+	Free         uint64  `json:"free"`
+	UsagePercent float64 `json:"usagePercent"`
+}
+
+// CustomMetricData 自定义指标，来自探针配置的命令或文本文件
+type CustomMetricData struct {
+	Name   string            `json:"name"`             // 指标名
+	Labels map[string]string `json:"labels,omitempty"` // 标签
+	Value  float64           `json:"value"`            // 数值
+	Source string            `json:"source"`           // 来源：命令名称或文本文件名
+}
+
+// TimeSyncData 时钟偏差数据，通过 SNTP 查询 NTP 服务器得到
+type TimeSyncData struct {
+	Server    string  `json:"server"`          // NTP 服务器
+	Offset    float64 `json:"offset"`          // 本机时钟相对 NTP 服务器的偏差（毫秒），正数表示本机慢
+	RTT       float64 `json:"rtt"`             // 往返时延（毫秒）
+	Stratum   int     `json:"stratum"`         // NTP 服务器层级
+	Error     string  `json:"error,omitempty"` // 查询失败原因
+	CheckedAt int64   `json:"checkedAt"`       // 查询时间（时间戳毫秒）
+}
+
+// PressureData Linux PSI（/proc/pressure）压力数据，内核 4.20 及以上支持
+type PressureData struct {
+	CPU    *PressureStat `json:"cpu,omitempty"`
+	Memory *PressureStat `json:"memory,omitempty"`
+	IO     *PressureStat `json:"io,omitempty"`
+}
+
+// PressureStat 单项资源的压力，some 表示至少一个任务因资源不足而停顿的时间占比，full 表示所有任务同时停顿的时间占比
+type PressureStat struct {
+	Some PressureAvg  `json:"some"`
+	Full *PressureAvg `json:"full,omitempty"` // 旧内核的 cpu 没有 full 行
+}
+
+// PressureAvg 最近 10 秒、60 秒、300 秒的停顿时间占比（%）
+type PressureAvg struct {
+	Avg10  float64 `json:"avg10"`
+	Avg60  float64 `json:"avg60"`
+	Avg300 float64 `json:"avg300"`
+	Total  uint64  `json:"total"` // 累计停顿时间（微秒）
+}
+
+// WireGuardData WireGuard 隧道数据
+type WireGuardData struct {
+	Interface  string              `json:"interface"`
+	PublicKey  string              `json:"publicKey"`
+	ListenPort int                 `json:"listenPort,omitempty"`
+	Up         bool                `json:"up"` // 网卡是否处于 UP 状态
+	Peers      []WireGuardPeerData `json:"peers"`
+}
+
+// WireGuardPeerData WireGuard 对端数据
+type WireGuardPeerData struct {
+	PublicKey       string   `json:"publicKey"`
+	Endpoint        string   `json:"endpoint,omitempty"`
+	AllowedIPs      []string `json:"allowedIps,omitempty"`
+	LatestHandshake int64    `json:"latestHandshake"` // 最近一次握手时间（时间戳毫秒），0 表示从未握手
+	TransferRx      uint64   `json:"transferRx"`      // 累计接收字节数
+	TransferTx      uint64   `json:"transferTx"`      // 累计发送字节数
+}
+
 // CommandRequest 指令请求
 type CommandRequest struct {
 	ID   string `json:"id"`   // 指令ID
-	Type string `json:"type"` // 指令类型: vps_audit
+	Type string `json:"type"` // 指令类型: vps_audit, log_tail, remediation, log_level, reboot, wol, disk_usage, terminal, remote_command, process_list, process_kill, docker_list, docker_action
 	Args string `json:"args,omitempty"`
 }
 
@@ -293,9 +468,47 @@ type MonitorData struct {
 	CheckedAt    int64  `json:"checkedAt"`              // 检测时间(毫秒时间戳)
 	Message      string `json:"message,omitempty"`      // 附加信息
 	ContentMatch bool   `json:"contentMatch,omitempty"` // 内容匹配结果
+	// 内容断言的逐条结果，如 `jsonpath $.status equals "ok": passed`
+	ContentDetail string `json:"contentDetail,omitempty"`
 	// TLS 证书信息（仅用于 HTTPS）
 	CertExpiryTime int64 `json:"certExpiryTime,omitempty"` // 证书过期时间(毫秒时间戳)
 	CertDaysLeft   int   `json:"certDaysLeft,omitempty"`   // 证书剩余天数
+	// 证书详情及证书链校验结果
+	CertInfo *CertInfo `json:"certInfo,omitempty"`
+}
+
+// CertInfo TLS 证书详情，探测时跳过了校验，证书链由探针单独按系统根证书校验
+type CertInfo struct {
+	Subject            string   `json:"subject"`              // 使用者
+	Issuer             string   `json:"issuer"`               // 签发者
+	SANs               []string `json:"sans,omitempty"`       // 备用名称（域名和 IP）
+	SignatureAlgorithm string   `json:"signatureAlgorithm"`   // 签名算法
+	NotBefore          int64    `json:"notBefore"`            // 生效时间(毫秒时间戳)
+	NotAfter           int64    `json:"notAfter"`             // 过期时间(毫秒时间戳)
+	Fingerprint        string   `json:"fingerprint"`          // 证书 SHA-256 指纹
+	ChainLength        int      `json:"chainLength"`          // 服务器返回的证书数量
+	ChainValid         bool     `json:"chainValid"`           // 证书链是否校验通过
+	ChainError         string   `json:"chainError,omitempty"` // 证书链校验失败原因
+}
+
+// MonitorTraceData 监控项变为离线时探针对目标执行的路径追踪（mtr 或 traceroute）
+// CheckedAt 与触发追踪的离线检测结果一致，用于关联到该次失败记录
+type MonitorTraceData struct {
+	MonitorID  string     `json:"monitorId"`       // 监控项ID
+	Host       string     `json:"host"`            // 追踪的目标主机
+	Tool       string     `json:"tool,omitempty"`  // 使用的工具: mtr, traceroute
+	CheckedAt  int64      `json:"checkedAt"`       // 离线检测的时间(毫秒时间戳)
+	CheckError string     `json:"checkError"`      // 离线检测的错误信息
+	Hops       []TraceHop `json:"hops,omitempty"`  // 逐跳结果
+	Error      string     `json:"error,omitempty"` // 追踪失败原因
+}
+
+// TraceHop 路径追踪的一跳，无响应的跳 Host 为空、丢包率为 100
+type TraceHop struct {
+	Hop    int     `json:"hop"`              // 跳数，从 1 开始
+	Host   string  `json:"host,omitempty"`   // 响应的地址
+	Loss   float64 `json:"loss"`             // 丢包率(百分比)
+	AvgRTT float64 `json:"avgRtt,omitempty"` // 平均往返时间(毫秒)
 }
 
 // TamperProtectConfig 防篡改保护配置（增量更新）
@@ -330,6 +543,21 @@ type TamperAlertData struct {
 	Restored  bool   `json:"restored"`  // 是否已自动恢复
 }
 
+// 内核日志事件分类
+const (
+	KernelEventOOM      = "oom"      // 内存不足，进程被 OOM Killer 杀死
+	KernelEventIO       = "io"       // 块设备或文件系统 I/O 错误
+	KernelEventHardware = "hardware" // 硬件故障（MCE、EDAC 内存错误等）
+)
+
+// KernelEventData 内核日志事件，同一分类在抑制窗口内重复出现的日志只上报一次，Suppressed 记录被抑制的条数
+type KernelEventData struct {
+	Category   string `json:"category"`             // 分类: oom, io, hardware
+	Message    string `json:"message"`              // 内核日志原文
+	Suppressed int    `json:"suppressed,omitempty"` // 上次上报后被抑制的同类日志条数
+	Timestamp  int64  `json:"timestamp"`            // 事件时间(毫秒)
+}
+
 // ==================== 资产清单相关数据结构 ====================
 
 // AssetInventory 资产清单
@@ -1,5 +1,12 @@
 package protocol
 
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
 // MonitorConfigPayload 监控配置 payload
 type MonitorConfigPayload struct {
 	Interval int           `json:"interval"`
@@ -8,12 +15,19 @@ type MonitorConfigPayload struct {
 
 // MonitorItem 监控项配置
 type MonitorItem struct {
-	ID         string             `json:"id"`
-	Type       string             `json:"type"`
-	Target     string             `json:"target"`
-	HTTPConfig *HTTPMonitorConfig `json:"httpConfig,omitempty"`
-	TCPConfig  *TCPMonitorConfig  `json:"tcpConfig,omitempty"`
-	ICMPConfig *ICMPMonitorConfig `json:"icmpConfig,omitempty"`
+	ID                string                    `json:"id"`
+	Type              string                    `json:"type"`
+	Target            string                    `json:"target"`
+	HTTPConfig        *HTTPMonitorConfig        `json:"httpConfig,omitempty"`
+	TCPConfig         *TCPMonitorConfig         `json:"tcpConfig,omitempty"`
+	UDPConfig         *UDPMonitorConfig         `json:"udpConfig,omitempty"`
+	ICMPConfig        *ICMPMonitorConfig        `json:"icmpConfig,omitempty"`
+	TransactionConfig *TransactionMonitorConfig `json:"transactionConfig,omitempty"`
+	MailConfig        *MailMonitorConfig        `json:"mailConfig,omitempty"`
+	Proxy             *ProxyConfig              `json:"proxy,omitempty"`    // 出口代理，用于 HTTP、事务和 TCP 监控
+	Interval          int                       `json:"interval,omitempty"` // 检测频率（秒），失败重试需在下一次检测前完成
+	Timeout           int                       `json:"timeout,omitempty"`  // 单次检测的超时时间（秒），设置后代替各类型配置中的超时时间
+	Retries           int                       `json:"retries,omitempty"`  // 检测失败后的重试次数，全部失败才上报离线
 }
 
 // HTTPMonitorConfig HTTP 监控配置
@@ -24,11 +38,155 @@ type HTTPMonitorConfig struct {
 	Timeout            int               `json:"timeout"`
 	Headers            map[string]string `json:"headers,omitempty"`
 	Body               string            `json:"body,omitempty"`
+	Auth               *HTTPAuthConfig   `json:"auth,omitempty"` // 认证信息，服务端加密保存，下发给探针时解密
+	// 重定向和状态码策略
+	FollowRedirects     *bool    `json:"followRedirects,omitempty"`     // 是否跟随重定向，未设置时跟随；不跟随时以重定向响应的状态码判断
+	MaxRedirects        int      `json:"maxRedirects,omitempty"`        // 最多跟随的重定向次数，默认 10
+	AcceptedStatusCodes []string `json:"acceptedStatusCodes,omitempty"` // 视为正常的状态码，支持 401、200-299、2xx，设置后代替 ExpectedStatusCode
+	// 响应内容断言，与 ExpectedContent 一起全部通过才视为正常
+	Assertions []HTTPAssertion `json:"assertions,omitempty"`
+}
+
+// HTTP 响应内容断言类型
+const (
+	AssertionContains    = "contains"     // 响应包含指定内容
+	AssertionNotContains = "not_contains" // 响应不包含指定内容
+	AssertionRegex       = "regex"        // 响应匹配正则表达式
+	AssertionNotRegex    = "not_regex"    // 响应不匹配正则表达式
+	AssertionJSONPath    = "jsonpath"     // 按 JSONPath 取值后比较
+)
+
+// JSONPath 断言的比较方式
+const (
+	AssertionOpEquals    = "equals" // 默认
+	AssertionOpNotEquals = "not_equals"
+	AssertionOpContains  = "contains"
+	AssertionOpRegex     = "regex"
+	AssertionOpExists    = "exists"
+	AssertionOpNotExists = "not_exists"
+	AssertionOpGreater   = "gt"
+	AssertionOpLess      = "lt"
+)
+
+// HTTPAssertion HTTP 响应内容断言
+type HTTPAssertion struct {
+	Type     string `json:"type"`               // 断言类型
+	Path     string `json:"path,omitempty"`     // JSONPath，如 $.data.status、$.items[0].id，仅 jsonpath 类型使用
+	Operator string `json:"operator,omitempty"` // JSONPath 取值的比较方式，仅 jsonpath 类型使用
+	Value    string `json:"value,omitempty"`    // 期望的内容、正则表达式或比较值
+}
+
+// DefaultMaxRedirects HTTP 监控默认最多跟随的重定向次数
+const DefaultMaxRedirects = 10
+
+// ParseStatusCodeRange 解析可接受的状态码，支持单个状态码（401）、范围（200-299）和通配（2xx）
+func ParseStatusCodeRange(pattern string) (min, max int, err error) {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if len(pattern) == 3 && strings.HasSuffix(pattern, "xx") && pattern[0] >= '1' && pattern[0] <= '5' {
+		min = int(pattern[0]-'0') * 100
+		return min, min + 99, nil
+	}
+	if from, to, ok := strings.Cut(pattern, "-"); ok {
+		min, err = parseStatusCode(from)
+		if err == nil {
+			max, err = parseStatusCode(to)
+		}
+		if err == nil && min > max {
+			err = fmt.Errorf("invalid status code range: %s", pattern)
+		}
+		return min, max, err
+	}
+	min, err = parseStatusCode(pattern)
+	return min, min, err
+}
+
+func parseStatusCode(s string) (int, error) {
+	code, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || code < 100 || code > 599 {
+		return 0, fmt.Errorf("invalid status code: %s", s)
+	}
+	return code, nil
+}
+
+// HTTP 监控的认证方式
+const (
+	HTTPAuthBasic  = "basic"
+	HTTPAuthBearer = "bearer"
+)
+
+// HTTPAuthConfig HTTP 监控认证配置
+type HTTPAuthConfig struct {
+	Type     string `json:"type"` // basic 或 bearer
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
 }
 
 // TCPMonitorConfig TCP 监控配置
 type TCPMonitorConfig struct {
-	Timeout int `json:"timeout"`
+	Timeout    int    `json:"timeout"`
+	Send       string `json:"send,omitempty"`       // 连接后发送的数据，支持 \r\n、\x00 等转义
+	Expect     string `json:"expect,omitempty"`     // 期望的响应内容，为空时只检查能否连接
+	ExpectMode string `json:"expectMode,omitempty"` // 匹配方式: contains（默认）, prefix, regex
+}
+
+// UDPMonitorConfig UDP 监控配置，发送一个数据报并等待应答，在超时时间内收到应答视为正常
+type UDPMonitorConfig struct {
+	Timeout    int    `json:"timeout"`
+	Send       string `json:"send,omitempty"`       // 发送的数据，支持 \r\n、\x00 等转义，二进制协议可用 \x 转义构造
+	Expect     string `json:"expect,omitempty"`     // 期望的应答内容，为空时收到任意应答即视为正常
+	ExpectMode string `json:"expectMode,omitempty"` // 匹配方式: contains（默认）, prefix, regex
+}
+
+// 响应内容匹配方式
+const (
+	ExpectModeContains = "contains"
+	ExpectModePrefix   = "prefix"
+	ExpectModeRegex    = "regex"
+)
+
+// TransactionMonitorConfig 多步骤事务监控配置：按顺序执行 HTTP 请求，各步骤共享 Cookie，
+// 前面步骤提取的变量可在后续步骤的地址、请求头、请求体和断言中以 {{name}} 引用
+type TransactionMonitorConfig struct {
+	Timeout int               `json:"timeout"` // 整个事务的超时时间（秒），默认 60
+	Steps   []TransactionStep `json:"steps"`
+}
+
+// TransactionStep 事务中的一个 HTTP 请求步骤，请求和校验配置与 HTTP 监控相同，Timeout 为单个步骤的超时时间
+type TransactionStep struct {
+	Name    string               `json:"name,omitempty"`
+	URL     string               `json:"url"`
+	Extract []TransactionExtract `json:"extract,omitempty"` // 步骤成功后从响应中提取的变量
+	HTTPMonitorConfig
+}
+
+// 事务变量的提取来源
+const (
+	ExtractSourceJSONPath = "jsonpath" // 按 JSONPath 从 JSON 响应体取值
+	ExtractSourceRegex    = "regex"    // 正则匹配响应体，有分组时取第一个分组
+	ExtractSourceHeader   = "header"   // 取响应头
+)
+
+// TransactionExtract 从步骤响应中提取变量
+type TransactionExtract struct {
+	Name       string `json:"name"`       // 变量名，只能包含字母、数字和下划线
+	Source     string `json:"source"`     // 提取来源
+	Expression string `json:"expression"` // JSONPath、正则表达式或响应头名称
+}
+
+// 邮件监控的加密方式
+const (
+	MailTLSStartTLS = "starttls" // 明文连接后通过 STARTTLS（POP3 为 STLS）升级，默认
+	MailTLSImplicit = "tls"      // 直接建立 TLS 连接，如 465、993、995 端口
+	MailTLSNone     = "none"     // 不加密，只检查服务应答
+)
+
+// MailMonitorConfig SMTP/IMAP/POP3 监控配置，检查服务应答、TLS 握手和可选的登录认证，并上报证书到期时间
+type MailMonitorConfig struct {
+	Timeout  int    `json:"timeout"`            // 超时时间（秒），默认 10
+	TLSMode  string `json:"tlsMode,omitempty"`  // 加密方式，默认 starttls
+	Username string `json:"username,omitempty"` // 配置后检查登录认证，必须使用加密连接
+	Password string `json:"password,omitempty"` // 服务端加密保存，下发给探针时解密
 }
 
 // ICMPMonitorConfig ICMP 监控配置
@@ -36,3 +194,30 @@ type ICMPMonitorConfig struct {
 	Timeout int `json:"timeout"` // 超时时间（秒）
 	Count   int `json:"count"`   // Ping 次数
 }
+
+// ProxyConfig 检测时使用的出口代理，用于只能通过企业代理访问外网的探针
+type ProxyConfig struct {
+	URL      string `json:"url"`                // 代理地址，支持 http://、https://、socks5://，如 socks5://10.0.0.1:1080
+	Username string `json:"username,omitempty"` // 代理认证用户名
+	Password string `json:"password,omitempty"` // 代理认证密码，服务端加密保存，下发给探针时解密
+}
+
+// ProxyURL 解析代理地址并附加认证信息，认证信息只能通过用户名和密码配置
+func (c *ProxyConfig) ProxyURL() (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(c.URL))
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme: %s", u.Scheme)
+	}
+	if u.Hostname() == "" || u.User != nil || (u.Path != "" && u.Path != "/") {
+		return nil, fmt.Errorf("invalid proxy url: %s", c.URL)
+	}
+	if c.Username != "" {
+		u.User = url.UserPassword(c.Username, c.Password)
+	}
+	return u, nil
+}
@@ -0,0 +1,28 @@
+package protocol
+
+// PingConfigPayload Ping 目标配置
+type PingConfigPayload struct {
+	Interval int          `json:"interval"` // 检测间隔（秒）
+	Targets  []PingTarget `json:"targets"`
+}
+
+// PingTarget Ping 目标
+type PingTarget struct {
+	ID     string `json:"id"`
+	Target string `json:"target"`
+	Mode   string `json:"mode,omitempty"` // 探测方式: icmp（默认）, tcp
+	Port   int    `json:"port,omitempty"` // TCP 探测端口
+}
+
+// PingData Ping 检测结果
+type PingData struct {
+	TargetID   string  `json:"targetId"`
+	Target     string  `json:"target"`
+	Sent       int     `json:"sent"`
+	Received   int     `json:"received"`
+	PacketLoss float64 `json:"packetLoss"` // 丢包率(%)
+	MinRtt     float64 `json:"minRtt"`     // 最小延迟(毫秒)
+	AvgRtt     float64 `json:"avgRtt"`     // 平均延迟(毫秒)
+	MaxRtt     float64 `json:"maxRtt"`     // 最大延迟(毫秒)
+	Error      string  `json:"error,omitempty"`
+}
@@ -0,0 +1,17 @@
+package protocol
+
+// RebootRequest 重启指令参数
+type RebootRequest struct {
+	DelaySeconds int `json:"delaySeconds"` // 回复指令响应后延迟多少秒重启，保证响应能送达服务端
+}
+
+// WakeOnLANRequest 网络唤醒指令参数，由同一局域网内的探针发送魔术包
+type WakeOnLANRequest struct {
+	MAC       string `json:"mac"`       // 目标 MAC 地址
+	Broadcast string `json:"broadcast"` // 广播地址（host:port），为空时使用 255.255.255.255:9
+}
+
+// PowerResult 电源操作执行结果
+type PowerResult struct {
+	Output string `json:"output"` // 执行输出
+}
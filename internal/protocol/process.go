@@ -0,0 +1,66 @@
+package protocol
+
+// 进程列表限制（服务端和探针两侧都会校验）
+const (
+	ProcessListDefaultLimit    = 100 // 默认按 CPU 和内存分别推送占用最高的进程数
+	ProcessListMaxLimit        = 500 // 推送进程数的上限
+	ProcessListDefaultInterval = 3   // 默认刷新间隔（秒）
+	ProcessListMaxInterval     = 60  // 刷新间隔的上限（秒）
+	ProcessListMaxDuration     = 600 // 持续推送的最长时间（秒），到期后需要重新打开
+	ProcessCmdlineMaxLength    = 512 // 推送的命令行最大字节数，超出部分截断
+)
+
+// 结束进程使用的信号
+const (
+	ProcessSignalTerm = "TERM" // 请求进程退出（默认）
+	ProcessSignalKill = "KILL" // 强制结束
+)
+
+// ProcessListRequest 进程列表参数（作为 process_list 指令的 Args 下发）
+type ProcessListRequest struct {
+	Limit    int `json:"limit"`    // 按 CPU 和内存分别取占用最高的进程数，合并后推送
+	Interval int `json:"interval"` // 刷新间隔（秒）
+	Duration int `json:"duration"` // 持续推送的时间（秒）
+}
+
+// ProcessSnapshot 进程列表快照
+type ProcessSnapshot struct {
+	ID        string        `json:"id"`                  // 指令ID
+	Processes []ProcessInfo `json:"processes,omitempty"` // CPU 或内存占用最高的进程，CPU 使用率为两次采集之间的平均值
+	Total     int           `json:"total"`               // 进程总数
+	Timestamp int64         `json:"timestamp"`           // 采集时间（时间戳毫秒）
+	Done      bool          `json:"done,omitempty"`      // 是否已结束推送
+	Error     string        `json:"error,omitempty"`     // 错误信息
+}
+
+// ProcessListStop 停止推送进程列表
+type ProcessListStop struct {
+	ID string `json:"id"` // 指令ID
+}
+
+// ProcessKillRequest 结束进程参数（作为 process_kill 指令的 Args 下发）
+type ProcessKillRequest struct {
+	PID        int32  `json:"pid"`
+	CreateTime int64  `json:"createTime"` // 进程的创建时间（毫秒），与探针上的进程不一致时拒绝执行，避免 PID 被复用后结束了其他进程
+	Signal     string `json:"signal"`     // 信号: TERM（默认）, KILL
+}
+
+// ProcessKillResult 结束进程结果
+type ProcessKillResult struct {
+	Name string `json:"name"` // 进程名
+}
+
+// NormalizeProcessListRequest 补全默认值并将推送数量、间隔和时长限制在允许范围内
+func NormalizeProcessListRequest(req *ProcessListRequest) {
+	if req.Limit <= 0 {
+		req.Limit = ProcessListDefaultLimit
+	}
+	req.Limit = min(req.Limit, ProcessListMaxLimit)
+	if req.Interval <= 0 {
+		req.Interval = ProcessListDefaultInterval
+	}
+	req.Interval = min(req.Interval, ProcessListMaxInterval)
+	if req.Duration <= 0 || req.Duration > ProcessListMaxDuration {
+		req.Duration = ProcessListMaxDuration
+	}
+}
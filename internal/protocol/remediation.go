@@ -0,0 +1,12 @@
+package protocol
+
+// RemediationRequest 修复动作参数（作为 remediation 指令的 Args 下发）
+type RemediationRequest struct {
+	Action string `json:"action"` // 修复动作: restart_service（重启 systemd 服务）, clean_dir（清空目录）
+	Target string `json:"target"` // systemd 服务名或目录路径，需在探针白名单中
+}
+
+// RemediationResult 修复动作执行结果
+type RemediationResult struct {
+	Output string `json:"output"` // 执行输出
+}
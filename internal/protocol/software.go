@@ -0,0 +1,22 @@
+package protocol
+
+// SoftwareInventoryData 软件清单上报数据
+type SoftwareInventoryData struct {
+	Items      []SoftwareItem   `json:"items"`
+	Containers []ContainerImage `json:"containers,omitempty"` // 运行中的容器镜像，未安装 Docker 时为空
+}
+
+// SoftwareItem 软件版本信息
+type SoftwareItem struct {
+	Name    string `json:"name"`    // 软件名称: kernel, docker, nginx, openssl, glibc
+	Version string `json:"version"` // 版本号
+}
+
+// ContainerImage 运行中容器使用的镜像
+type ContainerImage struct {
+	ContainerID   string   `json:"containerId"`   // 容器ID
+	ContainerName string   `json:"containerName"` // 容器名称
+	Image         string   `json:"image"`         // 启动容器时使用的镜像引用，如 nginx:1.25
+	ImageID       string   `json:"imageId"`       // 本地镜像ID
+	RepoDigests   []string `json:"repoDigests"`   // 镜像的仓库摘要，如 nginx@sha256:...
+}
@@ -0,0 +1,49 @@
+package protocol
+
+// 远程终端限制（服务端和探针两侧都会校验）
+const (
+	TerminalIdleTimeout    = 600  // 默认空闲超时（秒），期间没有键盘输入即结束会话
+	TerminalMaxIdleTimeout = 3600 // 空闲超时的上限（秒）
+	TerminalMaxCols        = 500  // 终端窗口的最大列数
+	TerminalMaxRows        = 200  // 终端窗口的最大行数
+)
+
+// TerminalOpenRequest 打开终端的参数（作为 terminal 指令的 Args 下发）
+type TerminalOpenRequest struct {
+	Cols        int    `json:"cols"`           // 终端列数
+	Rows        int    `json:"rows"`           // 终端行数
+	Term        string `json:"term,omitempty"` // TERM 环境变量，默认 xterm-256color
+	IdleTimeout int    `json:"idleTimeout"`    // 空闲超时（秒）
+}
+
+// TerminalInput 终端输入，Cols 和 Rows 大于 0 时调整窗口大小
+type TerminalInput struct {
+	ID   string `json:"id"`             // 指令ID
+	Data []byte `json:"data,omitempty"` // 键盘输入
+	Cols int    `json:"cols,omitempty"`
+	Rows int    `json:"rows,omitempty"`
+}
+
+// TerminalOutput 终端输出片段
+type TerminalOutput struct {
+	ID    string `json:"id"`              // 指令ID
+	Data  []byte `json:"data,omitempty"`  // 终端输出，可能包含不完整的 UTF-8 字符，按字节传输
+	Done  bool   `json:"done,omitempty"`  // 会话是否已结束
+	Error string `json:"error,omitempty"` // 错误信息
+}
+
+// TerminalClose 结束终端会话
+type TerminalClose struct {
+	ID string `json:"id"` // 指令ID
+}
+
+// ClampTerminalSize 将终端窗口大小限制在有效范围内
+func ClampTerminalSize(cols, rows int) (int, int) {
+	if cols <= 0 {
+		cols = 80
+	}
+	if rows <= 0 {
+		rows = 24
+	}
+	return min(cols, TerminalMaxCols), min(rows, TerminalMaxRows)
+}
@@ -0,0 +1,155 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/dushixiang/pika/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// agentDataModels 按探针存储的所有数据表（不含探针本身）
+var agentDataModels = []schema.Tabler{
+	&models.HostMetric{},
+	&models.CPUMetric{},
+	&models.CPUCoreMetric{},
+	&models.CPUTimesMetric{},
+	&models.PressureMetric{},
+	&models.MemoryMetric{},
+	&models.DiskMetric{},
+	&models.NetworkMetric{},
+	&models.NetworkConnectionMetric{},
+	&models.DiskIOMetric{},
+	&models.GPUMetric{},
+	&models.TemperatureMetric{},
+	&models.MonitorMetric{},
+	&models.PingMetric{},
+	&models.AggregatedCPUMetricModel{},
+	&models.AggregatedCPUCoreMetricModel{},
+	&models.AggregatedCPUTimesMetricModel{},
+	&models.AggregatedPressureMetricModel{},
+	&models.AggregatedMemoryMetricModel{},
+	&models.AggregatedDiskMetricModel{},
+	&models.AggregatedNetworkMetricModel{},
+	&models.AggregatedNetworkConnectionMetricModel{},
+	&models.AggregatedDiskIOMetricModel{},
+	&models.AggregatedGPUMetricModel{},
+	&models.AggregatedTemperatureMetricModel{},
+	&models.AggregatedMonitorMetricModel{},
+	&models.MonitorStats{},
+	&models.MonitorCert{},
+	&models.MonitorTrace{},
+	&models.AlertRecord{},
+	&models.AlertState{},
+	&models.RemediationRecord{},
+	&models.PowerTask{},
+	&models.AuditResult{},
+	&models.SoftwareInventory{},
+	&models.SecurityFinding{},
+	&models.ContainerImage{},
+	&models.DDNSConfig{},
+	&models.DDNSRecord{},
+	&models.TamperProtectConfig{},
+	&models.TamperEvent{},
+	&models.TamperAlert{},
+	&models.PingTarget{},
+	&models.AgentSession{},
+	&models.NotificationJob{},
+	&models.NotificationLog{},
+	&models.TerminalSession{},
+	&models.CommandExecution{},
+	&models.ProcessKillRecord{},
+	&models.DockerOperation{},
+}
+
+// agentChildDataModel 通过父表关联到探针的数据表
+type agentChildDataModel struct {
+	model      schema.Tabler
+	foreignKey string        // 关联父表主键的字段
+	parent     schema.Tabler // 带 agent_id 字段的父表
+}
+
+// agentChildDataModels 没有 agent_id 字段、通过父表关联到探针的数据表，清除时先于父表删除
+var agentChildDataModels = []agentChildDataModel{
+	{model: &models.TerminalRecordChunk{}, foreignKey: "session_id", parent: &models.TerminalSession{}},
+}
+
+// AgentDataRepo 探针数据导出与清除
+type AgentDataRepo struct {
+	db *gorm.DB
+}
+
+func NewAgentDataRepo(db *gorm.DB) *AgentDataRepo {
+	return &AgentDataRepo{
+		db: db,
+	}
+}
+
+// TableNames 返回按探针存储的所有数据表名
+func (r *AgentDataRepo) TableNames() []string {
+	names := make([]string, 0, len(agentDataModels)+len(agentChildDataModels))
+	for _, model := range agentDataModels {
+		names = append(names, model.TableName())
+	}
+	for _, child := range agentChildDataModels {
+		names = append(names, child.model.TableName())
+	}
+	return names
+}
+
+// agentCondition 返回筛选探针在指定表中数据的条件
+func agentCondition(table string) string {
+	for _, child := range agentChildDataModels {
+		if child.model.TableName() == table {
+			return child.condition()
+		}
+	}
+	return "agent_id = ?"
+}
+
+func (c agentChildDataModel) condition() string {
+	return c.foreignKey + " IN (SELECT id FROM " + c.parent.TableName() + " WHERE agent_id = ?)"
+}
+
+// EachRow 逐行读取探针在指定表中的数据，避免一次性加载到内存
+func (r *AgentDataRepo) EachRow(ctx context.Context, table, agentID string, fn func(row map[string]interface{}) error) error {
+	rows, err := r.db.WithContext(ctx).Table(table).Where(agentCondition(table), agentID).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row := make(map[string]interface{})
+		if err := r.db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Purge 在一个事务中删除探针的所有数据，返回每张表删除的行数
+func (r *AgentDataRepo) Purge(ctx context.Context, agentID string) (map[string]int64, error) {
+	deleted := make(map[string]int64, len(agentDataModels)+len(agentChildDataModels))
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, child := range agentChildDataModels {
+			result := tx.Where(child.condition(), agentID).Delete(child.model)
+			if result.Error != nil {
+				return result.Error
+			}
+			deleted[child.model.TableName()] = result.RowsAffected
+		}
+		for _, model := range agentDataModels {
+			result := tx.Where("agent_id = ?", agentID).Delete(model)
+			if result.Error != nil {
+				return result.Error
+			}
+			deleted[model.TableName()] = result.RowsAffected
+		}
+		return nil
+	})
+	return deleted, err
+}
@@ -0,0 +1,66 @@
+package repo
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestAgentDataModels 检查 models 中所有带探针ID字段的表都已加入 agentDataModels
+// 新增按探针存储的数据表时需要同时登记，否则导出和清除探针数据时会遗漏
+func TestAgentDataModels(t *testing.T) {
+	listed := make(map[string]bool, len(agentDataModels))
+	for _, model := range agentDataModels {
+		listed[reflect.TypeOf(model).Elem().Name()] = true
+	}
+
+	files, err := filepath.Glob("../models/*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	var found int
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(f, func(node ast.Node) bool {
+			spec, ok := node.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			st, ok := spec.Type.(*ast.StructType)
+			// MonitorMetric 的字段名为 AgentId
+			if !ok || !(hasField(st, "AgentID") || hasField(st, "AgentId")) {
+				return true
+			}
+			found++
+			if !listed[spec.Name.Name] {
+				t.Errorf("%s 按探针存储数据，但未加入 agentDataModels", spec.Name.Name)
+			}
+			return true
+		})
+	}
+	if found == 0 {
+		t.Fatalf("未在 models 中找到带探针ID字段的表")
+	}
+}
+
+func hasField(st *ast.StructType, name string) bool {
+	for _, field := range st.Fields.List {
+		for _, ident := range field.Names {
+			if ident.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
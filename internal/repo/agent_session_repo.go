@@ -0,0 +1,74 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/go-orz/orz"
+	"gorm.io/gorm"
+)
+
+type AgentSessionRepo struct {
+	orz.Repository[models.AgentSession, int64]
+	db *gorm.DB
+}
+
+func NewAgentSessionRepo(db *gorm.DB) *AgentSessionRepo {
+	return &AgentSessionRepo{
+		Repository: orz.NewRepository[models.AgentSession, int64](db),
+		db:         db,
+	}
+}
+
+// Close 结束会话
+func (r *AgentSessionRepo) Close(ctx context.Context, id int64, disconnectedAt int64) error {
+	return r.db.WithContext(ctx).
+		Model(&models.AgentSession{}).
+		Where("id = ? AND disconnected_at = 0", id).
+		Update("disconnected_at", disconnectedAt).Error
+}
+
+// CloseOpenByAgentID 结束探针所有未结束的会话，断开时间不早于连接时间
+func (r *AgentSessionRepo) CloseOpenByAgentID(ctx context.Context, agentID string, disconnectedAt int64) error {
+	return r.db.WithContext(ctx).
+		Model(&models.AgentSession{}).
+		Where("agent_id = ? AND disconnected_at = 0", agentID).
+		Update("disconnected_at", gorm.Expr("CASE WHEN connected_at > ? THEN connected_at ELSE ? END", disconnectedAt, disconnectedAt)).Error
+}
+
+// FindByAgentIDBetween 查询与时间范围有交集的会话，按连接时间升序
+func (r *AgentSessionRepo) FindByAgentIDBetween(ctx context.Context, agentID string, start, end int64) ([]models.AgentSession, error) {
+	var sessions []models.AgentSession
+	err := r.db.WithContext(ctx).
+		Where("agent_id = ? AND connected_at < ?", agentID, end).
+		Where("disconnected_at = 0 OR disconnected_at > ?", start).
+		Order("connected_at asc").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// DeleteByAgentID 删除探针的所有会话
+func (r *AgentSessionRepo) DeleteByAgentID(ctx context.Context, agentID string) error {
+	return r.db.WithContext(ctx).
+		Where("agent_id = ?", agentID).
+		Delete(&models.AgentSession{}).Error
+}
+
+// DeleteBefore 删除指定时间之前已结束的会话
+func (r *AgentSessionRepo) DeleteBefore(ctx context.Context, before int64) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("disconnected_at > 0 AND disconnected_at < ?", before).
+		Delete(&models.AgentSession{})
+	return result.RowsAffected, result.Error
+}
+
+// FindFirstConnectedAt 查询探针最早一次连接的时间，没有会话时返回 0
+func (r *AgentSessionRepo) FindFirstConnectedAt(ctx context.Context, agentID string) (int64, error) {
+	var connectedAt int64
+	err := r.db.WithContext(ctx).
+		Model(&models.AgentSession{}).
+		Where("agent_id = ?", agentID).
+		Select("COALESCE(MIN(connected_at), 0)").
+		Scan(&connectedAt).Error
+	return connectedAt, err
+}
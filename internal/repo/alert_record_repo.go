@@ -58,3 +58,64 @@ func (r *AlertRecordRepo) GetLatestAlertRecord(ctx context.Context, configID str
 func (r *AlertRecordRepo) Clear(ctx context.Context) error {
 	return r.db.WithContext(ctx).Where("1=1").Delete(&models.AlertRecord{}).Error
 }
+
+// CountFiringByLevel 按告警级别统计告警中的记录数量
+func (r *AlertRecordRepo) CountFiringByLevel(ctx context.Context, agentIDs []string) (map[string]int64, error) {
+	var rows []struct {
+		Level string
+		Count int64
+	}
+	err := r.db.WithContext(ctx).
+		Model(&models.AlertRecord{}).
+		Select("level, COUNT(*) AS count").
+		Where("status = ? AND agent_id IN ?", "firing", agentIDs).
+		Group("level").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		result[row.Level] = row.Count
+	}
+	return result, nil
+}
+
+// AlertRecordQuery 告警记录查询条件
+type AlertRecordQuery struct {
+	AgentID   string
+	AlertType string
+	Level     string
+	Start     int64 // 触发时间起始（毫秒），0 表示不限制
+	End       int64 // 触发时间结束（毫秒），0 表示不限制
+	Limit     int   // 最多返回条数，0 表示不限制
+}
+
+// FindByQuery 按条件查询告警记录，按触发时间倒序
+func (r *AlertRecordRepo) FindByQuery(ctx context.Context, query AlertRecordQuery) ([]models.AlertRecord, error) {
+	db := r.db.WithContext(ctx)
+	if query.AgentID != "" {
+		db = db.Where("agent_id = ?", query.AgentID)
+	}
+	if query.AlertType != "" {
+		db = db.Where("alert_type = ?", query.AlertType)
+	}
+	if query.Level != "" {
+		db = db.Where("level = ?", query.Level)
+	}
+	if query.Start > 0 {
+		db = db.Where("fired_at >= ?", query.Start)
+	}
+	if query.End > 0 {
+		db = db.Where("fired_at <= ?", query.End)
+	}
+
+	if query.Limit > 0 {
+		db = db.Limit(query.Limit)
+	}
+
+	var records []models.AlertRecord
+	err := db.Order("fired_at DESC").Find(&records).Error
+	return records, err
+}
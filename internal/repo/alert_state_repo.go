@@ -46,6 +46,20 @@ func (r *AlertStateRepo) DeleteAlertStatesByConfigID(ctx context.Context, config
 	return r.db.WithContext(ctx).Where("config_id = ?", configID).Delete(&models.AlertState{}).Error
 }
 
+// FindByAgentAndType 获取探针某类告警的所有状态
+func (r *AlertStateRepo) FindByAgentAndType(ctx context.Context, agentID, alertType string) ([]models.AlertState, error) {
+	var states []models.AlertState
+	err := r.db.WithContext(ctx).Where("agent_id = ? AND alert_type = ?", agentID, alertType).Find(&states).Error
+	return states, err
+}
+
+// FindByType 获取某类告警的所有状态
+func (r *AlertStateRepo) FindByType(ctx context.Context, alertType string) ([]models.AlertState, error) {
+	var states []models.AlertState
+	err := r.db.WithContext(ctx).Where("alert_type = ?", alertType).Find(&states).Error
+	return states, err
+}
+
 // LoadAllStates 加载所有告警状态
 func (r *AlertStateRepo) LoadAllStates(ctx context.Context) ([]models.AlertState, error) {
 	var states []models.AlertState
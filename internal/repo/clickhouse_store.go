@@ -0,0 +1,928 @@
+package repo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/config"
+	"github.com/dushixiang/pika/internal/models"
+)
+
+var clickHouseIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// clickHouseTables 指标表结构及迁移语句，列名与模型的 JSON 字段一致，写入时直接以 JSONEachRow 格式提交模型
+// 时序数据按月分区、按探针和时间排序；主机信息只保留每个探针的最新一条
+var clickHouseTables = []string{
+	`CREATE TABLE IF NOT EXISTS cpu_metrics (
+		agentId LowCardinality(String),
+		usagePercent Float64,
+		logicalCores Int32,
+		physicalCores Int32,
+		modelName String,
+		timestamp Int64
+	) ENGINE = MergeTree
+	PARTITION BY toYYYYMM(fromUnixTimestamp64Milli(timestamp))
+	ORDER BY (agentId, timestamp)`,
+
+	`CREATE TABLE IF NOT EXISTS cpu_core_metrics (
+		agentId LowCardinality(String),
+		core Int32,
+		usagePercent Float64,
+		timestamp Int64
+	) ENGINE = MergeTree
+	PARTITION BY toYYYYMM(fromUnixTimestamp64Milli(timestamp))
+	ORDER BY (agentId, core, timestamp)`,
+
+	`CREATE TABLE IF NOT EXISTS cpu_times_metrics (
+		agentId LowCardinality(String),
+		userPercent Float64,
+		systemPercent Float64,
+		iowaitPercent Float64,
+		stealPercent Float64,
+		idlePercent Float64,
+		timestamp Int64
+	) ENGINE = MergeTree
+	PARTITION BY toYYYYMM(fromUnixTimestamp64Milli(timestamp))
+	ORDER BY (agentId, timestamp)`,
+
+	`CREATE TABLE IF NOT EXISTS pressure_metrics (
+		agentId LowCardinality(String),
+		resource LowCardinality(String),
+		somePercent Float64,
+		fullPercent Float64,
+		timestamp Int64
+	) ENGINE = MergeTree
+	PARTITION BY toYYYYMM(fromUnixTimestamp64Milli(timestamp))
+	ORDER BY (agentId, resource, timestamp)`,
+
+	`CREATE TABLE IF NOT EXISTS memory_metrics (
+		agentId LowCardinality(String),
+		total UInt64,
+		used UInt64,
+		free UInt64,
+		available UInt64,
+		usagePercent Float64,
+		swapTotal UInt64,
+		swapUsed UInt64,
+		swapFree UInt64,
+		timestamp Int64
+	) ENGINE = MergeTree
+	PARTITION BY toYYYYMM(fromUnixTimestamp64Milli(timestamp))
+	ORDER BY (agentId, timestamp)`,
+
+	`CREATE TABLE IF NOT EXISTS disk_metrics (
+		agentId LowCardinality(String),
+		mountPoint LowCardinality(String),
+		total UInt64,
+		used UInt64,
+		free UInt64,
+		usagePercent Float64,
+		timestamp Int64
+	) ENGINE = MergeTree
+	PARTITION BY toYYYYMM(fromUnixTimestamp64Milli(timestamp))
+	ORDER BY (agentId, mountPoint, timestamp)`,
+
+	`CREATE TABLE IF NOT EXISTS network_metrics (
+		agentId LowCardinality(String),
+		interface LowCardinality(String),
+		bytesSentRate UInt64,
+		bytesRecvRate UInt64,
+		bytesSentTotal UInt64,
+		bytesRecvTotal UInt64,
+		timestamp Int64
+	) ENGINE = MergeTree
+	PARTITION BY toYYYYMM(fromUnixTimestamp64Milli(timestamp))
+	ORDER BY (agentId, interface, timestamp)`,
+
+	`CREATE TABLE IF NOT EXISTS network_connection_metrics (
+		agentId LowCardinality(String),
+		established UInt32,
+		synSent UInt32,
+		synRecv UInt32,
+		finWait1 UInt32,
+		finWait2 UInt32,
+		timeWait UInt32,
+		close UInt32,
+		closeWait UInt32,
+		lastAck UInt32,
+		listen UInt32,
+		closing UInt32,
+		total UInt32,
+		timestamp Int64
+	) ENGINE = MergeTree
+	PARTITION BY toYYYYMM(fromUnixTimestamp64Milli(timestamp))
+	ORDER BY (agentId, timestamp)`,
+
+	`CREATE TABLE IF NOT EXISTS disk_io_metrics (
+		agentId LowCardinality(String),
+		device LowCardinality(String),
+		readCount UInt64,
+		writeCount UInt64,
+		readBytes UInt64,
+		writeBytes UInt64,
+		readBytesRate UInt64,
+		writeBytesRate UInt64,
+		readTime UInt64,
+		writeTime UInt64,
+		ioTime UInt64,
+		iopsInProgress UInt64,
+		timestamp Int64
+	) ENGINE = MergeTree
+	PARTITION BY toYYYYMM(fromUnixTimestamp64Milli(timestamp))
+	ORDER BY (agentId, timestamp)`,
+
+	"CREATE TABLE IF NOT EXISTS gpu_metrics (" + `
+		agentId LowCardinality(String),
+		` + "`index`" + ` Int32,
+		name LowCardinality(String),
+		utilization Float64,
+		memoryTotal UInt64,
+		memoryUsed UInt64,
+		memoryFree UInt64,
+		temperature Float64,
+		powerDraw Float64,
+		fanSpeed Float64,
+		performanceState LowCardinality(String),
+		timestamp Int64
+	) ENGINE = MergeTree
+	PARTITION BY toYYYYMM(fromUnixTimestamp64Milli(timestamp))
+	ORDER BY (agentId, ` + "`index`" + `, timestamp)`,
+
+	`CREATE TABLE IF NOT EXISTS temperature_metrics (
+		agentId LowCardinality(String),
+		sensorKey LowCardinality(String),
+		sensorLabel LowCardinality(String),
+		temperature Float64,
+		timestamp Int64
+	) ENGINE = MergeTree
+	PARTITION BY toYYYYMM(fromUnixTimestamp64Milli(timestamp))
+	ORDER BY (agentId, sensorKey, timestamp)`,
+
+	`CREATE TABLE IF NOT EXISTS host_metrics (
+		agentId String,
+		os LowCardinality(String),
+		platform LowCardinality(String),
+		platformVersion LowCardinality(String),
+		kernelVersion LowCardinality(String),
+		kernelArch LowCardinality(String),
+		uptime UInt64,
+		bootTime UInt64,
+		procs UInt64,
+		timestamp Int64
+	) ENGINE = ReplacingMergeTree(timestamp)
+	ORDER BY agentId`,
+
+	`CREATE TABLE IF NOT EXISTS ping_metrics (
+		agentId LowCardinality(String),
+		targetId LowCardinality(String),
+		target LowCardinality(String),
+		minLatency Float64,
+		avgLatency Float64,
+		maxLatency Float64,
+		packetLoss Float64,
+		timestamp Int64
+	) ENGINE = MergeTree
+	PARTITION BY toYYYYMM(fromUnixTimestamp64Milli(timestamp))
+	ORDER BY (agentId, targetId, timestamp)`,
+
+	`CREATE TABLE IF NOT EXISTS monitor_metrics (
+		agentId LowCardinality(String),
+		monitorId LowCardinality(String),
+		type LowCardinality(String),
+		target String,
+		status LowCardinality(String),
+		statusCode Int32,
+		responseTime Int64,
+		error String,
+		message String,
+		contentMatch Bool,
+		contentDetail String DEFAULT '',
+		certExpiryTime Int64,
+		certDaysLeft Int32,
+		checks UInt32 DEFAULT 1,
+		responseHistogram String DEFAULT '',
+		timestamp Int64
+	) ENGINE = MergeTree
+	PARTITION BY toYYYYMM(fromUnixTimestamp64Milli(timestamp))
+	ORDER BY (monitorId, agentId, timestamp)`,
+
+	// 早期版本创建的表没有 checks、responseHistogram 列
+	`ALTER TABLE monitor_metrics ADD COLUMN IF NOT EXISTS checks UInt32 DEFAULT 1 AFTER certDaysLeft`,
+	`ALTER TABLE monitor_metrics ADD COLUMN IF NOT EXISTS responseHistogram String DEFAULT '' AFTER checks`,
+	`ALTER TABLE monitor_metrics ADD COLUMN IF NOT EXISTS contentDetail String DEFAULT '' AFTER contentMatch`,
+}
+
+// clickHouseTimeSeriesTables 按时间清理的指标表（主机信息只保留最新的，不需要清理）
+var clickHouseTimeSeriesTables = []string{
+	"cpu_metrics",
+	"cpu_core_metrics",
+	"cpu_times_metrics",
+	"pressure_metrics",
+	"memory_metrics",
+	"disk_metrics",
+	"network_metrics",
+	"network_connection_metrics",
+	"disk_io_metrics",
+	"gpu_metrics",
+	"temperature_metrics",
+	"monitor_metrics",
+	"ping_metrics",
+}
+
+// ClickHouseMetricStore 基于 ClickHouse 的指标存储，通过 HTTP 接口读写，适用于探针数量多、保留时间长的部署
+// 写入使用异步插入由服务端合并成批，查询直接在原始数据上聚合，因此不实现 MetricAggregator
+type ClickHouseMetricStore struct {
+	endpoint   string
+	database   string
+	username   string
+	password   string
+	httpClient *http.Client
+}
+
+// NewClickHouseMetricStore 创建 ClickHouse 指标存储，并自动创建数据库和表结构
+func NewClickHouseMetricStore(cfg *config.ClickHouseConfig) (*ClickHouseMetricStore, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("ClickHouse 地址未配置")
+	}
+	database := cfg.Database
+	if database == "" {
+		database = "pika"
+	}
+	if !clickHouseIdentifier.MatchString(database) {
+		return nil, fmt.Errorf("ClickHouse 数据库名不合法: %s", database)
+	}
+
+	s := &ClickHouseMetricStore{
+		endpoint: strings.TrimRight(cfg.URL, "/") + "/",
+		database: database,
+		username: cfg.Username,
+		password: cfg.Password,
+		httpClient: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := s.migrate(ctx); err != nil {
+		return nil, fmt.Errorf("初始化 ClickHouse 表结构失败: %w", err)
+	}
+	return s, nil
+}
+
+// migrate 创建数据库和指标表
+func (s *ClickHouseMetricStore) migrate(ctx context.Context) error {
+	// 目标数据库可能还不存在，建库语句在 default 库下执行
+	body, err := s.request(ctx, "CREATE DATABASE IF NOT EXISTS "+s.database, nil, nil, url.Values{
+		"database": {"default"},
+	})
+	if err != nil {
+		return err
+	}
+	_ = body.Close()
+
+	for _, ddl := range clickHouseTables {
+		if err := s.exec(ctx, ddl, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// request 发送一条 SQL，params 以 {name:Type} 查询参数的形式传递；data 不为空时作为 INSERT 的数据，SQL 放在 URL 中
+func (s *ClickHouseMetricStore) request(ctx context.Context, query string, params map[string]any, data io.Reader, settings url.Values) (io.ReadCloser, error) {
+	values := url.Values{}
+	values.Set("database", s.database)
+	for key, items := range settings {
+		values[key] = items
+	}
+	for name, value := range params {
+		values.Set("param_"+name, clickHouseParam(value))
+	}
+
+	body := data
+	if data == nil {
+		body = strings.NewReader(query)
+	} else {
+		values.Set("query", query)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint+"?"+values.Encode(), body)
+	if err != nil {
+		return nil, err
+	}
+	if s.username != "" {
+		req.Header.Set("X-ClickHouse-User", s.username)
+	}
+	if s.password != "" {
+		req.Header.Set("X-ClickHouse-Key", s.password)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("clickhouse: status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return resp.Body, nil
+}
+
+// exec 执行不返回数据的 SQL
+func (s *ClickHouseMetricStore) exec(ctx context.Context, query string, params map[string]any) error {
+	body, err := s.request(ctx, query, params, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	_, _ = io.Copy(io.Discard, body)
+	return nil
+}
+
+// insert 以 JSONEachRow 格式写入，开启异步插入由 ClickHouse 合并小批量写入
+func (s *ClickHouseMetricStore) insert(ctx context.Context, table string, rows ...any) error {
+	buf, err := encodeJSONEachRow(rows...)
+	if err != nil {
+		return err
+	}
+
+	body, err := s.request(ctx, "INSERT INTO "+table+" FORMAT JSONEachRow", nil, buf, url.Values{
+		"input_format_skip_unknown_fields": {"1"},
+		"async_insert":                     {"1"},
+		"wait_for_async_insert":            {"0"},
+	})
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	_, _ = io.Copy(io.Discard, body)
+	return nil
+}
+
+// encodeJSONEachRow 将每行编码为一个 JSON 对象，行之间以换行分隔
+func encodeJSONEachRow(rows ...any) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, row := range rows {
+		if err := enc.Encode(row); err != nil {
+			return nil, err
+		}
+	}
+	return &buf, nil
+}
+
+// clickHouseSelect 执行查询并按 JSON 字段名解码到 T，查询中的列别名需与 T 的 JSON 标签一致
+func clickHouseSelect[T any](ctx context.Context, s *ClickHouseMetricStore, query string, params map[string]any) ([]T, error) {
+	body, err := s.request(ctx, query+"\nFORMAT JSONEachRow", params, nil, url.Values{
+		"output_format_json_quote_64bit_integers": {"0"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var rows []T
+	dec := json.NewDecoder(body)
+	for {
+		var row T
+		if err := dec.Decode(&row); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// clickHouseParamEscaper HTTP 查询参数按 TabSeparated 的 escaped 格式解析，需要转义反斜杠和控制字符
+var clickHouseParamEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`, "\x00", `\0`, "\b", `\b`, "\f", `\f`)
+
+// clickHouseParam 将参数格式化为 HTTP 查询参数，字符串按 TSV 规则转义
+func clickHouseParam(value any) string {
+	switch v := value.(type) {
+	case string:
+		return clickHouseParamEscaper.Replace(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// rangeParams 按探针和时间范围查询的通用参数，interval 为聚合粒度（秒）
+func rangeParams(agentID string, start, end int64, interval int) map[string]any {
+	return map[string]any{
+		"agentId":  agentID,
+		"start":    start,
+		"end":      end,
+		"interval": int64(interval) * 1000,
+	}
+}
+
+// SaveCPUMetric 保存CPU指标
+func (s *ClickHouseMetricStore) SaveCPUMetric(ctx context.Context, metric *models.CPUMetric) error {
+	return s.insert(ctx, "cpu_metrics", metric)
+}
+
+// SaveMemoryMetric 保存内存指标
+func (s *ClickHouseMetricStore) SaveMemoryMetric(ctx context.Context, metric *models.MemoryMetric) error {
+	return s.insert(ctx, "memory_metrics", metric)
+}
+
+// SaveDiskMetric 保存磁盘指标
+func (s *ClickHouseMetricStore) SaveDiskMetric(ctx context.Context, metric *models.DiskMetric) error {
+	return s.insert(ctx, "disk_metrics", metric)
+}
+
+// SaveNetworkMetric 保存网络指标
+func (s *ClickHouseMetricStore) SaveNetworkMetric(ctx context.Context, metric *models.NetworkMetric) error {
+	return s.insert(ctx, "network_metrics", metric)
+}
+
+// SaveNetworkConnectionMetric 保存网络连接统计指标
+func (s *ClickHouseMetricStore) SaveNetworkConnectionMetric(ctx context.Context, metric *models.NetworkConnectionMetric) error {
+	return s.insert(ctx, "network_connection_metrics", metric)
+}
+
+// SaveDiskIOMetric 保存磁盘IO指标
+func (s *ClickHouseMetricStore) SaveDiskIOMetric(ctx context.Context, metric *models.DiskIOMetric) error {
+	return s.insert(ctx, "disk_io_metrics", metric)
+}
+
+// SaveGPUMetric 保存GPU指标
+func (s *ClickHouseMetricStore) SaveGPUMetric(ctx context.Context, metric *models.GPUMetric) error {
+	return s.insert(ctx, "gpu_metrics", metric)
+}
+
+// SaveTemperatureMetric 保存温度指标
+func (s *ClickHouseMetricStore) SaveTemperatureMetric(ctx context.Context, metric *models.TemperatureMetric) error {
+	return s.insert(ctx, "temperature_metrics", metric)
+}
+
+// SaveHostMetric 保存主机信息指标（ReplacingMergeTree 按探针保留时间戳最新的一条）
+func (s *ClickHouseMetricStore) SaveHostMetric(ctx context.Context, metric *models.HostMetric) error {
+	return s.insert(ctx, "host_metrics", metric)
+}
+
+// SavePingMetric 保存 Ping 指标
+func (s *ClickHouseMetricStore) SavePingMetric(ctx context.Context, metric *models.PingMetric) error {
+	return s.insert(ctx, "ping_metrics", metric)
+}
+
+// SaveMonitorMetric 保存监控指标
+func (s *ClickHouseMetricStore) SaveMonitorMetric(ctx context.Context, metric *models.MonitorMetric) error {
+	return s.insert(ctx, "monitor_metrics", metric)
+}
+
+// SaveMetricBatch 批量写入一批指标，每种指标一次请求
+func (s *ClickHouseMetricStore) SaveMetricBatch(ctx context.Context, batch *MetricBatch) error {
+	return errors.Join(
+		clickHouseInsert(ctx, s, "cpu_metrics", batch.CPU),
+		clickHouseInsert(ctx, s, "cpu_core_metrics", batch.CPUCore),
+		clickHouseInsert(ctx, s, "cpu_times_metrics", batch.CPUTimes),
+		clickHouseInsert(ctx, s, "pressure_metrics", batch.Pressure),
+		clickHouseInsert(ctx, s, "memory_metrics", batch.Memory),
+		clickHouseInsert(ctx, s, "disk_metrics", batch.Disk),
+		clickHouseInsert(ctx, s, "network_metrics", batch.Network),
+		clickHouseInsert(ctx, s, "network_connection_metrics", batch.NetworkConnection),
+		clickHouseInsert(ctx, s, "disk_io_metrics", batch.DiskIO),
+		clickHouseInsert(ctx, s, "gpu_metrics", batch.GPU),
+		clickHouseInsert(ctx, s, "temperature_metrics", batch.Temperature),
+		clickHouseInsert(ctx, s, "ping_metrics", batch.Ping),
+		clickHouseInsert(ctx, s, "monitor_metrics", batch.Monitor),
+	)
+}
+
+// clickHouseInsert 将同一张表的多行合并为一次写入
+func clickHouseInsert[T any](ctx context.Context, s *ClickHouseMetricStore, table string, rows []T) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	values := make([]any, len(rows))
+	for i := range rows {
+		values[i] = &rows[i]
+	}
+	return s.insert(ctx, table, values...)
+}
+
+// 以下查询先在子查询中按时间范围过滤，外层再以 timestamp 作为 bucket 别名聚合，
+// 避免 ClickHouse 中同名别名覆盖 WHERE 条件里的原始列
+
+// GetCPUMetrics 获取聚合后的CPU指标
+func (s *ClickHouseMetricStore) GetCPUMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedCPUMetric, error) {
+	return clickHouseSelect[AggregatedCPUMetric](ctx, s, `
+		SELECT
+			intDiv(timestamp, {interval:Int64}) * {interval:Int64} AS timestamp,
+			max(usagePercent) AS maxUsage,
+			avg(usagePercent) AS avgUsage,
+			min(usagePercent) AS minUsage,
+			max(logicalCores) AS logicalCores
+		FROM (
+			SELECT * FROM cpu_metrics
+			WHERE agentId = {agentId:String} AND timestamp >= {start:Int64} AND timestamp <= {end:Int64}
+		)
+		GROUP BY timestamp
+		ORDER BY timestamp ASC
+	`, rangeParams(agentID, start, end, interval))
+}
+
+// GetCPUCoreMetrics 获取聚合后的每核CPU指标
+func (s *ClickHouseMetricStore) GetCPUCoreMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedCPUCoreMetric, error) {
+	return clickHouseSelect[AggregatedCPUCoreMetric](ctx, s, `
+		SELECT
+			intDiv(timestamp, {interval:Int64}) * {interval:Int64} AS timestamp,
+			core,
+			max(usagePercent) AS maxUsage,
+			avg(usagePercent) AS avgUsage
+		FROM (
+			SELECT * FROM cpu_core_metrics
+			WHERE agentId = {agentId:String} AND timestamp >= {start:Int64} AND timestamp <= {end:Int64}
+		)
+		GROUP BY timestamp, core
+		ORDER BY timestamp ASC, core
+	`, rangeParams(agentID, start, end, interval))
+}
+
+// GetCPUTimesMetrics 获取聚合后的CPU时间分布指标
+func (s *ClickHouseMetricStore) GetCPUTimesMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedCPUTimesMetric, error) {
+	return clickHouseSelect[AggregatedCPUTimesMetric](ctx, s, `
+		SELECT
+			intDiv(timestamp, {interval:Int64}) * {interval:Int64} AS timestamp,
+			avg(userPercent) AS avgUser,
+			avg(systemPercent) AS avgSystem,
+			avg(iowaitPercent) AS avgIowait,
+			avg(stealPercent) AS avgSteal,
+			max(stealPercent) AS maxSteal,
+			avg(idlePercent) AS avgIdle
+		FROM (
+			SELECT * FROM cpu_times_metrics
+			WHERE agentId = {agentId:String} AND timestamp >= {start:Int64} AND timestamp <= {end:Int64}
+		)
+		GROUP BY timestamp
+		ORDER BY timestamp ASC
+	`, rangeParams(agentID, start, end, interval))
+}
+
+// GetPressureMetrics 获取聚合后的 PSI 压力指标
+func (s *ClickHouseMetricStore) GetPressureMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedPressureMetric, error) {
+	return clickHouseSelect[AggregatedPressureMetric](ctx, s, `
+		SELECT
+			intDiv(timestamp, {interval:Int64}) * {interval:Int64} AS timestamp,
+			resource,
+			max(somePercent) AS maxSome,
+			avg(somePercent) AS avgSome,
+			max(fullPercent) AS maxFull,
+			avg(fullPercent) AS avgFull
+		FROM (
+			SELECT * FROM pressure_metrics
+			WHERE agentId = {agentId:String} AND timestamp >= {start:Int64} AND timestamp <= {end:Int64}
+		)
+		GROUP BY timestamp, resource
+		ORDER BY timestamp ASC, resource
+	`, rangeParams(agentID, start, end, interval))
+}
+
+// GetMemoryMetrics 获取聚合后的内存指标
+func (s *ClickHouseMetricStore) GetMemoryMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedMemoryMetric, error) {
+	return clickHouseSelect[AggregatedMemoryMetric](ctx, s, `
+		SELECT
+			intDiv(timestamp, {interval:Int64}) * {interval:Int64} AS timestamp,
+			max(usagePercent) AS maxUsage,
+			avg(usagePercent) AS avgUsage,
+			min(usagePercent) AS minUsage,
+			max(total) AS total
+		FROM (
+			SELECT * FROM memory_metrics
+			WHERE agentId = {agentId:String} AND timestamp >= {start:Int64} AND timestamp <= {end:Int64}
+		)
+		GROUP BY timestamp
+		ORDER BY timestamp ASC
+	`, rangeParams(agentID, start, end, interval))
+}
+
+// GetDiskMetrics 获取聚合后的磁盘指标（查询 mountPoint 为空的总和记录）
+func (s *ClickHouseMetricStore) GetDiskMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedDiskMetric, error) {
+	return clickHouseSelect[AggregatedDiskMetric](ctx, s, `
+		SELECT
+			intDiv(timestamp, {interval:Int64}) * {interval:Int64} AS timestamp,
+			mountPoint,
+			max(usagePercent) AS maxUsage,
+			avg(usagePercent) AS avgUsage,
+			min(usagePercent) AS minUsage,
+			max(total) AS total
+		FROM (
+			SELECT * FROM disk_metrics
+			WHERE agentId = {agentId:String} AND timestamp >= {start:Int64} AND timestamp <= {end:Int64} AND mountPoint = ''
+		)
+		GROUP BY timestamp, mountPoint
+		ORDER BY timestamp ASC
+	`, rangeParams(agentID, start, end, interval))
+}
+
+// GetNetworkMetrics 获取聚合后的网络指标，interfaceName 为空时查询总和记录
+func (s *ClickHouseMetricStore) GetNetworkMetrics(ctx context.Context, agentID string, start, end int64, interval int, interfaceName string) ([]AggregatedNetworkMetric, error) {
+	params := rangeParams(agentID, start, end, interval)
+	params["interface"] = interfaceName
+	return clickHouseSelect[AggregatedNetworkMetric](ctx, s, `
+		SELECT
+			intDiv(timestamp, {interval:Int64}) * {interval:Int64} AS timestamp,
+			interface,
+			max(bytesSentRate) AS maxSentRate,
+			max(bytesRecvRate) AS maxRecvRate,
+			avg(bytesSentRate) AS avgSentRate,
+			avg(bytesRecvRate) AS avgRecvRate
+		FROM (
+			SELECT * FROM network_metrics
+			WHERE agentId = {agentId:String} AND timestamp >= {start:Int64} AND timestamp <= {end:Int64} AND interface = {interface:String}
+		)
+		GROUP BY timestamp, interface
+		ORDER BY timestamp ASC
+	`, params)
+}
+
+// GetNetworkConnectionMetrics 获取聚合后的网络连接统计指标
+func (s *ClickHouseMetricStore) GetNetworkConnectionMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedNetworkConnectionMetric, error) {
+	return clickHouseSelect[AggregatedNetworkConnectionMetric](ctx, s, `
+		SELECT
+			intDiv(timestamp, {interval:Int64}) * {interval:Int64} AS timestamp,
+			max(established) AS maxEstablished,
+			max(synSent) AS maxSynSent,
+			max(synRecv) AS maxSynRecv,
+			max(finWait1) AS maxFinWait1,
+			max(finWait2) AS maxFinWait2,
+			max(timeWait) AS maxTimeWait,
+			max(close) AS maxClose,
+			max(closeWait) AS maxCloseWait,
+			max(lastAck) AS maxLastAck,
+			max(listen) AS maxListen,
+			max(closing) AS maxClosing,
+			max(total) AS maxTotal
+		FROM (
+			SELECT * FROM network_connection_metrics
+			WHERE agentId = {agentId:String} AND timestamp >= {start:Int64} AND timestamp <= {end:Int64}
+		)
+		GROUP BY timestamp
+		ORDER BY timestamp ASC
+	`, rangeParams(agentID, start, end, interval))
+}
+
+// GetDiskIOMetrics 获取聚合后的磁盘IO指标（已在存储时合并所有磁盘）
+func (s *ClickHouseMetricStore) GetDiskIOMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedDiskIOMetric, error) {
+	return clickHouseSelect[AggregatedDiskIOMetric](ctx, s, `
+		SELECT
+			intDiv(timestamp, {interval:Int64}) * {interval:Int64} AS timestamp,
+			max(readBytesRate) AS maxReadRate,
+			max(writeBytesRate) AS maxWriteRate,
+			max(readBytes) AS totalReadBytes,
+			max(writeBytes) AS totalWriteBytes,
+			max(iopsInProgress) AS maxIopsInProgress
+		FROM (
+			SELECT * FROM disk_io_metrics
+			WHERE agentId = {agentId:String} AND timestamp >= {start:Int64} AND timestamp <= {end:Int64}
+		)
+		GROUP BY timestamp
+		ORDER BY timestamp ASC
+	`, rangeParams(agentID, start, end, interval))
+}
+
+// GetGPUMetrics 获取聚合后的GPU指标
+func (s *ClickHouseMetricStore) GetGPUMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedGPUMetric, error) {
+	return clickHouseSelect[AggregatedGPUMetric](ctx, s, `
+		SELECT
+			intDiv(timestamp, {interval:Int64}) * {interval:Int64} AS timestamp,
+			max(utilization) AS maxUtilization,
+			max(memoryUsed) AS maxMemoryUsed,
+			max(temperature) AS maxTemperature,
+			max(powerDraw) AS maxPowerDraw
+		FROM (
+			SELECT * FROM gpu_metrics
+			WHERE agentId = {agentId:String} AND timestamp >= {start:Int64} AND timestamp <= {end:Int64}
+		)
+		GROUP BY timestamp
+		ORDER BY timestamp ASC
+	`, rangeParams(agentID, start, end, interval))
+}
+
+// GetTemperatureMetrics 获取聚合后的温度指标
+func (s *ClickHouseMetricStore) GetTemperatureMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedTemperatureMetric, error) {
+	return clickHouseSelect[AggregatedTemperatureMetric](ctx, s, `
+		SELECT
+			intDiv(timestamp, {interval:Int64}) * {interval:Int64} AS timestamp,
+			sensorKey,
+			sensorLabel,
+			max(temperature) AS maxTemperature
+		FROM (
+			SELECT * FROM temperature_metrics
+			WHERE agentId = {agentId:String} AND timestamp >= {start:Int64} AND timestamp <= {end:Int64}
+		)
+		GROUP BY timestamp, sensorKey, sensorLabel
+		ORDER BY timestamp ASC, sensorKey
+	`, rangeParams(agentID, start, end, interval))
+}
+
+// GetPingMetrics 获取聚合后的 Ping 指标，延迟只统计未完全丢包的检测
+func (s *ClickHouseMetricStore) GetPingMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedPingMetric, error) {
+	return clickHouseSelect[AggregatedPingMetric](ctx, s, `
+		SELECT
+			intDiv(ts, {interval:Int64}) * {interval:Int64} AS timestamp,
+			targetId,
+			any(dst) AS target,
+			if(countIf(loss < 100) = 0, 0, minIf(minRtt, loss < 100)) AS minLatency,
+			if(countIf(loss < 100) = 0, 0, avgIf(avgRtt, loss < 100)) AS avgLatency,
+			max(maxRtt) AS maxLatency,
+			avg(loss) AS packetLoss
+		FROM (
+			SELECT
+				targetId,
+				target AS dst,
+				minLatency AS minRtt,
+				avgLatency AS avgRtt,
+				maxLatency AS maxRtt,
+				packetLoss AS loss,
+				timestamp AS ts
+			FROM ping_metrics
+			WHERE agentId = {agentId:String} AND timestamp >= {start:Int64} AND timestamp <= {end:Int64}
+		)
+		GROUP BY timestamp, targetId
+		ORDER BY timestamp ASC, targetId
+	`, rangeParams(agentID, start, end, interval))
+}
+
+// GetAvailableNetworkInterfaces 获取探针的可用网卡列表（不包括空白的总和记录）
+func (s *ClickHouseMetricStore) GetAvailableNetworkInterfaces(ctx context.Context, agentID string) ([]string, error) {
+	rows, err := clickHouseSelect[struct {
+		Interface string `json:"interface"`
+	}](ctx, s, `
+		SELECT DISTINCT interface
+		FROM network_metrics
+		WHERE agentId = {agentId:String} AND interface != ''
+		ORDER BY interface
+	`, map[string]any{"agentId": agentID})
+	if err != nil {
+		return nil, err
+	}
+
+	interfaces := make([]string, 0, len(rows))
+	for _, row := range rows {
+		interfaces = append(interfaces, row.Interface)
+	}
+	return interfaces, nil
+}
+
+// GetMonitorMetrics 获取监控指标列表，monitorID 为空时返回探针的所有监控项
+func (s *ClickHouseMetricStore) GetMonitorMetrics(ctx context.Context, agentID, monitorID string, start, end int64) ([]models.MonitorMetric, error) {
+	query := `
+		SELECT * FROM monitor_metrics
+		WHERE agentId = {agentId:String} AND timestamp >= {start:Int64} AND timestamp <= {end:Int64}`
+	params := map[string]any{"agentId": agentID, "start": start, "end": end}
+	if monitorID != "" {
+		query += " AND monitorId = {monitorId:String}"
+		params["monitorId"] = monitorID
+	}
+	return clickHouseSelect[models.MonitorMetric](ctx, s, query+"\nORDER BY timestamp ASC", params)
+}
+
+// GetMonitorMetricsByName 获取指定监控项的历史数据
+func (s *ClickHouseMetricStore) GetMonitorMetricsByName(ctx context.Context, agentID, monitorID string, start, end int64, limit int) ([]models.MonitorMetric, error) {
+	query := `
+		SELECT * FROM monitor_metrics
+		WHERE agentId = {agentId:String} AND monitorId = {monitorId:String} AND timestamp >= {start:Int64} AND timestamp <= {end:Int64}
+		ORDER BY timestamp DESC`
+	params := map[string]any{"agentId": agentID, "monitorId": monitorID, "start": start, "end": end}
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+	return clickHouseSelect[models.MonitorMetric](ctx, s, query, params)
+}
+
+// monitorMetricWhere 按过滤条件构建原始检测记录的查询条件
+func monitorMetricWhere(filter MonitorMetricFilter) (string, map[string]any) {
+	where := "monitorId = {monitorId:String} AND timestamp >= {start:Int64} AND timestamp <= {end:Int64}"
+	params := map[string]any{"monitorId": filter.MonitorID, "start": filter.Start, "end": filter.End}
+	if filter.AgentID != "" {
+		where += " AND agentId = {agentId:String}"
+		params["agentId"] = filter.AgentID
+	}
+	if filter.Status != "" {
+		where += " AND status = {status:String}"
+		params["status"] = filter.Status
+	}
+	return where, params
+}
+
+// PageMonitorMetrics 分页查询监控项的原始检测记录，按时间倒序
+func (s *ClickHouseMetricStore) PageMonitorMetrics(ctx context.Context, filter MonitorMetricFilter, offset, limit int) ([]models.MonitorMetric, int64, error) {
+	where, params := monitorMetricWhere(filter)
+	counts, err := clickHouseSelect[struct {
+		Total int64 `json:"total"`
+	}](ctx, s, "SELECT count() AS total FROM monitor_metrics WHERE "+where, params)
+	if err != nil {
+		return nil, 0, err
+	}
+	var total int64
+	if len(counts) > 0 {
+		total = counts[0].Total
+	}
+	metrics, err := clickHouseSelect[models.MonitorMetric](ctx, s, fmt.Sprintf(`
+		SELECT * FROM monitor_metrics
+		WHERE %s
+		ORDER BY timestamp DESC, agentId
+		LIMIT %d OFFSET %d`, where, limit, offset), params)
+	return metrics, total, err
+}
+
+// ListMonitorMetrics 查询监控项在时间范围内的全部原始检测记录，按时间正序
+func (s *ClickHouseMetricStore) ListMonitorMetrics(ctx context.Context, filter MonitorMetricFilter) ([]models.MonitorMetric, error) {
+	where, params := monitorMetricWhere(filter)
+	return clickHouseSelect[models.MonitorMetric](ctx, s, "SELECT * FROM monitor_metrics\nWHERE "+where+"\nORDER BY timestamp ASC, agentId", params)
+}
+
+// GetAggregatedMonitorMetrics 获取聚合后的监控指标（按探针和时间间隔聚合）
+func (s *ClickHouseMetricStore) GetAggregatedMonitorMetrics(ctx context.Context, monitorID string, start, end int64, interval int) ([]AggregatedMonitorMetric, error) {
+	return clickHouseSelect[AggregatedMonitorMetric](ctx, s, `
+		SELECT
+			intDiv(ts, {interval:Int64}) * {interval:Int64} AS timestamp,
+			agentId,
+			sum(responseTime * checks) / sum(checks) AS avgResponse,
+			max(responseTime) AS maxResponse,
+			min(responseTime) AS minResponse,
+			sumIf(checks, status = 'up') AS successCount,
+			sum(checks) AS totalCount,
+			successCount / totalCount * 100 AS successRate,
+			argMax(status, ts) AS lastStatus,
+			argMax(error, ts) AS lastErrorMsg
+		FROM (
+			SELECT agentId, responseTime, checks, status, error, timestamp AS ts
+			FROM monitor_metrics
+			WHERE monitorId = {monitorId:String} AND timestamp >= {start:Int64} AND timestamp <= {end:Int64}
+		)
+		GROUP BY timestamp, agentId
+		ORDER BY timestamp ASC, agentId
+	`, map[string]any{
+		"monitorId": monitorID,
+		"start":     start,
+		"end":       end,
+		"interval":  int64(interval) * 1000,
+	})
+}
+
+// GetLatestMonitorMetricsByType 获取指定类型的最新监控指标（每个监控项的最新一条）
+func (s *ClickHouseMetricStore) GetLatestMonitorMetricsByType(ctx context.Context, monitorType string) ([]*models.MonitorMetric, error) {
+	return clickHouseSelect[*models.MonitorMetric](ctx, s, `
+		SELECT * FROM monitor_metrics
+		WHERE type = {type:String}
+		ORDER BY monitorId, timestamp DESC
+		LIMIT 1 BY monitorId
+	`, map[string]any{"type": monitorType})
+}
+
+// GetAllLatestMonitorMetrics 获取所有最新的监控指标（每个监控项的最新一条）
+func (s *ClickHouseMetricStore) GetAllLatestMonitorMetrics(ctx context.Context) ([]*models.MonitorMetric, error) {
+	return clickHouseSelect[*models.MonitorMetric](ctx, s, `
+		SELECT * FROM monitor_metrics
+		ORDER BY monitorId, timestamp DESC
+		LIMIT 1 BY monitorId
+	`, nil)
+}
+
+// DeleteOldMetrics 删除指定时间之前的所有指标数据，ClickHouse 以异步 mutation 执行，整分区过期时直接丢弃
+func (s *ClickHouseMetricStore) DeleteOldMetrics(ctx context.Context, beforeTimestamp int64) error {
+	for _, table := range clickHouseTimeSeriesTables {
+		if err := s.exec(ctx, "ALTER TABLE "+table+" DELETE WHERE timestamp < {before:Int64}", map[string]any{
+			"before": beforeTimestamp,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteAgentMetrics 删除指定探针的所有指标数据
+func (s *ClickHouseMetricStore) DeleteAgentMetrics(ctx context.Context, agentID string) error {
+	tables := append([]string{"host_metrics"}, clickHouseTimeSeriesTables...)
+	for _, table := range tables {
+		if err := s.exec(ctx, "ALTER TABLE "+table+" DELETE WHERE agentId = {agentId:String}", map[string]any{
+			"agentId": agentID,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteMonitorMetrics 删除指定监控任务的所有指标数据
+func (s *ClickHouseMetricStore) DeleteMonitorMetrics(ctx context.Context, monitorID string) error {
+	return s.exec(ctx, "ALTER TABLE monitor_metrics DELETE WHERE monitorId = {monitorId:String}", map[string]any{
+		"monitorId": monitorID,
+	})
+}
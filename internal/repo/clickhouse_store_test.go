@@ -0,0 +1,109 @@
+package repo
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+func TestClickHouseParam(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{value: "agent-1", want: "agent-1"},
+		{value: `C:\tmp`, want: `C:\\tmp`},
+		{value: "a\tb\nc\rd", want: `a\tb\nc\rd`},
+		{value: "a\x00b\bc\fd", want: `a\0b\bc\fd`},
+		{value: "it's", want: "it's"},
+		{value: int64(1700000000000), want: "1700000000000"},
+	}
+	for _, tt := range tests {
+		if got := clickHouseParam(tt.value); got != tt.want {
+			t.Errorf("clickHouseParam(%q) = %q, 期望 %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+var (
+	clickHouseCreateTable = regexp.MustCompile(`(?s)CREATE TABLE IF NOT EXISTS (\w+) \((.*)\) ENGINE`)
+	clickHouseAddColumn   = regexp.MustCompile(`ALTER TABLE (\w+) ADD COLUMN IF NOT EXISTS (\w+)`)
+)
+
+// clickHouseColumns 从建表和迁移语句中解析每张表的列名
+func clickHouseColumns(t *testing.T) map[string][]string {
+	columns := make(map[string][]string)
+	for _, stmt := range clickHouseTables {
+		if m := clickHouseAddColumn.FindStringSubmatch(stmt); m != nil {
+			columns[m[1]] = append(columns[m[1]], m[2])
+			continue
+		}
+		m := clickHouseCreateTable.FindStringSubmatch(stmt)
+		if m == nil {
+			t.Fatalf("无法解析语句: %s", stmt)
+		}
+		for _, line := range strings.Split(m[2], "\n") {
+			fields := strings.Fields(line)
+			if len(fields) == 0 {
+				continue
+			}
+			columns[m[1]] = append(columns[m[1]], strings.Trim(fields[0], "`"))
+		}
+	}
+	return columns
+}
+
+// TestClickHouseInsertColumns 写入时开启了 input_format_skip_unknown_fields，
+// JSON 字段名与列名不一致时 ClickHouse 会静默写入默认值，这里检查每张表的列都能从模型的 JSON 中取到
+func TestClickHouseInsertColumns(t *testing.T) {
+	rows := map[string]any{
+		"cpu_metrics":                &models.CPUMetric{},
+		"cpu_core_metrics":           &models.CPUCoreMetric{},
+		"cpu_times_metrics":          &models.CPUTimesMetric{},
+		"pressure_metrics":           &models.PressureMetric{},
+		"memory_metrics":             &models.MemoryMetric{},
+		"disk_metrics":               &models.DiskMetric{},
+		"network_metrics":            &models.NetworkMetric{},
+		"network_connection_metrics": &models.NetworkConnectionMetric{},
+		"disk_io_metrics":            &models.DiskIOMetric{},
+		"gpu_metrics":                &models.GPUMetric{},
+		"temperature_metrics":        &models.TemperatureMetric{},
+		"host_metrics":               &models.HostMetric{},
+		"ping_metrics":               &models.PingMetric{},
+		"monitor_metrics":            &models.MonitorMetric{},
+	}
+
+	columns := clickHouseColumns(t)
+	for table, row := range rows {
+		buf, err := encodeJSONEachRow(row, row)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("%s 应编码为 2 行, 实际 %d 行", table, len(lines))
+		}
+
+		var decoded map[string]any
+		if err := json.Unmarshal([]byte(lines[0]), &decoded); err != nil {
+			t.Fatalf("%s 编码结果不是合法的 JSON: %v", table, err)
+		}
+		if len(columns[table]) == 0 {
+			t.Fatalf("未找到 %s 的建表语句", table)
+		}
+		var missing []string
+		for _, column := range columns[table] {
+			if _, ok := decoded[column]; !ok {
+				missing = append(missing, column)
+			}
+		}
+		sort.Strings(missing)
+		if len(missing) > 0 {
+			t.Errorf("%s 的列 %v 不在模型的 JSON 字段中", table, missing)
+		}
+	}
+}
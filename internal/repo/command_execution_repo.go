@@ -0,0 +1,55 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/go-orz/orz"
+	"gorm.io/gorm"
+)
+
+type CommandExecutionRepo struct {
+	orz.Repository[models.CommandExecution, int64]
+	db *gorm.DB
+}
+
+func NewCommandExecutionRepo(db *gorm.DB) *CommandExecutionRepo {
+	return &CommandExecutionRepo{
+		Repository: orz.NewRepository[models.CommandExecution, int64](db),
+		db:         db,
+	}
+}
+
+// FindByCommandID 根据指令ID获取执行记录
+func (r *CommandExecutionRepo) FindByCommandID(ctx context.Context, agentID, commandID string) (*models.CommandExecution, error) {
+	var record models.CommandExecution
+	err := r.db.WithContext(ctx).
+		Where("agent_id = ? AND command_id = ?", agentID, commandID).
+		First(&record).Error
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Finish 更新仍在执行中的记录为结束状态，返回是否更新成功；已结束（如已超时）的记录不再更新
+func (r *CommandExecutionRepo) Finish(ctx context.Context, id int64, values map[string]interface{}) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.CommandExecution{}).
+		Where("id = ? AND status = ?", id, models.CommandStatusRunning).
+		Updates(values)
+	return result.RowsAffected > 0, result.Error
+}
+
+// CloseRunning 将仍在执行中的记录标记为失败，用于服务重启后清理无法再收到结果的记录
+func (r *CommandExecutionRepo) CloseRunning(ctx context.Context, reason string, finishedAt int64) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.CommandExecution{}).
+		Where("status = ?", models.CommandStatusRunning).
+		Updates(map[string]interface{}{
+			"status":      models.CommandStatusError,
+			"error":       reason,
+			"finished_at": finishedAt,
+		})
+	return result.RowsAffected, result.Error
+}
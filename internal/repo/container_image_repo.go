@@ -0,0 +1,63 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/go-orz/orz"
+	"gorm.io/gorm"
+)
+
+type ContainerImageRepo struct {
+	orz.Repository[models.ContainerImage, string]
+	db *gorm.DB
+}
+
+func NewContainerImageRepo(db *gorm.DB) *ContainerImageRepo {
+	return &ContainerImageRepo{
+		Repository: orz.NewRepository[models.ContainerImage, string](db),
+		db:         db,
+	}
+}
+
+// ReplaceByAgentID 使用最新上报的容器替换探针的全部容器镜像记录
+func (r *ContainerImageRepo) ReplaceByAgentID(ctx context.Context, agentID string, images []models.ContainerImage) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("agent_id = ?", agentID).Delete(&models.ContainerImage{}).Error; err != nil {
+			return err
+		}
+		if len(images) == 0 {
+			return nil
+		}
+		return tx.Create(&images).Error
+	})
+}
+
+// FindByAgentID 获取探针的容器镜像
+func (r *ContainerImageRepo) FindByAgentID(ctx context.Context, agentID string) ([]models.ContainerImage, error) {
+	var images []models.ContainerImage
+	err := r.db.WithContext(ctx).
+		Where("agent_id = ?", agentID).
+		Order("container_name").
+		Find(&images).Error
+	return images, err
+}
+
+// UpdateRegistryDigest 更新镜像在仓库中的摘要和漂移状态
+func (r *ContainerImageRepo) UpdateRegistryDigest(ctx context.Context, id, registryDigest string, drift bool, checkedAt int64) error {
+	return r.db.WithContext(ctx).
+		Model(&models.ContainerImage{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"registry_digest": registryDigest,
+			"drift":           drift,
+			"checked_at":      checkedAt,
+		}).Error
+}
+
+// DeleteByAgentID 删除探针的容器镜像记录
+func (r *ContainerImageRepo) DeleteByAgentID(ctx context.Context, agentID string) error {
+	return r.db.WithContext(ctx).
+		Where("agent_id = ?", agentID).
+		Delete(&models.ContainerImage{}).Error
+}
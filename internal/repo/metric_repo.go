@@ -2,14 +2,19 @@ package repo
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/dushixiang/pika/internal/models"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
+const metricInsertBatchSize = 500 // 批量写入时单条 INSERT 的最大行数
+
 type MetricRepo struct {
-	db *gorm.DB
+	db        *gorm.DB
+	timescale bool // 指标表已转换为 TimescaleDB hypertable
 }
 
 func NewMetricRepo(db *gorm.DB) *MetricRepo {
@@ -18,6 +23,42 @@ func NewMetricRepo(db *gorm.DB) *MetricRepo {
 	}
 }
 
+// SaveMetricBatch 批量写入一批指标，每种指标各自写入，单个表写入失败不影响其他表
+func (r *MetricRepo) SaveMetricBatch(ctx context.Context, batch *MetricBatch) error {
+	db := r.db.WithContext(ctx)
+	return errors.Join(
+		createInBatches(db, batch.CPU),
+		createInBatches(db, batch.CPUCore),
+		createInBatches(db, batch.CPUTimes),
+		createInBatches(db, batch.Pressure),
+		createInBatches(db, batch.Memory),
+		createInBatches(db, batch.Disk),
+		createInBatches(db, batch.Network),
+		createInBatches(db, batch.NetworkConnection),
+		createInBatches(db, batch.DiskIO),
+		createInBatches(db, batch.GPU),
+		createInBatches(db, batch.Temperature),
+		createInBatches(db, batch.Ping),
+		createInBatches(db, batch.Monitor),
+	)
+}
+
+// createInBatches 按 metricInsertBatchSize 拆分为多条 INSERT 写入
+func createInBatches[T any](db *gorm.DB, rows []T) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	return db.CreateInBatches(rows, metricInsertBatchSize).Error
+}
+
+// timeBucket 返回按 intervalMs 对齐 timestamp 的 SQL 表达式，启用 TimescaleDB 时使用 time_bucket
+func (r *MetricRepo) timeBucket(intervalMs int64) string {
+	if r.timescale {
+		return fmt.Sprintf("time_bucket(CAST(%d AS BIGINT), timestamp)", intervalMs)
+	}
+	return fmt.Sprintf("CAST(FLOOR(timestamp / %d) * %d AS BIGINT)", intervalMs, intervalMs)
+}
+
 // SaveCPUMetric 保存CPU指标
 func (r *MetricRepo) SaveCPUMetric(ctx context.Context, metric *models.CPUMetric) error {
 	return r.db.WithContext(ctx).Create(metric).Error
@@ -53,6 +94,11 @@ func (r *MetricRepo) SaveTemperatureMetric(ctx context.Context, metric *models.T
 	return r.db.WithContext(ctx).Create(metric).Error
 }
 
+// SavePingMetric 保存 Ping 指标
+func (r *MetricRepo) SavePingMetric(ctx context.Context, metric *models.PingMetric) error {
+	return r.db.WithContext(ctx).Create(metric).Error
+}
+
 // SaveHostMetric 保存主机信息指标（按 agent 覆盖，避免先删后插的空窗）
 func (r *MetricRepo) SaveHostMetric(ctx context.Context, metric *models.HostMetric) error {
 	return r.db.WithContext(ctx).
@@ -89,9 +135,10 @@ type AggregatedNetworkConnectionMetric struct {
 func (r *MetricRepo) GetNetworkConnectionMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedNetworkConnectionMetric, error) {
 	var metrics []AggregatedNetworkConnectionMetric
 
-	query := `
+	intervalMs := int64(interval * 1000)
+	query := fmt.Sprintf(`
 		SELECT
-			CAST(FLOOR(timestamp / ?) * ? AS BIGINT) as timestamp,
+			%s as timestamp,
 			MAX(established) as max_established,
 			MAX(syn_sent) as max_syn_sent,
 			MAX(syn_recv) as max_syn_recv,
@@ -108,36 +155,37 @@ func (r *MetricRepo) GetNetworkConnectionMetrics(ctx context.Context, agentID st
 		WHERE agent_id = ? AND timestamp >= ? AND timestamp <= ?
 		GROUP BY 1
 		ORDER BY timestamp ASC
-	`
+	`, r.timeBucket(intervalMs))
 
-	intervalMs := int64(interval * 1000)
 	err := r.db.WithContext(ctx).
-		Raw(query, intervalMs, intervalMs, agentID, start, end).
+		Raw(query, agentID, start, end).
 		Scan(&metrics).Error
 
 	return metrics, err
 }
 
 // DeleteOldMetrics 删除指定时间之前的所有指标数据
+// 启用 TimescaleDB 时直接删除过期的 chunk，未满一个 chunk 的数据会在 chunk 整体过期后删除
 func (r *MetricRepo) DeleteOldMetrics(ctx context.Context, beforeTimestamp int64) error {
+	if r.timescale {
+		for _, table := range timeSeriesModels {
+			name, err := tableName(r.db, table)
+			if err != nil {
+				return err
+			}
+			if err := r.db.WithContext(ctx).
+				Exec("SELECT drop_chunks(?, older_than => CAST(? AS BIGINT))", name, beforeTimestamp).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
 	// 批量大小
 	batchSize := 1000
 
-	// 定义要清理的表（Host 信息只保留最新的，不需要清理）
-	tables := []interface{}{
-		&models.CPUMetric{},
-		&models.MemoryMetric{},
-		&models.DiskMetric{},
-		&models.NetworkMetric{},
-		&models.NetworkConnectionMetric{},
-		&models.DiskIOMetric{},
-		&models.GPUMetric{},
-		&models.TemperatureMetric{},
-		&models.MonitorMetric{},
-	}
-
 	// 对每个表进行分批删除
-	for _, table := range tables {
+	for _, table := range timeSeriesModels {
 		for {
 			// 分批删除，避免长事务
 			result := r.db.WithContext(ctx).
@@ -159,10 +207,12 @@ func (r *MetricRepo) DeleteOldMetrics(ctx context.Context, beforeTimestamp int64
 	return nil
 }
 
-// AggregatedCPUMetric CPU聚合指标（使用最大值）
+// AggregatedCPUMetric CPU聚合指标（图表使用最大值）
 type AggregatedCPUMetric struct {
 	Timestamp    int64   `json:"timestamp"`
 	MaxUsage     float64 `json:"maxUsage"`
+	AvgUsage     float64 `json:"avgUsage"`
+	MinUsage     float64 `json:"minUsage"`
 	LogicalCores int     `json:"logicalCores"`
 }
 
@@ -171,29 +221,139 @@ type AggregatedCPUMetric struct {
 func (r *MetricRepo) GetCPUMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedCPUMetric, error) {
 	var metrics []AggregatedCPUMetric
 
-	query := `
+	intervalMs := int64(interval * 1000)
+	query := fmt.Sprintf(`
 		SELECT
-			CAST(FLOOR(timestamp / ?) * ? AS BIGINT) as timestamp,
+			%s as timestamp,
 			MAX(usage_percent) as max_usage,
+			AVG(usage_percent) as avg_usage,
+			MIN(usage_percent) as min_usage,
 			MAX(logical_cores) as logical_cores
 		FROM cpu_metrics
 		WHERE agent_id = ? AND timestamp >= ? AND timestamp <= ?
 		GROUP BY 1
 		ORDER BY timestamp ASC
-	`
+	`, r.timeBucket(intervalMs))
+
+	err := r.db.WithContext(ctx).
+		Raw(query, agentID, start, end).
+		Scan(&metrics).Error
+
+	return metrics, err
+}
+
+// AggregatedCPUCoreMetric 每核CPU聚合指标
+type AggregatedCPUCoreMetric struct {
+	Timestamp int64   `json:"timestamp"`
+	Core      int     `json:"core"`
+	MaxUsage  float64 `json:"maxUsage"`
+	AvgUsage  float64 `json:"avgUsage"`
+}
+
+// GetCPUCoreMetrics 获取聚合后的每核CPU指标
+func (r *MetricRepo) GetCPUCoreMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedCPUCoreMetric, error) {
+	var metrics []AggregatedCPUCoreMetric
 
 	intervalMs := int64(interval * 1000)
+	query := fmt.Sprintf(`
+		SELECT
+			%s as timestamp,
+			core,
+			MAX(usage_percent) as max_usage,
+			AVG(usage_percent) as avg_usage
+		FROM cpu_core_metrics
+		WHERE agent_id = ? AND timestamp >= ? AND timestamp <= ?
+		GROUP BY 1, core
+		ORDER BY timestamp ASC, core
+	`, r.timeBucket(intervalMs))
+
+	err := r.db.WithContext(ctx).
+		Raw(query, agentID, start, end).
+		Scan(&metrics).Error
+
+	return metrics, err
+}
+
+// AggregatedCPUTimesMetric CPU时间分布聚合指标
+type AggregatedCPUTimesMetric struct {
+	Timestamp int64   `json:"timestamp"`
+	AvgUser   float64 `json:"avgUser"`
+	AvgSystem float64 `json:"avgSystem"`
+	AvgIOWait float64 `json:"avgIowait"`
+	AvgSteal  float64 `json:"avgSteal"`
+	MaxSteal  float64 `json:"maxSteal"`
+	AvgIdle   float64 `json:"avgIdle"`
+}
+
+// GetCPUTimesMetrics 获取聚合后的CPU时间分布指标
+func (r *MetricRepo) GetCPUTimesMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedCPUTimesMetric, error) {
+	var metrics []AggregatedCPUTimesMetric
+
+	intervalMs := int64(interval * 1000)
+	query := fmt.Sprintf(`
+		SELECT
+			%s as timestamp,
+			AVG(user_percent) as avg_user,
+			AVG(system_percent) as avg_system,
+			AVG(io_wait_percent) as avg_io_wait,
+			AVG(steal_percent) as avg_steal,
+			MAX(steal_percent) as max_steal,
+			AVG(idle_percent) as avg_idle
+		FROM cpu_times_metrics
+		WHERE agent_id = ? AND timestamp >= ? AND timestamp <= ?
+		GROUP BY 1
+		ORDER BY timestamp ASC
+	`, r.timeBucket(intervalMs))
+
+	err := r.db.WithContext(ctx).
+		Raw(query, agentID, start, end).
+		Scan(&metrics).Error
+
+	return metrics, err
+}
+
+// AggregatedPressureMetric PSI 压力聚合指标
+type AggregatedPressureMetric struct {
+	Timestamp int64   `json:"timestamp"`
+	Resource  string  `json:"resource"`
+	MaxSome   float64 `json:"maxSome"`
+	AvgSome   float64 `json:"avgSome"`
+	MaxFull   float64 `json:"maxFull"`
+	AvgFull   float64 `json:"avgFull"`
+}
+
+// GetPressureMetrics 获取聚合后的 PSI 压力指标
+func (r *MetricRepo) GetPressureMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedPressureMetric, error) {
+	var metrics []AggregatedPressureMetric
+
+	intervalMs := int64(interval * 1000)
+	query := fmt.Sprintf(`
+		SELECT
+			%s as timestamp,
+			resource,
+			MAX(some_percent) as max_some,
+			AVG(some_percent) as avg_some,
+			MAX(full_percent) as max_full,
+			AVG(full_percent) as avg_full
+		FROM pressure_metrics
+		WHERE agent_id = ? AND timestamp >= ? AND timestamp <= ?
+		GROUP BY 1, resource
+		ORDER BY timestamp ASC, resource
+	`, r.timeBucket(intervalMs))
+
 	err := r.db.WithContext(ctx).
-		Raw(query, intervalMs, intervalMs, agentID, start, end).
+		Raw(query, agentID, start, end).
 		Scan(&metrics).Error
 
 	return metrics, err
 }
 
-// AggregatedMemoryMetric 内存聚合指标（使用最大值）
+// AggregatedMemoryMetric 内存聚合指标（图表使用最大值）
 type AggregatedMemoryMetric struct {
 	Timestamp int64   `json:"timestamp"`
 	MaxUsage  float64 `json:"maxUsage"`
+	AvgUsage  float64 `json:"avgUsage"`
+	MinUsage  float64 `json:"minUsage"`
 	Total     uint64  `json:"total"`
 }
 
@@ -201,30 +361,34 @@ type AggregatedMemoryMetric struct {
 func (r *MetricRepo) GetMemoryMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedMemoryMetric, error) {
 	var metrics []AggregatedMemoryMetric
 
-	query := `
+	intervalMs := int64(interval * 1000)
+	query := fmt.Sprintf(`
 		SELECT
-			CAST(FLOOR(timestamp / ?) * ? AS BIGINT) as timestamp,
+			%s as timestamp,
 			MAX(usage_percent) as max_usage,
+			AVG(usage_percent) as avg_usage,
+			MIN(usage_percent) as min_usage,
 			MAX(total) as total
 		FROM memory_metrics
 		WHERE agent_id = ? AND timestamp >= ? AND timestamp <= ?
 		GROUP BY 1
 		ORDER BY timestamp ASC
-	`
+	`, r.timeBucket(intervalMs))
 
-	intervalMs := int64(interval * 1000)
 	err := r.db.WithContext(ctx).
-		Raw(query, intervalMs, intervalMs, agentID, start, end).
+		Raw(query, agentID, start, end).
 		Scan(&metrics).Error
 
 	return metrics, err
 }
 
-// AggregatedDiskMetric 磁盘聚合指标（使用最大值）
+// AggregatedDiskMetric 磁盘聚合指标（图表使用最大值）
 type AggregatedDiskMetric struct {
 	Timestamp  int64   `json:"timestamp"`
 	MountPoint string  `json:"mountPoint"`
 	MaxUsage   float64 `json:"maxUsage"`
+	AvgUsage   float64 `json:"avgUsage"`
+	MinUsage   float64 `json:"minUsage"`
 	Total      uint64  `json:"total"`
 }
 
@@ -233,21 +397,23 @@ type AggregatedDiskMetric struct {
 func (r *MetricRepo) GetDiskMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedDiskMetric, error) {
 	var metrics []AggregatedDiskMetric
 
-	query := `
+	intervalMs := int64(interval * 1000)
+	query := fmt.Sprintf(`
 		SELECT
-			CAST(FLOOR(timestamp / ?) * ? AS BIGINT) as timestamp,
+			%s as timestamp,
 			mount_point,
 			MAX(usage_percent) as max_usage,
+			AVG(usage_percent) as avg_usage,
+			MIN(usage_percent) as min_usage,
 			MAX(total) as total
 		FROM disk_metrics
 		WHERE agent_id = ? AND timestamp >= ? AND timestamp <= ? AND mount_point = ?
 		GROUP BY 1, mount_point
 		ORDER BY timestamp ASC
-	`
+	`, r.timeBucket(intervalMs))
 
-	intervalMs := int64(interval * 1000)
 	err := r.db.WithContext(ctx).
-		Raw(query, intervalMs, intervalMs, agentID, start, end, ""). // 空字符串查询总和记录
+		Raw(query, agentID, start, end, ""). // 空字符串查询总和记录
 		Scan(&metrics).Error
 
 	return metrics, err
@@ -259,6 +425,8 @@ type AggregatedNetworkMetric struct {
 	Interface   string  `json:"interface"`
 	MaxSentRate float64 `json:"maxSentRate"`
 	MaxRecvRate float64 `json:"maxRecvRate"`
+	AvgSentRate float64 `json:"avgSentRate"`
+	AvgRecvRate float64 `json:"avgRecvRate"`
 }
 
 // AggregatedNetworkMetricByInterface 按网卡接口分组的网络聚合指标
@@ -278,20 +446,22 @@ func (r *MetricRepo) GetNetworkMetrics(ctx context.Context, agentID string, star
 
 	// 不管是否指定网卡，查询逻辑都一样：直接查询对应 interface 的数据
 	// interfaceName 为空字符串时，会查询到预先保存的总和数据
-	query := `
+	query := fmt.Sprintf(`
 		SELECT
-			CAST(FLOOR(timestamp / ?) * ? AS BIGINT) as timestamp,
+			%s as timestamp,
 			interface,
 			MAX(bytes_sent_rate) as max_sent_rate,
-			MAX(bytes_recv_rate) as max_recv_rate
+			MAX(bytes_recv_rate) as max_recv_rate,
+			AVG(bytes_sent_rate) as avg_sent_rate,
+			AVG(bytes_recv_rate) as avg_recv_rate
 		FROM network_metrics
 		WHERE agent_id = ? AND timestamp >= ? AND timestamp <= ? AND interface = ?
 		GROUP BY 1, interface
 		ORDER BY timestamp ASC
-	`
+	`, r.timeBucket(intervalMs))
 
 	err := r.db.WithContext(ctx).
-		Raw(query, intervalMs, intervalMs, agentID, start, end, interfaceName).
+		Raw(query, agentID, start, end, interfaceName).
 		Scan(&metrics).Error
 
 	return metrics, err
@@ -332,6 +502,41 @@ func (r *MetricRepo) GetMonitorMetricsByName(ctx context.Context, agentID, monit
 	return metrics, err
 }
 
+// monitorMetricQuery 按过滤条件构建原始检测记录的查询
+func (r *MetricRepo) monitorMetricQuery(ctx context.Context, filter MonitorMetricFilter) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&models.MonitorMetric{}).
+		Where("monitor_id = ? AND timestamp >= ? AND timestamp <= ?", filter.MonitorID, filter.Start, filter.End)
+	if filter.AgentID != "" {
+		query = query.Where("agent_id = ?", filter.AgentID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	return query
+}
+
+// PageMonitorMetrics 分页查询监控项的原始检测记录，按时间倒序
+func (r *MetricRepo) PageMonitorMetrics(ctx context.Context, filter MonitorMetricFilter, offset, limit int) ([]models.MonitorMetric, int64, error) {
+	var total int64
+	if err := r.monitorMetricQuery(ctx, filter).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var metrics []models.MonitorMetric
+	err := r.monitorMetricQuery(ctx, filter).
+		Order("timestamp DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&metrics).Error
+	return metrics, total, err
+}
+
+// ListMonitorMetrics 查询监控项在时间范围内的全部原始检测记录，按时间正序
+func (r *MetricRepo) ListMonitorMetrics(ctx context.Context, filter MonitorMetricFilter) ([]models.MonitorMetric, error) {
+	var metrics []models.MonitorMetric
+	err := r.monitorMetricQuery(ctx, filter).Order("timestamp ASC, id ASC").Find(&metrics).Error
+	return metrics, err
+}
+
 // AggregatedMonitorMetric 聚合的监控指标
 type AggregatedMonitorMetric struct {
 	Timestamp    int64   `json:"timestamp"`
@@ -350,38 +555,39 @@ type AggregatedMonitorMetric struct {
 func (r *MetricRepo) GetAggregatedMonitorMetrics(ctx context.Context, monitorID string, start, end int64, interval int) ([]AggregatedMonitorMetric, error) {
 	var metrics []AggregatedMonitorMetric
 
-	query := `
+	intervalMs := int64(interval * 1000)
+	query := fmt.Sprintf(`
 		WITH ranked_metrics AS (
 			SELECT
 				agent_id,
-				CAST(FLOOR(timestamp / ?) * ? AS BIGINT) as time_bucket,
+				%[1]s as time_bucket,
 				response_time,
+				checks,
 				status,
 				error,
 				timestamp,
-				ROW_NUMBER() OVER (PARTITION BY agent_id, CAST(FLOOR(timestamp / ?) * ? AS BIGINT) ORDER BY timestamp DESC) as rn
+				ROW_NUMBER() OVER (PARTITION BY agent_id, %[1]s ORDER BY timestamp DESC) as rn
 			FROM monitor_metrics
 			WHERE monitor_id = ? AND timestamp >= ? AND timestamp <= ?
 		)
 		SELECT
 			time_bucket as timestamp,
 			agent_id,
-			AVG(response_time) as avg_response,
+			CAST(SUM(response_time * checks) AS REAL) / CAST(SUM(checks) AS REAL) as avg_response,
 			MAX(response_time) as max_response,
 			MIN(response_time) as min_response,
-			SUM(CASE WHEN status = 'up' THEN 1 ELSE 0 END) as success_count,
-			COUNT(*) as total_count,
-			CAST(SUM(CASE WHEN status = 'up' THEN 1 ELSE 0 END) AS REAL) / CAST(COUNT(*) AS REAL) * 100 as success_rate,
+			SUM(CASE WHEN status = 'up' THEN checks ELSE 0 END) as success_count,
+			SUM(checks) as total_count,
+			CAST(SUM(CASE WHEN status = 'up' THEN checks ELSE 0 END) AS REAL) / CAST(SUM(checks) AS REAL) * 100 as success_rate,
 			MAX(CASE WHEN rn = 1 THEN status END) as last_status,
 			MAX(CASE WHEN rn = 1 THEN error END) as last_error_msg
 		FROM ranked_metrics
 		GROUP BY time_bucket, agent_id
 		ORDER BY timestamp ASC, agent_id
-	`
+	`, r.timeBucket(intervalMs))
 
-	intervalMs := int64(interval * 1000)
 	err := r.db.WithContext(ctx).
-		Raw(query, intervalMs, intervalMs, intervalMs, intervalMs, monitorID, start, end).
+		Raw(query, monitorID, start, end).
 		Scan(&metrics).Error
 
 	return metrics, err
@@ -401,9 +607,10 @@ type AggregatedDiskIOMetric struct {
 func (r *MetricRepo) GetDiskIOMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedDiskIOMetric, error) {
 	var metrics []AggregatedDiskIOMetric
 
-	query := `
+	intervalMs := int64(interval * 1000)
+	query := fmt.Sprintf(`
 		SELECT
-			CAST(FLOOR(timestamp / ?) * ? AS BIGINT) as timestamp,
+			%s as timestamp,
 			MAX(read_bytes_rate) as max_read_rate,
 			MAX(write_bytes_rate) as max_write_rate,
 			MAX(read_bytes) as total_read_bytes,
@@ -413,11 +620,10 @@ func (r *MetricRepo) GetDiskIOMetrics(ctx context.Context, agentID string, start
 		WHERE agent_id = ? AND timestamp >= ? AND timestamp <= ?
 		GROUP BY 1
 		ORDER BY timestamp ASC
-	`
+	`, r.timeBucket(intervalMs))
 
-	intervalMs := int64(interval * 1000)
 	err := r.db.WithContext(ctx).
-		Raw(query, intervalMs, intervalMs, agentID, start, end).
+		Raw(query, agentID, start, end).
 		Scan(&metrics).Error
 
 	return metrics, err
@@ -439,9 +645,10 @@ type AggregatedGPUMetric struct {
 func (r *MetricRepo) GetGPUMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedGPUMetric, error) {
 	var metrics []AggregatedGPUMetric
 
-	query := `
+	intervalMs := int64(interval * 1000)
+	query := fmt.Sprintf(`
 		SELECT
-			CAST(FLOOR(timestamp / ?) * ? AS BIGINT) as timestamp,
+			%s as timestamp,
 			MAX(utilization) as max_utilization,
 			MAX(memory_used) as max_memory_used,
 			MAX(temperature) as max_temperature,
@@ -450,11 +657,10 @@ func (r *MetricRepo) GetGPUMetrics(ctx context.Context, agentID string, start, e
 		WHERE agent_id = ? AND timestamp >= ? AND timestamp <= ?
 		GROUP BY 1
 		ORDER BY timestamp ASC
-	`
+	`, r.timeBucket(intervalMs))
 
-	intervalMs := int64(interval * 1000)
 	err := r.db.WithContext(ctx).
-		Raw(query, intervalMs, intervalMs, agentID, start, end).
+		Raw(query, agentID, start, end).
 		Scan(&metrics).Error
 
 	return metrics, err
@@ -472,9 +678,10 @@ type AggregatedTemperatureMetric struct {
 func (r *MetricRepo) GetTemperatureMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedTemperatureMetric, error) {
 	var metrics []AggregatedTemperatureMetric
 
-	query := `
+	intervalMs := int64(interval * 1000)
+	query := fmt.Sprintf(`
 		SELECT
-			CAST(FLOOR(timestamp / ?) * ? AS BIGINT) as timestamp,
+			%s as timestamp,
 			sensor_key,
 			sensor_label,
 			MAX(temperature) as max_temperature
@@ -482,11 +689,48 @@ func (r *MetricRepo) GetTemperatureMetrics(ctx context.Context, agentID string,
 		WHERE agent_id = ? AND timestamp >= ? AND timestamp <= ?
 		GROUP BY 1, sensor_key, sensor_label
 		ORDER BY timestamp ASC, sensor_key
-	`
+	`, r.timeBucket(intervalMs))
+
+	err := r.db.WithContext(ctx).
+		Raw(query, agentID, start, end).
+		Scan(&metrics).Error
+
+	return metrics, err
+}
+
+// AggregatedPingMetric Ping 聚合指标
+type AggregatedPingMetric struct {
+	Timestamp  int64   `json:"timestamp"`
+	TargetID   string  `json:"targetId"`
+	Target     string  `json:"target"`
+	MinLatency float64 `json:"minLatency"`
+	AvgLatency float64 `json:"avgLatency"`
+	MaxLatency float64 `json:"maxLatency"`
+	PacketLoss float64 `json:"packetLoss"`
+}
+
+// GetPingMetrics 获取聚合后的 Ping 指标，延迟只统计未完全丢包的检测
+func (r *MetricRepo) GetPingMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedPingMetric, error) {
+	var metrics []AggregatedPingMetric
 
 	intervalMs := int64(interval * 1000)
+	query := fmt.Sprintf(`
+		SELECT
+			%s as timestamp,
+			target_id,
+			MAX(target) as target,
+			COALESCE(MIN(CASE WHEN packet_loss < 100 THEN min_latency END), 0) as min_latency,
+			COALESCE(AVG(CASE WHEN packet_loss < 100 THEN avg_latency END), 0) as avg_latency,
+			MAX(max_latency) as max_latency,
+			AVG(packet_loss) as packet_loss
+		FROM ping_metrics
+		WHERE agent_id = ? AND timestamp >= ? AND timestamp <= ?
+		GROUP BY 1, target_id
+		ORDER BY timestamp ASC, target_id
+	`, r.timeBucket(intervalMs))
+
 	err := r.db.WithContext(ctx).
-		Raw(query, intervalMs, intervalMs, agentID, start, end).
+		Raw(query, agentID, start, end).
 		Scan(&metrics).Error
 
 	return metrics, err
@@ -503,6 +747,9 @@ func (r *MetricRepo) DeleteMonitorMetrics(ctx context.Context, monitorID string)
 func (r *MetricRepo) DeleteAgentMetrics(ctx context.Context, agentID string) error {
 	tables := []interface{}{
 		&models.CPUMetric{},
+		&models.CPUCoreMetric{},
+		&models.CPUTimesMetric{},
+		&models.PressureMetric{},
 		&models.MemoryMetric{},
 		&models.DiskMetric{},
 		&models.DiskIOMetric{},
@@ -511,6 +758,7 @@ func (r *MetricRepo) DeleteAgentMetrics(ctx context.Context, agentID string) err
 		&models.GPUMetric{},
 		&models.TemperatureMetric{},
 		&models.MonitorMetric{},
+		&models.PingMetric{},
 	}
 
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
@@ -567,38 +815,120 @@ func (r *MetricRepo) GetAllLatestMonitorMetrics(ctx context.Context) ([]*models.
 func (r *MetricRepo) AggregateCPUToAgg(ctx context.Context, bucketSeconds int, start, end int64) error {
 	bucketMs := int64(bucketSeconds * 1000)
 	return r.db.WithContext(ctx).Exec(`
-		INSERT INTO cpu_metrics_aggs (agent_id, bucket_seconds, bucket_start, max_usage, logical_cores)
+		INSERT INTO cpu_metrics_aggs (agent_id, bucket_seconds, bucket_start, max_usage, avg_usage, min_usage, logical_cores)
 		SELECT
 			agent_id,
 			? as bucket_seconds,
 			(timestamp / ?) * ? as bucket_start,
 			MAX(usage_percent) as max_usage,
+			AVG(usage_percent) as avg_usage,
+			MIN(usage_percent) as min_usage,
 			MAX(logical_cores) as logical_cores
 		FROM cpu_metrics
 		WHERE timestamp >= ? AND timestamp < ?
 		GROUP BY agent_id, bucket_start
 		ON CONFLICT (agent_id, bucket_seconds, bucket_start) DO UPDATE SET
 			max_usage = EXCLUDED.max_usage,
+			avg_usage = EXCLUDED.avg_usage,
+			min_usage = EXCLUDED.min_usage,
 			logical_cores = EXCLUDED.logical_cores
 	`, bucketSeconds, bucketMs, bucketMs, start, end).Error
 }
 
+// AggregateCPUCoreToAgg 将原始每核CPU数据聚合到聚合表
+func (r *MetricRepo) AggregateCPUCoreToAgg(ctx context.Context, bucketSeconds int, start, end int64) error {
+	bucketMs := int64(bucketSeconds * 1000)
+	return r.db.WithContext(ctx).Exec(`
+		INSERT INTO cpu_core_metrics_aggs (agent_id, bucket_seconds, bucket_start, core, max_usage, avg_usage)
+		SELECT
+			agent_id,
+			? as bucket_seconds,
+			(timestamp / ?) * ? as bucket_start,
+			core,
+			MAX(usage_percent) as max_usage,
+			AVG(usage_percent) as avg_usage
+		FROM cpu_core_metrics
+		WHERE timestamp >= ? AND timestamp < ?
+		GROUP BY agent_id, bucket_start, core
+		ON CONFLICT (agent_id, bucket_seconds, bucket_start, core) DO UPDATE SET
+			max_usage = EXCLUDED.max_usage,
+			avg_usage = EXCLUDED.avg_usage
+	`, bucketSeconds, bucketMs, bucketMs, start, end).Error
+}
+
+// AggregateCPUTimesToAgg 将原始CPU时间分布数据聚合到聚合表
+func (r *MetricRepo) AggregateCPUTimesToAgg(ctx context.Context, bucketSeconds int, start, end int64) error {
+	bucketMs := int64(bucketSeconds * 1000)
+	return r.db.WithContext(ctx).Exec(`
+		INSERT INTO cpu_times_metrics_aggs (agent_id, bucket_seconds, bucket_start, avg_user, avg_system, avg_io_wait, avg_steal, max_steal, avg_idle)
+		SELECT
+			agent_id,
+			? as bucket_seconds,
+			(timestamp / ?) * ? as bucket_start,
+			AVG(user_percent) as avg_user,
+			AVG(system_percent) as avg_system,
+			AVG(io_wait_percent) as avg_io_wait,
+			AVG(steal_percent) as avg_steal,
+			MAX(steal_percent) as max_steal,
+			AVG(idle_percent) as avg_idle
+		FROM cpu_times_metrics
+		WHERE timestamp >= ? AND timestamp < ?
+		GROUP BY agent_id, bucket_start
+		ON CONFLICT (agent_id, bucket_seconds, bucket_start) DO UPDATE SET
+			avg_user = EXCLUDED.avg_user,
+			avg_system = EXCLUDED.avg_system,
+			avg_io_wait = EXCLUDED.avg_io_wait,
+			avg_steal = EXCLUDED.avg_steal,
+			max_steal = EXCLUDED.max_steal,
+			avg_idle = EXCLUDED.avg_idle
+	`, bucketSeconds, bucketMs, bucketMs, start, end).Error
+}
+
+// AggregatePressureToAgg 将原始 PSI 压力数据聚合到聚合表
+func (r *MetricRepo) AggregatePressureToAgg(ctx context.Context, bucketSeconds int, start, end int64) error {
+	bucketMs := int64(bucketSeconds * 1000)
+	return r.db.WithContext(ctx).Exec(`
+		INSERT INTO pressure_metrics_aggs (agent_id, bucket_seconds, bucket_start, resource, max_some, avg_some, max_full, avg_full)
+		SELECT
+			agent_id,
+			? as bucket_seconds,
+			(timestamp / ?) * ? as bucket_start,
+			resource,
+			MAX(some_percent) as max_some,
+			AVG(some_percent) as avg_some,
+			MAX(full_percent) as max_full,
+			AVG(full_percent) as avg_full
+		FROM pressure_metrics
+		WHERE timestamp >= ? AND timestamp < ?
+		GROUP BY agent_id, bucket_start, resource
+		ON CONFLICT (agent_id, bucket_seconds, bucket_start, resource) DO UPDATE SET
+			max_some = EXCLUDED.max_some,
+			avg_some = EXCLUDED.avg_some,
+			max_full = EXCLUDED.max_full,
+			avg_full = EXCLUDED.avg_full
+	`, bucketSeconds, bucketMs, bucketMs, start, end).Error
+}
+
 // AggregateMemoryToAgg 将原始内存数据聚合到聚合表
 func (r *MetricRepo) AggregateMemoryToAgg(ctx context.Context, bucketSeconds int, start, end int64) error {
 	bucketMs := int64(bucketSeconds * 1000)
 	return r.db.WithContext(ctx).Exec(`
-		INSERT INTO memory_metrics_aggs (agent_id, bucket_seconds, bucket_start, max_usage, total)
+		INSERT INTO memory_metrics_aggs (agent_id, bucket_seconds, bucket_start, max_usage, avg_usage, min_usage, total)
 		SELECT
 			agent_id,
 			? as bucket_seconds,
 			(timestamp / ?) * ? as bucket_start,
 			MAX(usage_percent) as max_usage,
+			AVG(usage_percent) as avg_usage,
+			MIN(usage_percent) as min_usage,
 			MAX(total) as total
 		FROM memory_metrics
 		WHERE timestamp >= ? AND timestamp < ?
 		GROUP BY agent_id, bucket_start
 		ON CONFLICT (agent_id, bucket_seconds, bucket_start) DO UPDATE SET
 			max_usage = EXCLUDED.max_usage,
+			avg_usage = EXCLUDED.avg_usage,
+			min_usage = EXCLUDED.min_usage,
 			total = EXCLUDED.total
 	`, bucketSeconds, bucketMs, bucketMs, start, end).Error
 }
@@ -607,19 +937,23 @@ func (r *MetricRepo) AggregateMemoryToAgg(ctx context.Context, bucketSeconds int
 func (r *MetricRepo) AggregateDiskToAgg(ctx context.Context, bucketSeconds int, start, end int64) error {
 	bucketMs := int64(bucketSeconds * 1000)
 	return r.db.WithContext(ctx).Exec(`
-		INSERT INTO disk_metrics_aggs (agent_id, bucket_seconds, bucket_start, mount_point, max_usage, total)
+		INSERT INTO disk_metrics_aggs (agent_id, bucket_seconds, bucket_start, mount_point, max_usage, avg_usage, min_usage, total)
 		SELECT
 			agent_id,
 			? as bucket_seconds,
 			(timestamp / ?) * ? as bucket_start,
 			mount_point,
 			MAX(usage_percent) as max_usage,
+			AVG(usage_percent) as avg_usage,
+			MIN(usage_percent) as min_usage,
 			MAX(total) as total
 		FROM disk_metrics
 		WHERE timestamp >= ? AND timestamp < ?
 		GROUP BY agent_id, bucket_start, mount_point
 		ON CONFLICT (agent_id, bucket_seconds, bucket_start, mount_point) DO UPDATE SET
 			max_usage = EXCLUDED.max_usage,
+			avg_usage = EXCLUDED.avg_usage,
+			min_usage = EXCLUDED.min_usage,
 			total = EXCLUDED.total
 	`, bucketSeconds, bucketMs, bucketMs, start, end).Error
 }
@@ -628,20 +962,24 @@ func (r *MetricRepo) AggregateDiskToAgg(ctx context.Context, bucketSeconds int,
 func (r *MetricRepo) AggregateNetworkToAgg(ctx context.Context, bucketSeconds int, start, end int64) error {
 	bucketMs := int64(bucketSeconds * 1000)
 	return r.db.WithContext(ctx).Exec(`
-		INSERT INTO network_metrics_aggs (agent_id, bucket_seconds, bucket_start, interface, max_sent_rate, max_recv_rate)
+		INSERT INTO network_metrics_aggs (agent_id, bucket_seconds, bucket_start, interface, max_sent_rate, max_recv_rate, avg_sent_rate, avg_recv_rate)
 		SELECT
 			agent_id,
 			? as bucket_seconds,
 			(timestamp / ?) * ? as bucket_start,
 			interface,
 			MAX(bytes_sent_rate) as max_sent_rate,
-			MAX(bytes_recv_rate) as max_recv_rate
+			MAX(bytes_recv_rate) as max_recv_rate,
+			AVG(bytes_sent_rate) as avg_sent_rate,
+			AVG(bytes_recv_rate) as avg_recv_rate
 		FROM network_metrics
 		WHERE timestamp >= ? AND timestamp < ?
 		GROUP BY agent_id, bucket_start, interface
 		ON CONFLICT (agent_id, bucket_seconds, bucket_start, interface) DO UPDATE SET
 			max_sent_rate = EXCLUDED.max_sent_rate,
-			max_recv_rate = EXCLUDED.max_recv_rate
+			max_recv_rate = EXCLUDED.max_recv_rate,
+			avg_sent_rate = EXCLUDED.avg_sent_rate,
+			avg_recv_rate = EXCLUDED.avg_recv_rate
 	`, bucketSeconds, bucketMs, bucketMs, start, end).Error
 }
 
@@ -775,19 +1113,55 @@ func (r *MetricRepo) GetCPUMetricsAgg(ctx context.Context, agentID string, start
 	var metrics []AggregatedCPUMetric
 	err := r.db.WithContext(ctx).
 		Table("cpu_metrics_aggs").
-		Select("bucket_start as timestamp, max_usage, logical_cores").
+		Select("bucket_start as timestamp, max_usage, avg_usage, min_usage, logical_cores").
+		Where("agent_id = ? AND bucket_seconds = ? AND bucket_start >= ? AND bucket_start <= ?", agentID, bucketSeconds, start, end).
+		Order("bucket_start").
+		Scan(&metrics).Error
+	return metrics, err
+}
+
+// GetCPUCoreMetricsAgg 从聚合表获取每核CPU指标
+func (r *MetricRepo) GetCPUCoreMetricsAgg(ctx context.Context, agentID string, start, end int64, bucketSeconds int) ([]AggregatedCPUCoreMetric, error) {
+	var metrics []AggregatedCPUCoreMetric
+	err := r.db.WithContext(ctx).
+		Table("cpu_core_metrics_aggs").
+		Select("bucket_start as timestamp, core, max_usage, avg_usage").
+		Where("agent_id = ? AND bucket_seconds = ? AND bucket_start >= ? AND bucket_start <= ?", agentID, bucketSeconds, start, end).
+		Order("bucket_start, core").
+		Scan(&metrics).Error
+	return metrics, err
+}
+
+// GetCPUTimesMetricsAgg 从聚合表获取CPU时间分布指标
+func (r *MetricRepo) GetCPUTimesMetricsAgg(ctx context.Context, agentID string, start, end int64, bucketSeconds int) ([]AggregatedCPUTimesMetric, error) {
+	var metrics []AggregatedCPUTimesMetric
+	err := r.db.WithContext(ctx).
+		Table("cpu_times_metrics_aggs").
+		Select("bucket_start as timestamp, avg_user, avg_system, avg_io_wait, avg_steal, max_steal, avg_idle").
 		Where("agent_id = ? AND bucket_seconds = ? AND bucket_start >= ? AND bucket_start <= ?", agentID, bucketSeconds, start, end).
 		Order("bucket_start").
 		Scan(&metrics).Error
 	return metrics, err
 }
 
+// GetPressureMetricsAgg 从聚合表获取 PSI 压力指标
+func (r *MetricRepo) GetPressureMetricsAgg(ctx context.Context, agentID string, start, end int64, bucketSeconds int) ([]AggregatedPressureMetric, error) {
+	var metrics []AggregatedPressureMetric
+	err := r.db.WithContext(ctx).
+		Table("pressure_metrics_aggs").
+		Select("bucket_start as timestamp, resource, max_some, avg_some, max_full, avg_full").
+		Where("agent_id = ? AND bucket_seconds = ? AND bucket_start >= ? AND bucket_start <= ?", agentID, bucketSeconds, start, end).
+		Order("bucket_start, resource").
+		Scan(&metrics).Error
+	return metrics, err
+}
+
 // GetMemoryMetricsAgg 从聚合表获取内存指标
 func (r *MetricRepo) GetMemoryMetricsAgg(ctx context.Context, agentID string, start, end int64, bucketSeconds int) ([]AggregatedMemoryMetric, error) {
 	var metrics []AggregatedMemoryMetric
 	err := r.db.WithContext(ctx).
 		Table("memory_metrics_aggs").
-		Select("bucket_start as timestamp, max_usage, total").
+		Select("bucket_start as timestamp, max_usage, avg_usage, min_usage, total").
 		Where("agent_id = ? AND bucket_seconds = ? AND bucket_start >= ? AND bucket_start <= ?", agentID, bucketSeconds, start, end).
 		Order("bucket_start").
 		Scan(&metrics).Error
@@ -800,7 +1174,7 @@ func (r *MetricRepo) GetDiskMetricsAgg(ctx context.Context, agentID string, star
 	var metrics []AggregatedDiskMetric
 	err := r.db.WithContext(ctx).
 		Table("disk_metrics_aggs").
-		Select("bucket_start as timestamp, mount_point, max_usage, total").
+		Select("bucket_start as timestamp, mount_point, max_usage, avg_usage, min_usage, total").
 		Where("agent_id = ? AND bucket_seconds = ? AND bucket_start >= ? AND bucket_start <= ? AND mount_point = ?",
 			agentID, bucketSeconds, start, end, ""). // 空字符串查询总和记录
 		Order("bucket_start").
@@ -817,7 +1191,7 @@ func (r *MetricRepo) GetNetworkMetricsAgg(ctx context.Context, agentID string, s
 	// interfaceName 为空字符串时，会查询到预先保存的总和数据
 	err := r.db.WithContext(ctx).
 		Table("network_metrics_aggs").
-		Select("bucket_start as timestamp, interface, max_sent_rate, max_recv_rate").
+		Select("bucket_start as timestamp, interface, max_sent_rate, max_recv_rate, avg_sent_rate, avg_recv_rate").
 		Where("agent_id = ? AND bucket_seconds = ? AND bucket_start >= ? AND bucket_start <= ? AND interface = ?",
 			agentID, bucketSeconds, start, end, interfaceName).
 		Order("bucket_start").
@@ -924,6 +1298,45 @@ func (r *MetricRepo) GetAggregationProgress(ctx context.Context, metricType stri
 	return &progress, nil
 }
 
+// DeleteOldAggregates 删除指定时间之前的预聚合数据
+func (r *MetricRepo) DeleteOldAggregates(ctx context.Context, beforeTimestamp int64) error {
+	batchSize := 1000
+
+	tables := []interface{}{
+		&models.AggregatedCPUMetricModel{},
+		&models.AggregatedCPUCoreMetricModel{},
+		&models.AggregatedCPUTimesMetricModel{},
+		&models.AggregatedPressureMetricModel{},
+		&models.AggregatedMemoryMetricModel{},
+		&models.AggregatedDiskMetricModel{},
+		&models.AggregatedNetworkMetricModel{},
+		&models.AggregatedNetworkConnectionMetricModel{},
+		&models.AggregatedDiskIOMetricModel{},
+		&models.AggregatedGPUMetricModel{},
+		&models.AggregatedTemperatureMetricModel{},
+		&models.AggregatedMonitorMetricModel{},
+	}
+
+	for _, table := range tables {
+		for {
+			// 分批删除，避免长事务
+			result := r.db.WithContext(ctx).
+				Where("bucket_start < ?", beforeTimestamp).
+				Limit(batchSize).
+				Delete(table)
+
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected < int64(batchSize) {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
 // ----------- 监控指标聚合表操作 -----------
 
 // AggregateMonitorMetricsToAgg 将原始监控数据聚合到聚合表
@@ -942,6 +1355,7 @@ func (r *MetricRepo) AggregateMonitorMetricsToAgg(ctx context.Context, bucketSec
 				agent_id,
 				(timestamp / ?) * ? as bucket_start,
 				response_time,
+				checks,
 				status,
 				error,
 				timestamp,
@@ -954,12 +1368,12 @@ func (r *MetricRepo) AggregateMonitorMetricsToAgg(ctx context.Context, bucketSec
 			agent_id,
 			? as bucket_seconds,
 			bucket_start,
-			AVG(response_time) as avg_response,
+			CAST(SUM(response_time * checks) AS REAL) / CAST(SUM(checks) AS REAL) as avg_response,
 			MAX(response_time) as max_response,
 			MIN(response_time) as min_response,
-			SUM(CASE WHEN status = 'up' THEN 1 ELSE 0 END) as success_count,
-			COUNT(*) as total_count,
-			CAST(SUM(CASE WHEN status = 'up' THEN 1 ELSE 0 END) AS REAL) / CAST(COUNT(*) AS REAL) * 100 as success_rate,
+			SUM(CASE WHEN status = 'up' THEN checks ELSE 0 END) as success_count,
+			SUM(checks) as total_count,
+			CAST(SUM(CASE WHEN status = 'up' THEN checks ELSE 0 END) AS REAL) / CAST(SUM(checks) AS REAL) * 100 as success_rate,
 			MAX(CASE WHEN rn = 1 THEN status END) as last_status,
 			MAX(CASE WHEN rn = 1 THEN error END) as last_error
 		FROM ranked_metrics
@@ -0,0 +1,172 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/dushixiang/pika/internal/config"
+	"github.com/dushixiang/pika/internal/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// MetricStore 指标存储接口，负责指标的写入、查询和清理
+// 默认实现为基于 GORM 的 MetricRepo；接入 ClickHouse、VictoriaMetrics、文件等其他存储后端时实现该接口即可，
+// 指标写入（MetricService.HandleMetricData）和查询（MetricService.GetMetrics）逻辑无需修改
+type MetricStore interface {
+	// 写入
+	SaveCPUMetric(ctx context.Context, metric *models.CPUMetric) error
+	SaveMemoryMetric(ctx context.Context, metric *models.MemoryMetric) error
+	SaveDiskMetric(ctx context.Context, metric *models.DiskMetric) error
+	SaveNetworkMetric(ctx context.Context, metric *models.NetworkMetric) error
+	SaveNetworkConnectionMetric(ctx context.Context, metric *models.NetworkConnectionMetric) error
+	SaveDiskIOMetric(ctx context.Context, metric *models.DiskIOMetric) error
+	SaveGPUMetric(ctx context.Context, metric *models.GPUMetric) error
+	SaveTemperatureMetric(ctx context.Context, metric *models.TemperatureMetric) error
+	SaveHostMetric(ctx context.Context, metric *models.HostMetric) error
+	SavePingMetric(ctx context.Context, metric *models.PingMetric) error
+	SaveMonitorMetric(ctx context.Context, metric *models.MonitorMetric) error
+	// 批量写入，MetricService 将一段时间内上报的指标攒批后一次写入
+	SaveMetricBatch(ctx context.Context, batch *MetricBatch) error
+
+	// 按时间范围查询原始数据，interval 为聚合粒度（秒）
+	GetCPUMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedCPUMetric, error)
+	GetCPUCoreMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedCPUCoreMetric, error)
+	GetCPUTimesMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedCPUTimesMetric, error)
+	GetPressureMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedPressureMetric, error)
+	GetMemoryMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedMemoryMetric, error)
+	GetDiskMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedDiskMetric, error)
+	GetNetworkMetrics(ctx context.Context, agentID string, start, end int64, interval int, interfaceName string) ([]AggregatedNetworkMetric, error)
+	GetNetworkConnectionMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedNetworkConnectionMetric, error)
+	GetDiskIOMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedDiskIOMetric, error)
+	GetGPUMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedGPUMetric, error)
+	GetTemperatureMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedTemperatureMetric, error)
+	GetPingMetrics(ctx context.Context, agentID string, start, end int64, interval int) ([]AggregatedPingMetric, error)
+	GetAvailableNetworkInterfaces(ctx context.Context, agentID string) ([]string, error)
+
+	// 服务监控
+	GetMonitorMetrics(ctx context.Context, agentID, monitorID string, start, end int64) ([]models.MonitorMetric, error)
+	GetMonitorMetricsByName(ctx context.Context, agentID, monitorID string, start, end int64, limit int) ([]models.MonitorMetric, error)
+	GetAggregatedMonitorMetrics(ctx context.Context, monitorID string, start, end int64, interval int) ([]AggregatedMonitorMetric, error)
+	GetLatestMonitorMetricsByType(ctx context.Context, monitorType string) ([]*models.MonitorMetric, error)
+	GetAllLatestMonitorMetrics(ctx context.Context) ([]*models.MonitorMetric, error)
+	// 原始检测记录，分页查询按时间倒序，导出按时间正序
+	PageMonitorMetrics(ctx context.Context, filter MonitorMetricFilter, offset, limit int) ([]models.MonitorMetric, int64, error)
+	ListMonitorMetrics(ctx context.Context, filter MonitorMetricFilter) ([]models.MonitorMetric, error)
+
+	// 清理
+	DeleteOldMetrics(ctx context.Context, beforeTimestamp int64) error
+	DeleteAgentMetrics(ctx context.Context, agentID string) error
+	DeleteMonitorMetrics(ctx context.Context, monitorID string) error
+}
+
+// MonitorMetricFilter 原始检测记录的查询条件，AgentID 和 Status 为空时不过滤
+type MonitorMetricFilter struct {
+	MonitorID string
+	AgentID   string
+	Status    string
+	Start     int64
+	End       int64
+}
+
+// MetricBatch 一批待写入的时序指标，主机信息按探针 upsert，不走批量写入
+type MetricBatch struct {
+	CPU               []models.CPUMetric
+	CPUCore           []models.CPUCoreMetric
+	CPUTimes          []models.CPUTimesMetric
+	Pressure          []models.PressureMetric
+	Memory            []models.MemoryMetric
+	Disk              []models.DiskMetric
+	Network           []models.NetworkMetric
+	NetworkConnection []models.NetworkConnectionMetric
+	DiskIO            []models.DiskIOMetric
+	GPU               []models.GPUMetric
+	Temperature       []models.TemperatureMetric
+	Ping              []models.PingMetric
+	Monitor           []models.MonitorMetric
+}
+
+// Len 批次中的指标总行数
+func (b *MetricBatch) Len() int {
+	return len(b.CPU) + len(b.CPUCore) + len(b.CPUTimes) + len(b.Pressure) + len(b.Memory) + len(b.Disk) + len(b.Network) + len(b.NetworkConnection) +
+		len(b.DiskIO) + len(b.GPU) + len(b.Temperature) + len(b.Ping) + len(b.Monitor)
+}
+
+// Merge 将另一批次的指标追加到当前批次
+func (b *MetricBatch) Merge(other *MetricBatch) {
+	b.CPU = append(b.CPU, other.CPU...)
+	b.CPUCore = append(b.CPUCore, other.CPUCore...)
+	b.CPUTimes = append(b.CPUTimes, other.CPUTimes...)
+	b.Pressure = append(b.Pressure, other.Pressure...)
+	b.Memory = append(b.Memory, other.Memory...)
+	b.Disk = append(b.Disk, other.Disk...)
+	b.Network = append(b.Network, other.Network...)
+	b.NetworkConnection = append(b.NetworkConnection, other.NetworkConnection...)
+	b.DiskIO = append(b.DiskIO, other.DiskIO...)
+	b.GPU = append(b.GPU, other.GPU...)
+	b.Temperature = append(b.Temperature, other.Temperature...)
+	b.Ping = append(b.Ping, other.Ping...)
+	b.Monitor = append(b.Monitor, other.Monitor...)
+}
+
+// MetricAggregator 预聚合（下采样）接口，为可选能力
+// 存储后端实现该接口后，MetricService 会定时把原始数据下采样到固定 bucket，长时间范围的查询优先读取预聚合数据；
+// 自带降采样能力的后端（如 VictoriaMetrics）可以不实现，查询时直接使用 MetricStore 的原始数据查询
+type MetricAggregator interface {
+	AggregateCPUToAgg(ctx context.Context, bucketSeconds int, start, end int64) error
+	AggregateCPUCoreToAgg(ctx context.Context, bucketSeconds int, start, end int64) error
+	AggregateCPUTimesToAgg(ctx context.Context, bucketSeconds int, start, end int64) error
+	AggregatePressureToAgg(ctx context.Context, bucketSeconds int, start, end int64) error
+	AggregateMemoryToAgg(ctx context.Context, bucketSeconds int, start, end int64) error
+	AggregateDiskToAgg(ctx context.Context, bucketSeconds int, start, end int64) error
+	AggregateNetworkToAgg(ctx context.Context, bucketSeconds int, start, end int64) error
+	AggregateNetworkConnectionToAgg(ctx context.Context, bucketSeconds int, start, end int64) error
+	AggregateDiskIOToAgg(ctx context.Context, bucketSeconds int, start, end int64) error
+	AggregateGPUToAgg(ctx context.Context, bucketSeconds int, start, end int64) error
+	AggregateTemperatureToAgg(ctx context.Context, bucketSeconds int, start, end int64) error
+	AggregateMonitorMetricsToAgg(ctx context.Context, bucketSeconds int, start, end int64) error
+
+	GetCPUMetricsAgg(ctx context.Context, agentID string, start, end int64, bucketSeconds int) ([]AggregatedCPUMetric, error)
+	GetCPUCoreMetricsAgg(ctx context.Context, agentID string, start, end int64, bucketSeconds int) ([]AggregatedCPUCoreMetric, error)
+	GetCPUTimesMetricsAgg(ctx context.Context, agentID string, start, end int64, bucketSeconds int) ([]AggregatedCPUTimesMetric, error)
+	GetPressureMetricsAgg(ctx context.Context, agentID string, start, end int64, bucketSeconds int) ([]AggregatedPressureMetric, error)
+	GetMemoryMetricsAgg(ctx context.Context, agentID string, start, end int64, bucketSeconds int) ([]AggregatedMemoryMetric, error)
+	GetDiskMetricsAgg(ctx context.Context, agentID string, start, end int64, bucketSeconds int) ([]AggregatedDiskMetric, error)
+	GetNetworkMetricsAgg(ctx context.Context, agentID string, start, end int64, bucketSeconds int, interfaceName string) ([]AggregatedNetworkMetric, error)
+	GetNetworkConnectionMetricsAgg(ctx context.Context, agentID string, start, end int64, bucketSeconds int) ([]AggregatedNetworkConnectionMetric, error)
+	GetDiskIOMetricsAgg(ctx context.Context, agentID string, start, end int64, bucketSeconds int) ([]AggregatedDiskIOMetric, error)
+	GetGPUMetricsAgg(ctx context.Context, agentID string, start, end int64, bucketSeconds int) ([]AggregatedGPUMetric, error)
+	GetTemperatureMetricsAgg(ctx context.Context, agentID string, start, end int64, bucketSeconds int) ([]AggregatedTemperatureMetric, error)
+	GetMonitorMetricsAgg(ctx context.Context, monitorID string, start, end int64, bucketSeconds int) ([]AggregatedMonitorMetric, error)
+
+	UpsertAggregationProgress(ctx context.Context, metricType string, bucketSeconds int, lastBucket int64) error
+	GetAggregationProgress(ctx context.Context, metricType string, bucketSeconds int) (*models.AggregationProgress, error)
+
+	// 清理超过预聚合保留时间的数据
+	DeleteOldAggregates(ctx context.Context, beforeTimestamp int64) error
+}
+
+var (
+	_ MetricStore      = (*MetricRepo)(nil)
+	_ MetricAggregator = (*MetricRepo)(nil)
+	_ MetricStore      = (*ClickHouseMetricStore)(nil)
+)
+
+// NewMetricStore 创建指标存储，启用 ClickHouse 时使用 ClickHouse，否则使用与业务数据相同的数据库
+// PostgreSQL 安装了 TimescaleDB 扩展时，指标表会转换为 hypertable，启用失败时退回普通表
+func NewMetricStore(logger *zap.Logger, db *gorm.DB, cfg *config.AppConfig) (MetricStore, error) {
+	if cfg.ClickHouse != nil && cfg.ClickHouse.Enabled {
+		return NewClickHouseMetricStore(cfg.ClickHouse)
+	}
+
+	logger = logger.Named("metric")
+	metricRepo := NewMetricRepo(db)
+	enabled, err := SetupTimescaleDB(context.Background(), db, cfg.TimescaleDB)
+	if err != nil {
+		logger.Warn("启用 TimescaleDB 失败，指标表保持普通表", zap.Error(err))
+	}
+	if enabled {
+		metricRepo.timescale = true
+		logger.Info("已启用 TimescaleDB，指标表使用 hypertable 存储")
+	}
+	return metricRepo, nil
+}
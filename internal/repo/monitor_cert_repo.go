@@ -0,0 +1,57 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/go-orz/orz"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MonitorCertRepo struct {
+	orz.Repository[models.MonitorCert, string]
+	db *gorm.DB
+}
+
+func NewMonitorCertRepo(db *gorm.DB) *MonitorCertRepo {
+	return &MonitorCertRepo{
+		Repository: orz.NewRepository[models.MonitorCert, string](db),
+		db:         db,
+	}
+}
+
+// Upsert 保存证书详情，已存在时覆盖
+func (r *MonitorCertRepo) Upsert(ctx context.Context, cert *models.MonitorCert) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(cert).Error
+}
+
+func (r *MonitorCertRepo) FindByMonitorId(ctx context.Context, monitorId string) ([]models.MonitorCert, error) {
+	var certs []models.MonitorCert
+	err := r.db.WithContext(ctx).
+		Where("monitor_id = ?", monitorId).
+		Order("agent_id").
+		Find(&certs).Error
+	return certs, err
+}
+
+// FindAll 查找所有证书详情
+func (r *MonitorCertRepo) FindAll(ctx context.Context) ([]models.MonitorCert, error) {
+	var certs []models.MonitorCert
+	err := r.db.WithContext(ctx).Find(&certs).Error
+	return certs, err
+}
+
+func (r *MonitorCertRepo) DeleteByMonitorId(ctx context.Context, monitorId string) error {
+	return r.db.WithContext(ctx).
+		Where("monitor_id = ?", monitorId).
+		Delete(&models.MonitorCert{}).Error
+}
+
+func (r *MonitorCertRepo) DeleteByAgentId(ctx context.Context, agentId string) error {
+	return r.db.WithContext(ctx).
+		Where("agent_id = ?", agentId).
+		Delete(&models.MonitorCert{}).Error
+}
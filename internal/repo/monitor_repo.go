@@ -68,3 +68,20 @@ func (r *MonitorRepo) FindByEnabled(ctx context.Context, enabled bool) ([]models
 	}
 	return monitors, nil
 }
+
+// FindByPushToken 根据心跳推送令牌查找监控任务
+func (r *MonitorRepo) FindByPushToken(ctx context.Context, token string) (models.MonitorTask, error) {
+	var task models.MonitorTask
+	err := r.GetDB(ctx).
+		Where("push_token = ?", token).
+		First(&task).Error
+	return task, err
+}
+
+// UpdateLastPushAt 更新最后一次收到心跳的时间，不修改配置的更新时间
+func (r *MonitorRepo) UpdateLastPushAt(ctx context.Context, id string, lastPushAt int64) error {
+	return r.GetDB(ctx).
+		Model(&models.MonitorTask{}).
+		Where("id = ?", id).
+		UpdateColumn("last_push_at", lastPushAt).Error
+}
@@ -64,3 +64,14 @@ func (r *MonitorStatsRepo) DeleteByIDs(ctx context.Context, ids []string) error
 		Where("id IN ?", ids).
 		Delete(&models.MonitorStats{}).Error
 }
+
+// CountExpiringCerts 统计证书剩余天数不超过指定天数的监控项数量（按监控项去重）
+func (r *MonitorStatsRepo) CountExpiringCerts(ctx context.Context, agentIDs []string, days int) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.MonitorStats{}).
+		Where("cert_expiry_date > 0 AND cert_expiry_days <= ? AND agent_id IN ?", days, agentIDs).
+		Distinct("monitor_id").
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,62 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/go-orz/orz"
+	"gorm.io/gorm"
+)
+
+type MonitorTraceRepo struct {
+	orz.Repository[models.MonitorTrace, int64]
+	db *gorm.DB
+}
+
+func NewMonitorTraceRepo(db *gorm.DB) *MonitorTraceRepo {
+	return &MonitorTraceRepo{
+		Repository: orz.NewRepository[models.MonitorTrace, int64](db),
+		db:         db,
+	}
+}
+
+// FindRecentByMonitorId 查询监控项最近的路径追踪，按检测时间倒序
+func (r *MonitorTraceRepo) FindRecentByMonitorId(ctx context.Context, monitorId string, limit int) ([]models.MonitorTrace, error) {
+	var traces []models.MonitorTrace
+	err := r.db.WithContext(ctx).
+		Where("monitor_id = ?", monitorId).
+		Order("checked_at desc").
+		Limit(limit).
+		Find(&traces).Error
+	return traces, err
+}
+
+// TrimByMonitorAgent 只保留探针在监控项上最近的 keep 条路径追踪
+func (r *MonitorTraceRepo) TrimByMonitorAgent(ctx context.Context, monitorId, agentId string, keep int) error {
+	var ids []int64
+	err := r.db.WithContext(ctx).
+		Model(&models.MonitorTrace{}).
+		Where("monitor_id = ? AND agent_id = ?", monitorId, agentId).
+		Order("id desc").
+		Offset(keep).
+		Limit(1).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+	return r.db.WithContext(ctx).
+		Where("monitor_id = ? AND agent_id = ? AND id <= ?", monitorId, agentId, ids[0]).
+		Delete(&models.MonitorTrace{}).Error
+}
+
+func (r *MonitorTraceRepo) DeleteByMonitorId(ctx context.Context, monitorId string) error {
+	return r.db.WithContext(ctx).
+		Where("monitor_id = ?", monitorId).
+		Delete(&models.MonitorTrace{}).Error
+}
+
+func (r *MonitorTraceRepo) DeleteByAgentId(ctx context.Context, agentId string) error {
+	return r.db.WithContext(ctx).
+		Where("agent_id = ?", agentId).
+		Delete(&models.MonitorTrace{}).Error
+}
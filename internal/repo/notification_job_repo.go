@@ -0,0 +1,42 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/go-orz/orz"
+	"gorm.io/gorm"
+)
+
+type NotificationJobRepo struct {
+	orz.Repository[models.NotificationJob, int64]
+	db *gorm.DB
+}
+
+func NewNotificationJobRepo(db *gorm.DB) *NotificationJobRepo {
+	return &NotificationJobRepo{
+		Repository: orz.NewRepository[models.NotificationJob, int64](db),
+		db:         db,
+	}
+}
+
+// FindDue 获取已到重试时间的待发送任务，按下次尝试时间排序，跳过 excludeChannels 中的渠道
+func (r *NotificationJobRepo) FindDue(ctx context.Context, now int64, limit int, excludeChannels []string) ([]models.NotificationJob, error) {
+	var jobs []models.NotificationJob
+	db := r.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", "pending", now)
+	if len(excludeChannels) > 0 {
+		db = db.Where("channel_type NOT IN ?", excludeChannels)
+	}
+	err := db.Order("next_attempt_at").
+		Limit(limit).
+		Find(&jobs).Error
+	return jobs, err
+}
+
+// DeleteByStatus 删除指定状态的任务
+func (r *NotificationJobRepo) DeleteByStatus(ctx context.Context, status string) error {
+	return r.db.WithContext(ctx).
+		Where("status = ?", status).
+		Delete(&models.NotificationJob{}).Error
+}
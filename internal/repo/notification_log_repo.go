@@ -0,0 +1,29 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/go-orz/orz"
+	"gorm.io/gorm"
+)
+
+type NotificationLogRepo struct {
+	orz.Repository[models.NotificationLog, int64]
+	db *gorm.DB
+}
+
+func NewNotificationLogRepo(db *gorm.DB) *NotificationLogRepo {
+	return &NotificationLogRepo{
+		Repository: orz.NewRepository[models.NotificationLog, int64](db),
+		db:         db,
+	}
+}
+
+// DeleteBefore 删除指定时间之前的发送日志
+func (r *NotificationLogRepo) DeleteBefore(ctx context.Context, before int64) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("created_at < ?", before).
+		Delete(&models.NotificationLog{})
+	return result.RowsAffected, result.Error
+}
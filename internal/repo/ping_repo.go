@@ -0,0 +1,48 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/go-orz/orz"
+	"gorm.io/gorm"
+)
+
+type PingTargetRepo struct {
+	orz.Repository[models.PingTarget, string]
+	db *gorm.DB
+}
+
+func NewPingTargetRepo(db *gorm.DB) *PingTargetRepo {
+	return &PingTargetRepo{
+		Repository: orz.NewRepository[models.PingTarget, string](db),
+		db:         db,
+	}
+}
+
+// FindByAgentID 获取探针的 Ping 目标
+func (r *PingTargetRepo) FindByAgentID(ctx context.Context, agentID string) ([]models.PingTarget, error) {
+	var targets []models.PingTarget
+	err := r.db.WithContext(ctx).
+		Where("agent_id = ?", agentID).
+		Order("created_at").
+		Find(&targets).Error
+	return targets, err
+}
+
+// FindEnabledByAgentID 获取探针已启用的 Ping 目标
+func (r *PingTargetRepo) FindEnabledByAgentID(ctx context.Context, agentID string) ([]models.PingTarget, error) {
+	var targets []models.PingTarget
+	err := r.db.WithContext(ctx).
+		Where("agent_id = ? AND enabled = ?", agentID, true).
+		Order("created_at").
+		Find(&targets).Error
+	return targets, err
+}
+
+// DeleteByAgentID 删除探针的 Ping 目标
+func (r *PingTargetRepo) DeleteByAgentID(ctx context.Context, agentID string) error {
+	return r.db.WithContext(ctx).
+		Where("agent_id = ?", agentID).
+		Delete(&models.PingTarget{}).Error
+}
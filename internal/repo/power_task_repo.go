@@ -0,0 +1,71 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/go-orz/orz"
+	"gorm.io/gorm"
+)
+
+type PowerTaskRepo struct {
+	orz.Repository[models.PowerTask, int64]
+	db *gorm.DB
+}
+
+func NewPowerTaskRepo(db *gorm.DB) *PowerTaskRepo {
+	return &PowerTaskRepo{
+		Repository: orz.NewRepository[models.PowerTask, int64](db),
+		db:         db,
+	}
+}
+
+// FindDue 获取已确认且到达计划时间的任务
+func (r *PowerTaskRepo) FindDue(ctx context.Context, now int64) ([]models.PowerTask, error) {
+	var tasks []models.PowerTask
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND scheduled_at <= ?", "scheduled", now).
+		Order("scheduled_at").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// FindByCommandID 根据指令ID获取任务
+func (r *PowerTaskRepo) FindByCommandID(ctx context.Context, agentID, commandID string) (*models.PowerTask, error) {
+	var task models.PowerTask
+	err := r.db.WithContext(ctx).
+		Where("agent_id = ? AND command_id = ?", agentID, commandID).
+		First(&task).Error
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// FindRecentByAgentID 获取探针最近的电源操作任务
+func (r *PowerTaskRepo) FindRecentByAgentID(ctx context.Context, agentID string, limit int) ([]models.PowerTask, error) {
+	var tasks []models.PowerTask
+	err := r.db.WithContext(ctx).
+		Where("agent_id = ?", agentID).
+		Order("created_at desc").
+		Limit(limit).
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// FindByAgentIDBetween 获取探针在时间范围内计划执行的电源操作任务，按计划时间升序
+func (r *PowerTaskRepo) FindByAgentIDBetween(ctx context.Context, agentID string, start, end int64) ([]models.PowerTask, error) {
+	var tasks []models.PowerTask
+	err := r.db.WithContext(ctx).
+		Where("agent_id = ? AND scheduled_at >= ? AND scheduled_at <= ?", agentID, start, end).
+		Order("scheduled_at").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// DeleteByAgentID 删除探针的电源操作任务
+func (r *PowerTaskRepo) DeleteByAgentID(ctx context.Context, agentID string) error {
+	return r.db.WithContext(ctx).
+		Where("agent_id = ?", agentID).
+		Delete(&models.PowerTask{}).Error
+}
@@ -0,0 +1,54 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/go-orz/orz"
+	"gorm.io/gorm"
+)
+
+type ProcessKillRepo struct {
+	orz.Repository[models.ProcessKillRecord, int64]
+	db *gorm.DB
+}
+
+func NewProcessKillRepo(db *gorm.DB) *ProcessKillRepo {
+	return &ProcessKillRepo{
+		Repository: orz.NewRepository[models.ProcessKillRecord, int64](db),
+		db:         db,
+	}
+}
+
+// FindByCommandID 根据指令ID获取结束进程记录
+func (r *ProcessKillRepo) FindByCommandID(ctx context.Context, agentID, commandID string) (*models.ProcessKillRecord, error) {
+	var record models.ProcessKillRecord
+	err := r.db.WithContext(ctx).
+		Where("agent_id = ? AND command_id = ?", agentID, commandID).
+		First(&record).Error
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Finish 更新仍在执行中的记录为结束状态，已结束的记录不再更新
+func (r *ProcessKillRepo) Finish(ctx context.Context, id int64, values map[string]interface{}) error {
+	return r.db.WithContext(ctx).
+		Model(&models.ProcessKillRecord{}).
+		Where("id = ? AND status = ?", id, models.ProcessKillStatusRunning).
+		Updates(values).Error
+}
+
+// CloseRunning 将仍在执行中的记录标记为失败，用于服务重启后清理无法再收到结果的记录
+func (r *ProcessKillRepo) CloseRunning(ctx context.Context, reason string, finishedAt int64) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.ProcessKillRecord{}).
+		Where("status = ?", models.ProcessKillStatusRunning).
+		Updates(map[string]interface{}{
+			"status":      models.ProcessKillStatusError,
+			"error":       reason,
+			"finished_at": finishedAt,
+		})
+	return result.RowsAffected, result.Error
+}
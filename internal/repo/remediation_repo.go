@@ -0,0 +1,74 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/go-orz/orz"
+	"gorm.io/gorm"
+)
+
+type RemediationRepo struct {
+	orz.Repository[models.RemediationRecord, int64]
+	db *gorm.DB
+}
+
+func NewRemediationRepo(db *gorm.DB) *RemediationRepo {
+	return &RemediationRepo{
+		Repository: orz.NewRepository[models.RemediationRecord, int64](db),
+		db:         db,
+	}
+}
+
+// FindByAlertRecordID 获取告警记录关联的修复动作
+func (r *RemediationRepo) FindByAlertRecordID(ctx context.Context, alertRecordID int64) ([]models.RemediationRecord, error) {
+	var records []models.RemediationRecord
+	err := r.db.WithContext(ctx).
+		Where("alert_record_id = ?", alertRecordID).
+		Order("id").
+		Find(&records).Error
+	return records, err
+}
+
+// FindByAgentIDBetween 获取探针在时间范围内创建的修复动作，按创建时间升序
+func (r *RemediationRepo) FindByAgentIDBetween(ctx context.Context, agentID string, start, end int64) ([]models.RemediationRecord, error) {
+	var records []models.RemediationRecord
+	err := r.db.WithContext(ctx).
+		Where("agent_id = ? AND created_at >= ? AND created_at <= ?", agentID, start, end).
+		Order("created_at").
+		Find(&records).Error
+	return records, err
+}
+
+// FindByCommandID 根据指令ID获取修复动作
+func (r *RemediationRepo) FindByCommandID(ctx context.Context, agentID, commandID string) (*models.RemediationRecord, error) {
+	var record models.RemediationRecord
+	err := r.db.WithContext(ctx).
+		Where("agent_id = ? AND command_id = ?", agentID, commandID).
+		First(&record).Error
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// UpdatePending 更新仍在待审批状态的修复动作，返回是否更新成功；并发审批时只有一个请求能更新成功
+func (r *RemediationRepo) UpdatePending(ctx context.Context, id int64, values map[string]interface{}) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.RemediationRecord{}).
+		Where("id = ? AND status = ?", id, "pending").
+		Updates(values)
+	return result.RowsAffected > 0, result.Error
+}
+
+// DeleteByAgentID 删除探针的修复动作记录
+func (r *RemediationRepo) DeleteByAgentID(ctx context.Context, agentID string) error {
+	return r.db.WithContext(ctx).
+		Where("agent_id = ?", agentID).
+		Delete(&models.RemediationRecord{}).Error
+}
+
+// Clear 清空修复动作记录
+func (r *RemediationRepo) Clear(ctx context.Context) error {
+	return r.db.WithContext(ctx).Where("1=1").Delete(&models.RemediationRecord{}).Error
+}
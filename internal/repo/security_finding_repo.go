@@ -0,0 +1,36 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/go-orz/orz"
+	"gorm.io/gorm"
+)
+
+type SecurityFindingRepo struct {
+	orz.Repository[models.SecurityFinding, string]
+	db *gorm.DB
+}
+
+func NewSecurityFindingRepo(db *gorm.DB) *SecurityFindingRepo {
+	return &SecurityFindingRepo{
+		Repository: orz.NewRepository[models.SecurityFinding, string](db),
+		db:         db,
+	}
+}
+
+// SaveAll 批量保存安全漏洞发现
+func (r *SecurityFindingRepo) SaveAll(ctx context.Context, findings []models.SecurityFinding) error {
+	if len(findings) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Save(&findings).Error
+}
+
+// DeleteByAgentID 删除探针的安全漏洞发现
+func (r *SecurityFindingRepo) DeleteByAgentID(ctx context.Context, agentID string) error {
+	return r.db.WithContext(ctx).
+		Where("agent_id = ?", agentID).
+		Delete(&models.SecurityFinding{}).Error
+}
@@ -0,0 +1,60 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/go-orz/orz"
+	"gorm.io/gorm"
+)
+
+type SoftwareRepo struct {
+	orz.Repository[models.SoftwareInventory, string]
+	db *gorm.DB
+}
+
+func NewSoftwareRepo(db *gorm.DB) *SoftwareRepo {
+	return &SoftwareRepo{
+		Repository: orz.NewRepository[models.SoftwareInventory, string](db),
+		db:         db,
+	}
+}
+
+// ReplaceByAgentID 使用最新上报的清单替换探针的全部软件记录
+func (r *SoftwareRepo) ReplaceByAgentID(ctx context.Context, agentID string, items []models.SoftwareInventory) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("agent_id = ?", agentID).Delete(&models.SoftwareInventory{}).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		return tx.Create(&items).Error
+	})
+}
+
+// FindByAgentID 获取探针的软件清单
+func (r *SoftwareRepo) FindByAgentID(ctx context.Context, agentID string) ([]models.SoftwareInventory, error) {
+	var items []models.SoftwareInventory
+	err := r.db.WithContext(ctx).
+		Where("agent_id = ?", agentID).
+		Order("name").
+		Find(&items).Error
+	return items, err
+}
+
+// FindByName 获取所有探针上指定软件的记录
+func (r *SoftwareRepo) FindByName(ctx context.Context, name string) ([]models.SoftwareInventory, error) {
+	var items []models.SoftwareInventory
+	err := r.db.WithContext(ctx).
+		Where("name = ?", name).
+		Find(&items).Error
+	return items, err
+}
+
+// DeleteByAgentID 删除探针的软件清单
+func (r *SoftwareRepo) DeleteByAgentID(ctx context.Context, agentID string) error {
+	return r.db.WithContext(ctx).
+		Where("agent_id = ?", agentID).
+		Delete(&models.SoftwareInventory{}).Error
+}
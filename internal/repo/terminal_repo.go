@@ -0,0 +1,66 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/go-orz/orz"
+	"gorm.io/gorm"
+)
+
+type TerminalSessionRepo struct {
+	orz.Repository[models.TerminalSession, string]
+	db *gorm.DB
+}
+
+func NewTerminalSessionRepo(db *gorm.DB) *TerminalSessionRepo {
+	return &TerminalSessionRepo{
+		Repository: orz.NewRepository[models.TerminalSession, string](db),
+		db:         db,
+	}
+}
+
+// AppendRecord 追加一段会话录像
+func (r *TerminalSessionRepo) AppendRecord(ctx context.Context, sessionID, data string) error {
+	return r.db.WithContext(ctx).Create(&models.TerminalRecordChunk{
+		SessionID: sessionID,
+		Data:      data,
+	}).Error
+}
+
+// EachRecordChunk 按写入顺序遍历会话录像片段
+func (r *TerminalSessionRepo) EachRecordChunk(ctx context.Context, sessionID string, fn func(data string) error) error {
+	rows, err := r.db.WithContext(ctx).
+		Model(&models.TerminalRecordChunk{}).
+		Select("data").
+		Where("session_id = ?", sessionID).
+		Order("id").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return err
+		}
+		if err := fn(data); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// CloseRunning 将仍处于运行中的会话标记为已结束，用于服务重启后清理中断的会话
+func (r *TerminalSessionRepo) CloseRunning(ctx context.Context, reason string, endedAt int64) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.TerminalSession{}).
+		Where("status = ?", models.TerminalStatusRunning).
+		Updates(map[string]interface{}{
+			"status":       models.TerminalStatusClosed,
+			"close_reason": reason,
+			"ended_at":     endedAt,
+		})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,128 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dushixiang/pika/internal/config"
+	"github.com/dushixiang/pika/internal/models"
+	"gorm.io/gorm"
+)
+
+const (
+	timescaleChunkInterval            = int64(24 * 60 * 60 * 1000) // 每个 chunk 覆盖 1 天（毫秒）
+	defaultTimescaleCompressAfterDays = 7
+	timescaleIntegerNowFunc           = "pika_unix_now_ms"
+)
+
+// timeSeriesModels 按 timestamp 持续写入的时序指标表（Host 信息只保留最新的一条，不在其中）
+var timeSeriesModels = []interface{}{
+	&models.CPUMetric{},
+	&models.CPUCoreMetric{},
+	&models.CPUTimesMetric{},
+	&models.PressureMetric{},
+	&models.MemoryMetric{},
+	&models.DiskMetric{},
+	&models.NetworkMetric{},
+	&models.NetworkConnectionMetric{},
+	&models.DiskIOMetric{},
+	&models.GPUMetric{},
+	&models.TemperatureMetric{},
+	&models.MonitorMetric{},
+	&models.PingMetric{},
+}
+
+// SetupTimescaleDB 在 PostgreSQL 上启用 TimescaleDB，将时序指标表转换为 hypertable 并添加压缩策略
+// 返回是否启用；非 PostgreSQL、扩展未安装或配置禁用时返回 false，指标表保持普通表
+func SetupTimescaleDB(ctx context.Context, db *gorm.DB, cfg *config.TimescaleDBConfig) (bool, error) {
+	if db.Dialector.Name() != "postgres" {
+		return false, nil
+	}
+	if cfg != nil && cfg.Disabled {
+		return false, nil
+	}
+
+	db = db.WithContext(ctx)
+	var available int64
+	if err := db.Raw(`SELECT COUNT(*) FROM pg_available_extensions WHERE name = 'timescaledb'`).Scan(&available).Error; err != nil {
+		return false, err
+	}
+	if available == 0 {
+		return false, nil
+	}
+	if err := db.Exec(`CREATE EXTENSION IF NOT EXISTS timescaledb`).Error; err != nil {
+		return false, fmt.Errorf("create extension: %w", err)
+	}
+
+	// timestamp 为毫秒整数，压缩策略需要通过该函数获取当前时间
+	if err := db.Exec(`CREATE OR REPLACE FUNCTION ` + timescaleIntegerNowFunc + `() RETURNS BIGINT
+		LANGUAGE SQL STABLE AS $$ SELECT CAST(EXTRACT(EPOCH FROM NOW()) * 1000 AS BIGINT) $$`).Error; err != nil {
+		return false, fmt.Errorf("create integer now func: %w", err)
+	}
+
+	compressAfterDays := defaultTimescaleCompressAfterDays
+	if cfg != nil && cfg.CompressAfterDays > 0 {
+		compressAfterDays = cfg.CompressAfterDays
+	}
+	compressAfter := int64(compressAfterDays) * timescaleChunkInterval
+
+	for _, model := range timeSeriesModels {
+		table, err := tableName(db, model)
+		if err != nil {
+			return false, err
+		}
+		if err := setupHypertable(db, table, compressAfter); err != nil {
+			return false, fmt.Errorf("%s: %w", table, err)
+		}
+	}
+	return true, nil
+}
+
+// setupHypertable 将单个表转换为 hypertable，已转换的表只补齐压缩配置，可重复执行
+func setupHypertable(db *gorm.DB, table string, compressAfter int64) error {
+	var hypertables []struct {
+		CompressionEnabled bool
+	}
+	if err := db.Raw(`SELECT compression_enabled FROM timescaledb_information.hypertables
+		WHERE hypertable_schema = current_schema() AND hypertable_name = ?`, table).
+		Scan(&hypertables).Error; err != nil {
+		return err
+	}
+
+	if len(hypertables) == 0 {
+		// hypertable 的唯一约束必须包含分区列，主键改为 (id, timestamp)
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(fmt.Sprintf(`ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s_pkey`, table, table)).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec(fmt.Sprintf(`ALTER TABLE %s ADD PRIMARY KEY (id, timestamp)`, table)).Error; err != nil {
+				return err
+			}
+			return tx.Exec(`SELECT create_hypertable(?, 'timestamp', chunk_time_interval => CAST(? AS BIGINT), migrate_data => true)`,
+				table, timescaleChunkInterval).Error
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := db.Exec(`SELECT set_integer_now_func(?, ?, replace_if_exists => true)`, table, timescaleIntegerNowFunc).Error; err != nil {
+		return err
+	}
+	// 已有压缩数据时不能修改压缩配置，只在首次启用时设置
+	if len(hypertables) == 0 || !hypertables[0].CompressionEnabled {
+		if err := db.Exec(fmt.Sprintf(`ALTER TABLE %s SET (timescaledb.compress, timescaledb.compress_segmentby = 'agent_id', timescaledb.compress_orderby = 'timestamp DESC')`, table)).Error; err != nil {
+			return err
+		}
+	}
+	return db.Exec(`SELECT add_compression_policy(?, compress_after => CAST(? AS BIGINT), if_not_exists => true)`, table, compressAfter).Error
+}
+
+// tableName 解析模型对应的表名
+func tableName(db *gorm.DB, model interface{}) (string, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return "", err
+	}
+	return stmt.Schema.Table, nil
+}
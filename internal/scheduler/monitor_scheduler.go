@@ -34,7 +34,7 @@ func NewMonitorScheduler(monitorService *service.MonitorService, logger *zap.Log
 		cron:           cron.New(cron.WithSeconds()), // 支持秒级调度
 		tasks:          make(map[string]*MonitorTask),
 		monitorService: monitorService,
-		logger:         logger,
+		logger:         logger.Named("monitor"),
 	}
 }
 
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 
 	"github.com/dushixiang/pika/internal/config"
@@ -20,18 +21,15 @@ func NewAccountService(logger *zap.Logger, userService *UserService, oidcService
 	if len(jwtSecret) < 32 {
 		logger.Warn("JWT secret is too short, should be at least 32 characters for security")
 	}
-	if tokenExpireHours <= 0 {
-		tokenExpireHours = 168 // 默认7天
-	}
 
 	service := &AccountService{
-		logger:           logger,
-		userService:      userService,
-		oidcService:      oidcService,
-		githubService:    githubService,
-		jwtSecret:        jwtSecret,
-		tokenExpireHours: tokenExpireHours,
+		logger:        logger.Named("auth"),
+		userService:   userService,
+		oidcService:   oidcService,
+		githubService: githubService,
+		jwtSecret:     jwtSecret,
 	}
+	service.SetTokenExpireHours(tokenExpireHours)
 	return service
 }
 
@@ -41,7 +39,15 @@ type AccountService struct {
 	oidcService      *OIDCService
 	githubService    *GitHubOAuthService
 	jwtSecret        string
-	tokenExpireHours int
+	tokenExpireHours atomic.Int64
+}
+
+// SetTokenExpireHours 设置 token 有效期（小时），只影响之后签发的 token
+func (s *AccountService) SetTokenExpireHours(hours int) {
+	if hours <= 0 {
+		hours = 168 // 默认7天
+	}
+	s.tokenExpireHours.Store(int64(hours))
 }
 
 // JWTClaims JWT 声明
@@ -114,7 +120,7 @@ func (s *AccountService) LoginWithOIDC(ctx context.Context, code, state string)
 
 // generateToken 生成 JWT token
 func (s *AccountService) generateToken(username, nickname string) (string, int64, error) {
-	expiresAt := time.Now().Add(time.Duration(s.tokenExpireHours) * time.Hour)
+	expiresAt := time.Now().Add(time.Duration(s.tokenExpireHours.Load()) * time.Hour)
 	claims := &JWTClaims{
 		UserID:   username, // 使用 username 作为 userID
 		Username: username,
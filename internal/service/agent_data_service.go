@@ -0,0 +1,114 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/repo"
+	"github.com/go-orz/orz"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// AgentDataService 探针数据导出与清除服务，用于服务器转让等数据处置场景
+type AgentDataService struct {
+	logger        *zap.Logger
+	agentDataRepo *repo.AgentDataRepo
+	agentService  *AgentService
+	metricService *MetricService
+}
+
+func NewAgentDataService(logger *zap.Logger, db *gorm.DB, agentService *AgentService, metricService *MetricService) *AgentDataService {
+	return &AgentDataService{
+		logger:        logger.Named("agent"),
+		agentDataRepo: repo.NewAgentDataRepo(db),
+		agentService:  agentService,
+		metricService: metricService,
+	}
+}
+
+// AgentDataManifest 导出包中的清单信息
+type AgentDataManifest struct {
+	Agent      models.Agent     `json:"agent"`
+	ExportedAt int64            `json:"exportedAt"`
+	Tables     map[string]int64 `json:"tables"` // 每张表导出的行数
+}
+
+// Export 将探针的所有数据导出为 zip 包，每张表一个 JSON Lines 文件，另附 manifest.json
+func (s *AgentDataService) Export(ctx context.Context, agentID string, w io.Writer) (*AgentDataManifest, error) {
+	agent, err := s.agentService.GetAgent(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &AgentDataManifest{
+		Agent:      *agent,
+		ExportedAt: time.Now().UnixMilli(),
+		Tables:     make(map[string]int64),
+	}
+
+	zw := zip.NewWriter(w)
+	for _, table := range s.agentDataRepo.TableNames() {
+		f, err := zw.Create(table + ".jsonl")
+		if err != nil {
+			return nil, err
+		}
+		encoder := json.NewEncoder(f)
+
+		var count int64
+		err = s.agentDataRepo.EachRow(ctx, table, agentID, func(row map[string]interface{}) error {
+			count++
+			return encoder.Encode(row)
+		})
+		if err != nil {
+			s.logger.Error("导出探针数据失败", zap.String("agentId", agentID), zap.String("table", table), zap.Error(err))
+			return nil, err
+		}
+		manifest.Tables[table] = count
+	}
+
+	f, err := zw.Create("manifest.json")
+	if err != nil {
+		return nil, err
+	}
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return nil, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// ExportAndPurge 先完整导出探针数据，导出成功后再不可逆地清除这些数据
+// 探针本身的注册信息会保留，需要彻底移除探针时请使用删除探针
+func (s *AgentDataService) ExportAndPurge(ctx context.Context, agentID, confirm string, w io.Writer) (*AgentDataManifest, error) {
+	if confirm != agentID {
+		return nil, orz.NewError(400, "请输入探针ID确认清除操作")
+	}
+
+	manifest, err := s.Export(ctx, agentID, w)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := s.agentDataRepo.Purge(ctx, agentID)
+	if err != nil {
+		s.logger.Error("清除探针数据失败", zap.String("agentId", agentID), zap.Error(err))
+		return nil, err
+	}
+	s.metricService.ClearLatestMetrics(agentID)
+
+	s.logger.Info("探针数据已导出并清除",
+		zap.String("agentId", agentID),
+		zap.String("agentName", manifest.Agent.Name),
+		zap.Any("deleted", deleted))
+	return manifest, nil
+}
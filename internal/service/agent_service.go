@@ -11,6 +11,7 @@ import (
 	"github.com/dushixiang/pika/internal/models"
 	"github.com/dushixiang/pika/internal/protocol"
 	"github.com/dushixiang/pika/internal/repo"
+	"github.com/dushixiang/pika/pkg/tracing"
 	"github.com/go-orz/orz"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -21,25 +22,61 @@ type AgentService struct {
 	*orz.Service
 	AgentRepo        *repo.AgentRepo
 	monitorStatsRepo *repo.MonitorStatsRepo
+	monitorCertRepo  *repo.MonitorCertRepo
+	monitorTraceRepo *repo.MonitorTraceRepo
+	softwareRepo     *repo.SoftwareRepo
+	imageRepo        *repo.ContainerImageRepo
+	findingRepo      *repo.SecurityFindingRepo
+	pingTargetRepo   *repo.PingTargetRepo
+	sessionRepo      *repo.AgentSessionRepo
 	apiKeyService    *ApiKeyService
+	remediationSvc   *RemediationService
+	powerSvc         *PowerService
+	diskUsageSvc     *DiskUsageService
+	commandSvc       *CommandService
+	processSvc       *ProcessService
+	dockerSvc        *DockerService
 	metricService    *MetricService
 	geoipService     *GeoIPService
+	eventNotifier    *EventNotifier
 }
 
-func NewAgentService(logger *zap.Logger, db *gorm.DB, apiKeyService *ApiKeyService, metricService *MetricService, geoipService *GeoIPService) *AgentService {
+func NewAgentService(logger *zap.Logger, db *gorm.DB, apiKeyService *ApiKeyService, metricService *MetricService, geoipService *GeoIPService,
+	remediationService *RemediationService, powerService *PowerService, diskUsageService *DiskUsageService, commandService *CommandService,
+	processService *ProcessService, dockerService *DockerService, eventNotifier *EventNotifier) *AgentService {
 	return &AgentService{
-		logger:           logger,
+		logger:           logger.Named("agent"),
 		Service:          orz.NewService(db),
 		AgentRepo:        repo.NewAgentRepo(db),
 		monitorStatsRepo: repo.NewMonitorStatsRepo(db),
+		monitorCertRepo:  repo.NewMonitorCertRepo(db),
+		monitorTraceRepo: repo.NewMonitorTraceRepo(db),
+		softwareRepo:     repo.NewSoftwareRepo(db),
+		imageRepo:        repo.NewContainerImageRepo(db),
+		findingRepo:      repo.NewSecurityFindingRepo(db),
+		pingTargetRepo:   repo.NewPingTargetRepo(db),
+		sessionRepo:      repo.NewAgentSessionRepo(db),
 		apiKeyService:    apiKeyService,
 		metricService:    metricService,
 		geoipService:     geoipService,
+		remediationSvc:   remediationService,
+		powerSvc:         powerService,
+		diskUsageSvc:     diskUsageService,
+		commandSvc:       commandService,
+		processSvc:       processService,
+		dockerSvc:        dockerService,
+		eventNotifier:    eventNotifier,
 	}
 }
 
 // RegisterAgent 注册探针
 func (s *AgentService) RegisterAgent(ctx context.Context, ip string, info *protocol.AgentInfo, apiKey string) (*models.Agent, error) {
+	ctx, span := tracing.Start(ctx, "AgentService.RegisterAgent", tracing.WithAttributes(
+		tracing.String("agent.id", info.ID),
+		tracing.String("agent.version", info.Version),
+	))
+	defer span.End()
+
 	// 验证API密钥
 	if _, err := s.apiKeyService.ValidateApiKey(ctx, apiKey); err != nil {
 		s.logger.Warn("agent registration failed: invalid api key",
@@ -107,6 +144,8 @@ func (s *AgentService) RegisterAgent(ctx context.Context, ip string, info *proto
 		zap.String("hostname", info.Hostname),
 		zap.String("ip", ip),
 		zap.String("version", info.Version))
+	s.eventNotifier.Notify(agent.ID, models.NotificationEventAgentRegister, "info",
+		fmt.Sprintf("新探针 %s（%s）已注册，版本 %s", agent.Name, agent.Hostname, agent.Version))
 	return agent, nil
 }
 
@@ -115,6 +154,11 @@ func (s *AgentService) UpdateAgentStatus(ctx context.Context, agentID string, st
 	return s.AgentRepo.UpdateStatus(ctx, agentID, status, time.Now().UnixMilli())
 }
 
+// MarkAgentOffline 将探针标记为离线，lastSeenAt 为连接上最后一次收到消息的时间
+func (s *AgentService) MarkAgentOffline(ctx context.Context, agentID string, lastSeenAt int64) error {
+	return s.AgentRepo.UpdateStatus(ctx, agentID, 0, lastSeenAt)
+}
+
 // GetAgent 获取探针信息
 func (s *AgentService) GetAgent(ctx context.Context, agentID string) (*models.Agent, error) {
 	agent, err := s.AgentRepo.FindById(ctx, agentID)
@@ -129,6 +173,38 @@ func (s *AgentService) ListAgents(ctx context.Context) ([]models.Agent, error) {
 	return s.AgentRepo.FindAll(ctx)
 }
 
+// AgentInventory 探针资产清单条目
+type AgentInventory struct {
+	models.Agent
+	MonthlySentBytes float64 `json:"monthlySentBytes"` // 本月发送流量（字节，按小时平均速率估算）
+	MonthlyRecvBytes float64 `json:"monthlyRecvBytes"` // 本月接收流量（字节，按小时平均速率估算）
+}
+
+// ListAgentInventory 列出所有探针的资产信息及本月流量
+func (s *AgentService) ListAgentInventory(ctx context.Context) ([]AgentInventory, error) {
+	agents, err := s.AgentRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).UnixMilli()
+
+	items := make([]AgentInventory, 0, len(agents))
+	for _, agent := range agents {
+		item := AgentInventory{Agent: agent}
+		sent, recv, err := s.metricService.GetTraffic(ctx, agent.ID, monthStart, now.UnixMilli())
+		if err != nil {
+			s.logger.Warn("failed to get monthly traffic", zap.String("agentID", agent.ID), zap.Error(err))
+		} else {
+			item.MonthlySentBytes = sent
+			item.MonthlyRecvBytes = recv
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
 // ListOnlineAgents 列出所有在线探针
 func (s *AgentService) ListOnlineAgents(ctx context.Context) ([]models.Agent, error) {
 	return s.AgentRepo.FindOnlineAgents(ctx)
@@ -146,6 +222,34 @@ func (s *AgentService) HandleCommandResponse(ctx context.Context, agentID string
 	switch resp.Type {
 	case "vps_audit":
 		return s.handleVPSAuditResponse(ctx, agentID, resp)
+	case "remediation":
+		return s.remediationSvc.HandleCommandResponse(ctx, agentID, resp)
+	case PowerActionReboot, PowerActionWakeOnLAN:
+		return s.powerSvc.HandleCommandResponse(ctx, agentID, resp)
+	case "disk_usage":
+		return s.diskUsageSvc.HandleCommandResponse(ctx, agentID, resp)
+	case "remote_command":
+		return s.commandSvc.HandleCommandResponse(ctx, agentID, resp)
+	case "process_kill":
+		return s.processSvc.HandleCommandResponse(ctx, agentID, resp)
+	case "process_list":
+		// 进程列表通过 process_snapshot 消息推送，这里无需处理
+		return nil
+	case "docker_list", "docker_action":
+		return s.dockerSvc.HandleCommandResponse(ctx, agentID, resp)
+	case "log_tail":
+		// 日志内容通过 log_tail_chunk 消息推送，这里无需处理
+		return nil
+	case "terminal":
+		// 终端输出和结束状态通过 terminal_output 消息推送，这里无需处理
+		return nil
+	case "log_level":
+		if resp.Status == "success" {
+			s.logger.Info("agent log levels updated", zap.String("agentID", agentID), zap.String("levels", resp.Result))
+		} else if resp.Status == "error" {
+			s.logger.Warn("failed to update agent log levels", zap.String("agentID", agentID), zap.String("error", resp.Error))
+		}
+		return nil
 	default:
 		s.logger.Warn("unknown command type", zap.String("type", resp.Type))
 		return nil
@@ -355,6 +459,14 @@ func (s *AgentService) DeleteAgent(ctx context.Context, agentID string) error {
 			s.logger.Error("删除探针监控统计数据失败", zap.String("agentId", agentID), zap.Error(err))
 			return err
 		}
+		if err := s.monitorCertRepo.DeleteByAgentId(ctx, agentID); err != nil {
+			s.logger.Error("删除探针证书信息失败", zap.String("agentId", agentID), zap.Error(err))
+			return err
+		}
+		if err := s.monitorTraceRepo.DeleteByAgentId(ctx, agentID); err != nil {
+			s.logger.Error("删除探针路径追踪失败", zap.String("agentId", agentID), zap.Error(err))
+			return err
+		}
 
 		// 3. 删除探针的审计结果
 		if err := s.AgentRepo.DeleteAuditResults(ctx, agentID); err != nil {
@@ -362,7 +474,47 @@ func (s *AgentService) DeleteAgent(ctx context.Context, agentID string) error {
 			return err
 		}
 
-		// 4. 最后删除探针本身
+		// 4. 删除探针的软件清单
+		if err := s.softwareRepo.DeleteByAgentID(ctx, agentID); err != nil {
+			s.logger.Error("删除探针软件清单失败", zap.String("agentId", agentID), zap.Error(err))
+			return err
+		}
+		if err := s.imageRepo.DeleteByAgentID(ctx, agentID); err != nil {
+			s.logger.Error("删除探针容器镜像失败", zap.String("agentId", agentID), zap.Error(err))
+			return err
+		}
+
+		// 5. 删除探针的安全漏洞发现
+		if err := s.findingRepo.DeleteByAgentID(ctx, agentID); err != nil {
+			s.logger.Error("删除探针安全漏洞发现失败", zap.String("agentId", agentID), zap.Error(err))
+			return err
+		}
+
+		// 6. 删除探针的修复动作记录
+		if err := s.remediationSvc.RemediationRepo.DeleteByAgentID(ctx, agentID); err != nil {
+			s.logger.Error("删除探针修复动作记录失败", zap.String("agentId", agentID), zap.Error(err))
+			return err
+		}
+
+		// 7. 删除探针的电源操作任务
+		if err := s.powerSvc.PowerTaskRepo.DeleteByAgentID(ctx, agentID); err != nil {
+			s.logger.Error("删除探针电源操作任务失败", zap.String("agentId", agentID), zap.Error(err))
+			return err
+		}
+
+		// 8. 删除探针的 Ping 目标
+		if err := s.pingTargetRepo.DeleteByAgentID(ctx, agentID); err != nil {
+			s.logger.Error("删除探针Ping目标失败", zap.String("agentId", agentID), zap.Error(err))
+			return err
+		}
+
+		// 9. 删除探针的连接会话
+		if err := s.sessionRepo.DeleteByAgentID(ctx, agentID); err != nil {
+			s.logger.Error("删除探针连接会话失败", zap.String("agentId", agentID), zap.Error(err))
+			return err
+		}
+
+		// 10. 最后删除探针本身
 		if err := s.AgentRepo.DeleteById(ctx, agentID); err != nil {
 			s.logger.Error("删除探针失败", zap.String("agentId", agentID), zap.Error(err))
 			return err
@@ -410,6 +562,10 @@ func (s *AgentService) InitStatus(ctx context.Context) error {
 		return err
 	}
 	for _, agent := range agents {
+		// 服务重启前未结束的会话以最后在线时间作为断开时间
+		if err := s.sessionRepo.CloseOpenByAgentID(ctx, agent.ID, agent.LastSeenAt); err != nil {
+			return err
+		}
 		if err := s.AgentRepo.UpdateStatus(ctx, agent.ID, 0, 0); err != nil {
 			return err
 		}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"go.uber.org/zap"
+)
+
+// agentSessionRetention 连接会话保留时间
+const agentSessionRetention = 90 * 24 * time.Hour
+
+// AgentDowntime 探针的一段离线时间
+type AgentDowntime struct {
+	Start    int64 `json:"start"`    // 开始时间（时间戳毫秒）
+	End      int64 `json:"end"`      // 结束时间（时间戳毫秒）
+	Duration int64 `json:"duration"` // 持续时间（毫秒）
+}
+
+// AgentAvailability 探针在时间范围内的可用率统计
+type AgentAvailability struct {
+	AgentID         string                `json:"agentId"`
+	Start           int64                 `json:"start"`           // 统计开始时间，早于首次连接时从首次连接开始统计
+	End             int64                 `json:"end"`             // 统计结束时间
+	Uptime          float64               `json:"uptime"`          // 在线率(百分比)
+	OnlineDuration  int64                 `json:"onlineDuration"`  // 在线时长（毫秒）
+	OfflineDuration int64                 `json:"offlineDuration"` // 离线时长（毫秒）
+	Sessions        []models.AgentSession `json:"sessions"`        // 时间范围内的连接会话
+	Downtimes       []AgentDowntime       `json:"downtimes"`       // 时间范围内的离线时间段
+}
+
+// OpenSession 探针连接后创建连接会话
+func (s *AgentService) OpenSession(ctx context.Context, agent *models.Agent) (*models.AgentSession, error) {
+	session := &models.AgentSession{
+		AgentID:     agent.ID,
+		IP:          agent.IP,
+		Version:     agent.Version,
+		ConnectedAt: time.Now().UnixMilli(),
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// CloseSession 探针断开后结束连接会话，disconnectedAt 为连接最后活跃的时间
+func (s *AgentService) CloseSession(ctx context.Context, sessionID int64, disconnectedAt int64) error {
+	return s.sessionRepo.Close(ctx, sessionID, disconnectedAt)
+}
+
+// CleanupSessions 清理过期的连接会话
+func (s *AgentService) CleanupSessions(ctx context.Context) {
+	before := time.Now().Add(-agentSessionRetention).UnixMilli()
+	deleted, err := s.sessionRepo.DeleteBefore(ctx, before)
+	if err != nil {
+		s.logger.Error("清理探针连接会话失败", zap.Error(err))
+		return
+	}
+	if deleted > 0 {
+		s.logger.Info("已清理过期的探针连接会话", zap.Int64("deleted", deleted))
+	}
+}
+
+// GetAvailability 统计探针在时间范围内的在线率和离线时间段
+// 统计从探针首次有连接记录开始，之前的时间不计为离线
+func (s *AgentService) GetAvailability(ctx context.Context, agentID string, start, end int64) (*AgentAvailability, error) {
+	result := &AgentAvailability{
+		AgentID:   agentID,
+		Start:     start,
+		End:       end,
+		Sessions:  []models.AgentSession{},
+		Downtimes: []AgentDowntime{},
+	}
+
+	firstConnectedAt, err := s.sessionRepo.FindFirstConnectedAt(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	if firstConnectedAt == 0 || firstConnectedAt >= end {
+		result.Start = end
+		return result, nil
+	}
+	if firstConnectedAt > start {
+		result.Start = firstConnectedAt
+	}
+
+	sessions, err := s.sessionRepo.FindByAgentIDBetween(ctx, agentID, result.Start, end)
+	if err != nil {
+		return nil, err
+	}
+	result.Sessions = sessions
+
+	// 截取到统计范围内，未结束的会话视为持续到统计结束
+	intervals := make([][2]int64, 0, len(sessions))
+	for _, session := range sessions {
+		from, to := session.ConnectedAt, session.DisconnectedAt
+		if to == 0 || to > end {
+			to = end
+		}
+		if from < result.Start {
+			from = result.Start
+		}
+		if to > from {
+			intervals = append(intervals, [2]int64{from, to})
+		}
+	}
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i][0] < intervals[j][0]
+	})
+
+	// 重连时新旧会话可能短暂重叠，合并后再计算在线时长和离线间隔
+	cursor := result.Start
+	for _, interval := range intervals {
+		if interval[0] > cursor {
+			result.Downtimes = append(result.Downtimes, AgentDowntime{
+				Start:    cursor,
+				End:      interval[0],
+				Duration: interval[0] - cursor,
+			})
+		}
+		if interval[1] > cursor {
+			result.OnlineDuration += interval[1] - max(interval[0], cursor)
+			cursor = interval[1]
+		}
+	}
+	if cursor < end {
+		result.Downtimes = append(result.Downtimes, AgentDowntime{
+			Start:    cursor,
+			End:      end,
+			Duration: end - cursor,
+		})
+	}
+
+	total := end - result.Start
+	result.OfflineDuration = total - result.OnlineDuration
+	if total > 0 {
+		result.Uptime = float64(result.OnlineDuration) / float64(total) * 100
+	}
+	return result, nil
+}
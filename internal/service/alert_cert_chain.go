@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"go.uber.org/zap"
+)
+
+// checkCertChainAlerts 检查证书链告警，证书链校验失败（如自签名、缺少中间证书、域名不匹配）即告警，不要求证书已过期
+func (s *AlertService) checkCertChainAlerts(ctx context.Context, config *models.AlertConfig, now int64) error {
+	certs, err := s.monitorCertRepo.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	tasks, err := s.monitorRepo.FindByEnabled(ctx, true)
+	if err != nil {
+		return err
+	}
+	monitors := make(map[string]models.MonitorTask, len(tasks))
+	for _, task := range tasks {
+		monitors[task.ID] = task
+	}
+
+	for _, cert := range certs {
+		agent, err := s.agentRepo.FindById(ctx, cert.AgentID)
+		if err != nil {
+			s.logger.Error("获取探针信息失败", zap.String("agentId", cert.AgentID), zap.Error(err))
+			continue
+		}
+
+		// 已停用的监控项和不再上报的证书只做恢复
+		monitor, ok := monitors[cert.MonitorId]
+		if ok && certChainFailing(cert, time.UnixMilli(now)) {
+			s.checkCertChainAlert(ctx, config, &agent, monitor, cert, now)
+		} else {
+			s.resolveCertChainAlert(ctx, &agent, cert)
+		}
+	}
+	return nil
+}
+
+// checkCertChainAlert 检查并触发证书链告警
+func (s *AlertService) checkCertChainAlert(ctx context.Context, config *models.AlertConfig, agent *models.Agent, monitor models.MonitorTask, cert models.MonitorCert, now int64) {
+	stateKey := fmt.Sprintf("%s:global:cert_chain:%s", agent.ID, cert.MonitorId)
+
+	state, err := s.AlertStateRepo.GetAlertState(ctx, stateKey)
+	if err != nil {
+		state = &models.AlertState{
+			ID:        stateKey,
+			AgentID:   agent.ID,
+			AlertType: "cert_chain",
+		}
+	}
+	state.AgentID = agent.ID
+	state.AlertType = "cert_chain"
+	state.Threshold = 0
+	state.Duration = 0
+	state.Value = 0
+	state.LastCheckTime = now
+
+	shouldFire := !state.IsFiring
+	if shouldFire {
+		state.IsFiring = true
+	}
+
+	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+		s.logger.Error("保存告警状态失败", zap.Error(err))
+	}
+
+	if !shouldFire {
+		return
+	}
+
+	s.logger.Info("触发证书链告警",
+		zap.String("agentId", agent.ID),
+		zap.String("monitorId", cert.MonitorId),
+		zap.String("issuer", cert.Issuer),
+		zap.String("chainError", cert.ChainError),
+	)
+
+	record := &models.AlertRecord{
+		AgentID:     agent.ID,
+		AgentName:   agent.Name,
+		AlertType:   "cert_chain",
+		Threshold:   0,
+		ActualValue: 0,
+		Level:       "warning",
+		Status:      "firing",
+		FiredAt:     now,
+		CreatedAt:   now,
+	}
+	setAlertMessage(record, "cert_chain", map[string]string{
+		"monitor": monitor.Name,
+		"error":   cert.ChainError,
+		"issuer":  cert.Issuer,
+	})
+
+	if err := s.saveFiringRecord(ctx, config, state, record); err != nil {
+		s.logger.Error("创建证书链告警记录失败", zap.Error(err))
+		return
+	}
+
+	state.LastRecordID = record.ID
+	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+		s.logger.Error("保存告警状态失败", zap.Error(err))
+	}
+
+	go s.sendAlertNotification(record, agent)
+	go s.remediationSvc.Trigger(config, record)
+}
+
+// resolveCertChainAlert 恢复证书链告警
+func (s *AlertService) resolveCertChainAlert(ctx context.Context, agent *models.Agent, cert models.MonitorCert) {
+	stateKey := fmt.Sprintf("%s:global:cert_chain:%s", agent.ID, cert.MonitorId)
+
+	state, err := s.AlertStateRepo.GetAlertState(ctx, stateKey)
+	if err != nil || !state.IsFiring {
+		return
+	}
+
+	s.logger.Info("证书链告警恢复",
+		zap.String("agentId", agent.ID),
+		zap.String("monitorId", cert.MonitorId),
+	)
+
+	if state.LastRecordID > 0 {
+		existingRecord, err := s.AlertRecordRepo.GetAlertRecordByID(ctx, state.LastRecordID)
+		if err != nil {
+			s.logger.Error("获取证书链告警记录失败", zap.Error(err))
+		} else if existingRecord != nil && existingRecord.Status == "firing" {
+			now := time.Now().UnixMilli()
+			existingRecord.Status = "resolved"
+			existingRecord.ResolvedAt = now
+			existingRecord.UpdatedAt = now
+
+			if err := s.AlertRecordRepo.UpdateAlertRecord(ctx, existingRecord); err != nil {
+				s.logger.Error("更新证书链告警记录失败", zap.Error(err))
+			} else {
+				go s.sendAlertNotification(existingRecord, agent)
+			}
+		}
+	}
+
+	state.IsFiring = false
+	markResolved(state)
+	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+		s.logger.Error("保存告警状态失败", zap.Error(err))
+	}
+}
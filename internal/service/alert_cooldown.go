@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"go.uber.org/zap"
+)
+
+// alertRecordStore 冷却期合并用到的告警记录存储
+type alertRecordStore interface {
+	CreateAlertRecord(ctx context.Context, record *models.AlertRecord) error
+	UpdateAlertRecord(ctx context.Context, record *models.AlertRecord) error
+	GetAlertRecordByID(ctx context.Context, id int64) (*models.AlertRecord, error)
+}
+
+// markResolved 记录恢复的告警记录，供冷却期内再次触发时合并
+func markResolved(state *models.AlertState) {
+	if state.LastRecordID > 0 {
+		state.ResolvedRecordID = state.LastRecordID
+		state.ResolvedAt = time.Now().UnixMilli()
+	}
+	state.LastRecordID = 0
+}
+
+// saveFiringRecord 保存触发的告警记录
+// 同一状态在恢复后的冷却期内再次触发时，重新打开上一条记录（保留首次触发时间），避免阈值附近抖动的指标产生大量记录；
+// 此前已经发送过恢复通知，调用方仍需发送通知和执行修复动作，记录中保存距上次恢复的秒数，通知时注明是再次触发
+func (s *AlertService) saveFiringRecord(ctx context.Context, config *models.AlertConfig, state *models.AlertState, record *models.AlertRecord) error {
+	merged, err := saveFiringRecord(ctx, s.AlertRecordRepo, config, state, record)
+	if err != nil {
+		return err
+	}
+	if merged {
+		s.logger.Info("告警在冷却期内再次触发，合并到上一条记录",
+			zap.String("stateKey", state.ID),
+			zap.Int64("recordId", record.ID),
+		)
+	}
+	return nil
+}
+
+// saveFiringRecord 合并到冷却期内的上一条记录或新建记录，返回是否合并
+func saveFiringRecord(ctx context.Context, store alertRecordStore, config *models.AlertConfig, state *models.AlertState, record *models.AlertRecord) (bool, error) {
+	previous := cooldownRecord(ctx, store, config, state, record.FiredAt)
+	if previous == nil {
+		return false, store.CreateAlertRecord(ctx, record)
+	}
+
+	record.RefiredAfter = (record.FiredAt - state.ResolvedAt) / 1000
+	record.ID = previous.ID
+	record.FiredAt = previous.FiredAt
+	record.CreatedAt = previous.CreatedAt
+	record.ResolvedAt = 0
+	if err := store.UpdateAlertRecord(ctx, record); err != nil {
+		return false, err
+	}
+	state.ResolvedRecordID = 0
+	state.ResolvedAt = 0
+	return true, nil
+}
+
+// cooldownRecord 返回冷却期内可合并的上一条已恢复记录，没有时返回 nil
+func cooldownRecord(ctx context.Context, store alertRecordStore, config *models.AlertConfig, state *models.AlertState, now int64) *models.AlertRecord {
+	if config == nil || state.ResolvedRecordID == 0 {
+		return nil
+	}
+	cooldown := config.Rules.Cooldowns[state.AlertType]
+	if cooldown <= 0 || now-state.ResolvedAt > int64(cooldown)*1000 {
+		return nil
+	}
+
+	previous, err := store.GetAlertRecordByID(ctx, state.ResolvedRecordID)
+	if err != nil {
+		// 记录可能已被清理，按新告警处理
+		return nil
+	}
+	if previous.Status != "resolved" || previous.AlertType != state.AlertType {
+		return nil
+	}
+	return previous
+}
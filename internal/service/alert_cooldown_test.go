@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+// fakeAlertRecordStore 内存中的告警记录存储
+type fakeAlertRecordStore struct {
+	records map[int64]*models.AlertRecord
+	nextID  int64
+}
+
+func (f *fakeAlertRecordStore) CreateAlertRecord(_ context.Context, record *models.AlertRecord) error {
+	f.nextID++
+	record.ID = f.nextID
+	copied := *record
+	f.records[record.ID] = &copied
+	return nil
+}
+
+func (f *fakeAlertRecordStore) UpdateAlertRecord(_ context.Context, record *models.AlertRecord) error {
+	copied := *record
+	f.records[record.ID] = &copied
+	return nil
+}
+
+func (f *fakeAlertRecordStore) GetAlertRecordByID(_ context.Context, id int64) (*models.AlertRecord, error) {
+	record, ok := f.records[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	copied := *record
+	return &copied, nil
+}
+
+func TestCooldownRecord(t *testing.T) {
+	const resolvedAt = int64(1_000_000)
+	config := &models.AlertConfig{Rules: models.AlertRules{Cooldowns: map[string]int{"cpu": 300}}}
+
+	tests := []struct {
+		name      string
+		config    *models.AlertConfig
+		record    *models.AlertRecord // 上一条记录，nil 表示已被清理
+		alertType string
+		now       int64
+		wantMerge bool
+	}{
+		{"冷却期内", config, &models.AlertRecord{ID: 1, AlertType: "cpu", Status: "resolved"}, "cpu", resolvedAt + 60_000, true},
+		{"刚好到冷却期结束", config, &models.AlertRecord{ID: 1, AlertType: "cpu", Status: "resolved"}, "cpu", resolvedAt + 300_000, true},
+		{"超过冷却期", config, &models.AlertRecord{ID: 1, AlertType: "cpu", Status: "resolved"}, "cpu", resolvedAt + 300_001, false},
+		{"告警类型不一致", config, &models.AlertRecord{ID: 1, AlertType: "memory", Status: "resolved"}, "cpu", resolvedAt + 60_000, false},
+		{"上一条记录已被清理", config, nil, "cpu", resolvedAt + 60_000, false},
+		{"上一条记录仍在告警", config, &models.AlertRecord{ID: 1, AlertType: "cpu", Status: "firing"}, "cpu", resolvedAt + 60_000, false},
+		{"未配置冷却期", &models.AlertConfig{}, &models.AlertRecord{ID: 1, AlertType: "cpu", Status: "resolved"}, "cpu", resolvedAt + 60_000, false},
+	}
+	for _, tt := range tests {
+		store := &fakeAlertRecordStore{records: map[int64]*models.AlertRecord{}}
+		if tt.record != nil {
+			store.records[tt.record.ID] = tt.record
+		}
+		state := &models.AlertState{AlertType: tt.alertType, ResolvedRecordID: 1, ResolvedAt: resolvedAt}
+		got := cooldownRecord(context.Background(), store, tt.config, state, tt.now)
+		if (got != nil) != tt.wantMerge {
+			t.Errorf("%s: cooldownRecord = %v, 期望合并 %v", tt.name, got, tt.wantMerge)
+		}
+	}
+}
+
+func TestSaveFiringRecord(t *testing.T) {
+	const resolvedAt = int64(1_000_000)
+	config := &models.AlertConfig{Rules: models.AlertRules{Cooldowns: map[string]int{"cpu": 300}}}
+	newStore := func() *fakeAlertRecordStore {
+		return &fakeAlertRecordStore{
+			records: map[int64]*models.AlertRecord{
+				7: {ID: 7, AlertType: "cpu", Status: "resolved", FiredAt: 900_000, CreatedAt: 900_000, ResolvedAt: resolvedAt},
+			},
+			nextID: 7,
+		}
+	}
+
+	t.Run("冷却期内合并到上一条记录", func(t *testing.T) {
+		store := newStore()
+		state := &models.AlertState{AlertType: "cpu", ResolvedRecordID: 7, ResolvedAt: resolvedAt}
+		record := &models.AlertRecord{AlertType: "cpu", Status: "firing", Message: "CPU使用率过高", FiredAt: resolvedAt + 30_000}
+
+		merged, err := saveFiringRecord(context.Background(), store, config, state, record)
+		if err != nil || !merged {
+			t.Fatalf("saveFiringRecord = %v, %v, 期望合并", merged, err)
+		}
+		saved := store.records[7]
+		if record.ID != 7 || saved.Status != "firing" || saved.FiredAt != 900_000 || saved.ResolvedAt != 0 {
+			t.Fatalf("合并后的记录错误: %+v", saved)
+		}
+		if saved.Message != "CPU使用率过高" || saved.RefiredAfter != 30 {
+			t.Fatalf("合并后的消息 = %q, 距上次恢复 %d 秒", saved.Message, saved.RefiredAfter)
+		}
+		if got := notificationLocales["en"].alertMessage(saved); got != "CPU使用率过高 (fired again 30s after recovery)" {
+			t.Fatalf("通知中的消息 = %q", got)
+		}
+		if state.ResolvedRecordID != 0 || state.ResolvedAt != 0 {
+			t.Fatalf("合并后应清除状态中的恢复记录: %+v", state)
+		}
+	})
+
+	tests := []struct {
+		name      string
+		alertType string
+		firedAt   int64
+		deleted   bool
+	}{
+		{"超过冷却期新建记录", "cpu", resolvedAt + 600_000, false},
+		{"告警类型不一致新建记录", "memory", resolvedAt + 30_000, false},
+		{"上一条记录已被清理新建记录", "cpu", resolvedAt + 30_000, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newStore()
+			if tt.deleted {
+				delete(store.records, 7)
+			}
+			state := &models.AlertState{AlertType: tt.alertType, ResolvedRecordID: 7, ResolvedAt: resolvedAt}
+			record := &models.AlertRecord{AlertType: tt.alertType, Status: "firing", Message: "告警", FiredAt: tt.firedAt}
+
+			merged, err := saveFiringRecord(context.Background(), store, config, state, record)
+			if err != nil || merged {
+				t.Fatalf("saveFiringRecord = %v, %v, 期望新建记录", merged, err)
+			}
+			if record.ID != 8 || record.Message != "告警" || record.RefiredAfter != 0 || record.FiredAt != tt.firedAt {
+				t.Fatalf("新建的记录错误: %+v", record)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/repo"
+	"github.com/go-orz/orz"
+	"go.uber.org/zap"
+)
+
+const (
+	// alertCorrelationWindow 关联上下文的时间窗口，取告警触发时间前后各 15 分钟
+	alertCorrelationWindow = 15 * time.Minute
+	// alertCorrelationInterval 指标快照的聚合粒度（秒）
+	alertCorrelationInterval = 60
+)
+
+// AlertCorrelation 告警关联上下文，供告警详情页一次性展示
+type AlertCorrelation struct {
+	Alert         models.AlertRecord         `json:"alert"`
+	Agent         *models.Agent              `json:"agent,omitempty"` // 分组告警、系统告警没有对应的探针
+	WindowStart   int64                      `json:"windowStart"`     // 关联窗口开始时间（毫秒）
+	WindowEnd     int64                      `json:"windowEnd"`       // 关联窗口结束时间（毫秒）
+	RelatedAlerts []models.AlertRecord       `json:"relatedAlerts"`   // 同一探针在窗口内触发的其他告警
+	Metrics       *AlertMetricSnapshot       `json:"metrics,omitempty"`
+	Remediations  []models.RemediationRecord `json:"remediations"` // 本告警触发的修复动作及窗口内的其他修复动作
+	PowerTasks    []models.PowerTask         `json:"powerTasks"`   // 窗口内计划执行的电源操作
+	Sessions      []models.AgentSession      `json:"sessions"`     // 与窗口有交集的连接会话，用于标注上线和断线
+}
+
+// AlertMetricSnapshot 告警触发前后的指标快照
+type AlertMetricSnapshot struct {
+	Interval          int                                      `json:"interval"` // 聚合粒度（秒）
+	CPU               []repo.AggregatedCPUMetric               `json:"cpu"`
+	Memory            []repo.AggregatedMemoryMetric            `json:"memory"`
+	Disk              []repo.AggregatedDiskMetric              `json:"disk"`
+	Network           []repo.AggregatedNetworkMetric           `json:"network"`
+	NetworkConnection []repo.AggregatedNetworkConnectionMetric `json:"networkConnection"`
+}
+
+// GetAlertCorrelation 获取告警的关联上下文：同一探针前后 15 分钟内的其他告警、指标快照、修复动作、电源操作和连接会话
+func (s *AlertService) GetAlertCorrelation(ctx context.Context, recordID int64) (*AlertCorrelation, error) {
+	record, err := s.AlertRecordRepo.GetAlertRecordByID(ctx, recordID)
+	if err != nil {
+		return nil, orz.NewError(404, "告警记录不存在")
+	}
+
+	window := alertCorrelationWindow.Milliseconds()
+	result := &AlertCorrelation{
+		Alert:         *record,
+		WindowStart:   record.FiredAt - window,
+		WindowEnd:     record.FiredAt + window,
+		RelatedAlerts: []models.AlertRecord{},
+		Remediations:  []models.RemediationRecord{},
+		PowerTasks:    []models.PowerTask{},
+		Sessions:      []models.AgentSession{},
+	}
+
+	related, err := s.AlertRecordRepo.FindByQuery(ctx, repo.AlertRecordQuery{
+		AgentID: record.AgentID,
+		Start:   result.WindowStart,
+		End:     result.WindowEnd,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range related {
+		if item.ID != record.ID {
+			result.RelatedAlerts = append(result.RelatedAlerts, item)
+		}
+	}
+
+	remediations, err := s.remediationSvc.RemediationRepo.FindByAlertRecordID(ctx, record.ID)
+	if err != nil {
+		return nil, err
+	}
+	result.Remediations = append(result.Remediations, remediations...)
+
+	agent, err := s.agentRepo.FindById(ctx, record.AgentID)
+	if err != nil {
+		// 分组告警、系统告警没有探针维度的上下文
+		return result, nil
+	}
+	result.Agent = &agent
+
+	inWindow, err := s.remediationSvc.RemediationRepo.FindByAgentIDBetween(ctx, agent.ID, result.WindowStart, result.WindowEnd)
+	if err != nil {
+		return nil, err
+	}
+	result.Remediations = mergeRemediations(result.Remediations, inWindow)
+
+	if result.PowerTasks, err = s.powerTaskRepo.FindByAgentIDBetween(ctx, agent.ID, result.WindowStart, result.WindowEnd); err != nil {
+		return nil, err
+	}
+	if result.Sessions, err = s.sessionRepo.FindByAgentIDBetween(ctx, agent.ID, result.WindowStart, result.WindowEnd); err != nil {
+		return nil, err
+	}
+
+	// 指标快照获取失败不影响其他上下文
+	snapshot, err := s.getAlertMetricSnapshot(ctx, agent.ID, result.WindowStart, result.WindowEnd)
+	if err != nil {
+		s.logger.Warn("获取告警指标快照失败", zap.Int64("recordId", record.ID), zap.Error(err))
+	} else {
+		result.Metrics = snapshot
+	}
+	return result, nil
+}
+
+// getAlertMetricSnapshot 获取告警窗口内按分钟聚合的指标
+func (s *AlertService) getAlertMetricSnapshot(ctx context.Context, agentID string, start, end int64) (*AlertMetricSnapshot, error) {
+	snapshot := &AlertMetricSnapshot{Interval: alertCorrelationInterval}
+	var err error
+	if snapshot.CPU, err = s.metricStore.GetCPUMetrics(ctx, agentID, start, end, alertCorrelationInterval); err != nil {
+		return nil, err
+	}
+	if snapshot.Memory, err = s.metricStore.GetMemoryMetrics(ctx, agentID, start, end, alertCorrelationInterval); err != nil {
+		return nil, err
+	}
+	if snapshot.Disk, err = s.metricStore.GetDiskMetrics(ctx, agentID, start, end, alertCorrelationInterval); err != nil {
+		return nil, err
+	}
+	if snapshot.Network, err = s.metricStore.GetNetworkMetrics(ctx, agentID, start, end, alertCorrelationInterval, ""); err != nil {
+		return nil, err
+	}
+	if snapshot.NetworkConnection, err = s.metricStore.GetNetworkConnectionMetrics(ctx, agentID, start, end, alertCorrelationInterval); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// mergeRemediations 合并修复动作记录并按创建时间排序，去除重复记录
+func mergeRemediations(records []models.RemediationRecord, more []models.RemediationRecord) []models.RemediationRecord {
+	seen := make(map[int64]bool, len(records))
+	for _, record := range records {
+		seen[record.ID] = true
+	}
+	for _, record := range more {
+		if !seen[record.ID] {
+			seen[record.ID] = true
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].CreatedAt < records[j].CreatedAt
+	})
+	return records
+}
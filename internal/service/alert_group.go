@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"go.uber.org/zap"
+)
+
+// groupAlertAgentPrefix 分组告警记录使用的探针ID前缀，完整格式为 group:<标签>
+const groupAlertAgentPrefix = "group:"
+
+// CheckGroupAlerts 检查分组告警规则
+func (s *AlertService) CheckGroupAlerts(ctx context.Context) error {
+	alertConfig, err := s.propertyService.GetAlertConfig(ctx)
+	if err != nil {
+		s.logger.Error("获取全局告警配置失败", zap.Error(err))
+		return err
+	}
+	if !alertConfig.Enabled || len(alertConfig.GroupRules) == 0 {
+		return nil
+	}
+
+	now := time.Now().UnixMilli()
+	// 同一分组的多条规则共用一次聚合结果
+	groups := make(map[string]*GroupMetrics)
+	for _, rule := range alertConfig.GroupRules {
+		if !rule.Enabled || rule.Tag == "" {
+			continue
+		}
+
+		metrics, ok := groups[rule.Tag]
+		if !ok {
+			metrics, err = s.groupService.GetGroupMetrics(ctx, rule.Tag)
+			if err != nil {
+				s.logger.Debug("获取分组指标失败", zap.String("tag", rule.Tag), zap.Error(err))
+			}
+			groups[rule.Tag] = metrics
+		}
+		if metrics == nil {
+			continue
+		}
+
+		value, ok := metrics.Value(rule.Metric, rule.Aggregation)
+		if !ok {
+			continue
+		}
+		s.checkGroupAlert(ctx, alertConfig, rule, value, metrics.ReportingCount, now)
+	}
+	return nil
+}
+
+// checkGroupAlert 检查单条分组告警规则，状态机与探针告警一致
+func (s *AlertService) checkGroupAlert(ctx context.Context, config *models.AlertConfig, rule models.GroupAlertRule, currentValue float64, agentCount int, now int64) {
+	aggregation := rule.Aggregation
+	if aggregation == "" {
+		aggregation = GroupAggregationAvg
+	}
+	agent := &models.Agent{
+		ID:   groupAlertAgentPrefix + rule.Tag,
+		Name: fmt.Sprintf("分组「%s」", rule.Tag),
+	}
+	stateKey := fmt.Sprintf("%s:%s:%s", agent.ID, rule.Metric, aggregation)
+
+	state, err := s.AlertStateRepo.GetAlertState(ctx, stateKey)
+	if err != nil {
+		state = &models.AlertState{
+			ID: stateKey,
+		}
+	}
+	state.AgentID = agent.ID
+	state.AlertType = "group"
+	state.Threshold = rule.Threshold
+	state.Duration = rule.Duration
+	state.Value = currentValue
+	state.LastCheckTime = now
+
+	var shouldFire, shouldResolve bool
+	if currentValue >= rule.Threshold {
+		if state.StartTime == 0 {
+			state.StartTime = now
+		}
+		elapsedSeconds := (now - state.StartTime) / 1000
+		if elapsedSeconds >= int64(rule.Duration) && !state.IsFiring {
+			shouldFire = true
+			state.IsFiring = true
+		}
+	} else {
+		if state.IsFiring {
+			shouldResolve = true
+		}
+		state.StartTime = 0
+	}
+
+	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+		s.logger.Error("保存告警状态失败", zap.Error(err))
+	}
+
+	if shouldFire {
+		s.fireGroupAlert(ctx, config, agent, rule, aggregation, state, agentCount)
+	}
+	if shouldResolve {
+		s.resolveAlert(ctx, config, agent, state)
+	}
+}
+
+// fireGroupAlert 触发分组告警，分组告警不关联具体探针，不执行修复动作
+func (s *AlertService) fireGroupAlert(ctx context.Context, config *models.AlertConfig, agent *models.Agent, rule models.GroupAlertRule, aggregation string, state *models.AlertState, agentCount int) {
+	s.logger.Info("触发分组告警",
+		zap.String("tag", rule.Tag),
+		zap.String("metric", rule.Metric),
+		zap.String("aggregation", aggregation),
+		zap.Float64("value", state.Value),
+		zap.Float64("threshold", state.Threshold),
+	)
+
+	now := time.Now().UnixMilli()
+	record := &models.AlertRecord{
+		AgentID:     agent.ID,
+		AgentName:   agent.Name,
+		AlertType:   state.AlertType,
+		Message:     buildGroupAlertMessage(rule, aggregation, state, agentCount),
+		Threshold:   state.Threshold,
+		ActualValue: state.Value,
+		Level:       s.calculateLevel(state.Value, state.Threshold),
+		Status:      "firing",
+		FiredAt:     now,
+		CreatedAt:   now,
+	}
+	if err := s.saveFiringRecord(ctx, config, state, record); err != nil {
+		s.logger.Error("创建告警记录失败", zap.Error(err))
+		return
+	}
+
+	state.LastRecordID = record.ID
+	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+		s.logger.Error("保存告警状态失败", zap.Error(err))
+	}
+
+	go s.sendAlertNotification(record, agent)
+}
+
+// buildGroupAlertMessage 构建分组告警消息，如：分组「prod」平均CPU使用率持续300秒超过80.00%，当前值85.20%（5台探针）
+func buildGroupAlertMessage(rule models.GroupAlertRule, aggregation string, state *models.AlertState, agentCount int) string {
+	var aggregationName string
+	switch aggregation {
+	case GroupAggregationMax:
+		aggregationName = "最高"
+	case GroupAggregationMin:
+		aggregationName = "最低"
+	case GroupAggregationSum:
+		aggregationName = "合计"
+	default:
+		aggregationName = "平均"
+	}
+
+	var metricName, unit string
+	switch rule.Metric {
+	case "cpu":
+		metricName, unit = "CPU使用率", "%"
+	case "memory":
+		metricName, unit = "内存使用率", "%"
+	case "disk":
+		metricName, unit = "磁盘使用率", "%"
+	case "network":
+		metricName, unit = "网速", "MB/s"
+	default:
+		metricName = rule.Metric
+	}
+
+	return fmt.Sprintf("分组「%s」%s%s持续%d秒超过%.2f%s，当前值%.2f%s（%d台探针）",
+		rule.Tag,
+		aggregationName,
+		metricName,
+		state.Duration,
+		state.Threshold, unit,
+		state.Value, unit,
+		agentCount,
+	)
+}
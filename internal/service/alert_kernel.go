@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/pkg/tracing"
+	"go.uber.org/zap"
+)
+
+// kernelEventMessageKeys 内核事件分类对应的消息模板，未知分类使用 kernel 模板
+var kernelEventMessageKeys = map[string]string{
+	protocol.KernelEventOOM:      "kernel_oom",
+	protocol.KernelEventIO:       "kernel_io",
+	protocol.KernelEventHardware: "kernel_hardware",
+}
+
+// HandleKernelEvent 处理探针上报的内核日志事件，同类事件首次出现时触发告警，告警期间再次出现只累计次数
+func (s *AlertService) HandleKernelEvent(ctx context.Context, agentID string, event *protocol.KernelEventData) error {
+	ctx, span := tracing.Start(ctx, "AlertService.HandleKernelEvent", tracing.WithAttributes(
+		tracing.String("agent.id", agentID),
+		tracing.String("kernel.category", event.Category),
+	))
+	defer span.End()
+
+	alertConfig, err := s.propertyService.GetAlertConfig(ctx)
+	if err != nil {
+		s.logger.Error("获取全局告警配置失败", zap.Error(err))
+		return err
+	}
+
+	rules := alertConfig.Rules
+	if !alertConfig.Enabled || !rules.KernelEnabled {
+		return nil
+	}
+	if len(rules.KernelCategories) > 0 && !slices.Contains(rules.KernelCategories, event.Category) {
+		return nil
+	}
+
+	agent, err := s.agentRepo.FindById(ctx, agentID)
+	if err != nil {
+		s.logger.Error("获取探针信息失败", zap.Error(err))
+		return err
+	}
+
+	now := time.Now().UnixMilli()
+	stateKey := fmt.Sprintf("%s:global:kernel:%s", agentID, event.Category)
+	state, err := s.AlertStateRepo.GetAlertState(ctx, stateKey)
+	if err != nil {
+		state = &models.AlertState{
+			ID:        stateKey,
+			AgentID:   agentID,
+			AlertType: "kernel",
+		}
+	}
+	state.Duration = rules.KernelRecovery
+	state.LastCheckTime = now
+
+	// Value 记录本次告警期间的事件条数（含探针端被抑制的条数）
+	count := float64(1 + event.Suppressed)
+	if state.IsFiring {
+		state.Value += count
+		if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+			s.logger.Error("保存告警状态失败", zap.Error(err))
+		}
+		return nil
+	}
+
+	state.IsFiring = true
+	state.Value = count
+	state.StartTime = now
+	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+		s.logger.Error("保存告警状态失败", zap.Error(err))
+	}
+
+	s.fireKernelAlert(ctx, alertConfig, &agent, event, state, now)
+	return nil
+}
+
+// fireKernelAlert 触发内核事件告警
+func (s *AlertService) fireKernelAlert(ctx context.Context, config *models.AlertConfig, agent *models.Agent, event *protocol.KernelEventData, state *models.AlertState, now int64) {
+	s.logger.Info("触发内核事件告警",
+		zap.String("agentId", agent.ID),
+		zap.String("category", event.Category),
+		zap.String("message", event.Message),
+	)
+
+	key := kernelEventMessageKeys[event.Category]
+	if key == "" {
+		key = "kernel"
+	}
+	// OOM 通常只影响单个进程，I/O 错误和硬件故障可能导致数据损坏
+	level := "critical"
+	if event.Category == protocol.KernelEventOOM {
+		level = "warning"
+	}
+
+	record := &models.AlertRecord{
+		AgentID:     agent.ID,
+		AgentName:   agent.Name,
+		AlertType:   "kernel",
+		ActualValue: state.Value,
+		Level:       level,
+		Status:      "firing",
+		FiredAt:     now,
+		CreatedAt:   now,
+	}
+	setAlertMessage(record, key, map[string]string{
+		"category": event.Category,
+		"message":  event.Message,
+	})
+
+	if err := s.saveFiringRecord(ctx, config, state, record); err != nil {
+		s.logger.Error("创建内核事件告警记录失败", zap.Error(err))
+		return
+	}
+
+	state.LastRecordID = record.ID
+	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+		s.logger.Error("保存告警状态失败", zap.Error(err))
+	}
+
+	// 发送通知
+	go s.sendAlertNotification(record, agent)
+
+	// 执行告警修复动作
+	go s.remediationSvc.Trigger(config, record)
+}
+
+// CheckKernelEventRecovery 恢复在恢复时间内没有再出现同类事件的内核事件告警
+func (s *AlertService) CheckKernelEventRecovery(ctx context.Context) error {
+	alertConfig, err := s.propertyService.GetAlertConfig(ctx)
+	if err != nil {
+		s.logger.Error("获取全局告警配置失败", zap.Error(err))
+		return err
+	}
+	if !alertConfig.Enabled {
+		return nil
+	}
+
+	states, err := s.AlertStateRepo.FindByType(ctx, "kernel")
+	if err != nil {
+		s.logger.Error("获取内核事件告警状态失败", zap.Error(err))
+		return err
+	}
+
+	now := time.Now().UnixMilli()
+	recovery := int64(alertConfig.Rules.KernelRecovery) * 1000
+	for i := range states {
+		state := &states[i]
+		if !state.IsFiring || now-state.LastCheckTime < recovery {
+			continue
+		}
+		agent, err := s.agentRepo.FindById(ctx, state.AgentID)
+		if err != nil {
+			s.logger.Error("获取探针信息失败", zap.String("agentId", state.AgentID), zap.Error(err))
+			continue
+		}
+		s.resolveAlert(ctx, alertConfig, &agent, state)
+	}
+	return nil
+}
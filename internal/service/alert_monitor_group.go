@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"go.uber.org/zap"
+)
+
+// monitorGroupAlertAgentPrefix 监控分组告警记录使用的探针ID前缀，完整格式为 monitor-group:<分组>
+const monitorGroupAlertAgentPrefix = "monitor-group:"
+
+// checkMonitorGroupAlerts 检查监控分组告警规则，组内离线的监控项超过允许数量并持续指定时间后告警
+func (s *AlertService) checkMonitorGroupAlerts(ctx context.Context, config *models.AlertConfig, now int64) error {
+	tasks, err := s.monitorRepo.FindByEnabled(ctx, true)
+	if err != nil {
+		return err
+	}
+	latest, err := s.metricStore.GetAllLatestMonitorMetrics(ctx)
+	if err != nil {
+		return err
+	}
+
+	groups := countMonitorGroupDown(tasks, latest, time.UnixMilli(now))
+	for _, rule := range config.MonitorGroupRules {
+		if !rule.Enabled || rule.Group == "" {
+			continue
+		}
+		group, ok := groups[rule.Group]
+		if !ok {
+			continue
+		}
+		s.checkMonitorGroupAlert(ctx, config, rule, group, now)
+	}
+	return nil
+}
+
+// checkMonitorGroupAlert 检查单条监控分组告警规则，状态机与探针告警一致
+func (s *AlertService) checkMonitorGroupAlert(ctx context.Context, config *models.AlertConfig, rule models.MonitorGroupAlertRule, group *monitorGroupDown, now int64) {
+	agent := &models.Agent{
+		ID:   monitorGroupAlertAgentPrefix + rule.Group,
+		Name: fmt.Sprintf("监控分组「%s」", rule.Group),
+	}
+	stateKey := agent.ID
+
+	state, err := s.AlertStateRepo.GetAlertState(ctx, stateKey)
+	if err != nil {
+		state = &models.AlertState{
+			ID: stateKey,
+		}
+	}
+	state.AgentID = agent.ID
+	state.AlertType = "monitor_group"
+	state.Threshold = float64(rule.MaxDown)
+	state.Duration = rule.Duration
+	state.Value = float64(len(group.Down))
+	state.LastCheckTime = now
+
+	var shouldFire, shouldResolve bool
+	if len(group.Down) > rule.MaxDown {
+		if state.StartTime == 0 {
+			state.StartTime = now
+		}
+		elapsedSeconds := (now - state.StartTime) / 1000
+		if elapsedSeconds >= int64(rule.Duration) && !state.IsFiring {
+			shouldFire = true
+			state.IsFiring = true
+		}
+	} else {
+		if state.IsFiring {
+			shouldResolve = true
+		}
+		state.StartTime = 0
+	}
+
+	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+		s.logger.Error("保存告警状态失败", zap.Error(err))
+	}
+
+	if shouldFire {
+		s.fireMonitorGroupAlert(ctx, config, agent, rule, group, state)
+	}
+	if shouldResolve {
+		s.resolveAlert(ctx, config, agent, state)
+	}
+}
+
+// fireMonitorGroupAlert 触发监控分组告警，组内全部离线时为严重级别
+func (s *AlertService) fireMonitorGroupAlert(ctx context.Context, config *models.AlertConfig, agent *models.Agent, rule models.MonitorGroupAlertRule, group *monitorGroupDown, state *models.AlertState) {
+	s.logger.Info("触发监控分组告警",
+		zap.String("group", rule.Group),
+		zap.Int("down", len(group.Down)),
+		zap.Int("maxDown", rule.MaxDown),
+	)
+
+	level := "warning"
+	if len(group.Down) == group.Total {
+		level = "critical"
+	}
+
+	now := time.Now().UnixMilli()
+	record := &models.AlertRecord{
+		AgentID:     agent.ID,
+		AgentName:   agent.Name,
+		AlertType:   state.AlertType,
+		Threshold:   state.Threshold,
+		ActualValue: state.Value,
+		Level:       level,
+		Status:      "firing",
+		FiredAt:     now,
+		CreatedAt:   now,
+	}
+	setMonitorGroupAlertMessage(record, rule, group)
+	if err := s.saveFiringRecord(ctx, config, state, record); err != nil {
+		s.logger.Error("创建告警记录失败", zap.Error(err))
+		return
+	}
+
+	state.LastRecordID = record.ID
+	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+		s.logger.Error("保存告警状态失败", zap.Error(err))
+	}
+
+	go s.sendAlertNotification(record, agent)
+}
+
+// setMonitorGroupAlertMessage 设置监控分组告警消息，如：监控分组「API」有3个监控项离线持续60秒，超过允许的1个（共5个）：a, b, c
+func setMonitorGroupAlertMessage(record *models.AlertRecord, rule models.MonitorGroupAlertRule, group *monitorGroupDown) {
+	setAlertMessage(record, "monitor_group", map[string]string{
+		"group":    rule.Group,
+		"down":     strconv.Itoa(len(group.Down)),
+		"duration": strconv.Itoa(rule.Duration),
+		"maxDown":  strconv.Itoa(rule.MaxDown),
+		"total":    strconv.Itoa(group.Total),
+		"monitors": strings.Join(group.Down, ", "),
+	})
+}
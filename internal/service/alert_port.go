@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/protocol"
+	"go.uber.org/zap"
+)
+
+// CheckListeningPorts 检查非预期监听端口告警，非回环地址上不在允许列表中的端口持续监听超过持续时间后触发，
+// 端口关闭或加入允许列表后恢复
+func (s *AlertService) CheckListeningPorts(ctx context.Context, agentID string, ports []protocol.ListeningPort) error {
+	alertConfig, err := s.propertyService.GetAlertConfig(ctx)
+	if err != nil {
+		s.logger.Error("获取全局告警配置失败", zap.Error(err))
+		return err
+	}
+
+	if !alertConfig.Enabled || !alertConfig.Rules.PortEnabled {
+		return nil
+	}
+
+	agent, err := s.agentRepo.FindById(ctx, agentID)
+	if err != nil {
+		s.logger.Error("获取探针信息失败", zap.Error(err))
+		return err
+	}
+
+	duration := alertConfig.Rules.PortDuration
+	if duration < 0 {
+		duration = 0
+	}
+
+	// 同一端口可能同时监听在多个地址上（如 0.0.0.0 和 ::），按协议和端口合并
+	unexpected := make(map[string]protocol.ListeningPort)
+	for _, port := range ports {
+		if !port.IsPublic || portAllowed(alertConfig.Rules.PortAllowed, port) {
+			continue
+		}
+		stateKey := portStateKey(agentID, port)
+		if _, ok := unexpected[stateKey]; !ok {
+			unexpected[stateKey] = port
+		}
+	}
+
+	now := time.Now().UnixMilli()
+	for stateKey, port := range unexpected {
+		s.checkListeningPort(ctx, alertConfig, &agent, stateKey, port, duration, now)
+	}
+
+	// 已关闭或已允许的端口：恢复告警，未触发的状态直接删除，避免端口再次出现时沿用旧的开始时间
+	states, err := s.AlertStateRepo.FindByAgentAndType(ctx, agentID, "port")
+	if err != nil {
+		s.logger.Error("获取监听端口告警状态失败", zap.Error(err))
+		return err
+	}
+	for i := range states {
+		state := &states[i]
+		if _, ok := unexpected[state.ID]; ok {
+			continue
+		}
+		if state.IsFiring {
+			state.Value = 0
+			state.StartTime = 0
+			s.resolveAlert(ctx, alertConfig, &agent, state)
+			continue
+		}
+		if state.ResolvedRecordID == 0 {
+			if err := s.AlertStateRepo.DeleteAlertState(ctx, state.ID); err != nil {
+				s.logger.Error("删除告警状态失败", zap.Error(err))
+			}
+		} else if state.StartTime != 0 {
+			// 保留恢复记录供冷却期合并使用
+			state.StartTime = 0
+			if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+				s.logger.Error("保存告警状态失败", zap.Error(err))
+			}
+		}
+	}
+	return nil
+}
+
+// checkListeningPort 检查单个非预期端口，Value 记录端口持续监听的秒数
+func (s *AlertService) checkListeningPort(ctx context.Context, config *models.AlertConfig, agent *models.Agent, stateKey string, port protocol.ListeningPort, duration int, now int64) {
+	state, err := s.AlertStateRepo.GetAlertState(ctx, stateKey)
+	if err != nil {
+		state = &models.AlertState{
+			ID:        stateKey,
+			AgentID:   agent.ID,
+			AlertType: "port",
+		}
+	}
+	state.AgentID = agent.ID
+	state.AlertType = "port"
+	state.Threshold = float64(duration)
+	state.Duration = duration
+	state.LastCheckTime = now
+
+	if state.StartTime == 0 {
+		state.StartTime = now
+	}
+	state.Value = float64((now - state.StartTime) / 1000)
+
+	var shouldFire bool
+	if state.Value >= state.Threshold && !state.IsFiring {
+		shouldFire = true
+		state.IsFiring = true
+	}
+
+	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+		s.logger.Error("保存告警状态失败", zap.Error(err))
+	}
+
+	if shouldFire {
+		s.firePortAlert(ctx, config, agent, port, state, now)
+	}
+}
+
+// firePortAlert 触发非预期监听端口告警
+func (s *AlertService) firePortAlert(ctx context.Context, config *models.AlertConfig, agent *models.Agent, port protocol.ListeningPort, state *models.AlertState, now int64) {
+	s.logger.Info("触发监听端口告警",
+		zap.String("agentId", agent.ID),
+		zap.String("protocol", port.Protocol),
+		zap.Uint32("port", port.Port),
+		zap.String("process", port.ProcessName),
+	)
+
+	record := &models.AlertRecord{
+		AgentID:     agent.ID,
+		AgentName:   agent.Name,
+		AlertType:   "port",
+		Threshold:   state.Threshold,
+		ActualValue: float64(port.Port),
+		Level:       "warning",
+		Status:      "firing",
+		FiredAt:     now,
+		CreatedAt:   now,
+	}
+	key := "port"
+	if port.ProcessName == "" {
+		key = "port_unknown_process"
+	}
+	setAlertMessage(record, key, map[string]string{
+		"protocol": port.Protocol,
+		"port":     strconv.FormatUint(uint64(port.Port), 10),
+		"address":  port.Address,
+		"process":  port.ProcessName,
+	})
+
+	if err := s.saveFiringRecord(ctx, config, state, record); err != nil {
+		s.logger.Error("创建监听端口告警记录失败", zap.Error(err))
+		return
+	}
+
+	state.LastRecordID = record.ID
+	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+		s.logger.Error("保存告警状态失败", zap.Error(err))
+	}
+
+	// 发送通知
+	go s.sendAlertNotification(record, agent)
+
+	// 执行告警修复动作
+	go s.remediationSvc.Trigger(config, record)
+}
+
+// portStateKey 监听端口告警状态ID
+func portStateKey(agentID string, port protocol.ListeningPort) string {
+	return fmt.Sprintf("%s:global:port:%s:%d", agentID, port.Protocol, port.Port)
+}
+
+// portAllowed 端口是否在允许列表中，列表项格式为 端口 或 协议/端口
+func portAllowed(allowed []string, port protocol.ListeningPort) bool {
+	for _, item := range allowed {
+		item = strings.ToLower(strings.TrimSpace(item))
+		protocolType, portText, ok := strings.Cut(item, "/")
+		if !ok {
+			protocolType, portText = "", item
+		}
+		if protocolType != "" && protocolType != port.Protocol {
+			continue
+		}
+		if value, err := strconv.ParseUint(portText, 10, 32); err == nil && uint32(value) == port.Port {
+			return true
+		}
+	}
+	return false
+}
@@ -3,9 +3,12 @@ package service
 import (
 	"context"
 	"fmt"
+	"math"
+	"strconv"
 	"time"
 
 	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/protocol"
 	"github.com/dushixiang/pika/internal/repo"
 	"github.com/go-orz/orz"
 	"go.uber.org/zap"
@@ -14,26 +17,40 @@ import (
 
 // AlertService 告警服务
 type AlertService struct {
-	Service         *orz.Service
-	AlertRecordRepo *repo.AlertRecordRepo
-	AlertStateRepo  *repo.AlertStateRepo
-	agentRepo       *repo.AgentRepo
-	metricRepo      *repo.MetricRepo
-	propertyService *PropertyService
-	notifier        *Notifier
-	logger          *zap.Logger
+	Service           *orz.Service
+	AlertRecordRepo   *repo.AlertRecordRepo
+	AlertStateRepo    *repo.AlertStateRepo
+	agentRepo         *repo.AgentRepo
+	monitorRepo       *repo.MonitorRepo
+	monitorCertRepo   *repo.MonitorCertRepo
+	powerTaskRepo     *repo.PowerTaskRepo
+	sessionRepo       *repo.AgentSessionRepo
+	metricStore       repo.MetricStore
+	propertyService   *PropertyService
+	notifier          *Notifier
+	notificationQueue *NotificationQueueService
+	remediationSvc    *RemediationService
+	groupService      *GroupService
+	logger            *zap.Logger
 }
 
-func NewAlertService(logger *zap.Logger, db *gorm.DB, propertyService *PropertyService, notifier *Notifier) *AlertService {
+func NewAlertService(logger *zap.Logger, db *gorm.DB, metricStore repo.MetricStore, propertyService *PropertyService, notifier *Notifier, notificationQueue *NotificationQueueService, remediationService *RemediationService, groupService *GroupService) *AlertService {
 	return &AlertService{
-		Service:         orz.NewService(db),
-		AlertRecordRepo: repo.NewAlertRecordRepo(db),
-		AlertStateRepo:  repo.NewAlertStateRepo(db),
-		agentRepo:       repo.NewAgentRepo(db),
-		metricRepo:      repo.NewMetricRepo(db),
-		propertyService: propertyService,
-		notifier:        notifier,
-		logger:          logger,
+		Service:           orz.NewService(db),
+		AlertRecordRepo:   repo.NewAlertRecordRepo(db),
+		AlertStateRepo:    repo.NewAlertStateRepo(db),
+		agentRepo:         repo.NewAgentRepo(db),
+		monitorRepo:       repo.NewMonitorRepo(db),
+		monitorCertRepo:   repo.NewMonitorCertRepo(db),
+		powerTaskRepo:     repo.NewPowerTaskRepo(db),
+		sessionRepo:       repo.NewAgentSessionRepo(db),
+		metricStore:       metricStore,
+		propertyService:   propertyService,
+		notifier:          notifier,
+		notificationQueue: notificationQueue,
+		remediationSvc:    remediationService,
+		groupService:      groupService,
+		logger:            logger.Named("alert"),
 	}
 }
 
@@ -52,6 +69,12 @@ func (s *AlertService) Clear(ctx context.Context) error {
 			return err
 		}
 
+		// 清空修复动作记录
+		if err := s.remediationSvc.RemediationRepo.Clear(ctx); err != nil {
+			s.logger.Error("清空修复动作记录失败", zap.Error(err))
+			return err
+		}
+
 		return nil
 	})
 }
@@ -102,6 +125,463 @@ func (s *AlertService) CheckMetrics(ctx context.Context, agentID string, cpu, me
 	return nil
 }
 
+// CheckWireGuard 检查 WireGuard 对端握手超时告警
+func (s *AlertService) CheckWireGuard(ctx context.Context, agentID string, tunnels []protocol.WireGuardData) error {
+	// 获取全局告警配置
+	alertConfig, err := s.propertyService.GetAlertConfig(ctx)
+	if err != nil {
+		s.logger.Error("获取全局告警配置失败", zap.Error(err))
+		return err
+	}
+
+	if !alertConfig.Enabled || !alertConfig.Rules.WireGuardEnabled {
+		return nil
+	}
+
+	// 获取探针信息（用于发送通知）
+	agent, err := s.agentRepo.FindById(ctx, agentID)
+	if err != nil {
+		s.logger.Error("获取探针信息失败", zap.Error(err))
+		return err
+	}
+
+	threshold := alertConfig.Rules.WireGuardHandshakeThreshold
+	if threshold <= 0 {
+		threshold = 300
+	}
+
+	now := time.Now().UnixMilli()
+	for _, tunnel := range tunnels {
+		for _, peer := range tunnel.Peers {
+			s.checkWireGuardPeer(ctx, alertConfig, &agent, tunnel, peer, threshold, now)
+		}
+	}
+
+	return nil
+}
+
+// checkWireGuardPeer 检查单个 WireGuard 对端的握手状态
+// 网卡停止或从未握手的对端从首次发现时开始计时
+func (s *AlertService) checkWireGuardPeer(ctx context.Context, config *models.AlertConfig, agent *models.Agent, tunnel protocol.WireGuardData, peer protocol.WireGuardPeerData, threshold int, now int64) {
+	stateKey := fmt.Sprintf("%s:global:wireguard:%s:%s", agent.ID, tunnel.Interface, peer.PublicKey)
+
+	state, err := s.AlertStateRepo.GetAlertState(ctx, stateKey)
+	if err != nil {
+		state = &models.AlertState{
+			ID:        stateKey,
+			AgentID:   agent.ID,
+			AlertType: "wireguard",
+		}
+	}
+	state.AgentID = agent.ID
+	state.AlertType = "wireguard"
+	state.Threshold = float64(threshold)
+	state.Duration = threshold
+	state.LastCheckTime = now
+
+	var staleSince int64
+	if tunnel.Up && peer.LatestHandshake > 0 {
+		staleSince = peer.LatestHandshake
+		state.StartTime = 0
+	} else {
+		if state.StartTime == 0 {
+			state.StartTime = now
+		}
+		staleSince = state.StartTime
+	}
+	state.Value = float64((now - staleSince) / 1000)
+
+	var shouldFire, shouldResolve bool
+	if state.Value >= state.Threshold {
+		if !state.IsFiring {
+			shouldFire = true
+			state.IsFiring = true
+		}
+	} else if state.IsFiring {
+		shouldResolve = true
+	}
+
+	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+		s.logger.Error("保存告警状态失败", zap.Error(err))
+	}
+
+	if shouldFire {
+		s.fireWireGuardAlert(ctx, config, agent, tunnel, peer, state, now)
+	}
+
+	if shouldResolve {
+		s.resolveAlert(ctx, config, agent, state)
+	}
+}
+
+// fireWireGuardAlert 触发 WireGuard 握手超时告警
+func (s *AlertService) fireWireGuardAlert(ctx context.Context, config *models.AlertConfig, agent *models.Agent, tunnel protocol.WireGuardData, peer protocol.WireGuardPeerData, state *models.AlertState, now int64) {
+	s.logger.Info("触发WireGuard告警",
+		zap.String("agentId", agent.ID),
+		zap.String("interface", tunnel.Interface),
+		zap.String("peer", peer.PublicKey),
+		zap.Float64("value", state.Value),
+	)
+
+	peerName := peer.PublicKey
+	if len(peerName) > 8 {
+		peerName = peerName[:8] + "..."
+	}
+	if peer.Endpoint != "" {
+		peerName = fmt.Sprintf("%s (%s)", peerName, peer.Endpoint)
+	}
+
+	level := "warning"
+	var message string
+	switch {
+	case !tunnel.Up:
+		level = "critical"
+		message = fmt.Sprintf("WireGuard 网卡 %s 已停止，对端 %s 持续%.0f秒无法握手", tunnel.Interface, peerName, state.Value)
+	case peer.LatestHandshake == 0:
+		message = fmt.Sprintf("WireGuard 网卡 %s 的对端 %s 持续%.0f秒未完成握手", tunnel.Interface, peerName, state.Value)
+	default:
+		message = fmt.Sprintf("WireGuard 网卡 %s 的对端 %s 已%.0f秒未完成握手，超过阈值%d秒", tunnel.Interface, peerName, state.Value, state.Duration)
+	}
+
+	record := &models.AlertRecord{
+		AgentID:     agent.ID,
+		AgentName:   agent.Name,
+		AlertType:   "wireguard",
+		Message:     message,
+		Threshold:   state.Threshold,
+		ActualValue: state.Value,
+		Level:       level,
+		Status:      "firing",
+		FiredAt:     now,
+		CreatedAt:   now,
+	}
+
+	if err := s.saveFiringRecord(ctx, config, state, record); err != nil {
+		s.logger.Error("创建WireGuard告警记录失败", zap.Error(err))
+		return
+	}
+
+	state.LastRecordID = record.ID
+	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+		s.logger.Error("保存告警状态失败", zap.Error(err))
+	}
+
+	// 发送通知
+	go s.sendAlertNotification(record, agent)
+
+	// 执行告警修复动作
+	go s.remediationSvc.Trigger(config, record)
+}
+
+// CheckMounts 检查网络挂载点告警，挂载点持续异常（挂起、句柄失效、未挂载等）超过持续时间后触发
+func (s *AlertService) CheckMounts(ctx context.Context, agentID string, mounts []protocol.MountData) error {
+	alertConfig, err := s.propertyService.GetAlertConfig(ctx)
+	if err != nil {
+		s.logger.Error("获取全局告警配置失败", zap.Error(err))
+		return err
+	}
+
+	if !alertConfig.Enabled || !alertConfig.Rules.MountEnabled {
+		return nil
+	}
+
+	agent, err := s.agentRepo.FindById(ctx, agentID)
+	if err != nil {
+		s.logger.Error("获取探针信息失败", zap.Error(err))
+		return err
+	}
+
+	duration := alertConfig.Rules.MountDuration
+	if duration <= 0 {
+		duration = 60
+	}
+
+	now := time.Now().UnixMilli()
+	for _, mount := range mounts {
+		s.checkMount(ctx, alertConfig, &agent, mount, duration, now)
+	}
+	return nil
+}
+
+// checkMount 检查单个挂载点的状态，Value 记录异常持续的秒数
+func (s *AlertService) checkMount(ctx context.Context, config *models.AlertConfig, agent *models.Agent, mount protocol.MountData, duration int, now int64) {
+	stateKey := fmt.Sprintf("%s:global:mount:%s", agent.ID, mount.MountPoint)
+
+	state, err := s.AlertStateRepo.GetAlertState(ctx, stateKey)
+	if err != nil {
+		state = &models.AlertState{
+			ID:        stateKey,
+			AgentID:   agent.ID,
+			AlertType: "mount",
+		}
+	}
+	state.AgentID = agent.ID
+	state.AlertType = "mount"
+	state.Threshold = float64(duration)
+	state.Duration = duration
+	state.LastCheckTime = now
+
+	var shouldFire, shouldResolve bool
+	if mount.Status != protocol.MountStatusOK {
+		if state.StartTime == 0 {
+			state.StartTime = now
+		}
+		state.Value = float64((now - state.StartTime) / 1000)
+		if state.Value >= state.Threshold && !state.IsFiring {
+			shouldFire = true
+			state.IsFiring = true
+		}
+	} else {
+		state.Value = 0
+		if state.IsFiring {
+			shouldResolve = true
+		}
+		state.StartTime = 0
+	}
+
+	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+		s.logger.Error("保存告警状态失败", zap.Error(err))
+	}
+
+	if shouldFire {
+		s.fireMountAlert(ctx, config, agent, mount, state, now)
+	}
+
+	if shouldResolve {
+		s.resolveAlert(ctx, config, agent, state)
+	}
+}
+
+// fireMountAlert 触发挂载点告警，挂起和句柄失效会阻塞访问挂载点的进程，按严重告警处理
+func (s *AlertService) fireMountAlert(ctx context.Context, config *models.AlertConfig, agent *models.Agent, mount protocol.MountData, state *models.AlertState, now int64) {
+	s.logger.Info("触发挂载点告警",
+		zap.String("agentId", agent.ID),
+		zap.String("mountPoint", mount.MountPoint),
+		zap.String("status", mount.Status),
+		zap.Float64("value", state.Value),
+	)
+
+	level := "critical"
+	key := "mount_error"
+	switch mount.Status {
+	case protocol.MountStatusHung:
+		key = "mount_hung"
+	case protocol.MountStatusStale:
+		key = "mount_stale"
+	case protocol.MountStatusUnmounted:
+		level = "warning"
+		key = "mount_unmounted"
+	default:
+		level = "warning"
+	}
+
+	record := &models.AlertRecord{
+		AgentID:     agent.ID,
+		AgentName:   agent.Name,
+		AlertType:   "mount",
+		Threshold:   state.Threshold,
+		ActualValue: state.Value,
+		Level:       level,
+		Status:      "firing",
+		FiredAt:     now,
+		CreatedAt:   now,
+	}
+	setAlertMessage(record, key, map[string]string{
+		"mountPoint": mount.MountPoint,
+		"device":     mount.Device,
+		"error":      mount.Error,
+		"seconds":    fmt.Sprintf("%.0f", state.Value),
+	})
+
+	if err := s.saveFiringRecord(ctx, config, state, record); err != nil {
+		s.logger.Error("创建挂载点告警记录失败", zap.Error(err))
+		return
+	}
+
+	state.LastRecordID = record.ID
+	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+		s.logger.Error("保存告警状态失败", zap.Error(err))
+	}
+
+	// 发送通知
+	go s.sendAlertNotification(record, agent)
+
+	// 执行告警修复动作
+	go s.remediationSvc.Trigger(config, record)
+}
+
+// CheckTimeSync 检查时钟偏差告警，按偏差绝对值判断，NTP 查询失败时不改变告警状态
+func (s *AlertService) CheckTimeSync(ctx context.Context, agentID string, data *protocol.TimeSyncData) error {
+	if data.Error != "" {
+		return nil
+	}
+
+	alertConfig, err := s.propertyService.GetAlertConfig(ctx)
+	if err != nil {
+		s.logger.Error("获取全局告警配置失败", zap.Error(err))
+		return err
+	}
+
+	if !alertConfig.Enabled || !alertConfig.Rules.ClockEnabled {
+		return nil
+	}
+
+	agent, err := s.agentRepo.FindById(ctx, agentID)
+	if err != nil {
+		s.logger.Error("获取探针信息失败", zap.Error(err))
+		return err
+	}
+
+	s.checkAlert(ctx, alertConfig, &agent, "clock", math.Abs(data.Offset), alertConfig.Rules.ClockThreshold, alertConfig.Rules.ClockDuration, time.Now().UnixMilli())
+	return nil
+}
+
+// CheckCPUSteal 检查 CPU steal 告警
+func (s *AlertService) CheckCPUSteal(ctx context.Context, agentID string, times *protocol.CPUTimesData) error {
+	alertConfig, err := s.propertyService.GetAlertConfig(ctx)
+	if err != nil {
+		s.logger.Error("获取全局告警配置失败", zap.Error(err))
+		return err
+	}
+
+	if !alertConfig.Enabled || !alertConfig.Rules.StealEnabled {
+		return nil
+	}
+
+	agent, err := s.agentRepo.FindById(ctx, agentID)
+	if err != nil {
+		s.logger.Error("获取探针信息失败", zap.Error(err))
+		return err
+	}
+
+	s.checkAlert(ctx, alertConfig, &agent, "steal", times.Steal, alertConfig.Rules.StealThreshold, alertConfig.Rules.StealDuration, time.Now().UnixMilli())
+	return nil
+}
+
+// CheckFileUsage 检查 inode 和文件描述符使用率告警，inode 按文件系统分别判断
+func (s *AlertService) CheckFileUsage(ctx context.Context, agentID string, data *protocol.FileUsageData) error {
+	alertConfig, err := s.propertyService.GetAlertConfig(ctx)
+	if err != nil {
+		s.logger.Error("获取全局告警配置失败", zap.Error(err))
+		return err
+	}
+
+	rules := alertConfig.Rules
+	if !alertConfig.Enabled || (!rules.InodeEnabled && !rules.FDEnabled) {
+		return nil
+	}
+
+	agent, err := s.agentRepo.FindById(ctx, agentID)
+	if err != nil {
+		s.logger.Error("获取探针信息失败", zap.Error(err))
+		return err
+	}
+
+	now := time.Now().UnixMilli()
+
+	if rules.FDEnabled && data.FileDescriptors != nil {
+		s.checkAlert(ctx, alertConfig, &agent, "fd", data.FileDescriptors.UsagePercent, rules.FDThreshold, rules.FDDuration, now)
+	}
+
+	if rules.InodeEnabled {
+		for _, inode := range data.Inodes {
+			s.checkInode(ctx, alertConfig, &agent, inode, now)
+		}
+	}
+	return nil
+}
+
+// checkInode 检查单个文件系统的 inode 使用率，规则与 checkAlert 一致，状态按挂载点区分
+func (s *AlertService) checkInode(ctx context.Context, config *models.AlertConfig, agent *models.Agent, inode protocol.InodeData, now int64) {
+	stateKey := fmt.Sprintf("%s:global:inode:%s", agent.ID, inode.MountPoint)
+
+	state, err := s.AlertStateRepo.GetAlertState(ctx, stateKey)
+	if err != nil {
+		state = &models.AlertState{
+			ID:        stateKey,
+			AgentID:   agent.ID,
+			AlertType: "inode",
+		}
+	}
+	state.AgentID = agent.ID
+	state.AlertType = "inode"
+	state.Threshold = config.Rules.InodeThreshold
+	state.Duration = config.Rules.InodeDuration
+	state.Value = inode.UsagePercent
+	state.LastCheckTime = now
+
+	var shouldFire, shouldResolve bool
+	if state.Value >= state.Threshold {
+		if state.StartTime == 0 {
+			state.StartTime = now
+		}
+		if (now-state.StartTime)/1000 >= int64(state.Duration) && !state.IsFiring {
+			shouldFire = true
+			state.IsFiring = true
+		}
+	} else {
+		if state.IsFiring {
+			shouldResolve = true
+		}
+		state.StartTime = 0
+	}
+
+	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+		s.logger.Error("保存告警状态失败", zap.Error(err))
+	}
+
+	if shouldFire {
+		s.fireInodeAlert(ctx, config, agent, inode, state, now)
+	}
+
+	if shouldResolve {
+		s.resolveAlert(ctx, config, agent, state)
+	}
+}
+
+// fireInodeAlert 触发 inode 使用率告警，消息中带上挂载点便于定位
+func (s *AlertService) fireInodeAlert(ctx context.Context, config *models.AlertConfig, agent *models.Agent, inode protocol.InodeData, state *models.AlertState, now int64) {
+	s.logger.Info("触发inode告警",
+		zap.String("agentId", agent.ID),
+		zap.String("mountPoint", inode.MountPoint),
+		zap.Float64("value", state.Value),
+		zap.Float64("threshold", state.Threshold),
+	)
+
+	record := &models.AlertRecord{
+		AgentID:     agent.ID,
+		AgentName:   agent.Name,
+		AlertType:   "inode",
+		Threshold:   state.Threshold,
+		ActualValue: state.Value,
+		Level:       s.calculateLevel(state.Value, state.Threshold),
+		Status:      "firing",
+		FiredAt:     now,
+		CreatedAt:   now,
+	}
+	setAlertMessage(record, "inode", map[string]string{
+		"mountPoint": inode.MountPoint,
+		"duration":   strconv.Itoa(state.Duration),
+		"free":       strconv.FormatUint(inode.Free, 10),
+	})
+
+	if err := s.saveFiringRecord(ctx, config, state, record); err != nil {
+		s.logger.Error("创建inode告警记录失败", zap.Error(err))
+		return
+	}
+
+	state.LastRecordID = record.ID
+	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
+		s.logger.Error("保存告警状态失败", zap.Error(err))
+	}
+
+	// 发送通知
+	go s.sendAlertNotification(record, agent)
+
+	// 执行告警修复动作
+	go s.remediationSvc.Trigger(config, record)
+}
+
 // checkAlert 检查单个告警规则
 func (s *AlertService) checkAlert(ctx context.Context, config *models.AlertConfig, agent *models.Agent, alertType string, currentValue, threshold float64, duration int, now int64) {
 	stateKey := fmt.Sprintf("%s:global:%s", agent.ID, alertType)
@@ -175,17 +655,18 @@ func (s *AlertService) fireAlert(ctx context.Context, config *models.AlertConfig
 		AgentID:     agent.ID,
 		AgentName:   agent.Name,
 		AlertType:   state.AlertType,
-		Message:     s.buildAlertMessage(state),
 		Threshold:   state.Threshold,
 		ActualValue: state.Value,
-		Level:       s.calculateLevel(state.Value, state.Threshold),
+		Level:       s.alertLevel(state),
 		Status:      "firing",
 		FiredAt:     now,
 		CreatedAt:   now,
 	}
+	setAlertMessage(record, state.AlertType, map[string]string{
+		"duration": strconv.Itoa(state.Duration),
+	})
 
-	err := s.AlertRecordRepo.CreateAlertRecord(ctx, record)
-	if err != nil {
+	if err := s.saveFiringRecord(ctx, config, state, record); err != nil {
 		s.logger.Error("创建告警记录失败", zap.Error(err))
 		// 不回滚 IsFiring 状态,避免下次检查时重复触发
 		// 记录创建失败不影响状态机,下次检查时会重试
@@ -200,6 +681,9 @@ func (s *AlertService) fireAlert(ctx context.Context, config *models.AlertConfig
 
 	// 发送通知 - 使用新的 context 避免父 context 取消影响通知发送
 	go s.sendAlertNotification(record, agent)
+
+	// 执行告警修复动作
+	go s.remediationSvc.Trigger(config, record)
 }
 
 // resolveAlert 恢复告警
@@ -242,42 +726,32 @@ func (s *AlertService) resolveAlert(ctx context.Context, config *models.AlertCon
 
 	// 更新状态
 	state.IsFiring = false
-	state.LastRecordID = 0
+	markResolved(state)
 	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
 		s.logger.Error("保存告警状态失败", zap.Error(err))
 	}
 }
 
-// buildAlertMessage 构建告警消息
-func (s *AlertService) buildAlertMessage(state *models.AlertState) string {
-	var alertTypeName string
-	switch state.AlertType {
-	case "cpu":
-		alertTypeName = "CPU使用率"
-	case "memory":
-		alertTypeName = "内存使用率"
-	case "disk":
-		alertTypeName = "磁盘使用率"
-	case "network":
-		return fmt.Sprintf("网速持续%d秒超过%.2fMB/s，当前值%.2fMB/s",
-			state.Duration,
-			state.Threshold,
-			state.Value,
-		)
-	case "cert":
-		return fmt.Sprintf("HTTPS证书剩余天数%.0f天，低于阈值%.0f天", state.Value, state.Threshold)
-	case "service":
-		return fmt.Sprintf("服务持续离线%d秒", state.Duration)
-	default:
-		alertTypeName = state.AlertType
+// alertLevel 按告警类型计算告警级别，时钟偏差以毫秒为单位，不能套用百分比的级别划分
+func (s *AlertService) alertLevel(state *models.AlertState) string {
+	if state.AlertType == "clock" {
+		return clockAlertLevel(state.Value, state.Threshold)
 	}
+	return s.calculateLevel(state.Value, state.Threshold)
+}
 
-	return fmt.Sprintf("%s持续%d秒超过%.2f%%，当前值%.2f%%",
-		alertTypeName,
-		state.Duration,
-		state.Threshold,
-		state.Value,
-	)
+// clockAlertLevel 时钟偏差告警级别，按偏差是阈值的几倍划分：2 倍以内为 info，5 倍以内为 warning，否则为 critical
+func clockAlertLevel(offset, threshold float64) string {
+	if threshold <= 0 {
+		return "warning"
+	}
+	ratio := offset / threshold
+	if ratio < 2 {
+		return "info"
+	} else if ratio < 5 {
+		return "warning"
+	}
+	return "critical"
 }
 
 // calculateLevel 计算告警级别
@@ -293,7 +767,7 @@ func (s *AlertService) calculateLevel(value, threshold float64) string {
 	}
 }
 
-// sendAlertNotification 发送告警通知(带panic恢复)
+// sendAlertNotification 将告警通知加入发送队列(带panic恢复)，入队失败时直接发送
 func (s *AlertService) sendAlertNotification(record *models.AlertRecord, agent *models.Agent) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -315,7 +789,7 @@ func (s *AlertService) sendAlertNotification(record *models.AlertRecord, agent *
 
 	var enabledChannels []models.NotificationChannelConfig
 	for _, channel := range channelConfigs {
-		if channel.Enabled {
+		if channel.Enabled && channel.Subscribes(models.NotificationEventAlert) {
 			enabledChannels = append(enabledChannels, channel)
 		}
 	}
@@ -324,12 +798,51 @@ func (s *AlertService) sendAlertNotification(record *models.AlertRecord, agent *
 		return
 	}
 
-	if err := s.notifier.SendNotificationByConfigs(ctx, enabledChannels, record, agent); err != nil {
-		s.logger.Error("发送告警通知失败", zap.Error(err))
+	if err := s.notificationQueue.Enqueue(ctx, enabledChannels, record, agent); err != nil {
+		s.logger.Error("告警通知入队失败，直接发送", zap.Error(err))
+		if err := s.notifier.SendNotificationByConfigs(ctx, enabledChannels, record, agent); err != nil {
+			s.logger.Error("发送告警通知失败", zap.Error(err))
+		}
+	}
+}
+
+// systemAgentID 服务端自身告警使用的探针ID
+const systemAgentID = "server"
+
+// FireSystemAlert 触发服务端自身的告警（如数据库损坏），不关联探针也不执行修复动作
+func (s *AlertService) FireSystemAlert(ctx context.Context, alertType, level, message string) error {
+	alertConfig, err := s.propertyService.GetAlertConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if !alertConfig.Enabled {
+		return nil
+	}
+
+	s.logger.Info("触发服务端告警", zap.String("alertType", alertType), zap.String("level", level))
+
+	agent := &models.Agent{ID: systemAgentID, Name: "Pika 服务端"}
+	now := time.Now().UnixMilli()
+	record := &models.AlertRecord{
+		AgentID:   agent.ID,
+		AgentName: agent.Name,
+		AlertType: alertType,
+		Message:   message,
+		Level:     level,
+		Status:    "firing",
+		FiredAt:   now,
+		CreatedAt: now,
 	}
+	// 数据库异常时记录可能写入失败，仍然发送通知
+	if err := s.AlertRecordRepo.CreateAlertRecord(ctx, record); err != nil {
+		s.logger.Error("创建告警记录失败", zap.Error(err))
+	}
+
+	go s.sendAlertNotification(record, agent)
+	return nil
 }
 
-// CheckMonitorAlerts 检查监控相关告警（证书和服务下线）
+// CheckMonitorAlerts 检查监控相关告警（证书、证书链和服务下线）
 func (s *AlertService) CheckMonitorAlerts(ctx context.Context) error {
 	// 获取全局告警配置
 	alertConfig, err := s.propertyService.GetAlertConfig(ctx)
@@ -352,6 +865,13 @@ func (s *AlertService) CheckMonitorAlerts(ctx context.Context) error {
 		}
 	}
 
+	// 检查证书链告警
+	if alertConfig.Rules.CertChainEnabled {
+		if err := s.checkCertChainAlerts(ctx, alertConfig, now); err != nil {
+			s.logger.Error("检查证书链告警失败", zap.Error(err))
+		}
+	}
+
 	// 检查服务下线告警
 	if alertConfig.Rules.ServiceEnabled {
 		if err := s.checkServiceDownAlerts(ctx, alertConfig, now); err != nil {
@@ -359,6 +879,13 @@ func (s *AlertService) CheckMonitorAlerts(ctx context.Context) error {
 		}
 	}
 
+	// 检查监控分组告警
+	if len(alertConfig.MonitorGroupRules) > 0 {
+		if err := s.checkMonitorGroupAlerts(ctx, alertConfig, now); err != nil {
+			s.logger.Error("检查监控分组告警失败", zap.Error(err))
+		}
+	}
+
 	// 检查探针离线告警
 	if alertConfig.Rules.AgentOfflineEnabled {
 		if err := s.checkAgentOfflineAlerts(ctx, alertConfig, now); err != nil {
@@ -373,7 +900,7 @@ func (s *AlertService) CheckMonitorAlerts(ctx context.Context) error {
 func (s *AlertService) checkCertificateAlerts(ctx context.Context, config *models.AlertConfig, now int64) error {
 	// 获取所有最新的监控指标（仅HTTPS类型）
 	// 这里需要查询最新的 monitor_metrics 记录，获取证书剩余天数
-	monitors, err := s.metricRepo.GetLatestMonitorMetricsByType(ctx, "http")
+	monitors, err := s.metricStore.GetLatestMonitorMetricsByType(ctx, "http")
 	if err != nil {
 		return err
 	}
@@ -463,8 +990,7 @@ func (s *AlertService) checkCertAlert(ctx context.Context, config *models.AlertC
 		CreatedAt:   now,
 	}
 
-	err = s.AlertRecordRepo.CreateAlertRecord(ctx, record)
-	if err != nil {
+	if err := s.saveFiringRecord(ctx, config, state, record); err != nil {
 		s.logger.Error("创建证书告警记录失败", zap.Error(err))
 		return
 	}
@@ -476,6 +1002,9 @@ func (s *AlertService) checkCertAlert(ctx context.Context, config *models.AlertC
 
 	// 发送通知
 	go s.sendAlertNotification(record, agent)
+
+	// 执行告警修复动作
+	go s.remediationSvc.Trigger(config, record)
 }
 
 // resolveCertAlert 恢复证书告警
@@ -516,7 +1045,7 @@ func (s *AlertService) resolveCertAlert(ctx context.Context, config *models.Aler
 	}
 
 	state.IsFiring = false
-	state.LastRecordID = 0
+	markResolved(state)
 	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
 		s.logger.Error("保存告警状态失败", zap.Error(err))
 	}
@@ -536,17 +1065,33 @@ func (s *AlertService) calculateCertLevel(daysLeft float64) string {
 // checkServiceDownAlerts 检查服务下线告警
 func (s *AlertService) checkServiceDownAlerts(ctx context.Context, config *models.AlertConfig, now int64) error {
 	// 获取所有最新的监控指标
-	monitors, err := s.metricRepo.GetAllLatestMonitorMetrics(ctx)
+	monitors, err := s.metricStore.GetAllLatestMonitorMetrics(ctx)
+	if err != nil {
+		return err
+	}
+
+	// 维护期间的离线不触发告警
+	tasks, err := s.monitorRepo.FindByEnabled(ctx, true)
 	if err != nil {
 		return err
 	}
+	maintenance := make(map[string]bool, len(tasks))
+	quorums := make(map[string]int, len(tasks))
+	for _, task := range tasks {
+		maintenance[task.ID] = inMaintenance(task.MaintenanceWindows, time.UnixMilli(now))
+		quorums[task.ID] = task.DownQuorum
+	}
+	counts := monitorStatusCounts(monitors)
 
 	for _, monitor := range monitors {
-		// 获取探针信息
-		agent, err := s.agentRepo.FindById(ctx, monitor.AgentId)
-		if err != nil {
-			s.logger.Error("获取探针信息失败", zap.String("agentId", monitor.AgentId), zap.Error(err))
-			continue
+		// 获取探针信息，被动心跳监控的结果不来自探针
+		agent := pushAgent()
+		if monitor.AgentId != PushAgentID {
+			agent, err = s.agentRepo.FindById(ctx, monitor.AgentId)
+			if err != nil {
+				s.logger.Error("获取探针信息失败", zap.String("agentId", monitor.AgentId), zap.Error(err))
+				continue
+			}
 		}
 
 		stateKey := fmt.Sprintf("%s:global:service:%s", agent.ID, monitor.MonitorId)
@@ -568,7 +1113,19 @@ func (s *AlertService) checkServiceDownAlerts(ctx context.Context, config *model
 		state.Duration = config.Rules.ServiceDuration
 		state.LastCheckTime = now
 
-		if monitor.Status == "down" {
+		// 配置了离线判定的探针数量时，离线的探针数量未达到要求不视为离线
+		down := monitor.Status == "down"
+		if quorum := quorums[monitor.MonitorId]; down && quorum > 0 {
+			count := counts[monitor.MonitorId]
+			down = monitorConsensusStatus(count[0], count[1], quorum) == "down"
+		}
+
+		if down && maintenance[monitor.MonitorId] {
+			// 维护期间顺延离线开始时间，维护结束后仍然离线时从维护结束开始计时，已触发的告警保持不变
+			if !state.IsFiring {
+				state.StartTime = now
+			}
+		} else if down {
 			if state.StartTime == 0 {
 				state.StartTime = monitor.Timestamp
 			}
@@ -625,8 +1182,7 @@ func (s *AlertService) fireServiceDownAlert(ctx context.Context, config *models.
 		CreatedAt:   now,
 	}
 
-	err := s.AlertRecordRepo.CreateAlertRecord(ctx, record)
-	if err != nil {
+	if err := s.saveFiringRecord(ctx, config, state, record); err != nil {
 		s.logger.Error("创建服务下线告警记录失败", zap.Error(err))
 		return
 	}
@@ -638,6 +1194,9 @@ func (s *AlertService) fireServiceDownAlert(ctx context.Context, config *models.
 
 	// 发送通知
 	go s.sendAlertNotification(record, agent)
+
+	// 执行告警修复动作
+	go s.remediationSvc.Trigger(config, record)
 }
 
 // resolveServiceDownAlert 恢复服务下线告警
@@ -669,7 +1228,7 @@ func (s *AlertService) resolveServiceDownAlert(ctx context.Context, config *mode
 	}
 
 	state.IsFiring = false
-	state.LastRecordID = 0
+	markResolved(state)
 	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
 		s.logger.Error("保存告警状态失败", zap.Error(err))
 	}
@@ -763,8 +1322,7 @@ func (s *AlertService) fireAgentOfflineAlert(ctx context.Context, config *models
 		CreatedAt:   now,
 	}
 
-	err := s.AlertRecordRepo.CreateAlertRecord(ctx, record)
-	if err != nil {
+	if err := s.saveFiringRecord(ctx, config, state, record); err != nil {
 		s.logger.Error("创建探针离线告警记录失败", zap.Error(err))
 		return
 	}
@@ -807,7 +1365,7 @@ func (s *AlertService) resolveAgentOfflineAlert(ctx context.Context, config *mod
 	}
 
 	state.IsFiring = false
-	state.LastRecordID = 0
+	markResolved(state)
 	if err := s.AlertStateRepo.SaveAlertState(ctx, state); err != nil {
 		s.logger.Error("保存告警状态失败", zap.Error(err))
 	}
@@ -0,0 +1,23 @@
+package service
+
+import "testing"
+
+func TestClockAlertLevel(t *testing.T) {
+	tests := []struct {
+		offset    float64
+		threshold float64
+		want      string
+	}{
+		{offset: 600, threshold: 500, want: "info"},
+		{offset: 1200, threshold: 500, want: "warning"},
+		{offset: 2600, threshold: 500, want: "critical"},
+		// 超出阈值几十毫秒不应直接判为 critical
+		{offset: 160, threshold: 100, want: "info"},
+		{offset: 100, threshold: 0, want: "warning"},
+	}
+	for _, tt := range tests {
+		if got := clockAlertLevel(tt.offset, tt.threshold); got != tt.want {
+			t.Errorf("clockAlertLevel(%v, %v) = %s, want %s", tt.offset, tt.threshold, got, tt.want)
+		}
+	}
+}
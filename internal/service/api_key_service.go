@@ -21,7 +21,7 @@ type ApiKeyService struct {
 
 func NewApiKeyService(logger *zap.Logger, db *gorm.DB) *ApiKeyService {
 	return &ApiKeyService{
-		logger:     logger,
+		logger:     logger.Named("auth"),
 		ApiKeyRepo: repo.NewApiKeyRepo(db),
 	}
 }
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/dushixiang/pika/internal/protocol"
+	ws "github.com/dushixiang/pika/internal/websocket"
+	"go.uber.org/zap"
+)
+
+// CollectorConfigService 采集器配置下发服务，配置来源于指标数据配置，所有探针共用
+type CollectorConfigService struct {
+	logger          *zap.Logger
+	propertyService *PropertyService
+	wsManager       *ws.Manager
+}
+
+func NewCollectorConfigService(logger *zap.Logger, propertyService *PropertyService, wsManager *ws.Manager) *CollectorConfigService {
+	return &CollectorConfigService{
+		logger:          logger.Named("collector-config"),
+		propertyService: propertyService,
+		wsManager:       wsManager,
+	}
+}
+
+// BuildConfigMessage 构建下发给探针的采集器配置消息
+func (s *CollectorConfigService) BuildConfigMessage(ctx context.Context) ([]byte, error) {
+	metricsConfig := s.propertyService.GetMetricsConfig(ctx)
+	payload := protocol.CollectorConfigPayload{
+		PerCoreCPU: metricsConfig.PerCoreCPU,
+		Intervals:  metricsConfig.CollectIntervals,
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(protocol.Message{
+		Type: protocol.MessageTypeCollectorConfig,
+		Data: data,
+	})
+}
+
+// SyncToAgents 向所有在线探针下发最新的采集器配置，离线探针会在下次连接时下发
+func (s *CollectorConfigService) SyncToAgents(ctx context.Context) {
+	msgData, err := s.BuildConfigMessage(ctx)
+	if err != nil {
+		s.logger.Error("构建采集器配置失败", zap.Error(err))
+		return
+	}
+
+	for _, agentID := range s.wsManager.GetAllClients() {
+		if err := s.wsManager.SendToClient(agentID, msgData); err != nil {
+			s.logger.Warn("下发采集器配置到探针失败", zap.String("agentId", agentID), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/dushixiang/pika/internal/config"
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/internal/repo"
+	ws "github.com/dushixiang/pika/internal/websocket"
+	"github.com/go-orz/orz"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// commandResultGrace 超时时间之外等待探针返回结果的时间，超过后执行记录标记为超时
+const commandResultGrace = 30 * time.Second
+
+// commandLabels 服务端支持的远程命令，新增命令需同时在探针注册执行器
+var commandLabels = map[string]string{
+	protocol.RemoteCommandRestartService: "重启服务",
+	protocol.RemoteCommandClearCache:     "清理缓存",
+	protocol.RemoteCommandRunScript:      "执行脚本",
+}
+
+// CommandOption 当前用户可下发的远程命令
+type CommandOption struct {
+	Name    string   `json:"name"`
+	Label   string   `json:"label"`
+	Targets []string `json:"targets"` // 允许的目标，为空时不限制
+	Timeout int      `json:"timeout"` // 超时时间上限（秒）
+}
+
+// CommandService 远程命令服务，按白名单下发命令并保存执行记录
+type CommandService struct {
+	logger               *zap.Logger
+	CommandExecutionRepo *repo.CommandExecutionRepo
+	wsManager            *ws.Manager
+	config               config.CommandsConfig
+}
+
+func NewCommandService(logger *zap.Logger, db *gorm.DB, wsManager *ws.Manager, cfg *config.AppConfig) *CommandService {
+	s := &CommandService{
+		logger:               logger.Named("command"),
+		CommandExecutionRepo: repo.NewCommandExecutionRepo(db),
+		wsManager:            wsManager,
+	}
+	if cfg.Commands != nil {
+		s.config = *cfg.Commands
+	}
+	return s
+}
+
+// Authorize 校验用户是否可以下发远程命令和查看执行记录
+func (s *CommandService) Authorize(username string) error {
+	if !s.config.Enabled {
+		return orz.NewError(403, "未启用远程命令")
+	}
+	if len(s.config.AllowedUsers) > 0 && !slices.Contains(s.config.AllowedUsers, username) {
+		return orz.NewError(403, "没有下发远程命令的权限")
+	}
+	return nil
+}
+
+// Options 获取允许下发的远程命令
+func (s *CommandService) Options(username string) ([]CommandOption, error) {
+	if err := s.Authorize(username); err != nil {
+		return nil, err
+	}
+	options := make([]CommandOption, 0, len(s.config.Allowed))
+	for _, rule := range s.config.Allowed {
+		label, ok := commandLabels[rule.Name]
+		if !ok {
+			continue
+		}
+		options = append(options, CommandOption{
+			Name:    rule.Name,
+			Label:   label,
+			Targets: rule.Targets,
+			Timeout: protocol.ClampRemoteCommandTimeout(rule.Timeout),
+		})
+	}
+	return options, nil
+}
+
+// Execute 校验白名单后向探针下发远程命令，执行结果异步更新到执行记录
+func (s *CommandService) Execute(ctx context.Context, agentID, username, clientIP string, req protocol.RemoteCommandRequest) (*models.CommandExecution, error) {
+	if err := s.Authorize(username); err != nil {
+		return nil, err
+	}
+	timeout, err := resolveCommandTimeout(s.config.Allowed, req)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := s.wsManager.GetClient(agentID); !ok {
+		return nil, orz.NewError(400, "探针未连接")
+	}
+
+	now := time.Now()
+	record := &models.CommandExecution{
+		AgentID:   agentID,
+		Name:      req.Name,
+		Target:    req.Target,
+		Timeout:   timeout,
+		Status:    models.CommandStatusRunning,
+		CommandID: fmt.Sprintf("command_%d", now.UnixNano()),
+		Username:  username,
+		ClientIP:  clientIP,
+		CreatedAt: now.UnixMilli(),
+	}
+	if err := s.CommandExecutionRepo.Create(ctx, record); err != nil {
+		return nil, err
+	}
+
+	req.Timeout = timeout
+	if err := s.sendCommand(agentID, record.CommandID, req); err != nil {
+		s.finish(ctx, record, map[string]interface{}{
+			"status":      models.CommandStatusError,
+			"error":       "下发指令失败: " + err.Error(),
+			"finished_at": time.Now().UnixMilli(),
+		})
+		return nil, orz.NewError(500, "发送指令失败")
+	}
+
+	s.logger.Info("远程命令已下发",
+		zap.Int64("id", record.ID),
+		zap.String("agentId", agentID),
+		zap.String("name", req.Name),
+		zap.String("target", req.Target),
+		zap.String("username", username))
+
+	// 探针断开或未及时返回结果时，超时后不再等待
+	time.AfterFunc(time.Duration(timeout)*time.Second+commandResultGrace, func() {
+		s.finish(context.Background(), record, map[string]interface{}{
+			"status":      models.CommandStatusTimeout,
+			"error":       fmt.Sprintf("超过 %d 秒未返回执行结果", timeout),
+			"finished_at": time.Now().UnixMilli(),
+		})
+	})
+	return record, nil
+}
+
+func (s *CommandService) sendCommand(agentID, commandID string, req protocol.RemoteCommandRequest) error {
+	args, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	cmdData, err := json.Marshal(protocol.CommandRequest{
+		ID:   commandID,
+		Type: "remote_command",
+		Args: string(args),
+	})
+	if err != nil {
+		return err
+	}
+	msgData, err := json.Marshal(protocol.Message{
+		Type: protocol.MessageTypeCommand,
+		Data: cmdData,
+	})
+	if err != nil {
+		return err
+	}
+	return s.wsManager.SendToClient(agentID, msgData)
+}
+
+// HandleCommandResponse 处理探针返回的远程命令执行结果
+func (s *CommandService) HandleCommandResponse(ctx context.Context, agentID string, resp *protocol.CommandResponse) error {
+	if resp.Status == "running" {
+		return nil
+	}
+
+	record, err := s.CommandExecutionRepo.FindByCommandID(ctx, agentID, resp.ID)
+	if err != nil {
+		s.logger.Warn("未找到远程命令执行记录", zap.String("agentId", agentID), zap.String("cmdId", resp.ID))
+		return nil
+	}
+
+	var result protocol.RemoteCommandResult
+	if resp.Result != "" {
+		if err := json.Unmarshal([]byte(resp.Result), &result); err != nil {
+			result.Output = resp.Result
+		}
+	}
+
+	status := models.CommandStatusSuccess
+	if resp.Status == "error" {
+		status = models.CommandStatusError
+	}
+
+	s.logger.Info("远程命令执行完成",
+		zap.Int64("id", record.ID),
+		zap.String("agentId", agentID),
+		zap.String("name", record.Name),
+		zap.String("status", status))
+
+	s.finish(ctx, record, map[string]interface{}{
+		"status":      status,
+		"output":      result.Output,
+		"error":       resp.Error,
+		"exit_code":   result.ExitCode,
+		"duration":    result.Duration,
+		"finished_at": time.Now().UnixMilli(),
+	})
+	return nil
+}
+
+// finish 结束执行记录，已超时的记录不会被之后返回的结果覆盖
+func (s *CommandService) finish(ctx context.Context, record *models.CommandExecution, values map[string]interface{}) {
+	if _, err := s.CommandExecutionRepo.Finish(ctx, record.ID, values); err != nil {
+		s.logger.Error("更新远程命令执行记录失败", zap.Int64("id", record.ID), zap.Error(err))
+	}
+}
+
+// CloseStaleExecutions 服务启动时将上次运行遗留的执行中记录标记为失败
+func (s *CommandService) CloseStaleExecutions(ctx context.Context) error {
+	count, err := s.CommandExecutionRepo.CloseRunning(ctx, "服务重启，未收到执行结果", time.Now().UnixMilli())
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		s.logger.Info("已结束中断的远程命令执行记录", zap.Int64("count", count))
+	}
+	return nil
+}
+
+// resolveCommandTimeout 校验命令和目标是否在白名单中，返回本次执行的超时时间（秒）
+// 未指定超时时间时使用白名单配置的上限
+func resolveCommandTimeout(rules []config.CommandRule, req protocol.RemoteCommandRequest) (int, error) {
+	if _, ok := commandLabels[req.Name]; !ok {
+		return 0, orz.NewError(400, "不支持的远程命令")
+	}
+	if req.Target == "" {
+		return 0, orz.NewError(400, "命令目标不能为空")
+	}
+	for _, rule := range rules {
+		if rule.Name != req.Name {
+			continue
+		}
+		if len(rule.Targets) > 0 && !slices.Contains(rule.Targets, req.Target) {
+			continue
+		}
+		limit := protocol.ClampRemoteCommandTimeout(rule.Timeout)
+		if req.Timeout <= 0 {
+			return limit, nil
+		}
+		return min(req.Timeout, limit), nil
+	}
+	return 0, orz.NewError(403, "命令不在白名单中")
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/dushixiang/pika/internal/config"
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+func TestResolveCommandTimeout(t *testing.T) {
+	rules := []config.CommandRule{
+		{Name: protocol.RemoteCommandRestartService, Targets: []string{"nginx"}, Timeout: 120},
+		{Name: protocol.RemoteCommandClearCache},
+		{Name: protocol.RemoteCommandRunScript, Targets: []string{"backup"}, Timeout: 7200},
+	}
+	tests := []struct {
+		name    string
+		req     protocol.RemoteCommandRequest
+		want    int
+		wantErr bool
+	}{
+		{"使用白名单的超时上限", protocol.RemoteCommandRequest{Name: protocol.RemoteCommandRestartService, Target: "nginx"}, 120, false},
+		{"小于上限的超时时间", protocol.RemoteCommandRequest{Name: protocol.RemoteCommandRestartService, Target: "nginx", Timeout: 30}, 30, false},
+		{"超过上限的超时时间", protocol.RemoteCommandRequest{Name: protocol.RemoteCommandRestartService, Target: "nginx", Timeout: 600}, 120, false},
+		{"目标不在白名单中", protocol.RemoteCommandRequest{Name: protocol.RemoteCommandRestartService, Target: "sshd"}, 0, true},
+		{"未限制目标", protocol.RemoteCommandRequest{Name: protocol.RemoteCommandClearCache, Target: "/var/cache/app"}, protocol.RemoteCommandDefaultTimeout, false},
+		{"目标为空", protocol.RemoteCommandRequest{Name: protocol.RemoteCommandClearCache}, 0, true},
+		{"白名单上限超过最长超时", protocol.RemoteCommandRequest{Name: protocol.RemoteCommandRunScript, Target: "backup"}, protocol.RemoteCommandMaxTimeout, false},
+		{"不支持的命令", protocol.RemoteCommandRequest{Name: "reboot", Target: "now"}, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := resolveCommandTimeout(rules, tt.req)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("%s: resolveCommandTimeout = %d, %v, 期望 %d, 错误 %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	if _, err := resolveCommandTimeout(nil, protocol.RemoteCommandRequest{Name: protocol.RemoteCommandClearCache, Target: "/tmp"}); err == nil {
+		t.Errorf("未配置白名单时不应允许下发命令")
+	}
+}
+
+func TestCommandAuthorize(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   config.CommandsConfig
+		username string
+		allowed  bool
+	}{
+		{"未启用", config.CommandsConfig{}, "admin", false},
+		{"未限制用户", config.CommandsConfig{Enabled: true}, "admin", true},
+		{"在允许列表中", config.CommandsConfig{Enabled: true, AllowedUsers: []string{"ops"}}, "ops", true},
+		{"不在允许列表中", config.CommandsConfig{Enabled: true, AllowedUsers: []string{"ops"}}, "admin", false},
+	}
+	for _, tt := range tests {
+		s := &CommandService{config: tt.config}
+		if err := s.Authorize(tt.username); (err == nil) != tt.allowed {
+			t.Errorf("%s: Authorize 错误 = %v, 期望允许 %v", tt.name, err, tt.allowed)
+		}
+	}
+}
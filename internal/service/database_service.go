@@ -0,0 +1,355 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// walCheckpointInterval WAL 检查点间隔，避免 -wal 文件无限增长
+	walCheckpointInterval = 5 * time.Minute
+	// incrementalVacuumInterval 增量回收空闲页间隔，指标清理会产生大量空闲页
+	incrementalVacuumInterval = time.Hour
+	// integrityCheckDelay 启动后首次完整性检查的延迟，避开启动阶段的写入高峰
+	integrityCheckDelay = 5 * time.Minute
+	// integrityCheckInterval 完整性检查间隔，大库检查耗时较长，每天执行一次
+	integrityCheckInterval = 24 * time.Hour
+	// integrityCheckMaxErrors 完整性检查最多返回的错误条数
+	integrityCheckMaxErrors = 100
+	// autoVacuumConvertMaxSize 自动切换为增量回收模式的数据库大小上限，切换需要执行一次完整 VACUUM
+	autoVacuumConvertMaxSize = 256 << 20
+)
+
+const (
+	DatabaseStatusOK          = "ok"
+	DatabaseStatusCorrupted   = "corrupted"
+	DatabaseStatusUnreachable = "unreachable"
+)
+
+// IntegrityCheckResult 完整性检查结果
+type IntegrityCheckResult struct {
+	OK         bool     `json:"ok"`
+	Errors     []string `json:"errors,omitempty"`
+	DurationMs int64    `json:"durationMs"`
+	CheckedAt  int64    `json:"checkedAt"`
+}
+
+// VacuumResult 增量回收结果
+type VacuumResult struct {
+	FreedPages int64 `json:"freedPages"`
+	DurationMs int64 `json:"durationMs"`
+	VacuumedAt int64 `json:"vacuumedAt"`
+}
+
+// CheckpointResult WAL 检查点结果，对应 PRAGMA wal_checkpoint 的返回值
+type CheckpointResult struct {
+	Busy           bool  `json:"busy"`           // 是否因其他连接占用而未完成
+	LogFrames      int64 `json:"logFrames"`      // WAL 中的帧数
+	Checkpointed   int64 `json:"checkpointed"`   // 已写回数据库的帧数
+	CheckpointedAt int64 `json:"checkpointedAt"` // 执行时间（时间戳毫秒）
+}
+
+// DatabaseHealth 数据库健康状态
+type DatabaseHealth struct {
+	Type           string                `json:"type"`   // sqlite, postgres, mysql
+	Status         string                `json:"status"` // ok, corrupted, unreachable
+	Error          string                `json:"error,omitempty"`
+	JournalMode    string                `json:"journalMode,omitempty"`
+	AutoVacuum     string                `json:"autoVacuum,omitempty"`
+	SizeBytes      int64                 `json:"sizeBytes,omitempty"`
+	FreeBytes      int64                 `json:"freeBytes,omitempty"`
+	Integrity      *IntegrityCheckResult `json:"integrity,omitempty"`
+	LastVacuum     *VacuumResult         `json:"lastVacuum,omitempty"`
+	LastCheckpoint *CheckpointResult     `json:"lastCheckpoint,omitempty"`
+}
+
+// DatabaseService 数据库维护服务，SQLite 部署下定时执行完整性检查、增量回收和 WAL 检查点
+type DatabaseService struct {
+	logger       *zap.Logger
+	db           *gorm.DB
+	alertService *AlertService
+
+	mu             sync.RWMutex
+	integrity      *IntegrityCheckResult
+	lastVacuum     *VacuumResult
+	lastCheckpoint *CheckpointResult
+}
+
+func NewDatabaseService(logger *zap.Logger, db *gorm.DB, alertService *AlertService) *DatabaseService {
+	return &DatabaseService{
+		logger:       logger.Named("database"),
+		db:           db,
+		alertService: alertService,
+	}
+}
+
+// isSQLite 是否为 SQLite 数据库
+func (s *DatabaseService) isSQLite() bool {
+	return s.db.Dialector.Name() == "sqlite"
+}
+
+// Run 启动 SQLite 维护任务，其他数据库由数据库自身负责维护，直接返回
+func (s *DatabaseService) Run(ctx context.Context) {
+	if !s.isSQLite() {
+		return
+	}
+
+	s.logger.Info("SQLite 维护任务已启动")
+	s.prepareAutoVacuum(ctx)
+
+	checkpointTicker := time.NewTicker(walCheckpointInterval)
+	defer checkpointTicker.Stop()
+	vacuumTicker := time.NewTicker(incrementalVacuumInterval)
+	defer vacuumTicker.Stop()
+	integrityTimer := time.NewTimer(integrityCheckDelay)
+	defer integrityTimer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("SQLite 维护任务已停止")
+			return
+		case <-checkpointTicker.C:
+			if _, err := s.Checkpoint(ctx); err != nil {
+				s.logger.Error("WAL 检查点执行失败", zap.Error(err))
+			}
+		case <-vacuumTicker.C:
+			if _, err := s.IncrementalVacuum(ctx); err != nil {
+				s.logger.Error("增量回收执行失败", zap.Error(err))
+			}
+		case <-integrityTimer.C:
+			if _, err := s.CheckIntegrity(ctx); err != nil {
+				s.logger.Error("完整性检查执行失败", zap.Error(err))
+			}
+			integrityTimer.Reset(integrityCheckInterval)
+		}
+	}
+}
+
+// prepareAutoVacuum 检查 auto_vacuum 模式，未开启增量回收时小库自动切换，大库提示手动处理
+func (s *DatabaseService) prepareAutoVacuum(ctx context.Context) {
+	mode, err := s.pragmaInt(ctx, "auto_vacuum")
+	if err != nil {
+		s.logger.Error("读取 auto_vacuum 配置失败", zap.Error(err))
+		return
+	}
+	if mode == 2 {
+		return
+	}
+
+	size, _, err := s.databaseSize(ctx)
+	if err != nil {
+		s.logger.Error("读取数据库大小失败", zap.Error(err))
+		return
+	}
+	if size > autoVacuumConvertMaxSize {
+		s.logger.Warn("SQLite 未开启增量回收，数据库较大未自动切换，可在停机后执行: PRAGMA auto_vacuum = INCREMENTAL; VACUUM;",
+			zap.Int64("sizeBytes", size))
+		return
+	}
+
+	// 修改 auto_vacuum 后需要执行一次 VACUUM 才能生效
+	start := time.Now()
+	db := s.db.WithContext(ctx)
+	if err := db.Exec("PRAGMA auto_vacuum = INCREMENTAL").Error; err != nil {
+		s.logger.Error("设置 auto_vacuum 失败", zap.Error(err))
+		return
+	}
+	if err := db.Exec("VACUUM").Error; err != nil {
+		s.logger.Error("切换增量回收模式失败", zap.Error(err))
+		return
+	}
+	s.logger.Info("SQLite 已切换为增量回收模式", zap.Int64("sizeBytes", size), zap.Duration("duration", time.Since(start)))
+}
+
+// CheckIntegrity 执行 PRAGMA integrity_check，发现损坏时触发告警
+func (s *DatabaseService) CheckIntegrity(ctx context.Context) (*IntegrityCheckResult, error) {
+	start := time.Now()
+	var rows []string
+	err := s.db.WithContext(ctx).
+		Raw(fmt.Sprintf("PRAGMA integrity_check(%d)", integrityCheckMaxErrors)).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := &IntegrityCheckResult{
+		OK:         len(rows) == 1 && rows[0] == "ok",
+		DurationMs: time.Since(start).Milliseconds(),
+		CheckedAt:  time.Now().UnixMilli(),
+	}
+	if !result.OK {
+		result.Errors = rows
+	}
+
+	s.mu.Lock()
+	wasCorrupted := s.integrity != nil && !s.integrity.OK
+	s.integrity = result
+	s.mu.Unlock()
+
+	if result.OK {
+		if wasCorrupted {
+			s.logger.Info("数据库完整性检查已恢复正常")
+		} else {
+			s.logger.Debug("数据库完整性检查通过", zap.Int64("durationMs", result.DurationMs))
+		}
+		return result, nil
+	}
+
+	s.logger.Error("数据库完整性检查发现损坏", zap.Strings("errors", result.Errors))
+	// 只在首次发现损坏时告警，避免每次检查重复通知
+	if !wasCorrupted {
+		message := fmt.Sprintf("SQLite 数据库完整性检查发现 %d 处错误，请尽快备份并修复数据库: %s",
+			len(result.Errors), result.Errors[0])
+		if err := s.alertService.FireSystemAlert(ctx, "database", "warning", message); err != nil {
+			s.logger.Error("发送数据库损坏告警失败", zap.Error(err))
+		}
+	}
+	return result, nil
+}
+
+// IncrementalVacuum 回收全部空闲页，仅在 auto_vacuum 为 INCREMENTAL 时生效
+func (s *DatabaseService) IncrementalVacuum(ctx context.Context) (*VacuumResult, error) {
+	start := time.Now()
+	before, err := s.pragmaInt(ctx, "freelist_count")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).Exec("PRAGMA incremental_vacuum").Error; err != nil {
+		return nil, err
+	}
+	after, err := s.pragmaInt(ctx, "freelist_count")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &VacuumResult{
+		FreedPages: before - after,
+		DurationMs: time.Since(start).Milliseconds(),
+		VacuumedAt: time.Now().UnixMilli(),
+	}
+	s.mu.Lock()
+	s.lastVacuum = result
+	s.mu.Unlock()
+
+	if result.FreedPages > 0 {
+		s.logger.Info("增量回收完成", zap.Int64("freedPages", result.FreedPages), zap.Int64("durationMs", result.DurationMs))
+	}
+	return result, nil
+}
+
+// Checkpoint 执行 WAL 检查点并截断 WAL 文件，非 WAL 模式时跳过
+func (s *DatabaseService) Checkpoint(ctx context.Context) (*CheckpointResult, error) {
+	journalMode, err := s.pragmaString(ctx, "journal_mode")
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(journalMode, "wal") {
+		return nil, nil
+	}
+
+	var busy int
+	result := &CheckpointResult{}
+	row := s.db.WithContext(ctx).Raw("PRAGMA wal_checkpoint(TRUNCATE)").Row()
+	if err := row.Scan(&busy, &result.LogFrames, &result.Checkpointed); err != nil {
+		return nil, err
+	}
+	result.Busy = busy != 0
+	result.CheckpointedAt = time.Now().UnixMilli()
+
+	s.mu.Lock()
+	s.lastCheckpoint = result
+	s.mu.Unlock()
+
+	if result.Busy {
+		s.logger.Warn("WAL 检查点未完成，存在长时间占用的读写连接", zap.Int64("logFrames", result.LogFrames), zap.Int64("checkpointed", result.Checkpointed))
+	}
+	return result, nil
+}
+
+// GetHealth 获取数据库健康状态
+func (s *DatabaseService) GetHealth(ctx context.Context) *DatabaseHealth {
+	health := &DatabaseHealth{
+		Type:   s.db.Dialector.Name(),
+		Status: DatabaseStatusOK,
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	sqlDB, err := s.db.DB()
+	if err == nil {
+		err = sqlDB.PingContext(ctx)
+	}
+	if err != nil {
+		health.Status = DatabaseStatusUnreachable
+		health.Error = err.Error()
+		return health
+	}
+
+	if !s.isSQLite() {
+		return health
+	}
+
+	health.JournalMode, _ = s.pragmaString(ctx, "journal_mode")
+	if mode, err := s.pragmaInt(ctx, "auto_vacuum"); err == nil {
+		health.AutoVacuum = autoVacuumModeName(mode)
+	}
+	health.SizeBytes, health.FreeBytes, _ = s.databaseSize(ctx)
+
+	s.mu.RLock()
+	health.Integrity = s.integrity
+	health.LastVacuum = s.lastVacuum
+	health.LastCheckpoint = s.lastCheckpoint
+	s.mu.RUnlock()
+
+	if health.Integrity != nil && !health.Integrity.OK {
+		health.Status = DatabaseStatusCorrupted
+	}
+	return health
+}
+
+// databaseSize 返回数据库文件大小和空闲页占用的大小（字节）
+func (s *DatabaseService) databaseSize(ctx context.Context) (size, free int64, err error) {
+	pageSize, err := s.pragmaInt(ctx, "page_size")
+	if err != nil {
+		return 0, 0, err
+	}
+	pageCount, err := s.pragmaInt(ctx, "page_count")
+	if err != nil {
+		return 0, 0, err
+	}
+	freelist, err := s.pragmaInt(ctx, "freelist_count")
+	if err != nil {
+		return 0, 0, err
+	}
+	return pageSize * pageCount, pageSize * freelist, nil
+}
+
+func (s *DatabaseService) pragmaInt(ctx context.Context, name string) (int64, error) {
+	var value int64
+	err := s.db.WithContext(ctx).Raw("PRAGMA " + name).Row().Scan(&value)
+	return value, err
+}
+
+func (s *DatabaseService) pragmaString(ctx context.Context, name string) (string, error) {
+	var value string
+	err := s.db.WithContext(ctx).Raw("PRAGMA " + name).Row().Scan(&value)
+	return value, err
+}
+
+func autoVacuumModeName(mode int64) string {
+	switch mode {
+	case 1:
+		return "full"
+	case 2:
+		return "incremental"
+	}
+	return "none"
+}
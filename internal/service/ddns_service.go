@@ -31,6 +31,7 @@ type DDNSService struct {
 	recordRepo      *repo.DDNSRecordRepo
 	propertyService *PropertyService
 	wsManager       *websocket.Manager
+	eventNotifier   *EventNotifier
 	ipCache         *syncx.SafeMap[string, *ipCacheData] // 使用内存缓存存储 IP
 }
 
@@ -40,13 +41,15 @@ func NewDDNSService(
 	recordRepo *repo.DDNSRecordRepo,
 	propertyService *PropertyService,
 	wsManager *websocket.Manager,
+	eventNotifier *EventNotifier,
 ) *DDNSService {
 	s := &DDNSService{
-		logger:          logger,
+		logger:          logger.Named("ddns"),
 		ConfigRepo:      configRepo,
 		recordRepo:      recordRepo,
 		propertyService: propertyService,
 		wsManager:       wsManager,
+		eventNotifier:   eventNotifier,
 		ipCache:         syncx.NewSafeMap[string, *ipCacheData](),
 	}
 
@@ -249,6 +252,8 @@ func (s *DDNSService) updateRecord(
 			zap.String("recordType", recordType),
 			zap.String("newIP", newIP),
 			zap.Error(err))
+		s.eventNotifier.Notify(config.AgentID, models.NotificationEventDDNS, "warning",
+			fmt.Sprintf("域名 %s 的 %s 记录更新为 %s 失败: %v", domain, recordType, newIP, err))
 	} else {
 		record.Status = "success"
 		s.logger.Info("DNS 记录更新成功",
@@ -256,6 +261,8 @@ func (s *DDNSService) updateRecord(
 			zap.String("recordType", recordType),
 			zap.String("oldIP", oldIP),
 			zap.String("newIP", newIP))
+		s.eventNotifier.Notify(config.AgentID, models.NotificationEventDDNS, "info",
+			fmt.Sprintf("域名 %s 的 %s 记录已从 %s 更新为 %s", domain, recordType, orDash(oldIP), newIP))
 	}
 
 	// 保存更新记录
@@ -0,0 +1,538 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/internal/repo"
+	"github.com/dushixiang/pika/pkg/version"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const (
+	demoHistory         = 24 * time.Hour   // 预置历史数据的时长
+	demoHistoryStep     = time.Minute      // 历史数据的采样间隔
+	demoMetricsInterval = 10 * time.Second // 实时指标的模拟上报间隔
+	demoMonitorInterval = time.Minute      // 服务监控的模拟检测间隔
+	demoBatchSize       = 500
+	demoGB              = 1 << 30
+)
+
+// demoHostSpec 演示主机的固定规格
+type demoHostSpec struct {
+	Name      string
+	OS        string
+	Platform  string
+	Arch      string
+	IP        string
+	Tags      []string
+	Cores     int
+	MemoryGB  uint64
+	DiskGB    uint64
+	CPUBase   float64
+	MemBase   float64
+	DiskUsage float64
+}
+
+var demoHostSpecs = []demoHostSpec{
+	{Name: "tokyo-web-01", OS: "linux", Platform: "ubuntu", Arch: "amd64", IP: "203.0.113.11", Tags: []string{"生产", "Web"}, Cores: 4, MemoryGB: 8, DiskGB: 80, CPUBase: 25, MemBase: 45, DiskUsage: 0.42},
+	{Name: "tokyo-web-02", OS: "linux", Platform: "ubuntu", Arch: "amd64", IP: "203.0.113.12", Tags: []string{"生产", "Web"}, Cores: 4, MemoryGB: 8, DiskGB: 80, CPUBase: 22, MemBase: 48, DiskUsage: 0.40},
+	{Name: "frankfurt-db-01", OS: "linux", Platform: "debian", Arch: "amd64", IP: "198.51.100.21", Tags: []string{"生产", "数据库"}, Cores: 16, MemoryGB: 64, DiskGB: 1000, CPUBase: 35, MemBase: 78, DiskUsage: 0.91},
+	{Name: "us-west-api-01", OS: "linux", Platform: "rocky", Arch: "arm64", IP: "198.51.100.35", Tags: []string{"生产", "API"}, Cores: 8, MemoryGB: 16, DiskGB: 160, CPUBase: 55, MemBase: 60, DiskUsage: 0.55},
+	{Name: "hk-gateway-01", OS: "linux", Platform: "alpine", Arch: "amd64", IP: "192.0.2.40", Tags: []string{"网关"}, Cores: 2, MemoryGB: 2, DiskGB: 20, CPUBase: 12, MemBase: 35, DiskUsage: 0.30},
+	{Name: "office-win-01", OS: "windows", Platform: "Microsoft Windows Server 2022", Arch: "amd64", IP: "192.0.2.88", Tags: []string{"办公"}, Cores: 8, MemoryGB: 32, DiskGB: 500, CPUBase: 15, MemBase: 55, DiskUsage: 0.65},
+}
+
+// demoMonitorSpec 演示服务监控
+type demoMonitorSpec struct {
+	Name    string
+	Type    string
+	Target  string
+	BaseRTT int64
+	Hosts   []int // 执行检测的演示主机下标
+}
+
+var demoMonitorSpecs = []demoMonitorSpec{
+	{Name: "官网", Type: "http", Target: "https://www.example.com", BaseRTT: 120, Hosts: []int{0, 3, 4}},
+	{Name: "API 健康检查", Type: "http", Target: "https://api.example.com/health", BaseRTT: 80, Hosts: []int{0, 1, 3}},
+	{Name: "PostgreSQL", Type: "tcp", Target: "10.0.0.21:5432", BaseRTT: 3, Hosts: []int{0, 1}},
+}
+
+// demoHost 演示主机，指标围绕基线随机游走
+type demoHost struct {
+	id       string
+	spec     demoHostSpec
+	bootTime time.Time
+
+	cpu        float64
+	mem        float64
+	diskUsed   uint64
+	sentTotal  uint64
+	recvTotal  uint64
+	readBytes  uint64
+	writeBytes uint64
+}
+
+// DemoService 演示模式：预置探针、指标历史、服务监控和告警记录，并持续模拟实时上报，
+// 无需部署探针即可体验界面和接口
+type DemoService struct {
+	logger          *zap.Logger
+	db              *gorm.DB
+	agentRepo       *repo.AgentRepo
+	alertRecordRepo *repo.AlertRecordRepo
+	metricService   *MetricService
+	monitorService  *MonitorService
+
+	rng      *rand.Rand
+	hosts    []*demoHost
+	monitors []*models.MonitorTask
+}
+
+func NewDemoService(logger *zap.Logger, db *gorm.DB, metricService *MetricService, monitorService *MonitorService) *DemoService {
+	return &DemoService{
+		logger:          logger.Named("demo"),
+		db:              db,
+		agentRepo:       repo.NewAgentRepo(db),
+		alertRecordRepo: repo.NewAlertRecordRepo(db),
+		metricService:   metricService,
+		monitorService:  monitorService,
+		rng:             rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Run 预置演示数据后持续模拟探针上报，直到 ctx 被取消
+func (s *DemoService) Run(ctx context.Context) {
+	if err := s.seed(ctx); err != nil {
+		s.logger.Error("预置演示数据失败", zap.Error(err))
+		return
+	}
+
+	metricsTicker := time.NewTicker(demoMetricsInterval)
+	defer metricsTicker.Stop()
+	monitorTicker := time.NewTicker(demoMonitorInterval)
+	defer monitorTicker.Stop()
+
+	s.logger.Info("演示数据模拟已启动", zap.Int("agents", len(s.hosts)), zap.Int("monitors", len(s.monitors)))
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("演示数据模拟已停止")
+			return
+		case <-metricsTicker.C:
+			s.reportMetrics(ctx)
+		case <-monitorTicker.C:
+			s.reportMonitors(ctx)
+		}
+	}
+}
+
+// seed 写入演示探针、最近 24 小时的指标和监控历史以及告警记录
+func (s *DemoService) seed(ctx context.Context) error {
+	now := time.Now()
+	start := now.Add(-demoHistory)
+
+	for i, spec := range demoHostSpecs {
+		host := &demoHost{
+			id:       fmt.Sprintf("demo-%02d", i+1),
+			spec:     spec,
+			bootTime: now.Add(-time.Duration(24+s.rng.Intn(60*24)) * time.Hour),
+			cpu:      spec.CPUBase,
+			mem:      spec.MemBase,
+			diskUsed: uint64(float64(spec.DiskGB*demoGB) * spec.DiskUsage),
+		}
+		s.hosts = append(s.hosts, host)
+
+		agent := &models.Agent{
+			ID:         host.id,
+			Name:       spec.Name,
+			Hostname:   spec.Name,
+			IP:         spec.IP,
+			OS:         spec.OS,
+			Arch:       spec.Arch,
+			Version:    version.GetAgentVersion(),
+			Tags:       datatypes.JSONSlice[string](spec.Tags),
+			ExpireTime: now.AddDate(0, 1+s.rng.Intn(11), 0).UnixMilli(),
+			Status:     1,
+			Visibility: "public",
+			LastSeenAt: now.UnixMilli(),
+			CreatedAt:  start.UnixMilli(),
+		}
+		if err := s.agentRepo.Save(ctx, agent); err != nil {
+			return err
+		}
+	}
+
+	if err := s.seedMetrics(ctx, start, now); err != nil {
+		return err
+	}
+	if err := s.seedMonitors(ctx, start, now); err != nil {
+		return err
+	}
+	if err := s.seedAlerts(ctx, now); err != nil {
+		return err
+	}
+
+	// 写入一次实时数据，保证最新指标缓存有值
+	s.reportMetrics(ctx)
+	s.reportMonitors(ctx)
+	return nil
+}
+
+// seedMetrics 批量写入历史指标，聚合任务会在下个周期把它们下采样到聚合表
+func (s *DemoService) seedMetrics(ctx context.Context, start, end time.Time) error {
+	var (
+		cpuMetrics  []models.CPUMetric
+		memMetrics  []models.MemoryMetric
+		diskMetrics []models.DiskMetric
+		netMetrics  []models.NetworkMetric
+		connMetrics []models.NetworkConnectionMetric
+		ioMetrics   []models.DiskIOMetric
+	)
+
+	for t := start; t.Before(end); t = t.Add(demoHistoryStep) {
+		ts := t.UnixMilli()
+		// 白天负载更高，让图表呈现明显的日周期
+		load := 1 + 0.4*math.Sin(float64(t.Hour()-8)/24*2*math.Pi)
+		for _, host := range s.hosts {
+			host.step(s.rng, demoHistoryStep, load)
+
+			cpu := host.cpuData(s.rng)
+			mem := host.memoryData()
+			disk := host.diskData()[0]
+			net := host.networkData(s.rng)[0]
+			conn := host.networkConnectionData(s.rng)
+			io := host.diskIOData(s.rng)[0]
+
+			cpuMetrics = append(cpuMetrics, models.CPUMetric{AgentID: host.id, UsagePercent: cpu.UsagePercent, LogicalCores: cpu.LogicalCores, PhysicalCores: cpu.PhysicalCores, ModelName: cpu.ModelName, Timestamp: ts})
+			memMetrics = append(memMetrics, models.MemoryMetric{AgentID: host.id, Total: mem.Total, Used: mem.Used, Free: mem.Free, Available: mem.Available, UsagePercent: mem.UsagePercent, Timestamp: ts})
+			diskMetrics = append(diskMetrics, models.DiskMetric{AgentID: host.id, MountPoint: "all", Total: disk.Total, Used: disk.Used, Free: disk.Free, UsagePercent: disk.UsagePercent, Timestamp: ts})
+			for _, iface := range []string{net.Interface, "all"} {
+				netMetrics = append(netMetrics, models.NetworkMetric{AgentID: host.id, Interface: iface, BytesSentRate: net.BytesSentRate, BytesRecvRate: net.BytesRecvRate, BytesSentTotal: net.BytesSentTotal, BytesRecvTotal: net.BytesRecvTotal, Timestamp: ts})
+			}
+			connMetrics = append(connMetrics, models.NetworkConnectionMetric{AgentID: host.id, Established: conn.Established, TimeWait: conn.TimeWait, Listen: conn.Listen, Total: conn.Total, Timestamp: ts})
+			ioMetrics = append(ioMetrics, models.DiskIOMetric{AgentID: host.id, Device: "all", ReadBytes: io.ReadBytes, WriteBytes: io.WriteBytes, ReadBytesRate: io.ReadBytesRate, WriteBytesRate: io.WriteBytesRate, Timestamp: ts})
+		}
+	}
+
+	db := s.db.WithContext(ctx)
+	for _, batch := range []any{&cpuMetrics, &memMetrics, &diskMetrics, &netMetrics, &connMetrics, &ioMetrics} {
+		if err := db.CreateInBatches(batch, demoBatchSize).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// seedMonitors 创建演示服务监控并写入历史检测结果，其中一个监控在 6 小时前有一段持续 15 分钟的故障
+func (s *DemoService) seedMonitors(ctx context.Context, start, end time.Time) error {
+	outageStart := end.Add(-6 * time.Hour)
+	outageEnd := outageStart.Add(15 * time.Minute)
+
+	var metrics []models.MonitorMetric
+	for i, spec := range demoMonitorSpecs {
+		agentIDs := make([]string, 0, len(spec.Hosts))
+		for _, index := range spec.Hosts {
+			agentIDs = append(agentIDs, s.hosts[index].id)
+		}
+		task, err := s.monitorService.CreateMonitor(ctx, &MonitorTaskRequest{
+			Name:             spec.Name,
+			Type:             spec.Type,
+			Target:           spec.Target,
+			Description:      "演示数据",
+			Enabled:          true,
+			ShowTargetPublic: true,
+			Visibility:       "public",
+			Interval:         int(demoMonitorInterval.Seconds()),
+			AgentIds:         agentIDs,
+		})
+		if err != nil {
+			return err
+		}
+		s.monitors = append(s.monitors, task)
+
+		for t := start; t.Before(end); t = t.Add(demoMonitorInterval) {
+			down := i == 1 && !t.Before(outageStart) && t.Before(outageEnd)
+			for _, agentID := range agentIDs {
+				result := s.monitorResult(task, spec, t, down)
+				metrics = append(metrics, models.MonitorMetric{
+					AgentId:        agentID,
+					MonitorId:      result.ID,
+					Type:           result.Type,
+					Target:         result.Target,
+					Status:         result.Status,
+					StatusCode:     result.StatusCode,
+					ResponseTime:   result.ResponseTime,
+					Error:          result.Error,
+					CertExpiryTime: result.CertExpiryTime,
+					CertDaysLeft:   result.CertDaysLeft,
+					Timestamp:      result.CheckedAt,
+				})
+			}
+		}
+	}
+	return s.db.WithContext(ctx).CreateInBatches(&metrics, demoBatchSize).Error
+}
+
+// seedAlerts 写入若干已恢复的历史告警，以及一条磁盘空间不足的进行中告警
+func (s *DemoService) seedAlerts(ctx context.Context, now time.Time) error {
+	type demoAlert struct {
+		host      int
+		alertType string
+		level     string
+		threshold float64
+		value     float64
+		firedAgo  time.Duration
+		lasted    time.Duration
+	}
+	alertNames := map[string]string{"cpu": "CPU", "memory": "内存", "disk": "磁盘"}
+	alerts := []demoAlert{
+		{host: 3, alertType: "cpu", level: "warning", threshold: 80, value: 91.3, firedAgo: 20 * time.Hour, lasted: 12 * time.Minute},
+		{host: 0, alertType: "memory", level: "warning", threshold: 85, value: 88.6, firedAgo: 9 * time.Hour, lasted: 25 * time.Minute},
+		{host: 3, alertType: "cpu", level: "critical", threshold: 80, value: 98.2, firedAgo: 3 * time.Hour, lasted: 8 * time.Minute},
+		{host: 2, alertType: "disk", level: "warning", threshold: 90, value: 91.0, firedAgo: 2 * time.Hour},
+	}
+
+	for _, alert := range alerts {
+		host := s.hosts[alert.host]
+		firedAt := now.Add(-alert.firedAgo)
+		record := &models.AlertRecord{
+			AgentID:     host.id,
+			AgentName:   host.spec.Name,
+			AlertType:   alert.alertType,
+			Message:     fmt.Sprintf("%s 使用率 %.1f%% 超过阈值 %.0f%%", alertNames[alert.alertType], alert.value, alert.threshold),
+			Threshold:   alert.threshold,
+			ActualValue: alert.value,
+			Level:       alert.level,
+			Status:      "firing",
+			FiredAt:     firedAt.UnixMilli(),
+			CreatedAt:   firedAt.UnixMilli(),
+		}
+		if alert.lasted > 0 {
+			record.Status = "resolved"
+			record.ResolvedAt = firedAt.Add(alert.lasted).UnixMilli()
+		}
+		if err := s.alertRecordRepo.CreateAlertRecord(ctx, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reportMetrics 按真实探针的上报流程写入一轮实时指标，告警判断、最新指标缓存和 remote_write 转发都会照常生效
+func (s *DemoService) reportMetrics(ctx context.Context) {
+	now := time.Now()
+	load := 1 + 0.4*math.Sin(float64(now.Hour()-8)/24*2*math.Pi)
+	for _, host := range s.hosts {
+		host.step(s.rng, demoMetricsInterval, load)
+
+		metrics := []struct {
+			metricType protocol.MetricType
+			data       any
+		}{
+			{protocol.MetricTypeCPU, host.cpuData(s.rng)},
+			{protocol.MetricTypeMemory, host.memoryData()},
+			{protocol.MetricTypeDisk, host.diskData()},
+			{protocol.MetricTypeDiskIO, host.diskIOData(s.rng)},
+			{protocol.MetricTypeNetwork, host.networkData(s.rng)},
+			{protocol.MetricTypeNetworkConnection, host.networkConnectionData(s.rng)},
+			{protocol.MetricTypeHost, host.hostData(s.rng)},
+		}
+		for _, m := range metrics {
+			data, err := json.Marshal(m.data)
+			if err != nil {
+				continue
+			}
+			if err := s.metricService.HandleMetricData(ctx, host.id, string(m.metricType), data); err != nil {
+				s.logger.Warn("写入演示指标失败", zap.String("agentId", host.id), zap.String("type", string(m.metricType)), zap.Error(err))
+			}
+		}
+		if err := s.agentRepo.UpdateStatus(ctx, host.id, 1, now.UnixMilli()); err != nil {
+			s.logger.Warn("更新演示探针状态失败", zap.String("agentId", host.id), zap.Error(err))
+		}
+	}
+}
+
+// reportMonitors 写入一轮服务监控检测结果
+func (s *DemoService) reportMonitors(ctx context.Context) {
+	now := time.Now()
+	results := make(map[string][]protocol.MonitorData)
+	for i, task := range s.monitors {
+		spec := demoMonitorSpecs[i]
+		for _, agentID := range task.AgentIds {
+			// 偶发单点检测失败，模拟网络抖动
+			down := s.rng.Float64() < 0.01
+			results[agentID] = append(results[agentID], s.monitorResult(task, spec, now, down))
+		}
+	}
+
+	for agentID, items := range results {
+		data, err := json.Marshal(items)
+		if err != nil {
+			continue
+		}
+		if err := s.metricService.HandleMetricData(ctx, agentID, string(protocol.MetricTypeMonitor), data); err != nil {
+			s.logger.Warn("写入演示监控数据失败", zap.String("agentId", agentID), zap.Error(err))
+			continue
+		}
+		s.monitorService.HandleMonitorResults(ctx, agentID, items)
+	}
+}
+
+func (s *DemoService) monitorResult(task *models.MonitorTask, spec demoMonitorSpec, t time.Time, down bool) protocol.MonitorData {
+	result := protocol.MonitorData{
+		ID:           task.ID,
+		Type:         spec.Type,
+		Target:       spec.Target,
+		Status:       "up",
+		ResponseTime: spec.BaseRTT + int64(s.rng.NormFloat64()*float64(spec.BaseRTT)/5),
+		CheckedAt:    t.UnixMilli(),
+	}
+	if result.ResponseTime < 1 {
+		result.ResponseTime = 1
+	}
+	if spec.Type == "http" {
+		result.StatusCode = 200
+		expiry := time.Now().AddDate(0, 0, 45)
+		result.CertExpiryTime = expiry.UnixMilli()
+		result.CertDaysLeft = int(time.Until(expiry).Hours() / 24)
+	}
+	if down {
+		result.Status = "down"
+		result.ResponseTime = 0
+		result.StatusCode = 0
+		result.Error = "context deadline exceeded"
+	}
+	return result
+}
+
+// step 推进模拟状态：CPU 和内存围绕基线（乘以日周期负载系数）做均值回归的随机游走
+func (h *demoHost) step(rng *rand.Rand, elapsed time.Duration, load float64) {
+	h.cpu = demoWalk(rng, h.cpu, math.Min(95, h.spec.CPUBase*load), 6)
+	h.mem = demoWalk(rng, h.mem, h.spec.MemBase, 1.5)
+
+	seconds := elapsed.Seconds()
+	h.sentTotal += uint64(h.netRate(rng) * seconds)
+	h.recvTotal += uint64(h.netRate(rng) * seconds * 1.6)
+	h.readBytes += uint64(h.ioRate(rng) * seconds)
+	h.writeBytes += uint64(h.ioRate(rng) * seconds)
+
+	// 磁盘缓慢增长，接近写满时回落，模拟日志清理
+	total := h.spec.DiskGB * demoGB
+	h.diskUsed += uint64(rng.Int63n(int64(elapsed/time.Second) * 64 << 10))
+	if h.diskUsed > total*96/100 {
+		h.diskUsed = uint64(float64(total) * h.spec.DiskUsage)
+	}
+}
+
+// demoWalk 均值回归的随机游走，结果限制在 0~100
+func demoWalk(rng *rand.Rand, current, baseline, volatility float64) float64 {
+	next := current + (baseline-current)*0.2 + rng.NormFloat64()*volatility
+	return math.Min(100, math.Max(0, next))
+}
+
+// netRate 当前网络速率（字节/秒），与 CPU 负载正相关
+func (h *demoHost) netRate(rng *rand.Rand) float64 {
+	return (h.cpu/100*8 + rng.Float64()*0.5) * 1024 * 1024
+}
+
+// ioRate 当前磁盘 IO 速率（字节/秒）
+func (h *demoHost) ioRate(rng *rand.Rand) float64 {
+	return (h.cpu/100*20 + rng.Float64()) * 1024 * 1024
+}
+
+func (h *demoHost) cpuData(rng *rand.Rand) protocol.CPUData {
+	perCore := make([]float64, h.spec.Cores)
+	for i := range perCore {
+		perCore[i] = math.Min(100, math.Max(0, h.cpu+rng.NormFloat64()*5))
+	}
+	return protocol.CPUData{
+		LogicalCores:  h.spec.Cores,
+		PhysicalCores: max(1, h.spec.Cores/2),
+		ModelName:     "Pika Demo CPU @ 2.40GHz",
+		UsagePercent:  h.cpu,
+		PerCore:       perCore,
+	}
+}
+
+func (h *demoHost) memoryData() protocol.MemoryData {
+	total := h.spec.MemoryGB * demoGB
+	used := uint64(float64(total) * h.mem / 100)
+	return protocol.MemoryData{
+		Total:        total,
+		Used:         used,
+		Free:         total - used,
+		Available:    total - used,
+		UsagePercent: h.mem,
+	}
+}
+
+func (h *demoHost) diskData() []protocol.DiskData {
+	total := h.spec.DiskGB * demoGB
+	mountPoint, device, fstype := "/", "/dev/vda1", "ext4"
+	if h.spec.OS == "windows" {
+		mountPoint, device, fstype = "C:", "C:", "NTFS"
+	}
+	return []protocol.DiskData{{
+		MountPoint:   mountPoint,
+		Device:       device,
+		Fstype:       fstype,
+		Total:        total,
+		Used:         h.diskUsed,
+		Free:         total - h.diskUsed,
+		UsagePercent: float64(h.diskUsed) / float64(total) * 100,
+	}}
+}
+
+func (h *demoHost) diskIOData(rng *rand.Rand) []protocol.DiskIOData {
+	return []protocol.DiskIOData{{
+		Device:         "vda",
+		ReadBytes:      h.readBytes,
+		WriteBytes:     h.writeBytes,
+		ReadBytesRate:  uint64(h.ioRate(rng)),
+		WriteBytesRate: uint64(h.ioRate(rng)),
+	}}
+}
+
+func (h *demoHost) networkData(rng *rand.Rand) []protocol.NetworkData {
+	rate := h.netRate(rng)
+	return []protocol.NetworkData{{
+		Interface:      "eth0",
+		BytesSentRate:  uint64(rate),
+		BytesRecvRate:  uint64(rate * 1.6),
+		BytesSentTotal: h.sentTotal,
+		BytesRecvTotal: h.recvTotal,
+	}}
+}
+
+func (h *demoHost) networkConnectionData(rng *rand.Rand) protocol.NetworkConnectionData {
+	established := uint32(20 + h.cpu*4 + rng.Float64()*20)
+	timeWait := uint32(rng.Intn(40))
+	listen := uint32(8)
+	return protocol.NetworkConnectionData{
+		Established: established,
+		TimeWait:    timeWait,
+		Listen:      listen,
+		Total:       established + timeWait + listen,
+	}
+}
+
+func (h *demoHost) hostData(rng *rand.Rand) protocol.HostInfoData {
+	return protocol.HostInfoData{
+		Hostname:   h.spec.Name,
+		Uptime:     uint64(time.Since(h.bootTime).Seconds()),
+		BootTime:   uint64(h.bootTime.Unix()),
+		Procs:      uint64(120 + rng.Intn(80)),
+		OS:         h.spec.OS,
+		Platform:   h.spec.Platform,
+		KernelArch: h.spec.Arch,
+	}
+}
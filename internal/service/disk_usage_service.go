@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+	ws "github.com/dushixiang/pika/internal/websocket"
+	"github.com/go-orz/orz"
+	"go.uber.org/zap"
+)
+
+// diskUsageScanTimeout 扫描超过该时间仍未返回时视为失败，允许重新发起（探针端扫描超时为 2 分钟）
+const diskUsageScanTimeout = 5 * time.Minute
+
+// DiskUsageScan 磁盘占用分析任务，只在内存中保留每个探针最近一次的结果
+type DiskUsageScan struct {
+	ID         string                    `json:"id"`
+	AgentID    string                    `json:"agentId"`
+	Path       string                    `json:"path"`
+	Status     string                    `json:"status"` // running, success, error
+	Error      string                    `json:"error,omitempty"`
+	Result     *protocol.DiskUsageResult `json:"result,omitempty"`
+	StartedAt  int64                     `json:"startedAt"`
+	FinishedAt int64                     `json:"finishedAt,omitempty"`
+}
+
+// DiskUsageService 磁盘占用分析服务，向探针下发 disk_usage 指令并保存结果
+type DiskUsageService struct {
+	logger    *zap.Logger
+	wsManager *ws.Manager
+
+	mu    sync.Mutex
+	scans map[string]*DiskUsageScan // agentID -> 最近一次扫描
+}
+
+func NewDiskUsageService(logger *zap.Logger, wsManager *ws.Manager) *DiskUsageService {
+	return &DiskUsageService{
+		logger:    logger.Named("disk-usage"),
+		wsManager: wsManager,
+		scans:     make(map[string]*DiskUsageScan),
+	}
+}
+
+// Scan 向探针下发磁盘占用分析指令，同一探针同时只允许一个扫描
+func (s *DiskUsageService) Scan(agentID string, req *protocol.DiskUsageRequest) (*DiskUsageScan, error) {
+	if req.Path == "" {
+		return nil, orz.NewError(400, "扫描路径不能为空")
+	}
+	if _, exists := s.wsManager.GetClient(agentID); !exists {
+		return nil, orz.NewError(400, "探针未连接")
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	if scan, ok := s.scans[agentID]; ok && scan.Status == "running" &&
+		now.Sub(time.UnixMilli(scan.StartedAt)) < diskUsageScanTimeout {
+		s.mu.Unlock()
+		return nil, orz.NewError(400, "已有磁盘占用分析正在进行")
+	}
+	scan := &DiskUsageScan{
+		ID:        fmt.Sprintf("disk_usage_%d", now.UnixMilli()),
+		AgentID:   agentID,
+		Path:      req.Path,
+		Status:    "running",
+		StartedAt: now.UnixMilli(),
+	}
+	s.scans[agentID] = scan
+	s.mu.Unlock()
+
+	if err := s.sendCommand(agentID, scan.ID, req); err != nil {
+		s.mu.Lock()
+		delete(s.scans, agentID)
+		s.mu.Unlock()
+		return nil, err
+	}
+
+	s.logger.Info("下发磁盘占用分析指令",
+		zap.String("agentId", agentID),
+		zap.String("path", req.Path),
+		zap.Int("depth", req.Depth))
+	copied := *scan
+	return &copied, nil
+}
+
+// sendCommand 发送 disk_usage 指令
+func (s *DiskUsageService) sendCommand(agentID, commandID string, req *protocol.DiskUsageRequest) error {
+	args, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	cmdData, err := json.Marshal(protocol.CommandRequest{
+		ID:   commandID,
+		Type: "disk_usage",
+		Args: string(args),
+	})
+	if err != nil {
+		return err
+	}
+	msgData, err := json.Marshal(protocol.Message{
+		Type: protocol.MessageTypeCommand,
+		Data: cmdData,
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.wsManager.SendToClient(agentID, msgData); err != nil {
+		return orz.NewError(500, "发送指令失败")
+	}
+	return nil
+}
+
+// Get 获取探针最近一次的磁盘占用分析，没有时返回 nil
+func (s *DiskUsageService) Get(agentID string) *DiskUsageScan {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scan, ok := s.scans[agentID]
+	if !ok {
+		return nil
+	}
+	copied := *scan
+	if copied.Status == "running" && time.Since(time.UnixMilli(copied.StartedAt)) >= diskUsageScanTimeout {
+		copied.Status = "error"
+		copied.Error = "探针未在规定时间内返回结果"
+	}
+	return &copied
+}
+
+// HandleCommandResponse 处理探针返回的磁盘占用分析结果
+func (s *DiskUsageService) HandleCommandResponse(ctx context.Context, agentID string, resp *protocol.CommandResponse) error {
+	if resp.Status == "running" {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scan, ok := s.scans[agentID]
+	if !ok || scan.ID != resp.ID {
+		// 已被新的扫描替换
+		return nil
+	}
+	scan.FinishedAt = time.Now().UnixMilli()
+	if resp.Status == "error" {
+		scan.Status = "error"
+		scan.Error = resp.Error
+		return nil
+	}
+
+	var result protocol.DiskUsageResult
+	if err := json.Unmarshal([]byte(resp.Result), &result); err != nil {
+		scan.Status = "error"
+		scan.Error = "解析扫描结果失败"
+		return err
+	}
+	scan.Status = "success"
+	scan.Result = &result
+	return nil
+}
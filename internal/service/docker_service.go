@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/dushixiang/pika/internal/config"
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/internal/repo"
+	ws "github.com/dushixiang/pika/internal/websocket"
+	"github.com/go-orz/orz"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// dockerListTimeout 获取容器列表超过该时间仍未返回时视为失败，允许重新获取（探针端超时为 30 秒）
+	dockerListTimeout = time.Minute
+	// dockerActionTimeout 等待探针返回容器操作结果的时间，超过后记录标记为失败
+	dockerActionTimeout = (protocol.DockerActionTimeout + 30) * time.Second
+)
+
+// DockerContainerList 探针的容器列表，只在内存中保留每个探针最近一次的结果
+type DockerContainerList struct {
+	ID         string                     `json:"id"`
+	AgentID    string                     `json:"agentId"`
+	Status     string                     `json:"status"` // running, success, error
+	Error      string                     `json:"error,omitempty"`
+	Containers []protocol.DockerContainer `json:"containers,omitempty"`
+	StartedAt  int64                      `json:"startedAt"`
+	FinishedAt int64                      `json:"finishedAt,omitempty"`
+}
+
+// DockerActionParams 容器操作参数，容器名称为用户操作时看到的信息，随记录一起保存
+type DockerActionParams struct {
+	protocol.DockerActionRequest
+	Name string `json:"name"`
+}
+
+// DockerService Docker 容器管理服务，负责获取探针的容器列表，以及下发并记录容器操作
+type DockerService struct {
+	logger              *zap.Logger
+	DockerOperationRepo *repo.DockerOperationRepo
+	wsManager           *ws.Manager
+	config              config.DockerConfig
+
+	mu    sync.Mutex
+	lists map[string]*DockerContainerList // agentID -> 最近一次获取的容器列表
+}
+
+func NewDockerService(logger *zap.Logger, db *gorm.DB, wsManager *ws.Manager, cfg *config.AppConfig) *DockerService {
+	s := &DockerService{
+		logger:              logger.Named("docker"),
+		DockerOperationRepo: repo.NewDockerOperationRepo(db),
+		wsManager:           wsManager,
+		lists:               make(map[string]*DockerContainerList),
+	}
+	if cfg.Docker != nil {
+		s.config = *cfg.Docker
+	}
+	return s
+}
+
+// AuthorizeAction 校验用户是否可以操作容器
+func (s *DockerService) AuthorizeAction(username string) error {
+	if len(s.config.AllowedUsers) > 0 && !slices.Contains(s.config.AllowedUsers, username) {
+		return orz.NewError(403, "没有操作容器的权限")
+	}
+	return nil
+}
+
+// Refresh 向探针下发获取容器列表指令，结果通过 GetContainers 轮询获取
+func (s *DockerService) Refresh(agentID string) (*DockerContainerList, error) {
+	if _, ok := s.wsManager.GetClient(agentID); !ok {
+		return nil, orz.NewError(400, "探针未连接")
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	if list, ok := s.lists[agentID]; ok && list.Status == "running" &&
+		now.Sub(time.UnixMilli(list.StartedAt)) < dockerListTimeout {
+		copied := *list
+		s.mu.Unlock()
+		return &copied, nil
+	}
+	list := &DockerContainerList{
+		ID:        fmt.Sprintf("docker_list_%d", now.UnixNano()),
+		AgentID:   agentID,
+		Status:    "running",
+		StartedAt: now.UnixMilli(),
+	}
+	// 保留上一次的容器列表，刷新期间页面不会变为空白
+	if prev, ok := s.lists[agentID]; ok {
+		list.Containers = prev.Containers
+	}
+	s.lists[agentID] = list
+	copied := *list
+	s.mu.Unlock()
+
+	if err := s.sendCommand(agentID, list.ID, "docker_list", nil); err != nil {
+		s.mu.Lock()
+		delete(s.lists, agentID)
+		s.mu.Unlock()
+		return nil, orz.NewError(500, "发送指令失败")
+	}
+	return &copied, nil
+}
+
+// GetContainers 获取探针最近一次的容器列表，没有时返回 nil
+func (s *DockerService) GetContainers(agentID string) *DockerContainerList {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, ok := s.lists[agentID]
+	if !ok {
+		return nil
+	}
+	copied := *list
+	if copied.Status == "running" && time.Since(time.UnixMilli(copied.StartedAt)) >= dockerListTimeout {
+		copied.Status = "error"
+		copied.Error = "探针未在规定时间内返回结果"
+	}
+	return &copied
+}
+
+// Execute 向探针下发容器操作指令并保存记录，执行结果异步更新到记录
+func (s *DockerService) Execute(ctx context.Context, agentID, username, clientIP string, params DockerActionParams) (*models.DockerOperation, error) {
+	if err := s.AuthorizeAction(username); err != nil {
+		return nil, err
+	}
+	if !protocol.IsDockerAction(params.Action) {
+		return nil, orz.NewError(400, "操作仅支持 start、stop 或 restart")
+	}
+	if params.Container == "" {
+		return nil, orz.NewError(400, "容器不能为空")
+	}
+	if _, ok := s.wsManager.GetClient(agentID); !ok {
+		return nil, orz.NewError(400, "探针未连接")
+	}
+
+	now := time.Now()
+	record := &models.DockerOperation{
+		AgentID:   agentID,
+		Action:    params.Action,
+		Container: params.Container,
+		Name:      params.Name,
+		Status:    models.DockerOperationStatusRunning,
+		CommandID: fmt.Sprintf("docker_action_%d", now.UnixNano()),
+		Username:  username,
+		ClientIP:  clientIP,
+		CreatedAt: now.UnixMilli(),
+	}
+	if err := s.DockerOperationRepo.Create(ctx, record); err != nil {
+		return nil, err
+	}
+
+	if err := s.sendCommand(agentID, record.CommandID, "docker_action", params.DockerActionRequest); err != nil {
+		s.finish(ctx, record.ID, map[string]interface{}{
+			"status":      models.DockerOperationStatusError,
+			"error":       "下发指令失败: " + err.Error(),
+			"finished_at": time.Now().UnixMilli(),
+		})
+		return nil, orz.NewError(500, "发送指令失败")
+	}
+
+	s.logger.Info("容器操作指令已下发",
+		zap.Int64("id", record.ID),
+		zap.String("agentId", agentID),
+		zap.String("action", params.Action),
+		zap.String("container", params.Container),
+		zap.String("username", username))
+
+	time.AfterFunc(dockerActionTimeout, func() {
+		s.finish(context.Background(), record.ID, map[string]interface{}{
+			"status":      models.DockerOperationStatusError,
+			"error":       fmt.Sprintf("超过 %d 秒未返回执行结果", int(dockerActionTimeout.Seconds())),
+			"finished_at": time.Now().UnixMilli(),
+		})
+	})
+	return record, nil
+}
+
+// HandleCommandResponse 处理探针返回的容器列表和容器操作结果
+func (s *DockerService) HandleCommandResponse(ctx context.Context, agentID string, resp *protocol.CommandResponse) error {
+	if resp.Status == "running" {
+		return nil
+	}
+	switch resp.Type {
+	case "docker_list":
+		return s.handleListResponse(agentID, resp)
+	case "docker_action":
+		return s.handleActionResponse(ctx, agentID, resp)
+	}
+	return nil
+}
+
+func (s *DockerService) handleListResponse(agentID string, resp *protocol.CommandResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list, ok := s.lists[agentID]
+	if !ok || list.ID != resp.ID {
+		// 已被新的请求替换
+		return nil
+	}
+	list.FinishedAt = time.Now().UnixMilli()
+	if resp.Status == "error" {
+		list.Status = "error"
+		list.Error = resp.Error
+		return nil
+	}
+
+	var result protocol.DockerListResult
+	if err := json.Unmarshal([]byte(resp.Result), &result); err != nil {
+		list.Status = "error"
+		list.Error = "解析容器列表失败"
+		return err
+	}
+	list.Status = "success"
+	list.Error = ""
+	list.Containers = result.Containers
+	return nil
+}
+
+func (s *DockerService) handleActionResponse(ctx context.Context, agentID string, resp *protocol.CommandResponse) error {
+	record, err := s.DockerOperationRepo.FindByCommandID(ctx, agentID, resp.ID)
+	if err != nil {
+		s.logger.Warn("未找到容器操作记录", zap.String("agentId", agentID), zap.String("cmdId", resp.ID))
+		return nil
+	}
+
+	status := models.DockerOperationStatusSuccess
+	if resp.Status == "error" {
+		status = models.DockerOperationStatusError
+	}
+	values := map[string]interface{}{
+		"status":      status,
+		"error":       resp.Error,
+		"finished_at": time.Now().UnixMilli(),
+	}
+	var result protocol.DockerActionResult
+	if err := json.Unmarshal([]byte(resp.Result), &result); err == nil {
+		values["output"] = result.Output
+	}
+
+	s.logger.Info("容器操作执行完成",
+		zap.Int64("id", record.ID),
+		zap.String("agentId", agentID),
+		zap.String("action", record.Action),
+		zap.String("container", record.Container),
+		zap.String("status", status))
+
+	s.finish(ctx, record.ID, values)
+	return nil
+}
+
+func (s *DockerService) finish(ctx context.Context, id int64, values map[string]interface{}) {
+	if err := s.DockerOperationRepo.Finish(ctx, id, values); err != nil {
+		s.logger.Error("更新容器操作记录失败", zap.Int64("id", id), zap.Error(err))
+	}
+}
+
+// CloseStaleOperations 服务启动时将上次运行遗留的执行中记录标记为失败
+func (s *DockerService) CloseStaleOperations(ctx context.Context) error {
+	count, err := s.DockerOperationRepo.CloseRunning(ctx, "服务重启，未收到执行结果", time.Now().UnixMilli())
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		s.logger.Info("已结束中断的容器操作记录", zap.Int64("count", count))
+	}
+	return nil
+}
+
+// sendCommand 发送 docker_list 或 docker_action 指令，args 为 nil 时不携带参数
+func (s *DockerService) sendCommand(agentID, commandID, cmdType string, args any) error {
+	var argsData []byte
+	if args != nil {
+		var err error
+		if argsData, err = json.Marshal(args); err != nil {
+			return err
+		}
+	}
+	cmdData, err := json.Marshal(protocol.CommandRequest{
+		ID:   commandID,
+		Type: cmdType,
+		Args: string(argsData),
+	})
+	if err != nil {
+		return err
+	}
+	msgData, err := json.Marshal(protocol.Message{
+		Type: protocol.MessageTypeCommand,
+		Data: cmdData,
+	})
+	if err != nil {
+		return err
+	}
+	return s.wsManager.SendToClient(agentID, msgData)
+}
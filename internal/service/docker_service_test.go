@@ -0,0 +1,56 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dushixiang/pika/internal/config"
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+func TestDockerAuthorizeAction(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   config.DockerConfig
+		username string
+		allowed  bool
+	}{
+		{"未限制用户", config.DockerConfig{}, "admin", true},
+		{"在允许列表中", config.DockerConfig{AllowedUsers: []string{"ops"}}, "ops", true},
+		{"不在允许列表中", config.DockerConfig{AllowedUsers: []string{"ops"}}, "admin", false},
+	}
+	for _, tt := range tests {
+		s := &DockerService{config: tt.config}
+		if err := s.AuthorizeAction(tt.username); (err == nil) != tt.allowed {
+			t.Errorf("%s: AuthorizeAction 错误 = %v, 期望允许 %v", tt.name, err, tt.allowed)
+		}
+	}
+}
+
+func TestDockerListResponse(t *testing.T) {
+	s := &DockerService{lists: map[string]*DockerContainerList{
+		"agent-1": {ID: "docker_list_2", AgentID: "agent-1", Status: "running", StartedAt: time.Now().UnixMilli()},
+	}}
+
+	// 已被新请求替换的结果直接丢弃
+	stale := &protocol.CommandResponse{ID: "docker_list_1", Type: "docker_list", Status: "success", Result: `{"containers":[{"id":"a"}]}`}
+	if err := s.handleListResponse("agent-1", stale); err != nil {
+		t.Fatalf("handleListResponse 错误: %v", err)
+	}
+	if list := s.GetContainers("agent-1"); list.Status != "running" || len(list.Containers) != 0 {
+		t.Fatalf("过期的结果不应更新容器列表: %+v", list)
+	}
+
+	resp := &protocol.CommandResponse{ID: "docker_list_2", Type: "docker_list", Status: "success", Result: `{"containers":[{"id":"b","name":"nginx"}]}`}
+	if err := s.handleListResponse("agent-1", resp); err != nil {
+		t.Fatalf("handleListResponse 错误: %v", err)
+	}
+	list := s.GetContainers("agent-1")
+	if list.Status != "success" || len(list.Containers) != 1 || list.Containers[0].Name != "nginx" {
+		t.Fatalf("容器列表更新错误: %+v", list)
+	}
+
+	if s.GetContainers("agent-2") != nil {
+		t.Fatalf("未获取过容器列表的探针应返回 nil")
+	}
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/repo"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// EventNotifier 非告警事件通知（DDNS 更新、防篡改、安全审计、探针注册）
+// 事件只发送给订阅了该事件类型的渠道，与告警通知共用发送队列和重试机制
+type EventNotifier struct {
+	logger            *zap.Logger
+	agentRepo         *repo.AgentRepo
+	propertyService   *PropertyService
+	notificationQueue *NotificationQueueService
+	notifier          *Notifier
+}
+
+func NewEventNotifier(logger *zap.Logger, db *gorm.DB, propertyService *PropertyService, notificationQueue *NotificationQueueService, notifier *Notifier) *EventNotifier {
+	return &EventNotifier{
+		logger:            logger.Named("event-notifier"),
+		agentRepo:         repo.NewAgentRepo(db),
+		propertyService:   propertyService,
+		notificationQueue: notificationQueue,
+		notifier:          notifier,
+	}
+}
+
+// Notify 异步发送事件通知，不阻塞调用方
+func (n *EventNotifier) Notify(agentID, event, level, message string) {
+	go n.notify(agentID, event, level, message)
+}
+
+// notify 将事件通知加入订阅渠道的发送队列(带panic恢复)，入队失败时直接发送
+func (n *EventNotifier) notify(agentID, event, level, message string) {
+	defer func() {
+		if r := recover(); r != nil {
+			n.logger.Error("发送事件通知时发生panic", zap.Any("panic", r), zap.String("event", event))
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	alertConfig, err := n.propertyService.GetAlertConfig(ctx)
+	if err != nil {
+		n.logger.Error("获取告警配置失败", zap.Error(err))
+		return
+	}
+	if !alertConfig.Enabled {
+		return
+	}
+
+	channelConfigs, err := n.propertyService.GetNotificationChannelConfigs(ctx)
+	if err != nil {
+		n.logger.Error("获取通知渠道配置失败", zap.Error(err))
+		return
+	}
+
+	var channels []models.NotificationChannelConfig
+	for _, channel := range channelConfigs {
+		if channel.Enabled && channel.Subscribes(event) {
+			channels = append(channels, channel)
+		}
+	}
+	if len(channels) == 0 {
+		return
+	}
+
+	agent, err := n.agentRepo.FindById(ctx, agentID)
+	if err != nil {
+		n.logger.Error("获取探针信息失败", zap.String("agentId", agentID), zap.Error(err))
+		return
+	}
+
+	// 事件不写入告警记录，仅借用告警记录结构作为通知内容
+	now := time.Now().UnixMilli()
+	record := &models.AlertRecord{
+		AgentID:   agent.ID,
+		AgentName: agent.Name,
+		AlertType: event,
+		Message:   message,
+		Level:     level,
+		Status:    "firing",
+		FiredAt:   now,
+		CreatedAt: now,
+	}
+
+	if err := n.notificationQueue.Enqueue(ctx, channels, record, &agent); err != nil {
+		n.logger.Error("事件通知入队失败，直接发送", zap.Error(err))
+		if err := n.notifier.SendNotificationByConfigs(ctx, channels, record, &agent); err != nil {
+			n.logger.Error("发送事件通知失败", zap.Error(err))
+		}
+	}
+}
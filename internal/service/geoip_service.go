@@ -20,7 +20,7 @@ type GeoIPService struct {
 func NewGeoIPService(logger *zap.Logger, appCfg *config.AppConfig) (*GeoIPService, error) {
 	cfg := appCfg.GeoIP
 	s := &GeoIPService{
-		logger: logger,
+		logger: logger.Named("geoip"),
 		config: cfg,
 	}
 
@@ -10,6 +10,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/dushixiang/pika/internal/config"
@@ -19,8 +20,9 @@ import (
 // GitHubOAuthService GitHub OAuth 认证服务
 type GitHubOAuthService struct {
 	logger     *zap.Logger
-	config     *config.GitHubOAuthConfig
-	stateStore map[string]time.Time // 简单的 state 存储（生产环境应使用 Redis 等）
+	mu         sync.Mutex
+	config     *config.GitHubOAuthConfig // 未启用或配置不完整时为 nil
+	stateStore map[string]time.Time      // 简单的 state 存储（生产环境应使用 Redis 等）
 	httpClient *http.Client
 }
 
@@ -41,45 +43,59 @@ type GitHubAccessTokenResponse struct {
 
 // NewGitHubOAuthService 创建 GitHub OAuth 服务
 func NewGitHubOAuthService(logger *zap.Logger, appConfig *config.AppConfig) *GitHubOAuthService {
-	if appConfig.GitHub == nil || !appConfig.GitHub.Enabled {
-		logger.Info("GitHub OAuth 认证未启用")
-		return &GitHubOAuthService{
-			logger: logger,
-			config: nil,
-		}
+	s := &GitHubOAuthService{
+		logger:     logger.Named("auth"),
+		stateStore: make(map[string]time.Time),
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
 	}
+	s.config = s.validConfig(appConfig.GitHub)
+	return s
+}
 
-	githubConfig := appConfig.GitHub
+// Reload 使用新配置替换 GitHub OAuth 配置，进行中的登录流程的 state 保持有效
+func (s *GitHubOAuthService) Reload(githubConfig *config.GitHubOAuthConfig) {
+	githubConfig = s.validConfig(githubConfig)
+
+	s.mu.Lock()
+	s.config = githubConfig
+	s.mu.Unlock()
+}
+
+// validConfig 校验配置，未启用或配置不完整时返回 nil
+func (s *GitHubOAuthService) validConfig(githubConfig *config.GitHubOAuthConfig) *config.GitHubOAuthConfig {
+	if githubConfig == nil || !githubConfig.Enabled {
+		s.logger.Info("GitHub OAuth 认证未启用")
+		return nil
+	}
 
 	// 验证配置
 	if githubConfig.ClientID == "" || githubConfig.ClientSecret == "" {
-		logger.Error("GitHub OAuth 配置不完整，GitHub 认证将被禁用")
-		return &GitHubOAuthService{
-			logger: logger,
-			config: nil,
-		}
+		s.logger.Error("GitHub OAuth 配置不完整，GitHub 认证将被禁用")
+		return nil
 	}
 
-	logger.Info("GitHub OAuth 服务初始化成功")
+	s.logger.Info("GitHub OAuth 服务初始化成功")
+	return githubConfig
+}
 
-	return &GitHubOAuthService{
-		logger:     logger,
-		config:     githubConfig,
-		stateStore: make(map[string]time.Time),
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-	}
+// getConfig 获取当前的 GitHub OAuth 配置
+func (s *GitHubOAuthService) getConfig() *config.GitHubOAuthConfig {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.config
 }
 
 // IsEnabled 检查 GitHub OAuth 是否启用
 func (s *GitHubOAuthService) IsEnabled() bool {
-	return s.config != nil && s.config.Enabled
+	return s.getConfig() != nil
 }
 
 // GenerateAuthURL 生成 GitHub 认证 URL
 func (s *GitHubOAuthService) GenerateAuthURL() (string, string, error) {
-	if !s.IsEnabled() {
+	githubConfig := s.getConfig()
+	if githubConfig == nil {
 		return "", "", errors.New("GitHub OAuth 未启用")
 	}
 
@@ -89,16 +105,18 @@ func (s *GitHubOAuthService) GenerateAuthURL() (string, string, error) {
 		return "", "", fmt.Errorf("生成 state 失败: %w", err)
 	}
 
+	s.mu.Lock()
 	// 存储 state（有效期 10 分钟）
 	s.stateStore[state] = time.Now().Add(10 * time.Minute)
 
 	// 清理过期的 state
 	s.cleanExpiredStates()
+	s.mu.Unlock()
 
 	// 构建 GitHub 授权 URL
 	authURL := fmt.Sprintf("https://github.com/login/oauth/authorize?client_id=%s&redirect_uri=%s&state=%s&scope=user:email",
-		url.QueryEscape(s.config.ClientID),
-		url.QueryEscape(s.config.RedirectURL),
+		url.QueryEscape(githubConfig.ClientID),
+		url.QueryEscape(githubConfig.RedirectURL),
 		url.QueryEscape(state),
 	)
 
@@ -107,20 +125,18 @@ func (s *GitHubOAuthService) GenerateAuthURL() (string, string, error) {
 
 // ExchangeCode 交换授权码获取 access token 和用户信息
 func (s *GitHubOAuthService) ExchangeCode(ctx context.Context, code, state string) (string, string, error) {
-	if !s.IsEnabled() {
+	githubConfig := s.getConfig()
+	if githubConfig == nil {
 		return "", "", errors.New("GitHub OAuth 未启用")
 	}
 
 	// 验证 state
-	if !s.validateState(state) {
+	if !s.consumeState(state) {
 		return "", "", errors.New("无效的 state")
 	}
 
-	// 删除已使用的 state
-	delete(s.stateStore, state)
-
 	// 交换 code 获取 access token
-	accessToken, err := s.getAccessToken(ctx, code)
+	accessToken, err := s.getAccessToken(ctx, githubConfig, code)
 	if err != nil {
 		return "", "", fmt.Errorf("获取 access token 失败: %w", err)
 	}
@@ -138,7 +154,7 @@ func (s *GitHubOAuthService) ExchangeCode(ctx context.Context, code, state strin
 	}
 
 	// 检查用户是否在白名单中
-	if !s.isUserAllowed(username) {
+	if !isGitHubUserAllowed(githubConfig, username) {
 		s.logger.Warn("GitHub 用户不在白名单中，拒绝登录",
 			zap.String("username", username))
 		return "", "", fmt.Errorf("用户 %s 不在允许登录的白名单中", username)
@@ -158,13 +174,13 @@ func (s *GitHubOAuthService) ExchangeCode(ctx context.Context, code, state strin
 }
 
 // getAccessToken 获取 access token
-func (s *GitHubOAuthService) getAccessToken(ctx context.Context, code string) (string, error) {
+func (s *GitHubOAuthService) getAccessToken(ctx context.Context, githubConfig *config.GitHubOAuthConfig, code string) (string, error) {
 	// 构建请求
 	data := url.Values{}
-	data.Set("client_id", s.config.ClientID)
-	data.Set("client_secret", s.config.ClientSecret)
+	data.Set("client_id", githubConfig.ClientID)
+	data.Set("client_secret", githubConfig.ClientSecret)
 	data.Set("code", code)
-	data.Set("redirect_uri", s.config.RedirectURL)
+	data.Set("redirect_uri", githubConfig.RedirectURL)
 
 	req, err := http.NewRequestWithContext(ctx, "POST", "https://github.com/login/oauth/access_token", nil)
 	if err != nil {
@@ -237,16 +253,20 @@ func (s *GitHubOAuthService) generateState() (string, error) {
 	return base64.URLEncoding.EncodeToString(b), nil
 }
 
-// validateState 验证 state
-func (s *GitHubOAuthService) validateState(state string) bool {
+// consumeState 验证 state 并删除已使用的 state
+func (s *GitHubOAuthService) consumeState(state string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	expiresAt, exists := s.stateStore[state]
 	if !exists {
 		return false
 	}
+	delete(s.stateStore, state)
 	return time.Now().Before(expiresAt)
 }
 
-// cleanExpiredStates 清理过期的 state
+// cleanExpiredStates 清理过期的 state，调用方需持有锁
 func (s *GitHubOAuthService) cleanExpiredStates() {
 	now := time.Now()
 	for state, expiresAt := range s.stateStore {
@@ -256,15 +276,15 @@ func (s *GitHubOAuthService) cleanExpiredStates() {
 	}
 }
 
-// isUserAllowed 检查用户是否在白名单中
-func (s *GitHubOAuthService) isUserAllowed(username string) bool {
+// isGitHubUserAllowed 检查用户是否在白名单中
+func isGitHubUserAllowed(githubConfig *config.GitHubOAuthConfig, username string) bool {
 	// 如果未配置白名单，则允许所有用户
-	if len(s.config.AllowedUsers) == 0 {
+	if len(githubConfig.AllowedUsers) == 0 {
 		return true
 	}
 
 	// 检查用户是否在白名单中
-	for _, allowedUser := range s.config.AllowedUsers {
+	for _, allowedUser := range githubConfig.AllowedUsers {
 		if allowedUser == username {
 			return true
 		}
@@ -0,0 +1,362 @@
+package service
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/repo"
+	"github.com/dushixiang/pika/pkg/tracing"
+	"github.com/go-orz/orz"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 分组聚合方式
+const (
+	GroupAggregationAvg = "avg"
+	GroupAggregationMax = "max"
+	GroupAggregationMin = "min"
+	GroupAggregationSum = "sum"
+)
+
+// GroupService 探针分组服务，以标签作为分组，汇总组内探针的指标
+type GroupService struct {
+	logger        *zap.Logger
+	agentRepo     *repo.AgentRepo
+	metricService *MetricService
+}
+
+func NewGroupService(logger *zap.Logger, db *gorm.DB, metricService *MetricService) *GroupService {
+	return &GroupService{
+		logger:        logger.Named("group"),
+		agentRepo:     repo.NewAgentRepo(db),
+		metricService: metricService,
+	}
+}
+
+// GroupSummary 分组概要
+type GroupSummary struct {
+	Tag         string `json:"tag"`
+	AgentCount  int    `json:"agentCount"`
+	OnlineCount int    `json:"onlineCount"`
+}
+
+// GroupUsage 组内使用率统计
+type GroupUsage struct {
+	Avg float64 `json:"avg"`
+	Max float64 `json:"max"`
+	Min float64 `json:"min"`
+}
+
+// GroupMember 组内单个探针的最新指标
+type GroupMember struct {
+	AgentID     string  `json:"agentId"`
+	AgentName   string  `json:"agentName"`
+	Online      bool    `json:"online"`
+	Reporting   bool    `json:"reporting"` // 是否有最新指标
+	CPU         float64 `json:"cpu"`       // CPU 使用率
+	Memory      float64 `json:"memory"`    // 内存使用率
+	Disk        float64 `json:"disk"`      // 磁盘使用率
+	SentRate    uint64  `json:"sentRate"`  // 发送速率(字节/秒)
+	RecvRate    uint64  `json:"recvRate"`  // 接收速率(字节/秒)
+	memoryTotal uint64
+	memoryUsed  uint64
+	diskTotal   uint64
+	diskUsed    uint64
+}
+
+// GroupMetrics 分组的最新聚合指标，使用率只统计在线且有最新指标的探针
+type GroupMetrics struct {
+	Tag            string        `json:"tag"`
+	AgentCount     int           `json:"agentCount"`
+	OnlineCount    int           `json:"onlineCount"`
+	ReportingCount int           `json:"reportingCount"`
+	CPU            GroupUsage    `json:"cpu"`
+	Memory         GroupUsage    `json:"memory"`
+	MemoryTotal    uint64        `json:"memoryTotal"` // 内存总量(字节)
+	MemoryUsed     uint64        `json:"memoryUsed"`  // 已使用内存(字节)
+	Disk           GroupUsage    `json:"disk"`
+	DiskTotal      uint64        `json:"diskTotal"` // 磁盘总容量(字节)
+	DiskUsed       uint64        `json:"diskUsed"`  // 已使用磁盘(字节)
+	SentRate       uint64        `json:"sentRate"`  // 总发送速率(字节/秒)
+	RecvRate       uint64        `json:"recvRate"`  // 总接收速率(字节/秒)
+	Members        []GroupMember `json:"members"`
+	Timestamp      int64         `json:"timestamp"`
+}
+
+// GroupMetricPoint 分组历史指标的一个时间点
+type GroupMetricPoint struct {
+	Timestamp int64   `json:"timestamp"`
+	Avg       float64 `json:"avg"`
+	Max       float64 `json:"max"`
+	Min       float64 `json:"min"`
+	Sum       float64 `json:"sum"`
+	Count     int     `json:"count"` // 该时间点有数据的探针数量
+}
+
+// ListGroups 列出所有分组及探针数量
+func (s *GroupService) ListGroups(ctx context.Context) ([]GroupSummary, error) {
+	agents, err := s.agentRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string]*GroupSummary)
+	for _, agent := range agents {
+		for _, tag := range agent.Tags {
+			if tag == "" {
+				continue
+			}
+			group, ok := groups[tag]
+			if !ok {
+				group = &GroupSummary{Tag: tag}
+				groups[tag] = group
+			}
+			group.AgentCount++
+			if agent.Status == 1 {
+				group.OnlineCount++
+			}
+		}
+	}
+
+	items := make([]GroupSummary, 0, len(groups))
+	for _, group := range groups {
+		items = append(items, *group)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Tag < items[j].Tag
+	})
+	return items, nil
+}
+
+// findMembers 查询拥有指定标签的探针
+func (s *GroupService) findMembers(ctx context.Context, tag string) ([]models.Agent, error) {
+	agents, err := s.agentRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	members := make([]models.Agent, 0)
+	for _, agent := range agents {
+		if slices.Contains(agent.Tags, tag) {
+			members = append(members, agent)
+		}
+	}
+	return members, nil
+}
+
+// GetGroupMetrics 获取分组的最新聚合指标
+func (s *GroupService) GetGroupMetrics(ctx context.Context, tag string) (*GroupMetrics, error) {
+	ctx, span := tracing.Start(ctx, "GroupService.GetGroupMetrics", tracing.WithAttributes(
+		tracing.String("group.tag", tag),
+	))
+	defer span.End()
+
+	agents, err := s.findMembers(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+	if len(agents) == 0 {
+		return nil, orz.NewError(404, "分组不存在")
+	}
+
+	result := &GroupMetrics{
+		Tag:       tag,
+		Members:   make([]GroupMember, 0, len(agents)),
+		Timestamp: time.Now().UnixMilli(),
+	}
+	for _, agent := range agents {
+		member := GroupMember{
+			AgentID:   agent.ID,
+			AgentName: agent.Name,
+			Online:    agent.Status == 1,
+		}
+		result.AgentCount++
+		if member.Online {
+			result.OnlineCount++
+			// 最新指标直接取自内存缓存，避免查询指标表
+			if latest, _ := s.metricService.GetLatestMetrics(ctx, agent.ID); latest != nil {
+				fillGroupMember(&member, latest)
+			}
+		}
+		result.Members = append(result.Members, member)
+	}
+
+	var cpu, memory, disk []float64
+	for _, member := range result.Members {
+		if !member.Reporting {
+			continue
+		}
+		result.ReportingCount++
+		cpu = append(cpu, member.CPU)
+		memory = append(memory, member.Memory)
+		disk = append(disk, member.Disk)
+		result.MemoryTotal += member.memoryTotal
+		result.MemoryUsed += member.memoryUsed
+		result.DiskTotal += member.diskTotal
+		result.DiskUsed += member.diskUsed
+		result.SentRate += member.SentRate
+		result.RecvRate += member.RecvRate
+	}
+	result.CPU = summarizeUsage(cpu)
+	result.Memory = summarizeUsage(memory)
+	result.Disk = summarizeUsage(disk)
+
+	return result, nil
+}
+
+// fillGroupMember 填充探针的最新指标
+func fillGroupMember(member *GroupMember, latest *LatestMetrics) {
+	if latest.CPU != nil {
+		member.CPU = latest.CPU.UsagePercent
+		member.Reporting = true
+	}
+	if latest.Memory != nil {
+		member.Memory = latest.Memory.UsagePercent
+		member.memoryTotal = latest.Memory.Total
+		member.memoryUsed = latest.Memory.Used
+		member.Reporting = true
+	}
+	if latest.Disk != nil {
+		member.Disk = latest.Disk.UsagePercent
+		member.diskTotal = latest.Disk.Total
+		member.diskUsed = latest.Disk.Used
+	}
+	if latest.Network != nil {
+		member.SentRate = latest.Network.TotalBytesSentRate
+		member.RecvRate = latest.Network.TotalBytesRecvRate
+	}
+}
+
+// Value 计算告警规则使用的分组聚合值，metric 为 cpu、memory、disk（使用率）或 network（收发速率之和，MB/s）
+// 没有上报指标的探针不参与计算，组内没有可用数据时返回 false
+func (m *GroupMetrics) Value(metric, aggregation string) (float64, bool) {
+	var values []float64
+	for _, member := range m.Members {
+		if !member.Reporting {
+			continue
+		}
+		switch metric {
+		case "cpu":
+			values = append(values, member.CPU)
+		case "memory":
+			values = append(values, member.Memory)
+		case "disk":
+			values = append(values, member.Disk)
+		case "network":
+			values = append(values, float64(member.SentRate+member.RecvRate)/1024/1024)
+		default:
+			return 0, false
+		}
+	}
+	if len(values) == 0 {
+		return 0, false
+	}
+
+	usage := summarizeUsage(values)
+	switch aggregation {
+	case GroupAggregationMax:
+		return usage.Max, true
+	case GroupAggregationMin:
+		return usage.Min, true
+	case GroupAggregationSum:
+		var sum float64
+		for _, v := range values {
+			sum += v
+		}
+		return sum, true
+	default:
+		return usage.Avg, true
+	}
+}
+
+// GetGroupMetricsHistory 获取分组的历史聚合指标，按时间点汇总组内每个探针的数据
+// metricType 支持 cpu、memory、disk（使用率）和 network（收发速率之和，字节/秒）
+func (s *GroupService) GetGroupMetricsHistory(ctx context.Context, tag, metricType string, start, end int64) ([]GroupMetricPoint, error) {
+	ctx, span := tracing.Start(ctx, "GroupService.GetGroupMetricsHistory", tracing.WithAttributes(
+		tracing.String("group.tag", tag),
+		tracing.String("metric.type", metricType),
+	))
+	defer span.End()
+
+	switch metricType {
+	case "cpu", "memory", "disk", "network":
+	default:
+		return nil, orz.NewError(400, "不支持的指标类型")
+	}
+
+	agents, err := s.findMembers(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+	if len(agents) == 0 {
+		return nil, orz.NewError(404, "分组不存在")
+	}
+
+	// 所有探针使用相同的时间范围和粒度查询，时间点天然对齐
+	buckets := make(map[int64][]float64)
+	for _, agent := range agents {
+		metrics, err := s.metricService.GetMetrics(ctx, agent.ID, metricType, start, end, 0, "")
+		if err != nil {
+			s.logger.Warn("查询探针指标失败", zap.String("agentId", agent.ID), zap.String("type", metricType), zap.Error(err))
+			continue
+		}
+		switch items := metrics.(type) {
+		case []repo.AggregatedCPUMetric:
+			for _, item := range items {
+				buckets[item.Timestamp] = append(buckets[item.Timestamp], item.MaxUsage)
+			}
+		case []repo.AggregatedMemoryMetric:
+			for _, item := range items {
+				buckets[item.Timestamp] = append(buckets[item.Timestamp], item.MaxUsage)
+			}
+		case []repo.AggregatedDiskMetric:
+			for _, item := range items {
+				buckets[item.Timestamp] = append(buckets[item.Timestamp], item.MaxUsage)
+			}
+		case []repo.AggregatedNetworkMetric:
+			for _, item := range items {
+				buckets[item.Timestamp] = append(buckets[item.Timestamp], item.MaxSentRate+item.MaxRecvRate)
+			}
+		}
+	}
+
+	points := make([]GroupMetricPoint, 0, len(buckets))
+	for timestamp, values := range buckets {
+		usage := summarizeUsage(values)
+		point := GroupMetricPoint{
+			Timestamp: timestamp,
+			Avg:       usage.Avg,
+			Max:       usage.Max,
+			Min:       usage.Min,
+			Count:     len(values),
+		}
+		for _, v := range values {
+			point.Sum += v
+		}
+		points = append(points, point)
+	}
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].Timestamp < points[j].Timestamp
+	})
+	return points, nil
+}
+
+// summarizeUsage 计算平均值、最大值和最小值
+func summarizeUsage(values []float64) GroupUsage {
+	if len(values) == 0 {
+		return GroupUsage{}
+	}
+	usage := GroupUsage{Max: math.Inf(-1), Min: math.Inf(1)}
+	var sum float64
+	for _, v := range values {
+		sum += v
+		usage.Max = math.Max(usage.Max, v)
+		usage.Min = math.Min(usage.Min, v)
+	}
+	usage.Avg = sum / float64(len(values))
+	return usage
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// dockerHubRegistry 未指定仓库地址的镜像默认来自 Docker Hub
+const dockerHubRegistry = "docker.io"
+
+// registryManifestTypes 查询标签摘要时接受的清单类型，多架构镜像返回索引的摘要，与 docker pull 记录的摘要一致
+var registryManifestTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// imageReference 解析后的镜像引用
+type imageReference struct {
+	Registry   string // 仓库地址，如 docker.io、ghcr.io、registry.example.com:5000
+	Repository string // 镜像名称，Docker Hub 的官方镜像带有 library/ 前缀
+	Tag        string // 标签，按摘要引用时为空
+	Digest     string // 摘要，如 sha256:...
+}
+
+// parseImageReference 解析 docker 镜像引用，如 nginx、nginx:1.25、ghcr.io/acme/app@sha256:...
+func parseImageReference(ref string) (imageReference, bool) {
+	var image imageReference
+	name, digest, _ := strings.Cut(strings.TrimSpace(ref), "@")
+	image.Digest = digest
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, image.Tag = name[:i], name[i+1:]
+	}
+
+	// 第一段包含 . 或 : 或为 localhost 时才是仓库地址，否则属于 Docker Hub 上的镜像名称
+	first, rest, ok := strings.Cut(name, "/")
+	if ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		image.Registry, image.Repository = first, rest
+	} else {
+		image.Registry, image.Repository = dockerHubRegistry, name
+		if !strings.Contains(name, "/") {
+			image.Repository = "library/" + name
+		}
+	}
+	if image.Tag == "" && image.Digest == "" {
+		image.Tag = "latest"
+	}
+	if name == "" || image.Repository == "" || strings.HasSuffix(image.Repository, "/") {
+		return imageReference{}, false
+	}
+	return image, true
+}
+
+// Name 镜像名称，作为漏洞数据源中的软件名称，Docker Hub 的镜像省略仓库地址和 library/ 前缀
+func (r imageReference) Name() string {
+	if r.Registry == dockerHubRegistry {
+		return strings.TrimPrefix(r.Repository, "library/")
+	}
+	return r.Registry + "/" + r.Repository
+}
+
+// imageDigest 从镜像的仓库摘要中找出与镜像引用同一仓库的摘要
+func imageDigest(image imageReference, repoDigests []string) string {
+	if image.Digest != "" {
+		return image.Digest
+	}
+	for _, repoDigest := range repoDigests {
+		ref, ok := parseImageReference(repoDigest)
+		if ok && ref.Digest != "" && ref.Registry == image.Registry && ref.Repository == image.Repository {
+			return ref.Digest
+		}
+	}
+	return ""
+}
+
+// registryClient 镜像仓库客户端，使用 Registry HTTP API V2 匿名查询标签的当前摘要
+type registryClient struct {
+	httpClient *http.Client
+}
+
+func newRegistryClient(httpClient *http.Client) *registryClient {
+	return &registryClient{httpClient: httpClient}
+}
+
+// manifestDigest 查询镜像标签在仓库中的当前摘要
+func (c *registryClient) manifestDigest(ctx context.Context, image imageReference) (string, error) {
+	host := image.Registry
+	if host == dockerHubRegistry {
+		host = "registry-1.docker.io"
+	}
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", host, image.Repository, url.PathEscape(image.Tag))
+
+	resp, err := c.headManifest(ctx, manifestURL, "")
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		// 公开镜像也需要先获取匿名令牌
+		token, err := c.fetchToken(ctx, resp.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return "", err
+		}
+		resp, err = c.headManifest(ctx, manifestURL, token)
+		if err != nil {
+			return "", err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("查询镜像 %s:%s 失败: HTTP %d", image.Name(), image.Tag, resp.StatusCode)
+	}
+
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", fmt.Errorf("镜像仓库 %s 未返回摘要", image.Registry)
+	}
+	return digest, nil
+}
+
+func (c *registryClient) headManifest(ctx context.Context, manifestURL, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Accept", strings.Join(registryManifestTypes, ", "))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求镜像仓库失败: %w", err)
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// fetchToken 按 WWW-Authenticate 中的 Bearer 参数获取匿名令牌
+func (c *registryClient) fetchToken(ctx context.Context, challenge string) (string, error) {
+	params, ok := parseBearerChallenge(challenge)
+	if !ok || params["realm"] == "" {
+		return "", fmt.Errorf("镜像仓库需要认证: %s", challenge)
+	}
+
+	tokenURL, err := url.Parse(params["realm"])
+	if err != nil {
+		return "", fmt.Errorf("解析令牌地址失败: %w", err)
+	}
+	query := tokenURL.Query()
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+	tokenURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("创建请求失败: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("获取镜像仓库令牌失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("获取镜像仓库令牌失败: HTTP %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&result); err != nil {
+		return "", fmt.Errorf("解析镜像仓库令牌失败: %w", err)
+	}
+	if result.Token != "" {
+		return result.Token, nil
+	}
+	if result.AccessToken != "" {
+		return result.AccessToken, nil
+	}
+	return "", fmt.Errorf("镜像仓库未返回令牌")
+}
+
+// parseBearerChallenge 解析 Bearer realm="...",service="...",scope="..." 格式的认证质询
+func parseBearerChallenge(challenge string) (map[string]string, bool) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+	for rest = strings.TrimSpace(rest); rest != ""; {
+		key, value, ok := strings.Cut(rest, "=")
+		if !ok {
+			return nil, false
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimLeft(value, " ")
+		if strings.HasPrefix(value, `"`) {
+			// 引号内可能包含逗号，如 scope="repository:app:pull,push"
+			end := strings.Index(value[1:], `"`)
+			if end < 0 {
+				return nil, false
+			}
+			params[key] = value[1 : end+1]
+			value = value[end+2:]
+		} else {
+			token, _, _ := strings.Cut(value, ",")
+			params[key] = strings.TrimSpace(token)
+			value = value[len(token):]
+		}
+		rest = strings.TrimLeft(strings.TrimSpace(value), ",")
+		rest = strings.TrimSpace(rest)
+	}
+	return params, true
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseImageReference(t *testing.T) {
+	tests := []struct {
+		ref      string
+		want     imageReference
+		wantName string
+	}{
+		{"nginx", imageReference{Registry: "docker.io", Repository: "library/nginx", Tag: "latest"}, "nginx"},
+		{"nginx:1.25", imageReference{Registry: "docker.io", Repository: "library/nginx", Tag: "1.25"}, "nginx"},
+		{"bitnami/redis:7.2", imageReference{Registry: "docker.io", Repository: "bitnami/redis", Tag: "7.2"}, "bitnami/redis"},
+		{"ghcr.io/acme/app@sha256:0123", imageReference{Registry: "ghcr.io", Repository: "acme/app", Digest: "sha256:0123"}, "ghcr.io/acme/app"},
+		{"registry.example.com:5000/app:v2", imageReference{Registry: "registry.example.com:5000", Repository: "app", Tag: "v2"}, "registry.example.com:5000/app"},
+		{"localhost/app", imageReference{Registry: "localhost", Repository: "app", Tag: "latest"}, "localhost/app"},
+		{"nginx:1.25@sha256:0123", imageReference{Registry: "docker.io", Repository: "library/nginx", Tag: "1.25", Digest: "sha256:0123"}, "nginx"},
+	}
+	for _, tt := range tests {
+		got, ok := parseImageReference(tt.ref)
+		if !ok || got != tt.want {
+			t.Errorf("parseImageReference(%q) = %+v, %v, 期望 %+v", tt.ref, got, ok, tt.want)
+			continue
+		}
+		if got.Name() != tt.wantName {
+			t.Errorf("parseImageReference(%q).Name() = %q, 期望 %q", tt.ref, got.Name(), tt.wantName)
+		}
+	}
+
+	for _, ref := range []string{"", "ghcr.io/", "@sha256:0123"} {
+		if got, ok := parseImageReference(ref); ok {
+			t.Errorf("parseImageReference(%q) = %+v, 应解析失败", ref, got)
+		}
+	}
+}
+
+func TestImageDigest(t *testing.T) {
+	repoDigests := []string{"mirror.example.com/library/nginx@sha256:1111", "nginx@sha256:2222"}
+
+	ref, _ := parseImageReference("nginx:1.25")
+	if got := imageDigest(ref, repoDigests); got != "sha256:2222" {
+		t.Errorf("imageDigest = %q, 期望同一仓库的摘要 sha256:2222", got)
+	}
+	ref, _ = parseImageReference("ghcr.io/acme/app:1.0")
+	if got := imageDigest(ref, repoDigests); got != "" {
+		t.Errorf("本地没有同一仓库的摘要时 imageDigest = %q, 期望为空", got)
+	}
+	ref, _ = parseImageReference("nginx@sha256:3333")
+	if got := imageDigest(ref, repoDigests); got != "sha256:3333" {
+		t.Errorf("按摘要引用时 imageDigest = %q, 期望 sha256:3333", got)
+	}
+}
+
+func TestParseBearerChallenge(t *testing.T) {
+	got, ok := parseBearerChallenge(`Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/nginx:pull,push"`)
+	want := map[string]string{
+		"realm":   "https://auth.docker.io/token",
+		"service": "registry.docker.io",
+		"scope":   "repository:library/nginx:pull,push",
+	}
+	if !ok || !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseBearerChallenge = %v, %v, 期望 %v", got, ok, want)
+	}
+
+	if _, ok := parseBearerChallenge(`Basic realm="registry"`); ok {
+		t.Fatal("Basic 认证不应解析为 Bearer 质询")
+	}
+}
+
+func TestRegistryManifestDigest(t *testing.T) {
+	const digest = "sha256:4c0e7bd4bbb2e5b8d1b2e0f2d3c8f2c6a2a3b0e6d0a9c6a8f7e6d5c4b3a2f1e0"
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			if r.URL.Query().Get("scope") != "repository:acme/app:pull" || r.URL.Query().Get("service") != "test-registry" {
+				t.Errorf("令牌请求参数错误: %s", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`{"token":"anonymous"}`))
+		case "/v2/acme/app/manifests/1.0":
+			if r.Method != http.MethodHead {
+				t.Errorf("查询摘要应使用 HEAD, 实际 %s", r.Method)
+			}
+			if !strings.Contains(r.Header.Get("Accept"), "application/vnd.oci.image.index.v1+json") {
+				t.Errorf("Accept 缺少多架构索引类型: %s", r.Header.Get("Accept"))
+			}
+			if r.Header.Get("Authorization") != "Bearer anonymous" {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="test-registry",scope="repository:acme/app:pull"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Docker-Content-Digest", digest)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := newRegistryClient(server.Client())
+
+	ref, _ := parseImageReference(serverURL.Host + "/acme/app:1.0")
+	got, err := client.manifestDigest(context.Background(), ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != digest {
+		t.Fatalf("manifestDigest = %q, 期望 %q", got, digest)
+	}
+
+	ref, _ = parseImageReference(serverURL.Host + "/acme/missing:1.0")
+	if _, err := client.manifestDigest(context.Background(), ref); err == nil {
+		t.Fatal("标签不存在时应返回错误")
+	}
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/protocol"
+	"go.uber.org/zap"
+)
+
+const (
+	influxQueueSize     = 10000           // 待写入行队列长度，队列满时丢弃新数据
+	influxBatchSize     = 5000            // 单次请求最多携带的行数
+	influxFlushInterval = 5 * time.Second // 未攒满一批时的写入间隔，同时用于刷新配置
+)
+
+// InfluxDBExporter 将探针上报的指标以 InfluxDB 行协议写入 InfluxDB v2
+// 配置保存在 PropertyService 中，可以在管理后台随时启用或修改，写入失败不影响指标入库
+type InfluxDBExporter struct {
+	logger          *zap.Logger
+	propertyService *PropertyService
+	httpClient      *http.Client
+	queue           chan string
+
+	mu     sync.RWMutex
+	config *models.InfluxDBConfig // 最近一次读取的配置，未启用时为 nil
+}
+
+func NewInfluxDBExporter(logger *zap.Logger, propertyService *PropertyService) *InfluxDBExporter {
+	return &InfluxDBExporter{
+		logger:          logger.Named("influxdb"),
+		propertyService: propertyService,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		queue: make(chan string, influxQueueSize),
+	}
+}
+
+// enabled 导出是否已启用
+func (e *InfluxDBExporter) enabled() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.config != nil
+}
+
+// refreshConfig 重新读取配置，未启用或配置不完整时停止导出
+func (e *InfluxDBExporter) refreshConfig(ctx context.Context) *models.InfluxDBConfig {
+	cfg, err := e.propertyService.GetInfluxDBConfig(ctx)
+	if err != nil || !cfg.Enabled || cfg.URL == "" || cfg.Bucket == "" {
+		cfg = nil
+	}
+
+	e.mu.Lock()
+	e.config = cfg
+	e.mu.Unlock()
+	return cfg
+}
+
+// Append 将行协议数据加入写入队列，未启用或队列已满时丢弃
+func (e *InfluxDBExporter) Append(lines ...string) {
+	if !e.enabled() {
+		return
+	}
+	for _, line := range lines {
+		select {
+		case e.queue <- line:
+		default:
+			e.logger.Warn("InfluxDB 写入队列已满，丢弃数据")
+			return
+		}
+	}
+}
+
+// Run 启动后台写入任务
+func (e *InfluxDBExporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(influxFlushInterval)
+	defer ticker.Stop()
+
+	e.refreshConfig(ctx)
+	e.logger.Info("InfluxDB 导出任务已启动")
+
+	batch := make([]string, 0, influxBatchSize)
+	flush := func(cfg *models.InfluxDBConfig) {
+		if len(batch) == 0 {
+			return
+		}
+		if cfg != nil {
+			if err := e.write(ctx, cfg, batch); err != nil {
+				e.logger.Error("写入 InfluxDB 失败", zap.Int("lines", len(batch)), zap.Error(err))
+			}
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			e.logger.Info("InfluxDB 导出任务已停止")
+			return
+		case line := <-e.queue:
+			batch = append(batch, line)
+			if len(batch) >= influxBatchSize {
+				e.mu.RLock()
+				cfg := e.config
+				e.mu.RUnlock()
+				flush(cfg)
+			}
+		case <-ticker.C:
+			flush(e.refreshConfig(ctx))
+		}
+	}
+}
+
+// write 通过 /api/v2/write 接口写入一批数据，时间戳精度为毫秒
+func (e *InfluxDBExporter) write(ctx context.Context, cfg *models.InfluxDBConfig, lines []string) error {
+	query := url.Values{}
+	query.Set("org", cfg.Org)
+	query.Set("bucket", cfg.Bucket)
+	query.Set("precision", "ms")
+	endpoint := strings.TrimRight(cfg.URL, "/") + "/api/v2/write?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(strings.Join(lines, "\n")))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if cfg.Token != "" {
+		req.Header.Set("Authorization", "Token "+cfg.Token)
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 == 2 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("status %d: %s", resp.StatusCode, string(body))
+}
+
+// influxLines 将刚写入的指标转换为行协议，measurement 与 /metrics 接口的指标前缀保持一致
+func influxLines(agentID string, metricType protocol.MetricType, latest *LatestMetrics, timestamp int64) []string {
+	tags := map[string]string{"agent_id": agentID}
+	var lines []string
+	add := func(measurement string, fields map[string]float64) {
+		if line := formatInfluxLine(measurement, tags, fields, timestamp); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	switch metricType {
+	case protocol.MetricTypeCPU:
+		if latest.CPU != nil {
+			add("pika_cpu", map[string]float64{
+				"usage_percent": latest.CPU.UsagePercent,
+				"logical_cores": float64(latest.CPU.LogicalCores),
+			})
+		}
+	case protocol.MetricTypeMemory:
+		if latest.Memory != nil {
+			add("pika_memory", map[string]float64{
+				"usage_percent":   latest.Memory.UsagePercent,
+				"total_bytes":     float64(latest.Memory.Total),
+				"used_bytes":      float64(latest.Memory.Used),
+				"swap_used_bytes": float64(latest.Memory.SwapUsed),
+			})
+		}
+	case protocol.MetricTypeDisk:
+		if latest.Disk != nil {
+			add("pika_disk", map[string]float64{
+				"usage_percent": latest.Disk.UsagePercent,
+				"total_bytes":   float64(latest.Disk.Total),
+				"used_bytes":    float64(latest.Disk.Used),
+			})
+		}
+	case protocol.MetricTypeNetwork:
+		if latest.Network != nil {
+			add("pika_network", map[string]float64{
+				"transmit_bytes_per_second": float64(latest.Network.TotalBytesSentRate),
+				"receive_bytes_per_second":  float64(latest.Network.TotalBytesRecvRate),
+				"transmit_bytes_total":      float64(latest.Network.TotalBytesSentTotal),
+				"receive_bytes_total":       float64(latest.Network.TotalBytesRecvTotal),
+			})
+		}
+	case protocol.MetricTypeNetworkConnection:
+		if latest.NetworkConnection != nil {
+			add("pika_network_connections", map[string]float64{
+				"established": float64(latest.NetworkConnection.Established),
+				"time_wait":   float64(latest.NetworkConnection.TimeWait),
+				"total":       float64(latest.NetworkConnection.Total),
+			})
+		}
+	}
+	return lines
+}
+
+// 行协议不允许在标识符中出现换行，换行符写成字面量 \n，反斜杠本身也需要转义，避免以反斜杠结尾的值吞掉后面的分隔符
+var (
+	influxMeasurementEscaper = strings.NewReplacer(`\`, `\\`, `,`, `\,`, ` `, `\ `, "\n", `\n`, "\r", `\r`)
+	influxTagEscaper         = strings.NewReplacer(`\`, `\\`, `,`, `\,`, `=`, `\=`, ` `, `\ `, "\n", `\n`, "\r", `\r`)
+)
+
+// formatInfluxLine 按行协议格式化一条数据: measurement,tag=value field=value timestamp
+// 标签和字段按名称排序，保证输出稳定；InfluxDB 不接受 NaN 和 Inf，这类字段会被跳过，没有有效字段时返回空字符串
+func formatInfluxLine(measurement string, tags map[string]string, fields map[string]float64, timestamp int64) string {
+	fieldNames := make([]string, 0, len(fields))
+	for name, value := range fields {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			continue
+		}
+		fieldNames = append(fieldNames, name)
+	}
+	if len(fieldNames) == 0 {
+		return ""
+	}
+	sort.Strings(fieldNames)
+
+	var b strings.Builder
+	b.WriteString(influxMeasurementEscaper.Replace(measurement))
+
+	tagNames := make([]string, 0, len(tags))
+	for name := range tags {
+		tagNames = append(tagNames, name)
+	}
+	sort.Strings(tagNames)
+	for _, name := range tagNames {
+		if tags[name] == "" {
+			continue
+		}
+		b.WriteByte(',')
+		b.WriteString(influxTagEscaper.Replace(name))
+		b.WriteByte('=')
+		b.WriteString(influxTagEscaper.Replace(tags[name]))
+	}
+
+	for i, name := range fieldNames {
+		if i == 0 {
+			b.WriteByte(' ')
+		} else {
+			b.WriteByte(',')
+		}
+		b.WriteString(influxTagEscaper.Replace(name))
+		b.WriteByte('=')
+		b.WriteString(strconv.FormatFloat(fields[name], 'f', -1, 64))
+	}
+
+	b.WriteByte(' ')
+	b.WriteString(strconv.FormatInt(timestamp, 10))
+	return b.String()
+}
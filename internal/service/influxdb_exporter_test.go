@@ -0,0 +1,72 @@
+package service
+
+import (
+	"math"
+	"testing"
+)
+
+// 期望值按 InfluxDB 行协议文档中特殊字符的转义规则编写
+func TestFormatInfluxLine(t *testing.T) {
+	tests := []struct {
+		name        string
+		measurement string
+		tags        map[string]string
+		fields      map[string]float64
+		want        string
+	}{
+		{
+			name:        "普通数据",
+			measurement: "pika_cpu",
+			tags:        map[string]string{"agent_id": "a1"},
+			fields:      map[string]float64{"usage_percent": 12.5, "logical_cores": 8},
+			want:        "pika_cpu,agent_id=a1 logical_cores=8,usage_percent=12.5 1700000000000",
+		},
+		{
+			name:        "measurement 中的逗号和空格",
+			measurement: "my Measurement,x",
+			fields:      map[string]float64{"v": 1},
+			want:        `my\ Measurement\,x v=1 1700000000000`,
+		},
+		{
+			name:        "标签和字段名中的逗号、等号和空格",
+			measurement: "m",
+			tags:        map[string]string{"tag Key=1": "tag,Value 1"},
+			fields:      map[string]float64{"field Key=1": 1},
+			want:        `m,tag\ Key\=1=tag\,Value\ 1 field\ Key\=1=1 1700000000000`,
+		},
+		{
+			name:        "以反斜杠结尾的标签值",
+			measurement: "m",
+			tags:        map[string]string{"path": `C:\`, "z": "1"},
+			fields:      map[string]float64{"v": 1},
+			want:        `m,path=C:\\,z=1 v=1 1700000000000`,
+		},
+		{
+			name:        "标签值中的换行",
+			measurement: "m",
+			tags:        map[string]string{"host": "a\nb"},
+			fields:      map[string]float64{"v": 1},
+			want:        `m,host=a\nb v=1 1700000000000`,
+		},
+		{
+			name:        "跳过空标签和非有限数值",
+			measurement: "m",
+			tags:        map[string]string{"empty": ""},
+			fields:      map[string]float64{"nan": math.NaN(), "inf": math.Inf(-1), "v": -0.25},
+			want:        "m v=-0.25 1700000000000",
+		},
+		{
+			name:        "没有有效字段",
+			measurement: "m",
+			fields:      map[string]float64{"nan": math.NaN()},
+			want:        "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatInfluxLine(tt.measurement, tt.tags, tt.fields, 1700000000000); got != tt.want {
+				t.Fatalf("formatInfluxLine = %q, 期望 %q", got, tt.want)
+			}
+		})
+	}
+}
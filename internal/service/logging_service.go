@@ -0,0 +1,97 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+	ws "github.com/dushixiang/pika/internal/websocket"
+	"github.com/dushixiang/pika/pkg/logging"
+	"github.com/go-orz/orz"
+	"go.uber.org/zap"
+)
+
+// LoggingService 日志级别管理服务，支持运行时调整服务端和探针的模块日志级别
+type LoggingService struct {
+	logger    *zap.Logger
+	manager   *logging.Manager
+	wsManager *ws.Manager
+}
+
+func NewLoggingService(logger *zap.Logger, manager *logging.Manager, wsManager *ws.Manager) *LoggingService {
+	return &LoggingService{
+		logger:    logger.Named("logging"),
+		manager:   manager,
+		wsManager: wsManager,
+	}
+}
+
+// GetLevels 获取服务端当前的日志级别
+func (s *LoggingService) GetLevels() logging.Levels {
+	return s.manager.Levels()
+}
+
+// SetLevel 调整服务端日志级别，module 为空时调整默认级别
+func (s *LoggingService) SetLevel(req *protocol.LogLevelRequest) (logging.Levels, error) {
+	if req.Reset {
+		if req.Module == "" {
+			return logging.Levels{}, orz.NewError(400, "默认级别不支持重置")
+		}
+		s.manager.ResetLevel(req.Module)
+	} else if err := s.manager.SetLevel(req.Module, req.Level); err != nil {
+		return logging.Levels{}, orz.NewError(400, err.Error())
+	}
+
+	s.logger.Info("调整服务端日志级别",
+		zap.String("module", req.Module),
+		zap.String("level", req.Level),
+		zap.Bool("reset", req.Reset))
+	return s.manager.Levels(), nil
+}
+
+// SetAgentLevel 向探针下发日志级别调整指令，探针执行后通过指令响应返回当前级别
+func (s *LoggingService) SetAgentLevel(agentID string, req *protocol.LogLevelRequest) (string, error) {
+	if !req.Reset {
+		if _, err := zap.ParseAtomicLevel(req.Level); err != nil {
+			return "", orz.NewError(400, fmt.Sprintf("无效的日志级别: %s", req.Level))
+		}
+	}
+	if _, exists := s.wsManager.GetClient(agentID); !exists {
+		return "", orz.NewError(400, "探针未连接")
+	}
+
+	args, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+
+	commandID := fmt.Sprintf("log_level_%d", time.Now().UnixMilli())
+	cmdData, err := json.Marshal(protocol.CommandRequest{
+		ID:   commandID,
+		Type: "log_level",
+		Args: string(args),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	msgData, err := json.Marshal(protocol.Message{
+		Type: protocol.MessageTypeCommand,
+		Data: cmdData,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.wsManager.SendToClient(agentID, msgData); err != nil {
+		return "", orz.NewError(500, "发送指令失败")
+	}
+
+	s.logger.Info("下发探针日志级别调整指令",
+		zap.String("agentId", agentID),
+		zap.String("module", req.Module),
+		zap.String("level", req.Level),
+		zap.Bool("reset", req.Reset))
+	return commandID, nil
+}
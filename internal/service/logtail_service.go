@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dushixiang/pika/internal/config"
+	"github.com/dushixiang/pika/internal/protocol"
+	ws "github.com/dushixiang/pika/internal/websocket"
+	"github.com/go-orz/orz"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// defaultLogTailLinesPerSecond 每个会话每秒最多转发给浏览器的默认行数
+	defaultLogTailLinesPerSecond = 200
+	// defaultLogTailMaxSessions 每个探针同时查看日志的默认会话数上限
+	defaultLogTailMaxSessions = 3
+)
+
+// LogTailService 远程日志查看服务，负责下发指令并将探针推送的日志片段转发给浏览器
+type LogTailService struct {
+	logger    *zap.Logger
+	wsManager *ws.Manager
+	config    config.LogTailConfig
+
+	tickets  *wsTicketStore
+	mu       sync.Mutex
+	sessions map[string]*LogTailSession
+}
+
+// LogTailSession 日志查看会话
+type LogTailSession struct {
+	ID       string
+	AgentID  string
+	Duration time.Duration
+	Chunks   chan protocol.LogTailChunk
+
+	client  *ws.Client    // 开始查看时探针的连接，探针重连后日志输出已随旧连接结束
+	limiter *rate.Limiter // 转发给浏览器的行数限制
+	dropped int           // 因超过速率被丢弃、尚未提示的行数，只在探针的消息循环中访问
+}
+
+func NewLogTailService(logger *zap.Logger, wsManager *ws.Manager, cfg *config.AppConfig) *LogTailService {
+	s := &LogTailService{
+		logger:    logger.Named("logtail"),
+		wsManager: wsManager,
+		tickets:   newWSTicketStore(),
+		sessions:  make(map[string]*LogTailSession),
+	}
+	if cfg.LogTail != nil {
+		s.config = *cfg.LogTail
+	}
+	if s.config.LinesPerSecond <= 0 {
+		s.config.LinesPerSecond = defaultLogTailLinesPerSecond
+	}
+	if s.config.MaxSessions <= 0 {
+		s.config.MaxSessions = defaultLogTailMaxSessions
+	}
+	return s
+}
+
+// CreateTicket 为已认证的用户创建查看日志的一次性凭证
+func (s *LogTailService) CreateTicket(agentID, username, clientIP string) (string, error) {
+	if _, ok := s.wsManager.GetClient(agentID); !ok {
+		return "", orz.NewError(400, "探针未连接")
+	}
+	return s.tickets.issue(agentID, username, clientIP)
+}
+
+// Open 使用凭证开始查看日志，用于浏览器的 WebSocket 连接
+func (s *LogTailService) Open(ctx context.Context, ticket string, req protocol.LogTailRequest) (*LogTailSession, error) {
+	t, ok := s.tickets.redeem(ticket)
+	if !ok {
+		return nil, orz.NewError(401, "凭证无效或已过期")
+	}
+	session, err := s.Start(ctx, t.agentID, req)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("用户开始查看日志",
+		zap.String("agentId", t.agentID),
+		zap.String("sessionId", session.ID),
+		zap.String("username", t.username),
+		zap.String("clientIp", t.clientIP))
+	return session, nil
+}
+
+// Start 向探针下发日志查看指令并创建会话
+func (s *LogTailService) Start(ctx context.Context, agentID string, req protocol.LogTailRequest) (*LogTailSession, error) {
+	if req.Source != "file" && req.Source != "journal" {
+		return nil, orz.NewError(400, "日志来源仅支持 file 或 journal")
+	}
+	if req.Target == "" {
+		return nil, orz.NewError(400, "日志文件路径或服务名称不能为空")
+	}
+	if req.Lines < 0 || req.Lines > protocol.LogTailMaxLines {
+		return nil, orz.NewError(400, fmt.Sprintf("行数范围为 0-%d", protocol.LogTailMaxLines))
+	}
+	if req.Duration <= 0 || req.Duration > protocol.LogTailMaxDuration {
+		req.Duration = protocol.LogTailMaxDuration
+	}
+	if req.MaxBytes <= 0 || req.MaxBytes > protocol.LogTailMaxBytes {
+		req.MaxBytes = protocol.LogTailMaxBytes
+	}
+
+	client, ok := s.wsManager.GetClient(agentID)
+	if !ok {
+		return nil, orz.NewError(400, "探针未连接")
+	}
+
+	args, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &LogTailSession{
+		ID:       fmt.Sprintf("log_tail_%d", time.Now().UnixNano()),
+		AgentID:  agentID,
+		Duration: time.Duration(req.Duration) * time.Second,
+		Chunks:   make(chan protocol.LogTailChunk, 64),
+		client:   client,
+		// 突发上限不低于初始输出的最大行数，保证初始输出完整
+		limiter: rate.NewLimiter(rate.Limit(s.config.LinesPerSecond), max(s.config.LinesPerSecond, protocol.LogTailMaxLines)),
+	}
+
+	if err := s.addSession(session); err != nil {
+		return nil, err
+	}
+
+	cmdData, err := json.Marshal(protocol.CommandRequest{
+		ID:   session.ID,
+		Type: "log_tail",
+		Args: string(args),
+	})
+	if err != nil {
+		s.removeSession(session.ID)
+		return nil, err
+	}
+	if err := s.sendToAgent(agentID, protocol.MessageTypeCommand, cmdData); err != nil {
+		s.removeSession(session.ID)
+		return nil, orz.NewError(500, "发送指令失败")
+	}
+
+	s.logger.Info("开始远程查看日志",
+		zap.String("agentId", agentID),
+		zap.String("sessionId", session.ID),
+		zap.String("source", req.Source),
+		zap.String("target", req.Target))
+	return session, nil
+}
+
+// Stop 结束日志查看会话，并通知探针停止输出
+func (s *LogTailService) Stop(session *LogTailSession) {
+	if !s.removeSession(session.ID) {
+		return
+	}
+
+	data, err := json.Marshal(protocol.LogTailStop{ID: session.ID})
+	if err != nil {
+		return
+	}
+	// 探针可能已经结束或断开，发送失败无需处理
+	_ = s.sendToAgent(session.AgentID, protocol.MessageTypeLogTailStop, data)
+}
+
+// AgentConnected 开始查看日志时的探针连接是否仍然有效
+func (s *LogTailService) AgentConnected(session *LogTailSession) bool {
+	client, ok := s.wsManager.GetClient(session.AgentID)
+	return ok && client == session.client
+}
+
+// HandleChunk 处理探针推送的日志片段
+func (s *LogTailService) HandleChunk(agentID string, chunk *protocol.LogTailChunk) error {
+	s.mu.Lock()
+	session, ok := s.sessions[chunk.ID]
+	s.mu.Unlock()
+	if !ok || session.AgentID != agentID {
+		// 浏览器已断开，丢弃剩余片段
+		return nil
+	}
+
+	chunk.Data = session.throttle(chunk.Data, time.Now())
+	if chunk.Data == "" && !chunk.Done {
+		return nil
+	}
+
+	if chunk.Done {
+		select {
+		case session.Chunks <- *chunk:
+		case <-time.After(time.Second):
+		}
+		return nil
+	}
+
+	select {
+	case session.Chunks <- *chunk:
+	default:
+		// 浏览器消费过慢时丢弃片段，避免阻塞探针的消息循环
+		s.logger.Warn("日志片段缓冲已满，丢弃片段", zap.String("sessionId", chunk.ID))
+	}
+	return nil
+}
+
+// throttle 按速率限制保留日志行，超出的行被丢弃，下次有日志行转发时提示丢弃的行数
+func (session *LogTailSession) throttle(data string, now time.Time) string {
+	if data == "" {
+		return ""
+	}
+	var b strings.Builder
+	for line := range strings.SplitAfterSeq(data, "\n") {
+		if line == "" {
+			continue
+		}
+		if !session.limiter.AllowN(now, 1) {
+			session.dropped++
+			continue
+		}
+		if session.dropped > 0 {
+			fmt.Fprintf(&b, "... 日志输出超过速率限制，已丢弃 %d 行\n", session.dropped)
+			session.dropped = 0
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+// addSession 保存会话，每个探针同时查看日志的会话数有上限
+func (s *LogTailService) addSession(session *LogTailSession) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	count := 0
+	for _, item := range s.sessions {
+		if item.AgentID == session.AgentID {
+			count++
+		}
+	}
+	if count >= s.config.MaxSessions {
+		return orz.NewError(429, fmt.Sprintf("该探针同时查看日志的会话数已达上限 %d", s.config.MaxSessions))
+	}
+	s.sessions[session.ID] = session
+	return nil
+}
+
+func (s *LogTailService) removeSession(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.sessions[id]; !ok {
+		return false
+	}
+	delete(s.sessions, id)
+	return true
+}
+
+func (s *LogTailService) sendToAgent(agentID string, msgType protocol.MessageType, data []byte) error {
+	msgData, err := json.Marshal(protocol.Message{
+		Type: msgType,
+		Data: data,
+	})
+	if err != nil {
+		return err
+	}
+	return s.wsManager.SendToClient(agentID, msgData)
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestLogTailThrottle(t *testing.T) {
+	session := &LogTailSession{limiter: rate.NewLimiter(1, 2)}
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		data string
+		at   time.Time
+		want string
+	}{
+		{"突发额度内完整转发", "a\nb\n", now, "a\nb\n"},
+		{"超出速率的行被丢弃", "c\nd\n", now, ""},
+		{"恢复后提示丢弃的行数", "e\n", now.Add(time.Second), "... 日志输出超过速率限制，已丢弃 2 行\ne\n"},
+		{"提示只出现一次", "g\n", now.Add(3 * time.Second), "g\n"},
+		{"空片段", "", now.Add(5 * time.Second), ""},
+	}
+	for _, tt := range tests {
+		if got := session.throttle(tt.data, tt.at); got != tt.want {
+			t.Errorf("%s: throttle = %q, 期望 %q", tt.name, got, tt.want)
+		}
+	}
+}
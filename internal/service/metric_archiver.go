@@ -0,0 +1,204 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/config"
+	"github.com/dushixiang/pika/internal/repo"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultArchivePrefix = "pika/metrics"
+	defaultArchiveStep   = 60
+	archiveCheckInterval = time.Hour
+)
+
+// metricArchiveState 归档进度，保存在 Property 中
+type metricArchiveState struct {
+	ArchivedUntil int64 `json:"archivedUntil"` // 已归档到的时间（时间戳毫秒，不含），按 UTC 整天推进
+}
+
+// MetricArchiver 指标归档任务，每天将前一天（UTC）的指标按探针、类型导出为 gzip 压缩的 CSV 上传到对象存储
+// 对象路径为 {Prefix}/{yyyy}/{mm}/{dd}/{agentId}/{type}.csv.gz，格式与指标导出接口的 CSV 一致
+// 归档完成前 MetricService 不会清理对应时间段的数据，对象存储不可用时会暂停清理，需留意数据库容量
+type MetricArchiver struct {
+	logger          *zap.Logger
+	config          *config.ArchiveConfig
+	client          *s3Client
+	metricService   *MetricService
+	propertyService *PropertyService
+	agentRepo       *repo.AgentRepo
+}
+
+// NewMetricArchiver 根据配置创建归档任务，未启用或配置错误时返回 nil
+func NewMetricArchiver(logger *zap.Logger, db *gorm.DB, cfg *config.AppConfig, metricService *MetricService, propertyService *PropertyService) *MetricArchiver {
+	archiveConfig := cfg.Archive
+	if archiveConfig == nil || !archiveConfig.Enabled {
+		return nil
+	}
+	logger = logger.Named("archive")
+	if archiveConfig.Endpoint == "" || archiveConfig.Bucket == "" {
+		logger.Error("指标归档未配置 Endpoint 或 Bucket，已禁用")
+		return nil
+	}
+	client, err := newS3Client(archiveConfig.Endpoint, archiveConfig.Region, archiveConfig.Bucket,
+		archiveConfig.AccessKey, archiveConfig.SecretKey, archiveConfig.PathStyle)
+	if err != nil {
+		logger.Error("指标归档配置错误，已禁用", zap.Error(err))
+		return nil
+	}
+
+	archiver := &MetricArchiver{
+		logger:          logger,
+		config:          archiveConfig,
+		client:          client,
+		metricService:   metricService,
+		propertyService: propertyService,
+		agentRepo:       repo.NewAgentRepo(db),
+	}
+	// 清理任务需要等待归档完成
+	metricService.archiver = archiver
+	return archiver
+}
+
+// Run 启动归档任务，启动时和之后每小时检查一次是否有待归档的整天数据
+func (a *MetricArchiver) Run(ctx context.Context) {
+	ticker := time.NewTicker(archiveCheckInterval)
+	defer ticker.Stop()
+
+	a.logger.Info("指标归档任务已启动", zap.String("bucket", a.config.Bucket))
+
+	a.archivePending(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			a.logger.Info("指标归档任务已停止")
+			return
+		case <-ticker.C:
+			a.archivePending(ctx)
+		}
+	}
+}
+
+// ArchivedUntil 已归档到的时间（时间戳毫秒），尚未归档过时返回 0
+func (a *MetricArchiver) ArchivedUntil(ctx context.Context) int64 {
+	var state metricArchiveState
+	if err := a.propertyService.GetValue(ctx, PropertyIDMetricArchiveState, &state); err != nil {
+		return 0
+	}
+	return state.ArchivedUntil
+}
+
+// archivePending 依次归档尚未归档的整天数据，首次运行时从保留期内最早的一天开始
+func (a *MetricArchiver) archivePending(ctx context.Context) {
+	cfg := a.metricService.getMetricsConfig(ctx)
+	oldest := time.Now().Add(-time.Duration(cfg.RollupRetentionHours) * time.Hour).UTC().Truncate(24 * time.Hour)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	day := oldest
+	if archivedUntil := a.ArchivedUntil(ctx); archivedUntil > 0 {
+		day = time.UnixMilli(archivedUntil).UTC()
+		if day.Before(oldest) {
+			// 长时间未归档，早于保留期的数据已不存在
+			day = oldest
+		}
+	}
+
+	for ; day.Before(today); day = day.Add(24 * time.Hour) {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := a.archiveDay(ctx, day); err != nil {
+			a.logger.Error("归档指标失败，稍后重试", zap.String("day", day.Format("2006-01-02")), zap.Error(err))
+			return
+		}
+		next := day.Add(24 * time.Hour).UnixMilli()
+		if err := a.propertyService.Set(ctx, PropertyIDMetricArchiveState, "指标归档进度", metricArchiveState{ArchivedUntil: next}); err != nil {
+			a.logger.Error("保存指标归档进度失败", zap.Error(err))
+			return
+		}
+	}
+}
+
+// archiveDay 归档一天内所有探针的指标，每个探针的每种指标上传为一个对象，没有数据时跳过
+func (a *MetricArchiver) archiveDay(ctx context.Context, day time.Time) error {
+	agents, err := a.agentRepo.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	step := a.config.Step
+	if step <= 0 {
+		step = defaultArchiveStep
+	}
+	start := day.UnixMilli()
+	end := day.Add(24*time.Hour).UnixMilli() - 1
+
+	var uploaded int
+	for _, agent := range agents {
+		for _, metricType := range archiveMetricTypes() {
+			var buf bytes.Buffer
+			counter := &countingWriter{}
+			gz := gzip.NewWriter(&buf)
+			if err := a.metricService.ExportMetrics(ctx, agent.ID, metricType, start, end, step, "", MetricExportCSV, io.MultiWriter(gz, counter)); err != nil {
+				return fmt.Errorf("导出 %s/%s 失败: %w", agent.ID, metricType, err)
+			}
+			if err := gz.Close(); err != nil {
+				return err
+			}
+			if counter.n == 0 {
+				continue
+			}
+
+			key := fmt.Sprintf("%s/%s/%s/%s.csv.gz", a.prefix(), day.Format("2006/01/02"), agent.ID, metricType)
+			if err := a.client.PutObject(ctx, key, buf.Bytes(), "application/gzip"); err != nil {
+				return fmt.Errorf("上传 %s 失败: %w", key, err)
+			}
+			uploaded++
+		}
+	}
+
+	a.logger.Info("指标归档完成",
+		zap.String("day", day.Format("2006-01-02")),
+		zap.Int("agents", len(agents)),
+		zap.Int("objects", uploaded))
+	return nil
+}
+
+// prefix 对象路径前缀
+func (a *MetricArchiver) prefix() string {
+	prefix := strings.Trim(a.config.Prefix, "/")
+	if prefix == "" {
+		return defaultArchivePrefix
+	}
+	return prefix
+}
+
+// archiveMetricTypes 归档的指标类型，与支持导出的类型一致
+func archiveMetricTypes() []string {
+	types := []string{"ping"}
+	for metricType := range rollupMetricTypes {
+		types = append(types, metricType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// countingWriter 统计写入的字节数，用于判断导出结果是否为空
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/internal/repo"
+	"github.com/dushixiang/pika/pkg/tracing"
+)
+
+// encodedMetric 合并消息中的一类指标，Data 的编码由所在消息决定
+type encodedMetric struct {
+	Type protocol.MetricType
+	Data []byte
+}
+
+// HandleMetricBatch 处理合并的 JSON 指标消息
+func (s *MetricService) HandleMetricBatch(ctx context.Context, agentID string, metrics *protocol.MetricsBatch) error {
+	items := make([]encodedMetric, 0, len(metrics.Metrics))
+	for _, item := range metrics.Metrics {
+		items = append(items, encodedMetric{Type: item.Type, Data: item.Data})
+	}
+	return s.handleMetricBatch(ctx, agentID, items, json.Unmarshal)
+}
+
+// HandleBinaryMetricBatch 处理合并的 MessagePack 指标消息
+func (s *MetricService) HandleBinaryMetricBatch(ctx context.Context, agentID string, metrics *protocol.BinaryMetrics) error {
+	items := make([]encodedMetric, 0, len(metrics.Batch))
+	for _, item := range metrics.Batch {
+		items = append(items, encodedMetric{Type: item.Type, Data: item.Data})
+	}
+	return s.handleMetricBatch(ctx, agentID, items, protocol.UnmarshalMsgpack)
+}
+
+// handleMetricBatch 以同一个时间戳解析合并消息中的各类指标并一次加入写入缓冲区，
+// 单类指标解析失败不影响其他指标
+func (s *MetricService) handleMetricBatch(ctx context.Context, agentID string, items []encodedMetric, unmarshal unmarshalFunc) error {
+	ctx, span := tracing.Start(ctx, "MetricService.HandleMetricBatch", tracing.WithAttributes(
+		tracing.String("agent.id", agentID),
+		tracing.Int("metric.count", len(items)),
+	))
+	defer span.End()
+
+	now := time.Now().UnixMilli()
+	latestMetrics := s.latestMetricsOf(agentID)
+	batch := &repo.MetricBatch{}
+
+	var errs []error
+	parsed := make([]protocol.MetricType, 0, len(items))
+	for _, item := range items {
+		if err := s.parseMetricData(ctx, agentID, string(item.Type), item.Data, unmarshal, now, latestMetrics, batch); err != nil {
+			errs = append(errs, fmt.Errorf("解析 %s 指标失败: %w", item.Type, err))
+			continue
+		}
+		parsed = append(parsed, item.Type)
+	}
+	s.bufferMetrics(agentID, batch)
+
+	for _, metricType := range parsed {
+		s.forwardRemoteWrite(agentID, string(metricType))
+		s.forwardInfluxDB(agentID, string(metricType))
+	}
+
+	err := errors.Join(errs...)
+	span.RecordError(err)
+	return err
+}
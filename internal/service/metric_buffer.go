@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dushixiang/pika/internal/repo"
+	"go.uber.org/zap"
+)
+
+const (
+	metricFlushSize     = 1000        // 缓冲的指标行数达到该值时立即写入
+	metricFlushInterval = time.Second // 未攒满一批时的写入间隔
+	metricBufferLimit   = 100000      // 缓冲上限，数据库写入过慢时丢弃新数据，避免内存无限增长
+)
+
+// metricBuffer 指标写入缓冲区，探针上报的指标先攒批，再由后台任务一次写入存储
+type metricBuffer struct {
+	mu    sync.Mutex
+	batch *repo.MetricBatch
+	full  chan struct{} // 达到 metricFlushSize 时通知后台任务立即写入
+}
+
+func newMetricBuffer() *metricBuffer {
+	return &metricBuffer{
+		batch: &repo.MetricBatch{},
+		full:  make(chan struct{}, 1),
+	}
+}
+
+// add 将一批指标加入缓冲区，缓冲区已满时丢弃并返回 false
+func (b *metricBuffer) add(batch *repo.MetricBatch) bool {
+	b.mu.Lock()
+	if b.batch.Len() >= metricBufferLimit {
+		b.mu.Unlock()
+		return false
+	}
+	b.batch.Merge(batch)
+	size := b.batch.Len()
+	b.mu.Unlock()
+
+	if size >= metricFlushSize {
+		select {
+		case b.full <- struct{}{}:
+		default:
+		}
+	}
+	return true
+}
+
+// take 取出缓冲区中的全部指标
+func (b *metricBuffer) take() *repo.MetricBatch {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	batch := b.batch
+	b.batch = &repo.MetricBatch{}
+	return batch
+}
+
+// bufferMetrics 将解析后的指标加入写入缓冲区
+func (s *MetricService) bufferMetrics(agentID string, batch *repo.MetricBatch) {
+	if batch.Len() == 0 {
+		return
+	}
+	if !s.buffer.add(batch) {
+		s.logger.Warn("metric buffer is full, dropping metrics",
+			zap.String("agentID", agentID),
+			zap.Int("rows", batch.Len()))
+	}
+}
+
+// StartMetricFlush 启动指标批量写入任务，按数量或时间间隔将缓冲区写入存储
+func (s *MetricService) StartMetricFlush(ctx context.Context) {
+	ticker := time.NewTicker(metricFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// 退出前写入剩余数据
+			s.flushMetrics(context.Background())
+			return
+		case <-ticker.C:
+			s.sweepMonitorSamples(ctx)
+		case <-s.buffer.full:
+		}
+		s.flushMetrics(ctx)
+	}
+}
+
+// sweepMonitorSamples 将长时间未再收到检测结果的服务监控采样数据加入缓冲区
+func (s *MetricService) sweepMonitorSamples(ctx context.Context) {
+	sampleMs := int64(s.getMetricsConfig(ctx).MonitorSampleSeconds) * 1000
+	metrics := s.monitorSampler.sweep(time.Now().UnixMilli(), sampleMs)
+	s.bufferMetrics("", &repo.MetricBatch{Monitor: metrics})
+}
+
+// flushMetrics 将缓冲区中的指标批量写入存储，写入失败的数据直接丢弃
+func (s *MetricService) flushMetrics(ctx context.Context) {
+	batch := s.buffer.take()
+	rows := batch.Len()
+	if rows == 0 {
+		return
+	}
+
+	start := time.Now()
+	if err := s.metricStore.SaveMetricBatch(ctx, batch); err != nil {
+		s.logger.Error("failed to save metric batch", zap.Int("rows", rows), zap.Error(err))
+		return
+	}
+	s.logger.Debug("metric batch saved", zap.Int("rows", rows), zap.Duration("elapsed", time.Since(start)))
+}
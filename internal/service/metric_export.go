@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/utils"
+)
+
+const (
+	MetricExportCSV  = "csv"
+	MetricExportJSON = "json" // JSON Lines，每行一个对象
+
+	defaultMetricExportStep = 60   // 未指定 step 时按 1 分钟导出
+	metricExportWindowSize  = 1000 // 每次查询的时间桶数量，分段查询避免一次加载整个时间范围
+)
+
+// IsValidMetricExportFormat 导出格式是否有效
+func IsValidMetricExportFormat(format string) bool {
+	return format == MetricExportCSV || format == MetricExportJSON
+}
+
+// SupportsMetricExport 指标类型是否支持导出
+func SupportsMetricExport(metricType string) bool {
+	return rollupMetricTypes[metricType] || metricType == "ping"
+}
+
+// ExportMetrics 按 step（秒）导出时间范围内的聚合指标，分段查询并边查边写，适合导出较长时间范围
+// 有预聚合数据的指标按不小于 step 的预聚合粒度导出
+func (s *MetricService) ExportMetrics(ctx context.Context, agentID, metricType string, start, end int64, step int, interfaceName, format string, w io.Writer) error {
+	if step <= 0 {
+		step = defaultMetricExportStep
+	}
+	rollup := s.aggregator != nil && rollupMetricTypes[metricType]
+	start, end = s.normalizeTimeRange(ctx, start, end, rollup)
+	interval := alignInterval(step)
+	var bucketSeconds int
+	if rollup {
+		bucketSeconds = chooseAggregationBucket(interval)
+	}
+	bucketMs := queryBucketMs(interval, bucketSeconds)
+	start, end = alignTimeRangeToBucket(start, end, bucketMs)
+
+	encoder := newMetricRowEncoder(format, w)
+	windowMs := bucketMs * metricExportWindowSize
+	for windowStart := start; windowStart <= end; windowStart += windowMs {
+		windowEnd := windowStart + windowMs - 1
+		if windowEnd > end {
+			windowEnd = end
+		}
+		metrics, err := s.queryMetrics(ctx, agentID, metricType, windowStart, windowEnd, interval, bucketSeconds, interfaceName)
+		if err != nil {
+			return err
+		}
+
+		rows := reflect.ValueOf(metrics)
+		if rows.Kind() != reflect.Slice {
+			continue
+		}
+		for i := 0; i < rows.Len(); i++ {
+			if err := encoder.encode(rows.Index(i)); err != nil {
+				return err
+			}
+		}
+		if err := encoder.flush(); err != nil {
+			return err
+		}
+		if f, ok := w.(interface{ Flush() }); ok {
+			f.Flush()
+		}
+	}
+	return encoder.flush()
+}
+
+// metricRowEncoder 将聚合结果结构体按 json 标签编码为 CSV 或 JSON Lines
+type metricRowEncoder struct {
+	csv     *csv.Writer
+	json    *json.Encoder
+	headers bool
+}
+
+func newMetricRowEncoder(format string, w io.Writer) *metricRowEncoder {
+	if format == MetricExportJSON {
+		return &metricRowEncoder{json: json.NewEncoder(w)}
+	}
+	return &metricRowEncoder{csv: csv.NewWriter(w)}
+}
+
+// encode 写入一行，CSV 在第一行前写入表头，并在 timestamp 后追加可读的 time 列方便表格处理
+func (e *metricRowEncoder) encode(row reflect.Value) error {
+	if e.json != nil {
+		return e.json.Encode(row.Interface())
+	}
+
+	if row.Kind() == reflect.Ptr {
+		row = row.Elem()
+	}
+	rowType := row.Type()
+	if !e.headers {
+		var headers []string
+		for i := 0; i < rowType.NumField(); i++ {
+			name := metricFieldName(rowType.Field(i))
+			headers = append(headers, name)
+			if name == "timestamp" {
+				headers = append(headers, "time")
+			}
+		}
+		if err := e.csv.Write(headers); err != nil {
+			return err
+		}
+		e.headers = true
+	}
+
+	record := make([]string, 0, rowType.NumField()+1)
+	for i := 0; i < rowType.NumField(); i++ {
+		field := row.Field(i)
+		value := field.Interface()
+		if field.Kind() == reflect.String {
+			// 文本字段可能来自探针或被监控的目标，需要防止被表格软件当作公式执行
+			record = append(record, utils.EscapeCSVCell(field.String()))
+		} else {
+			record = append(record, fmt.Sprint(value))
+		}
+		if metricFieldName(rowType.Field(i)) == "timestamp" {
+			if ms, ok := value.(int64); ok {
+				record = append(record, time.UnixMilli(ms).Format(time.RFC3339))
+			}
+		}
+	}
+	return e.csv.Write(record)
+}
+
+// flush 将 CSV 缓冲写入底层 Writer
+func (e *metricRowEncoder) flush() error {
+	if e.csv == nil {
+		return nil
+	}
+	e.csv.Flush()
+	return e.csv.Error()
+}
+
+// metricFieldName 字段的 json 名称，未设置时使用字段名
+func metricFieldName(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" {
+		return name
+	}
+	return field.Name
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"testing"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+func TestMetricRowEncoderEscapesText(t *testing.T) {
+	var buf bytes.Buffer
+	encoder := newMetricRowEncoder(MetricExportCSV, &buf)
+	row := models.TemperatureMetric{
+		AgentID:     "a1",
+		SensorKey:   "=cmd|' /C calc'!A0",
+		SensorLabel: "@SUM(A1)",
+		Temperature: -5,
+		Timestamp:   1700000000000,
+	}
+	if err := encoder.encode(reflect.ValueOf(row)); err != nil {
+		t.Fatal(err)
+	}
+	if err := encoder.flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("应导出表头和 1 条记录，实际 %d 行", len(rows))
+	}
+	cells := make(map[string]string)
+	for i, name := range rows[0] {
+		cells[name] = rows[1][i]
+	}
+	if cells["sensorKey"] != "'=cmd|' /C calc'!A0" || cells["sensorLabel"] != "'@SUM(A1)" {
+		t.Fatalf("文本字段未转义: %v", cells)
+	}
+	// 数值字段保持原样，负数不应被当作公式转义
+	if cells["temperature"] != "-5" || cells["agentId"] != "a1" {
+		t.Fatalf("导出的记录错误: %v", cells)
+	}
+}
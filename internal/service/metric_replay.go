@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/internal/repo"
+	"go.uber.org/zap"
+)
+
+// replayableMetricTypes 可以补发的时序指标，其余类型只保留最新数据，补发没有意义
+var replayableMetricTypes = map[protocol.MetricType]bool{
+	protocol.MetricTypeCPU:               true,
+	protocol.MetricTypeMemory:            true,
+	protocol.MetricTypeDisk:              true,
+	protocol.MetricTypeDiskIO:            true,
+	protocol.MetricTypeNetwork:           true,
+	protocol.MetricTypeNetworkConnection: true,
+	protocol.MetricTypeGPU:               true,
+	protocol.MetricTypeTemperature:       true,
+}
+
+// metricReplay 记录各类型补发指标的最早采集时间，聚合任务据此回退聚合进度
+type metricReplay struct {
+	mu       sync.Mutex
+	earliest map[string]int64
+}
+
+func newMetricReplay() *metricReplay {
+	return &metricReplay{earliest: make(map[string]int64)}
+}
+
+// mark 记录补发指标的采集时间
+func (r *metricReplay) mark(metricType string, timestamp int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if earliest, ok := r.earliest[metricType]; !ok || timestamp < earliest {
+		r.earliest[metricType] = timestamp
+	}
+}
+
+// take 取出并清空记录
+func (r *metricReplay) take() map[string]int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	earliest := r.earliest
+	r.earliest = make(map[string]int64)
+	return earliest
+}
+
+// HandleReplayedMetricData 处理探针断线期间缓存、重连后补发的指标，按原始采集时间写入历史数据，
+// 不更新最新指标缓存，避免旧数据覆盖实时数据或触发告警
+func (s *MetricService) HandleReplayedMetricData(ctx context.Context, agentID string, metrics *protocol.MetricsWrapper) error {
+	if !replayableMetricTypes[metrics.Type] || metrics.Timestamp <= 0 {
+		return nil
+	}
+
+	batch := &repo.MetricBatch{}
+	if err := s.parseMetricData(ctx, agentID, string(metrics.Type), metrics.Data, json.Unmarshal, metrics.Timestamp, &LatestMetrics{}, batch); err != nil {
+		return err
+	}
+	s.bufferMetrics(agentID, batch)
+	s.replay.mark(string(metrics.Type), metrics.Timestamp)
+	// CPU 指标中的每核使用率和时间分布单独聚合，同样需要回退聚合进度
+	if len(batch.CPUCore) > 0 {
+		s.replay.mark("cpu_core", metrics.Timestamp)
+	}
+	if len(batch.CPUTimes) > 0 {
+		s.replay.mark("cpu_times", metrics.Timestamp)
+	}
+	return nil
+}
+
+// rewindReplayedAggregation 补发指标所在的时间段已经聚合过时，将聚合进度回退到该时间段之前，
+// 聚合语句按 bucket 覆盖写入，重新聚合不会产生重复数据
+func (s *MetricService) rewindReplayedAggregation(ctx context.Context) {
+	for metricType, earliest := range s.replay.take() {
+		for _, bucket := range aggregationBuckets {
+			bucketMs := int64(bucket * 1000)
+			bucketStart := (earliest / bucketMs) * bucketMs
+
+			progress, err := s.aggregator.GetAggregationProgress(ctx, metricType, bucket)
+			if err != nil || progress == nil || progress.LastBucket < bucketStart {
+				continue
+			}
+			if err := s.aggregator.UpsertAggregationProgress(ctx, metricType, bucket, bucketStart-bucketMs); err != nil {
+				s.logger.Error("rewind aggregation progress failed", zap.String("metricType", metricType), zap.Int("bucketSeconds", bucket), zap.Error(err))
+			}
+		}
+	}
+}
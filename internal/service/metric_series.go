@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/dushixiang/pika/internal/repo"
+)
+
+const (
+	MetricAggAvg = "avg"
+	MetricAggMax = "max"
+	MetricAggP95 = "p95"
+
+	// p95 在 step 的 1/10 粒度上计算，子区间数量受 defaultMaxQueryPoints 限制
+	metricP95Resolution = 10
+)
+
+// seriesMetricTypes 支持按 agg 返回序列的指标类型，均同时提供平均值和最大值
+var seriesMetricTypes = map[string]bool{
+	"cpu": true, "memory": true, "disk": true, "network": true, "ping": true,
+}
+
+// MetricSeries 一条指标序列，如 CPU 使用率、网络上行速率
+type MetricSeries struct {
+	Name   string        `json:"name"`
+	Points []MetricPoint `json:"points"`
+}
+
+// MetricPoint 序列中的一个点，Timestamp 为 step 区间的起始时间（毫秒）
+type MetricPoint struct {
+	Timestamp int64   `json:"timestamp"`
+	Value     float64 `json:"value"`
+}
+
+// MetricSeriesResult 按 step 和 agg 聚合后的指标序列
+type MetricSeriesResult struct {
+	Start  int64          `json:"start"`
+	End    int64          `json:"end"`
+	Step   int            `json:"step"` // 实际使用的聚合粒度（秒），可能因点数限制大于请求值
+	Agg    string         `json:"agg"`
+	Series []MetricSeries `json:"series"`
+}
+
+// metricSample 聚合查询结果中的一个点
+type metricSample struct {
+	series    string
+	timestamp int64
+	avg       float64
+	max       float64
+}
+
+// IsValidMetricAgg agg 参数是否有效
+func IsValidMetricAgg(agg string) bool {
+	return agg == MetricAggAvg || agg == MetricAggMax || agg == MetricAggP95
+}
+
+// SupportsMetricSeries 指标类型是否支持按 agg 返回序列
+func SupportsMetricSeries(metricType string) bool {
+	return seriesMetricTypes[metricType]
+}
+
+// GetMetricSeries 按 step（秒）和 agg 返回聚合后的指标序列，只返回每个区间的一个值，减少前端处理的数据量
+// avg/max 由聚合数据按区间合并得到；p95 在更细的粒度上取各子区间平均值的 95 分位，为近似值
+func (s *MetricService) GetMetricSeries(ctx context.Context, agentID, metricType string, start, end int64, step int, agg, interfaceName string) (*MetricSeriesResult, error) {
+	step = s.DetermineInterval(ctx, start, end, step)
+	queryInterval := step
+	if agg == MetricAggP95 {
+		queryInterval = maxInt(step/metricP95Resolution, 1)
+	}
+
+	metrics, err := s.GetMetrics(ctx, agentID, metricType, start, end, queryInterval, interfaceName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MetricSeriesResult{
+		Start:  start,
+		End:    end,
+		Step:   step,
+		Agg:    agg,
+		Series: buildMetricSeries(metricSamples(metrics), int64(step)*1000, agg),
+	}, nil
+}
+
+// metricSamples 将各类型的聚合结果转换为统一的采样点
+func metricSamples(metrics interface{}) []metricSample {
+	var samples []metricSample
+	switch items := metrics.(type) {
+	case []repo.AggregatedCPUMetric:
+		for _, m := range items {
+			samples = append(samples, metricSample{series: "usage", timestamp: m.Timestamp, avg: m.AvgUsage, max: m.MaxUsage})
+		}
+	case []repo.AggregatedMemoryMetric:
+		for _, m := range items {
+			samples = append(samples, metricSample{series: "usage", timestamp: m.Timestamp, avg: m.AvgUsage, max: m.MaxUsage})
+		}
+	case []repo.AggregatedDiskMetric:
+		for _, m := range items {
+			samples = append(samples, metricSample{series: "usage", timestamp: m.Timestamp, avg: m.AvgUsage, max: m.MaxUsage})
+		}
+	case []repo.AggregatedNetworkMetric:
+		for _, m := range items {
+			samples = append(samples,
+				metricSample{series: "sent", timestamp: m.Timestamp, avg: m.AvgSentRate, max: m.MaxSentRate},
+				metricSample{series: "recv", timestamp: m.Timestamp, avg: m.AvgRecvRate, max: m.MaxRecvRate},
+			)
+		}
+	case []repo.AggregatedPingMetric:
+		for _, m := range items {
+			samples = append(samples, metricSample{series: m.Target, timestamp: m.Timestamp, avg: m.AvgLatency, max: m.MaxLatency})
+		}
+	}
+	return samples
+}
+
+// buildMetricSeries 按 stepMs 对采样点分组，每组按 agg 计算一个值，序列按首次出现的顺序排列
+func buildMetricSeries(samples []metricSample, stepMs int64, agg string) []MetricSeries {
+	type group struct {
+		timestamp int64
+		samples   []metricSample
+	}
+
+	var names []string
+	groups := make(map[string][]*group)
+	for _, sample := range samples {
+		bucket := sample.timestamp / stepMs * stepMs
+		list, ok := groups[sample.series]
+		if !ok {
+			names = append(names, sample.series)
+		}
+		if n := len(list); n > 0 && list[n-1].timestamp == bucket {
+			list[n-1].samples = append(list[n-1].samples, sample)
+			continue
+		}
+		groups[sample.series] = append(list, &group{timestamp: bucket, samples: []metricSample{sample}})
+	}
+
+	series := make([]MetricSeries, 0, len(names))
+	for _, name := range names {
+		points := make([]MetricPoint, 0, len(groups[name]))
+		for _, g := range groups[name] {
+			points = append(points, MetricPoint{Timestamp: g.timestamp, Value: aggregateSamples(g.samples, agg)})
+		}
+		series = append(series, MetricSeries{Name: name, Points: points})
+	}
+	return series
+}
+
+// aggregateSamples 计算一组采样点的聚合值
+func aggregateSamples(samples []metricSample, agg string) float64 {
+	switch agg {
+	case MetricAggMax:
+		value := samples[0].max
+		for _, sample := range samples[1:] {
+			value = math.Max(value, sample.max)
+		}
+		return value
+	case MetricAggP95:
+		values := make([]float64, len(samples))
+		for i, sample := range samples {
+			values[i] = sample.avg
+		}
+		sort.Float64s(values)
+		return values[int(math.Ceil(float64(len(values))*0.95))-1]
+	default:
+		var sum float64
+		for _, sample := range samples {
+			sum += sample.avg
+		}
+		return sum / float64(len(samples))
+	}
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"math"
+	"regexp"
 	"time"
 
 	"github.com/dushixiang/pika/internal/models"
 	"github.com/dushixiang/pika/internal/protocol"
 	"github.com/dushixiang/pika/internal/repo"
+	"github.com/dushixiang/pika/pkg/tracing"
 
 	"github.com/go-orz/cache"
 	"go.uber.org/zap"
@@ -16,8 +18,9 @@ import (
 )
 
 const (
-	defaultMetricsRetentionHours = 24 * 7 // 默认保留 7 天
-	defaultMaxQueryPoints        = 300    // 默认最多返回 300 个点（优化前端渲染性能）
+	defaultMetricsRetentionHours = 24 * 7  // 默认保留 7 天
+	defaultRollupRetentionHours  = 24 * 90 // 预聚合数据默认保留 90 天
+	defaultMaxQueryPoints        = 300     // 默认最多返回 300 个点（优化前端渲染性能）
 )
 
 var allowedIntervals = []int{
@@ -28,6 +31,21 @@ var allowedIntervals = []int{
 	3600, 7200, 14400,
 }
 
+// rollupMetricTypes 有预聚合数据的指标类型
+var rollupMetricTypes = map[string]bool{
+	"cpu":                true,
+	"cpu_core":           true,
+	"cpu_times":          true,
+	"pressure":           true,
+	"memory":             true,
+	"disk":               true,
+	"network":            true,
+	"network_connection": true,
+	"disk_io":            true,
+	"gpu":                true,
+	"temperature":        true,
+}
+
 // 聚合任务支持的 bucket 列表（升序）
 // 与前端 timeRangeOptions 对齐，优化查询效率
 var aggregationBuckets = []int{
@@ -39,42 +57,149 @@ var aggregationBuckets = []int{
 	7200, // 2 小时   - 适用于 7天时间范围
 }
 
+// 自定义指标名和标签名需要符合 Prometheus 命名规则，导出时直接拼接到 /metrics 的输出中
+var (
+	customMetricNamePattern  = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	customMetricLabelPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// maxCustomMetrics 每个探针保留的自定义指标数量上限，与探针端一致
+const maxCustomMetrics = 500
+
 // MetricService 指标服务
 type MetricService struct {
 	logger           *zap.Logger
-	metricRepo       *repo.MetricRepo
+	metricStore      repo.MetricStore
+	aggregator       repo.MetricAggregator // 存储后端不支持预聚合时为 nil
 	monitorStatsRepo *repo.MonitorStatsRepo
 	propertyService  *PropertyService
+	remoteWriter     *RemoteWriter // 未启用 remote_write 转发时为 nil
+	influxExporter   *InfluxDBExporter
+	buffer           *metricBuffer   // 时序指标写入缓冲，由 StartMetricFlush 批量写入
+	monitorSampler   *monitorSampler // 服务监控结果采样，状态不变时合并保存
+	archiver         *MetricArchiver // 未启用指标归档时为 nil，由 NewMetricArchiver 设置
+	replay           *metricReplay   // 补发指标的最早时间，用于重新聚合已聚合过的时间段
 
 	latestCache cache.Cache[string, *LatestMetrics]
 }
 
 // NewMetricService 创建指标服务
-func NewMetricService(logger *zap.Logger, db *gorm.DB, propertyService *PropertyService) *MetricService {
+func NewMetricService(logger *zap.Logger, db *gorm.DB, metricStore repo.MetricStore, propertyService *PropertyService, remoteWriter *RemoteWriter, influxExporter *InfluxDBExporter) *MetricService {
+	aggregator, _ := metricStore.(repo.MetricAggregator)
 	return &MetricService{
-		logger:           logger,
-		metricRepo:       repo.NewMetricRepo(db),
+		logger:           logger.Named("metric"),
+		metricStore:      metricStore,
+		aggregator:       aggregator,
 		monitorStatsRepo: repo.NewMonitorStatsRepo(db),
 		propertyService:  propertyService,
+		remoteWriter:     remoteWriter,
+		influxExporter:   influxExporter,
+		buffer:           newMetricBuffer(),
+		monitorSampler:   newMonitorSampler(),
+		replay:           newMetricReplay(),
 		latestCache:      cache.New[string, *LatestMetrics](time.Minute),
 	}
 }
 
+// unmarshalFunc 指标数据解码函数，JSON 和 MessagePack 编码的指标共用同一套解析逻辑
+type unmarshalFunc func(data []byte, v interface{}) error
+
 // HandleMetricData 处理指标数据
 func (s *MetricService) HandleMetricData(ctx context.Context, agentID string, metricType string, data json.RawMessage) error {
-	now := time.Now().UnixMilli()
+	return s.handleEncodedMetricData(ctx, agentID, metricType, data, json.Unmarshal)
+}
+
+// HandleBinaryMetricData 处理 MessagePack 编码的指标数据
+func (s *MetricService) HandleBinaryMetricData(ctx context.Context, agentID string, metrics *protocol.BinaryMetrics) error {
+	return s.handleEncodedMetricData(ctx, agentID, string(metrics.Type), metrics.Data, protocol.UnmarshalMsgpack)
+}
+
+// handleEncodedMetricData 使用 unmarshal 解码并处理指标数据，入库后转发到 remote_write 和 InfluxDB
+func (s *MetricService) handleEncodedMetricData(ctx context.Context, agentID string, metricType string, data []byte, unmarshal unmarshalFunc) error {
+	ctx, span := tracing.Start(ctx, "MetricService.HandleMetricData", tracing.WithAttributes(
+		tracing.String("agent.id", agentID),
+		tracing.String("metric.type", metricType),
+	))
+	defer span.End()
+
+	err := s.handleMetricData(ctx, agentID, metricType, data, unmarshal)
+	span.RecordError(err)
+	if err == nil {
+		s.forwardRemoteWrite(agentID, metricType)
+		s.forwardInfluxDB(agentID, metricType)
+	}
+	return err
+}
+
+// forwardRemoteWrite 将刚入库的指标加入 remote_write 转发队列
+func (s *MetricService) forwardRemoteWrite(agentID string, metricType string) {
+	if s.remoteWriter == nil {
+		return
+	}
+	latest, ok := s.latestCache.Get(agentID)
+	if !ok {
+		return
+	}
+	s.remoteWriter.Append(remoteWriteSamples(agentID, protocol.MetricType(metricType), latest, time.Now().UnixMilli())...)
+}
 
+// StartRemoteWrite 启动 remote_write 转发任务，未启用时直接返回
+func (s *MetricService) StartRemoteWrite(ctx context.Context) {
+	if s.remoteWriter == nil {
+		return
+	}
+	s.remoteWriter.Run(ctx)
+}
+
+// StartArchive 启动指标归档任务，未启用时直接返回
+func (s *MetricService) StartArchive(ctx context.Context) {
+	if s.archiver == nil {
+		return
+	}
+	s.archiver.Run(ctx)
+}
+
+// forwardInfluxDB 将刚入库的指标加入 InfluxDB 导出队列，未启用时由导出器直接丢弃
+func (s *MetricService) forwardInfluxDB(agentID string, metricType string) {
+	latest, ok := s.latestCache.Get(agentID)
+	if !ok {
+		return
+	}
+	s.influxExporter.Append(influxLines(agentID, protocol.MetricType(metricType), latest, time.Now().UnixMilli())...)
+}
+
+// StartInfluxDBExport 启动 InfluxDB 导出任务，是否写入由属性配置决定
+func (s *MetricService) StartInfluxDBExport(ctx context.Context) {
+	s.influxExporter.Run(ctx)
+}
+
+// handleMetricData 解析指标并更新最新指标缓存，时序指标加入写入缓冲区，主机信息直接写入
+func (s *MetricService) handleMetricData(ctx context.Context, agentID string, metricType string, data []byte, unmarshal unmarshalFunc) error {
+	batch := &repo.MetricBatch{}
+	if err := s.parseMetricData(ctx, agentID, metricType, data, unmarshal, time.Now().UnixMilli(), s.latestMetricsOf(agentID), batch); err != nil {
+		return err
+	}
+	s.bufferMetrics(agentID, batch)
+	return nil
+}
+
+// latestMetricsOf 获取探针的最新指标缓存，不存在时创建
+func (s *MetricService) latestMetricsOf(agentID string) *LatestMetrics {
 	latestMetrics, ok := s.latestCache.Get(agentID)
 	if !ok {
 		latestMetrics = &LatestMetrics{}
 		s.latestCache.Set(agentID, latestMetrics, time.Hour)
 	}
+	return latestMetrics
+}
 
+// parseMetricData 按类型使用 unmarshal 解析指标，时间戳使用 now，最新数据写入 latestMetrics，需要写入的时序指标追加到 batch
+func (s *MetricService) parseMetricData(ctx context.Context, agentID string, metricType string, data []byte, unmarshal unmarshalFunc, now int64, latestMetrics *LatestMetrics, batch *repo.MetricBatch) error {
 	switch protocol.MetricType(metricType) {
 	case protocol.MetricTypeCPU:
 		// CPU数据现在包含静态和动态信息
 		var cpuData protocol.CPUData
-		if err := json.Unmarshal(data, &cpuData); err != nil {
+		if err := unmarshal(data, &cpuData); err != nil {
 			return err
 		}
 		metric := &models.CPUMetric{
@@ -86,12 +211,36 @@ func (s *MetricService) HandleMetricData(ctx context.Context, agentID string, me
 			Timestamp:     now,
 		}
 		latestMetrics.CPU = metric
-		return s.metricRepo.SaveCPUMetric(ctx, metric)
+		// 每核使用率只在服务端开启每核采集后上报，按核心写入历史数据用于发现单核打满
+		latestMetrics.CPUPerCore = cpuData.PerCore
+		for core, usage := range cpuData.PerCore {
+			batch.CPUCore = append(batch.CPUCore, models.CPUCoreMetric{
+				AgentID:      agentID,
+				Core:         core,
+				UsagePercent: usage,
+				Timestamp:    now,
+			})
+		}
+		// CPU 时间分布写入历史数据，最新数据用于展示和 steal 告警
+		latestMetrics.CPUTimes = cpuData.Times
+		if times := cpuData.Times; times != nil {
+			batch.CPUTimes = append(batch.CPUTimes, models.CPUTimesMetric{
+				AgentID:       agentID,
+				UserPercent:   times.User,
+				SystemPercent: times.System,
+				IOWaitPercent: times.IOWait,
+				StealPercent:  times.Steal,
+				IdlePercent:   times.Idle,
+				Timestamp:     now,
+			})
+		}
+		batch.CPU = append(batch.CPU, *metric)
+		return nil
 
 	case protocol.MetricTypeMemory:
 		// Memory数据现在包含静态和动态信息
 		var memData protocol.MemoryData
-		if err := json.Unmarshal(data, &memData); err != nil {
+		if err := unmarshal(data, &memData); err != nil {
 			return err
 		}
 		metric := &models.MemoryMetric{
@@ -107,12 +256,13 @@ func (s *MetricService) HandleMetricData(ctx context.Context, agentID string, me
 			Timestamp:    now,
 		}
 		latestMetrics.Memory = metric
-		return s.metricRepo.SaveMemoryMetric(ctx, metric)
+		batch.Memory = append(batch.Memory, *metric)
+		return nil
 
 	case protocol.MetricTypeDisk:
 		// Disk现在是数组,需要批量处理
 		var diskDataList []protocol.DiskData
-		if err := json.Unmarshal(data, &diskDataList); err != nil {
+		if err := unmarshal(data, &diskDataList); err != nil {
 			return err
 		}
 
@@ -131,12 +281,7 @@ func (s *MetricService) HandleMetricData(ctx context.Context, agentID string, me
 				UsagePercent: diskData.UsagePercent,
 				Timestamp:    now,
 			}
-			if err := s.metricRepo.SaveDiskMetric(ctx, metric); err != nil {
-				s.logger.Error("failed to save disk metric",
-					zap.Error(err),
-					zap.String("agentID", agentID),
-					zap.String("mountPoint", diskData.MountPoint))
-			}
+			batch.Disk = append(batch.Disk, *metric)
 
 			// 累加所有磁盘的数据
 			totalTotal += diskData.Total
@@ -165,12 +310,13 @@ func (s *MetricService) HandleMetricData(ctx context.Context, agentID string, me
 			Used:         totalMetric.Used,
 			Free:         totalMetric.Free,
 		}
-		return s.metricRepo.SaveDiskMetric(ctx, totalMetric)
+		batch.Disk = append(batch.Disk, *totalMetric)
+		return nil
 
 	case protocol.MetricTypeNetwork:
 		// Network现在是数组,需要批量处理
 		var networkDataList []protocol.NetworkData
-		if err := json.Unmarshal(data, &networkDataList); err != nil {
+		if err := unmarshal(data, &networkDataList); err != nil {
 			return err
 		}
 
@@ -190,12 +336,7 @@ func (s *MetricService) HandleMetricData(ctx context.Context, agentID string, me
 				BytesRecvTotal: netData.BytesRecvTotal,
 				Timestamp:      now,
 			}
-			if err := s.metricRepo.SaveNetworkMetric(ctx, metric); err != nil {
-				s.logger.Error("failed to save network metric",
-					zap.Error(err),
-					zap.String("agentID", agentID),
-					zap.String("interface", netData.Interface))
-			}
+			batch.Network = append(batch.Network, *metric)
 
 			// 累加所有网卡的数据
 			totalSentRate += netData.BytesSentRate
@@ -221,11 +362,12 @@ func (s *MetricService) HandleMetricData(ctx context.Context, agentID string, me
 			TotalBytesRecvTotal: totalRecvTotal,
 			TotalInterfaces:     len(networkDataList),
 		}
-		return s.metricRepo.SaveNetworkMetric(ctx, totalMetric)
+		batch.Network = append(batch.Network, *totalMetric)
+		return nil
 
 	case protocol.MetricTypeNetworkConnection:
 		var connData protocol.NetworkConnectionData
-		if err := json.Unmarshal(data, &connData); err != nil {
+		if err := unmarshal(data, &connData); err != nil {
 			return err
 		}
 		metric := &models.NetworkConnectionMetric{
@@ -245,12 +387,13 @@ func (s *MetricService) HandleMetricData(ctx context.Context, agentID string, me
 			Timestamp:   now,
 		}
 		latestMetrics.NetworkConnection = metric
-		return s.metricRepo.SaveNetworkConnectionMetric(ctx, metric)
+		batch.NetworkConnection = append(batch.NetworkConnection, *metric)
+		return nil
 
 	case protocol.MetricTypeDiskIO:
 		// DiskIO现在是数组，直接合并所有磁盘的数据存储为一条记录
 		var diskIODataList []*protocol.DiskIOData
-		if err := json.Unmarshal(data, &diskIODataList); err != nil {
+		if err := unmarshal(data, &diskIODataList); err != nil {
 			return err
 		}
 
@@ -292,11 +435,12 @@ func (s *MetricService) HandleMetricData(ctx context.Context, agentID string, me
 			IopsInProgress: maxIopsInProgress,
 			Timestamp:      now,
 		}
-		return s.metricRepo.SaveDiskIOMetric(ctx, metric)
+		batch.DiskIO = append(batch.DiskIO, *metric)
+		return nil
 
 	case protocol.MetricTypeHost:
 		var hostData protocol.HostInfoData
-		if err := json.Unmarshal(data, &hostData); err != nil {
+		if err := unmarshal(data, &hostData); err != nil {
 			return err
 		}
 		// 保存主机信息
@@ -313,12 +457,12 @@ func (s *MetricService) HandleMetricData(ctx context.Context, agentID string, me
 			Timestamp:       now,
 		}
 		latestMetrics.Host = metric
-		return s.metricRepo.SaveHostMetric(ctx, metric)
+		return s.metricStore.SaveHostMetric(ctx, metric)
 
 	case protocol.MetricTypeGPU:
 		// GPU现在是数组,需要批量处理
 		var gpuDataList []protocol.GPUData
-		if err := json.Unmarshal(data, &gpuDataList); err != nil {
+		if err := unmarshal(data, &gpuDataList); err != nil {
 			return err
 		}
 		// 保存每个GPU的数据
@@ -339,12 +483,7 @@ func (s *MetricService) HandleMetricData(ctx context.Context, agentID string, me
 				Timestamp:        now,
 			}
 			gpuMetrics = append(gpuMetrics, metric)
-			if err := s.metricRepo.SaveGPUMetric(ctx, &metric); err != nil {
-				s.logger.Error("failed to save gpu metric",
-					zap.Error(err),
-					zap.String("agentID", agentID),
-					zap.Int("index", gpuData.Index))
-			}
+			batch.GPU = append(batch.GPU, metric)
 		}
 		latestMetrics.GPU = gpuMetrics
 		return nil
@@ -352,7 +491,7 @@ func (s *MetricService) HandleMetricData(ctx context.Context, agentID string, me
 	case protocol.MetricTypeTemperature:
 		// Temperature现在是数组,需要批量处理
 		var tempDataList []protocol.TemperatureData
-		if err := json.Unmarshal(data, &tempDataList); err != nil {
+		if err := unmarshal(data, &tempDataList); err != nil {
 			return err
 		}
 		// 保存每个温度传感器的数据
@@ -372,23 +511,141 @@ func (s *MetricService) HandleMetricData(ctx context.Context, agentID string, me
 				Timestamp:   now,
 			}
 			tempMetrics = append(tempMetrics, metric)
-			if err := s.metricRepo.SaveTemperatureMetric(ctx, &metric); err != nil {
-				s.logger.Error("failed to save temperature metric",
-					zap.Error(err),
-					zap.String("agentID", agentID),
-					zap.String("sensor", tempData.SensorKey))
-			}
+			batch.Temperature = append(batch.Temperature, metric)
 		}
 		latestMetrics.Temp = tempMetrics
 		return nil
 
+	case protocol.MetricTypeWireGuard:
+		// WireGuard 隧道状态只保留最新数据，用于展示和握手超时告警
+		var tunnels []protocol.WireGuardData
+		if err := unmarshal(data, &tunnels); err != nil {
+			return err
+		}
+		latestMetrics.WireGuard = tunnels
+		return nil
+
+	case protocol.MetricTypeCollectorHealth:
+		// 采集器健康状态只保留最新数据，用于展示卡住或失败的采集器
+		var health []protocol.CollectorHealth
+		if err := unmarshal(data, &health); err != nil {
+			return err
+		}
+		latestMetrics.Collectors = health
+		return nil
+
+	case protocol.MetricTypeMount:
+		// 网络挂载点状态只保留最新数据，用于展示和挂载点告警
+		var mounts []protocol.MountData
+		if err := unmarshal(data, &mounts); err != nil {
+			return err
+		}
+		latestMetrics.Mounts = mounts
+		return nil
+
+	case protocol.MetricTypeFileUsage:
+		// 文件描述符和 inode 使用情况只保留最新数据，用于展示和告警
+		var fileUsage protocol.FileUsageData
+		if err := unmarshal(data, &fileUsage); err != nil {
+			return err
+		}
+		latestMetrics.FileUsage = &fileUsage
+		return nil
+
+	case protocol.MetricTypeTimeSync:
+		// 时钟偏差只保留最新数据，用于展示和时钟偏差告警
+		var timeSync protocol.TimeSyncData
+		if err := unmarshal(data, &timeSync); err != nil {
+			return err
+		}
+		latestMetrics.TimeSync = &timeSync
+		return nil
+
+	case protocol.MetricTypeListeningPort:
+		// 监听端口只保留最新数据，用于展示端口暴露清单和非预期端口告警
+		var ports []protocol.ListeningPort
+		if err := unmarshal(data, &ports); err != nil {
+			return err
+		}
+		latestMetrics.ListeningPorts = ports
+		return nil
+
+	case protocol.MetricTypePressure:
+		// PSI 压力按资源写入历史数据，最新数据用于展示和 Prometheus 导出
+		var pressure protocol.PressureData
+		if err := unmarshal(data, &pressure); err != nil {
+			return err
+		}
+		latestMetrics.Pressure = &pressure
+		for _, resource := range []struct {
+			name string
+			stat *protocol.PressureStat
+		}{
+			{"cpu", pressure.CPU},
+			{"memory", pressure.Memory},
+			{"io", pressure.IO},
+		} {
+			if resource.stat == nil {
+				continue
+			}
+			metric := models.PressureMetric{
+				AgentID:     agentID,
+				Resource:    resource.name,
+				SomePercent: resource.stat.Some.Avg10,
+				Timestamp:   now,
+			}
+			if resource.stat.Full != nil {
+				metric.FullPercent = resource.stat.Full.Avg10
+			}
+			batch.Pressure = append(batch.Pressure, metric)
+		}
+		return nil
+
+	case protocol.MetricTypeCustom:
+		// 自定义指标只保留最新数据，用于展示和 Prometheus 导出
+		var custom []protocol.CustomMetricData
+		if err := unmarshal(data, &custom); err != nil {
+			return err
+		}
+		valid := validCustomMetrics(custom)
+		if dropped := len(custom) - len(valid); dropped > 0 {
+			s.logger.Warn("丢弃不合法的自定义指标", zap.String("agentId", agentID), zap.Int("count", dropped))
+		}
+		latestMetrics.Custom = valid
+		return nil
+
+	case protocol.MetricTypePing:
+		// Ping 结果是数组，每个目标一条记录
+		var pingDataList []protocol.PingData
+		if err := unmarshal(data, &pingDataList); err != nil {
+			return err
+		}
+		for _, pingData := range pingDataList {
+			metric := &models.PingMetric{
+				AgentID:    agentID,
+				TargetID:   pingData.TargetID,
+				Target:     pingData.Target,
+				MinLatency: pingData.MinRtt,
+				AvgLatency: pingData.AvgRtt,
+				MaxLatency: pingData.MaxRtt,
+				PacketLoss: pingData.PacketLoss,
+				Timestamp:  now,
+			}
+			batch.Ping = append(batch.Ping, *metric)
+		}
+		// 同时保留最新一轮结果，用于延迟网格矩阵
+		latestMetrics.Ping = pingDataList
+		latestMetrics.PingAt = now
+		return nil
+
 	case protocol.MetricTypeMonitor:
 		// 监控数据也是数组,需要批量处理
 		var monitorDataList []protocol.MonitorData
-		if err := json.Unmarshal(data, &monitorDataList); err != nil {
+		if err := unmarshal(data, &monitorDataList); err != nil {
 			return err
 		}
-		// 保存每个监控项的数据
+		// 状态变化时立即保存，状态不变时按采样间隔合并保存
+		sampleMs := int64(s.getMetricsConfig(ctx).MonitorSampleSeconds) * 1000
 		for _, monitorData := range monitorDataList {
 			metric := &models.MonitorMetric{
 				AgentId:        agentID,
@@ -401,16 +658,12 @@ func (s *MetricService) HandleMetricData(ctx context.Context, agentID string, me
 				Error:          monitorData.Error,
 				Message:        monitorData.Message,
 				ContentMatch:   monitorData.ContentMatch,
+				ContentDetail:  monitorData.ContentDetail,
 				CertExpiryTime: monitorData.CertExpiryTime,
 				CertDaysLeft:   monitorData.CertDaysLeft,
 				Timestamp:      monitorData.CheckedAt, // 使用检测时间
 			}
-			if err := s.metricRepo.SaveMonitorMetric(ctx, metric); err != nil {
-				s.logger.Error("failed to save monitor metric",
-					zap.Error(err),
-					zap.String("agentID", agentID),
-					zap.String("MonitorId", monitorData.ID))
-			}
+			batch.Monitor = append(batch.Monitor, s.monitorSampler.sample(*metric, sampleMs)...)
 		}
 		return nil
 
@@ -420,102 +673,164 @@ func (s *MetricService) HandleMetricData(ctx context.Context, agentID string, me
 	}
 }
 
+// validCustomMetrics 过滤指标名或标签名不合法、数值为 NaN 或 Inf 的自定义指标，并限制数量
+func validCustomMetrics(custom []protocol.CustomMetricData) []protocol.CustomMetricData {
+	valid := make([]protocol.CustomMetricData, 0, len(custom))
+	for _, metric := range custom {
+		if len(valid) >= maxCustomMetrics {
+			break
+		}
+		if !customMetricNamePattern.MatchString(metric.Name) {
+			continue
+		}
+		if math.IsNaN(metric.Value) || math.IsInf(metric.Value, 0) {
+			continue
+		}
+		labelsValid := true
+		for name := range metric.Labels {
+			if !customMetricLabelPattern.MatchString(name) {
+				labelsValid = false
+				break
+			}
+		}
+		if labelsValid {
+			valid = append(valid, metric)
+		}
+	}
+	return valid
+}
+
 // GetMetrics 获取聚合指标数据（自动路由到聚合表或原始表）
 // interfaceName: 网卡过滤参数（仅对 network 类型有效）
 func (s *MetricService) GetMetrics(ctx context.Context, agentID, metricType string, start, end int64, interval int, interfaceName string) (interface{}, error) {
-	start, end = s.normalizeTimeRange(ctx, start, end)
-	interval = s.DetermineInterval(ctx, start, end, interval)
+	ctx, span := tracing.Start(ctx, "MetricService.GetMetrics", tracing.WithAttributes(
+		tracing.String("agent.id", agentID),
+		tracing.String("metric.type", metricType),
+		tracing.Int64("metric.range_ms", end-start),
+	))
+	defer span.End()
+
+	result, err := s.getMetrics(ctx, agentID, metricType, start, end, interval, interfaceName)
+	span.RecordError(err)
+	return result, err
+}
 
+func (s *MetricService) getMetrics(ctx context.Context, agentID, metricType string, start, end int64, interval int, interfaceName string) (interface{}, error) {
 	// 判断是否可以使用聚合表（仅支持部分指标类型）
-	aggCapable := map[string]bool{
-		"cpu":                true,
-		"memory":             true,
-		"disk":               true,
-		"network":            true,
-		"network_connection": true,
-		"disk_io":            true,
-		"gpu":                true,
-		"temperature":        true,
-	}
+	rollup := s.aggregator != nil && rollupMetricTypes[metricType]
+
+	start, end = s.normalizeTimeRange(ctx, start, end, rollup)
+	interval = s.DetermineInterval(ctx, start, end, interval)
 
 	// 智能选择聚合粒度：根据查询间隔选择最合适的bucket
 	// 例如：查询90秒数据时使用60秒bucket，查询600秒数据时使用300秒bucket
 	var bucketSeconds int
-	useAgg := false
-	if aggCapable[metricType] {
+	if rollup {
 		bucketSeconds = chooseAggregationBucket(interval)
-		useAgg = bucketSeconds > 0
 	}
 
 	// 将时间范围对齐到最终使用的 bucket，避免不同时间框架出现桶数量偏差
-	bucketMs := int64(interval * 1000)
-	if useAgg {
-		bucketMs = int64(bucketSeconds * 1000)
-	}
-	start, end = alignTimeRangeToBucket(start, end, bucketMs)
+	start, end = alignTimeRangeToBucket(start, end, queryBucketMs(interval, bucketSeconds))
 
+	return s.queryMetrics(ctx, agentID, metricType, start, end, interval, bucketSeconds, interfaceName)
+}
+
+// queryMetrics 按已确定的粒度查询指标，bucketSeconds 大于 0 时优先读取预聚合数据
+func (s *MetricService) queryMetrics(ctx context.Context, agentID, metricType string, start, end int64, interval, bucketSeconds int, interfaceName string) (interface{}, error) {
+	useAgg := bucketSeconds > 0
 	switch metricType {
 	case "cpu":
 		if useAgg {
-			if metrics, err := s.metricRepo.GetCPUMetricsAgg(ctx, agentID, start, end, bucketSeconds); err == nil && len(metrics) > 0 {
+			if metrics, err := s.aggregator.GetCPUMetricsAgg(ctx, agentID, start, end, bucketSeconds); err == nil && len(metrics) > 0 {
+				return metrics, nil
+			}
+		}
+		return s.metricStore.GetCPUMetrics(ctx, agentID, start, end, interval)
+	case "cpu_core":
+		if useAgg {
+			if metrics, err := s.aggregator.GetCPUCoreMetricsAgg(ctx, agentID, start, end, bucketSeconds); err == nil && len(metrics) > 0 {
+				return metrics, nil
+			}
+		}
+		return s.metricStore.GetCPUCoreMetrics(ctx, agentID, start, end, interval)
+	case "cpu_times":
+		if useAgg {
+			if metrics, err := s.aggregator.GetCPUTimesMetricsAgg(ctx, agentID, start, end, bucketSeconds); err == nil && len(metrics) > 0 {
+				return metrics, nil
+			}
+		}
+		return s.metricStore.GetCPUTimesMetrics(ctx, agentID, start, end, interval)
+	case "pressure":
+		if useAgg {
+			if metrics, err := s.aggregator.GetPressureMetricsAgg(ctx, agentID, start, end, bucketSeconds); err == nil && len(metrics) > 0 {
 				return metrics, nil
 			}
 		}
-		return s.metricRepo.GetCPUMetrics(ctx, agentID, start, end, interval)
+		return s.metricStore.GetPressureMetrics(ctx, agentID, start, end, interval)
 	case "memory":
 		if useAgg {
-			if metrics, err := s.metricRepo.GetMemoryMetricsAgg(ctx, agentID, start, end, bucketSeconds); err == nil && len(metrics) > 0 {
+			if metrics, err := s.aggregator.GetMemoryMetricsAgg(ctx, agentID, start, end, bucketSeconds); err == nil && len(metrics) > 0 {
 				return metrics, nil
 			}
 		}
-		return s.metricRepo.GetMemoryMetrics(ctx, agentID, start, end, interval)
+		return s.metricStore.GetMemoryMetrics(ctx, agentID, start, end, interval)
 	case "disk":
 		if useAgg {
-			if metrics, err := s.metricRepo.GetDiskMetricsAgg(ctx, agentID, start, end, bucketSeconds); err == nil && len(metrics) > 0 {
+			if metrics, err := s.aggregator.GetDiskMetricsAgg(ctx, agentID, start, end, bucketSeconds); err == nil && len(metrics) > 0 {
 				return metrics, nil
 			}
 		}
-		return s.metricRepo.GetDiskMetrics(ctx, agentID, start, end, interval)
+		return s.metricStore.GetDiskMetrics(ctx, agentID, start, end, interval)
 	case "network":
 		if useAgg {
-			if metrics, err := s.metricRepo.GetNetworkMetricsAgg(ctx, agentID, start, end, bucketSeconds, interfaceName); err == nil && len(metrics) > 0 {
+			if metrics, err := s.aggregator.GetNetworkMetricsAgg(ctx, agentID, start, end, bucketSeconds, interfaceName); err == nil && len(metrics) > 0 {
 				return metrics, nil
 			}
 		}
-		return s.metricRepo.GetNetworkMetrics(ctx, agentID, start, end, interval, interfaceName)
+		return s.metricStore.GetNetworkMetrics(ctx, agentID, start, end, interval, interfaceName)
 	case "network_connection":
 		if useAgg {
-			if metrics, err := s.metricRepo.GetNetworkConnectionMetricsAgg(ctx, agentID, start, end, bucketSeconds); err == nil && len(metrics) > 0 {
+			if metrics, err := s.aggregator.GetNetworkConnectionMetricsAgg(ctx, agentID, start, end, bucketSeconds); err == nil && len(metrics) > 0 {
 				return metrics, nil
 			}
 		}
-		return s.metricRepo.GetNetworkConnectionMetrics(ctx, agentID, start, end, interval)
+		return s.metricStore.GetNetworkConnectionMetrics(ctx, agentID, start, end, interval)
 	case "disk_io":
 		if useAgg {
-			if metrics, err := s.metricRepo.GetDiskIOMetricsAgg(ctx, agentID, start, end, bucketSeconds); err == nil && len(metrics) > 0 {
+			if metrics, err := s.aggregator.GetDiskIOMetricsAgg(ctx, agentID, start, end, bucketSeconds); err == nil && len(metrics) > 0 {
 				return metrics, nil
 			}
 		}
-		return s.metricRepo.GetDiskIOMetrics(ctx, agentID, start, end, interval)
+		return s.metricStore.GetDiskIOMetrics(ctx, agentID, start, end, interval)
 	case "gpu":
 		if useAgg {
-			if metrics, err := s.metricRepo.GetGPUMetricsAgg(ctx, agentID, start, end, bucketSeconds); err == nil && len(metrics) > 0 {
+			if metrics, err := s.aggregator.GetGPUMetricsAgg(ctx, agentID, start, end, bucketSeconds); err == nil && len(metrics) > 0 {
 				return metrics, nil
 			}
 		}
-		return s.metricRepo.GetGPUMetrics(ctx, agentID, start, end, interval)
+		return s.metricStore.GetGPUMetrics(ctx, agentID, start, end, interval)
 	case "temperature":
 		if useAgg {
-			if metrics, err := s.metricRepo.GetTemperatureMetricsAgg(ctx, agentID, start, end, bucketSeconds); err == nil && len(metrics) > 0 {
+			if metrics, err := s.aggregator.GetTemperatureMetricsAgg(ctx, agentID, start, end, bucketSeconds); err == nil && len(metrics) > 0 {
 				return metrics, nil
 			}
 		}
-		return s.metricRepo.GetTemperatureMetrics(ctx, agentID, start, end, interval)
+		return s.metricStore.GetTemperatureMetrics(ctx, agentID, start, end, interval)
+	case "ping":
+		return s.metricStore.GetPingMetrics(ctx, agentID, start, end, interval)
 	default:
 		return nil, nil
 	}
 }
 
+// queryBucketMs 查询实际使用的时间桶（毫秒）
+func queryBucketMs(interval, bucketSeconds int) int64 {
+	if bucketSeconds > 0 {
+		return int64(bucketSeconds * 1000)
+	}
+	return int64(interval * 1000)
+}
+
 // DetermineInterval 根据配置、用户请求和时间范围决定聚合粒度
 func (s *MetricService) DetermineInterval(ctx context.Context, start, end int64, requested int) int {
 	interval := requested
@@ -528,9 +843,13 @@ func (s *MetricService) DetermineInterval(ctx context.Context, start, end int64,
 }
 
 // normalizeTimeRange 将时间范围限制在保留周期内，避免无意义的全表扫描
-func (s *MetricService) normalizeTimeRange(ctx context.Context, start, end int64) (int64, int64) {
+// rollup 为 true 时查询可以读取预聚合数据，按预聚合数据的保留时间限制
+func (s *MetricService) normalizeTimeRange(ctx context.Context, start, end int64, rollup bool) (int64, int64) {
 	cfg := s.getMetricsConfig(ctx)
 	retentionDuration := time.Duration(cfg.RetentionHours) * time.Hour
+	if rollup {
+		retentionDuration = time.Duration(cfg.RollupRetentionHours) * time.Hour
+	}
 
 	retentionBoundary := time.Now().Add(-retentionDuration).UnixMilli()
 	if start < retentionBoundary {
@@ -643,16 +962,27 @@ func chooseAggregationBucket(interval int) int {
 // getMetricsConfig 获取指标配置
 func (s *MetricService) getMetricsConfig(ctx context.Context) models.MetricsConfig {
 	cfg := models.MetricsConfig{
-		RetentionHours: defaultMetricsRetentionHours,
+		RetentionHours:       defaultMetricsRetentionHours,
+		RollupRetentionHours: defaultRollupRetentionHours,
+		MonitorSampleSeconds: defaultMonitorSampleSeconds,
 	}
 
-	if s.propertyService == nil {
-		return cfg
+	if s.propertyService != nil {
+		loaded := s.propertyService.GetMetricsConfig(ctx)
+		if loaded.RetentionHours > 0 {
+			cfg.RetentionHours = loaded.RetentionHours
+		}
+		if loaded.RollupRetentionHours > 0 {
+			cfg.RollupRetentionHours = loaded.RollupRetentionHours
+		}
+		if loaded.MonitorSampleSeconds > 0 {
+			cfg.MonitorSampleSeconds = loaded.MonitorSampleSeconds
+		}
 	}
 
-	loaded := s.propertyService.GetMetricsConfig(ctx)
-	if loaded.RetentionHours > 0 {
-		cfg.RetentionHours = loaded.RetentionHours
+	// 预聚合数据由原始数据生成，保留时间不应短于原始数据
+	if cfg.RollupRetentionHours < cfg.RetentionHours {
+		cfg.RollupRetentionHours = cfg.RetentionHours
 	}
 	return cfg
 }
@@ -677,19 +1007,29 @@ func (s *MetricService) StartAggregationTask(ctx context.Context) {
 
 // runAggregation 按固定 bucket 下采样存储
 func (s *MetricService) runAggregation(ctx context.Context) {
+	if s.aggregator == nil {
+		return
+	}
+
+	// 补发的指标落在已聚合的时间段内，回退聚合进度后重新聚合
+	s.rewindReplayedAggregation(ctx)
+
 	cfg := s.getMetricsConfig(ctx)
 	retention := time.Duration(cfg.RetentionHours) * time.Hour
 
 	for _, bucket := range aggregationBuckets {
-		s.aggregateMetric(ctx, "cpu", bucket, retention, s.metricRepo.AggregateCPUToAgg)
-		s.aggregateMetric(ctx, "memory", bucket, retention, s.metricRepo.AggregateMemoryToAgg)
-		s.aggregateMetric(ctx, "disk", bucket, retention, s.metricRepo.AggregateDiskToAgg)
-		s.aggregateMetric(ctx, "network", bucket, retention, s.metricRepo.AggregateNetworkToAgg)
-		s.aggregateMetric(ctx, "network_connection", bucket, retention, s.metricRepo.AggregateNetworkConnectionToAgg)
-		s.aggregateMetric(ctx, "disk_io", bucket, retention, s.metricRepo.AggregateDiskIOToAgg)
-		s.aggregateMetric(ctx, "gpu", bucket, retention, s.metricRepo.AggregateGPUToAgg)
-		s.aggregateMetric(ctx, "temperature", bucket, retention, s.metricRepo.AggregateTemperatureToAgg)
-		s.aggregateMetric(ctx, "monitor", bucket, retention, s.metricRepo.AggregateMonitorMetricsToAgg)
+		s.aggregateMetric(ctx, "cpu", bucket, retention, s.aggregator.AggregateCPUToAgg)
+		s.aggregateMetric(ctx, "cpu_core", bucket, retention, s.aggregator.AggregateCPUCoreToAgg)
+		s.aggregateMetric(ctx, "cpu_times", bucket, retention, s.aggregator.AggregateCPUTimesToAgg)
+		s.aggregateMetric(ctx, "pressure", bucket, retention, s.aggregator.AggregatePressureToAgg)
+		s.aggregateMetric(ctx, "memory", bucket, retention, s.aggregator.AggregateMemoryToAgg)
+		s.aggregateMetric(ctx, "disk", bucket, retention, s.aggregator.AggregateDiskToAgg)
+		s.aggregateMetric(ctx, "network", bucket, retention, s.aggregator.AggregateNetworkToAgg)
+		s.aggregateMetric(ctx, "network_connection", bucket, retention, s.aggregator.AggregateNetworkConnectionToAgg)
+		s.aggregateMetric(ctx, "disk_io", bucket, retention, s.aggregator.AggregateDiskIOToAgg)
+		s.aggregateMetric(ctx, "gpu", bucket, retention, s.aggregator.AggregateGPUToAgg)
+		s.aggregateMetric(ctx, "temperature", bucket, retention, s.aggregator.AggregateTemperatureToAgg)
+		s.aggregateMetric(ctx, "monitor", bucket, retention, s.aggregator.AggregateMonitorMetricsToAgg)
 	}
 }
 
@@ -713,14 +1053,14 @@ func (s *MetricService) aggregateMetric(ctx context.Context, metricType string,
 		return
 	}
 
-	if err := s.metricRepo.UpsertAggregationProgress(ctx, metricType, bucketSeconds, endBucket); err != nil {
+	if err := s.aggregator.UpsertAggregationProgress(ctx, metricType, bucketSeconds, endBucket); err != nil {
 		s.logger.Error("update aggregation progress failed", zap.String("metricType", metricType), zap.Int("bucketSeconds", bucketSeconds), zap.Error(err))
 	}
 }
 
 // getAggregationStart 获取聚合开始时间
 func (s *MetricService) getAggregationStart(ctx context.Context, metricType string, bucketSeconds int, retention time.Duration, bucketMs int64) int64 {
-	progress, err := s.metricRepo.GetAggregationProgress(ctx, metricType, bucketSeconds)
+	progress, err := s.aggregator.GetAggregationProgress(ctx, metricType, bucketSeconds)
 	if err == nil && progress != nil && progress.LastBucket > 0 {
 		return progress.LastBucket + bucketMs
 	}
@@ -748,19 +1088,37 @@ func (s *MetricService) StartCleanupTask(ctx context.Context) {
 	}
 }
 
-// cleanupOldMetrics 清理旧数据
+// cleanupOldMetrics 清理旧数据，启用归档时不清理尚未归档的数据
 func (s *MetricService) cleanupOldMetrics(ctx context.Context) {
 	cfg := s.getMetricsConfig(ctx)
 	retentionDuration := time.Duration(cfg.RetentionHours) * time.Hour
 	before := time.Now().Add(-retentionDuration).UnixMilli()
+	rollupBefore := time.Now().Add(-time.Duration(cfg.RollupRetentionHours) * time.Hour).UnixMilli()
+
+	if s.archiver != nil {
+		archivedUntil := s.archiver.ArchivedUntil(ctx)
+		if archivedUntil == 0 {
+			s.logger.Info("metrics not archived yet, skip cleaning")
+			return
+		}
+		before = min(before, archivedUntil)
+		rollupBefore = min(rollupBefore, archivedUntil)
+	}
 
 	s.logger.Info("starting to clean old metrics", zap.Int64("beforeTimestamp", before), zap.Int("retentionHours", cfg.RetentionHours))
 
-	if err := s.metricRepo.DeleteOldMetrics(ctx, before); err != nil {
+	if err := s.metricStore.DeleteOldMetrics(ctx, before); err != nil {
 		s.logger.Error("failed to clean old metrics", zap.Error(err))
 		return
 	}
 
+	if s.aggregator != nil {
+		if err := s.aggregator.DeleteOldAggregates(ctx, rollupBefore); err != nil {
+			s.logger.Error("failed to clean old aggregates", zap.Error(err))
+			return
+		}
+	}
+
 	s.logger.Info("old metrics cleaned successfully")
 }
 
@@ -772,22 +1130,53 @@ func (s *MetricService) GetLatestMetrics(ctx context.Context, agentID string) (*
 
 // GetMonitorMetrics 获取监控指标历史数据
 func (s *MetricService) GetMonitorMetrics(ctx context.Context, agentID, monitorName string, start, end int64) ([]models.MonitorMetric, error) {
-	return s.metricRepo.GetMonitorMetrics(ctx, agentID, monitorName, start, end)
+	return s.metricStore.GetMonitorMetrics(ctx, agentID, monitorName, start, end)
+}
+
+// GetAllLatestMonitorMetrics 获取所有监控项的最新一条数据
+func (s *MetricService) GetAllLatestMonitorMetrics(ctx context.Context) ([]*models.MonitorMetric, error) {
+	return s.metricStore.GetAllLatestMonitorMetrics(ctx)
 }
 
 // GetMonitorMetricsByName 获取指定监控项的历史数据
 func (s *MetricService) GetMonitorMetricsByName(ctx context.Context, agentID, monitorName string, start, end int64, limit int) ([]models.MonitorMetric, error) {
-	return s.metricRepo.GetMonitorMetricsByName(ctx, agentID, monitorName, start, end, limit)
+	return s.metricStore.GetMonitorMetricsByName(ctx, agentID, monitorName, start, end, limit)
 }
 
 // DeleteAgentMetrics 删除探针的所有指标数据
 func (s *MetricService) DeleteAgentMetrics(ctx context.Context, agentID string) error {
-	return s.metricRepo.DeleteAgentMetrics(ctx, agentID)
+	return s.metricStore.DeleteAgentMetrics(ctx, agentID)
+}
+
+// ClearLatestMetrics 清除探针缓存的最新指标
+func (s *MetricService) ClearLatestMetrics(agentID string) {
+	s.latestCache.Delete(agentID)
+}
+
+// GetTraffic 估算探针在时间范围内的总流量（字节），按小时平均速率累加
+// 优先读取小时预聚合数据，预聚合数据不存在时回退到原始数据
+func (s *MetricService) GetTraffic(ctx context.Context, agentID string, start, end int64) (sent, recv float64, err error) {
+	const bucketSeconds = 3600
+	var metrics []repo.AggregatedNetworkMetric
+	if s.aggregator != nil {
+		metrics, err = s.aggregator.GetNetworkMetricsAgg(ctx, agentID, start, end, bucketSeconds, "")
+	}
+	if err != nil || len(metrics) == 0 {
+		metrics, err = s.metricStore.GetNetworkMetrics(ctx, agentID, start, end, bucketSeconds, "")
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	for _, metric := range metrics {
+		sent += metric.AvgSentRate * bucketSeconds
+		recv += metric.AvgRecvRate * bucketSeconds
+	}
+	return sent, recv, nil
 }
 
 // GetAvailableNetworkInterfaces 获取探针的可用网卡列表
 func (s *MetricService) GetAvailableNetworkInterfaces(ctx context.Context, agentID string) ([]string, error) {
-	return s.metricRepo.GetAvailableNetworkInterfaces(ctx, agentID)
+	return s.metricStore.GetAvailableNetworkInterfaces(ctx, agentID)
 }
 
 // DiskSummary 磁盘汇总数据
@@ -811,6 +1200,8 @@ type NetworkSummary struct {
 // LatestMetrics 最新指标数据（用于API响应）
 type LatestMetrics struct {
 	CPU               *models.CPUMetric               `json:"cpu,omitempty"`
+	CPUPerCore        []float64                       `json:"cpuPerCore,omitempty"`
+	CPUTimes          *protocol.CPUTimesData          `json:"cpuTimes,omitempty"`
 	Memory            *models.MemoryMetric            `json:"memory,omitempty"`
 	Disk              *DiskSummary                    `json:"disk,omitempty"`
 	Network           *NetworkSummary                 `json:"network,omitempty"`
@@ -818,4 +1209,14 @@ type LatestMetrics struct {
 	Host              *models.HostMetric              `json:"host,omitempty"`
 	GPU               []models.GPUMetric              `json:"gpu,omitempty"`
 	Temp              []models.TemperatureMetric      `json:"temperature,omitempty"`
+	WireGuard         []protocol.WireGuardData        `json:"wireguard,omitempty"`
+	Collectors        []protocol.CollectorHealth      `json:"collectors,omitempty"`
+	Mounts            []protocol.MountData            `json:"mounts,omitempty"`
+	FileUsage         *protocol.FileUsageData         `json:"fileUsage,omitempty"`
+	TimeSync          *protocol.TimeSyncData          `json:"timeSync,omitempty"`
+	ListeningPorts    []protocol.ListeningPort        `json:"listeningPorts,omitempty"`
+	Custom            []protocol.CustomMetricData     `json:"custom,omitempty"`
+	Pressure          *protocol.PressureData          `json:"pressure,omitempty"`
+	Ping              []protocol.PingData             `json:"ping,omitempty"`
+	PingAt            int64                           `json:"pingAt,omitempty"`
 }
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+func TestValidCustomMetrics(t *testing.T) {
+	tests := []struct {
+		name   string
+		metric protocol.CustomMetricData
+		want   bool
+	}{
+		{"合法指标", protocol.CustomMetricData{Name: "queue_size", Labels: map[string]string{"queue": "a"}, Value: 1}, true},
+		{"冒号指标名", protocol.CustomMetricData{Name: "job:requests:rate5m", Value: 1}, true},
+		{"标签值可以包含任意字符", protocol.CustomMetricData{Name: "up", Labels: map[string]string{"path": "a\"b\n{c}"}, Value: 1}, true},
+		{"指标名为空", protocol.CustomMetricData{Name: "", Value: 1}, false},
+		{"指标名包含换行", protocol.CustomMetricData{Name: "up 1\npika_fake", Value: 1}, false},
+		{"指标名包含花括号", protocol.CustomMetricData{Name: `up{a="b"}`, Value: 1}, false},
+		{"指标名以数字开头", protocol.CustomMetricData{Name: "1up", Value: 1}, false},
+		{"标签名包含引号", protocol.CustomMetricData{Name: "up", Labels: map[string]string{`a="b"`: "c"}, Value: 1}, false},
+		{"标签名包含冒号", protocol.CustomMetricData{Name: "up", Labels: map[string]string{"a:b": "c"}, Value: 1}, false},
+		{"数值为 NaN", protocol.CustomMetricData{Name: "up", Value: math.NaN()}, false},
+		{"数值为 Inf", protocol.CustomMetricData{Name: "up", Value: math.Inf(1)}, false},
+	}
+	for _, tt := range tests {
+		got := validCustomMetrics([]protocol.CustomMetricData{tt.metric})
+		if (len(got) == 1) != tt.want {
+			t.Errorf("%s: validCustomMetrics = %+v, 期望保留 %v", tt.name, got, tt.want)
+		}
+	}
+
+	custom := make([]protocol.CustomMetricData, 0, maxCustomMetrics+10)
+	for i := 0; i < maxCustomMetrics+10; i++ {
+		custom = append(custom, protocol.CustomMetricData{Name: fmt.Sprintf("metric_%d", i), Value: 1})
+	}
+	if got := validCustomMetrics(custom); len(got) != maxCustomMetrics {
+		t.Errorf("validCustomMetrics 返回 %d 条, 期望最多 %d 条", len(got), maxCustomMetrics)
+	}
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/protocol"
+	"gorm.io/datatypes"
+)
+
+// sealHTTPAuth 加密 HTTP 监控的认证信息；编辑时未填写的密码或令牌沿用之前保存的密文
+func (s *MonitorService) sealHTTPAuth(ctx context.Context, cfg *protocol.HTTPMonitorConfig, previous *protocol.HTTPAuthConfig) error {
+	auth := cfg.Auth
+	if auth == nil || auth.Type == "" {
+		cfg.Auth = nil
+		return nil
+	}
+	if previous == nil || previous.Type != auth.Type {
+		previous = &protocol.HTTPAuthConfig{}
+	}
+
+	switch auth.Type {
+	case protocol.HTTPAuthBasic:
+		auth.Token = ""
+	case protocol.HTTPAuthBearer:
+		auth.Username, auth.Password = "", ""
+	}
+
+	var err error
+	if auth.Password, err = s.sealSecret(ctx, auth.Password, previous.Password); err != nil {
+		return fmt.Errorf("加密认证密码失败: %w", err)
+	}
+	if auth.Token, err = s.sealSecret(ctx, auth.Token, previous.Token); err != nil {
+		return fmt.Errorf("加密认证令牌失败: %w", err)
+	}
+	return nil
+}
+
+// sealTransactionAuth 加密事务各步骤的认证信息，编辑时按步骤地址匹配之前保存的密文
+func (s *MonitorService) sealTransactionAuth(ctx context.Context, cfg *protocol.TransactionMonitorConfig, previous protocol.TransactionMonitorConfig) error {
+	for i := range cfg.Steps {
+		step := &cfg.Steps[i]
+		var previousAuth *protocol.HTTPAuthConfig
+		for _, prev := range previous.Steps {
+			if prev.URL == step.URL {
+				previousAuth = prev.Auth
+				break
+			}
+		}
+		if err := s.sealHTTPAuth(ctx, &step.HTTPMonitorConfig, previousAuth); err != nil {
+			return fmt.Errorf("步骤 %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// sealMailAuth 加密邮件服务的登录密码，编辑时用户名未变且未填写密码则沿用之前保存的密文
+func (s *MonitorService) sealMailAuth(ctx context.Context, cfg *protocol.MailMonitorConfig, previous protocol.MailMonitorConfig) error {
+	if cfg.Username == "" {
+		cfg.Password = ""
+		return nil
+	}
+	if previous.Username != cfg.Username {
+		previous.Password = ""
+	}
+	var err error
+	if cfg.Password, err = s.sealSecret(ctx, cfg.Password, previous.Password); err != nil {
+		return fmt.Errorf("加密邮件服务密码失败: %w", err)
+	}
+	return nil
+}
+
+// sealProxy 加密代理的认证密码，编辑时代理地址和用户名未变且未填写密码则沿用之前保存的密文
+func (s *MonitorService) sealProxy(ctx context.Context, cfg *protocol.ProxyConfig, previous protocol.ProxyConfig) error {
+	cfg.URL = strings.TrimSpace(cfg.URL)
+	if cfg.URL == "" {
+		*cfg = protocol.ProxyConfig{}
+		return nil
+	}
+	if cfg.Username == "" {
+		cfg.Password = ""
+		return nil
+	}
+	// 更换代理地址后不沿用旧密码，避免把密码发送给新的代理
+	if previous.URL != cfg.URL || previous.Username != cfg.Username {
+		previous.Password = ""
+	}
+	var err error
+	if cfg.Password, err = s.sealSecret(ctx, cfg.Password, previous.Password); err != nil {
+		return fmt.Errorf("加密代理密码失败: %w", err)
+	}
+	return nil
+}
+
+// sealSecret 加密新填写的值，未填写时沿用之前保存的密文
+func (s *MonitorService) sealSecret(ctx context.Context, value, previous string) (string, error) {
+	if value == "" {
+		return previous, nil
+	}
+	return s.secrets.Encrypt(ctx, value)
+}
+
+// openHTTPAuth 解密 HTTP 监控的认证信息，用于下发给探针
+func (s *MonitorService) openHTTPAuth(ctx context.Context, cfg *protocol.HTTPMonitorConfig) error {
+	if cfg.Auth == nil {
+		return nil
+	}
+	auth := *cfg.Auth
+
+	var err error
+	if auth.Password, err = s.secrets.Decrypt(ctx, auth.Password); err != nil {
+		return fmt.Errorf("解密认证密码失败: %w", err)
+	}
+	if auth.Token, err = s.secrets.Decrypt(ctx, auth.Token); err != nil {
+		return fmt.Errorf("解密认证令牌失败: %w", err)
+	}
+	cfg.Auth = &auth
+	return nil
+}
+
+// MaskMonitorSecrets 清除接口返回中的认证密码、令牌和代理密码，编辑时留空即沿用已保存的值
+func MaskMonitorSecrets(task *models.MonitorTask) {
+	httpConfig := task.HTTPConfig.Data()
+	if httpConfig.Auth != nil {
+		httpConfig.Auth = maskHTTPAuth(httpConfig.Auth)
+		task.HTTPConfig = datatypes.NewJSONType(httpConfig)
+	}
+
+	mailConfig := task.MailConfig.Data()
+	if mailConfig.Password != "" {
+		mailConfig.Password = ""
+		task.MailConfig = datatypes.NewJSONType(mailConfig)
+	}
+
+	proxy := task.Proxy.Data()
+	if proxy.Password != "" {
+		proxy.Password = ""
+		task.Proxy = datatypes.NewJSONType(proxy)
+	}
+
+	transactionConfig := task.TransactionConfig.Data()
+	if len(transactionConfig.Steps) == 0 {
+		return
+	}
+	// Data 返回的步骤切片与原配置共享，复制后再修改
+	steps := make([]protocol.TransactionStep, len(transactionConfig.Steps))
+	copy(steps, transactionConfig.Steps)
+	for i := range steps {
+		if steps[i].Auth != nil {
+			steps[i].Auth = maskHTTPAuth(steps[i].Auth)
+		}
+	}
+	transactionConfig.Steps = steps
+	task.TransactionConfig = datatypes.NewJSONType(transactionConfig)
+}
+
+func maskHTTPAuth(auth *protocol.HTTPAuthConfig) *protocol.HTTPAuthConfig {
+	masked := *auth
+	masked.Password, masked.Token = "", ""
+	return &masked
+}
@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dushixiang/pika/internal/config"
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/pkg/secretbox"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+)
+
+func newAuthTestService() *MonitorService {
+	return &MonitorService{
+		logger:  zap.NewNop(),
+		secrets: newSecretBox(zap.NewNop(), &config.AppConfig{SecretKey: "test"}, nil),
+	}
+}
+
+func TestSealHTTPAuthRoundTrip(t *testing.T) {
+	s := newAuthTestService()
+	ctx := context.Background()
+
+	cfg := protocol.HTTPMonitorConfig{Auth: &protocol.HTTPAuthConfig{
+		Type:     protocol.HTTPAuthBasic,
+		Username: "admin",
+		Password: "p@ss",
+		Token:    "ignored",
+	}}
+	if err := s.sealHTTPAuth(ctx, &cfg, nil); err != nil {
+		t.Fatalf("加密失败: %v", err)
+	}
+	if !secretbox.IsSealed(cfg.Auth.Password) {
+		t.Fatalf("密码未加密: %q", cfg.Auth.Password)
+	}
+	if cfg.Auth.Token != "" {
+		t.Fatalf("Basic 认证不应保存令牌: %q", cfg.Auth.Token)
+	}
+
+	if err := s.openHTTPAuth(ctx, &cfg); err != nil {
+		t.Fatalf("解密失败: %v", err)
+	}
+	if cfg.Auth.Password != "p@ss" || cfg.Auth.Username != "admin" {
+		t.Fatalf("解密结果错误: %+v", cfg.Auth)
+	}
+}
+
+func TestSealHTTPAuthKeepOnEdit(t *testing.T) {
+	s := newAuthTestService()
+	ctx := context.Background()
+
+	created := protocol.HTTPMonitorConfig{Auth: &protocol.HTTPAuthConfig{Type: protocol.HTTPAuthBearer, Token: "t1"}}
+	if err := s.sealHTTPAuth(ctx, &created, nil); err != nil {
+		t.Fatal(err)
+	}
+	previous := *created.Auth
+
+	tests := []struct {
+		name      string
+		auth      *protocol.HTTPAuthConfig
+		wantToken string // 解密后的令牌
+		wantNil   bool
+	}{
+		{name: "留空沿用之前的令牌", auth: &protocol.HTTPAuthConfig{Type: protocol.HTTPAuthBearer}, wantToken: "t1"},
+		{name: "填写新令牌", auth: &protocol.HTTPAuthConfig{Type: protocol.HTTPAuthBearer, Token: "t2"}, wantToken: "t2"},
+		{name: "切换认证方式不沿用", auth: &protocol.HTTPAuthConfig{Type: protocol.HTTPAuthBasic, Username: "u"}, wantToken: ""},
+		{name: "关闭认证", auth: &protocol.HTTPAuthConfig{}, wantNil: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := protocol.HTTPMonitorConfig{Auth: tt.auth}
+			if err := s.sealHTTPAuth(ctx, &cfg, &previous); err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantNil {
+				if cfg.Auth != nil {
+					t.Fatalf("认证信息应被清除: %+v", cfg.Auth)
+				}
+				return
+			}
+			if err := s.openHTTPAuth(ctx, &cfg); err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Auth.Token != tt.wantToken {
+				t.Fatalf("令牌为 %q，期望 %q", cfg.Auth.Token, tt.wantToken)
+			}
+			if cfg.Auth.Password != "" {
+				t.Fatalf("密码应为空: %q", cfg.Auth.Password)
+			}
+		})
+	}
+}
+
+func TestSealTransactionAuthKeepOnEdit(t *testing.T) {
+	s := newAuthTestService()
+	ctx := context.Background()
+
+	bearer := func(token string) protocol.HTTPMonitorConfig {
+		return protocol.HTTPMonitorConfig{Auth: &protocol.HTTPAuthConfig{Type: protocol.HTTPAuthBearer, Token: token}}
+	}
+	previous := protocol.TransactionMonitorConfig{Steps: []protocol.TransactionStep{
+		{URL: "https://a.example.com/login", HTTPMonitorConfig: bearer("t-login")},
+		{URL: "https://a.example.com/me", HTTPMonitorConfig: bearer("t-me")},
+	}}
+	if err := s.sealTransactionAuth(ctx, &previous, protocol.TransactionMonitorConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 删除第一个步骤后，留空的令牌按地址沿用原步骤的值，不会串到其他步骤
+	edited := protocol.TransactionMonitorConfig{Steps: []protocol.TransactionStep{
+		{URL: "https://a.example.com/me", HTTPMonitorConfig: bearer("")},
+		{URL: "https://a.example.com/new", HTTPMonitorConfig: bearer("")},
+	}}
+	if err := s.sealTransactionAuth(ctx, &edited, previous); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []string{"t-me", ""} {
+		cfg := edited.Steps[i].HTTPMonitorConfig
+		if err := s.openHTTPAuth(ctx, &cfg); err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Auth.Token != want {
+			t.Errorf("步骤 %d 的令牌为 %q，期望 %q", i+1, cfg.Auth.Token, want)
+		}
+	}
+}
+
+func TestSealMailAuthKeepOnEdit(t *testing.T) {
+	s := newAuthTestService()
+	ctx := context.Background()
+
+	previous := protocol.MailMonitorConfig{Username: "user", Password: "secret"}
+	if err := s.sealMailAuth(ctx, &previous, protocol.MailMonitorConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if !secretbox.IsSealed(previous.Password) {
+		t.Fatalf("密码应加密保存: %q", previous.Password)
+	}
+
+	tests := []struct {
+		name string
+		cfg  protocol.MailMonitorConfig
+		want string
+	}{
+		{name: "留空沿用", cfg: protocol.MailMonitorConfig{Username: "user"}, want: "secret"},
+		{name: "填写新密码", cfg: protocol.MailMonitorConfig{Username: "user", Password: "new"}, want: "new"},
+		{name: "更换用户名不沿用", cfg: protocol.MailMonitorConfig{Username: "other"}, want: ""},
+		{name: "清空用户名", cfg: protocol.MailMonitorConfig{Password: "secret"}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			if err := s.sealMailAuth(ctx, &cfg, previous); err != nil {
+				t.Fatal(err)
+			}
+			password, err := s.secrets.Decrypt(ctx, cfg.Password)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if password != tt.want {
+				t.Fatalf("密码为 %q，期望 %q", password, tt.want)
+			}
+		})
+	}
+}
+
+func TestSealProxyKeepOnEdit(t *testing.T) {
+	s := newAuthTestService()
+	ctx := context.Background()
+
+	previous := protocol.ProxyConfig{URL: "http://proxy.local:3128", Username: "user", Password: "secret"}
+	if err := s.sealProxy(ctx, &previous, protocol.ProxyConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if !secretbox.IsSealed(previous.Password) {
+		t.Fatalf("代理密码应加密保存: %q", previous.Password)
+	}
+
+	tests := []struct {
+		name string
+		cfg  protocol.ProxyConfig
+		want string
+	}{
+		{name: "留空沿用", cfg: protocol.ProxyConfig{URL: "http://proxy.local:3128", Username: "user"}, want: "secret"},
+		{name: "填写新密码", cfg: protocol.ProxyConfig{URL: "http://proxy.local:3128", Username: "user", Password: "new"}, want: "new"},
+		{name: "更换代理地址不沿用", cfg: protocol.ProxyConfig{URL: "http://other.local:3128", Username: "user"}, want: ""},
+		{name: "更换用户名不沿用", cfg: protocol.ProxyConfig{URL: "http://proxy.local:3128", Username: "other"}, want: ""},
+		{name: "清空代理地址", cfg: protocol.ProxyConfig{Username: "user", Password: "secret"}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			if err := s.sealProxy(ctx, &cfg, previous); err != nil {
+				t.Fatal(err)
+			}
+			password, err := s.secrets.Decrypt(ctx, cfg.Password)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if password != tt.want {
+				t.Fatalf("代理密码为 %q，期望 %q", password, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskMonitorSecrets(t *testing.T) {
+	task := models.MonitorTask{
+		HTTPConfig: datatypes.NewJSONType(protocol.HTTPMonitorConfig{Auth: &protocol.HTTPAuthConfig{
+			Type:     protocol.HTTPAuthBasic,
+			Username: "admin",
+			Password: secretbox.Prefix + "xxx",
+		}}),
+	}
+
+	MaskMonitorSecrets(&task)
+
+	auth := task.HTTPConfig.Data().Auth
+	if auth.Password != "" || auth.Token != "" {
+		t.Fatalf("密码和令牌应被清除: %+v", auth)
+	}
+	if auth.Type != protocol.HTTPAuthBasic || auth.Username != "admin" {
+		t.Fatalf("认证方式和用户名应保留: %+v", auth)
+	}
+}
+
+func TestMaskMonitorSecretsTransaction(t *testing.T) {
+	steps := []protocol.TransactionStep{{
+		URL:               "https://example.com",
+		HTTPMonitorConfig: protocol.HTTPMonitorConfig{Auth: &protocol.HTTPAuthConfig{Type: protocol.HTTPAuthBearer, Token: secretbox.Prefix + "xxx"}},
+	}}
+	task := models.MonitorTask{
+		TransactionConfig: datatypes.NewJSONType(protocol.TransactionMonitorConfig{Steps: steps}),
+	}
+
+	MaskMonitorSecrets(&task)
+
+	if token := task.TransactionConfig.Data().Steps[0].Auth.Token; token != "" {
+		t.Fatalf("事务步骤的令牌应被清除: %q", token)
+	}
+	if steps[0].Auth.Token == "" {
+		t.Fatal("不应修改原配置中的令牌")
+	}
+}
+
+func TestMaskMonitorSecretsMail(t *testing.T) {
+	task := models.MonitorTask{
+		MailConfig: datatypes.NewJSONType(protocol.MailMonitorConfig{Username: "user", Password: secretbox.Prefix + "xxx"}),
+	}
+
+	MaskMonitorSecrets(&task)
+
+	if cfg := task.MailConfig.Data(); cfg.Password != "" || cfg.Username != "user" {
+		t.Fatalf("邮件服务密码应被清除，用户名保留: %+v", cfg)
+	}
+}
+
+func TestMaskMonitorSecretsProxy(t *testing.T) {
+	task := models.MonitorTask{
+		Proxy: datatypes.NewJSONType(protocol.ProxyConfig{URL: "socks5://10.0.0.1:1080", Username: "user", Password: secretbox.Prefix + "xxx"}),
+	}
+
+	MaskMonitorSecrets(&task)
+
+	if cfg := task.Proxy.Data(); cfg.Password != "" || cfg.Username != "user" || cfg.URL == "" {
+		t.Fatalf("代理密码应被清除，地址和用户名保留: %+v", cfg)
+	}
+}
+
+func TestValidateMonitorRequestAuth(t *testing.T) {
+	tests := []struct {
+		name    string
+		auth    *protocol.HTTPAuthConfig
+		wantErr bool
+	}{
+		{name: "无认证", auth: nil},
+		{name: "Basic", auth: &protocol.HTTPAuthConfig{Type: protocol.HTTPAuthBasic, Username: "u", Password: "p"}},
+		{name: "Basic 缺少用户名", auth: &protocol.HTTPAuthConfig{Type: protocol.HTTPAuthBasic}, wantErr: true},
+		{name: "Bearer", auth: &protocol.HTTPAuthConfig{Type: protocol.HTTPAuthBearer, Token: "t"}},
+		{name: "未知认证方式", auth: &protocol.HTTPAuthConfig{Type: "digest"}, wantErr: true},
+		{name: "密码带密文前缀", auth: &protocol.HTTPAuthConfig{Type: protocol.HTTPAuthBasic, Username: "u", Password: secretbox.Prefix + "x"}, wantErr: true},
+		{name: "令牌带密文前缀", auth: &protocol.HTTPAuthConfig{Type: protocol.HTTPAuthBearer, Token: secretbox.Prefix + "x"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &MonitorTaskRequest{Type: "http", HTTPConfig: protocol.HTTPMonitorConfig{Auth: tt.auth}}
+			err := validateMonitorRequest(req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v，wantErr = %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dushixiang/pika/internal/repo"
+	"github.com/go-orz/orz"
+	"gorm.io/gorm"
+)
+
+// 徽章可用率的统计天数范围
+const (
+	defaultBadgeUptimeDays = 30
+	maxBadgeUptimeDays     = 90
+)
+
+// badgeUptimeBucketSeconds 徽章可用率使用的聚合粒度（2 小时）
+const badgeUptimeBucketSeconds = 7200
+
+// MonitorBadge 徽章展示的监控状态
+type MonitorBadge struct {
+	Name          string
+	Status        string  // up、down、unknown
+	InMaintenance bool    // 是否处于维护时间窗口
+	Days          int     // 可用率的统计天数
+	Uptime        float64 // 统计天数内的可用率（百分比）
+	HasUptime     bool    // 统计天数内是否有检测数据
+}
+
+// GetMonitorBadge 获取公开监控项的徽章数据，只允许访问匿名可见的监控项
+// 可用率基于预聚合数据计算，较长的统计范围超出原始数据保留时间时仍然可用
+func (s *MonitorService) GetMonitorBadge(ctx context.Context, monitorID string, days int) (*MonitorBadge, error) {
+	monitor, err := s.MonitorRepo.FindPublicMonitorByID(ctx, monitorID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, orz.NewError(404, "监控项不存在")
+		}
+		return nil, err
+	}
+	if !monitor.Enabled {
+		return nil, orz.NewError(404, "监控项不存在")
+	}
+
+	statsList, err := s.monitorStatsRepo.FindByMonitorId(ctx, monitor.ID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	badge := &MonitorBadge{
+		Name:          monitor.Name,
+		Status:        aggregateMonitorStats(statsList, monitor.DownQuorum).LastCheckStatus,
+		InMaintenance: inMaintenance(monitor.MaintenanceWindows, now),
+	}
+
+	if days <= 0 {
+		days = defaultBadgeUptimeDays
+	}
+	if days > maxBadgeUptimeDays {
+		days = maxBadgeUptimeDays
+	}
+	badge.Days = days
+	bucketMs := int64(badgeUptimeBucketSeconds * 1000)
+	start, end := alignTimeRangeToBucket(now.AddDate(0, 0, -days).UnixMilli(), now.UnixMilli(), bucketMs)
+
+	var metrics []repo.AggregatedMonitorMetric
+	if aggregator, ok := s.metricStore.(repo.MetricAggregator); ok {
+		metrics, err = aggregator.GetMonitorMetricsAgg(ctx, monitor.ID, start, end, badgeUptimeBucketSeconds)
+	} else {
+		metrics, err = s.metricStore.GetAggregatedMonitorMetrics(ctx, monitor.ID, start, end, badgeUptimeBucketSeconds)
+	}
+	if err != nil {
+		return nil, err
+	}
+	badge.Uptime, badge.HasUptime = sumUptime(metrics)
+	return badge, nil
+}
+
+// sumUptime 汇总各探针、各时间段的检测次数计算可用率
+func sumUptime(metrics []repo.AggregatedMonitorMetric) (float64, bool) {
+	var success, total int64
+	for _, metric := range metrics {
+		success += metric.SuccessCount
+		total += metric.TotalCount
+	}
+	if total == 0 {
+		return 0, false
+	}
+	return float64(success) / float64(total) * 100, true
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/protocol"
+	"go.uber.org/zap"
+)
+
+const (
+	// monitorCertRefresh 证书未变化时重新保存的间隔，使更新时间能反映证书仍在使用
+	monitorCertRefresh = time.Hour
+	// monitorCertStale 超过该时间未更新的证书视为不再使用（如目标改为 HTTP），不再参与告警
+	monitorCertStale = 24 * time.Hour
+)
+
+// certSnapshot 最近一次保存的证书，用于跳过未变化的写入
+type certSnapshot struct {
+	key     string
+	savedAt time.Time
+}
+
+// certSnapshotKey 证书或校验结果变化时需要立即保存
+func certSnapshotKey(info *protocol.CertInfo) string {
+	return fmt.Sprintf("%s|%v|%s", info.Fingerprint, info.ChainValid, info.ChainError)
+}
+
+// certNeedsSave 判断证书详情是否需要保存：首次收到、证书或校验结果变化、或距上次保存超过刷新间隔
+func certNeedsSave(previous certSnapshot, seen bool, key string, now time.Time) bool {
+	return !seen || previous.key != key || now.Sub(previous.savedAt) >= monitorCertRefresh
+}
+
+// recordCert 保存检测结果中的证书详情
+func (s *MonitorService) recordCert(ctx context.Context, agentID string, result protocol.MonitorData) {
+	info := result.CertInfo
+	if info == nil {
+		return
+	}
+	id := agentID + ":" + result.ID
+	key := certSnapshotKey(info)
+	now := time.Now()
+
+	s.certMu.Lock()
+	previous, seen := s.certSnapshots[id]
+	s.certMu.Unlock()
+	if !certNeedsSave(previous, seen, key, now) {
+		return
+	}
+
+	cert := &models.MonitorCert{
+		ID:                 id,
+		AgentID:            agentID,
+		MonitorId:          result.ID,
+		Subject:            info.Subject,
+		Issuer:             info.Issuer,
+		SANs:               info.SANs,
+		SignatureAlgorithm: info.SignatureAlgorithm,
+		NotBefore:          info.NotBefore,
+		NotAfter:           info.NotAfter,
+		Fingerprint:        info.Fingerprint,
+		ChainLength:        info.ChainLength,
+		ChainValid:         info.ChainValid,
+		ChainError:         info.ChainError,
+	}
+	if err := s.monitorCertRepo.Upsert(ctx, cert); err != nil {
+		s.logger.Warn("保存证书信息失败", zap.String("monitorId", result.ID), zap.String("agentId", agentID), zap.Error(err))
+		return
+	}
+
+	s.certMu.Lock()
+	s.certSnapshots[id] = certSnapshot{key: key, savedAt: now}
+	s.certMu.Unlock()
+}
+
+// forgetCerts 清除监控项的证书保存记录
+func (s *MonitorService) forgetCerts(monitorID string) {
+	s.certMu.Lock()
+	defer s.certMu.Unlock()
+	for id := range s.certSnapshots {
+		if strings.HasSuffix(id, ":"+monitorID) {
+			delete(s.certSnapshots, id)
+		}
+	}
+}
+
+// GetMonitorCerts 获取监控任务各探针检测到的证书详情，只返回当前仍在执行该监控的探针
+// 证书的使用者和备用名称包含目标域名，未登录时只有公开展示目标的监控项才返回
+func (s *MonitorService) GetMonitorCerts(ctx context.Context, monitorID string, isAuthenticated bool) ([]models.MonitorCert, error) {
+	monitor, err := s.MonitorRepo.FindById(ctx, monitorID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthenticated && !monitor.ShowTargetPublic {
+		return []models.MonitorCert{}, nil
+	}
+	certs, err := s.monitorCertRepo.FindByMonitorId(ctx, monitor.ID)
+	if err != nil {
+		return nil, err
+	}
+	agents, err := s.agentRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	agentNames := make(map[string]string)
+	for _, agent := range s.resolveTargetAgents(monitor, agents) {
+		agentNames[agent.ID] = agent.Name
+	}
+
+	result := make([]models.MonitorCert, 0, len(certs))
+	for _, cert := range certs {
+		name, ok := agentNames[cert.AgentID]
+		if !ok {
+			continue
+		}
+		cert.AgentName = name
+		result = append(result, cert)
+	}
+	return result, nil
+}
+
+// certChainFailing 判断证书是否应触发证书链告警：证书仍在使用且证书链校验未通过
+func certChainFailing(cert models.MonitorCert, now time.Time) bool {
+	return !cert.ChainValid && now.Sub(time.UnixMilli(cert.UpdatedAt)) < monitorCertStale
+}
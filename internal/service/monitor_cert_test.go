@@ -0,0 +1,51 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+func TestCertNeedsSave(t *testing.T) {
+	now := time.Now()
+	saved := certSnapshot{key: "abc|true|", savedAt: now.Add(-time.Minute)}
+
+	tests := []struct {
+		name     string
+		previous certSnapshot
+		seen     bool
+		key      string
+		want     bool
+	}{
+		{name: "首次收到", key: "abc|true|", want: true},
+		{name: "证书未变化", previous: saved, seen: true, key: "abc|true|", want: false},
+		{name: "更换证书", previous: saved, seen: true, key: "def|true|", want: true},
+		{name: "校验结果变化", previous: saved, seen: true, key: "abc|false|x509: certificate signed by unknown authority", want: true},
+		{name: "超过刷新间隔", previous: certSnapshot{key: "abc|true|", savedAt: now.Add(-monitorCertRefresh)}, seen: true, key: "abc|true|", want: true},
+	}
+	for _, tt := range tests {
+		if got := certNeedsSave(tt.previous, tt.seen, tt.key, now); got != tt.want {
+			t.Errorf("%s: 得到 %v，期望 %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCertChainFailing(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name string
+		cert models.MonitorCert
+		want bool
+	}{
+		{name: "校验通过", cert: models.MonitorCert{ChainValid: true, UpdatedAt: now.UnixMilli()}, want: false},
+		{name: "校验失败", cert: models.MonitorCert{UpdatedAt: now.Add(-time.Hour).UnixMilli()}, want: true},
+		// 目标不再返回证书（如改为 HTTP）后旧记录不再告警
+		{name: "证书已不再上报", cert: models.MonitorCert{UpdatedAt: now.Add(-monitorCertStale).UnixMilli()}, want: false},
+	}
+	for _, tt := range tests {
+		if got := certChainFailing(tt.cert, now); got != tt.want {
+			t.Errorf("%s: 得到 %v，期望 %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+// maxDownQuorum 离线判定的探针数量上限
+const maxDownQuorum = 100
+
+// monitorConsensusStatus 根据各探针的最新检测状态得出监控项的整体状态
+// quorum 为 0 时任一探针检测在线即视为在线；大于 0 时至少 quorum 个探针检测离线才视为离线，
+// 避免单个探针自身的网络故障导致误报
+func monitorConsensusStatus(up, down, quorum int) string {
+	if quorum > 0 {
+		switch {
+		case down >= quorum:
+			return "down"
+		case up > 0 || down > 0:
+			return "up"
+		default:
+			return "unknown"
+		}
+	}
+	switch {
+	case up > 0:
+		return "up"
+	case down > 0:
+		return "down"
+	default:
+		return "unknown"
+	}
+}
+
+// monitorStatusCounts 统计每个监控项最新检测结果中在线和离线的探针数量
+func monitorStatusCounts(latest []*models.MonitorMetric) map[string][2]int {
+	counts := make(map[string][2]int)
+	for _, metric := range latest {
+		count := counts[metric.MonitorId]
+		switch metric.Status {
+		case "up":
+			count[0]++
+		case "down":
+			count[1]++
+		}
+		counts[metric.MonitorId] = count
+	}
+	return counts
+}
+
+// MonitorRegionStats 监控项在某个地区的统计，地区取自执行检测的探针
+type MonitorRegionStats struct {
+	Region          string  `json:"region"`          // 地区，探针未设置地区时为空
+	AgentCount      int     `json:"agentCount"`      // 探针数量
+	UpCount         int     `json:"upCount"`         // 最后检测在线的探针数量
+	DownCount       int     `json:"downCount"`       // 最后检测离线的探针数量
+	CurrentResponse int64   `json:"currentResponse"` // 当前平均响应时间(ms)
+	AvgResponse24h  int64   `json:"avgResponse24h"`  // 24小时平均响应时间(ms)，按成功次数加权
+	Uptime24h       float64 `json:"uptime24h"`       // 24小时在线率(百分比)
+}
+
+// aggregateMonitorRegions 按探针所在地区汇总监控统计，地区按名称排序
+func aggregateMonitorRegions(stats []models.MonitorStats) []MonitorRegionStats {
+	type accumulator struct {
+		MonitorRegionStats
+		currentResponse int64
+		weightedAvg     int64
+		successChecks   int64
+		totalChecks     int64
+	}
+
+	regions := make(map[string]*accumulator)
+	for _, stat := range stats {
+		region, ok := regions[stat.Region]
+		if !ok {
+			region = &accumulator{MonitorRegionStats: MonitorRegionStats{Region: stat.Region}}
+			regions[stat.Region] = region
+		}
+		region.AgentCount++
+		switch stat.LastCheckStatus {
+		case "up":
+			region.UpCount++
+		case "down":
+			region.DownCount++
+		}
+		region.currentResponse += stat.CurrentResponse
+		region.weightedAvg += stat.AvgResponse24h * stat.SuccessChecks24h
+		region.successChecks += stat.SuccessChecks24h
+		region.totalChecks += stat.TotalChecks24h
+	}
+
+	result := make([]MonitorRegionStats, 0, len(regions))
+	for _, region := range regions {
+		region.CurrentResponse = region.currentResponse / int64(region.AgentCount)
+		if region.successChecks > 0 {
+			region.AvgResponse24h = region.weightedAvg / region.successChecks
+		}
+		if region.totalChecks > 0 {
+			region.Uptime24h = float64(region.successChecks) / float64(region.totalChecks) * 100
+		}
+		result = append(result, region.MonitorRegionStats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Region < result[j].Region
+	})
+	return result
+}
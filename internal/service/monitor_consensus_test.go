@@ -0,0 +1,51 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+func TestMonitorConsensusStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		up     int
+		down   int
+		quorum int
+		want   string
+	}{
+		{name: "任一在线即在线", up: 1, down: 2, quorum: 0, want: "up"},
+		{name: "全部离线", up: 0, down: 2, quorum: 0, want: "down"},
+		{name: "没有数据", up: 0, down: 0, quorum: 0, want: "unknown"},
+		{name: "达到离线判定数量", up: 3, down: 2, quorum: 2, want: "down"},
+		{name: "未达到离线判定数量", up: 0, down: 1, quorum: 2, want: "up"},
+		{name: "配置判定数量时没有数据", up: 0, down: 0, quorum: 2, want: "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := monitorConsensusStatus(tt.up, tt.down, tt.quorum); got != tt.want {
+				t.Fatalf("monitorConsensusStatus(%d, %d, %d) = %q，期望 %q", tt.up, tt.down, tt.quorum, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregateMonitorRegions(t *testing.T) {
+	stats := []models.MonitorStats{
+		{AgentID: "a", Region: "华东", LastCheckStatus: "up", CurrentResponse: 20, AvgResponse24h: 10, SuccessChecks24h: 100, TotalChecks24h: 100},
+		{AgentID: "b", Region: "华东", LastCheckStatus: "down", CurrentResponse: 40, AvgResponse24h: 40, SuccessChecks24h: 50, TotalChecks24h: 100},
+		{AgentID: "c", Region: "us-west", LastCheckStatus: "up", CurrentResponse: 180, AvgResponse24h: 200, SuccessChecks24h: 10, TotalChecks24h: 10},
+		{AgentID: "d", LastCheckStatus: "unknown"},
+	}
+
+	want := []MonitorRegionStats{
+		{Region: "", AgentCount: 1},
+		// 平均响应时间按成功次数加权：(10*100 + 40*50) / 150
+		{Region: "us-west", AgentCount: 1, UpCount: 1, CurrentResponse: 180, AvgResponse24h: 200, Uptime24h: 100},
+		{Region: "华东", AgentCount: 2, UpCount: 1, DownCount: 1, CurrentResponse: 30, AvgResponse24h: 20, Uptime24h: 75},
+	}
+	if got := aggregateMonitorRegions(stats); !reflect.DeepEqual(got, want) {
+		t.Fatalf("按地区汇总错误:\n得到 %+v\n期望 %+v", got, want)
+	}
+}
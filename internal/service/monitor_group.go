@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+// maxMonitorGroupLength 监控分组名称的最大长度
+const maxMonitorGroupLength = 64
+
+// 监控分组状态
+const (
+	MonitorGroupUp       = "up"       // 全部在线
+	MonitorGroupDegraded = "degraded" // 部分离线
+	MonitorGroupDown     = "down"     // 全部离线
+	MonitorGroupUnknown  = "unknown"  // 没有检测数据
+)
+
+// MonitorGroup 监控分组概要，可用率取组内有检测数据的监控项的平均值
+type MonitorGroup struct {
+	Name         string   `json:"name"`
+	Status       string   `json:"status"`
+	MonitorCount int      `json:"monitorCount"`
+	UpCount      int      `json:"upCount"`
+	DownCount    int      `json:"downCount"`
+	Uptime24h    float64  `json:"uptime24h"`
+	Uptime7d     float64  `json:"uptime7d"`
+	MonitorIds   []string `json:"monitorIds"`
+}
+
+// ListMonitorGroups 列出监控分组，按可见性过滤监控项后汇总，未分组的监控项不参与
+func (s *MonitorService) ListMonitorGroups(ctx context.Context, isAuthenticated bool) ([]MonitorGroup, error) {
+	items, err := s.ListByAuth(ctx, isAuthenticated)
+	if err != nil {
+		return nil, err
+	}
+	return summarizeMonitorGroups(items), nil
+}
+
+// summarizeMonitorGroups 按分组汇总监控项的状态和可用率
+func summarizeMonitorGroups(items []PublicMonitorOverview) []MonitorGroup {
+	groups := make(map[string]*MonitorGroup)
+	reporting := make(map[string]int)
+	for _, item := range items {
+		if item.Group == "" {
+			continue
+		}
+		group, ok := groups[item.Group]
+		if !ok {
+			group = &MonitorGroup{Name: item.Group, MonitorIds: []string{}}
+			groups[item.Group] = group
+		}
+		group.MonitorCount++
+		group.MonitorIds = append(group.MonitorIds, item.ID)
+		switch item.LastCheckStatus {
+		case "up":
+			group.UpCount++
+		case "down":
+			group.DownCount++
+		}
+		// 还没有统计数据的监控项不拉低分组的可用率
+		if item.AgentCount > 0 {
+			reporting[item.Group]++
+			group.Uptime24h += item.Uptime24h
+			group.Uptime7d += item.Uptime7d
+		}
+	}
+
+	result := make([]MonitorGroup, 0, len(groups))
+	for name, group := range groups {
+		if count := reporting[name]; count > 0 {
+			group.Uptime24h /= float64(count)
+			group.Uptime7d /= float64(count)
+		}
+		switch {
+		case group.DownCount > 0 && group.UpCount == 0:
+			group.Status = MonitorGroupDown
+		case group.DownCount > 0:
+			group.Status = MonitorGroupDegraded
+		case group.UpCount > 0:
+			group.Status = MonitorGroupUp
+		default:
+			group.Status = MonitorGroupUnknown
+		}
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// monitorGroupDown 分组内当前离线的监控项
+type monitorGroupDown struct {
+	Total int      // 组内启用的监控项数量
+	Down  []string // 离线的监控项名称
+}
+
+// countMonitorGroupDown 根据各探针的最新检测结果统计每个分组离线的监控项
+// 与概览一致，按监控项的离线判定探针数量得出整体状态；维护期间的监控项不计为离线
+func countMonitorGroupDown(tasks []models.MonitorTask, latest []*models.MonitorMetric, now time.Time) map[string]*monitorGroupDown {
+	counts := monitorStatusCounts(latest)
+
+	groups := make(map[string]*monitorGroupDown)
+	for _, task := range tasks {
+		if task.Group == "" {
+			continue
+		}
+		group, ok := groups[task.Group]
+		if !ok {
+			group = &monitorGroupDown{}
+			groups[task.Group] = group
+		}
+		group.Total++
+		count := counts[task.ID]
+		if monitorConsensusStatus(count[0], count[1], task.DownQuorum) == "down" && !inMaintenance(task.MaintenanceWindows, now) {
+			group.Down = append(group.Down, task.Name)
+		}
+	}
+	for _, group := range groups {
+		sort.Strings(group.Down)
+	}
+	return groups
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"gorm.io/datatypes"
+)
+
+func TestSummarizeMonitorGroups(t *testing.T) {
+	items := []PublicMonitorOverview{
+		{ID: "a", Group: "API", AgentCount: 2, LastCheckStatus: "up", Uptime24h: 100, Uptime7d: 99},
+		{ID: "b", Group: "API", AgentCount: 1, LastCheckStatus: "down", Uptime24h: 80, Uptime7d: 95},
+		// 没有统计数据的监控项不参与可用率计算
+		{ID: "c", Group: "API", AgentCount: 0, LastCheckStatus: "unknown"},
+		{ID: "d", Group: "Edge", AgentCount: 1, LastCheckStatus: "down", Uptime24h: 50, Uptime7d: 70},
+		{ID: "e", Group: "DB", LastCheckStatus: "unknown"},
+		{ID: "f", AgentCount: 1, LastCheckStatus: "up", Uptime24h: 100},
+	}
+
+	want := []MonitorGroup{
+		{Name: "API", Status: MonitorGroupDegraded, MonitorCount: 3, UpCount: 1, DownCount: 1, Uptime24h: 90, Uptime7d: 97, MonitorIds: []string{"a", "b", "c"}},
+		{Name: "DB", Status: MonitorGroupUnknown, MonitorCount: 1, MonitorIds: []string{"e"}},
+		{Name: "Edge", Status: MonitorGroupDown, MonitorCount: 1, DownCount: 1, Uptime24h: 50, Uptime7d: 70, MonitorIds: []string{"d"}},
+	}
+	if got := summarizeMonitorGroups(items); !reflect.DeepEqual(got, want) {
+		t.Fatalf("分组汇总错误:\n得到 %+v\n期望 %+v", got, want)
+	}
+}
+
+func TestCountMonitorGroupDown(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tasks := []models.MonitorTask{
+		{ID: "a", Name: "a", Group: "API"},
+		{ID: "b", Name: "b", Group: "API"},
+		{ID: "c", Name: "c", Group: "API"},
+		{ID: "d", Name: "d", Group: "API", MaintenanceWindows: datatypes.JSONSlice[models.MonitorMaintenanceWindow]{
+			{Type: models.MaintenanceDaily, RecurringWindow: models.RecurringWindow{StartTime: "11:00", EndTime: "13:00"}, Timezone: "UTC"},
+		}},
+		{ID: "e", Name: "e"},
+	}
+	latest := []*models.MonitorMetric{
+		{AgentId: "1", MonitorId: "a", Status: "down"},
+		// 任一探针检测在线即视为在线
+		{AgentId: "1", MonitorId: "b", Status: "down"},
+		{AgentId: "2", MonitorId: "b", Status: "up"},
+		{AgentId: "1", MonitorId: "c", Status: "down"},
+		// 维护期间不计为离线
+		{AgentId: "1", MonitorId: "d", Status: "down"},
+		{AgentId: "1", MonitorId: "e", Status: "down"},
+	}
+
+	groups := countMonitorGroupDown(tasks, latest, now)
+	if len(groups) != 1 {
+		t.Fatalf("得到 %d 个分组，期望 1 个", len(groups))
+	}
+	api := groups["API"]
+	if api.Total != 4 || !reflect.DeepEqual(api.Down, []string{"a", "c"}) {
+		t.Fatalf("得到 %+v，期望共 4 个、离线 [a c]", api)
+	}
+}
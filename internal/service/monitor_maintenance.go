@@ -0,0 +1,108 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/go-orz/orz"
+)
+
+// maxMaintenanceWindows 单个监控项最多配置的维护时间窗口数量
+const maxMaintenanceWindows = 20
+
+// maintenanceLocation 返回维护时间窗口的时区，未配置或无效时使用服务端时区
+func maintenanceLocation(w models.MonitorMaintenanceWindow) *time.Location {
+	if w.Timezone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// maintenanceActive 判断时间点是否处于维护时间窗口内
+func maintenanceActive(w models.MonitorMaintenanceWindow, t time.Time) bool {
+	if w.Type == models.MaintenanceOnce {
+		ms := t.UnixMilli()
+		return ms >= w.StartAt && ms < w.EndAt
+	}
+
+	// 每天的窗口忽略星期，每周的窗口必须指定星期
+	recurring := w.RecurringWindow
+	if w.Type == models.MaintenanceDaily {
+		recurring.Weekdays = nil
+	} else if len(recurring.Weekdays) == 0 {
+		return false
+	}
+
+	local := t.In(maintenanceLocation(w))
+	start, ok := recurringWindowStart(recurring, local)
+	return ok && !start.After(local)
+}
+
+// inMaintenance 判断时间点是否处于任一维护时间窗口内
+func inMaintenance(windows []models.MonitorMaintenanceWindow, t time.Time) bool {
+	for _, w := range windows {
+		if maintenanceActive(w, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// countsTowardUptime 维护期间的离线结果照常保存，但不计入可用率
+func countsTowardUptime(metric models.MonitorMetric, windows []models.MonitorMaintenanceWindow) bool {
+	if metric.Status == "up" || len(windows) == 0 {
+		return true
+	}
+	return !inMaintenance(windows, time.UnixMilli(metric.Timestamp))
+}
+
+// validateMaintenanceWindows 校验维护时间窗口配置
+func validateMaintenanceWindows(windows []models.MonitorMaintenanceWindow) error {
+	if len(windows) > maxMaintenanceWindows {
+		return orz.NewError(400, fmt.Sprintf("维护时间窗口不能超过 %d 个", maxMaintenanceWindows))
+	}
+	for i, w := range windows {
+		if w.Timezone != "" {
+			if _, err := time.LoadLocation(w.Timezone); err != nil {
+				return orz.NewError(400, fmt.Sprintf("维护时间窗口 %d: 无效的时区 %s", i+1, w.Timezone))
+			}
+		}
+		switch w.Type {
+		case models.MaintenanceOnce:
+			if w.StartAt <= 0 || w.EndAt <= w.StartAt {
+				return orz.NewError(400, fmt.Sprintf("维护时间窗口 %d: 结束时间必须晚于开始时间", i+1))
+			}
+			continue
+		case models.MaintenanceDaily:
+		case models.MaintenanceWeekly:
+			if len(w.Weekdays) == 0 {
+				return orz.NewError(400, fmt.Sprintf("维护时间窗口 %d: 请选择每周的维护日", i+1))
+			}
+			for _, day := range w.Weekdays {
+				if day < 0 || day > 6 {
+					return orz.NewError(400, fmt.Sprintf("维护时间窗口 %d: 无效的星期 %d", i+1, day))
+				}
+			}
+		default:
+			return orz.NewError(400, fmt.Sprintf("维护时间窗口 %d: 不支持的类型 %s", i+1, w.Type))
+		}
+
+		start, err := parseClock(w.StartTime)
+		if err != nil {
+			return orz.NewError(400, fmt.Sprintf("维护时间窗口 %d: 无效的开始时间 %s", i+1, w.StartTime))
+		}
+		end, err := parseClock(w.EndTime)
+		if err != nil {
+			return orz.NewError(400, fmt.Sprintf("维护时间窗口 %d: 无效的结束时间 %s", i+1, w.EndTime))
+		}
+		if start == end {
+			return orz.NewError(400, fmt.Sprintf("维护时间窗口 %d: 开始时间和结束时间不能相同", i+1))
+		}
+	}
+	return nil
+}
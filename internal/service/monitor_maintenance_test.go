@@ -0,0 +1,101 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+func TestMaintenanceActive(t *testing.T) {
+	// 2024-01-03 是周三
+	at := func(clock string) time.Time {
+		parsed, err := time.ParseInLocation("2006-01-02 15:04", "2024-01-03 "+clock, time.UTC)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	once := models.MonitorMaintenanceWindow{Type: models.MaintenanceOnce, StartAt: at("10:00").UnixMilli(), EndAt: at("12:00").UnixMilli()}
+	daily := models.MonitorMaintenanceWindow{Type: models.MaintenanceDaily, RecurringWindow: models.RecurringWindow{StartTime: "02:00", EndTime: "04:00"}, Timezone: "UTC"}
+	overnight := models.MonitorMaintenanceWindow{Type: models.MaintenanceDaily, RecurringWindow: models.RecurringWindow{StartTime: "23:00", EndTime: "01:00"}, Timezone: "UTC"}
+	// 周二 23:00 开始，跨天到周三 01:00
+	weekly := models.MonitorMaintenanceWindow{Type: models.MaintenanceWeekly, RecurringWindow: models.RecurringWindow{Weekdays: []int{2}, StartTime: "23:00", EndTime: "01:00"}, Timezone: "UTC"}
+	shanghai := models.MonitorMaintenanceWindow{Type: models.MaintenanceDaily, RecurringWindow: models.RecurringWindow{StartTime: "10:00", EndTime: "11:00"}, Timezone: "Asia/Shanghai"}
+
+	tests := []struct {
+		name   string
+		window models.MonitorMaintenanceWindow
+		t      time.Time
+		want   bool
+	}{
+		{name: "一次性-窗口内", window: once, t: at("11:59"), want: true},
+		{name: "一次性-结束时间不包含", window: once, t: at("12:00"), want: false},
+		{name: "一次性-开始之前", window: once, t: at("09:59"), want: false},
+		{name: "每天-窗口内", window: daily, t: at("03:30"), want: true},
+		{name: "每天-窗口外", window: daily, t: at("04:00"), want: false},
+		{name: "跨天-开始当天", window: overnight, t: at("23:30"), want: true},
+		{name: "跨天-次日", window: overnight, t: at("00:30"), want: true},
+		{name: "跨天-窗口外", window: overnight, t: at("12:00"), want: false},
+		{name: "每周-前一天开始的跨天窗口", window: weekly, t: at("00:30"), want: true},
+		{name: "每周-当天不是维护日", window: weekly, t: at("23:30"), want: false},
+		{name: "时区-上海 10:30 即 UTC 02:30", window: shanghai, t: at("02:30"), want: true},
+		{name: "时区-UTC 10:30 不在上海的窗口内", window: shanghai, t: at("10:30"), want: false},
+		{name: "时间格式无效", window: models.MonitorMaintenanceWindow{Type: models.MaintenanceDaily, RecurringWindow: models.RecurringWindow{StartTime: "2点"}}, t: at("02:00"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := maintenanceActive(tt.window, tt.t); got != tt.want {
+				t.Fatalf("maintenanceActive = %v，期望 %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountsTowardUptime(t *testing.T) {
+	windows := []models.MonitorMaintenanceWindow{{Type: models.MaintenanceOnce, StartAt: 1000, EndAt: 2000}}
+	tests := []struct {
+		name   string
+		metric models.MonitorMetric
+		want   bool
+	}{
+		{name: "维护期间正常", metric: models.MonitorMetric{Status: "up", Timestamp: 1500}, want: true},
+		{name: "维护期间离线", metric: models.MonitorMetric{Status: "down", Timestamp: 1500}, want: false},
+		{name: "维护之外离线", metric: models.MonitorMetric{Status: "down", Timestamp: 2500}, want: true},
+	}
+	for _, tt := range tests {
+		if got := countsTowardUptime(tt.metric, windows); got != tt.want {
+			t.Errorf("%s: countsTowardUptime = %v，期望 %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidateMaintenanceWindows(t *testing.T) {
+	tests := []struct {
+		name    string
+		window  models.MonitorMaintenanceWindow
+		wantErr bool
+	}{
+		{name: "一次性", window: models.MonitorMaintenanceWindow{Type: models.MaintenanceOnce, StartAt: 1000, EndAt: 2000}},
+		{name: "一次性-结束早于开始", window: models.MonitorMaintenanceWindow{Type: models.MaintenanceOnce, StartAt: 2000, EndAt: 1000}, wantErr: true},
+		{name: "每天跨天", window: models.MonitorMaintenanceWindow{Type: models.MaintenanceDaily, RecurringWindow: models.RecurringWindow{StartTime: "23:00", EndTime: "01:00"}}},
+		{name: "每天-开始结束相同", window: models.MonitorMaintenanceWindow{Type: models.MaintenanceDaily, RecurringWindow: models.RecurringWindow{StartTime: "01:00", EndTime: "01:00"}}, wantErr: true},
+		{name: "每天-时间无效", window: models.MonitorMaintenanceWindow{Type: models.MaintenanceDaily, RecurringWindow: models.RecurringWindow{StartTime: "25:00", EndTime: "01:00"}}, wantErr: true},
+		{name: "每周", window: models.MonitorMaintenanceWindow{Type: models.MaintenanceWeekly, RecurringWindow: models.RecurringWindow{Weekdays: []int{0, 6}, StartTime: "02:00", EndTime: "03:00"}, Timezone: "Asia/Shanghai"}},
+		{name: "每周-未选择星期", window: models.MonitorMaintenanceWindow{Type: models.MaintenanceWeekly, RecurringWindow: models.RecurringWindow{StartTime: "02:00", EndTime: "03:00"}}, wantErr: true},
+		{name: "每周-星期无效", window: models.MonitorMaintenanceWindow{Type: models.MaintenanceWeekly, RecurringWindow: models.RecurringWindow{Weekdays: []int{7}, StartTime: "02:00", EndTime: "03:00"}}, wantErr: true},
+		{name: "时区无效", window: models.MonitorMaintenanceWindow{Type: models.MaintenanceDaily, RecurringWindow: models.RecurringWindow{StartTime: "02:00", EndTime: "03:00"}, Timezone: "Mars/Base"}, wantErr: true},
+		{name: "不支持的类型", window: models.MonitorMaintenanceWindow{Type: "monthly"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMaintenanceWindows([]models.MonitorMaintenanceWindow{tt.window})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v，wantErr = %v", err, tt.wantErr)
+			}
+		})
+	}
+	if err := validateMaintenanceWindows(make([]models.MonitorMaintenanceWindow, maxMaintenanceWindows+1)); err == nil {
+		t.Fatal("维护时间窗口过多时应该返回错误")
+	}
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/repo"
+)
+
+// percentileBucketSeconds 原始数据已过期的时间段使用的预聚合粒度（1 小时）
+const percentileBucketSeconds = 3600
+
+// responseSample 响应时间样本，weight 为该响应时间代表的检测次数
+type responseSample struct {
+	value  int64
+	weight int64
+}
+
+// responsePercentiles 计算加权的 p50、p95、p99 响应时间（最近秩法），没有样本时返回 0
+func responsePercentiles(samples []responseSample) (p50, p95, p99 int64) {
+	var total int64
+	for _, sample := range samples {
+		total += sample.weight
+	}
+	if total == 0 {
+		return 0, 0, 0
+	}
+
+	sorted := make([]responseSample, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].value < sorted[j].value
+	})
+
+	percentile := func(p float64) int64 {
+		rank := int64(math.Ceil(p / 100 * float64(total)))
+		var cumulative int64
+		for _, sample := range sorted {
+			cumulative += sample.weight
+			if cumulative >= rank {
+				return sample.value
+			}
+		}
+		return sorted[len(sorted)-1].value
+	}
+	return percentile(50), percentile(95), percentile(99)
+}
+
+// metricResponseSamples 提取成功检测的响应时间，状态不变时合并保存的记录按其中的响应时间分布还原，
+// 没有分布的旧记录以平均响应时间按检测次数加权
+func metricResponseSamples(metrics []models.MonitorMetric) []responseSample {
+	samples := make([]responseSample, 0, len(metrics))
+	for _, metric := range metrics {
+		if metric.Status != "up" {
+			continue
+		}
+		if histogram := parseResponseHistogram(metric.ResponseHistogram); len(histogram) > 0 {
+			samples = append(samples, histogram...)
+			continue
+		}
+		samples = append(samples, responseSample{value: metric.ResponseTime, weight: metric.CheckCount()})
+	}
+	return samples
+}
+
+// parseResponseHistogram 解析合并记录中的响应时间分布，每个桶以桶中点代表桶内的检测，格式错误时返回 nil
+func parseResponseHistogram(s string) []responseSample {
+	if s == "" {
+		return nil
+	}
+	var samples []responseSample
+	for _, part := range strings.Split(s, ",") {
+		bucket, count, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil
+		}
+		index, err := strconv.Atoi(bucket)
+		if err != nil {
+			return nil
+		}
+		weight, err := strconv.ParseInt(count, 10, 64)
+		if err != nil || weight <= 0 {
+			return nil
+		}
+		samples = append(samples, responseSample{value: responseBucketValue(index), weight: weight})
+	}
+	return samples
+}
+
+// responseBucketValue 对数桶的中点（毫秒），与 responseBucket 对应
+func responseBucketValue(bucket int) int64 {
+	if bucket <= 0 {
+		return 1
+	}
+	return int64(math.Round(math.Exp2((float64(bucket) - 0.5) / responseBucketsPerDoubling)))
+}
+
+// aggResponseSamples 从预聚合数据中提取指定探针在 before 之前结束的时间段，每个时间段以平均值代表该段内的成功检测
+// 预聚合数据丢失了时间段内的分布，尾部延迟会被低估，只用于原始数据已过期的时间段
+func aggResponseSamples(metrics []repo.AggregatedMonitorMetric, agentID string, before, bucketMs int64) []responseSample {
+	samples := make([]responseSample, 0, len(metrics))
+	for _, metric := range metrics {
+		if metric.AgentID != agentID || metric.Timestamp+bucketMs > before || metric.SuccessCount == 0 {
+			continue
+		}
+		samples = append(samples, responseSample{value: int64(math.Round(metric.AvgResponse)), weight: metric.SuccessCount})
+	}
+	return samples
+}
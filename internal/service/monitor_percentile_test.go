@@ -0,0 +1,108 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/repo"
+)
+
+func TestResponsePercentiles(t *testing.T) {
+	// 1..100 每个值一次检测
+	uniform := make([]responseSample, 0, 100)
+	for i := int64(100); i >= 1; i-- {
+		uniform = append(uniform, responseSample{value: i, weight: 1})
+	}
+
+	tests := []struct {
+		name    string
+		samples []responseSample
+		want    [3]int64
+	}{
+		{name: "没有样本", samples: nil, want: [3]int64{0, 0, 0}},
+		{name: "单个样本", samples: []responseSample{{value: 42, weight: 1}}, want: [3]int64{42, 42, 42}},
+		{name: "均匀分布", samples: uniform, want: [3]int64{50, 95, 99}},
+		// 合并保存的记录按检测次数加权：98 次 10ms，2 次 1000ms
+		{name: "按检测次数加权", samples: []responseSample{{value: 1000, weight: 2}, {value: 10, weight: 98}}, want: [3]int64{10, 10, 1000}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p50, p95, p99 := responsePercentiles(tt.samples)
+			if got := [3]int64{p50, p95, p99}; got != tt.want {
+				t.Fatalf("得到 %v，期望 %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetricResponseSamples(t *testing.T) {
+	metrics := []models.MonitorMetric{
+		{Status: "up", ResponseTime: 10},
+		{Status: "up", ResponseTime: 20, Checks: 5},
+		{Status: "down", ResponseTime: 5000},
+	}
+	samples := metricResponseSamples(metrics)
+	if len(samples) != 2 || samples[0].weight != 1 || samples[1].weight != 5 {
+		t.Fatalf("离线的检测不应计入响应时间，合并的记录应按检测次数加权，得到 %+v", samples)
+	}
+}
+
+func TestMergedRecordPercentiles(t *testing.T) {
+	// 同一状态下 98 次 10ms、2 次 1000ms 的检测经过采样器合并保存
+	sampler := newMonitorSampler()
+	var saved []models.MonitorMetric
+	for i := int64(0); i < 100; i++ {
+		responseTime := int64(10)
+		if i%50 == 49 {
+			responseTime = 1000
+		}
+		metric := models.MonitorMetric{AgentId: "a", MonitorId: "m", Status: "up", ResponseTime: responseTime, Timestamp: i * 1000}
+		saved = append(saved, sampler.sample(metric, 3600*1000)...)
+	}
+	saved = append(saved, sampler.sweep(100*3600*1000, 3600*1000)...)
+	if len(saved) != 2 || saved[1].Checks != 99 {
+		t.Fatalf("应保存首次检测和 1 条合并记录，得到 %+v", saved)
+	}
+
+	p50, p95, p99 := responsePercentiles(metricResponseSamples(saved))
+	if p50 != 10 || p95 != 10 {
+		t.Fatalf("p50、p95 = %d、%d，期望 10", p50, p95)
+	}
+	// 按平均值还原时 p99 只有 29ms，按分布还原时应接近 1000ms
+	if p99 < 950 || p99 > 1050 {
+		t.Fatalf("p99 = %d，期望接近 1000", p99)
+	}
+}
+
+func TestResponseHistogram(t *testing.T) {
+	for _, ms := range []int64{1, 2, 3, 10, 99, 1000, 60000} {
+		got := responseBucketValue(responseBucket(ms))
+		if diff := math.Abs(float64(got-ms)) / math.Max(float64(ms), 1); diff > 0.05 {
+			t.Errorf("%dms 还原为 %dms，误差超过 5%%", ms, got)
+		}
+	}
+
+	samples := parseResponseHistogram(formatResponseHistogram(map[int]int64{27: 98, 80: 2}))
+	if len(samples) != 2 || samples[0].weight != 98 || samples[1].weight != 2 {
+		t.Fatalf("解析结果 %+v", samples)
+	}
+	if parseResponseHistogram("27:98,x") != nil {
+		t.Fatalf("格式错误时应返回 nil")
+	}
+}
+
+func TestAggResponseSamples(t *testing.T) {
+	const bucketMs = 3600 * 1000
+	metrics := []repo.AggregatedMonitorMetric{
+		{Timestamp: 0, AgentID: "a", AvgResponse: 10.4, SuccessCount: 12},
+		{Timestamp: bucketMs, AgentID: "b", AvgResponse: 30, SuccessCount: 12},
+		{Timestamp: bucketMs, AgentID: "a", AvgResponse: 20, SuccessCount: 0},
+		// 与原始数据重叠的时间段不使用
+		{Timestamp: 2 * bucketMs, AgentID: "a", AvgResponse: 40, SuccessCount: 12},
+	}
+	samples := aggResponseSamples(metrics, "a", 2*bucketMs+1000, bucketMs)
+	if len(samples) != 1 || samples[0] != (responseSample{value: 10, weight: 12}) {
+		t.Fatalf("得到 %+v，期望只有第一个时间段", samples)
+	}
+}
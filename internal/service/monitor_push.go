@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/go-orz/orz"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// PushAgentID 被动心跳监控的结果不来自探针，统计、告警和回调中统一使用该 ID
+const PushAgentID = "push"
+
+// maxPushGracePeriod 宽限时间上限（秒）
+const maxPushGracePeriod = 7 * 24 * 3600
+
+// pushAgent 被动心跳监控在统计和告警中对应的虚拟探针
+func pushAgent() models.Agent {
+	return models.Agent{ID: PushAgentID, Name: "被动心跳"}
+}
+
+// newPushToken 生成心跳推送地址中的令牌
+func newPushToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// pushOverdue 判断是否已超过检测频率加宽限时间仍未收到心跳，返回距离上次心跳（或上次修改配置）的秒数
+func pushOverdue(monitor models.MonitorTask, now int64) (bool, int64) {
+	since := monitor.LastPushAt
+	if monitor.UpdatedAt > since {
+		// 新建或修改配置后重新计时，避免刚创建的监控项立即离线
+		since = monitor.UpdatedAt
+	}
+	elapsed := (now - since) / 1000
+	return elapsed > int64(monitor.Interval+monitor.PushGracePeriod), elapsed
+}
+
+// ReceivePush 处理外部任务推送的心跳，status 为 down 时表示任务主动上报失败
+func (s *MonitorService) ReceivePush(ctx context.Context, token, status, msg string, responseTime int64) error {
+	if token == "" {
+		return orz.NewError(404, "监控项不存在")
+	}
+	monitor, err := s.MonitorRepo.FindByPushToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return orz.NewError(404, "监控项不存在")
+		}
+		return err
+	}
+	if !monitor.Enabled {
+		return orz.NewError(400, "监控项已停用")
+	}
+
+	switch status {
+	case "":
+		status = "up"
+	case "up", "down":
+	default:
+		return orz.NewError(400, "status 只支持 up 或 down")
+	}
+	if responseTime < 0 {
+		responseTime = 0
+	}
+
+	now := time.Now().UnixMilli()
+	if err := s.MonitorRepo.UpdateLastPushAt(ctx, monitor.ID, now); err != nil {
+		return err
+	}
+
+	result := protocol.MonitorData{
+		ID:           monitor.ID,
+		Type:         monitor.Type,
+		Target:       monitor.Target,
+		Status:       status,
+		ResponseTime: responseTime,
+		CheckedAt:    now,
+	}
+	if status == "down" {
+		result.Error = msg
+	} else {
+		result.Message = msg
+	}
+	return s.recordPushResult(ctx, result)
+}
+
+// evaluatePush 由调度器按检测频率调用，超过宽限时间仍未收到心跳时记录一次离线结果
+func (s *MonitorService) evaluatePush(ctx context.Context, monitor models.MonitorTask) error {
+	now := time.Now().UnixMilli()
+	overdue, elapsed := pushOverdue(monitor, now)
+	if !overdue {
+		return nil
+	}
+
+	s.logger.Debug("未按时收到心跳",
+		zap.String("monitorID", monitor.ID),
+		zap.Int64("elapsed", elapsed))
+
+	return s.recordPushResult(ctx, protocol.MonitorData{
+		ID:        monitor.ID,
+		Type:      monitor.Type,
+		Target:    monitor.Target,
+		Status:    "down",
+		Error:     fmt.Sprintf("已 %d 秒未收到心跳，超过检测频率 %d 秒加宽限时间 %d 秒", elapsed, monitor.Interval, monitor.PushGracePeriod),
+		CheckedAt: now,
+	})
+}
+
+// recordPushResult 保存心跳检测结果，并按状态变化触发回调
+func (s *MonitorService) recordPushResult(ctx context.Context, result protocol.MonitorData) error {
+	metric := &models.MonitorMetric{
+		AgentId:      PushAgentID,
+		MonitorId:    result.ID,
+		Type:         result.Type,
+		Target:       result.Target,
+		Status:       result.Status,
+		ResponseTime: result.ResponseTime,
+		Error:        result.Error,
+		Message:      result.Message,
+		Timestamp:    result.CheckedAt,
+	}
+	if err := s.metricStore.SaveMonitorMetric(ctx, metric); err != nil {
+		return err
+	}
+	s.HandleMonitorResults(ctx, PushAgentID, []protocol.MonitorData{result})
+	return nil
+}
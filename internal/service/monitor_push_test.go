@@ -0,0 +1,51 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+func TestPushOverdue(t *testing.T) {
+	const now = int64(1700000000000)
+	tests := []struct {
+		name        string
+		monitor     models.MonitorTask
+		wantOverdue bool
+		wantElapsed int64
+	}{
+		{
+			name:        "周期内收到心跳",
+			monitor:     models.MonitorTask{Interval: 60, LastPushAt: now - 30*1000, UpdatedAt: now - 3600*1000},
+			wantOverdue: false, wantElapsed: 30,
+		},
+		{
+			name:        "超过周期但在宽限时间内",
+			monitor:     models.MonitorTask{Interval: 60, PushGracePeriod: 30, LastPushAt: now - 80*1000},
+			wantOverdue: false, wantElapsed: 80,
+		},
+		{
+			name:        "超过周期加宽限时间",
+			monitor:     models.MonitorTask{Interval: 60, PushGracePeriod: 30, LastPushAt: now - 91*1000},
+			wantOverdue: true, wantElapsed: 91,
+		},
+		{
+			name:        "新建后从未收到心跳",
+			monitor:     models.MonitorTask{Interval: 3600, UpdatedAt: now - 10*1000},
+			wantOverdue: false, wantElapsed: 10,
+		},
+		{
+			name:        "修改配置后重新计时",
+			monitor:     models.MonitorTask{Interval: 60, LastPushAt: now - 7200*1000, UpdatedAt: now - 5*1000},
+			wantOverdue: false, wantElapsed: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overdue, elapsed := pushOverdue(tt.monitor, now)
+			if overdue != tt.wantOverdue || elapsed != tt.wantElapsed {
+				t.Fatalf("pushOverdue = (%v, %d)，期望 (%v, %d)", overdue, elapsed, tt.wantOverdue, tt.wantElapsed)
+			}
+		})
+	}
+}
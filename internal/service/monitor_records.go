@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/repo"
+)
+
+const (
+	// monitorRecordMaxPageSize 分页查询原始检测记录时每页的最大数量
+	monitorRecordMaxPageSize = 500
+	// monitorRecordExportWindow 导出时每次查询的时间范围，分段查询避免一次加载整个时间范围的记录
+	monitorRecordExportWindow = 6 * time.Hour
+)
+
+// IsValidMonitorRecordStatus 检测记录的状态筛选是否有效，为空表示不筛选
+func IsValidMonitorRecordStatus(status string) bool {
+	switch status {
+	case "", "up", "down":
+		return true
+	}
+	return false
+}
+
+// PageMonitorRecords 分页查询监控项的原始检测记录，按时间倒序
+// 状态不变的连续检测按采样间隔合并为一条记录，checks 为合并的检测次数
+func (s *MonitorService) PageMonitorRecords(ctx context.Context, filter repo.MonitorMetricFilter, pageIndex, pageSize int) ([]models.MonitorMetric, int64, error) {
+	if _, err := s.MonitorRepo.FindById(ctx, filter.MonitorID); err != nil {
+		return nil, 0, err
+	}
+	if pageIndex < 1 {
+		pageIndex = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	if pageSize > monitorRecordMaxPageSize {
+		pageSize = monitorRecordMaxPageSize
+	}
+	records, total, err := s.metricStore.PageMonitorMetrics(ctx, filter, (pageIndex-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	if records == nil {
+		records = []models.MonitorMetric{}
+	}
+	return records, total, nil
+}
+
+// ExportMonitorRecords 按时间正序导出原始检测记录（CSV 或 JSON Lines），分段查询并边查边写，用于留存 SLA 证据
+func (s *MonitorService) ExportMonitorRecords(ctx context.Context, filter repo.MonitorMetricFilter, format string, w io.Writer) error {
+	encoder := newMetricRowEncoder(format, w)
+	windowMs := monitorRecordExportWindow.Milliseconds()
+	for windowStart := filter.Start; windowStart <= filter.End; windowStart += windowMs {
+		window := filter
+		window.Start = windowStart
+		window.End = min(windowStart+windowMs-1, filter.End)
+		records, err := s.metricStore.ListMonitorMetrics(ctx, window)
+		if err != nil {
+			return err
+		}
+		for i := range records {
+			if err := encoder.encode(reflect.ValueOf(records[i])); err != nil {
+				return err
+			}
+		}
+		if err := encoder.flush(); err != nil {
+			return err
+		}
+		if f, ok := w.(interface{ Flush() }); ok {
+			f.Flush()
+		}
+	}
+	return encoder.flush()
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/repo"
+)
+
+// recordMetricStore 只实现导出用到的查询，记录每次查询的时间范围
+type recordMetricStore struct {
+	repo.MetricStore
+	records []models.MonitorMetric
+	windows [][2]int64
+}
+
+func (s *recordMetricStore) ListMonitorMetrics(ctx context.Context, filter repo.MonitorMetricFilter) ([]models.MonitorMetric, error) {
+	s.windows = append(s.windows, [2]int64{filter.Start, filter.End})
+	var result []models.MonitorMetric
+	for _, record := range s.records {
+		if record.Timestamp >= filter.Start && record.Timestamp <= filter.End && (filter.Status == "" || record.Status == filter.Status) {
+			result = append(result, record)
+		}
+	}
+	return result, nil
+}
+
+func TestExportMonitorRecords(t *testing.T) {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	end := start + 2*monitorRecordExportWindow.Milliseconds()
+	store := &recordMetricStore{records: []models.MonitorMetric{
+		{AgentId: "a1", MonitorId: "m1", Status: "down", Error: "timeout", Checks: 3, Timestamp: start},
+		{AgentId: "a1", MonitorId: "m1", Status: "up", Checks: 10, Timestamp: start + 1000},
+		{AgentId: "a2", MonitorId: "m1", Status: "down", Error: "refused", Checks: 1, Timestamp: end},
+		{AgentId: "a2", MonitorId: "m1", Status: "down", Target: "@evil", Error: "=HYPERLINK(\"http://evil\")", Message: "+1", ContentDetail: "-cmd", Checks: 1, Timestamp: end},
+	}}
+	s := &MonitorService{metricStore: store}
+
+	var buf bytes.Buffer
+	filter := repo.MonitorMetricFilter{MonitorID: "m1", Status: "down", Start: start, End: end}
+	if err := s.ExportMonitorRecords(context.Background(), filter, MetricExportCSV, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// 时间范围按窗口分段查询，相邻窗口不重叠且覆盖到结束时间
+	if len(store.windows) != 3 || store.windows[0][0] != start || store.windows[2][1] != end || store.windows[1][0] != store.windows[0][1]+1 {
+		t.Fatalf("分段查询的时间范围错误: %v", store.windows)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("应导出表头和 3 条离线记录，实际 %d 行", len(rows))
+	}
+	header := strings.Join(rows[0], ",")
+	if !strings.Contains(header, "status") || !strings.Contains(header, "checks") || !strings.Contains(header, "timestamp,time") {
+		t.Fatalf("表头缺少字段: %s", header)
+	}
+	if !strings.Contains(strings.Join(rows[1], ","), "timeout") || !strings.Contains(strings.Join(rows[2], ","), "refused") {
+		t.Fatalf("导出的记录错误: %v", rows[1:])
+	}
+
+	// 来自被监控目标的文本不应被表格软件当作公式执行
+	cells := make(map[string]string)
+	for i, name := range rows[0] {
+		cells[name] = rows[3][i]
+	}
+	if cells["target"] != "'@evil" || cells["error"] != "'=HYPERLINK(\"http://evil\")" || cells["message"] != "'+1" || cells["contentDetail"] != "'-cmd" {
+		t.Fatalf("文本字段未转义: %v", cells)
+	}
+}
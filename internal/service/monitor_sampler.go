@@ -0,0 +1,133 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+const defaultMonitorSampleSeconds = 300 // 服务监控状态不变时默认每 5 分钟保存一条
+
+// responseBucketsPerDoubling 响应时间分布每翻一倍划分的桶数，按桶中点还原时误差不超过约 4.5%
+const responseBucketsPerDoubling = 8
+
+// monitorSampler 服务监控结果采样器
+// 状态变化时立即保存，状态不变时把期间的检测结果合并为一条，每隔采样间隔保存一次，
+// 合并后的记录通过 Checks 记录检测次数、ResponseTime 记录平均响应时间、ResponseHistogram 记录响应时间分布，统计时按次数加权还原
+type monitorSampler struct {
+	mu      sync.Mutex
+	samples map[string]*monitorSample // key: agentID/monitorID
+}
+
+// monitorSample 单个探针单个监控项尚未保存的检测结果
+type monitorSample struct {
+	status      string
+	savedAt     int64                // 上一次保存的检测时间
+	latest      models.MonitorMetric // 最近一次检测结果
+	checks      int                  // 未保存的检测次数
+	responseSum int64                // 未保存的检测响应时间之和
+	histogram   map[int]int64        // 未保存的检测响应时间分布，key 为 responseBucket 的桶序号
+}
+
+func newMonitorSampler() *monitorSampler {
+	return &monitorSampler{
+		samples: make(map[string]*monitorSample),
+	}
+}
+
+// sample 处理一次检测结果，返回需要保存的记录，intervalMs 为状态不变时的保存间隔
+func (m *monitorSampler) sample(metric models.MonitorMetric, intervalMs int64) []models.MonitorMetric {
+	key := metric.AgentId + "/" + metric.MonitorId
+	metric.Checks = 1
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.samples[key]
+	if !ok || current.status != metric.Status {
+		// 首次检测或状态变化：先保存变化前尚未保存的结果，再立即保存本次结果
+		var metrics []models.MonitorMetric
+		if ok && current.checks > 0 {
+			metrics = append(metrics, current.merged())
+		}
+		m.samples[key] = &monitorSample{status: metric.Status, savedAt: metric.Timestamp, latest: metric}
+		return append(metrics, metric)
+	}
+
+	current.latest = metric
+	current.checks++
+	current.responseSum += metric.ResponseTime
+	if current.histogram == nil {
+		current.histogram = make(map[int]int64)
+	}
+	current.histogram[responseBucket(metric.ResponseTime)]++
+	if metric.Timestamp-current.savedAt < intervalMs {
+		return nil
+	}
+
+	merged := current.merged()
+	current.reset(metric.Timestamp)
+	return []models.MonitorMetric{merged}
+}
+
+// sweep 保存超过采样间隔未再收到检测结果的数据（如探针离线、监控项被删除），并释放对应状态
+func (m *monitorSampler) sweep(now, intervalMs int64) []models.MonitorMetric {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var metrics []models.MonitorMetric
+	for key, current := range m.samples {
+		if now-current.latest.Timestamp < intervalMs {
+			continue
+		}
+		if current.checks > 0 {
+			metrics = append(metrics, current.merged())
+		}
+		delete(m.samples, key)
+	}
+	return metrics
+}
+
+// merged 将尚未保存的检测结果合并为一条记录，其余字段取最近一次检测
+func (s *monitorSample) merged() models.MonitorMetric {
+	metric := s.latest
+	metric.Checks = s.checks
+	metric.ResponseTime = s.responseSum / int64(s.checks)
+	metric.ResponseHistogram = formatResponseHistogram(s.histogram)
+	return metric
+}
+
+// reset 保存后清空计数
+func (s *monitorSample) reset(savedAt int64) {
+	s.savedAt = savedAt
+	s.checks = 0
+	s.responseSum = 0
+	s.histogram = nil
+}
+
+// responseBucket 响应时间所在的对数桶，桶 i 覆盖 (2^((i-1)/8), 2^(i/8)] 毫秒，不超过 1ms 的归入桶 0
+func responseBucket(ms int64) int {
+	if ms <= 1 {
+		return 0
+	}
+	return int(math.Ceil(math.Log2(float64(ms)) * responseBucketsPerDoubling))
+}
+
+// formatResponseHistogram 将响应时间分布编码为 "桶序号:次数" 列表，按桶序号排序
+func formatResponseHistogram(histogram map[int]int64) string {
+	buckets := make([]int, 0, len(histogram))
+	for bucket := range histogram {
+		buckets = append(buckets, bucket)
+	}
+	sort.Ints(buckets)
+
+	parts := make([]string, 0, len(buckets))
+	for _, bucket := range buckets {
+		parts = append(parts, strconv.Itoa(bucket)+":"+strconv.FormatInt(histogram[bucket], 10))
+	}
+	return strings.Join(parts, ",")
+}
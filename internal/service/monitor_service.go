@@ -4,13 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/dushixiang/pika/internal/config"
 	"github.com/dushixiang/pika/internal/models"
 	"github.com/dushixiang/pika/internal/protocol"
 	"github.com/dushixiang/pika/internal/repo"
 	ws "github.com/dushixiang/pika/internal/websocket"
+	"github.com/dushixiang/pika/pkg/tracing"
 	"github.com/go-orz/cache"
 	"github.com/go-orz/orz"
 	"github.com/go-orz/toolkit"
@@ -25,9 +29,12 @@ type MonitorService struct {
 	*repo.MonitorRepo
 	*orz.Service
 	agentRepo        *repo.AgentRepo
-	metricRepo       *repo.MetricRepo
+	metricStore      repo.MetricStore
 	monitorStatsRepo *repo.MonitorStatsRepo
+	monitorCertRepo  *repo.MonitorCertRepo
+	monitorTraceRepo *repo.MonitorTraceRepo
 	wsManager        *ws.Manager
+	secrets          *secretBox
 
 	// 监控概览缓存：缓存监控任务列表（使用不同的 key 区分 public 和 private）
 	overviewCache cache.Cache[string, []PublicMonitorOverview]
@@ -36,6 +43,14 @@ type MonitorService struct {
 
 	// 调度器引用（用于动态管理任务）
 	scheduler MonitorScheduler
+
+	// 各探针上最近一次的检测状态，用于判断状态变化并触发回调
+	statusMu     sync.Mutex
+	lastStatuses map[string]map[string]string // monitorID -> agentID -> status
+
+	// 最近一次保存的证书，证书未变化时减少写入
+	certMu        sync.Mutex
+	certSnapshots map[string]certSnapshot // agentID:monitorID -> snapshot
 }
 
 // MonitorScheduler 调度器接口（避免循环依赖）
@@ -45,19 +60,25 @@ type MonitorScheduler interface {
 	RemoveTask(monitorID string)
 }
 
-func NewMonitorService(logger *zap.Logger, db *gorm.DB, wsManager *ws.Manager) *MonitorService {
+func NewMonitorService(logger *zap.Logger, db *gorm.DB, metricStore repo.MetricStore, wsManager *ws.Manager, propertyService *PropertyService, appConfig *config.AppConfig) *MonitorService {
 	return &MonitorService{
-		logger:           logger,
+		logger:           logger.Named("monitor"),
 		Service:          orz.NewService(db),
 		MonitorRepo:      repo.NewMonitorRepo(db),
 		agentRepo:        repo.NewAgentRepo(db),
-		metricRepo:       repo.NewMetricRepo(db),
+		metricStore:      metricStore,
 		monitorStatsRepo: repo.NewMonitorStatsRepo(db),
+		monitorCertRepo:  repo.NewMonitorCertRepo(db),
+		monitorTraceRepo: repo.NewMonitorTraceRepo(db),
 		wsManager:        wsManager,
+		secrets:          newSecretBox(logger.Named("secret"), appConfig, propertyService),
 
 		// 缓存 5 分钟，避免频繁查询
 		overviewCache: cache.New[string, []PublicMonitorOverview](5 * time.Minute),
 		statsCache:    cache.New[string, []models.MonitorStats](5 * time.Minute),
+
+		lastStatuses:  make(map[string]map[string]string),
+		certSnapshots: make(map[string]certSnapshot),
 	}
 }
 
@@ -67,45 +88,69 @@ func (s *MonitorService) SetScheduler(scheduler MonitorScheduler) {
 }
 
 type MonitorTaskRequest struct {
-	Name             string                     `json:"name"`
-	Type             string                     `json:"type"`
-	Target           string                     `json:"target"`
-	Description      string                     `json:"description"`
-	Enabled          bool                       `json:"enabled,omitempty"`
-	ShowTargetPublic bool                       `json:"showTargetPublic,omitempty"` // 在公开页面是否显示目标地址
-	Visibility       string                     `json:"visibility,omitempty"`       // 可见性: public-匿名可见, private-登录可见
-	Interval         int                        `json:"interval"`                   // 检测频率（秒）
-	HTTPConfig       protocol.HTTPMonitorConfig `json:"httpConfig,omitempty"`
-	TCPConfig        protocol.TCPMonitorConfig  `json:"tcpConfig,omitempty"`
-	ICMPConfig       protocol.ICMPMonitorConfig `json:"icmpConfig,omitempty"`
-	AgentIds         []string                   `json:"agentIds,omitempty"`
-	Tags             []string                   `json:"tags"`
+	Name               string                            `json:"name"`
+	Type               string                            `json:"type"`
+	Target             string                            `json:"target"`
+	Description        string                            `json:"description"`
+	Enabled            bool                              `json:"enabled,omitempty"`
+	ShowTargetPublic   bool                              `json:"showTargetPublic,omitempty"` // 在公开页面是否显示目标地址
+	Visibility         string                            `json:"visibility,omitempty"`       // 可见性: public-匿名可见, private-登录可见
+	Interval           int                               `json:"interval"`                   // 检测频率（秒）
+	Retries            int                               `json:"retries,omitempty"`          // 检测失败后的重试次数
+	HTTPConfig         protocol.HTTPMonitorConfig        `json:"httpConfig,omitempty"`
+	TCPConfig          protocol.TCPMonitorConfig         `json:"tcpConfig,omitempty"`
+	UDPConfig          protocol.UDPMonitorConfig         `json:"udpConfig,omitempty"`
+	ICMPConfig         protocol.ICMPMonitorConfig        `json:"icmpConfig,omitempty"`
+	TransactionConfig  protocol.TransactionMonitorConfig `json:"transactionConfig,omitempty"`  // 多步骤事务监控配置
+	MailConfig         protocol.MailMonitorConfig        `json:"mailConfig,omitempty"`         // 邮件服务监控配置
+	Proxy              protocol.ProxyConfig              `json:"proxy,omitempty"`              // 出口代理
+	PushGracePeriod    int                               `json:"pushGracePeriod,omitempty"`    // 被动心跳监控的宽限时间（秒）
+	MaintenanceWindows []models.MonitorMaintenanceWindow `json:"maintenanceWindows,omitempty"` // 维护时间窗口
+	Webhook            models.MonitorWebhookConfig       `json:"webhook,omitempty"`            // 状态变化回调
+	AgentIds           []string                          `json:"agentIds,omitempty"`
+	Tags               []string                          `json:"tags"`
+	Group              string                            `json:"group,omitempty"`      // 监控分组
+	DownQuorum         int                               `json:"downQuorum,omitempty"` // 至少多少个探针检测离线才视为离线
+	SLATarget          float64                           `json:"slaTarget,omitempty"`  // SLA 目标可用率（百分比）
 }
 
 // PublicMonitorOverview 用于公开展示的监控配置及汇总数据
 type PublicMonitorOverview struct {
-	ID               string   `json:"id"`
-	Name             string   `json:"name"`
-	Type             string   `json:"type"`
-	Target           string   `json:"target"`
-	ShowTargetPublic bool     `json:"showTargetPublic"` // 在公开页面是否显示目标地址
-	Description      string   `json:"description"`
-	Enabled          bool     `json:"enabled"`
-	Interval         int      `json:"interval"`
-	AgentIds         []string `json:"agentIds"`
-	AgentCount       int      `json:"agentCount"`
-	LastCheckStatus  string   `json:"lastCheckStatus"`
-	LastCheckError   string   `json:"lastCheckError"`
-	CurrentResponse  int64    `json:"currentResponse"`
-	AvgResponse24h   int64    `json:"avgResponse24h"`
-	Uptime24h        float64  `json:"uptime24h"`
-	Uptime7d         float64  `json:"uptime7d"`
-	CertExpiryDate   int64    `json:"certExpiryDate"`
-	CertExpiryDays   int      `json:"certExpiryDays"`
-	LastCheckTime    int64    `json:"lastCheckTime"`
+	ID               string               `json:"id"`
+	Name             string               `json:"name"`
+	Type             string               `json:"type"`
+	Target           string               `json:"target"`
+	ShowTargetPublic bool                 `json:"showTargetPublic"` // 在公开页面是否显示目标地址
+	Description      string               `json:"description"`
+	Enabled          bool                 `json:"enabled"`
+	Interval         int                  `json:"interval"`
+	Group            string               `json:"group"` // 监控分组
+	AgentIds         []string             `json:"agentIds"`
+	AgentCount       int                  `json:"agentCount"`
+	LastCheckStatus  string               `json:"lastCheckStatus"`
+	LastCheckError   string               `json:"lastCheckError"`
+	CurrentResponse  int64                `json:"currentResponse"`
+	AvgResponse24h   int64                `json:"avgResponse24h"`
+	P50Response24h   int64                `json:"p50Response24h"` // 24小时响应时间中位数(ms)，多个探针时取最慢的探针，下同
+	P95Response24h   int64                `json:"p95Response24h"`
+	P99Response24h   int64                `json:"p99Response24h"`
+	P50Response30d   int64                `json:"p50Response30d"`
+	P95Response30d   int64                `json:"p95Response30d"`
+	P99Response30d   int64                `json:"p99Response30d"`
+	Uptime24h        float64              `json:"uptime24h"`
+	Uptime7d         float64              `json:"uptime7d"`
+	CertExpiryDate   int64                `json:"certExpiryDate"`
+	CertExpiryDays   int                  `json:"certExpiryDays"`
+	LastCheckTime    int64                `json:"lastCheckTime"`
+	DownQuorum       int                  `json:"downQuorum"`
+	Regions          []MonitorRegionStats `json:"regions"` // 按探针地区统计的响应时间
 }
 
 func (s *MonitorService) CreateMonitor(ctx context.Context, req *MonitorTaskRequest) (*models.MonitorTask, error) {
+	if err := validateMonitorRequest(req); err != nil {
+		return nil, err
+	}
+
 	// 设置默认检测频率
 	interval := req.Interval
 	if interval <= 0 {
@@ -118,23 +163,57 @@ func (s *MonitorService) CreateMonitor(ctx context.Context, req *MonitorTaskRequ
 		visibility = "public" // 默认公开可见
 	}
 
+	if err := s.sealHTTPAuth(ctx, &req.HTTPConfig, nil); err != nil {
+		return nil, err
+	}
+	if err := s.sealTransactionAuth(ctx, &req.TransactionConfig, protocol.TransactionMonitorConfig{}); err != nil {
+		return nil, err
+	}
+	if err := s.sealMailAuth(ctx, &req.MailConfig, protocol.MailMonitorConfig{}); err != nil {
+		return nil, err
+	}
+	if err := s.sealProxy(ctx, &req.Proxy, protocol.ProxyConfig{}); err != nil {
+		return nil, err
+	}
+
+	var pushToken string
+	if req.Type == "push" {
+		token, err := newPushToken()
+		if err != nil {
+			return nil, err
+		}
+		pushToken = token
+	}
+
 	task := &models.MonitorTask{
-		ID:               uuid.NewString(),
-		Name:             strings.TrimSpace(req.Name),
-		Type:             req.Type,
-		Target:           strings.TrimSpace(req.Target),
-		Description:      req.Description,
-		Enabled:          req.Enabled,
-		ShowTargetPublic: req.ShowTargetPublic,
-		Visibility:       visibility,
-		Interval:         interval,
-		AgentIds:         datatypes.JSONSlice[string](req.AgentIds),
-		Tags:             datatypes.JSONSlice[string](req.Tags),
-		HTTPConfig:       datatypes.NewJSONType(req.HTTPConfig),
-		TCPConfig:        datatypes.NewJSONType(req.TCPConfig),
-		ICMPConfig:       datatypes.NewJSONType(req.ICMPConfig),
-		CreatedAt:        0,
-		UpdatedAt:        0,
+		ID:                 uuid.NewString(),
+		Name:               strings.TrimSpace(req.Name),
+		Type:               req.Type,
+		Target:             strings.TrimSpace(req.Target),
+		Description:        req.Description,
+		Enabled:            req.Enabled,
+		ShowTargetPublic:   req.ShowTargetPublic,
+		Visibility:         visibility,
+		Interval:           interval,
+		Retries:            req.Retries,
+		AgentIds:           datatypes.JSONSlice[string](req.AgentIds),
+		Tags:               datatypes.JSONSlice[string](req.Tags),
+		Group:              strings.TrimSpace(req.Group),
+		DownQuorum:         req.DownQuorum,
+		SLATarget:          req.SLATarget,
+		HTTPConfig:         datatypes.NewJSONType(req.HTTPConfig),
+		TCPConfig:          datatypes.NewJSONType(req.TCPConfig),
+		UDPConfig:          datatypes.NewJSONType(req.UDPConfig),
+		ICMPConfig:         datatypes.NewJSONType(req.ICMPConfig),
+		Webhook:            datatypes.NewJSONType(req.Webhook),
+		TransactionConfig:  datatypes.NewJSONType(req.TransactionConfig),
+		MailConfig:         datatypes.NewJSONType(req.MailConfig),
+		Proxy:              datatypes.NewJSONType(req.Proxy),
+		PushToken:          pushToken,
+		PushGracePeriod:    req.PushGracePeriod,
+		MaintenanceWindows: req.MaintenanceWindows,
+		CreatedAt:          0,
+		UpdatedAt:          0,
 	}
 
 	if err := s.MonitorRepo.Create(ctx, task); err != nil {
@@ -157,6 +236,10 @@ func (s *MonitorService) CreateMonitor(ctx context.Context, req *MonitorTaskRequ
 }
 
 func (s *MonitorService) UpdateMonitor(ctx context.Context, id string, req *MonitorTaskRequest) (*models.MonitorTask, error) {
+	if err := validateMonitorRequest(req); err != nil {
+		return nil, err
+	}
+
 	task, err := s.MonitorRepo.FindById(ctx, id)
 	if err != nil {
 		return nil, err
@@ -174,6 +257,9 @@ func (s *MonitorService) UpdateMonitor(ctx context.Context, id string, req *Moni
 	task.ShowTargetPublic = req.ShowTargetPublic
 	task.Visibility = req.Visibility
 	task.Tags = req.Tags
+	task.Group = strings.TrimSpace(req.Group)
+	task.DownQuorum = req.DownQuorum
+	task.SLATarget = req.SLATarget
 
 	// 更新检测频率
 	interval := req.Interval
@@ -181,11 +267,37 @@ func (s *MonitorService) UpdateMonitor(ctx context.Context, id string, req *Moni
 		interval = 60 // 默认 60 秒
 	}
 	task.Interval = interval
+	task.Retries = req.Retries
 
 	task.AgentIds = req.AgentIds
+	if err := s.sealHTTPAuth(ctx, &req.HTTPConfig, task.HTTPConfig.Data().Auth); err != nil {
+		return nil, err
+	}
+	if err := s.sealTransactionAuth(ctx, &req.TransactionConfig, task.TransactionConfig.Data()); err != nil {
+		return nil, err
+	}
+	if err := s.sealMailAuth(ctx, &req.MailConfig, task.MailConfig.Data()); err != nil {
+		return nil, err
+	}
+	if err := s.sealProxy(ctx, &req.Proxy, task.Proxy.Data()); err != nil {
+		return nil, err
+	}
 	task.HTTPConfig = datatypes.NewJSONType(req.HTTPConfig)
 	task.TCPConfig = datatypes.NewJSONType(req.TCPConfig)
+	task.UDPConfig = datatypes.NewJSONType(req.UDPConfig)
 	task.ICMPConfig = datatypes.NewJSONType(req.ICMPConfig)
+	task.TransactionConfig = datatypes.NewJSONType(req.TransactionConfig)
+	task.MailConfig = datatypes.NewJSONType(req.MailConfig)
+	task.Proxy = datatypes.NewJSONType(req.Proxy)
+	task.PushGracePeriod = req.PushGracePeriod
+	task.MaintenanceWindows = req.MaintenanceWindows
+	if task.Type == "push" && task.PushToken == "" {
+		// 从其他类型改为被动心跳时生成令牌，已有的令牌保持不变，外部任务无需修改地址
+		if task.PushToken, err = newPushToken(); err != nil {
+			return nil, err
+		}
+	}
+	task.Webhook = datatypes.NewJSONType(req.Webhook)
 
 	if err := s.MonitorRepo.Save(ctx, &task); err != nil {
 		return nil, err
@@ -233,8 +345,20 @@ func (s *MonitorService) DeleteMonitor(ctx context.Context, id string) error {
 			return err
 		}
 
+		// 删除证书信息
+		if err := s.monitorCertRepo.DeleteByMonitorId(ctx, id); err != nil {
+			s.logger.Error("删除证书信息失败", zap.String("monitorId", id), zap.Error(err))
+			return err
+		}
+
+		// 删除路径追踪
+		if err := s.monitorTraceRepo.DeleteByMonitorId(ctx, id); err != nil {
+			s.logger.Error("删除路径追踪失败", zap.String("monitorId", id), zap.Error(err))
+			return err
+		}
+
 		// 删除监控指标数据
-		if err := s.metricRepo.DeleteMonitorMetrics(ctx, id); err != nil {
+		if err := s.metricStore.DeleteMonitorMetrics(ctx, id); err != nil {
 			s.logger.Error("删除监控指标数据失败", zap.String("monitorId", id), zap.Error(err))
 			return err
 		}
@@ -248,6 +372,8 @@ func (s *MonitorService) DeleteMonitor(ctx context.Context, id string) error {
 
 	// 清理缓存
 	s.clearCache(id)
+	s.forgetStatuses(id)
+	s.forgetCerts(id)
 
 	// 从调度器中移除
 	if s.scheduler != nil {
@@ -306,7 +432,7 @@ func (s *MonitorService) ListByAuth(ctx context.Context, isAuthenticated bool) (
 	items := make([]PublicMonitorOverview, 0, len(monitors))
 	for _, monitor := range monitors {
 		// 直接聚合预计算的统计数据
-		summary := aggregateMonitorStats(statsMap[monitor.ID])
+		summary := aggregateMonitorStats(statsMap[monitor.ID], monitor.DownQuorum)
 
 		// 构建监控概览对象
 		item := s.buildMonitorOverview(monitor, summary)
@@ -336,17 +462,26 @@ func (s *MonitorService) buildMonitorOverview(monitor models.MonitorTask, summar
 		Description:      monitor.Description,
 		Enabled:          monitor.Enabled,
 		Interval:         monitor.Interval,
+		Group:            monitor.Group,
 		AgentIds:         cloneAgentIDs(monitor.AgentIds),
 		AgentCount:       summary.AgentCount,
 		LastCheckStatus:  summary.LastCheckStatus,
 		LastCheckError:   summary.LastCheckError,
 		CurrentResponse:  summary.CurrentResponse,
 		AvgResponse24h:   summary.AvgResponse24h,
+		P50Response24h:   summary.P50Response24h,
+		P95Response24h:   summary.P95Response24h,
+		P99Response24h:   summary.P99Response24h,
+		P50Response30d:   summary.P50Response30d,
+		P95Response30d:   summary.P95Response30d,
+		P99Response30d:   summary.P99Response30d,
 		Uptime24h:        summary.Uptime24h,
 		Uptime7d:         summary.Uptime7d,
 		CertExpiryDate:   summary.CertExpiryDate,
 		CertExpiryDays:   summary.CertExpiryDays,
 		LastCheckTime:    summary.LastCheckTime,
+		DownQuorum:       monitor.DownQuorum,
+		Regions:          summary.Regions,
 	}
 }
 
@@ -356,14 +491,21 @@ type monitorOverviewSummary struct {
 	LastCheckError  string
 	CurrentResponse int64
 	AvgResponse24h  int64
+	P50Response24h  int64 // 各探针中最慢的分位数
+	P95Response24h  int64
+	P99Response24h  int64
+	P50Response30d  int64
+	P95Response30d  int64
+	P99Response30d  int64
 	Uptime24h       float64
 	Uptime7d        float64
 	CertExpiryDate  int64
 	CertExpiryDays  int
 	LastCheckTime   int64
+	Regions         []MonitorRegionStats
 }
 
-func aggregateMonitorStats(stats []models.MonitorStats) monitorOverviewSummary {
+func aggregateMonitorStats(stats []models.MonitorStats, downQuorum int) monitorOverviewSummary {
 	summary := monitorOverviewSummary{
 		LastCheckStatus: "unknown",
 	}
@@ -381,14 +523,19 @@ func aggregateMonitorStats(stats []models.MonitorStats) monitorOverviewSummary {
 	var certExpiryDate int64
 	var certExpiryDays int
 	hasCert := false
-	hasUp := false
-	hasDown := false
+	var upCount, downCount int
 
 	for _, stat := range stats {
 		totalCurrentResponse += stat.CurrentResponse
 		totalAvgResponse24h += stat.AvgResponse24h
 		totalUptime24h += stat.Uptime24h
 		totalUptime7d += stat.Uptime7d
+		summary.P50Response24h = max(summary.P50Response24h, stat.P50Response24h)
+		summary.P95Response24h = max(summary.P95Response24h, stat.P95Response24h)
+		summary.P99Response24h = max(summary.P99Response24h, stat.P99Response24h)
+		summary.P50Response30d = max(summary.P50Response30d, stat.P50Response30d)
+		summary.P95Response30d = max(summary.P95Response30d, stat.P95Response30d)
+		summary.P99Response30d = max(summary.P99Response30d, stat.P99Response30d)
 
 		// 记录最新的检测时间和对应的错误信息
 		if stat.LastCheckTime > lastCheckTime {
@@ -403,9 +550,9 @@ func aggregateMonitorStats(stats []models.MonitorStats) monitorOverviewSummary {
 
 		switch stat.LastCheckStatus {
 		case "up":
-			hasUp = true
+			upCount++
 		case "down":
-			hasDown = true
+			downCount++
 		}
 
 		if stat.CertExpiryDate > 0 {
@@ -428,14 +575,8 @@ func aggregateMonitorStats(stats []models.MonitorStats) monitorOverviewSummary {
 	summary.LastCheckTime = lastCheckTime
 	summary.LastCheckError = lastCheckError
 
-	switch {
-	case hasUp:
-		summary.LastCheckStatus = "up"
-	case hasDown:
-		summary.LastCheckStatus = "down"
-	default:
-		summary.LastCheckStatus = "unknown"
-	}
+	summary.LastCheckStatus = monitorConsensusStatus(upCount, downCount, downQuorum)
+	summary.Regions = aggregateMonitorRegions(stats)
 
 	if hasCert {
 		summary.CertExpiryDate = certExpiryDate
@@ -528,6 +669,11 @@ func (s *MonitorService) sendMonitorConfigToAgent(agentID string, payload protoc
 
 // SendMonitorTaskToAgents 向指定探针发送单个监控任务（公开方法）
 func (s *MonitorService) SendMonitorTaskToAgents(ctx context.Context, monitor models.MonitorTask) error {
+	// 被动心跳监控不下发给探针，由服务端检查是否按时收到心跳
+	if monitor.Type == "push" {
+		return s.evaluatePush(ctx, monitor)
+	}
+
 	// 实时获取所有在线探针，避免依赖数据库状态
 	onlineIDs := s.wsManager.GetAllClients()
 	if len(onlineIDs) == 0 {
@@ -552,26 +698,70 @@ func (s *MonitorService) SendMonitorTaskToAgents(ctx context.Context, monitor mo
 
 	// 构建监控项
 	item := protocol.MonitorItem{
-		ID:     monitor.ID,
-		Type:   monitor.Type,
-		Target: monitor.Target,
-	}
-
-	if monitor.Type == "http" || monitor.Type == "https" {
-		var httpConfig protocol.HTTPMonitorConfig
-		if err := monitor.HTTPConfig.Scan(&httpConfig); err == nil {
-			item.HTTPConfig = &httpConfig
+		ID:       monitor.ID,
+		Type:     monitor.Type,
+		Target:   monitor.Target,
+		Interval: monitor.Interval,
+		Timeout:  monitorTimeout(monitor),
+		Retries:  monitor.Retries,
+	}
+
+	switch monitor.Type {
+	case "http", "https":
+		httpConfig := monitor.HTTPConfig.Data()
+		if err := s.openHTTPAuth(ctx, &httpConfig); err != nil {
+			// 密钥变更后旧的密文无法解密，不携带认证信息继续检测，检测结果会体现认证失败
+			s.logger.Error("解密监控认证信息失败，请重新填写认证信息",
+				zap.String("monitorID", monitor.ID),
+				zap.Error(err))
+			httpConfig.Auth = nil
 		}
-	} else if monitor.Type == "tcp" {
-		var tcpConfig protocol.TCPMonitorConfig
-		if err := monitor.TCPConfig.Scan(&tcpConfig); err == nil {
-			item.TCPConfig = &tcpConfig
+		item.HTTPConfig = &httpConfig
+	case "tcp":
+		tcpConfig := monitor.TCPConfig.Data()
+		item.TCPConfig = &tcpConfig
+	case "udp":
+		udpConfig := monitor.UDPConfig.Data()
+		item.UDPConfig = &udpConfig
+	case "icmp", "ping":
+		icmpConfig := monitor.ICMPConfig.Data()
+		item.ICMPConfig = &icmpConfig
+	case "transaction":
+		transactionConfig := monitor.TransactionConfig.Data()
+		for i := range transactionConfig.Steps {
+			if err := s.openHTTPAuth(ctx, &transactionConfig.Steps[i].HTTPMonitorConfig); err != nil {
+				s.logger.Error("解密事务步骤认证信息失败，请重新填写认证信息",
+					zap.String("monitorID", monitor.ID),
+					zap.Int("step", i+1),
+					zap.Error(err))
+				transactionConfig.Steps[i].Auth = nil
+			}
+		}
+		item.TransactionConfig = &transactionConfig
+	case "smtp", "imap", "pop3":
+		mailConfig := monitor.MailConfig.Data()
+		password, err := s.secrets.Decrypt(ctx, mailConfig.Password)
+		if err != nil {
+			s.logger.Error("解密邮件服务密码失败，请重新填写密码",
+				zap.String("monitorID", monitor.ID),
+				zap.Error(err))
+			password = ""
 		}
-	} else if monitor.Type == "icmp" || monitor.Type == "ping" {
-		var icmpConfig protocol.ICMPMonitorConfig
-		if err := monitor.ICMPConfig.Scan(&icmpConfig); err == nil {
-			item.ICMPConfig = &icmpConfig
+		mailConfig.Password = password
+		item.MailConfig = &mailConfig
+	}
+
+	if proxy := monitor.Proxy.Data(); proxy.URL != "" && proxySupported(monitor.Type) {
+		password, err := s.secrets.Decrypt(ctx, proxy.Password)
+		if err != nil {
+			// 不携带密码继续检测，代理会拒绝认证并体现在检测结果中
+			s.logger.Error("解密代理密码失败，请重新填写密码",
+				zap.String("monitorID", monitor.ID),
+				zap.Error(err))
+			password = ""
 		}
+		proxy.Password = password
+		item.Proxy = &proxy
 	}
 
 	// 构建 payload
@@ -617,9 +807,12 @@ func (s *MonitorService) CalculateMonitorStats(ctx context.Context) error {
 	for _, monitor := range monitors {
 		// 使用统一的方法计算目标探针
 		targetAgents := s.resolveTargetAgents(monitor, agents)
+		if monitor.Type == "push" {
+			targetAgents = []models.Agent{pushAgent()}
+		}
 
 		for _, agent := range targetAgents {
-			stats, err := s.calculateStatsForAgentMonitor(ctx, agent.ID, monitor.ID, monitor.Type, monitor.Target, now)
+			stats, err := s.calculateStatsForAgentMonitor(ctx, agent.ID, monitor.ID, monitor.Type, monitor.Target, monitor.MaintenanceWindows, now)
 			if err != nil {
 				s.logger.Error("计算监控统计失败",
 					zap.String("agentID", agent.ID),
@@ -627,6 +820,7 @@ func (s *MonitorService) CalculateMonitorStats(ctx context.Context) error {
 					zap.Error(err))
 				continue
 			}
+			stats.Region = agent.Region
 
 			// 记录有效的监控任务ID
 			validStatsIDs[stats.ID] = true
@@ -649,8 +843,8 @@ func (s *MonitorService) CalculateMonitorStats(ctx context.Context) error {
 	return nil
 }
 
-// calculateStatsForAgentMonitor 计算单个探针单个监控任务的统计数据
-func (s *MonitorService) calculateStatsForAgentMonitor(ctx context.Context, agentID, monitorId, monitorType, target string, now time.Time) (*models.MonitorStats, error) {
+// calculateStatsForAgentMonitor 计算单个探针单个监控任务的统计数据，维护期间的离线结果不计入可用率
+func (s *MonitorService) calculateStatsForAgentMonitor(ctx context.Context, agentID, monitorId, monitorType, target string, windows []models.MonitorMaintenanceWindow, now time.Time) (*models.MonitorStats, error) {
 	stats := &models.MonitorStats{
 		ID:          toolkit.Sign("monitor_stats", agentID, monitorId, monitorType, target),
 		AgentID:     agentID,
@@ -659,39 +853,42 @@ func (s *MonitorService) calculateStatsForAgentMonitor(ctx context.Context, agen
 		Target:      target,
 	}
 
-	// 计算24小时数据
-	start24h := now.Add(-24 * time.Hour).UnixMilli()
+	// 一次查询30天的原始数据，24小时和7天的数据从中截取（数据按时间升序）
 	end := now.UnixMilli()
-	metrics24h, err := s.metricRepo.GetMonitorMetrics(ctx, agentID, monitorId, start24h, end)
-	if err != nil {
-		return nil, err
-	}
-
-	// 计算7天数据
+	start24h := now.Add(-24 * time.Hour).UnixMilli()
 	start7d := now.Add(-7 * 24 * time.Hour).UnixMilli()
-	metrics7d, err := s.metricRepo.GetMonitorMetrics(ctx, agentID, monitorId, start7d, end)
+	start30d := now.Add(-30 * 24 * time.Hour).UnixMilli()
+	metrics30d, err := s.metricStore.GetMonitorMetrics(ctx, agentID, monitorId, start30d, end)
 	if err != nil {
 		return nil, err
 	}
+	metrics7d := metrics30d[sort.Search(len(metrics30d), func(i int) bool { return metrics30d[i].Timestamp >= start7d }):]
+	metrics24h := metrics7d[sort.Search(len(metrics7d), func(i int) bool { return metrics7d[i].Timestamp >= start24h }):]
 
-	// 计算24小时统计
+	// 计算24小时统计，状态不变时多次检测合并为一条记录，按 Checks 加权还原
 	if len(metrics24h) > 0 {
 		var totalResponse int64
-		var successCount int64
+		var totalChecks, successCount int64
 		lastMetric := metrics24h[len(metrics24h)-1]
 
 		for _, metric := range metrics24h {
+			if !countsTowardUptime(metric, windows) {
+				continue
+			}
+			checks := metric.CheckCount()
+			totalChecks += checks
 			if metric.Status == "up" {
-				successCount++
-				totalResponse += metric.ResponseTime
+				successCount += checks
+				totalResponse += metric.ResponseTime * checks
 			}
 		}
 
-		stats.TotalChecks24h = int64(len(metrics24h))
+		stats.TotalChecks24h = totalChecks
 		stats.SuccessChecks24h = successCount
 		if successCount > 0 {
 			stats.AvgResponse24h = totalResponse / successCount
 		}
+		stats.P50Response24h, stats.P95Response24h, stats.P99Response24h = responsePercentiles(metricResponseSamples(metrics24h))
 		if stats.TotalChecks24h > 0 {
 			stats.Uptime24h = float64(successCount) / float64(stats.TotalChecks24h) * 100
 		}
@@ -711,20 +908,41 @@ func (s *MonitorService) calculateStatsForAgentMonitor(ctx context.Context, agen
 
 	// 计算7天统计
 	if len(metrics7d) > 0 {
-		var successCount int64
+		var totalChecks, successCount int64
 		for _, metric := range metrics7d {
+			if !countsTowardUptime(metric, windows) {
+				continue
+			}
+			checks := metric.CheckCount()
+			totalChecks += checks
 			if metric.Status == "up" {
-				successCount++
+				successCount += checks
 			}
 		}
 
-		stats.TotalChecks7d = int64(len(metrics7d))
+		stats.TotalChecks7d = totalChecks
 		stats.SuccessChecks7d = successCount
 		if stats.TotalChecks7d > 0 {
 			stats.Uptime7d = float64(successCount) / float64(stats.TotalChecks7d) * 100
 		}
 	}
 
+	// 计算30天响应时间分位数，原始数据保留时间不足30天时，更早的时间段使用预聚合数据补充
+	samples := metricResponseSamples(metrics30d)
+	rawStart := end
+	if len(metrics30d) > 0 {
+		rawStart = metrics30d[0].Timestamp
+	}
+	if aggregator, ok := s.metricStore.(repo.MetricAggregator); ok && rawStart-start30d > percentileBucketSeconds*1000 {
+		aggs, err := aggregator.GetMonitorMetricsAgg(ctx, monitorId, start30d, rawStart, percentileBucketSeconds)
+		if err != nil {
+			s.logger.Warn("查询监控预聚合数据失败", zap.String("monitorId", monitorId), zap.Error(err))
+		} else {
+			samples = append(samples, aggResponseSamples(aggs, agentID, rawStart, percentileBucketSeconds*1000)...)
+		}
+	}
+	stats.P50Response30d, stats.P95Response30d, stats.P99Response30d = responsePercentiles(samples)
+
 	return stats, nil
 }
 
@@ -743,7 +961,7 @@ func (s *MonitorService) GetMonitorStatsByID(ctx context.Context, monitorID stri
 	}
 
 	// 聚合统计数据
-	summary := aggregateMonitorStats(statsList)
+	summary := aggregateMonitorStats(statsList, monitor.DownQuorum)
 
 	// 构建监控概览对象
 	overview := s.buildMonitorOverview(monitor, summary)
@@ -813,6 +1031,12 @@ func (s *MonitorService) GetMonitorAgentStats(ctx context.Context, monitorID str
 // 根据前端时间范围选择，直接使用聚合表数据
 // 支持时间范围：15m, 30m, 1h, 3h, 6h, 12h, 1d, 3d, 7d
 func (s *MonitorService) GetMonitorHistory(ctx context.Context, monitorID, timeRange string) ([]repo.AggregatedMonitorMetric, error) {
+	ctx, span := tracing.Start(ctx, "MonitorService.GetMonitorHistory", tracing.WithAttributes(
+		tracing.String("monitor.id", monitorID),
+		tracing.String("monitor.time_range", timeRange),
+	))
+	defer span.End()
+
 	monitor, err := s.MonitorRepo.FindById(ctx, monitorID)
 	if err != nil {
 		return nil, err
@@ -864,8 +1088,11 @@ func (s *MonitorService) GetMonitorHistory(ctx context.Context, monitorID, timeR
 	bucketMs := int64(bucketSeconds * 1000)
 	start, end = alignTimeRangeToBucket(start, end, bucketMs)
 
-	// 直接使用聚合表查询
-	return s.metricRepo.GetMonitorMetricsAgg(ctx, monitor.ID, start, end, bucketSeconds)
+	// 优先使用聚合表查询，存储后端不支持预聚合时实时聚合
+	if aggregator, ok := s.metricStore.(repo.MetricAggregator); ok {
+		return aggregator.GetMonitorMetricsAgg(ctx, monitor.ID, start, end, bucketSeconds)
+	}
+	return s.metricStore.GetAggregatedMonitorMetrics(ctx, monitor.ID, start, end, bucketSeconds)
 }
 
 // GetMonitorByAuth 根据认证状态获取监控任务（已登录返回全部，未登录返回公开可见）
@@ -878,6 +1105,7 @@ func (s *MonitorService) GetMonitorByAuth(ctx context.Context, id string, isAuth
 		if !monitor.Enabled {
 			return nil, fmt.Errorf("monitor is disabled")
 		}
+		MaskMonitorSecrets(&monitor)
 		return &monitor, nil
 	}
 	monitor, err := s.MonitorRepo.FindPublicMonitorByID(ctx, id)
@@ -887,6 +1115,9 @@ func (s *MonitorService) GetMonitorByAuth(ctx context.Context, id string, isAuth
 	if !monitor.Enabled {
 		return nil, fmt.Errorf("monitor is disabled")
 	}
+	// 回调地址和签名密钥不对未登录用户展示
+	monitor.Webhook = datatypes.NewJSONType(models.MonitorWebhookConfig{})
+	MaskMonitorSecrets(monitor)
 	return monitor, nil
 }
 
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/repo"
+	"github.com/go-orz/orz"
+	"go.uber.org/zap"
+)
+
+const (
+	// slaQueryWindow 查询原始检测记录时每次查询的时间范围
+	slaQueryWindow = 24 * time.Hour
+	// slaBucketSeconds 原始数据已过期的时间段使用的预聚合粒度（1 小时）
+	slaBucketSeconds = 3600
+)
+
+// MonitorSLAReport 监控项某个自然月的 SLA 达成情况
+// 与在线率统计一致按检测次数计算：每个探针的可用率为成功检测占比，监控项的可用率为各探针的平均值，
+// 不可用时长按失败检测占比折算统计时长得出；维护期间的离线结果不计入
+type MonitorSLAReport struct {
+	MonitorID            string            `json:"monitorId"`
+	Month                string            `json:"month"`                // 统计月份，如 2025-03
+	Start                int64             `json:"start"`                // 统计开始时间（毫秒）
+	End                  int64             `json:"end"`                  // 统计结束时间（毫秒），当月统计到当前时间
+	Target               float64           `json:"target"`               // SLA 目标可用率(百分比)，0 表示未设置
+	Uptime               float64           `json:"uptime"`               // 可用率(百分比)
+	TotalChecks          int64             `json:"totalChecks"`          // 检测次数
+	SuccessChecks        int64             `json:"successChecks"`        // 成功次数
+	Compliant            bool              `json:"compliant"`            // 可用率是否达到 SLA 目标
+	DowntimeMinutes      float64           `json:"downtimeMinutes"`      // 不可用时长（分钟）
+	ErrorBudgetMinutes   float64           `json:"errorBudgetMinutes"`   // 整月允许的不可用时长（分钟）
+	ErrorBudgetRemaining float64           `json:"errorBudgetRemaining"` // 剩余可用的不可用时长（分钟），超出时为负数
+	ErrorBudgetPercent   float64           `json:"errorBudgetPercent"`   // 剩余错误预算占整月的比例(百分比)
+	Agents               []MonitorSLAAgent `json:"agents"`               // 各探针的可用率
+}
+
+// MonitorSLAAgent 单个探针在统计月份内的可用率
+type MonitorSLAAgent struct {
+	AgentID       string  `json:"agentId"`
+	AgentName     string  `json:"agentName"`
+	Uptime        float64 `json:"uptime"`
+	TotalChecks   int64   `json:"totalChecks"`
+	SuccessChecks int64   `json:"successChecks"`
+}
+
+// slaCounts 单个探针的检测次数
+type slaCounts struct {
+	total   int64
+	success int64
+}
+
+// parseSLAMonth 解析统计月份（YYYY-MM，服务端时区），为空时为当月；不能统计未来的月份
+func parseSLAMonth(month string, now time.Time) (time.Time, time.Time, error) {
+	var start time.Time
+	if month == "" {
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	} else {
+		parsed, err := time.ParseInLocation("2006-01", month, now.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, orz.NewError(400, "月份格式错误，示例: 2025-03")
+		}
+		start = parsed
+	}
+	if start.After(now) {
+		return time.Time{}, time.Time{}, orz.NewError(400, "不能统计未来的月份")
+	}
+	return start, start.AddDate(0, 1, 0), nil
+}
+
+// slaRawCounts 按探针累计原始检测记录的检测次数，合并保存的记录按 Checks 还原
+func slaRawCounts(counts map[string]*slaCounts, metrics []models.MonitorMetric, windows []models.MonitorMaintenanceWindow) {
+	for _, metric := range metrics {
+		if !countsTowardUptime(metric, windows) {
+			continue
+		}
+		c := counts[metric.AgentId]
+		if c == nil {
+			c = &slaCounts{}
+			counts[metric.AgentId] = c
+		}
+		checks := metric.CheckCount()
+		c.total += checks
+		if metric.Status == "up" {
+			c.success += checks
+		}
+	}
+}
+
+// slaAggCounts 按探针累计原始数据过期时间段（before 之前）的预聚合检测次数
+// 预聚合数据无法区分每次检测的时间，时间桶开始时处于维护期间的，只计入成功的检测
+func slaAggCounts(counts map[string]*slaCounts, aggs []repo.AggregatedMonitorMetric, windows []models.MonitorMaintenanceWindow, before, bucketMs int64) {
+	for _, agg := range aggs {
+		if agg.Timestamp+bucketMs > before || agg.TotalCount == 0 {
+			continue
+		}
+		c := counts[agg.AgentID]
+		if c == nil {
+			c = &slaCounts{}
+			counts[agg.AgentID] = c
+		}
+		c.success += agg.SuccessCount
+		if inMaintenance(windows, time.UnixMilli(agg.Timestamp)) {
+			c.total += agg.SuccessCount
+		} else {
+			c.total += agg.TotalCount
+		}
+	}
+}
+
+// buildSLAReport 根据各探针的检测次数计算可用率和错误预算
+// monthMinutes 为整月时长，elapsedMinutes 为已统计的时长（当月为月初到当前时间）
+func buildSLAReport(report *MonitorSLAReport, counts map[string]*slaCounts, agentNames map[string]string, monthMinutes, elapsedMinutes float64) {
+	report.Agents = make([]MonitorSLAAgent, 0, len(counts))
+	var uptimeSum float64
+	for agentID, c := range counts {
+		if c.total == 0 {
+			continue
+		}
+		uptime := float64(c.success) / float64(c.total) * 100
+		uptimeSum += uptime
+		report.TotalChecks += c.total
+		report.SuccessChecks += c.success
+		report.Agents = append(report.Agents, MonitorSLAAgent{
+			AgentID:       agentID,
+			AgentName:     agentNames[agentID],
+			Uptime:        uptime,
+			TotalChecks:   c.total,
+			SuccessChecks: c.success,
+		})
+	}
+	sort.Slice(report.Agents, func(i, j int) bool {
+		return report.Agents[i].AgentID < report.Agents[j].AgentID
+	})
+
+	// 没有检测数据时视为完全可用
+	report.Uptime = 100
+	if len(report.Agents) > 0 {
+		report.Uptime = uptimeSum / float64(len(report.Agents))
+	}
+	report.DowntimeMinutes = elapsedMinutes * (100 - report.Uptime) / 100
+	report.Compliant = report.Target <= 0 || report.Uptime >= report.Target
+	if report.Target <= 0 {
+		return
+	}
+	report.ErrorBudgetMinutes = monthMinutes * (100 - report.Target) / 100
+	report.ErrorBudgetRemaining = report.ErrorBudgetMinutes - report.DowntimeMinutes
+	if report.ErrorBudgetMinutes > 0 {
+		report.ErrorBudgetPercent = math.Max(report.ErrorBudgetRemaining/report.ErrorBudgetMinutes*100, -100)
+	} else if report.DowntimeMinutes > 0 {
+		// 目标为 100% 时没有错误预算，出现不可用即视为耗尽
+		report.ErrorBudgetPercent = -100
+	}
+}
+
+// GetMonitorSLAReport 计算监控项某个自然月（YYYY-MM，为空时为当月）的 SLA 达成情况
+// 原始检测记录保留时间不足时，更早的时间段使用预聚合数据补充
+func (s *MonitorService) GetMonitorSLAReport(ctx context.Context, monitorID, month string) (*MonitorSLAReport, error) {
+	monitor, err := s.MonitorRepo.FindById(ctx, monitorID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	monthStart, monthEnd, err := parseSLAMonth(month, now)
+	if err != nil {
+		return nil, err
+	}
+	end := monthEnd
+	if now.Before(end) {
+		end = now
+	}
+	startMs, endMs := monthStart.UnixMilli(), end.UnixMilli()
+
+	counts := make(map[string]*slaCounts)
+	rawStart := endMs
+	windowMs := slaQueryWindow.Milliseconds()
+	for windowStart := startMs; windowStart < endMs; windowStart += windowMs {
+		metrics, err := s.metricStore.ListMonitorMetrics(ctx, repo.MonitorMetricFilter{
+			MonitorID: monitor.ID,
+			Start:     windowStart,
+			End:       min(windowStart+windowMs, endMs) - 1,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(metrics) > 0 && metrics[0].Timestamp < rawStart {
+			rawStart = metrics[0].Timestamp
+		}
+		slaRawCounts(counts, metrics, monitor.MaintenanceWindows)
+	}
+	if aggregator, ok := s.metricStore.(repo.MetricAggregator); ok && rawStart-startMs > slaBucketSeconds*1000 {
+		aggs, err := aggregator.GetMonitorMetricsAgg(ctx, monitor.ID, startMs, rawStart, slaBucketSeconds)
+		if err != nil {
+			s.logger.Warn("查询监控预聚合数据失败", zap.String("monitorId", monitor.ID), zap.Error(err))
+		} else {
+			slaAggCounts(counts, aggs, monitor.MaintenanceWindows, rawStart, slaBucketSeconds*1000)
+		}
+	}
+
+	agents, err := s.agentRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	agentNames := map[string]string{PushAgentID: pushAgent().Name}
+	for _, agent := range agents {
+		agentNames[agent.ID] = agent.Name
+	}
+
+	report := &MonitorSLAReport{
+		MonitorID: monitor.ID,
+		Month:     monthStart.Format("2006-01"),
+		Start:     startMs,
+		End:       endMs,
+		Target:    monitor.SLATarget,
+	}
+	buildSLAReport(report, counts, agentNames, monthEnd.Sub(monthStart).Minutes(), end.Sub(monthStart).Minutes())
+	return report, nil
+}
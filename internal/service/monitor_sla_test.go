@@ -0,0 +1,99 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/repo"
+)
+
+func TestParseSLAMonth(t *testing.T) {
+	now := time.Date(2025, 3, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		month     string
+		wantStart time.Time
+		wantErr   bool
+	}{
+		{month: "", wantStart: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{month: "2025-02", wantStart: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{month: "2025-04", wantErr: true},
+		{month: "2025/02", wantErr: true},
+	}
+	for _, tt := range tests {
+		start, end, err := parseSLAMonth(tt.month, now)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("parseSLAMonth(%q) err = %v，wantErr = %v", tt.month, err, tt.wantErr)
+		}
+		if err == nil && (!start.Equal(tt.wantStart) || !end.Equal(tt.wantStart.AddDate(0, 1, 0))) {
+			t.Fatalf("parseSLAMonth(%q) = %v ~ %v", tt.month, start, end)
+		}
+	}
+}
+
+func TestSLACounts(t *testing.T) {
+	hour := int64(time.Hour / time.Millisecond)
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	windows := []models.MonitorMaintenanceWindow{{Type: models.MaintenanceOnce, StartAt: start + 5*hour, EndAt: start + 6*hour}}
+
+	counts := make(map[string]*slaCounts)
+	// 原始数据从第 10 小时开始，之前的时间段使用预聚合数据
+	rawStart := start + 10*hour
+	slaAggCounts(counts, []repo.AggregatedMonitorMetric{
+		{AgentID: "a1", Timestamp: start, SuccessCount: 50, TotalCount: 60},
+		{AgentID: "a1", Timestamp: start + 5*hour, SuccessCount: 10, TotalCount: 60},  // 维护期间只计入成功的检测
+		{AgentID: "a1", Timestamp: start + 10*hour, SuccessCount: 60, TotalCount: 60}, // 与原始数据重叠
+	}, windows, rawStart, hour)
+	slaRawCounts(counts, []models.MonitorMetric{
+		{AgentId: "a1", Status: "up", Checks: 55, Timestamp: rawStart},
+		{AgentId: "a1", Status: "down", Checks: 5, Timestamp: rawStart + hour},
+		{AgentId: "a2", Status: "up", Timestamp: rawStart},
+	}, windows)
+
+	if c := counts["a1"]; c.total != 60+10+60 || c.success != 50+10+55 {
+		t.Fatalf("a1 的检测次数错误: %+v", *c)
+	}
+	if c := counts["a2"]; c.total != 1 || c.success != 1 {
+		t.Fatalf("a2 的检测次数错误: %+v", *c)
+	}
+}
+
+func TestBuildSLAReport(t *testing.T) {
+	const monthMinutes = 30 * 24 * 60
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-6 }
+
+	t.Run("达到目标", func(t *testing.T) {
+		report := &MonitorSLAReport{Target: 99.9}
+		counts := map[string]*slaCounts{"a1": {total: 10000, success: 9995}, "a2": {total: 10000, success: 10000}}
+		buildSLAReport(report, counts, map[string]string{"a1": "东京"}, monthMinutes, monthMinutes)
+
+		// 两个探针平均可用率 99.975%，整月允许 43.2 分钟不可用，已用 10.8 分钟
+		if !near(report.Uptime, 99.975) || !report.Compliant {
+			t.Fatalf("可用率 %v，是否达标 %v", report.Uptime, report.Compliant)
+		}
+		if !near(report.DowntimeMinutes, 10.8) || !near(report.ErrorBudgetMinutes, 43.2) || !near(report.ErrorBudgetRemaining, 32.4) || !near(report.ErrorBudgetPercent, 75) {
+			t.Fatalf("错误预算计算错误: %+v", report)
+		}
+		if len(report.Agents) != 2 || report.Agents[0].AgentName != "东京" || report.TotalChecks != 20000 {
+			t.Fatalf("探针明细错误: %+v", report.Agents)
+		}
+	})
+
+	t.Run("超出错误预算", func(t *testing.T) {
+		report := &MonitorSLAReport{Target: 99.9}
+		// 月中统计：已过去一半时间，可用率 99.7%
+		buildSLAReport(report, map[string]*slaCounts{"a1": {total: 1000, success: 997}}, nil, monthMinutes, monthMinutes/2)
+		if report.Compliant || !near(report.DowntimeMinutes, 64.8) || !near(report.ErrorBudgetRemaining, -21.6) || !near(report.ErrorBudgetPercent, -50) {
+			t.Fatalf("超出错误预算时计算错误: %+v", report)
+		}
+	})
+
+	t.Run("未设置目标且没有数据", func(t *testing.T) {
+		report := &MonitorSLAReport{}
+		buildSLAReport(report, map[string]*slaCounts{}, nil, monthMinutes, monthMinutes)
+		if report.Uptime != 100 || !report.Compliant || report.ErrorBudgetMinutes != 0 || len(report.Agents) != 0 {
+			t.Fatalf("没有检测数据时应视为完全可用: %+v", report)
+		}
+	})
+}
@@ -0,0 +1,26 @@
+package service
+
+import "github.com/dushixiang/pika/internal/models"
+
+// maxMonitorRetries 检测失败后最多重试的次数
+const maxMonitorRetries = 5
+
+// monitorTimeout 监控项单次检测的超时时间（秒），下发给探针统一处理，0 表示使用探针默认值
+// 超时时间保存在各类型的配置中，事务监控为整个事务的超时时间
+func monitorTimeout(monitor models.MonitorTask) int {
+	switch monitor.Type {
+	case "http", "https":
+		return monitor.HTTPConfig.Data().Timeout
+	case "tcp":
+		return monitor.TCPConfig.Data().Timeout
+	case "udp":
+		return monitor.UDPConfig.Data().Timeout
+	case "icmp", "ping":
+		return monitor.ICMPConfig.Data().Timeout
+	case "transaction":
+		return monitor.TransactionConfig.Data().Timeout
+	case "smtp", "imap", "pop3":
+		return monitor.MailConfig.Data().Timeout
+	}
+	return 0
+}
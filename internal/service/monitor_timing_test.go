@@ -0,0 +1,50 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/protocol"
+	"gorm.io/datatypes"
+)
+
+func TestMonitorTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		monitor models.MonitorTask
+		want    int
+	}{
+		{
+			name:    "HTTP",
+			monitor: models.MonitorTask{Type: "https", HTTPConfig: datatypes.NewJSONType(protocol.HTTPMonitorConfig{Timeout: 30})},
+			want:    30,
+		},
+		{
+			name:    "事务取整个事务的超时时间",
+			monitor: models.MonitorTask{Type: "transaction", TransactionConfig: datatypes.NewJSONType(protocol.TransactionMonitorConfig{Timeout: 90})},
+			want:    90,
+		},
+		{
+			name:    "邮件",
+			monitor: models.MonitorTask{Type: "smtp", MailConfig: datatypes.NewJSONType(protocol.MailMonitorConfig{Timeout: 15})},
+			want:    15,
+		},
+		{
+			name:    "未设置时使用探针默认值",
+			monitor: models.MonitorTask{Type: "tcp"},
+			want:    0,
+		},
+		{
+			name:    "被动心跳没有超时时间",
+			monitor: models.MonitorTask{Type: "push", HTTPConfig: datatypes.NewJSONType(protocol.HTTPMonitorConfig{Timeout: 30})},
+			want:    0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := monitorTimeout(tt.monitor); got != tt.want {
+				t.Errorf("超时时间 = %d, 期望 %d", got, tt.want)
+			}
+		})
+	}
+}
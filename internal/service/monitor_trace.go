@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/protocol"
+	"go.uber.org/zap"
+)
+
+const (
+	// monitorTraceKeep 每个探针在每个监控项上保留的路径追踪数量
+	monitorTraceKeep = 20
+	// monitorTraceListLimit 查询监控项路径追踪时返回的最大数量
+	monitorTraceListLimit = 50
+)
+
+// HandleMonitorTrace 保存探针在监控项变为离线时上报的路径追踪，已删除的监控项直接忽略
+func (s *MonitorService) HandleMonitorTrace(ctx context.Context, agentID string, data *protocol.MonitorTraceData) error {
+	if data.MonitorID == "" {
+		return nil
+	}
+	exists, err := s.MonitorRepo.ExistsById(ctx, data.MonitorID)
+	if err != nil || !exists {
+		return err
+	}
+
+	trace := &models.MonitorTrace{
+		AgentID:    agentID,
+		MonitorId:  data.MonitorID,
+		Host:       data.Host,
+		Tool:       data.Tool,
+		CheckedAt:  data.CheckedAt,
+		CheckError: data.CheckError,
+		Hops:       data.Hops,
+		Error:      data.Error,
+	}
+	if err := s.monitorTraceRepo.Create(ctx, trace); err != nil {
+		return err
+	}
+	if err := s.monitorTraceRepo.TrimByMonitorAgent(ctx, data.MonitorID, agentID, monitorTraceKeep); err != nil {
+		s.logger.Warn("清理路径追踪失败", zap.String("monitorId", data.MonitorID), zap.Error(err))
+	}
+
+	s.logger.Info("收到离线路径追踪",
+		zap.String("monitorId", data.MonitorID),
+		zap.String("agentId", agentID),
+		zap.String("tool", data.Tool),
+		zap.Int("hops", len(data.Hops)),
+		zap.String("error", data.Error))
+	return nil
+}
+
+// GetMonitorTraces 获取监控项最近的离线路径追踪
+// 路径中包含目标地址，未登录时只有公开展示目标的监控项才返回
+func (s *MonitorService) GetMonitorTraces(ctx context.Context, monitorID string, isAuthenticated bool) ([]models.MonitorTrace, error) {
+	monitor, err := s.MonitorRepo.FindById(ctx, monitorID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthenticated && !monitor.ShowTargetPublic {
+		return []models.MonitorTrace{}, nil
+	}
+
+	traces, err := s.monitorTraceRepo.FindRecentByMonitorId(ctx, monitor.ID, monitorTraceListLimit)
+	if err != nil {
+		return nil, err
+	}
+	agents, err := s.agentRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	agentNames := make(map[string]string, len(agents))
+	for _, agent := range agents {
+		agentNames[agent.ID] = agent.Name
+	}
+	for i := range traces {
+		traces[i].AgentName = agentNames[traces[i].AgentID]
+	}
+	return traces, nil
+}
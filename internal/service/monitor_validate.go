@@ -0,0 +1,243 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/pkg/jsonpath"
+	"github.com/dushixiang/pika/pkg/secretbox"
+	"github.com/go-orz/orz"
+)
+
+// validateMonitorRequest 校验监控项配置，探针端无法执行的配置在保存时拒绝
+func validateMonitorRequest(req *MonitorTaskRequest) error {
+	if err := validateMonitorWebhook(req.Webhook); err != nil {
+		return err
+	}
+	if err := validateMaintenanceWindows(req.MaintenanceWindows); err != nil {
+		return err
+	}
+	if utf8.RuneCountInString(strings.TrimSpace(req.Group)) > maxMonitorGroupLength {
+		return orz.NewError(400, fmt.Sprintf("监控分组不能超过 %d 个字符", maxMonitorGroupLength))
+	}
+	if req.DownQuorum < 0 || req.DownQuorum > maxDownQuorum {
+		return orz.NewError(400, fmt.Sprintf("离线判定的探针数量需在 0 到 %d 之间", maxDownQuorum))
+	}
+	if req.SLATarget < 0 || req.SLATarget > 100 {
+		return orz.NewError(400, "SLA 目标需在 0 到 100 之间")
+	}
+	if req.Retries < 0 || req.Retries > maxMonitorRetries {
+		return orz.NewError(400, fmt.Sprintf("失败重试次数需在 0 到 %d 之间", maxMonitorRetries))
+	}
+	if err := validateProxy(req.Type, &req.Proxy); err != nil {
+		return err
+	}
+	switch req.Type {
+	case "http", "https":
+		return validateHTTPConfig(&req.HTTPConfig)
+	case "transaction":
+		return validateTransaction(&req.TransactionConfig)
+	case "push":
+		if req.PushGracePeriod < 0 || req.PushGracePeriod > maxPushGracePeriod {
+			return orz.NewError(400, fmt.Sprintf("宽限时间需在 0 到 %d 秒之间", maxPushGracePeriod))
+		}
+	case "smtp", "imap", "pop3":
+		return validateMailConfig(&req.MailConfig)
+	case "tcp":
+		return validateExpect(req.TCPConfig.ExpectMode, req.TCPConfig.Expect)
+	case "udp":
+		return validateExpect(req.UDPConfig.ExpectMode, req.UDPConfig.Expect)
+	}
+	return nil
+}
+
+// validateHTTPConfig 校验 HTTP 请求配置，HTTP 监控和事务的每个步骤共用
+func validateHTTPConfig(cfg *protocol.HTTPMonitorConfig) error {
+	switch strings.ToUpper(cfg.Method) {
+	case "", http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodHead, http.MethodOptions:
+	default:
+		return orz.NewError(400, "不支持的 HTTP 方法: "+cfg.Method)
+	}
+	if cfg.Body != "" && strings.EqualFold(cfg.Method, http.MethodHead) {
+		return orz.NewError(400, "HEAD 请求不能携带请求体")
+	}
+	for _, pattern := range cfg.AcceptedStatusCodes {
+		if _, _, err := protocol.ParseStatusCodeRange(pattern); err != nil {
+			return orz.NewError(400, "无效的可接受状态码: "+pattern)
+		}
+	}
+	if cfg.MaxRedirects < 0 {
+		return orz.NewError(400, "最大重定向次数不能为负数")
+	}
+	if err := validateAssertions(cfg.Assertions); err != nil {
+		return err
+	}
+	if auth := cfg.Auth; auth != nil {
+		// 密文前缀保留给服务端加密后的值，避免明文被当作密文保存
+		if secretbox.IsSealed(auth.Password) || secretbox.IsSealed(auth.Token) {
+			return orz.NewError(400, "认证密码和令牌不能以 "+secretbox.Prefix+" 开头")
+		}
+		switch auth.Type {
+		case "", protocol.HTTPAuthBearer:
+		case protocol.HTTPAuthBasic:
+			if auth.Username == "" {
+				return orz.NewError(400, "Basic 认证的用户名不能为空")
+			}
+		default:
+			return orz.NewError(400, "不支持的认证方式: "+auth.Type)
+		}
+	}
+	return nil
+}
+
+// maxTransactionSteps 单个事务最多包含的步骤数
+const maxTransactionSteps = 10
+
+// transactionVarName 事务变量名
+var transactionVarName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validateTransaction 校验事务监控的步骤和变量提取配置
+func validateTransaction(cfg *protocol.TransactionMonitorConfig) error {
+	if len(cfg.Steps) == 0 {
+		return orz.NewError(400, "事务监控至少需要一个步骤")
+	}
+	if len(cfg.Steps) > maxTransactionSteps {
+		return orz.NewError(400, fmt.Sprintf("事务监控不能超过 %d 个步骤", maxTransactionSteps))
+	}
+	for i := range cfg.Steps {
+		step := &cfg.Steps[i]
+		url := strings.ToLower(strings.TrimSpace(step.URL))
+		if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+			return orz.NewError(400, fmt.Sprintf("步骤 %d 的地址必须以 http:// 或 https:// 开头", i+1))
+		}
+		if err := validateHTTPConfig(&step.HTTPMonitorConfig); err != nil {
+			return orz.NewError(400, fmt.Sprintf("步骤 %d: %s", i+1, err.Error()))
+		}
+		for _, extract := range step.Extract {
+			if !transactionVarName.MatchString(extract.Name) {
+				return orz.NewError(400, fmt.Sprintf("步骤 %d 的变量名无效: %s", i+1, extract.Name))
+			}
+			var err error
+			switch extract.Source {
+			case protocol.ExtractSourceJSONPath:
+				_, err = jsonpath.Compile(extract.Expression)
+			case protocol.ExtractSourceRegex:
+				_, err = regexp.Compile(extract.Expression)
+			case protocol.ExtractSourceHeader:
+				if strings.TrimSpace(extract.Expression) == "" {
+					err = fmt.Errorf("响应头名称不能为空")
+				}
+			default:
+				err = fmt.Errorf("不支持的提取来源 %s", extract.Source)
+			}
+			if err != nil {
+				return orz.NewError(400, fmt.Sprintf("步骤 %d 提取变量 %s 失败: %v", i+1, extract.Name, err))
+			}
+		}
+	}
+	return nil
+}
+
+// maxHTTPAssertions 单个监控项最多配置的内容断言数
+const maxHTTPAssertions = 20
+
+// validateAssertions 校验 HTTP 响应内容断言
+func validateAssertions(assertions []protocol.HTTPAssertion) error {
+	if len(assertions) > maxHTTPAssertions {
+		return orz.NewError(400, fmt.Sprintf("内容断言不能超过 %d 条", maxHTTPAssertions))
+	}
+	for _, a := range assertions {
+		switch a.Type {
+		case protocol.AssertionContains, protocol.AssertionNotContains:
+			if a.Value == "" {
+				return orz.NewError(400, "内容断言的匹配内容不能为空")
+			}
+		case protocol.AssertionRegex, protocol.AssertionNotRegex:
+			if _, err := regexp.Compile(a.Value); err != nil {
+				return orz.NewError(400, "内容断言的正则表达式无效: "+err.Error())
+			}
+		case protocol.AssertionJSONPath:
+			if _, err := jsonpath.Compile(a.Path); err != nil {
+				return orz.NewError(400, "无效的 JSONPath: "+err.Error())
+			}
+			switch a.Operator {
+			case "", protocol.AssertionOpEquals, protocol.AssertionOpNotEquals, protocol.AssertionOpContains,
+				protocol.AssertionOpExists, protocol.AssertionOpNotExists:
+			case protocol.AssertionOpRegex:
+				if _, err := regexp.Compile(a.Value); err != nil {
+					return orz.NewError(400, "JSONPath 断言的正则表达式无效: "+err.Error())
+				}
+			case protocol.AssertionOpGreater, protocol.AssertionOpLess:
+				if _, err := strconv.ParseFloat(a.Value, 64); err != nil {
+					return orz.NewError(400, "JSONPath 断言的比较值必须是数字: "+a.Value)
+				}
+			default:
+				return orz.NewError(400, "不支持的 JSONPath 比较方式: "+a.Operator)
+			}
+		default:
+			return orz.NewError(400, "不支持的内容断言类型: "+a.Type)
+		}
+	}
+	return nil
+}
+
+// validateExpect 校验响应内容匹配方式
+func validateExpect(mode, expect string) error {
+	switch mode {
+	case "", protocol.ExpectModeContains, protocol.ExpectModePrefix:
+	case protocol.ExpectModeRegex:
+		if _, err := regexp.Compile(expect); err != nil {
+			return orz.NewError(400, "期望响应的正则表达式无效: "+err.Error())
+		}
+	default:
+		return orz.NewError(400, "不支持的响应匹配方式: "+mode)
+	}
+	return nil
+}
+
+// proxySupported 判断监控类型是否支持通过代理检测
+func proxySupported(monitorType string) bool {
+	switch monitorType {
+	case "http", "https", "transaction", "tcp":
+		return true
+	}
+	return false
+}
+
+// validateProxy 校验出口代理配置，未填写代理地址表示直接连接
+func validateProxy(monitorType string, cfg *protocol.ProxyConfig) error {
+	if strings.TrimSpace(cfg.URL) == "" {
+		return nil
+	}
+	if !proxySupported(monitorType) {
+		return orz.NewError(400, "只有 HTTP、事务和 TCP 监控支持代理")
+	}
+	if _, err := cfg.ProxyURL(); err != nil {
+		return orz.NewError(400, "代理地址无效，支持 http://、https://、socks5:// 且认证信息需单独填写")
+	}
+	if secretbox.IsSealed(cfg.Password) {
+		return orz.NewError(400, "代理密码不能以 "+secretbox.Prefix+" 开头")
+	}
+	return nil
+}
+
+// validateMailConfig 校验邮件服务监控配置，不允许在明文连接上发送账号密码
+func validateMailConfig(cfg *protocol.MailMonitorConfig) error {
+	switch cfg.TLSMode {
+	case "", protocol.MailTLSStartTLS, protocol.MailTLSImplicit, protocol.MailTLSNone:
+	default:
+		return orz.NewError(400, "不支持的加密方式: "+cfg.TLSMode)
+	}
+	if cfg.Username != "" && cfg.TLSMode == protocol.MailTLSNone {
+		return orz.NewError(400, "不加密的连接不能进行登录认证")
+	}
+	if secretbox.IsSealed(cfg.Password) {
+		return orz.NewError(400, "密码不能以 "+secretbox.Prefix+" 开头")
+	}
+	return nil
+}
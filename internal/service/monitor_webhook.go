@@ -0,0 +1,209 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/pkg/tracing"
+	"github.com/go-orz/orz"
+	"go.uber.org/zap"
+)
+
+const (
+	// MonitorWebhookEvent 监控状态变化回调的事件名
+	MonitorWebhookEvent = "monitor.status_changed"
+	// monitorWebhookTimeout 回调请求超时时间
+	monitorWebhookTimeout = 10 * time.Second
+	// monitorWebhookSignatureHeader 签名请求头
+	monitorWebhookSignatureHeader = "X-Pika-Signature"
+)
+
+// MonitorWebhookPayload 监控状态变化回调的请求体
+type MonitorWebhookPayload struct {
+	Event          string               `json:"event"`          // 事件名，固定为 monitor.status_changed
+	MonitorID      string               `json:"monitorId"`      // 监控项ID
+	MonitorName    string               `json:"monitorName"`    // 监控项名称
+	AgentID        string               `json:"agentId"`        // 探针ID
+	AgentName      string               `json:"agentName"`      // 探针名称
+	PreviousStatus string               `json:"previousStatus"` // 变化前的状态
+	Status         string               `json:"status"`         // 当前状态
+	Timestamp      int64                `json:"timestamp"`      // 回调时间(毫秒时间戳)
+	Data           protocol.MonitorData `json:"data"`           // 本次检测结果
+}
+
+// validateMonitorWebhook 校验监控项回调配置
+func validateMonitorWebhook(cfg models.MonitorWebhookConfig) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	u, err := url.Parse(strings.TrimSpace(cfg.URL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return orz.NewError(400, "回调地址必须是有效的 http 或 https 地址")
+	}
+	switch strings.ToUpper(cfg.Method) {
+	case "", http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return orz.NewError(400, "回调请求方法只支持 POST、PUT、PATCH")
+	}
+	return nil
+}
+
+// HandleMonitorResults 处理探针上报的监控结果，保存证书详情，状态发生变化的监控项异步调用其回调地址
+func (s *MonitorService) HandleMonitorResults(ctx context.Context, agentID string, results []protocol.MonitorData) {
+	ctx, span := tracing.Start(ctx, "MonitorService.HandleMonitorResults", tracing.WithAttributes(
+		tracing.String("agent.id", agentID),
+		tracing.Int("monitor.result_count", len(results)),
+	))
+	defer span.End()
+
+	for _, result := range results {
+		if result.ID == "" || result.Status == "" {
+			continue
+		}
+		s.recordCert(ctx, agentID, result)
+
+		previous, changed := s.updateStatus(ctx, agentID, result.ID, result.Status)
+		if !changed {
+			continue
+		}
+
+		s.logger.Info("监控状态变化",
+			zap.String("monitorId", result.ID),
+			zap.String("agentId", agentID),
+			zap.String("previousStatus", previous),
+			zap.String("status", result.Status))
+
+		go s.fireWebhook(agentID, previous, result)
+	}
+}
+
+// updateStatus 记录最新状态，返回之前的状态以及是否发生变化
+// 服务启动后首次收到某个探针的结果时，以统计表中的最后检测状态作为之前的状态，避免重启后丢失状态变化
+func (s *MonitorService) updateStatus(ctx context.Context, agentID, monitorID, status string) (string, bool) {
+	s.statusMu.Lock()
+	statuses, ok := s.lastStatuses[monitorID]
+	s.statusMu.Unlock()
+
+	if !ok {
+		statuses = make(map[string]string)
+		stats, err := s.monitorStatsRepo.FindByMonitorId(ctx, monitorID)
+		if err != nil {
+			s.logger.Warn("读取监控统计失败", zap.String("monitorId", monitorID), zap.Error(err))
+		}
+		for _, stat := range stats {
+			if stat.LastCheckStatus != "" {
+				statuses[stat.AgentID] = stat.LastCheckStatus
+			}
+		}
+	}
+
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	if existing, ok := s.lastStatuses[monitorID]; ok {
+		statuses = existing
+	} else {
+		s.lastStatuses[monitorID] = statuses
+	}
+
+	previous, seen := statuses[agentID]
+	statuses[agentID] = status
+	return previous, seen && previous != status
+}
+
+// forgetStatuses 清除监控项的状态记录
+func (s *MonitorService) forgetStatuses(monitorID string) {
+	s.statusMu.Lock()
+	delete(s.lastStatuses, monitorID)
+	s.statusMu.Unlock()
+}
+
+// fireWebhook 调用监控项的状态变化回调
+func (s *MonitorService) fireWebhook(agentID, previous string, result protocol.MonitorData) {
+	ctx, cancel := context.WithTimeout(context.Background(), monitorWebhookTimeout)
+	defer cancel()
+
+	monitor, err := s.MonitorRepo.FindById(ctx, result.ID)
+	if err != nil {
+		return
+	}
+	cfg := monitor.Webhook.Data()
+	if !cfg.Enabled || cfg.URL == "" {
+		return
+	}
+
+	payload := MonitorWebhookPayload{
+		Event:          MonitorWebhookEvent,
+		MonitorID:      monitor.ID,
+		MonitorName:    monitor.Name,
+		AgentID:        agentID,
+		PreviousStatus: previous,
+		Status:         result.Status,
+		Timestamp:      time.Now().UnixMilli(),
+		Data:           result,
+	}
+	if agent, err := s.agentRepo.FindById(ctx, agentID); err == nil {
+		payload.AgentName = agent.Name
+	}
+
+	if err := sendMonitorWebhook(ctx, cfg, payload); err != nil {
+		s.logger.Warn("监控状态回调失败",
+			zap.String("monitorId", monitor.ID),
+			zap.String("agentId", agentID),
+			zap.Error(err))
+		return
+	}
+	s.logger.Debug("监控状态回调成功",
+		zap.String("monitorId", monitor.ID),
+		zap.String("agentId", agentID),
+		zap.String("status", result.Status))
+}
+
+// sendMonitorWebhook 发送回调请求，配置了密钥时附带请求体签名
+func sendMonitorWebhook(ctx context.Context, cfg models.MonitorWebhookConfig, payload MonitorWebhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("序列化请求体失败: %w", err)
+	}
+
+	method := strings.ToUpper(cfg.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSpace(cfg.URL), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	if cfg.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(cfg.Secret))
+		mac.Write(body)
+		req.Header.Set(monitorWebhookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("发送请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("请求失败，状态码: %d, 响应: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
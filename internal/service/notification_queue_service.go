@@ -0,0 +1,404 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/repo"
+	"github.com/go-orz/orz"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const (
+	NotificationJobStatusPending = "pending"
+	NotificationJobStatusDead    = "dead"
+
+	NotificationLogStatusSuccess = "success"
+	NotificationLogStatusFailed  = "failed"
+	NotificationLogStatusSkipped = "skipped"
+
+	// notificationMaxAttempts 单个通知的最大尝试次数，按默认退避约 2 小时后进入死信
+	notificationMaxAttempts = 8
+	// notificationRetryBaseDelay 首次重试延迟，之后每次翻倍
+	notificationRetryBaseDelay = 30 * time.Second
+	// notificationRetryMaxDelay 重试延迟上限
+	notificationRetryMaxDelay = time.Hour
+	// notificationPollInterval 扫描到期任务的间隔
+	notificationPollInterval = 10 * time.Second
+	// notificationSendTimeout 单次发送超时时间
+	notificationSendTimeout = 30 * time.Second
+	// notificationBatchSize 每轮处理的任务数
+	notificationBatchSize = 50
+	// notificationConcurrency 同时发送的渠道数上限
+	notificationConcurrency = 4
+	// notificationLogRetention 发送日志保留时间
+	notificationLogRetention = 30 * 24 * time.Hour
+	// notificationLogCleanupInterval 发送日志清理间隔
+	notificationLogCleanupInterval = time.Hour
+)
+
+// NotificationQueueService 持久化的通知发送队列，每个渠道一个任务，失败后按指数退避重试，服务重启后继续发送
+// 不同渠道并行发送，某个渠道超时或持续失败时不会阻塞其他渠道
+type NotificationQueueService struct {
+	logger          *zap.Logger
+	JobRepo         *repo.NotificationJobRepo
+	LogRepo         *repo.NotificationLogRepo
+	propertyService *PropertyService
+	notifier        *Notifier
+
+	wake       chan struct{}
+	dispatcher *channelDispatcher
+}
+
+func NewNotificationQueueService(logger *zap.Logger, db *gorm.DB, propertyService *PropertyService, notifier *Notifier) *NotificationQueueService {
+	s := &NotificationQueueService{
+		logger:          logger.Named("notification-queue"),
+		JobRepo:         repo.NewNotificationJobRepo(db),
+		LogRepo:         repo.NewNotificationLogRepo(db),
+		propertyService: propertyService,
+		notifier:        notifier,
+		wake:            make(chan struct{}, 1),
+	}
+	// 渠道发送完成后再次唤醒，该渠道或因达到并发上限被跳过的渠道可能还有到期的任务
+	s.dispatcher = newChannelDispatcher(notificationConcurrency, s.notify)
+	return s
+}
+
+// Enqueue 为每个通知渠道创建发送任务并唤醒发送协程
+func (s *NotificationQueueService) Enqueue(ctx context.Context, channels []models.NotificationChannelConfig, record *models.AlertRecord, agent *models.Agent) error {
+	now := time.Now().UnixMilli()
+	for _, channel := range channels {
+		job := &models.NotificationJob{
+			AlertRecordID: record.ID,
+			AgentID:       agent.ID,
+			ChannelType:   channel.Type,
+			Record:        datatypes.NewJSONType(*record),
+			Agent:         datatypes.NewJSONType(*agent),
+			Status:        NotificationJobStatusPending,
+			MaxAttempts:   notificationMaxAttempts,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		}
+		if err := s.JobRepo.Create(ctx, job); err != nil {
+			return err
+		}
+	}
+	s.notify()
+	return nil
+}
+
+// notify 唤醒发送协程立即处理，已有未处理的唤醒信号时直接返回
+func (s *NotificationQueueService) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run 启动通知发送任务
+func (s *NotificationQueueService) Run(ctx context.Context) {
+	ticker := time.NewTicker(notificationPollInterval)
+	defer ticker.Stop()
+	cleanupTicker := time.NewTicker(notificationLogCleanupInterval)
+	defer cleanupTicker.Stop()
+
+	s.logger.Info("通知发送队列已启动")
+
+	// 处理上次退出时未发送完成的任务
+	s.processDue(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.dispatcher.wait()
+			s.logger.Info("通知发送队列已停止")
+			return
+		case <-ticker.C:
+			s.processDue(ctx)
+		case <-s.wake:
+			s.processDue(ctx)
+		case <-cleanupTicker.C:
+			s.cleanupLogs(ctx)
+		}
+	}
+}
+
+// processDue 按渠道分发到期的任务，正在发送的渠道会被跳过，由其发送完成后再次唤醒处理
+func (s *NotificationQueueService) processDue(ctx context.Context) {
+	jobs, err := s.JobRepo.FindDue(ctx, time.Now().UnixMilli(), notificationBatchSize, s.dispatcher.busyChannels())
+	if err != nil {
+		s.logger.Error("查询待发送通知失败", zap.Error(err))
+		return
+	}
+	if len(jobs) == 0 {
+		return
+	}
+
+	channelConfigs, err := s.propertyService.GetNotificationChannelConfigs(ctx)
+	if err != nil {
+		s.logger.Error("获取通知渠道配置失败", zap.Error(err))
+		return
+	}
+
+	for _, group := range groupJobsByChannel(jobs) {
+		s.dispatcher.dispatch(group[0].ChannelType, func() {
+			for i := range group {
+				if ctx.Err() != nil {
+					return
+				}
+				s.process(ctx, &group[i], channelConfigs)
+			}
+		})
+	}
+}
+
+// groupJobsByChannel 按渠道分组，保持每个渠道内任务的顺序
+func groupJobsByChannel(jobs []models.NotificationJob) [][]models.NotificationJob {
+	var groups [][]models.NotificationJob
+	index := make(map[string]int)
+	for _, job := range jobs {
+		i, ok := index[job.ChannelType]
+		if !ok {
+			i = len(groups)
+			index[job.ChannelType] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], job)
+	}
+	return groups
+}
+
+// channelDispatcher 按渠道执行发送：同一渠道内顺序执行，不同渠道并行执行，同时执行的渠道数不超过 limit
+type channelDispatcher struct {
+	limit  int
+	onIdle func() // 渠道发送完成并释放后调用
+	mu     sync.Mutex
+	busy   map[string]bool
+	wg     sync.WaitGroup
+}
+
+func newChannelDispatcher(limit int, onIdle func()) *channelDispatcher {
+	return &channelDispatcher{
+		limit:  limit,
+		onIdle: onIdle,
+		busy:   make(map[string]bool),
+	}
+}
+
+// dispatch 在后台执行渠道的发送任务，渠道正在发送或已达到并发上限时返回 false
+func (d *channelDispatcher) dispatch(channel string, fn func()) bool {
+	d.mu.Lock()
+	if d.busy[channel] || len(d.busy) >= d.limit {
+		d.mu.Unlock()
+		return false
+	}
+	d.busy[channel] = true
+	d.mu.Unlock()
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		defer func() {
+			d.mu.Lock()
+			delete(d.busy, channel)
+			d.mu.Unlock()
+			if d.onIdle != nil {
+				d.onIdle()
+			}
+		}()
+		fn()
+	}()
+	return true
+}
+
+// busyChannels 返回正在发送的渠道
+func (d *channelDispatcher) busyChannels() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	channels := make([]string, 0, len(d.busy))
+	for channel := range d.busy {
+		channels = append(channels, channel)
+	}
+	return channels
+}
+
+// wait 等待所有渠道发送完成
+func (d *channelDispatcher) wait() {
+	d.wg.Wait()
+}
+
+// process 发送单个任务，成功后删除，失败后计算下次重试时间或转入死信
+func (s *NotificationQueueService) process(ctx context.Context, job *models.NotificationJob, channelConfigs []models.NotificationChannelConfig) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("发送通知时发生panic", zap.Any("panic", r), zap.Int64("jobId", job.ID))
+			err := fmt.Errorf("panic: %v", r)
+			s.recordAttempt(ctx, job, NotificationLogStatusFailed, 0, err.Error())
+			s.fail(ctx, job, err)
+		}
+	}()
+
+	// 使用最新的渠道配置发送，修改后的密钥等配置对重试生效
+	var channel *models.NotificationChannelConfig
+	for i := range channelConfigs {
+		if channelConfigs[i].Type == job.ChannelType {
+			channel = &channelConfigs[i]
+			break
+		}
+	}
+	if channel == nil || !channel.Enabled {
+		reason := "通知渠道已删除或已禁用"
+		s.recordAttempt(ctx, job, NotificationLogStatusSkipped, 0, reason)
+		s.dead(ctx, job, reason)
+		return
+	}
+
+	record := job.Record.Data()
+	agent := job.Agent.Data()
+
+	start := time.Now()
+	sendCtx, cancel := context.WithTimeout(ctx, notificationSendTimeout)
+	err := s.notifier.SendNotificationByConfig(sendCtx, channel, &record, &agent)
+	cancel()
+	latency := time.Since(start)
+	if err != nil {
+		s.recordAttempt(ctx, job, NotificationLogStatusFailed, latency, err.Error())
+		s.fail(ctx, job, err)
+		return
+	}
+	s.recordAttempt(ctx, job, NotificationLogStatusSuccess, latency, "")
+
+	if err := s.JobRepo.DeleteById(ctx, job.ID); err != nil {
+		s.logger.Error("删除已发送的通知任务失败", zap.Int64("jobId", job.ID), zap.Error(err))
+	}
+}
+
+// recordAttempt 写入发送日志
+func (s *NotificationQueueService) recordAttempt(ctx context.Context, job *models.NotificationJob, status string, latency time.Duration, errMsg string) {
+	entry := &models.NotificationLog{
+		JobID:         job.ID,
+		AlertRecordID: job.AlertRecordID,
+		AgentID:       job.AgentID,
+		ChannelType:   job.ChannelType,
+		Attempt:       job.Attempts + 1,
+		Status:        status,
+		LatencyMs:     latency.Milliseconds(),
+		Error:         errMsg,
+		CreatedAt:     time.Now().UnixMilli(),
+	}
+	if err := s.LogRepo.Create(ctx, entry); err != nil {
+		s.logger.Error("写入通知发送日志失败", zap.Int64("jobId", job.ID), zap.Error(err))
+	}
+}
+
+// cleanupLogs 清理过期的发送日志
+func (s *NotificationQueueService) cleanupLogs(ctx context.Context) {
+	before := time.Now().Add(-notificationLogRetention).UnixMilli()
+	deleted, err := s.LogRepo.DeleteBefore(ctx, before)
+	if err != nil {
+		s.logger.Error("清理通知发送日志失败", zap.Error(err))
+		return
+	}
+	if deleted > 0 {
+		s.logger.Info("已清理过期的通知发送日志", zap.Int64("deleted", deleted))
+	}
+}
+
+// fail 记录一次发送失败，未超过最大次数时按指数退避安排重试
+func (s *NotificationQueueService) fail(ctx context.Context, job *models.NotificationJob, err error) {
+	attempts := job.Attempts + 1
+	if attempts >= job.MaxAttempts {
+		s.logger.Error("通知多次发送失败，已转入死信",
+			zap.Int64("jobId", job.ID),
+			zap.String("channelType", job.ChannelType),
+			zap.Int("attempts", attempts),
+			zap.Error(err),
+		)
+		if updateErr := s.JobRepo.UpdateColumnsById(ctx, job.ID, map[string]interface{}{
+			"status":     NotificationJobStatusDead,
+			"attempts":   attempts,
+			"last_error": err.Error(),
+		}); updateErr != nil {
+			s.logger.Error("更新通知任务失败", zap.Int64("jobId", job.ID), zap.Error(updateErr))
+		}
+		return
+	}
+
+	delay := notificationRetryDelay(attempts)
+	s.logger.Warn("通知发送失败，稍后重试",
+		zap.Int64("jobId", job.ID),
+		zap.String("channelType", job.ChannelType),
+		zap.Int("attempts", attempts),
+		zap.Duration("retryIn", delay),
+		zap.Error(err),
+	)
+	if updateErr := s.JobRepo.UpdateColumnsById(ctx, job.ID, map[string]interface{}{
+		"attempts":        attempts,
+		"last_error":      err.Error(),
+		"next_attempt_at": time.Now().Add(delay).UnixMilli(),
+	}); updateErr != nil {
+		s.logger.Error("更新通知任务失败", zap.Int64("jobId", job.ID), zap.Error(updateErr))
+	}
+}
+
+// dead 不再重试，直接转入死信
+func (s *NotificationQueueService) dead(ctx context.Context, job *models.NotificationJob, reason string) {
+	s.logger.Warn("通知无法发送，已转入死信",
+		zap.Int64("jobId", job.ID),
+		zap.String("channelType", job.ChannelType),
+		zap.String("reason", reason),
+	)
+	if err := s.JobRepo.UpdateColumnsById(ctx, job.ID, map[string]interface{}{
+		"status":     NotificationJobStatusDead,
+		"last_error": reason,
+	}); err != nil {
+		s.logger.Error("更新通知任务失败", zap.Int64("jobId", job.ID), zap.Error(err))
+	}
+}
+
+// notificationRetryDelay 第 attempts 次失败后的重试延迟：30s, 1m, 2m, 4m ... 最长 1 小时
+func notificationRetryDelay(attempts int) time.Duration {
+	delay := notificationRetryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= notificationRetryMaxDelay {
+			return notificationRetryMaxDelay
+		}
+	}
+	return delay
+}
+
+// Retry 重新发送死信中的通知
+func (s *NotificationQueueService) Retry(ctx context.Context, id int64) error {
+	job, err := s.JobRepo.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if job.Status != NotificationJobStatusDead {
+		return orz.NewError(400, "通知任务不在死信列表中")
+	}
+	if err := s.JobRepo.UpdateColumnsById(ctx, id, map[string]interface{}{
+		"status":          NotificationJobStatusPending,
+		"attempts":        0,
+		"next_attempt_at": time.Now().UnixMilli(),
+	}); err != nil {
+		return err
+	}
+	s.notify()
+	return nil
+}
+
+// Delete 删除通知任务
+func (s *NotificationQueueService) Delete(ctx context.Context, id int64) error {
+	return s.JobRepo.DeleteById(ctx, id)
+}
+
+// ClearDead 清空死信列表
+func (s *NotificationQueueService) ClearDead(ctx context.Context) error {
+	return s.JobRepo.DeleteByStatus(ctx, NotificationJobStatusDead)
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+func TestChannelDispatcherSlowChannel(t *testing.T) {
+	var idle atomic.Int32
+	d := newChannelDispatcher(2, func() { idle.Add(1) })
+
+	slow := make(chan struct{})
+	if !d.dispatch("webhook", func() { <-slow }) {
+		t.Fatalf("空闲渠道应可以发送")
+	}
+
+	fast := make(chan struct{})
+	if !d.dispatch("email", func() { close(fast) }) {
+		t.Fatalf("其他渠道应可以并行发送")
+	}
+	select {
+	case <-fast:
+	case <-time.After(time.Second):
+		t.Fatalf("慢渠道阻塞了其他渠道的发送")
+	}
+
+	if d.dispatch("webhook", func() {}) {
+		t.Fatalf("同一渠道应按顺序发送，正在发送时不应再次分发")
+	}
+	if got := waitBusy(d, 1); !reflect.DeepEqual(got, []string{"webhook"}) {
+		t.Fatalf("busyChannels = %v, 期望 [webhook]", got)
+	}
+
+	// 达到并发上限后跳过新的渠道
+	blocked := make(chan struct{})
+	if !d.dispatch("dingtalk", func() { <-blocked }) {
+		t.Fatalf("未达到并发上限时应可以发送")
+	}
+	if d.dispatch("feishu", func() {}) {
+		t.Fatalf("达到并发上限时不应再分发")
+	}
+
+	close(slow)
+	close(blocked)
+	d.wait()
+	if len(d.busyChannels()) != 0 {
+		t.Fatalf("发送完成后应释放所有渠道")
+	}
+	if idle.Load() != 3 {
+		t.Fatalf("每个渠道发送完成后应唤醒一次, got %d", idle.Load())
+	}
+}
+
+// waitBusy 等待正在发送的渠道数降到 n
+func waitBusy(d *channelDispatcher, n int) []string {
+	deadline := time.Now().Add(time.Second)
+	for {
+		channels := d.busyChannels()
+		if len(channels) <= n || time.Now().After(deadline) {
+			return channels
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestGroupJobsByChannel(t *testing.T) {
+	jobs := []models.NotificationJob{
+		{ID: 1, ChannelType: "webhook"},
+		{ID: 2, ChannelType: "email"},
+		{ID: 3, ChannelType: "webhook"},
+		{ID: 4, ChannelType: "dingtalk"},
+	}
+	groups := groupJobsByChannel(jobs)
+	var got [][]int64
+	for _, group := range groups {
+		var ids []int64
+		for _, job := range group {
+			ids = append(ids, job.ID)
+		}
+		got = append(got, ids)
+	}
+	want := [][]int64{{1, 3}, {2}, {4}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("groupJobsByChannel = %v, 期望 %v", got, want)
+	}
+}
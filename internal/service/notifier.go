@@ -6,32 +6,46 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/dushixiang/pika/internal/models"
+	"github.com/go-orz/cache"
 	"github.com/valyala/fasttemplate"
 	"go.uber.org/zap"
 )
 
+// discordMessageTTL Discord 消息 ID 的保留时间，超过后告警恢复时发送新的恢复消息而不是编辑原消息
+const discordMessageTTL = 7 * 24 * time.Hour
+
 // Notifier 告警通知服务
 type Notifier struct {
 	logger *zap.Logger
+	// discordMessages 记录告警记录对应的 Discord 消息 ID（key: webhook:recordId），用于恢复时编辑原消息
+	// 只保存在内存中，过期或服务重启后找不到原消息时发送一条新的恢复消息
+	discordMessages cache.Cache[string, string]
+	// pushoverReceipts 记录告警记录对应的 Pushover 紧急通知回执（key: userKey:recordId），用于恢复时取消重复提醒
+	// 回执在紧急通知停止重复（expire）后失效，保留时间与 expire 一致
+	pushoverReceipts cache.Cache[string, string]
 }
 
 func NewNotifier(logger *zap.Logger) *Notifier {
 	return &Notifier{
-		logger: logger,
+		logger:           logger.Named("notifier"),
+		discordMessages:  cache.New[string, string](time.Hour),
+		pushoverReceipts: cache.New[string, string](time.Hour),
 	}
 }
 
 // buildMessage 构建告警消息文本
-func (n *Notifier) buildMessage(agent *models.Agent, record *models.AlertRecord) string {
+func (n *Notifier) buildMessage(loc *notificationLocale, agent *models.Agent, record *models.AlertRecord) string {
 	var message string
 
 	// 告警级别图标
@@ -45,91 +59,126 @@ func (n *Notifier) buildMessage(agent *models.Agent, record *models.AlertRecord)
 		levelIcon = "🚨"
 	}
 
-	// 告警类型名称
-	alertTypeName := ""
-	switch record.AlertType {
-	case "cpu":
-		alertTypeName = "CPU告警"
-	case "memory":
-		alertTypeName = "内存告警"
-	case "disk":
-		alertTypeName = "磁盘告警"
-	case "network":
-		alertTypeName = "网络断开告警"
-	case "cert":
-		alertTypeName = "证书告警"
-	case "service":
-		alertTypeName = "服务告警"
-	}
-
+	label := loc.Labels
 	if record.Status == "firing" {
 		// 告警触发消息
 		message = fmt.Sprintf(
 			"%s %s\n\n"+
-				"探针: %s (%s)\n"+
-				"主机: %s\n"+
-				"IP: %s\n"+
-				"告警类型: %s\n"+
-				"告警消息: %s\n"+
-				"阈值: %.2f%%\n"+
-				"当前值: %.2f%%\n"+
-				"触发时间: %s",
+				"%s: %s (%s)\n"+
+				"%s: %s\n"+
+				"%s: %s\n"+
+				"%s: %s\n"+
+				"%s: %s\n"+
+				"%s: %.2f%%\n"+
+				"%s: %.2f%%\n"+
+				"%s: %s",
 			levelIcon,
-			alertTypeName,
-			agent.Name,
-			agent.ID,
-			agent.Hostname,
-			agent.IP,
-			record.AlertType,
-			record.Message,
-			record.Threshold,
-			record.ActualValue,
-			time.Unix(record.FiredAt/1000, 0).Format("2006-01-02 15:04:05"),
+			loc.alertTypeName(record.AlertType),
+			label.Agent, agent.Name, agent.ID,
+			label.Host, agent.Hostname,
+			label.IP, agent.IP,
+			label.AlertType, record.AlertType,
+			label.Message, loc.alertMessage(record),
+			label.Threshold, record.Threshold,
+			label.Value, record.ActualValue,
+			label.FiredAt, time.Unix(record.FiredAt/1000, 0).Format("2006-01-02 15:04:05"),
 		)
 	} else if record.Status == "resolved" {
 		// 告警恢复消息
 		message = fmt.Sprintf(
-			"✅ %s已恢复\n\n"+
-				"探针: %s (%s)\n"+
-				"主机: %s\n"+
-				"IP: %s\n"+
-				"告警类型: %s\n"+
-				"当前值: %.2f%%\n"+
-				"恢复时间: %s",
-			alertTypeName,
-			agent.Name,
-			agent.ID,
-			agent.Hostname,
-			agent.IP,
-			record.AlertType,
-			record.ActualValue,
-			time.Unix(record.ResolvedAt/1000, 0).Format("2006-01-02 15:04:05"),
+			"✅ %s\n\n"+
+				"%s: %s (%s)\n"+
+				"%s: %s\n"+
+				"%s: %s\n"+
+				"%s: %s\n"+
+				"%s: %.2f%%\n"+
+				"%s: %s",
+			loc.resolvedTitle(record.AlertType),
+			label.Agent, agent.Name, agent.ID,
+			label.Host, agent.Hostname,
+			label.IP, agent.IP,
+			label.AlertType, record.AlertType,
+			label.Value, record.ActualValue,
+			label.ResolvedAt, time.Unix(record.ResolvedAt/1000, 0).Format("2006-01-02 15:04:05"),
 		)
 	}
 
 	return message
 }
 
-// sendDingTalk 发送钉钉通知
-func (n *Notifier) sendDingTalk(ctx context.Context, webhook, secret, message string) error {
-	// 构造钉钉消息体
-	body := map[string]interface{}{
-		"msgtype": "text",
-		"text": map[string]string{
-			"content": message,
-		},
+// buildPushTitle 构建推送类渠道（ntfy、Gotify 等）的通知标题
+func buildPushTitle(loc *notificationLocale, agent *models.Agent, record *models.AlertRecord) string {
+	if record.Status == "resolved" {
+		return fmt.Sprintf("%s - %s", loc.resolvedTitle(record.AlertType), agent.Name)
 	}
+	return fmt.Sprintf("[%s] %s - %s", strings.ToUpper(record.Level), loc.alertTypeName(record.AlertType), agent.Name)
+}
 
+// buildMarkdownMessage 构建 Markdown 格式的告警消息，返回标题和正文
+func (n *Notifier) buildMarkdownMessage(loc *notificationLocale, agent *models.Agent, record *models.AlertRecord) (string, string) {
+	label := loc.Labels
+
+	var lines []string
+	var title string
+	if record.Status == "resolved" {
+		title = loc.resolvedTitle(record.AlertType)
+		lines = []string{
+			fmt.Sprintf("### ✅ %s", title),
+			fmt.Sprintf("**%s**: %s (%s)", label.Agent, agent.Name, agent.ID),
+			fmt.Sprintf("**%s**: %s", label.Host, agent.Hostname),
+			fmt.Sprintf("**%s**: %s", label.IP, agent.IP),
+			fmt.Sprintf("**%s**: %.2f", label.Value, record.ActualValue),
+			fmt.Sprintf("**%s**: %s", label.ResolvedAt, time.UnixMilli(record.ResolvedAt).Format("2006-01-02 15:04:05")),
+		}
+	} else {
+		levelIcon := "ℹ️"
+		switch record.Level {
+		case "warning":
+			levelIcon = "⚠️"
+		case "critical":
+			levelIcon = "🚨"
+		}
+		title = loc.alertTypeName(record.AlertType)
+		lines = []string{
+			fmt.Sprintf("### %s %s", levelIcon, title),
+			fmt.Sprintf("**%s**: %s (%s)", label.Agent, agent.Name, agent.ID),
+			fmt.Sprintf("**%s**: %s", label.Host, agent.Hostname),
+			fmt.Sprintf("**%s**: %s", label.IP, agent.IP),
+			fmt.Sprintf("**%s**: %s", label.Level, record.Level),
+			fmt.Sprintf("**%s**: %s", label.Message, loc.alertMessage(record)),
+			fmt.Sprintf("**%s**: %.2f", label.Threshold, record.Threshold),
+			fmt.Sprintf("**%s**: %.2f", label.Value, record.ActualValue),
+			fmt.Sprintf("**%s**: %s", label.FiredAt, time.UnixMilli(record.FiredAt).Format("2006-01-02 15:04:05")),
+		}
+	}
+
+	return title, strings.Join(lines, "\n\n")
+}
+
+type DingTalkResult struct {
+	Errcode int    `json:"errcode"`
+	Errmsg  string `json:"errmsg"`
+}
+
+// sendDingTalk 发送钉钉通知
+func (n *Notifier) sendDingTalk(ctx context.Context, webhook, secret string, body map[string]interface{}) error {
 	// 如果有加签密钥，计算签名
 	timestamp := time.Now().UnixMilli()
 	if secret != "" {
 		sign := n.calculateDingTalkSign(timestamp, secret)
-		webhook = fmt.Sprintf("%s&timestamp=%d&sign=%s", webhook, timestamp, sign)
+		webhook = fmt.Sprintf("%s&timestamp=%d&sign=%s", webhook, timestamp, url.QueryEscape(sign))
 	}
-	_, err := n.sendJSONRequest(ctx, webhook, body)
+	result, err := n.sendJSONRequest(ctx, webhook, body)
 	if err != nil {
 		return err
 	}
+	var dingTalkResult DingTalkResult
+	if err := json.Unmarshal(result, &dingTalkResult); err != nil {
+		return err
+	}
+	if dingTalkResult.Errcode != 0 {
+		return fmt.Errorf("%s", dingTalkResult.Errmsg)
+	}
 	return nil
 }
 
@@ -150,13 +199,7 @@ type WeComResult struct {
 }
 
 // sendWeCom 发送企业微信通知
-func (n *Notifier) sendWeCom(ctx context.Context, webhook, message string) error {
-	body := map[string]interface{}{
-		"msgtype": "text",
-		"text": map[string]string{
-			"content": message,
-		},
-	}
+func (n *Notifier) sendWeCom(ctx context.Context, webhook string, body map[string]interface{}) error {
 	result, err := n.sendJSONRequest(ctx, webhook, body)
 	if err != nil {
 		return err
@@ -171,19 +214,225 @@ func (n *Notifier) sendWeCom(ctx context.Context, webhook, message string) error
 	return nil
 }
 
+type FeishuResult struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
 // sendFeishu 发送飞书通知
-func (n *Notifier) sendFeishu(ctx context.Context, webhook, message string) error {
-	body := map[string]interface{}{
-		"msg_type": "text",
-		"content": map[string]string{
-			"text": message,
+func (n *Notifier) sendFeishu(ctx context.Context, webhook, secret string, body map[string]interface{}) error {
+	// 如果有加签密钥，在消息体中附带签名
+	if secret != "" {
+		timestamp := time.Now().Unix()
+		body["timestamp"] = strconv.FormatInt(timestamp, 10)
+		body["sign"] = n.calculateFeishuSign(timestamp, secret)
+	}
+
+	result, err := n.sendJSONRequest(ctx, webhook, body)
+	if err != nil {
+		return err
+	}
+	var feishuResult FeishuResult
+	if err := json.Unmarshal(result, &feishuResult); err != nil {
+		return err
+	}
+	if feishuResult.Code != 0 {
+		return fmt.Errorf("%s", feishuResult.Msg)
+	}
+	return nil
+}
+
+// calculateFeishuSign 计算飞书加签，飞书使用 timestamp+"\n"+secret 作为密钥对空字符串签名
+func (n *Notifier) calculateFeishuSign(timestamp int64, secret string) string {
+	stringToSign := fmt.Sprintf("%d\n%s", timestamp, secret)
+	h := hmac.New(sha256.New, []byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// buildFeishuCard 构建飞书消息卡片，dashboardURL 不为空时附带跳转到探针详情的按钮
+func (n *Notifier) buildFeishuCard(loc *notificationLocale, agent *models.Agent, record *models.AlertRecord, dashboardURL string) map[string]interface{} {
+	headerColor := "blue"
+	switch record.Level {
+	case "warning":
+		headerColor = "orange"
+	case "critical":
+		headerColor = "red"
+	}
+
+	alertTypeName := loc.alertTypeName(record.AlertType)
+	title := fmt.Sprintf("[%s] %s", strings.ToUpper(record.Level), alertTypeName)
+	timeLabel := loc.Labels.FiredAt
+	timestamp := record.FiredAt
+	if record.Status == "resolved" {
+		headerColor = "green"
+		title = fmt.Sprintf("[RESOLVED] %s", loc.resolvedTitle(record.AlertType))
+		timeLabel = loc.Labels.ResolvedAt
+		timestamp = record.ResolvedAt
+	}
+
+	field := func(name, value string) map[string]interface{} {
+		return map[string]interface{}{
+			"is_short": true,
+			"text": map[string]string{
+				"tag":     "lark_md",
+				"content": fmt.Sprintf("**%s**\n%s", name, orDash(value)),
+			},
+		}
+	}
+
+	elements := []interface{}{
+		map[string]interface{}{
+			"tag": "div",
+			"fields": []interface{}{
+				field(loc.Labels.Agent, fmt.Sprintf("%s (%s)", agent.Name, agent.ID)),
+				field(loc.Labels.Host, agent.Hostname),
+				field(loc.Labels.IP, agent.IP),
+				field(loc.Labels.AlertType, alertTypeName),
+				field(loc.Labels.Threshold, fmt.Sprintf("%.2f", record.Threshold)),
+				field(loc.Labels.Value, fmt.Sprintf("%.2f", record.ActualValue)),
+				field(timeLabel, time.UnixMilli(timestamp).Format("2006-01-02 15:04:05")),
+			},
 		},
 	}
 
-	_, err := n.sendJSONRequest(ctx, webhook, body)
+	if message := loc.alertMessage(record); message != "" {
+		elements = append(elements,
+			map[string]interface{}{"tag": "hr"},
+			map[string]interface{}{
+				"tag": "div",
+				"text": map[string]string{
+					"tag":     "plain_text",
+					"content": message,
+				},
+			},
+		)
+	}
+
+	if dashboardURL != "" {
+		elements = append(elements, map[string]interface{}{
+			"tag": "action",
+			"actions": []interface{}{
+				map[string]interface{}{
+					"tag":  "button",
+					"type": "primary",
+					"text": map[string]string{
+						"tag":     "plain_text",
+						"content": loc.Labels.ViewAgent,
+					},
+					"url": fmt.Sprintf("%s/admin/agents/%s", strings.TrimRight(dashboardURL, "/"), agent.ID),
+				},
+			},
+		})
+	}
+
+	return map[string]interface{}{
+		"config": map[string]interface{}{
+			"wide_screen_mode": true,
+		},
+		"header": map[string]interface{}{
+			"template": headerColor,
+			"title": map[string]string{
+				"tag":     "plain_text",
+				"content": title,
+			},
+		},
+		"elements": elements,
+	}
+}
+
+// Discord 嵌入消息颜色
+const (
+	discordColorInfo     = 0x3498DB
+	discordColorWarning  = 0xF1C40F
+	discordColorCritical = 0xE74C3C
+	discordColorResolved = 0x2ECC71
+)
+
+// buildDiscordEmbed 构建 Discord 嵌入消息，颜色按告警级别和状态区分
+func (n *Notifier) buildDiscordEmbed(loc *notificationLocale, agent *models.Agent, record *models.AlertRecord) map[string]interface{} {
+	color := discordColorInfo
+	switch record.Level {
+	case "warning":
+		color = discordColorWarning
+	case "critical":
+		color = discordColorCritical
+	}
+
+	title := fmt.Sprintf("[%s] %s", strings.ToUpper(record.Level), loc.alertTypeName(record.AlertType))
+	timestamp := record.FiredAt
+	if record.Status == "resolved" {
+		color = discordColorResolved
+		title = fmt.Sprintf("[RESOLVED] %s", loc.resolvedTitle(record.AlertType))
+		timestamp = record.ResolvedAt
+	}
+
+	fields := []map[string]interface{}{
+		{"name": loc.Labels.Agent, "value": fmt.Sprintf("%s (%s)", agent.Name, agent.ID), "inline": false},
+		{"name": loc.Labels.Host, "value": orDash(agent.Hostname), "inline": true},
+		{"name": loc.Labels.IP, "value": orDash(agent.IP), "inline": true},
+		{"name": loc.Labels.Threshold, "value": fmt.Sprintf("%.2f", record.Threshold), "inline": true},
+		{"name": loc.Labels.Value, "value": fmt.Sprintf("%.2f", record.ActualValue), "inline": true},
+	}
+
+	return map[string]interface{}{
+		"title":       title,
+		"description": loc.alertMessage(record),
+		"color":       color,
+		"fields":      fields,
+		"timestamp":   time.UnixMilli(timestamp).UTC().Format(time.RFC3339),
+	}
+}
+
+// orDash 空字符串显示为 -，Discord 不允许字段值为空
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+type discordMessage struct {
+	ID string `json:"id"`
+}
+
+// sendDiscord 发送 Discord 通知
+// 告警触发时发送新消息并记录消息 ID，恢复时编辑原消息；找不到原消息时发送一条新的恢复消息
+func (n *Notifier) sendDiscord(ctx context.Context, webhook string, loc *notificationLocale, agent *models.Agent, record *models.AlertRecord) error {
+	body := map[string]interface{}{
+		"embeds": []interface{}{n.buildDiscordEmbed(loc, agent, record)},
+	}
+
+	messageKey := fmt.Sprintf("%s:%d", webhook, record.ID)
+	if record.Status == "resolved" && record.ID > 0 {
+		if messageID, ok := n.discordMessages.Get(messageKey); ok {
+			n.discordMessages.Delete(messageKey)
+			editURL := fmt.Sprintf("%s/messages/%s", strings.TrimRight(webhook, "/"), messageID)
+			_, err := n.sendJSONRequestWithMethod(ctx, http.MethodPatch, editURL, body)
+			if err == nil {
+				return nil
+			}
+			n.logger.Warn("编辑 Discord 消息失败，改为发送新消息", zap.Error(err))
+		}
+	}
+
+	// wait=true 时 Discord 会返回创建的消息，用于后续编辑
+	sendURL := webhook
+	if strings.Contains(sendURL, "?") {
+		sendURL += "&wait=true"
+	} else {
+		sendURL += "?wait=true"
+	}
+	result, err := n.sendJSONRequest(ctx, sendURL, body)
 	if err != nil {
 		return err
 	}
+
+	if record.Status == "firing" && record.ID > 0 {
+		var message discordMessage
+		if err := json.Unmarshal(result, &message); err == nil && message.ID != "" {
+			n.discordMessages.Set(messageKey, message.ID, discordMessageTTL)
+		}
+	}
 	return nil
 }
 
@@ -218,10 +467,11 @@ func (n *Notifier) sendCustomWebhook(ctx context.Context, config map[string]inte
 	}
 
 	// 构建消息内容
-	message := n.buildMessage(agent, record)
+	loc := localeFromConfig(config)
+	message := n.buildMessage(loc, agent, record)
 
 	// 根据模板类型构建请求体
-	var reqBody io.Reader
+	var payload []byte
 	var contentType string
 
 	switch bodyTemplate {
@@ -242,7 +492,7 @@ func (n *Notifier) sendCustomWebhook(ctx context.Context, config map[string]inte
 				"type":        record.AlertType,
 				"level":       record.Level,
 				"status":      record.Status,
-				"message":     record.Message,
+				"message":     loc.alertMessage(record),
 				"threshold":   record.Threshold,
 				"actualValue": record.ActualValue,
 				"firedAt":     record.FiredAt,
@@ -253,7 +503,7 @@ func (n *Notifier) sendCustomWebhook(ctx context.Context, config map[string]inte
 		if err != nil {
 			return fmt.Errorf("序列化 JSON 失败: %w", err)
 		}
-		reqBody = bytes.NewReader(data)
+		payload = data
 		contentType = "application/json"
 
 	case "form":
@@ -267,14 +517,14 @@ func (n *Notifier) sendCustomWebhook(ctx context.Context, config map[string]inte
 		formData.Set("alert_type", record.AlertType)
 		formData.Set("alert_level", record.Level)
 		formData.Set("alert_status", record.Status)
-		formData.Set("alert_message", record.Message)
+		formData.Set("alert_message", loc.alertMessage(record))
 		formData.Set("threshold", fmt.Sprintf("%.2f", record.Threshold))
 		formData.Set("actual_value", fmt.Sprintf("%.2f", record.ActualValue))
 		formData.Set("fired_at", fmt.Sprintf("%d", record.FiredAt))
 		if record.ResolvedAt > 0 {
 			formData.Set("resolved_at", fmt.Sprintf("%d", record.ResolvedAt))
 		}
-		reqBody = strings.NewReader(formData.Encode())
+		payload = []byte(formData.Encode())
 		contentType = "application/x-www-form-urlencoded"
 
 	case "custom":
@@ -314,7 +564,7 @@ func (n *Notifier) sendCustomWebhook(ctx context.Context, config map[string]inte
 			case "alert.status":
 				v = record.Status
 			case "alert.message":
-				v = record.Message
+				v = loc.alertMessage(record)
 			case "alert.threshold":
 				v = fmt.Sprintf("%.2f", record.Threshold)
 			case "alert.actualValue":
@@ -331,7 +581,7 @@ func (n *Notifier) sendCustomWebhook(ctx context.Context, config map[string]inte
 			return w.Write([]byte(escape(v)))
 		})
 		n.logger.Sugar().Debugf("自定义Webhook请求体: %s", bodyStr)
-		reqBody = strings.NewReader(bodyStr)
+		payload = []byte(bodyStr)
 		contentType = "text/plain"
 
 	default:
@@ -339,7 +589,7 @@ func (n *Notifier) sendCustomWebhook(ctx context.Context, config map[string]inte
 	}
 
 	// 创建请求
-	req, err := http.NewRequestWithContext(ctx, method, webhookURL, reqBody)
+	req, err := http.NewRequestWithContext(ctx, method, webhookURL, bytes.NewReader(payload))
 	if err != nil {
 		return fmt.Errorf("创建请求失败: %w", err)
 	}
@@ -352,6 +602,15 @@ func (n *Notifier) sendCustomWebhook(ctx context.Context, config map[string]inte
 		req.Header.Set(k, v)
 	}
 
+	// 配置了签名密钥时，对请求体计算 HMAC-SHA256 签名，接收方可据此校验请求来源
+	if signSecret, _ := config["signSecret"].(string); signSecret != "" {
+		signatureHeader, _ := config["signatureHeader"].(string)
+		if signatureHeader == "" {
+			signatureHeader = defaultWebhookSignatureHeader
+		}
+		req.Header.Set(signatureHeader, signWebhookPayload(signSecret, payload))
+	}
+
 	// 发送请求
 	client := &http.Client{
 		Timeout: 10 * time.Second,
@@ -365,6 +624,7 @@ func (n *Notifier) sendCustomWebhook(ctx context.Context, config map[string]inte
 
 	// 读取响应
 	respBody, _ := io.ReadAll(resp.Body)
+	recordProviderResponse(ctx, resp, respBody)
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return fmt.Errorf("请求失败，状态码: %d, 响应: %s", resp.StatusCode, string(respBody))
@@ -379,14 +639,29 @@ func (n *Notifier) sendCustomWebhook(ctx context.Context, config map[string]inte
 	return nil
 }
 
+// defaultWebhookSignatureHeader 自定义Webhook默认的签名请求头
+const defaultWebhookSignatureHeader = "X-Pika-Signature"
+
+// signWebhookPayload 使用密钥对请求体计算 HMAC-SHA256 签名，格式为 sha256=<hex>
+func signWebhookPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
 // sendJSONRequest 发送JSON请求
 func (n *Notifier) sendJSONRequest(ctx context.Context, url string, body interface{}) ([]byte, error) {
+	return n.sendJSONRequestWithMethod(ctx, http.MethodPost, url, body)
+}
+
+// sendJSONRequestWithMethod 使用指定方法发送JSON请求
+func (n *Notifier) sendJSONRequestWithMethod(ctx context.Context, method, url string, body interface{}) ([]byte, error) {
 	data, err := json.Marshal(body)
 	if err != nil {
 		return nil, fmt.Errorf("序列化请求体失败: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(data))
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(data))
 	if err != nil {
 		return nil, fmt.Errorf("创建请求失败: %w", err)
 	}
@@ -405,6 +680,7 @@ func (n *Notifier) sendJSONRequest(ctx context.Context, url string, body interfa
 
 	// 读取响应
 	respBody, _ := io.ReadAll(resp.Body)
+	recordProviderResponse(ctx, resp, respBody)
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return nil, fmt.Errorf("请求失败，状态码: %d, 响应: %s", resp.StatusCode, string(respBody))
@@ -415,7 +691,7 @@ func (n *Notifier) sendJSONRequest(ctx context.Context, url string, body interfa
 }
 
 // sendDingTalkByConfig 根据配置发送钉钉通知
-func (n *Notifier) sendDingTalkByConfig(ctx context.Context, config map[string]interface{}, message string) error {
+func (n *Notifier) sendDingTalkByConfig(ctx context.Context, config map[string]interface{}, agent *models.Agent, record *models.AlertRecord) error {
 	secretKey, ok := config["secretKey"].(string)
 	if !ok || secretKey == "" {
 		return fmt.Errorf("钉钉配置缺少 secretKey")
@@ -427,11 +703,31 @@ func (n *Notifier) sendDingTalkByConfig(ctx context.Context, config map[string]i
 	// 检查是否有加签密钥
 	signSecret, _ := config["signSecret"].(string)
 
-	return n.sendDingTalk(ctx, webhook, signSecret, message)
+	// 构造钉钉消息体，默认使用文本消息
+	var body map[string]interface{}
+	if msgType, _ := config["msgType"].(string); msgType == "markdown" {
+		title, text := n.buildMarkdownMessage(localeFromConfig(config), agent, record)
+		body = map[string]interface{}{
+			"msgtype": "markdown",
+			"markdown": map[string]string{
+				"title": title,
+				"text":  text,
+			},
+		}
+	} else {
+		body = map[string]interface{}{
+			"msgtype": "text",
+			"text": map[string]string{
+				"content": n.buildMessage(localeFromConfig(config), agent, record),
+			},
+		}
+	}
+
+	return n.sendDingTalk(ctx, webhook, signSecret, body)
 }
 
 // sendWeComByConfig 根据配置发送企业微信通知
-func (n *Notifier) sendWeComByConfig(ctx context.Context, config map[string]interface{}, message string) error {
+func (n *Notifier) sendWeComByConfig(ctx context.Context, config map[string]interface{}, agent *models.Agent, record *models.AlertRecord) error {
 	secretKey, ok := config["secretKey"].(string)
 	if !ok || secretKey == "" {
 		return fmt.Errorf("企业微信配置缺少 secretKey")
@@ -440,11 +736,48 @@ func (n *Notifier) sendWeComByConfig(ctx context.Context, config map[string]inte
 	// 构造 Webhook URL
 	webhook := fmt.Sprintf("https://qyapi.weixin.qq.com/cgi-bin/webhook/send?key=%s", secretKey)
 
-	return n.sendWeCom(ctx, webhook, message)
+	// 仅严重级别的告警触发时 @ 指定成员，避免普通告警和恢复通知打扰
+	var mentionedList, mentionedMobileList []string
+	if record.Level == "critical" && record.Status == "firing" {
+		mentionedList = parseStringList(config["mentionedList"])
+		mentionedMobileList = parseStringList(config["mentionedMobileList"])
+	}
+
+	// 构造企业微信消息体，默认使用文本消息
+	var body map[string]interface{}
+	if msgType, _ := config["msgType"].(string); msgType == "markdown" {
+		_, content := n.buildMarkdownMessage(localeFromConfig(config), agent, record)
+		// markdown 消息只支持通过 <@userid> 提醒成员，不支持手机号
+		for _, userID := range mentionedList {
+			content += fmt.Sprintf("\n\n<@%s>", userID)
+		}
+		body = map[string]interface{}{
+			"msgtype": "markdown",
+			"markdown": map[string]string{
+				"content": content,
+			},
+		}
+	} else {
+		text := map[string]interface{}{
+			"content": n.buildMessage(localeFromConfig(config), agent, record),
+		}
+		if len(mentionedList) > 0 {
+			text["mentioned_list"] = mentionedList
+		}
+		if len(mentionedMobileList) > 0 {
+			text["mentioned_mobile_list"] = mentionedMobileList
+		}
+		body = map[string]interface{}{
+			"msgtype": "text",
+			"text":    text,
+		}
+	}
+
+	return n.sendWeCom(ctx, webhook, body)
 }
 
 // sendFeishuByConfig 根据配置发送飞书通知
-func (n *Notifier) sendFeishuByConfig(ctx context.Context, config map[string]interface{}, message string) error {
+func (n *Notifier) sendFeishuByConfig(ctx context.Context, config map[string]interface{}, agent *models.Agent, record *models.AlertRecord) error {
 	secretKey, ok := config["secretKey"].(string)
 	if !ok || secretKey == "" {
 		return fmt.Errorf("飞书配置缺少 secretKey")
@@ -453,7 +786,27 @@ func (n *Notifier) sendFeishuByConfig(ctx context.Context, config map[string]int
 	// 构造 Webhook URL
 	webhook := fmt.Sprintf("https://open.feishu.cn/open-apis/bot/v2/hook/%s", secretKey)
 
-	return n.sendFeishu(ctx, webhook, message)
+	// 检查是否有加签密钥
+	signSecret, _ := config["signSecret"].(string)
+	// 控制台地址，用于卡片中跳转到探针详情
+	dashboardURL, _ := config["dashboardUrl"].(string)
+
+	body := map[string]interface{}{
+		"msg_type": "interactive",
+		"card":     n.buildFeishuCard(localeFromConfig(config), agent, record, dashboardURL),
+	}
+
+	return n.sendFeishu(ctx, webhook, signSecret, body)
+}
+
+// sendDiscordByConfig 根据配置发送 Discord 通知
+func (n *Notifier) sendDiscordByConfig(ctx context.Context, config map[string]interface{}, agent *models.Agent, record *models.AlertRecord) error {
+	webhookURL, ok := config["webhookUrl"].(string)
+	if !ok || webhookURL == "" {
+		return fmt.Errorf("Discord 配置缺少 webhookUrl")
+	}
+
+	return n.sendDiscord(ctx, webhookURL, localeFromConfig(config), agent, record)
 }
 
 // sendWebhookByConfig 根据配置发送自定义Webhook
@@ -471,21 +824,29 @@ func (n *Notifier) SendNotificationByConfig(ctx context.Context, channelConfig *
 		zap.String("channelType", channelConfig.Type),
 	)
 
-	// 构造通知消息内容
-	message := n.buildMessage(agent, record)
-
 	switch channelConfig.Type {
 	case "dingtalk":
-		return n.sendDingTalkByConfig(ctx, channelConfig.Config, message)
+		return n.sendDingTalkByConfig(ctx, channelConfig.Config, agent, record)
 	case "wecom":
-		return n.sendWeComByConfig(ctx, channelConfig.Config, message)
+		return n.sendWeComByConfig(ctx, channelConfig.Config, agent, record)
 	case "feishu":
-		return n.sendFeishuByConfig(ctx, channelConfig.Config, message)
+		return n.sendFeishuByConfig(ctx, channelConfig.Config, agent, record)
 	case "webhook":
 		return n.sendWebhookByConfig(ctx, channelConfig.Config, agent, record)
+	case "discord":
+		return n.sendDiscordByConfig(ctx, channelConfig.Config, agent, record)
 	case "email":
-		// TODO: 实现邮件通知
-		return fmt.Errorf("邮件通知暂未实现")
+		return n.sendEmailByConfig(ctx, channelConfig.Config, agent, record)
+	case "ntfy":
+		return n.sendNtfyByConfig(ctx, channelConfig.Config, agent, record)
+	case "gotify":
+		return n.sendGotifyByConfig(ctx, channelConfig.Config, agent, record)
+	case "pushover":
+		return n.sendPushoverByConfig(ctx, channelConfig.Config, agent, record)
+	case "matrix":
+		return n.sendMatrixByConfig(ctx, channelConfig.Config, agent, record)
+	case "twilio":
+		return n.sendTwilioByConfig(ctx, channelConfig.Config, agent, record)
 	default:
 		return fmt.Errorf("不支持的通知渠道类型: %s", channelConfig.Type)
 	}
@@ -512,24 +873,58 @@ func (n *Notifier) SendNotificationByConfigs(ctx context.Context, channelConfigs
 	return nil
 }
 
-// SendDingTalkByConfig 导出方法供外部调用
+// SendDingTalkByConfig 导出方法供外部调用（测试用）
 func (n *Notifier) SendDingTalkByConfig(ctx context.Context, config map[string]interface{}, message string) error {
-	return n.sendDingTalkByConfig(ctx, config, message)
+	agent, record := newTestAlert(message)
+	return n.sendDingTalkByConfig(ctx, config, agent, record)
 }
 
-// SendWeComByConfig 导出方法供外部调用
+// SendWeComByConfig 导出方法供外部调用（测试用）
 func (n *Notifier) SendWeComByConfig(ctx context.Context, config map[string]interface{}, message string) error {
-	return n.sendWeComByConfig(ctx, config, message)
+	agent, record := newTestAlert(message)
+	return n.sendWeComByConfig(ctx, config, agent, record)
 }
 
-// SendFeishuByConfig 导出方法供外部调用
+// SendFeishuByConfig 导出方法供外部调用（测试用）
 func (n *Notifier) SendFeishuByConfig(ctx context.Context, config map[string]interface{}, message string) error {
-	return n.sendFeishuByConfig(ctx, config, message)
+	agent, record := newTestAlert(message)
+	return n.sendFeishuByConfig(ctx, config, agent, record)
 }
 
 // SendWebhookByConfig 导出方法供外部调用（测试用）
 func (n *Notifier) SendWebhookByConfig(ctx context.Context, config map[string]interface{}, message string) error {
-	// 为了测试，创建一个临时的 agent 和 record
+	agent, record := newTestAlert(message)
+	return n.sendWebhookByConfig(ctx, config, agent, record)
+}
+
+// SendDiscordByConfig 导出方法供外部调用（测试用）
+func (n *Notifier) SendDiscordByConfig(ctx context.Context, config map[string]interface{}, message string) error {
+	agent, record := newTestAlert(message)
+	return n.sendDiscordByConfig(ctx, config, agent, record)
+}
+
+// parseStringList 解析配置中的字符串列表，支持数组或逗号分隔的字符串
+func parseStringList(value interface{}) []string {
+	var items []string
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+				items = append(items, strings.TrimSpace(s))
+			}
+		}
+	case string:
+		for _, s := range strings.Split(v, ",") {
+			if strings.TrimSpace(s) != "" {
+				items = append(items, strings.TrimSpace(s))
+			}
+		}
+	}
+	return items
+}
+
+// newTestAlert 创建用于测试通知的临时探针和告警记录
+func newTestAlert(message string) (*models.Agent, *models.AlertRecord) {
 	agent := &models.Agent{
 		ID:       "test-agent",
 		Name:     "测试探针",
@@ -545,5 +940,5 @@ func (n *Notifier) SendWebhookByConfig(ctx context.Context, config map[string]in
 		ActualValue: 0,
 		FiredAt:     time.Now().UnixMilli(),
 	}
-	return n.sendWebhookByConfig(ctx, config, agent, record)
+	return agent, record
 }
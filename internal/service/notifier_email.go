@@ -0,0 +1,253 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+// emailConfig 邮件通知配置
+type emailConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       []string
+	Security string // ssl, starttls, none
+}
+
+// parseEmailConfig 解析邮件通知配置
+func parseEmailConfig(config map[string]interface{}) (*emailConfig, error) {
+	cfg := &emailConfig{}
+	cfg.Host, _ = config["host"].(string)
+	if cfg.Host == "" {
+		return nil, fmt.Errorf("邮件配置缺少 host")
+	}
+
+	switch port := config["port"].(type) {
+	case float64:
+		cfg.Port = int(port)
+	case string:
+		cfg.Port, _ = strconv.Atoi(port)
+	}
+	if cfg.Port <= 0 {
+		cfg.Port = 465
+	}
+
+	cfg.Username, _ = config["username"].(string)
+	cfg.Password, _ = config["password"].(string)
+	cfg.From, _ = config["from"].(string)
+	if cfg.From == "" {
+		cfg.From = cfg.Username
+	}
+	if cfg.From == "" {
+		return nil, fmt.Errorf("邮件配置缺少 from")
+	}
+
+	cfg.To = parseStringList(config["to"])
+	if len(cfg.To) == 0 {
+		return nil, fmt.Errorf("邮件配置缺少收件人 to")
+	}
+
+	cfg.Security, _ = config["security"].(string)
+	if cfg.Security == "" {
+		// 465 端口默认使用 SSL，其它端口默认尝试 STARTTLS
+		if cfg.Port == 465 {
+			cfg.Security = "ssl"
+		} else {
+			cfg.Security = "starttls"
+		}
+	}
+	if cfg.Security != "ssl" && cfg.Security != "starttls" && cfg.Security != "none" {
+		return nil, fmt.Errorf("不支持的加密方式: %s", cfg.Security)
+	}
+
+	return cfg, nil
+}
+
+// emailTemplate 告警邮件 HTML 模板
+var emailTemplate = template.Must(template.New("alert").Parse(`<!DOCTYPE html>
+<html>
+<body style="margin:0;padding:24px;background:#f5f5f5;font-family:-apple-system,BlinkMacSystemFont,'Segoe UI',sans-serif;">
+<table width="100%" cellpadding="0" cellspacing="0" style="max-width:600px;margin:0 auto;background:#ffffff;border-radius:8px;overflow:hidden;">
+  <tr><td style="background:{{.Color}};color:#ffffff;padding:16px 24px;font-size:18px;font-weight:bold;">{{.Title}}</td></tr>
+  <tr><td style="padding:24px;">
+    <p style="margin:0 0 16px;font-size:14px;color:#333333;">{{.Message}}</p>
+    <table width="100%" cellpadding="8" cellspacing="0" style="font-size:14px;color:#333333;border-collapse:collapse;">
+      <tr><td style="width:96px;color:#888888;border-bottom:1px solid #eeeeee;">{{.L.Agent}}</td><td style="border-bottom:1px solid #eeeeee;">{{.AgentName}} ({{.AgentID}})</td></tr>
+      <tr><td style="color:#888888;border-bottom:1px solid #eeeeee;">{{.L.Host}}</td><td style="border-bottom:1px solid #eeeeee;">{{.Hostname}}</td></tr>
+      <tr><td style="color:#888888;border-bottom:1px solid #eeeeee;">{{.L.IP}}</td><td style="border-bottom:1px solid #eeeeee;">{{.IP}}</td></tr>
+      <tr><td style="color:#888888;border-bottom:1px solid #eeeeee;">{{.L.AlertType}}</td><td style="border-bottom:1px solid #eeeeee;">{{.AlertType}}</td></tr>
+      <tr><td style="color:#888888;border-bottom:1px solid #eeeeee;">{{.L.Level}}</td><td style="border-bottom:1px solid #eeeeee;">{{.Level}}</td></tr>
+      {{if not .Resolved}}<tr><td style="color:#888888;border-bottom:1px solid #eeeeee;">{{.L.Threshold}}</td><td style="border-bottom:1px solid #eeeeee;">{{printf "%.2f" .Threshold}}</td></tr>{{end}}
+      <tr><td style="color:#888888;border-bottom:1px solid #eeeeee;">{{.L.Value}}</td><td style="border-bottom:1px solid #eeeeee;">{{printf "%.2f" .ActualValue}}</td></tr>
+      <tr><td style="color:#888888;">{{if .Resolved}}{{.L.ResolvedAt}}{{else}}{{.L.FiredAt}}{{end}}</td><td>{{.Time}}</td></tr>
+    </table>
+  </td></tr>
+  <tr><td style="padding:12px 24px;font-size:12px;color:#aaaaaa;border-top:1px solid #eeeeee;">{{.L.EmailFooter}}</td></tr>
+</table>
+</body>
+</html>`))
+
+// buildEmailContent 构建告警邮件的标题和 HTML 正文
+func (n *Notifier) buildEmailContent(loc *notificationLocale, agent *models.Agent, record *models.AlertRecord) (string, string, error) {
+	alertTypeName := loc.alertTypeName(record.AlertType)
+
+	resolved := record.Status == "resolved"
+	color := "#3498db"
+	switch record.Level {
+	case "warning":
+		color = "#f39c12"
+	case "critical":
+		color = "#e74c3c"
+	}
+
+	title := fmt.Sprintf("[%s] %s", strings.ToUpper(record.Level), alertTypeName)
+	timestamp := record.FiredAt
+	if resolved {
+		color = "#2ecc71"
+		title = loc.resolvedTitle(record.AlertType)
+		timestamp = record.ResolvedAt
+	}
+
+	var body bytes.Buffer
+	err := emailTemplate.Execute(&body, map[string]interface{}{
+		"L":           loc.Labels,
+		"Title":       title,
+		"Color":       color,
+		"Message":     loc.alertMessage(record),
+		"AgentName":   agent.Name,
+		"AgentID":     agent.ID,
+		"Hostname":    agent.Hostname,
+		"IP":          agent.IP,
+		"AlertType":   alertTypeName,
+		"Level":       record.Level,
+		"Threshold":   record.Threshold,
+		"ActualValue": record.ActualValue,
+		"Time":        time.UnixMilli(timestamp).Format("2006-01-02 15:04:05"),
+		"Resolved":    resolved,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("渲染邮件模板失败: %w", err)
+	}
+
+	subject := fmt.Sprintf("[Pika] %s - %s", title, agent.Name)
+	return subject, body.String(), nil
+}
+
+// sendEmail 通过 SMTP 发送 HTML 邮件
+func (n *Notifier) sendEmail(ctx context.Context, cfg *emailConfig, subject, htmlBody string) error {
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	tlsConfig := &tls.Config{ServerName: cfg.Host}
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+
+	var conn net.Conn
+	var err error
+	if cfg.Security == "ssl" {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("连接邮件服务器失败: %w", err)
+	}
+	_ = conn.SetDeadline(time.Now().Add(30 * time.Second))
+
+	client, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("连接邮件服务器失败: %w", err)
+	}
+	defer client.Close()
+
+	if cfg.Security == "starttls" {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("邮件服务器不支持 STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("STARTTLS 失败: %w", err)
+		}
+	}
+
+	if cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)); err != nil {
+			return fmt.Errorf("邮件服务器认证失败: %w", err)
+		}
+	}
+
+	if err := client.Mail(cfg.From); err != nil {
+		return fmt.Errorf("设置发件人失败: %w", err)
+	}
+	for _, to := range cfg.To {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("设置收件人 %s 失败: %w", to, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("发送邮件失败: %w", err)
+	}
+	if _, err := w.Write(buildEmailMessage(cfg, subject, htmlBody)); err != nil {
+		return fmt.Errorf("发送邮件失败: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("发送邮件失败: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// buildEmailMessage 构建 MIME 邮件内容，正文使用 base64 编码
+func buildEmailMessage(cfg *emailConfig, subject, htmlBody string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("From: " + cfg.From + "\r\n")
+	buf.WriteString("To: " + strings.Join(cfg.To, ", ") + "\r\n")
+	buf.WriteString("Subject: " + mime.BEncoding.Encode("UTF-8", subject) + "\r\n")
+	buf.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n")
+	buf.WriteString("\r\n")
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(htmlBody))
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+	return buf.Bytes()
+}
+
+// sendEmailByConfig 根据配置发送邮件通知
+func (n *Notifier) sendEmailByConfig(ctx context.Context, config map[string]interface{}, agent *models.Agent, record *models.AlertRecord) error {
+	cfg, err := parseEmailConfig(config)
+	if err != nil {
+		return err
+	}
+
+	subject, body, err := n.buildEmailContent(localeFromConfig(config), agent, record)
+	if err != nil {
+		return err
+	}
+
+	return n.sendEmail(ctx, cfg, subject, body)
+}
+
+// SendEmailByConfig 导出方法供外部调用（测试用）
+func (n *Notifier) SendEmailByConfig(ctx context.Context, config map[string]interface{}, message string) error {
+	agent, record := newTestAlert(message)
+	return n.sendEmailByConfig(ctx, config, agent, record)
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"go.uber.org/zap"
+)
+
+// gotifyPriority 根据告警级别和状态映射 Gotify 消息优先级（0-10）
+// Gotify 客户端中 1-3 静默通知，4-7 普通通知，8 及以上会弹出提醒
+func gotifyPriority(record *models.AlertRecord) int {
+	if record.Status == "resolved" {
+		return 3
+	}
+	switch record.Level {
+	case "warning":
+		return 6
+	case "critical":
+		return 9
+	}
+	return 4
+}
+
+// sendGotify 发送 Gotify 消息
+func (n *Notifier) sendGotify(ctx context.Context, server, token string, body map[string]interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("序列化请求体失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server+"/message", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", token)
+
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("发送请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	recordProviderResponse(ctx, resp, respBody)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Gotify 请求失败，状态码: %d, 响应: %s", resp.StatusCode, string(respBody))
+	}
+
+	n.logger.Info("Gotify 通知发送成功", zap.String("server", server))
+	return nil
+}
+
+// sendGotifyByConfig 根据配置发送 Gotify 通知
+func (n *Notifier) sendGotifyByConfig(ctx context.Context, config map[string]interface{}, agent *models.Agent, record *models.AlertRecord) error {
+	server, _ := config["serverUrl"].(string)
+	server = strings.TrimRight(strings.TrimSpace(server), "/")
+	if server == "" {
+		return fmt.Errorf("Gotify 配置缺少 serverUrl")
+	}
+	token, _ := config["appToken"].(string)
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return fmt.Errorf("Gotify 配置缺少 appToken")
+	}
+
+	loc := localeFromConfig(config)
+	body := map[string]interface{}{
+		"title":    buildPushTitle(loc, agent, record),
+		"message":  n.buildMessage(loc, agent, record),
+		"priority": gotifyPriority(record),
+	}
+
+	return n.sendGotify(ctx, server, token, body)
+}
+
+// SendGotifyByConfig 导出方法供外部调用（测试用）
+func (n *Notifier) SendGotifyByConfig(ctx context.Context, config map[string]interface{}, message string) error {
+	agent, record := newTestAlert(message)
+	return n.sendGotifyByConfig(ctx, config, agent, record)
+}
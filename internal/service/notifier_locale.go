@@ -0,0 +1,164 @@
+package service
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/valyala/fasttemplate"
+	"gorm.io/datatypes"
+)
+
+// defaultNotificationLanguage 渠道未配置语言时使用的默认语言
+const defaultNotificationLanguage = "zh"
+
+// notificationTemplateFS 通知模板文件，每种语言一个 JSON 文件，文件名即语言代码
+//
+//go:embed templates/notification/*.json
+var notificationTemplateFS embed.FS
+
+// notificationLabels 通知中的字段名称
+type notificationLabels struct {
+	Agent       string `json:"agent"`
+	Host        string `json:"host"`
+	IP          string `json:"ip"`
+	AlertType   string `json:"alertType"`
+	Level       string `json:"level"`
+	Message     string `json:"message"`
+	Threshold   string `json:"threshold"`
+	Value       string `json:"value"`
+	FiredAt     string `json:"firedAt"`
+	ResolvedAt  string `json:"resolvedAt"`
+	ViewAgent   string `json:"viewAgent"`
+	EmailFooter string `json:"emailFooter"`
+}
+
+// notificationLocale 单个语言的通知模板
+type notificationLocale struct {
+	AlertTypes    map[string]string  `json:"alertTypes"`
+	Labels        notificationLabels `json:"labels"`
+	ResolvedTitle string             `json:"resolvedTitle"` // 恢复通知标题，占位符 {alertType}
+	Refired       string             `json:"refired"`       // 冷却期内再次触发的告警消息，占位符 {message}、{seconds}
+	// Messages 告警消息模板，键为告警记录的消息模板（默认为告警类型），占位符 {threshold}、{value} 以及记录中的消息参数；
+	// 未配置的模板使用告警记录中保存的消息
+	Messages map[string]string `json:"messages"`
+}
+
+var notificationLocales = loadNotificationLocales()
+
+// loadNotificationLocales 加载内置的通知模板
+func loadNotificationLocales() map[string]*notificationLocale {
+	entries, err := notificationTemplateFS.ReadDir("templates/notification")
+	if err != nil {
+		panic(fmt.Sprintf("读取通知模板失败: %v", err))
+	}
+
+	locales := make(map[string]*notificationLocale, len(entries))
+	for _, entry := range entries {
+		data, err := notificationTemplateFS.ReadFile(path.Join("templates/notification", entry.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("读取通知模板 %s 失败: %v", entry.Name(), err))
+		}
+		var locale notificationLocale
+		if err := json.Unmarshal(data, &locale); err != nil {
+			panic(fmt.Sprintf("解析通知模板 %s 失败: %v", entry.Name(), err))
+		}
+		locales[strings.TrimSuffix(entry.Name(), path.Ext(entry.Name()))] = &locale
+	}
+	if _, ok := locales[defaultNotificationLanguage]; !ok {
+		panic("缺少默认语言的通知模板")
+	}
+	return locales
+}
+
+// localeFromConfig 根据渠道配置中的 language 获取通知模板，未配置或不支持时使用默认语言
+func localeFromConfig(config map[string]interface{}) *notificationLocale {
+	if language, ok := config["language"].(string); ok {
+		if locale, ok := notificationLocales[strings.ToLower(strings.TrimSpace(language))]; ok {
+			return locale
+		}
+	}
+	return notificationLocales[defaultNotificationLanguage]
+}
+
+// alertTypeName 获取告警类型名称，未知类型返回原始类型
+func (l *notificationLocale) alertTypeName(alertType string) string {
+	if name := l.AlertTypes[alertType]; name != "" {
+		return name
+	}
+	return alertType
+}
+
+// resolvedTitle 构建恢复通知标题，如：CPU告警已恢复
+func (l *notificationLocale) resolvedTitle(alertType string) string {
+	return fasttemplate.ExecuteString(l.ResolvedTitle, "{", "}", map[string]interface{}{
+		"alertType": l.alertTypeName(alertType),
+	})
+}
+
+// alertMessage 获取告警消息，冷却期内再次触发的告警注明距上次恢复的时间
+func (l *notificationLocale) alertMessage(record *models.AlertRecord) string {
+	message := l.baseAlertMessage(record)
+	if record.RefiredAfter <= 0 {
+		return message
+	}
+	return fasttemplate.ExecuteString(l.Refired, "{", "}", map[string]interface{}{
+		"message": message,
+		"seconds": strconv.FormatInt(record.RefiredAfter, 10),
+	})
+}
+
+// baseAlertMessage 当前语言配置了消息模板且参数齐全时按模板重新生成告警消息，否则使用记录中保存的消息
+func (l *notificationLocale) baseAlertMessage(record *models.AlertRecord) string {
+	key := record.MessageKey
+	if key == "" {
+		key = record.AlertType
+	}
+	tpl := l.Messages[key]
+	if tpl == "" {
+		return record.Message
+	}
+
+	params := map[string]string{
+		"threshold": formatNotificationValue(record.Threshold),
+		"value":     formatNotificationValue(record.ActualValue),
+	}
+	for k, v := range record.MessageParams.Data() {
+		params[k] = v
+	}
+	missing := false
+	message := fasttemplate.ExecuteFuncString(tpl, "{", "}", func(w io.Writer, tag string) (int, error) {
+		v, ok := params[tag]
+		if !ok {
+			missing = true
+		}
+		return io.WriteString(w, v)
+	})
+	// 旧记录没有保存消息参数
+	if missing {
+		return record.Message
+	}
+	return message
+}
+
+// setAlertMessage 设置告警记录的消息模板和参数，并按默认语言生成记录中保存的消息
+// 需要在设置阈值和实际值之后调用
+func setAlertMessage(record *models.AlertRecord, key string, params map[string]string) {
+	if key != record.AlertType {
+		record.MessageKey = key
+	}
+	record.MessageParams = datatypes.NewJSONType(params)
+	record.Message = notificationLocales[defaultNotificationLanguage].baseAlertMessage(record)
+}
+
+// formatNotificationValue 格式化数值，保留两位小数并去掉末尾的 0
+func formatNotificationValue(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 2, 64)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+// TestNotificationLocaleKeys 检查每种语言都包含默认语言中的告警类型名称和消息模板
+func TestNotificationLocaleKeys(t *testing.T) {
+	base := notificationLocales[defaultNotificationLanguage]
+	for language, locale := range notificationLocales {
+		for alertType := range base.AlertTypes {
+			if locale.AlertTypes[alertType] == "" {
+				t.Errorf("%s 缺少告警类型 %s 的名称", language, alertType)
+			}
+		}
+		for key := range base.Messages {
+			if locale.Messages[key] == "" {
+				t.Errorf("%s 缺少消息模板 %s", language, key)
+			}
+		}
+	}
+}
+
+func TestAlertMessage(t *testing.T) {
+	record := &models.AlertRecord{AlertType: "cpu", Threshold: 80, ActualValue: 92.5}
+	setAlertMessage(record, "cpu", map[string]string{"duration": "60"})
+	if record.Message != "CPU使用率持续60秒超过80%，当前值92.5%" {
+		t.Fatalf("默认语言消息 = %q", record.Message)
+	}
+	if got := notificationLocales["en"].alertMessage(record); got != "CPU usage stayed above 80% for 60s, current value 92.5%" {
+		t.Fatalf("英文消息 = %q", got)
+	}
+
+	// 旧记录没有保存消息参数时使用记录中的消息
+	legacy := &models.AlertRecord{AlertType: "cpu", Message: "CPU使用率过高"}
+	if got := notificationLocales["en"].alertMessage(legacy); got != "CPU使用率过高" {
+		t.Fatalf("缺少参数时应使用记录中的消息, got %q", got)
+	}
+}
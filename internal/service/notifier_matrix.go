@@ -0,0 +1,141 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"go.uber.org/zap"
+)
+
+// buildMatrixHTML 构建 Matrix 消息的 HTML 正文（Element 等客户端按 org.matrix.custom.html 渲染）
+func buildMatrixHTML(loc *notificationLocale, agent *models.Agent, record *models.AlertRecord) string {
+	label := loc.Labels
+	rows := [][2]string{
+		{label.Agent, fmt.Sprintf("%s (%s)", agent.Name, agent.ID)},
+		{label.Host, agent.Hostname},
+		{label.IP, agent.IP},
+	}
+	if record.Status == "resolved" {
+		rows = append(rows,
+			[2]string{label.Value, fmt.Sprintf("%.2f", record.ActualValue)},
+			[2]string{label.ResolvedAt, time.UnixMilli(record.ResolvedAt).Format("2006-01-02 15:04:05")},
+		)
+	} else {
+		rows = append(rows,
+			[2]string{label.Level, record.Level},
+			[2]string{label.Message, loc.alertMessage(record)},
+			[2]string{label.Threshold, fmt.Sprintf("%.2f", record.Threshold)},
+			[2]string{label.Value, fmt.Sprintf("%.2f", record.ActualValue)},
+			[2]string{label.FiredAt, time.UnixMilli(record.FiredAt).Format("2006-01-02 15:04:05")},
+		)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<h4>")
+	if record.Status == "resolved" {
+		sb.WriteString("✅ ")
+	} else {
+		switch record.Level {
+		case "warning":
+			sb.WriteString("⚠️ ")
+		case "critical":
+			sb.WriteString("🚨 ")
+		default:
+			sb.WriteString("ℹ️ ")
+		}
+	}
+	sb.WriteString(html.EscapeString(buildPushTitle(loc, agent, record)))
+	sb.WriteString("</h4><ul>")
+	for _, row := range rows {
+		sb.WriteString(fmt.Sprintf("<li><b>%s</b>: %s</li>", html.EscapeString(row[0]), html.EscapeString(row[1])))
+	}
+	sb.WriteString("</ul>")
+	return sb.String()
+}
+
+// sendMatrix 向 Matrix 房间发送消息
+func (n *Notifier) sendMatrix(ctx context.Context, homeserver, accessToken, roomID, txnID string, body map[string]interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("序列化请求体失败: %w", err)
+	}
+
+	// 房间 ID 形如 !abc:example.com，需要转义后放入路径
+	endpoint := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		homeserver, url.PathEscape(roomID), url.PathEscape(txnID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("发送请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	recordProviderResponse(ctx, resp, respBody)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Matrix 请求失败，状态码: %d, 响应: %s", resp.StatusCode, string(respBody))
+	}
+
+	n.logger.Info("Matrix 通知发送成功", zap.String("homeserver", homeserver), zap.String("roomId", roomID))
+	return nil
+}
+
+// sendMatrixByConfig 根据配置发送 Matrix 通知
+func (n *Notifier) sendMatrixByConfig(ctx context.Context, config map[string]interface{}, agent *models.Agent, record *models.AlertRecord) error {
+	homeserver, _ := config["homeserverUrl"].(string)
+	homeserver = strings.TrimRight(strings.TrimSpace(homeserver), "/")
+	if homeserver == "" {
+		return fmt.Errorf("Matrix 配置缺少 homeserverUrl")
+	}
+	accessToken, _ := config["accessToken"].(string)
+	accessToken = strings.TrimSpace(accessToken)
+	if accessToken == "" {
+		return fmt.Errorf("Matrix 配置缺少 accessToken")
+	}
+	roomID, _ := config["roomId"].(string)
+	roomID = strings.TrimSpace(roomID)
+	if roomID == "" {
+		return fmt.Errorf("Matrix 配置缺少 roomId")
+	}
+
+	// 告警使用 m.text 触发客户端提醒，恢复通知使用 m.notice 降低打扰
+	msgType := "m.text"
+	if record.Status == "resolved" {
+		msgType = "m.notice"
+	}
+	loc := localeFromConfig(config)
+	body := map[string]interface{}{
+		"msgtype":        msgType,
+		"body":           n.buildMessage(loc, agent, record),
+		"format":         "org.matrix.custom.html",
+		"formatted_body": buildMatrixHTML(loc, agent, record),
+	}
+
+	// 事务 ID 用于服务端去重，每次发送需唯一
+	txnID := fmt.Sprintf("pika-%d-%s-%d", record.ID, record.Status, time.Now().UnixNano())
+	return n.sendMatrix(ctx, homeserver, accessToken, roomID, txnID, body)
+}
+
+// SendMatrixByConfig 导出方法供外部调用（测试用）
+func (n *Notifier) SendMatrixByConfig(ctx context.Context, config map[string]interface{}, message string) error {
+	agent, record := newTestAlert(message)
+	return n.sendMatrixByConfig(ctx, config, agent, record)
+}
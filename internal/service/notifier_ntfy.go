@@ -0,0 +1,114 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"go.uber.org/zap"
+)
+
+// defaultNtfyServer ntfy 官方服务地址
+const defaultNtfyServer = "https://ntfy.sh"
+
+// ntfyPriority 根据告警级别和状态映射 ntfy 消息优先级（1-5）
+func ntfyPriority(record *models.AlertRecord) int {
+	if record.Status == "resolved" {
+		return 3
+	}
+	switch record.Level {
+	case "warning":
+		return 4
+	case "critical":
+		return 5
+	}
+	return 3
+}
+
+// ntfyTags 根据告警级别和状态选择 ntfy 标签，标签名为 emoji 短码时会显示为图标
+func ntfyTags(record *models.AlertRecord) []string {
+	if record.Status == "resolved" {
+		return []string{"white_check_mark", "pika"}
+	}
+	switch record.Level {
+	case "warning":
+		return []string{"warning", "pika"}
+	case "critical":
+		return []string{"rotating_light", "pika"}
+	}
+	return []string{"information_source", "pika"}
+}
+
+// sendNtfy 以 JSON 方式发布 ntfy 消息，避免中文标题放在请求头中的编码问题
+func (n *Notifier) sendNtfy(ctx context.Context, server, token string, body map[string]interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("序列化请求体失败: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("发送请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	recordProviderResponse(ctx, resp, respBody)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("ntfy 请求失败，状态码: %d, 响应: %s", resp.StatusCode, string(respBody))
+	}
+
+	n.logger.Info("ntfy 通知发送成功", zap.String("server", server), zap.Any("topic", body["topic"]))
+	return nil
+}
+
+// sendNtfyByConfig 根据配置发送 ntfy 通知
+func (n *Notifier) sendNtfyByConfig(ctx context.Context, config map[string]interface{}, agent *models.Agent, record *models.AlertRecord) error {
+	topic, _ := config["topic"].(string)
+	topic = strings.TrimSpace(topic)
+	if topic == "" {
+		return fmt.Errorf("ntfy 配置缺少 topic")
+	}
+
+	server, _ := config["serverUrl"].(string)
+	server = strings.TrimRight(strings.TrimSpace(server), "/")
+	if server == "" {
+		server = defaultNtfyServer
+	}
+	token, _ := config["token"].(string)
+
+	loc := localeFromConfig(config)
+	body := map[string]interface{}{
+		"topic":    topic,
+		"title":    buildPushTitle(loc, agent, record),
+		"message":  n.buildMessage(loc, agent, record),
+		"priority": ntfyPriority(record),
+		"tags":     ntfyTags(record),
+	}
+
+	return n.sendNtfy(ctx, server, strings.TrimSpace(token), body)
+}
+
+// SendNtfyByConfig 导出方法供外部调用（测试用）
+func (n *Notifier) SendNtfyByConfig(ctx context.Context, config map[string]interface{}, message string) error {
+	agent, record := newTestAlert(message)
+	return n.sendNtfyByConfig(ctx, config, agent, record)
+}
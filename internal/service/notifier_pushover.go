@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"go.uber.org/zap"
+)
+
+const (
+	pushoverAPI = "https://api.pushover.net/1"
+
+	// pushoverPriorityEmergency 紧急优先级，用户确认前会按 retry 间隔重复提醒
+	pushoverPriorityEmergency = 2
+	// 紧急通知默认每 60 秒重复一次，持续 1 小时（Pushover 限制 retry >= 30，expire <= 10800）
+	pushoverDefaultRetry  = 60
+	pushoverDefaultExpire = 3600
+)
+
+// pushoverPriority 根据告警级别和状态映射 Pushover 优先级，严重告警使用紧急优先级
+func pushoverPriority(record *models.AlertRecord) int {
+	if record.Status == "resolved" {
+		return 0
+	}
+	switch record.Level {
+	case "warning":
+		return 1
+	case "critical":
+		return pushoverPriorityEmergency
+	}
+	return 0
+}
+
+type pushoverResult struct {
+	Status  int      `json:"status"`
+	Receipt string   `json:"receipt"`
+	Errors  []string `json:"errors"`
+}
+
+// postPushover 以表单方式调用 Pushover 接口
+func (n *Notifier) postPushover(ctx context.Context, endpoint string, form url.Values) (*pushoverResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("发送请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	recordProviderResponse(ctx, resp, respBody)
+	var result pushoverResult
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("Pushover 响应解析失败，状态码: %d, 响应: %s", resp.StatusCode, string(respBody))
+	}
+	if result.Status != 1 {
+		return nil, fmt.Errorf("Pushover 请求失败: %s", strings.Join(result.Errors, "; "))
+	}
+	return &result, nil
+}
+
+// sendPushoverByConfig 根据配置发送 Pushover 通知
+// 严重告警以紧急优先级发送并记录回执，告警恢复时取消尚未确认的重复提醒
+func (n *Notifier) sendPushoverByConfig(ctx context.Context, config map[string]interface{}, agent *models.Agent, record *models.AlertRecord) error {
+	appToken, _ := config["appToken"].(string)
+	appToken = strings.TrimSpace(appToken)
+	if appToken == "" {
+		return fmt.Errorf("Pushover 配置缺少 appToken")
+	}
+	userKey, _ := config["userKey"].(string)
+	userKey = strings.TrimSpace(userKey)
+	if userKey == "" {
+		return fmt.Errorf("Pushover 配置缺少 userKey")
+	}
+
+	receiptKey := fmt.Sprintf("%s:%d", userKey, record.ID)
+	if record.Status == "resolved" && record.ID > 0 {
+		if receipt, ok := n.pushoverReceipts.Get(receiptKey); ok {
+			n.pushoverReceipts.Delete(receiptKey)
+			cancelURL := fmt.Sprintf("%s/receipts/%s/cancel.json", pushoverAPI, receipt)
+			if _, err := n.postPushover(ctx, cancelURL, url.Values{"token": {appToken}}); err != nil {
+				n.logger.Warn("取消 Pushover 紧急通知失败", zap.Error(err))
+			}
+		}
+	}
+
+	loc := localeFromConfig(config)
+	priority := pushoverPriority(record)
+	form := url.Values{
+		"token":    {appToken},
+		"user":     {userKey},
+		"title":    {buildPushTitle(loc, agent, record)},
+		"message":  {n.buildMessage(loc, agent, record)},
+		"priority": {strconv.Itoa(priority)},
+	}
+	if device, _ := config["device"].(string); device != "" {
+		form.Set("device", device)
+	}
+	expire := parsePositiveInt(config["expire"], pushoverDefaultExpire)
+	if priority == pushoverPriorityEmergency {
+		form.Set("retry", strconv.Itoa(parsePositiveInt(config["retry"], pushoverDefaultRetry)))
+		form.Set("expire", strconv.Itoa(expire))
+	}
+
+	result, err := n.postPushover(ctx, pushoverAPI+"/messages.json", form)
+	if err != nil {
+		return err
+	}
+
+	if result.Receipt != "" && record.Status == "firing" && record.ID > 0 {
+		n.pushoverReceipts.Set(receiptKey, result.Receipt, time.Duration(expire)*time.Second)
+	}
+	n.logger.Info("Pushover 通知发送成功", zap.Int("priority", priority))
+	return nil
+}
+
+// parsePositiveInt 解析配置中的正整数，支持数字或字符串，无效时返回默认值
+func parsePositiveInt(value interface{}, defaultValue int) int {
+	var v int
+	switch val := value.(type) {
+	case float64:
+		v = int(val)
+	case string:
+		v, _ = strconv.Atoi(val)
+	}
+	if v <= 0 {
+		return defaultValue
+	}
+	return v
+}
+
+// SendPushoverByConfig 导出方法供外部调用（测试用）
+func (n *Notifier) SendPushoverByConfig(ctx context.Context, config map[string]interface{}, message string) error {
+	agent, record := newTestAlert(message)
+	return n.sendPushoverByConfig(ctx, config, agent, record)
+}
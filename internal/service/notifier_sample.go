@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+// maxProviderResponseBody 记录的服务商响应体最大长度
+const maxProviderResponseBody = 4096
+
+// ProviderResponse 通知服务商返回的响应
+type ProviderResponse struct {
+	StatusCode int    `json:"statusCode"` // HTTP 状态码
+	Body       string `json:"body"`       // 响应体，超长时截断
+}
+
+// SampleNotificationResult 示例告警的发送结果
+type SampleNotificationResult struct {
+	ChannelType string             `json:"channelType"`     // 渠道类型
+	Success     bool               `json:"success"`         // 是否发送成功
+	Error       string             `json:"error,omitempty"` // 失败原因
+	LatencyMs   int64              `json:"latencyMs"`       // 发送耗时(毫秒)
+	Responses   []ProviderResponse `json:"responses"`       // 服务商响应，一次发送可能包含多个请求（如短信多个接收号码）
+}
+
+type providerResponseKey struct{}
+
+// providerResponseRecorder 收集一次发送过程中服务商返回的响应
+type providerResponseRecorder struct {
+	mu        sync.Mutex
+	responses []ProviderResponse
+}
+
+// recordProviderResponse 记录服务商响应，ctx 中没有记录器时忽略
+func recordProviderResponse(ctx context.Context, resp *http.Response, body []byte) {
+	recorder, ok := ctx.Value(providerResponseKey{}).(*providerResponseRecorder)
+	if !ok {
+		return
+	}
+	if len(body) > maxProviderResponseBody {
+		body = body[:maxProviderResponseBody]
+	}
+	recorder.mu.Lock()
+	recorder.responses = append(recorder.responses, ProviderResponse{
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+	})
+	recorder.mu.Unlock()
+}
+
+// SendSampleNotification 通过指定渠道发送一条示例告警，返回服务商的响应，用于验证渠道配置
+func (n *Notifier) SendSampleNotification(ctx context.Context, channel *models.NotificationChannelConfig) *SampleNotificationResult {
+	recorder := &providerResponseRecorder{}
+	ctx = context.WithValue(ctx, providerResponseKey{}, recorder)
+
+	agent, record := newSampleAlert()
+	start := time.Now()
+	err := n.SendNotificationByConfig(ctx, channel, record, agent)
+
+	result := &SampleNotificationResult{
+		ChannelType: channel.Type,
+		Success:     err == nil,
+		LatencyMs:   time.Since(start).Milliseconds(),
+		Responses:   recorder.responses,
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	if result.Responses == nil {
+		result.Responses = []ProviderResponse{}
+	}
+	return result
+}
+
+// newSampleAlert 构造示例告警，内容与真实的 CPU 告警一致，便于检查消息模板的展示效果
+func newSampleAlert() (*models.Agent, *models.AlertRecord) {
+	agent, record := newTestAlert("这是一条测试告警：CPU 使用率 92.50% 超过阈值 80.00%")
+	record.AlertType = "cpu"
+	record.Level = "warning"
+	record.Threshold = 80
+	record.ActualValue = 92.5
+	return agent, record
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"go.uber.org/zap"
+)
+
+const twilioAPI = "https://api.twilio.com/2010-04-01"
+
+// twilioDefaultLevels 短信按条计费且会打扰值班人员，默认只发送严重告警
+var twilioDefaultLevels = []string{"critical"}
+
+// twilioConfig Twilio 短信通知配置
+type twilioConfig struct {
+	AccountSID string
+	AuthToken  string
+	From       string
+	To         []string
+	Levels     []string // 需要发送短信的告警级别
+}
+
+// parseTwilioConfig 解析 Twilio 短信通知配置
+func parseTwilioConfig(config map[string]interface{}) (*twilioConfig, error) {
+	cfg := &twilioConfig{}
+	cfg.AccountSID, _ = config["accountSid"].(string)
+	cfg.AccountSID = strings.TrimSpace(cfg.AccountSID)
+	if cfg.AccountSID == "" {
+		return nil, fmt.Errorf("Twilio 配置缺少 accountSid")
+	}
+	cfg.AuthToken, _ = config["authToken"].(string)
+	cfg.AuthToken = strings.TrimSpace(cfg.AuthToken)
+	if cfg.AuthToken == "" {
+		return nil, fmt.Errorf("Twilio 配置缺少 authToken")
+	}
+	cfg.From, _ = config["from"].(string)
+	cfg.From = strings.TrimSpace(cfg.From)
+	if cfg.From == "" {
+		return nil, fmt.Errorf("Twilio 配置缺少发送号码 from")
+	}
+	cfg.To = parseStringList(config["to"])
+	if len(cfg.To) == 0 {
+		return nil, fmt.Errorf("Twilio 配置缺少接收号码 to")
+	}
+	cfg.Levels = parseStringList(config["levels"])
+	if len(cfg.Levels) == 0 {
+		cfg.Levels = twilioDefaultLevels
+	}
+	return cfg, nil
+}
+
+// acceptLevel 判断告警级别是否需要发送短信，恢复通知跟随原告警级别
+func (c *twilioConfig) acceptLevel(level string) bool {
+	for _, l := range c.Levels {
+		if strings.EqualFold(l, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildSMSMessage 构建短信正文，不含图标并尽量精简以减少分段计费
+func buildSMSMessage(loc *notificationLocale, agent *models.Agent, record *models.AlertRecord) string {
+	if record.Status == "resolved" {
+		return fmt.Sprintf("[Pika] %s\n%s: %.2f%%\n%s: %s",
+			buildPushTitle(loc, agent, record),
+			loc.Labels.Value, record.ActualValue,
+			loc.Labels.ResolvedAt,
+			time.UnixMilli(record.ResolvedAt).Format("01-02 15:04"),
+		)
+	}
+	return fmt.Sprintf("[Pika] %s\n%s\n%s: %.2f%% %s: %.2f%%\n%s: %s\n%s: %s",
+		buildPushTitle(loc, agent, record),
+		loc.alertMessage(record),
+		loc.Labels.Value, record.ActualValue,
+		loc.Labels.Threshold, record.Threshold,
+		loc.Labels.IP, agent.IP,
+		loc.Labels.FiredAt,
+		time.UnixMilli(record.FiredAt).Format("01-02 15:04"),
+	)
+}
+
+// sendTwilioSMS 调用 Twilio Messages 接口向单个号码发送短信
+func (n *Notifier) sendTwilioSMS(ctx context.Context, cfg *twilioConfig, to, body string) error {
+	endpoint := fmt.Sprintf("%s/Accounts/%s/Messages.json", twilioAPI, url.PathEscape(cfg.AccountSID))
+	form := url.Values{
+		"From": {cfg.From},
+		"To":   {to},
+		"Body": {body},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(cfg.AccountSID, cfg.AuthToken)
+
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("发送请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	recordProviderResponse(ctx, resp, respBody)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var result struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(respBody, &result) == nil && result.Message != "" {
+			return fmt.Errorf("Twilio 发送短信到 %s 失败: %s (code: %d)", to, result.Message, result.Code)
+		}
+		return fmt.Errorf("Twilio 发送短信到 %s 失败，状态码: %d, 响应: %s", to, resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+// sendTwilio 向所有接收号码发送短信，单个号码失败不影响其它号码
+func (n *Notifier) sendTwilio(ctx context.Context, cfg *twilioConfig, body string) error {
+	var errs []error
+	for _, to := range cfg.To {
+		if err := n.sendTwilioSMS(ctx, cfg, to, body); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	n.logger.Info("Twilio 短信发送成功", zap.Int("recipients", len(cfg.To)))
+	return nil
+}
+
+// sendTwilioByConfig 根据配置发送 Twilio 短信，未在 levels 中的告警级别直接跳过
+func (n *Notifier) sendTwilioByConfig(ctx context.Context, config map[string]interface{}, agent *models.Agent, record *models.AlertRecord) error {
+	cfg, err := parseTwilioConfig(config)
+	if err != nil {
+		return err
+	}
+	if !cfg.acceptLevel(record.Level) {
+		n.logger.Debug("告警级别未开启短信通知，跳过", zap.String("level", record.Level), zap.Strings("levels", cfg.Levels))
+		return nil
+	}
+	return n.sendTwilio(ctx, cfg, buildSMSMessage(localeFromConfig(config), agent, record))
+}
+
+// SendTwilioByConfig 导出方法供外部调用（测试用），测试消息不受告警级别限制
+func (n *Notifier) SendTwilioByConfig(ctx context.Context, config map[string]interface{}, message string) error {
+	cfg, err := parseTwilioConfig(config)
+	if err != nil {
+		return err
+	}
+	agent, record := newTestAlert(message)
+	return n.sendTwilio(ctx, cfg, buildSMSMessage(localeFromConfig(config), agent, record))
+}
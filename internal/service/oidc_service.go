@@ -6,6 +6,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
@@ -16,33 +17,50 @@ import (
 
 // OIDCService OIDC 认证服务
 type OIDCService struct {
-	logger       *zap.Logger
+	logger     *zap.Logger
+	mu         sync.Mutex
+	client     *oidcClient          // 未启用或配置无效时为 nil
+	stateStore map[string]time.Time // 简单的 state 存储（生产环境应使用 Redis 等）
+}
+
+// oidcClient 根据配置初始化的 OIDC 客户端，重新加载配置时整体替换
+type oidcClient struct {
 	config       *config.OIDCConfig
 	provider     *oidc.Provider
 	oauth2Config oauth2.Config
 	verifier     *oidc.IDTokenVerifier
-	stateStore   map[string]time.Time // 简单的 state 存储（生产环境应使用 Redis 等）
 }
 
 // NewOIDCService 创建 OIDC 服务
 func NewOIDCService(logger *zap.Logger, appConfig *config.AppConfig) *OIDCService {
-	if appConfig.OIDC == nil || !appConfig.OIDC.Enabled {
-		logger.Info("OIDC 认证未启用")
-		return &OIDCService{
-			logger: logger,
-			config: nil,
-		}
+	s := &OIDCService{
+		logger:     logger.Named("auth"),
+		stateStore: make(map[string]time.Time),
 	}
+	s.client = s.newClient(appConfig.OIDC)
+	return s
+}
 
-	oidcConfig := appConfig.OIDC
+// Reload 使用新配置重新初始化 OIDC 客户端，进行中的登录流程的 state 保持有效
+func (s *OIDCService) Reload(oidcConfig *config.OIDCConfig) {
+	client := s.newClient(oidcConfig)
+
+	s.mu.Lock()
+	s.client = client
+	s.mu.Unlock()
+}
+
+// newClient 初始化 OIDC 客户端，未启用或配置无效时返回 nil
+func (s *OIDCService) newClient(oidcConfig *config.OIDCConfig) *oidcClient {
+	if oidcConfig == nil || !oidcConfig.Enabled {
+		s.logger.Info("OIDC 认证未启用")
+		return nil
+	}
 
 	// 验证配置
 	if oidcConfig.Issuer == "" || oidcConfig.ClientID == "" || oidcConfig.ClientSecret == "" {
-		logger.Error("OIDC 配置不完整，OIDC 认证将被禁用")
-		return &OIDCService{
-			logger: logger,
-			config: nil,
-		}
+		s.logger.Error("OIDC 配置不完整，OIDC 认证将被禁用")
+		return nil
 	}
 
 	ctx := context.Background()
@@ -50,11 +68,8 @@ func NewOIDCService(logger *zap.Logger, appConfig *config.AppConfig) *OIDCServic
 	// 初始化 OIDC Provider
 	provider, err := oidc.NewProvider(ctx, oidcConfig.Issuer)
 	if err != nil {
-		logger.Error("初始化 OIDC Provider 失败，OIDC 认证将被禁用", zap.Error(err))
-		return &OIDCService{
-			logger: logger,
-			config: nil,
-		}
+		s.logger.Error("初始化 OIDC Provider 失败，OIDC 认证将被禁用", zap.Error(err))
+		return nil
 	}
 
 	// 配置 OAuth2
@@ -69,26 +84,32 @@ func NewOIDCService(logger *zap.Logger, appConfig *config.AppConfig) *OIDCServic
 	// 创建 ID Token 验证器
 	verifier := provider.Verifier(&oidc.Config{ClientID: oidcConfig.ClientID})
 
-	logger.Info("OIDC 服务初始化成功", zap.String("issuer", oidcConfig.Issuer))
+	s.logger.Info("OIDC 服务初始化成功", zap.String("issuer", oidcConfig.Issuer))
 
-	return &OIDCService{
-		logger:       logger,
+	return &oidcClient{
 		config:       oidcConfig,
 		provider:     provider,
 		oauth2Config: oauth2Config,
 		verifier:     verifier,
-		stateStore:   make(map[string]time.Time),
 	}
 }
 
+// getClient 获取当前的 OIDC 客户端
+func (s *OIDCService) getClient() *oidcClient {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.client
+}
+
 // IsEnabled 检查 OIDC 是否启用
 func (s *OIDCService) IsEnabled() bool {
-	return s.config != nil && s.config.Enabled
+	return s.getClient() != nil
 }
 
 // GenerateAuthURL 生成认证 URL
 func (s *OIDCService) GenerateAuthURL() (string, string, error) {
-	if !s.IsEnabled() {
+	client := s.getClient()
+	if client == nil {
 		return "", "", errors.New("OIDC 未启用")
 	}
 
@@ -98,32 +119,32 @@ func (s *OIDCService) GenerateAuthURL() (string, string, error) {
 		return "", "", fmt.Errorf("生成 state 失败: %w", err)
 	}
 
+	s.mu.Lock()
 	// 存储 state（有效期 10 分钟）
 	s.stateStore[state] = time.Now().Add(10 * time.Minute)
 
 	// 清理过期的 state
 	s.cleanExpiredStates()
+	s.mu.Unlock()
 
-	authURL := s.oauth2Config.AuthCodeURL(state)
+	authURL := client.oauth2Config.AuthCodeURL(state)
 	return authURL, state, nil
 }
 
 // ExchangeCode 交换授权码获取 token 和用户信息
 func (s *OIDCService) ExchangeCode(ctx context.Context, code, state string) (string, string, error) {
-	if !s.IsEnabled() {
+	client := s.getClient()
+	if client == nil {
 		return "", "", errors.New("OIDC 未启用")
 	}
 
 	// 验证 state
-	if !s.validateState(state) {
+	if !s.consumeState(state) {
 		return "", "", errors.New("无效的 state")
 	}
 
-	// 删除已使用的 state
-	delete(s.stateStore, state)
-
 	// 交换授权码
-	oauth2Token, err := s.oauth2Config.Exchange(ctx, code)
+	oauth2Token, err := client.oauth2Config.Exchange(ctx, code)
 	if err != nil {
 		return "", "", fmt.Errorf("交换授权码失败: %w", err)
 	}
@@ -135,7 +156,7 @@ func (s *OIDCService) ExchangeCode(ctx context.Context, code, state string) (str
 	}
 
 	// 验证 ID Token
-	idToken, err := s.verifier.Verify(ctx, rawIDToken)
+	idToken, err := client.verifier.Verify(ctx, rawIDToken)
 	if err != nil {
 		return "", "", fmt.Errorf("验证 ID Token 失败: %w", err)
 	}
@@ -183,16 +204,20 @@ func (s *OIDCService) generateState() (string, error) {
 	return base64.URLEncoding.EncodeToString(b), nil
 }
 
-// validateState 验证 state
-func (s *OIDCService) validateState(state string) bool {
+// consumeState 验证 state 并删除已使用的 state
+func (s *OIDCService) consumeState(state string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	expiresAt, exists := s.stateStore[state]
 	if !exists {
 		return false
 	}
+	delete(s.stateStore, state)
 	return time.Now().Before(expiresAt)
 }
 
-// cleanExpiredStates 清理过期的 state
+// cleanExpiredStates 清理过期的 state，调用方需持有锁
 func (s *OIDCService) cleanExpiredStates() {
 	now := time.Now()
 	for state, expiresAt := range s.stateStore {
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+
+	"github.com/dushixiang/pika/internal/repo"
+	"github.com/dushixiang/pika/pkg/tracing"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// 未配置证书告警阈值时，统计即将过期证书使用的默认天数
+const defaultCertExpiringDays = 30
+
+// OverviewService 全局概览服务
+type OverviewService struct {
+	logger           *zap.Logger
+	agentService     *AgentService
+	metricService    *MetricService
+	propertyService  *PropertyService
+	alertRecordRepo  *repo.AlertRecordRepo
+	monitorStatsRepo *repo.MonitorStatsRepo
+}
+
+func NewOverviewService(logger *zap.Logger, db *gorm.DB, agentService *AgentService, metricService *MetricService, propertyService *PropertyService) *OverviewService {
+	return &OverviewService{
+		logger:           logger.Named("overview"),
+		agentService:     agentService,
+		metricService:    metricService,
+		propertyService:  propertyService,
+		alertRecordRepo:  repo.NewAlertRecordRepo(db),
+		monitorStatsRepo: repo.NewMonitorStatsRepo(db),
+	}
+}
+
+// FleetOverview 全局概览数据
+type FleetOverview struct {
+	Agents       AgentOverview    `json:"agents"`
+	Traffic      TrafficOverview  `json:"traffic"`
+	AvgCPU       float64          `json:"avgCpu"`       // 在线探针平均 CPU 使用率
+	AvgMemory    float64          `json:"avgMemory"`    // 在线探针平均内存使用率
+	ActiveAlerts map[string]int64 `json:"activeAlerts"` // 告警中的记录数量（按级别）
+	ExpiringCert int64            `json:"expiringCert"` // 即将过期的证书数量
+	CertDays     int              `json:"certDays"`     // 即将过期的判定天数
+}
+
+// AgentOverview 探针数量概览
+type AgentOverview struct {
+	Total   int `json:"total"`
+	Online  int `json:"online"`
+	Offline int `json:"offline"`
+}
+
+// TrafficOverview 流量概览
+type TrafficOverview struct {
+	SentRate  uint64 `json:"sentRate"`  // 总发送速率(字节/秒)
+	RecvRate  uint64 `json:"recvRate"`  // 总接收速率(字节/秒)
+	SentTotal uint64 `json:"sentTotal"` // 累计发送流量(字节)
+	RecvTotal uint64 `json:"recvTotal"` // 累计接收流量(字节)
+}
+
+// GetOverview 获取全局概览（已登录统计全部探针，未登录只统计公开探针）
+func (s *OverviewService) GetOverview(ctx context.Context, isAuthenticated bool) (*FleetOverview, error) {
+	ctx, span := tracing.Start(ctx, "OverviewService.GetOverview", tracing.WithAttributes(
+		tracing.Bool("auth.authenticated", isAuthenticated),
+	))
+	defer span.End()
+
+	agents, err := s.agentService.ListByAuth(ctx, isAuthenticated)
+	if err != nil {
+		return nil, err
+	}
+
+	overview := &FleetOverview{
+		ActiveAlerts: map[string]int64{
+			"info":     0,
+			"warning":  0,
+			"critical": 0,
+		},
+		CertDays: defaultCertExpiringDays,
+	}
+
+	agentIDs := make([]string, 0, len(agents))
+	var cpuSum, memorySum float64
+	var cpuCount, memoryCount int
+	for _, agent := range agents {
+		agentIDs = append(agentIDs, agent.ID)
+		overview.Agents.Total++
+		if agent.Status != 1 {
+			overview.Agents.Offline++
+			continue
+		}
+		overview.Agents.Online++
+
+		// 最新指标直接取自内存缓存，避免查询指标表
+		latest, _ := s.metricService.GetLatestMetrics(ctx, agent.ID)
+		if latest == nil {
+			continue
+		}
+		if latest.CPU != nil {
+			cpuSum += latest.CPU.UsagePercent
+			cpuCount++
+		}
+		if latest.Memory != nil {
+			memorySum += latest.Memory.UsagePercent
+			memoryCount++
+		}
+		if latest.Network != nil {
+			overview.Traffic.SentRate += latest.Network.TotalBytesSentRate
+			overview.Traffic.RecvRate += latest.Network.TotalBytesRecvRate
+			overview.Traffic.SentTotal += latest.Network.TotalBytesSentTotal
+			overview.Traffic.RecvTotal += latest.Network.TotalBytesRecvTotal
+		}
+	}
+	if cpuCount > 0 {
+		overview.AvgCPU = cpuSum / float64(cpuCount)
+	}
+	if memoryCount > 0 {
+		overview.AvgMemory = memorySum / float64(memoryCount)
+	}
+
+	if len(agentIDs) == 0 {
+		return overview, nil
+	}
+
+	alertCounts, err := s.alertRecordRepo.CountFiringByLevel(ctx, agentIDs)
+	if err != nil {
+		return nil, err
+	}
+	for level, count := range alertCounts {
+		overview.ActiveAlerts[level] = count
+	}
+
+	// 证书过期天数优先使用告警配置中的阈值
+	alertConfig, err := s.propertyService.GetAlertConfig(ctx)
+	if err != nil {
+		s.logger.Warn("获取告警配置失败，使用默认证书过期天数", zap.Error(err))
+	} else if alertConfig.Rules.CertThreshold > 0 {
+		overview.CertDays = int(alertConfig.Rules.CertThreshold)
+	}
+
+	expiring, err := s.monitorStatsRepo.CountExpiringCerts(ctx, agentIDs, overview.CertDays)
+	if err != nil {
+		return nil, err
+	}
+	overview.ExpiringCert = expiring
+
+	return overview, nil
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/go-orz/orz"
+	"go.uber.org/zap"
+)
+
+const (
+	// meshTargetPrefix 延迟网格目标ID前缀，后接对端探针ID
+	meshTargetPrefix = "mesh:"
+	// defaultMeshPort TCP 探测的默认端口
+	defaultMeshPort = 22
+)
+
+// LatencyMeshAgent 延迟网格成员
+type LatencyMeshAgent struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	IP     string `json:"ip"`
+	Online bool   `json:"online"`
+}
+
+// LatencyMeshCell 延迟网格中一条探针到探针的最新探测结果
+type LatencyMeshCell struct {
+	From       string  `json:"from"`
+	To         string  `json:"to"`
+	MinRtt     float64 `json:"minRtt"`     // 最小延迟(毫秒)
+	AvgRtt     float64 `json:"avgRtt"`     // 平均延迟(毫秒)
+	MaxRtt     float64 `json:"maxRtt"`     // 最大延迟(毫秒)
+	PacketLoss float64 `json:"packetLoss"` // 丢包率(%)
+	Error      string  `json:"error,omitempty"`
+	UpdatedAt  int64   `json:"updatedAt"` // 探测时间（时间戳毫秒）
+}
+
+// LatencyMeshMatrix 延迟网格矩阵
+type LatencyMeshMatrix struct {
+	Mode   string             `json:"mode"`
+	Agents []LatencyMeshAgent `json:"agents"`
+	Cells  []LatencyMeshCell  `json:"cells"`
+}
+
+// GetMeshConfig 获取延迟网格配置
+func (s *PingService) GetMeshConfig(ctx context.Context) (*models.LatencyMeshConfig, error) {
+	return s.propertyService.GetLatencyMeshConfig(ctx)
+}
+
+// SaveMeshConfig 保存延迟网格配置，并向新旧成员重新下发 Ping 配置
+func (s *PingService) SaveMeshConfig(ctx context.Context, config *models.LatencyMeshConfig) error {
+	switch config.Mode {
+	case "", "icmp":
+		config.Mode = "icmp"
+	case "tcp":
+		if config.Port == 0 {
+			config.Port = defaultMeshPort
+		}
+		if config.Port < 1 || config.Port > 65535 {
+			return orz.NewError(400, "TCP 端口范围为 1-65535")
+		}
+	default:
+		return orz.NewError(400, "不支持的探测方式")
+	}
+
+	agentIDs := make([]string, 0, len(config.AgentIDs))
+	for _, id := range config.AgentIDs {
+		id = strings.TrimSpace(id)
+		if id != "" && !slices.Contains(agentIDs, id) {
+			agentIDs = append(agentIDs, id)
+		}
+	}
+	config.AgentIDs = agentIDs
+
+	old, err := s.propertyService.GetLatencyMeshConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.propertyService.Set(ctx, PropertyIDLatencyMesh, "延迟网格配置", config); err != nil {
+		return err
+	}
+
+	// 被移出网格的探针也需要重新下发，清理掉旧的对端目标
+	affected := slices.Clone(config.AgentIDs)
+	for _, id := range old.AgentIDs {
+		if !slices.Contains(affected, id) {
+			affected = append(affected, id)
+		}
+	}
+	for _, id := range affected {
+		s.syncToAgent(ctx, id)
+	}
+	return nil
+}
+
+// GetMeshMatrix 获取延迟网格矩阵，数据来自各成员最新一轮的 Ping 结果
+func (s *PingService) GetMeshMatrix(ctx context.Context) (*LatencyMeshMatrix, error) {
+	config, err := s.propertyService.GetLatencyMeshConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	matrix := &LatencyMeshMatrix{
+		Mode:   config.Mode,
+		Agents: []LatencyMeshAgent{},
+		Cells:  []LatencyMeshCell{},
+	}
+	if !config.Enabled {
+		return matrix, nil
+	}
+
+	agents, err := s.agentRepo.ListByIDs(ctx, config.AgentIDs)
+	if err != nil {
+		return nil, err
+	}
+	// 按配置顺序输出成员，方便前端按固定顺序渲染矩阵
+	slices.SortFunc(agents, func(a, b models.Agent) int {
+		return slices.Index(config.AgentIDs, a.ID) - slices.Index(config.AgentIDs, b.ID)
+	})
+
+	for _, agent := range agents {
+		matrix.Agents = append(matrix.Agents, LatencyMeshAgent{
+			ID:     agent.ID,
+			Name:   agent.Name,
+			IP:     agent.IP,
+			Online: agent.Status == 1,
+		})
+
+		latest, _ := s.metricService.GetLatestMetrics(ctx, agent.ID)
+		if latest == nil {
+			continue
+		}
+		for _, ping := range latest.Ping {
+			peerID, ok := strings.CutPrefix(ping.TargetID, meshTargetPrefix)
+			if !ok {
+				continue
+			}
+			matrix.Cells = append(matrix.Cells, LatencyMeshCell{
+				From:       agent.ID,
+				To:         peerID,
+				MinRtt:     ping.MinRtt,
+				AvgRtt:     ping.AvgRtt,
+				MaxRtt:     ping.MaxRtt,
+				PacketLoss: ping.PacketLoss,
+				Error:      ping.Error,
+				UpdatedAt:  latest.PingAt,
+			})
+		}
+	}
+
+	return matrix, nil
+}
+
+// meshTargets 构建探针在延迟网格中需要探测的对端目标，探针不在网格中时返回空
+func (s *PingService) meshTargets(ctx context.Context, agentID string) []protocol.PingTarget {
+	config, err := s.propertyService.GetLatencyMeshConfig(ctx)
+	if err != nil {
+		s.logger.Warn("获取延迟网格配置失败", zap.Error(err))
+		return nil
+	}
+	if !config.Enabled || !slices.Contains(config.AgentIDs, agentID) {
+		return nil
+	}
+
+	peers, err := s.agentRepo.ListByIDs(ctx, config.AgentIDs)
+	if err != nil {
+		s.logger.Warn("获取延迟网格成员失败", zap.Error(err))
+		return nil
+	}
+
+	var targets []protocol.PingTarget
+	for _, peer := range peers {
+		// 跳过自身和尚未上报 IP 的探针
+		if peer.ID == agentID || peer.IP == "" {
+			continue
+		}
+		target := protocol.PingTarget{
+			ID:     meshTargetPrefix + peer.ID,
+			Target: peer.IP,
+		}
+		if config.Mode == "tcp" {
+			target.Mode = "tcp"
+			target.Port = config.Port
+		}
+		targets = append(targets, target)
+	}
+	return targets
+}
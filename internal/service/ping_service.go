@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/internal/repo"
+	ws "github.com/dushixiang/pika/internal/websocket"
+	"github.com/go-orz/orz"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// defaultPingInterval Ping 目标的默认检测间隔（秒）
+const defaultPingInterval = 60
+
+// PingService 探针 Ping 目标管理服务
+type PingService struct {
+	logger          *zap.Logger
+	PingTargetRepo  *repo.PingTargetRepo
+	agentRepo       *repo.AgentRepo
+	wsManager       *ws.Manager
+	propertyService *PropertyService
+	metricService   *MetricService
+}
+
+func NewPingService(logger *zap.Logger, db *gorm.DB, wsManager *ws.Manager, propertyService *PropertyService, metricService *MetricService) *PingService {
+	return &PingService{
+		logger:          logger.Named("ping"),
+		PingTargetRepo:  repo.NewPingTargetRepo(db),
+		agentRepo:       repo.NewAgentRepo(db),
+		wsManager:       wsManager,
+		propertyService: propertyService,
+		metricService:   metricService,
+	}
+}
+
+// PingTargetRequest 创建或更新 Ping 目标请求
+type PingTargetRequest struct {
+	Name    string `json:"name"`
+	Target  string `json:"target"`
+	Enabled bool   `json:"enabled"`
+}
+
+// validate 校验 Ping 目标，目标只允许 IP 或域名
+func (r *PingTargetRequest) validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	r.Target = strings.TrimSpace(r.Target)
+	if r.Target == "" {
+		return orz.NewError(400, "目标地址不能为空")
+	}
+	if net.ParseIP(r.Target) == nil && strings.ContainsAny(r.Target, " /:") {
+		return orz.NewError(400, "目标地址只能是 IP 或域名")
+	}
+	if r.Name == "" {
+		r.Name = r.Target
+	}
+	return nil
+}
+
+// ListTargets 获取探针的 Ping 目标
+func (s *PingService) ListTargets(ctx context.Context, agentID string) ([]models.PingTarget, error) {
+	return s.PingTargetRepo.FindByAgentID(ctx, agentID)
+}
+
+// CreateTarget 创建 Ping 目标并下发到探针
+func (s *PingService) CreateTarget(ctx context.Context, agentID string, req *PingTargetRequest) (*models.PingTarget, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	target := &models.PingTarget{
+		ID:        uuid.NewString(),
+		AgentID:   agentID,
+		Name:      req.Name,
+		Target:    req.Target,
+		Enabled:   req.Enabled,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.PingTargetRepo.Create(ctx, target); err != nil {
+		return nil, err
+	}
+
+	s.syncToAgent(ctx, agentID)
+	return target, nil
+}
+
+// UpdateTarget 更新 Ping 目标并下发到探针
+func (s *PingService) UpdateTarget(ctx context.Context, agentID, id string, req *PingTargetRequest) (*models.PingTarget, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	target, err := s.PingTargetRepo.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if target.AgentID != agentID {
+		return nil, orz.NewError(404, "Ping 目标不存在")
+	}
+
+	target.Name = req.Name
+	target.Target = req.Target
+	target.Enabled = req.Enabled
+	if err := s.PingTargetRepo.Save(ctx, &target); err != nil {
+		return nil, err
+	}
+
+	s.syncToAgent(ctx, agentID)
+	return &target, nil
+}
+
+// DeleteTarget 删除 Ping 目标并下发到探针，历史指标随指标保留周期自动清理
+func (s *PingService) DeleteTarget(ctx context.Context, agentID, id string) error {
+	target, err := s.PingTargetRepo.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if target.AgentID != agentID {
+		return orz.NewError(404, "Ping 目标不存在")
+	}
+
+	if err := s.PingTargetRepo.DeleteById(ctx, id); err != nil {
+		return err
+	}
+
+	s.syncToAgent(ctx, agentID)
+	return nil
+}
+
+// BuildConfigMessage 构建下发给探针的 Ping 配置消息
+func (s *PingService) BuildConfigMessage(ctx context.Context, agentID string) ([]byte, error) {
+	targets, err := s.PingTargetRepo.FindEnabledByAgentID(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := protocol.PingConfigPayload{
+		Interval: defaultPingInterval,
+		Targets:  make([]protocol.PingTarget, 0, len(targets)),
+	}
+	for _, target := range targets {
+		payload.Targets = append(payload.Targets, protocol.PingTarget{
+			ID:     target.ID,
+			Target: target.Target,
+		})
+	}
+	payload.Targets = append(payload.Targets, s.meshTargets(ctx, agentID)...)
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(protocol.Message{
+		Type: protocol.MessageTypePingConfig,
+		Data: data,
+	})
+}
+
+// syncToAgent 向在线探针下发最新的 Ping 配置，探针离线时会在下次连接时下发
+func (s *PingService) syncToAgent(ctx context.Context, agentID string) {
+	if _, ok := s.wsManager.GetClient(agentID); !ok {
+		return
+	}
+
+	msgData, err := s.BuildConfigMessage(ctx, agentID)
+	if err != nil {
+		s.logger.Error("构建Ping配置失败", zap.String("agentId", agentID), zap.Error(err))
+		return
+	}
+
+	if err := s.wsManager.SendToClient(agentID, msgData); err != nil {
+		s.logger.Warn("下发Ping配置到探针失败", zap.String("agentId", agentID), zap.Error(err))
+	}
+}
@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/internal/repo"
+	ws "github.com/dushixiang/pika/internal/websocket"
+	"github.com/go-orz/orz"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	PowerActionReboot    = "reboot"
+	PowerActionWakeOnLAN = "wol"
+)
+
+// PowerService 电源操作服务（计划重启、网络唤醒）
+// 重启任务创建后处于待确认状态，确认后才会在计划时间（启用维护窗口时顺延到窗口内）下发到探针
+type PowerService struct {
+	logger          *zap.Logger
+	PowerTaskRepo   *repo.PowerTaskRepo
+	agentRepo       *repo.AgentRepo
+	propertyService *PropertyService
+	wsManager       *ws.Manager
+}
+
+func NewPowerService(logger *zap.Logger, db *gorm.DB, propertyService *PropertyService, wsManager *ws.Manager) *PowerService {
+	return &PowerService{
+		logger:          logger.Named("power"),
+		PowerTaskRepo:   repo.NewPowerTaskRepo(db),
+		agentRepo:       repo.NewAgentRepo(db),
+		propertyService: propertyService,
+		wsManager:       wsManager,
+	}
+}
+
+// ScheduleReboot 创建计划重启任务，scheduledAt 为 0 表示尽快执行
+func (s *PowerService) ScheduleReboot(ctx context.Context, agentID string, scheduledAt int64) (*models.PowerTask, error) {
+	if _, err := s.agentRepo.FindById(ctx, agentID); err != nil {
+		return nil, orz.NewError(404, "探针不存在")
+	}
+
+	now := time.Now().UnixMilli()
+	if scheduledAt < now {
+		scheduledAt = now
+	}
+
+	task := &models.PowerTask{
+		AgentID:     agentID,
+		Action:      PowerActionReboot,
+		ScheduledAt: scheduledAt,
+		Status:      "pending",
+		CreatedAt:   now,
+	}
+	if err := s.PowerTaskRepo.Create(ctx, task); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("计划重启任务已创建，等待确认",
+		zap.Int64("taskId", task.ID),
+		zap.String("agentId", agentID),
+		zap.Int64("scheduledAt", scheduledAt))
+	return task, nil
+}
+
+// Confirm 确认重启任务，确认后由定时任务在计划时间下发
+func (s *PowerService) Confirm(ctx context.Context, id int64) error {
+	task, err := s.PowerTaskRepo.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if task.Status != "pending" {
+		return orz.NewError(400, "任务不是待确认状态")
+	}
+	return s.PowerTaskRepo.UpdateColumnsById(ctx, id, map[string]interface{}{
+		"status": "scheduled",
+	})
+}
+
+// Cancel 取消尚未下发的任务
+func (s *PowerService) Cancel(ctx context.Context, id int64) error {
+	task, err := s.PowerTaskRepo.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if task.Status != "pending" && task.Status != "scheduled" {
+		return orz.NewError(400, "任务已下发，无法取消")
+	}
+	return s.PowerTaskRepo.UpdateColumnsById(ctx, id, map[string]interface{}{
+		"status": "cancelled",
+	})
+}
+
+// WakeOnLAN 通过同一局域网内的中继探针发送网络唤醒魔术包，立即下发
+func (s *PowerService) WakeOnLAN(ctx context.Context, relayAgentID, mac, broadcast string) (*models.PowerTask, error) {
+	hw, err := net.ParseMAC(mac)
+	if err != nil || len(hw) != 6 {
+		return nil, orz.NewError(400, "MAC 地址格式错误")
+	}
+	if broadcast != "" {
+		if _, _, err := net.SplitHostPort(broadcast); err != nil {
+			return nil, orz.NewError(400, "广播地址格式错误，应为 host:port")
+		}
+	}
+	if _, err := s.agentRepo.FindById(ctx, relayAgentID); err != nil {
+		return nil, orz.NewError(404, "探针不存在")
+	}
+
+	now := time.Now().UnixMilli()
+	task := &models.PowerTask{
+		AgentID:     relayAgentID,
+		Action:      PowerActionWakeOnLAN,
+		TargetMAC:   hw.String(),
+		Broadcast:   broadcast,
+		ScheduledAt: now,
+		Status:      "scheduled",
+		CreatedAt:   now,
+	}
+	if err := s.PowerTaskRepo.Create(ctx, task); err != nil {
+		return nil, err
+	}
+
+	if err := s.dispatch(ctx, task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// ListByAgentID 获取探针的电源操作任务
+func (s *PowerService) ListByAgentID(ctx context.Context, agentID string) ([]models.PowerTask, error) {
+	return s.PowerTaskRepo.FindRecentByAgentID(ctx, agentID, 50)
+}
+
+// Run 启动定时任务，每分钟下发到期的重启任务
+func (s *PowerService) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	s.logger.Info("电源操作定时任务已启动")
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("电源操作定时任务已停止")
+			return
+		case <-ticker.C:
+			s.dispatchDue(ctx)
+		}
+	}
+}
+
+func (s *PowerService) dispatchDue(ctx context.Context) {
+	now := time.Now()
+	tasks, err := s.PowerTaskRepo.FindDue(ctx, now.UnixMilli())
+	if err != nil {
+		s.logger.Error("获取到期的电源操作任务失败", zap.Error(err))
+		return
+	}
+	if len(tasks) == 0 {
+		return
+	}
+
+	window, err := s.propertyService.GetMaintenanceWindowConfig(ctx)
+	if err != nil {
+		s.logger.Warn("获取维护窗口配置失败，按计划时间执行", zap.Error(err))
+		window = &models.MaintenanceWindowConfig{}
+	}
+
+	for i := range tasks {
+		task := &tasks[i]
+		if task.Action == PowerActionReboot && window.Enabled {
+			next := nextMaintenanceWindow(window, now)
+			if next.After(now) {
+				// 不在维护窗口内，顺延到下一个窗口开始时间
+				s.logger.Info("重启任务不在维护窗口内，顺延执行",
+					zap.Int64("taskId", task.ID),
+					zap.Time("nextWindow", next))
+				_ = s.PowerTaskRepo.UpdateColumnsById(ctx, task.ID, map[string]interface{}{
+					"scheduled_at": next.UnixMilli(),
+				})
+				continue
+			}
+		}
+		if err := s.dispatch(ctx, task); err != nil {
+			s.logger.Error("下发电源操作失败", zap.Int64("taskId", task.ID), zap.Error(err))
+		}
+	}
+}
+
+// dispatch 将电源操作作为指令下发到探针
+func (s *PowerService) dispatch(ctx context.Context, task *models.PowerTask) error {
+	commandID := fmt.Sprintf("power_%d_%d", task.ID, time.Now().UnixMilli())
+
+	if err := s.sendCommand(task, commandID); err != nil {
+		_ = s.PowerTaskRepo.UpdateColumnsById(ctx, task.ID, map[string]interface{}{
+			"status":     "error",
+			"command_id": commandID,
+			"error":      err.Error(),
+		})
+		return err
+	}
+
+	s.logger.Info("电源操作已下发",
+		zap.Int64("taskId", task.ID),
+		zap.String("agentId", task.AgentID),
+		zap.String("action", task.Action))
+
+	return s.PowerTaskRepo.UpdateColumnsById(ctx, task.ID, map[string]interface{}{
+		"status":     "running",
+		"command_id": commandID,
+	})
+}
+
+func (s *PowerService) sendCommand(task *models.PowerTask, commandID string) error {
+	if _, ok := s.wsManager.GetClient(task.AgentID); !ok {
+		return orz.NewError(400, "探针未连接")
+	}
+
+	var args any
+	switch task.Action {
+	case PowerActionReboot:
+		args = protocol.RebootRequest{DelaySeconds: 5}
+	case PowerActionWakeOnLAN:
+		args = protocol.WakeOnLANRequest{MAC: task.TargetMAC, Broadcast: task.Broadcast}
+	default:
+		return orz.NewError(400, "不支持的电源操作")
+	}
+	argsData, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+
+	cmdData, err := json.Marshal(protocol.CommandRequest{
+		ID:   commandID,
+		Type: task.Action,
+		Args: string(argsData),
+	})
+	if err != nil {
+		return err
+	}
+
+	msgData, err := json.Marshal(protocol.Message{
+		Type: protocol.MessageTypeCommand,
+		Data: cmdData,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.wsManager.SendToClient(task.AgentID, msgData)
+}
+
+// HandleCommandResponse 处理探针返回的电源操作执行结果
+func (s *PowerService) HandleCommandResponse(ctx context.Context, agentID string, resp *protocol.CommandResponse) error {
+	if resp.Status == "running" {
+		return nil
+	}
+
+	task, err := s.PowerTaskRepo.FindByCommandID(ctx, agentID, resp.ID)
+	if err != nil {
+		s.logger.Warn("未找到电源操作任务", zap.String("agentId", agentID), zap.String("cmdId", resp.ID))
+		return nil
+	}
+
+	var result protocol.PowerResult
+	if resp.Result != "" {
+		if err := json.Unmarshal([]byte(resp.Result), &result); err != nil {
+			result.Output = resp.Result
+		}
+	}
+
+	status := "success"
+	if resp.Status == "error" {
+		status = "error"
+	}
+
+	s.logger.Info("电源操作执行完成",
+		zap.Int64("taskId", task.ID),
+		zap.String("agentId", agentID),
+		zap.String("action", task.Action),
+		zap.String("status", status))
+
+	return s.PowerTaskRepo.UpdateColumnsById(ctx, task.ID, map[string]interface{}{
+		"status": status,
+		"output": result.Output,
+		"error":  resp.Error,
+	})
+}
+
+// nextMaintenanceWindow 返回 t 之后最近的维护窗口开始时间，t 已在窗口内或窗口配置无效时返回 t
+func nextMaintenanceWindow(window *models.MaintenanceWindowConfig, t time.Time) time.Time {
+	start, ok := recurringWindowStart(window.RecurringWindow, t)
+	if !ok || !start.After(t) {
+		return t
+	}
+	return start
+}
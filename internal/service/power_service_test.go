@@ -0,0 +1,45 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+func TestNextMaintenanceWindow(t *testing.T) {
+	// 2024-01-01 为周一
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+	daily := &models.MaintenanceWindowConfig{RecurringWindow: models.RecurringWindow{StartTime: "02:00", EndTime: "04:00"}}
+	overnight := &models.MaintenanceWindowConfig{RecurringWindow: models.RecurringWindow{StartTime: "23:00", EndTime: "02:00"}}
+	mondayOvernight := &models.MaintenanceWindowConfig{RecurringWindow: models.RecurringWindow{Weekdays: []int{1}, StartTime: "23:00", EndTime: "02:00"}}
+
+	tests := []struct {
+		name   string
+		window *models.MaintenanceWindowConfig
+		now    time.Time
+		want   time.Time
+	}{
+		{"窗口内", daily, at(1, 3, 0), at(1, 3, 0)},
+		{"刚好到开始时间", daily, at(1, 2, 0), at(1, 2, 0)},
+		{"开始前一分钟", daily, at(1, 1, 59), at(1, 2, 0)},
+		{"刚好到结束时间", daily, at(1, 4, 0), at(2, 2, 0)},
+		{"跨天窗口开始前", overnight, at(1, 22, 0), at(1, 23, 0)},
+		{"跨天窗口当天部分", overnight, at(1, 23, 30), at(1, 23, 30)},
+		{"跨天窗口次日部分", overnight, at(2, 1, 0), at(2, 1, 0)},
+		{"跨天窗口结束后", overnight, at(2, 2, 0), at(2, 23, 0)},
+		{"跨天窗口次日部分按开始当天的星期", mondayOvernight, at(2, 1, 0), at(2, 1, 0)},
+		{"跨天窗口结束后等到下周", mondayOvernight, at(2, 3, 0), at(8, 23, 0)},
+		{"星期过滤排除今天", &models.MaintenanceWindowConfig{RecurringWindow: models.RecurringWindow{Weekdays: []int{3}, StartTime: "02:00", EndTime: "04:00"}}, at(1, 3, 0), at(3, 2, 0)},
+		{"今天的窗口已结束等到下周", &models.MaintenanceWindowConfig{RecurringWindow: models.RecurringWindow{Weekdays: []int{1}, StartTime: "02:00", EndTime: "04:00"}}, at(1, 5, 0), at(8, 2, 0)},
+		{"开始时间等于结束时间不限制", &models.MaintenanceWindowConfig{RecurringWindow: models.RecurringWindow{StartTime: "02:00", EndTime: "02:00"}}, at(1, 5, 0), at(1, 5, 0)},
+		{"时间格式错误不限制", &models.MaintenanceWindowConfig{RecurringWindow: models.RecurringWindow{StartTime: "2am", EndTime: "04:00"}}, at(1, 5, 0), at(1, 5, 0)},
+	}
+	for _, tt := range tests {
+		if got := nextMaintenanceWindow(tt.window, tt.now); !got.Equal(tt.want) {
+			t.Errorf("%s: nextMaintenanceWindow(%s) = %s, 期望 %s", tt.name, tt.now.Format(time.DateTime), got.Format(time.DateTime), tt.want.Format(time.DateTime))
+		}
+	}
+}
@@ -0,0 +1,331 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/dushixiang/pika/internal/config"
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/internal/repo"
+	ws "github.com/dushixiang/pika/internal/websocket"
+	"github.com/go-orz/orz"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// processKillTimeout 等待探针返回结束进程结果的时间，超过后记录标记为失败
+const processKillTimeout = 30 * time.Second
+
+// ProcessService 进程管理服务，负责转发探针推送的实时进程列表，以及下发并记录结束进程操作
+type ProcessService struct {
+	logger          *zap.Logger
+	ProcessKillRepo *repo.ProcessKillRepo
+	wsManager       *ws.Manager
+	config          config.ProcessesConfig
+
+	tickets *wsTicketStore
+	mu      sync.Mutex
+	streams map[string]*ProcessStream
+}
+
+// ProcessStream 正在查看的进程列表
+type ProcessStream struct {
+	ID        string
+	AgentID   string
+	Duration  time.Duration
+	Snapshots chan protocol.ProcessSnapshot
+
+	client *ws.Client // 打开进程列表时探针的连接，探针重连后推送已随旧连接结束
+}
+
+// ProcessKillParams 结束进程参数，进程名和命令行为用户操作时看到的信息，随记录一起保存
+type ProcessKillParams struct {
+	protocol.ProcessKillRequest
+	Name    string `json:"name"`
+	Cmdline string `json:"cmdline"`
+}
+
+func NewProcessService(logger *zap.Logger, db *gorm.DB, wsManager *ws.Manager, cfg *config.AppConfig) *ProcessService {
+	s := &ProcessService{
+		logger:          logger.Named("process"),
+		ProcessKillRepo: repo.NewProcessKillRepo(db),
+		wsManager:       wsManager,
+		tickets:         newWSTicketStore(),
+		streams:         make(map[string]*ProcessStream),
+	}
+	if cfg.Processes != nil {
+		s.config = *cfg.Processes
+	}
+	return s
+}
+
+// AuthorizeKill 校验用户是否可以结束进程
+func (s *ProcessService) AuthorizeKill(username string) error {
+	if !s.config.AllowKill {
+		return orz.NewError(403, "未启用结束进程")
+	}
+	if len(s.config.AllowedUsers) > 0 && !slices.Contains(s.config.AllowedUsers, username) {
+		return orz.NewError(403, "没有结束进程的权限")
+	}
+	return nil
+}
+
+// CreateTicket 为已认证的用户创建查看进程列表的一次性凭证
+func (s *ProcessService) CreateTicket(agentID, username, clientIP string) (string, error) {
+	if _, ok := s.wsManager.GetClient(agentID); !ok {
+		return "", orz.NewError(400, "探针未连接")
+	}
+	return s.tickets.issue(agentID, username, clientIP)
+}
+
+// Open 使用凭证向探针下发进程列表指令，探针按间隔推送快照直到到期或被停止
+func (s *ProcessService) Open(ticket string, req protocol.ProcessListRequest) (*ProcessStream, error) {
+	t, ok := s.tickets.redeem(ticket)
+	if !ok {
+		return nil, orz.NewError(401, "凭证无效或已过期")
+	}
+	client, ok := s.wsManager.GetClient(t.agentID)
+	if !ok {
+		return nil, orz.NewError(400, "探针未连接")
+	}
+
+	protocol.NormalizeProcessListRequest(&req)
+	args, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	stream := &ProcessStream{
+		ID:        fmt.Sprintf("process_list_%d", time.Now().UnixNano()),
+		AgentID:   t.agentID,
+		Duration:  time.Duration(req.Duration) * time.Second,
+		Snapshots: make(chan protocol.ProcessSnapshot, 4),
+		client:    client,
+	}
+	s.mu.Lock()
+	s.streams[stream.ID] = stream
+	s.mu.Unlock()
+
+	cmdData, err := json.Marshal(protocol.CommandRequest{
+		ID:   stream.ID,
+		Type: "process_list",
+		Args: string(args),
+	})
+	if err != nil {
+		s.removeStream(stream.ID)
+		return nil, err
+	}
+	if err := s.sendToAgent(stream.AgentID, protocol.MessageTypeCommand, cmdData); err != nil {
+		s.removeStream(stream.ID)
+		return nil, orz.NewError(500, "发送指令失败")
+	}
+
+	s.logger.Debug("开始查看进程列表",
+		zap.String("agentId", stream.AgentID),
+		zap.String("streamId", stream.ID),
+		zap.String("username", t.username))
+	return stream, nil
+}
+
+// Stop 结束进程列表推送
+func (s *ProcessService) Stop(stream *ProcessStream) {
+	if !s.removeStream(stream.ID) {
+		return
+	}
+	data, err := json.Marshal(protocol.ProcessListStop{ID: stream.ID})
+	if err != nil {
+		return
+	}
+	// 探针可能已经结束推送或断开，发送失败无需处理
+	_ = s.sendToAgent(stream.AgentID, protocol.MessageTypeProcessListStop, data)
+}
+
+// AgentConnected 打开进程列表时的探针连接是否仍然有效
+func (s *ProcessService) AgentConnected(stream *ProcessStream) bool {
+	client, ok := s.wsManager.GetClient(stream.AgentID)
+	return ok && client == stream.client
+}
+
+// HandleSnapshot 处理探针推送的进程列表快照
+func (s *ProcessService) HandleSnapshot(agentID string, snapshot *protocol.ProcessSnapshot) error {
+	s.mu.Lock()
+	stream, ok := s.streams[snapshot.ID]
+	s.mu.Unlock()
+	if !ok || stream.AgentID != agentID {
+		// 浏览器已断开，丢弃快照
+		return nil
+	}
+
+	if snapshot.Done {
+		select {
+		case stream.Snapshots <- *snapshot:
+		case <-time.After(time.Second):
+		}
+		return nil
+	}
+
+	select {
+	case stream.Snapshots <- *snapshot:
+	default:
+		// 浏览器消费过慢时丢弃快照，下一份快照会包含最新的进程列表
+	}
+	return nil
+}
+
+func (s *ProcessService) removeStream(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.streams[id]; !ok {
+		return false
+	}
+	delete(s.streams, id)
+	return true
+}
+
+// Kill 向探针下发结束进程指令并保存记录，执行结果异步更新到记录
+func (s *ProcessService) Kill(ctx context.Context, agentID, username, clientIP string, params ProcessKillParams) (*models.ProcessKillRecord, error) {
+	if err := s.AuthorizeKill(username); err != nil {
+		return nil, err
+	}
+	if params.PID <= 1 {
+		return nil, orz.NewError(400, "不允许结束该进程")
+	}
+	if params.Signal == "" {
+		params.Signal = protocol.ProcessSignalTerm
+	}
+	if params.Signal != protocol.ProcessSignalTerm && params.Signal != protocol.ProcessSignalKill {
+		return nil, orz.NewError(400, "信号仅支持 TERM 或 KILL")
+	}
+	if _, ok := s.wsManager.GetClient(agentID); !ok {
+		return nil, orz.NewError(400, "探针未连接")
+	}
+
+	now := time.Now()
+	record := &models.ProcessKillRecord{
+		AgentID:   agentID,
+		PID:       params.PID,
+		Name:      params.Name,
+		Cmdline:   params.Cmdline,
+		Signal:    params.Signal,
+		Status:    models.ProcessKillStatusRunning,
+		CommandID: fmt.Sprintf("process_kill_%d", now.UnixNano()),
+		Username:  username,
+		ClientIP:  clientIP,
+		CreatedAt: now.UnixMilli(),
+	}
+	if err := s.ProcessKillRepo.Create(ctx, record); err != nil {
+		return nil, err
+	}
+
+	if err := s.sendKill(agentID, record.CommandID, params.ProcessKillRequest); err != nil {
+		s.finish(ctx, record.ID, map[string]interface{}{
+			"status":      models.ProcessKillStatusError,
+			"error":       "下发指令失败: " + err.Error(),
+			"finished_at": time.Now().UnixMilli(),
+		})
+		return nil, orz.NewError(500, "发送指令失败")
+	}
+
+	s.logger.Info("结束进程指令已下发",
+		zap.Int64("id", record.ID),
+		zap.String("agentId", agentID),
+		zap.Int32("pid", params.PID),
+		zap.String("name", params.Name),
+		zap.String("signal", params.Signal),
+		zap.String("username", username))
+
+	time.AfterFunc(processKillTimeout, func() {
+		s.finish(context.Background(), record.ID, map[string]interface{}{
+			"status":      models.ProcessKillStatusError,
+			"error":       fmt.Sprintf("超过 %d 秒未返回执行结果", int(processKillTimeout.Seconds())),
+			"finished_at": time.Now().UnixMilli(),
+		})
+	})
+	return record, nil
+}
+
+func (s *ProcessService) sendKill(agentID, commandID string, req protocol.ProcessKillRequest) error {
+	args, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	cmdData, err := json.Marshal(protocol.CommandRequest{
+		ID:   commandID,
+		Type: "process_kill",
+		Args: string(args),
+	})
+	if err != nil {
+		return err
+	}
+	return s.sendToAgent(agentID, protocol.MessageTypeCommand, cmdData)
+}
+
+// HandleCommandResponse 处理探针返回的结束进程结果
+func (s *ProcessService) HandleCommandResponse(ctx context.Context, agentID string, resp *protocol.CommandResponse) error {
+	if resp.Status == "running" {
+		return nil
+	}
+
+	record, err := s.ProcessKillRepo.FindByCommandID(ctx, agentID, resp.ID)
+	if err != nil {
+		s.logger.Warn("未找到结束进程记录", zap.String("agentId", agentID), zap.String("cmdId", resp.ID))
+		return nil
+	}
+
+	status := models.ProcessKillStatusSuccess
+	if resp.Status == "error" {
+		status = models.ProcessKillStatusError
+	}
+	values := map[string]interface{}{
+		"status":      status,
+		"error":       resp.Error,
+		"finished_at": time.Now().UnixMilli(),
+	}
+	var result protocol.ProcessKillResult
+	if err := json.Unmarshal([]byte(resp.Result), &result); err == nil && result.Name != "" {
+		values["name"] = result.Name
+	}
+
+	s.logger.Info("结束进程执行完成",
+		zap.Int64("id", record.ID),
+		zap.String("agentId", agentID),
+		zap.Int32("pid", record.PID),
+		zap.String("status", status))
+
+	s.finish(ctx, record.ID, values)
+	return nil
+}
+
+func (s *ProcessService) finish(ctx context.Context, id int64, values map[string]interface{}) {
+	if err := s.ProcessKillRepo.Finish(ctx, id, values); err != nil {
+		s.logger.Error("更新结束进程记录失败", zap.Int64("id", id), zap.Error(err))
+	}
+}
+
+// CloseStaleKills 服务启动时将上次运行遗留的执行中记录标记为失败
+func (s *ProcessService) CloseStaleKills(ctx context.Context) error {
+	count, err := s.ProcessKillRepo.CloseRunning(ctx, "服务重启，未收到执行结果", time.Now().UnixMilli())
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		s.logger.Info("已结束中断的结束进程记录", zap.Int64("count", count))
+	}
+	return nil
+}
+
+func (s *ProcessService) sendToAgent(agentID string, msgType protocol.MessageType, data []byte) error {
+	msgData, err := json.Marshal(protocol.Message{
+		Type: msgType,
+		Data: data,
+	})
+	if err != nil {
+		return err
+	}
+	return s.wsManager.SendToClient(agentID, msgData)
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/dushixiang/pika/internal/config"
+)
+
+func TestProcessAuthorizeKill(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   config.ProcessesConfig
+		username string
+		allowed  bool
+	}{
+		{"未启用", config.ProcessesConfig{}, "admin", false},
+		{"未限制用户", config.ProcessesConfig{AllowKill: true}, "admin", true},
+		{"在允许列表中", config.ProcessesConfig{AllowKill: true, AllowedUsers: []string{"ops"}}, "ops", true},
+		{"不在允许列表中", config.ProcessesConfig{AllowKill: true, AllowedUsers: []string{"ops"}}, "admin", false},
+	}
+	for _, tt := range tests {
+		s := &ProcessService{config: tt.config}
+		if err := s.AuthorizeKill(tt.username); (err == nil) != tt.allowed {
+			t.Errorf("%s: AuthorizeKill 错误 = %v, 期望允许 %v", tt.name, err, tt.allowed)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dushixiang/pika/internal/protocol"
+	"go.uber.org/zap"
+)
+
+// PrometheusService 以 Prometheus 文本格式导出所有探针的最新指标和服务监控状态
+type PrometheusService struct {
+	logger        *zap.Logger
+	agentService  *AgentService
+	metricService *MetricService
+}
+
+func NewPrometheusService(logger *zap.Logger, agentService *AgentService, metricService *MetricService) *PrometheusService {
+	return &PrometheusService{
+		logger:        logger.Named("prometheus"),
+		agentService:  agentService,
+		metricService: metricService,
+	}
+}
+
+// promLabel 指标标签
+type promLabel struct {
+	Name  string
+	Value string
+}
+
+// promSample 指标样本
+type promSample struct {
+	Labels []promLabel
+	Value  float64
+}
+
+// promFamily 同名指标集合，输出时共用一组 HELP 和 TYPE
+type promFamily struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []promSample
+}
+
+// promRegistry 按注册顺序输出指标，保证每次抓取的输出顺序稳定
+type promRegistry struct {
+	families []*promFamily
+	byName   map[string]*promFamily
+}
+
+func newPromRegistry() *promRegistry {
+	return &promRegistry{byName: make(map[string]*promFamily)}
+}
+
+// gauge 注册 gauge 类型指标
+func (r *promRegistry) gauge(name, help string) *promFamily {
+	return r.family(name, help, "gauge")
+}
+
+// counter 注册 counter 类型指标
+func (r *promRegistry) counter(name, help string) *promFamily {
+	return r.family(name, help, "counter")
+}
+
+func (r *promRegistry) family(name, help, typ string) *promFamily {
+	if f, ok := r.byName[name]; ok {
+		return f
+	}
+	f := &promFamily{Name: name, Help: help, Type: typ}
+	r.families = append(r.families, f)
+	r.byName[name] = f
+	return f
+}
+
+// add 添加一条样本
+func (f *promFamily) add(value float64, labels ...promLabel) {
+	f.Samples = append(f.Samples, promSample{Labels: labels, Value: value})
+}
+
+// write 按 Prometheus 文本格式（0.0.4）输出，没有样本的指标不输出
+func (r *promRegistry) write(buf *bytes.Buffer) {
+	for _, f := range r.families {
+		if len(f.Samples) == 0 {
+			continue
+		}
+		fmt.Fprintf(buf, "# HELP %s %s\n", f.Name, f.Help)
+		fmt.Fprintf(buf, "# TYPE %s %s\n", f.Name, f.Type)
+		for _, sample := range f.Samples {
+			buf.WriteString(f.Name)
+			if len(sample.Labels) > 0 {
+				buf.WriteByte('{')
+				for i, label := range sample.Labels {
+					if i > 0 {
+						buf.WriteByte(',')
+					}
+					fmt.Fprintf(buf, "%s=\"%s\"", label.Name, escapePromLabel(label.Value))
+				}
+				buf.WriteByte('}')
+			}
+			buf.WriteByte(' ')
+			buf.WriteString(strconv.FormatFloat(sample.Value, 'g', -1, 64))
+			buf.WriteByte('\n')
+		}
+	}
+}
+
+var promLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapePromLabel 转义标签值中的反斜杠、双引号和换行符
+func escapePromLabel(value string) string {
+	return promLabelEscaper.Replace(value)
+}
+
+// customMetricLabels 合并探针标签和自定义指标的标签，与探针标签同名的自定义标签会被忽略
+func customMetricLabels(agentLabels []promLabel, custom map[string]string) []promLabel {
+	labels := make([]promLabel, 0, len(agentLabels)+len(custom))
+	labels = append(labels, agentLabels...)
+
+	names := make([]string, 0, len(custom))
+	for name := range custom {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if slices.ContainsFunc(agentLabels, func(label promLabel) bool { return label.Name == name }) {
+			continue
+		}
+		labels = append(labels, promLabel{Name: name, Value: custom[name]})
+	}
+	return labels
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Render 生成 /metrics 接口的响应内容
+func (s *PrometheusService) Render(ctx context.Context) ([]byte, error) {
+	agents, err := s.agentService.ListAgents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r := newPromRegistry()
+	agentUp := r.gauge("pika_agent_up", "Whether the agent is connected (1) or offline (0).")
+	agentLastSeen := r.gauge("pika_agent_last_seen_timestamp_seconds", "Last time the agent reported, in unix seconds.")
+	cpuUsage := r.gauge("pika_cpu_usage_percent", "CPU usage percent.")
+	cpuCores := r.gauge("pika_cpu_logical_cores", "Number of logical CPU cores.")
+	cpuMode := r.gauge("pika_cpu_mode_percent", "Percentage of CPU time spent in each mode since the previous sample.")
+	cpuCoreUsage := r.gauge("pika_cpu_core_usage_percent", "CPU usage percent per logical core, only reported when per-core collection is enabled.")
+	memUsage := r.gauge("pika_memory_usage_percent", "Memory usage percent.")
+	memTotal := r.gauge("pika_memory_total_bytes", "Total memory in bytes.")
+	memUsed := r.gauge("pika_memory_used_bytes", "Used memory in bytes.")
+	swapUsed := r.gauge("pika_memory_swap_used_bytes", "Used swap in bytes.")
+	diskUsage := r.gauge("pika_disk_usage_percent", "Average disk usage percent across all disks.")
+	diskTotal := r.gauge("pika_disk_total_bytes", "Total disk capacity in bytes.")
+	diskUsed := r.gauge("pika_disk_used_bytes", "Used disk capacity in bytes.")
+	netSentRate := r.gauge("pika_network_transmit_bytes_per_second", "Network transmit rate across all interfaces.")
+	netRecvRate := r.gauge("pika_network_receive_bytes_per_second", "Network receive rate across all interfaces.")
+	netSentTotal := r.counter("pika_network_transmit_bytes_total", "Total bytes transmitted across all interfaces since agent host boot.")
+	netRecvTotal := r.counter("pika_network_receive_bytes_total", "Total bytes received across all interfaces since agent host boot.")
+	pressureAvg := r.gauge("pika_pressure_avg60_percent", "Percentage of time in the last 60 seconds that tasks were stalled on a resource (Linux PSI).")
+	pressureTotal := r.counter("pika_pressure_stalled_seconds_total", "Total time tasks were stalled on a resource (Linux PSI).")
+	clockOffset := r.gauge("pika_clock_offset_seconds", "Offset of the agent clock relative to the configured NTP server, positive when the agent is behind.")
+
+	agentNames := make(map[string]string, len(agents))
+	for _, agent := range agents {
+		agentNames[agent.ID] = agent.Name
+		labels := []promLabel{
+			{Name: "agent_id", Value: agent.ID},
+			{Name: "agent_name", Value: agent.Name},
+			{Name: "hostname", Value: agent.Hostname},
+		}
+		agentUp.add(boolToFloat(agent.Status == 1), labels...)
+		if agent.LastSeenAt > 0 {
+			agentLastSeen.add(float64(agent.LastSeenAt)/1000, labels...)
+		}
+
+		latest, _ := s.metricService.GetLatestMetrics(ctx, agent.ID)
+		if latest == nil {
+			continue
+		}
+		if latest.CPU != nil {
+			cpuUsage.add(latest.CPU.UsagePercent, labels...)
+			cpuCores.add(float64(latest.CPU.LogicalCores), labels...)
+		}
+		if times := latest.CPUTimes; times != nil {
+			for _, mode := range []struct {
+				name  string
+				value float64
+			}{
+				{"user", times.User},
+				{"system", times.System},
+				{"iowait", times.IOWait},
+				{"steal", times.Steal},
+				{"idle", times.Idle},
+			} {
+				modeLabels := append(slices.Clone(labels), promLabel{Name: "mode", Value: mode.name})
+				cpuMode.add(mode.value, modeLabels...)
+			}
+		}
+		for i, usage := range latest.CPUPerCore {
+			coreLabels := append(slices.Clone(labels), promLabel{Name: "core", Value: strconv.Itoa(i)})
+			cpuCoreUsage.add(usage, coreLabels...)
+		}
+		if latest.Memory != nil {
+			memUsage.add(latest.Memory.UsagePercent, labels...)
+			memTotal.add(float64(latest.Memory.Total), labels...)
+			memUsed.add(float64(latest.Memory.Used), labels...)
+			swapUsed.add(float64(latest.Memory.SwapUsed), labels...)
+		}
+		if latest.Disk != nil {
+			diskUsage.add(latest.Disk.UsagePercent, labels...)
+			diskTotal.add(float64(latest.Disk.Total), labels...)
+			diskUsed.add(float64(latest.Disk.Used), labels...)
+		}
+		if latest.Network != nil {
+			netSentRate.add(float64(latest.Network.TotalBytesSentRate), labels...)
+			netRecvRate.add(float64(latest.Network.TotalBytesRecvRate), labels...)
+			netSentTotal.add(float64(latest.Network.TotalBytesSentTotal), labels...)
+			netRecvTotal.add(float64(latest.Network.TotalBytesRecvTotal), labels...)
+		}
+		if latest.TimeSync != nil && latest.TimeSync.Error == "" {
+			clockOffset.add(latest.TimeSync.Offset/1000, labels...)
+		}
+		if latest.Pressure != nil {
+			for _, resource := range []struct {
+				name string
+				stat *protocol.PressureStat
+			}{
+				{"cpu", latest.Pressure.CPU},
+				{"memory", latest.Pressure.Memory},
+				{"io", latest.Pressure.IO},
+			} {
+				if resource.stat == nil {
+					continue
+				}
+				addPressure := func(kind string, avg protocol.PressureAvg) {
+					pressureLabels := append(slices.Clone(labels),
+						promLabel{Name: "resource", Value: resource.name},
+						promLabel{Name: "kind", Value: kind})
+					pressureAvg.add(avg.Avg60, pressureLabels...)
+					pressureTotal.add(float64(avg.Total)/1e6, pressureLabels...)
+				}
+				addPressure("some", resource.stat.Some)
+				if resource.stat.Full != nil {
+					addPressure("full", *resource.stat.Full)
+				}
+			}
+		}
+		for _, custom := range latest.Custom {
+			family := r.gauge("pika_custom_"+custom.Name, "Custom metric reported by the agent.")
+			family.add(custom.Value, customMetricLabels(labels, custom.Labels)...)
+		}
+	}
+
+	monitorUp := r.gauge("pika_monitor_up", "Whether the latest monitor check succeeded (1) or failed (0).")
+	monitorResponseTime := r.gauge("pika_monitor_response_time_milliseconds", "Response time of the latest monitor check.")
+	monitorCertDaysLeft := r.gauge("pika_monitor_cert_days_left", "Days until the monitored TLS certificate expires.")
+	monitors, err := s.metricService.GetAllLatestMonitorMetrics(ctx)
+	if err != nil {
+		// 服务监控数据查询失败时仍然输出探针指标
+		s.logger.Warn("获取服务监控最新数据失败", zap.Error(err))
+	}
+	for _, monitor := range monitors {
+		labels := []promLabel{
+			{Name: "monitor_id", Value: monitor.MonitorId},
+			{Name: "type", Value: monitor.Type},
+			{Name: "target", Value: monitor.Target},
+			{Name: "agent_id", Value: monitor.AgentId},
+			{Name: "agent_name", Value: agentNames[monitor.AgentId]},
+		}
+		monitorUp.add(boolToFloat(monitor.Status == "up"), labels...)
+		monitorResponseTime.add(float64(monitor.ResponseTime), labels...)
+		if monitor.CertExpiryTime > 0 {
+			monitorCertDaysLeft.add(float64(monitor.CertDaysLeft), labels...)
+		}
+	}
+
+	var buf bytes.Buffer
+	r.write(&buf)
+	return buf.Bytes(), nil
+}
@@ -25,6 +25,18 @@ const (
 	PropertyIDAlertConfig = "alert_config"
 	// PropertyIDDNSProviders DNS 服务商配置的固定 ID
 	PropertyIDDNSProviders = "dns_providers"
+	// PropertyIDVulnerabilityConfig 漏洞匹配配置的固定 ID
+	PropertyIDVulnerabilityConfig = "vulnerability_config"
+	// PropertyIDMaintenanceWindow 维护窗口配置的固定 ID
+	PropertyIDMaintenanceWindow = "maintenance_window"
+	// PropertyIDInfluxDBConfig InfluxDB 导出配置的固定 ID
+	PropertyIDInfluxDBConfig = "influxdb_config"
+	// PropertyIDLatencyMesh 探针间延迟网格配置的固定 ID
+	PropertyIDLatencyMesh = "latency_mesh"
+	// PropertyIDMetricArchiveState 指标归档进度的固定 ID
+	PropertyIDMetricArchiveState = "metric_archive_state"
+	// PropertyIDSecretKey 敏感字段加密密钥的固定 ID
+	PropertyIDSecretKey = "secret_key"
 )
 
 type PropertyService struct {
@@ -37,7 +49,7 @@ type PropertyService struct {
 func NewPropertyService(logger *zap.Logger, db *gorm.DB) *PropertyService {
 	return &PropertyService{
 		repo:   repo.NewPropertyRepo(db),
-		logger: logger,
+		logger: logger.Named("property"),
 		cache:  cache.New[string, *models.Property](time.Minute), // 0 表示永不过期
 	}
 }
@@ -225,6 +237,46 @@ func (s *PropertyService) DeleteDNSProvider(ctx context.Context, providerType st
 	return s.SetDNSProviderConfigs(ctx, newProviders)
 }
 
+// GetVulnerabilityConfig 获取漏洞匹配配置
+func (s *PropertyService) GetVulnerabilityConfig(ctx context.Context) (*models.VulnerabilityConfig, error) {
+	var config models.VulnerabilityConfig
+	err := s.GetValue(ctx, PropertyIDVulnerabilityConfig, &config)
+	if err != nil {
+		return nil, fmt.Errorf("获取漏洞匹配配置失败: %w", err)
+	}
+	return &config, nil
+}
+
+// GetMaintenanceWindowConfig 获取维护窗口配置
+func (s *PropertyService) GetMaintenanceWindowConfig(ctx context.Context) (*models.MaintenanceWindowConfig, error) {
+	var config models.MaintenanceWindowConfig
+	err := s.GetValue(ctx, PropertyIDMaintenanceWindow, &config)
+	if err != nil {
+		return nil, fmt.Errorf("获取维护窗口配置失败: %w", err)
+	}
+	return &config, nil
+}
+
+// GetInfluxDBConfig 获取 InfluxDB 导出配置
+func (s *PropertyService) GetInfluxDBConfig(ctx context.Context) (*models.InfluxDBConfig, error) {
+	var config models.InfluxDBConfig
+	err := s.GetValue(ctx, PropertyIDInfluxDBConfig, &config)
+	if err != nil {
+		return nil, fmt.Errorf("获取 InfluxDB 导出配置失败: %w", err)
+	}
+	return &config, nil
+}
+
+// GetLatencyMeshConfig 获取探针间延迟网格配置
+func (s *PropertyService) GetLatencyMeshConfig(ctx context.Context) (*models.LatencyMeshConfig, error) {
+	var config models.LatencyMeshConfig
+	err := s.GetValue(ctx, PropertyIDLatencyMesh, &config)
+	if err != nil {
+		return nil, fmt.Errorf("获取延迟网格配置失败: %w", err)
+	}
+	return &config, nil
+}
+
 // defaultPropertyConfig 默认配置项定义
 type defaultPropertyConfig struct {
 	ID    string
@@ -256,7 +308,9 @@ func (s *PropertyService) InitializeDefaultConfigs(ctx context.Context) error {
 			ID:   PropertyIDMetricsConfig,
 			Name: "指标数据配置",
 			Value: models.MetricsConfig{
-				RetentionHours: 168, // 默认7天
+				RetentionHours:       168,  // 默认7天
+				RollupRetentionHours: 2160, // 默认90天
+				MonitorSampleSeconds: 300,  // 状态不变时每5分钟保存一条
 			},
 		},
 		{
@@ -265,24 +319,46 @@ func (s *PropertyService) InitializeDefaultConfigs(ctx context.Context) error {
 			Value: models.AlertConfig{
 				Enabled: true, // 默认启用告警
 				Rules: models.AlertRules{
-					CPUEnabled:           true,
-					CPUThreshold:         80,
-					CPUDuration:          300, // 5分钟
-					MemoryEnabled:        true,
-					MemoryThreshold:      80,
-					MemoryDuration:       300, // 5分钟
-					DiskEnabled:          true,
-					DiskThreshold:        85,
-					DiskDuration:         300, // 5分钟
-					NetworkEnabled:       false,
-					NetworkThreshold:     100,
-					NetworkDuration:      300, // 5分钟
-					CertEnabled:          true,
-					CertThreshold:        30, // 30天
-					ServiceEnabled:       true,
-					ServiceDuration:      300, // 5分钟
-					AgentOfflineEnabled:  true,
-					AgentOfflineDuration: 300, // 5分钟
+					CPUEnabled:                  true,
+					CPUThreshold:                80,
+					CPUDuration:                 300, // 5分钟
+					MemoryEnabled:               true,
+					MemoryThreshold:             80,
+					MemoryDuration:              300, // 5分钟
+					DiskEnabled:                 true,
+					DiskThreshold:               85,
+					DiskDuration:                300, // 5分钟
+					NetworkEnabled:              false,
+					NetworkThreshold:            100,
+					NetworkDuration:             300, // 5分钟
+					CertEnabled:                 true,
+					CertThreshold:               30, // 30天
+					ServiceEnabled:              true,
+					ServiceDuration:             300, // 5分钟
+					AgentOfflineEnabled:         true,
+					AgentOfflineDuration:        300, // 5分钟
+					WireGuardEnabled:            false,
+					WireGuardHandshakeThreshold: 300, // 5分钟
+					MountEnabled:                false,
+					MountDuration:               60, // 1分钟
+					InodeEnabled:                true,
+					InodeThreshold:              90,
+					InodeDuration:               300, // 5分钟
+					FDEnabled:                   true,
+					FDThreshold:                 90,
+					FDDuration:                  300, // 5分钟
+					StealEnabled:                true,
+					StealThreshold:              20,
+					StealDuration:               300, // 5分钟
+					ClockEnabled:                true,
+					ClockThreshold:              1000, // 1秒
+					ClockDuration:               300,  // 5分钟
+					PortEnabled:                 false,
+					PortAllowed:                 []string{"tcp/22", "tcp/80", "tcp/443"},
+					PortDuration:                300, // 5分钟
+					KernelEnabled:               true,
+					KernelCategories:            []string{"oom", "io", "hardware"},
+					KernelRecovery:              600, // 10分钟
 				},
 			},
 		},
@@ -291,6 +367,43 @@ func (s *PropertyService) InitializeDefaultConfigs(ctx context.Context) error {
 			Name:  "DNS 服务商配置",
 			Value: []models.DNSProviderConfig{}, // 默认为空数组
 		},
+		{
+			ID:   PropertyIDVulnerabilityConfig,
+			Name: "漏洞匹配配置",
+			Value: models.VulnerabilityConfig{
+				Enabled:       false,
+				FeedURL:       "",
+				IntervalHours: 24,
+			},
+		},
+		{
+			ID:   PropertyIDMaintenanceWindow,
+			Name: "维护窗口配置",
+			Value: models.MaintenanceWindowConfig{
+				Enabled: false,
+				RecurringWindow: models.RecurringWindow{
+					StartTime: "02:00",
+					EndTime:   "05:00",
+				},
+			},
+		},
+		{
+			ID:   PropertyIDInfluxDBConfig,
+			Name: "InfluxDB 导出配置",
+			Value: models.InfluxDBConfig{
+				Enabled: false,
+			},
+		},
+		{
+			ID:   PropertyIDLatencyMesh,
+			Name: "延迟网格配置",
+			Value: models.LatencyMeshConfig{
+				Enabled:  false,
+				AgentIDs: []string{},
+				Mode:     "icmp",
+				Port:     22,
+			},
+		},
 	}
 
 	// 遍历并初始化每个配置
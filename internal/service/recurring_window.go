@@ -0,0 +1,45 @@
+package service
+
+import (
+	"slices"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+)
+
+// parseClock 解析 HH:mm 格式的时间，返回距当天零点的时长
+func parseClock(value string) (time.Duration, error) {
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
+
+// recurringWindowStart 返回 t 所在窗口的开始时间，t 不在窗口内时返回之后最近的窗口开始时间
+// 窗口按 t 的时区计算，跨天窗口的星期以窗口开始当天为准；时间无效、开始等于结束或一周内没有窗口时 ok 为 false
+func recurringWindowStart(window models.RecurringWindow, t time.Time) (start time.Time, ok bool) {
+	startClock, err1 := parseClock(window.StartTime)
+	endClock, err2 := parseClock(window.EndTime)
+	if err1 != nil || err2 != nil || startClock == endClock {
+		return time.Time{}, false
+	}
+
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	// 从前一天开始查找，覆盖前一天开始的跨天窗口
+	for offset := -1; offset <= 7; offset++ {
+		day := midnight.AddDate(0, 0, offset)
+		if len(window.Weekdays) > 0 && !slices.Contains(window.Weekdays, int(day.Weekday())) {
+			continue
+		}
+		windowStart := day.Add(startClock)
+		windowEnd := day.Add(endClock)
+		if endClock < startClock {
+			windowEnd = windowEnd.AddDate(0, 0, 1)
+		}
+		if t.Before(windowEnd) {
+			return windowStart, true
+		}
+	}
+	return time.Time{}, false
+}
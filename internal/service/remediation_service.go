@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/internal/repo"
+	ws "github.com/dushixiang/pika/internal/websocket"
+	"github.com/go-orz/orz"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// RemediationService 告警修复动作服务
+type RemediationService struct {
+	logger          *zap.Logger
+	RemediationRepo *repo.RemediationRepo
+	wsManager       *ws.Manager
+}
+
+func NewRemediationService(logger *zap.Logger, db *gorm.DB, wsManager *ws.Manager) *RemediationService {
+	return &RemediationService{
+		logger:          logger.Named("remediation"),
+		RemediationRepo: repo.NewRemediationRepo(db),
+		wsManager:       wsManager,
+	}
+}
+
+// Trigger 告警触发时创建匹配的修复动作，自动执行的动作会立即下发到探针
+func (s *RemediationService) Trigger(config *models.AlertConfig, record *models.AlertRecord) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("执行告警修复动作时发生panic",
+				zap.Any("panic", r),
+				zap.Int64("recordId", record.ID),
+			)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	for _, rule := range config.Remediations {
+		if !rule.Enabled || rule.AlertType != record.AlertType {
+			continue
+		}
+
+		remediation := &models.RemediationRecord{
+			AlertRecordID: record.ID,
+			AgentID:       record.AgentID,
+			AlertType:     record.AlertType,
+			Action:        rule.Action,
+			Target:        rule.Target,
+			Auto:          rule.Auto,
+			Status:        "pending",
+			CreatedAt:     time.Now().UnixMilli(),
+		}
+		if err := s.RemediationRepo.Create(ctx, remediation); err != nil {
+			s.logger.Error("创建修复动作记录失败", zap.Error(err))
+			continue
+		}
+
+		if !rule.Auto {
+			s.logger.Info("修复动作等待审批",
+				zap.Int64("remediationId", remediation.ID),
+				zap.String("agentId", remediation.AgentID),
+				zap.String("action", remediation.Action),
+				zap.String("target", remediation.Target))
+			continue
+		}
+
+		if err := s.dispatch(ctx, remediation); err != nil {
+			s.logger.Error("下发修复动作失败", zap.Int64("remediationId", remediation.ID), zap.Error(err))
+		}
+	}
+}
+
+// Approve 审批通过并执行修复动作
+func (s *RemediationService) Approve(ctx context.Context, id int64) error {
+	remediation, err := s.RemediationRepo.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+	// 先将状态从待审批改为执行中，并发审批时只有一个请求会下发指令
+	ok, err := s.RemediationRepo.UpdatePending(ctx, id, map[string]interface{}{
+		"status": "running",
+	})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return orz.NewError(400, "修复动作不是待审批状态")
+	}
+	return s.dispatch(ctx, &remediation)
+}
+
+// Reject 拒绝执行修复动作
+func (s *RemediationService) Reject(ctx context.Context, id int64) error {
+	if _, err := s.RemediationRepo.FindById(ctx, id); err != nil {
+		return err
+	}
+	ok, err := s.RemediationRepo.UpdatePending(ctx, id, map[string]interface{}{
+		"status": "rejected",
+	})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return orz.NewError(400, "修复动作不是待审批状态")
+	}
+	return nil
+}
+
+// ListByAlertRecordID 获取告警记录关联的修复动作
+func (s *RemediationService) ListByAlertRecordID(ctx context.Context, alertRecordID int64) ([]models.RemediationRecord, error) {
+	return s.RemediationRepo.FindByAlertRecordID(ctx, alertRecordID)
+}
+
+// dispatch 将修复动作作为指令下发到探针
+func (s *RemediationService) dispatch(ctx context.Context, remediation *models.RemediationRecord) error {
+	commandID := fmt.Sprintf("remediation_%d_%d", remediation.ID, time.Now().UnixMilli())
+
+	if err := s.sendCommand(remediation, commandID); err != nil {
+		_ = s.RemediationRepo.UpdateColumnsById(ctx, remediation.ID, map[string]interface{}{
+			"status":     "error",
+			"command_id": commandID,
+			"error":      err.Error(),
+		})
+		return err
+	}
+
+	s.logger.Info("修复动作已下发",
+		zap.Int64("remediationId", remediation.ID),
+		zap.String("agentId", remediation.AgentID),
+		zap.String("action", remediation.Action),
+		zap.String("target", remediation.Target))
+
+	return s.RemediationRepo.UpdateColumnsById(ctx, remediation.ID, map[string]interface{}{
+		"status":     "running",
+		"command_id": commandID,
+	})
+}
+
+func (s *RemediationService) sendCommand(remediation *models.RemediationRecord, commandID string) error {
+	if _, ok := s.wsManager.GetClient(remediation.AgentID); !ok {
+		return orz.NewError(400, "探针未连接")
+	}
+
+	args, err := json.Marshal(protocol.RemediationRequest{
+		Action: remediation.Action,
+		Target: remediation.Target,
+	})
+	if err != nil {
+		return err
+	}
+
+	cmdData, err := json.Marshal(protocol.CommandRequest{
+		ID:   commandID,
+		Type: "remediation",
+		Args: string(args),
+	})
+	if err != nil {
+		return err
+	}
+
+	msgData, err := json.Marshal(protocol.Message{
+		Type: protocol.MessageTypeCommand,
+		Data: cmdData,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.wsManager.SendToClient(remediation.AgentID, msgData)
+}
+
+// HandleCommandResponse 处理探针返回的修复动作执行结果
+func (s *RemediationService) HandleCommandResponse(ctx context.Context, agentID string, resp *protocol.CommandResponse) error {
+	if resp.Status == "running" {
+		return nil
+	}
+
+	remediation, err := s.RemediationRepo.FindByCommandID(ctx, agentID, resp.ID)
+	if err != nil {
+		s.logger.Warn("未找到修复动作记录", zap.String("agentId", agentID), zap.String("cmdId", resp.ID))
+		return nil
+	}
+
+	var result protocol.RemediationResult
+	if resp.Result != "" {
+		if err := json.Unmarshal([]byte(resp.Result), &result); err != nil {
+			result.Output = resp.Result
+		}
+	}
+
+	status := "success"
+	if resp.Status == "error" {
+		status = "error"
+	}
+
+	s.logger.Info("修复动作执行完成",
+		zap.Int64("remediationId", remediation.ID),
+		zap.String("agentId", agentID),
+		zap.String("status", status))
+
+	return s.RemediationRepo.UpdateColumnsById(ctx, remediation.ID, map[string]interface{}{
+		"status": status,
+		"output": result.Output,
+		"error":  resp.Error,
+	})
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/dushixiang/pika/internal/config"
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/golang/snappy"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	remoteWriteQueueSize     = 10000           // 待发送样本队列长度，队列满时丢弃新样本
+	remoteWriteBatchSize     = 1000            // 单次请求最多携带的时间序列数
+	remoteWriteFlushInterval = 5 * time.Second // 未攒满一批时的发送间隔
+	remoteWriteMaxRetries    = 3               // 5xx 或网络错误时的重试次数
+)
+
+// remoteWriteSeries 一条时间序列的单个样本
+type remoteWriteSeries struct {
+	Labels    map[string]string // 含 __name__
+	Value     float64
+	Timestamp int64 // 毫秒
+}
+
+// RemoteWriter 将探针上报的指标通过 Prometheus remote_write 协议转发到外部时序库（VictoriaMetrics、Mimir 等）
+// 样本先进入内存队列，由后台协程按批发送，转发失败不影响指标入库
+type RemoteWriter struct {
+	logger     *zap.Logger
+	config     *config.RemoteWriteConfig
+	httpClient *http.Client
+	queue      chan remoteWriteSeries
+}
+
+// NewRemoteWriter 根据配置创建转发器，未启用时返回 nil
+func NewRemoteWriter(logger *zap.Logger, cfg *config.AppConfig) *RemoteWriter {
+	if cfg.RemoteWrite == nil || !cfg.RemoteWrite.Enabled || cfg.RemoteWrite.URL == "" {
+		return nil
+	}
+	return &RemoteWriter{
+		logger: logger.Named("remote-write"),
+		config: cfg.RemoteWrite,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		queue: make(chan remoteWriteSeries, remoteWriteQueueSize),
+	}
+}
+
+// Append 将样本加入发送队列，队列已满时丢弃
+func (w *RemoteWriter) Append(series ...remoteWriteSeries) {
+	for _, s := range series {
+		for name, value := range w.config.ExternalLabels {
+			if _, ok := s.Labels[name]; !ok {
+				s.Labels[name] = value
+			}
+		}
+		select {
+		case w.queue <- s:
+		default:
+			w.logger.Warn("remote write 发送队列已满，丢弃样本")
+			return
+		}
+	}
+}
+
+// Run 启动后台发送任务
+func (w *RemoteWriter) Run(ctx context.Context) {
+	ticker := time.NewTicker(remoteWriteFlushInterval)
+	defer ticker.Stop()
+
+	w.logger.Info("remote write 转发已启动", zap.String("url", w.config.URL))
+
+	batch := make([]remoteWriteSeries, 0, remoteWriteBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := w.send(ctx, batch); err != nil {
+			w.logger.Error("remote write 发送失败", zap.Int("series", len(batch)), zap.Error(err))
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("remote write 转发已停止")
+			return
+		case s := <-w.queue:
+			batch = append(batch, s)
+			if len(batch) >= remoteWriteBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// send 发送一批样本，5xx 和网络错误按指数退避重试，4xx 直接丢弃
+func (w *RemoteWriter) send(ctx context.Context, batch []remoteWriteSeries) error {
+	body := snappy.Encode(nil, encodeWriteRequest(batch))
+
+	var err error
+	for attempt := 0; attempt < remoteWriteMaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(1<<attempt) * time.Second):
+			}
+		}
+
+		var retryable bool
+		retryable, err = w.post(ctx, body)
+		if err == nil || !retryable {
+			return err
+		}
+	}
+	return err
+}
+
+func (w *RemoteWriter) post(ctx context.Context, body []byte) (retryable bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	for name, value := range w.config.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 == 2 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return false, nil
+	}
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return resp.StatusCode >= 500, fmt.Errorf("status %d: %s", resp.StatusCode, string(respBody))
+}
+
+// remoteWriteSamples 将刚写入的指标转换为 remote_write 样本，指标名与 /metrics 接口保持一致
+func remoteWriteSamples(agentID string, metricType protocol.MetricType, latest *LatestMetrics, timestamp int64) []remoteWriteSeries {
+	var series []remoteWriteSeries
+	add := func(name string, value float64) {
+		series = append(series, remoteWriteSeries{
+			Labels:    map[string]string{"__name__": name, "agent_id": agentID},
+			Value:     value,
+			Timestamp: timestamp,
+		})
+	}
+
+	switch metricType {
+	case protocol.MetricTypeCPU:
+		if latest.CPU != nil {
+			add("pika_cpu_usage_percent", latest.CPU.UsagePercent)
+		}
+	case protocol.MetricTypeMemory:
+		if latest.Memory != nil {
+			add("pika_memory_usage_percent", latest.Memory.UsagePercent)
+			add("pika_memory_total_bytes", float64(latest.Memory.Total))
+			add("pika_memory_used_bytes", float64(latest.Memory.Used))
+			add("pika_memory_swap_used_bytes", float64(latest.Memory.SwapUsed))
+		}
+	case protocol.MetricTypeDisk:
+		if latest.Disk != nil {
+			add("pika_disk_usage_percent", latest.Disk.UsagePercent)
+			add("pika_disk_total_bytes", float64(latest.Disk.Total))
+			add("pika_disk_used_bytes", float64(latest.Disk.Used))
+		}
+	case protocol.MetricTypeNetwork:
+		if latest.Network != nil {
+			add("pika_network_transmit_bytes_per_second", float64(latest.Network.TotalBytesSentRate))
+			add("pika_network_receive_bytes_per_second", float64(latest.Network.TotalBytesRecvRate))
+			add("pika_network_transmit_bytes_total", float64(latest.Network.TotalBytesSentTotal))
+			add("pika_network_receive_bytes_total", float64(latest.Network.TotalBytesRecvTotal))
+		}
+	case protocol.MetricTypeNetworkConnection:
+		if latest.NetworkConnection != nil {
+			add("pika_network_connections_established", float64(latest.NetworkConnection.Established))
+			add("pika_network_connections_time_wait", float64(latest.NetworkConnection.TimeWait))
+		}
+	}
+	return series
+}
+
+// encodeWriteRequest 按 prompb.WriteRequest 的 protobuf 格式编码
+//
+//	WriteRequest { repeated TimeSeries timeseries = 1; }
+//	TimeSeries   { repeated Label labels = 1; repeated Sample samples = 2; }
+//	Label        { string name = 1; string value = 2; }
+//	Sample       { double value = 1; int64 timestamp = 2; }
+func encodeWriteRequest(batch []remoteWriteSeries) []byte {
+	var req []byte
+	for _, s := range batch {
+		var ts []byte
+
+		// 标签需要按名称排序
+		names := make([]string, 0, len(s.Labels))
+		for name := range s.Labels {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			var label []byte
+			label = protowire.AppendTag(label, 1, protowire.BytesType)
+			label = protowire.AppendString(label, name)
+			label = protowire.AppendTag(label, 2, protowire.BytesType)
+			label = protowire.AppendString(label, s.Labels[name])
+			ts = protowire.AppendTag(ts, 1, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, label)
+		}
+
+		var sample []byte
+		sample = protowire.AppendTag(sample, 1, protowire.Fixed64Type)
+		sample = protowire.AppendFixed64(sample, math.Float64bits(s.Value))
+		sample = protowire.AppendTag(sample, 2, protowire.VarintType)
+		sample = protowire.AppendVarint(sample, uint64(s.Timestamp))
+		ts = protowire.AppendTag(ts, 2, protowire.BytesType)
+		ts = protowire.AppendBytes(ts, sample)
+
+		req = protowire.AppendTag(req, 1, protowire.BytesType)
+		req = protowire.AppendBytes(req, ts)
+	}
+	return req
+}
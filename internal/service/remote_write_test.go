@@ -0,0 +1,156 @@
+package service
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/golang/snappy"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// decodedSeries 从 protobuf 中解析出的时间序列
+type decodedSeries struct {
+	Labels    [][2]string
+	Value     float64
+	Timestamp int64
+}
+
+// consumeFields 逐个读取 protobuf 字段，遇到格式错误时让测试失败
+func consumeFields(t *testing.T, b []byte, fn func(num protowire.Number, typ protowire.Type, value []byte, varint uint64)) {
+	t.Helper()
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			t.Fatalf("解析字段标签失败: %v", protowire.ParseError(n))
+		}
+		b = b[n:]
+		switch typ {
+		case protowire.BytesType:
+			v, m := protowire.ConsumeBytes(b)
+			if m < 0 {
+				t.Fatalf("解析字段 %d 失败: %v", num, protowire.ParseError(m))
+			}
+			fn(num, typ, v, 0)
+			b = b[m:]
+		case protowire.VarintType:
+			v, m := protowire.ConsumeVarint(b)
+			if m < 0 {
+				t.Fatalf("解析字段 %d 失败: %v", num, protowire.ParseError(m))
+			}
+			fn(num, typ, nil, v)
+			b = b[m:]
+		case protowire.Fixed64Type:
+			v, m := protowire.ConsumeFixed64(b)
+			if m < 0 {
+				t.Fatalf("解析字段 %d 失败: %v", num, protowire.ParseError(m))
+			}
+			fn(num, typ, nil, v)
+			b = b[m:]
+		default:
+			t.Fatalf("字段 %d 类型 %d 不在 prompb 定义中", num, typ)
+		}
+	}
+}
+
+// decodeWriteRequest 按 prompb.WriteRequest 的定义解析请求体
+func decodeWriteRequest(t *testing.T, body []byte) []decodedSeries {
+	t.Helper()
+	var result []decodedSeries
+	consumeFields(t, body, func(num protowire.Number, typ protowire.Type, ts []byte, _ uint64) {
+		if num != 1 || typ != protowire.BytesType {
+			t.Fatalf("WriteRequest 包含未知字段 %d", num)
+		}
+		var s decodedSeries
+		consumeFields(t, ts, func(num protowire.Number, typ protowire.Type, value []byte, _ uint64) {
+			switch num {
+			case 1:
+				var label [2]string
+				consumeFields(t, value, func(num protowire.Number, _ protowire.Type, value []byte, _ uint64) {
+					label[num-1] = string(value)
+				})
+				s.Labels = append(s.Labels, label)
+			case 2:
+				consumeFields(t, value, func(num protowire.Number, typ protowire.Type, _ []byte, v uint64) {
+					switch {
+					case num == 1 && typ == protowire.Fixed64Type:
+						s.Value = math.Float64frombits(v)
+					case num == 2 && typ == protowire.VarintType:
+						s.Timestamp = int64(v)
+					default:
+						t.Fatalf("Sample 字段 %d 类型 %d 错误", num, typ)
+					}
+				})
+			default:
+				t.Fatalf("TimeSeries 包含未知字段 %d", num)
+			}
+		})
+		result = append(result, s)
+	})
+	return result
+}
+
+func TestEncodeWriteRequestRoundTrip(t *testing.T) {
+	batch := []remoteWriteSeries{
+		{
+			Labels:    map[string]string{"__name__": "pika_cpu_usage_percent", "agent_id": "agent-1", "region": "华东"},
+			Value:     12.5,
+			Timestamp: 1760600000123,
+		},
+		{
+			Labels:    map[string]string{"__name__": "pika_memory_total_bytes", "agent_id": "agent-2"},
+			Value:     math.Inf(1),
+			Timestamp: 0,
+		},
+	}
+
+	body := snappy.Encode(nil, encodeWriteRequest(batch))
+	decoded, err := snappy.Decode(nil, body)
+	if err != nil {
+		t.Fatalf("snappy 解码失败: %v", err)
+	}
+
+	got := decodeWriteRequest(t, decoded)
+	want := []decodedSeries{
+		{
+			Labels:    [][2]string{{"__name__", "pika_cpu_usage_percent"}, {"agent_id", "agent-1"}, {"region", "华东"}},
+			Value:     12.5,
+			Timestamp: 1760600000123,
+		},
+		{
+			Labels:    [][2]string{{"__name__", "pika_memory_total_bytes"}, {"agent_id", "agent-2"}},
+			Value:     math.Inf(1),
+			Timestamp: 0,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("解码结果 = %+v, 期望 %+v", got, want)
+	}
+}
+
+func TestEncodeWriteRequestLargeBatch(t *testing.T) {
+	// 超过 64KB 的请求体需要能被 snappy 正确解码
+	batch := make([]remoteWriteSeries, remoteWriteBatchSize)
+	for i := range batch {
+		batch[i] = remoteWriteSeries{
+			Labels:    map[string]string{"__name__": "pika_disk_used_bytes", "agent_id": "agent-with-a-long-identifier-0123456789"},
+			Value:     float64(i),
+			Timestamp: int64(i),
+		}
+	}
+	raw := encodeWriteRequest(batch)
+	if len(raw) <= 1<<16 {
+		t.Fatalf("测试数据应超过 64KB, 实际 %d", len(raw))
+	}
+	decoded, err := snappy.Decode(nil, snappy.Encode(nil, raw))
+	if err != nil {
+		t.Fatalf("snappy 解码失败: %v", err)
+	}
+	got := decodeWriteRequest(t, decoded)
+	if len(got) != len(batch) {
+		t.Fatalf("解码出 %d 条序列, 期望 %d", len(got), len(batch))
+	}
+	if got[len(got)-1].Value != float64(len(batch)-1) {
+		t.Fatalf("最后一条序列的值 = %v", got[len(got)-1].Value)
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dushixiang/pika/internal/config"
+	"github.com/dushixiang/pika/pkg/secretbox"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// secretBox 加解密保存在数据库中的敏感字段；密钥优先由配置的 SecretKey 派生，
+// 未配置时使用首次使用时随机生成并保存在属性表中的密钥（属性接口不允许读写该属性）
+type secretBox struct {
+	logger          *zap.Logger
+	appConfig       *config.AppConfig
+	propertyService *PropertyService
+
+	mu  sync.Mutex
+	box *secretbox.Box
+}
+
+func newSecretBox(logger *zap.Logger, appConfig *config.AppConfig, propertyService *PropertyService) *secretBox {
+	return &secretBox{
+		logger:          logger,
+		appConfig:       appConfig,
+		propertyService: propertyService,
+	}
+}
+
+// load 加载加密密钥
+func (b *secretBox) load(ctx context.Context) (*secretbox.Box, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.box != nil {
+		return b.box, nil
+	}
+
+	var key []byte
+	if b.appConfig.SecretKey != "" {
+		sum := sha256.Sum256([]byte("pika-secret:" + b.appConfig.SecretKey))
+		key = sum[:]
+	} else {
+		var err error
+		if key, err = b.storedKey(ctx); err != nil {
+			return nil, err
+		}
+		b.logger.Warn("未配置 SecretKey，敏感字段使用保存在数据库中的密钥加密")
+	}
+
+	box, err := secretbox.New(key)
+	if err != nil {
+		return nil, err
+	}
+	b.box = box
+	return box, nil
+}
+
+// storedKey 读取保存在属性表中的密钥，不存在时随机生成
+func (b *secretBox) storedKey(ctx context.Context) ([]byte, error) {
+	var encoded string
+	err := b.propertyService.GetValue(ctx, PropertyIDSecretKey, &encoded)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("读取加密密钥失败: %w", err)
+	}
+	if encoded != "" {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("加密密钥格式错误: %w", err)
+		}
+		return key, nil
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := b.propertyService.Set(ctx, PropertyIDSecretKey, "加密密钥", base64.StdEncoding.EncodeToString(key)); err != nil {
+		return nil, fmt.Errorf("保存加密密钥失败: %w", err)
+	}
+	return key, nil
+}
+
+// Encrypt 加密敏感字段，空值原样返回
+func (b *secretBox) Encrypt(ctx context.Context, plain string) (string, error) {
+	if plain == "" {
+		return "", nil
+	}
+	box, err := b.load(ctx)
+	if err != nil {
+		return "", err
+	}
+	return box.Seal(plain)
+}
+
+// Decrypt 解密敏感字段，未加密的值原样返回
+func (b *secretBox) Decrypt(ctx context.Context, value string) (string, error) {
+	if !secretbox.IsSealed(value) {
+		return value, nil
+	}
+	box, err := b.load(ctx)
+	if err != nil {
+		return "", err
+	}
+	return box.Open(value)
+}
@@ -0,0 +1,349 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/internal/repo"
+	"github.com/dushixiang/pika/pkg/tracing"
+	"github.com/go-orz/orz"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// SoftwareService 软件清单服务
+type SoftwareService struct {
+	logger             *zap.Logger
+	SoftwareRepo       *repo.SoftwareRepo
+	ContainerImageRepo *repo.ContainerImageRepo
+	agentRepo          *repo.AgentRepo
+}
+
+func NewSoftwareService(logger *zap.Logger, db *gorm.DB) *SoftwareService {
+	return &SoftwareService{
+		logger:             logger.Named("software"),
+		SoftwareRepo:       repo.NewSoftwareRepo(db),
+		ContainerImageRepo: repo.NewContainerImageRepo(db),
+		agentRepo:          repo.NewAgentRepo(db),
+	}
+}
+
+// SoftwareSearchResult 软件搜索结果
+type SoftwareSearchResult struct {
+	AgentID   string `json:"agentId"`
+	AgentName string `json:"agentName"`
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	UpdatedAt int64  `json:"updatedAt"`
+}
+
+// HandleInventoryReport 处理探针上报的软件清单
+func (s *SoftwareService) HandleInventoryReport(ctx context.Context, agentID string, data *protocol.SoftwareInventoryData) error {
+	ctx, span := tracing.Start(ctx, "SoftwareService.HandleInventoryReport", tracing.WithAttributes(
+		tracing.String("agent.id", agentID),
+		tracing.Int("software.item_count", len(data.Items)),
+		tracing.Int("software.container_count", len(data.Containers)),
+	))
+	defer span.End()
+
+	now := time.Now().UnixMilli()
+	items := make([]models.SoftwareInventory, 0, len(data.Items))
+	seen := make(map[string]bool, len(data.Items))
+	for _, item := range data.Items {
+		name := strings.ToLower(strings.TrimSpace(item.Name))
+		if name == "" || item.Version == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		items = append(items, models.SoftwareInventory{
+			ID:        fmt.Sprintf("%s:%s", agentID, name),
+			AgentID:   agentID,
+			Name:      name,
+			Version:   item.Version,
+			UpdatedAt: now,
+		})
+	}
+	if err := s.SoftwareRepo.ReplaceByAgentID(ctx, agentID, items); err != nil {
+		return err
+	}
+	return s.saveContainerImages(ctx, agentID, data.Containers, now)
+}
+
+// saveContainerImages 保存运行中容器的镜像，镜像未变化时保留上次查询仓库的结果
+func (s *SoftwareService) saveContainerImages(ctx context.Context, agentID string, containers []protocol.ContainerImage, now int64) error {
+	existing, err := s.ContainerImageRepo.FindByAgentID(ctx, agentID)
+	if err != nil {
+		return err
+	}
+	existingByID := make(map[string]models.ContainerImage, len(existing))
+	for _, image := range existing {
+		existingByID[image.ID] = image
+	}
+
+	images := make([]models.ContainerImage, 0, len(containers))
+	seen := make(map[string]bool, len(containers))
+	for _, container := range containers {
+		id := fmt.Sprintf("%s:%s", agentID, container.ContainerID)
+		if container.ContainerID == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		image := models.ContainerImage{
+			ID:            id,
+			AgentID:       agentID,
+			ContainerID:   container.ContainerID,
+			ContainerName: container.ContainerName,
+			Image:         container.Image,
+			ImageID:       container.ImageID,
+			UpdatedAt:     now,
+		}
+		if ref, ok := parseImageReference(container.Image); ok {
+			image.Digest = imageDigest(ref, container.RepoDigests)
+		}
+		if old, ok := existingByID[id]; ok && old.Image == image.Image {
+			image.RegistryDigest = old.RegistryDigest
+			image.CheckedAt = old.CheckedAt
+			image.Drift = image.Digest != "" && image.RegistryDigest != "" && image.Digest != image.RegistryDigest
+		}
+		images = append(images, image)
+	}
+	return s.ContainerImageRepo.ReplaceByAgentID(ctx, agentID, images)
+}
+
+// ListContainerImages 获取探针运行中容器的镜像
+func (s *SoftwareService) ListContainerImages(ctx context.Context, agentID string) ([]models.ContainerImage, error) {
+	return s.ContainerImageRepo.FindByAgentID(ctx, agentID)
+}
+
+// ListByAgentID 获取探针的软件清单
+func (s *SoftwareService) ListByAgentID(ctx context.Context, agentID string) ([]models.SoftwareInventory, error) {
+	return s.SoftwareRepo.FindByAgentID(ctx, agentID)
+}
+
+// Search 按软件名称和版本条件搜索探针
+// op 支持 lt、lte、eq、gte、gt，为空时返回所有安装了该软件的探针
+func (s *SoftwareService) Search(ctx context.Context, name, op, version string) ([]SoftwareSearchResult, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return nil, orz.NewError(400, "软件名称不能为空")
+	}
+	if op != "" && version == "" {
+		return nil, orz.NewError(400, "版本号不能为空")
+	}
+
+	items, err := s.SoftwareRepo.FindByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []models.SoftwareInventory
+	for _, item := range items {
+		ok, err := matchVersion(item.Version, op, version)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, item)
+		}
+	}
+
+	results := make([]SoftwareSearchResult, 0, len(matched))
+	if len(matched) == 0 {
+		return results, nil
+	}
+
+	agentIDs := make([]string, 0, len(matched))
+	for _, item := range matched {
+		agentIDs = append(agentIDs, item.AgentID)
+	}
+	agents, err := s.agentRepo.FindByIdIn(ctx, agentIDs)
+	if err != nil {
+		return nil, err
+	}
+	agentNames := make(map[string]string, len(agents))
+	for _, agent := range agents {
+		agentNames[agent.ID] = agent.Name
+	}
+
+	for _, item := range matched {
+		results = append(results, SoftwareSearchResult{
+			AgentID:   item.AgentID,
+			AgentName: agentNames[item.AgentID],
+			Name:      item.Name,
+			Version:   item.Version,
+			UpdatedAt: item.UpdatedAt,
+		})
+	}
+	return results, nil
+}
+
+// matchVersion 判断版本号是否满足比较条件
+func matchVersion(actual, op, expected string) (bool, error) {
+	if op == "" {
+		return true, nil
+	}
+	cmp := CompareVersion(actual, expected)
+	switch op {
+	case "lt":
+		return cmp < 0, nil
+	case "lte":
+		return cmp <= 0, nil
+	case "eq":
+		return cmp == 0, nil
+	case "gte":
+		return cmp >= 0, nil
+	case "gt":
+		return cmp > 0, nil
+	default:
+		return false, orz.NewError(400, "不支持的比较操作符，支持: lt, lte, eq, gte, gt")
+	}
+}
+
+// preReleaseRanks 预发布标识的先后顺序，预发布版本低于对应的正式版本
+var preReleaseRanks = map[string]int{
+	"dev":      0,
+	"snapshot": 0,
+	"alpha":    1,
+	"beta":     2,
+	"pre":      3,
+	"preview":  3,
+	"rc":       4,
+}
+
+// versionSegment 版本号中的数字段或字母段
+type versionSegment struct {
+	value   string
+	numeric bool
+	pre     bool // 预发布段，如 rc、beta 以及 Debian 版本中 ~ 之后的段
+}
+
+// CompareVersion 比较两个版本号，a < b 返回 -1，相等返回 0，a > b 返回 1
+// 先比较 Debian 风格的 epoch（如 2:1.2 中的 2，没有时为 0），再按数字段和字母段拆分后逐段比较：
+// 3.0.13 > 3.0.2；预发布版本低于正式版本，如 3.0.0-rc1 < 3.0.0、1.0~beta < 1.0；
+// 其他字母段视为补丁后缀，高于没有后缀的版本，如 OpenSSL 的 1.1.1w > 1.1.1、OpenSSH 的 9.6p1 > 9.6
+func CompareVersion(a, b string) int {
+	aEpoch, aVersion := splitVersionEpoch(a)
+	bEpoch, bVersion := splitVersionEpoch(b)
+	if c := compareNumeric(aEpoch, bEpoch); c != 0 {
+		return c
+	}
+
+	as, bs := splitVersion(aVersion), splitVersion(bVersion)
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		if i >= len(as) {
+			// 较长的版本多出预发布段时更低，如 3.0.0 > 3.0.0-rc1
+			if bs[i].pre {
+				return 1
+			}
+			return -1
+		}
+		if i >= len(bs) {
+			if as[i].pre {
+				return -1
+			}
+			return 1
+		}
+		if c := compareVersionSegment(as[i], bs[i]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// splitVersionEpoch 拆分 Debian 风格的 epoch，如 2:1.2-1 返回 2 和 1.2-1
+func splitVersionEpoch(version string) (string, string) {
+	version = strings.TrimSpace(version)
+	epoch, rest, ok := strings.Cut(version, ":")
+	if !ok || epoch == "" || strings.TrimFunc(epoch, unicode.IsDigit) != "" {
+		return "0", version
+	}
+	return epoch, rest
+}
+
+// splitVersion 将版本号拆分为连续的数字段和字母段
+func splitVersion(version string) []versionSegment {
+	var segments []versionSegment
+	var current strings.Builder
+	var currentIsDigit, afterTilde, currentAfterTilde bool
+	flush := func() {
+		if current.Len() == 0 {
+			return
+		}
+		value := current.String()
+		_, keyword := preReleaseRanks[value]
+		segments = append(segments, versionSegment{
+			value:   value,
+			numeric: currentIsDigit,
+			pre:     currentAfterTilde || (!currentIsDigit && keyword),
+		})
+		current.Reset()
+	}
+	for _, r := range strings.ToLower(version) {
+		if !unicode.IsDigit(r) && !unicode.IsLetter(r) {
+			flush()
+			if r == '~' {
+				afterTilde = true
+			}
+			continue
+		}
+		isDigit := unicode.IsDigit(r)
+		if current.Len() > 0 && isDigit != currentIsDigit {
+			flush()
+		}
+		if current.Len() == 0 {
+			currentAfterTilde = afterTilde
+			afterTilde = false
+		}
+		currentIsDigit = isDigit
+		current.WriteRune(r)
+	}
+	flush()
+	return segments
+}
+
+func compareVersionSegment(a, b versionSegment) int {
+	switch {
+	case a.pre != b.pre:
+		// 预发布段低于正式版本的任何段，如 3.0.0-rc1 < 3.0.0.1
+		if a.pre {
+			return -1
+		}
+		return 1
+	case a.numeric && b.numeric:
+		return compareNumeric(a.value, b.value)
+	case a.numeric:
+		// 数字段大于字母段，例如 1.0 > 1.a
+		return 1
+	case b.numeric:
+		return -1
+	case a.pre && b.pre:
+		ar, aok := preReleaseRanks[a.value]
+		br, bok := preReleaseRanks[b.value]
+		if aok && bok && ar != br {
+			if ar < br {
+				return -1
+			}
+			return 1
+		}
+	}
+	return strings.Compare(a.value, b.value)
+}
+
+// compareNumeric 比较两个数字字符串，不受长度限制
+func compareNumeric(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
+}
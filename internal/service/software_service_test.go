@@ -0,0 +1,112 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompareVersion(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"3.0.13", "3.0.2", 1},
+		{"1.2.3", "1.2.3", 0},
+		{"1.2", "1.2.0", -1},
+		{"01.2", "1.2", 0},
+		{"20240101000000000001", "20240101000000000000", 1},
+		// OpenSSL、OpenSSH 风格的字母补丁后缀高于没有后缀的版本
+		{"1.1.1w", "1.1.1", 1},
+		{"1.1.1w", "1.1.1a", 1},
+		{"1.1.1w", "1.1.2", -1},
+		{"9.6p1", "9.6", 1},
+		{"1.0", "1.a", 1},
+		// 预发布版本低于正式版本
+		{"3.0.0-rc1", "3.0.0", -1},
+		{"3.0.0", "3.0.0-rc1", 1},
+		{"3.0.0-rc1", "3.0.0-rc2", -1},
+		{"3.0.0-alpha", "3.0.0-beta", -1},
+		{"3.0.0-beta2", "3.0.0-rc1", -1},
+		{"3.0.0-rc1", "3.0.0.1", -1},
+		{"2.0.0-SNAPSHOT", "2.0.0", -1},
+		{"1.0~beta1", "1.0", -1},
+		{"1.0~20230101", "1.0", -1},
+		{"1.0-1", "1.0", 1},
+		// Debian 风格的 epoch
+		{"1:2.3", "2.4", 1},
+		{"2:1.2", "1:9.9", 1},
+		{"0:1.2", "1.2", 0},
+		{"1:2.3-1", "1:2.3-2", -1},
+	}
+	for _, tt := range tests {
+		if got := CompareVersion(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersion(%q, %q) = %d, 期望 %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSplitVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    []versionSegment
+	}{
+		{"1.1.1w", []versionSegment{{"1", true, false}, {"1", true, false}, {"1", true, false}, {"w", false, false}}},
+		{"3.0.0-RC1", []versionSegment{{"3", true, false}, {"0", true, false}, {"0", true, false}, {"rc", false, true}, {"1", true, false}}},
+		{"1.0~20230101", []versionSegment{{"1", true, false}, {"0", true, false}, {"20230101", true, true}}},
+		{"9.6p1", []versionSegment{{"9", true, false}, {"6", true, false}, {"p", false, false}, {"1", true, false}}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := splitVersion(tt.version); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitVersion(%q) = %+v, 期望 %+v", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestSplitVersionEpoch(t *testing.T) {
+	tests := []struct {
+		version, epoch, rest string
+	}{
+		{"2:1.2-1", "2", "1.2-1"},
+		{"1.2", "0", "1.2"},
+		{"a:1.2", "0", "a:1.2"},
+		{":1.2", "0", ":1.2"},
+	}
+	for _, tt := range tests {
+		epoch, rest := splitVersionEpoch(tt.version)
+		if epoch != tt.epoch || rest != tt.rest {
+			t.Errorf("splitVersionEpoch(%q) = %q, %q, 期望 %q, %q", tt.version, epoch, rest, tt.epoch, tt.rest)
+		}
+	}
+}
+
+func TestMatchVersion(t *testing.T) {
+	tests := []struct {
+		actual, op, expected string
+		want                 bool
+		wantErr              bool
+	}{
+		{"1.1.1w", "", "3.0.0", true, false},
+		{"1.1.1w", "lt", "3.0.0", true, false},
+		{"3.0.0", "lt", "3.0.0", false, false},
+		{"3.0.0", "lte", "3.0.0", true, false},
+		{"3.0.0-rc1", "lte", "3.0.0", true, false},
+		{"3.0.0", "eq", "3.0.0", true, false},
+		{"3.0.1", "eq", "3.0.0", false, false},
+		{"3.0.0", "gte", "3.0.0", true, false},
+		{"3.0.0-rc1", "gte", "3.0.0", false, false},
+		{"3.0.13", "gt", "3.0.2", true, false},
+		{"3.0.2", "gt", "3.0.2", false, false},
+		{"3.0.2", "ne", "3.0.2", false, true},
+	}
+	for _, tt := range tests {
+		got, err := matchVersion(tt.actual, tt.op, tt.expected)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("matchVersion(%q, %q, %q) error = %v, 期望错误 %v", tt.actual, tt.op, tt.expected, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("matchVersion(%q, %q, %q) = %v, 期望 %v", tt.actual, tt.op, tt.expected, got, tt.want)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/dushixiang/pika/internal/models"
@@ -16,16 +17,18 @@ import (
 )
 
 type TamperService struct {
-	logger     *zap.Logger
-	tamperRepo *repo.TamperRepo
-	wsManager  *websocket.Manager
+	logger        *zap.Logger
+	tamperRepo    *repo.TamperRepo
+	wsManager     *websocket.Manager
+	eventNotifier *EventNotifier
 }
 
-func NewTamperService(logger *zap.Logger, tamperRepo *repo.TamperRepo, wsManager *websocket.Manager) *TamperService {
+func NewTamperService(logger *zap.Logger, tamperRepo *repo.TamperRepo, wsManager *websocket.Manager, eventNotifier *EventNotifier) *TamperService {
 	return &TamperService{
-		logger:     logger,
-		tamperRepo: tamperRepo,
-		wsManager:  wsManager,
+		logger:        logger.Named("tamper"),
+		tamperRepo:    tamperRepo,
+		wsManager:     wsManager,
+		eventNotifier: eventNotifier,
 	}
 }
 
@@ -207,7 +210,17 @@ func (s *TamperService) CreateAlert(agentID, path, details string, restored bool
 		Timestamp: timestamp,
 		CreatedAt: time.Now().UnixMilli(),
 	}
-	return s.tamperRepo.CreateAlert(alert)
+	if err := s.tamperRepo.CreateAlert(alert); err != nil {
+		return err
+	}
+
+	// 文件已被自动恢复时降低通知级别
+	level, message := "critical", fmt.Sprintf("受保护的路径 %s 被篡改: %s", path, details)
+	if restored {
+		level, message = "warning", fmt.Sprintf("受保护的路径 %s 被篡改，已自动恢复: %s", path, details)
+	}
+	s.eventNotifier.Notify(agentID, models.NotificationEventTamper, level, message)
+	return nil
 }
 
 // GetAlertsByAgentID 获取探针的防篡改告警
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// terminalRecordEventSize 单个录像事件的最大字节数，超出时拆分为多个事件
+	terminalRecordEventSize = 4 * 1024
+	// terminalRecordChunkSize 录像攒够该大小后保存一次，保存的片段不会超过 MySQL TEXT 的 64KB 上限
+	terminalRecordChunkSize = 32 * 1024
+)
+
+// asciicastHeader asciicast v2 录像的首行
+type asciicastHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// terminalRecorder 以 asciicast v2 格式记录终端会话，包括输出（o）、键盘输入（i）和窗口大小变化（r）
+type terminalRecorder struct {
+	start     time.Time
+	maxSize   int64
+	size      int64 // 已记录的字节数
+	truncated bool  // 超出大小上限后不再记录
+
+	pending map[string][]byte // 各类事件末尾不完整的 UTF-8 字符，与下一段数据拼接后再记录
+	buf     bytes.Buffer      // 尚未攒够一个片段的录像
+	chunks  []string          // 已攒够、等待保存的片段
+}
+
+func newTerminalRecorder(start time.Time, cols, rows int, term string, maxSize int64) *terminalRecorder {
+	r := &terminalRecorder{
+		start:   start,
+		maxSize: maxSize,
+		pending: make(map[string][]byte),
+	}
+	header := asciicastHeader{
+		Version:   2,
+		Width:     cols,
+		Height:    rows,
+		Timestamp: start.Unix(),
+	}
+	if term != "" {
+		header.Env = map[string]string{"TERM": term}
+	}
+	line, _ := json.Marshal(header)
+	r.writeLine(line)
+	return r
+}
+
+// record 记录一段输出或输入，数据末尾不完整的 UTF-8 字符留到下一次记录
+func (r *terminalRecorder) record(at time.Time, kind string, data []byte) {
+	if r.truncated {
+		return
+	}
+	if pending := r.pending[kind]; len(pending) > 0 {
+		data = append(pending, data...)
+	}
+	complete := utf8CompleteLen(data)
+	r.pending[kind] = append([]byte(nil), data[complete:]...)
+	data = data[:complete]
+
+	for len(data) > 0 {
+		n := min(len(data), terminalRecordEventSize)
+		// 在字符边界拆分，避免把多字节字符拆到两个事件中
+		for n < len(data) && n > 0 && !utf8.RuneStart(data[n]) {
+			n--
+		}
+		if n == 0 {
+			n = min(len(data), terminalRecordEventSize)
+		}
+		if !r.writeEvent(at, kind, string(data[:n])) {
+			return
+		}
+		data = data[n:]
+	}
+}
+
+// resize 记录窗口大小变化
+func (r *terminalRecorder) resize(at time.Time, cols, rows int) {
+	if r.truncated {
+		return
+	}
+	r.writeEvent(at, "r", fmt.Sprintf("%dx%d", cols, rows))
+}
+
+// take 取出待保存的片段，会话结束时连同未攒够的部分一起取出
+func (r *terminalRecorder) take(final bool) []string {
+	if final && r.buf.Len() > 0 {
+		r.chunks = append(r.chunks, r.buf.String())
+		r.buf.Reset()
+	}
+	chunks := r.chunks
+	r.chunks = nil
+	return chunks
+}
+
+func (r *terminalRecorder) writeEvent(at time.Time, kind, data string) bool {
+	elapsed := math.Round(at.Sub(r.start).Seconds()*1e6) / 1e6
+	line, err := json.Marshal([]interface{}{max(elapsed, 0), kind, data})
+	if err != nil {
+		return false
+	}
+	return r.writeLine(line)
+}
+
+func (r *terminalRecorder) writeLine(line []byte) bool {
+	if r.maxSize > 0 && r.size+int64(len(line))+1 > r.maxSize {
+		r.truncated = true
+		return false
+	}
+	r.buf.Write(line)
+	r.buf.WriteByte('\n')
+	r.size += int64(len(line)) + 1
+	if r.buf.Len() >= terminalRecordChunkSize {
+		r.chunks = append(r.chunks, r.buf.String())
+		r.buf.Reset()
+	}
+	return true
+}
+
+// utf8CompleteLen 返回 data 中不含末尾不完整 UTF-8 字符的长度，非法字节按完整字符处理
+func utf8CompleteLen(data []byte) int {
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if !utf8.RuneStart(data[i]) {
+			continue
+		}
+		if utf8.FullRune(data[i:]) {
+			return len(data)
+		}
+		return i
+	}
+	return len(data)
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dushixiang/pika/internal/config"
+)
+
+// parseCast 解析录像内容，返回首行和各事件
+func parseCast(t *testing.T, data string) (asciicastHeader, [][]interface{}) {
+	t.Helper()
+	lines := strings.Split(strings.TrimSuffix(data, "\n"), "\n")
+	var header asciicastHeader
+	if err := json.Unmarshal([]byte(lines[0]), &header); err != nil {
+		t.Fatalf("解析录像首行失败: %v", err)
+	}
+	var events [][]interface{}
+	for _, line := range lines[1:] {
+		var event []interface{}
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("解析录像事件失败: %v", err)
+		}
+		events = append(events, event)
+	}
+	return header, events
+}
+
+func TestTerminalRecorder(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	r := newTerminalRecorder(start, 120, 40, "xterm-256color", 0)
+	r.record(start.Add(500*time.Millisecond), "i", []byte("ls\r"))
+	// "你" 的 UTF-8 编码被拆到两段输出中
+	you := []byte("你")
+	r.record(start.Add(time.Second), "o", append([]byte("a"), you[:2]...))
+	r.record(start.Add(2*time.Second), "o", append(you[2:], 'b'))
+	r.resize(start.Add(3*time.Second), 80, 24)
+
+	if chunks := r.take(false); len(chunks) != 0 {
+		t.Fatalf("未攒够片段时不应取出录像")
+	}
+	chunks := r.take(true)
+	if len(chunks) != 1 {
+		t.Fatalf("片段数量 = %d, 期望 1", len(chunks))
+	}
+	header, events := parseCast(t, chunks[0])
+	if header.Version != 2 || header.Width != 120 || header.Height != 40 || header.Timestamp != 1700000000 {
+		t.Fatalf("录像首行错误: %+v", header)
+	}
+	if header.Env["TERM"] != "xterm-256color" {
+		t.Fatalf("录像首行缺少 TERM: %+v", header)
+	}
+
+	want := [][]interface{}{
+		{0.5, "i", "ls\r"},
+		{1.0, "o", "a"},
+		{2.0, "o", "你b"},
+		{3.0, "r", "80x24"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("事件数量 = %d, 期望 %d: %v", len(events), len(want), events)
+	}
+	for i, event := range events {
+		for j := range want[i] {
+			if event[j] != want[i][j] {
+				t.Errorf("事件 %d = %v, 期望 %v", i, event, want[i])
+				break
+			}
+		}
+	}
+	if len(r.take(true)) != 0 {
+		t.Fatalf("录像已全部取出")
+	}
+}
+
+func TestTerminalRecorderSplitAndChunk(t *testing.T) {
+	start := time.Now()
+	r := newTerminalRecorder(start, 80, 24, "", 0)
+	r.take(true)
+
+	// 超过单个事件大小的输出按字符边界拆分，需要转义的字符较多时片段也不超过 64KB
+	output := strings.Repeat("中<\x1b", terminalRecordChunkSize)
+	r.record(start, "o", []byte(output))
+	chunks := r.take(false)
+	if len(chunks) < 2 {
+		t.Fatalf("片段数量 = %d, 期望按大小拆分为多个片段", len(chunks))
+	}
+	chunks = append(chunks, r.take(true)...)
+	for _, data := range chunks {
+		if len(data) > 64*1024 {
+			t.Fatalf("片段大小 %d 超过 64KB", len(data))
+		}
+	}
+	var joined strings.Builder
+	for _, line := range strings.Split(strings.TrimSuffix(strings.Join(chunks, ""), "\n"), "\n") {
+		var event []interface{}
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("解析录像事件失败: %v", err)
+		}
+		text := event[2].(string)
+		if len(text) > terminalRecordEventSize {
+			t.Fatalf("事件大小 %d 超过上限", len(text))
+		}
+		joined.WriteString(text)
+	}
+	if joined.String() != output {
+		t.Fatalf("拆分后的输出与原始输出不一致")
+	}
+}
+
+func TestTerminalRecorderMaxSize(t *testing.T) {
+	start := time.Now()
+	r := newTerminalRecorder(start, 80, 24, "", 1024)
+	for i := 0; i < 100; i++ {
+		r.record(start, "o", []byte("0123456789"))
+	}
+	if !r.truncated {
+		t.Fatalf("超出大小上限后应标记为不完整")
+	}
+	if r.size > 1024 {
+		t.Fatalf("录像大小 %d 超过上限", r.size)
+	}
+	if data := strings.Join(r.take(true), ""); int64(len(data)) != r.size {
+		t.Fatalf("录像大小 = %d, 期望 %d", len(data), r.size)
+	}
+}
+
+func TestUTF8CompleteLen(t *testing.T) {
+	you := []byte("你")
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{"空", nil, 0},
+		{"ASCII", []byte("abc"), 3},
+		{"完整字符", []byte("a你"), 4},
+		{"不完整字符", append([]byte("a"), you[:2]...), 1},
+		{"非法字节", []byte{'a', 0xff}, 2},
+	}
+	for _, tt := range tests {
+		if got := utf8CompleteLen(tt.data); got != tt.want {
+			t.Errorf("%s: utf8CompleteLen = %d, 期望 %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTerminalAuthorize(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   config.TerminalConfig
+		username string
+		allowed  bool
+	}{
+		{"未启用", config.TerminalConfig{}, "admin", false},
+		{"未限制用户", config.TerminalConfig{Enabled: true}, "admin", true},
+		{"在允许列表中", config.TerminalConfig{Enabled: true, AllowedUsers: []string{"ops"}}, "ops", true},
+		{"不在允许列表中", config.TerminalConfig{Enabled: true, AllowedUsers: []string{"ops"}}, "admin", false},
+	}
+	for _, tt := range tests {
+		s := &TerminalService{config: tt.config}
+		if err := s.Authorize(tt.username); (err == nil) != tt.allowed {
+			t.Errorf("%s: Authorize 错误 = %v, 期望允许 %v", tt.name, err, tt.allowed)
+		}
+	}
+}
@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/dushixiang/pika/internal/config"
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/internal/repo"
+	ws "github.com/dushixiang/pika/internal/websocket"
+	"github.com/go-orz/orz"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// defaultTerminalRecordMaxSize 单个会话录像的默认大小上限（MB）
+const defaultTerminalRecordMaxSize = 10
+
+// TerminalService 远程终端服务，负责权限校验、下发终端指令、转发输入输出和会话录像
+type TerminalService struct {
+	logger              *zap.Logger
+	TerminalSessionRepo *repo.TerminalSessionRepo
+	wsManager           *ws.Manager
+	config              config.TerminalConfig
+
+	tickets  *wsTicketStore
+	mu       sync.Mutex
+	sessions map[string]*TerminalConn
+}
+
+// TerminalConn 正在进行的终端会话
+type TerminalConn struct {
+	ID          string
+	AgentID     string
+	IdleTimeout time.Duration
+	Output      chan protocol.TerminalOutput
+
+	client   *ws.Client // 打开终端时探针的连接，探针重连后终端已随旧连接结束
+	mu       sync.Mutex
+	recorder *terminalRecorder
+	closed   bool
+}
+
+func NewTerminalService(logger *zap.Logger, db *gorm.DB, wsManager *ws.Manager, cfg *config.AppConfig) *TerminalService {
+	s := &TerminalService{
+		logger:              logger.Named("terminal"),
+		TerminalSessionRepo: repo.NewTerminalSessionRepo(db),
+		wsManager:           wsManager,
+		tickets:             newWSTicketStore(),
+		sessions:            make(map[string]*TerminalConn),
+	}
+	if cfg.Terminal != nil {
+		s.config = *cfg.Terminal
+	}
+	if s.config.IdleTimeout <= 0 || s.config.IdleTimeout > protocol.TerminalMaxIdleTimeout {
+		s.config.IdleTimeout = protocol.TerminalIdleTimeout
+	}
+	if s.config.RecordMaxSize <= 0 {
+		s.config.RecordMaxSize = defaultTerminalRecordMaxSize
+	}
+	return s
+}
+
+// Authorize 校验用户是否可以使用远程终端和查看会话录像
+func (s *TerminalService) Authorize(username string) error {
+	if !s.config.Enabled {
+		return orz.NewError(403, "未启用远程终端")
+	}
+	if len(s.config.AllowedUsers) > 0 && !slices.Contains(s.config.AllowedUsers, username) {
+		return orz.NewError(403, "没有使用远程终端的权限")
+	}
+	return nil
+}
+
+// IdleTimeout 会话的空闲超时时间
+func (s *TerminalService) IdleTimeout() time.Duration {
+	return time.Duration(s.config.IdleTimeout) * time.Second
+}
+
+// CreateTicket 为已认证的用户创建打开终端的一次性凭证
+func (s *TerminalService) CreateTicket(agentID, username, clientIP string) (string, error) {
+	if err := s.Authorize(username); err != nil {
+		return "", err
+	}
+	if _, ok := s.wsManager.GetClient(agentID); !ok {
+		return "", orz.NewError(400, "探针未连接")
+	}
+	return s.tickets.issue(agentID, username, clientIP)
+}
+
+// Open 使用凭证打开终端：创建会话记录并向探针下发终端指令
+// clientIP 为建立连接的客户端地址，必须与签发凭证时的地址一致，防止凭证泄露后被他人使用
+func (s *TerminalService) Open(ctx context.Context, ticket, clientIP string, cols, rows int, term string) (*TerminalConn, error) {
+	t, ok := s.tickets.redeem(ticket)
+	if !ok {
+		return nil, orz.NewError(401, "终端凭证无效或已过期")
+	}
+	if t.clientIP != clientIP {
+		s.logger.Warn("terminal ticket client ip mismatch",
+			zap.String("agentId", t.agentID),
+			zap.String("username", t.username),
+			zap.String("ticketIp", t.clientIP),
+			zap.String("clientIp", clientIP))
+		return nil, orz.NewError(401, "终端凭证与客户端地址不匹配")
+	}
+	// 探针可能在签发凭证后断开
+	client, ok := s.wsManager.GetClient(t.agentID)
+	if !ok {
+		return nil, orz.NewError(400, "探针未连接")
+	}
+
+	cols, rows = protocol.ClampTerminalSize(cols, rows)
+	now := time.Now()
+	session := &models.TerminalSession{
+		ID:        uuid.NewString(),
+		AgentID:   t.agentID,
+		Username:  t.username,
+		ClientIP:  t.clientIP,
+		Status:    models.TerminalStatusRunning,
+		StartedAt: now.UnixMilli(),
+	}
+	if err := s.TerminalSessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	conn := &TerminalConn{
+		ID:          session.ID,
+		AgentID:     t.agentID,
+		IdleTimeout: s.IdleTimeout(),
+		Output:      make(chan protocol.TerminalOutput, 256),
+		client:      client,
+		recorder:    newTerminalRecorder(now, cols, rows, term, int64(s.config.RecordMaxSize)*1024*1024),
+	}
+	s.mu.Lock()
+	s.sessions[conn.ID] = conn
+	s.mu.Unlock()
+
+	args, err := json.Marshal(protocol.TerminalOpenRequest{
+		Cols:        cols,
+		Rows:        rows,
+		Term:        term,
+		IdleTimeout: s.config.IdleTimeout,
+	})
+	if err != nil {
+		s.Close(conn, "下发终端指令失败")
+		return nil, err
+	}
+	cmdData, err := json.Marshal(protocol.CommandRequest{
+		ID:   conn.ID,
+		Type: "terminal",
+		Args: string(args),
+	})
+	if err != nil {
+		s.Close(conn, "下发终端指令失败")
+		return nil, err
+	}
+	if err := s.sendToAgent(conn.AgentID, protocol.MessageTypeCommand, cmdData); err != nil {
+		s.Close(conn, "下发终端指令失败")
+		return nil, orz.NewError(500, "发送指令失败")
+	}
+
+	s.logger.Info("打开远程终端",
+		zap.String("agentId", conn.AgentID),
+		zap.String("sessionId", conn.ID),
+		zap.String("username", t.username),
+		zap.String("clientIp", t.clientIP))
+	return conn, nil
+}
+
+// AgentConnected 判断打开终端时的探针连接是否仍然有效
+func (s *TerminalService) AgentConnected(conn *TerminalConn) bool {
+	client, ok := s.wsManager.GetClient(conn.AgentID)
+	return ok && client == conn.client
+}
+
+// Input 转发键盘输入，并记录到录像中
+func (s *TerminalService) Input(conn *TerminalConn, data []byte) error {
+	s.record(conn, func(r *terminalRecorder) {
+		r.record(time.Now(), "i", data)
+	})
+	return s.sendInput(conn, protocol.TerminalInput{ID: conn.ID, Data: data})
+}
+
+// Resize 调整终端窗口大小
+func (s *TerminalService) Resize(conn *TerminalConn, cols, rows int) error {
+	cols, rows = protocol.ClampTerminalSize(cols, rows)
+	s.record(conn, func(r *terminalRecorder) {
+		r.resize(time.Now(), cols, rows)
+	})
+	return s.sendInput(conn, protocol.TerminalInput{ID: conn.ID, Cols: cols, Rows: rows})
+}
+
+// RecordOutput 将转发给浏览器的终端输出记录到录像中
+func (s *TerminalService) RecordOutput(conn *TerminalConn, data []byte) {
+	s.record(conn, func(r *terminalRecorder) {
+		r.record(time.Now(), "o", data)
+	})
+}
+
+// Close 结束终端会话：通知探针关闭终端，保存剩余录像并更新会话记录
+func (s *TerminalService) Close(conn *TerminalConn, reason string) {
+	s.mu.Lock()
+	delete(s.sessions, conn.ID)
+	s.mu.Unlock()
+
+	conn.mu.Lock()
+	if conn.closed {
+		conn.mu.Unlock()
+		return
+	}
+	conn.closed = true
+	chunks := conn.recorder.take(true)
+	size, truncated := conn.recorder.size, conn.recorder.truncated
+	conn.mu.Unlock()
+
+	if data, err := json.Marshal(protocol.TerminalClose{ID: conn.ID}); err == nil {
+		// 探针可能已经结束或断开，发送失败无需处理
+		_ = s.sendToAgent(conn.AgentID, protocol.MessageTypeTerminalClose, data)
+	}
+
+	// 浏览器断开时请求的 context 已取消，使用独立的 context 保存
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	s.saveRecord(ctx, conn.ID, chunks)
+	if err := s.TerminalSessionRepo.UpdateColumnsById(ctx, conn.ID, map[string]interface{}{
+		"status":           models.TerminalStatusClosed,
+		"close_reason":     reason,
+		"ended_at":         time.Now().UnixMilli(),
+		"record_size":      size,
+		"record_truncated": truncated,
+	}); err != nil {
+		s.logger.Error("更新终端会话记录失败", zap.String("sessionId", conn.ID), zap.Error(err))
+	}
+
+	s.logger.Info("远程终端已关闭",
+		zap.String("agentId", conn.AgentID),
+		zap.String("sessionId", conn.ID),
+		zap.String("reason", reason))
+}
+
+// HandleOutput 处理探针推送的终端输出
+func (s *TerminalService) HandleOutput(agentID string, output *protocol.TerminalOutput) error {
+	s.mu.Lock()
+	conn, ok := s.sessions[output.ID]
+	s.mu.Unlock()
+	if !ok || conn.AgentID != agentID {
+		// 浏览器已断开，丢弃剩余输出
+		return nil
+	}
+
+	if output.Done {
+		select {
+		case conn.Output <- *output:
+		case <-time.After(time.Second):
+		}
+		return nil
+	}
+
+	select {
+	case conn.Output <- *output:
+	default:
+		// 浏览器消费过慢时丢弃输出，避免阻塞探针的消息循环
+		s.logger.Warn("终端输出缓冲已满，丢弃输出", zap.String("sessionId", output.ID))
+	}
+	return nil
+}
+
+// CloseStaleSessions 服务启动时将上次运行遗留的会话标记为已结束
+func (s *TerminalService) CloseStaleSessions(ctx context.Context) error {
+	count, err := s.TerminalSessionRepo.CloseRunning(ctx, "服务重启，会话已中断", time.Now().UnixMilli())
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		s.logger.Info("已结束中断的终端会话", zap.Int64("count", count))
+	}
+	return nil
+}
+
+// WriteRecording 输出会话的 asciicast v2 录像
+func (s *TerminalService) WriteRecording(ctx context.Context, id string, w io.Writer) error {
+	return s.TerminalSessionRepo.EachRecordChunk(ctx, id, func(data string) error {
+		_, err := io.WriteString(w, data)
+		return err
+	})
+}
+
+// record 在会话锁内更新录像，攒够一个片段时保存到数据库
+func (s *TerminalService) record(conn *TerminalConn, fn func(r *terminalRecorder)) {
+	conn.mu.Lock()
+	if conn.closed {
+		conn.mu.Unlock()
+		return
+	}
+	fn(conn.recorder)
+	chunks := conn.recorder.take(false)
+	conn.mu.Unlock()
+	if len(chunks) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	s.saveRecord(ctx, conn.ID, chunks)
+}
+
+func (s *TerminalService) saveRecord(ctx context.Context, sessionID string, chunks []string) {
+	for _, data := range chunks {
+		if err := s.TerminalSessionRepo.AppendRecord(ctx, sessionID, data); err != nil {
+			s.logger.Error("保存终端录像失败", zap.String("sessionId", sessionID), zap.Error(err))
+			return
+		}
+	}
+}
+
+func (s *TerminalService) sendInput(conn *TerminalConn, input protocol.TerminalInput) error {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	return s.sendToAgent(conn.AgentID, protocol.MessageTypeTerminalInput, data)
+}
+
+func (s *TerminalService) sendToAgent(agentID string, msgType protocol.MessageType, data []byte) error {
+	msgData, err := json.Marshal(protocol.Message{
+		Type: msgType,
+		Data: data,
+	})
+	if err != nil {
+		return err
+	}
+	return s.wsManager.SendToClient(agentID, msgData)
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dushixiang/pika/internal/config"
+	ws "github.com/dushixiang/pika/internal/websocket"
+	"go.uber.org/zap"
+)
+
+func TestTerminalOpenClientIP(t *testing.T) {
+	s := &TerminalService{
+		logger:    zap.NewNop(),
+		wsManager: ws.NewManager(zap.NewNop(), &config.AppConfig{}),
+		tickets:   newWSTicketStore(),
+		sessions:  make(map[string]*TerminalConn),
+	}
+
+	ticket, err := s.tickets.issue("agent-1", "admin", "10.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Open(context.Background(), ticket, "10.0.0.2", 80, 24, ""); err == nil || !strings.Contains(err.Error(), "客户端地址不匹配") {
+		t.Fatalf("客户端地址不一致时应拒绝凭证, err = %v", err)
+	}
+	if _, err := s.Open(context.Background(), ticket, "10.0.0.1", 80, 24, ""); err == nil || !strings.Contains(err.Error(), "无效或已过期") {
+		t.Fatalf("被拒绝的凭证不应再次可用, err = %v", err)
+	}
+
+	// 地址一致时通过凭证校验，由于探针未连接而失败
+	ticket, err = s.tickets.issue("agent-1", "admin", "10.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Open(context.Background(), ticket, "10.0.0.1", 80, 24, ""); err == nil || !strings.Contains(err.Error(), "探针未连接") {
+		t.Fatalf("客户端地址一致时应通过凭证校验, err = %v", err)
+	}
+}
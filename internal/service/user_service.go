@@ -18,7 +18,7 @@ type UserService struct {
 // NewUserService 创建 User 服务
 func NewUserService(logger *zap.Logger, appConfig *config.AppConfig) *UserService {
 	return &UserService{
-		logger: logger,
+		logger: logger.Named("auth"),
 		users:  appConfig.Users,
 	}
 }
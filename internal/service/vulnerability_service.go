@@ -0,0 +1,499 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dushixiang/pika/internal/models"
+	"github.com/dushixiang/pika/internal/repo"
+	"github.com/go-orz/orz"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// vulnerabilityFeedMaxSize 漏洞数据源大小上限，防止异常的数据源耗尽内存
+const vulnerabilityFeedMaxSize = 256 << 20
+
+// VulnerabilityService 漏洞匹配服务（将软件清单与 OSV 格式的漏洞数据源进行匹配）
+type VulnerabilityService struct {
+	logger          *zap.Logger
+	FindingRepo     *repo.SecurityFindingRepo
+	softwareRepo    *repo.SoftwareRepo
+	imageRepo       *repo.ContainerImageRepo
+	registryClient  *registryClient
+	propertyService *PropertyService
+	eventNotifier   *EventNotifier
+	httpClient      *http.Client
+
+	scanMu     sync.Mutex
+	mu         sync.Mutex
+	lastScanAt time.Time
+}
+
+func NewVulnerabilityService(logger *zap.Logger, db *gorm.DB, propertyService *PropertyService, eventNotifier *EventNotifier) *VulnerabilityService {
+	httpClient := &http.Client{
+		Timeout: 60 * time.Second,
+	}
+	return &VulnerabilityService{
+		logger:          logger.Named("vulnerability"),
+		FindingRepo:     repo.NewSecurityFindingRepo(db),
+		softwareRepo:    repo.NewSoftwareRepo(db),
+		imageRepo:       repo.NewContainerImageRepo(db),
+		registryClient:  newRegistryClient(httpClient),
+		propertyService: propertyService,
+		eventNotifier:   eventNotifier,
+		httpClient:      httpClient,
+	}
+}
+
+// osvVulnerability OSV 漏洞记录（仅解析匹配所需字段）
+type osvVulnerability struct {
+	ID               string        `json:"id"`
+	Aliases          []string      `json:"aliases"`
+	Summary          string        `json:"summary"`
+	Affected         []osvAffected `json:"affected"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+}
+
+type osvAffected struct {
+	Package struct {
+		Name string `json:"name"`
+	} `json:"package"`
+	Ranges   []osvRange `json:"ranges"`
+	Versions []string   `json:"versions"`
+}
+
+type osvRange struct {
+	Type   string     `json:"type"`
+	Events []osvEvent `json:"events"`
+}
+
+type osvEvent struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+}
+
+// Run 启动漏洞匹配定时任务
+func (s *VulnerabilityService) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	s.logger.Info("漏洞匹配定时任务已启动")
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("漏洞匹配定时任务已停止")
+			return
+		case <-ticker.C:
+			config, err := s.propertyService.GetVulnerabilityConfig(ctx)
+			if err != nil || !config.Enabled || config.FeedURL == "" {
+				continue
+			}
+			interval := time.Duration(config.IntervalHours) * time.Hour
+			if interval <= 0 {
+				interval = 24 * time.Hour
+			}
+			if time.Since(s.getLastScanAt()) < interval {
+				continue
+			}
+			if err := s.Scan(ctx); err != nil {
+				s.logger.Error("漏洞匹配失败", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (s *VulnerabilityService) getLastScanAt() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastScanAt
+}
+
+// Scan 拉取漏洞数据源并与所有探针的软件清单和容器镜像进行匹配
+func (s *VulnerabilityService) Scan(ctx context.Context) error {
+	if !s.scanMu.TryLock() {
+		return orz.NewError(400, "漏洞匹配正在进行中")
+	}
+	defer s.scanMu.Unlock()
+
+	config, err := s.propertyService.GetVulnerabilityConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if config.FeedURL == "" {
+		return orz.NewError(400, "未配置漏洞数据源地址")
+	}
+
+	vulns, err := s.fetchFeed(ctx, config.FeedURL)
+	if err != nil {
+		return err
+	}
+
+	// 按软件名称索引漏洞
+	vulnsByPackage := make(map[string][]osvVulnerability)
+	for _, vuln := range vulns {
+		for _, affected := range vuln.Affected {
+			name := strings.ToLower(affected.Package.Name)
+			if name != "" {
+				vulnsByPackage[name] = append(vulnsByPackage[name], vuln)
+			}
+		}
+	}
+
+	inventories, err := s.softwareRepo.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	images, err := s.imageRepo.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.FindingRepo.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	existingByID := make(map[string]models.SecurityFinding, len(existing))
+	for _, finding := range existing {
+		existingByID[finding.ID] = finding
+	}
+
+	now := time.Now().UnixMilli()
+	matched := make(map[string]bool)
+	var findings []models.SecurityFinding
+	// 按探针记录本次新发现的漏洞，用于发送安全审计通知
+	newFindings := make(map[string][]models.SecurityFinding)
+	for _, inventory := range inventories {
+		for _, vuln := range vulnsByPackage[inventory.Name] {
+			fixedVersion, ok := matchOSVAffected(vuln, inventory.Name, inventory.Version)
+			if !ok {
+				continue
+			}
+
+			id := fmt.Sprintf("%s:%s:%s", inventory.AgentID, inventory.Name, vuln.ID)
+			if matched[id] {
+				continue
+			}
+			matched[id] = true
+
+			finding := models.SecurityFinding{
+				ID:               id,
+				AgentID:          inventory.AgentID,
+				Software:         inventory.Name,
+				InstalledVersion: inventory.Version,
+				VulnID:           vuln.ID,
+				Aliases:          strings.Join(vuln.Aliases, ","),
+				Summary:          vuln.Summary,
+				Severity:         normalizeSeverity(vuln.DatabaseSpecific.Severity),
+				FixedVersion:     fixedVersion,
+				FirstSeenAt:      now,
+				LastSeenAt:       now,
+			}
+			if old, ok := existingByID[id]; ok {
+				finding.FirstSeenAt = old.FirstSeenAt
+			} else {
+				newFindings[finding.AgentID] = append(newFindings[finding.AgentID], finding)
+			}
+			findings = append(findings, finding)
+		}
+	}
+
+	// 容器镜像按镜像名称匹配，标签作为版本号，数据源中列出的摘要也视为受影响
+	for _, image := range images {
+		ref, ok := parseImageReference(image.Image)
+		if !ok {
+			continue
+		}
+		name := strings.ToLower(ref.Name())
+		software := "image:" + name
+		installed := ref.Tag
+		if installed == "" {
+			installed = image.Digest
+		}
+		for _, vuln := range vulnsByPackage[name] {
+			fixedVersion, ok := matchImageAffected(vuln, name, ref.Tag, image.Digest)
+			if !ok {
+				continue
+			}
+
+			id := fmt.Sprintf("%s:%s:%s", image.AgentID, software, vuln.ID)
+			if matched[id] {
+				continue
+			}
+			matched[id] = true
+
+			finding := models.SecurityFinding{
+				ID:               id,
+				AgentID:          image.AgentID,
+				Software:         software,
+				InstalledVersion: installed,
+				VulnID:           vuln.ID,
+				Aliases:          strings.Join(vuln.Aliases, ","),
+				Summary:          vuln.Summary,
+				Severity:         normalizeSeverity(vuln.DatabaseSpecific.Severity),
+				FixedVersion:     fixedVersion,
+				FirstSeenAt:      now,
+				LastSeenAt:       now,
+			}
+			if old, ok := existingByID[id]; ok {
+				finding.FirstSeenAt = old.FirstSeenAt
+			} else {
+				newFindings[finding.AgentID] = append(newFindings[finding.AgentID], finding)
+			}
+			findings = append(findings, finding)
+		}
+	}
+
+	// 已升级或数据源中不再匹配的记录直接移除
+	var staleIDs []string
+	for id := range existingByID {
+		if !matched[id] {
+			staleIDs = append(staleIDs, id)
+		}
+	}
+
+	if err := s.FindingRepo.SaveAll(ctx, findings); err != nil {
+		return err
+	}
+	if len(staleIDs) > 0 {
+		if err := s.FindingRepo.DeleteByIdIn(ctx, staleIDs); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	s.lastScanAt = time.Now()
+	s.mu.Unlock()
+
+	s.logger.Info("漏洞匹配完成",
+		zap.Int("vulnerabilities", len(vulns)),
+		zap.Int("findings", len(findings)),
+		zap.Int("resolved", len(staleIDs)))
+
+	for agentID, agentFindings := range newFindings {
+		s.notifyNewFindings(agentID, agentFindings)
+	}
+
+	if config.RegistryCheck {
+		s.checkImageDrift(ctx, images)
+	}
+	return nil
+}
+
+// checkImageDrift 查询镜像标签在仓库中的当前摘要，标记运行中镜像已落后于标签的容器
+func (s *VulnerabilityService) checkImageDrift(ctx context.Context, images []models.ContainerImage) {
+	// 同一标签在一次检查中只查询一次仓库，查询失败时记为空，不影响其他镜像
+	registryDigests := make(map[string]string)
+	newDrifts := make(map[string][]models.ContainerImage)
+	checked, drifted := 0, 0
+	for _, image := range images {
+		ref, ok := parseImageReference(image.Image)
+		// 按摘要引用的镜像和本地构建的镜像没有可比较的标签
+		if !ok || ref.Tag == "" || ref.Digest != "" || image.Digest == "" {
+			continue
+		}
+
+		key := ref.Registry + "/" + ref.Repository + ":" + ref.Tag
+		registryDigest, ok := registryDigests[key]
+		if !ok {
+			digest, err := s.registryClient.manifestDigest(ctx, ref)
+			if err != nil {
+				s.logger.Warn("查询镜像仓库摘要失败", zap.String("image", image.Image), zap.Error(err))
+			}
+			registryDigest = digest
+			registryDigests[key] = digest
+		}
+		if registryDigest == "" {
+			continue
+		}
+
+		drift := image.Digest != registryDigest
+		if err := s.imageRepo.UpdateRegistryDigest(ctx, image.ID, registryDigest, drift, time.Now().UnixMilli()); err != nil {
+			s.logger.Error("更新镜像仓库摘要失败", zap.String("id", image.ID), zap.Error(err))
+			continue
+		}
+		checked++
+		if drift {
+			drifted++
+			if !image.Drift {
+				newDrifts[image.AgentID] = append(newDrifts[image.AgentID], image)
+			}
+		}
+	}
+
+	s.logger.Info("镜像摘要检查完成", zap.Int("checked", checked), zap.Int("drift", drifted))
+
+	for agentID, agentImages := range newDrifts {
+		var names []string
+		for _, image := range agentImages {
+			if len(names) < 5 {
+				names = append(names, fmt.Sprintf("%s(%s)", image.ContainerName, image.Image))
+			}
+		}
+		message := fmt.Sprintf("%d 个容器运行的镜像与仓库中的标签不一致: %s", len(agentImages), strings.Join(names, ", "))
+		if len(agentImages) > len(names) {
+			message += " 等"
+		}
+		s.eventNotifier.Notify(agentID, models.NotificationEventAudit, "warning", message)
+	}
+}
+
+// notifyNewFindings 发送新发现漏洞的安全审计通知，存在高危及以上漏洞时按严重级别通知
+func (s *VulnerabilityService) notifyNewFindings(agentID string, findings []models.SecurityFinding) {
+	level := "info"
+	var vulnIDs []string
+	for _, finding := range findings {
+		switch finding.Severity {
+		case "critical", "high":
+			level = "critical"
+		case "medium":
+			if level == "info" {
+				level = "warning"
+			}
+		}
+		if len(vulnIDs) < 5 {
+			vulnIDs = append(vulnIDs, fmt.Sprintf("%s(%s)", finding.VulnID, finding.Software))
+		}
+	}
+
+	message := fmt.Sprintf("发现 %d 个新漏洞: %s", len(findings), strings.Join(vulnIDs, ", "))
+	if len(findings) > len(vulnIDs) {
+		message += " 等"
+	}
+	s.eventNotifier.Notify(agentID, models.NotificationEventAudit, level, message)
+}
+
+// fetchFeed 拉取 OSV 格式的漏洞数据源，支持 JSON 数组或 {"vulns": [...]} 两种格式
+func (s *VulnerabilityService) fetchFeed(ctx context.Context, feedURL string) ([]osvVulnerability, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("拉取漏洞数据源失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("拉取漏洞数据源失败: HTTP %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, vulnerabilityFeedMaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("读取漏洞数据源失败: %w", err)
+	}
+	if len(body) > vulnerabilityFeedMaxSize {
+		return nil, fmt.Errorf("漏洞数据源超过 %d MB", vulnerabilityFeedMaxSize>>20)
+	}
+
+	var vulns []osvVulnerability
+	if err := json.Unmarshal(body, &vulns); err == nil {
+		return vulns, nil
+	}
+
+	var wrapped struct {
+		Vulns []osvVulnerability `json:"vulns"`
+	}
+	if err := json.Unmarshal(body, &wrapped); err != nil {
+		return nil, fmt.Errorf("解析漏洞数据源失败: %w", err)
+	}
+	return wrapped.Vulns, nil
+}
+
+// matchOSVAffected 判断软件版本是否受漏洞影响，返回修复版本
+func matchOSVAffected(vuln osvVulnerability, name, version string) (string, bool) {
+	for _, affected := range vuln.Affected {
+		if !strings.EqualFold(affected.Package.Name, name) {
+			continue
+		}
+
+		for _, v := range affected.Versions {
+			if CompareVersion(v, version) == 0 {
+				return "", true
+			}
+		}
+
+		for _, r := range affected.Ranges {
+			if r.Type == "GIT" {
+				continue
+			}
+			if fixed, ok := matchOSVRange(r.Events, version); ok {
+				return fixed, true
+			}
+		}
+	}
+	return "", false
+}
+
+// matchImageAffected 判断容器镜像是否受漏洞影响，数据源中列出镜像摘要时按摘要精确匹配，
+// 否则以数字开头的标签按版本号匹配，latest 等标签无法判断版本时不匹配
+func matchImageAffected(vuln osvVulnerability, name, tag, digest string) (string, bool) {
+	if digest != "" {
+		for _, affected := range vuln.Affected {
+			if strings.EqualFold(affected.Package.Name, name) && slices.Contains(affected.Versions, digest) {
+				return "", true
+			}
+		}
+	}
+	version := strings.TrimPrefix(tag, "v")
+	if version == "" || version[0] < '0' || version[0] > '9' {
+		return "", false
+	}
+	return matchOSVAffected(vuln, name, version)
+}
+
+// matchOSVRange 按 OSV 事件顺序判断版本是否落在受影响区间内
+func matchOSVRange(events []osvEvent, version string) (string, bool) {
+	affected := false
+	for _, event := range events {
+		switch {
+		case event.Introduced != "":
+			if event.Introduced == "0" || CompareVersion(version, event.Introduced) >= 0 {
+				affected = true
+			}
+		case event.Fixed != "":
+			if CompareVersion(version, event.Fixed) >= 0 {
+				affected = false
+			} else if affected {
+				return event.Fixed, true
+			}
+		case event.LastAffected != "":
+			if CompareVersion(version, event.LastAffected) > 0 {
+				affected = false
+			} else if affected {
+				return "", true
+			}
+		}
+	}
+	return "", affected
+}
+
+// normalizeSeverity 统一漏洞严重程度
+func normalizeSeverity(severity string) string {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return "critical"
+	case "high":
+		return "high"
+	case "moderate", "medium":
+		return "medium"
+	case "low":
+		return "low"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import "testing"
+
+func TestMatchOSVRange(t *testing.T) {
+	// 两段受影响区间：[1.0, 1.5) 和 [2.0, 2.3)
+	multiple := []osvEvent{
+		{Introduced: "1.0"},
+		{Fixed: "1.5"},
+		{Introduced: "2.0"},
+		{Fixed: "2.3"},
+	}
+	lastAffected := []osvEvent{
+		{Introduced: "0"},
+		{LastAffected: "3.0.7"},
+	}
+	tests := []struct {
+		name      string
+		events    []osvEvent
+		version   string
+		wantFixed string
+		want      bool
+	}{
+		{"早于首个区间", multiple, "0.9", "", false},
+		{"等于引入版本", multiple, "1.0", "1.5", true},
+		{"位于第一个区间", multiple, "1.2", "1.5", true},
+		{"等于修复版本", multiple, "1.5", "", false},
+		{"位于两个区间之间", multiple, "1.7", "", false},
+		{"位于第二个区间", multiple, "2.1", "2.3", true},
+		{"第二个区间的预发布版本", multiple, "2.3-rc1", "2.3", true},
+		{"等于第二个修复版本", multiple, "2.3", "", false},
+		{"晚于所有区间", multiple, "3.0", "", false},
+		{"引入版本为 0", lastAffected, "1.0", "", true},
+		{"等于最后受影响版本", lastAffected, "3.0.7", "", true},
+		{"晚于最后受影响版本", lastAffected, "3.0.8", "", false},
+		{"只有引入版本", []osvEvent{{Introduced: "2.0"}}, "2.5", "", true},
+	}
+	for _, tt := range tests {
+		fixed, got := matchOSVRange(tt.events, tt.version)
+		if got != tt.want || fixed != tt.wantFixed {
+			t.Errorf("%s: matchOSVRange(%q) = %q, %v, 期望 %q, %v", tt.name, tt.version, fixed, got, tt.wantFixed, tt.want)
+		}
+	}
+}
+
+func TestMatchOSVAffected(t *testing.T) {
+	vuln := osvVulnerability{
+		ID: "CVE-2024-0001",
+		Affected: []osvAffected{
+			{
+				Versions: []string{"1.1.1w"},
+				Ranges: []osvRange{
+					{Type: "ECOSYSTEM", Events: []osvEvent{{Introduced: "3.0.0"}, {Fixed: "3.0.13"}}},
+					{Type: "GIT", Events: []osvEvent{{Introduced: "0"}}},
+				},
+			},
+		},
+	}
+	vuln.Affected[0].Package.Name = "OpenSSL"
+
+	tests := []struct {
+		name      string
+		pkg       string
+		version   string
+		wantFixed string
+		want      bool
+	}{
+		{"列出的受影响版本", "openssl", "1.1.1w", "", true},
+		{"区间内的版本", "openssl", "3.0.2", "3.0.13", true},
+		{"已修复版本", "openssl", "3.0.13", "", false},
+		{"忽略 GIT 区间", "openssl", "1.1.1v", "", false},
+		{"软件名称不一致", "openssh", "3.0.2", "", false},
+	}
+	for _, tt := range tests {
+		fixed, got := matchOSVAffected(vuln, tt.pkg, tt.version)
+		if got != tt.want || fixed != tt.wantFixed {
+			t.Errorf("%s: matchOSVAffected(%q, %q) = %q, %v, 期望 %q, %v", tt.name, tt.pkg, tt.version, fixed, got, tt.wantFixed, tt.want)
+		}
+	}
+}
+
+func TestMatchImageAffected(t *testing.T) {
+	vuln := osvVulnerability{
+		ID: "CVE-2024-0002",
+		Affected: []osvAffected{
+			{
+				Versions: []string{"sha256:bad"},
+				Ranges: []osvRange{
+					{Type: "ECOSYSTEM", Events: []osvEvent{{Introduced: "0"}, {Fixed: "1.25.3"}}},
+				},
+			},
+		},
+	}
+	vuln.Affected[0].Package.Name = "nginx"
+
+	tests := []struct {
+		name      string
+		tag       string
+		digest    string
+		wantFixed string
+		want      bool
+	}{
+		{"列出的受影响摘要", "latest", "sha256:bad", "", true},
+		{"区间内的标签", "1.25.2-alpine", "sha256:good", "1.25.3", true},
+		{"带 v 前缀的标签", "v1.24", "", "1.25.3", true},
+		{"已修复的标签", "1.25.3", "sha256:good", "", false},
+		{"无法判断版本的标签", "latest", "sha256:good", "", false},
+		{"按摘要引用", "", "sha256:good", "", false},
+	}
+	for _, tt := range tests {
+		fixed, got := matchImageAffected(vuln, "nginx", tt.tag, tt.digest)
+		if got != tt.want || fixed != tt.wantFixed {
+			t.Errorf("%s: matchImageAffected(%q, %q) = %q, %v, 期望 %q, %v", tt.name, tt.tag, tt.digest, fixed, got, tt.wantFixed, tt.want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// wsTicketTTL 一次性凭证的有效期，凭证只能使用一次
+const wsTicketTTL = 30 * time.Second
+
+// wsTicket 浏览器建立 WebSocket 连接的一次性凭证
+// 浏览器建立 WebSocket 连接时无法携带认证请求头，需要先通过已认证的接口换取凭证
+type wsTicket struct {
+	agentID   string
+	username  string
+	clientIP  string
+	expiresAt time.Time
+}
+
+// wsTicketStore 保存已签发但尚未使用的凭证
+type wsTicketStore struct {
+	mu      sync.Mutex
+	tickets map[string]wsTicket
+}
+
+func newWSTicketStore() *wsTicketStore {
+	return &wsTicketStore{tickets: make(map[string]wsTicket)}
+}
+
+// issue 为已认证的用户签发凭证，同时清理已过期的凭证
+func (s *wsTicketStore) issue(agentID, username, clientIP string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	ticket := hex.EncodeToString(b)
+
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, t := range s.tickets {
+		if now.After(t.expiresAt) {
+			delete(s.tickets, key)
+		}
+	}
+	s.tickets[ticket] = wsTicket{
+		agentID:   agentID,
+		username:  username,
+		clientIP:  clientIP,
+		expiresAt: now.Add(wsTicketTTL),
+	}
+	return ticket, nil
+}
+
+// redeem 使用凭证，凭证无论是否有效都会被删除
+func (s *wsTicketStore) redeem(ticket string) (wsTicket, bool) {
+	s.mu.Lock()
+	t, ok := s.tickets[ticket]
+	delete(s.tickets, ticket)
+	s.mu.Unlock()
+	if !ok || time.Now().After(t.expiresAt) {
+		return wsTicket{}, false
+	}
+	return t, true
+}
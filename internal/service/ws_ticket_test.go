@@ -0,0 +1,37 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWSTicketStore(t *testing.T) {
+	store := newWSTicketStore()
+	ticket, err := store.issue("agent-1", "admin", "127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := store.redeem(ticket)
+	if !ok || got.agentID != "agent-1" || got.username != "admin" || got.clientIP != "127.0.0.1" {
+		t.Fatalf("redeem = %+v, %v", got, ok)
+	}
+	if _, ok := store.redeem(ticket); ok {
+		t.Fatalf("凭证只能使用一次")
+	}
+	if _, ok := store.redeem("unknown"); ok {
+		t.Fatalf("未签发的凭证不应有效")
+	}
+
+	expired, err := store.issue("agent-1", "admin", "127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.mu.Lock()
+	item := store.tickets[expired]
+	item.expiresAt = time.Now().Add(-time.Second)
+	store.tickets[expired] = item
+	store.mu.Unlock()
+	if _, ok := store.redeem(expired); ok {
+		t.Fatalf("过期的凭证不应有效")
+	}
+}
@@ -0,0 +1,11 @@
+package utils
+
+import "strings"
+
+// EscapeCSVCell 在以 =、+、-、@ 等开头的单元格前加单引号，防止在表格软件中打开时被当作公式执行
+func EscapeCSVCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
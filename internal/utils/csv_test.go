@@ -0,0 +1,23 @@
+package utils
+
+import "testing"
+
+func TestEscapeCSVCell(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "", want: ""},
+		{value: "web-01", want: "web-01"},
+		{value: "=HYPERLINK(\"http://evil\")", want: "'=HYPERLINK(\"http://evil\")"},
+		{value: "+1", want: "'+1"},
+		{value: "-cmd", want: "'-cmd"},
+		{value: "@SUM(A1)", want: "'@SUM(A1)"},
+		{value: "\tleading tab", want: "'\tleading tab"},
+	}
+	for _, tt := range tests {
+		if got := EscapeCSVCell(tt.value); got != tt.want {
+			t.Errorf("EscapeCSVCell(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package utils
+
+import "github.com/labstack/echo/v4"
+
+// BaseURL 返回对外访问的完整地址（协议、主机和子路径前缀），用于生成安装脚本等需要完整地址的内容
+func BaseURL(c echo.Context) string {
+	if baseURL, ok := c.Get("baseURL").(string); ok && baseURL != "" {
+		return baseURL
+	}
+	return c.Scheme() + "://" + c.Request().Host
+}
+
+// BasePath 返回对外访问的子路径前缀，根路径部署时为空
+func BasePath(c echo.Context) string {
+	basePath, _ := c.Get("basePath").(string)
+	return basePath
+}
+
+// ClientIP 返回可信的客户端 IP，使用服务端配置的 IPExtractor（未信任代理时为连接的对端地址）；
+// 未配置时同样取连接的对端地址，不会像 echo 的默认行为那样直接采用客户端传入的 X-Forwarded-For、X-Real-IP
+func ClientIP(c echo.Context) string {
+	if extract := c.Echo().IPExtractor; extract != nil {
+		return extract(c.Request())
+	}
+	return echo.ExtractIPDirect()(c.Request())
+}
@@ -4,24 +4,55 @@ import (
 	"context"
 	"encoding/json"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/dushixiang/pika/internal/config"
 	"github.com/dushixiang/pika/internal/protocol"
 	"github.com/gorilla/websocket"
 	"go.uber.org/zap"
 )
 
+// 连接保活默认值
+const (
+	defaultPingInterval = 30 * time.Second
+	defaultPongTimeout  = 60 * time.Second
+	defaultWriteTimeout = 10 * time.Second
+)
+
 // Client WebSocket客户端
 type Client struct {
 	ID         string          // 探针ID
 	Conn       *websocket.Conn // WebSocket连接
 	Send       chan []byte     // 发送消息通道
 	Manager    *Manager        // 管理器引用
-	LastActive time.Time       // 最后活跃时间
+	lastActive atomic.Int64    // 最后一次收到消息或 Pong 的时间（毫秒），读写协程与管理器并发访问
 	closed     bool            // 标记channel是否已关闭
 	closeMu    sync.Mutex      // 保护closed字段
 }
 
+// NewClient 创建探针客户端
+func NewClient(id string, conn *websocket.Conn, manager *Manager) *Client {
+	client := &Client{
+		ID:      id,
+		Conn:    conn,
+		Send:    make(chan []byte, 256),
+		Manager: manager,
+	}
+	client.touch()
+	return client
+}
+
+// touch 记录客户端活跃时间
+func (c *Client) touch() {
+	c.lastActive.Store(time.Now().UnixMilli())
+}
+
+// LastActive 最后一次收到消息或 Pong 的时间
+func (c *Client) LastActive() time.Time {
+	return time.UnixMilli(c.lastActive.Load())
+}
+
 // Manager WebSocket连接管理器
 type Manager struct {
 	clients    map[string]*Client // 客户端映射 probeID -> Client
@@ -31,20 +62,59 @@ type Manager struct {
 	mu         sync.RWMutex       // 读写锁
 	logger     *zap.Logger        // 日志
 	onMessage  MessageHandler     // 消息处理器
+	onBinary   BinaryHandler      // 二进制消息处理器
+
+	pingInterval time.Duration // 发送 Ping 的间隔
+	pongTimeout  time.Duration // 读超时，超时未收到 Pong 或消息视为半开连接
+	writeTimeout time.Duration // 单条消息写入超时
+	compression  bool          // 是否接受探针的 permessage-deflate 压缩协商
 }
 
 // MessageHandler 消息处理器接口
 type MessageHandler func(ctx context.Context, probeID string, messageType string, data json.RawMessage) error
 
+// BinaryHandler 二进制消息处理器接口，协商使用 MessagePack 编码的探针以二进制帧发送指标
+type BinaryHandler func(ctx context.Context, probeID string, data []byte) error
+
 // NewManager 创建新的WebSocket管理器
-func NewManager(logger *zap.Logger) *Manager {
-	return &Manager{
-		clients:    make(map[string]*Client),
-		register:   make(chan *Client, 10),
-		unregister: make(chan *Client, 10),
-		broadcast:  make(chan []byte, 256),
-		logger:     logger,
+func NewManager(logger *zap.Logger, cfg *config.AppConfig) *Manager {
+	m := &Manager{
+		clients:      make(map[string]*Client),
+		register:     make(chan *Client, 10),
+		unregister:   make(chan *Client, 10),
+		broadcast:    make(chan []byte, 256),
+		logger:       logger.Named("ws"),
+		pingInterval: defaultPingInterval,
+		pongTimeout:  defaultPongTimeout,
+		writeTimeout: defaultWriteTimeout,
+		compression:  true,
+	}
+
+	if ws := cfg.WebSocket; ws != nil {
+		if ws.PingInterval > 0 {
+			m.pingInterval = time.Duration(ws.PingInterval) * time.Second
+		}
+		if ws.PongTimeout > 0 {
+			m.pongTimeout = time.Duration(ws.PongTimeout) * time.Second
+		}
+		if ws.WriteTimeout > 0 {
+			m.writeTimeout = time.Duration(ws.WriteTimeout) * time.Second
+		}
+		m.compression = !ws.DisableCompression
 	}
+	// 超时时间不大于 Ping 间隔时，正常连接也会在两次 Ping 之间被断开
+	if m.pongTimeout <= m.pingInterval {
+		m.logger.Warn("pong timeout must be greater than ping interval, using twice the ping interval",
+			zap.Duration("pingInterval", m.pingInterval),
+			zap.Duration("pongTimeout", m.pongTimeout))
+		m.pongTimeout = 2 * m.pingInterval
+	}
+	return m
+}
+
+// CompressionEnabled 是否接受探针的 permessage-deflate 压缩协商
+func (m *Manager) CompressionEnabled() bool {
+	return m.compression
 }
 
 // SetMessageHandler 设置消息处理器
@@ -52,11 +122,21 @@ func (m *Manager) SetMessageHandler(handler MessageHandler) {
 	m.onMessage = handler
 }
 
+// SetBinaryHandler 设置二进制消息处理器
+func (m *Manager) SetBinaryHandler(handler BinaryHandler) {
+	m.onBinary = handler
+}
+
 // Run 启动管理器
 func (m *Manager) Run(ctx context.Context) {
-	ticker := time.NewTicker(30 * time.Second)
+	ticker := time.NewTicker(m.pingInterval)
 	defer ticker.Stop()
 
+	m.logger.Info("websocket manager started",
+		zap.Duration("pingInterval", m.pingInterval),
+		zap.Duration("pongTimeout", m.pongTimeout),
+		zap.Duration("writeTimeout", m.writeTimeout))
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -95,16 +175,16 @@ func (m *Manager) registerClient(client *Client) {
 	m.logger.Info("agent connected", zap.String("agentID", client.ID), zap.Int("totalClients", len(m.clients)))
 }
 
-// unregisterClient 注销客户端
+// unregisterClient 注销客户端，探针已重连时只关闭旧连接，不影响新连接
 func (m *Manager) unregisterClient(client *Client) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if _, exists := m.clients[client.ID]; exists {
+	if current, exists := m.clients[client.ID]; exists && current == client {
 		delete(m.clients, client.ID)
-		client.closeChannel()
 		m.logger.Info("agent disconnected", zap.String("agentID", client.ID), zap.Int("totalClients", len(m.clients)))
 	}
+	client.closeChannel()
 }
 
 // broadcastMessage 广播消息
@@ -122,31 +202,25 @@ func (m *Manager) broadcastMessage(message []byte) {
 	}
 }
 
-// checkInactiveClients 检查不活跃的客户端
+// checkInactiveClients 回收半开连接：超过 pongTimeout 未收到 Pong 或任何消息的客户端
+// 正常情况下读超时会先让 ReadPump 退出，这里兜底处理读协程阻塞在处理消息等情况
 func (m *Manager) checkInactiveClients() {
 	m.mu.RLock()
 	inactiveClients := make([]*Client, 0)
-	timeout := 2 * time.Minute
-
 	for _, client := range m.clients {
-		if time.Since(client.LastActive) > timeout {
+		if time.Since(client.LastActive()) > m.pongTimeout {
 			inactiveClients = append(inactiveClients, client)
 		}
 	}
 	m.mu.RUnlock()
 
-	// 断开不活跃的客户端
+	// 在 Run 协程内直接注销，避免向自身消费的 unregister 通道发送造成阻塞
 	for _, client := range inactiveClients {
-		// 再次检查客户端是否仍然存在（避免竞态条件）
-		m.mu.RLock()
-		_, exists := m.clients[client.ID]
-		m.mu.RUnlock()
-
-		if exists {
-			m.logger.Warn("agent inactive timeout, disconnecting", zap.String("agentID", client.ID))
-			client.Conn.Close()
-			m.unregister <- client
-		}
+		m.logger.Warn("agent inactive timeout, disconnecting",
+			zap.String("agentID", client.ID),
+			zap.Time("lastActive", client.LastActive()))
+		client.Conn.Close()
+		m.unregisterClient(client)
 	}
 }
 
@@ -202,15 +276,16 @@ func (c *Client) ReadPump(ctx context.Context) {
 		c.Conn.Close()
 	}()
 
-	c.Conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+	pongTimeout := c.Manager.pongTimeout
+	c.Conn.SetReadDeadline(time.Now().Add(pongTimeout))
 	c.Conn.SetPongHandler(func(string) error {
-		c.Conn.SetReadDeadline(time.Now().Add(60 * time.Second))
-		c.LastActive = time.Now()
+		c.Conn.SetReadDeadline(time.Now().Add(pongTimeout))
+		c.touch()
 		return nil
 	})
 
 	for {
-		_, message, err := c.Conn.ReadMessage()
+		messageType, message, err := c.Conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				c.Manager.logger.Error("websocket read error", zap.Error(err), zap.String("agentID", c.ID))
@@ -218,7 +293,17 @@ func (c *Client) ReadPump(ctx context.Context) {
 			break
 		}
 
-		c.LastActive = time.Now()
+		c.Conn.SetReadDeadline(time.Now().Add(pongTimeout))
+		c.touch()
+
+		if messageType == websocket.BinaryMessage {
+			if c.Manager.onBinary != nil {
+				if err := c.Manager.onBinary(ctx, c.ID, message); err != nil {
+					c.Manager.logger.Error("failed to handle binary message", zap.Error(err), zap.String("agentID", c.ID))
+				}
+			}
+			continue
+		}
 
 		// 解析消息
 		var msg protocol.Message
@@ -238,7 +323,8 @@ func (c *Client) ReadPump(ctx context.Context) {
 
 // WritePump 向客户端写入消息
 func (c *Client) WritePump() {
-	ticker := time.NewTicker(30 * time.Second)
+	writeTimeout := c.Manager.writeTimeout
+	ticker := time.NewTicker(c.Manager.pingInterval)
 	defer func() {
 		ticker.Stop()
 		c.Conn.Close()
@@ -247,7 +333,7 @@ func (c *Client) WritePump() {
 	for {
 		select {
 		case message, ok := <-c.Send:
-			c.Conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			c.Conn.SetWriteDeadline(time.Now().Add(writeTimeout))
 			if !ok {
 				// 通道已关闭
 				c.Conn.WriteMessage(websocket.CloseMessage, []byte{})
@@ -260,7 +346,7 @@ func (c *Client) WritePump() {
 			}
 
 		case <-ticker.C:
-			c.Conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			c.Conn.SetWriteDeadline(time.Now().Add(writeTimeout))
 			if err := c.Conn.WriteMessage(websocket.PingMessage, nil); err != nil {
 				return
 			}
@@ -9,13 +9,14 @@ import (
 	"github.com/dushixiang/pika/internal/repo"
 	"github.com/dushixiang/pika/internal/service"
 	"github.com/dushixiang/pika/internal/websocket"
+	"github.com/dushixiang/pika/pkg/logging"
 	"github.com/google/wire"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 // InitializeApp 初始化应用
-func InitializeApp(logger *zap.Logger, db *gorm.DB, cfg *config.AppConfig) (*AppComponents, error) {
+func InitializeApp(logger *zap.Logger, logManager *logging.Manager, db *gorm.DB, cfg *config.AppConfig) (*AppComponents, error) {
 	wire.Build(
 		service.NewAccountService,
 		service.NewAgentService,
@@ -30,12 +31,37 @@ func InitializeApp(logger *zap.Logger, db *gorm.DB, cfg *config.AppConfig) (*App
 		service.NewMetricService,
 		service.NewGeoIPService,
 		service.NewDDNSService,
+		service.NewOverviewService,
+		service.NewSoftwareService,
+		service.NewVulnerabilityService,
+		service.NewLogTailService,
+		service.NewRemediationService,
+		service.NewPingService,
+		service.NewAgentDataService,
+		service.NewLoggingService,
+		service.NewDatabaseService,
+		service.NewNotificationQueueService,
+		service.NewGroupService,
+		service.NewEventNotifier,
+		service.NewPrometheusService,
+		service.NewRemoteWriter,
+		service.NewInfluxDBExporter,
+		service.NewMetricArchiver,
+		service.NewPowerService,
+		service.NewCollectorConfigService,
+		service.NewDiskUsageService,
+		service.NewDemoService,
+		service.NewTerminalService,
+		service.NewCommandService,
+		service.NewProcessService,
+		service.NewDockerService,
 
 		service.NewNotifier,
 		// WebSocket Manager
 		websocket.NewManager,
 
 		// Repositories
+		repo.NewMetricStore,
 		repo.NewTamperRepo,
 		repo.NewDDNSConfigRepo,
 		repo.NewDDNSRecordRepo,
@@ -45,11 +71,32 @@ func InitializeApp(logger *zap.Logger, db *gorm.DB, cfg *config.AppConfig) (*App
 		handler.NewAlertHandler,
 		handler.NewPropertyHandler,
 		handler.NewMonitorHandler,
+		handler.NewPushHandler,
 		handler.NewApiKeyHandler,
 		handler.NewAccountHandler,
 		handler.NewTamperHandler,
 		handler.NewDNSProviderHandler,
 		handler.NewDDNSHandler,
+		handler.NewOverviewHandler,
+		handler.NewSoftwareHandler,
+		handler.NewVulnerabilityHandler,
+		handler.NewLogTailHandler,
+		handler.NewRemediationHandler,
+		handler.NewPingHandler,
+		handler.NewAgentDataHandler,
+		handler.NewLoggingHandler,
+		handler.NewGraphQLHandler,
+		handler.NewHealthHandler,
+		handler.NewNotificationJobHandler,
+		handler.NewGroupHandler,
+		handler.NewPrometheusHandler,
+		handler.NewWidgetHandler,
+		handler.NewPowerHandler,
+		handler.NewDiskUsageHandler,
+		handler.NewTerminalHandler,
+		handler.NewCommandHandler,
+		handler.NewProcessHandler,
+		handler.NewDockerHandler,
 
 		// App Components
 		wire.Struct(new(AppComponents), "*"),
@@ -59,24 +106,58 @@ func InitializeApp(logger *zap.Logger, db *gorm.DB, cfg *config.AppConfig) (*App
 
 // AppComponents 应用组件
 type AppComponents struct {
-	AccountHandler     *handler.AccountHandler
-	AgentHandler       *handler.AgentHandler
-	ApiKeyHandler      *handler.ApiKeyHandler
-	AlertHandler       *handler.AlertHandler
-	PropertyHandler    *handler.PropertyHandler
-	MonitorHandler     *handler.MonitorHandler
-	TamperHandler      *handler.TamperHandler
-	DNSProviderHandler *handler.DNSProviderHandler
-	DDNSHandler        *handler.DDNSHandler
+	AccountHandler         *handler.AccountHandler
+	AgentHandler           *handler.AgentHandler
+	ApiKeyHandler          *handler.ApiKeyHandler
+	AlertHandler           *handler.AlertHandler
+	PropertyHandler        *handler.PropertyHandler
+	MonitorHandler         *handler.MonitorHandler
+	PushHandler            *handler.PushHandler
+	TamperHandler          *handler.TamperHandler
+	DNSProviderHandler     *handler.DNSProviderHandler
+	DDNSHandler            *handler.DDNSHandler
+	OverviewHandler        *handler.OverviewHandler
+	SoftwareHandler        *handler.SoftwareHandler
+	VulnerabilityHandler   *handler.VulnerabilityHandler
+	LogTailHandler         *handler.LogTailHandler
+	RemediationHandler     *handler.RemediationHandler
+	PingHandler            *handler.PingHandler
+	AgentDataHandler       *handler.AgentDataHandler
+	LoggingHandler         *handler.LoggingHandler
+	GraphQLHandler         *handler.GraphQLHandler
+	HealthHandler          *handler.HealthHandler
+	NotificationJobHandler *handler.NotificationJobHandler
+	GroupHandler           *handler.GroupHandler
+	PrometheusHandler      *handler.PrometheusHandler
+	WidgetHandler          *handler.WidgetHandler
+	PowerHandler           *handler.PowerHandler
+	DiskUsageHandler       *handler.DiskUsageHandler
+	TerminalHandler        *handler.TerminalHandler
+	CommandHandler         *handler.CommandHandler
+	ProcessHandler         *handler.ProcessHandler
+	DockerHandler          *handler.DockerHandler
 
-	AgentService    *service.AgentService
-	MetricService   *service.MetricService
-	AlertService    *service.AlertService
-	PropertyService *service.PropertyService
-	MonitorService  *service.MonitorService
-	ApiKeyService   *service.ApiKeyService
-	TamperService   *service.TamperService
-	DDNSService     *service.DDNSService
+	AccountService           *service.AccountService
+	OIDCService              *service.OIDCService
+	GitHubOAuthService       *service.GitHubOAuthService
+	AgentService             *service.AgentService
+	MetricService            *service.MetricService
+	MetricArchiver           *service.MetricArchiver
+	AlertService             *service.AlertService
+	PropertyService          *service.PropertyService
+	MonitorService           *service.MonitorService
+	ApiKeyService            *service.ApiKeyService
+	TamperService            *service.TamperService
+	DDNSService              *service.DDNSService
+	VulnerabilityService     *service.VulnerabilityService
+	DatabaseService          *service.DatabaseService
+	NotificationQueueService *service.NotificationQueueService
+	PowerService             *service.PowerService
+	DemoService              *service.DemoService
+	TerminalService          *service.TerminalService
+	CommandService           *service.CommandService
+	ProcessService           *service.ProcessService
+	DockerService            *service.DockerService
 
 	WSManager *websocket.Manager
 }
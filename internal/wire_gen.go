@@ -12,6 +12,7 @@ import (
 	"github.com/dushixiang/pika/internal/repo"
 	"github.com/dushixiang/pika/internal/service"
 	"github.com/dushixiang/pika/internal/websocket"
+	"github.com/dushixiang/pika/pkg/logging"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -19,7 +20,7 @@ import (
 // Injectors from wire.go:
 
 // InitializeApp 初始化应用
-func InitializeApp(logger *zap.Logger, db *gorm.DB, cfg *config.AppConfig) (*AppComponents, error) {
+func InitializeApp(logger *zap.Logger, logManager *logging.Manager, db *gorm.DB, cfg *config.AppConfig) (*AppComponents, error) {
 	userService := service.NewUserService(logger, cfg)
 	oidcService := service.NewOIDCService(logger, cfg)
 	gitHubOAuthService := service.NewGitHubOAuthService(logger, cfg)
@@ -27,48 +28,131 @@ func InitializeApp(logger *zap.Logger, db *gorm.DB, cfg *config.AppConfig) (*App
 	accountHandler := handler.NewAccountHandler(accountService)
 	apiKeyService := service.NewApiKeyService(logger, db)
 	propertyService := service.NewPropertyService(logger, db)
-	metricService := service.NewMetricService(logger, db, propertyService)
+	metricStore, err := repo.NewMetricStore(logger, db, cfg)
+	if err != nil {
+		return nil, err
+	}
+	remoteWriter := service.NewRemoteWriter(logger, cfg)
+	influxDBExporter := service.NewInfluxDBExporter(logger, propertyService)
+	metricService := service.NewMetricService(logger, db, metricStore, propertyService, remoteWriter, influxDBExporter)
+	metricArchiver := service.NewMetricArchiver(logger, db, cfg, metricService, propertyService)
 	geoIPService, err := service.NewGeoIPService(logger, cfg)
 	if err != nil {
 		return nil, err
 	}
-	agentService := service.NewAgentService(logger, db, apiKeyService, metricService, geoIPService)
-	manager := websocket.NewManager(logger)
-	monitorService := service.NewMonitorService(logger, db, manager)
+	manager := websocket.NewManager(logger, cfg)
+	remediationService := service.NewRemediationService(logger, db, manager)
+	notifier := service.NewNotifier(logger)
+	notificationQueueService := service.NewNotificationQueueService(logger, db, propertyService, notifier)
+	eventNotifier := service.NewEventNotifier(logger, db, propertyService, notificationQueueService, notifier)
+	powerService := service.NewPowerService(logger, db, propertyService, manager)
+	diskUsageService := service.NewDiskUsageService(logger, manager)
+	commandService := service.NewCommandService(logger, db, manager, cfg)
+	processService := service.NewProcessService(logger, db, manager, cfg)
+	dockerService := service.NewDockerService(logger, db, manager, cfg)
+	agentService := service.NewAgentService(logger, db, apiKeyService, metricService, geoIPService, remediationService, powerService, diskUsageService, commandService, processService, dockerService, eventNotifier)
+	monitorService := service.NewMonitorService(logger, db, metricStore, manager, propertyService, cfg)
 	tamperRepo := repo.NewTamperRepo(db)
-	tamperService := service.NewTamperService(logger, tamperRepo, manager)
+	tamperService := service.NewTamperService(logger, tamperRepo, manager, eventNotifier)
 	ddnsConfigRepo := repo.NewDDNSConfigRepo(db)
 	ddnsRecordRepo := repo.NewDDNSRecordRepo(db)
-	ddnsService := service.NewDDNSService(logger, ddnsConfigRepo, ddnsRecordRepo, propertyService, manager)
-	agentHandler := handler.NewAgentHandler(logger, agentService, metricService, monitorService, tamperService, ddnsService, manager)
+	ddnsService := service.NewDDNSService(logger, ddnsConfigRepo, ddnsRecordRepo, propertyService, manager, eventNotifier)
+	softwareService := service.NewSoftwareService(logger, db)
+	logTailService := service.NewLogTailService(logger, manager, cfg)
+	terminalService := service.NewTerminalService(logger, db, manager, cfg)
+	pingService := service.NewPingService(logger, db, manager, propertyService, metricService)
+	collectorConfigService := service.NewCollectorConfigService(logger, propertyService, manager)
+	groupService := service.NewGroupService(logger, db, metricService)
+	alertService := service.NewAlertService(logger, db, metricStore, propertyService, notifier, notificationQueueService, remediationService, groupService)
+	agentHandler := handler.NewAgentHandler(logger, agentService, metricService, monitorService, tamperService, ddnsService, softwareService, logTailService, terminalService, processService, pingService, collectorConfigService, alertService, manager)
 	apiKeyHandler := handler.NewApiKeyHandler(logger, apiKeyService)
-	notifier := service.NewNotifier(logger)
-	alertService := service.NewAlertService(logger, db, propertyService, notifier)
 	alertHandler := handler.NewAlertHandler(logger, alertService)
-	propertyHandler := handler.NewPropertyHandler(logger, propertyService, notifier)
+	propertyHandler := handler.NewPropertyHandler(logger, propertyService, notifier, collectorConfigService)
 	monitorHandler := handler.NewMonitorHandler(logger, monitorService, agentService)
+	pushHandler := handler.NewPushHandler(logger, monitorService)
 	tamperHandler := handler.NewTamperHandler(logger, tamperService)
 	dnsProviderHandler := handler.NewDNSProviderHandler(logger, propertyService)
 	ddnsHandler := handler.NewDDNSHandler(logger, ddnsService)
+	overviewService := service.NewOverviewService(logger, db, agentService, metricService, propertyService)
+	overviewHandler := handler.NewOverviewHandler(logger, overviewService)
+	softwareHandler := handler.NewSoftwareHandler(logger, softwareService)
+	vulnerabilityService := service.NewVulnerabilityService(logger, db, propertyService, eventNotifier)
+	vulnerabilityHandler := handler.NewVulnerabilityHandler(logger, vulnerabilityService)
+	logTailHandler := handler.NewLogTailHandler(logger, logTailService)
+	remediationHandler := handler.NewRemediationHandler(logger, remediationService)
+	pingHandler := handler.NewPingHandler(logger, pingService)
+	agentDataService := service.NewAgentDataService(logger, db, agentService, metricService)
+	agentDataHandler := handler.NewAgentDataHandler(logger, agentDataService)
+	loggingService := service.NewLoggingService(logger, logManager, manager)
+	loggingHandler := handler.NewLoggingHandler(logger, loggingService)
+	graphQLHandler := handler.NewGraphQLHandler(logger, agentService, metricService, monitorService, alertService)
+	databaseService := service.NewDatabaseService(logger, db, alertService)
+	healthHandler := handler.NewHealthHandler(logger, databaseService)
+	notificationJobHandler := handler.NewNotificationJobHandler(logger, notificationQueueService)
+	groupHandler := handler.NewGroupHandler(logger, groupService)
+	prometheusService := service.NewPrometheusService(logger, agentService, metricService)
+	prometheusHandler := handler.NewPrometheusHandler(logger, prometheusService, cfg)
+	widgetHandler := handler.NewWidgetHandler(logger, agentService, metricService, cfg)
+	powerHandler := handler.NewPowerHandler(logger, powerService)
+	diskUsageHandler := handler.NewDiskUsageHandler(logger, diskUsageService)
+	terminalHandler := handler.NewTerminalHandler(logger, terminalService)
+	commandHandler := handler.NewCommandHandler(logger, commandService)
+	processHandler := handler.NewProcessHandler(logger, processService)
+	dockerHandler := handler.NewDockerHandler(logger, dockerService)
+	demoService := service.NewDemoService(logger, db, metricService, monitorService)
 	appComponents := &AppComponents{
-		AccountHandler:     accountHandler,
-		AgentHandler:       agentHandler,
-		ApiKeyHandler:      apiKeyHandler,
-		AlertHandler:       alertHandler,
-		PropertyHandler:    propertyHandler,
-		MonitorHandler:     monitorHandler,
-		TamperHandler:      tamperHandler,
-		DNSProviderHandler: dnsProviderHandler,
-		DDNSHandler:        ddnsHandler,
-		AgentService:       agentService,
-		MetricService:      metricService,
-		AlertService:       alertService,
-		PropertyService:    propertyService,
-		MonitorService:     monitorService,
-		ApiKeyService:      apiKeyService,
-		TamperService:      tamperService,
-		DDNSService:        ddnsService,
-		WSManager:          manager,
+		AccountHandler:           accountHandler,
+		AgentHandler:             agentHandler,
+		ApiKeyHandler:            apiKeyHandler,
+		AlertHandler:             alertHandler,
+		PropertyHandler:          propertyHandler,
+		MonitorHandler:           monitorHandler,
+		PushHandler:              pushHandler,
+		TamperHandler:            tamperHandler,
+		DNSProviderHandler:       dnsProviderHandler,
+		DDNSHandler:              ddnsHandler,
+		OverviewHandler:          overviewHandler,
+		SoftwareHandler:          softwareHandler,
+		VulnerabilityHandler:     vulnerabilityHandler,
+		LogTailHandler:           logTailHandler,
+		RemediationHandler:       remediationHandler,
+		PingHandler:              pingHandler,
+		AgentDataHandler:         agentDataHandler,
+		LoggingHandler:           loggingHandler,
+		GraphQLHandler:           graphQLHandler,
+		HealthHandler:            healthHandler,
+		NotificationJobHandler:   notificationJobHandler,
+		GroupHandler:             groupHandler,
+		PrometheusHandler:        prometheusHandler,
+		WidgetHandler:            widgetHandler,
+		PowerHandler:             powerHandler,
+		DiskUsageHandler:         diskUsageHandler,
+		TerminalHandler:          terminalHandler,
+		CommandHandler:           commandHandler,
+		ProcessHandler:           processHandler,
+		DockerHandler:            dockerHandler,
+		AccountService:           accountService,
+		OIDCService:              oidcService,
+		GitHubOAuthService:       gitHubOAuthService,
+		AgentService:             agentService,
+		MetricService:            metricService,
+		MetricArchiver:           metricArchiver,
+		AlertService:             alertService,
+		PropertyService:          propertyService,
+		MonitorService:           monitorService,
+		ApiKeyService:            apiKeyService,
+		TamperService:            tamperService,
+		DDNSService:              ddnsService,
+		VulnerabilityService:     vulnerabilityService,
+		DatabaseService:          databaseService,
+		NotificationQueueService: notificationQueueService,
+		PowerService:             powerService,
+		DemoService:              demoService,
+		TerminalService:          terminalService,
+		CommandService:           commandService,
+		ProcessService:           processService,
+		DockerService:            dockerService,
+		WSManager:                manager,
 	}
 	return appComponents, nil
 }
@@ -77,24 +161,58 @@ func InitializeApp(logger *zap.Logger, db *gorm.DB, cfg *config.AppConfig) (*App
 
 // AppComponents 应用组件
 type AppComponents struct {
-	AccountHandler     *handler.AccountHandler
-	AgentHandler       *handler.AgentHandler
-	ApiKeyHandler      *handler.ApiKeyHandler
-	AlertHandler       *handler.AlertHandler
-	PropertyHandler    *handler.PropertyHandler
-	MonitorHandler     *handler.MonitorHandler
-	TamperHandler      *handler.TamperHandler
-	DNSProviderHandler *handler.DNSProviderHandler
-	DDNSHandler        *handler.DDNSHandler
+	AccountHandler         *handler.AccountHandler
+	AgentHandler           *handler.AgentHandler
+	ApiKeyHandler          *handler.ApiKeyHandler
+	AlertHandler           *handler.AlertHandler
+	PropertyHandler        *handler.PropertyHandler
+	MonitorHandler         *handler.MonitorHandler
+	PushHandler            *handler.PushHandler
+	TamperHandler          *handler.TamperHandler
+	DNSProviderHandler     *handler.DNSProviderHandler
+	DDNSHandler            *handler.DDNSHandler
+	OverviewHandler        *handler.OverviewHandler
+	SoftwareHandler        *handler.SoftwareHandler
+	VulnerabilityHandler   *handler.VulnerabilityHandler
+	LogTailHandler         *handler.LogTailHandler
+	RemediationHandler     *handler.RemediationHandler
+	PingHandler            *handler.PingHandler
+	AgentDataHandler       *handler.AgentDataHandler
+	LoggingHandler         *handler.LoggingHandler
+	GraphQLHandler         *handler.GraphQLHandler
+	HealthHandler          *handler.HealthHandler
+	NotificationJobHandler *handler.NotificationJobHandler
+	GroupHandler           *handler.GroupHandler
+	PrometheusHandler      *handler.PrometheusHandler
+	WidgetHandler          *handler.WidgetHandler
+	PowerHandler           *handler.PowerHandler
+	DiskUsageHandler       *handler.DiskUsageHandler
+	TerminalHandler        *handler.TerminalHandler
+	CommandHandler         *handler.CommandHandler
+	ProcessHandler         *handler.ProcessHandler
+	DockerHandler          *handler.DockerHandler
 
-	AgentService    *service.AgentService
-	MetricService   *service.MetricService
-	AlertService    *service.AlertService
-	PropertyService *service.PropertyService
-	MonitorService  *service.MonitorService
-	ApiKeyService   *service.ApiKeyService
-	TamperService   *service.TamperService
-	DDNSService     *service.DDNSService
+	AccountService           *service.AccountService
+	OIDCService              *service.OIDCService
+	GitHubOAuthService       *service.GitHubOAuthService
+	AgentService             *service.AgentService
+	MetricService            *service.MetricService
+	MetricArchiver           *service.MetricArchiver
+	AlertService             *service.AlertService
+	PropertyService          *service.PropertyService
+	MonitorService           *service.MonitorService
+	ApiKeyService            *service.ApiKeyService
+	TamperService            *service.TamperService
+	DDNSService              *service.DDNSService
+	VulnerabilityService     *service.VulnerabilityService
+	DatabaseService          *service.DatabaseService
+	NotificationQueueService *service.NotificationQueueService
+	PowerService             *service.PowerService
+	DemoService              *service.DemoService
+	TerminalService          *service.TerminalService
+	CommandService           *service.CommandService
+	ProcessService           *service.ProcessService
+	DockerService            *service.DockerService
 
 	WSManager *websocket.Manager
 }
@@ -7,14 +7,15 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io"
-	"log"
 	"os"
 	"os/exec"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/dushixiang/pika/pkg/logging"
 	"github.com/shirou/gopsutil/v4/process"
+	"go.uber.org/zap"
 )
 
 // Logger 日志接口
@@ -26,25 +27,27 @@ type Logger interface {
 }
 
 // defaultLogger 默认日志实现
-type defaultLogger struct{}
+type defaultLogger struct {
+	sugar *zap.SugaredLogger
+}
 
 func (l *defaultLogger) Debug(format string, args ...interface{}) {
-	log.Printf("[DEBUG] "+format, args...)
+	l.sugar.Debugf(format, args...)
 }
 
 func (l *defaultLogger) Info(format string, args ...interface{}) {
-	log.Printf("[INFO] "+format, args...)
+	l.sugar.Infof(format, args...)
 }
 
 func (l *defaultLogger) Warn(format string, args ...interface{}) {
-	log.Printf("[WARN] "+format, args...)
+	l.sugar.Warnf(format, args...)
 }
 
 func (l *defaultLogger) Error(format string, args ...interface{}) {
-	log.Printf("[ERROR] "+format, args...)
+	l.sugar.Errorf(format, args...)
 }
 
-var globalLogger Logger = &defaultLogger{}
+var globalLogger Logger = &defaultLogger{sugar: logging.Module("audit").WithOptions(zap.AddCallerSkip(1))}
 
 // SetLogger 设置全局日志器
 func SetLogger(logger Logger) {
@@ -0,0 +1,86 @@
+package collector
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+// BatchWriter 缓存一轮采集的所有指标，Flush 时合并为一条消息发送，减少消息数和服务端处理开销；
+// 非指标消息直接透传给底层连接
+type BatchWriter struct {
+	conn WebSocketWriter
+
+	mu      sync.Mutex
+	metrics []protocol.MetricsWrapper
+	binary  []protocol.BinaryMetrics
+}
+
+// NewBatchWriter 创建合并写入器
+func NewBatchWriter(conn WebSocketWriter) *BatchWriter {
+	return &BatchWriter{conn: conn}
+}
+
+// WriteJSON 透传非指标消息
+func (w *BatchWriter) WriteJSON(v interface{}) error {
+	return w.conn.WriteJSON(v)
+}
+
+// msgpack 底层连接是否协商使用 MessagePack 编码
+func (w *BatchWriter) msgpack() (BinaryWriter, bool) {
+	writer, ok := w.conn.(BinaryWriter)
+	return writer, ok && writer.Encoding() == protocol.EncodingMsgpack
+}
+
+// add 按底层连接的编码缓存一类指标
+func (w *BatchWriter) add(metricType protocol.MetricType, data interface{}) error {
+	if _, ok := w.msgpack(); ok {
+		dataBytes, err := protocol.MarshalMsgpack(data)
+		if err != nil {
+			return err
+		}
+		w.mu.Lock()
+		w.binary = append(w.binary, protocol.BinaryMetrics{Type: metricType, Data: dataBytes})
+		w.mu.Unlock()
+		return nil
+	}
+
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	w.metrics = append(w.metrics, protocol.MetricsWrapper{Type: metricType, Data: dataBytes})
+	w.mu.Unlock()
+	return nil
+}
+
+// Flush 将缓存的指标合并为一条消息发送并清空缓存，超时后才返回的采集器写入的指标留到下一轮发送
+func (w *BatchWriter) Flush() error {
+	w.mu.Lock()
+	metrics, binary := w.metrics, w.binary
+	w.metrics, w.binary = nil, nil
+	w.mu.Unlock()
+
+	if len(binary) > 0 {
+		writer, _ := w.msgpack()
+		frame, err := protocol.MarshalMsgpack(protocol.BinaryMetrics{Batch: binary})
+		if err != nil {
+			return err
+		}
+		return writer.WriteBinary(frame)
+	}
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(protocol.MetricsBatch{Metrics: metrics})
+	if err != nil {
+		return err
+	}
+	return w.conn.WriteJSON(protocol.Message{
+		Type: protocol.MessageTypeMetricsBatch,
+		Data: data,
+	})
+}
@@ -0,0 +1,107 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/pkg/agent/sysutil"
+)
+
+// containerImageTimeout 采集容器镜像的超时时间
+const containerImageTimeout = 30 * time.Second
+
+// collectContainerImages 采集运行中容器的镜像及其仓库摘要
+func collectContainerImages() ([]protocol.ContainerImage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), containerImageTimeout)
+	defer cancel()
+
+	output, err := sysutil.RunDocker(ctx, "ps", "-q", "--no-trunc")
+	if err != nil {
+		return nil, err
+	}
+	containerIDs := strings.Fields(output)
+	if len(containerIDs) == 0 {
+		return nil, nil
+	}
+
+	output, err = sysutil.RunDocker(ctx, append([]string{"inspect", "--type", "container"}, containerIDs...)...)
+	if err != nil {
+		return nil, err
+	}
+	containers, err := parseContainerInspect(output)
+	if err != nil {
+		return nil, err
+	}
+
+	var imageIDs []string
+	seen := make(map[string]bool)
+	for _, container := range containers {
+		if container.ImageID != "" && !seen[container.ImageID] {
+			seen[container.ImageID] = true
+			imageIDs = append(imageIDs, container.ImageID)
+		}
+	}
+	if len(imageIDs) == 0 {
+		return containers, nil
+	}
+
+	output, err = sysutil.RunDocker(ctx, append([]string{"image", "inspect"}, imageIDs...)...)
+	if err != nil {
+		return nil, err
+	}
+	repoDigests, err := parseImageRepoDigests(output)
+	if err != nil {
+		return nil, err
+	}
+	for i := range containers {
+		containers[i].RepoDigests = repoDigests[containers[i].ImageID]
+	}
+	return containers, nil
+}
+
+// parseContainerInspect 解析 docker inspect --type container 的输出
+func parseContainerInspect(output string) ([]protocol.ContainerImage, error) {
+	var items []struct {
+		ID     string `json:"Id"`
+		Name   string `json:"Name"`
+		Image  string `json:"Image"`
+		Config struct {
+			Image string `json:"Image"`
+		} `json:"Config"`
+	}
+	if err := json.Unmarshal([]byte(output), &items); err != nil {
+		return nil, fmt.Errorf("解析容器信息失败: %w", err)
+	}
+
+	containers := make([]protocol.ContainerImage, 0, len(items))
+	for _, item := range items {
+		containers = append(containers, protocol.ContainerImage{
+			ContainerID:   item.ID,
+			ContainerName: strings.TrimPrefix(item.Name, "/"),
+			Image:         item.Config.Image,
+			ImageID:       item.Image,
+		})
+	}
+	return containers, nil
+}
+
+// parseImageRepoDigests 解析 docker image inspect 的输出，返回镜像ID到仓库摘要的映射
+func parseImageRepoDigests(output string) (map[string][]string, error) {
+	var items []struct {
+		ID          string   `json:"Id"`
+		RepoDigests []string `json:"RepoDigests"`
+	}
+	if err := json.Unmarshal([]byte(output), &items); err != nil {
+		return nil, fmt.Errorf("解析镜像信息失败: %w", err)
+	}
+
+	repoDigests := make(map[string][]string, len(items))
+	for _, item := range items {
+		repoDigests[item.ID] = item.RepoDigests
+	}
+	return repoDigests, nil
+}
@@ -0,0 +1,59 @@
+package collector
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+func TestParseContainerInspect(t *testing.T) {
+	output := `[
+  {
+    "Id": "3f4e5d6c7b8a",
+    "Name": "/web",
+    "Image": "sha256:a1b2c3",
+    "State": {"Status": "running"},
+    "Config": {"Image": "nginx:1.25", "Env": ["PATH=/usr/bin"]}
+  },
+  {
+    "Id": "9a8b7c6d5e4f",
+    "Name": "/app",
+    "Image": "sha256:d4e5f6",
+    "Config": {"Image": "ghcr.io/acme/app@sha256:0123"}
+  }
+]`
+	got, err := parseContainerInspect(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []protocol.ContainerImage{
+		{ContainerID: "3f4e5d6c7b8a", ContainerName: "web", Image: "nginx:1.25", ImageID: "sha256:a1b2c3"},
+		{ContainerID: "9a8b7c6d5e4f", ContainerName: "app", Image: "ghcr.io/acme/app@sha256:0123", ImageID: "sha256:d4e5f6"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseContainerInspect = %+v, 期望 %+v", got, want)
+	}
+
+	if _, err := parseContainerInspect("Error: No such container"); err == nil {
+		t.Fatal("非 JSON 输出应返回错误")
+	}
+}
+
+func TestParseImageRepoDigests(t *testing.T) {
+	output := `[
+  {"Id": "sha256:a1b2c3", "RepoTags": ["nginx:1.25"], "RepoDigests": ["nginx@sha256:1111"]},
+  {"Id": "sha256:d4e5f6", "RepoTags": [], "RepoDigests": null}
+]`
+	got, err := parseImageRepoDigests(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{
+		"sha256:a1b2c3": {"nginx@sha256:1111"},
+		"sha256:d4e5f6": nil,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseImageRepoDigests = %v, 期望 %v", got, want)
+	}
+}
@@ -3,6 +3,7 @@ package collector
 import (
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/dushixiang/pika/internal/protocol"
@@ -17,6 +18,10 @@ type CPUCollector struct {
 	physicalCores int
 	modelName     string
 	initOnce      sync.Once
+	// 是否采集每个核心的使用率，由服务端下发
+	perCore atomic.Bool
+	// 上次采集的 CPU 累计时间，用于计算时间分布
+	lastTimes *cpu.TimesStat
 }
 
 // NewCPUCollector 创建 CPU 采集器
@@ -49,25 +54,81 @@ func (c *CPUCollector) init() {
 	})
 }
 
+// SetPerCore 设置是否采集每个核心的使用率
+func (c *CPUCollector) SetPerCore(enabled bool) {
+	c.perCore.Store(enabled)
+}
+
 // Collect 采集 CPU 数据(返回完整数据,包括静态和动态信息)
 func (c *CPUCollector) Collect() (*protocol.CPUData, error) {
 	c.init()
 
+	data := &protocol.CPUData{
+		LogicalCores:  c.logicalCores,
+		PhysicalCores: c.physicalCores,
+		ModelName:     c.modelName,
+		Times:         c.collectTimes(),
+	}
+
+	if c.perCore.Load() {
+		// 采样一次每核使用率，总体使用率取各核平均值，避免两次采样拉长采集耗时
+		percentages, err := cpu.Percent(time.Second, true)
+		if err != nil {
+			return nil, err
+		}
+		total := 0.0
+		for _, p := range percentages {
+			total += p
+		}
+		if len(percentages) > 0 {
+			data.UsagePercent = total / float64(len(percentages))
+		}
+		data.PerCore = percentages
+		return data, nil
+	}
+
 	// 获取 CPU 总体使用率
 	percentages, err := cpu.Percent(time.Second, false)
 	if err != nil {
 		return nil, err
 	}
 
-	cpuPercent := 0.0
 	if len(percentages) > 0 {
-		cpuPercent = percentages[0]
+		data.UsagePercent = percentages[0]
 	}
 
-	return &protocol.CPUData{
-		LogicalCores:  c.logicalCores,
-		PhysicalCores: c.physicalCores,
-		ModelName:     c.modelName,
-		UsagePercent:  cpuPercent,
-	}, nil
+	return data, nil
+}
+
+// collectTimes 计算距上次采集期间的 CPU 时间分布，首次采集没有基准时返回 nil
+func (c *CPUCollector) collectTimes() *protocol.CPUTimesData {
+	times, err := cpu.Times(false)
+	if err != nil || len(times) == 0 {
+		return nil
+	}
+	current := times[0]
+	last := c.lastTimes
+	c.lastTimes = &current
+	if last == nil {
+		return nil
+	}
+
+	// Linux 下 guest 时间已计入 user，不再单独累加
+	user := (current.User + current.Nice) - (last.User + last.Nice)
+	system := (current.System + current.Irq + current.Softirq) - (last.System + last.Irq + last.Softirq)
+	iowait := current.Iowait - last.Iowait
+	steal := current.Steal - last.Steal
+	idle := current.Idle - last.Idle
+	total := user + system + iowait + steal + idle
+	if total <= 0 {
+		return nil
+	}
+
+	return &protocol.CPUTimesData{
+		User:   user / total * 100,
+		System: system / total * 100,
+		IOWait: iowait / total * 100,
+		Steal:  steal / total * 100,
+		Idle:   idle / total * 100,
+	}
 }
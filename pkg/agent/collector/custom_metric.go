@@ -0,0 +1,285 @@
+package collector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/pkg/agent/config"
+)
+
+const (
+	defaultCustomCommandInterval = time.Minute
+	defaultCustomCommandTimeout  = 10 * time.Second
+	// maxCustomMetrics 单次上报的自定义指标数量上限，避免错误的脚本产生大量指标
+	maxCustomMetrics = 500
+)
+
+var (
+	customMetricNamePattern  = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	customMetricLabelPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// CustomMetricCollector 自定义指标采集器，读取文本文件目录下的 *.prom 文件并按各自的间隔执行自定义命令
+// 命令的结果在下次执行前重复上报，执行失败时保留错误，不影响其他命令和文本文件
+type CustomMetricCollector struct {
+	textfileDir string
+	commands    []config.CustomCommandConfig
+
+	mu      sync.Mutex
+	results map[string]*customCommandResult // 命令名称 -> 最近一次执行结果
+}
+
+// customCommandResult 命令最近一次的执行结果
+type customCommandResult struct {
+	metrics []protocol.CustomMetricData
+	err     error
+	ranAt   time.Time
+}
+
+// NewCustomMetricCollector 创建自定义指标采集器
+func NewCustomMetricCollector(cfg *config.Config) *CustomMetricCollector {
+	return &CustomMetricCollector{
+		textfileDir: cfg.CustomMetrics.TextfileDir,
+		commands:    cfg.CustomMetrics.Commands,
+		results:     make(map[string]*customCommandResult),
+	}
+}
+
+// Enabled 是否配置了文本文件目录或命令
+func (c *CustomMetricCollector) Enabled() bool {
+	return c.textfileDir != "" || len(c.commands) > 0
+}
+
+// Collect 采集所有自定义指标，部分来源失败时仍返回其他来源的指标，错误合并返回
+func (c *CustomMetricCollector) Collect() ([]protocol.CustomMetricData, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	metrics := make([]protocol.CustomMetricData, 0)
+	var errs []string
+
+	if c.textfileDir != "" {
+		fileMetrics, err := c.readTextfiles()
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		metrics = append(metrics, fileMetrics...)
+	}
+
+	for _, command := range c.commands {
+		result := c.runCommand(command)
+		if result.err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", command.Name, result.err))
+		}
+		metrics = append(metrics, result.metrics...)
+	}
+
+	if len(metrics) > maxCustomMetrics {
+		errs = append(errs, fmt.Sprintf("自定义指标数量 %d 超过上限 %d，超出部分已丢弃", len(metrics), maxCustomMetrics))
+		metrics = metrics[:maxCustomMetrics]
+	}
+
+	if len(errs) > 0 {
+		return metrics, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return metrics, nil
+}
+
+// readTextfiles 读取目录下所有 *.prom 文件，单个文件解析失败时跳过该文件
+func (c *CustomMetricCollector) readTextfiles() ([]protocol.CustomMetricData, error) {
+	files, err := filepath.Glob(filepath.Join(c.textfileDir, "*.prom"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var metrics []protocol.CustomMetricData
+	var errs []string
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		fileMetrics, err := parseCustomMetrics(bytes.NewReader(content), filepath.Base(file))
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", filepath.Base(file), err))
+			continue
+		}
+		metrics = append(metrics, fileMetrics...)
+	}
+
+	if len(errs) > 0 {
+		return metrics, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return metrics, nil
+}
+
+// runCommand 未到执行间隔时返回上一次的结果，否则执行命令并解析标准输出
+func (c *CustomMetricCollector) runCommand(command config.CustomCommandConfig) *customCommandResult {
+	interval := defaultCustomCommandInterval
+	if command.Interval > 0 {
+		interval = time.Duration(command.Interval) * time.Second
+	}
+	if result, ok := c.results[command.Name]; ok && time.Since(result.ranAt) < interval {
+		return result
+	}
+
+	timeout := defaultCustomCommandTimeout
+	if command.Timeout > 0 {
+		timeout = time.Duration(command.Timeout) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command.Command[0], command.Command[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	result := &customCommandResult{ranAt: time.Now()}
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			err = fmt.Errorf("执行超时")
+		} else if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = fmt.Errorf("%v: %s", err, msg)
+		}
+		result.err = err
+	} else {
+		result.metrics, result.err = parseCustomMetrics(&stdout, command.Name)
+	}
+
+	c.results[command.Name] = result
+	return result
+}
+
+// parseCustomMetrics 解析 Prometheus 文本格式：指标名{标签="值",...} 数值 [时间戳]
+// 忽略空行、注释和 HELP/TYPE 行，NaN 和 Inf 无法通过 JSON 上报，直接跳过
+func parseCustomMetrics(r io.Reader, source string) ([]protocol.CustomMetricData, error) {
+	var metrics []protocol.CustomMetricData
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		metric, err := parseCustomMetricLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 行: %w", lineNo, err)
+		}
+		if math.IsNaN(metric.Value) || math.IsInf(metric.Value, 0) {
+			continue
+		}
+		metric.Source = source
+		metrics = append(metrics, metric)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+// parseCustomMetricLine 解析单行样本
+func parseCustomMetricLine(line string) (protocol.CustomMetricData, error) {
+	var metric protocol.CustomMetricData
+
+	nameEnd := strings.IndexAny(line, "{ \t")
+	if nameEnd < 0 {
+		return metric, fmt.Errorf("缺少数值")
+	}
+	metric.Name = line[:nameEnd]
+	if !customMetricNamePattern.MatchString(metric.Name) {
+		return metric, fmt.Errorf("指标名不合法: %s", metric.Name)
+	}
+
+	rest := line[nameEnd:]
+	if strings.HasPrefix(rest, "{") {
+		labels, remaining, err := parseCustomMetricLabels(rest[1:])
+		if err != nil {
+			return metric, err
+		}
+		metric.Labels = labels
+		rest = remaining
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 || len(fields) > 2 {
+		return metric, fmt.Errorf("数值格式错误")
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return metric, fmt.Errorf("数值格式错误: %s", fields[0])
+	}
+	metric.Value = value
+	return metric, nil
+}
+
+// parseCustomMetricLabels 解析标签，返回标签和右花括号之后的内容
+func parseCustomMetricLabels(s string) (map[string]string, string, error) {
+	labels := make(map[string]string)
+	for {
+		s = strings.TrimLeft(s, " \t")
+		if strings.HasPrefix(s, "}") {
+			return labels, s[1:], nil
+		}
+
+		eq := strings.Index(s, "=")
+		if eq < 0 {
+			return nil, "", fmt.Errorf("标签格式错误")
+		}
+		name := strings.TrimSpace(s[:eq])
+		if !customMetricLabelPattern.MatchString(name) {
+			return nil, "", fmt.Errorf("标签名不合法: %s", name)
+		}
+		s = strings.TrimLeft(s[eq+1:], " \t")
+		if !strings.HasPrefix(s, `"`) {
+			return nil, "", fmt.Errorf("标签值需要使用双引号")
+		}
+
+		var value strings.Builder
+		i := 1
+		for ; i < len(s); i++ {
+			ch := s[i]
+			if ch == '\\' && i+1 < len(s) {
+				i++
+				switch s[i] {
+				case 'n':
+					value.WriteByte('\n')
+				default:
+					value.WriteByte(s[i])
+				}
+				continue
+			}
+			if ch == '"' {
+				break
+			}
+			value.WriteByte(ch)
+		}
+		if i >= len(s) {
+			return nil, "", fmt.Errorf("标签值缺少结束引号")
+		}
+		labels[name] = value.String()
+
+		s = strings.TrimLeft(s[i+1:], " \t")
+		if strings.HasPrefix(s, ",") {
+			s = s[1:]
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package collector
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCustomMetricLine(t *testing.T) {
+	tests := []struct {
+		name       string
+		line       string
+		wantName   string
+		wantLabels map[string]string
+		wantValue  float64
+		wantErr    string
+	}{
+		{name: "无标签", line: "queue_size 42", wantName: "queue_size", wantValue: 42},
+		{name: "带时间戳", line: "queue_size 42 1700000000000", wantName: "queue_size", wantValue: 42},
+		{name: "科学计数法", line: "bytes_total 1.5e3", wantName: "bytes_total", wantValue: 1500},
+		{name: "冒号指标名", line: "job:requests:rate5m 0.5", wantName: "job:requests:rate5m", wantValue: 0.5},
+		{name: "带标签", line: `backup_age{job="db",host="a"} 3600`, wantName: "backup_age", wantLabels: map[string]string{"job": "db", "host": "a"}, wantValue: 3600},
+		{name: "空标签", line: "up{} 1", wantName: "up", wantLabels: map[string]string{}, wantValue: 1},
+		{name: "缺少数值", line: "queue_size", wantErr: "缺少数值"},
+		{name: "指标名不合法", line: "queue-size 1", wantErr: "指标名不合法"},
+		{name: "指标名以数字开头", line: "1queue 1", wantErr: "指标名不合法"},
+		{name: "数值不合法", line: "queue_size abc", wantErr: "数值格式错误"},
+		{name: "多余字段", line: "queue_size 1 2 3", wantErr: "数值格式错误"},
+		{name: "标签名不合法", line: `queue{bad-name="a"} 1`, wantErr: "标签名不合法"},
+	}
+	for _, tt := range tests {
+		metric, err := parseCustomMetricLine(tt.line)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: parseCustomMetricLine(%q) error = %v, 期望包含 %q", tt.name, tt.line, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: parseCustomMetricLine(%q) error = %v", tt.name, tt.line, err)
+			continue
+		}
+		if metric.Name != tt.wantName || metric.Value != tt.wantValue || !reflect.DeepEqual(metric.Labels, tt.wantLabels) {
+			t.Errorf("%s: parseCustomMetricLine(%q) = %+v", tt.name, tt.line, metric)
+		}
+	}
+}
+
+func TestParseCustomMetricLabels(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantLabels map[string]string
+		wantRest   string
+		wantErr    string
+	}{
+		{name: "单个标签", input: `job="db"} 1`, wantLabels: map[string]string{"job": "db"}, wantRest: " 1"},
+		{name: "多个标签和空格", input: ` job = "db" , host="a" } 1`, wantLabels: map[string]string{"job": "db", "host": "a"}, wantRest: " 1"},
+		{name: "末尾逗号", input: `job="db",} 1`, wantLabels: map[string]string{"job": "db"}, wantRest: " 1"},
+		{name: "转义字符", input: `path="C:\\tmp",msg="a\"b\nc"} 1`, wantLabels: map[string]string{"path": `C:\tmp`, "msg": "a\"b\nc"}, wantRest: " 1"},
+		{name: "值中包含花括号和逗号", input: `q="{a,b}"} 1`, wantLabels: map[string]string{"q": "{a,b}"}, wantRest: " 1"},
+		{name: "空标签", input: `} 1`, wantLabels: map[string]string{}, wantRest: " 1"},
+		{name: "缺少等号", input: `job} 1`, wantErr: "标签格式错误"},
+		{name: "标签名不合法", input: `1job="db"} 1`, wantErr: "标签名不合法"},
+		{name: "标签名包含空格", input: `my job="db"} 1`, wantErr: "标签名不合法"},
+		{name: "值未使用双引号", input: `job=db} 1`, wantErr: "标签值需要使用双引号"},
+		{name: "缺少结束引号", input: `job="db} 1`, wantErr: "标签值缺少结束引号"},
+	}
+	for _, tt := range tests {
+		labels, rest, err := parseCustomMetricLabels(tt.input)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: parseCustomMetricLabels(%q) error = %v, 期望包含 %q", tt.name, tt.input, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: parseCustomMetricLabels(%q) error = %v", tt.name, tt.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(labels, tt.wantLabels) || rest != tt.wantRest {
+			t.Errorf("%s: parseCustomMetricLabels(%q) = %v, %q, 期望 %v, %q", tt.name, tt.input, labels, rest, tt.wantLabels, tt.wantRest)
+		}
+	}
+}
+
+func TestParseCustomMetrics(t *testing.T) {
+	input := `# HELP queue_size Queue size
+# TYPE queue_size gauge
+queue_size{queue="a"} 1
+
+queue_size{queue="b"} NaN
+queue_size{queue="c"} +Inf
+queue_size{queue="d"} 4
+`
+	metrics, err := parseCustomMetrics(strings.NewReader(input), "queue.prom")
+	if err != nil {
+		t.Fatalf("parseCustomMetrics error = %v", err)
+	}
+	if len(metrics) != 2 || metrics[0].Labels["queue"] != "a" || metrics[1].Labels["queue"] != "d" {
+		t.Fatalf("parseCustomMetrics = %+v, 期望跳过注释、空行和 NaN/Inf", metrics)
+	}
+	if metrics[0].Source != "queue.prom" {
+		t.Fatalf("来源 = %q, 期望 queue.prom", metrics[0].Source)
+	}
+
+	if _, err := parseCustomMetrics(strings.NewReader("ok 1\nbad-name 2\n"), "bad.prom"); err == nil || !strings.Contains(err.Error(), "第 2 行") {
+		t.Fatalf("parseCustomMetrics error = %v, 期望指出第 2 行", err)
+	}
+}
@@ -0,0 +1,60 @@
+package collector
+
+import (
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/pkg/agent/config"
+	"github.com/shirou/gopsutil/v4/disk"
+)
+
+// FileUsageCollector 文件描述符和 inode 使用情况采集器
+type FileUsageCollector struct {
+	config *config.Config
+}
+
+// NewFileUsageCollector 创建文件描述符和 inode 采集器
+func NewFileUsageCollector(cfg *config.Config) *FileUsageCollector {
+	return &FileUsageCollector{
+		config: cfg,
+	}
+}
+
+// Collect 采集系统级文件描述符和各文件系统的 inode 使用情况
+// inode 与磁盘采集使用相同的挂载点白名单
+func (f *FileUsageCollector) Collect() (*protocol.FileUsageData, error) {
+	data := &protocol.FileUsageData{}
+
+	fd, err := collectFileDescriptors()
+	if err != nil {
+		return nil, err
+	}
+	data.FileDescriptors = fd
+
+	partitions, err := disk.Partitions(false)
+	if err != nil {
+		return nil, err
+	}
+	for _, partition := range partitions {
+		if !f.config.ShouldIncludeDiskMountPoint(partition.Mountpoint) {
+			continue
+		}
+		usage, err := disk.Usage(partition.Mountpoint)
+		if err != nil {
+			continue
+		}
+		// btrfs 等动态分配 inode 的文件系统不报告总数，跳过
+		if usage.InodesTotal == 0 {
+			continue
+		}
+		data.Inodes = append(data.Inodes, protocol.InodeData{
+			MountPoint:   partition.Mountpoint,
+			Device:       partition.Device,
+			Fstype:       partition.Fstype,
+			Total:        usage.InodesTotal,
+			Used:         usage.InodesUsed,
+			Free:         usage.InodesFree,
+			UsagePercent: usage.InodesUsedPercent,
+		})
+	}
+
+	return data, nil
+}
@@ -0,0 +1,45 @@
+//go:build linux
+
+package collector
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+// collectFileDescriptors 读取 /proc/sys/fs/file-nr：已分配数量、已分配未使用数量（2.6 以后恒为 0）、系统上限
+func collectFileDescriptors() (*protocol.FileDescriptorData, error) {
+	content, err := os.ReadFile("/proc/sys/fs/file-nr")
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.Fields(string(content))
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("无法解析 file-nr: %q", string(content))
+	}
+	allocated, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	unused, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	max, err := strconv.ParseUint(fields[2], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &protocol.FileDescriptorData{
+		Allocated: allocated - unused,
+		Max:       max,
+	}
+	if max > 0 {
+		data.UsagePercent = float64(data.Allocated) / float64(max) * 100
+	}
+	return data, nil
+}
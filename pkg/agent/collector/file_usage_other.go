@@ -0,0 +1,10 @@
+//go:build !linux
+
+package collector
+
+import "github.com/dushixiang/pika/internal/protocol"
+
+// collectFileDescriptors 非 Linux 系统没有统一的系统级文件描述符统计，不采集
+func collectFileDescriptors() (*protocol.FileDescriptorData, error) {
+	return nil, nil
+}
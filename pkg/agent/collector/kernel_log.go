@@ -0,0 +1,78 @@
+package collector
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+// kernelLogSuppressWindow 同一分类的内核日志在窗口内只上报一次，避免磁盘故障时 I/O 错误刷屏
+const kernelLogSuppressWindow = time.Minute
+
+// kernelLogPatterns 内核日志分类规则，按顺序匹配
+var kernelLogPatterns = []struct {
+	category string
+	pattern  *regexp.Regexp
+}{
+	{protocol.KernelEventOOM, regexp.MustCompile(`(?i)out of memory|oom-kill|oom_reaper|killed process \d+`)},
+	{protocol.KernelEventHardware, regexp.MustCompile(`(?i)hardware error|machine check|\bmce:|edac .*(ce|ue) |corrected error|uncorrected error|thermal .*throttl`)},
+	{protocol.KernelEventIO, regexp.MustCompile(`(?i)i/o error|blk_update_request|critical medium error|ext4-fs error|xfs .*(error|corruption)|btrfs .*error|remounting filesystem read-only|ata\d+.*(failed command|exception)`)},
+}
+
+// classifyKernelLog 返回内核日志的分类，不关心的日志返回空字符串
+func classifyKernelLog(line string) string {
+	for _, item := range kernelLogPatterns {
+		if item.pattern.MatchString(line) {
+			return item.category
+		}
+	}
+	return ""
+}
+
+// KernelLogWatcher 内核日志监视器，持续读取内核日志并输出 OOM、I/O 错误和硬件故障事件
+type KernelLogWatcher struct {
+	lastSent   map[string]time.Time // 各分类上次上报时间
+	suppressed map[string]int       // 各分类被抑制的条数
+}
+
+// NewKernelLogWatcher 创建内核日志监视器
+func NewKernelLogWatcher() *KernelLogWatcher {
+	return &KernelLogWatcher{
+		lastSent:   make(map[string]time.Time),
+		suppressed: make(map[string]int),
+	}
+}
+
+// Run 读取内核日志直到 ctx 取消，emit 在调用方协程中依次执行；系统不支持时返回错误
+func (w *KernelLogWatcher) Run(ctx context.Context, emit func(protocol.KernelEventData)) error {
+	return readKernelLog(ctx, func(line string, timestamp time.Time) {
+		if event, ok := w.handle(line, timestamp); ok {
+			emit(event)
+		}
+	})
+}
+
+// handle 对单行日志分类并按抑制窗口去重
+func (w *KernelLogWatcher) handle(line string, timestamp time.Time) (protocol.KernelEventData, bool) {
+	category := classifyKernelLog(line)
+	if category == "" {
+		return protocol.KernelEventData{}, false
+	}
+
+	if last, ok := w.lastSent[category]; ok && timestamp.Sub(last) < kernelLogSuppressWindow {
+		w.suppressed[category]++
+		return protocol.KernelEventData{}, false
+	}
+
+	event := protocol.KernelEventData{
+		Category:   category,
+		Message:    line,
+		Suppressed: w.suppressed[category],
+		Timestamp:  timestamp.UnixMilli(),
+	}
+	w.lastSent[category] = timestamp
+	w.suppressed[category] = 0
+	return event, true
+}
@@ -0,0 +1,95 @@
+//go:build linux
+
+package collector
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// readKernelLog 从 /dev/kmsg 读取新产生的内核日志，无权限访问时（如容器中）改用 journalctl -k
+func readKernelLog(ctx context.Context, handle func(line string, timestamp time.Time)) error {
+	file, err := os.Open("/dev/kmsg")
+	if err != nil {
+		if _, lookErr := exec.LookPath("journalctl"); lookErr != nil {
+			return fmt.Errorf("无法读取 /dev/kmsg: %w", err)
+		}
+		return readJournalKernelLog(ctx, handle)
+	}
+	defer file.Close()
+
+	// 只关心启动后新产生的日志，跳过环形缓冲区中的历史记录
+	if _, err := file.Seek(0, io.SeekEnd); err != nil {
+		return fmt.Errorf("定位 /dev/kmsg 失败: %w", err)
+	}
+
+	// 读取会一直阻塞，ctx 取消时关闭文件以结束读取
+	stop := context.AfterFunc(ctx, func() { file.Close() })
+	defer stop()
+
+	// /dev/kmsg 每次 read 返回一条完整记录
+	buf := make([]byte, 8192)
+	for {
+		n, err := file.Read(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			// 读取速度跟不上时旧记录被覆盖，返回 EPIPE，继续读取即可
+			if errors.Is(err, syscall.EPIPE) {
+				continue
+			}
+			return fmt.Errorf("读取 /dev/kmsg 失败: %w", err)
+		}
+		if line, ok := parseKmsgRecord(string(buf[:n])); ok {
+			handle(line, time.Now())
+		}
+	}
+}
+
+// parseKmsgRecord 解析 /dev/kmsg 记录，格式为 "优先级,序号,时间戳,标志;消息"，之后以空格开头的行是附加字段
+func parseKmsgRecord(record string) (string, bool) {
+	_, message, ok := strings.Cut(record, ";")
+	if !ok {
+		return "", false
+	}
+	message, _, _ = strings.Cut(message, "\n")
+	message = strings.TrimSpace(message)
+	return message, message != ""
+}
+
+// readJournalKernelLog 通过 journalctl 跟随读取内核日志
+func readJournalKernelLog(ctx context.Context, handle func(line string, timestamp time.Time)) error {
+	cmd := exec.CommandContext(ctx, "journalctl", "-k", "-f", "-n", "0", "-o", "cat")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("启动 journalctl 失败: %w", err)
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			handle(line, time.Now())
+		}
+	}
+
+	err = cmd.Wait()
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err == nil {
+		err = errors.New("journalctl 意外退出")
+	}
+	return err
+}
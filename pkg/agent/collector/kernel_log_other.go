@@ -0,0 +1,14 @@
+//go:build !linux
+
+package collector
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// readKernelLog 内核日志监视只支持 Linux
+func readKernelLog(ctx context.Context, handle func(line string, timestamp time.Time)) error {
+	return errors.New("当前系统不支持内核日志监视")
+}
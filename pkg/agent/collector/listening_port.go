@@ -0,0 +1,109 @@
+package collector
+
+import (
+	"net"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+	gopsutilNet "github.com/shirou/gopsutil/v4/net"
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// listeningPortInterval 监听端口变化不频繁，且查询进程信息开销较大，按该间隔采集，期间重复上报上一次的结果
+const listeningPortInterval = time.Minute
+
+// ListeningPortCollector 监听端口采集器，上报 TCP 监听和未连接的 UDP 套接字及其所属进程
+type ListeningPortCollector struct {
+	mu          sync.Mutex
+	last        []protocol.ListeningPort
+	collectedAt time.Time
+}
+
+// NewListeningPortCollector 创建监听端口采集器
+func NewListeningPortCollector() *ListeningPortCollector {
+	return &ListeningPortCollector{}
+}
+
+// Collect 采集监听端口，同一协议、地址和端口只保留一条（如多进程共享监听）
+func (l *ListeningPortCollector) Collect() ([]protocol.ListeningPort, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.last != nil && time.Since(l.collectedAt) < listeningPortInterval {
+		return l.last, nil
+	}
+
+	connections, err := gopsutilNet.Connections("inet")
+	if err != nil {
+		return nil, err
+	}
+
+	type portKey struct {
+		protocol string
+		address  string
+		port     uint32
+	}
+	seen := make(map[portKey]bool)
+	processNames := make(map[int32]string)
+	ports := make([]protocol.ListeningPort, 0)
+	for _, conn := range connections {
+		var protocolType string
+		switch {
+		case conn.Type == syscall.SOCK_STREAM && conn.Status == "LISTEN":
+			protocolType = "tcp"
+		case conn.Type == syscall.SOCK_DGRAM && conn.Raddr.Port == 0:
+			// 未连接的 UDP 套接字才能接收任意来源的数据
+			protocolType = "udp"
+		default:
+			continue
+		}
+
+		key := portKey{protocol: protocolType, address: conn.Laddr.IP, port: conn.Laddr.Port}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		port := protocol.ListeningPort{
+			Protocol:   protocolType,
+			Address:    conn.Laddr.IP,
+			Port:       conn.Laddr.Port,
+			ProcessPID: conn.Pid,
+			IsPublic:   !isLoopbackAddress(conn.Laddr.IP),
+		}
+		if conn.Pid > 0 {
+			name, ok := processNames[conn.Pid]
+			if !ok {
+				if proc, err := process.NewProcess(conn.Pid); err == nil {
+					name, _ = proc.Name()
+				}
+				processNames[conn.Pid] = name
+			}
+			port.ProcessName = name
+		}
+		ports = append(ports, port)
+	}
+
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].Protocol != ports[j].Protocol {
+			return ports[i].Protocol < ports[j].Protocol
+		}
+		if ports[i].Port != ports[j].Port {
+			return ports[i].Port < ports[j].Port
+		}
+		return ports[i].Address < ports[j].Address
+	})
+
+	l.last = ports
+	l.collectedAt = time.Now()
+	return ports, nil
+}
+
+// isLoopbackAddress 是否只监听在回环地址上，回环地址上的端口不对外暴露
+func isLoopbackAddress(address string) bool {
+	ip := net.ParseIP(address)
+	return ip != nil && ip.IsLoopback()
+}
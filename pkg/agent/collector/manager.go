@@ -12,6 +12,12 @@ type WebSocketWriter interface {
 	WriteJSON(v interface{}) error
 }
 
+// BinaryWriter 支持二进制帧的连接，注册时协商使用 MessagePack 编码后指标以二进制帧发送
+type BinaryWriter interface {
+	WriteBinary(data []byte) error
+	Encoding() string
+}
+
 // Manager 采集器管理器
 type Manager struct {
 	cpuCollector               *CPUCollector
@@ -25,6 +31,15 @@ type Manager struct {
 	gpuCollector               *GPUCollector
 	monitorCollector           *MonitorCollector
 	ddnsCollector              *DDNSCollector
+	softwareCollector          *SoftwareCollector
+	wireGuardCollector         *WireGuardCollector
+	pingCollector              *PingCollector
+	mountCollector             *MountCollector
+	fileUsageCollector         *FileUsageCollector
+	timeSyncCollector          *TimeSyncCollector
+	listeningPortCollector     *ListeningPortCollector
+	customMetricCollector      *CustomMetricCollector
+	pressureCollector          *PressureCollector
 }
 
 // NewManager 创建采集器管理器
@@ -41,9 +56,23 @@ func NewManager(cfg *config.Config) *Manager {
 		gpuCollector:               NewGPUCollector(),
 		monitorCollector:           NewMonitorCollector(),
 		ddnsCollector:              nil, // DDNS 采集器需要配置后才能初始化
+		softwareCollector:          NewSoftwareCollector(),
+		wireGuardCollector:         NewWireGuardCollector(),
+		pingCollector:              NewPingCollector(),
+		mountCollector:             NewMountCollector(cfg),
+		fileUsageCollector:         NewFileUsageCollector(cfg),
+		timeSyncCollector:          NewTimeSyncCollector(cfg),
+		listeningPortCollector:     NewListeningPortCollector(),
+		customMetricCollector:      NewCustomMetricCollector(cfg),
+		pressureCollector:          NewPressureCollector(),
 	}
 }
 
+// ApplyConfig 应用服务端下发的采集器配置
+func (m *Manager) ApplyConfig(config protocol.CollectorConfigPayload) {
+	m.cpuCollector.SetPerCore(config.PerCoreCPU)
+}
+
 // CollectAndSendCPU 采集并发送 CPU 指标
 func (m *Manager) CollectAndSendCPU(conn WebSocketWriter) error {
 	cpuData, err := m.cpuCollector.Collect()
@@ -132,12 +161,141 @@ func (m *Manager) CollectAndSendTemperature(conn WebSocketWriter) error {
 	return m.sendMetrics(conn, protocol.MetricTypeTemperature, tempDataList)
 }
 
-// CollectAndSendMonitor 采集并发送监控数据
+// CollectAndSendWireGuard 采集并发送 WireGuard 隧道状态
+func (m *Manager) CollectAndSendWireGuard(conn WebSocketWriter) error {
+	tunnels, err := m.wireGuardCollector.Collect()
+	if err != nil || len(tunnels) == 0 {
+		// WireGuard 监控不是必须的,失败或无隧道时直接返回
+		return nil
+	}
+
+	return m.sendMetrics(conn, protocol.MetricTypeWireGuard, tunnels)
+}
+
+// CollectAndSendMount 探测并发送网络挂载点健康状态，未配置挂载点时不发送
+func (m *Manager) CollectAndSendMount(conn WebSocketWriter) error {
+	if !m.mountCollector.Enabled() {
+		return nil
+	}
+	mounts, err := m.mountCollector.Collect()
+	if err != nil {
+		return err
+	}
+
+	return m.sendMetrics(conn, protocol.MetricTypeMount, mounts)
+}
+
+// CollectAndSendTimeSync 查询并发送时钟偏差，未配置 NTP 服务器时不发送
+func (m *Manager) CollectAndSendTimeSync(conn WebSocketWriter) error {
+	if !m.timeSyncCollector.Enabled() {
+		return nil
+	}
+
+	return m.sendMetrics(conn, protocol.MetricTypeTimeSync, m.timeSyncCollector.Collect())
+}
+
+// CollectAndSendListeningPort 采集并发送监听端口
+func (m *Manager) CollectAndSendListeningPort(conn WebSocketWriter) error {
+	ports, err := m.listeningPortCollector.Collect()
+	if err != nil {
+		return err
+	}
+
+	return m.sendMetrics(conn, protocol.MetricTypeListeningPort, ports)
+}
+
+// CollectAndSendCustom 采集并发送自定义指标，未配置时不发送；部分来源失败时仍发送其他来源的指标
+func (m *Manager) CollectAndSendCustom(conn WebSocketWriter) error {
+	if !m.customMetricCollector.Enabled() {
+		return nil
+	}
+	metrics, collectErr := m.customMetricCollector.Collect()
+	if err := m.sendMetrics(conn, protocol.MetricTypeCustom, metrics); err != nil {
+		return err
+	}
+
+	return collectErr
+}
+
+// CollectAndSendFileUsage 采集并发送文件描述符和 inode 使用情况
+func (m *Manager) CollectAndSendFileUsage(conn WebSocketWriter) error {
+	fileUsage, err := m.fileUsageCollector.Collect()
+	if err != nil {
+		return err
+	}
+
+	return m.sendMetrics(conn, protocol.MetricTypeFileUsage, fileUsage)
+}
+
+// CollectAndSendPressure 采集并发送 PSI 压力数据，系统不支持时不发送
+func (m *Manager) CollectAndSendPressure(conn WebSocketWriter) error {
+	pressure, err := m.pressureCollector.Collect()
+	if err != nil {
+		return err
+	}
+	if pressure == nil {
+		return nil
+	}
+
+	return m.sendMetrics(conn, protocol.MetricTypePressure, pressure)
+}
+
+// SendCollectorHealth 发送采集器健康状态
+func (m *Manager) SendCollectorHealth(conn WebSocketWriter, health []protocol.CollectorHealth) error {
+	return m.sendMetrics(conn, protocol.MetricTypeCollectorHealth, health)
+}
+
+// CollectAndSendPing 采集并发送 Ping 目标的延迟和丢包
+func (m *Manager) CollectAndSendPing(conn WebSocketWriter, targets []protocol.PingTarget) error {
+	if len(targets) == 0 {
+		return nil
+	}
+	pingDataList := m.pingCollector.Collect(targets)
+	return m.sendMetrics(conn, protocol.MetricTypePing, pingDataList)
+}
+
+// CollectAndSendMonitor 采集并发送监控数据，变为离线的监控项在后台执行路径追踪后单独上报
 func (m *Manager) CollectAndSendMonitor(conn WebSocketWriter, items []protocol.MonitorItem) error {
 	monitorDataList := m.monitorCollector.Collect(items)
+	for _, trace := range m.monitorCollector.traceTransitions(items, monitorDataList) {
+		go m.sendMonitorTrace(conn, trace)
+	}
 	return m.sendMetrics(conn, protocol.MetricTypeMonitor, monitorDataList)
 }
 
+// sendMonitorTrace 执行路径追踪并上报结果
+func (m *Manager) sendMonitorTrace(conn WebSocketWriter, trace protocol.MonitorTraceData) {
+	trace = m.monitorCollector.runTrace(trace)
+	dataBytes, err := json.Marshal(trace)
+	if err != nil {
+		return
+	}
+	_ = conn.WriteJSON(protocol.Message{
+		Type: protocol.MessageTypeMonitorTrace,
+		Data: dataBytes,
+	})
+}
+
+// CollectAndSendSoftware 采集并发送软件清单
+func (m *Manager) CollectAndSendSoftware(conn WebSocketWriter) error {
+	inventory, err := m.softwareCollector.Collect()
+	if err != nil {
+		return err
+	}
+
+	dataBytes, err := json.Marshal(inventory)
+	if err != nil {
+		return err
+	}
+
+	msg := protocol.Message{
+		Type: protocol.MessageTypeSoftwareInventory,
+		Data: dataBytes,
+	}
+
+	return conn.WriteJSON(msg)
+}
+
 // UpdateDDNSConfig 更新 DDNS 配置
 func (m *Manager) UpdateDDNSConfig(config *protocol.DDNSConfigData) {
 	if config == nil || !config.Enabled {
@@ -183,6 +341,17 @@ func (m *Manager) CollectAndSendDDNSIP(conn WebSocketWriter) error {
 
 // sendMetrics 发送指标数据
 func (m *Manager) sendMetrics(conn WebSocketWriter, metricType protocol.MetricType, data interface{}) error {
+	if batch, ok := conn.(*BatchWriter); ok {
+		return batch.add(metricType, data)
+	}
+	if writer, ok := conn.(BinaryWriter); ok && writer.Encoding() == protocol.EncodingMsgpack {
+		frame, err := protocol.MarshalBinaryMetrics(metricType, data)
+		if err != nil {
+			return err
+		}
+		return writer.WriteBinary(frame)
+	}
+
 	dataBytes, err := json.Marshal(data)
 	if err != nil {
 		return err
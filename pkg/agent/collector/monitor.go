@@ -1,13 +1,18 @@
 package collector
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	probing "github.com/prometheus-community/pro-bing"
@@ -18,6 +23,12 @@ import (
 // MonitorCollector 监控采集器
 type MonitorCollector struct {
 	httpClient *http.Client
+
+	// 各监控项上一次的检测状态，用于在变为离线时触发路径追踪
+	traceMu    sync.Mutex
+	lastStatus map[string]string
+	tracing    map[string]bool
+	traceSem   chan struct{}
 }
 
 // NewMonitorCollector 创建监控采集器
@@ -30,17 +41,13 @@ func NewMonitorCollector() *MonitorCollector {
 			},
 			DisableKeepAlives: true,
 		},
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			// 限制重定向次数为 10
-			if len(via) >= 10 {
-				return fmt.Errorf("stopped after 10 redirects")
-			}
-			return nil
-		},
 	}
 
 	return &MonitorCollector{
 		httpClient: httpClient,
+		lastStatus: make(map[string]string),
+		tracing:    make(map[string]bool),
+		traceSem:   make(chan struct{}, traceMaxConcurrent),
 	}
 }
 
@@ -53,32 +60,40 @@ func (c *MonitorCollector) Collect(items []protocol.MonitorItem) []protocol.Moni
 	results := make([]protocol.MonitorData, 0, len(items))
 
 	for _, item := range items {
-		var result protocol.MonitorData
-
-		switch strings.ToLower(item.Type) {
-		case "http", "https":
-			result = c.checkHTTP(item)
-		case "tcp":
-			result = c.checkTCP(item)
-		case "icmp", "ping":
-			result = c.checkICMP(item)
-		default:
-			result = protocol.MonitorData{
-				ID:        item.ID,
-				Type:      item.Type,
-				Target:    item.Target,
-				Status:    "down",
-				Error:     fmt.Sprintf("unsupported monitor type: %s", item.Type),
-				CheckedAt: time.Now().UnixMilli(),
-			}
-		}
-
-		results = append(results, result)
+		item = withItemTimeout(item)
+		results = append(results, checkWithRetries(item, monitorRetryDelay, c.check))
 	}
 
 	return results
 }
 
+// check 按监控类型执行一次检测
+func (c *MonitorCollector) check(item protocol.MonitorItem) protocol.MonitorData {
+	switch strings.ToLower(item.Type) {
+	case "http", "https":
+		return c.checkHTTP(item)
+	case "tcp":
+		return c.checkTCP(item)
+	case "udp":
+		return c.checkUDP(item)
+	case "transaction":
+		return c.checkTransaction(item)
+	case "smtp", "imap", "pop3":
+		return c.checkMail(item)
+	case "icmp", "ping":
+		return c.checkICMP(item)
+	default:
+		return protocol.MonitorData{
+			ID:        item.ID,
+			Type:      item.Type,
+			Target:    item.Target,
+			Status:    "down",
+			Error:     fmt.Sprintf("unsupported monitor type: %s", item.Type),
+			CheckedAt: time.Now().UnixMilli(),
+		}
+	}
+}
+
 // checkHTTP 检查 HTTP/HTTPS 服务
 func (c *MonitorCollector) checkHTTP(item protocol.MonitorItem) protocol.MonitorData {
 	result := protocol.MonitorData{
@@ -98,20 +113,43 @@ func (c *MonitorCollector) checkHTTP(item protocol.MonitorItem) protocol.Monitor
 		}
 	}
 
-	// 设置默认值
-	method := httpCfg.Method
-	if method == "" {
-		method = "GET"
-	}
-
 	timeout := httpCfg.Timeout
 	if timeout <= 0 {
 		timeout = 60
 	}
 
-	expectedStatus := httpCfg.ExpectedStatusCode
-	if expectedStatus == 0 {
-		expectedStatus = 200
+	// 为请求创建带超时的上下文
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	client, err := withProxy(*c.httpClient, item.Proxy)
+	if err != nil {
+		result.Status = "down"
+		result.Error = fmt.Sprintf("proxy config error: %v", err)
+		return result
+	}
+	client.CheckRedirect = redirectPolicy(httpCfg)
+	if _, ok := doHTTP(ctx, &client, item.Target, httpCfg, false, &result); ok {
+		// 检查成功
+		result.Status = "up"
+		result.Message = fmt.Sprintf("HTTP %d - %dms", result.StatusCode, result.ResponseTime)
+	}
+	return result
+}
+
+// httpResponse 一次 HTTP 请求的响应，用于事务步骤提取变量
+type httpResponse struct {
+	header http.Header
+	body   []byte
+}
+
+// doHTTP 发送一次 HTTP 请求并校验状态码和响应内容，状态码、响应时间等写入 result，
+// 失败时同时写入 Status 和 Error 并返回 false；keepBody 为 true 时总是读取响应体
+func doHTTP(ctx context.Context, client *http.Client, target string, httpCfg *protocol.HTTPMonitorConfig, keepBody bool, result *protocol.MonitorData) (*httpResponse, bool) {
+	// 设置默认值
+	method := strings.ToUpper(httpCfg.Method)
+	if method == "" {
+		method = "GET"
 	}
 
 	// 创建请求
@@ -120,88 +158,137 @@ func (c *MonitorCollector) checkHTTP(item protocol.MonitorItem) protocol.Monitor
 		bodyReader = strings.NewReader(httpCfg.Body)
 	}
 
-	// 为请求创建带超时的上下文
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
-	defer cancel()
-
 	// 为请求添加上下文
-	req, err := http.NewRequestWithContext(ctx, method, item.Target, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, target, bodyReader)
 	if err != nil {
 		result.Status = "down"
 		result.Error = fmt.Sprintf("create request failed: %v", err)
-		return result
+		return nil, false
 	}
 
-	// 设置请求头
-	if httpCfg.Headers != nil {
-		for key, value := range httpCfg.Headers {
-			req.Header.Set(key, value)
+	// 设置请求头，Host 需要通过 req.Host 设置才会生效
+	for key, value := range httpCfg.Headers {
+		if strings.EqualFold(key, "Host") {
+			req.Host = value
+			continue
+		}
+		req.Header.Set(key, value)
+	}
+	// 请求头中已指定 Authorization 时以请求头为准
+	if auth := httpCfg.Auth; auth != nil && req.Header.Get("Authorization") == "" {
+		switch auth.Type {
+		case protocol.HTTPAuthBasic:
+			req.SetBasicAuth(auth.Username, auth.Password)
+		case protocol.HTTPAuthBearer:
+			req.Header.Set("Authorization", "Bearer "+auth.Token)
 		}
 	}
+	// 未指定 Content-Type 时，JSON 格式的请求体按 application/json 发送
+	if httpCfg.Body != "" && req.Header.Get("Content-Type") == "" && json.Valid([]byte(httpCfg.Body)) {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
 	// 发送请求并计时
 	startTime := time.Now()
-	resp, err := c.httpClient.Do(req)
-	responseTime := time.Since(startTime).Milliseconds()
-	result.ResponseTime = responseTime
+	resp, err := client.Do(req)
+	result.ResponseTime = time.Since(startTime).Milliseconds()
 
 	if err != nil {
 		result.Status = "down"
 		result.Error = fmt.Sprintf("request failed: %v", err)
-		return result
+		return nil, false
 	}
 	defer resp.Body.Close()
 
 	result.StatusCode = resp.StatusCode
 
 	// 检查状态码
-	if resp.StatusCode != expectedStatus {
+	if accepted, expected := statusAccepted(httpCfg, resp.StatusCode); !accepted {
 		result.Status = "down"
-		result.Error = fmt.Sprintf("status code mismatch: expected %d, got %d", expectedStatus, resp.StatusCode)
+		result.Error = fmt.Sprintf("status code mismatch: expected %s, got %d", expected, resp.StatusCode)
 		result.Message = fmt.Sprintf("HTTP %d", resp.StatusCode)
-		return result
+		return nil, false
 	}
 
-	// 检查响应内容（如果有配置）
+	// 检查响应内容（如果有配置），期望内容按包含断言处理
+	assertions := httpCfg.Assertions
 	if httpCfg.ExpectedContent != "" {
-		body, err := io.ReadAll(resp.Body)
+		assertions = append([]protocol.HTTPAssertion{{Type: protocol.AssertionContains, Value: httpCfg.ExpectedContent}}, assertions...)
+	}
+	response := &httpResponse{header: resp.Header}
+	if len(assertions) > 0 || keepBody {
+		response.body, err = io.ReadAll(io.LimitReader(resp.Body, maxAssertBodySize))
 		if err != nil {
 			result.Status = "down"
 			result.Error = fmt.Sprintf("read response body failed: %v", err)
-			return result
+			return nil, false
 		}
-
-		bodyStr := string(body)
-		if !strings.Contains(bodyStr, httpCfg.ExpectedContent) {
+	}
+	if len(assertions) > 0 {
+		passed, results := checkAssertions(response.body, assertions)
+		details := make([]string, 0, len(results))
+		for _, r := range results {
+			details = append(details, r.detail)
+		}
+		result.ContentMatch = passed
+		result.ContentDetail = strings.Join(details, "; ")
+		if !passed {
 			result.Status = "down"
-			result.Error = fmt.Sprintf("content does not contain expected string: %s", httpCfg.ExpectedContent)
-			result.ContentMatch = false
-			return result
+			for _, r := range results {
+				if !r.passed {
+					result.Error = "content assertion failed: " + r.detail
+					break
+				}
+			}
+			return nil, false
 		}
-		result.ContentMatch = true
 	}
 
-	// 获取 HTTPS 证书信息
-	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
-		// 获取第一个证书（服务器证书）
-		cert := resp.TLS.PeerCertificates[0]
+	// 获取 HTTPS 证书信息，按最终请求的主机名校验证书链
+	applyCert(result, resp.TLS, resp.Request.URL.Hostname())
 
-		// 证书过期时间
-		expiryTime := cert.NotAfter
-		result.CertExpiryTime = expiryTime.UnixMilli()
+	return response, true
+}
 
-		// 计算剩余天数
-		daysLeft := int(time.Until(expiryTime).Hours() / 24)
-		result.CertDaysLeft = daysLeft
+// redirectPolicy 按监控配置处理重定向，不跟随时直接以重定向响应作为检测结果
+func redirectPolicy(cfg *protocol.HTTPMonitorConfig) func(req *http.Request, via []*http.Request) error {
+	if cfg.FollowRedirects != nil && !*cfg.FollowRedirects {
+		return func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
 	}
+	maxRedirects := cfg.MaxRedirects
+	if maxRedirects <= 0 {
+		maxRedirects = protocol.DefaultMaxRedirects
+	}
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		return nil
+	}
+}
 
-	// 检查成功
-	result.Status = "up"
-	result.Message = fmt.Sprintf("HTTP %d - %dms", resp.StatusCode, responseTime)
-	return result
+// statusAccepted 判断状态码是否符合预期，配置了可接受的状态码时以其为准，否则与期望状态码（默认 200）比较
+func statusAccepted(cfg *protocol.HTTPMonitorConfig, code int) (bool, string) {
+	if len(cfg.AcceptedStatusCodes) == 0 {
+		expected := cfg.ExpectedStatusCode
+		if expected == 0 {
+			expected = 200
+		}
+		return code == expected, strconv.Itoa(expected)
+	}
+	for _, pattern := range cfg.AcceptedStatusCodes {
+		// 服务端保存时已校验，无法解析的配置忽略
+		min, max, err := protocol.ParseStatusCodeRange(pattern)
+		if err == nil && code >= min && code <= max {
+			return true, ""
+		}
+	}
+	return false, strings.Join(cfg.AcceptedStatusCodes, ", ")
 }
 
-// checkTCP 检查 TCP 端口
+// checkTCP 检查 TCP 端口，配置了发送内容或期望响应时校验服务的应答（如 SMTP 欢迎语、Redis PONG）
 func (c *MonitorCollector) checkTCP(item protocol.MonitorItem) protocol.MonitorData {
 	result := protocol.MonitorData{
 		ID:        item.ID,
@@ -212,14 +299,18 @@ func (c *MonitorCollector) checkTCP(item protocol.MonitorItem) protocol.MonitorD
 
 	// 获取配置，使用默认值
 	tcpCfg := item.TCPConfig
+	if tcpCfg == nil {
+		tcpCfg = &protocol.TCPMonitorConfig{}
+	}
 	timeout := 10 // 默认 10 秒
-	if tcpCfg != nil && tcpCfg.Timeout > 0 {
+	if tcpCfg.Timeout > 0 {
 		timeout = tcpCfg.Timeout
 	}
+	deadline := time.Now().Add(time.Duration(timeout) * time.Second)
 
 	// 连接并计时
 	startTime := time.Now()
-	conn, err := net.DialTimeout("tcp", item.Target, time.Duration(timeout)*time.Second)
+	conn, err := dialTCP(item.Proxy, item.Target, time.Duration(timeout)*time.Second)
 	responseTime := time.Since(startTime).Milliseconds()
 	result.ResponseTime = responseTime
 
@@ -230,12 +321,214 @@ func (c *MonitorCollector) checkTCP(item protocol.MonitorItem) protocol.MonitorD
 	}
 	defer conn.Close()
 
-	// 连接成功
+	if tcpCfg.Send == "" && tcpCfg.Expect == "" {
+		// 连接成功
+		result.Status = "up"
+		result.Message = fmt.Sprintf("TCP connected - %dms", responseTime)
+		return result
+	}
+
+	_ = conn.SetDeadline(deadline)
+	payload := unescapePayload(tcpCfg.Send)
+	if len(payload) > 0 {
+		if _, err := conn.Write(payload); err != nil {
+			result.Status = "down"
+			result.Error = fmt.Sprintf("send failed: %v", err)
+			return result
+		}
+	}
+	if tcpCfg.Expect == "" {
+		result.Status = "up"
+		result.Message = fmt.Sprintf("TCP connected and sent %d bytes - %dms", len(payload), responseTime)
+		return result
+	}
+
+	matcher, err := newResponseMatcher(tcpCfg.ExpectMode, tcpCfg.Expect)
+	if err != nil {
+		result.Status = "down"
+		result.Error = err.Error()
+		return result
+	}
+	response, matched, err := readExpected(conn, matcher)
+	result.ResponseTime = time.Since(startTime).Milliseconds()
+	result.ContentMatch = matched
+	if !matched {
+		result.Status = "down"
+		if err != nil && len(response) == 0 {
+			result.Error = fmt.Sprintf("read response failed: %v", err)
+		} else {
+			result.Error = fmt.Sprintf("response does not match expected %s: %s", matcher.mode, tcpCfg.Expect)
+		}
+		result.Message = truncateResponse(response)
+		return result
+	}
+
+	result.Status = "up"
+	result.Message = fmt.Sprintf("TCP response matched - %dms", result.ResponseTime)
+	return result
+}
+
+// checkUDP 检查 UDP 服务：发送一个数据报并等待应答，UDP 无连接，只有收到应答才能确认服务可用
+func (c *MonitorCollector) checkUDP(item protocol.MonitorItem) protocol.MonitorData {
+	result := protocol.MonitorData{
+		ID:        item.ID,
+		Type:      item.Type,
+		Target:    item.Target,
+		CheckedAt: time.Now().UnixMilli(),
+	}
+
+	// 获取配置，使用默认值
+	udpCfg := item.UDPConfig
+	if udpCfg == nil {
+		udpCfg = &protocol.UDPMonitorConfig{}
+	}
+	timeout := 5 // 默认 5 秒
+	if udpCfg.Timeout > 0 {
+		timeout = udpCfg.Timeout
+	}
+
+	var matcher *responseMatcher
+	if udpCfg.Expect != "" {
+		var err error
+		if matcher, err = newResponseMatcher(udpCfg.ExpectMode, udpCfg.Expect); err != nil {
+			result.Status = "down"
+			result.Error = err.Error()
+			return result
+		}
+	}
+
+	startTime := time.Now()
+	conn, err := net.DialTimeout("udp", item.Target, time.Duration(timeout)*time.Second)
+	if err != nil {
+		result.Status = "down"
+		result.Error = fmt.Sprintf("resolve failed: %v", err)
+		return result
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(startTime.Add(time.Duration(timeout) * time.Second))
+
+	if _, err := conn.Write(unescapePayload(udpCfg.Send)); err != nil {
+		result.Status = "down"
+		result.Error = fmt.Sprintf("send failed: %v", err)
+		return result
+	}
+
+	// 一个数据报就是一次完整的应答，目标端口未监听时通常会收到 ICMP 端口不可达，读取返回 connection refused
+	buf := make([]byte, maxExpectResponseSize)
+	n, err := conn.Read(buf)
+	result.ResponseTime = time.Since(startTime).Milliseconds()
+	if err != nil {
+		result.Status = "down"
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			result.Error = fmt.Sprintf("no response within %ds", timeout)
+		} else {
+			result.Error = fmt.Sprintf("read response failed: %v", err)
+		}
+		return result
+	}
+
+	response := buf[:n]
+	if matcher != nil {
+		matched, _ := matcher.match(response)
+		result.ContentMatch = matched
+		if !matched {
+			result.Status = "down"
+			result.Error = fmt.Sprintf("response does not match expected %s: %s", matcher.mode, udpCfg.Expect)
+			result.Message = truncateResponse(response)
+			return result
+		}
+	}
+
 	result.Status = "up"
-	result.Message = fmt.Sprintf("TCP connected - %dms", responseTime)
+	result.Message = fmt.Sprintf("UDP response %d bytes - %dms", n, result.ResponseTime)
 	return result
 }
 
+// maxExpectResponseSize 校验响应内容时最多读取的字节数
+const maxExpectResponseSize = 64 * 1024
+
+// responseMatcher 响应内容匹配器
+type responseMatcher struct {
+	mode    string
+	expect  []byte
+	pattern *regexp.Regexp
+}
+
+func newResponseMatcher(mode, expect string) (*responseMatcher, error) {
+	m := &responseMatcher{mode: mode, expect: unescapePayload(expect)}
+	switch mode {
+	case "", protocol.ExpectModeContains:
+		m.mode = protocol.ExpectModeContains
+	case protocol.ExpectModePrefix:
+	case protocol.ExpectModeRegex:
+		pattern, err := regexp.Compile(expect)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expect regex: %v", err)
+		}
+		m.pattern = pattern
+	default:
+		return nil, fmt.Errorf("unsupported expect mode: %s", mode)
+	}
+	return m, nil
+}
+
+// match 判断已读取的响应是否匹配，decided 为 true 表示继续读取也不会改变结果
+func (m *responseMatcher) match(response []byte) (matched, decided bool) {
+	switch m.mode {
+	case protocol.ExpectModePrefix:
+		if len(response) < len(m.expect) {
+			return false, !bytes.HasPrefix(m.expect, response)
+		}
+		return bytes.HasPrefix(response, m.expect), true
+	case protocol.ExpectModeRegex:
+		matched = m.pattern.Match(response)
+		return matched, matched
+	default:
+		matched = bytes.Contains(response, m.expect)
+		return matched, matched
+	}
+}
+
+// readExpected 持续读取响应直到匹配、连接关闭、超时或达到读取上限
+func readExpected(conn net.Conn, matcher *responseMatcher) ([]byte, bool, error) {
+	var response []byte
+	buf := make([]byte, 4096)
+	for len(response) < maxExpectResponseSize {
+		n, err := conn.Read(buf)
+		response = append(response, buf[:n]...)
+		if matched, decided := matcher.match(response); decided {
+			return response, matched, nil
+		}
+		if err != nil {
+			return response, false, err
+		}
+	}
+	matched, _ := matcher.match(response)
+	return response, matched, nil
+}
+
+// unescapePayload 解析发送内容和期望内容中的转义字符，无法解析时按原样使用
+func unescapePayload(payload string) []byte {
+	if !strings.Contains(payload, `\`) {
+		return []byte(payload)
+	}
+	unquoted, err := strconv.Unquote(`"` + strings.ReplaceAll(payload, `"`, `\"`) + `"`)
+	if err != nil {
+		return []byte(payload)
+	}
+	return []byte(unquoted)
+}
+
+// truncateResponse 截取响应内容用于展示
+func truncateResponse(response []byte) string {
+	const maxLen = 200
+	text := strings.ToValidUTF8(string(response), "?")
+	if len(text) > maxLen {
+		text = text[:maxLen] + "..."
+	}
+	return strings.TrimSpace(text)
+}
+
 // checkICMP 检查 ICMP (Ping)
 func (c *MonitorCollector) checkICMP(item protocol.MonitorItem) protocol.MonitorData {
 	result := protocol.MonitorData{
@@ -0,0 +1,171 @@
+package collector
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/pkg/jsonpath"
+)
+
+// maxAssertBodySize 校验响应内容时最多读取的字节数
+const maxAssertBodySize = 1 << 20
+
+// assertionResult 单条断言的结果
+type assertionResult struct {
+	passed bool
+	detail string
+}
+
+// checkAssertions 依次执行响应内容断言，返回是否全部通过和每条断言的结果
+func checkAssertions(body []byte, assertions []protocol.HTTPAssertion) (bool, []assertionResult) {
+	var (
+		doc     interface{}
+		docErr  error
+		decoded bool
+	)
+	// 响应只在存在 JSONPath 断言时按 JSON 解析一次
+	parseJSON := func() (interface{}, error) {
+		if !decoded {
+			decoded = true
+			doc, docErr = decodeJSON(body)
+		}
+		return doc, docErr
+	}
+
+	allPassed := true
+	results := make([]assertionResult, 0, len(assertions))
+	for _, a := range assertions {
+		passed, reason := evaluateAssertion(body, a, parseJSON)
+		detail := describeAssertion(a) + ": passed"
+		if !passed {
+			allPassed = false
+			detail = describeAssertion(a) + ": failed"
+			if reason != "" {
+				detail += ", " + reason
+			}
+		}
+		results = append(results, assertionResult{passed: passed, detail: detail})
+	}
+	return allPassed, results
+}
+
+// evaluateAssertion 执行单条断言，未通过时 reason 说明实际取到的值或失败原因
+func evaluateAssertion(body []byte, a protocol.HTTPAssertion, parseJSON func() (interface{}, error)) (passed bool, reason string) {
+	switch a.Type {
+	case protocol.AssertionContains:
+		return bytes.Contains(body, []byte(a.Value)), ""
+	case protocol.AssertionNotContains:
+		return !bytes.Contains(body, []byte(a.Value)), ""
+	case protocol.AssertionRegex, protocol.AssertionNotRegex:
+		pattern, err := regexp.Compile(a.Value)
+		if err != nil {
+			return false, fmt.Sprintf("invalid regex: %v", err)
+		}
+		return pattern.Match(body) == (a.Type == protocol.AssertionRegex), ""
+	case protocol.AssertionJSONPath:
+		path, err := jsonpath.Compile(a.Path)
+		if err != nil {
+			return false, err.Error()
+		}
+		doc, err := parseJSON()
+		if err != nil {
+			return false, "response is not valid JSON"
+		}
+		value, found := path.Lookup(doc)
+		return compareJSONValue(a.Operator, value, found, a.Value)
+	default:
+		return false, "unsupported assertion type"
+	}
+}
+
+// compareJSONValue 按比较方式比较 JSONPath 取到的值，字符串按原文比较，其余值按 JSON 文本比较
+func compareJSONValue(operator string, value interface{}, found bool, expected string) (bool, string) {
+	switch operator {
+	case protocol.AssertionOpExists:
+		return found, "path not found"
+	case protocol.AssertionOpNotExists:
+		return !found, "path exists"
+	}
+	if !found {
+		return false, "path not found"
+	}
+
+	actual := jsonText(value)
+	got := "got " + truncateResponse([]byte(actual))
+	switch operator {
+	case "", protocol.AssertionOpEquals:
+		return actual == expected, got
+	case protocol.AssertionOpNotEquals:
+		return actual != expected, got
+	case protocol.AssertionOpContains:
+		return strings.Contains(actual, expected), got
+	case protocol.AssertionOpRegex:
+		pattern, err := regexp.Compile(expected)
+		if err != nil {
+			return false, fmt.Sprintf("invalid regex: %v", err)
+		}
+		return pattern.MatchString(actual), got
+	case protocol.AssertionOpGreater, protocol.AssertionOpLess:
+		number, ok := value.(json.Number)
+		if !ok {
+			return false, got + ", not a number"
+		}
+		actualNum, err1 := number.Float64()
+		expectedNum, err2 := strconv.ParseFloat(expected, 64)
+		if err1 != nil || err2 != nil {
+			return false, got + ", not a number"
+		}
+		if operator == protocol.AssertionOpGreater {
+			return actualNum > expectedNum, got
+		}
+		return actualNum < expectedNum, got
+	default:
+		return false, "unsupported operator"
+	}
+}
+
+// decodeJSON 解析 JSON 响应体，数字保留原文以便按文本比较
+func decodeJSON(body []byte) (interface{}, error) {
+	var doc interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	err := decoder.Decode(&doc)
+	return doc, err
+}
+
+// jsonText 将 JSON 值转为用于比较的文本
+func jsonText(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case nil:
+		return "null"
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// describeAssertion 断言的简短描述，用于上报结果
+func describeAssertion(a protocol.HTTPAssertion) string {
+	switch a.Type {
+	case protocol.AssertionJSONPath:
+		operator := a.Operator
+		if operator == "" {
+			operator = protocol.AssertionOpEquals
+		}
+		if operator == protocol.AssertionOpExists || operator == protocol.AssertionOpNotExists {
+			return fmt.Sprintf("jsonpath %s %s", a.Path, operator)
+		}
+		return fmt.Sprintf("jsonpath %s %s %q", a.Path, operator, a.Value)
+	default:
+		return fmt.Sprintf("%s %q", a.Type, a.Value)
+	}
+}
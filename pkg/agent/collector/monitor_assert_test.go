@@ -0,0 +1,63 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+func TestCheckAssertions(t *testing.T) {
+	body := []byte(`{"status":"ok","version":"1.2.3","queue":{"size":42,"ready":true},"items":[{"id":"a"},{"id":"b"}],"error":null}`)
+
+	tests := []struct {
+		name       string
+		assertion  protocol.HTTPAssertion
+		wantPassed bool
+		wantDetail string
+	}{
+		{name: "包含", assertion: protocol.HTTPAssertion{Type: protocol.AssertionContains, Value: `"ok"`}, wantPassed: true},
+		{name: "不包含-通过", assertion: protocol.HTTPAssertion{Type: protocol.AssertionNotContains, Value: "maintenance"}, wantPassed: true},
+		{name: "不包含-失败", assertion: protocol.HTTPAssertion{Type: protocol.AssertionNotContains, Value: "status"}, wantPassed: false},
+		{name: "正则", assertion: protocol.HTTPAssertion{Type: protocol.AssertionRegex, Value: `"version":"1\.\d+`}, wantPassed: true},
+		{name: "正则不匹配", assertion: protocol.HTTPAssertion{Type: protocol.AssertionNotRegex, Value: `"status":"(down|error)"`}, wantPassed: true},
+		{name: "无效正则", assertion: protocol.HTTPAssertion{Type: protocol.AssertionRegex, Value: `([`}, wantPassed: false, wantDetail: "invalid regex"},
+		{name: "JSONPath 默认相等", assertion: protocol.HTTPAssertion{Type: protocol.AssertionJSONPath, Path: "$.status", Value: "ok"}, wantPassed: true},
+		{name: "JSONPath 相等失败", assertion: protocol.HTTPAssertion{Type: protocol.AssertionJSONPath, Path: "$.items[1].id", Value: "a"}, wantPassed: false, wantDetail: "got b"},
+		{name: "JSONPath 布尔值", assertion: protocol.HTTPAssertion{Type: protocol.AssertionJSONPath, Path: "$.queue.ready", Value: "true"}, wantPassed: true},
+		{name: "JSONPath null", assertion: protocol.HTTPAssertion{Type: protocol.AssertionJSONPath, Path: "$.error", Value: "null"}, wantPassed: true},
+		{name: "JSONPath 不相等", assertion: protocol.HTTPAssertion{Type: protocol.AssertionJSONPath, Path: "$.status", Operator: protocol.AssertionOpNotEquals, Value: "down"}, wantPassed: true},
+		{name: "JSONPath 包含", assertion: protocol.HTTPAssertion{Type: protocol.AssertionJSONPath, Path: "$.items", Operator: protocol.AssertionOpContains, Value: `"id":"b"`}, wantPassed: true},
+		{name: "JSONPath 正则", assertion: protocol.HTTPAssertion{Type: protocol.AssertionJSONPath, Path: "$.version", Operator: protocol.AssertionOpRegex, Value: `^1\.`}, wantPassed: true},
+		{name: "JSONPath 存在", assertion: protocol.HTTPAssertion{Type: protocol.AssertionJSONPath, Path: "$.queue.size", Operator: protocol.AssertionOpExists}, wantPassed: true},
+		{name: "JSONPath 不存在", assertion: protocol.HTTPAssertion{Type: protocol.AssertionJSONPath, Path: "$.queue.failed", Operator: protocol.AssertionOpNotExists}, wantPassed: true},
+		{name: "JSONPath 路径不存在", assertion: protocol.HTTPAssertion{Type: protocol.AssertionJSONPath, Path: "$.missing", Value: "x"}, wantPassed: false, wantDetail: "path not found"},
+		{name: "JSONPath 小于", assertion: protocol.HTTPAssertion{Type: protocol.AssertionJSONPath, Path: "$.queue.size", Operator: protocol.AssertionOpLess, Value: "100"}, wantPassed: true},
+		{name: "JSONPath 大于失败", assertion: protocol.HTTPAssertion{Type: protocol.AssertionJSONPath, Path: "$.queue.size", Operator: protocol.AssertionOpGreater, Value: "100"}, wantPassed: false, wantDetail: "got 42"},
+		{name: "JSONPath 比较非数字", assertion: protocol.HTTPAssertion{Type: protocol.AssertionJSONPath, Path: "$.status", Operator: protocol.AssertionOpGreater, Value: "1"}, wantPassed: false, wantDetail: "not a number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			passed, results := checkAssertions(body, []protocol.HTTPAssertion{tt.assertion})
+			if passed != tt.wantPassed || results[0].passed != tt.wantPassed {
+				t.Fatalf("passed = %v，期望 %v，结果: %s", passed, tt.wantPassed, results[0].detail)
+			}
+			if !strings.Contains(results[0].detail, tt.wantDetail) {
+				t.Fatalf("结果 %q 中应包含 %q", results[0].detail, tt.wantDetail)
+			}
+		})
+	}
+}
+
+func TestCheckAssertionsNonJSONBody(t *testing.T) {
+	passed, results := checkAssertions([]byte("<html>ok</html>"), []protocol.HTTPAssertion{
+		{Type: protocol.AssertionContains, Value: "ok"},
+		{Type: protocol.AssertionJSONPath, Path: "$.status", Value: "ok"},
+	})
+	if passed {
+		t.Fatal("响应不是 JSON 时 JSONPath 断言应失败")
+	}
+	if !results[0].passed || results[1].passed || !strings.Contains(results[1].detail, "not valid JSON") {
+		t.Fatalf("断言结果错误: %+v", results)
+	}
+}
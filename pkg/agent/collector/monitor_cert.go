@@ -0,0 +1,62 @@
+package collector
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+// applyCert 记录服务器证书的过期时间和详情，没有证书时不做处理
+func applyCert(result *protocol.MonitorData, state *tls.ConnectionState, host string) {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return
+	}
+	expiryTime := state.PeerCertificates[0].NotAfter
+	result.CertExpiryTime = expiryTime.UnixMilli()
+	result.CertDaysLeft = int(time.Until(expiryTime).Hours() / 24)
+	result.CertInfo = inspectCert(state.PeerCertificates, host, nil, time.Now())
+}
+
+// inspectCert 提取服务器证书详情并校验证书链
+// 探测时跳过了证书校验，这里以服务器返回的其余证书作为中间证书单独校验，roots 为 nil 时使用系统根证书
+func inspectCert(certs []*x509.Certificate, host string, roots *x509.CertPool, now time.Time) *protocol.CertInfo {
+	leaf := certs[0]
+	sans := make([]string, 0, len(leaf.DNSNames)+len(leaf.IPAddresses))
+	sans = append(sans, leaf.DNSNames...)
+	for _, ip := range leaf.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	fingerprint := sha256.Sum256(leaf.Raw)
+
+	info := &protocol.CertInfo{
+		Subject:            leaf.Subject.String(),
+		Issuer:             leaf.Issuer.String(),
+		SANs:               sans,
+		SignatureAlgorithm: leaf.SignatureAlgorithm.String(),
+		NotBefore:          leaf.NotBefore.UnixMilli(),
+		NotAfter:           leaf.NotAfter.UnixMilli(),
+		Fingerprint:        hex.EncodeToString(fingerprint[:]),
+		ChainLength:        len(certs),
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := leaf.Verify(x509.VerifyOptions{
+		DNSName:       host,
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+	})
+	if err != nil {
+		info.ChainError = err.Error()
+	} else {
+		info.ChainValid = true
+	}
+	return info
+}
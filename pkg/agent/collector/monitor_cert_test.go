@@ -0,0 +1,72 @@
+package collector
+
+import (
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+func TestInspectCert(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	certs := []*x509.Certificate{server.Certificate()}
+	roots := server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+	leaf := server.Certificate()
+
+	tests := []struct {
+		name      string
+		host      string
+		roots     *x509.CertPool
+		now       time.Time
+		wantValid bool
+		wantError string
+	}{
+		{name: "受信任的根证书", host: "example.com", roots: roots, now: time.Now(), wantValid: true},
+		{name: "IP 备用名称", host: "127.0.0.1", roots: roots, now: time.Now(), wantValid: true},
+		{name: "自签名证书不受信任", host: "example.com", now: time.Now(), wantError: "unknown authority"},
+		{name: "主机名不匹配", host: "pika.example.org", roots: roots, now: time.Now(), wantError: "not pika.example.org"},
+		{name: "证书已过期", host: "example.com", roots: roots, now: leaf.NotAfter.Add(time.Hour), wantError: "expired"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := inspectCert(certs, tt.host, tt.roots, tt.now)
+			if info.ChainValid != tt.wantValid {
+				t.Fatalf("证书链校验结果为 %v（%s），期望 %v", info.ChainValid, info.ChainError, tt.wantValid)
+			}
+			if !strings.Contains(info.ChainError, tt.wantError) {
+				t.Fatalf("校验失败原因 %q 中应包含 %q", info.ChainError, tt.wantError)
+			}
+		})
+	}
+
+	info := inspectCert(certs, "example.com", roots, time.Now())
+	if info.Issuer == "" || info.Subject == "" || info.SignatureAlgorithm == "" || info.ChainLength != 1 || len(info.Fingerprint) != 64 {
+		t.Fatalf("证书详情不完整: %+v", info)
+	}
+	if !strings.Contains(strings.Join(info.SANs, ","), "example.com") || info.NotAfter != leaf.NotAfter.UnixMilli() {
+		t.Fatalf("证书备用名称或过期时间错误: %+v", info)
+	}
+}
+
+func TestCheckHTTPCertInfo(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	result := NewMonitorCollector().checkHTTP(protocol.MonitorItem{ID: "m1", Type: "https", Target: server.URL})
+	if result.Status != "up" {
+		t.Fatalf("状态为 %s（%s），期望 up", result.Status, result.Error)
+	}
+	// 跳过校验保证自签名证书的服务仍视为在线，证书链问题单独上报
+	if result.CertInfo == nil || result.CertInfo.ChainValid || result.CertInfo.ChainError == "" {
+		t.Fatalf("自签名证书应上报证书链校验失败: %+v", result.CertInfo)
+	}
+	if result.CertExpiryTime != result.CertInfo.NotAfter {
+		t.Fatalf("证书过期时间 %d 与详情 %d 不一致", result.CertExpiryTime, result.CertInfo.NotAfter)
+	}
+}
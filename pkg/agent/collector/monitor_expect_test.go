@@ -0,0 +1,131 @@
+package collector
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+func TestUnescapePayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    string
+	}{
+		{name: "无转义", payload: "PING", want: "PING"},
+		{name: "换行", payload: `PING\r\n`, want: "PING\r\n"},
+		{name: "十六进制", payload: `\x00\x01\xff`, want: "\x00\x01\xff"},
+		{name: "八进制", payload: `\101`, want: "A"},
+		{name: "Unicode", payload: `你好`, want: "你好"},
+		{name: "双引号", payload: `say "hi"\n`, want: "say \"hi\"\n"},
+		{name: "反斜杠", payload: `a\\b`, want: `a\b`},
+		{name: "无效转义按原样使用", payload: `\q`, want: `\q`},
+		{name: "不完整的十六进制按原样使用", payload: `\x0`, want: `\x0`},
+		{name: "结尾的反斜杠按原样使用", payload: `abc\`, want: `abc\`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(unescapePayload(tt.payload)); got != tt.want {
+				t.Fatalf("unescapePayload(%q) = %q，期望 %q", tt.payload, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewResponseMatcher(t *testing.T) {
+	tests := []struct {
+		mode    string
+		expect  string
+		wantErr bool
+	}{
+		{mode: "", expect: "OK"},
+		{mode: protocol.ExpectModeContains, expect: "OK"},
+		{mode: protocol.ExpectModePrefix, expect: "+OK"},
+		{mode: protocol.ExpectModeRegex, expect: `^SSH-2\.0-`},
+		{mode: protocol.ExpectModeRegex, expect: `([`, wantErr: true},
+		{mode: "suffix", expect: "OK", wantErr: true},
+	}
+	for _, tt := range tests {
+		_, err := newResponseMatcher(tt.mode, tt.expect)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("newResponseMatcher(%q, %q) err = %v，wantErr = %v", tt.mode, tt.expect, err, tt.wantErr)
+		}
+	}
+}
+
+func TestResponseMatcherMatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        string
+		expect      string
+		response    string
+		wantMatched bool
+		wantDecided bool
+	}{
+		{name: "包含-匹配", mode: "", expect: "OK", response: "200 OK\r\n", wantMatched: true, wantDecided: true},
+		{name: "包含-未匹配继续读取", mode: protocol.ExpectModeContains, expect: "OK", response: "200 O", wantMatched: false, wantDecided: false},
+		{name: "包含-转义", mode: protocol.ExpectModeContains, expect: `\x00\x01`, response: "a\x00\x01b", wantMatched: true, wantDecided: true},
+		{name: "前缀-匹配", mode: protocol.ExpectModePrefix, expect: "+OK", response: "+OK ready", wantMatched: true, wantDecided: true},
+		{name: "前缀-不匹配", mode: protocol.ExpectModePrefix, expect: "+OK", response: "-ERR", wantMatched: false, wantDecided: true},
+		{name: "前缀-响应不完整继续读取", mode: protocol.ExpectModePrefix, expect: "+OK", response: "+O", wantMatched: false, wantDecided: false},
+		{name: "前缀-不完整且已不匹配", mode: protocol.ExpectModePrefix, expect: "+OK", response: "-E", wantMatched: false, wantDecided: true},
+		{name: "正则-匹配", mode: protocol.ExpectModeRegex, expect: `^SSH-2\.0-\S+`, response: "SSH-2.0-OpenSSH_9.6", wantMatched: true, wantDecided: true},
+		{name: "正则-未匹配继续读取", mode: protocol.ExpectModeRegex, expect: `^SSH-2\.0-\S+`, response: "SSH-1.99", wantMatched: false, wantDecided: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := newResponseMatcher(tt.mode, tt.expect)
+			if err != nil {
+				t.Fatal(err)
+			}
+			matched, decided := m.match([]byte(tt.response))
+			if matched != tt.wantMatched || decided != tt.wantDecided {
+				t.Fatalf("match(%q) = (%v, %v)，期望 (%v, %v)", tt.response, matched, decided, tt.wantMatched, tt.wantDecided)
+			}
+		})
+	}
+}
+
+func TestReadExpected(t *testing.T) {
+	tests := []struct {
+		name        string
+		chunks      []string
+		wantMatched bool
+	}{
+		{name: "分多次到达后匹配", chunks: []string{"220 smtp.", "example.com ESMTP\r\n"}, wantMatched: true},
+		{name: "连接关闭仍未匹配", chunks: []string{"500 error\r\n"}, wantMatched: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server := net.Pipe()
+			defer client.Close()
+			go func() {
+				for _, chunk := range tt.chunks {
+					_, _ = server.Write([]byte(chunk))
+				}
+				server.Close()
+			}()
+
+			m, _ := newResponseMatcher(protocol.ExpectModeContains, "ESMTP")
+			response, matched, _ := readExpected(client, m)
+			if matched != tt.wantMatched {
+				t.Fatalf("matched = %v，期望 %v，响应 %q", matched, tt.wantMatched, response)
+			}
+		})
+	}
+}
+
+func TestTruncateResponse(t *testing.T) {
+	if got := truncateResponse([]byte("  OK\r\n")); got != "OK" {
+		t.Errorf("truncateResponse = %q，期望 OK", got)
+	}
+	long := truncateResponse([]byte(strings.Repeat("a", 300)))
+	if len(long) != 203 || !strings.HasSuffix(long, "...") {
+		t.Errorf("超长响应应截断为 200 个字符加省略号，得到 %d 个字符", len(long))
+	}
+	if got := truncateResponse([]byte{'a', 0xff, 'b'}); got != "a?b" {
+		t.Errorf("无效的 UTF-8 应替换为 ?，得到 %q", got)
+	}
+}
@@ -0,0 +1,56 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+func TestCheckHTTPRedirectAndStatusPolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.Redirect(w, r, "/private", http.StatusFound)
+		case "/private":
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	no := false
+	tests := []struct {
+		name       string
+		path       string
+		cfg        protocol.HTTPMonitorConfig
+		wantStatus string
+		wantCode   int
+		wantError  string
+	}{
+		{name: "默认期望 200", path: "/", wantStatus: "up", wantCode: 200},
+		{name: "跟随重定向后状态码不符", path: "/login", wantStatus: "down", wantCode: 401, wantError: "expected 200"},
+		{name: "可接受 401", path: "/login", cfg: protocol.HTTPMonitorConfig{AcceptedStatusCodes: []string{"2xx", "401"}}, wantStatus: "up", wantCode: 401},
+		{name: "不跟随重定向", path: "/login", cfg: protocol.HTTPMonitorConfig{FollowRedirects: &no, AcceptedStatusCodes: []string{"300-399"}}, wantStatus: "up", wantCode: 302},
+		{name: "不跟随重定向时期望状态码", path: "/login", cfg: protocol.HTTPMonitorConfig{FollowRedirects: &no}, wantStatus: "down", wantCode: 302},
+		{name: "超过最大重定向次数", path: "/loop", cfg: protocol.HTTPMonitorConfig{MaxRedirects: 2}, wantStatus: "down", wantError: "stopped after 2 redirects"},
+	}
+	c := NewMonitorCollector()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			result := c.checkHTTP(protocol.MonitorItem{ID: "m", Type: "http", Target: server.URL + tt.path, HTTPConfig: &cfg})
+			if result.Status != tt.wantStatus || result.StatusCode != tt.wantCode {
+				t.Fatalf("状态为 %s（%d），期望 %s（%d），错误: %s", result.Status, result.StatusCode, tt.wantStatus, tt.wantCode, result.Error)
+			}
+			if !strings.Contains(result.Error, tt.wantError) {
+				t.Fatalf("错误 %q 中应包含 %q", result.Error, tt.wantError)
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+package collector
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+// checkMail 检查 SMTP/IMAP/POP3 服务：读取欢迎语，按配置完成 TLS 握手和登录认证，并上报证书到期时间
+func (c *MonitorCollector) checkMail(item protocol.MonitorItem) protocol.MonitorData {
+	result := protocol.MonitorData{
+		ID:        item.ID,
+		Type:      item.Type,
+		Target:    item.Target,
+		CheckedAt: time.Now().UnixMilli(),
+	}
+
+	// 获取配置，使用默认值
+	cfg := item.MailConfig
+	if cfg == nil {
+		cfg = &protocol.MailMonitorConfig{}
+	}
+	timeout := 10 // 默认 10 秒
+	if cfg.Timeout > 0 {
+		timeout = cfg.Timeout
+	}
+	mode := cfg.TLSMode
+	if mode == "" {
+		mode = protocol.MailTLSStartTLS
+	}
+	proto := strings.ToUpper(item.Type)
+
+	host, _, err := net.SplitHostPort(item.Target)
+	if err != nil {
+		result.Status = "down"
+		result.Error = fmt.Sprintf("invalid target: %v", err)
+		return result
+	}
+	// 与 HTTP 监控一致握手时不校验证书链，证书链由 applyCert 单独校验
+	tlsConfig := &tls.Config{ServerName: host, InsecureSkipVerify: true}
+
+	startTime := time.Now()
+	dialer := &net.Dialer{Timeout: time.Duration(timeout) * time.Second}
+	var conn net.Conn
+	if mode == protocol.MailTLSImplicit {
+		conn, err = tls.DialWithDialer(dialer, "tcp", item.Target, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", item.Target)
+	}
+	if err != nil {
+		result.ResponseTime = time.Since(startTime).Milliseconds()
+		result.Status = "down"
+		result.Error = fmt.Sprintf("connection failed: %v", err)
+		return result
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(startTime.Add(time.Duration(timeout) * time.Second))
+
+	probe := mailProbe{host: host, starttls: mode == protocol.MailTLSStartTLS, tlsConfig: tlsConfig, username: cfg.Username, password: cfg.Password}
+	var state *tls.ConnectionState
+	switch item.Type {
+	case "smtp":
+		state, err = probe.smtp(conn)
+	case "imap":
+		state, err = probe.imap(conn)
+	default:
+		state, err = probe.pop3(conn)
+	}
+	result.ResponseTime = time.Since(startTime).Milliseconds()
+	applyCert(&result, state, host)
+	if err != nil {
+		result.Status = "down"
+		result.Error = fmt.Sprintf("%s check failed: %v", proto, err)
+		return result
+	}
+
+	parts := []string{proto}
+	if state != nil {
+		parts = append(parts, tls.VersionName(state.Version))
+	}
+	if cfg.Username != "" {
+		parts = append(parts, "authenticated")
+	}
+	result.Status = "up"
+	result.Message = fmt.Sprintf("%s - %dms", strings.Join(parts, " "), result.ResponseTime)
+	return result
+}
+
+// mailProbe 单次邮件服务检查
+type mailProbe struct {
+	host      string
+	starttls  bool
+	tlsConfig *tls.Config
+	username  string
+	password  string
+}
+
+// errPlaintextAuth 未加密时拒绝发送账号密码
+var errPlaintextAuth = errors.New("refusing to authenticate over an unencrypted connection")
+
+// tlsState 返回连接的 TLS 状态，未加密时返回 nil
+func tlsState(conn net.Conn) *tls.ConnectionState {
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		state := tlsConn.ConnectionState()
+		return &state
+	}
+	return nil
+}
+
+// upgradeTLS 在已协商 STARTTLS 的连接上完成 TLS 握手
+func (p mailProbe) upgradeTLS(conn net.Conn) (*tls.Conn, error) {
+	tlsConn := tls.Client(conn, p.tlsConfig)
+	if err := tlsConn.Handshake(); err != nil {
+		return nil, fmt.Errorf("TLS handshake failed: %w", err)
+	}
+	return tlsConn, nil
+}
+
+func (p mailProbe) smtp(conn net.Conn) (*tls.ConnectionState, error) {
+	client, err := smtp.NewClient(conn, p.host)
+	if err != nil {
+		return nil, fmt.Errorf("greeting failed: %w", err)
+	}
+	defer client.Close()
+
+	if p.starttls {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return nil, errors.New("server does not support STARTTLS")
+		}
+		if err := client.StartTLS(p.tlsConfig); err != nil {
+			return nil, fmt.Errorf("STARTTLS failed: %w", err)
+		}
+	}
+	var state *tls.ConnectionState
+	if s, ok := client.TLSConnectionState(); ok {
+		state = &s
+	}
+
+	if p.username != "" {
+		if state == nil {
+			return nil, errPlaintextAuth
+		}
+		ok, mechanisms := client.Extension("AUTH")
+		if !ok {
+			return state, errors.New("server does not support AUTH")
+		}
+		var auth smtp.Auth
+		switch {
+		case hasMechanism(mechanisms, "PLAIN"):
+			auth = smtp.PlainAuth("", p.username, p.password, p.host)
+		case hasMechanism(mechanisms, "LOGIN"):
+			auth = &smtpLoginAuth{username: p.username, password: p.password}
+		default:
+			return state, fmt.Errorf("no supported AUTH mechanism: %s", mechanisms)
+		}
+		if err := client.Auth(auth); err != nil {
+			return state, fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+	_ = client.Quit()
+	return state, nil
+}
+
+func hasMechanism(mechanisms, name string) bool {
+	for _, m := range strings.Fields(mechanisms) {
+		if strings.EqualFold(m, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// smtpLoginAuth AUTH LOGIN 认证，部分邮件服务只支持这种方式
+type smtpLoginAuth struct {
+	username string
+	password string
+}
+
+func (a *smtpLoginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	return "LOGIN", nil, nil
+}
+
+func (a *smtpLoginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+	switch strings.ToLower(strings.TrimSpace(string(fromServer))) {
+	case "username:":
+		return []byte(a.username), nil
+	case "password:":
+		return []byte(a.password), nil
+	}
+	return nil, fmt.Errorf("unexpected challenge: %s", fromServer)
+}
+
+func (p mailProbe) pop3(conn net.Conn) (*tls.ConnectionState, error) {
+	tp := textproto.NewConn(conn)
+	if _, err := pop3Response(tp); err != nil {
+		return nil, fmt.Errorf("greeting failed: %w", err)
+	}
+	if p.starttls {
+		if _, err := pop3Command(tp, "STLS"); err != nil {
+			return nil, fmt.Errorf("STLS failed: %w", err)
+		}
+		tlsConn, err := p.upgradeTLS(conn)
+		if err != nil {
+			return nil, err
+		}
+		conn, tp = tlsConn, textproto.NewConn(tlsConn)
+	}
+	state := tlsState(conn)
+
+	if p.username != "" {
+		if state == nil {
+			return nil, errPlaintextAuth
+		}
+		if _, err := pop3Command(tp, "USER %s", p.username); err != nil {
+			return state, fmt.Errorf("authentication failed: %w", err)
+		}
+		if _, err := pop3Command(tp, "PASS %s", p.password); err != nil {
+			return state, fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+	_, _ = pop3Command(tp, "QUIT")
+	return state, nil
+}
+
+func pop3Command(tp *textproto.Conn, format string, args ...interface{}) (string, error) {
+	if err := tp.PrintfLine(format, args...); err != nil {
+		return "", err
+	}
+	return pop3Response(tp)
+}
+
+// pop3Response 读取一行应答，+OK 表示成功
+func pop3Response(tp *textproto.Conn) (string, error) {
+	line, err := tp.ReadLine()
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(line, "+OK") {
+		return line, errors.New(truncateResponse([]byte(line)))
+	}
+	return line, nil
+}
+
+func (p mailProbe) imap(conn net.Conn) (*tls.ConnectionState, error) {
+	tp := textproto.NewConn(conn)
+	greeting, err := tp.ReadLine()
+	if err != nil {
+		return nil, fmt.Errorf("greeting failed: %w", err)
+	}
+	if !strings.HasPrefix(greeting, "* OK") && !strings.HasPrefix(greeting, "* PREAUTH") {
+		return nil, fmt.Errorf("greeting failed: %s", truncateResponse([]byte(greeting)))
+	}
+
+	session := &imapSession{tp: tp}
+	if p.starttls {
+		if err := session.command("STARTTLS"); err != nil {
+			return nil, fmt.Errorf("STARTTLS failed: %w", err)
+		}
+		tlsConn, err := p.upgradeTLS(conn)
+		if err != nil {
+			return nil, err
+		}
+		conn, session.tp = tlsConn, textproto.NewConn(tlsConn)
+	}
+	state := tlsState(conn)
+
+	if p.username != "" {
+		if state == nil {
+			return nil, errPlaintextAuth
+		}
+		if err := session.command("LOGIN " + imapQuote(p.username) + " " + imapQuote(p.password)); err != nil {
+			return state, fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+	_ = session.command("LOGOUT")
+	return state, nil
+}
+
+// imapSession 按顺序生成命令标签的 IMAP 会话
+type imapSession struct {
+	tp  *textproto.Conn
+	seq int
+}
+
+// command 发送命令并读取到带标签的结果行，结果不是 OK 时返回错误
+func (s *imapSession) command(cmd string) error {
+	s.seq++
+	tag := fmt.Sprintf("a%d", s.seq)
+	if err := s.tp.PrintfLine("%s %s", tag, cmd); err != nil {
+		return err
+	}
+	for {
+		line, err := s.tp.ReadLine()
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(line, tag+" ") {
+			continue
+		}
+		status := strings.TrimPrefix(line, tag+" ")
+		if !strings.HasPrefix(strings.ToUpper(status), "OK") {
+			return errors.New(truncateResponse([]byte(status)))
+		}
+		return nil
+	}
+}
+
+// imapQuote 将字符串编码为 IMAP 带引号字符串
+func imapQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
@@ -0,0 +1,177 @@
+package collector
+
+import (
+	"bufio"
+	"crypto/tls"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+// testMailCert 复用 httptest 的自签名证书
+func testMailCert(t *testing.T) tls.Certificate {
+	srv := httptest.NewTLSServer(nil)
+	t.Cleanup(srv.Close)
+	return srv.TLS.Certificates[0]
+}
+
+// serveMail 启动只处理一个连接的假邮件服务，handler 按行处理命令，返回 true 表示升级为 TLS
+func serveMail(t *testing.T, cert tls.Certificate, greeting string, handler func(line string, tlsOn bool) (reply string, upgrade bool)) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { conn.Close() }()
+		tlsOn := false
+		reader := bufio.NewReader(conn)
+		_, _ = conn.Write([]byte(greeting + "\r\n"))
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			reply, upgrade := handler(strings.TrimRight(line, "\r\n"), tlsOn)
+			_, _ = conn.Write([]byte(reply + "\r\n"))
+			if upgrade {
+				tlsConn := tls.Server(conn, &tls.Config{Certificates: []tls.Certificate{cert}})
+				if err := tlsConn.Handshake(); err != nil {
+					return
+				}
+				conn, reader, tlsOn = tlsConn, bufio.NewReader(tlsConn), true
+			}
+		}
+	}()
+	return ln.Addr().String()
+}
+
+// pop3Handler 用户名 user、密码 secret 的 POP3 服务
+func pop3Handler(line string, tlsOn bool) (string, bool) {
+	switch {
+	case line == "STLS" && !tlsOn:
+		return "+OK begin TLS", true
+	case line == "USER user":
+		return "+OK", false
+	case line == "PASS secret":
+		return "+OK logged in", false
+	case line == "QUIT":
+		return "+OK bye", false
+	}
+	return "-ERR invalid", false
+}
+
+// imapHandler 用户名 user、密码 secret 的 IMAP 服务
+func imapHandler(line string, tlsOn bool) (string, bool) {
+	tag, cmd, _ := strings.Cut(line, " ")
+	switch {
+	case cmd == "STARTTLS" && !tlsOn:
+		return tag + " OK begin TLS", true
+	case cmd == `LOGIN "user" "secret"`:
+		return "* CAPABILITY IMAP4rev1\r\n" + tag + " OK logged in", false
+	case cmd == "LOGOUT":
+		return "* BYE\r\n" + tag + " OK bye", false
+	}
+	return tag + " NO invalid", false
+}
+
+// smtpHandler 只支持 EHLO 和 QUIT 的 SMTP 服务
+func smtpHandler(line string, tlsOn bool) (string, bool) {
+	switch {
+	case strings.HasPrefix(line, "EHLO"):
+		return "250-mail.example.com\r\n250 AUTH PLAIN", false
+	case line == "QUIT":
+		return "221 bye", false
+	}
+	return "502 unsupported", false
+}
+
+func TestCheckMail(t *testing.T) {
+	cert := testMailCert(t)
+	tests := []struct {
+		name        string
+		monitorType string
+		greeting    string
+		handler     func(string, bool) (string, bool)
+		cfg         protocol.MailMonitorConfig
+		wantStatus  string
+		wantMsg     string
+		wantCert    bool
+	}{
+		{name: "POP3 STLS 认证成功", monitorType: "pop3", greeting: "+OK ready", handler: pop3Handler,
+			cfg: protocol.MailMonitorConfig{Username: "user", Password: "secret"}, wantStatus: "up", wantMsg: "authenticated", wantCert: true},
+		{name: "POP3 密码错误", monitorType: "pop3", greeting: "+OK ready", handler: pop3Handler,
+			cfg: protocol.MailMonitorConfig{Username: "user", Password: "wrong"}, wantStatus: "down", wantMsg: "authentication failed", wantCert: true},
+		{name: "IMAP STARTTLS 认证成功", monitorType: "imap", greeting: "* OK IMAP4rev1 ready", handler: imapHandler,
+			cfg: protocol.MailMonitorConfig{Username: "user", Password: "secret"}, wantStatus: "up", wantMsg: "IMAP TLS", wantCert: true},
+		{name: "IMAP 欢迎语错误", monitorType: "imap", greeting: "* BYE busy", handler: imapHandler,
+			wantStatus: "down", wantMsg: "greeting failed"},
+		{name: "SMTP 不加密仅检查欢迎语", monitorType: "smtp", greeting: "220 mail.example.com ESMTP", handler: smtpHandler,
+			cfg: protocol.MailMonitorConfig{TLSMode: protocol.MailTLSNone}, wantStatus: "up", wantMsg: "SMTP"},
+		{name: "SMTP 不支持 STARTTLS", monitorType: "smtp", greeting: "220 mail.example.com ESMTP", handler: smtpHandler,
+			wantStatus: "down", wantMsg: "does not support STARTTLS"},
+		{name: "SMTP 不在明文连接上认证", monitorType: "smtp", greeting: "220 mail.example.com ESMTP", handler: smtpHandler,
+			cfg: protocol.MailMonitorConfig{TLSMode: protocol.MailTLSNone, Username: "user", Password: "secret"}, wantStatus: "down", wantMsg: "unencrypted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := serveMail(t, cert, tt.greeting, tt.handler)
+			cfg := tt.cfg
+			cfg.Timeout = 5
+			result := NewMonitorCollector().checkMail(protocol.MonitorItem{ID: "m1", Type: tt.monitorType, Target: addr, MailConfig: &cfg})
+
+			if result.Status != tt.wantStatus {
+				t.Fatalf("状态为 %s，期望 %s，错误: %s", result.Status, tt.wantStatus, result.Error)
+			}
+			text := result.Message + result.Error
+			if !strings.Contains(text, tt.wantMsg) {
+				t.Fatalf("结果 %q 中应包含 %q", text, tt.wantMsg)
+			}
+			if tt.wantCert != (result.CertExpiryTime > 0) {
+				t.Fatalf("证书到期时间为 %d，期望上报证书: %v", result.CertExpiryTime, tt.wantCert)
+			}
+		})
+	}
+}
+
+func TestSMTPLoginAuth(t *testing.T) {
+	auth := &smtpLoginAuth{username: "user", password: "secret"}
+	tests := []struct {
+		challenge string
+		want      string
+		wantErr   bool
+	}{
+		{challenge: "Username:", want: "user"},
+		{challenge: "Password:", want: "secret"},
+		{challenge: "Token:", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := auth.Next([]byte(tt.challenge), true)
+		if (err != nil) != tt.wantErr || string(got) != tt.want {
+			t.Errorf("Next(%q) = %q, %v，期望 %q", tt.challenge, got, err, tt.want)
+		}
+	}
+}
+
+func TestIMAPQuote(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "user", want: `"user"`},
+		{in: `pa"ss`, want: `"pa\"ss"`},
+		{in: `a\b`, want: `"a\\b"`},
+	}
+	for _, tt := range tests {
+		if got := imapQuote(tt.in); got != tt.want {
+			t.Errorf("imapQuote(%q) = %s，期望 %s", tt.in, got, tt.want)
+		}
+	}
+}
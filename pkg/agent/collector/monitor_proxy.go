@@ -0,0 +1,133 @@
+package collector
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/proxy"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+// withProxy 返回通过代理发送请求的 HTTP 客户端副本，未配置代理时原样返回
+func withProxy(client http.Client, cfg *protocol.ProxyConfig) (http.Client, error) {
+	if cfg == nil || cfg.URL == "" {
+		return client, nil
+	}
+	proxyURL, err := cfg.ProxyURL()
+	if err != nil {
+		return client, err
+	}
+	base, ok := client.Transport.(*http.Transport)
+	if !ok {
+		base = http.DefaultTransport.(*http.Transport)
+	}
+	transport := base.Clone()
+	transport.Proxy = http.ProxyURL(proxyURL)
+	client.Transport = transport
+	return client, nil
+}
+
+// dialTCP 建立到目标的 TCP 连接，配置了代理时通过 HTTP CONNECT 或 SOCKS5 建立隧道
+func dialTCP(cfg *protocol.ProxyConfig, target string, timeout time.Duration) (net.Conn, error) {
+	if cfg == nil || cfg.URL == "" {
+		return net.DialTimeout("tcp", target, timeout)
+	}
+	proxyURL, err := cfg.ProxyURL()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	dialer := &net.Dialer{}
+	switch proxyURL.Scheme {
+	case "socks5", "socks5h":
+		socks, err := proxy.FromURL(proxyURL, dialer)
+		if err != nil {
+			return nil, err
+		}
+		return socks.(proxy.ContextDialer).DialContext(ctx, "tcp", target)
+	default:
+		return dialHTTPConnect(ctx, dialer, proxyURL, target)
+	}
+}
+
+// dialHTTPConnect 通过 HTTP 代理的 CONNECT 方法建立到目标的隧道
+func dialHTTPConnect(ctx context.Context, dialer *net.Dialer, proxyURL *url.URL, target string) (net.Conn, error) {
+	proxyAddr := proxyURL.Host
+	if proxyURL.Port() == "" {
+		port := "80"
+		if proxyURL.Scheme == "https" {
+			port = "443"
+		}
+		proxyAddr = net.JoinHostPort(proxyURL.Hostname(), port)
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", proxyAddr)
+	if err != nil {
+		return nil, fmt.Errorf("connect to proxy failed: %w", err)
+	}
+	if proxyURL.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("proxy tls handshake failed: %w", err)
+		}
+		conn = tlsConn
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: target},
+		Host:   target,
+		Header: make(http.Header),
+	}
+	if user := proxyURL.User; user != nil {
+		password, _ := user.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("send proxy connect failed: %w", err)
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("read proxy response failed: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy connect refused: %s", resp.Status)
+	}
+	// 隧道建立后由调用方重新设置读写超时
+	_ = conn.SetDeadline(time.Time{})
+	if reader.Buffered() > 0 {
+		// 目标服务可能紧随代理应答发送数据（如 SMTP 欢迎语），已读入缓冲区的部分不能丢弃
+		return &bufferedConn{Conn: conn, reader: reader}, nil
+	}
+	return conn, nil
+}
+
+// bufferedConn 先读取缓冲区中剩余的数据再从连接读取
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
@@ -0,0 +1,127 @@
+package collector
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+func TestCheckHTTPThroughProxy(t *testing.T) {
+	// 作为正向代理接收绝对地址的请求，直接返回响应
+	var proxied, auth string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		auth = r.Header.Get("Proxy-Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	item := protocol.MonitorItem{
+		ID:     "m1",
+		Type:   "http",
+		Target: "http://internal.example/health",
+		Proxy:  &protocol.ProxyConfig{URL: proxy.URL, Username: "user", Password: "secret"},
+	}
+	result := NewMonitorCollector().checkHTTP(item)
+	if result.Status != "up" {
+		t.Fatalf("状态为 %s（%s），期望 up", result.Status, result.Error)
+	}
+	if proxied != "http://internal.example/health" || !strings.HasPrefix(auth, "Basic ") {
+		t.Fatalf("请求未通过代理发送: url=%s auth=%s", proxied, auth)
+	}
+
+	item.Proxy = &protocol.ProxyConfig{URL: "ftp://proxy.local"}
+	if result := NewMonitorCollector().checkHTTP(item); result.Status != "down" || !strings.Contains(result.Error, "proxy config error") {
+		t.Fatalf("无效的代理配置应检测失败: %+v", result)
+	}
+}
+
+// serveConnectProxy 启动一个只支持 CONNECT 的 HTTP 代理，要求 Basic 认证
+func serveConnectProxy(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				req, err := http.ReadRequest(bufio.NewReader(conn))
+				if err != nil || req.Method != http.MethodConnect {
+					return
+				}
+				if user, pass, ok := (&http.Request{Header: http.Header{"Authorization": req.Header["Proxy-Authorization"]}}).BasicAuth(); !ok || user != "user" || pass != "secret" {
+					_, _ = io.WriteString(conn, "HTTP/1.1 407 Proxy Authentication Required\r\n\r\n")
+					return
+				}
+				upstream, err := net.Dial("tcp", req.Host)
+				if err != nil {
+					_, _ = io.WriteString(conn, "HTTP/1.1 502 Bad Gateway\r\n\r\n")
+					return
+				}
+				defer upstream.Close()
+				_, _ = io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+				go func() { _, _ = io.Copy(upstream, conn) }()
+				_, _ = io.Copy(conn, upstream)
+			}(conn)
+		}
+	}()
+	return "http://" + ln.Addr().String()
+}
+
+func TestCheckTCPThroughProxy(t *testing.T) {
+	// 目标服务连接后立即发送欢迎语，与代理应答可能在同一次读取中到达
+	target, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer target.Close()
+	go func() {
+		for {
+			conn, err := target.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = io.WriteString(conn, "220 ready\r\n")
+			conn.Close()
+		}
+	}()
+	proxyURL := serveConnectProxy(t)
+
+	tests := []struct {
+		name       string
+		proxy      protocol.ProxyConfig
+		wantStatus string
+		wantError  string
+	}{
+		{name: "认证通过", proxy: protocol.ProxyConfig{URL: proxyURL, Username: "user", Password: "secret"}, wantStatus: "up"},
+		{name: "认证失败", proxy: protocol.ProxyConfig{URL: proxyURL, Username: "user", Password: "wrong"}, wantStatus: "down", wantError: "407"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := protocol.MonitorItem{
+				ID:        "m1",
+				Type:      "tcp",
+				Target:    target.Addr().String(),
+				TCPConfig: &protocol.TCPMonitorConfig{Timeout: 5, Expect: "220"},
+				Proxy:     &tt.proxy,
+			}
+			result := NewMonitorCollector().checkTCP(item)
+			if result.Status != tt.wantStatus || !strings.Contains(result.Error, tt.wantError) {
+				t.Fatalf("状态为 %s（%s），期望 %s（%s）", result.Status, result.Error, tt.wantStatus, tt.wantError)
+			}
+		})
+	}
+}
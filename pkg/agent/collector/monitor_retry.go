@@ -0,0 +1,67 @@
+package collector
+
+import (
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+// monitorRetryDelay 检测失败后重新检测前的等待时间
+const monitorRetryDelay = 2 * time.Second
+
+// withItemTimeout 使用监控项的超时时间代替各类型配置中的超时时间
+// 配置按值复制后修改，不影响服务端下发的原始配置
+func withItemTimeout(item protocol.MonitorItem) protocol.MonitorItem {
+	if item.Timeout <= 0 {
+		return item
+	}
+	if item.HTTPConfig != nil {
+		cfg := *item.HTTPConfig
+		cfg.Timeout = item.Timeout
+		item.HTTPConfig = &cfg
+	}
+	if item.TCPConfig != nil {
+		cfg := *item.TCPConfig
+		cfg.Timeout = item.Timeout
+		item.TCPConfig = &cfg
+	}
+	if item.UDPConfig != nil {
+		cfg := *item.UDPConfig
+		cfg.Timeout = item.Timeout
+		item.UDPConfig = &cfg
+	}
+	if item.ICMPConfig != nil {
+		cfg := *item.ICMPConfig
+		cfg.Timeout = item.Timeout
+		item.ICMPConfig = &cfg
+	}
+	if item.TransactionConfig != nil {
+		cfg := *item.TransactionConfig
+		cfg.Timeout = item.Timeout
+		item.TransactionConfig = &cfg
+	}
+	if item.MailConfig != nil {
+		cfg := *item.MailConfig
+		cfg.Timeout = item.Timeout
+		item.MailConfig = &cfg
+	}
+	return item
+}
+
+// checkWithRetries 检测失败时按监控项的重试次数重新检测，任意一次成功即视为正常，全部失败时返回最后一次的结果
+// 设置了检测频率时，预计无法在下一次检测前完成的重试不再执行，避免与下一次检测重叠
+func checkWithRetries(item protocol.MonitorItem, delay time.Duration, check func(protocol.MonitorItem) protocol.MonitorData) protocol.MonitorData {
+	start := time.Now()
+	result := check(item)
+	last := time.Since(start)
+	for attempt := 0; attempt < item.Retries && result.Status == "down"; attempt++ {
+		if item.Interval > 0 && time.Since(start)+delay+last >= time.Duration(item.Interval)*time.Second {
+			break
+		}
+		time.Sleep(delay)
+		attemptStart := time.Now()
+		result = check(item)
+		last = time.Since(attemptStart)
+	}
+	return result
+}
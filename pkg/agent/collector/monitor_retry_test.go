@@ -0,0 +1,92 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+func TestCheckWithRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		item      protocol.MonitorItem
+		statuses  []string // 每次检测的结果
+		check     time.Duration
+		want      string
+		wantCalls int
+	}{
+		{
+			name:      "首次成功不重试",
+			item:      protocol.MonitorItem{Retries: 3},
+			statuses:  []string{"up"},
+			want:      "up",
+			wantCalls: 1,
+		},
+		{
+			name:      "未设置重试次数",
+			item:      protocol.MonitorItem{},
+			statuses:  []string{"down", "up"},
+			want:      "down",
+			wantCalls: 1,
+		},
+		{
+			name:      "重试成功视为正常",
+			item:      protocol.MonitorItem{Retries: 3},
+			statuses:  []string{"down", "down", "up"},
+			want:      "up",
+			wantCalls: 3,
+		},
+		{
+			name:      "全部失败",
+			item:      protocol.MonitorItem{Retries: 2},
+			statuses:  []string{"down", "down", "down", "up"},
+			want:      "down",
+			wantCalls: 3,
+		},
+		{
+			name:      "重试不超过下一次检测",
+			item:      protocol.MonitorItem{Retries: 3, Interval: 1},
+			statuses:  []string{"down", "down", "down", "up"},
+			check:     600 * time.Millisecond,
+			want:      "down",
+			wantCalls: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			result := checkWithRetries(tt.item, time.Millisecond, func(item protocol.MonitorItem) protocol.MonitorData {
+				status := tt.statuses[calls]
+				calls++
+				time.Sleep(tt.check)
+				return protocol.MonitorData{Status: status}
+			})
+			if result.Status != tt.want {
+				t.Errorf("状态 = %s, 期望 %s", result.Status, tt.want)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("检测次数 = %d, 期望 %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestWithItemTimeout(t *testing.T) {
+	httpCfg := &protocol.HTTPMonitorConfig{Method: "GET", Timeout: 60}
+	item := withItemTimeout(protocol.MonitorItem{Type: "http", Timeout: 10, HTTPConfig: httpCfg})
+	if item.HTTPConfig.Timeout != 10 || item.HTTPConfig.Method != "GET" {
+		t.Errorf("HTTP 配置 = %+v, 期望超时时间为 10", item.HTTPConfig)
+	}
+	if httpCfg.Timeout != 60 {
+		t.Errorf("原始配置被修改: %d", httpCfg.Timeout)
+	}
+	if item.TCPConfig != nil {
+		t.Error("未设置的配置不应被创建")
+	}
+
+	item = withItemTimeout(protocol.MonitorItem{Type: "tcp", TCPConfig: &protocol.TCPMonitorConfig{Timeout: 5}})
+	if item.TCPConfig.Timeout != 5 {
+		t.Errorf("未设置超时时间时应保留原配置: %d", item.TCPConfig.Timeout)
+	}
+}
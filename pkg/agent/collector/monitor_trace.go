@@ -0,0 +1,173 @@
+package collector
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+const (
+	// traceMaxHops 路径追踪的最大跳数
+	traceMaxHops = 20
+	// traceTimeout 单次路径追踪的总时长上限
+	traceTimeout = 30 * time.Second
+	// traceMaxConcurrent 同时进行的路径追踪数量上限，避免大量监控项同时离线时占满资源
+	traceMaxConcurrent = 4
+)
+
+// traceTransitions 记录本轮检测的状态，返回由其他状态变为离线的监控项
+// 探针启动后首次检测即为离线的监控项也视为变为离线；正在追踪的监控项不重复追踪
+func (c *MonitorCollector) traceTransitions(items []protocol.MonitorItem, results []protocol.MonitorData) []protocol.MonitorTraceData {
+	c.traceMu.Lock()
+	defer c.traceMu.Unlock()
+
+	var traces []protocol.MonitorTraceData
+	for i, result := range results {
+		previous := c.lastStatus[result.ID]
+		c.lastStatus[result.ID] = result.Status
+		if result.Status != "down" || previous == "down" || c.tracing[result.ID] {
+			continue
+		}
+		host := traceHost(items[i])
+		if host == "" {
+			continue
+		}
+		c.tracing[result.ID] = true
+		traces = append(traces, protocol.MonitorTraceData{MonitorID: result.ID, Host: host, CheckedAt: result.CheckedAt, CheckError: result.Error})
+	}
+	return traces
+}
+
+// runTrace 执行路径追踪，并发数量受 traceMaxConcurrent 限制
+func (c *MonitorCollector) runTrace(trace protocol.MonitorTraceData) protocol.MonitorTraceData {
+	c.traceSem <- struct{}{}
+	defer func() {
+		<-c.traceSem
+		c.traceMu.Lock()
+		delete(c.tracing, trace.MonitorID)
+		c.traceMu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), traceTimeout)
+	defer cancel()
+	trace.Tool, trace.Hops, trace.Error = tracePath(ctx, trace.Host)
+	return trace
+}
+
+// traceHost 从监控项中取出路径追踪的目标主机
+func traceHost(item protocol.MonitorItem) string {
+	switch strings.ToLower(item.Type) {
+	case "http", "https":
+		if u, err := url.Parse(item.Target); err == nil {
+			return u.Hostname()
+		}
+		return ""
+	case "transaction":
+		// 事务监控追踪第一个步骤的地址，地址中引用了变量时无法解析则跳过
+		if item.TransactionConfig == nil || len(item.TransactionConfig.Steps) == 0 {
+			return ""
+		}
+		if u, err := url.Parse(item.TransactionConfig.Steps[0].URL); err == nil {
+			return u.Hostname()
+		}
+		return ""
+	case "icmp", "ping":
+		return item.Target
+	default:
+		if host, _, err := net.SplitHostPort(item.Target); err == nil {
+			return host
+		}
+		return item.Target
+	}
+}
+
+// tracePath 优先使用 mtr（多轮探测可得到丢包率），没有安装时退回 traceroute
+func tracePath(ctx context.Context, host string) (string, []protocol.TraceHop, string) {
+	maxHops := strconv.Itoa(traceMaxHops)
+	if _, err := exec.LookPath("mtr"); err == nil {
+		output, err := exec.CommandContext(ctx, "mtr", "--report", "--json", "-n", "-c", "3", "-m", maxHops, host).Output()
+		if err != nil {
+			return "mtr", nil, fmt.Sprintf("mtr failed: %v", err)
+		}
+		hops, err := parseMTRReport(output)
+		if err != nil {
+			return "mtr", nil, fmt.Sprintf("parse mtr report failed: %v", err)
+		}
+		return "mtr", hops, ""
+	}
+	if _, err := exec.LookPath("traceroute"); err == nil {
+		output, err := exec.CommandContext(ctx, "traceroute", "-n", "-q", "1", "-w", "1", "-m", maxHops, host).Output()
+		// 到达最大跳数或超时也会返回非零退出码，已有的输出仍然有效
+		hops := parseTraceroute(output)
+		if len(hops) == 0 && err != nil {
+			return "traceroute", nil, fmt.Sprintf("traceroute failed: %v", err)
+		}
+		return "traceroute", hops, ""
+	}
+	return "", nil, "neither mtr nor traceroute is installed"
+}
+
+// parseMTRReport 解析 mtr --report --json 的输出
+func parseMTRReport(output []byte) ([]protocol.TraceHop, error) {
+	var report struct {
+		Report struct {
+			Hubs []struct {
+				Count int     `json:"count"`
+				Host  string  `json:"host"`
+				Loss  float64 `json:"Loss%"`
+				Avg   float64 `json:"Avg"`
+			} `json:"hubs"`
+		} `json:"report"`
+	}
+	if err := json.Unmarshal(output, &report); err != nil {
+		return nil, err
+	}
+	hops := make([]protocol.TraceHop, 0, len(report.Report.Hubs))
+	for _, hub := range report.Report.Hubs {
+		hop := protocol.TraceHop{Hop: hub.Count, Loss: hub.Loss, AvgRTT: hub.Avg}
+		// mtr 以 ??? 表示无响应的跳
+		if hub.Host != "???" {
+			hop.Host = hub.Host
+		}
+		hops = append(hops, hop)
+	}
+	return hops, nil
+}
+
+// parseTraceroute 解析 traceroute -n -q 1 的输出，每行形如 " 3  10.0.0.1  1.234 ms" 或 " 4  *"
+func parseTraceroute(output []byte) []protocol.TraceHop {
+	var hops []protocol.TraceHop
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		n, err := strconv.Atoi(fields[0])
+		if err != nil {
+			// 首行 "traceroute to ..." 等非跳数行
+			continue
+		}
+		hop := protocol.TraceHop{Hop: n, Loss: 100}
+		if fields[1] != "*" {
+			hop.Host = fields[1]
+			if len(fields) >= 4 && fields[3] == "ms" {
+				if rtt, err := strconv.ParseFloat(fields[2], 64); err == nil {
+					hop.Loss = 0
+					hop.AvgRTT = rtt
+				}
+			}
+		}
+		hops = append(hops, hop)
+	}
+	return hops
+}
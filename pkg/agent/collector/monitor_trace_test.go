@@ -0,0 +1,104 @@
+package collector
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+func TestTraceTransitions(t *testing.T) {
+	c := NewMonitorCollector()
+	items := []protocol.MonitorItem{
+		{ID: "web", Type: "http", Target: "https://example.com:8443/health"},
+		{ID: "db", Type: "tcp", Target: "10.0.0.5:5432"},
+	}
+	check := func(statuses ...string) []string {
+		results := make([]protocol.MonitorData, len(items))
+		for i, item := range items {
+			results[i] = protocol.MonitorData{ID: item.ID, Status: statuses[i], CheckedAt: int64(i + 1)}
+		}
+		var hosts []string
+		for _, trace := range c.traceTransitions(items, results) {
+			hosts = append(hosts, trace.Host)
+		}
+		return hosts
+	}
+
+	if got := check("up", "down"); !reflect.DeepEqual(got, []string{"10.0.0.5"}) {
+		t.Fatalf("首次检测离线应追踪，得到 %v", got)
+	}
+	// 上一次追踪尚未结束，持续离线也不会重复追踪
+	if got := check("down", "down"); !reflect.DeepEqual(got, []string{"example.com"}) {
+		t.Fatalf("只有变为离线的监控项需要追踪，得到 %v", got)
+	}
+	c.traceMu.Lock()
+	c.tracing = make(map[string]bool)
+	c.traceMu.Unlock()
+	if got := check("down", "up"); got != nil {
+		t.Fatalf("持续离线或恢复在线不需要追踪，得到 %v", got)
+	}
+	if got := check("down", "down"); !reflect.DeepEqual(got, []string{"10.0.0.5"}) {
+		t.Fatalf("恢复后再次离线应追踪，得到 %v", got)
+	}
+}
+
+func TestTraceHost(t *testing.T) {
+	tests := []struct {
+		item protocol.MonitorItem
+		want string
+	}{
+		{item: protocol.MonitorItem{Type: "http", Target: "https://example.com/path"}, want: "example.com"},
+		{item: protocol.MonitorItem{Type: "tcp", Target: "[2001:db8::1]:443"}, want: "2001:db8::1"},
+		{item: protocol.MonitorItem{Type: "smtp", Target: "mail.example.com:25"}, want: "mail.example.com"},
+		{item: protocol.MonitorItem{Type: "icmp", Target: "1.1.1.1"}, want: "1.1.1.1"},
+		{item: protocol.MonitorItem{Type: "transaction", TransactionConfig: &protocol.TransactionMonitorConfig{
+			Steps: []protocol.TransactionStep{{URL: "https://api.example.com/login"}}}}, want: "api.example.com"},
+		{item: protocol.MonitorItem{Type: "transaction"}, want: ""},
+	}
+	for _, tt := range tests {
+		if got := traceHost(tt.item); got != tt.want {
+			t.Errorf("%s %s 的追踪目标为 %q，期望 %q", tt.item.Type, tt.item.Target, got, tt.want)
+		}
+	}
+}
+
+func TestParseMTRReport(t *testing.T) {
+	output := []byte(`{"report":{"mtr":{"dst":"1.1.1.1","tests":3},"hubs":[
+		{"count":1,"host":"192.168.1.1","Loss%":0.00,"Snt":3,"Last":0.5,"Avg":0.6,"Best":0.4,"Wrst":0.8,"StDev":0.1},
+		{"count":2,"host":"???","Loss%":100.00,"Snt":3,"Last":0.0,"Avg":0.0,"Best":0.0,"Wrst":0.0,"StDev":0.0},
+		{"count":3,"host":"1.1.1.1","Loss%":33.33,"Snt":3,"Last":12.1,"Avg":11.5,"Best":10.9,"Wrst":12.1,"StDev":0.6}]}}`)
+	hops, err := parseMTRReport(output)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	want := []protocol.TraceHop{
+		{Hop: 1, Host: "192.168.1.1", Loss: 0, AvgRTT: 0.6},
+		{Hop: 2, Loss: 100},
+		{Hop: 3, Host: "1.1.1.1", Loss: 33.33, AvgRTT: 11.5},
+	}
+	if !reflect.DeepEqual(hops, want) {
+		t.Fatalf("解析结果错误:\n得到 %+v\n期望 %+v", hops, want)
+	}
+	if _, err := parseMTRReport([]byte("mtr: unknown host")); err == nil {
+		t.Fatal("无效输出应返回错误")
+	}
+}
+
+func TestParseTraceroute(t *testing.T) {
+	output := []byte(`traceroute to 1.1.1.1 (1.1.1.1), 20 hops max, 60 byte packets
+ 1  192.168.1.1  0.512 ms
+ 2  *
+ 3  10.0.0.1  !H
+10  1.1.1.1  11.204 ms
+`)
+	want := []protocol.TraceHop{
+		{Hop: 1, Host: "192.168.1.1", AvgRTT: 0.512},
+		{Hop: 2, Loss: 100},
+		{Hop: 3, Host: "10.0.0.1", Loss: 100},
+		{Hop: 10, Host: "1.1.1.1", AvgRTT: 11.204},
+	}
+	if got := parseTraceroute(output); !reflect.DeepEqual(got, want) {
+		t.Fatalf("解析结果错误:\n得到 %+v\n期望 %+v", got, want)
+	}
+}
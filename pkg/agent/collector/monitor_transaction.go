@@ -0,0 +1,190 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"regexp"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/pkg/jsonpath"
+)
+
+// transactionVarPattern 步骤配置中引用变量的写法 {{name}}
+var transactionVarPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// checkTransaction 按顺序执行事务的各个步骤，任一步骤失败即判定为 down，响应时间为所有步骤的总耗时
+func (c *MonitorCollector) checkTransaction(item protocol.MonitorItem) protocol.MonitorData {
+	result := protocol.MonitorData{
+		ID:        item.ID,
+		Type:      item.Type,
+		Target:    item.Target,
+		CheckedAt: time.Now().UnixMilli(),
+	}
+
+	cfg := item.TransactionConfig
+	if cfg == nil || len(cfg.Steps) == 0 {
+		result.Status = "down"
+		result.Error = "transaction has no steps"
+		return result
+	}
+	timeout := 60 // 默认 60 秒
+	if cfg.Timeout > 0 {
+		timeout = cfg.Timeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	baseClient, err := withProxy(*c.httpClient, item.Proxy)
+	if err != nil {
+		result.Status = "down"
+		result.Error = fmt.Sprintf("proxy config error: %v", err)
+		return result
+	}
+
+	// 各步骤共享 Cookie，登录步骤返回的会话可在后续步骤中使用
+	jar, _ := cookiejar.New(nil)
+	vars := make(map[string]string)
+	startTime := time.Now()
+	for i, step := range cfg.Steps {
+		stepCfg := expandStep(step.HTTPMonitorConfig, vars)
+		client := baseClient
+		client.Jar = jar
+		client.CheckRedirect = redirectPolicy(&stepCfg)
+
+		stepResult, response, ok := runTransactionStep(ctx, &client, expandVars(step.URL, vars), &stepCfg, len(step.Extract) > 0)
+		result.StatusCode = stepResult.StatusCode
+		result.ContentMatch = stepResult.ContentMatch
+		result.ContentDetail = stepResult.ContentDetail
+		if result.CertExpiryTime == 0 {
+			result.CertExpiryTime = stepResult.CertExpiryTime
+			result.CertDaysLeft = stepResult.CertDaysLeft
+			result.CertInfo = stepResult.CertInfo
+		}
+		result.ResponseTime = time.Since(startTime).Milliseconds()
+
+		name := step.Name
+		if name == "" {
+			name = fmt.Sprintf("step %d", i+1)
+		}
+		if !ok {
+			result.Status = "down"
+			result.Error = fmt.Sprintf("step %d (%s) failed: %s", i+1, name, stepResult.Error)
+			result.Message = stepResult.Message
+			return result
+		}
+		for _, extract := range step.Extract {
+			value, err := extractVariable(response, extract)
+			if err != nil {
+				result.Status = "down"
+				result.Error = fmt.Sprintf("step %d (%s) extract %s failed: %v", i+1, name, extract.Name, err)
+				return result
+			}
+			vars[extract.Name] = value
+		}
+	}
+
+	result.Status = "up"
+	result.Message = fmt.Sprintf("%d steps passed - %dms", len(cfg.Steps), result.ResponseTime)
+	return result
+}
+
+// runTransactionStep 执行单个步骤，配置了步骤超时时在事务超时内再单独限制
+func runTransactionStep(ctx context.Context, client *http.Client, target string, cfg *protocol.HTTPMonitorConfig, keepBody bool) (protocol.MonitorData, *httpResponse, bool) {
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(cfg.Timeout)*time.Second)
+		defer cancel()
+	}
+	var result protocol.MonitorData
+	response, ok := doHTTP(ctx, client, target, cfg, keepBody, &result)
+	return result, response, ok
+}
+
+// expandStep 替换步骤请求头、请求体、认证信息和断言中引用的变量
+func expandStep(cfg protocol.HTTPMonitorConfig, vars map[string]string) protocol.HTTPMonitorConfig {
+	if len(vars) == 0 {
+		return cfg
+	}
+	cfg.Body = expandVars(cfg.Body, vars)
+	if len(cfg.Headers) > 0 {
+		headers := make(map[string]string, len(cfg.Headers))
+		for key, value := range cfg.Headers {
+			headers[key] = expandVars(value, vars)
+		}
+		cfg.Headers = headers
+	}
+	if cfg.Auth != nil {
+		auth := *cfg.Auth
+		auth.Username = expandVars(auth.Username, vars)
+		auth.Token = expandVars(auth.Token, vars)
+		cfg.Auth = &auth
+	}
+	cfg.ExpectedContent = expandVars(cfg.ExpectedContent, vars)
+	if len(cfg.Assertions) > 0 {
+		assertions := make([]protocol.HTTPAssertion, len(cfg.Assertions))
+		for i, a := range cfg.Assertions {
+			a.Value = expandVars(a.Value, vars)
+			assertions[i] = a
+		}
+		cfg.Assertions = assertions
+	}
+	return cfg
+}
+
+// expandVars 替换文本中的 {{name}}，未定义的变量保持原样
+func expandVars(text string, vars map[string]string) string {
+	if len(vars) == 0 {
+		return text
+	}
+	return transactionVarPattern.ReplaceAllStringFunc(text, func(match string) string {
+		name := transactionVarPattern.FindStringSubmatch(match)[1]
+		if value, ok := vars[name]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// extractVariable 从步骤响应中提取变量
+func extractVariable(response *httpResponse, extract protocol.TransactionExtract) (string, error) {
+	switch extract.Source {
+	case protocol.ExtractSourceHeader:
+		value := response.header.Get(extract.Expression)
+		if value == "" {
+			return "", fmt.Errorf("header %s not found", extract.Expression)
+		}
+		return value, nil
+	case protocol.ExtractSourceRegex:
+		pattern, err := regexp.Compile(extract.Expression)
+		if err != nil {
+			return "", fmt.Errorf("invalid regex: %v", err)
+		}
+		match := pattern.FindSubmatch(response.body)
+		if match == nil {
+			return "", fmt.Errorf("regex %s not matched", extract.Expression)
+		}
+		if len(match) > 1 {
+			return string(match[1]), nil
+		}
+		return string(match[0]), nil
+	case protocol.ExtractSourceJSONPath:
+		path, err := jsonpath.Compile(extract.Expression)
+		if err != nil {
+			return "", err
+		}
+		doc, err := decodeJSON(response.body)
+		if err != nil {
+			return "", fmt.Errorf("response is not valid JSON")
+		}
+		value, found := path.Lookup(doc)
+		if !found {
+			return "", fmt.Errorf("path %s not found", extract.Expression)
+		}
+		return jsonText(value), nil
+	default:
+		return "", fmt.Errorf("unsupported extract source: %s", extract.Source)
+	}
+}
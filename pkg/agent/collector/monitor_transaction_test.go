@@ -0,0 +1,117 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+func newTransactionServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			if r.Method != http.MethodPost {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "s1"})
+			w.Header().Set("X-Request-Id", "req-42")
+			_, _ = w.Write([]byte(`{"data":{"token":"t-123","user":{"id":7}}}`))
+		case "/users/7":
+			cookie, err := r.Cookie("session")
+			if err != nil || cookie.Value != "s1" || r.Header.Get("Authorization") != "Bearer t-123" || r.Header.Get("X-Trace") != "req-42" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"id":7,"name":"ops","csrf":"<input name=csrf value=abc>"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestCheckTransaction(t *testing.T) {
+	server := newTransactionServer()
+	defer server.Close()
+
+	login := protocol.TransactionStep{
+		Name: "login",
+		URL:  server.URL + "/login",
+		Extract: []protocol.TransactionExtract{
+			{Name: "token", Source: protocol.ExtractSourceJSONPath, Expression: "$.data.token"},
+			{Name: "uid", Source: protocol.ExtractSourceJSONPath, Expression: "$.data.user.id"},
+			{Name: "rid", Source: protocol.ExtractSourceHeader, Expression: "X-Request-Id"},
+		},
+		HTTPMonitorConfig: protocol.HTTPMonitorConfig{Method: "POST", Body: `{"user":"ops"}`},
+	}
+	fetch := protocol.TransactionStep{
+		Name: "profile",
+		URL:  server.URL + "/users/{{uid}}",
+		Extract: []protocol.TransactionExtract{
+			{Name: "csrf", Source: protocol.ExtractSourceRegex, Expression: `name=csrf value=(\w+)`},
+		},
+		HTTPMonitorConfig: protocol.HTTPMonitorConfig{
+			Headers:    map[string]string{"X-Trace": "{{rid}}"},
+			Auth:       &protocol.HTTPAuthConfig{Type: protocol.HTTPAuthBearer, Token: "{{token}}"},
+			Assertions: []protocol.HTTPAssertion{{Type: protocol.AssertionJSONPath, Path: "$.id", Value: "{{uid}}"}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		steps      []protocol.TransactionStep
+		wantStatus string
+		wantError  string
+	}{
+		{name: "登录后获取用户信息", steps: []protocol.TransactionStep{login, fetch}, wantStatus: "up"},
+		{name: "缺少登录步骤", steps: []protocol.TransactionStep{fetch}, wantStatus: "down", wantError: "step 1 (profile) failed: status code mismatch"},
+		{
+			name: "变量提取失败",
+			steps: []protocol.TransactionStep{{
+				URL:               server.URL + "/login",
+				Extract:           []protocol.TransactionExtract{{Name: "x", Source: protocol.ExtractSourceJSONPath, Expression: "$.missing"}},
+				HTTPMonitorConfig: protocol.HTTPMonitorConfig{Method: "POST"},
+			}},
+			wantStatus: "down",
+			wantError:  "step 1 (step 1) extract x failed: path $.missing not found",
+		},
+		{name: "没有步骤", wantStatus: "down", wantError: "no steps"},
+	}
+	c := NewMonitorCollector()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := c.checkTransaction(protocol.MonitorItem{
+				ID:                "m",
+				Type:              "transaction",
+				TransactionConfig: &protocol.TransactionMonitorConfig{Steps: tt.steps},
+			})
+			if result.Status != tt.wantStatus {
+				t.Fatalf("状态为 %s，期望 %s，错误: %s", result.Status, tt.wantStatus, result.Error)
+			}
+			if !strings.Contains(result.Error, tt.wantError) {
+				t.Fatalf("错误 %q 中应包含 %q", result.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestExpandVars(t *testing.T) {
+	vars := map[string]string{"token": "abc", "id": "7"}
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "/users/{{id}}", want: "/users/7"},
+		{text: "Bearer {{ token }}", want: "Bearer abc"},
+		{text: "{{missing}}-{{id}}", want: "{{missing}}-7"},
+		{text: "{{id", want: "{{id"},
+	}
+	for _, tt := range tests {
+		if got := expandVars(tt.text, vars); got != tt.want {
+			t.Errorf("expandVars(%q) = %q，期望 %q", tt.text, got, tt.want)
+		}
+	}
+}
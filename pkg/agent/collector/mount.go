@@ -0,0 +1,145 @@
+package collector
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/pkg/agent/config"
+	"github.com/shirou/gopsutil/v4/disk"
+)
+
+// MountCollector 网络挂载点健康检查采集器
+// NFS/SMB 等网络挂载在服务端无响应时，stat 等系统调用会一直阻塞而不是返回错误，
+// 因此每个挂载点在独立的 goroutine 中探测，超过超时时间即判定为挂起；
+// 挂起的探测返回前不会重复发起，避免阻塞的 goroutine 越积越多
+type MountCollector struct {
+	mountPoints []string
+	timeout     time.Duration
+	mu          sync.Mutex
+	probing     map[string]time.Time // 尚未返回的探测及其开始时间
+}
+
+// mountProbeResult 单次探测结果
+type mountProbeResult struct {
+	status    string
+	err       error
+	startedAt time.Time // 探测开始时间，上一次探测仍未返回时为其开始时间
+}
+
+// NewMountCollector 创建网络挂载点采集器
+func NewMountCollector(cfg *config.Config) *MountCollector {
+	return &MountCollector{
+		mountPoints: cfg.Collector.Mounts,
+		timeout:     cfg.GetMountTimeout(),
+		probing:     make(map[string]time.Time),
+	}
+}
+
+// Enabled 是否配置了需要检查的挂载点
+func (m *MountCollector) Enabled() bool {
+	return len(m.mountPoints) > 0
+}
+
+// Collect 并发探测所有配置的挂载点
+func (m *MountCollector) Collect() ([]protocol.MountData, error) {
+	// 挂载表来自 /proc/self/mountinfo 等，读取时不会访问挂载点本身
+	partitions, err := disk.Partitions(true)
+	if err != nil {
+		return nil, err
+	}
+	mounted := make(map[string]disk.PartitionStat, len(partitions))
+	for _, partition := range partitions {
+		mounted[filepath.Clean(partition.Mountpoint)] = partition
+	}
+
+	results := make([]protocol.MountData, len(m.mountPoints))
+	var wg sync.WaitGroup
+	for i, mountPoint := range m.mountPoints {
+		mountPoint = filepath.Clean(mountPoint)
+		result := protocol.MountData{MountPoint: mountPoint}
+		partition, ok := mounted[mountPoint]
+		if !ok {
+			result.Status = protocol.MountStatusUnmounted
+			results[i] = result
+			continue
+		}
+		result.Device = partition.Device
+		result.Fstype = partition.Fstype
+
+		wg.Add(1)
+		go func(i int, result protocol.MountData) {
+			defer wg.Done()
+			probe := m.probe(result.MountPoint)
+			result.Status = probe.status
+			result.Latency = time.Since(probe.startedAt).Milliseconds()
+			if probe.err != nil {
+				result.Error = probe.err.Error()
+			}
+			results[i] = result
+		}(i, result)
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+// probe 探测单个挂载点，超时返回 hung；上一次探测仍未返回时直接返回 hung
+func (m *MountCollector) probe(mountPoint string) mountProbeResult {
+	m.mu.Lock()
+	if startedAt, ok := m.probing[mountPoint]; ok {
+		m.mu.Unlock()
+		return mountProbeResult{status: protocol.MountStatusHung, startedAt: startedAt}
+	}
+	startedAt := time.Now()
+	m.probing[mountPoint] = startedAt
+	m.mu.Unlock()
+
+	done := make(chan mountProbeResult, 1)
+	go func() {
+		result := statMount(mountPoint)
+		m.mu.Lock()
+		delete(m.probing, mountPoint)
+		m.mu.Unlock()
+		done <- result
+	}()
+
+	timer := time.NewTimer(m.timeout)
+	defer timer.Stop()
+
+	select {
+	case result := <-done:
+		result.startedAt = startedAt
+		return result
+	case <-timer.C:
+		return mountProbeResult{status: protocol.MountStatusHung, startedAt: startedAt}
+	}
+}
+
+// statMount 访问挂载点的属性和目录内容，只读取目录项不读取文件
+func statMount(mountPoint string) mountProbeResult {
+	if _, err := os.Stat(mountPoint); err != nil {
+		return mountErrorResult(err)
+	}
+	f, err := os.Open(mountPoint)
+	if err != nil {
+		return mountErrorResult(err)
+	}
+	defer f.Close()
+	if _, err := f.Readdirnames(1); err != nil && !errors.Is(err, io.EOF) {
+		return mountErrorResult(err)
+	}
+	return mountProbeResult{status: protocol.MountStatusOK}
+}
+
+// mountErrorResult 根据错误类型区分句柄失效和其他错误
+func mountErrorResult(err error) mountProbeResult {
+	if isStaleHandle(err) {
+		return mountProbeResult{status: protocol.MountStatusStale, err: err}
+	}
+	return mountProbeResult{status: protocol.MountStatusError, err: err}
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package collector
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isStaleHandle 是否为 NFS 等网络文件系统的句柄失效错误
+func isStaleHandle(err error) bool {
+	return errors.Is(err, syscall.ESTALE)
+}
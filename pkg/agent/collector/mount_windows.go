@@ -0,0 +1,8 @@
+//go:build windows
+
+package collector
+
+// isStaleHandle Windows 上 SMB 共享断开时返回普通的网络错误，不区分句柄失效
+func isStaleHandle(err error) bool {
+	return false
+}
@@ -0,0 +1,137 @@
+package collector
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	probing "github.com/prometheus-community/pro-bing"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+const (
+	// pingCount 每个目标每轮发送的包数
+	pingCount = 5
+	// pingTimeout 每个目标每轮的超时时间
+	pingTimeout = 10 * time.Second
+	// tcpPingTimeout TCP 模式下单次连接的超时时间
+	tcpPingTimeout = 2 * time.Second
+)
+
+// PingCollector Ping 目标采集器，用于跟踪到网关、DNS 等目标的延迟和丢包
+type PingCollector struct{}
+
+// NewPingCollector 创建 Ping 采集器
+func NewPingCollector() *PingCollector {
+	return &PingCollector{}
+}
+
+// Collect 并发 Ping 所有目标
+func (c *PingCollector) Collect(targets []protocol.PingTarget) []protocol.PingData {
+	results := make([]protocol.PingData, len(targets))
+
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		go func(i int, target protocol.PingTarget) {
+			defer wg.Done()
+			results[i] = c.ping(target)
+		}(i, target)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// ping Ping 单个目标
+func (c *PingCollector) ping(target protocol.PingTarget) protocol.PingData {
+	result := protocol.PingData{
+		TargetID:   target.ID,
+		Target:     target.Target,
+		Sent:       pingCount,
+		PacketLoss: 100,
+	}
+
+	if target.Mode == "tcp" {
+		return c.tcpPing(target, result)
+	}
+
+	pinger, err := probing.NewPinger(target.Target)
+	if err != nil {
+		result.Error = fmt.Sprintf("create pinger failed: %v", err)
+		return result
+	}
+	pinger.Count = pingCount
+	pinger.Timeout = pingTimeout
+	pinger.Interval = 200 * time.Millisecond
+
+	// 与 ICMP 监控一致，优先使用非特权模式，失败后尝试特权模式
+	pinger.SetPrivileged(false)
+	if err := pinger.Run(); err != nil {
+		pinger.SetPrivileged(true)
+		if err := pinger.Run(); err != nil {
+			result.Error = fmt.Sprintf("ping failed: %v", err)
+			return result
+		}
+	}
+
+	stats := pinger.Statistics()
+	result.Sent = stats.PacketsSent
+	result.Received = stats.PacketsRecv
+	result.PacketLoss = stats.PacketLoss
+	if stats.PacketsRecv > 0 {
+		result.MinRtt = durationToMillis(stats.MinRtt)
+		result.AvgRtt = durationToMillis(stats.AvgRtt)
+		result.MaxRtt = durationToMillis(stats.MaxRtt)
+	}
+	return result
+}
+
+// tcpPing 通过 TCP 建连耗时测量延迟，用于禁止 ICMP 的网络环境
+func (c *PingCollector) tcpPing(target protocol.PingTarget, result protocol.PingData) protocol.PingData {
+	address := net.JoinHostPort(target.Target, strconv.Itoa(target.Port))
+
+	var (
+		total, minRtt, maxRtt time.Duration
+		lastErr               error
+	)
+	for i := 0; i < pingCount; i++ {
+		if i > 0 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		start := time.Now()
+		conn, err := net.DialTimeout("tcp", address, tcpPingTimeout)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		rtt := time.Since(start)
+		_ = conn.Close()
+
+		result.Received++
+		total += rtt
+		if minRtt == 0 || rtt < minRtt {
+			minRtt = rtt
+		}
+		if rtt > maxRtt {
+			maxRtt = rtt
+		}
+	}
+
+	result.PacketLoss = float64(pingCount-result.Received) / float64(pingCount) * 100
+	if result.Received == 0 {
+		result.Error = fmt.Sprintf("tcp ping failed: %v", lastErr)
+		return result
+	}
+	result.MinRtt = durationToMillis(minRtt)
+	result.AvgRtt = durationToMillis(total / time.Duration(result.Received))
+	result.MaxRtt = durationToMillis(maxRtt)
+	return result
+}
+
+func durationToMillis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,16 @@
+package collector
+
+import "github.com/dushixiang/pika/internal/protocol"
+
+// PressureCollector PSI 压力采集器，相比负载更能直接反映 CPU、内存、IO 的资源争抢
+type PressureCollector struct{}
+
+// NewPressureCollector 创建 PSI 压力采集器
+func NewPressureCollector() *PressureCollector {
+	return &PressureCollector{}
+}
+
+// Collect 采集 PSI 压力数据，系统不支持时返回 nil
+func (c *PressureCollector) Collect() (*protocol.PressureData, error) {
+	return collectPressure()
+}
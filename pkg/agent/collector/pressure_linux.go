@@ -0,0 +1,99 @@
+//go:build linux
+
+package collector
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+// collectPressure 读取 /proc/pressure 下的 cpu、memory、io，内核未开启 PSI 时目录不存在，返回 nil
+func collectPressure() (*protocol.PressureData, error) {
+	if _, err := os.Stat("/proc/pressure"); errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	var data protocol.PressureData
+	var err error
+	if data.CPU, err = readPressureFile("/proc/pressure/cpu"); err != nil {
+		return nil, err
+	}
+	if data.Memory, err = readPressureFile("/proc/pressure/memory"); err != nil {
+		return nil, err
+	}
+	if data.IO, err = readPressureFile("/proc/pressure/io"); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// readPressureFile 解析 PSI 文件，格式如：
+//
+//	some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func readPressureFile(path string) (*protocol.PressureStat, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		// 启用了 PSI 但禁止读取（如容器中）时跳过该项
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var stat protocol.PressureStat
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		avg, err := parsePressureAvg(fields[1:])
+		if err != nil {
+			return nil, fmt.Errorf("解析 %s 失败: %w", path, err)
+		}
+		switch fields[0] {
+		case "some":
+			stat.Some = avg
+		case "full":
+			stat.Full = &avg
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return &stat, nil
+}
+
+func parsePressureAvg(fields []string) (protocol.PressureAvg, error) {
+	var avg protocol.PressureAvg
+	for _, field := range fields {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			continue
+		}
+		var err error
+		switch key {
+		case "avg10":
+			avg.Avg10, err = strconv.ParseFloat(value, 64)
+		case "avg60":
+			avg.Avg60, err = strconv.ParseFloat(value, 64)
+		case "avg300":
+			avg.Avg300, err = strconv.ParseFloat(value, 64)
+		case "total":
+			avg.Total, err = strconv.ParseUint(value, 10, 64)
+		}
+		if err != nil {
+			return avg, err
+		}
+	}
+	return avg, nil
+}
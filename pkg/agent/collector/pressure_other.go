@@ -0,0 +1,10 @@
+//go:build !linux
+
+package collector
+
+import "github.com/dushixiang/pika/internal/protocol"
+
+// collectPressure PSI 是 Linux 特有的接口，其他系统不采集
+func collectPressure() (*protocol.PressureData, error) {
+	return nil, nil
+}
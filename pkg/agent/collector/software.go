@@ -0,0 +1,95 @@
+package collector
+
+import (
+	"context"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/pkg/agent/sysutil"
+	"github.com/shirou/gopsutil/v4/host"
+)
+
+// versionPattern 匹配常见的版本号格式，如 3.0.13、1.1.1w、24.0.7
+var versionPattern = regexp.MustCompile(`\d+(?:\.\d+)+[a-z]*`)
+
+// softwareProbe 软件版本探测命令
+type softwareProbe struct {
+	name     string
+	commands [][]string // 依次尝试，直到获取到版本号
+}
+
+var softwareProbes = []softwareProbe{
+	{name: "docker", commands: [][]string{
+		{"docker", "version", "--format", "{{.Server.Version}}"},
+		{"docker", "--version"},
+	}},
+	{name: "nginx", commands: [][]string{
+		{"nginx", "-v"},
+	}},
+	{name: "openssl", commands: [][]string{
+		{"openssl", "version"},
+	}},
+	{name: "glibc", commands: [][]string{
+		{"getconf", "GNU_LIBC_VERSION"},
+		{"ldd", "--version"},
+	}},
+}
+
+// SoftwareCollector 软件清单采集器
+type SoftwareCollector struct{}
+
+// NewSoftwareCollector 创建软件清单采集器
+func NewSoftwareCollector() *SoftwareCollector {
+	return &SoftwareCollector{}
+}
+
+// Collect 采集关键软件版本和运行中容器的镜像，未安装的软件不会出现在结果中
+func (s *SoftwareCollector) Collect() (*protocol.SoftwareInventoryData, error) {
+	var items []protocol.SoftwareItem
+
+	if kernelVersion, err := host.KernelVersion(); err == nil && kernelVersion != "" {
+		items = append(items, protocol.SoftwareItem{Name: "kernel", Version: kernelVersion})
+	}
+
+	for _, probe := range softwareProbes {
+		if version := s.probe(probe); version != "" {
+			items = append(items, protocol.SoftwareItem{Name: probe.name, Version: version})
+		}
+	}
+
+	inventory := &protocol.SoftwareInventoryData{Items: items}
+	// Docker 不可用时跳过容器镜像，与未安装的软件一样不影响清单上报
+	if sysutil.DockerAvailable() {
+		if containers, err := collectContainerImages(); err == nil {
+			inventory.Containers = containers
+		}
+	}
+	return inventory, nil
+}
+
+// probe 执行探测命令并解析版本号
+func (s *SoftwareCollector) probe(probe softwareProbe) string {
+	for _, command := range probe.commands {
+		// 前一个命令不存在时继续尝试后备命令，如没有 getconf 时使用 ldd 获取 glibc 版本
+		if _, err := exec.LookPath(command[0]); err != nil {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		// nginx -v 等命令会把版本输出到 stderr
+		output, err := exec.CommandContext(ctx, command[0], command[1:]...).CombinedOutput()
+		cancel()
+		if err != nil {
+			continue
+		}
+
+		firstLine := strings.SplitN(strings.TrimSpace(string(output)), "\n", 2)[0]
+		if version := versionPattern.FindString(firstLine); version != "" {
+			return version
+		}
+	}
+	return ""
+}
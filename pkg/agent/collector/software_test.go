@@ -0,0 +1,49 @@
+package collector
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestSoftwareProbe(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("依赖 echo 命令")
+	}
+
+	tests := []struct {
+		name     string
+		commands [][]string
+		want     string
+	}{
+		{
+			name:     "首个命令成功",
+			commands: [][]string{{"echo", "OpenSSL 3.0.13 30 Jan 2024"}},
+			want:     "3.0.13",
+		},
+		{
+			name:     "首个命令不存在时尝试后备命令",
+			commands: [][]string{{"pika-no-such-command", "--version"}, {"echo", "ldd (GNU libc) 2.35"}},
+			want:     "2.35",
+		},
+		{
+			name:     "输出中没有版本号时尝试后备命令",
+			commands: [][]string{{"echo", "unknown"}, {"echo", "nginx version: nginx/1.24.0"}},
+			want:     "1.24.0",
+		},
+		{
+			name:     "所有命令都不存在",
+			commands: [][]string{{"pika-no-such-command"}, {"pika-no-such-command-2"}},
+			want:     "",
+		},
+	}
+
+	s := NewSoftwareCollector()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.probe(softwareProbe{name: "test", commands: tt.commands})
+			if got != tt.want {
+				t.Fatalf("probe() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -21,7 +21,9 @@ func NewTemperatureCollector() *TemperatureCollector {
 // Collect 采集温度数据（某些系统可能不支持）
 func (t *TemperatureCollector) Collect() ([]*protocol.TemperatureData, error) {
 	// 使用 gopsutil 的 sensors 包采集温度数据
-	temps, err := sensors.TemperaturesWithContext(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	temps, err := sensors.TemperaturesWithContext(ctx)
 	if err != nil && len(temps) == 0 {
 		// 如果获取失败，返回空数组（某些系统可能不支持）
 		return []*protocol.TemperatureData{}, nil
@@ -0,0 +1,137 @@
+package collector
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/pkg/agent/config"
+)
+
+// ntpEpochOffset NTP 时间（1900 年起）与 Unix 时间（1970 年起）相差的秒数
+const ntpEpochOffset = 2208988800
+
+// TimeSyncCollector 时钟偏差采集器，通过 SNTP 查询配置的 NTP 服务器计算本机时钟偏差
+// 查询有网络开销，按 ntp_interval 间隔执行，期间重复上报上一次的结果
+type TimeSyncCollector struct {
+	server   string
+	interval time.Duration
+	timeout  time.Duration
+
+	mu        sync.Mutex
+	last      *protocol.TimeSyncData
+	checkedAt time.Time
+}
+
+// NewTimeSyncCollector 创建时钟偏差采集器
+func NewTimeSyncCollector(cfg *config.Config) *TimeSyncCollector {
+	return &TimeSyncCollector{
+		server:   cfg.Collector.NTPServer,
+		interval: cfg.GetNTPInterval(),
+		timeout:  5 * time.Second,
+	}
+}
+
+// Enabled 是否配置了 NTP 服务器
+func (t *TimeSyncCollector) Enabled() bool {
+	return t.server != ""
+}
+
+// Collect 查询 NTP 服务器，距上一次查询未超过间隔时返回上一次的结果
+// 查询失败时返回带 Error 的结果而不是错误，服务端据此展示 NTP 不可达
+func (t *TimeSyncCollector) Collect() *protocol.TimeSyncData {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.last != nil && time.Since(t.checkedAt) < t.interval {
+		return t.last
+	}
+
+	data := &protocol.TimeSyncData{
+		Server:    t.server,
+		CheckedAt: time.Now().UnixMilli(),
+	}
+	offset, rtt, stratum, err := t.query()
+	if err != nil {
+		data.Error = err.Error()
+	} else {
+		data.Offset = float64(offset.Microseconds()) / 1000
+		data.RTT = float64(rtt.Microseconds()) / 1000
+		data.Stratum = stratum
+	}
+
+	t.last = data
+	t.checkedAt = time.Now()
+	return data
+}
+
+// query 发送一次 SNTP v4 客户端请求（RFC 4330），返回时钟偏差、往返时延和服务器层级
+func (t *TimeSyncCollector) query() (offset, rtt time.Duration, stratum int, err error) {
+	address := t.server
+	if _, _, splitErr := net.SplitHostPort(address); splitErr != nil {
+		address = net.JoinHostPort(address, "123")
+	}
+
+	conn, err := net.DialTimeout("udp", address, t.timeout)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(t.timeout)); err != nil {
+		return 0, 0, 0, err
+	}
+
+	request := make([]byte, 48)
+	request[0] = 0x23 // LI = 0, VN = 4, Mode = 3（客户端）
+	t1 := time.Now()
+	binary.BigEndian.PutUint64(request[40:], toNTPTime(t1))
+	if _, err := conn.Write(request); err != nil {
+		return 0, 0, 0, err
+	}
+
+	response := make([]byte, 48)
+	n, err := conn.Read(response)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	t4 := time.Now()
+	if n < 48 {
+		return 0, 0, 0, fmt.Errorf("NTP 响应长度错误: %d", n)
+	}
+	if mode := response[0] & 0x07; mode != 4 {
+		return 0, 0, 0, fmt.Errorf("NTP 响应模式错误: %d", mode)
+	}
+	stratum = int(response[1])
+	if stratum == 0 {
+		// Kiss-o'-Death 报文，服务器拒绝或限制了请求
+		return 0, 0, 0, fmt.Errorf("NTP 服务器拒绝请求: %s", string(response[12:16]))
+	}
+	if binary.BigEndian.Uint64(response[24:]) != binary.BigEndian.Uint64(request[40:]) {
+		return 0, 0, 0, fmt.Errorf("NTP 响应与请求不匹配")
+	}
+
+	t2 := fromNTPTime(binary.BigEndian.Uint64(response[32:]))
+	t3 := fromNTPTime(binary.BigEndian.Uint64(response[40:]))
+
+	offset = (t2.Sub(t1) + t3.Sub(t4)) / 2
+	rtt = t4.Sub(t1) - t3.Sub(t2)
+	return offset, rtt, stratum, nil
+}
+
+// toNTPTime 转换为 64 位 NTP 时间戳（高 32 位为秒，低 32 位为秒的小数部分）
+func toNTPTime(t time.Time) uint64 {
+	nsec := uint64(t.UnixNano()) + ntpEpochOffset*1e9
+	sec := nsec / 1e9
+	frac := (nsec % 1e9) << 32 / 1e9
+	return sec<<32 | frac
+}
+
+// fromNTPTime 解析 64 位 NTP 时间戳
+func fromNTPTime(v uint64) time.Time {
+	sec := int64(v>>32) - ntpEpochOffset
+	nsec := (int64(v&0xffffffff) * 1e9) >> 32
+	return time.Unix(sec, nsec)
+}
@@ -0,0 +1,138 @@
+package collector
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+// commandTimeout 采集器调用外部命令（nvidia-smi、wg 等）的超时时间，超时后终止子进程
+const commandTimeout = 10 * time.Second
+
+var (
+	// ErrCollectorTimeout 采集超过超时时间仍未返回
+	ErrCollectorTimeout = errors.New("collector timeout")
+	// ErrCollectorStuck 上一次采集仍未返回，本次跳过
+	ErrCollectorStuck = errors.New("collector stuck")
+)
+
+// Watchdog 采集器看门狗
+// 每次采集在独立的 goroutine 中执行，超过超时时间未返回（如 smartctl 挂起、NFS 挂载点 stat 阻塞）时不再等待，
+// 避免单个采集器拖住整个采集循环。Go 无法强制结束 goroutine，卡住的采集在返回前不会重复调度，
+// 防止 goroutine 堆积；调用外部命令的采集器通过 commandTimeout 终止子进程。卡住的采集返回后重新调度
+type Watchdog struct {
+	timeout time.Duration
+	mu      sync.Mutex
+	states  map[string]*collectorState
+	changed bool // 状态是否变化，用于决定是否立即上报
+}
+
+// collectorState 单个采集器的运行状态
+type collectorState struct {
+	health    protocol.CollectorHealth
+	running   bool      // 是否有采集仍在执行（包括已超时放弃等待的）
+	startedAt time.Time // 正在执行的采集开始时间
+}
+
+// NewWatchdog 创建采集器看门狗，timeout 为单次采集的超时时间
+func NewWatchdog(timeout time.Duration) *Watchdog {
+	return &Watchdog{
+		timeout: timeout,
+		states:  make(map[string]*collectorState),
+	}
+}
+
+// Run 在看门狗监督下执行一次采集
+// 上一次采集仍未返回时直接返回 ErrCollectorStuck，本次超时返回 ErrCollectorTimeout
+func (w *Watchdog) Run(name string, fn func() error) error {
+	w.mu.Lock()
+	state, ok := w.states[name]
+	if !ok {
+		state = &collectorState{health: protocol.CollectorHealth{Name: name, Status: protocol.CollectorStatusOK}}
+		w.states[name] = state
+	}
+	if state.running {
+		w.mu.Unlock()
+		return ErrCollectorStuck
+	}
+	state.running = true
+	state.startedAt = time.Now()
+	w.mu.Unlock()
+
+	done := make(chan error, 1)
+	go func() {
+		err := fn()
+		w.finish(state, err)
+		done <- err
+	}()
+
+	timer := time.NewTimer(w.timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		w.mu.Lock()
+		// 超时与完成可能同时发生，以状态为准
+		if state.running {
+			state.health.Timeouts++
+			state.health.StuckSince = state.startedAt.UnixMilli()
+			w.setStatus(state, protocol.CollectorStatusStuck)
+		}
+		w.mu.Unlock()
+		return ErrCollectorTimeout
+	}
+}
+
+// finish 记录一次采集的结果
+func (w *Watchdog) finish(state *collectorState, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	if state.health.Status == protocol.CollectorStatusStuck {
+		// 卡住的采集终于返回，下一轮重新调度
+		state.health.Restarts++
+		state.health.StuckSince = 0
+	}
+	state.running = false
+	state.health.LastDuration = now.Sub(state.startedAt).Milliseconds()
+	if err != nil {
+		state.health.LastError = err.Error()
+		w.setStatus(state, protocol.CollectorStatusError)
+		return
+	}
+	state.health.LastError = ""
+	state.health.LastSuccessAt = now.UnixMilli()
+	w.setStatus(state, protocol.CollectorStatusOK)
+}
+
+// setStatus 更新采集器状态，需持有锁
+func (w *Watchdog) setStatus(state *collectorState, status string) {
+	if state.health.Status != status {
+		state.health.Status = status
+		w.changed = true
+	}
+}
+
+// Health 返回所有采集器的健康状态（按名称排序），以及自上次调用以来状态是否变化
+func (w *Watchdog) Health() ([]protocol.CollectorHealth, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	health := make([]protocol.CollectorHealth, 0, len(w.states))
+	for _, state := range w.states {
+		health = append(health, state.health)
+	}
+	sort.Slice(health, func(i, j int) bool {
+		return health[i].Name < health[j].Name
+	})
+
+	changed := w.changed
+	w.changed = false
+	return health, changed
+}
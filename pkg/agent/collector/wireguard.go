@@ -0,0 +1,96 @@
+package collector
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+// WireGuardCollector WireGuard 隧道采集器
+type WireGuardCollector struct{}
+
+// NewWireGuardCollector 创建 WireGuard 采集器
+func NewWireGuardCollector() *WireGuardCollector {
+	return &WireGuardCollector{}
+}
+
+// Collect 采集 WireGuard 隧道和对端状态
+// 依赖 wg 命令（wireguard-tools），通常需要 root 权限
+func (w *WireGuardCollector) Collect() ([]*protocol.WireGuardData, error) {
+	if _, err := exec.LookPath("wg"); err != nil {
+		return []*protocol.WireGuardData{}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, "wg", "show", "all", "dump").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	return parseWireGuardDump(string(output)), nil
+}
+
+// parseWireGuardDump 解析 wg show all dump 的输出
+// 网卡行: interface private-key public-key listen-port fwmark
+// 对端行: interface public-key preshared-key endpoint allowed-ips latest-handshake transfer-rx transfer-tx persistent-keepalive
+func parseWireGuardDump(output string) []*protocol.WireGuardData {
+	var tunnels []*protocol.WireGuardData
+	tunnelMap := make(map[string]*protocol.WireGuardData)
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		switch len(fields) {
+		case 5:
+			listenPort, _ := strconv.Atoi(fields[3])
+			tunnel := &protocol.WireGuardData{
+				Interface:  fields[0],
+				PublicKey:  fields[2],
+				ListenPort: listenPort,
+				Up:         isInterfaceUp(fields[0]),
+				Peers:      []protocol.WireGuardPeerData{},
+			}
+			tunnelMap[tunnel.Interface] = tunnel
+			tunnels = append(tunnels, tunnel)
+		case 9:
+			tunnel, ok := tunnelMap[fields[0]]
+			if !ok {
+				continue
+			}
+
+			peer := protocol.WireGuardPeerData{
+				PublicKey: fields[1],
+			}
+			if fields[3] != "(none)" {
+				peer.Endpoint = fields[3]
+			}
+			if fields[4] != "(none)" {
+				peer.AllowedIPs = strings.Split(fields[4], ",")
+			}
+			if handshake, _ := strconv.ParseInt(fields[5], 10, 64); handshake > 0 {
+				peer.LatestHandshake = handshake * 1000
+			}
+			peer.TransferRx, _ = strconv.ParseUint(fields[6], 10, 64)
+			peer.TransferTx, _ = strconv.ParseUint(fields[7], 10, 64)
+
+			tunnel.Peers = append(tunnel.Peers, peer)
+		}
+	}
+
+	return tunnels
+}
+
+// isInterfaceUp 检查网卡是否处于 UP 状态
+func isInterfaceUp(name string) bool {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return false
+	}
+	return iface.Flags&net.FlagUp != 0
+}
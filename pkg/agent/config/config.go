@@ -7,8 +7,10 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"strings"
 	"time"
 
+	"github.com/dushixiang/pika/internal/protocol"
 	"gopkg.in/yaml.v3"
 )
 
@@ -17,6 +19,9 @@ type Config struct {
 	// 配置文件路径
 	Path string `yaml:"-"`
 
+	// 是否加密保存敏感字段（配置文件中的敏感字段带有 enc:v1: 前缀时自动开启）
+	encryptSecrets bool
+
 	// 服务器配置
 	Server ServerConfig `yaml:"server"`
 
@@ -26,8 +31,38 @@ type Config struct {
 	// 采集配置
 	Collector CollectorConfig `yaml:"collector"`
 
+	// 自定义指标配置
+	CustomMetrics CustomMetricsConfig `yaml:"custom_metrics"`
+
+	// 离线指标缓存配置
+	Spool SpoolConfig `yaml:"spool"`
+
 	// 自动更新配置
 	AutoUpdate AutoUpdateConfig `yaml:"auto_update"`
+
+	// 远程日志查看配置
+	LogTail LogTailConfig `yaml:"log_tail"`
+
+	// 告警修复动作配置
+	Remediation RemediationConfig `yaml:"remediation"`
+
+	// 电源操作配置
+	Power PowerConfig `yaml:"power"`
+
+	// 远程终端配置
+	Terminal TerminalConfig `yaml:"terminal"`
+
+	// 远程命令配置
+	Commands CommandsConfig `yaml:"commands"`
+
+	// 进程管理配置
+	Processes ProcessesConfig `yaml:"processes"`
+
+	// Docker 容器管理配置
+	Docker DockerConfig `yaml:"docker"`
+
+	// 日志配置
+	Log LogConfig `yaml:"log"`
 }
 
 // ServerConfig 服务器配置
@@ -40,12 +75,21 @@ type ServerConfig struct {
 
 	// 是否跳过 TLS 证书验证（仅用于测试环境，生产环境不建议开启）
 	InsecureSkipVerify bool `yaml:"insecure_skip_verify"`
+
+	// 是否启用 WebSocket 消息压缩（permessage-deflate），在连接握手时与服务端协商，服务端不支持时不压缩
+	Compression bool `yaml:"compression"`
+
+	// 指标编码：json（默认）或 msgpack，在注册时与服务端协商，服务端不支持时使用 json
+	Encoding string `yaml:"encoding"`
 }
 
 // AgentConfig Agent 配置
 type AgentConfig struct {
 	// Agent 名称（默认使用主机名）
 	Name string `yaml:"name"`
+
+	// 运行模式：standard（默认）或 lite（精简模式，适用于 OpenWrt 和小型 ARM 设备）
+	Profile string `yaml:"profile"`
 }
 
 // CollectorConfig 采集器配置
@@ -72,6 +116,63 @@ type CollectorConfig struct {
 	//   Linux/macOS: ["/", "/data", "/home"]
 	//   Windows: ["C:", "D:"]
 	DiskInclude []string `yaml:"disk_include"`
+
+	// 是否上报软件清单（kernel、docker、nginx、openssl、glibc 等版本，每小时采集一次）
+	SoftwareInventory bool `yaml:"software_inventory"`
+
+	// 需要健康检查的网络挂载点（NFS/SMB 等），为空时不检查
+	// 例如: ["/mnt/nfs", "/mnt/share"]
+	Mounts []string `yaml:"mounts"`
+
+	// 挂载点探测超时时间（秒），超时视为挂起，默认 3 秒
+	MountTimeout int `yaml:"mount_timeout"`
+
+	// 用于检查时钟偏差的 NTP 服务器，为空时不检查
+	// 例如: "pool.ntp.org"、"ntp.aliyun.com"、"192.168.1.1:123"
+	NTPServer string `yaml:"ntp_server"`
+
+	// 查询 NTP 服务器的间隔（秒），默认 300 秒，最小 60 秒
+	NTPInterval int `yaml:"ntp_interval"`
+
+	// 是否监视内核日志（仅 Linux），将 OOM、I/O 错误和硬件故障作为事件上报
+	KernelLog bool `yaml:"kernel_log"`
+}
+
+// CustomMetricsConfig 自定义指标配置，指标使用 Prometheus 文本格式（name{label="value"} 数值）
+type CustomMetricsConfig struct {
+	// 文本文件目录，读取目录下所有 *.prom 文件（与 node_exporter 的 textfile collector 相同），为空时不读取
+	// 例如: "/var/lib/pika/textfile"
+	TextfileDir string `yaml:"textfile_dir"`
+
+	// 自定义命令，按各自的间隔执行，标准输出按 Prometheus 文本格式解析
+	Commands []CustomCommandConfig `yaml:"commands"`
+}
+
+// CustomCommandConfig 自定义指标命令
+type CustomCommandConfig struct {
+	// 名称，用于日志和标识指标来源
+	Name string `yaml:"name"`
+
+	// 命令及参数，不经过 shell 解析，需要管道等功能时使用 ["sh", "-c", "..."]
+	Command []string `yaml:"command"`
+
+	// 执行间隔（秒），默认 60 秒
+	Interval int `yaml:"interval"`
+
+	// 执行超时时间（秒），默认 10 秒
+	Timeout int `yaml:"timeout"`
+}
+
+// SpoolConfig 离线指标缓存配置，与服务端断开期间采集的指标写入本地文件，重连后按原始采集时间补发
+type SpoolConfig struct {
+	// 是否启用离线缓存，默认关闭
+	Enabled bool `yaml:"enabled"`
+
+	// 缓存文件路径，默认 ~/.pika/spool.jsonl
+	Path string `yaml:"path"`
+
+	// 缓存文件大小上限（MB），超出时丢弃最早的数据，默认 16 MB
+	MaxSize int `yaml:"max_size"`
 }
 
 // AutoUpdateConfig 自动更新配置
@@ -83,6 +184,95 @@ type AutoUpdateConfig struct {
 	CheckInterval string `yaml:"check_interval"`
 }
 
+// LogTailConfig 远程日志查看配置
+type LogTailConfig struct {
+	// 是否允许管理员通过服务端查看本机日志（默认关闭）
+	Enabled bool `yaml:"enabled"`
+
+	// 允许查看的日志文件（白名单，需为绝对路径，精确匹配）
+	// 例如: ["/var/log/nginx/error.log", "/var/log/syslog"]
+	Paths []string `yaml:"paths"`
+
+	// 允许通过 journalctl 查看的 systemd 服务（白名单）
+	// 例如: ["nginx", "sshd"]
+	Units []string `yaml:"units"`
+}
+
+// RemediationConfig 告警修复动作配置
+type RemediationConfig struct {
+	// 是否允许服务端在告警触发时执行修复动作（默认关闭）
+	Enabled bool `yaml:"enabled"`
+
+	// 允许重启的 systemd 服务（白名单）
+	// 例如: ["nginx", "php-fpm"]
+	Services []string `yaml:"services"`
+
+	// 允许清空的目录（白名单，需为绝对路径，只删除目录下的内容，不删除目录本身）
+	// 例如: ["/tmp/app-cache"]
+	CleanDirs []string `yaml:"clean_dirs"`
+}
+
+// PowerConfig 电源操作配置
+type PowerConfig struct {
+	// 是否允许服务端下发重启指令（默认关闭）
+	AllowReboot bool `yaml:"allow_reboot"`
+
+	// 是否允许作为网络唤醒中继，向所在局域网发送魔术包（默认关闭）
+	AllowWakeOnLAN bool `yaml:"allow_wake_on_lan"`
+}
+
+// TerminalConfig 远程终端配置
+type TerminalConfig struct {
+	// 是否允许管理员通过服务端打开本机的交互式终端（默认关闭），终端以探针的运行用户执行
+	Enabled bool `yaml:"enabled"`
+
+	// 终端使用的 shell，默认 /bin/bash，不存在时使用 /bin/sh
+	Shell string `yaml:"shell"`
+}
+
+// CommandsConfig 远程命令配置，服务端只能执行白名单内的命令
+type CommandsConfig struct {
+	// 是否允许服务端下发远程命令（默认关闭）
+	Enabled bool `yaml:"enabled"`
+
+	// 允许重启的 systemd 服务（restart_service）
+	// 例如: ["nginx", "php-fpm"]
+	Services []string `yaml:"services"`
+
+	// 允许清空的缓存目录（clear_cache，需为绝对路径，只删除目录下的内容，不删除目录本身）
+	// 例如: ["/var/cache/nginx"]
+	CacheDirs []string `yaml:"cache_dirs"`
+
+	// 允许执行的脚本（run_script），名称到脚本绝对路径的映射，服务端只能按名称执行，不能传入脚本内容和参数
+	// 例如: {"backup": "/opt/scripts/backup.sh"}
+	Scripts map[string]string `yaml:"scripts"`
+}
+
+// ProcessesConfig 进程管理配置，服务端可以随时查看进程列表，结束进程需要单独开启
+type ProcessesConfig struct {
+	// 是否允许服务端结束本机进程（默认关闭），PID 1 和探针自身始终不允许结束
+	AllowKill bool `yaml:"allow_kill"`
+}
+
+// DockerConfig Docker 容器管理配置，开启后服务端可以查看容器列表并启动、停止、重启容器
+type DockerConfig struct {
+	// 是否允许服务端管理本机的 Docker 容器（默认关闭），需要探针有执行 docker 命令的权限
+	Enabled bool `yaml:"enabled"`
+}
+
+// LogConfig 日志配置
+type LogConfig struct {
+	// 默认日志级别：debug、info（默认）、warn、error
+	Level string `yaml:"level"`
+
+	// 日志格式：console（默认）或 json
+	Format string `yaml:"format"`
+
+	// 模块日志级别，未配置的模块使用默认级别
+	// 例如: {"tamper": "debug", "updater": "warn"}
+	Modules map[string]string `yaml:"modules"`
+}
+
 // DefaultConfig 返回默认配置
 func DefaultConfig() *Config {
 	return &Config{
@@ -92,16 +282,26 @@ func DefaultConfig() *Config {
 			InsecureSkipVerify: false,
 		},
 		Agent: AgentConfig{
-			Name: "",
+			Name:    "",
+			Profile: defaultProfile,
 		},
 		Collector: CollectorConfig{
 			Interval:          5,
 			HeartbeatInterval: 30,
 		},
+		Spool: SpoolConfig{
+			// 默认关闭，避免精简模式下的路由器、小型 ARM 设备频繁写入闪存
+			Enabled: false,
+			MaxSize: 16,
+		},
 		AutoUpdate: AutoUpdateConfig{
 			Enabled:       true,
 			CheckInterval: "10m",
 		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "console",
+		},
 	}
 }
 
@@ -141,6 +341,11 @@ func Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("解析配置文件失败: %w", err)
 	}
 
+	// 解密敏感字段
+	if err := cfg.decryptSecrets(); err != nil {
+		return nil, err
+	}
+
 	// 验证配置
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("配置验证失败: %w", err)
@@ -163,8 +368,18 @@ func (c *Config) Save(path string) error {
 		return fmt.Errorf("创建配置目录失败: %w", err)
 	}
 
+	// 开启加密时，敏感字段加密后再写入文件
+	out := c
+	if c.encryptSecrets {
+		encrypted, err := c.withEncryptedSecrets()
+		if err != nil {
+			return err
+		}
+		out = encrypted
+	}
+
 	// 序列化配置
-	data, err := yaml.Marshal(c)
+	data, err := yaml.Marshal(out)
 	if err != nil {
 		return fmt.Errorf("序列化配置失败: %w", err)
 	}
@@ -195,23 +410,91 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("心跳间隔必须大于 0")
 	}
 
+	if c.Agent.Profile != "" && c.Agent.Profile != ProfileStandard && c.Agent.Profile != ProfileLite {
+		return fmt.Errorf("运行模式仅支持 %s 或 %s", ProfileStandard, ProfileLite)
+	}
+
 	if c.AutoUpdate.Enabled {
 		if _, err := time.ParseDuration(c.AutoUpdate.CheckInterval); err != nil {
 			return fmt.Errorf("更新检查间隔格式错误: %w", err)
 		}
 	}
 
+	for _, command := range c.CustomMetrics.Commands {
+		if command.Name == "" || len(command.Command) == 0 {
+			return fmt.Errorf("自定义指标命令的名称和命令不能为空")
+		}
+	}
+
+	if c.Log.Format != "" && c.Log.Format != "console" && c.Log.Format != "json" {
+		return fmt.Errorf("日志格式仅支持 console 或 json")
+	}
+
 	return nil
 }
 
 // GetCollectorInterval 获取采集间隔时长
 func (c *Config) GetCollectorInterval() time.Duration {
-	return time.Duration(c.Collector.Interval) * time.Second
+	return c.applyProfileInterval(c.Collector.Interval, liteMinCollectorInterval)
+}
+
+// GetMountTimeout 获取挂载点探测超时时间
+func (c *Config) GetMountTimeout() time.Duration {
+	if c.Collector.MountTimeout <= 0 {
+		return 3 * time.Second
+	}
+	return time.Duration(c.Collector.MountTimeout) * time.Second
+}
+
+// GetNTPInterval 获取查询 NTP 服务器的间隔，避免频繁请求公共 NTP 服务器被限流
+func (c *Config) GetNTPInterval() time.Duration {
+	if c.Collector.NTPInterval <= 0 {
+		return 5 * time.Minute
+	}
+	if c.Collector.NTPInterval < 60 {
+		return time.Minute
+	}
+	return time.Duration(c.Collector.NTPInterval) * time.Second
+}
+
+// GetSpoolPath 获取离线指标缓存文件路径
+func (c *Config) GetSpoolPath() string {
+	if c.Spool.Path != "" {
+		return c.Spool.Path
+	}
+	return filepath.Join(filepath.Dir(GetDefaultConfigPath()), "spool.jsonl")
+}
+
+// GetSpoolMaxBytes 获取离线指标缓存文件大小上限
+func (c *Config) GetSpoolMaxBytes() int64 {
+	if c.Spool.MaxSize <= 0 {
+		return 16 << 20
+	}
+	return int64(c.Spool.MaxSize) << 20
+}
+
+// GetTerminalShell 获取远程终端使用的 shell
+func (c *Config) GetTerminalShell() string {
+	if c.Terminal.Shell != "" {
+		return c.Terminal.Shell
+	}
+	if _, err := os.Stat("/bin/bash"); err == nil {
+		return "/bin/bash"
+	}
+	return "/bin/sh"
+}
+
+// GetEncodings 获取注册时声明支持的指标编码，按优先级排列
+func (c *Config) GetEncodings() []string {
+	if c.Server.Encoding == protocol.EncodingMsgpack {
+		return []string{protocol.EncodingMsgpack, protocol.EncodingJSON}
+	}
+	return []string{protocol.EncodingJSON}
 }
 
 // GetHeartbeatInterval 获取心跳间隔时长
 func (c *Config) GetHeartbeatInterval() time.Duration {
-	return time.Duration(c.Collector.HeartbeatInterval) * time.Second
+	return c.applyProfileInterval(c.Collector.HeartbeatInterval, liteMinHeartbeatInterval)
 }
 
 // GetUpdateCheckInterval 获取更新检查间隔时长
@@ -234,7 +517,8 @@ func (c *Config) GetWebSocketURL() string {
 		scheme = "wss"
 	}
 
-	return fmt.Sprintf("%s://%s/ws/agent", scheme, u.Host)
+	// 保留路径前缀，支持服务端部署在反向代理的子路径下
+	return fmt.Sprintf("%s://%s%s/ws/agent", scheme, u.Host, strings.TrimRight(u.Path, "/"))
 }
 
 // GetLatestVersionURL 获取更新检查地址
@@ -255,7 +539,7 @@ func (c *Config) Endpoint() string {
 	if err != nil {
 		return c.Server.Endpoint
 	}
-	var endpoint = fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+	var endpoint = fmt.Sprintf("%s://%s%s", u.Scheme, u.Host, strings.TrimRight(u.Path, "/"))
 	return endpoint
 }
 
@@ -0,0 +1,45 @@
+package config
+
+import "time"
+
+const (
+	// ProfileStandard 标准模式，启用全部采集模块
+	ProfileStandard = "standard"
+	// ProfileLite 精简模式，适用于 OpenWrt 路由器和小型 ARM 设备
+	// 不采集 GPU、温度，不支持安全审计，采集间隔更长，缓冲区更小
+	ProfileLite = "lite"
+)
+
+const (
+	liteMinCollectorInterval = 30 // 精简模式下的最小采集间隔（秒）
+	liteMinHeartbeatInterval = 60 // 精简模式下的最小心跳间隔（秒）
+)
+
+// IsLite 是否运行在精简模式
+func (c *Config) IsLite() bool {
+	return c.Agent.Profile == ProfileLite
+}
+
+// GetWebSocketBufferSize 获取 WebSocket 读写缓冲区大小，0 表示使用默认值
+func (c *Config) GetWebSocketBufferSize() int {
+	if c.IsLite() {
+		return 1024
+	}
+	return 0
+}
+
+// GetLogTailBufferLines 获取远程日志查看的行缓冲数量
+func (c *Config) GetLogTailBufferLines() int {
+	if c.IsLite() {
+		return 32
+	}
+	return 256
+}
+
+// applyProfileInterval 精简模式下采集和心跳间隔不低于最小值
+func (c *Config) applyProfileInterval(seconds, liteMin int) time.Duration {
+	if c.IsLite() && seconds < liteMin {
+		seconds = liteMin
+	}
+	return time.Duration(seconds) * time.Second
+}
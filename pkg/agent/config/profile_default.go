@@ -0,0 +1,6 @@
+//go:build !lite
+
+package config
+
+// defaultProfile 默认运行模式，使用 -tags lite 编译时为精简模式
+const defaultProfile = ProfileStandard
@@ -0,0 +1,6 @@
+//go:build lite
+
+package config
+
+// defaultProfile 使用 -tags lite 编译时默认运行在精简模式
+const defaultProfile = ProfileLite
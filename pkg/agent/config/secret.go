@@ -0,0 +1,98 @@
+package config
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/dushixiang/pika/pkg/secretbox"
+	"github.com/shirou/gopsutil/v4/host"
+)
+
+// secretFields 返回配置中需要加密保存的敏感字段
+func (c *Config) secretFields() map[string]*string {
+	return map[string]*string{
+		"server.api_key": &c.Server.APIKey,
+	}
+}
+
+// EnableSecretEncryption 开启敏感字段加密，之后调用 Save 时会加密保存
+func (c *Config) EnableSecretEncryption() {
+	c.encryptSecrets = true
+}
+
+// SecretsEncrypted 配置文件中的敏感字段是否已加密
+func (c *Config) SecretsEncrypted() bool {
+	return c.encryptSecrets
+}
+
+// decryptSecrets 解密配置文件中已加密的敏感字段
+func (c *Config) decryptSecrets() error {
+	for name, field := range c.secretFields() {
+		if !secretbox.IsSealed(*field) {
+			continue
+		}
+		c.encryptSecrets = true
+
+		plain, err := decryptSecret(*field)
+		if err != nil {
+			return fmt.Errorf("解密 %s 失败（配置文件可能来自其它机器，请填写明文后重新执行 agent config encrypt）: %w", name, err)
+		}
+		*field = plain
+	}
+	return nil
+}
+
+// withEncryptedSecrets 返回敏感字段已加密的配置副本，用于保存到文件
+func (c *Config) withEncryptedSecrets() (*Config, error) {
+	encrypted := *c
+	for name, field := range encrypted.secretFields() {
+		if *field == "" || secretbox.IsSealed(*field) {
+			continue
+		}
+		value, err := encryptSecret(*field)
+		if err != nil {
+			return nil, fmt.Errorf("加密 %s 失败: %w", name, err)
+		}
+		*field = value
+	}
+	return &encrypted, nil
+}
+
+// machineKey 根据本机唯一标识派生加密密钥，加密后的配置只能在本机解密
+func machineKey() ([]byte, error) {
+	hostID, err := host.HostID()
+	if err != nil {
+		return nil, fmt.Errorf("获取机器标识失败: %w", err)
+	}
+	if hostID == "" {
+		return nil, fmt.Errorf("获取机器标识失败: 标识为空")
+	}
+	key := sha256.Sum256([]byte("pika-agent-config:" + hostID))
+	return key[:], nil
+}
+
+func newSecretBox() (*secretbox.Box, error) {
+	key, err := machineKey()
+	if err != nil {
+		return nil, err
+	}
+	return secretbox.New(key)
+}
+
+// encryptSecret 使用 AES-256-GCM 加密敏感字段
+func encryptSecret(plain string) (string, error) {
+	box, err := newSecretBox()
+	if err != nil {
+		return "", err
+	}
+	return box.Seal(plain)
+}
+
+// decryptSecret 解密敏感字段
+func decryptSecret(value string) (string, error) {
+	box, err := newSecretBox()
+	if err != nil {
+		return "", err
+	}
+	return box.Open(value)
+}
@@ -6,23 +6,44 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 	"os"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/dushixiang/pika/internal/protocol"
-	"github.com/dushixiang/pika/pkg/agent/audit"
 	"github.com/dushixiang/pika/pkg/agent/collector"
 	"github.com/dushixiang/pika/pkg/agent/config"
 	"github.com/dushixiang/pika/pkg/agent/id"
+	"github.com/dushixiang/pika/pkg/agent/spool"
 	"github.com/dushixiang/pika/pkg/agent/tamper"
+	"github.com/dushixiang/pika/pkg/logging"
 	"github.com/dushixiang/pika/pkg/version"
 	"github.com/gorilla/websocket"
 	"github.com/jpillora/backoff"
 )
 
+// 探针各子系统的日志，可按模块单独调整级别
+var (
+	logger            = logging.Module("agent")
+	pingLogger        = logging.Module("ping")
+	tamperLogger      = logging.Module("tamper")
+	ddnsLogger        = logging.Module("ddns")
+	logTailLogger     = logging.Module("logtail")
+	remediationLogger = logging.Module("remediation")
+	powerLogger       = logging.Module("power")
+	terminalLogger    = logging.Module("terminal")
+	commandLogger     = logging.Module("command")
+	processLogger     = logging.Module("process")
+	dockerLogger      = logging.Module("docker")
+	kernelLogger      = logging.Module("kernel")
+	spoolLogger       = logging.Module("spool")
+)
+
+// collectorHealthReportInterval 采集器状态无变化时的健康状态上报间隔
+const collectorHealthReportInterval = time.Minute
+
 // 定义特殊错误类型
 var (
 	// ErrConnectionEstablished 表示连接已建立后断开（需要立即重连）
@@ -31,8 +52,10 @@ var (
 
 // safeConn 线程安全的 WebSocket 连接包装器
 type safeConn struct {
-	conn *websocket.Conn
-	mu   sync.Mutex
+	conn     *websocket.Conn
+	mu       sync.Mutex
+	encoding string // 注册时协商的指标编码，注册完成前为空
+	batch    bool   // 服务端是否支持合并的指标消息
 }
 
 // WriteJSON 线程安全地写入 JSON 消息
@@ -49,6 +72,16 @@ func (sc *safeConn) WriteMessage(messageType int, data []byte) error {
 	return sc.conn.WriteMessage(messageType, data)
 }
 
+// WriteBinary 线程安全地写入二进制消息
+func (sc *safeConn) WriteBinary(data []byte) error {
+	return sc.WriteMessage(websocket.BinaryMessage, data)
+}
+
+// Encoding 返回注册时协商的指标编码
+func (sc *safeConn) Encoding() string {
+	return sc.encoding
+}
+
 // ReadJSON 读取 JSON 消息（读操作本身是安全的）
 func (sc *safeConn) ReadJSON(v interface{}) error {
 	return sc.conn.ReadJSON(v)
@@ -69,15 +102,41 @@ type Agent struct {
 	collectorMu      sync.RWMutex
 	collectorManager *collector.Manager
 	tamperProtector  *tamper.Protector
+	logTailMu        sync.Mutex
+	logTails         map[string]context.CancelFunc // 正在进行的日志查看（key: 指令ID）
+	terminalMu       sync.Mutex
+	terminals        map[string]*terminalSession // 正在运行的远程终端（key: 指令ID）
+	processListMu    sync.Mutex
+	processLists     map[string]context.CancelFunc // 正在推送的进程列表（key: 指令ID）
+	pingMu           sync.RWMutex
+	pingConfig       protocol.PingConfigPayload
+	pingUpdated      chan struct{} // Ping 配置变更通知
+	intervalMu       sync.RWMutex
+	metricIntervals  map[string]int                // 服务端下发的各类指标采集间隔（秒）
+	intervalsUpdated chan struct{}                 // 采集间隔变更通知
+	watchdog         *collector.Watchdog           // 采集器看门狗，跨重连保留，避免卡住的采集被重复调度
+	kernelEvents     chan protocol.KernelEventData // 内核日志事件，监视器跨重连运行，断线期间的事件在重连后上报
+	spool            *spool.Spool                  // 离线指标缓存，未启用时为 nil
 }
 
 // New 创建 Agent 实例
 func New(cfg *config.Config) *Agent {
-	return &Agent{
-		cfg:             cfg,
-		idMgr:           id.NewManager(),
-		tamperProtector: tamper.NewProtector(),
-	}
+	a := &Agent{
+		cfg:              cfg,
+		idMgr:            id.NewManager(),
+		tamperProtector:  tamper.NewProtector(),
+		logTails:         make(map[string]context.CancelFunc),
+		terminals:        make(map[string]*terminalSession),
+		processLists:     make(map[string]context.CancelFunc),
+		pingUpdated:      make(chan struct{}, 1),
+		intervalsUpdated: make(chan struct{}, 1),
+		watchdog:         collector.NewWatchdog(cfg.GetCollectorInterval()),
+		kernelEvents:     make(chan protocol.KernelEventData, 100),
+	}
+	if cfg.Spool.Enabled {
+		a.spool = spool.New(cfg.GetSpoolPath(), cfg.GetSpoolMaxBytes())
+	}
+	return a
 }
 
 // Start 启动探针服务
@@ -86,6 +145,16 @@ func (a *Agent) Start(ctx context.Context) error {
 	ctx, cancel := context.WithCancel(ctx)
 	a.cancel = cancel
 
+	// 启动内核日志监视（可选）
+	if a.cfg.Collector.KernelLog {
+		go a.watchKernelLog(ctx)
+	}
+
+	// 启动离线指标缓存（可选）
+	if a.spool != nil {
+		go a.spoolLoop(ctx)
+	}
+
 	// 启动探针主循环
 	b := &backoff.Backoff{
 		Min:    5 * time.Second,
@@ -105,14 +174,14 @@ func (a *Agent) Start(ctx context.Context) error {
 
 		// 检查是否是上下文取消
 		if ctx.Err() != nil {
-			log.Println("收到停止信号，探针服务退出")
+			logger.Info("收到停止信号，探针服务退出")
 			return nil
 		}
 
 		// 连接建立失败或注册失败（使用 backoff）
 		if err != nil {
 			retryAfter := b.Duration()
-			log.Printf("⚠️  探针运行出错: %v，将在 %v 后重试", err, retryAfter)
+			logger.Warnf("探针运行出错: %v，将在 %v 后重试", err, retryAfter)
 
 			select {
 			case <-time.After(retryAfter):
@@ -123,7 +192,7 @@ func (a *Agent) Start(ctx context.Context) error {
 		}
 
 		// 理论上不会到这里
-		log.Println("连接意外结束")
+		logger.Info("连接意外结束")
 		return nil
 	}
 }
@@ -139,24 +208,39 @@ func (a *Agent) Stop() {
 // 返回 error 表示需要重连，返回 nil 可能是正常关闭或上下文取消
 func (a *Agent) runOnce(ctx context.Context, onConnected func()) error {
 	wsURL := a.cfg.GetWebSocketURL()
-	log.Printf("🔌 正在连接到服务器: %s", wsURL)
+	logger.Infof("正在连接到服务器: %s", wsURL)
 
 	// 创建自定义的 Dialer
-	var dialer = websocket.DefaultDialer
+	var dialer = *websocket.DefaultDialer
+	if size := a.cfg.GetWebSocketBufferSize(); size > 0 {
+		// 精简模式使用更小的读写缓冲区，降低内存占用
+		dialer.ReadBufferSize = size
+		dialer.WriteBufferSize = size
+	}
+	// 请求 permessage-deflate 压缩，服务端未启用时握手结果中不含该扩展，连接照常以不压缩的方式工作
+	dialer.EnableCompression = a.cfg.Server.Compression
 	if a.cfg.Server.InsecureSkipVerify {
 		dialer.TLSClientConfig = &tls.Config{
 			InsecureSkipVerify: true,
 		}
-		log.Println("⚠️  警告: 已禁用 TLS 证书验证")
+		logger.Warn("已禁用 TLS 证书验证")
 	}
 
 	// 连接到服务器
-	rawConn, _, err := dialer.Dial(wsURL, nil)
+	rawConn, resp, err := dialer.Dial(wsURL, nil)
 	if err != nil {
 		return fmt.Errorf("连接失败: %w", err)
 	}
 	defer rawConn.Close()
 
+	if a.cfg.Server.Compression {
+		if strings.Contains(resp.Header.Get("Sec-WebSocket-Extensions"), "permessage-deflate") {
+			logger.Info("已启用 WebSocket 消息压缩")
+		} else {
+			logger.Warn("服务端未启用 WebSocket 消息压缩，将以不压缩的方式传输")
+		}
+	}
+
 	onConnected()
 
 	// 创建线程安全的连接包装器
@@ -177,7 +261,7 @@ func (a *Agent) runOnce(ctx context.Context, onConnected func()) error {
 		return fmt.Errorf("注册失败: %w", err)
 	}
 
-	log.Println("✅ 探针注册成功，开始监控...")
+	logger.Info("探针注册成功，开始监控...")
 
 	// 创建采集器管理器
 	collectorManager := collector.NewManager(a.cfg)
@@ -193,6 +277,11 @@ func (a *Agent) runOnce(ctx context.Context, onConnected func()) error {
 	done := make(chan struct{})
 	errChan := make(chan error, 3)
 
+	// 补发断线期间缓存的指标
+	if a.spool != nil {
+		go a.replaySpool(conn, done)
+	}
+
 	// 启动读取循环（处理服务端的 Ping/Pong 等控制消息）
 	go func() {
 		if err := a.readLoop(rawConn, done); err != nil {
@@ -213,6 +302,14 @@ func (a *Agent) runOnce(ctx context.Context, onConnected func()) error {
 		}
 	}()
 
+	// 启动软件清单上报（可选）
+	if a.cfg.Collector.SoftwareInventory {
+		go a.softwareLoop(ctx, conn, collectorManager, done)
+	}
+
+	// 启动 Ping 目标检测
+	go a.pingLoop(ctx, conn, collectorManager, done)
+
 	// 启动防篡改事件监控
 	go func() {
 		a.tamperEventLoop(ctx, conn, done)
@@ -223,19 +320,24 @@ func (a *Agent) runOnce(ctx context.Context, onConnected func()) error {
 		a.tamperAlertLoop(ctx, conn, done)
 	}()
 
+	// 启动内核日志事件上报
+	if a.cfg.Collector.KernelLog {
+		go a.kernelEventLoop(ctx, conn, done)
+	}
+
 	// 等待错误或上下文取消
 	select {
 	case err := <-errChan:
 		close(done)
 		// 连接已建立，无论什么原因断开都标记为已建立状态
-		log.Printf("连接断开: %v", err)
+		logger.Infof("连接断开: %v", err)
 		return ErrConnectionEstablished
 	case <-ctx.Done():
 		close(done)
 		// 优雅关闭连接
 		closeMsg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
 		if err := conn.WriteMessage(websocket.CloseMessage, closeMsg); err != nil {
-			log.Printf("⚠️  关闭连接失败: %v", err)
+			logger.Warnf("关闭连接失败: %v", err)
 		}
 		time.Sleep(time.Second)
 		return ctx.Err() // 返回上下文错误
@@ -260,7 +362,7 @@ func (a *Agent) readLoop(conn *websocket.Conn, done chan struct{}) error {
 		// 解析消息
 		var msg protocol.Message
 		if err := json.Unmarshal(message, &msg); err != nil {
-			log.Printf("⚠️  解析消息失败: %v", err)
+			logger.Warnf("解析消息失败: %v", err)
 			continue
 		}
 
@@ -273,6 +375,19 @@ func (a *Agent) readLoop(conn *websocket.Conn, done chan struct{}) error {
 			go a.handleTamperProtect(msg.Data)
 		case protocol.MessageTypeDDNSConfig:
 			go a.handleDDNSConfig(msg.Data)
+		case protocol.MessageTypePingConfig:
+			a.handlePingConfig(msg.Data)
+		case protocol.MessageTypeCollectorConfig:
+			a.handleCollectorConfig(msg.Data)
+		case protocol.MessageTypeLogTailStop:
+			go a.handleLogTailStop(msg.Data)
+		case protocol.MessageTypeTerminalInput:
+			// 按顺序写入，保证键盘输入不乱序
+			a.handleTerminalInput(msg.Data)
+		case protocol.MessageTypeTerminalClose:
+			go a.handleTerminalClose(msg.Data)
+		case protocol.MessageTypeProcessListStop:
+			go a.handleProcessListStop(msg.Data)
 		default:
 			// 忽略其他类型
 		}
@@ -286,7 +401,7 @@ func (a *Agent) registerAgent(conn *safeConn) error {
 	if err != nil {
 		return fmt.Errorf("加载 agent ID 失败: %w", err)
 	}
-	log.Printf("🆔 Agent ID: %s (存储在: %s)", agentID, a.idMgr.GetPath())
+	logger.Infof("Agent ID: %s (存储在: %s)", agentID, a.idMgr.GetPath())
 
 	// 获取主机信息
 	hostname, _ := os.Hostname()
@@ -310,7 +425,8 @@ func (a *Agent) registerAgent(conn *safeConn) error {
 			Arch:     runtime.GOARCH,
 			Version:  GetVersion(),
 		},
-		ApiKey: a.cfg.Server.APIKey,
+		ApiKey:    a.cfg.Server.APIKey,
+		Encodings: a.cfg.GetEncodings(),
 	}
 
 	reqData, err := json.Marshal(registerReq)
@@ -351,36 +467,133 @@ func (a *Agent) registerAgent(conn *safeConn) error {
 		return fmt.Errorf("解析注册响应失败: %w", err)
 	}
 
-	log.Printf("注册成功: AgentId=%s, Status=%s", registerResp.AgentID, registerResp.Status)
+	// 旧版本服务端不返回编码，按 JSON 处理
+	conn.encoding = registerResp.Encoding
+	if conn.encoding == "" {
+		conn.encoding = protocol.EncodingJSON
+	}
+	if a.cfg.Server.Encoding == protocol.EncodingMsgpack && conn.encoding != protocol.EncodingMsgpack {
+		logger.Warn("服务端不支持 MessagePack 编码，指标将以 JSON 发送")
+	}
+
+	conn.batch = registerResp.Batch
+
+	logger.Infof("注册成功: AgentId=%s, Status=%s, Encoding=%s", registerResp.AgentID, registerResp.Status, conn.encoding)
 	return nil
 }
 
 func (a *Agent) handleMonitorConfig(data json.RawMessage) {
 	var payload protocol.MonitorConfigPayload
 	if err := json.Unmarshal(data, &payload); err != nil {
-		log.Printf("⚠️  解析监控配置失败: %v", err)
+		logger.Warnf("解析监控配置失败: %v", err)
 		return
 	}
 
 	if len(payload.Items) == 0 {
-		log.Println("ℹ️  收到空的服务监控配置，跳过")
+		logger.Debug("收到空的服务监控配置，跳过")
 		return
 	}
 
 	conn := a.getActiveConn()
 	manager := a.getCollectorManager()
 	if conn == nil || manager == nil {
-		log.Println("⚠️  当前连接未就绪，无法执行服务监控任务")
+		logger.Warn("当前连接未就绪，无法执行服务监控任务")
 		return
 	}
 
-	log.Printf("📥 收到服务监控配置，总计 %d 个监控项，立即执行检测", len(payload.Items))
+	logger.Infof("收到服务监控配置，总计 %d 个监控项，立即执行检测", len(payload.Items))
 
 	// 立即执行一次监控检测
 	if err := manager.CollectAndSendMonitor(conn, payload.Items); err != nil {
-		log.Printf("⚠️  监控检测失败: %v", err)
+		logger.Warnf("监控检测失败: %v", err)
 	} else {
-		log.Printf("✅ 服务监控检测完成，已上报 %d 个监控项结果", len(payload.Items))
+		logger.Infof("服务监控检测完成，已上报 %d 个监控项结果", len(payload.Items))
+	}
+}
+
+// handlePingConfig 处理 Ping 目标配置（连接建立时及目标变更时下发）
+func (a *Agent) handlePingConfig(data json.RawMessage) {
+	var payload protocol.PingConfigPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		pingLogger.Warnf("解析Ping配置失败: %v", err)
+		return
+	}
+
+	a.pingMu.Lock()
+	a.pingConfig = payload
+	a.pingMu.Unlock()
+
+	pingLogger.Infof("收到Ping配置，总计 %d 个目标", len(payload.Targets))
+
+	// 通知 pingLoop 重新加载配置
+	select {
+	case a.pingUpdated <- struct{}{}:
+	default:
+	}
+}
+
+// handleCollectorConfig 处理采集器配置（连接建立时及配置变更时下发）
+func (a *Agent) handleCollectorConfig(data json.RawMessage) {
+	var payload protocol.CollectorConfigPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		logger.Warnf("解析采集器配置失败: %v", err)
+		return
+	}
+
+	// 每次连接都会创建新的采集器管理器，服务端在连接建立时会重新下发，因此无需在 Agent 上保存
+	if manager := a.getCollectorManager(); manager != nil {
+		manager.ApplyConfig(payload)
+	}
+	logger.Infof("收到采集器配置，每核 CPU 采集: %v，单独配置采集间隔的指标: %d 类", payload.PerCoreCPU, len(payload.Intervals))
+
+	// 采集间隔可能在 metricsLoop 启动前到达，保存后通知 metricsLoop 重新加载
+	a.intervalMu.Lock()
+	a.metricIntervals = payload.Intervals
+	a.intervalMu.Unlock()
+	select {
+	case a.intervalsUpdated <- struct{}{}:
+	default:
+	}
+}
+
+func (a *Agent) getMetricIntervals() map[string]int {
+	a.intervalMu.RLock()
+	defer a.intervalMu.RUnlock()
+	return a.metricIntervals
+}
+
+func (a *Agent) getPingConfig() protocol.PingConfigPayload {
+	a.pingMu.RLock()
+	defer a.pingMu.RUnlock()
+	return a.pingConfig
+}
+
+// pingLoop Ping 目标检测循环，配置变更后立即检测一次并按新的间隔继续
+func (a *Agent) pingLoop(ctx context.Context, conn *safeConn, manager *collector.Manager, done chan struct{}) {
+	interval := func() time.Duration {
+		if seconds := a.getPingConfig().Interval; seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		return time.Minute
+	}
+
+	ticker := time.NewTicker(interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.pingUpdated:
+			ticker.Reset(interval())
+		case <-ticker.C:
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		}
+
+		if err := manager.CollectAndSendPing(conn, a.getPingConfig().Targets); err != nil {
+			pingLogger.Warnf("发送Ping数据失败: %v", err)
+		}
 	}
 }
 
@@ -434,22 +647,39 @@ func (a *Agent) getCollectorManager() *collector.Manager {
 
 // metricsLoop 指标采集循环
 func (a *Agent) metricsLoop(ctx context.Context, conn *safeConn, manager *collector.Manager, done chan struct{}) error {
+	// 服务端支持时，每轮采集的指标合并为一条消息发送
+	var writer collector.WebSocketWriter = conn
+	if conn.batch {
+		writer = collector.NewBatchWriter(conn)
+	}
+
+	// 各类指标按服务端下发的间隔采集，未下发时都使用探针配置的采集间隔
+	schedule := newMetricSchedule(a.cfg.GetCollectorInterval(), a.cfg.IsLite())
+	schedule.setIntervals(a.getMetricIntervals())
+
 	// 立即采集一次动态数据
-	if err := a.collectAndSendAllMetrics(conn, manager); err != nil {
-		log.Printf("⚠️  初始数据采集失败: %v", err)
+	if err := a.collectAndSendAllMetrics(writer, manager, schedule); err != nil {
+		logger.Warnf("初始数据采集失败: %v", err)
 	}
+	// 新连接立即上报一次采集器状态
+	var healthReportedAt time.Time
+	a.reportCollectorHealth(conn, manager, &healthReportedAt)
 
 	// 定时采集动态指标
-	ticker := time.NewTicker(a.cfg.GetCollectorInterval())
+	ticker := time.NewTicker(schedule.tick())
 	defer ticker.Stop()
 
 	for {
 		select {
+		case <-a.intervalsUpdated:
+			schedule.setIntervals(a.getMetricIntervals())
+			ticker.Reset(schedule.tick())
 		case <-ticker.C:
-			// 采集并发送各种动态指标
-			if err := a.collectAndSendAllMetrics(conn, manager); err != nil {
+			// 采集并发送本轮到期的动态指标
+			if err := a.collectAndSendAllMetrics(writer, manager, schedule); err != nil {
 				return fmt.Errorf("数据采集失败: %w", err)
 			}
+			a.reportCollectorHealth(conn, manager, &healthReportedAt)
 		case <-done:
 			return nil
 		case <-ctx.Done():
@@ -458,60 +688,85 @@ func (a *Agent) metricsLoop(ctx context.Context, conn *safeConn, manager *collec
 	}
 }
 
-// collectAndSendAllMetrics 采集并发送所有动态指标
-func (a *Agent) collectAndSendAllMetrics(conn *safeConn, manager *collector.Manager) error {
-	var hasError bool
-
-	// CPU 动态指标
-	if err := manager.CollectAndSendCPU(conn); err != nil {
-		log.Printf("⚠️  发送CPU指标失败: %v", err)
-		hasError = true
-	}
-
-	// 内存动态指标
-	if err := manager.CollectAndSendMemory(conn); err != nil {
-		log.Printf("⚠️  发送内存指标失败: %v", err)
-		hasError = true
+// softwareLoop 软件清单上报循环（软件版本变化不频繁，每小时上报一次）
+func (a *Agent) softwareLoop(ctx context.Context, conn *safeConn, manager *collector.Manager, done chan struct{}) {
+	if err := manager.CollectAndSendSoftware(conn); err != nil {
+		logger.Warnf("发送软件清单失败: %v", err)
 	}
 
-	// 磁盘指标
-	if err := manager.CollectAndSendDisk(conn); err != nil {
-		log.Printf("⚠️  发送磁盘指标失败: %v", err)
-		hasError = true
-	}
-
-	// 磁盘 IO 指标
-	if err := manager.CollectAndSendDiskIO(conn); err != nil {
-		log.Printf("⚠️  发送磁盘IO指标失败: %v", err)
-		hasError = true
-	}
-
-	// 网络指标
-	if err := manager.CollectAndSendNetwork(conn); err != nil {
-		log.Printf("⚠️  发送网络指标失败: %v", err)
-		hasError = true
-	}
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
 
-	// 网络连接统计
-	if err := manager.CollectAndSendNetworkConnection(conn); err != nil {
-		log.Printf("⚠️  发送网络连接统计失败: %v", err)
-		hasError = true
+	for {
+		select {
+		case <-ticker.C:
+			if err := manager.CollectAndSendSoftware(conn); err != nil {
+				logger.Warnf("发送软件清单失败: %v", err)
+			}
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		}
 	}
+}
 
-	// 主机信息
-	if err := manager.CollectAndSendHost(conn); err != nil {
-		log.Printf("⚠️  发送主机信息失败: %v", err)
-		hasError = true
-	}
+// collectAndSendAllMetrics 采集并发送本轮到期的动态指标，每个采集器由看门狗监督，卡住的采集器不会阻塞其他指标
+func (a *Agent) collectAndSendAllMetrics(conn collector.WebSocketWriter, manager *collector.Manager, schedule *metricSchedule) error {
+	var hasError bool
+	now := time.Now()
 
-	// GPU 信息（可选）
-	if err := manager.CollectAndSendGPU(conn); err != nil {
-		log.Printf("ℹ️  发送GPU信息失败: %v", err)
+	// run 执行一次采集，未到采集间隔的跳过，optional 为 true 的采集器失败时只记录调试日志
+	run := func(name, desc string, optional bool, fn func(collector.WebSocketWriter) error) {
+		if !schedule.due(name, now) {
+			return
+		}
+		err := a.watchdog.Run(name, func() error { return fn(conn) })
+		switch {
+		case err == nil:
+		case errors.Is(err, collector.ErrCollectorTimeout):
+			logger.Warnf("%s采集超时，已跳过: %v", desc, err)
+		case errors.Is(err, collector.ErrCollectorStuck):
+			logger.Debugf("%s采集仍未返回，本次跳过", desc)
+		case optional:
+			logger.Debugf("发送%s失败: %v", desc, err)
+		default:
+			logger.Warnf("发送%s失败: %v", desc, err)
+			hasError = true
+		}
 	}
 
-	// 温度信息（可选）
-	if err := manager.CollectAndSendTemperature(conn); err != nil {
-		log.Printf("ℹ️  发送温度信息失败: %v", err)
+	run("cpu", "CPU指标", false, manager.CollectAndSendCPU)
+	run("memory", "内存指标", false, manager.CollectAndSendMemory)
+	run("disk", "磁盘指标", false, manager.CollectAndSendDisk)
+	run("disk_io", "磁盘IO指标", false, manager.CollectAndSendDiskIO)
+	run("network", "网络指标", false, manager.CollectAndSendNetwork)
+	run("network_connection", "网络连接统计", false, manager.CollectAndSendNetworkConnection)
+	run("host", "主机信息", false, manager.CollectAndSendHost)
+	run("file_usage", "文件描述符和inode", false, manager.CollectAndSendFileUsage)
+	run("listening_port", "监听端口", false, manager.CollectAndSendListeningPort)
+	// WireGuard 隧道（可选）
+	run("wireguard", "WireGuard信息", true, manager.CollectAndSendWireGuard)
+	// 网络挂载点（可选，探测本身有超时，不会被看门狗判定为卡住）
+	run("mount", "挂载点状态", true, manager.CollectAndSendMount)
+	// 自定义指标（可选，命令按各自的间隔执行）
+	run("custom", "自定义指标", true, manager.CollectAndSendCustom)
+	// PSI 压力（可选，仅 Linux 4.20 及以上内核）
+	run("pressure", "PSI压力", true, manager.CollectAndSendPressure)
+	// 时钟偏差（可选，按 ntp_interval 间隔查询 NTP 服务器）
+	run("time_sync", "时钟偏差", true, manager.CollectAndSendTimeSync)
+
+	// 精简模式下不采集 GPU 和温度
+	if !a.cfg.IsLite() {
+		run("gpu", "GPU信息", true, manager.CollectAndSendGPU)
+		run("temperature", "温度信息", true, manager.CollectAndSendTemperature)
+	}
+
+	if batch, ok := conn.(*collector.BatchWriter); ok {
+		if err := batch.Flush(); err != nil {
+			logger.Warnf("发送合并指标失败: %v", err)
+			hasError = true
+		}
 	}
 
 	if hasError {
@@ -521,15 +776,28 @@ func (a *Agent) collectAndSendAllMetrics(conn *safeConn, manager *collector.Mana
 	return nil
 }
 
+// reportCollectorHealth 上报采集器健康状态，状态变化时立即上报，否则按固定间隔上报
+func (a *Agent) reportCollectorHealth(conn *safeConn, manager *collector.Manager, lastReport *time.Time) {
+	health, changed := a.watchdog.Health()
+	if len(health) == 0 || (!changed && time.Since(*lastReport) < collectorHealthReportInterval) {
+		return
+	}
+	if err := manager.SendCollectorHealth(conn, health); err != nil {
+		logger.Warnf("发送采集器健康状态失败: %v", err)
+		return
+	}
+	*lastReport = time.Now()
+}
+
 // handleCommand 处理服务端下发的指令
 func (a *Agent) handleCommand(data json.RawMessage) {
 	var cmdReq protocol.CommandRequest
 	if err := json.Unmarshal(data, &cmdReq); err != nil {
-		log.Printf("⚠️  解析指令失败: %v", err)
+		logger.Warnf("解析指令失败: %v", err)
 		return
 	}
 
-	log.Printf("📥 收到指令: %s (ID: %s)", cmdReq.Type, cmdReq.ID)
+	logger.Infof("收到指令: %s (ID: %s)", cmdReq.Type, cmdReq.ID)
 
 	conn := a.getActiveConn()
 	// 发送运行中状态
@@ -538,18 +806,46 @@ func (a *Agent) handleCommand(data json.RawMessage) {
 	switch cmdReq.Type {
 	case "vps_audit":
 		a.handleVPSAudit(conn, cmdReq.ID)
+	case "log_tail":
+		a.handleLogTail(conn, cmdReq.ID, cmdReq.Args)
+	case "remediation":
+		a.handleRemediation(conn, cmdReq.ID, cmdReq.Args)
+	case "log_level":
+		a.handleLogLevel(conn, cmdReq.ID, cmdReq.Args)
+	case "reboot":
+		a.handleReboot(conn, cmdReq.ID, cmdReq.Args)
+	case "wol":
+		a.handleWakeOnLAN(conn, cmdReq.ID, cmdReq.Args)
+	case "disk_usage":
+		a.handleDiskUsage(conn, cmdReq.ID, cmdReq.Args)
+	case "terminal":
+		a.handleTerminal(conn, cmdReq.ID, cmdReq.Args)
+	case "remote_command":
+		a.handleRemoteCommand(conn, cmdReq.ID, cmdReq.Args)
+	case "process_list":
+		a.handleProcessList(conn, cmdReq.ID, cmdReq.Args)
+	case "process_kill":
+		a.handleProcessKill(conn, cmdReq.ID, cmdReq.Args)
+	case "docker_list":
+		a.handleDockerList(conn, cmdReq.ID)
+	case "docker_action":
+		a.handleDockerAction(conn, cmdReq.ID, cmdReq.Args)
 	default:
-		log.Printf("⚠️  未知指令类型: %s", cmdReq.Type)
+		logger.Warnf("未知指令类型: %s", cmdReq.Type)
 		a.sendCommandResponse(conn, cmdReq.ID, cmdReq.Type, "error", "未知指令类型", "")
 	}
 }
 
 // handleVPSAudit 处理VPS安全审计指令
 func (a *Agent) handleVPSAudit(conn *safeConn, cmdID string) {
-	// 导入 audit 包
+	if a.cfg.IsLite() {
+		a.sendCommandResponse(conn, cmdID, "vps_audit", "error", "精简模式不支持安全审计", "")
+		return
+	}
+
 	result, err := a.runVPSAudit()
 	if err != nil {
-		log.Printf("❌ VPS安全审计失败: %v", err)
+		logger.Errorf("VPS安全审计失败: %v", err)
 		a.sendCommandResponse(conn, cmdID, "vps_audit", "error", err.Error(), "")
 		return
 	}
@@ -557,20 +853,15 @@ func (a *Agent) handleVPSAudit(conn *safeConn, cmdID string) {
 	// 将结果序列化为JSON
 	resultJSON, err := json.Marshal(result)
 	if err != nil {
-		log.Printf("❌ 序列化审计结果失败: %v", err)
+		logger.Errorf("序列化审计结果失败: %v", err)
 		a.sendCommandResponse(conn, cmdID, "vps_audit", "error", "序列化结果失败", "")
 		return
 	}
 
-	log.Println("✅ VPS安全审计完成")
+	logger.Info("VPS安全审计完成")
 	a.sendCommandResponse(conn, cmdID, "vps_audit", "success", "", string(resultJSON))
 }
 
-// runVPSAudit 运行VPS安全审计
-func (a *Agent) runVPSAudit() (*protocol.VPSAuditResult, error) {
-	return audit.RunAudit()
-}
-
 // sendCommandResponse 发送指令响应
 func (a *Agent) sendCommandResponse(conn *safeConn, cmdID, cmdType, status, errMsg, result string) {
 	resp := protocol.CommandResponse{
@@ -583,7 +874,7 @@ func (a *Agent) sendCommandResponse(conn *safeConn, cmdID, cmdType, status, errM
 
 	respData, err := json.Marshal(resp)
 	if err != nil {
-		log.Printf("⚠️  序列化指令响应失败: %v", err)
+		logger.Warnf("序列化指令响应失败: %v", err)
 		return
 	}
 
@@ -594,12 +885,12 @@ func (a *Agent) sendCommandResponse(conn *safeConn, cmdID, cmdType, status, errM
 
 	msgData, err := json.Marshal(msg)
 	if err != nil {
-		log.Printf("⚠️  序列化消息失败: %v", err)
+		logger.Warnf("序列化消息失败: %v", err)
 		return
 	}
 
 	if err := conn.WriteMessage(websocket.TextMessage, msgData); err != nil {
-		log.Printf("⚠️  发送指令响应失败: %v", err)
+		logger.Warnf("发送指令响应失败: %v", err)
 	}
 }
 
@@ -612,22 +903,22 @@ func GetVersion() string {
 func (a *Agent) handleTamperProtect(data json.RawMessage) {
 	var tamperProtectConfig protocol.TamperProtectConfig
 	if err := json.Unmarshal(data, &tamperProtectConfig); err != nil {
-		log.Printf("⚠️  解析防篡改保护配置失败: %v", err)
+		tamperLogger.Warnf("解析防篡改保护配置失败: %v", err)
 		a.sendTamperProtectResponse(false, "解析配置失败", nil, nil, nil, err.Error())
 		return
 	}
 
-	log.Printf("📥 收到防篡改保护增量配置: Added=%v, Removed=%v", tamperProtectConfig.Added, tamperProtectConfig.Removed)
+	tamperLogger.Infof("收到防篡改保护增量配置: Added=%v, Removed=%v", tamperProtectConfig.Added, tamperProtectConfig.Removed)
 
 	conn := a.getActiveConn()
 	if conn == nil {
-		log.Println("⚠️  当前连接未就绪，无法执行防篡改保护")
+		tamperLogger.Warn("当前连接未就绪，无法执行防篡改保护")
 		return
 	}
 
 	// 如果没有新增也没有移除，不需要做任何操作
 	if len(tamperProtectConfig.Added) == 0 && len(tamperProtectConfig.Removed) == 0 {
-		log.Println("ℹ️  配置无变化，跳过更新")
+		tamperLogger.Debug("配置无变化，跳过更新")
 		a.sendTamperProtectResponse(true, "配置无变化", a.tamperProtector.GetProtectedPaths(), []string{}, []string{}, "")
 		return
 	}
@@ -637,7 +928,7 @@ func (a *Agent) handleTamperProtect(data json.RawMessage) {
 	// 应用增量更新
 	result, err := a.tamperProtector.ApplyIncrementalUpdate(ctx, tamperProtectConfig.Added, tamperProtectConfig.Removed)
 	if err != nil {
-		log.Printf("⚠️  应用增量更新失败: %v", err)
+		tamperLogger.Warnf("应用增量更新失败: %v", err)
 		// 即使有错误也返回部分成功的结果
 		if result != nil {
 			a.sendTamperProtectResponse(false, "部分更新失败", result.Current, result.Added, result.Removed, err.Error())
@@ -650,7 +941,7 @@ func (a *Agent) handleTamperProtect(data json.RawMessage) {
 	// 成功更新
 	message := fmt.Sprintf("防篡改保护已更新: 新增 %d 个, 移除 %d 个, 当前保护 %d 个目录",
 		len(result.Added), len(result.Removed), len(result.Current))
-	log.Printf("✅ %s", message)
+	tamperLogger.Info(message)
 	a.sendTamperProtectResponse(true, message, result.Current, result.Added, result.Removed, "")
 }
 
@@ -672,7 +963,7 @@ func (a *Agent) sendTamperProtectResponse(success bool, message string, paths []
 
 	respData, err := json.Marshal(resp)
 	if err != nil {
-		log.Printf("⚠️  序列化防篡改保护响应失败: %v", err)
+		tamperLogger.Warnf("序列化防篡改保护响应失败: %v", err)
 		return
 	}
 
@@ -682,7 +973,7 @@ func (a *Agent) sendTamperProtectResponse(success bool, message string, paths []
 	}
 
 	if err := conn.WriteJSON(msg); err != nil {
-		log.Printf("⚠️  发送防篡改保护响应失败: %v", err)
+		tamperLogger.Warnf("发送防篡改保护响应失败: %v", err)
 	}
 }
 
@@ -707,7 +998,7 @@ func (a *Agent) tamperEventLoop(ctx context.Context, conn *safeConn, done chan s
 
 			data, err := json.Marshal(eventData)
 			if err != nil {
-				log.Printf("⚠️  序列化防篡改事件失败: %v", err)
+				tamperLogger.Warnf("序列化防篡改事件失败: %v", err)
 				continue
 			}
 
@@ -717,9 +1008,61 @@ func (a *Agent) tamperEventLoop(ctx context.Context, conn *safeConn, done chan s
 			}
 
 			if err := conn.WriteJSON(msg); err != nil {
-				log.Printf("⚠️  发送防篡改事件失败: %v", err)
+				tamperLogger.Warnf("发送防篡改事件失败: %v", err)
+			} else {
+				tamperLogger.Infof("已上报防篡改事件: %s - %s", event.Path, event.Operation)
+			}
+		}
+	}
+}
+
+// watchKernelLog 监视内核日志，读取失败时（如 journalctl 被重启）等待一段时间后重试
+func (a *Agent) watchKernelLog(ctx context.Context) {
+	watcher := collector.NewKernelLogWatcher()
+	emit := func(event protocol.KernelEventData) {
+		select {
+		case a.kernelEvents <- event:
+		default:
+			kernelLogger.Warnf("内核事件队列已满，丢弃事件: %s", event.Message)
+		}
+	}
+
+	for {
+		if err := watcher.Run(ctx, emit); err != nil {
+			kernelLogger.Warnf("内核日志监视失败: %v，将在 1 分钟后重试", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Minute):
+		}
+	}
+}
+
+// kernelEventLoop 内核日志事件上报循环
+func (a *Agent) kernelEventLoop(ctx context.Context, conn *safeConn, done chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		case event := <-a.kernelEvents:
+			data, err := json.Marshal(event)
+			if err != nil {
+				kernelLogger.Warnf("序列化内核事件失败: %v", err)
+				continue
+			}
+
+			msg := protocol.Message{
+				Type: protocol.MessageTypeKernelEvent,
+				Data: data,
+			}
+
+			if err := conn.WriteJSON(msg); err != nil {
+				kernelLogger.Warnf("发送内核事件失败: %v", err)
 			} else {
-				log.Printf("📤 已上报防篡改事件: %s - %s", event.Path, event.Operation)
+				kernelLogger.Infof("已上报内核事件: [%s] %s", event.Category, event.Message)
 			}
 		}
 	}
@@ -746,7 +1089,7 @@ func (a *Agent) tamperAlertLoop(ctx context.Context, conn *safeConn, done chan s
 
 			data, err := json.Marshal(alertData)
 			if err != nil {
-				log.Printf("⚠️  序列化属性篡改告警失败: %v", err)
+				tamperLogger.Warnf("序列化属性篡改告警失败: %v", err)
 				continue
 			}
 
@@ -756,13 +1099,13 @@ func (a *Agent) tamperAlertLoop(ctx context.Context, conn *safeConn, done chan s
 			}
 
 			if err := conn.WriteJSON(msg); err != nil {
-				log.Printf("⚠️  发送属性篡改告警失败: %v", err)
+				tamperLogger.Warnf("发送属性篡改告警失败: %v", err)
 			} else {
 				status := "未恢复"
 				if alert.Restored {
 					status = "已恢复"
 				}
-				log.Printf("📤 已上报属性篡改告警: %s - %s", alert.Path, status)
+				tamperLogger.Infof("已上报属性篡改告警: %s - %s", alert.Path, status)
 			}
 		}
 	}
@@ -772,29 +1115,29 @@ func (a *Agent) tamperAlertLoop(ctx context.Context, conn *safeConn, done chan s
 func (a *Agent) handleDDNSConfig(data json.RawMessage) {
 	var ddnsConfig protocol.DDNSConfigData
 	if err := json.Unmarshal(data, &ddnsConfig); err != nil {
-		log.Printf("⚠️  解析 DDNS 配置失败: %v", err)
+		ddnsLogger.Warnf("解析 DDNS 配置失败: %v", err)
 		return
 	}
 
 	if !ddnsConfig.Enabled {
-		log.Println("ℹ️  DDNS 已禁用，跳过 IP 检查")
+		ddnsLogger.Debug("DDNS 已禁用，跳过 IP 检查")
 		return
 	}
 
 	conn := a.getActiveConn()
 	manager := a.getCollectorManager()
 	if conn == nil || manager == nil {
-		log.Println("⚠️  当前连接未就绪，无法执行 DDNS IP 检查")
+		ddnsLogger.Warn("当前连接未就绪，无法执行 DDNS IP 检查")
 		return
 	}
 
-	log.Println("📥 收到 DDNS 配置检查请求，开始采集 IP 地址")
+	ddnsLogger.Info("收到 DDNS 配置检查请求，开始采集 IP 地址")
 
 	// 采集 IP 地址并上报
 	if err := a.collectAndSendDDNSIP(conn, manager, &ddnsConfig); err != nil {
-		log.Printf("⚠️  DDNS IP 采集失败: %v", err)
+		ddnsLogger.Warnf("DDNS IP 采集失败: %v", err)
 	} else {
-		log.Println("✅ DDNS IP 地址已上报")
+		ddnsLogger.Info("DDNS IP 地址已上报")
 	}
 }
 
@@ -806,10 +1149,10 @@ func (a *Agent) collectAndSendDDNSIP(conn *safeConn, manager *collector.Manager,
 	if config.EnableIPv4 {
 		ipv4, err := a.getIPAddress(manager, config.IPv4GetMethod, config.IPv4GetValue, false)
 		if err != nil {
-			log.Printf("⚠️  获取 IPv4 失败: %v", err)
+			ddnsLogger.Warnf("获取 IPv4 失败: %v", err)
 		} else {
 			ipReport.IPv4 = ipv4
-			log.Printf("✅ 获取 IPv4: %s", ipv4)
+			ddnsLogger.Infof("获取 IPv4: %s", ipv4)
 		}
 	}
 
@@ -817,10 +1160,10 @@ func (a *Agent) collectAndSendDDNSIP(conn *safeConn, manager *collector.Manager,
 	if config.EnableIPv6 {
 		ipv6, err := a.getIPAddress(manager, config.IPv6GetMethod, config.IPv6GetValue, true)
 		if err != nil {
-			log.Printf("⚠️  获取 IPv6 失败: %v", err)
+			ddnsLogger.Warnf("获取 IPv6 失败: %v", err)
 		} else {
 			ipReport.IPv6 = ipv6
-			log.Printf("✅ 获取 IPv6: %s", ipv6)
+			ddnsLogger.Infof("获取 IPv6: %s", ipv6)
 		}
 	}
 
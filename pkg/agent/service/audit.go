@@ -0,0 +1,13 @@
+//go:build !lite
+
+package service
+
+import (
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/pkg/agent/audit"
+)
+
+// runVPSAudit 运行VPS安全审计
+func (a *Agent) runVPSAudit() (*protocol.VPSAuditResult, error) {
+	return audit.RunAudit()
+}
@@ -0,0 +1,14 @@
+//go:build lite
+
+package service
+
+import (
+	"errors"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+// runVPSAudit 精简版本不编译安全审计模块，以减小程序体积
+func (a *Agent) runVPSAudit() (*protocol.VPSAuditResult, error) {
+	return nil, errors.New("精简版本不支持安全审计")
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/pkg/agent/config"
+)
+
+// remoteCommand 注册的远程命令
+type remoteCommand struct {
+	// resolve 校验目标是否在探针白名单中，返回实际执行的目标（如脚本路径）
+	resolve func(cfg *config.CommandsConfig, target string) (string, error)
+	// run 执行命令，超时后 ctx 被取消；exitCode 只对脚本有意义
+	run func(ctx context.Context, target string) (output string, exitCode int, err error)
+}
+
+var remoteCommands = make(map[string]remoteCommand)
+
+// registerRemoteCommand 注册远程命令，新增命令只需提供白名单校验和执行逻辑
+func registerRemoteCommand(name string, cmd remoteCommand) {
+	remoteCommands[name] = cmd
+}
+
+func init() {
+	registerRemoteCommand(protocol.RemoteCommandRestartService, remoteCommand{
+		resolve: func(cfg *config.CommandsConfig, target string) (string, error) {
+			if !slices.Contains(cfg.Services, target) {
+				return "", fmt.Errorf("服务不在白名单中: %s", target)
+			}
+			return target, nil
+		},
+		run: func(ctx context.Context, unit string) (string, int, error) {
+			output, err := restartService(ctx, unit)
+			return output, 0, err
+		},
+	})
+
+	registerRemoteCommand(protocol.RemoteCommandClearCache, remoteCommand{
+		resolve: func(cfg *config.CommandsConfig, target string) (string, error) {
+			dir, ok := allowedDir(cfg.CacheDirs, target)
+			if !ok {
+				return "", fmt.Errorf("目录不在白名单中: %s", target)
+			}
+			return dir, nil
+		},
+		run: func(ctx context.Context, dir string) (string, int, error) {
+			output, err := cleanDir(ctx, dir)
+			return output, 0, err
+		},
+	})
+
+	registerRemoteCommand(protocol.RemoteCommandRunScript, remoteCommand{
+		resolve: func(cfg *config.CommandsConfig, target string) (string, error) {
+			path, ok := cfg.Scripts[target]
+			if !ok {
+				return "", fmt.Errorf("脚本不在白名单中: %s", target)
+			}
+			if !filepath.IsAbs(path) {
+				return "", fmt.Errorf("脚本路径需为绝对路径: %s", path)
+			}
+			return path, nil
+		},
+		run: runScript,
+	})
+}
+
+// handleRemoteCommand 处理远程命令指令，只执行注册且在白名单内的命令
+func (a *Agent) handleRemoteCommand(conn *safeConn, cmdID, args string) {
+	var req protocol.RemoteCommandRequest
+	if err := json.Unmarshal([]byte(args), &req); err != nil {
+		a.sendCommandResponse(conn, cmdID, "remote_command", "error", "解析远程命令参数失败", "")
+		return
+	}
+
+	commandLogger.Infof("执行远程命令: %s %s (ID: %s)", req.Name, req.Target, cmdID)
+
+	start := time.Now()
+	output, exitCode, err := a.runRemoteCommand(req)
+	result := protocol.RemoteCommandResult{
+		Output:   output,
+		ExitCode: exitCode,
+		Duration: time.Since(start).Milliseconds(),
+	}
+	resultJSON, _ := json.Marshal(result)
+	if err != nil {
+		commandLogger.Errorf("远程命令执行失败: %s %s: %v", req.Name, req.Target, err)
+		a.sendCommandResponse(conn, cmdID, "remote_command", "error", err.Error(), string(resultJSON))
+		return
+	}
+	commandLogger.Infof("远程命令执行完成: %s %s", req.Name, req.Target)
+	a.sendCommandResponse(conn, cmdID, "remote_command", "success", "", string(resultJSON))
+}
+
+// runRemoteCommand 校验白名单并在超时时间内执行远程命令
+func (a *Agent) runRemoteCommand(req protocol.RemoteCommandRequest) (string, int, error) {
+	cfg := &a.cfg.Commands
+	if !cfg.Enabled {
+		return "", 0, fmt.Errorf("探针未开启远程命令")
+	}
+	cmd, ok := remoteCommands[req.Name]
+	if !ok {
+		return "", 0, fmt.Errorf("不支持的远程命令: %s", req.Name)
+	}
+	target, err := cmd.resolve(cfg, req.Target)
+	if err != nil {
+		return "", 0, err
+	}
+
+	timeout := time.Duration(protocol.ClampRemoteCommandTimeout(req.Timeout)) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	output, exitCode, err := cmd.run(ctx, target)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("执行超过 %s 未完成，已终止: %w", timeout, err)
+	}
+	return output, exitCode, err
+}
+
+// runScript 执行白名单内的脚本，合并标准输出和标准错误，超出上限的输出丢弃
+func runScript(ctx context.Context, path string) (string, int, error) {
+	output := &limitedBuffer{limit: protocol.RemoteCommandMaxOutput}
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Dir = filepath.Dir(path)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	// 脚本被终止后，其启动的子进程可能仍持有输出管道，超过该时间不再等待
+	cmd.WaitDelay = 5 * time.Second
+
+	err := cmd.Run()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return output.String(), exitErr.ExitCode(), fmt.Errorf("脚本退出码 %d", exitErr.ExitCode())
+		}
+		return output.String(), -1, fmt.Errorf("执行脚本失败: %w", err)
+	}
+	return output.String(), 0, nil
+}
+
+// limitedBuffer 只保留前 limit 字节的输出
+type limitedBuffer struct {
+	mu        sync.Mutex
+	limit     int
+	buf       []byte
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if remain := b.limit - len(b.buf); remain < len(p) {
+		b.buf = append(b.buf, p[:max(remain, 0)]...)
+		b.truncated = true
+	} else {
+		b.buf = append(b.buf, p...)
+	}
+	// 丢弃的输出也视为写入成功，避免脚本因管道写入失败而退出
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.truncated {
+		return string(b.buf) + "\n... 输出过长，已截断"
+	}
+	return string(b.buf)
+}
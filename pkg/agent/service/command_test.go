@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/pkg/agent/config"
+)
+
+func TestRemoteCommandResolve(t *testing.T) {
+	cfg := &config.CommandsConfig{
+		Services:  []string{"nginx"},
+		CacheDirs: []string{"/var/cache/app/"},
+		Scripts:   map[string]string{"backup": "/opt/scripts/backup.sh", "relative": "scripts/a.sh"},
+	}
+	tests := []struct {
+		name    string
+		command string
+		target  string
+		want    string
+		wantErr bool
+	}{
+		{"白名单内的服务", protocol.RemoteCommandRestartService, "nginx", "nginx", false},
+		{"白名单外的服务", protocol.RemoteCommandRestartService, "sshd", "", true},
+		{"白名单内的目录", protocol.RemoteCommandClearCache, "/var/cache/app", "/var/cache/app", false},
+		{"目录穿越", protocol.RemoteCommandClearCache, "/var/cache/app/..", "", true},
+		{"脚本名称", protocol.RemoteCommandRunScript, "backup", "/opt/scripts/backup.sh", false},
+		{"脚本路径不能直接执行", protocol.RemoteCommandRunScript, "/opt/scripts/backup.sh", "", true},
+		{"脚本需为绝对路径", protocol.RemoteCommandRunScript, "relative", "", true},
+	}
+	for _, tt := range tests {
+		got, err := remoteCommands[tt.command].resolve(cfg, tt.target)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("%s: resolve = %q, %v, 期望 %q, 错误 %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestRunScript(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows 不支持 shell 脚本")
+	}
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("#!/bin/sh\n"+content), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	output, exitCode, err := runScript(context.Background(), write("ok.sh", "echo hello\necho oops >&2\n"))
+	if err != nil || exitCode != 0 || output != "hello\noops\n" {
+		t.Fatalf("runScript = %q, %d, %v", output, exitCode, err)
+	}
+
+	output, exitCode, err = runScript(context.Background(), write("fail.sh", "echo failed\nexit 3\n"))
+	if err == nil || exitCode != 3 || output != "failed\n" {
+		t.Fatalf("runScript = %q, %d, %v, 期望退出码 3", output, exitCode, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, _, err = runScript(ctx, write("slow.sh", "exec sleep 10\n")); err == nil {
+		t.Fatalf("超时的脚本应返回错误")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("超时后未及时终止脚本: %s", elapsed)
+	}
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	b := &limitedBuffer{limit: 5}
+	if n, err := b.Write([]byte("abc")); n != 3 || err != nil {
+		t.Fatalf("Write = %d, %v", n, err)
+	}
+	if n, err := b.Write([]byte("defg")); n != 4 || err != nil {
+		t.Fatalf("超出上限的写入也应返回成功: %d, %v", n, err)
+	}
+	if got := b.String(); !strings.HasPrefix(got, "abcde\n") {
+		t.Fatalf("String = %q", got)
+	}
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"container/heap"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+const (
+	defaultDiskUsageDepth = 2
+	maxDiskUsageDepth     = 5
+	defaultDiskUsageLimit = 20
+	maxDiskUsageLimit     = 100
+	// diskUsageTimeout 单次扫描的最长时间，超时后返回已扫描部分的结果
+	diskUsageTimeout = 2 * time.Minute
+)
+
+var errDiskUsageTimeout = errors.New("disk usage scan timeout")
+
+// handleDiskUsage 处理磁盘占用分析指令，统计指定路径下占用最大的目录和文件
+func (a *Agent) handleDiskUsage(conn *safeConn, cmdID, args string) {
+	var req protocol.DiskUsageRequest
+	if err := json.Unmarshal([]byte(args), &req); err != nil {
+		a.sendCommandResponse(conn, cmdID, "disk_usage", "error", "解析磁盘占用分析参数失败", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), diskUsageTimeout)
+	defer cancel()
+
+	result, err := scanDiskUsage(ctx, req)
+	if err != nil {
+		logger.Warnf("磁盘占用分析失败: %v", err)
+		a.sendCommandResponse(conn, cmdID, "disk_usage", "error", err.Error(), "")
+		return
+	}
+
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		a.sendCommandResponse(conn, cmdID, "disk_usage", "error", "序列化结果失败", "")
+		return
+	}
+
+	logger.Infof("磁盘占用分析完成: %s, 文件 %d 个, 耗时 %dms", result.Path, result.FileCount, result.Duration)
+	a.sendCommandResponse(conn, cmdID, "disk_usage", "success", "", string(resultJSON))
+}
+
+// scanDiskUsage 遍历扫描路径，类似 du -x --max-depth：不跨越文件系统、不跟随符号链接，
+// 只统计不超过 Depth 层的目录占用，文件按大小保留前 Limit 个
+func scanDiskUsage(ctx context.Context, req protocol.DiskUsageRequest) (*protocol.DiskUsageResult, error) {
+	depth := req.Depth
+	if depth <= 0 {
+		depth = defaultDiskUsageDepth
+	}
+	if depth > maxDiskUsageDepth {
+		depth = maxDiskUsageDepth
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultDiskUsageLimit
+	}
+	if limit > maxDiskUsageLimit {
+		limit = maxDiskUsageLimit
+	}
+
+	root := filepath.Clean(req.Path)
+	if req.Path == "" || !filepath.IsAbs(root) {
+		return nil, errors.New("扫描路径必须为绝对路径")
+	}
+	rootInfo, err := os.Lstat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !rootInfo.IsDir() {
+		return nil, errors.New("扫描路径不是目录")
+	}
+	rootDevice, _ := fileUsage(rootInfo)
+
+	startedAt := time.Now()
+	result := &protocol.DiskUsageResult{Path: root}
+	dirSizes := make(map[string]int64)
+	files := &diskUsageHeap{}
+
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			result.Errors++
+			if d != nil && d.IsDir() && path != root {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if ctx.Err() != nil {
+			return errDiskUsageTimeout
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			result.Errors++
+			return nil
+		}
+		device, size := fileUsage(info)
+
+		if d.IsDir() {
+			if path != root && device != rootDevice {
+				// 不统计挂载在扫描路径下的其他文件系统（如 /proc、网络挂载）
+				return fs.SkipDir
+			}
+			result.DirCount++
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		result.FileCount++
+		result.TotalSize += size
+		addDirUsage(dirSizes, root, path, depth, size)
+
+		if files.Len() < limit {
+			heap.Push(files, protocol.DiskUsageEntry{Path: path, Size: size})
+		} else if size > (*files)[0].Size {
+			(*files)[0] = protocol.DiskUsageEntry{Path: path, Size: size}
+			heap.Fix(files, 0)
+		}
+		return nil
+	})
+	if errors.Is(err, errDiskUsageTimeout) {
+		result.Truncated = true
+	} else if err != nil {
+		return nil, err
+	}
+
+	result.Directories = make([]protocol.DiskUsageEntry, 0, len(dirSizes))
+	for path, size := range dirSizes {
+		result.Directories = append(result.Directories, protocol.DiskUsageEntry{Path: path, Size: size})
+	}
+	result.Directories = topDiskUsage(result.Directories, limit)
+	result.Files = topDiskUsage(*files, limit)
+	result.Duration = time.Since(startedAt).Milliseconds()
+	return result, nil
+}
+
+// addDirUsage 将文件大小累加到扫描路径下不超过 depth 层的各级上层目录
+func addDirUsage(dirSizes map[string]int64, root, path string, depth int, size int64) {
+	rel, err := filepath.Rel(root, filepath.Dir(path))
+	if err != nil || rel == "." {
+		return
+	}
+	parts := strings.Split(rel, string(filepath.Separator))
+	dir := root
+	for i := 0; i < len(parts) && i < depth; i++ {
+		dir = filepath.Join(dir, parts[i])
+		dirSizes[dir] += size
+	}
+}
+
+// topDiskUsage 按占用降序排列并保留前 limit 个
+func topDiskUsage(entries []protocol.DiskUsageEntry, limit int) []protocol.DiskUsageEntry {
+	sorted := make([]protocol.DiskUsageEntry, len(entries))
+	copy(sorted, entries)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Size > sorted[j].Size
+	})
+	if len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+	return sorted
+}
+
+// diskUsageHeap 按大小排列的小顶堆，用于保留最大的 N 个文件
+type diskUsageHeap []protocol.DiskUsageEntry
+
+func (h diskUsageHeap) Len() int           { return len(h) }
+func (h diskUsageHeap) Less(i, j int) bool { return h[i].Size < h[j].Size }
+func (h diskUsageHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *diskUsageHeap) Push(x any) {
+	*h = append(*h, x.(protocol.DiskUsageEntry))
+}
+
+func (h *diskUsageHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+
+package service
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// fileUsage 返回文件所在设备和实际占用的磁盘空间（按块计算，与 du 一致）
+func fileUsage(info fs.FileInfo) (uint64, int64) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, info.Size()
+	}
+	return uint64(stat.Dev), int64(stat.Blocks) * 512
+}
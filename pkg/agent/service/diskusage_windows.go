@@ -0,0 +1,10 @@
+//go:build windows
+
+package service
+
+import "io/fs"
+
+// fileUsage 返回文件所在设备和占用空间，Windows 下不区分设备，按文件大小计算
+func fileUsage(info fs.FileInfo) (uint64, int64) {
+	return 0, info.Size()
+}
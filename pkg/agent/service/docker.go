@@ -0,0 +1,116 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/pkg/agent/sysutil"
+)
+
+// dockerListTimeout 获取容器列表的超时时间
+const dockerListTimeout = 30 * time.Second
+
+// dockerContainerPattern 容器ID或名称，不允许以 - 开头，避免被 docker 解析为参数
+var dockerContainerPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,127}$`)
+
+// handleDockerList 处理获取容器列表指令
+func (a *Agent) handleDockerList(conn *safeConn, cmdID string) {
+	if !a.cfg.Docker.Enabled {
+		a.sendCommandResponse(conn, cmdID, "docker_list", "error", "探针未开启 Docker 容器管理", "")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dockerListTimeout)
+	defer cancel()
+	output, err := sysutil.RunDocker(ctx, "ps", "-a", "--no-trunc", "--format", "{{json .}}")
+	if err != nil {
+		dockerLogger.Warnf("获取容器列表失败: %v", err)
+		a.sendCommandResponse(conn, cmdID, "docker_list", "error", err.Error(), "")
+		return
+	}
+	containers, err := parseDockerContainers(output)
+	if err != nil {
+		a.sendCommandResponse(conn, cmdID, "docker_list", "error", err.Error(), "")
+		return
+	}
+
+	result, _ := json.Marshal(protocol.DockerListResult{Containers: containers})
+	a.sendCommandResponse(conn, cmdID, "docker_list", "success", "", string(result))
+}
+
+// handleDockerAction 处理启动、停止、重启容器指令
+func (a *Agent) handleDockerAction(conn *safeConn, cmdID, args string) {
+	var req protocol.DockerActionRequest
+	if err := json.Unmarshal([]byte(args), &req); err != nil {
+		a.sendCommandResponse(conn, cmdID, "docker_action", "error", "解析容器操作参数失败", "")
+		return
+	}
+
+	output, err := a.runDockerAction(req)
+	result, _ := json.Marshal(protocol.DockerActionResult{Output: output})
+	if err != nil {
+		dockerLogger.Warnf("容器操作失败: %s %s: %v", req.Action, req.Container, err)
+		a.sendCommandResponse(conn, cmdID, "docker_action", "error", err.Error(), string(result))
+		return
+	}
+	dockerLogger.Infof("容器操作完成: %s %s (ID: %s)", req.Action, req.Container, cmdID)
+	a.sendCommandResponse(conn, cmdID, "docker_action", "success", "", string(result))
+}
+
+// runDockerAction 校验参数后执行容器操作
+func (a *Agent) runDockerAction(req protocol.DockerActionRequest) (string, error) {
+	if !a.cfg.Docker.Enabled {
+		return "", fmt.Errorf("探针未开启 Docker 容器管理")
+	}
+	if !protocol.IsDockerAction(req.Action) {
+		return "", fmt.Errorf("不支持的容器操作: %s", req.Action)
+	}
+	if !dockerContainerPattern.MatchString(req.Container) {
+		return "", fmt.Errorf("容器ID或名称格式错误: %s", req.Container)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), protocol.DockerActionTimeout*time.Second)
+	defer cancel()
+	return sysutil.RunDocker(ctx, req.Action, req.Container)
+}
+
+// parseDockerContainers 解析 docker ps --format '{{json .}}' 的输出，每行一个容器
+func parseDockerContainers(output string) ([]protocol.DockerContainer, error) {
+	containers := make([]protocol.DockerContainer, 0)
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var item struct {
+			ID        string `json:"ID"`
+			Names     string `json:"Names"`
+			Image     string `json:"Image"`
+			State     string `json:"State"`
+			Status    string `json:"Status"`
+			Ports     string `json:"Ports"`
+			CreatedAt string `json:"CreatedAt"`
+		}
+		if err := json.Unmarshal([]byte(line), &item); err != nil {
+			return nil, fmt.Errorf("解析容器列表失败: %w", err)
+		}
+		containers = append(containers, protocol.DockerContainer{
+			ID:        item.ID,
+			Name:      item.Names,
+			Image:     item.Image,
+			State:     item.State,
+			Status:    item.Status,
+			Ports:     item.Ports,
+			CreatedAt: item.CreatedAt,
+		})
+	}
+	return containers, scanner.Err()
+}
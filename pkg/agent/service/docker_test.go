@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+)
+
+func TestParseDockerContainers(t *testing.T) {
+	output := `{"ID":"3f2a","Names":"nginx","Image":"nginx:1.27","State":"running","Status":"Up 2 hours","Ports":"0.0.0.0:80->80/tcp","CreatedAt":"2026-10-01 08:00:00 +0000 UTC"}
+
+{"ID":"9b1c","Names":"redis","Image":"redis:7","State":"exited","Status":"Exited (0) 3 days ago","Ports":"","CreatedAt":"2026-09-20 08:00:00 +0000 UTC"}
+`
+	containers, err := parseDockerContainers(output)
+	if err != nil {
+		t.Fatalf("parseDockerContainers 错误: %v", err)
+	}
+	if len(containers) != 2 {
+		t.Fatalf("容器数量 = %d, 期望 2", len(containers))
+	}
+	if c := containers[0]; c.ID != "3f2a" || c.Name != "nginx" || c.State != "running" || c.Ports != "0.0.0.0:80->80/tcp" {
+		t.Errorf("第一个容器解析错误: %+v", c)
+	}
+	if c := containers[1]; c.Name != "redis" || c.State != "exited" {
+		t.Errorf("第二个容器解析错误: %+v", c)
+	}
+
+	if containers, err := parseDockerContainers(""); err != nil || len(containers) != 0 {
+		t.Errorf("空输出应返回空列表: %v, %v", containers, err)
+	}
+	if _, err := parseDockerContainers("not json"); err == nil {
+		t.Errorf("格式错误的输出应返回错误")
+	}
+}
+
+func TestDockerContainerPattern(t *testing.T) {
+	tests := []struct {
+		container string
+		valid     bool
+	}{
+		{"nginx", true},
+		{"my_app.web-1", true},
+		{"3f2a9b1c", true},
+		{"", false},
+		{"-rm", false},
+		{"--help", false},
+		{"a b", false},
+		{"app;reboot", false},
+	}
+	for _, tt := range tests {
+		if got := dockerContainerPattern.MatchString(tt.container); got != tt.valid {
+			t.Errorf("%q: 匹配结果 = %v, 期望 %v", tt.container, got, tt.valid)
+		}
+	}
+}
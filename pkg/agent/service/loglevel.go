@@ -0,0 +1,35 @@
+package service
+
+import (
+	"encoding/json"
+
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/pkg/logging"
+)
+
+// handleLogLevel 处理日志级别调整指令，调整后返回当前的日志级别
+// 调整只在本次运行期间有效，重启后恢复为配置文件中的级别
+func (a *Agent) handleLogLevel(conn *safeConn, cmdID, args string) {
+	var req protocol.LogLevelRequest
+	if err := json.Unmarshal([]byte(args), &req); err != nil {
+		a.sendCommandResponse(conn, cmdID, "log_level", "error", "解析日志级别参数失败", "")
+		return
+	}
+
+	manager := logging.Default()
+	if req.Reset {
+		if req.Module == "" {
+			a.sendCommandResponse(conn, cmdID, "log_level", "error", "默认级别不支持重置", "")
+			return
+		}
+		manager.ResetLevel(req.Module)
+	} else if err := manager.SetLevel(req.Module, req.Level); err != nil {
+		a.sendCommandResponse(conn, cmdID, "log_level", "error", err.Error(), "")
+		return
+	}
+
+	logger.Infof("日志级别已调整: module=%s, level=%s, reset=%v", req.Module, req.Level, req.Reset)
+
+	resultJSON, _ := json.Marshal(manager.Levels())
+	a.sendCommandResponse(conn, cmdID, "log_level", "success", "", string(resultJSON))
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+const (
+	logTailFlushInterval = 500 * time.Millisecond // 日志片段的发送间隔
+	logTailChunkSize     = 16 * 1024              // 单个日志片段的最大字节数
+)
+
+// handleLogTail 处理日志查看指令，将白名单内的日志实时推送给服务端
+func (a *Agent) handleLogTail(conn *safeConn, cmdID, args string) {
+	var req protocol.LogTailRequest
+	if err := json.Unmarshal([]byte(args), &req); err != nil {
+		a.finishLogTail(conn, cmdID, fmt.Errorf("解析日志查看参数失败: %w", err))
+		return
+	}
+	normalizeLogTailRequest(&req)
+
+	name, cmdArgs, err := a.buildLogTailCommand(req)
+	if err != nil {
+		a.finishLogTail(conn, cmdID, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(req.Duration)*time.Second)
+	defer cancel()
+	a.registerLogTail(cmdID, cancel)
+	defer a.unregisterLogTail(cmdID)
+
+	cmd := exec.CommandContext(ctx, name, cmdArgs...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		a.finishLogTail(conn, cmdID, err)
+		return
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		a.finishLogTail(conn, cmdID, fmt.Errorf("启动日志查看失败: %w", err))
+		return
+	}
+
+	logTailLogger.Infof("开始查看日志: %s %s (ID: %s)", req.Source, req.Target, cmdID)
+
+	lines := make(chan string, a.cfg.GetLogTailBufferLines())
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 64*1024), 64*1024)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text() + "\n":
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(logTailFlushInterval)
+	defer ticker.Stop()
+
+	var buf strings.Builder
+	total := 0
+	flush := func() bool {
+		if buf.Len() == 0 {
+			return true
+		}
+		err := a.sendLogTailChunk(conn, protocol.LogTailChunk{ID: cmdID, Data: buf.String()})
+		buf.Reset()
+		return err == nil
+	}
+
+	truncated := false
+loop:
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				break loop
+			}
+			if total+len(line) > req.MaxBytes {
+				truncated = true
+				break loop
+			}
+			total += len(line)
+			buf.WriteString(line)
+			if buf.Len() >= logTailChunkSize && !flush() {
+				cancel()
+				break loop
+			}
+		case <-ticker.C:
+			if !flush() {
+				cancel()
+				break loop
+			}
+		}
+	}
+	flush()
+
+	stopped := ctx.Err() != nil
+	cancel()
+	waitErr := cmd.Wait()
+
+	// 超时、被停止或超出大小限制导致的退出属于正常结束
+	if waitErr != nil && !stopped && !truncated {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = waitErr.Error()
+		}
+		a.finishLogTail(conn, cmdID, fmt.Errorf("%s", msg))
+		return
+	}
+
+	if truncated {
+		logTailLogger.Infof("日志输出超过 %d 字节，已结束查看 (ID: %s)", req.MaxBytes, cmdID)
+	}
+	a.finishLogTail(conn, cmdID, nil)
+}
+
+// normalizeLogTailRequest 补全默认值并限制日志查看的行数、时长和大小
+func normalizeLogTailRequest(req *protocol.LogTailRequest) {
+	if req.Lines <= 0 {
+		req.Lines = 100
+	}
+	if req.Lines > protocol.LogTailMaxLines {
+		req.Lines = protocol.LogTailMaxLines
+	}
+	if req.Duration <= 0 || req.Duration > protocol.LogTailMaxDuration {
+		req.Duration = protocol.LogTailMaxDuration
+	}
+	if req.MaxBytes <= 0 || req.MaxBytes > protocol.LogTailMaxBytes {
+		req.MaxBytes = protocol.LogTailMaxBytes
+	}
+}
+
+// buildLogTailCommand 校验白名单并构建日志查看命令
+func (a *Agent) buildLogTailCommand(req protocol.LogTailRequest) (string, []string, error) {
+	cfg := a.cfg.LogTail
+	if !cfg.Enabled {
+		return "", nil, fmt.Errorf("探针未开启远程日志查看")
+	}
+	if runtime.GOOS == "windows" {
+		return "", nil, fmt.Errorf("当前系统不支持远程日志查看")
+	}
+
+	lines := strconv.Itoa(req.Lines)
+	switch req.Source {
+	case "file":
+		target := filepath.Clean(req.Target)
+		allowed := slices.ContainsFunc(cfg.Paths, func(p string) bool {
+			return filepath.Clean(p) == target
+		})
+		if !filepath.IsAbs(target) || !allowed {
+			return "", nil, fmt.Errorf("日志文件不在白名单中: %s", req.Target)
+		}
+		return "tail", []string{"-n", lines, "-F", target}, nil
+	case "journal":
+		if req.Target == "" || !slices.Contains(cfg.Units, req.Target) {
+			return "", nil, fmt.Errorf("服务不在白名单中: %s", req.Target)
+		}
+		return "journalctl", []string{"-u", req.Target, "-n", lines, "-f", "--no-pager", "-o", "short-iso"}, nil
+	default:
+		return "", nil, fmt.Errorf("不支持的日志来源: %s", req.Source)
+	}
+}
+
+// handleLogTailStop 处理服务端的停止日志查看请求
+func (a *Agent) handleLogTailStop(data json.RawMessage) {
+	var stop protocol.LogTailStop
+	if err := json.Unmarshal(data, &stop); err != nil {
+		logTailLogger.Warnf("解析停止日志查看请求失败: %v", err)
+		return
+	}
+
+	a.logTailMu.Lock()
+	cancel, ok := a.logTails[stop.ID]
+	a.logTailMu.Unlock()
+	if ok {
+		cancel()
+	}
+}
+
+func (a *Agent) registerLogTail(cmdID string, cancel context.CancelFunc) {
+	a.logTailMu.Lock()
+	defer a.logTailMu.Unlock()
+	a.logTails[cmdID] = cancel
+}
+
+func (a *Agent) unregisterLogTail(cmdID string) {
+	a.logTailMu.Lock()
+	defer a.logTailMu.Unlock()
+	delete(a.logTails, cmdID)
+}
+
+// finishLogTail 发送结束片段和指令响应
+func (a *Agent) finishLogTail(conn *safeConn, cmdID string, err error) {
+	chunk := protocol.LogTailChunk{ID: cmdID, Done: true}
+	if err != nil {
+		logTailLogger.Warnf("日志查看失败: %v", err)
+		chunk.Error = err.Error()
+	}
+	if sendErr := a.sendLogTailChunk(conn, chunk); sendErr != nil {
+		logTailLogger.Warnf("发送日志片段失败: %v", sendErr)
+	}
+
+	if err != nil {
+		a.sendCommandResponse(conn, cmdID, "log_tail", "error", err.Error(), "")
+		return
+	}
+	a.sendCommandResponse(conn, cmdID, "log_tail", "success", "", "")
+}
+
+// sendLogTailChunk 发送日志片段
+func (a *Agent) sendLogTailChunk(conn *safeConn, chunk protocol.LogTailChunk) error {
+	data, err := json.Marshal(chunk)
+	if err != nil {
+		return err
+	}
+	return conn.WriteJSON(protocol.Message{
+		Type: protocol.MessageTypeLogTailChunk,
+		Data: data,
+	})
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+const defaultWakeOnLANBroadcast = "255.255.255.255:9"
+
+// handleReboot 处理重启指令，先回复执行结果再延迟重启，保证响应能送达服务端
+func (a *Agent) handleReboot(conn *safeConn, cmdID, args string) {
+	if !a.cfg.Power.AllowReboot {
+		a.sendCommandResponse(conn, cmdID, "reboot", "error", "探针未允许远程重启", "")
+		return
+	}
+
+	var req protocol.RebootRequest
+	if args != "" {
+		if err := json.Unmarshal([]byte(args), &req); err != nil {
+			a.sendCommandResponse(conn, cmdID, "reboot", "error", "解析重启参数失败", "")
+			return
+		}
+	}
+	delay := time.Duration(req.DelaySeconds) * time.Second
+	if delay <= 0 {
+		delay = 5 * time.Second
+	}
+
+	cmd, err := rebootCommand()
+	if err != nil {
+		a.sendCommandResponse(conn, cmdID, "reboot", "error", err.Error(), "")
+		return
+	}
+
+	powerLogger.Warnf("收到重启指令，%s 后重启系统 (ID: %s)", delay, cmdID)
+	resultJSON, _ := json.Marshal(protocol.PowerResult{Output: fmt.Sprintf("系统将在 %s 后重启", delay)})
+	a.sendCommandResponse(conn, cmdID, "reboot", "success", "", string(resultJSON))
+
+	go func() {
+		time.Sleep(delay)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			powerLogger.Errorf("执行重启失败: %v, %s", err, string(output))
+		}
+	}()
+}
+
+// rebootCommand 返回当前系统的重启命令
+func rebootCommand() (*exec.Cmd, error) {
+	switch runtime.GOOS {
+	case "linux", "freebsd", "darwin":
+		return exec.Command("shutdown", "-r", "now"), nil
+	case "windows":
+		return exec.Command("shutdown", "/r", "/t", "0"), nil
+	default:
+		return nil, fmt.Errorf("当前系统不支持远程重启: %s", runtime.GOOS)
+	}
+}
+
+// handleWakeOnLAN 处理网络唤醒指令，向局域网广播魔术包
+func (a *Agent) handleWakeOnLAN(conn *safeConn, cmdID, args string) {
+	if !a.cfg.Power.AllowWakeOnLAN {
+		a.sendCommandResponse(conn, cmdID, "wol", "error", "探针未允许作为网络唤醒中继", "")
+		return
+	}
+
+	var req protocol.WakeOnLANRequest
+	if err := json.Unmarshal([]byte(args), &req); err != nil {
+		a.sendCommandResponse(conn, cmdID, "wol", "error", "解析网络唤醒参数失败", "")
+		return
+	}
+
+	broadcast := req.Broadcast
+	if broadcast == "" {
+		broadcast = defaultWakeOnLANBroadcast
+	}
+
+	if err := sendMagicPacket(req.MAC, broadcast); err != nil {
+		powerLogger.Errorf("发送网络唤醒魔术包失败: %v", err)
+		a.sendCommandResponse(conn, cmdID, "wol", "error", err.Error(), "")
+		return
+	}
+
+	powerLogger.Infof("已发送网络唤醒魔术包: %s -> %s", req.MAC, broadcast)
+	resultJSON, _ := json.Marshal(protocol.PowerResult{Output: fmt.Sprintf("已向 %s 发送 %s 的魔术包", broadcast, req.MAC)})
+	a.sendCommandResponse(conn, cmdID, "wol", "success", "", string(resultJSON))
+}
+
+// sendMagicPacket 发送魔术包：6 字节 0xFF 后接 16 次目标 MAC 地址
+func sendMagicPacket(mac, broadcast string) error {
+	hw, err := net.ParseMAC(mac)
+	if err != nil || len(hw) != 6 {
+		return fmt.Errorf("MAC 地址格式错误: %s", mac)
+	}
+
+	packet := bytes.Repeat([]byte{0xFF}, 6)
+	for i := 0; i < 16; i++ {
+		packet = append(packet, hw...)
+	}
+
+	addr, err := net.ResolveUDPAddr("udp4", broadcast)
+	if err != nil {
+		return fmt.Errorf("广播地址格式错误: %w", err)
+	}
+	udpConn, err := net.DialUDP("udp4", nil, addr)
+	if err != nil {
+		return err
+	}
+	defer udpConn.Close()
+
+	_, err = udpConn.Write(packet)
+	return err
+}
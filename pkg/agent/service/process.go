@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/shirou/gopsutil/v4/mem"
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// handleProcessList 处理进程列表指令，按间隔推送 CPU 和内存占用最高的进程，直到到期或服务端停止
+func (a *Agent) handleProcessList(conn *safeConn, cmdID, args string) {
+	var req protocol.ProcessListRequest
+	if err := json.Unmarshal([]byte(args), &req); err != nil {
+		a.finishProcessList(conn, cmdID, fmt.Errorf("解析进程列表参数失败: %w", err))
+		return
+	}
+	protocol.NormalizeProcessListRequest(&req)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(req.Duration)*time.Second)
+	defer cancel()
+	a.registerProcessList(cmdID, cancel)
+	defer a.unregisterProcessList(cmdID)
+
+	processLogger.Infof("开始推送进程列表 (ID: %s)", cmdID)
+
+	// 先采集一次作为计算 CPU 使用率的基准，1 秒后推送第一份快照
+	sampler := &processSampler{}
+	if _, _, err := sampler.sample(0); err != nil {
+		a.finishProcessList(conn, cmdID, err)
+		return
+	}
+	timer := time.NewTimer(time.Second)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			a.finishProcessList(conn, cmdID, nil)
+			return
+		case <-timer.C:
+			processes, total, err := sampler.sample(req.Limit)
+			if err != nil {
+				a.finishProcessList(conn, cmdID, err)
+				return
+			}
+			err = a.sendProcessSnapshot(conn, protocol.ProcessSnapshot{
+				ID:        cmdID,
+				Processes: processes,
+				Total:     total,
+				Timestamp: time.Now().UnixMilli(),
+			})
+			if err != nil {
+				processLogger.Warnf("发送进程列表失败: %v", err)
+				return
+			}
+			timer.Reset(time.Duration(req.Interval) * time.Second)
+		}
+	}
+}
+
+// handleProcessListStop 处理服务端的停止推送进程列表请求
+func (a *Agent) handleProcessListStop(data json.RawMessage) {
+	var stop protocol.ProcessListStop
+	if err := json.Unmarshal(data, &stop); err != nil {
+		processLogger.Warnf("解析停止进程列表请求失败: %v", err)
+		return
+	}
+
+	a.processListMu.Lock()
+	cancel, ok := a.processLists[stop.ID]
+	a.processListMu.Unlock()
+	if ok {
+		cancel()
+	}
+}
+
+func (a *Agent) registerProcessList(cmdID string, cancel context.CancelFunc) {
+	a.processListMu.Lock()
+	defer a.processListMu.Unlock()
+	a.processLists[cmdID] = cancel
+}
+
+func (a *Agent) unregisterProcessList(cmdID string) {
+	a.processListMu.Lock()
+	defer a.processListMu.Unlock()
+	delete(a.processLists, cmdID)
+}
+
+// finishProcessList 发送结束快照和指令响应
+func (a *Agent) finishProcessList(conn *safeConn, cmdID string, err error) {
+	snapshot := protocol.ProcessSnapshot{ID: cmdID, Done: true, Timestamp: time.Now().UnixMilli()}
+	if err != nil {
+		processLogger.Warnf("推送进程列表失败: %v", err)
+		snapshot.Error = err.Error()
+	}
+	if sendErr := a.sendProcessSnapshot(conn, snapshot); sendErr != nil {
+		processLogger.Warnf("发送进程列表失败: %v", sendErr)
+	}
+
+	if err != nil {
+		a.sendCommandResponse(conn, cmdID, "process_list", "error", err.Error(), "")
+		return
+	}
+	a.sendCommandResponse(conn, cmdID, "process_list", "success", "", "")
+}
+
+// sendProcessSnapshot 发送进程列表快照
+func (a *Agent) sendProcessSnapshot(conn *safeConn, snapshot protocol.ProcessSnapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	return conn.WriteJSON(protocol.Message{
+		Type: protocol.MessageTypeProcessSnapshot,
+		Data: data,
+	})
+}
+
+// processCPUTime 进程累计使用的 CPU 时间，用于计算两次采集之间的 CPU 使用率
+type processCPUTime struct {
+	createTime int64
+	total      float64 // 用户态和内核态时间之和（秒）
+}
+
+// processSampler 进程采集器，CPU 使用率为与上一次采集之间的平均值，与 top 一致，多核时可能超过 100%
+type processSampler struct {
+	last     map[int32]processCPUTime
+	lastTime time.Time
+}
+
+// sample 采集所有进程，返回 CPU 或内存占用最高的进程和进程总数
+func (s *processSampler) sample(limit int) ([]protocol.ProcessInfo, int, error) {
+	procs, err := process.Processes()
+	if err != nil {
+		return nil, 0, fmt.Errorf("获取进程列表失败: %w", err)
+	}
+	var memTotal uint64
+	if vm, err := mem.VirtualMemory(); err == nil {
+		memTotal = vm.Total
+	}
+
+	now := time.Now()
+	elapsed := now.Sub(s.lastTime).Seconds()
+	current := make(map[int32]processCPUTime, len(procs))
+	handles := make(map[int32]*process.Process, len(procs))
+	infos := make([]protocol.ProcessInfo, 0, len(procs))
+	for _, p := range procs {
+		times, err := p.Times()
+		if err != nil {
+			// 进程已退出或没有权限读取
+			continue
+		}
+		createTime, _ := p.CreateTime()
+		cpuTime := processCPUTime{createTime: createTime, total: times.User + times.System}
+		current[p.Pid] = cpuTime
+		handles[p.Pid] = p
+
+		info := protocol.ProcessInfo{PID: p.Pid, CreateTime: createTime}
+		if prev, ok := s.last[p.Pid]; ok && prev.createTime == createTime && elapsed > 0 {
+			info.CPUPercent = max(cpuTime.total-prev.total, 0) / elapsed * 100
+		}
+		if memInfo, err := p.MemoryInfo(); err == nil && memInfo != nil {
+			info.MemoryMB = memInfo.RSS / 1024 / 1024
+			if memTotal > 0 {
+				info.MemPercent = float32(float64(memInfo.RSS) / float64(memTotal) * 100)
+			}
+		}
+		infos = append(infos, info)
+	}
+	s.last = current
+	s.lastTime = now
+
+	top := selectTopProcesses(infos, limit)
+	// 只为推送的进程读取名称、命令行等信息
+	for i := range top {
+		p := handles[top[i].PID]
+		top[i].Name, _ = p.Name()
+		cmdline, _ := p.Cmdline()
+		top[i].Cmdline = truncateUTF8(cmdline, protocol.ProcessCmdlineMaxLength)
+		top[i].PPID, _ = p.Ppid()
+		top[i].Username, _ = p.Username()
+		if status, err := p.Status(); err == nil && len(status) > 0 {
+			top[i].Status = status[0]
+		}
+	}
+	return top, len(infos), nil
+}
+
+// selectTopProcesses 合并 CPU 和内存占用各自最高的 limit 个进程，按 CPU 使用率降序排列
+// 浏览器按任一列排序时，排在前面的进程都是完整的
+func selectTopProcesses(infos []protocol.ProcessInfo, limit int) []protocol.ProcessInfo {
+	if len(infos) <= limit {
+		result := append([]protocol.ProcessInfo(nil), infos...)
+		sortProcessesByCPU(result)
+		return result
+	}
+
+	byMemory := append([]protocol.ProcessInfo(nil), infos...)
+	sort.SliceStable(byMemory, func(i, j int) bool {
+		return byMemory[i].MemPercent > byMemory[j].MemPercent
+	})
+	byCPU := append([]protocol.ProcessInfo(nil), infos...)
+	sortProcessesByCPU(byCPU)
+
+	selected := make(map[int32]bool, limit*2)
+	result := make([]protocol.ProcessInfo, 0, limit*2)
+	for _, list := range [][]protocol.ProcessInfo{byCPU[:limit], byMemory[:limit]} {
+		for _, info := range list {
+			if !selected[info.PID] {
+				selected[info.PID] = true
+				result = append(result, info)
+			}
+		}
+	}
+	sortProcessesByCPU(result)
+	return result
+}
+
+func sortProcessesByCPU(infos []protocol.ProcessInfo) {
+	sort.SliceStable(infos, func(i, j int) bool {
+		if infos[i].CPUPercent != infos[j].CPUPercent {
+			return infos[i].CPUPercent > infos[j].CPUPercent
+		}
+		return infos[i].MemPercent > infos[j].MemPercent
+	})
+}
+
+// truncateUTF8 截断字符串到不超过 n 字节，不截断多字节字符
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// handleProcessKill 处理结束进程指令
+func (a *Agent) handleProcessKill(conn *safeConn, cmdID, args string) {
+	var req protocol.ProcessKillRequest
+	if err := json.Unmarshal([]byte(args), &req); err != nil {
+		a.sendCommandResponse(conn, cmdID, "process_kill", "error", "解析结束进程参数失败", "")
+		return
+	}
+
+	name, err := a.killProcess(req)
+	result, _ := json.Marshal(protocol.ProcessKillResult{Name: name})
+	if err != nil {
+		processLogger.Warnf("结束进程失败: %d %s: %v", req.PID, name, err)
+		a.sendCommandResponse(conn, cmdID, "process_kill", "error", err.Error(), string(result))
+		return
+	}
+	processLogger.Infof("已结束进程: %d %s (信号: %s, ID: %s)", req.PID, name, req.Signal, cmdID)
+	a.sendCommandResponse(conn, cmdID, "process_kill", "success", "", string(result))
+}
+
+// killProcess 校验后向进程发送信号，返回进程名
+func (a *Agent) killProcess(req protocol.ProcessKillRequest) (string, error) {
+	if !a.cfg.Processes.AllowKill {
+		return "", fmt.Errorf("探针未开启结束进程")
+	}
+	if req.PID <= 1 || int(req.PID) == os.Getpid() {
+		return "", fmt.Errorf("不允许结束该进程: %d", req.PID)
+	}
+
+	p, err := process.NewProcess(req.PID)
+	if err != nil {
+		return "", fmt.Errorf("进程不存在: %d", req.PID)
+	}
+	name, _ := p.Name()
+	if req.CreateTime > 0 {
+		if createTime, err := p.CreateTime(); err == nil && createTime != req.CreateTime {
+			return name, fmt.Errorf("进程 %d 已不是列表中的进程，请刷新后重试", req.PID)
+		}
+	}
+
+	switch req.Signal {
+	case protocol.ProcessSignalKill:
+		err = p.Kill()
+	case "", protocol.ProcessSignalTerm:
+		err = p.Terminate()
+	default:
+		return name, fmt.Errorf("不支持的信号: %s", req.Signal)
+	}
+	if err != nil {
+		return name, fmt.Errorf("发送信号失败: %w", err)
+	}
+	return name, nil
+}
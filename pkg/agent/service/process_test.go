@@ -0,0 +1,62 @@
+package service
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+func TestSelectTopProcesses(t *testing.T) {
+	infos := []protocol.ProcessInfo{
+		{PID: 1, CPUPercent: 0.1, MemPercent: 50},
+		{PID: 2, CPUPercent: 80, MemPercent: 1},
+		{PID: 3, CPUPercent: 5, MemPercent: 2},
+		{PID: 4, CPUPercent: 0, MemPercent: 30},
+		{PID: 5, CPUPercent: 40, MemPercent: 0.5},
+	}
+	pids := func(list []protocol.ProcessInfo) []int32 {
+		var result []int32
+		for _, info := range list {
+			result = append(result, info.PID)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name  string
+		limit int
+		want  []int32
+	}{
+		{"合并 CPU 和内存最高的进程", 2, []int32{2, 5, 1, 4}},
+		{"CPU 和内存最高的进程重复时只保留一个", 1, []int32{2, 1}},
+		{"进程数不足时全部返回", 10, []int32{2, 5, 3, 1, 4}},
+		{"不推送进程", 0, nil},
+	}
+	for _, tt := range tests {
+		if got := pids(selectTopProcesses(infos, tt.limit)); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: selectTopProcesses = %v, 期望 %v", tt.name, got, tt.want)
+		}
+	}
+	if infos[0].PID != 1 || infos[1].PID != 2 {
+		t.Errorf("不应修改传入的进程列表")
+	}
+}
+
+func TestTruncateUTF8(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"nginx -g daemon off;", 64, "nginx -g daemon off;"},
+		{"nginx -g daemon off;", 5, "nginx"},
+		{"进程名称", 4, "进"},
+		{"进程名称", 6, "进程"},
+	}
+	for _, tt := range tests {
+		if got := truncateUTF8(tt.s, tt.n); got != tt.want {
+			t.Errorf("truncateUTF8(%q, %d) = %q, 期望 %q", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+)
+
+// handleRemediation 处理告警修复动作指令，只执行配置白名单内的动作
+func (a *Agent) handleRemediation(conn *safeConn, cmdID, args string) {
+	var req protocol.RemediationRequest
+	if err := json.Unmarshal([]byte(args), &req); err != nil {
+		a.sendCommandResponse(conn, cmdID, "remediation", "error", "解析修复动作参数失败", "")
+		return
+	}
+
+	remediationLogger.Infof("执行修复动作: %s %s (ID: %s)", req.Action, req.Target, cmdID)
+
+	output, err := a.runRemediation(req)
+	if err != nil {
+		remediationLogger.Errorf("修复动作执行失败: %v", err)
+	} else {
+		remediationLogger.Infof("修复动作执行完成: %s %s", req.Action, req.Target)
+	}
+
+	resultJSON, _ := json.Marshal(protocol.RemediationResult{Output: output})
+	if err != nil {
+		a.sendCommandResponse(conn, cmdID, "remediation", "error", err.Error(), string(resultJSON))
+		return
+	}
+	a.sendCommandResponse(conn, cmdID, "remediation", "success", "", string(resultJSON))
+}
+
+// runRemediation 校验白名单并执行修复动作
+func (a *Agent) runRemediation(req protocol.RemediationRequest) (string, error) {
+	cfg := a.cfg.Remediation
+	if !cfg.Enabled {
+		return "", fmt.Errorf("探针未开启告警修复动作")
+	}
+
+	switch req.Action {
+	case "restart_service":
+		if !slices.Contains(cfg.Services, req.Target) {
+			return "", fmt.Errorf("服务不在白名单中: %s", req.Target)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+		return restartService(ctx, req.Target)
+	case "clean_dir":
+		target, ok := allowedDir(cfg.CleanDirs, req.Target)
+		if !ok {
+			return "", fmt.Errorf("目录不在白名单中: %s", req.Target)
+		}
+		return cleanDir(context.Background(), target)
+	default:
+		return "", fmt.Errorf("不支持的修复动作: %s", req.Action)
+	}
+}
+
+// allowedDir 判断目录是否在白名单中，返回清理后的路径；根目录和相对路径始终不允许
+func allowedDir(dirs []string, dir string) (string, bool) {
+	target := filepath.Clean(dir)
+	allowed := slices.ContainsFunc(dirs, func(d string) bool {
+		return filepath.Clean(d) == target
+	})
+	if !allowed || !filepath.IsAbs(target) || target == string(filepath.Separator) {
+		return "", false
+	}
+	return target, true
+}
+
+// restartService 通过 systemctl 重启服务
+func restartService(ctx context.Context, unit string) (string, error) {
+	if runtime.GOOS != "linux" {
+		return "", fmt.Errorf("当前系统不支持重启 systemd 服务")
+	}
+
+	output, err := exec.CommandContext(ctx, "systemctl", "restart", unit).CombinedOutput()
+	result := strings.TrimSpace(string(output))
+	if err != nil {
+		if result == "" {
+			result = err.Error()
+		}
+		return result, fmt.Errorf("重启服务 %s 失败: %s", unit, result)
+	}
+	return fmt.Sprintf("服务 %s 已重启", unit), nil
+}
+
+// cleanDir 删除目录下的所有内容（保留目录本身），超时后停止删除剩余的内容
+func cleanDir(ctx context.Context, dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("读取目录失败: %w", err)
+	}
+
+	var freed int64
+	var removed int
+	var failed []string
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			output := fmt.Sprintf("已删除 %d 项，释放 %.2f MB", removed, float64(freed)/1024/1024)
+			return output, fmt.Errorf("执行超时，剩余 %d 项未删除", len(entries)-removed-len(failed))
+		}
+		path := filepath.Join(dir, entry.Name())
+		size := dirSize(path)
+		if err := os.RemoveAll(path); err != nil {
+			failed = append(failed, entry.Name())
+			continue
+		}
+		freed += size
+		removed++
+	}
+
+	output := fmt.Sprintf("已删除 %d 项，释放 %.2f MB", removed, float64(freed)/1024/1024)
+	if len(failed) > 0 {
+		return output, fmt.Errorf("%d 项删除失败: %s", len(failed), strings.Join(failed, ", "))
+	}
+	return output, nil
+}
+
+// dirSize 计算文件或目录的总大小（不跟随符号链接）
+func dirSize(path string) int64 {
+	var size int64
+	_ = filepath.WalkDir(path, func(_ string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
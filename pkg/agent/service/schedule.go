@@ -0,0 +1,73 @@
+package service
+
+import (
+	"time"
+)
+
+// metricSchedule 记录各类指标的上次采集时间，服务端可为各类指标下发独立的采集间隔，
+// 未单独配置的指标使用探针配置的采集间隔
+type metricSchedule struct {
+	base      time.Duration
+	minimum   time.Duration // 单独配置的间隔不低于该值，精简模式下为探针的采集间隔
+	intervals map[string]time.Duration
+	lastRun   map[string]time.Time
+}
+
+func newMetricSchedule(base time.Duration, lite bool) *metricSchedule {
+	minimum := time.Second
+	if lite {
+		minimum = base
+	}
+	return &metricSchedule{
+		base:      base,
+		minimum:   minimum,
+		intervals: make(map[string]time.Duration),
+		lastRun:   make(map[string]time.Time),
+	}
+}
+
+// setIntervals 更新各类指标的采集间隔（秒），小于等于 0 的忽略
+func (s *metricSchedule) setIntervals(seconds map[string]int) {
+	intervals := make(map[string]time.Duration, len(seconds))
+	for name, value := range seconds {
+		if value <= 0 {
+			continue
+		}
+		intervals[name] = max(time.Duration(value)*time.Second, s.minimum)
+	}
+	s.intervals = intervals
+}
+
+// interval 获取指标的采集间隔
+func (s *metricSchedule) interval(name string) time.Duration {
+	if interval, ok := s.intervals[name]; ok {
+		return interval
+	}
+	return s.base
+}
+
+// tick 调度精度，取所有间隔的最大公约数，保证每类指标都能按各自的间隔准时采集
+func (s *metricSchedule) tick() time.Duration {
+	tick := s.base
+	for _, interval := range s.intervals {
+		tick = gcdDuration(tick, interval)
+	}
+	return max(tick.Truncate(time.Second), time.Second)
+}
+
+// due 判断指标本轮是否需要采集，需要时记录采集时间；留出半个调度周期的余量，避免定时器抖动导致跳过一轮
+func (s *metricSchedule) due(name string, now time.Time) bool {
+	last, ok := s.lastRun[name]
+	if ok && now.Sub(last) < s.interval(name)-s.tick()/2 {
+		return false
+	}
+	s.lastRun[name] = now
+	return true
+}
+
+func gcdDuration(a, b time.Duration) time.Duration {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
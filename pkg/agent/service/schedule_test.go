@@ -0,0 +1,101 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMetricScheduleInterval(t *testing.T) {
+	s := newMetricSchedule(5*time.Second, false)
+	s.setIntervals(map[string]int{"cpu": 1, "disk": 60, "gpu": 0, "host": -1})
+
+	tests := []struct {
+		name string
+		want time.Duration
+	}{
+		{name: "cpu", want: time.Second},
+		{name: "disk", want: time.Minute},
+		{name: "gpu", want: 5 * time.Second},    // 0 忽略，使用探针的采集间隔
+		{name: "host", want: 5 * time.Second},   // 负数忽略
+		{name: "memory", want: 5 * time.Second}, // 未配置
+	}
+	for _, tt := range tests {
+		if got := s.interval(tt.name); got != tt.want {
+			t.Errorf("interval(%q) = %v，期望 %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMetricScheduleLiteMinimum(t *testing.T) {
+	s := newMetricSchedule(10*time.Second, true)
+	s.setIntervals(map[string]int{"cpu": 2, "disk": 30})
+
+	if got := s.interval("cpu"); got != 10*time.Second {
+		t.Errorf("精简模式下间隔不应低于探针的采集间隔，得到 %v", got)
+	}
+	if got := s.interval("disk"); got != 30*time.Second {
+		t.Errorf("interval(disk) = %v，期望 30s", got)
+	}
+}
+
+func TestMetricScheduleTick(t *testing.T) {
+	tests := []struct {
+		base      time.Duration
+		intervals map[string]int
+		want      time.Duration
+	}{
+		{base: 5 * time.Second, intervals: nil, want: 5 * time.Second},
+		{base: 5 * time.Second, intervals: map[string]int{"disk": 60}, want: 5 * time.Second},
+		{base: 6 * time.Second, intervals: map[string]int{"disk": 15}, want: 3 * time.Second},
+		{base: 10 * time.Second, intervals: map[string]int{"cpu": 4, "disk": 6}, want: 2 * time.Second},
+		{base: 7 * time.Second, intervals: map[string]int{"cpu": 5}, want: time.Second},
+		// 探针间隔不是整秒时调度精度不低于 1 秒
+		{base: 1500 * time.Millisecond, intervals: map[string]int{"cpu": 1}, want: time.Second},
+	}
+	for _, tt := range tests {
+		s := newMetricSchedule(tt.base, false)
+		s.setIntervals(tt.intervals)
+		if got := s.tick(); got != tt.want {
+			t.Errorf("base=%v intervals=%v tick = %v，期望 %v", tt.base, tt.intervals, got, tt.want)
+		}
+	}
+}
+
+func TestMetricScheduleDue(t *testing.T) {
+	s := newMetricSchedule(5*time.Second, false)
+	s.setIntervals(map[string]int{"disk": 20})
+	start := time.Unix(1700000000, 0)
+
+	if !s.due("disk", start) {
+		t.Fatal("首次应该采集")
+	}
+
+	tests := []struct {
+		offset time.Duration
+		want   bool
+	}{
+		{offset: 5 * time.Second, want: false},
+		{offset: 15 * time.Second, want: false},
+		// 定时器稍早触发时，在半个调度周期（2.5s）的余量内仍然采集
+		{offset: 17*time.Second + 600*time.Millisecond, want: true},
+	}
+	for _, tt := range tests {
+		if got := s.due("disk", start.Add(tt.offset)); got != tt.want {
+			t.Errorf("第 %v 时 due = %v，期望 %v", tt.offset, got, tt.want)
+		}
+	}
+
+	// 采集后重新计时
+	last := start.Add(17*time.Second + 600*time.Millisecond)
+	if s.due("disk", last.Add(10*time.Second)) {
+		t.Error("采集后未到间隔不应再次采集")
+	}
+	if !s.due("disk", last.Add(20*time.Second)) {
+		t.Error("到达间隔后应该采集")
+	}
+
+	// 未单独配置的指标按探针的采集间隔
+	if !s.due("cpu", start) || s.due("cpu", start.Add(2*time.Second)) || !s.due("cpu", start.Add(5*time.Second)) {
+		t.Error("cpu 应按探针的采集间隔采集")
+	}
+}
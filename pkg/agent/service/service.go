@@ -3,7 +3,6 @@ package service
 import (
 	"context"
 	"fmt"
-	"log"
 	"os"
 	"os/signal"
 	"syscall"
@@ -25,9 +24,7 @@ type program struct {
 // configureICMP 配置 ICMP 权限（抽取通用逻辑）
 func configureICMP() {
 	if err := sysutil.ConfigureICMPPermissions(); err != nil {
-		log.Printf("⚠️  配置 ICMP 权限失败: %v", err)
-		log.Println("   提示: ICMP 监控可能需要 root 权限运行，或手动执行:")
-		log.Println("   sudo sysctl -w net.ipv4.ping_group_range=\"0 2147483647\"")
+		logger.Warnf("配置 ICMP 权限失败: %v，ICMP 监控可能需要 root 权限运行，或手动执行: sudo sysctl -w net.ipv4.ping_group_range=\"0 2147483647\"", err)
 	}
 }
 
@@ -40,7 +37,7 @@ func startAgent(ctx context.Context, cfg *config.Config) *Agent {
 	if cfg.AutoUpdate.Enabled {
 		upd, err := updater.New(cfg, GetVersion())
 		if err != nil {
-			log.Printf("⚠️  创建更新器失败: %v", err)
+			logger.Warnf("创建更新器失败: %v", err)
 		} else {
 			go upd.Start(ctx)
 		}
@@ -49,7 +46,7 @@ func startAgent(ctx context.Context, cfg *config.Config) *Agent {
 	// 在后台启动 Agent
 	go func() {
 		if err := agent.Start(ctx); err != nil {
-			log.Printf("⚠️  探针运行出错: %v", err)
+			logger.Warnf("探针运行出错: %v", err)
 		}
 	}()
 
@@ -58,7 +55,7 @@ func startAgent(ctx context.Context, cfg *config.Config) *Agent {
 
 // Start 启动服务
 func (p *program) Start(s service.Service) error {
-	log.Println("✅ Pika Agent 服务启动中...")
+	logger.Info("Pika Agent 服务启动中...")
 
 	// 初始化系统配置（Linux ICMP 权限等）
 	configureICMP()
@@ -74,7 +71,7 @@ func (p *program) Start(s service.Service) error {
 
 // Stop 停止服务
 func (p *program) Stop(s service.Service) error {
-	log.Println("📴 Pika Agent 服务停止中...")
+	logger.Info("Pika Agent 服务停止中...")
 
 	if p.cancel != nil {
 		p.cancel()
@@ -84,7 +81,7 @@ func (p *program) Stop(s service.Service) error {
 		p.agent.Stop()
 	}
 
-	log.Println("✅ Pika Agent 服务已停止")
+	logger.Info("Pika Agent 服务已停止")
 	return nil
 }
 
@@ -205,10 +202,11 @@ func (m *ServiceManager) Run() error {
 	}
 
 	// 交互模式（前台运行）
-	log.Printf("✅ 配置加载成功")
-	log.Printf("   服务器地址: %s", m.cfg.Server.Endpoint)
-	log.Printf("   采集间隔: %v", m.cfg.GetCollectorInterval())
-	log.Printf("   心跳间隔: %v", m.cfg.GetHeartbeatInterval())
+	logger.Infow("配置加载成功",
+		"endpoint", m.cfg.Server.Endpoint,
+		"interval", m.cfg.GetCollectorInterval(),
+		"heartbeat", m.cfg.GetHeartbeatInterval(),
+	)
 
 	// 初始化系统配置（Linux ICMP 权限等）
 	configureICMP()
@@ -226,12 +224,12 @@ func (m *ServiceManager) Run() error {
 
 	// 等待中断信号
 	<-interrupt
-	log.Println("📴 收到中断信号，正在关闭...")
+	logger.Info("收到中断信号，正在关闭...")
 	cancel()
 
 	// 等待 Agent 停止
 	agent.Stop()
-	log.Println("✅ 探针已停止")
+	logger.Info("探针已停止")
 
 	return nil
 }
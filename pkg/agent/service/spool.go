@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/pkg/agent/collector"
+	"github.com/dushixiang/pika/pkg/agent/spool"
+)
+
+// 补发离线缓存时每批之间停顿一下，避免重连后瞬间占满带宽
+const (
+	spoolReplayBatch = 100
+	spoolReplayPause = 100 * time.Millisecond
+)
+
+// spoolWriter 将采集器输出的指标消息写入离线缓存，并记录采集时间
+type spoolWriter struct {
+	spool *spool.Spool
+}
+
+// WriteJSON 实现 collector.WebSocketWriter，只缓存指标消息
+func (w *spoolWriter) WriteJSON(v interface{}) error {
+	msg, ok := v.(protocol.Message)
+	if !ok || msg.Type != protocol.MessageTypeMetrics {
+		return nil
+	}
+
+	var metrics protocol.MetricsWrapper
+	if err := json.Unmarshal(msg.Data, &metrics); err != nil {
+		return err
+	}
+	metrics.Timestamp = time.Now().UnixMilli()
+
+	record, err := json.Marshal(metrics)
+	if err != nil {
+		return err
+	}
+	dropped, err := w.spool.Append(record)
+	if dropped > 0 {
+		spoolLogger.Warnf("离线缓存已满，丢弃最早的 %d 条指标", dropped)
+	}
+	return err
+}
+
+// spoolLoop 与服务端断开期间按采集间隔将时序指标写入离线缓存
+func (a *Agent) spoolLoop(ctx context.Context) {
+	writer := &spoolWriter{spool: a.spool}
+	// 离线采集使用独立的采集器，网络速率等需要两次采样的指标在断线期间保持连续
+	var manager *collector.Manager
+
+	ticker := time.NewTicker(a.cfg.GetCollectorInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if a.getActiveConn() != nil {
+				manager = nil
+				continue
+			}
+			if manager == nil {
+				manager = collector.NewManager(a.cfg)
+				spoolLogger.Info("与服务端断开，开始缓存指标")
+			}
+			a.spoolMetrics(writer, manager)
+		}
+	}
+}
+
+// spoolMetrics 采集需要保留历史的时序指标写入离线缓存，只保留最新值的指标不缓存
+func (a *Agent) spoolMetrics(writer collector.WebSocketWriter, manager *collector.Manager) {
+	run := func(name string, fn func(collector.WebSocketWriter) error) {
+		if err := a.watchdog.Run(name, func() error { return fn(writer) }); err != nil {
+			spoolLogger.Debugf("缓存%s指标失败: %v", name, err)
+		}
+	}
+
+	run("cpu", manager.CollectAndSendCPU)
+	run("memory", manager.CollectAndSendMemory)
+	run("disk", manager.CollectAndSendDisk)
+	run("disk_io", manager.CollectAndSendDiskIO)
+	run("network", manager.CollectAndSendNetwork)
+	run("network_connection", manager.CollectAndSendNetworkConnection)
+
+	// 精简模式下不采集 GPU 和温度
+	if !a.cfg.IsLite() {
+		run("gpu", manager.CollectAndSendGPU)
+		run("temperature", manager.CollectAndSendTemperature)
+	}
+}
+
+// replaySpool 重连后补发离线缓存的指标，发送成功的记录才从缓存中删除，
+// 补发中途断开或进程退出时未确认的记录保留在缓存中，下次重连后再补发
+func (a *Agent) replaySpool(conn *safeConn, done chan struct{}) {
+	records, err := a.spool.Peek()
+	if err != nil {
+		spoolLogger.Warnf("读取离线缓存失败: %v", err)
+		return
+	}
+	if len(records) == 0 {
+		return
+	}
+
+	spoolLogger.Infof("开始补发离线缓存的 %d 条指标", len(records))
+	for i, record := range records {
+		if i > 0 && i%spoolReplayBatch == 0 {
+			select {
+			case <-done:
+				a.discardSpool(i)
+				return
+			case <-time.After(spoolReplayPause):
+			}
+		}
+
+		msg := protocol.Message{
+			Type: protocol.MessageTypeMetricsReplay,
+			Data: record,
+		}
+		if err := conn.WriteJSON(msg); err != nil {
+			spoolLogger.Warnf("补发离线缓存失败: %v", err)
+			a.discardSpool(i)
+			return
+		}
+	}
+	a.discardSpool(len(records))
+	spoolLogger.Infof("离线缓存补发完成，共 %d 条指标", len(records))
+}
+
+// discardSpool 从离线缓存中删除已发送的记录
+func (a *Agent) discardSpool(sent int) {
+	if err := a.spool.Discard(sent); err != nil {
+		spoolLogger.Warnf("删除已补发的离线缓存失败: %v", err)
+	}
+}
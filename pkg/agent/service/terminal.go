@@ -0,0 +1,223 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/pkg/agent/sysutil"
+)
+
+const (
+	terminalReadBufferSize = 16 * 1024       // 单个终端输出片段的最大字节数
+	terminalWriteTimeout   = 5 * time.Second // 写入键盘输入的超时时间，shell 长时间不读取输入时丢弃
+)
+
+// terminalSession 正在运行的远程终端
+type terminalSession struct {
+	ptmx     *os.File
+	cmd      *exec.Cmd
+	activity chan struct{} // 收到键盘输入时通知空闲计时器
+
+	mu     sync.Mutex
+	reason string // 结束原因，shell 自行退出时为空
+}
+
+// stop 终止终端中的进程并关闭伪终端，正在进行的读取随之结束
+func (s *terminalSession) stop(reason string) {
+	s.mu.Lock()
+	if s.reason == "" {
+		s.reason = reason
+	}
+	s.mu.Unlock()
+	sysutil.KillProcessGroup(s.cmd)
+	_ = s.ptmx.Close()
+}
+
+// touch 记录键盘输入，重置空闲计时
+func (s *terminalSession) touch() {
+	select {
+	case s.activity <- struct{}{}:
+	default:
+	}
+}
+
+// handleTerminal 处理远程终端指令，在伪终端中启动 shell 并将输出推送给服务端，直到 shell 退出、会话被关闭或空闲超时
+func (a *Agent) handleTerminal(conn *safeConn, cmdID, args string) {
+	if !a.cfg.Terminal.Enabled {
+		a.finishTerminal(conn, cmdID, fmt.Errorf("探针未开启远程终端"))
+		return
+	}
+
+	var req protocol.TerminalOpenRequest
+	if err := json.Unmarshal([]byte(args), &req); err != nil {
+		a.finishTerminal(conn, cmdID, fmt.Errorf("解析终端参数失败: %w", err))
+		return
+	}
+	cols, rows := protocol.ClampTerminalSize(req.Cols, req.Rows)
+	idleTimeout := req.IdleTimeout
+	if idleTimeout <= 0 || idleTimeout > protocol.TerminalMaxIdleTimeout {
+		idleTimeout = protocol.TerminalIdleTimeout
+	}
+	term := req.Term
+	if term == "" {
+		term = "xterm-256color"
+	}
+
+	shell := a.cfg.GetTerminalShell()
+	cmd := exec.Command(shell, "-l")
+	cmd.Env = append(os.Environ(), "TERM="+term)
+	if home, err := os.UserHomeDir(); err == nil {
+		cmd.Dir = home
+	}
+	ptmx, err := sysutil.StartPTY(cmd, cols, rows)
+	if err != nil {
+		a.finishTerminal(conn, cmdID, fmt.Errorf("启动终端失败: %w", err))
+		return
+	}
+
+	session := &terminalSession{
+		ptmx:     ptmx,
+		cmd:      cmd,
+		activity: make(chan struct{}, 1),
+	}
+	a.terminalMu.Lock()
+	a.terminals[cmdID] = session
+	a.terminalMu.Unlock()
+	defer func() {
+		a.terminalMu.Lock()
+		delete(a.terminals, cmdID)
+		a.terminalMu.Unlock()
+	}()
+
+	terminalLogger.Infof("远程终端已打开: %s (ID: %s)", shell, cmdID)
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		timeout := time.Duration(idleTimeout) * time.Second
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-session.activity:
+				timer.Reset(timeout)
+			case <-timer.C:
+				session.stop(fmt.Sprintf("空闲超过 %d 秒，终端已关闭", idleTimeout))
+				return
+			}
+		}
+	}()
+
+	buf := make([]byte, terminalReadBufferSize)
+	for {
+		n, err := ptmx.Read(buf)
+		if n > 0 {
+			data := make([]byte, n)
+			copy(data, buf[:n])
+			if sendErr := a.sendTerminalOutput(conn, protocol.TerminalOutput{ID: cmdID, Data: data}); sendErr != nil {
+				// 与服务端的连接已断开，没有人再接收输出
+				session.stop("与服务端的连接已断开")
+				break
+			}
+		}
+		if err != nil {
+			// shell 退出后读取主设备返回 EIO，被关闭时返回 ErrClosed
+			break
+		}
+	}
+
+	session.stop("")
+	_ = cmd.Wait()
+
+	session.mu.Lock()
+	reason := session.reason
+	session.mu.Unlock()
+	terminalLogger.Infof("远程终端已关闭 (ID: %s) %s", cmdID, reason)
+	if reason != "" {
+		a.finishTerminal(conn, cmdID, fmt.Errorf("%s", reason))
+		return
+	}
+	a.finishTerminal(conn, cmdID, nil)
+}
+
+// handleTerminalInput 将服务端转发的键盘输入写入终端，或调整终端窗口大小
+func (a *Agent) handleTerminalInput(data json.RawMessage) {
+	var input protocol.TerminalInput
+	if err := json.Unmarshal(data, &input); err != nil {
+		terminalLogger.Warnf("解析终端输入失败: %v", err)
+		return
+	}
+
+	a.terminalMu.Lock()
+	session, ok := a.terminals[input.ID]
+	a.terminalMu.Unlock()
+	if !ok {
+		return
+	}
+
+	if input.Cols > 0 && input.Rows > 0 {
+		cols, rows := protocol.ClampTerminalSize(input.Cols, input.Rows)
+		if err := sysutil.ResizePTY(session.ptmx, cols, rows); err != nil {
+			terminalLogger.Warnf("调整终端大小失败: %v", err)
+		}
+	}
+	if len(input.Data) > 0 {
+		session.touch()
+		_ = session.ptmx.SetWriteDeadline(time.Now().Add(terminalWriteTimeout))
+		if _, err := session.ptmx.Write(input.Data); err != nil {
+			terminalLogger.Warnf("写入终端输入失败: %v", err)
+		}
+	}
+}
+
+// handleTerminalClose 处理服务端的结束终端请求
+func (a *Agent) handleTerminalClose(data json.RawMessage) {
+	var req protocol.TerminalClose
+	if err := json.Unmarshal(data, &req); err != nil {
+		terminalLogger.Warnf("解析结束终端请求失败: %v", err)
+		return
+	}
+
+	a.terminalMu.Lock()
+	session, ok := a.terminals[req.ID]
+	a.terminalMu.Unlock()
+	if ok {
+		session.stop("服务端已结束会话")
+	}
+}
+
+// finishTerminal 发送结束片段和指令响应
+func (a *Agent) finishTerminal(conn *safeConn, cmdID string, err error) {
+	output := protocol.TerminalOutput{ID: cmdID, Done: true}
+	if err != nil {
+		output.Error = err.Error()
+	}
+	if sendErr := a.sendTerminalOutput(conn, output); sendErr != nil {
+		terminalLogger.Warnf("发送终端输出失败: %v", sendErr)
+	}
+
+	if err != nil {
+		a.sendCommandResponse(conn, cmdID, "terminal", "error", err.Error(), "")
+		return
+	}
+	a.sendCommandResponse(conn, cmdID, "terminal", "success", "", "")
+}
+
+// sendTerminalOutput 发送终端输出片段
+func (a *Agent) sendTerminalOutput(conn *safeConn, output protocol.TerminalOutput) error {
+	data, err := json.Marshal(output)
+	if err != nil {
+		return err
+	}
+	return conn.WriteJSON(protocol.Message{
+		Type: protocol.MessageTypeTerminalOutput,
+		Data: data,
+	})
+}
@@ -0,0 +1,167 @@
+package spool
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Spool 有界的本地文件缓存，每条记录占一行，超出大小上限时丢弃最早的记录
+type Spool struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+	size     int64 // 当前文件大小，首次写入时从文件读取
+	loaded   bool
+}
+
+// New 创建缓存，文件在首次写入时创建
+func New(path string, maxBytes int64) *Spool {
+	return &Spool{
+		path:     path,
+		maxBytes: maxBytes,
+	}
+}
+
+// Append 追加一条记录，记录中不能包含换行符；返回因超出大小上限而丢弃的记录数
+func (s *Spool) Append(record []byte) (int, error) {
+	if bytes.IndexByte(record, '\n') >= 0 {
+		return 0, fmt.Errorf("记录中包含换行符")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.loaded {
+		if info, err := os.Stat(s.path); err == nil {
+			s.size = info.Size()
+		}
+		s.loaded = true
+	}
+
+	var dropped int
+	lineSize := int64(len(record) + 1)
+	if s.size+lineSize > s.maxBytes {
+		// 保留较新的 3/4 容量，避免缓存满后每次写入都重写文件
+		var err error
+		if dropped, err = s.trim(s.maxBytes*3/4 - lineSize); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return dropped, err
+	}
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return dropped, err
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(record, '\n')); err != nil {
+		return dropped, err
+	}
+	s.size += lineSize
+	return dropped, nil
+}
+
+// Peek 按写入顺序返回所有记录，不清空缓存；记录发送成功后调用 Discard 删除
+func (s *Spool) Peek() ([][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.readAll()
+}
+
+// Discard 删除最早的 n 条记录，记录全部删除时删除缓存文件
+func (s *Spool) Discard(n int) error {
+	if n <= 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.readAll()
+	if err != nil {
+		return err
+	}
+	if n < len(records) {
+		return s.rewrite(records[n:])
+	}
+
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	s.size = 0
+	s.loaded = true
+	return nil
+}
+
+// trim 丢弃最早的记录，使保留的记录总大小不超过 keepBytes，返回丢弃的记录数
+func (s *Spool) trim(keepBytes int64) (int, error) {
+	records, err := s.readAll()
+	if err != nil {
+		return 0, err
+	}
+
+	var kept int64
+	start := len(records)
+	for start > 0 {
+		lineSize := int64(len(records[start-1]) + 1)
+		if kept+lineSize > keepBytes {
+			break
+		}
+		kept += lineSize
+		start--
+	}
+
+	if err := s.rewrite(records[start:]); err != nil {
+		return 0, err
+	}
+	return start, nil
+}
+
+// rewrite 用给定的记录替换缓存文件，先写临时文件再替换，避免写入过程中断导致缓存损坏
+func (s *Spool) rewrite(records [][]byte) error {
+	tmpPath := s.path + ".tmp"
+	var buf bytes.Buffer
+	for _, record := range records {
+		buf.Write(record)
+		buf.WriteByte('\n')
+	}
+	if err := os.WriteFile(tmpPath, buf.Bytes(), 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return err
+	}
+	s.size = int64(buf.Len())
+	s.loaded = true
+	return nil
+}
+
+// readAll 读取所有记录，文件不存在时返回空
+func (s *Spool) readAll() ([][]byte, error) {
+	file, err := os.Open(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var records [][]byte
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		if line := scanner.Bytes(); len(line) > 0 {
+			records = append(records, bytes.Clone(line))
+		}
+	}
+	return records, scanner.Err()
+}
@@ -0,0 +1,94 @@
+package spool
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func appendRecords(t *testing.T, s *Spool, records ...string) {
+	t.Helper()
+	for _, record := range records {
+		if _, err := s.Append([]byte(record)); err != nil {
+			t.Fatalf("写入 %q 失败: %v", record, err)
+		}
+	}
+}
+
+func peekStrings(t *testing.T, s *Spool) []string {
+	t.Helper()
+	records, err := s.Peek()
+	if err != nil {
+		t.Fatalf("读取缓存失败: %v", err)
+	}
+	var result []string
+	for _, record := range records {
+		result = append(result, string(record))
+	}
+	return result
+}
+
+func TestPeekKeepsRecords(t *testing.T) {
+	s := New(filepath.Join(t.TempDir(), "spool.jsonl"), 1<<20)
+	appendRecords(t, s, "a", "b", "c")
+
+	for i := 0; i < 2; i++ {
+		if got := peekStrings(t, s); fmt.Sprint(got) != "[a b c]" {
+			t.Fatalf("第 %d 次读取为 %v，期望 [a b c]", i+1, got)
+		}
+	}
+}
+
+func TestDiscard(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spool.jsonl")
+	s := New(path, 1<<20)
+	appendRecords(t, s, "a", "b", "c")
+
+	if err := s.Discard(2); err != nil {
+		t.Fatal(err)
+	}
+	if got := peekStrings(t, s); fmt.Sprint(got) != "[c]" {
+		t.Fatalf("删除 2 条后为 %v，期望 [c]", got)
+	}
+
+	// 补发期间新写入的记录不受影响
+	appendRecords(t, s, "d")
+	if err := s.Discard(1); err != nil {
+		t.Fatal(err)
+	}
+	if got := peekStrings(t, s); fmt.Sprint(got) != "[d]" {
+		t.Fatalf("删除 1 条后为 %v，期望 [d]", got)
+	}
+
+	if err := s.Discard(5); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("记录全部删除后应删除缓存文件: %v", err)
+	}
+}
+
+func TestAppendTrimsOldest(t *testing.T) {
+	// 每条记录 10 字节（含换行），上限 40 字节，超出时保留 3/4 容量
+	s := New(filepath.Join(t.TempDir(), "spool.jsonl"), 40)
+	appendRecords(t, s, "000000000", "111111111", "222222222", "333333333")
+
+	dropped, err := s.Append([]byte("444444444"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dropped != 2 {
+		t.Fatalf("丢弃 %d 条，期望 2 条", dropped)
+	}
+	if got := peekStrings(t, s); fmt.Sprint(got) != "[222222222 333333333 444444444]" {
+		t.Fatalf("裁剪后为 %v", got)
+	}
+}
+
+func TestAppendRejectsNewline(t *testing.T) {
+	s := New(filepath.Join(t.TempDir(), "spool.jsonl"), 1<<20)
+	if _, err := s.Append([]byte("a\nb")); err == nil {
+		t.Fatal("包含换行符的记录应该写入失败")
+	}
+}
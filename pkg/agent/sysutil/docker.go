@@ -0,0 +1,30 @@
+package sysutil
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// DockerAvailable 判断本机是否安装了 docker 命令
+func DockerAvailable() bool {
+	_, err := exec.LookPath("docker")
+	return err == nil
+}
+
+// RunDocker 执行 docker 命令，返回合并后的标准输出和标准错误
+func RunDocker(ctx context.Context, args ...string) (string, error) {
+	output, err := exec.CommandContext(ctx, "docker", args...).CombinedOutput()
+	result := strings.TrimSpace(string(output))
+	if err != nil {
+		if ctx.Err() != nil {
+			return result, fmt.Errorf("执行 docker %s 超时", args[0])
+		}
+		if result == "" {
+			result = err.Error()
+		}
+		return result, fmt.Errorf("执行 docker %s 失败: %s", args[0], result)
+	}
+	return result, nil
+}
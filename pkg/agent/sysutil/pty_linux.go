@@ -0,0 +1,81 @@
+package sysutil
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// StartPTY 在新的伪终端中启动命令，返回伪终端的主设备，读取主设备得到命令的输出，写入即为键盘输入
+// 命令在新的会话中运行并以伪终端作为控制终端；主设备为非阻塞模式，关闭时会结束正在进行的读取
+func StartPTY(cmd *exec.Cmd, cols, rows int) (*os.File, error) {
+	fd, err := unix.Open("/dev/ptmx", unix.O_RDWR|unix.O_NOCTTY|unix.O_CLOEXEC|unix.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, fmt.Errorf("打开伪终端失败: %w", err)
+	}
+	// 解锁从设备并获取编号
+	if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("解锁伪终端失败: %w", err)
+	}
+	n, err := unix.IoctlGetInt(fd, unix.TIOCGPTN)
+	if err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("获取伪终端编号失败: %w", err)
+	}
+	if err := unix.IoctlSetWinsize(fd, unix.TIOCSWINSZ, &unix.Winsize{Row: uint16(rows), Col: uint16(cols)}); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("调整终端大小失败: %w", err)
+	}
+	// 非阻塞的文件由运行时的网络轮询器管理，Close 可以中断阻塞的 Read
+	ptmx := os.NewFile(uintptr(fd), "/dev/ptmx")
+
+	tty, err := os.OpenFile("/dev/pts/"+strconv.Itoa(n), os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		ptmx.Close()
+		return nil, fmt.Errorf("打开伪终端从设备失败: %w", err)
+	}
+	defer tty.Close()
+
+	cmd.Stdin = tty
+	cmd.Stdout = tty
+	cmd.Stderr = tty
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true}
+	if err := cmd.Start(); err != nil {
+		ptmx.Close()
+		return nil, err
+	}
+	return ptmx, nil
+}
+
+// ResizePTY 调整伪终端的窗口大小
+// 不能调用 ptmx.Fd()，否则文件会被切换回阻塞模式
+func ResizePTY(ptmx *os.File, cols, rows int) error {
+	conn, err := ptmx.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var ioctlErr error
+	err = conn.Control(func(fd uintptr) {
+		ioctlErr = unix.IoctlSetWinsize(int(fd), unix.TIOCSWINSZ, &unix.Winsize{Row: uint16(rows), Col: uint16(cols)})
+	})
+	if err == nil {
+		err = ioctlErr
+	}
+	if err != nil {
+		return fmt.Errorf("调整终端大小失败: %w", err)
+	}
+	return nil
+}
+
+// KillProcessGroup 终止命令所在的进程组
+func KillProcessGroup(cmd *exec.Cmd) {
+	if cmd.Process == nil {
+		return
+	}
+	_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
@@ -0,0 +1,108 @@
+package sysutil
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStartPTY(t *testing.T) {
+	if _, err := os.Stat("/dev/ptmx"); err != nil {
+		t.Skip("系统没有 /dev/ptmx")
+	}
+	cmd := exec.Command("sh", "-c", "stty size; read line; echo got:$line")
+	ptmx, err := StartPTY(cmd, 100, 30)
+	if err != nil {
+		t.Fatalf("启动失败: %v", err)
+	}
+	defer ptmx.Close()
+
+	if _, err := ptmx.Write([]byte("hello\n")); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+
+	done := make(chan []byte)
+	go func() {
+		var buf bytes.Buffer
+		// 命令结束后读取主设备返回 EIO
+		_, _ = io.Copy(&buf, ptmx)
+		done <- buf.Bytes()
+	}()
+
+	select {
+	case out := <-done:
+		text := string(out)
+		if !strings.Contains(text, "30 100") {
+			t.Errorf("窗口大小未生效: %q", text)
+		}
+		if !strings.Contains(text, "got:hello") {
+			t.Errorf("未读取到输入: %q", text)
+		}
+	case <-time.After(5 * time.Second):
+		KillProcessGroup(cmd)
+		t.Fatal("等待输出超时")
+	}
+	_ = cmd.Wait()
+}
+
+func TestResizeAndClosePTY(t *testing.T) {
+	if _, err := os.Stat("/dev/ptmx"); err != nil {
+		t.Skip("系统没有 /dev/ptmx")
+	}
+	cmd := exec.Command("sh", "-c", "read line; stty size")
+	ptmx, err := StartPTY(cmd, 80, 24)
+	if err != nil {
+		t.Fatalf("启动失败: %v", err)
+	}
+	defer func() {
+		KillProcessGroup(cmd)
+		_ = cmd.Wait()
+	}()
+
+	if err := ResizePTY(ptmx, 120, 40); err != nil {
+		t.Fatalf("调整大小失败: %v", err)
+	}
+	if _, err := ptmx.Write([]byte("\n")); err != nil {
+		t.Fatalf("写入失败: %v", err)
+	}
+	var out bytes.Buffer
+	buf := make([]byte, 1024)
+	for !strings.Contains(out.String(), "40 120") {
+		_ = ptmx.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, err := ptmx.Read(buf)
+		if err != nil {
+			t.Fatalf("未读取到调整后的大小: %q, %v", out.String(), err)
+		}
+		out.Write(buf[:n])
+	}
+
+	// 关闭主设备应中断阻塞的读取
+	cmd2 := exec.Command("sh", "-c", "sleep 30")
+	ptmx2, err := StartPTY(cmd2, 80, 24)
+	if err != nil {
+		t.Fatalf("启动失败: %v", err)
+	}
+	defer func() {
+		KillProcessGroup(cmd2)
+		_ = cmd2.Wait()
+	}()
+	readDone := make(chan error, 1)
+	go func() {
+		_, err := ptmx2.Read(make([]byte, 16))
+		readDone <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	ptmx2.Close()
+	select {
+	case err := <-readDone:
+		if err == nil {
+			t.Error("关闭后读取应返回错误")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("关闭主设备未中断读取")
+	}
+}
@@ -0,0 +1,30 @@
+//go:build !linux
+// +build !linux
+
+package sysutil
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// errPTYUnsupported 当前系统不支持伪终端
+var errPTYUnsupported = errors.New("当前系统不支持远程终端")
+
+// StartPTY 仅支持 Linux
+func StartPTY(cmd *exec.Cmd, cols, rows int) (*os.File, error) {
+	return nil, errPTYUnsupported
+}
+
+// ResizePTY 仅支持 Linux
+func ResizePTY(ptmx *os.File, cols, rows int) error {
+	return errPTYUnsupported
+}
+
+// KillProcessGroup 仅支持 Linux
+func KillProcessGroup(cmd *exec.Cmd) {
+	if cmd.Process != nil {
+		_ = cmd.Process.Kill()
+	}
+}
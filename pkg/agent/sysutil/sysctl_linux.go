@@ -2,12 +2,15 @@ package sysutil
 
 import (
 	"fmt"
-	"log"
 	"os"
 	"strconv"
 	"strings"
+
+	"github.com/dushixiang/pika/pkg/logging"
 )
 
+var logger = logging.Module("sysctl")
+
 // ConfigureICMPPermissions 配置 ICMP 权限
 // 在 Linux 系统上，允许非特权用户发起 ICMP 请求
 // 使用 sync.Once 确保只执行一次
@@ -27,19 +30,18 @@ func doConfigureICMPPermissions() error {
 
 	// 2. 检查是否已经满足要求 (范围包含 0 到 2147483647)
 	if currentMin <= 0 && currentMax >= 2147483647 {
-		log.Printf("✅ ICMP 权限已配置: net.ipv4.ping_group_range=%d %d", currentMin, currentMax)
+		logger.Infof("ICMP 权限已配置: net.ipv4.ping_group_range=%d %d", currentMin, currentMax)
 		return nil
 	}
 
 	// 3. 需要配置，写入新值
-	log.Printf("🔧 当前 ICMP 配置: net.ipv4.ping_group_range=%d %d (不满足要求)", currentMin, currentMax)
-	log.Println("   正在配置为: 0 2147483647")
+	logger.Infof("当前 ICMP 配置: net.ipv4.ping_group_range=%d %d (不满足要求)，正在配置为: 0 2147483647", currentMin, currentMax)
 
 	if err := writePingGroupRange(sysctlPath, 0, 2147483647); err != nil {
 		return fmt.Errorf("配置 ICMP 权限失败: %w", err)
 	}
 
-	log.Println("✅ ICMP 权限配置成功: net.ipv4.ping_group_range=0 2147483647")
+	logger.Info("ICMP 权限配置成功: net.ipv4.ping_group_range=0 2147483647")
 	return nil
 }
 
@@ -3,15 +3,17 @@ package tamper
 import (
 	"context"
 	"fmt"
-	"log"
 	"os"
 	"runtime"
 	"sync"
 	"time"
 
+	"github.com/dushixiang/pika/pkg/logging"
 	"github.com/fsnotify/fsnotify"
 )
 
+var logger = logging.Module("tamper")
+
 // TamperEvent 防篡改事件
 type TamperEvent struct {
 	Path      string    `json:"path"`      // 被修改的路径
@@ -72,7 +74,7 @@ func (p *Protector) ApplyIncrementalUpdate(ctx context.Context, toAdd, toRemove
 
 	// 如果没有变化,直接返回
 	if len(toAdd) == 0 && len(toRemove) == 0 {
-		log.Println("ℹ️  防篡改保护目录列表无变化")
+		logger.Debug("防篡改保护目录列表无变化")
 		return &UpdateResult{
 			Added:   []string{},
 			Removed: []string{},
@@ -91,15 +93,15 @@ func (p *Protector) ApplyIncrementalUpdate(ctx context.Context, toAdd, toRemove
 	var removeFailed []string
 	for _, path := range toRemove {
 		if !p.paths[path] {
-			log.Printf("ℹ️  目录 %s 未被保护，跳过移除", path)
+			logger.Debugf("目录 %s 未被保护，跳过移除", path)
 			continue
 		}
 		if err := p.removePath(path); err != nil {
-			log.Printf("⚠️  移除目录 %s 保护失败: %v", path, err)
+			logger.Warnf("移除目录 %s 保护失败: %v", path, err)
 			removeFailed = append(removeFailed, path)
 		} else {
 			delete(p.paths, path)
-			log.Printf("✅ 已取消保护目录: %s", path)
+			logger.Infof("已取消保护目录: %s", path)
 		}
 	}
 
@@ -107,15 +109,15 @@ func (p *Protector) ApplyIncrementalUpdate(ctx context.Context, toAdd, toRemove
 	var addFailed []string
 	for _, path := range toAdd {
 		if p.paths[path] {
-			log.Printf("ℹ️  目录 %s 已被保护，跳过新增", path)
+			logger.Debugf("目录 %s 已被保护，跳过新增", path)
 			continue
 		}
 		if err := p.addPath(path); err != nil {
-			log.Printf("⚠️  添加目录 %s 保护失败: %v", path, err)
+			logger.Warnf("添加目录 %s 保护失败: %v", path, err)
 			addFailed = append(addFailed, path)
 		} else {
 			p.paths[path] = true
-			log.Printf("✅ 已保护目录: %s", path)
+			logger.Infof("已保护目录: %s", path)
 		}
 	}
 
@@ -131,7 +133,7 @@ func (p *Protector) ApplyIncrementalUpdate(ctx context.Context, toAdd, toRemove
 		return result, fmt.Errorf("部分操作失败: 添加失败 %d 个, 移除失败 %d 个", len(addFailed), len(removeFailed))
 	}
 
-	log.Printf("✅ 防篡改保护已更新: 新增 %d 个目录, 移除 %d 个目录, 当前保护 %d 个目录",
+	logger.Infof("防篡改保护已更新: 新增 %d 个目录, 移除 %d 个目录, 当前保护 %d 个目录",
 		len(result.Added), len(result.Removed), len(result.Current))
 
 	return result, nil
@@ -173,7 +175,7 @@ func (p *Protector) UpdatePaths(ctx context.Context, newPaths []string) (*Update
 
 	// 如果没有变化,直接返回
 	if len(toAdd) == 0 && len(toRemove) == 0 {
-		log.Println("ℹ️  防篡改保护目录列表无变化")
+		logger.Debug("防篡改保护目录列表无变化")
 		return &UpdateResult{
 			Added:   []string{},
 			Removed: []string{},
@@ -190,11 +192,11 @@ func (p *Protector) UpdatePaths(ctx context.Context, newPaths []string) (*Update
 	var removeFailed []string
 	for _, path := range toRemove {
 		if err := p.removePath(path); err != nil {
-			log.Printf("⚠️  移除目录 %s 保护失败: %v", path, err)
+			logger.Warnf("移除目录 %s 保护失败: %v", path, err)
 			removeFailed = append(removeFailed, path)
 		} else {
 			delete(p.paths, path)
-			log.Printf("✅ 已取消保护目录: %s", path)
+			logger.Infof("已取消保护目录: %s", path)
 		}
 	}
 
@@ -202,11 +204,11 @@ func (p *Protector) UpdatePaths(ctx context.Context, newPaths []string) (*Update
 	var addFailed []string
 	for _, path := range toAdd {
 		if err := p.addPath(path); err != nil {
-			log.Printf("⚠️  添加目录 %s 保护失败: %v", path, err)
+			logger.Warnf("添加目录 %s 保护失败: %v", path, err)
 			addFailed = append(addFailed, path)
 		} else {
 			p.paths[path] = true
-			log.Printf("✅ 已保护目录: %s", path)
+			logger.Infof("已保护目录: %s", path)
 		}
 	}
 
@@ -222,7 +224,7 @@ func (p *Protector) UpdatePaths(ctx context.Context, newPaths []string) (*Update
 		return result, fmt.Errorf("部分操作失败: 添加失败 %d 个, 移除失败 %d 个", len(addFailed), len(removeFailed))
 	}
 
-	log.Printf("✅ 防篡改保护已更新: 新增 %d 个目录, 移除 %d 个目录, 当前保护 %d 个目录",
+	logger.Infof("防篡改保护已更新: 新增 %d 个目录, 移除 %d 个目录, 当前保护 %d 个目录",
 		len(result.Added), len(result.Removed), len(result.Current))
 
 	return result, nil
@@ -234,7 +236,7 @@ func (p *Protector) StopAll() error {
 	defer p.mu.Unlock()
 
 	if len(p.paths) == 0 {
-		log.Println("ℹ️  没有正在保护的目录")
+		logger.Debug("没有正在保护的目录")
 		return nil
 	}
 
@@ -255,7 +257,7 @@ func (p *Protector) StopAll() error {
 	// 关闭监控器
 	if p.watcher != nil {
 		if err := p.watcher.Close(); err != nil {
-			log.Printf("⚠️  关闭文件监控器失败: %v", err)
+			logger.Warnf("关闭文件监控器失败: %v", err)
 			lastErr = err
 		}
 		p.watcher = nil
@@ -265,17 +267,17 @@ func (p *Protector) StopAll() error {
 	// 移除所有目录的不可变属性
 	for path := range p.paths {
 		if err := p.setImmutable(path, false); err != nil {
-			log.Printf("⚠️  移除目录 %s 不可变属性失败: %v", path, err)
+			logger.Warnf("移除目录 %s 不可变属性失败: %v", path, err)
 			lastErr = err
 		} else {
-			log.Printf("✅ 已取消保护目录: %s", path)
+			logger.Infof("已取消保护目录: %s", path)
 		}
 	}
 
 	// 清空路径列表
 	p.paths = make(map[string]bool)
 
-	log.Println("✅ 已停止所有防篡改保护")
+	logger.Info("已停止所有防篡改保护")
 	return lastErr
 }
 
@@ -333,7 +335,7 @@ func (p *Protector) initWatcher(ctx context.Context) error {
 		p.checkTicker = time.NewTicker(5 * time.Second)
 		go p.periodicAttributeCheck()
 
-		log.Println("✅ 文件监控器已启动")
+		logger.Info("文件监控器已启动")
 	})
 	return err
 }
@@ -362,7 +364,7 @@ func (p *Protector) removePath(path string) error {
 	// 从监控中移除
 	if p.watcher != nil {
 		if err := p.watcher.Remove(path); err != nil {
-			log.Printf("⚠️  从监控中移除目录失败: %v", err)
+			logger.Warnf("从监控中移除目录失败: %v", err)
 			// 继续执行,不返回错误
 		}
 	}
@@ -390,7 +392,7 @@ func (p *Protector) watchLoop() {
 			if !ok {
 				return
 			}
-			log.Printf("⚠️  文件监控错误: %v", err)
+			logger.Warnf("文件监控错误: %v", err)
 		}
 	}
 }
@@ -431,9 +433,9 @@ func (p *Protector) handleEvent(event fsnotify.Event) {
 	// 发送事件(非阻塞)
 	select {
 	case p.eventCh <- tamperEvent:
-		log.Printf("🚨 检测到文件变动: %s - %s (%s)", event.Name, operation, details)
+		logger.Warnf("检测到文件变动: %s - %s (%s)", event.Name, operation, details)
 	default:
-		log.Printf("⚠️  事件队列已满,丢弃事件: %s", event.Name)
+		logger.Warnf("事件队列已满,丢弃事件: %s", event.Name)
 	}
 }
 
@@ -468,20 +470,20 @@ func (p *Protector) checkAndRestoreImmutable(path string) {
 	// 检查不可变属性
 	hasImmutable, err := p.checkImmutable(path)
 	if err != nil {
-		log.Printf("⚠️  检查目录 %s 属性失败: %v", path, err)
+		logger.Warnf("检查目录 %s 属性失败: %v", path, err)
 		return
 	}
 
 	// 如果不可变属性被移除
 	if !hasImmutable {
-		log.Printf("🚨 检测到属性篡改: %s 的不可变属性被移除", path)
+		logger.Warnf("检测到属性篡改: %s 的不可变属性被移除", path)
 
 		// 尝试恢复属性
 		restored := false
 		if err := p.setImmutable(path, true); err != nil {
-			log.Printf("❌ 恢复目录 %s 不可变属性失败: %v", path, err)
+			logger.Errorf("恢复目录 %s 不可变属性失败: %v", path, err)
 		} else {
-			log.Printf("✅ 已自动恢复目录 %s 的不可变属性", path)
+			logger.Infof("已自动恢复目录 %s 的不可变属性", path)
 			restored = true
 		}
 
@@ -495,9 +497,9 @@ func (p *Protector) checkAndRestoreImmutable(path string) {
 
 		select {
 		case p.alertCh <- alert:
-			log.Printf("📤 已发送属性篡改告警: %s", path)
+			logger.Infof("已发送属性篡改告警: %s", path)
 		default:
-			log.Printf("⚠️  告警队列已满,丢弃告警: %s", path)
+			logger.Warnf("告警队列已满,丢弃告警: %s", path)
 		}
 	}
 }
@@ -0,0 +1,83 @@
+// Package badge 生成 shields.io flat 风格的 SVG 徽章，用于在 README、Wiki 等页面中嵌入状态
+package badge
+
+import (
+	"fmt"
+	"html"
+	"unicode/utf8"
+)
+
+// 常用颜色，与 shields.io 的命名颜色一致
+const (
+	ColorBrightGreen = "#4c1"
+	ColorGreen       = "#97ca00"
+	ColorYellow      = "#dfb317"
+	ColorOrange      = "#fe7d37"
+	ColorRed         = "#e05d44"
+	ColorBlue        = "#007ec6"
+	ColorGrey        = "#9f9f9f"
+)
+
+// horizontalPadding 文字两侧的留白（像素）
+const horizontalPadding = 10
+
+// UptimeColor 按可用率（百分比）选择颜色
+func UptimeColor(percent float64) string {
+	switch {
+	case percent >= 99.9:
+		return ColorBrightGreen
+	case percent >= 99:
+		return ColorGreen
+	case percent >= 95:
+		return ColorYellow
+	case percent >= 90:
+		return ColorOrange
+	default:
+		return ColorRed
+	}
+}
+
+// textWidth 估算 11px Verdana 下文本的宽度，宽字符（如中文）按两倍计算
+func textWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		switch {
+		case r == 'i' || r == 'l' || r == '.' || r == ',' || r == ':' || r == '|' || r == '!' || r == '\'':
+			width += 4
+		case r == ' ' || r == 'f' || r == 'j' || r == 't' || r == 'r' || r == '(' || r == ')' || r == '-':
+			width += 5
+		case r == 'm' || r == 'w' || r == 'M' || r == 'W' || r == '%':
+			width += 10
+		case utf8.RuneLen(r) > 2:
+			width += 12
+		default:
+			width += 7
+		}
+	}
+	return width
+}
+
+// Render 生成左侧为标签、右侧为内容的徽章
+func Render(label, message, color string) []byte {
+	labelWidth := textWidth(label) + horizontalPadding
+	messageWidth := textWidth(message) + horizontalPadding
+	width := labelWidth + messageWidth
+
+	label = html.EscapeString(label)
+	message = html.EscapeString(message)
+	color = html.EscapeString(color)
+	labelX := labelWidth * 10 / 2
+	messageX := (labelWidth + messageWidth/2) * 10
+
+	svg := fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[4]s: %[5]s">`+
+		`<title>%[4]s: %[5]s</title>`+
+		`<linearGradient id="s" x2="0" y2="100%%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`+
+		`<clipPath id="r"><rect width="%[1]d" height="20" rx="3" fill="#fff"/></clipPath>`+
+		`<g clip-path="url(#r)"><rect width="%[2]d" height="20" fill="#555"/><rect x="%[2]d" width="%[3]d" height="20" fill="%[6]s"/><rect width="%[1]d" height="20" fill="url(#s)"/></g>`+
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" text-rendering="geometricPrecision" font-size="110">`+
+		`<text x="%[7]d" y="150" fill="#010101" fill-opacity=".3" transform="scale(.1)">%[4]s</text><text x="%[7]d" y="140" transform="scale(.1)">%[4]s</text>`+
+		`<text x="%[8]d" y="150" fill="#010101" fill-opacity=".3" transform="scale(.1)">%[5]s</text><text x="%[8]d" y="140" transform="scale(.1)">%[5]s</text>`+
+		`</g></svg>`,
+		width, labelWidth, messageWidth, label, message, color, labelX, messageX)
+	return []byte(svg)
+}
@@ -0,0 +1,57 @@
+package badge
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	svg := string(Render("api <prod>", "up", ColorBrightGreen))
+
+	// 生成的内容应是合法的 XML，标签中的特殊字符需要转义
+	decoder := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		if _, err := decoder.Token(); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			t.Fatalf("SVG 不是合法的 XML: %v", err)
+		}
+	}
+	if !strings.Contains(svg, "api &lt;prod&gt;") {
+		t.Fatalf("标签未转义: %s", svg)
+	}
+	if !strings.Contains(svg, `fill="#4c1"`) {
+		t.Fatalf("缺少内容部分的颜色: %s", svg)
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	if textWidth("uptime") >= textWidth("uptime 30d") {
+		t.Error("更长的文本宽度应更大")
+	}
+	if got := textWidth("可用率"); got != 36 {
+		t.Errorf("中文宽度 = %d，期望 36", got)
+	}
+}
+
+func TestUptimeColor(t *testing.T) {
+	tests := []struct {
+		percent float64
+		want    string
+	}{
+		{percent: 100, want: ColorBrightGreen},
+		{percent: 99.5, want: ColorGreen},
+		{percent: 97, want: ColorYellow},
+		{percent: 92, want: ColorOrange},
+		{percent: 50, want: ColorRed},
+	}
+	for _, tt := range tests {
+		if got := UptimeColor(tt.percent); got != tt.want {
+			t.Errorf("UptimeColor(%v) = %s，期望 %s", tt.percent, got, tt.want)
+		}
+	}
+}
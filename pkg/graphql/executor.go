@@ -0,0 +1,475 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+// QueryType 查询根类型名称
+const QueryType = "Query"
+
+// maxDepth 查询最大嵌套层级，防止恶意构造的深层查询
+const maxDepth = 12
+
+// FieldResolveFn 字段解析函数
+type FieldResolveFn func(ctx context.Context, p ResolveParams) (interface{}, error)
+
+// FieldConfig 字段定义
+type FieldConfig struct {
+	// Type 返回值的对象类型，子字段优先使用该类型上注册的解析函数；为空时按 JSON 字段名直接投影
+	Type    string
+	Resolve FieldResolveFn
+}
+
+// ResolveParams 解析函数参数
+type ResolveParams struct {
+	// Source 父对象，根字段为 nil；列表中的结构体元素以指针形式传入
+	Source interface{}
+	Args   map[string]interface{}
+}
+
+// String 获取字符串参数
+func (p ResolveParams) String(name string) string {
+	switch v := p.Args[name].(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Int64 获取整数参数，缺省或无效时返回默认值
+func (p ResolveParams) Int64(name string, defaultValue int64) int64 {
+	switch v := p.Args[name].(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+	case string:
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
+
+// Int 获取整数参数，缺省或无效时返回默认值
+func (p ResolveParams) Int(name string, defaultValue int) int {
+	return int(p.Int64(name, int64(defaultValue)))
+}
+
+// Bool 获取布尔参数
+func (p ResolveParams) Bool(name string) bool {
+	v, _ := p.Args[name].(bool)
+	return v
+}
+
+// Schema 查询结构，由对象类型及其字段解析函数组成
+type Schema struct {
+	types map[string]map[string]*FieldConfig
+}
+
+// NewSchema 创建空的 Schema
+func NewSchema() *Schema {
+	return &Schema{types: make(map[string]map[string]*FieldConfig)}
+}
+
+// AddField 为对象类型注册字段，typeName 为 QueryType 时注册根查询字段
+func (s *Schema) AddField(typeName, fieldName string, field *FieldConfig) {
+	fields, ok := s.types[typeName]
+	if !ok {
+		fields = make(map[string]*FieldConfig)
+		s.types[typeName] = fields
+	}
+	fields[fieldName] = field
+}
+
+func (s *Schema) field(typeName, fieldName string) *FieldConfig {
+	if typeName == "" {
+		return nil
+	}
+	return s.types[typeName][fieldName]
+}
+
+// Request 查询请求
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Response 查询结果
+type Response struct {
+	Data   interface{} `json:"data"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Error 查询错误，Path 指向出错的字段
+type Error struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+// Execute 执行查询，单个字段解析失败时该字段返回 null 并在 errors 中记录原因
+func (s *Schema) Execute(ctx context.Context, req Request) *Response {
+	doc, err := Parse(req.Query)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+	if op.Type != "query" {
+		return &Response{Errors: []*Error{{Message: fmt.Sprintf("不支持 %s 操作", op.Type)}}}
+	}
+
+	e := &executor{schema: s, doc: doc, variables: make(map[string]interface{})}
+	for _, def := range op.Variables {
+		if v, ok := req.Variables[def.Name]; ok {
+			e.variables[def.Name] = v
+		} else if def.HasDefault {
+			e.variables[def.Name] = e.resolveValue(def.DefaultValue)
+		}
+	}
+
+	if batchFromContext(ctx) == nil {
+		ctx = WithBatch(ctx)
+	}
+	data := e.executeSelectionSet(ctx, QueryType, nil, op.SelectionSet, nil, 0)
+	return &Response{Data: data, Errors: e.errors}
+}
+
+// selectOperation 选择要执行的操作，文档包含多个操作时必须指定名称
+func selectOperation(doc *Document, name string) (*Operation, error) {
+	if name == "" {
+		if len(doc.Operations) > 1 {
+			return nil, fmt.Errorf("查询包含多个操作，需要指定 operationName")
+		}
+		return doc.Operations[0], nil
+	}
+	for _, op := range doc.Operations {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("未找到操作 %s", name)
+}
+
+type executor struct {
+	schema    *Schema
+	doc       *Document
+	variables map[string]interface{}
+	errors    []*Error
+}
+
+func (e *executor) addError(path []interface{}, format string, args ...interface{}) {
+	e.errors = append(e.errors, &Error{Message: fmt.Sprintf(format, args...), Path: path})
+}
+
+// collectedField 同一返回键下合并的字段
+type collectedField struct {
+	key    string
+	fields []*Field
+}
+
+// collectFields 展开片段并按返回键合并字段，保持查询中的顺序
+func (e *executor) collectFields(typeName string, selections []Selection, result []*collectedField, visited map[string]bool) []*collectedField {
+	for _, selection := range selections {
+		switch sel := selection.(type) {
+		case *Field:
+			if !e.shouldInclude(sel.Directives) {
+				continue
+			}
+			key := sel.ResponseKey()
+			merged := false
+			for _, cf := range result {
+				if cf.key == key {
+					cf.fields = append(cf.fields, sel)
+					merged = true
+					break
+				}
+			}
+			if !merged {
+				result = append(result, &collectedField{key: key, fields: []*Field{sel}})
+			}
+		case *FragmentSpread:
+			if !e.shouldInclude(sel.Directives) || visited[sel.Name] {
+				continue
+			}
+			visited[sel.Name] = true
+			fragment, ok := e.doc.Fragments[sel.Name]
+			if !ok {
+				e.addError(nil, "片段 %s 未定义", sel.Name)
+				continue
+			}
+			if typeMatches(fragment.TypeCondition, typeName) {
+				result = e.collectFields(typeName, fragment.SelectionSet, result, visited)
+			}
+		case *InlineFragment:
+			if e.shouldInclude(sel.Directives) && typeMatches(sel.TypeCondition, typeName) {
+				result = e.collectFields(typeName, sel.SelectionSet, result, visited)
+			}
+		}
+	}
+	return result
+}
+
+// typeMatches 判断片段类型条件是否适用，未声明类型的对象接受任意条件
+func typeMatches(condition, typeName string) bool {
+	return condition == "" || typeName == "" || condition == typeName
+}
+
+// shouldInclude 处理 @skip 和 @include 指令
+func (e *executor) shouldInclude(directives []*Directive) bool {
+	for _, d := range directives {
+		cond, _ := e.resolveValue(d.Arguments["if"]).(bool)
+		if d.Name == "skip" && cond {
+			return false
+		}
+		if d.Name == "include" && !cond {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveValue 替换参数中的变量引用
+func (e *executor) resolveValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case Variable:
+		return e.variables[string(v)]
+	case EnumValue:
+		return string(v)
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = e.resolveValue(item)
+		}
+		return list
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			object[k] = e.resolveValue(item)
+		}
+		return object
+	}
+	return value
+}
+
+func (e *executor) resolveArguments(args map[string]interface{}) map[string]interface{} {
+	resolved := make(map[string]interface{}, len(args))
+	for k, v := range args {
+		resolved[k] = e.resolveValue(v)
+	}
+	return resolved
+}
+
+func (e *executor) executeSelectionSet(ctx context.Context, typeName string, source interface{}, selections []Selection, path []interface{}, depth int) *orderedMap {
+	result := &orderedMap{values: make(map[string]interface{})}
+
+	var plain map[string]interface{}
+	plainLoaded := false
+	for _, cf := range e.collectFields(typeName, selections, nil, make(map[string]bool)) {
+		field := cf.fields[0]
+		fieldPath := appendPath(path, cf.key)
+		var subSelections []Selection
+		for _, f := range cf.fields {
+			subSelections = append(subSelections, f.SelectionSet...)
+		}
+
+		if field.Name == "__typename" {
+			if typeName == "" {
+				result.set(cf.key, nil)
+			} else {
+				result.set(cf.key, typeName)
+			}
+			continue
+		}
+
+		if def := e.schema.field(typeName, field.Name); def != nil {
+			value, err := def.Resolve(ctx, ResolveParams{Source: source, Args: e.resolveArguments(field.Arguments)})
+			if err != nil {
+				e.addError(fieldPath, "%s", err.Error())
+				result.set(cf.key, nil)
+				continue
+			}
+			result.set(cf.key, e.completeValue(ctx, def.Type, value, subSelections, fieldPath, depth+1))
+			continue
+		}
+
+		if typeName == QueryType {
+			e.addError(fieldPath, "查询中不存在字段 %s", field.Name)
+			result.set(cf.key, nil)
+			continue
+		}
+
+		// 未注册解析函数的字段直接取对象的 JSON 字段
+		if !plainLoaded {
+			plainLoaded = true
+			var err error
+			if plain, err = toPlainMap(source); err != nil {
+				e.addError(path, "%s", err.Error())
+			}
+		}
+		result.set(cf.key, e.completeValue(ctx, "", plain[field.Name], subSelections, fieldPath, depth+1))
+	}
+	return result
+}
+
+// completeValue 按子字段选择构造返回值，列表逐项处理
+func (e *executor) completeValue(ctx context.Context, typeName string, value interface{}, selections []Selection, path []interface{}, depth int) interface{} {
+	if value == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil
+	}
+	if len(selections) == 0 {
+		return value
+	}
+	if depth > maxDepth {
+		e.addError(path, "查询嵌套层级超过限制 %d", maxDepth)
+		return nil
+	}
+
+	elem := reflect.Indirect(rv)
+	if (elem.Kind() == reflect.Slice || elem.Kind() == reflect.Array) && elem.Type().Elem().Kind() != reflect.Uint8 {
+		list := make([]interface{}, elem.Len())
+		for i := range list {
+			item := elem.Index(i)
+			var itemValue interface{}
+			if item.Kind() == reflect.Struct && item.CanAddr() {
+				itemValue = item.Addr().Interface()
+			} else {
+				itemValue = item.Interface()
+			}
+			list[i] = e.completeValue(ctx, typeName, itemValue, selections, appendPath(path, i), depth)
+		}
+		return list
+	}
+	if typeName == "" {
+		plain, err := toPlainMap(value)
+		if err != nil {
+			e.addError(path, "%s", err.Error())
+			return nil
+		}
+		value = plain
+	}
+	return e.executeSelectionSet(ctx, typeName, value, selections, path, depth)
+}
+
+func appendPath(path []interface{}, segment interface{}) []interface{} {
+	next := make([]interface{}, len(path), len(path)+1)
+	copy(next, path)
+	return append(next, segment)
+}
+
+// toPlainMap 将对象按 JSON 序列化规则转换为 map，字段名与 REST 接口保持一致
+func toPlainMap(source interface{}) (map[string]interface{}, error) {
+	if m, ok := source.(map[string]interface{}); ok {
+		return m, nil
+	}
+	data, err := json.Marshal(source)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var m map[string]interface{}
+	if err := decoder.Decode(&m); err != nil {
+		return nil, fmt.Errorf("该字段不是对象，不能选择子字段")
+	}
+	return m, nil
+}
+
+// orderedMap 按查询中字段的顺序输出 JSON
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (m *orderedMap) set(key string, value interface{}) {
+	if _, exists := m.values[key]; !exists {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		buf.Write(k)
+		buf.WriteByte(':')
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+type batchContextKey struct{}
+
+type batchEntry struct {
+	once  sync.Once
+	value interface{}
+	err   error
+}
+
+// batchCache 请求级缓存
+type batchCache struct {
+	mu      sync.Mutex
+	entries map[string]*batchEntry
+}
+
+// WithBatch 创建请求级缓存，使用同一 ctx 执行的多个查询共享 Load 加载的数据
+func WithBatch(ctx context.Context) context.Context {
+	return context.WithValue(ctx, batchContextKey{}, &batchCache{entries: make(map[string]*batchEntry)})
+}
+
+func batchFromContext(ctx context.Context) *batchCache {
+	cache, _ := ctx.Value(batchContextKey{}).(*batchCache)
+	return cache
+}
+
+// Load 在请求级缓存中按 key 加载数据，同一请求内只加载一次
+// 解析函数可以借此把列表中每个元素的关联查询合并为一次批量查询，避免 N+1 查询
+func Load(ctx context.Context, key string, fn func() (interface{}, error)) (interface{}, error) {
+	cache := batchFromContext(ctx)
+	if cache == nil {
+		return fn()
+	}
+
+	cache.mu.Lock()
+	entry, ok := cache.entries[key]
+	if !ok {
+		entry = &batchEntry{}
+		cache.entries[key] = entry
+	}
+	cache.mu.Unlock()
+
+	entry.once.Do(func() {
+		entry.value, entry.err = fn()
+	})
+	return entry.value, entry.err
+}
@@ -0,0 +1,345 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testOwner struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type testAgent struct {
+	ID    string     `json:"id"`
+	Name  string     `json:"name"`
+	Owner *testOwner `json:"owner,omitempty"`
+}
+
+type testNode struct {
+	ID int `json:"id"`
+}
+
+func newTestSchema(loads *int) *Schema {
+	agents := []testAgent{
+		{ID: "a1", Name: "web", Owner: &testOwner{Name: "ops", Email: "ops@example.com"}},
+		{ID: "a2", Name: "db"},
+	}
+
+	s := NewSchema()
+	s.AddField(QueryType, "agents", &FieldConfig{
+		Type: "Agent",
+		Resolve: func(ctx context.Context, p ResolveParams) (interface{}, error) {
+			limit := p.Int("limit", len(agents))
+			return agents[:min(limit, len(agents))], nil
+		},
+	})
+	s.AddField(QueryType, "echo", &FieldConfig{
+		Resolve: func(ctx context.Context, p ResolveParams) (interface{}, error) {
+			return p.Args["value"], nil
+		},
+	})
+	s.AddField(QueryType, "fail", &FieldConfig{
+		Resolve: func(ctx context.Context, p ResolveParams) (interface{}, error) {
+			return nil, errors.New("boom")
+		},
+	})
+	s.AddField(QueryType, "node", &FieldConfig{
+		Type: "Node",
+		Resolve: func(ctx context.Context, p ResolveParams) (interface{}, error) {
+			return &testNode{ID: 1}, nil
+		},
+	})
+	s.AddField("Node", "child", &FieldConfig{
+		Type: "Node",
+		Resolve: func(ctx context.Context, p ResolveParams) (interface{}, error) {
+			return &testNode{ID: p.Source.(*testNode).ID + 1}, nil
+		},
+	})
+	s.AddField("Agent", "score", &FieldConfig{
+		Resolve: func(ctx context.Context, p ResolveParams) (interface{}, error) {
+			// 列表中每个元素共享一次加载
+			scores, err := Load(ctx, "scores", func() (interface{}, error) {
+				*loads++
+				return map[string]int{"a1": 90, "a2": 70}, nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			return scores.(map[string]int)[p.Source.(*testAgent).ID], nil
+		},
+	})
+	return s
+}
+
+func execute(t *testing.T, s *Schema, req Request) (string, []*Error) {
+	t.Helper()
+	resp := s.Execute(context.Background(), req)
+	data, err := json.Marshal(resp.Data)
+	if err != nil {
+		t.Fatalf("序列化结果失败: %v", err)
+	}
+	return string(data), resp.Errors
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		operation  string
+		variables  map[string]interface{}
+		want       string
+		wantErrors []string
+	}{
+		{
+			name:  "按查询顺序返回字段",
+			query: `{ agents { name id } }`,
+			want:  `{"agents":[{"name":"web","id":"a1"},{"name":"db","id":"a2"}]}`,
+		},
+		{
+			name:  "别名",
+			query: `{ first: agents(limit: 1) { key: id } all: agents { id } }`,
+			want:  `{"first":[{"key":"a1"}],"all":[{"id":"a1"},{"id":"a2"}]}`,
+		},
+		{
+			name:  "同名字段合并子选择",
+			query: `{ agents(limit: 1) { id } agents(limit: 1) { name } }`,
+			want:  `{"agents":[{"id":"a1","name":"web"}]}`,
+		},
+		{
+			name:  "嵌套对象按 JSON 字段投影",
+			query: `{ agents { owner { email } } }`,
+			want:  `{"agents":[{"owner":{"email":"ops@example.com"}},{"owner":null}]}`,
+		},
+		{
+			name:      "变量",
+			query:     `query($n: Int) { agents(limit: $n) { id } }`,
+			variables: map[string]interface{}{"n": float64(1)},
+			want:      `{"agents":[{"id":"a1"}]}`,
+		},
+		{
+			name:  "变量默认值",
+			query: `query($n: Int = 1) { agents(limit: $n) { id } }`,
+			want:  `{"agents":[{"id":"a1"}]}`,
+		},
+		{
+			name:      "传入的变量优先于默认值",
+			query:     `query($n: Int = 1) { agents(limit: $n) { id } }`,
+			variables: map[string]interface{}{"n": float64(2)},
+			want:      `{"agents":[{"id":"a1"},{"id":"a2"}]}`,
+		},
+		{
+			name:      "列表和对象参数中的变量",
+			query:     `query($v: String) { echo(value: {list: [$v, "b"], kind: ENUM}) }`,
+			variables: map[string]interface{}{"v": "a"},
+			want:      `{"echo":{"kind":"ENUM","list":["a","b"]}}`,
+		},
+		{
+			name:      "skip 和 include 指令",
+			query:     `query($hide: Boolean) { agents(limit: 1) { id @skip(if: $hide) name @include(if: false) owner @include(if: true) { name } } }`,
+			variables: map[string]interface{}{"hide": true},
+			want:      `{"agents":[{"owner":{"name":"ops"}}]}`,
+		},
+		{
+			name:  "命名片段和内联片段",
+			query: `{ agents(limit: 1) { ...f ... on Agent { name } ... on Monitor { target } } } fragment f on Agent { id }`,
+			want:  `{"agents":[{"id":"a1","name":"web"}]}`,
+		},
+		{
+			name:  "片段互相引用不会无限展开",
+			query: `{ agents(limit: 1) { ...A } } fragment A on Agent { id ...B } fragment B on Agent { name ...A }`,
+			want:  `{"agents":[{"id":"a1","name":"web"}]}`,
+		},
+		{
+			name:  "__typename",
+			query: `{ __typename node { __typename id } }`,
+			want:  `{"__typename":"Query","node":{"__typename":"Node","id":1}}`,
+		},
+		{
+			name:      "指定操作名称",
+			query:     `query A { node { id } } query B { agents(limit: 1) { id } }`,
+			operation: "B",
+			want:      `{"agents":[{"id":"a1"}]}`,
+		},
+		{
+			name:       "解析失败的字段返回 null",
+			query:      `{ fail node { id } }`,
+			want:       `{"fail":null,"node":{"id":1}}`,
+			wantErrors: []string{"boom"},
+		},
+		{
+			name:       "不存在的根字段",
+			query:      `{ missing }`,
+			want:       `{"missing":null}`,
+			wantErrors: []string{"不存在字段 missing"},
+		},
+		{
+			name:       "未定义的片段",
+			query:      `{ agents(limit: 1) { ...nope id } }`,
+			want:       `{"agents":[{"id":"a1"}]}`,
+			wantErrors: []string{"片段 nope 未定义"},
+		},
+		{
+			name:       "标量字段不能选择子字段",
+			query:      `{ agents(limit: 1) { id { x } } }`,
+			want:       `{"agents":[{"id":null}]}`,
+			wantErrors: []string{"不是对象"},
+		},
+		{
+			name:       "多个操作未指定名称",
+			query:      `query A { node { id } } query B { node { id } }`,
+			want:       `null`,
+			wantErrors: []string{"需要指定 operationName"},
+		},
+		{
+			name:       "操作名称不存在",
+			query:      `query A { node { id } }`,
+			operation:  "B",
+			want:       `null`,
+			wantErrors: []string{"未找到操作 B"},
+		},
+		{
+			name:       "不支持 mutation",
+			query:      `mutation { node { id } }`,
+			want:       `null`,
+			wantErrors: []string{"不支持 mutation"},
+		},
+		{
+			name:       "语法错误",
+			query:      `{ agents { id }`,
+			want:       `null`,
+			wantErrors: []string{"意外结束"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var loads int
+			got, errs := execute(t, newTestSchema(&loads), Request{Query: tt.query, OperationName: tt.operation, Variables: tt.variables})
+			if got != tt.want {
+				t.Errorf("结果为 %s\n期望 %s", got, tt.want)
+			}
+			if len(errs) != len(tt.wantErrors) {
+				t.Fatalf("错误为 %v，期望 %v", errorMessages(errs), tt.wantErrors)
+			}
+			for i, want := range tt.wantErrors {
+				if !strings.Contains(errs[i].Message, want) {
+					t.Errorf("错误 %q 中应包含 %q", errs[i].Message, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteErrorPath(t *testing.T) {
+	var loads int
+	_, errs := execute(t, newTestSchema(&loads), Request{Query: `{ agents(limit: 1) { id { x } } }`})
+	if len(errs) != 1 {
+		t.Fatalf("错误为 %v", errorMessages(errs))
+	}
+	path, _ := json.Marshal(errs[0].Path)
+	if string(path) != `["agents",0,"id"]` {
+		t.Fatalf("错误路径为 %s", path)
+	}
+}
+
+// nestedNodeQuery 构造 node 下嵌套 levels 层 child 的查询
+func nestedNodeQuery(levels int) string {
+	return "{ node { " + strings.Repeat("child { ", levels) + "id" + strings.Repeat(" }", levels) + " } }"
+}
+
+func TestExecuteMaxDepth(t *testing.T) {
+	var loads int
+	s := newTestSchema(&loads)
+
+	// node 位于第 1 层，嵌套 maxDepth-1 层 child 时最内层刚好达到上限
+	_, errs := execute(t, s, Request{Query: nestedNodeQuery(maxDepth - 1)})
+	if len(errs) != 0 {
+		t.Fatalf("未超过层级限制时不应报错: %v", errorMessages(errs))
+	}
+
+	got, errs := execute(t, s, Request{Query: nestedNodeQuery(maxDepth)})
+	if len(errs) != 1 || !strings.Contains(errs[0].Message, "嵌套层级超过限制") {
+		t.Fatalf("超过层级限制时应报错，得到 %v", errorMessages(errs))
+	}
+	if !strings.Contains(got, `"child":null`) {
+		t.Fatalf("超过层级的字段应返回 null: %s", got)
+	}
+
+	// 通过片段在嵌套字段中递归引用自身，由层级限制终止
+	_, errs = execute(t, s, Request{Query: `{ node { ...N } } fragment N on Node { id child { ...N } }`})
+	if len(errs) != 1 || !strings.Contains(errs[0].Message, "嵌套层级超过限制") {
+		t.Fatalf("递归片段应被层级限制终止，得到 %v", errorMessages(errs))
+	}
+}
+
+func TestLoadSharesResultsWithinRequest(t *testing.T) {
+	var loads int
+	s := newTestSchema(&loads)
+
+	got, errs := execute(t, s, Request{Query: `{ agents { id score } }`})
+	if len(errs) != 0 {
+		t.Fatal(errorMessages(errs))
+	}
+	if got != `{"agents":[{"id":"a1","score":90},{"id":"a2","score":70}]}` {
+		t.Fatalf("结果为 %s", got)
+	}
+	if loads != 1 {
+		t.Fatalf("同一请求内应只加载一次，实际加载 %d 次", loads)
+	}
+
+	// 批量请求共享同一个 ctx 时也只加载一次
+	loads = 0
+	ctx := WithBatch(context.Background())
+	s.Execute(ctx, Request{Query: `{ agents { score } }`})
+	s.Execute(ctx, Request{Query: `{ agents { score } }`})
+	if loads != 1 {
+		t.Fatalf("批量请求应只加载一次，实际加载 %d 次", loads)
+	}
+
+	// 没有请求级缓存时每次都加载
+	loads = 0
+	for i := 0; i < 2; i++ {
+		if _, err := Load(context.Background(), "k", func() (interface{}, error) { loads++; return nil, nil }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if loads != 2 {
+		t.Fatalf("没有请求级缓存时应每次加载，实际加载 %d 次", loads)
+	}
+}
+
+func TestResolveParams(t *testing.T) {
+	p := ResolveParams{Args: map[string]interface{}{
+		"int":    int64(3),
+		"float":  float64(4),
+		"number": json.Number("5"),
+		"string": "6",
+		"bad":    "x",
+		"bool":   true,
+		"name":   "web",
+		"enum":   12,
+	}}
+
+	for name, want := range map[string]int64{"int": 3, "float": 4, "number": 5, "string": 6, "bad": -1, "missing": -1} {
+		if got := p.Int64(name, -1); got != want {
+			t.Errorf("Int64(%q) = %d，期望 %d", name, got, want)
+		}
+	}
+	if !p.Bool("bool") || p.Bool("name") {
+		t.Error("Bool 参数解析错误")
+	}
+	if p.String("name") != "web" || p.String("enum") != "12" || p.String("missing") != "" {
+		t.Error("String 参数解析错误")
+	}
+}
+
+func errorMessages(errs []*Error) []string {
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.Message)
+	}
+	return messages
+}
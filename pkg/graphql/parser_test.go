@@ -0,0 +1,132 @@
+package graphql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	doc, err := Parse(`
+		# 注释会被忽略
+		query Agents($limit: Int = 10, $tags: [String!]!) {
+			list: agents(limit: $limit, tags: $tags, os: LINUX, online: true, filter: {name: "a\"b", ratio: 1.5, ids: [1, -2]}) {
+				id
+				... on Agent @include(if: true) { name }
+				...agentFields @skip(if: false)
+			}
+		}
+		fragment agentFields on Agent { hostname }
+	`)
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+
+	if len(doc.Operations) != 1 || len(doc.Fragments) != 1 {
+		t.Fatalf("操作 %d 个、片段 %d 个，期望各 1 个", len(doc.Operations), len(doc.Fragments))
+	}
+	op := doc.Operations[0]
+	if op.Type != "query" || op.Name != "Agents" {
+		t.Fatalf("操作为 %s %s", op.Type, op.Name)
+	}
+	if len(op.Variables) != 2 || !op.Variables[0].HasDefault || op.Variables[0].DefaultValue != int64(10) || op.Variables[1].HasDefault {
+		t.Fatalf("变量定义解析错误: %+v %+v", op.Variables[0], op.Variables[1])
+	}
+
+	field := op.SelectionSet[0].(*Field)
+	if field.Alias != "list" || field.Name != "agents" || field.ResponseKey() != "list" {
+		t.Fatalf("别名解析错误: %+v", field)
+	}
+	wantArgs := map[string]interface{}{
+		"limit":  Variable("limit"),
+		"tags":   Variable("tags"),
+		"os":     EnumValue("LINUX"),
+		"online": true,
+		"filter": map[string]interface{}{
+			"name":  `a"b`,
+			"ratio": 1.5,
+			"ids":   []interface{}{int64(1), int64(-2)},
+		},
+	}
+	if !reflect.DeepEqual(field.Arguments, wantArgs) {
+		t.Fatalf("参数解析错误:\n得到 %#v\n期望 %#v", field.Arguments, wantArgs)
+	}
+
+	if len(field.SelectionSet) != 3 {
+		t.Fatalf("子字段 %d 个，期望 3 个", len(field.SelectionSet))
+	}
+	inline, ok := field.SelectionSet[1].(*InlineFragment)
+	if !ok || inline.TypeCondition != "Agent" || len(inline.Directives) != 1 || inline.Directives[0].Name != "include" {
+		t.Fatalf("内联片段解析错误: %#v", field.SelectionSet[1])
+	}
+	spread, ok := field.SelectionSet[2].(*FragmentSpread)
+	if !ok || spread.Name != "agentFields" || spread.Directives[0].Name != "skip" {
+		t.Fatalf("片段展开解析错误: %#v", field.SelectionSet[2])
+	}
+	if doc.Fragments["agentFields"].TypeCondition != "Agent" {
+		t.Fatalf("片段类型条件解析错误: %+v", doc.Fragments["agentFields"])
+	}
+}
+
+func TestParseStrings(t *testing.T) {
+	tests := []struct {
+		literal string
+		want    string
+	}{
+		{literal: `"plain"`, want: "plain"},
+		{literal: `"tab\tnew\nline"`, want: "tab\tnew\nline"},
+		{literal: `"你好"`, want: "你好"},
+		{literal: `"slash\/back\\"`, want: `slash/back\`},
+		{literal: `"""  block "quoted"  """`, want: `block "quoted"`},
+	}
+	for _, tt := range tests {
+		doc, err := Parse(`{ f(s: ` + tt.literal + `) }`)
+		if err != nil {
+			t.Errorf("解析 %s 失败: %v", tt.literal, err)
+			continue
+		}
+		if got := doc.Operations[0].SelectionSet[0].(*Field).Arguments["s"]; got != tt.want {
+			t.Errorf("解析 %s 得到 %q，期望 %q", tt.literal, got, tt.want)
+		}
+	}
+}
+
+func TestParseMalformed(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		wantErr string
+	}{
+		{name: "空查询", query: ``, wantErr: "没有可执行的操作"},
+		{name: "只有片段", query: `fragment f on A { id }`, wantErr: "没有可执行的操作"},
+		{name: "选择集为空", query: `{ }`, wantErr: "选择集不能为空"},
+		{name: "选择集未结束", query: `{ agents { id }`, wantErr: "意外结束"},
+		{name: "参数未结束", query: `{ agents(limit: 1 }`, wantErr: "意外的"},
+		{name: "参数缺少值", query: `{ agents(limit: ) { id } }`, wantErr: "意外的"},
+		{name: "字符串未结束", query: `{ f(s: "abc) }`, wantErr: "字符串未结束"},
+		{name: "字符串中换行", query: "{ f(s: \"a\nb\") }", wantErr: "字符串未结束"},
+		{name: "块字符串未结束", query: `{ f(s: """abc) }`, wantErr: "字符串未结束"},
+		{name: "无效转义", query: `{ f(s: "\q") }`, wantErr: "无效的转义字符"},
+		{name: "无效的 Unicode 转义", query: `{ f(s: "\u12") }`, wantErr: "无效的转义字符"},
+		{name: "无效数字", query: `{ f(n: 1.2.3) }`, wantErr: "无效的数字"},
+		{name: "单独的负号", query: `{ f(n: -) }`, wantErr: "无效的数字"},
+		{name: "无法识别的字符", query: `{ f% }`, wantErr: "无法识别的字符"},
+		{name: "单个点", query: `{ .f }`, wantErr: "无法识别的字符"},
+		{name: "默认值引用变量", query: `query($a: Int = $b) { f }`, wantErr: "不允许使用变量"},
+		{name: "变量缺少类型", query: `query($a) { f }`, wantErr: "意外的"},
+		{name: "片段重复定义", query: `{ ...f } fragment f on A { id } fragment f on A { id }`, wantErr: "重复定义"},
+		{name: "片段缺少类型条件", query: `{ ...f } fragment f { id }`, wantErr: "意外的"},
+		{name: "顶层无法识别", query: `agents { id }`, wantErr: "意外的"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			if err == nil {
+				t.Fatalf("解析 %q 应该失败", tt.query)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("错误 %q 中应包含 %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
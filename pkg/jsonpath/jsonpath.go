@@ -0,0 +1,108 @@
+// Package jsonpath 实现 HTTP 监控响应断言使用的 JSONPath 子集：
+// $ 表示根节点，.key 和 ['key'] 取对象字段，[n] 取数组元素（负数从末尾计）
+package jsonpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// step 路径中的一级
+type step struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// Path 编译后的 JSONPath
+type Path struct {
+	raw   string
+	steps []step
+}
+
+// Compile 解析 JSONPath，如 $.data.items[0].status、$['x-key'][-1]
+func Compile(path string) (*Path, error) {
+	s := strings.TrimSpace(path)
+	if !strings.HasPrefix(s, "$") {
+		return nil, fmt.Errorf("jsonpath must start with $: %s", path)
+	}
+	p := &Path{raw: s}
+	for i := 1; i < len(s); {
+		switch s[i] {
+		case '.':
+			i++
+			start := i
+			for i < len(s) && s[i] != '.' && s[i] != '[' {
+				i++
+			}
+			if start == i {
+				return nil, fmt.Errorf("empty field name at position %d: %s", start, path)
+			}
+			p.steps = append(p.steps, step{key: s[start:i]})
+		case '[':
+			if i+1 < len(s) && (s[i+1] == '\'' || s[i+1] == '"') {
+				quote := s[i+1]
+				end := strings.IndexByte(s[i+2:], quote)
+				if end < 0 {
+					return nil, fmt.Errorf("unterminated quoted field at position %d: %s", i, path)
+				}
+				key := s[i+2 : i+2+end]
+				i += 2 + end + 1
+				if i >= len(s) || s[i] != ']' {
+					return nil, fmt.Errorf("missing ] at position %d: %s", i, path)
+				}
+				i++
+				p.steps = append(p.steps, step{key: key})
+				continue
+			}
+			end := strings.IndexByte(s[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("missing ] at position %d: %s", i, path)
+			}
+			index, err := strconv.Atoi(strings.TrimSpace(s[i+1 : i+end]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid array index %q: %s", s[i+1:i+end], path)
+			}
+			p.steps = append(p.steps, step{index: index, isIndex: true})
+			i += end + 1
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d: %s", s[i], i, path)
+		}
+	}
+	return p, nil
+}
+
+// String 返回原始路径
+func (p *Path) String() string {
+	return p.raw
+}
+
+// Lookup 在 json.Unmarshal 解码出的值中查找路径对应的值，路径不存在时 ok 为 false
+func (p *Path) Lookup(v interface{}) (interface{}, bool) {
+	for _, st := range p.steps {
+		if st.isIndex {
+			arr, ok := v.([]interface{})
+			if !ok {
+				return nil, false
+			}
+			index := st.index
+			if index < 0 {
+				index += len(arr)
+			}
+			if index < 0 || index >= len(arr) {
+				return nil, false
+			}
+			v = arr[index]
+			continue
+		}
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if v, ok = obj[st.key]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
@@ -0,0 +1,73 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCompileErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"data.status",
+		"$.",
+		"$..a",
+		"$[abc]",
+		"$[0",
+		"$['a",
+		"$['a'x",
+		"$a",
+	}
+	for _, path := range tests {
+		if _, err := Compile(path); err == nil {
+			t.Errorf("Compile(%q) 应该失败", path)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(`{
+		"status": "ok",
+		"data": {"items": [{"id": 1}, {"id": 2, "tags": ["a", "b"]}], "x-key": null},
+		"a.b": true
+	}`), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path   string
+		want   string
+		wantOK bool
+	}{
+		{path: "$", want: "", wantOK: true},
+		{path: "$.status", want: `"ok"`, wantOK: true},
+		{path: " $.data.items[0].id ", want: "1", wantOK: true},
+		{path: "$.data.items[-1].tags[1]", want: `"b"`, wantOK: true},
+		{path: "$['data'][\"x-key\"]", want: "null", wantOK: true},
+		{path: "$['a.b']", want: "true", wantOK: true},
+		{path: "$.missing", wantOK: false},
+		{path: "$.data.items[2]", wantOK: false},
+		{path: "$.data.items[-3]", wantOK: false},
+		{path: "$.status.length", wantOK: false},
+		{path: "$.data[0]", wantOK: false},
+	}
+	for _, tt := range tests {
+		p, err := Compile(tt.path)
+		if err != nil {
+			t.Errorf("Compile(%q) 失败: %v", tt.path, err)
+			continue
+		}
+		v, ok := p.Lookup(doc)
+		if ok != tt.wantOK {
+			t.Errorf("Lookup(%q) ok = %v，期望 %v", tt.path, ok, tt.wantOK)
+			continue
+		}
+		if !ok || tt.want == "" {
+			continue
+		}
+		got, _ := json.Marshal(v)
+		if string(got) != tt.want {
+			t.Errorf("Lookup(%q) = %s，期望 %s", tt.path, got, tt.want)
+		}
+	}
+}
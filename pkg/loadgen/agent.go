@@ -0,0 +1,374 @@
+package loadgen
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/dushixiang/pika/internal/protocol"
+	"github.com/dushixiang/pika/pkg/version"
+	"github.com/gorilla/websocket"
+)
+
+var errInjectedDisconnect = errors.New("主动注入断线")
+
+const gb = 1 << 30
+
+// virtualAgent 虚拟探针，按真实探针的协议注册并周期性上报模拟指标
+type virtualAgent struct {
+	runner   *Runner
+	id       string
+	hostname string
+	rng      *rand.Rand
+
+	// 机器规格，创建后保持不变
+	cores       int
+	memoryTotal uint64
+	diskTotal   uint64
+	cpuBaseline float64
+	memBaseline float64
+	bootTime    time.Time
+
+	// 随时间变化的状态
+	cpu        float64
+	mem        float64
+	diskUsed   uint64
+	sentTotal  uint64
+	recvTotal  uint64
+	readBytes  uint64
+	writeBytes uint64
+	spikeLeft  int
+}
+
+func newVirtualAgent(runner *Runner, index int, rng *rand.Rand) *virtualAgent {
+	coreChoices := []int{1, 2, 4, 8, 16, 32}
+	memoryChoices := []uint64{1, 2, 4, 8, 16, 32, 64}
+	diskChoices := []uint64{20, 40, 80, 160, 500, 1000}
+
+	a := &virtualAgent{
+		runner:      runner,
+		id:          fmt.Sprintf("%s-%04d", runner.cfg.IDPrefix, index),
+		hostname:    fmt.Sprintf("%s-host-%04d", runner.cfg.IDPrefix, index),
+		rng:         rng,
+		cores:       coreChoices[rng.Intn(len(coreChoices))],
+		memoryTotal: memoryChoices[rng.Intn(len(memoryChoices))] * gb,
+		diskTotal:   diskChoices[rng.Intn(len(diskChoices))] * gb,
+		cpuBaseline: 5 + rng.Float64()*45,
+		memBaseline: 20 + rng.Float64()*50,
+		bootTime:    time.Now().Add(-time.Duration(rng.Intn(90*24)) * time.Hour),
+	}
+	a.cpu = a.cpuBaseline
+	a.mem = a.memBaseline
+	a.diskUsed = uint64(float64(a.diskTotal) * (0.2 + rng.Float64()*0.5))
+	return a
+}
+
+// run 保持虚拟探针在线，断线后随机等待一段时间重连
+func (a *virtualAgent) run(ctx context.Context) {
+	stats := a.runner.stats
+	for {
+		err := a.runOnce(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			stats.Disconnects.Add(1)
+		}
+
+		wait := time.Second + time.Duration(a.rng.Int63n(int64(4*time.Second)))
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// runOnce 建立一次连接并持续上报，直到出错、注入断线或 ctx 被取消
+func (a *virtualAgent) runOnce(ctx context.Context) error {
+	stats := a.runner.stats
+
+	start := time.Now()
+	conn, _, err := a.runner.dialer.DialContext(ctx, a.runner.wsURL, nil)
+	if err != nil {
+		stats.RegisterFailed.Add(1)
+		return fmt.Errorf("连接失败: %w", err)
+	}
+	defer conn.Close()
+
+	if err := a.register(conn); err != nil {
+		stats.RegisterFailed.Add(1)
+		return err
+	}
+	stats.observeRegister(time.Since(start))
+	stats.Registered.Add(1)
+	stats.Connected.Add(1)
+	defer stats.Connected.Add(-1)
+
+	// 读取并丢弃服务端下发的配置等消息，Ping 由默认处理器自动响应
+	readErr := make(chan error, 1)
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				readErr <- err
+				return
+			}
+		}
+	}()
+
+	// 与真实探针一样，连接建立后立即上报一次
+	if err := a.sendAllMetrics(conn); err != nil {
+		return err
+	}
+
+	metricsTicker := time.NewTicker(a.runner.cfg.Interval)
+	defer metricsTicker.Stop()
+	heartbeatTicker := time.NewTicker(a.runner.cfg.HeartbeatInterval)
+	defer heartbeatTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			_ = conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+				time.Now().Add(time.Second))
+			return nil
+		case err := <-readErr:
+			return fmt.Errorf("读取失败: %w", err)
+		case <-heartbeatTicker.C:
+			if err := a.send(conn, protocol.MessageTypeHeartbeat, json.RawMessage(`{}`)); err != nil {
+				return err
+			}
+		case <-metricsTicker.C:
+			if a.rng.Float64() < a.runner.cfg.DisconnectProbability {
+				return errInjectedDisconnect
+			}
+			if err := a.sendAllMetrics(conn); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// register 发送注册消息并等待服务端确认
+func (a *virtualAgent) register(conn *websocket.Conn) error {
+	req := protocol.RegisterRequest{
+		AgentInfo: protocol.AgentInfo{
+			ID:       a.id,
+			Name:     a.hostname,
+			Hostname: a.hostname,
+			OS:       "linux",
+			Arch:     "amd64",
+			Version:  version.GetAgentVersion(),
+		},
+		ApiKey: a.runner.cfg.APIKey,
+	}
+	if err := a.send(conn, protocol.MessageTypeRegister, req); err != nil {
+		return err
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(30 * time.Second))
+	var response protocol.Message
+	if err := conn.ReadJSON(&response); err != nil {
+		return fmt.Errorf("读取注册响应失败: %w", err)
+	}
+	_ = conn.SetReadDeadline(time.Time{})
+
+	switch response.Type {
+	case protocol.MessageTypeRegisterAck:
+		return nil
+	case protocol.MessageTypeRegisterErr:
+		var resp protocol.RegisterResponse
+		_ = json.Unmarshal(response.Data, &resp)
+		return fmt.Errorf("注册失败: %s", resp.Message)
+	default:
+		return fmt.Errorf("注册失败: 收到未知响应类型 %s", response.Type)
+	}
+}
+
+// sendAllMetrics 推进模拟状态并发送一轮完整的指标，与真实探针每个周期上报的内容一致
+func (a *virtualAgent) sendAllMetrics(conn *websocket.Conn) error {
+	a.step()
+
+	metrics := []struct {
+		metricType protocol.MetricType
+		data       any
+	}{
+		{protocol.MetricTypeCPU, a.cpuData()},
+		{protocol.MetricTypeMemory, a.memoryData()},
+		{protocol.MetricTypeDisk, a.diskData()},
+		{protocol.MetricTypeDiskIO, a.diskIOData()},
+		{protocol.MetricTypeNetwork, a.networkData()},
+		{protocol.MetricTypeNetworkConnection, a.networkConnectionData()},
+		{protocol.MetricTypeHost, a.hostData()},
+	}
+	for _, m := range metrics {
+		data, err := json.Marshal(m.data)
+		if err != nil {
+			return err
+		}
+		wrapper := protocol.MetricsWrapper{Type: m.metricType, Data: data}
+		if err := a.send(conn, protocol.MessageTypeMetrics, wrapper); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// send 序列化并发送消息，同时记录统计
+func (a *virtualAgent) send(conn *websocket.Conn, msgType protocol.MessageType, payload any) error {
+	stats := a.runner.stats
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	msg, err := json.Marshal(protocol.Message{Type: msgType, Data: data})
+	if err != nil {
+		return err
+	}
+
+	_ = conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+		stats.SendErrors.Add(1)
+		return fmt.Errorf("发送消息失败: %w", err)
+	}
+	stats.Messages.Add(1)
+	stats.Bytes.Add(int64(len(msg)))
+	return nil
+}
+
+// step 推进一个上报周期的模拟状态：CPU 和内存围绕基线随机游走，按概率注入负载尖峰
+func (a *virtualAgent) step() {
+	cfg := a.runner.cfg
+
+	if a.spikeLeft == 0 && cfg.SpikeProbability > 0 && a.rng.Float64() < cfg.SpikeProbability {
+		a.spikeLeft = cfg.SpikeCycles
+		a.runner.stats.Spikes.Add(1)
+	}
+
+	if a.spikeLeft > 0 {
+		a.spikeLeft--
+		a.cpu = 92 + a.rng.Float64()*8
+		a.mem = math.Max(a.mem, 90+a.rng.Float64()*8)
+	} else {
+		a.cpu = walk(a.rng, a.cpu, a.cpuBaseline, 8)
+		a.mem = walk(a.rng, a.mem, a.memBaseline, 2)
+	}
+
+	seconds := cfg.Interval.Seconds()
+	a.sentTotal += uint64(a.netRate() * seconds)
+	a.recvTotal += uint64(a.netRate() * seconds)
+	a.readBytes += uint64(a.ioRate() * seconds)
+	a.writeBytes += uint64(a.ioRate() * seconds)
+
+	// 磁盘缓慢增长，写满前回落，模拟日志轮转
+	a.diskUsed += uint64(a.rng.Int63n(8 << 20))
+	if a.diskUsed > a.diskTotal*95/100 {
+		a.diskUsed = a.diskTotal / 2
+	}
+}
+
+// walk 均值回归的随机游走，结果限制在 0~100
+func walk(rng *rand.Rand, current, baseline, volatility float64) float64 {
+	next := current + (baseline-current)*0.2 + rng.NormFloat64()*volatility
+	return math.Min(100, math.Max(0, next))
+}
+
+// netRate 当前网络速率（字节/秒），与 CPU 负载正相关
+func (a *virtualAgent) netRate() float64 {
+	return (a.cpu/100*20 + a.rng.Float64()) * 1024 * 1024
+}
+
+// ioRate 当前磁盘 IO 速率（字节/秒）
+func (a *virtualAgent) ioRate() float64 {
+	return (a.cpu/100*50 + a.rng.Float64()*2) * 1024 * 1024
+}
+
+func (a *virtualAgent) cpuData() protocol.CPUData {
+	perCore := make([]float64, a.cores)
+	for i := range perCore {
+		perCore[i] = math.Min(100, math.Max(0, a.cpu+a.rng.NormFloat64()*5))
+	}
+	return protocol.CPUData{
+		LogicalCores:  a.cores,
+		PhysicalCores: max(1, a.cores/2),
+		ModelName:     "Pika Virtual CPU",
+		UsagePercent:  a.cpu,
+		PerCore:       perCore,
+	}
+}
+
+func (a *virtualAgent) memoryData() protocol.MemoryData {
+	used := uint64(float64(a.memoryTotal) * a.mem / 100)
+	return protocol.MemoryData{
+		Total:        a.memoryTotal,
+		Used:         used,
+		Free:         a.memoryTotal - used,
+		Available:    a.memoryTotal - used,
+		UsagePercent: a.mem,
+	}
+}
+
+func (a *virtualAgent) diskData() []protocol.DiskData {
+	return []protocol.DiskData{{
+		MountPoint:   "/",
+		Device:       "/dev/vda1",
+		Fstype:       "ext4",
+		Total:        a.diskTotal,
+		Used:         a.diskUsed,
+		Free:         a.diskTotal - a.diskUsed,
+		UsagePercent: float64(a.diskUsed) / float64(a.diskTotal) * 100,
+	}}
+}
+
+func (a *virtualAgent) diskIOData() []protocol.DiskIOData {
+	return []protocol.DiskIOData{{
+		Device:         "vda",
+		ReadBytes:      a.readBytes,
+		WriteBytes:     a.writeBytes,
+		ReadBytesRate:  uint64(a.ioRate()),
+		WriteBytesRate: uint64(a.ioRate()),
+	}}
+}
+
+func (a *virtualAgent) networkData() []protocol.NetworkData {
+	return []protocol.NetworkData{{
+		Interface:      "eth0",
+		BytesSentRate:  uint64(a.netRate()),
+		BytesRecvRate:  uint64(a.netRate()),
+		BytesSentTotal: a.sentTotal,
+		BytesRecvTotal: a.recvTotal,
+	}}
+}
+
+func (a *virtualAgent) networkConnectionData() protocol.NetworkConnectionData {
+	established := uint32(20 + a.cpu*5 + a.rng.Float64()*20)
+	timeWait := uint32(a.rng.Intn(50))
+	listen := uint32(10)
+	return protocol.NetworkConnectionData{
+		Established: established,
+		TimeWait:    timeWait,
+		Listen:      listen,
+		Total:       established + timeWait + listen,
+	}
+}
+
+func (a *virtualAgent) hostData() protocol.HostInfoData {
+	return protocol.HostInfoData{
+		Hostname:        a.hostname,
+		Uptime:          uint64(time.Since(a.bootTime).Seconds()),
+		BootTime:        uint64(a.bootTime.Unix()),
+		Procs:           uint64(100 + a.rng.Intn(200)),
+		OS:              "linux",
+		Platform:        "ubuntu",
+		PlatformFamily:  "debian",
+		PlatformVersion: "24.04",
+		KernelVersion:   "6.8.0-loadgen",
+		KernelArch:      "x86_64",
+	}
+}
@@ -0,0 +1,170 @@
+package loadgen
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Config 压测配置
+type Config struct {
+	// 服务端地址（如：http://localhost:8080）
+	Endpoint string
+	// API Key
+	APIKey string
+	// 是否跳过 TLS 证书验证
+	InsecureSkipVerify bool
+
+	// 虚拟探针数量
+	Agents int
+	// 虚拟探针 ID 前缀，便于压测结束后识别和清理
+	IDPrefix string
+	// 指标上报间隔
+	Interval time.Duration
+	// 心跳间隔
+	HeartbeatInterval time.Duration
+	// 全部虚拟探针上线所用的时间，避免瞬间建立大量连接
+	RampUp time.Duration
+	// 压测持续时间，为 0 时一直运行直到取消
+	Duration time.Duration
+
+	// 每个上报周期触发负载尖峰的概率（0~1），尖峰期间 CPU 和内存接近满载，用于验证告警判断
+	SpikeProbability float64
+	// 负载尖峰持续的上报周期数
+	SpikeCycles int
+	// 每个上报周期主动断开连接的概率（0~1），用于验证断线重连和探针离线处理
+	DisconnectProbability float64
+
+	// 随机数种子，相同种子生成相同的指标序列
+	Seed int64
+}
+
+// Validate 校验配置并填充默认值
+func (c *Config) Validate() error {
+	if c.Endpoint == "" {
+		return fmt.Errorf("服务器地址不能为空")
+	}
+	if c.APIKey == "" {
+		return fmt.Errorf("API Key 不能为空")
+	}
+	if c.Agents <= 0 {
+		return fmt.Errorf("虚拟探针数量必须大于 0")
+	}
+	if c.Interval <= 0 {
+		return fmt.Errorf("上报间隔必须大于 0")
+	}
+	if c.SpikeProbability < 0 || c.SpikeProbability > 1 {
+		return fmt.Errorf("负载尖峰概率必须在 0~1 之间")
+	}
+	if c.DisconnectProbability < 0 || c.DisconnectProbability > 1 {
+		return fmt.Errorf("断线概率必须在 0~1 之间")
+	}
+	if c.IDPrefix == "" {
+		c.IDPrefix = "loadgen"
+	}
+	if c.HeartbeatInterval <= 0 {
+		c.HeartbeatInterval = 30 * time.Second
+	}
+	if c.SpikeCycles <= 0 {
+		c.SpikeCycles = 6
+	}
+	if c.Seed == 0 {
+		c.Seed = time.Now().UnixNano()
+	}
+	return nil
+}
+
+// webSocketURL 获取 WebSocket 连接地址
+func (c *Config) webSocketURL() (string, error) {
+	u, err := url.Parse(c.Endpoint)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("服务器地址格式错误: %s", c.Endpoint)
+	}
+
+	scheme := "ws"
+	if u.Scheme == "https" {
+		scheme = "wss"
+	}
+	return fmt.Sprintf("%s://%s/ws/agent", scheme, u.Host), nil
+}
+
+// Runner 压测执行器，模拟多个虚拟探针向服务端上报指标
+type Runner struct {
+	cfg    Config
+	wsURL  string
+	dialer websocket.Dialer
+	stats  *Stats
+}
+
+// NewRunner 创建压测执行器
+func NewRunner(cfg Config) (*Runner, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	wsURL, err := cfg.webSocketURL()
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := *websocket.DefaultDialer
+	if cfg.InsecureSkipVerify {
+		dialer.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	return &Runner{
+		cfg:    cfg,
+		wsURL:  wsURL,
+		dialer: dialer,
+		stats:  newStats(),
+	}, nil
+}
+
+// Stats 获取压测统计
+func (r *Runner) Stats() *Stats {
+	return r.stats
+}
+
+// Run 启动所有虚拟探针，直到压测时间结束或 ctx 被取消
+func (r *Runner) Run(ctx context.Context) error {
+	if r.cfg.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.cfg.Duration)
+		defer cancel()
+	}
+
+	// 按上线时间均匀分布启动虚拟探针
+	var step time.Duration
+	if r.cfg.Agents > 1 {
+		step = r.cfg.RampUp / time.Duration(r.cfg.Agents-1)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < r.cfg.Agents; i++ {
+		agent := newVirtualAgent(r, i, rand.New(rand.NewSource(r.cfg.Seed+int64(i))))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			agent.run(ctx)
+		}()
+
+		if step > 0 && i < r.cfg.Agents-1 {
+			select {
+			case <-time.After(step):
+			case <-ctx.Done():
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	wg.Wait()
+	return nil
+}
@@ -0,0 +1,114 @@
+package loadgen
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats 压测统计，所有虚拟探针共享
+type Stats struct {
+	startedAt time.Time
+
+	Connected      atomic.Int64 // 当前在线的虚拟探针数
+	Registered     atomic.Int64 // 累计注册成功次数
+	RegisterFailed atomic.Int64 // 累计注册失败次数
+	Disconnects    atomic.Int64 // 累计断线次数（含主动注入的断线）
+	Messages       atomic.Int64 // 累计发送的消息数
+	Bytes          atomic.Int64 // 累计发送的字节数
+	SendErrors     atomic.Int64 // 累计发送失败次数
+	Spikes         atomic.Int64 // 累计注入的负载尖峰次数
+
+	mu               sync.Mutex
+	registerLatency  []time.Duration
+	lastMessages     int64
+	lastBytes        int64
+	lastSnapshotTime time.Time
+}
+
+func newStats() *Stats {
+	now := time.Now()
+	return &Stats{startedAt: now, lastSnapshotTime: now}
+}
+
+// observeRegister 记录一次注册耗时
+func (s *Stats) observeRegister(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.registerLatency = append(s.registerLatency, d)
+}
+
+// Snapshot 某一时刻的统计快照
+type Snapshot struct {
+	Elapsed        time.Duration
+	Connected      int64
+	Registered     int64
+	RegisterFailed int64
+	Disconnects    int64
+	Messages       int64
+	Bytes          int64
+	SendErrors     int64
+	Spikes         int64
+	MessageRate    float64 // 距上次快照的消息速率（条/秒）
+	ByteRate       float64 // 距上次快照的发送速率（字节/秒）
+	RegisterP50    time.Duration
+	RegisterP99    time.Duration
+	RegisterMax    time.Duration
+}
+
+// Snapshot 生成统计快照，速率按距上次调用的时间计算
+func (s *Stats) Snapshot() Snapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	snap := Snapshot{
+		Elapsed:        now.Sub(s.startedAt),
+		Connected:      s.Connected.Load(),
+		Registered:     s.Registered.Load(),
+		RegisterFailed: s.RegisterFailed.Load(),
+		Disconnects:    s.Disconnects.Load(),
+		Messages:       s.Messages.Load(),
+		Bytes:          s.Bytes.Load(),
+		SendErrors:     s.SendErrors.Load(),
+		Spikes:         s.Spikes.Load(),
+	}
+
+	if seconds := now.Sub(s.lastSnapshotTime).Seconds(); seconds > 0 {
+		snap.MessageRate = float64(snap.Messages-s.lastMessages) / seconds
+		snap.ByteRate = float64(snap.Bytes-s.lastBytes) / seconds
+	}
+	s.lastMessages = snap.Messages
+	s.lastBytes = snap.Bytes
+	s.lastSnapshotTime = now
+
+	if n := len(s.registerLatency); n > 0 {
+		sorted := make([]time.Duration, n)
+		copy(sorted, s.registerLatency)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		snap.RegisterP50 = sorted[n*50/100]
+		snap.RegisterP99 = sorted[min(n*99/100, n-1)]
+		snap.RegisterMax = sorted[n-1]
+	}
+	return snap
+}
+
+// String 格式化为单行输出
+func (s Snapshot) String() string {
+	return fmt.Sprintf("[%s] 在线 %d | 注册 %d (失败 %d, p50 %s, p99 %s) | 消息 %d (%.0f/s, %.1f KB/s) | 发送失败 %d | 断线 %d | 尖峰 %d",
+		s.Elapsed.Truncate(time.Second),
+		s.Connected,
+		s.Registered,
+		s.RegisterFailed,
+		s.RegisterP50.Truncate(time.Millisecond),
+		s.RegisterP99.Truncate(time.Millisecond),
+		s.Messages,
+		s.MessageRate,
+		s.ByteRate/1024,
+		s.SendErrors,
+		s.Disconnects,
+		s.Spikes,
+	)
+}
@@ -0,0 +1,216 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Manager 日志管理器，按模块控制日志级别并支持运行时调整
+// 模块名取自 logger 名称的第一段（如 alert.notifier 属于 alert 模块），未单独设置级别的模块使用默认级别
+type Manager struct {
+	output atomic.Pointer[outputCore]
+	opts   []zap.Option
+
+	// minLevel 默认级别和所有模块级别中最低的一个，用于快速过滤
+	minLevel     zap.AtomicLevel
+	defaultLevel zap.AtomicLevel
+
+	mu      sync.RWMutex
+	modules map[string]zapcore.Level
+}
+
+type outputCore struct {
+	core zapcore.Core
+}
+
+// NewManager 创建日志管理器，core 负责实际输出，自身不应再做级别过滤
+func NewManager(core zapcore.Core, level zapcore.Level, opts ...zap.Option) *Manager {
+	m := &Manager{
+		opts:         opts,
+		minLevel:     zap.NewAtomicLevelAt(level),
+		defaultLevel: zap.NewAtomicLevelAt(level),
+		modules:      make(map[string]zapcore.Level),
+	}
+	m.SetOutput(core)
+	return m
+}
+
+// SetOutput 替换日志输出，已创建的 logger 会立即使用新的输出
+func (m *Manager) SetOutput(core zapcore.Core) {
+	m.output.Store(&outputCore{core: core})
+}
+
+// Logger 获取模块 logger，module 为空时返回根 logger
+func (m *Manager) Logger(module string) *zap.Logger {
+	logger := zap.New(&moduleCore{m: m}, m.opts...)
+	if module != "" {
+		logger = logger.Named(module)
+	}
+	return logger
+}
+
+// Sugar 获取模块的 SugaredLogger，便于 printf 风格的调用
+func (m *Manager) Sugar(module string) *zap.SugaredLogger {
+	return m.Logger(module).Sugar()
+}
+
+// SetLevel 设置模块日志级别，module 为空时设置默认级别
+func (m *Manager) SetLevel(module, level string) error {
+	lvl, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("无效的日志级别: %s", level)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if module == "" {
+		m.defaultLevel.SetLevel(lvl)
+	} else {
+		m.modules[module] = lvl
+	}
+	m.updateMinLevel()
+	return nil
+}
+
+// ResetLevel 清除模块单独设置的级别，恢复使用默认级别
+func (m *Manager) ResetLevel(module string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.modules, module)
+	m.updateMinLevel()
+}
+
+// Levels 当前的日志级别配置
+type Levels struct {
+	Default string            `json:"default" yaml:"default"`
+	Modules map[string]string `json:"modules" yaml:"modules"`
+}
+
+// Levels 获取当前的日志级别配置
+func (m *Manager) Levels() Levels {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	levels := Levels{
+		Default: m.defaultLevel.Level().String(),
+		Modules: make(map[string]string, len(m.modules)),
+	}
+	for module, lvl := range m.modules {
+		levels.Modules[module] = lvl.String()
+	}
+	return levels
+}
+
+// ApplyLevels 批量应用日志级别配置，通常在启动时根据配置文件调用
+func (m *Manager) ApplyLevels(levels Levels) error {
+	if levels.Default != "" {
+		if err := m.SetLevel("", levels.Default); err != nil {
+			return err
+		}
+	}
+	modules := make([]string, 0, len(levels.Modules))
+	for module := range levels.Modules {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+	for _, module := range modules {
+		if err := m.SetLevel(module, levels.Modules[module]); err != nil {
+			return fmt.Errorf("模块 %s: %w", module, err)
+		}
+	}
+	return nil
+}
+
+// updateMinLevel 重新计算最低级别，调用方需持有写锁
+func (m *Manager) updateMinLevel() {
+	min := m.defaultLevel.Level()
+	for _, lvl := range m.modules {
+		if lvl < min {
+			min = lvl
+		}
+	}
+	m.minLevel.SetLevel(min)
+}
+
+// levelFor 根据 logger 名称获取生效的日志级别
+func (m *Manager) levelFor(loggerName string) zapcore.Level {
+	module, _, _ := strings.Cut(loggerName, ".")
+	m.mu.RLock()
+	lvl, ok := m.modules[module]
+	m.mu.RUnlock()
+	if ok {
+		return lvl
+	}
+	return m.defaultLevel.Level()
+}
+
+// moduleCore 按模块级别过滤日志，通过过滤的日志交给当前的输出写入
+type moduleCore struct {
+	m      *Manager
+	fields []zapcore.Field
+}
+
+func (c *moduleCore) Enabled(lvl zapcore.Level) bool {
+	return c.m.minLevel.Enabled(lvl)
+}
+
+func (c *moduleCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+	return &moduleCore{m: c.m, fields: merged}
+}
+
+func (c *moduleCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level < c.m.levelFor(ent.LoggerName) {
+		return ce
+	}
+	return ce.AddCore(ent, c)
+}
+
+func (c *moduleCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if len(c.fields) > 0 {
+		fields = append(c.fields[:len(c.fields):len(c.fields)], fields...)
+	}
+	return c.m.output.Load().core.Write(ent, fields)
+}
+
+func (c *moduleCore) Sync() error {
+	return c.m.output.Load().core.Sync()
+}
+
+// NewOutput 创建输出到标准错误的日志输出，format 支持 console（默认）和 json
+func NewOutput(format string) zapcore.Core {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.TimeKey = "time"
+	encoderConfig.EncodeTime = zapcore.TimeEncoderOfLayout("2006-01-02 15:04:05.000")
+	encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+
+	var encoder zapcore.Encoder
+	if strings.ToLower(format) == "json" {
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	} else {
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	}
+	// 级别由 Manager 控制，输出本身不过滤
+	return zapcore.NewCore(encoder, zapcore.Lock(os.Stderr), zapcore.DebugLevel)
+}
+
+var defaultManager = NewManager(NewOutput("console"), zapcore.InfoLevel, zap.AddCaller())
+
+// Default 获取全局日志管理器
+func Default() *Manager {
+	return defaultManager
+}
+
+// Module 获取全局日志管理器中模块的 SugaredLogger
+func Module(module string) *zap.SugaredLogger {
+	return defaultManager.Sugar(module)
+}
@@ -0,0 +1,69 @@
+// Package secretbox 使用 AES-256-GCM 加密保存在配置文件或数据库中的敏感字段，
+// 密文以 Prefix 开头并以 base64 编码，未带前缀的值视为明文
+package secretbox
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// Prefix 加密字段的前缀
+const Prefix = "enc:v1:"
+
+// IsSealed 判断值是否为加密后的密文
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// Box 使用固定密钥加解密敏感字段
+type Box struct {
+	gcm cipher.AEAD
+}
+
+// New 创建加密器，key 必须为 32 字节
+func New(key []byte) (*Box, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Box{gcm: gcm}, nil
+}
+
+// Seal 加密敏感字段，返回带前缀的密文
+func (b *Box) Seal(plain string) (string, error) {
+	nonce := make([]byte, b.gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := b.gcm.Seal(nonce, nonce, []byte(plain), nil)
+	return Prefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open 解密带前缀的密文
+func (b *Box) Open(value string) (string, error) {
+	if !IsSealed(value) {
+		return "", fmt.Errorf("不是加密字段")
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, Prefix))
+	if err != nil {
+		return "", err
+	}
+	if len(data) < b.gcm.NonceSize() {
+		return "", fmt.Errorf("密文格式错误")
+	}
+
+	plain, err := b.gcm.Open(nil, data[:b.gcm.NonceSize()], data[b.gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
@@ -0,0 +1,84 @@
+package secretbox
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func newTestBox(t *testing.T, fill byte) *Box {
+	t.Helper()
+	box, err := New(bytes.Repeat([]byte{fill}, 32))
+	if err != nil {
+		t.Fatalf("创建加密器失败: %v", err)
+	}
+	return box
+}
+
+func TestSealOpen(t *testing.T) {
+	box := newTestBox(t, 1)
+
+	for _, plain := range []string{"", "secret", "密码 with spaces", strings.Repeat("x", 4096)} {
+		sealed, err := box.Seal(plain)
+		if err != nil {
+			t.Fatalf("加密 %q 失败: %v", plain, err)
+		}
+		if !IsSealed(sealed) {
+			t.Fatalf("密文缺少前缀: %q", sealed)
+		}
+		if plain != "" && strings.Contains(sealed, plain) {
+			t.Fatalf("密文中包含明文: %q", sealed)
+		}
+
+		opened, err := box.Open(sealed)
+		if err != nil {
+			t.Fatalf("解密 %q 失败: %v", plain, err)
+		}
+		if opened != plain {
+			t.Fatalf("解密结果 %q，期望 %q", opened, plain)
+		}
+	}
+}
+
+func TestSealUsesRandomNonce(t *testing.T) {
+	box := newTestBox(t, 1)
+
+	a, _ := box.Seal("secret")
+	b, _ := box.Seal("secret")
+	if a == b {
+		t.Fatal("相同明文两次加密的结果不应相同")
+	}
+}
+
+func TestOpenErrors(t *testing.T) {
+	box := newTestBox(t, 1)
+	sealed, err := box.Seal("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		box   *Box
+		value string
+	}{
+		{name: "明文", box: box, value: "secret"},
+		{name: "非 base64", box: box, value: Prefix + "!!!"},
+		{name: "长度不足", box: box, value: Prefix + "AAAA"},
+		{name: "密文被篡改", box: box, value: sealed[:len(sealed)-4] + "AAAA"},
+		{name: "密钥不同", box: newTestBox(t, 2), value: sealed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.box.Open(tt.value); err == nil {
+				t.Fatalf("解密 %q 应该失败", tt.value)
+			}
+		})
+	}
+}
+
+func TestNewRejectsInvalidKey(t *testing.T) {
+	if _, err := New([]byte("short")); err == nil {
+		t.Fatal("密钥长度错误时应该失败")
+	}
+}
@@ -0,0 +1,277 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// exportQueueSize 待导出队列长度，队列满时丢弃新的 Span，避免拖慢业务
+	exportQueueSize = 4096
+	// exportBatchSize 单次导出的最大 Span 数
+	exportBatchSize = 512
+	// exportInterval 定时导出间隔
+	exportInterval = 5 * time.Second
+	// exportTimeout 单次导出超时时间
+	exportTimeout = 10 * time.Second
+	// maxAttributeValueLength 字符串属性最大长度，超出截断
+	maxAttributeValueLength = 4096
+)
+
+// exporter 批量将 Span 以 OTLP/HTTP JSON 格式发送到 Collector
+type exporter struct {
+	endpoint string
+	headers  map[string]string
+	resource []Attribute
+	onError  func(error)
+	client   *http.Client
+
+	queue   chan *Span
+	stop    chan struct{}
+	done    chan struct{}
+	dropped atomic.Int64 // 队列满时丢弃的 Span 数，enqueue 并发累加，导出协程读取后清零
+}
+
+func newExporter(cfg Config) (*exporter, error) {
+	endpoint, err := tracesEndpoint(cfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := []Attribute{String("service.name", cfg.ServiceName)}
+	if cfg.ServiceVersion != "" {
+		resource = append(resource, String("service.version", cfg.ServiceVersion))
+	}
+
+	e := &exporter{
+		endpoint: endpoint,
+		headers:  cfg.Headers,
+		resource: resource,
+		onError:  cfg.OnError,
+		client:   &http.Client{Timeout: exportTimeout},
+		queue:    make(chan *Span, exportQueueSize),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go e.run()
+	return e, nil
+}
+
+// tracesEndpoint 补全 OTLP traces 路径
+func tracesEndpoint(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("tracing: invalid endpoint: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("tracing: endpoint must be http or https: %s", endpoint)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = "/v1/traces"
+	}
+	return u.String(), nil
+}
+
+func (e *exporter) enqueue(span *Span) {
+	select {
+	case e.queue <- span:
+	default:
+		e.dropped.Add(1)
+	}
+}
+
+func (e *exporter) run() {
+	defer close(e.done)
+
+	ticker := time.NewTicker(exportInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Span, 0, exportBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := e.export(batch); err != nil && e.onError != nil {
+			e.onError(err)
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case span := <-e.queue:
+			batch = append(batch, span)
+			if len(batch) >= exportBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-e.stop:
+			for {
+				select {
+				case span := <-e.queue:
+					batch = append(batch, span)
+					if len(batch) >= exportBatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (e *exporter) shutdown(ctx context.Context) error {
+	close(e.stop)
+	select {
+	case <-e.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (e *exporter) export(spans []*Span) error {
+	body, err := json.Marshal(e.buildRequest(spans))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("tracing: export %d spans: %w", len(spans), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("tracing: export %d spans: status %d: %s", len(spans), resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// buildRequest 构建 ExportTraceServiceRequest 的 JSON 结构
+func (e *exporter) buildRequest(spans []*Span) map[string]interface{} {
+	otlpSpans := make([]map[string]interface{}, 0, len(spans))
+	for _, span := range spans {
+		otlpSpans = append(otlpSpans, encodeSpan(span))
+	}
+
+	if dropped := e.dropped.Swap(0); dropped > 0 && e.onError != nil {
+		e.onError(fmt.Errorf("tracing: export queue full, dropped %d spans", dropped))
+	}
+
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{
+					"attributes": encodeAttributes(e.resource),
+				},
+				"scopeSpans": []interface{}{
+					map[string]interface{}{
+						"scope": map[string]interface{}{
+							"name": "github.com/dushixiang/pika",
+						},
+						"spans": otlpSpans,
+					},
+				},
+			},
+		},
+	}
+}
+
+func encodeSpan(span *Span) map[string]interface{} {
+	span.mu.Lock()
+	defer span.mu.Unlock()
+
+	out := map[string]interface{}{
+		"traceId":           span.spanContext.TraceID.String(),
+		"spanId":            span.spanContext.SpanID.String(),
+		"name":              span.name,
+		"kind":              int(span.kind),
+		"startTimeUnixNano": strconv.FormatInt(span.start.UnixNano(), 10),
+		"endTimeUnixNano":   strconv.FormatInt(span.end.UnixNano(), 10),
+		"attributes":        encodeAttributes(span.attrs),
+		"status": map[string]interface{}{
+			"code":    int(span.status),
+			"message": span.statusMessage,
+		},
+	}
+	if span.parentSpanID.IsValid() {
+		out["parentSpanId"] = span.parentSpanID.String()
+	}
+	if len(span.events) > 0 {
+		events := make([]map[string]interface{}, 0, len(span.events))
+		for _, ev := range span.events {
+			events = append(events, map[string]interface{}{
+				"name":         ev.name,
+				"timeUnixNano": strconv.FormatInt(ev.time.UnixNano(), 10),
+				"attributes":   encodeAttributes(ev.attrs),
+			})
+		}
+		out["events"] = events
+	}
+	return out
+}
+
+// encodeAttributes 编码属性，同名属性只保留最后一个
+func encodeAttributes(attrs []Attribute) []map[string]interface{} {
+	index := make(map[string]int, len(attrs))
+	out := make([]map[string]interface{}, 0, len(attrs))
+	for _, attr := range attrs {
+		encoded := map[string]interface{}{
+			"key":   attr.Key,
+			"value": encodeValue(attr.Value),
+		}
+		if i, ok := index[attr.Key]; ok {
+			out[i] = encoded
+			continue
+		}
+		index[attr.Key] = len(out)
+		out = append(out, encoded)
+	}
+	return out
+}
+
+func encodeValue(value interface{}) map[string]interface{} {
+	switch v := value.(type) {
+	case string:
+		if len(v) > maxAttributeValueLength {
+			v = v[:maxAttributeValueLength]
+		}
+		return map[string]interface{}{"stringValue": v}
+	case bool:
+		return map[string]interface{}{"boolValue": v}
+	case int64:
+		// OTLP JSON 中 64 位整数以字符串表示
+		return map[string]interface{}{"intValue": strconv.FormatInt(v, 10)}
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return map[string]interface{}{"stringValue": strconv.FormatFloat(v, 'g', -1, 64)}
+		}
+		return map[string]interface{}{"doubleValue": v}
+	default:
+		return map[string]interface{}{"stringValue": fmt.Sprint(v)}
+	}
+}
@@ -0,0 +1,173 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// collector 记录每次导出请求中的 Span 数量
+type collector struct {
+	mu      sync.Mutex
+	batches []int
+	names   []string
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []struct {
+					Name string `json:"name"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	count := 0
+	for _, rs := range req.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			for _, span := range ss.Spans {
+				c.names = append(c.names, span.Name)
+				count++
+			}
+		}
+	}
+	c.batches = append(c.batches, count)
+}
+
+func (c *collector) snapshot() ([]int, []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]int(nil), c.batches...), append([]string(nil), c.names...)
+}
+
+func newTestTracer(t *testing.T, endpoint string) *Tracer {
+	t.Helper()
+	tracer, err := New(Config{ServiceName: "test", Endpoint: endpoint, SampleRatio: 1})
+	if err != nil {
+		t.Fatalf("创建 Tracer 失败: %v", err)
+	}
+	return tracer
+}
+
+func TestExporterFlushOnShutdown(t *testing.T) {
+	c := &collector{}
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	tracer := newTestTracer(t, srv.URL)
+	for _, name := range []string{"a", "b", "c"} {
+		_, span := tracer.Start(context.Background(), name)
+		span.End()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := tracer.Shutdown(ctx); err != nil {
+		t.Fatalf("关闭 Tracer 失败: %v", err)
+	}
+
+	batches, names := c.snapshot()
+	if len(batches) != 1 || batches[0] != 3 {
+		t.Fatalf("导出批次 %v，期望一次导出 3 个 Span", batches)
+	}
+	if strings.Join(names, ",") != "a,b,c" {
+		t.Fatalf("导出的 Span %v，期望 a,b,c", names)
+	}
+}
+
+func TestExporterBatching(t *testing.T) {
+	c := &collector{}
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	tracer := newTestTracer(t, srv.URL)
+	total := exportBatchSize*2 + 10
+	for i := 0; i < total; i++ {
+		_, span := tracer.Start(context.Background(), "span")
+		span.End()
+	}
+
+	// 攒满一批立即导出，不等待定时器
+	deadline := time.Now().Add(exportInterval / 2)
+	for {
+		batches, _ := c.snapshot()
+		if len(batches) >= 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("攒满一批后未立即导出，当前批次 %v", batches)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := tracer.Shutdown(ctx); err != nil {
+		t.Fatalf("关闭 Tracer 失败: %v", err)
+	}
+
+	batches, _ := c.snapshot()
+	want := []int{exportBatchSize, exportBatchSize, 10}
+	if len(batches) != len(want) {
+		t.Fatalf("导出批次 %v，期望 %v", batches, want)
+	}
+	for i := range want {
+		if batches[i] != want[i] {
+			t.Fatalf("导出批次 %v，期望 %v", batches, want)
+		}
+	}
+}
+
+func TestExporterDropWhenFull(t *testing.T) {
+	var reported []string
+	// 不启动导出协程，队列写满后新的 Span 直接丢弃
+	e := &exporter{
+		queue:   make(chan *Span, 2),
+		onError: func(err error) { reported = append(reported, err.Error()) },
+	}
+	tracer := &Tracer{sampleBound: 1 << 63, exporter: e}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, span := tracer.Start(context.Background(), "span")
+			span.End()
+		}()
+	}
+	wg.Wait()
+
+	if len(e.queue) != 2 {
+		t.Fatalf("队列长度 %d，期望 2", len(e.queue))
+	}
+	if got := e.dropped.Load(); got != 8 {
+		t.Fatalf("丢弃 %d 个 Span，期望 8", got)
+	}
+
+	// 下一次导出时上报丢弃数量并清零
+	e.buildRequest(nil)
+	if len(reported) != 1 || !strings.Contains(reported[0], "dropped 8 spans") {
+		t.Fatalf("丢弃上报 %v，期望包含 dropped 8 spans", reported)
+	}
+	if got := e.dropped.Load(); got != 0 {
+		t.Fatalf("上报后丢弃计数 %d，期望 0", got)
+	}
+	e.buildRequest(nil)
+	if len(reported) != 1 {
+		t.Fatalf("没有新的丢弃时不应重复上报: %v", reported)
+	}
+}
@@ -0,0 +1,93 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+const (
+	gormSpanKey   = "tracing:span"
+	gormParentKey = "tracing:parent"
+)
+
+// GormPlugin 为 GORM 数据库操作创建子 Span，只在 ctx 中存在被采样的 Span 时记录，
+// 不会为后台任务中没有上游链路的查询单独创建追踪
+type GormPlugin struct{}
+
+func (GormPlugin) Name() string {
+	return "tracing"
+}
+
+func (p GormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	hooks := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+	for _, h := range hooks {
+		if err := h.before("tracing:before_"+h.operation, p.before(h.operation)); err != nil {
+			return err
+		}
+		if err := h.after("tracing:after_"+h.operation, p.after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p GormPlugin) before(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		parent := db.Statement.Context
+		if parent == nil || !SpanFromContext(parent).IsRecording() {
+			return
+		}
+		ctx, span := Start(parent, "db."+operation,
+			WithKind(SpanKindClient),
+			WithAttributes(
+				String("db.system", db.Dialector.Name()),
+				String("db.operation", operation),
+			),
+		)
+		db.Statement.Context = ctx
+		db.InstanceSet(gormSpanKey, span)
+		db.InstanceSet(gormParentKey, parent)
+	}
+}
+
+func (p GormPlugin) after(db *gorm.DB) {
+	value, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, _ := value.(*Span)
+	if span == nil {
+		return
+	}
+
+	if db.Statement.Table != "" {
+		span.SetAttributes(String("db.sql.table", db.Statement.Table))
+	}
+	span.SetAttributes(
+		String("db.statement", db.Statement.SQL.String()),
+		Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+	}
+	span.End()
+
+	// 恢复原 ctx，避免复用同一 Statement 的后续操作挂到已结束的 Span 下
+	if parent, ok := db.InstanceGet(gormParentKey); ok {
+		db.Statement.Context = parent.(context.Context)
+	}
+}
@@ -0,0 +1,308 @@
+// Package tracing 轻量的分布式追踪实现，Span 模型与 OpenTelemetry 一致，
+// 通过 OTLP/HTTP (JSON) 导出到 OpenTelemetry Collector、Jaeger、Tempo 等后端，
+// 并使用 W3C Trace Context（traceparent）在服务之间传播。
+//
+// 未调用 SetDefault 时 Start 返回 nil Span，所有 Span 方法都可以安全地在 nil 上调用，埋点代码无需判断是否启用。
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TraceID 追踪ID
+type TraceID [16]byte
+
+func (t TraceID) IsValid() bool  { return t != TraceID{} }
+func (t TraceID) String() string { return hex.EncodeToString(t[:]) }
+
+// SpanID Span ID
+type SpanID [8]byte
+
+func (s SpanID) IsValid() bool  { return s != SpanID{} }
+func (s SpanID) String() string { return hex.EncodeToString(s[:]) }
+
+// SpanKind Span 类型，取值与 OTLP 协议一致
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+	SpanKindProducer SpanKind = 4
+	SpanKindConsumer SpanKind = 5
+)
+
+// StatusCode Span 状态，取值与 OTLP 协议一致
+type StatusCode int
+
+const (
+	StatusUnset StatusCode = 0
+	StatusOK    StatusCode = 1
+	StatusError StatusCode = 2
+)
+
+// Attribute Span 属性
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+func String(key, value string) Attribute          { return Attribute{Key: key, Value: value} }
+func Int(key string, value int) Attribute         { return Attribute{Key: key, Value: int64(value)} }
+func Int64(key string, value int64) Attribute     { return Attribute{Key: key, Value: value} }
+func Bool(key string, value bool) Attribute       { return Attribute{Key: key, Value: value} }
+func Float64(key string, value float64) Attribute { return Attribute{Key: key, Value: value} }
+
+// SpanContext 跨进程传播的 Span 标识
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+type event struct {
+	name  string
+	time  time.Time
+	attrs []Attribute
+}
+
+// Span 一次操作的耗时记录
+type Span struct {
+	tracer       *Tracer
+	name         string
+	kind         SpanKind
+	spanContext  SpanContext
+	parentSpanID SpanID
+	start        time.Time
+
+	mu            sync.Mutex
+	end           time.Time
+	attrs         []Attribute
+	events        []event
+	status        StatusCode
+	statusMessage string
+	ended         bool
+}
+
+// SpanContext 获取 Span 标识
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.spanContext
+}
+
+// IsRecording 是否被采样并记录
+func (s *Span) IsRecording() bool {
+	return s != nil && s.spanContext.Sampled
+}
+
+// SetAttributes 设置属性，同名属性以最后一次为准
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if !s.IsRecording() {
+		return
+	}
+	s.mu.Lock()
+	s.attrs = append(s.attrs, attrs...)
+	s.mu.Unlock()
+}
+
+// AddEvent 添加事件
+func (s *Span) AddEvent(name string, attrs ...Attribute) {
+	if !s.IsRecording() {
+		return
+	}
+	s.mu.Lock()
+	s.events = append(s.events, event{name: name, time: time.Now(), attrs: attrs})
+	s.mu.Unlock()
+}
+
+// SetStatus 设置状态
+func (s *Span) SetStatus(code StatusCode, message string) {
+	if !s.IsRecording() {
+		return
+	}
+	s.mu.Lock()
+	s.status = code
+	s.statusMessage = message
+	s.mu.Unlock()
+}
+
+// RecordError 记录错误并将状态置为 Error，err 为 nil 时忽略
+func (s *Span) RecordError(err error) {
+	if err == nil || !s.IsRecording() {
+		return
+	}
+	s.AddEvent("exception", String("exception.message", err.Error()))
+	s.SetStatus(StatusError, err.Error())
+}
+
+// End 结束 Span 并提交导出，重复调用只生效一次
+func (s *Span) End() {
+	if !s.IsRecording() {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	s.mu.Unlock()
+
+	s.tracer.exporter.enqueue(s)
+}
+
+// Config 追踪配置
+type Config struct {
+	ServiceName    string            // 服务名，对应 service.name
+	ServiceVersion string            // 服务版本，对应 service.version
+	Endpoint       string            // OTLP/HTTP 地址，如 http://otel-collector:4318，未包含路径时自动补全 /v1/traces
+	Headers        map[string]string // 导出请求附加的请求头，如鉴权信息
+	SampleRatio    float64           // 根 Span 采样率 0~1，子 Span 跟随父 Span 的采样结果
+	OnError        func(error)       // 导出失败回调
+}
+
+// Tracer 创建 Span 并批量导出
+type Tracer struct {
+	sampleBound uint64
+	exporter    *exporter
+}
+
+// New 创建 Tracer 并启动后台导出协程
+func New(cfg Config) (*Tracer, error) {
+	if cfg.Endpoint == "" {
+		return nil, errors.New("tracing: endpoint is empty")
+	}
+	if cfg.ServiceName == "" {
+		cfg.ServiceName = "pika"
+	}
+	ratio := cfg.SampleRatio
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
+
+	exp, err := newExporter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Tracer{
+		sampleBound: uint64(ratio * (1 << 63)),
+		exporter:    exp,
+	}, nil
+}
+
+// Shutdown 停止导出协程并导出剩余的 Span
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	return t.exporter.shutdown(ctx)
+}
+
+// StartOption Span 创建选项
+type StartOption func(*Span)
+
+// WithKind 设置 Span 类型，默认为 Internal
+func WithKind(kind SpanKind) StartOption {
+	return func(s *Span) { s.kind = kind }
+}
+
+// WithAttributes 设置初始属性
+func WithAttributes(attrs ...Attribute) StartOption {
+	return func(s *Span) { s.attrs = append(s.attrs, attrs...) }
+}
+
+// Start 创建 Span，ctx 中存在父 Span 时作为其子 Span
+func (t *Tracer) Start(ctx context.Context, name string, opts ...StartOption) (context.Context, *Span) {
+	span := &Span{
+		tracer: t,
+		name:   name,
+		kind:   SpanKindInternal,
+		start:  time.Now(),
+	}
+
+	if parent := spanContextFromContext(ctx); parent.IsValid() {
+		span.spanContext.TraceID = parent.TraceID
+		span.spanContext.Sampled = parent.Sampled
+		span.parentSpanID = parent.SpanID
+	} else {
+		span.spanContext.TraceID = newTraceID()
+		span.spanContext.Sampled = t.shouldSample(span.spanContext.TraceID)
+	}
+	span.spanContext.SpanID = newSpanID()
+
+	for _, opt := range opts {
+		opt(span)
+	}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// shouldSample 按 TraceID 的低 8 字节判断是否采样，与 OpenTelemetry 的 TraceIDRatioBased 一致
+func (t *Tracer) shouldSample(traceID TraceID) bool {
+	return binary.BigEndian.Uint64(traceID[8:])>>1 < t.sampleBound
+}
+
+var defaultTracer atomic.Pointer[Tracer]
+
+// SetDefault 设置全局 Tracer，传入 nil 时关闭追踪
+func SetDefault(t *Tracer) {
+	defaultTracer.Store(t)
+}
+
+// Enabled 是否已启用追踪
+func Enabled() bool {
+	return defaultTracer.Load() != nil
+}
+
+// Start 使用全局 Tracer 创建 Span，未启用追踪时返回原 ctx 和 nil Span
+func Start(ctx context.Context, name string, opts ...StartOption) (context.Context, *Span) {
+	t := defaultTracer.Load()
+	if t == nil {
+		return ctx, nil
+	}
+	return t.Start(ctx, name, opts...)
+}
+
+type spanKey struct{}
+type remoteKey struct{}
+
+// SpanFromContext 获取 ctx 中的当前 Span
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// ContextWithRemoteSpanContext 将上游传入的 Span 标识写入 ctx，之后创建的 Span 作为其子 Span
+func ContextWithRemoteSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteKey{}, sc)
+}
+
+func spanContextFromContext(ctx context.Context) SpanContext {
+	if span := SpanFromContext(ctx); span != nil {
+		return span.spanContext
+	}
+	sc, _ := ctx.Value(remoteKey{}).(SpanContext)
+	return sc
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	_, _ = rand.Read(id[:])
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	_, _ = rand.Read(id[:])
+	return id
+}
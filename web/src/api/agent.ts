@@ -1,4 +1,5 @@
 import {del, get, post, put} from './request';
+import {withBasePath} from '@/lib/utils';
 import type {Agent, LatestMetrics} from '@/types';
 
 export interface ListAgentsResponse {
@@ -8,7 +9,7 @@ export interface ListAgentsResponse {
 
 export interface GetAgentMetricsRequest {
     agentId: string;
-    type: 'cpu' | 'memory' | 'disk' | 'network' | 'network_connection' | 'disk_io' | 'gpu' | 'temperature';
+    type: 'cpu' | 'memory' | 'disk' | 'network' | 'network_connection' | 'disk_io' | 'gpu' | 'temperature' | 'cpu_core' | 'cpu_times' | 'pressure' | 'ping';
     range?: string; // 时间范围，如 '15m', '1h', '1d' 等，从后端配置获取
     interface?: string; // 网卡过滤参数（仅对 network 类型有效）
 }
@@ -42,6 +43,11 @@ export const getAgentPaging = (pageIndex: number = 1, pageSize: number = 10, hos
     return get<ListAgentsResponse>(`/admin/agents?${params.toString()}`);
 };
 
+// 管理员接口 - 导出探针资产清单（CSV）
+export const exportAgentInventory = () => {
+    return get<string>('/admin/agents/export?format=csv');
+};
+
 export const listAgents = () => {
     return get<ListAgentsResponse>('/agents');
 };
@@ -66,10 +72,121 @@ export const getAgentMetrics = (params: GetAgentMetricsRequest) => {
     return get<GetAgentMetricsResponse>(`/agents/${agentId}/metrics?${query.toString()}`);
 };
 
+export type MetricAgg = 'avg' | 'max' | 'p95';
+
+export interface GetAgentMetricSeriesRequest {
+    agentId: string;
+    type: 'cpu' | 'memory' | 'disk' | 'network' | 'ping';
+    agg: MetricAgg;
+    from?: number; // 开始时间（毫秒），与 to 均未指定时使用 range
+    to?: number;   // 结束时间（毫秒）
+    range?: string;
+    step?: string; // 聚合粒度，如 '60s', '5m'，不指定时自动计算
+    interface?: string;
+}
+
+export interface MetricSeries {
+    name: string; // 序列名称：usage、sent/recv，ping 为目标地址
+    points: { timestamp: number; value: number }[];
+}
+
+export interface GetAgentMetricSeriesResponse {
+    agentId: string;
+    type: string;
+    interface?: string;
+    start: number;
+    end: number;
+    step: number; // 实际使用的聚合粒度（秒）
+    agg: MetricAgg;
+    series: MetricSeries[];
+}
+
+// 获取按 step 聚合后的指标序列，每个区间只返回一个值
+export const getAgentMetricSeries = (params: GetAgentMetricSeriesRequest) => {
+    const {agentId, type, agg, from, to, range, step, interface: interfaceName} = params;
+    const query = new URLSearchParams();
+    query.append('type', type);
+    query.append('agg', agg);
+    if (from && to) {
+        query.append('from', from.toString());
+        query.append('to', to.toString());
+    } else {
+        query.append('range', range || '1h');
+    }
+    if (step) {
+        query.append('step', step);
+    }
+    if (interfaceName) {
+        query.append('interface', interfaceName);
+    }
+    return get<GetAgentMetricSeriesResponse>(`/agents/${agentId}/metrics?${query.toString()}`);
+};
+
+// 管理员接口 - 导出探针历史指标（CSV 或 JSON Lines）
+export interface ExportAgentMetricsParams {
+    type: string;
+    from: number;
+    to: number;
+    step?: string;    // 如 60s、5m、1h
+    format?: 'csv' | 'json';
+    interface?: string;
+}
+
+export const exportAgentMetrics = (agentId: string, params: ExportAgentMetricsParams) => {
+    const query = new URLSearchParams();
+    query.append('type', params.type);
+    query.append('from', params.from.toString());
+    query.append('to', params.to.toString());
+    if (params.step) {
+        query.append('step', params.step);
+    }
+    if (params.format) {
+        query.append('format', params.format);
+    }
+    if (params.interface) {
+        query.append('interface', params.interface);
+    }
+    // 长时间范围导出耗时较长，放宽超时时间
+    return get<string>(`/admin/agents/${agentId}/metrics/export?${query.toString()}`, {timeout: 5 * 60 * 1000});
+};
+
 export const getAgentLatestMetrics = (agentId: string) => {
     return get<LatestMetrics>(`/agents/${agentId}/metrics/latest`);
 };
 
+// 探针连接会话
+export interface AgentSession {
+    id: number;
+    agentId: string;
+    ip: string;
+    version: string;
+    connectedAt: number;
+    disconnectedAt: number; // 0 表示仍在连接
+}
+
+// 探针离线时间段
+export interface AgentDowntime {
+    start: number;
+    end: number;
+    duration: number;
+}
+
+// 探针可用率统计
+export interface AgentAvailability {
+    agentId: string;
+    start: number;
+    end: number;
+    uptime: number;
+    onlineDuration: number;
+    offlineDuration: number;
+    sessions: AgentSession[];
+    downtimes: AgentDowntime[];
+}
+
+export const getAgentAvailability = (agentId: string, range: string = '7d') => {
+    return get<AgentAvailability>(`/agents/${agentId}/availability?range=${range}`);
+};
+
 // 获取探针的可用网卡列表
 export interface GetNetworkInterfacesResponse {
     interfaces: string[];
@@ -212,6 +329,9 @@ export interface ProcessInfo {
     cpuPercent: number;
     memPercent: number;
     memoryMb: number;
+    ppid?: number;
+    status?: string;
+    createTime?: number;
     exeDeleted?: boolean;
 }
 
@@ -431,10 +551,11 @@ export const updateAgentName = (agentId: string, name: string) => {
     return put(`/admin/agents/${agentId}/name`, {name});
 };
 
-// 更新探针信息（名称、标签、到期时间、可见性）
+// 更新探针信息（名称、标签、地区、到期时间、可见性）
 export interface UpdateAgentInfoRequest {
     name?: string;
     tags?: string[];
+    region?: string;
     expireTime?: number;
     visibility?: string;
 }
@@ -469,3 +590,386 @@ export const getTags = () => {
 export const getPublicTags = () => {
     return get<GetTagsResponse>('/agents/tags');
 };
+
+// Ping 目标（管理员接口）
+export interface PingTarget {
+    id: string;
+    agentId: string;
+    name: string;
+    target: string;
+    enabled: boolean;
+    createdAt: number;
+    updatedAt: number;
+}
+
+export interface PingTargetRequest {
+    name: string;
+    target: string;
+    enabled: boolean;
+}
+
+export const listPingTargets = (agentId: string) => {
+    return get<PingTarget[]>(`/admin/agents/${agentId}/ping-targets`);
+};
+
+export const createPingTarget = (agentId: string, data: PingTargetRequest) => {
+    return post<PingTarget>(`/admin/agents/${agentId}/ping-targets`, data);
+};
+
+export const updatePingTarget = (agentId: string, targetId: string, data: PingTargetRequest) => {
+    return put<PingTarget>(`/admin/agents/${agentId}/ping-targets/${targetId}`, data);
+};
+
+export const deletePingTarget = (agentId: string, targetId: string) => {
+    return del(`/admin/agents/${agentId}/ping-targets/${targetId}`);
+};
+
+// 探针间延迟网格（管理员接口）
+export interface LatencyMeshConfig {
+    enabled: boolean;
+    agentIds: string[];
+    mode: 'icmp' | 'tcp';
+    port: number;
+}
+
+export interface LatencyMeshAgent {
+    id: string;
+    name: string;
+    ip: string;
+    online: boolean;
+}
+
+export interface LatencyMeshCell {
+    from: string;
+    to: string;
+    minRtt: number;
+    avgRtt: number;
+    maxRtt: number;
+    packetLoss: number;
+    error?: string;
+    updatedAt: number;
+}
+
+export interface LatencyMeshMatrix {
+    mode: string;
+    agents: LatencyMeshAgent[];
+    cells: LatencyMeshCell[];
+}
+
+export const getLatencyMeshConfig = () => {
+    return get<LatencyMeshConfig>('/admin/latency-mesh');
+};
+
+export const saveLatencyMeshConfig = (data: LatencyMeshConfig) => {
+    return put<LatencyMeshConfig>('/admin/latency-mesh', data);
+};
+
+export const getLatencyMeshMatrix = () => {
+    return get<LatencyMeshMatrix>('/admin/latency-mesh/matrix');
+};
+
+export interface PowerTask {
+    id: number;
+    agentId: string;
+    action: 'reboot' | 'wol';
+    targetMac: string;
+    broadcast: string;
+    scheduledAt: number;
+    status: 'pending' | 'scheduled' | 'running' | 'success' | 'error' | 'cancelled';
+    commandId: string;
+    output: string;
+    error: string;
+    createdAt: number;
+    updatedAt: number;
+}
+
+export const listPowerTasks = (agentId: string) => {
+    return get<PowerTask[]>(`/admin/agents/${agentId}/power-tasks`);
+};
+
+// 创建计划重启任务，需要确认后才会执行
+export const scheduleReboot = (agentId: string, scheduledAt?: number) => {
+    return post<PowerTask>(`/admin/agents/${agentId}/reboot`, {scheduledAt: scheduledAt || 0});
+};
+
+// 通过探针向所在局域网发送网络唤醒魔术包
+export const wakeOnLAN = (agentId: string, mac: string, broadcast?: string) => {
+    return post<PowerTask>(`/admin/agents/${agentId}/wol`, {mac, broadcast: broadcast || ''});
+};
+
+export const confirmPowerTask = (id: number) => {
+    return post(`/admin/power-tasks/${id}/confirm`);
+};
+
+export const cancelPowerTask = (id: number) => {
+    return post(`/admin/power-tasks/${id}/cancel`);
+};
+
+export interface DiskUsageEntry {
+    path: string;
+    size: number;
+}
+
+export interface DiskUsageResult {
+    path: string;
+    totalSize: number;
+    fileCount: number;
+    dirCount: number;
+    directories: DiskUsageEntry[];
+    files: DiskUsageEntry[];
+    errors: number;
+    truncated: boolean;
+    duration: number;
+}
+
+export interface DiskUsageScan {
+    id: string;
+    agentId: string;
+    path: string;
+    status: 'running' | 'success' | 'error';
+    error?: string;
+    result?: DiskUsageResult;
+    startedAt: number;
+    finishedAt?: number;
+}
+
+// 获取探针最近一次的磁盘占用分析结果
+export const getDiskUsage = (agentId: string) => {
+    return get<DiskUsageScan | null>(`/admin/agents/${agentId}/disk-usage`);
+};
+
+// 发起磁盘占用分析，统计指定路径下占用最大的目录和文件
+export const scanDiskUsage = (agentId: string, data: { path: string; depth?: number; limit?: number }) => {
+    return post<DiskUsageScan>(`/admin/agents/${agentId}/disk-usage`, data);
+};
+
+export interface TerminalTicket {
+    ticket: string;
+    idleTimeout: number;
+}
+
+export interface TerminalSession {
+    id: string;
+    agentId: string;
+    username: string;
+    clientIp: string;
+    status: 'running' | 'closed';
+    closeReason: string;
+    startedAt: number;
+    endedAt: number;
+    recordSize: number;
+    recordTruncated: boolean;
+}
+
+// 申请打开远程终端的一次性凭证，凭证 30 秒内有效
+export const createTerminalTicket = (agentId: string) => {
+    return post<TerminalTicket>(`/admin/agents/${agentId}/terminal`);
+};
+
+// 远程终端的 WebSocket 地址
+export const getTerminalWebSocketURL = (ticket: string, cols: number, rows: number, term: string) => {
+    const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
+    const query = new URLSearchParams({ticket, cols: String(cols), rows: String(rows), term});
+    return `${protocol}//${window.location.host}${withBasePath('/ws/terminal')}?${query.toString()}`;
+};
+
+export const listTerminalSessions = (agentId: string, pageIndex: number = 1, pageSize: number = 10) => {
+    const query = new URLSearchParams({
+        agentId,
+        pageIndex: pageIndex.toString(),
+        pageSize: pageSize.toString(),
+        sortField: 'startedAt',
+        sortOrder: 'desc',
+    });
+    return get<{ items: TerminalSession[]; total: number }>(`/admin/terminal-sessions?${query.toString()}`);
+};
+
+// 下载终端会话录像（asciicast v2 格式）
+export const downloadTerminalRecording = (id: string) => {
+    return get<string>(`/admin/terminal-sessions/${id}/recording`, {timeout: 5 * 60 * 1000});
+};
+
+export interface RemoteCommandOption {
+    name: string;
+    label: string;
+    targets: string[] | null;
+    timeout: number;
+}
+
+export interface CommandExecution {
+    id: number;
+    agentId: string;
+    name: string;
+    target: string;
+    timeout: number;
+    status: 'running' | 'success' | 'error' | 'timeout';
+    commandId: string;
+    output: string;
+    error: string;
+    exitCode: number;
+    duration: number;
+    username: string;
+    clientIp: string;
+    createdAt: number;
+    finishedAt: number;
+}
+
+// 获取允许下发的远程命令
+export const getRemoteCommandOptions = () => {
+    return get<RemoteCommandOption[]>('/admin/remote-commands');
+};
+
+// 下发远程命令，执行结果异步更新到执行记录
+export const executeRemoteCommand = (agentId: string, data: { name: string; target: string; timeout?: number }) => {
+    return post<CommandExecution>(`/admin/agents/${agentId}/remote-commands`, data);
+};
+
+export const listCommandExecutions = (agentId: string, pageIndex: number = 1, pageSize: number = 10) => {
+    const query = new URLSearchParams({
+        agentId,
+        pageIndex: pageIndex.toString(),
+        pageSize: pageSize.toString(),
+        sortField: 'createdAt',
+        sortOrder: 'desc',
+    });
+    return get<{ items: CommandExecution[]; total: number }>(`/admin/remote-command-executions?${query.toString()}`);
+};
+
+export interface ProcessSnapshot {
+    id: string;
+    processes?: ProcessInfo[];
+    total: number;
+    timestamp: number;
+}
+
+export interface ProcessKillRecord {
+    id: number;
+    agentId: string;
+    pid: number;
+    name: string;
+    cmdline: string;
+    signal: 'TERM' | 'KILL';
+    status: 'running' | 'success' | 'error';
+    commandId: string;
+    error: string;
+    username: string;
+    clientIp: string;
+    createdAt: number;
+    finishedAt: number;
+}
+
+// 申请查看进程列表的一次性凭证，凭证 30 秒内有效
+export const createProcessTicket = (agentId: string) => {
+    return post<{ ticket: string }>(`/admin/agents/${agentId}/processes/ticket`);
+};
+
+// 进程列表的 WebSocket 地址
+export const getProcessWebSocketURL = (ticket: string, limit: number, interval: number) => {
+    const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
+    const query = new URLSearchParams({ticket, limit: String(limit), interval: String(interval)});
+    return `${protocol}//${window.location.host}${withBasePath('/ws/processes')}?${query.toString()}`;
+};
+
+// 结束进程，执行结果异步更新到结束进程记录
+export const killProcess = (agentId: string, data: {
+    pid: number;
+    createTime?: number;
+    signal: 'TERM' | 'KILL';
+    name: string;
+    cmdline?: string
+}) => {
+    return post<ProcessKillRecord>(`/admin/agents/${agentId}/processes/kill`, data);
+};
+
+export const listProcessKills = (agentId: string, pageIndex: number = 1, pageSize: number = 10) => {
+    const query = new URLSearchParams({
+        agentId,
+        pageIndex: pageIndex.toString(),
+        pageSize: pageSize.toString(),
+        sortField: 'createdAt',
+        sortOrder: 'desc',
+    });
+    return get<{ items: ProcessKillRecord[]; total: number }>(`/admin/process-kills?${query.toString()}`);
+};
+
+// 申请查看日志的一次性凭证，凭证 30 秒内有效
+export const createLogTailTicket = (agentId: string) => {
+    return post<{ ticket: string }>(`/admin/agents/${agentId}/logs/ticket`);
+};
+
+// 日志查看的 WebSocket 地址，source 为 file（文件路径）或 journal（systemd 服务）
+export const getLogTailWebSocketURL = (ticket: string, source: 'file' | 'journal', target: string, lines: number, duration: number) => {
+    const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
+    const query = new URLSearchParams({
+        ticket,
+        source,
+        target,
+        lines: String(lines),
+        duration: String(duration),
+    });
+    return `${protocol}//${window.location.host}${withBasePath('/ws/logs')}?${query.toString()}`;
+};
+
+export interface DockerContainer {
+    id: string;
+    name: string;
+    image: string;
+    state: string;
+    status: string;
+    ports: string;
+    createdAt: string;
+}
+
+export interface DockerContainerList {
+    id: string;
+    agentId: string;
+    status: 'running' | 'success' | 'error';
+    error?: string;
+    containers?: DockerContainer[];
+    startedAt: number;
+    finishedAt?: number;
+}
+
+export type DockerAction = 'start' | 'stop' | 'restart';
+
+export interface DockerOperation {
+    id: number;
+    agentId: string;
+    action: DockerAction;
+    container: string;
+    name: string;
+    status: 'running' | 'success' | 'error';
+    commandId: string;
+    output: string;
+    error: string;
+    username: string;
+    clientIp: string;
+    createdAt: number;
+    finishedAt: number;
+}
+
+// 获取探针最近一次的容器列表，未获取过时返回 null
+export const getDockerContainers = (agentId: string) => {
+    return get<DockerContainerList | null>(`/admin/agents/${agentId}/docker/containers`);
+};
+
+// 重新获取容器列表，结果通过 getDockerContainers 轮询获取
+export const refreshDockerContainers = (agentId: string) => {
+    return post<DockerContainerList>(`/admin/agents/${agentId}/docker/containers/refresh`);
+};
+
+// 启动、停止或重启容器，执行结果异步更新到容器操作记录
+export const executeDockerAction = (agentId: string, data: { action: DockerAction; container: string; name: string }) => {
+    return post<DockerOperation>(`/admin/agents/${agentId}/docker/actions`, data);
+};
+
+export const listDockerOperations = (agentId: string, pageIndex: number = 1, pageSize: number = 10) => {
+    const query = new URLSearchParams({
+        agentId,
+        pageIndex: pageIndex.toString(),
+        pageSize: pageSize.toString(),
+        sortField: 'createdAt',
+        sortOrder: 'desc',
+    });
+    return get<{ items: DockerOperation[]; total: number }>(`/admin/docker-operations?${query.toString()}`);
+};
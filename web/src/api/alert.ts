@@ -1,5 +1,14 @@
 import {del, get} from './request';
-import type {AlertRecord} from '@/types';
+import type {
+    Agent,
+    AggregatedCPUMetric,
+    AggregatedDiskMetric,
+    AggregatedMemoryMetric,
+    AggregatedNetworkConnectionMetric,
+    AggregatedNetworkMetric,
+    AlertRecord
+} from '@/types';
+import type {AgentSession, PowerTask} from './agent';
 
 // 注意：告警配置相关 API 已迁移到 property.ts 中
 // 使用 getAlertConfig() 和 saveAlertConfig() 从 '@/api/property' 导入
@@ -35,3 +44,46 @@ export const clearAlertRecords = async (agentId?: string): Promise<void> => {
     if (agentId) url += `?agentId=${agentId}`;
     await del(url);
 };
+
+// 告警修复动作执行记录
+export interface RemediationRecord {
+    id: number;
+    alertRecordId: number;
+    agentId: string;
+    alertType: string;
+    action: 'restart_service' | 'clean_dir';
+    target: string;
+    auto: boolean;
+    status: 'pending' | 'running' | 'success' | 'error' | 'rejected';
+    commandId: string;
+    output: string;
+    error: string;
+    createdAt: number;
+    updatedAt: number;
+}
+
+// 告警关联上下文，窗口为告警触发时间前后 15 分钟
+export interface AlertCorrelation {
+    alert: AlertRecord;
+    agent?: Agent; // 分组告警、系统告警没有对应的探针
+    windowStart: number;
+    windowEnd: number;
+    relatedAlerts: AlertRecord[];
+    metrics?: {
+        interval: number; // 聚合粒度（秒）
+        cpu: AggregatedCPUMetric[];
+        memory: AggregatedMemoryMetric[];
+        disk: AggregatedDiskMetric[];
+        network: AggregatedNetworkMetric[];
+        networkConnection: AggregatedNetworkConnectionMetric[];
+    };
+    remediations: RemediationRecord[];
+    powerTasks: PowerTask[];
+    sessions: AgentSession[];
+}
+
+// 获取告警关联上下文
+export const getAlertCorrelation = async (id: number): Promise<AlertCorrelation> => {
+    const response = await get<AlertCorrelation>(`/admin/alert-records/${id}/correlation`);
+    return response.data;
+};
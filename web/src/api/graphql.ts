@@ -0,0 +1,32 @@
+import {post} from './request';
+
+export interface GraphQLRequest {
+    query: string;
+    variables?: Record<string, unknown>;
+    operationName?: string;
+}
+
+export interface GraphQLError {
+    message: string;
+    path?: (string | number)[];
+}
+
+export interface GraphQLResponse<T> {
+    data: T | null;
+    errors?: GraphQLError[];
+}
+
+// 执行 GraphQL 查询，部分字段出错时仍返回其余字段的数据
+export const graphqlQuery = async <T = any>(
+    query: string,
+    variables?: Record<string, unknown>,
+): Promise<GraphQLResponse<T>> => {
+    const response = await post<GraphQLResponse<T>>('/admin/graphql', {query, variables});
+    return response.data;
+};
+
+// 批量执行多个 GraphQL 查询，只发送一次请求
+export const graphqlBatch = async (requests: GraphQLRequest[]): Promise<GraphQLResponse<any>[]> => {
+    const response = await post<GraphQLResponse<any>[]>('/admin/graphql', requests);
+    return response.data;
+};
@@ -0,0 +1,75 @@
+import {get} from './request';
+
+// 探针分组（以标签作为分组）
+export interface GroupSummary {
+    tag: string;
+    agentCount: number;
+    onlineCount: number;
+}
+
+export interface GroupUsage {
+    avg: number;
+    max: number;
+    min: number;
+}
+
+export interface GroupMember {
+    agentId: string;
+    agentName: string;
+    online: boolean;
+    reporting: boolean;
+    cpu: number;
+    memory: number;
+    disk: number;
+    sentRate: number;
+    recvRate: number;
+}
+
+export interface GroupMetrics {
+    tag: string;
+    agentCount: number;
+    onlineCount: number;
+    reportingCount: number;
+    cpu: GroupUsage;
+    memory: GroupUsage;
+    memoryTotal: number;
+    memoryUsed: number;
+    disk: GroupUsage;
+    diskTotal: number;
+    diskUsed: number;
+    sentRate: number;
+    recvRate: number;
+    members: GroupMember[];
+    timestamp: number;
+}
+
+export interface GroupMetricPoint {
+    timestamp: number;
+    avg: number;
+    max: number;
+    min: number;
+    sum: number;
+    count: number;
+}
+
+export interface GroupMetricsHistory {
+    tag: string;
+    type: string;
+    range: string;
+    start: number;
+    end: number;
+    metrics: GroupMetricPoint[];
+}
+
+export const listGroups = () => {
+    return get<GroupSummary[]>('/admin/groups');
+};
+
+export const getGroupMetrics = (tag: string) => {
+    return get<GroupMetrics>(`/admin/groups/${encodeURIComponent(tag)}/metrics`);
+};
+
+export const getGroupMetricsHistory = (tag: string, type: 'cpu' | 'memory' | 'disk' | 'network', range: string = '1h') => {
+    const params = new URLSearchParams({type, range});
+    return get<GroupMetricsHistory>(`/admin/groups/${encodeURIComponent(tag)}/metrics/history?${params.toString()}`);
+};
@@ -1,5 +1,5 @@
 import {get, post, put, del} from './request';
-import type {MonitorListResponse, MonitorTask, MonitorTaskRequest, MonitorStats, PublicMonitor} from '../types';
+import type {MonitorCert, MonitorGroup, MonitorMetric, MonitorSLAReport, MonitorTrace, MonitorListResponse, MonitorTask, MonitorTaskRequest, MonitorStats, PublicMonitor} from '../types';
 
 export const listMonitors = (page: number = 1, pageSize: number = 10, keyword?: string) => {
     const params = new URLSearchParams();
@@ -34,6 +34,11 @@ export const getPublicMonitors = () => {
     return get<PublicMonitor[]>('/monitors');
 };
 
+// 公开接口 - 获取监控分组的状态和可用率
+export const getMonitorGroups = () => {
+    return get<MonitorGroup[]>('/monitor-groups');
+};
+
 // 公开接口 - 获取指定监控的统计数据（聚合后的单个监控详情）
 export const getMonitorStatsById = (id: string) => {
     return get<PublicMonitor>(`/monitors/${encodeURIComponent(id)}/stats`);
@@ -44,6 +49,16 @@ export const getMonitorAgentStats = (id: string) => {
     return get<MonitorStats[]>(`/monitors/${encodeURIComponent(id)}/agents`);
 };
 
+// 公开接口 - 获取指定监控各探针检测到的证书详情
+export const getMonitorCerts = (id: string) => {
+    return get<MonitorCert[]>(`/monitors/${encodeURIComponent(id)}/certs`);
+};
+
+// 公开接口 - 获取指定监控最近的离线路径追踪
+export const getMonitorTraces = (id: string) => {
+    return get<MonitorTrace[]>(`/monitors/${encodeURIComponent(id)}/traces`);
+};
+
 // 聚合的监控历史数据
 export interface AggregatedMonitorMetric {
     timestamp: number;
@@ -62,3 +77,46 @@ export interface AggregatedMonitorMetric {
 export const getMonitorHistory = (id: string, range: string = '5m') => {
     return get<AggregatedMonitorMetric[]>(`/monitors/${encodeURIComponent(id)}/history?range=${range}`);
 };
+
+// 原始检测记录的筛选条件
+export interface MonitorRecordParams {
+    from: number;
+    to: number;
+    agentId?: string;
+    status?: 'up' | 'down';
+}
+
+const monitorRecordQuery = (params: MonitorRecordParams) => {
+    const query = new URLSearchParams();
+    query.append('from', params.from.toString());
+    query.append('to', params.to.toString());
+    if (params.agentId) {
+        query.append('agentId', params.agentId);
+    }
+    if (params.status) {
+        query.append('status', params.status);
+    }
+    return query;
+};
+
+// 管理员接口 - 分页查询监控项的原始检测记录，按时间倒序
+export const listMonitorRecords = (id: string, params: MonitorRecordParams, pageIndex: number = 1, pageSize: number = 50) => {
+    const query = monitorRecordQuery(params);
+    query.append('pageIndex', pageIndex.toString());
+    query.append('pageSize', pageSize.toString());
+    return get<{ items: MonitorMetric[]; total: number }>(`/admin/monitors/${encodeURIComponent(id)}/records?${query.toString()}`);
+};
+
+// 管理员接口 - 导出监控项的原始检测记录（CSV 或 JSON Lines）
+export const exportMonitorRecords = (id: string, params: MonitorRecordParams, format: 'csv' | 'json' = 'csv') => {
+    const query = monitorRecordQuery(params);
+    query.append('format', format);
+    // 长时间范围导出耗时较长，放宽超时时间
+    return get<string>(`/admin/monitors/${encodeURIComponent(id)}/records/export?${query.toString()}`, {timeout: 5 * 60 * 1000});
+};
+
+// 管理员接口 - 获取监控项某个自然月的 SLA 达成情况，month 为空时统计当月
+export const getMonitorSLAReport = (id: string, month?: string) => {
+    const query = month ? `?month=${month}` : '';
+    return get<MonitorSLAReport>(`/admin/monitors/${encodeURIComponent(id)}/sla${query}`);
+};
@@ -33,7 +33,11 @@ export interface TimeRangeOption {
 }
 
 export interface MetricsConfig {
-    retentionHours: number;       // 数据保留时长（小时）
+    retentionHours: number;       // 原始数据保留时长（小时）
+    rollupRetentionHours: number; // 聚合数据保留时长（小时）
+    monitorSampleSeconds: number; // 服务监控状态不变时的结果保存间隔（秒）
+    perCoreCpu: boolean;          // 是否采集每核 CPU 使用率
+    collectIntervals?: Record<string, number>; // 各类指标的采集间隔（秒），未配置的使用探针的采集间隔
     maxQueryPoints: number;       // 最大查询点数
     timeRangeOptions: TimeRangeOption[];  // 时间范围选项
 }
@@ -54,9 +58,10 @@ const PROPERTY_ID_NOTIFICATION_CHANNELS = 'notification_channels';
 
 // 通知渠道配置（通过 type 标识，不再使用独立ID）
 export interface NotificationChannel {
-    type: 'dingtalk' | 'wecom' | 'feishu' | 'email' | 'webhook'; // 渠道类型，作为唯一标识
+    type: 'dingtalk' | 'wecom' | 'feishu' | 'email' | 'ntfy' | 'gotify' | 'pushover' | 'matrix' | 'twilio' | 'webhook'; // 渠道类型，作为唯一标识
     enabled: boolean; // 是否启用
     config: Record<string, any>; // JSON配置，根据type不同而不同
+    events?: string[]; // 订阅的事件类型，为空时仅接收告警通知
 }
 
 // 获取通知渠道列表
@@ -70,9 +75,22 @@ export const saveNotificationChannels = async (channels: NotificationChannel[]):
     return saveProperty(PROPERTY_ID_NOTIFICATION_CHANNELS, '通知渠道配置', channels);
 };
 
-// 测试通知渠道（从数据库读取配置）
-export const testNotificationChannel = async (type: string): Promise<{ message: string }> => {
-    const response = await post<{ message: string }>(`/admin/notification-channels/${type}/test`);
+export interface ProviderResponse {
+    statusCode: number;
+    body: string;
+}
+
+export interface SampleNotificationResult {
+    channelType: string;
+    success: boolean;
+    error?: string;
+    latencyMs: number;
+    responses: ProviderResponse[];
+}
+
+// 测试通知渠道：使用已保存的配置发送一条示例告警，返回服务商的响应
+export const testNotificationChannel = async (type: string): Promise<SampleNotificationResult> => {
+    const response = await post<SampleNotificationResult>(`/admin/notifications/channels/${type}/test`);
     return response.data;
 };
 
@@ -122,12 +140,55 @@ export interface AlertRules {
     serviceDuration: number;   // 服务下线持续时间（秒）
     agentOfflineEnabled: boolean;   // 探针离线告警开关
     agentOfflineDuration: number;   // 探针离线持续时间（秒）
+    wireGuardEnabled: boolean;   // WireGuard 告警开关
+    wireGuardHandshakeThreshold: number;   // WireGuard 对端未握手时长阈值（秒）
+    mountEnabled: boolean;   // 网络挂载点告警开关
+    mountDuration: number;   // 挂载点异常持续时间（秒）
+    inodeEnabled: boolean;   // inode 使用率告警开关
+    inodeThreshold: number;  // inode 使用率阈值（%）
+    inodeDuration: number;
+    fdEnabled: boolean;      // 文件描述符使用率告警开关
+    fdThreshold: number;     // 文件描述符使用率阈值（%）
+    fdDuration: number;
+    stealEnabled: boolean;   // CPU steal 告警开关
+    stealThreshold: number;  // steal 占比阈值（%）
+    stealDuration: number;
+    clockEnabled: boolean;   // 时钟偏差告警开关
+    clockThreshold: number;  // 时钟偏差阈值（毫秒）
+    clockDuration: number;
+    portEnabled: boolean;    // 非预期监听端口告警开关
+    portAllowed: string[];   // 允许的端口，如 22、tcp/443、udp/53
+    portDuration: number;    // 端口持续监听多久后触发告警（秒）
+    kernelEnabled: boolean;  // 内核事件告警开关
+    kernelCategories: string[];  // 触发告警的事件分类: oom, io, hardware，为空表示全部
+    kernelRecovery: number;  // 同类事件多久未再出现后恢复（秒）
+    cooldowns?: Record<string, number>;   // 各告警类型恢复后的冷却期（秒）
 }
 
 // 全局告警配置
+// 分组告警规则：按标签汇总组内在线探针的指标
+export interface GroupAlertRule {
+    enabled: boolean;
+    tag: string;
+    metric: 'cpu' | 'memory' | 'disk' | 'network';
+    aggregation: 'avg' | 'max' | 'min' | 'sum';
+    threshold: number;
+    duration: number;    // 持续时间（秒）
+}
+
+// 监控分组告警规则：组内离线的监控项超过允许数量时告警
+export interface MonitorGroupAlertRule {
+    enabled: boolean;
+    group: string;
+    maxDown: number;     // 允许同时离线的监控项数量
+    duration: number;    // 持续时间（秒）
+}
+
 export interface AlertConfig {
     enabled: boolean;  // 全局告警开关
     rules: AlertRules;
+    groupRules?: GroupAlertRule[];
+    monitorGroupRules?: MonitorGroupAlertRule[];
 }
 
 // 获取告警配置
@@ -140,3 +201,48 @@ export const saveAlertConfig = async (config: AlertConfig): Promise<void> => {
     return saveProperty(PROPERTY_ID_ALERT_CONFIG, '告警配置', config);
 };
 
+
+// ==================== 维护窗口配置 ====================
+
+const PROPERTY_ID_MAINTENANCE_WINDOW = 'maintenance_window';
+
+// 维护窗口配置，启用后计划重启只会在窗口内执行
+export interface MaintenanceWindowConfig {
+    enabled: boolean;
+    weekdays?: number[]; // 允许维护的星期（0 为周日），为空表示每天
+    startTime: string;   // 开始时间 HH:mm（服务端时区）
+    endTime: string;     // 结束时间 HH:mm，早于开始时间时表示跨天
+}
+
+// 获取维护窗口配置
+export const getMaintenanceWindowConfig = async (): Promise<MaintenanceWindowConfig> => {
+    return getProperty<MaintenanceWindowConfig>(PROPERTY_ID_MAINTENANCE_WINDOW);
+};
+
+// 保存维护窗口配置
+export const saveMaintenanceWindowConfig = async (config: MaintenanceWindowConfig): Promise<void> => {
+    return saveProperty(PROPERTY_ID_MAINTENANCE_WINDOW, '维护窗口配置', config);
+};
+
+// ==================== InfluxDB 导出配置 ====================
+
+const PROPERTY_ID_INFLUXDB_CONFIG = 'influxdb_config';
+
+// InfluxDB 导出配置，启用后探针上报的指标会以行协议写入 InfluxDB v2
+export interface InfluxDBConfig {
+    enabled: boolean;
+    url: string;     // InfluxDB 地址，如 http://influxdb:8086
+    token: string;   // API Token
+    org: string;     // 组织名称或 ID
+    bucket: string;  // 写入的 bucket
+}
+
+// 获取 InfluxDB 导出配置
+export const getInfluxDBConfig = async (): Promise<InfluxDBConfig> => {
+    return getProperty<InfluxDBConfig>(PROPERTY_ID_INFLUXDB_CONFIG);
+};
+
+// 保存 InfluxDB 导出配置
+export const saveInfluxDBConfig = async (config: InfluxDBConfig): Promise<void> => {
+    return saveProperty(PROPERTY_ID_INFLUXDB_CONFIG, 'InfluxDB 导出配置', config);
+};
@@ -1,4 +1,6 @@
-const BASE_URL = '/api';
+import {withBasePath} from '@/lib/utils';
+
+const BASE_URL = withBasePath('/api');
 const DEFAULT_TIMEOUT = 30000;
 
 export interface RequestConfig extends RequestInit {
@@ -81,7 +83,7 @@ const sendRequest = async <T>(url: string, config: RequestConfig = {}): Promise<
         if (response.status === 401) {
             localStorage.removeItem('token');
             localStorage.removeItem('userInfo');
-            window.location.href = '/login';
+            window.location.href = withBasePath('/login');
             throw new HttpError('未认证或认证已过期', {
                 status: response.status,
                 statusText: response.statusText,
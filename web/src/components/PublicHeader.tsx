@@ -1,5 +1,6 @@
 import {useEffect, useRef, useState} from 'react';
 import {Activity, LayoutGrid, List, LogIn, Moon, Server, Settings, Sun} from 'lucide-react';
+import {withBasePath} from '@/lib/utils';
 import {getCurrentUser} from '../api/auth';
 import {Link, useLocation} from "react-router-dom";
 import {flushSync} from "react-dom";
@@ -127,7 +128,7 @@ const PublicHeader = ({
                         {/* Logo 和品牌 */}
                         <div className="flex items-center gap-2 sm:gap-3">
                             <img
-                                src={"/api/logo"}
+                                src={withBasePath("/api/logo")}
                                 className="h-8 w-8 sm:h-9 sm:w-9 object-contain rounded-md"
                                 alt={'logo'}
                                 onError={(e) => {
@@ -223,7 +224,7 @@ const PublicHeader = ({
                         {/* 登录/管理后台按钮 */}
                         {isLoggedIn ? (
                             <a
-                                href="/admin"
+                                href={withBasePath("/admin")}
                                 className="inline-flex items-center gap-1.5 rounded-lg bg-blue-600 px-2.5 py-1.5 sm:px-3 sm:py-2 text-xs font-medium text-white hover:bg-blue-700 dark:bg-blue-500 dark:text-slate-950 dark:hover:bg-blue-400 transition-all"
                                 target="_blank"
                             >
@@ -232,7 +233,7 @@ const PublicHeader = ({
                             </a>
                         ) : (
                             <a
-                                href="/login"
+                                href={withBasePath("/login")}
                                 className="inline-flex items-center gap-1.5 rounded-lg bg-blue-600 px-2.5 py-1.5 sm:px-3 sm:py-2 text-xs font-medium text-white hover:bg-blue-700 dark:bg-blue-500 dark:text-slate-950 dark:hover:bg-blue-400 transition-all"
                                 target="_blank"
                             >
@@ -1,6 +1,22 @@
 import {type ClassValue, clsx} from 'clsx';
 import {twMerge} from 'tailwind-merge';
 
+/**
+ * 部署子路径前缀，由服务端渲染到 index.html，根路径部署或开发模式下为空字符串
+ */
+export const basePath = (() => {
+    const value = window.SystemConfig?.BasePath || '';
+    // 开发模式下模板未渲染
+    return value.startsWith('{{') ? '' : value.replace(/\/+$/, '');
+})();
+
+/**
+ * 为站内绝对路径加上子路径前缀
+ */
+export function withBasePath(path: string) {
+    return `${basePath}${path}`;
+}
+
 export function cn(...inputs: ClassValue[]) {
     return twMerge(clsx(inputs));
 }
@@ -2,10 +2,10 @@ import {type JSX, useEffect, useMemo, useRef, useState} from 'react';
 import {Outlet, useLocation, useNavigate} from 'react-router-dom';
 import type {MenuProps} from 'antd';
 import {App, Avatar, Button, ConfigProvider, Dropdown, Space, theme} from 'antd';
-import {Activity, AlertTriangle, BookOpen, Eye, Globe, Key, LogOut, Moon, Server, Settings, Sun, User as UserIcon} from 'lucide-react';
+import {Activity, AlertTriangle, BookOpen, Eye, Globe, Key, LogOut, Moon, Network, Server, Settings, Sun, User as UserIcon} from 'lucide-react';
 import {logout} from '@/api/auth.ts';
 import type {User} from '@/types';
-import {cn} from '@/lib/utils';
+import {cn, withBasePath} from '@/lib/utils';
 import {getServerVersion, type VersionInfo} from "@/api/version.ts";
 import {flushSync} from "react-dom";
 
@@ -48,6 +48,12 @@ const AdminLayout = () => {
                 path: '/admin/monitors',
                 icon: <Activity className="h-4 w-4" strokeWidth={2}/>,
             },
+            {
+                key: 'latency-mesh',
+                label: '延迟网格',
+                path: '/admin/latency-mesh',
+                icon: <Network className="h-4 w-4" strokeWidth={2}/>,
+            },
             {
                 key: 'ddns',
                 label: 'DDNS',
@@ -204,7 +210,7 @@ const AdminLayout = () => {
                         <div className="flex items-center gap-3 text-white">
                             <div className="flex items-center justify-center">
                                 <img
-                                    src={"/api/logo"}
+                                    src={withBasePath("/api/logo")}
                                     alt="Logo"
                                     className="h-10 w-10 object-contain rounded-md"
                                     onError={(e) => {
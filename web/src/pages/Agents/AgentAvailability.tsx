@@ -0,0 +1,146 @@
+import React, {useEffect, useState} from 'react';
+import {App, Card, Col, Empty, Radio, Row, Space, Statistic, Table, Tooltip} from 'antd';
+import type {ColumnsType} from 'antd/es/table';
+import dayjs from 'dayjs';
+import {type AgentAvailability as Availability, type AgentDowntime, getAgentAvailability} from '@/api/agent.ts';
+import {getErrorMessage} from '@/lib/utils';
+
+interface AgentAvailabilityProps {
+    agentId: string;
+}
+
+const rangeOptions = [
+    {label: '24小时', value: '24h'},
+    {label: '7天', value: '7d'},
+    {label: '30天', value: '30d'},
+];
+
+// 将毫秒格式化为 x天x小时x分钟
+const formatDuration = (ms: number) => {
+    const minutes = Math.floor(ms / 60000);
+    if (minutes < 1) {
+        return `${Math.floor(ms / 1000)}秒`;
+    }
+    const days = Math.floor(minutes / 1440);
+    const hours = Math.floor((minutes % 1440) / 60);
+    const rest = minutes % 60;
+    return [
+        days > 0 ? `${days}天` : '',
+        hours > 0 ? `${hours}小时` : '',
+        rest > 0 ? `${rest}分钟` : '',
+    ].join('');
+};
+
+const AgentAvailability: React.FC<AgentAvailabilityProps> = ({agentId}) => {
+    const {message} = App.useApp();
+    const [range, setRange] = useState('7d');
+    const [loading, setLoading] = useState(false);
+    const [data, setData] = useState<Availability | null>(null);
+
+    useEffect(() => {
+        const loadData = async () => {
+            setLoading(true);
+            try {
+                const res = await getAgentAvailability(agentId, range);
+                setData(res.data);
+            } catch (error) {
+                message.error(getErrorMessage(error, '获取可用率失败'));
+            } finally {
+                setLoading(false);
+            }
+        };
+        loadData();
+    }, [agentId, range]);
+
+    const total = data ? data.end - data.start : 0;
+
+    const columns: ColumnsType<AgentDowntime> = [
+        {
+            title: '离线时间',
+            dataIndex: 'start',
+            render: (value: number) => dayjs(value).format('YYYY-MM-DD HH:mm:ss'),
+        },
+        {
+            title: '恢复时间',
+            dataIndex: 'end',
+            render: (value: number) => data && value >= data.end ? '-' : dayjs(value).format('YYYY-MM-DD HH:mm:ss'),
+        },
+        {
+            title: '持续时间',
+            dataIndex: 'duration',
+            render: (value: number) => formatDuration(value),
+        },
+    ];
+
+    return (
+        <Space direction="vertical" className="w-full" size="middle">
+            <Radio.Group
+                optionType="button"
+                options={rangeOptions}
+                value={range}
+                onChange={(e) => setRange(e.target.value)}
+            />
+
+            <Row gutter={[16, 16]}>
+                <Col xs={24} sm={8}>
+                    <Card loading={loading}>
+                        <Statistic title="在线率" value={data?.uptime ?? 0} precision={2} suffix="%"/>
+                    </Card>
+                </Col>
+                <Col xs={24} sm={8}>
+                    <Card loading={loading}>
+                        <Statistic title="在线时长" value={formatDuration(data?.onlineDuration ?? 0) || '-'}/>
+                    </Card>
+                </Col>
+                <Col xs={24} sm={8}>
+                    <Card loading={loading}>
+                        <Statistic title="离线时长" value={formatDuration(data?.offlineDuration ?? 0) || '-'}/>
+                    </Card>
+                </Col>
+            </Row>
+
+            {/* 时间线：绿色为在线，红色为离线 */}
+            <Card title="在线时间线" loading={loading}>
+                {data && total > 0 ? (
+                    <>
+                        <div className="relative h-6 w-full overflow-hidden rounded bg-green-500">
+                            {data.downtimes.map((downtime) => (
+                                <Tooltip
+                                    key={downtime.start}
+                                    title={`${dayjs(downtime.start).format('MM-DD HH:mm')} ~ ${dayjs(downtime.end).format('MM-DD HH:mm')}，离线 ${formatDuration(downtime.duration)}`}
+                                >
+                                    <div
+                                        className="absolute top-0 h-full bg-red-500"
+                                        style={{
+                                            left: `${(downtime.start - data.start) / total * 100}%`,
+                                            width: `max(2px, ${downtime.duration / total * 100}%)`,
+                                        }}
+                                    />
+                                </Tooltip>
+                            ))}
+                        </div>
+                        <div className="mt-2 flex justify-between text-xs text-gray-500">
+                            <span>{dayjs(data.start).format('YYYY-MM-DD HH:mm')}</span>
+                            <span>{dayjs(data.end).format('YYYY-MM-DD HH:mm')}</span>
+                        </div>
+                    </>
+                ) : (
+                    <Empty description="暂无连接记录"/>
+                )}
+            </Card>
+
+            <Card title="离线记录">
+                <Table
+                    rowKey="start"
+                    size="small"
+                    loading={loading}
+                    columns={columns}
+                    dataSource={[...(data?.downtimes || [])].reverse()}
+                    pagination={{pageSize: 10, hideOnSinglePage: true}}
+                />
+            </Card>
+        </Space>
+    );
+};
+
+export default AgentAvailability;
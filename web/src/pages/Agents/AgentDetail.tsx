@@ -1,15 +1,34 @@
 import {useEffect, useState} from 'react';
 import {useNavigate, useParams, useSearchParams} from 'react-router-dom';
 import type {MenuProps, TabsProps} from 'antd';
-import {Alert, App, Button, Card, Descriptions, Dropdown, Space, Spin, Tabs, Tag} from 'antd';
-import {Activity, ArrowLeft, Clock, FileWarning, RefreshCw, Shield, Terminal} from 'lucide-react';
+import {Alert, App, Button, Card, Descriptions, Dropdown, Space, Spin, Tabs, Tag, Tooltip} from 'antd';
+import {Activity, ArrowLeft, Clock, Command, Container, Cpu, Download, FileText, FileWarning, Gauge, HardDrive, Network, Plug, Power, RefreshCw, Shield, SquareTerminal, Terminal} from 'lucide-react';
 import TamperProtection from './TamperProtection.tsx';
-import {getAgentForAdmin, getAuditResult, sendAuditCommand, type VPSAuditResult} from '@/api/agent.ts';
-import type {Agent} from '@/types';
+import PingTargets from './PingTargets.tsx';
+import AgentAvailability from './AgentAvailability.tsx';
+import PowerManagement from './PowerManagement.tsx';
+import DiskUsage from './DiskUsage.tsx';
+import ListeningPorts from './ListeningPorts.tsx';
+import RemoteTerminal from './RemoteTerminal.tsx';
+import RemoteCommands from './RemoteCommands.tsx';
+import Processes from './Processes.tsx';
+import LogTail from './LogTail.tsx';
+import DockerContainers from './DockerContainers.tsx';
+import MetricExportModal from './MetricExportModal.tsx';
+import {getAgentForAdmin, getAgentLatestMetrics, getAuditResult, sendAuditCommand, type VPSAuditResult} from '@/api/agent.ts';
+import type {Agent, CollectorHealth, CPUTimes, CustomMetric, FileUsage, MountStatus, Pressure, TimeSync} from '@/types';
 import dayjs from 'dayjs';
 import {getErrorMessage} from '@/lib/utils';
 import AuditResultView from './AuditResultView';
 
+const mountStatusText: Record<string, string> = {
+    ok: '正常',
+    hung: '挂起',
+    stale: '句柄失效',
+    unmounted: '未挂载',
+    error: '访问失败',
+};
+
 const AgentDetail = () => {
     const {id} = useParams<{ id: string }>();
     const navigate = useNavigate();
@@ -18,7 +37,16 @@ const AgentDetail = () => {
     const [loading, setLoading] = useState(false);
     const [agent, setAgent] = useState<Agent | null>(null);
     const [auditResult, setAuditResult] = useState<VPSAuditResult | null>(null);
+    const [collectors, setCollectors] = useState<CollectorHealth[]>([]);
+    const [mounts, setMounts] = useState<MountStatus[]>([]);
+    const [fileUsage, setFileUsage] = useState<FileUsage | null>(null);
+    const [timeSync, setTimeSync] = useState<TimeSync | null>(null);
+    const [customMetrics, setCustomMetrics] = useState<CustomMetric[]>([]);
+    const [cpuPerCore, setCpuPerCore] = useState<number[]>([]);
+    const [cpuTimes, setCpuTimes] = useState<CPUTimes | null>(null);
+    const [pressure, setPressure] = useState<Pressure | null>(null);
     const [auditing, setAuditing] = useState(false);
+    const [exportOpen, setExportOpen] = useState(false);
     const [activeTab, setActiveTab] = useState<string>(searchParams.get('tab') || 'info');
 
     const fetchData = async () => {
@@ -26,13 +54,22 @@ const AgentDetail = () => {
 
         setLoading(true);
         try {
-            const [agentRes, auditRes] = await Promise.all([
+            const [agentRes, auditRes, latestRes] = await Promise.all([
                 getAgentForAdmin(id),
                 getAuditResult(id).catch(() => ({data: null})),
+                getAgentLatestMetrics(id).catch(() => ({data: null})),
             ]);
 
             setAgent(agentRes.data);
             setAuditResult(auditRes.data);
+            setCollectors(latestRes.data?.collectors || []);
+            setMounts(latestRes.data?.mounts || []);
+            setFileUsage(latestRes.data?.fileUsage || null);
+            setTimeSync(latestRes.data?.timeSync || null);
+            setCustomMetrics(latestRes.data?.custom || []);
+            setCpuPerCore(latestRes.data?.cpuPerCore || []);
+            setCpuTimes(latestRes.data?.cpuTimes || null);
+            setPressure(latestRes.data?.pressure || null);
         } catch (error: any) {
             messageApi.error(error.response?.data?.message || '获取探针信息失败');
         } finally {
@@ -90,6 +127,12 @@ const AgentDetail = () => {
             label: '安全审计',
             onClick: handleStartAudit,
         },
+        {
+            key: 'export',
+            icon: <Download size={16}/>,
+            label: '导出历史指标',
+            onClick: () => setExportOpen(true),
+        },
         {
             type: 'divider',
         },
@@ -153,6 +196,184 @@ const AgentDetail = () => {
                             {agent?.lastSeenAt && dayjs(agent.lastSeenAt).format('YYYY-MM-DD HH:mm:ss')}
                         </Space>
                     </Descriptions.Item>
+                    <Descriptions.Item label="采集器状态" span={2}>
+                        {collectors.length === 0 ? '-' : (
+                            <Space size={[4, 4]} wrap>
+                                {collectors.map(item => (
+                                    <Tooltip
+                                        key={item.name}
+                                        title={
+                                            <div>
+                                                <div>耗时: {item.lastDuration}ms</div>
+                                                {item.lastSuccessAt && <div>最近成功: {dayjs(item.lastSuccessAt).format('YYYY-MM-DD HH:mm:ss')}</div>}
+                                                {item.stuckSince && <div>卡住开始: {dayjs(item.stuckSince).format('YYYY-MM-DD HH:mm:ss')}</div>}
+                                                {item.lastError && <div>错误: {item.lastError}</div>}
+                                                <div>超时 {item.timeouts} 次，恢复 {item.restarts} 次</div>
+                                            </div>
+                                        }
+                                    >
+                                        <Tag
+                                            bordered={false}
+                                            color={item.status === 'ok' ? 'green' : item.status === 'stuck' ? 'red' : 'orange'}
+                                        >
+                                            {item.name}
+                                        </Tag>
+                                    </Tooltip>
+                                ))}
+                            </Space>
+                        )}
+                    </Descriptions.Item>
+                    {mounts.length > 0 && (
+                        <Descriptions.Item label="网络挂载点" span={2}>
+                            <Space size={[4, 4]} wrap>
+                                {mounts.map(mount => (
+                                    <Tooltip
+                                        key={mount.mountPoint}
+                                        title={
+                                            <div>
+                                                {mount.device && <div>设备: {mount.device}{mount.fstype && ` (${mount.fstype})`}</div>}
+                                                <div>状态: {mountStatusText[mount.status] || mount.status}</div>
+                                                <div>耗时: {mount.latency}ms</div>
+                                                {mount.error && <div>错误: {mount.error}</div>}
+                                            </div>
+                                        }
+                                    >
+                                        <Tag bordered={false} color={mount.status === 'ok' ? 'green' : mount.status === 'unmounted' || mount.status === 'error' ? 'orange' : 'red'}>
+                                            {mount.mountPoint}
+                                        </Tag>
+                                    </Tooltip>
+                                ))}
+                            </Space>
+                        </Descriptions.Item>
+                    )}
+                    {timeSync && (
+                        <Descriptions.Item label="时钟偏差" span={2}>
+                            {timeSync.error ? (
+                                <Tooltip title={timeSync.error}>
+                                    <Tag bordered={false} color="orange">{timeSync.server} 查询失败</Tag>
+                                </Tooltip>
+                            ) : (
+                                <Tooltip
+                                    title={
+                                        <div>
+                                            <div>NTP 服务器: {timeSync.server} (stratum {timeSync.stratum})</div>
+                                            <div>往返时延: {timeSync.rtt.toFixed(1)}ms</div>
+                                            <div>检查时间: {dayjs(timeSync.checkedAt).format('YYYY-MM-DD HH:mm:ss')}</div>
+                                        </div>
+                                    }
+                                >
+                                    <Tag bordered={false} color={Math.abs(timeSync.offset) >= 1000 ? 'red' : Math.abs(timeSync.offset) >= 100 ? 'orange' : 'green'}>
+                                        {timeSync.offset > 0 ? '+' : ''}{timeSync.offset.toFixed(1)}ms
+                                    </Tag>
+                                </Tooltip>
+                            )}
+                        </Descriptions.Item>
+                    )}
+                    {cpuTimes && (
+                        <Descriptions.Item label="CPU 时间分布" span={2}>
+                            <Space size={[4, 4]} wrap>
+                                <Tag bordered={false}>user {cpuTimes.user.toFixed(1)}%</Tag>
+                                <Tag bordered={false}>system {cpuTimes.system.toFixed(1)}%</Tag>
+                                <Tag bordered={false} color={cpuTimes.iowait >= 20 ? 'orange' : undefined}>
+                                    iowait {cpuTimes.iowait.toFixed(1)}%
+                                </Tag>
+                                <Tooltip title="被宿主机上其他虚拟机占用的时间，持续偏高说明宿主机资源争抢严重">
+                                    <Tag bordered={false} color={cpuTimes.steal >= 10 ? 'red' : undefined}>
+                                        steal {cpuTimes.steal.toFixed(1)}%
+                                    </Tag>
+                                </Tooltip>
+                                <Tag bordered={false}>idle {cpuTimes.idle.toFixed(1)}%</Tag>
+                            </Space>
+                        </Descriptions.Item>
+                    )}
+                    {pressure && (
+                        <Descriptions.Item label="PSI 压力" span={2}>
+                            <Space size={[4, 4]} wrap>
+                                {([['CPU', pressure.cpu], ['内存', pressure.memory], ['IO', pressure.io]] as const).map(([name, stat]) => stat && (
+                                    <Tooltip
+                                        key={name}
+                                        title={
+                                            <div>
+                                                <div>some 10s/60s/300s: {stat.some.avg10.toFixed(2)}% / {stat.some.avg60.toFixed(2)}% / {stat.some.avg300.toFixed(2)}%</div>
+                                                {stat.full && (
+                                                    <div>full 10s/60s/300s: {stat.full.avg10.toFixed(2)}% / {stat.full.avg60.toFixed(2)}% / {stat.full.avg300.toFixed(2)}%</div>
+                                                )}
+                                            </div>
+                                        }
+                                    >
+                                        <Tag bordered={false} color={stat.some.avg60 >= 40 ? 'red' : stat.some.avg60 >= 10 ? 'orange' : 'green'}>
+                                            {name} {stat.some.avg60.toFixed(2)}%
+                                        </Tag>
+                                    </Tooltip>
+                                ))}
+                            </Space>
+                        </Descriptions.Item>
+                    )}
+                    {cpuPerCore.length > 0 && (
+                        <Descriptions.Item label="每核 CPU 使用率" span={2}>
+                            <Space size={[4, 4]} wrap>
+                                {cpuPerCore.map((usage, index) => (
+                                    <Tag
+                                        key={index}
+                                        bordered={false}
+                                        className="font-mono"
+                                        color={usage >= 90 ? 'red' : usage >= 70 ? 'orange' : 'green'}
+                                    >
+                                        #{index} {usage.toFixed(1)}%
+                                    </Tag>
+                                ))}
+                            </Space>
+                        </Descriptions.Item>
+                    )}
+                    {fileUsage?.fileDescriptors && (
+                        <Descriptions.Item label="文件描述符" span={2}>
+                            <Tag bordered={false} color={fileUsage.fileDescriptors.usagePercent >= 90 ? 'red' : 'green'}>
+                                {fileUsage.fileDescriptors.allocated} / {fileUsage.fileDescriptors.max}
+                                （{fileUsage.fileDescriptors.usagePercent.toFixed(2)}%）
+                            </Tag>
+                        </Descriptions.Item>
+                    )}
+                    {fileUsage?.inodes && fileUsage.inodes.length > 0 && (
+                        <Descriptions.Item label="inode 使用率" span={2}>
+                            <Space size={[4, 4]} wrap>
+                                {fileUsage.inodes.map(inode => (
+                                    <Tooltip
+                                        key={inode.mountPoint}
+                                        title={
+                                            <div>
+                                                <div>设备: {inode.device} ({inode.fstype})</div>
+                                                <div>已用: {inode.used} / {inode.total}</div>
+                                                <div>剩余: {inode.free}</div>
+                                            </div>
+                                        }
+                                    >
+                                        <Tag bordered={false} color={inode.usagePercent >= 90 ? 'red' : inode.usagePercent >= 80 ? 'orange' : 'green'}>
+                                            {inode.mountPoint} {inode.usagePercent.toFixed(1)}%
+                                        </Tag>
+                                    </Tooltip>
+                                ))}
+                            </Space>
+                        </Descriptions.Item>
+                    )}
+                    {customMetrics.length > 0 && (
+                        <Descriptions.Item label="自定义指标" span={2}>
+                            <Space size={[4, 4]} wrap>
+                                {customMetrics.map(metric => {
+                                    const labels = Object.entries(metric.labels || {})
+                                        .map(([name, value]) => `${name}="${value}"`)
+                                        .join(',');
+                                    const key = `${metric.source}:${metric.name}{${labels}}`;
+                                    return (
+                                        <Tooltip key={key} title={`来源: ${metric.source}`}>
+                                            <Tag bordered={false} className="font-mono">
+                                                {metric.name}{labels && `{${labels}}`} = {metric.value}
+                                            </Tag>
+                                        </Tooltip>
+                                    );
+                                })}
+                            </Space>
+                        </Descriptions.Item>
+                    )}
                     <Descriptions.Item label="创建时间">
                         {agent?.createdAt && dayjs(agent.createdAt).format('YYYY-MM-DD HH:mm:ss')}
                     </Descriptions.Item>
@@ -228,6 +449,106 @@ const AgentDetail = () => {
                 />
             ),
         },
+        {
+            key: 'ping',
+            label: (
+                <div className="flex items-center gap-2 text-sm">
+                    <Network size={16}/>
+                    <div>网络质量</div>
+                </div>
+            ),
+            children: agent ? <PingTargets agentId={agent.id}/> : null,
+        },
+        {
+            key: 'availability',
+            label: (
+                <div className="flex items-center gap-2 text-sm">
+                    <Gauge size={16}/>
+                    <div>可用率</div>
+                </div>
+            ),
+            children: agent ? <AgentAvailability agentId={agent.id}/> : null,
+        },
+        {
+            key: 'power',
+            label: (
+                <div className="flex items-center gap-2 text-sm">
+                    <Power size={16}/>
+                    <div>电源管理</div>
+                </div>
+            ),
+            children: agent ? <PowerManagement agentId={agent.id}/> : null,
+        },
+        {
+            key: 'disk-usage',
+            label: (
+                <div className="flex items-center gap-2 text-sm">
+                    <HardDrive size={16}/>
+                    <div>磁盘分析</div>
+                </div>
+            ),
+            children: agent ? <DiskUsage agentId={agent.id}/> : null,
+        },
+        {
+            key: 'ports',
+            label: (
+                <div className="flex items-center gap-2 text-sm">
+                    <Plug size={16}/>
+                    <div>监听端口</div>
+                </div>
+            ),
+            children: agent ? <ListeningPorts agentId={agent.id}/> : null,
+        },
+        {
+            key: 'terminal',
+            label: (
+                <div className="flex items-center gap-2 text-sm">
+                    <SquareTerminal size={16}/>
+                    <div>远程终端</div>
+                </div>
+            ),
+            children: agent ? <RemoteTerminal agentId={agent.id}/> : null,
+        },
+        {
+            key: 'commands',
+            label: (
+                <div className="flex items-center gap-2 text-sm">
+                    <Command size={16}/>
+                    <div>远程命令</div>
+                </div>
+            ),
+            children: agent ? <RemoteCommands agentId={agent.id}/> : null,
+        },
+        {
+            key: 'processes',
+            label: (
+                <div className="flex items-center gap-2 text-sm">
+                    <Cpu size={16}/>
+                    <div>进程</div>
+                </div>
+            ),
+            children: agent ? <Processes agentId={agent.id}/> : null,
+        },
+        {
+            key: 'logs',
+            label: (
+                <div className="flex items-center gap-2 text-sm">
+                    <FileText size={16}/>
+                    <div>日志查看</div>
+                </div>
+            ),
+            children: agent ? <LogTail agentId={agent.id}/> : null,
+        },
+        {
+            key: 'docker',
+            label: (
+                <div className="flex items-center gap-2 text-sm">
+                    <Container size={16}/>
+                    <div>Docker 容器</div>
+                </div>
+            ),
+            children: agent ? <DockerContainers agentId={agent.id}/> : null,
+        },
     ];
 
     return (
@@ -288,6 +609,8 @@ const AgentDetail = () => {
                 items={tabItems}
 
             />
+
+            {id && <MetricExportModal agentId={id} open={exportOpen} onClose={() => setExportOpen(false)}/>}
         </div>
     );
 };
@@ -3,6 +3,7 @@ import {Alert, App, Button, Card, Select, Space, Tabs, Typography} from 'antd';
 import {CopyIcon} from 'lucide-react';
 import {listApiKeys} from '@/api/apiKey.ts';
 import type {ApiKey} from '@/types';
+import {basePath, withBasePath} from '@/lib/utils';
 import linuxPng from '../../assets/os/linux.png';
 import applePng from '../../assets/os/apple.png';
 import windowsPng from '../../assets/os/win11.png';
@@ -39,7 +40,7 @@ const AgentInstall = () => {
 
     const {message} = App.useApp();
     const navigate = useNavigate();
-    const serverUrl = useMemo(() => window.location.origin, []);
+    const serverUrl = useMemo(() => window.location.origin + basePath, []);
 
     // 加载API密钥列表
     useEffect(() => {
@@ -136,7 +137,7 @@ const AgentInstall = () => {
                         message="暂无可用的 API Token"
                         description={
                             <span>
-                                请先前往 <a href="/admin/api-keys">API密钥管理</a> 页面生成一个 API Token
+                                请先前往 <a href={withBasePath("/admin/api-keys")}>API密钥管理</a> 页面生成一个 API Token
                             </span>
                         }
                         type="warning"
@@ -4,8 +4,8 @@ import type {ActionType, ProColumns} from '@ant-design/pro-components';
 import {ProTable} from '@ant-design/pro-components';
 import type {MenuProps} from 'antd';
 import {App, Button, DatePicker, Divider, Dropdown, Form, Input, Modal, Select, Space, Tag} from 'antd';
-import {Edit, Eye, MoreVertical, Plus, RefreshCw, Shield, Trash2} from 'lucide-react';
-import {deleteAgent, getAgentPaging, getTags, updateAgentInfo} from '@/api/agent.ts';
+import {Download, Edit, Eye, MoreVertical, Plus, RefreshCw, Shield, Trash2} from 'lucide-react';
+import {deleteAgent, exportAgentInventory, getAgentPaging, getTags, updateAgentInfo} from '@/api/agent.ts';
 import type {Agent} from '@/types';
 import {getErrorMessage} from '@/lib/utils';
 import dayjs from 'dayjs';
@@ -40,6 +40,7 @@ const AgentList = () => {
         form.setFieldsValue({
             name: agent.name,
             tags: agent.tags || [],
+            region: agent.region || '',
             expireTime: agent.expireTime ? dayjs(agent.expireTime) : null,
             visibility: agent.visibility || 'public',
         });
@@ -60,6 +61,7 @@ const AgentList = () => {
                 name: values.name,
                 visibility: values.visibility || 'public',
                 tags: values.tags || [],
+                region: values.region?.trim() || '',
             };
 
             if (values.expireTime) {
@@ -286,6 +288,22 @@ const AgentList = () => {
         },
     ];
 
+    // 导出探针资产清单，加上 BOM 以便 Excel 正确识别中文
+    const handleExport = async () => {
+        try {
+            const res = await exportAgentInventory();
+            const blob = new Blob(['\uFEFF', res.data], {type: 'text/csv;charset=utf-8'});
+            const url = URL.createObjectURL(blob);
+            const link = document.createElement('a');
+            link.href = url;
+            link.download = `agents-${dayjs().format('YYYYMMDDHHmmss')}.csv`;
+            link.click();
+            URL.revokeObjectURL(url);
+        } catch (error) {
+            messageApi.error(getErrorMessage(error, '导出失败'));
+        }
+    };
+
     return (
         <div className="space-y-6">
             {/* 页面头部 */}
@@ -300,6 +318,12 @@ const AgentList = () => {
                         onClick: () => navigate('/admin/agents-install'),
                         type: 'primary',
                     },
+                    {
+                        key: 'export',
+                        label: '导出',
+                        icon: <Download size={16}/>,
+                        onClick: handleExport,
+                    },
                     {
                         key: 'refresh',
                         label: '刷新',
@@ -378,6 +402,13 @@ const AgentList = () => {
                             tokenSeparators={[',']}
                         />
                     </Form.Item>
+                    <Form.Item
+                        label="地区"
+                        name="region"
+                        extra="服务监控按探针所在地区统计响应时间，如 华东、us-west"
+                    >
+                        <Input placeholder="可选"/>
+                    </Form.Item>
                     <Form.Item
                         label="到期时间"
                         name="expireTime"
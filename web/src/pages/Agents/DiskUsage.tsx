@@ -0,0 +1,171 @@
+import React, {useEffect, useState} from 'react';
+import {Alert, App, Button, Card, Form, Input, InputNumber, Progress, Space, Table, Tag} from 'antd';
+import type {ColumnsType} from 'antd/es/table';
+import dayjs from 'dayjs';
+import {type DiskUsageEntry, type DiskUsageScan, getDiskUsage, scanDiskUsage} from '@/api/agent.ts';
+import {getErrorMessage} from '@/lib/utils';
+
+interface DiskUsageProps {
+    agentId: string;
+}
+
+interface ScanFormValues {
+    path: string;
+    depth: number;
+    limit: number;
+}
+
+const formatBytes = (bytes: number): string => {
+    if (!bytes || bytes <= 0) return '0 B';
+    const k = 1024;
+    const sizes = ['B', 'KB', 'MB', 'GB', 'TB'];
+    const i = Math.min(Math.floor(Math.log(bytes) / Math.log(k)), sizes.length - 1);
+    return `${(bytes / Math.pow(k, i)).toFixed(2)} ${sizes[i]}`;
+};
+
+const DiskUsage: React.FC<DiskUsageProps> = ({agentId}) => {
+    const {message} = App.useApp();
+    const [form] = Form.useForm<ScanFormValues>();
+    const [scan, setScan] = useState<DiskUsageScan | null>(null);
+    const [submitting, setSubmitting] = useState(false);
+
+    const loadData = async () => {
+        try {
+            const res = await getDiskUsage(agentId);
+            setScan(res.data || null);
+        } catch (error) {
+            message.error(getErrorMessage(error, '获取磁盘占用分析结果失败'));
+        }
+    };
+
+    useEffect(() => {
+        loadData();
+    }, [agentId]);
+
+    // 扫描进行中时轮询结果
+    useEffect(() => {
+        if (scan?.status !== 'running') {
+            return;
+        }
+        const timer = setInterval(loadData, 2000);
+        return () => clearInterval(timer);
+    }, [scan?.status, agentId]);
+
+    const handleScan = async () => {
+        const values = await form.validateFields();
+        setSubmitting(true);
+        try {
+            const res = await scanDiskUsage(agentId, values);
+            setScan(res.data);
+        } catch (error) {
+            message.error(getErrorMessage(error, '发起磁盘占用分析失败'));
+        } finally {
+            setSubmitting(false);
+        }
+    };
+
+    const result = scan?.status === 'success' ? scan.result : undefined;
+    const totalSize = result?.totalSize || 0;
+
+    const columns: ColumnsType<DiskUsageEntry> = [
+        {
+            title: '路径',
+            dataIndex: 'path',
+            ellipsis: true,
+            render: (path: string) => <span className="font-mono text-xs">{path}</span>,
+        },
+        {
+            title: '占用',
+            dataIndex: 'size',
+            width: 120,
+            render: (size: number) => formatBytes(size),
+        },
+        {
+            title: '占比',
+            dataIndex: 'size',
+            key: 'percent',
+            width: 180,
+            render: (size: number) => (
+                <Progress
+                    percent={totalSize > 0 ? Number((size / totalSize * 100).toFixed(1)) : 0}
+                    size="small"
+                />
+            ),
+        },
+    ];
+
+    return (
+        <Space direction="vertical" className="w-full" size="middle">
+            <Card size="small" title="磁盘占用分析">
+                <Form
+                    form={form}
+                    layout="inline"
+                    initialValues={{path: '/', depth: 2, limit: 20}}
+                >
+                    <Form.Item name="path" label="路径" rules={[{required: true, message: '请输入扫描路径'}]}>
+                        <Input placeholder="/var" style={{width: 240}}/>
+                    </Form.Item>
+                    <Form.Item name="depth" label="目录深度" tooltip="统计目录占用的最大层级，更深的目录计入上层目录">
+                        <InputNumber min={1} max={5}/>
+                    </Form.Item>
+                    <Form.Item name="limit" label="数量" tooltip="返回占用最大的目录和文件数量">
+                        <InputNumber min={1} max={100}/>
+                    </Form.Item>
+                    <Form.Item>
+                        <Button type="primary" onClick={handleScan} loading={submitting || scan?.status === 'running'}>
+                            开始分析
+                        </Button>
+                    </Form.Item>
+                </Form>
+                <div className="mt-2 text-xs text-gray-500">
+                    不跨越文件系统、不跟随符号链接，扫描超过 2 分钟时返回已扫描部分的结果
+                </div>
+            </Card>
+
+            {scan?.status === 'running' && (
+                <Alert type="info" showIcon message={`正在分析 ${scan.path}，开始于 ${dayjs(scan.startedAt).format('HH:mm:ss')}`}/>
+            )}
+            {scan?.status === 'error' && (
+                <Alert type="error" showIcon message={`分析 ${scan.path} 失败`} description={scan.error}/>
+            )}
+
+            {result && (
+                <>
+                    <div className="flex flex-wrap items-center gap-2 text-sm">
+                        <Tag color="blue">总占用 {formatBytes(result.totalSize)}</Tag>
+                        <Tag>文件 {result.fileCount}</Tag>
+                        <Tag>目录 {result.dirCount}</Tag>
+                        <Tag>耗时 {(result.duration / 1000).toFixed(1)}s</Tag>
+                        {result.errors > 0 && <Tag color="orange">无法读取 {result.errors}</Tag>}
+                        {result.truncated && <Tag color="red">扫描超时，结果不完整</Tag>}
+                        {scan?.finishedAt && (
+                            <span className="text-xs text-gray-500">
+                                完成于 {dayjs(scan.finishedAt).format('YYYY-MM-DD HH:mm:ss')}
+                            </span>
+                        )}
+                    </div>
+                    <Card size="small" title="占用最大的目录">
+                        <Table
+                            rowKey="path"
+                            size="small"
+                            columns={columns}
+                            dataSource={result.directories || []}
+                            pagination={false}
+                        />
+                    </Card>
+                    <Card size="small" title="占用最大的文件">
+                        <Table
+                            rowKey="path"
+                            size="small"
+                            columns={columns}
+                            dataSource={result.files || []}
+                            pagination={false}
+                        />
+                    </Card>
+                </>
+            )}
+        </Space>
+    );
+};
+
+export default DiskUsage;
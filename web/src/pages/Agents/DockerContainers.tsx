@@ -0,0 +1,310 @@
+import React, {useEffect, useRef, useState} from 'react';
+import {Alert, App, Button, Card, Popconfirm, Space, Table, Tag} from 'antd';
+import type {ColumnsType} from 'antd/es/table';
+import {RefreshCw} from 'lucide-react';
+import dayjs from 'dayjs';
+import {
+    type DockerAction,
+    type DockerContainer,
+    type DockerContainerList,
+    type DockerOperation,
+    executeDockerAction,
+    getDockerContainers,
+    listDockerOperations,
+    refreshDockerContainers,
+} from '@/api/agent.ts';
+import {getErrorMessage} from '@/lib/utils';
+
+interface DockerContainersProps {
+    agentId: string;
+}
+
+const PAGE_SIZE = 10;
+// 获取容器列表或有执行中的操作时的轮询间隔
+const POLL_INTERVAL = 2000;
+
+const actionText: Record<DockerAction, string> = {
+    start: '启动',
+    stop: '停止',
+    restart: '重启',
+};
+
+const stateColor: Record<string, string> = {
+    running: 'green',
+    paused: 'orange',
+    restarting: 'processing',
+    exited: 'default',
+    dead: 'red',
+    created: 'blue',
+};
+
+const statusMap: Record<DockerOperation['status'], { color: string; text: string }> = {
+    running: {color: 'processing', text: '执行中'},
+    success: {color: 'green', text: '成功'},
+    error: {color: 'red', text: '失败'},
+};
+
+const DockerContainers: React.FC<DockerContainersProps> = ({agentId}) => {
+    const {message} = App.useApp();
+    const [list, setList] = useState<DockerContainerList | null>(null);
+    const [refreshing, setRefreshing] = useState(false);
+    const [records, setRecords] = useState<DockerOperation[]>([]);
+    const [total, setTotal] = useState(0);
+    const [pageIndex, setPageIndex] = useState(1);
+    const [loading, setLoading] = useState(false);
+
+    const loadContainers = async () => {
+        try {
+            const res = await getDockerContainers(agentId);
+            setList(res.data);
+            return res.data;
+        } catch (error) {
+            message.error(getErrorMessage(error, '获取容器列表失败'));
+            return null;
+        }
+    };
+
+    const handleRefresh = async () => {
+        setRefreshing(true);
+        try {
+            const res = await refreshDockerContainers(agentId);
+            setList(res.data);
+        } catch (error) {
+            message.error(getErrorMessage(error, '获取容器列表失败'));
+        } finally {
+            setRefreshing(false);
+        }
+    };
+
+    const loadRecords = async (page: number = pageIndex, silent: boolean = false) => {
+        if (!silent) {
+            setLoading(true);
+        }
+        try {
+            const res = await listDockerOperations(agentId, page, PAGE_SIZE);
+            setRecords(res.data.items || []);
+            setTotal(res.data.total || 0);
+            setPageIndex(page);
+        } catch (error) {
+            if (!silent) {
+                message.error(getErrorMessage(error, '获取容器操作记录失败'));
+            }
+        } finally {
+            setLoading(false);
+        }
+    };
+
+    useEffect(() => {
+        const init = async () => {
+            const current = await loadContainers();
+            if (!current) {
+                handleRefresh();
+            }
+            loadRecords(1);
+        };
+        init();
+    }, [agentId]);
+
+    const listRunning = list?.status === 'running';
+    useEffect(() => {
+        if (!listRunning) {
+            return;
+        }
+        const timer = window.setInterval(loadContainers, POLL_INTERVAL);
+        return () => window.clearInterval(timer);
+    }, [listRunning, agentId]);
+
+    const hasRunning = records.some((item) => item.status === 'running');
+    const prevHasRunning = useRef(false);
+    useEffect(() => {
+        // 容器操作结束后重新获取容器列表，显示最新的状态
+        if (prevHasRunning.current && !hasRunning) {
+            handleRefresh();
+        }
+        prevHasRunning.current = hasRunning;
+        if (!hasRunning) {
+            return;
+        }
+        const timer = window.setInterval(() => loadRecords(pageIndex, true), POLL_INTERVAL);
+        return () => window.clearInterval(timer);
+    }, [hasRunning, pageIndex]);
+
+    const handleAction = async (container: DockerContainer, action: DockerAction) => {
+        try {
+            await executeDockerAction(agentId, {action, container: container.id, name: container.name});
+            message.success(`${actionText[action]}指令已下发`);
+            loadRecords(1);
+        } catch (error) {
+            message.error(getErrorMessage(error, `${actionText[action]}容器失败`));
+        }
+    };
+
+    const containerColumns: ColumnsType<DockerContainer> = [
+        {
+            title: '名称',
+            dataIndex: 'name',
+            width: 180,
+            ellipsis: true,
+        },
+        {
+            title: '镜像',
+            dataIndex: 'image',
+            width: 200,
+            ellipsis: true,
+        },
+        {
+            title: '状态',
+            dataIndex: 'state',
+            width: 100,
+            render: (state: string) => <Tag color={stateColor[state] || 'default'}>{state}</Tag>,
+        },
+        {
+            title: '状态描述',
+            dataIndex: 'status',
+            width: 180,
+            ellipsis: true,
+        },
+        {
+            title: '端口',
+            dataIndex: 'ports',
+            ellipsis: true,
+            render: (value: string) => value || '-',
+        },
+        {
+            title: '操作',
+            key: 'action',
+            width: 180,
+            render: (_, record) => {
+                const running = record.state === 'running' || record.state === 'restarting';
+                return (
+                    <Space size="small">
+                        {running ? (
+                            <Popconfirm title={`确定停止容器 ${record.name} 吗？`} onConfirm={() => handleAction(record, 'stop')}>
+                                <Button type="link" size="small" danger>停止</Button>
+                            </Popconfirm>
+                        ) : (
+                            <Popconfirm title={`确定启动容器 ${record.name} 吗？`} onConfirm={() => handleAction(record, 'start')}>
+                                <Button type="link" size="small">启动</Button>
+                            </Popconfirm>
+                        )}
+                        <Popconfirm title={`确定重启容器 ${record.name} 吗？`} onConfirm={() => handleAction(record, 'restart')}>
+                            <Button type="link" size="small" danger>重启</Button>
+                        </Popconfirm>
+                    </Space>
+                );
+            },
+        },
+    ];
+
+    const recordColumns: ColumnsType<DockerOperation> = [
+        {
+            title: '操作时间',
+            dataIndex: 'createdAt',
+            width: 180,
+            render: (value: number) => dayjs(value).format('YYYY-MM-DD HH:mm:ss'),
+        },
+        {
+            title: '操作',
+            dataIndex: 'action',
+            width: 80,
+            render: (action: DockerAction) => actionText[action] || action,
+        },
+        {
+            title: '容器',
+            dataIndex: 'name',
+            width: 160,
+            ellipsis: true,
+            render: (name: string, record) => name || record.container,
+        },
+        {
+            title: '状态',
+            dataIndex: 'status',
+            width: 90,
+            render: (status: DockerOperation['status']) => {
+                const item = statusMap[status];
+                return item ? <Tag color={item.color}>{item.text}</Tag> : status;
+            },
+        },
+        {
+            title: '用户',
+            dataIndex: 'username',
+            width: 120,
+        },
+        {
+            title: 'IP',
+            dataIndex: 'clientIp',
+            width: 140,
+        },
+        {
+            title: '错误信息',
+            dataIndex: 'error',
+            ellipsis: true,
+            render: (value: string) => value || '-',
+        },
+    ];
+
+    return (
+        <Space direction="vertical" size="large" className="w-full">
+            <Card
+                title="容器列表"
+                extra={
+                    <Button icon={<RefreshCw size={14}/>} loading={refreshing || listRunning} onClick={handleRefresh}>
+                        刷新
+                    </Button>
+                }
+            >
+                <Alert
+                    className="mb-4"
+                    type="info"
+                    showIcon
+                    message="需要探针在配置文件中开启 docker.enabled，且探针有执行 docker 命令的权限。每次启动、停止、重启容器都会记录在下方的容器操作记录中。"
+                />
+                {list?.status === 'error' && (
+                    <Alert className="mb-4" type="warning" showIcon message={`获取容器列表失败：${list.error}`}/>
+                )}
+                <Table
+                    columns={containerColumns}
+                    dataSource={list?.containers || []}
+                    rowKey="id"
+                    size="small"
+                    loading={listRunning && !list?.containers?.length}
+                    pagination={false}
+                    footer={list?.finishedAt ? () => `更新于 ${dayjs(list.finishedAt).format('YYYY-MM-DD HH:mm:ss')}` : undefined}
+                    scroll={{x: 900}}
+                />
+            </Card>
+
+            <Card
+                title="容器操作记录"
+                extra={
+                    <Button icon={<RefreshCw size={14}/>} onClick={() => loadRecords()}>
+                        刷新
+                    </Button>
+                }
+            >
+                <Table
+                    columns={recordColumns}
+                    dataSource={records}
+                    rowKey="id"
+                    loading={loading}
+                    expandable={{
+                        rowExpandable: (record) => !!record.output,
+                        expandedRowRender: (record) => (
+                            <pre className="m-0 max-h-80 overflow-auto whitespace-pre-wrap break-all text-xs">{record.output}</pre>
+                        ),
+                    }}
+                    pagination={{
+                        current: pageIndex,
+                        pageSize: PAGE_SIZE,
+                        total,
+                        showSizeChanger: false,
+                        onChange: (page) => loadRecords(page),
+                    }}
+                    scroll={{x: 900}}
+                />
+            </Card>
+        </Space>
+    );
+};
+
+export default DockerContainers;
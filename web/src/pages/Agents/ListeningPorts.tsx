@@ -0,0 +1,96 @@
+import React, {useEffect, useState} from 'react';
+import {App, Button, Card, Table, Tag} from 'antd';
+import type {ColumnsType} from 'antd/es/table';
+import {RefreshCw} from 'lucide-react';
+import {getAgentLatestMetrics} from '@/api/agent.ts';
+import type {ListeningPort} from '@/types';
+import {getErrorMessage} from '@/lib/utils';
+
+interface ListeningPortsProps {
+    agentId: string;
+}
+
+const ListeningPorts: React.FC<ListeningPortsProps> = ({agentId}) => {
+    const {message} = App.useApp();
+    const [ports, setPorts] = useState<ListeningPort[]>([]);
+    const [loading, setLoading] = useState(false);
+
+    const loadData = async () => {
+        setLoading(true);
+        try {
+            const res = await getAgentLatestMetrics(agentId);
+            setPorts(res.data?.listeningPorts || []);
+        } catch (error) {
+            message.error(getErrorMessage(error, '获取监听端口失败'));
+        } finally {
+            setLoading(false);
+        }
+    };
+
+    useEffect(() => {
+        loadData();
+    }, [agentId]);
+
+    const columns: ColumnsType<ListeningPort> = [
+        {
+            title: '协议',
+            dataIndex: 'protocol',
+            width: 80,
+            filters: [{text: 'TCP', value: 'tcp'}, {text: 'UDP', value: 'udp'}],
+            onFilter: (value, record) => record.protocol === value,
+            render: (protocol: string) => <Tag bordered={false}>{protocol.toUpperCase()}</Tag>,
+        },
+        {
+            title: '端口',
+            dataIndex: 'port',
+            width: 100,
+            sorter: (a, b) => a.port - b.port,
+        },
+        {
+            title: '监听地址',
+            dataIndex: 'address',
+            render: (address: string) => <span className="font-mono text-xs">{address}</span>,
+        },
+        {
+            title: '暴露',
+            dataIndex: 'isPublic',
+            width: 100,
+            filters: [{text: '对外', value: true}, {text: '仅本机', value: false}],
+            onFilter: (value, record) => record.isPublic === value,
+            render: (isPublic: boolean) => isPublic
+                ? <Tag bordered={false} color="orange">对外</Tag>
+                : <Tag bordered={false}>仅本机</Tag>,
+        },
+        {
+            title: '进程',
+            dataIndex: 'processName',
+            render: (name: string, record) => name ? `${name} (${record.processPid})` : '-',
+        },
+    ];
+
+    return (
+        <Card
+            size="small"
+            title="监听端口"
+            extra={
+                <Button size="small" icon={<RefreshCw size={14}/>} onClick={loadData} loading={loading}>
+                    刷新
+                </Button>
+            }
+        >
+            <div className="mb-2 text-xs text-gray-500">
+                探针每分钟采集一次 TCP 监听和未连接的 UDP 套接字，非回环地址上不在允许列表中的端口可在告警设置中配置告警
+            </div>
+            <Table
+                rowKey={(record) => `${record.protocol}:${record.address}:${record.port}`}
+                size="small"
+                columns={columns}
+                dataSource={ports}
+                loading={loading}
+                pagination={false}
+            />
+        </Card>
+    );
+};
+
+export default ListeningPorts;
@@ -0,0 +1,143 @@
+import React, {useEffect, useRef, useState} from 'react';
+import {Alert, App, Button, Card, Form, Input, InputNumber, Select, Space} from 'antd';
+import {createLogTailTicket, getLogTailWebSocketURL} from '@/api/agent.ts';
+import {getErrorMessage} from '@/lib/utils';
+
+interface LogTailProps {
+    agentId: string;
+}
+
+interface LogTailFormValues {
+    source: 'file' | 'journal';
+    target: string;
+    lines: number;
+    duration: number;
+}
+
+// 浏览器中最多保留的日志字符数，超出后丢弃最早的内容
+const MAX_OUTPUT_LENGTH = 512 * 1024;
+
+const LogTail: React.FC<LogTailProps> = ({agentId}) => {
+    const {message} = App.useApp();
+    const [form] = Form.useForm<LogTailFormValues>();
+    const [connecting, setConnecting] = useState(false);
+    const [connected, setConnected] = useState(false);
+    const [closedReason, setClosedReason] = useState('');
+    const [output, setOutput] = useState('');
+
+    const socketRef = useRef<WebSocket | null>(null);
+    const screenRef = useRef<HTMLPreElement | null>(null);
+
+    const source = Form.useWatch('source', form);
+
+    useEffect(() => {
+        return () => socketRef.current?.close();
+    }, [agentId]);
+
+    useEffect(() => {
+        const screen = screenRef.current;
+        if (screen) {
+            screen.scrollTop = screen.scrollHeight;
+        }
+    }, [output]);
+
+    const handleOpen = async () => {
+        const values = await form.validateFields();
+        setConnecting(true);
+        try {
+            const res = await createLogTailTicket(agentId);
+            const socket = new WebSocket(getLogTailWebSocketURL(res.data.ticket, values.source, values.target, values.lines, values.duration));
+            let reason = '';
+
+            socket.onopen = () => {
+                setConnecting(false);
+                setConnected(true);
+                setClosedReason('');
+                setOutput('');
+            };
+            socket.onmessage = (event) => {
+                const msg = JSON.parse(event.data as string) as { type: string; data?: string; reason?: string };
+                if (msg.type === 'log' && msg.data) {
+                    const data = msg.data;
+                    setOutput((prev) => (prev + data).slice(-MAX_OUTPUT_LENGTH));
+                } else if (msg.type === 'closed') {
+                    reason = msg.reason || '';
+                }
+            };
+            socket.onclose = () => {
+                socketRef.current = null;
+                setConnecting(false);
+                setConnected(false);
+                setClosedReason(reason || '连接已断开');
+            };
+            socketRef.current = socket;
+        } catch (error) {
+            setConnecting(false);
+            message.error(getErrorMessage(error, '查看日志失败'));
+        }
+    };
+
+    return (
+        <Card title="日志查看">
+            <Alert
+                className="mb-4"
+                type="info"
+                showIcon
+                message="只能查看探针配置文件中 log_tail.paths 和 log_tail.units 白名单内的日志，每次最长 5 分钟、最多 2MB。服务端会限制每秒转发的行数，超出的日志行会被丢弃并提示丢弃的行数。"
+            />
+            <Form
+                form={form}
+                layout="inline"
+                className="mb-4 gap-y-3"
+                initialValues={{source: 'file', lines: 100, duration: 300}}
+            >
+                <Form.Item name="source">
+                    <Select
+                        style={{width: 140}}
+                        disabled={connected}
+                        options={[
+                            {label: '日志文件', value: 'file'},
+                            {label: 'systemd 服务', value: 'journal'},
+                        ]}
+                    />
+                </Form.Item>
+                <Form.Item name="target" rules={[{required: true, message: '请输入日志文件路径或服务名称'}]}>
+                    <Input
+                        disabled={connected}
+                        placeholder={source === 'journal' ? '服务名称，如 nginx' : '文件路径，如 /var/log/syslog'}
+                        style={{width: 280}}
+                    />
+                </Form.Item>
+                <Form.Item name="lines" label="初始行数">
+                    <InputNumber min={0} max={500} disabled={connected} style={{width: 100}}/>
+                </Form.Item>
+                <Form.Item name="duration" label="时长（秒）">
+                    <InputNumber min={1} max={300} disabled={connected} style={{width: 100}}/>
+                </Form.Item>
+                <Form.Item>
+                    <Space>
+                        {connected ? (
+                            <Button onClick={() => socketRef.current?.close()}>停止</Button>
+                        ) : (
+                            <Button type="primary" loading={connecting} onClick={handleOpen}>
+                                开始查看
+                            </Button>
+                        )}
+                        <Button onClick={() => setOutput('')}>清空</Button>
+                    </Space>
+                </Form.Item>
+            </Form>
+            {closedReason && !connected && (
+                <Alert className="mb-4" type="warning" showIcon message={`日志查看已结束：${closedReason}`}/>
+            )}
+            <pre
+                ref={screenRef}
+                className="m-0 h-[480px] overflow-auto whitespace-pre-wrap break-all rounded bg-black p-3 font-mono text-[13px] leading-[18px] text-gray-100"
+            >
+                {output}
+            </pre>
+        </Card>
+    );
+};
+
+export default LogTail;
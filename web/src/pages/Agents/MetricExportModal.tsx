@@ -0,0 +1,130 @@
+import {useState} from 'react';
+import {App, DatePicker, Form, Input, Modal, Radio, Select} from 'antd';
+import type {Dayjs} from 'dayjs';
+import dayjs from 'dayjs';
+import {exportAgentMetrics} from '@/api/agent.ts';
+import {getErrorMessage} from '@/lib/utils';
+
+interface MetricExportModalProps {
+    agentId: string;
+    open: boolean;
+    onClose: () => void;
+}
+
+interface ExportFormValues {
+    type: string;
+    range: [Dayjs, Dayjs];
+    step: string;
+    format: 'csv' | 'json';
+    interface?: string;
+}
+
+const metricTypeOptions = [
+    {label: 'CPU', value: 'cpu'},
+    {label: '每核 CPU', value: 'cpu_core'},
+    {label: 'CPU 时间分布', value: 'cpu_times'},
+    {label: 'PSI 压力', value: 'pressure'},
+    {label: '内存', value: 'memory'},
+    {label: '磁盘', value: 'disk'},
+    {label: '磁盘 IO', value: 'disk_io'},
+    {label: '网络', value: 'network'},
+    {label: '网络连接', value: 'network_connection'},
+    {label: 'GPU', value: 'gpu'},
+    {label: '温度', value: 'temperature'},
+    {label: 'Ping', value: 'ping'},
+];
+
+const stepOptions = [
+    {label: '1 分钟', value: '1m'},
+    {label: '5 分钟', value: '5m'},
+    {label: '15 分钟', value: '15m'},
+    {label: '1 小时', value: '1h'},
+];
+
+const MetricExportModal = ({agentId, open, onClose}: MetricExportModalProps) => {
+    const {message} = App.useApp();
+    const [form] = Form.useForm<ExportFormValues>();
+    const [exporting, setExporting] = useState(false);
+    const metricType = Form.useWatch('type', form);
+
+    const handleExport = async () => {
+        const values = await form.validateFields();
+        setExporting(true);
+        try {
+            const [start, end] = values.range;
+            const res = await exportAgentMetrics(agentId, {
+                type: values.type,
+                from: start.valueOf(),
+                to: end.valueOf(),
+                step: values.step,
+                format: values.format,
+                interface: values.type === 'network' ? values.interface : undefined,
+            });
+            const isCSV = values.format === 'csv';
+            const blob = isCSV
+                ? new Blob(['\uFEFF', res.data], {type: 'text/csv;charset=utf-8'})
+                : new Blob([res.data], {type: 'application/x-ndjson'});
+            const url = URL.createObjectURL(blob);
+            const link = document.createElement('a');
+            link.href = url;
+            link.download = `agent-${agentId}-${values.type}-${dayjs().format('YYYYMMDDHHmmss')}.${isCSV ? 'csv' : 'jsonl'}`;
+            link.click();
+            URL.revokeObjectURL(url);
+            onClose();
+        } catch (error) {
+            message.error(getErrorMessage(error, '导出失败'));
+        } finally {
+            setExporting(false);
+        }
+    };
+
+    return (
+        <Modal
+            title="导出历史指标"
+            open={open}
+            onOk={handleExport}
+            onCancel={onClose}
+            okText="导出"
+            confirmLoading={exporting}
+            destroyOnClose
+        >
+            <Form
+                form={form}
+                layout="vertical"
+                initialValues={{
+                    type: 'cpu',
+                    range: [dayjs().subtract(7, 'day'), dayjs()],
+                    step: '5m',
+                    format: 'csv',
+                }}
+            >
+                <Form.Item label="指标类型" name="type" rules={[{required: true, message: '请选择指标类型'}]}>
+                    <Select options={metricTypeOptions}/>
+                </Form.Item>
+                {metricType === 'network' && (
+                    <Form.Item label="网卡" name="interface" extra="留空导出全部网卡的汇总数据">
+                        <Input placeholder="例如 eth0"/>
+                    </Form.Item>
+                )}
+                <Form.Item label="时间范围" name="range" rules={[{required: true, message: '请选择时间范围'}]}>
+                    <DatePicker.RangePicker showTime className="w-full"/>
+                </Form.Item>
+                <Form.Item
+                    label="聚合粒度"
+                    name="step"
+                    extra="有预聚合数据的指标按不小于所选值的预聚合粒度导出"
+                >
+                    <Select options={stepOptions}/>
+                </Form.Item>
+                <Form.Item label="格式" name="format">
+                    <Radio.Group>
+                        <Radio value="csv">CSV</Radio>
+                        <Radio value="json">JSON Lines</Radio>
+                    </Radio.Group>
+                </Form.Item>
+            </Form>
+        </Modal>
+    );
+};
+
+export default MetricExportModal;
@@ -0,0 +1,203 @@
+import React, {useEffect, useState} from 'react';
+import {App, Button, Form, Input, Modal, Popconfirm, Space, Switch, Table, Tag} from 'antd';
+import type {ColumnsType} from 'antd/es/table';
+import {Plus, RefreshCw} from 'lucide-react';
+import {
+    createPingTarget,
+    deletePingTarget,
+    getAgentMetrics,
+    listPingTargets,
+    type PingTarget,
+    type PingTargetRequest,
+    updatePingTarget
+} from '@/api/agent.ts';
+import {getErrorMessage} from '@/lib/utils';
+
+interface PingTargetsProps {
+    agentId: string;
+}
+
+interface PingSummary {
+    avgLatency: number;
+    maxLatency: number;
+    packetLoss: number;
+}
+
+const PingTargets: React.FC<PingTargetsProps> = ({agentId}) => {
+    const {message} = App.useApp();
+    const [form] = Form.useForm<PingTargetRequest>();
+    const [targets, setTargets] = useState<PingTarget[]>([]);
+    const [summaries, setSummaries] = useState<Record<string, PingSummary>>({});
+    const [loading, setLoading] = useState(false);
+    const [editing, setEditing] = useState<PingTarget | null>(null);
+    const [modalOpen, setModalOpen] = useState(false);
+
+    const loadData = async () => {
+        setLoading(true);
+        try {
+            const [targetsRes, metricsRes] = await Promise.all([
+                listPingTargets(agentId),
+                getAgentMetrics({agentId, type: 'ping', range: '1h'}),
+            ]);
+            setTargets(targetsRes.data || []);
+
+            // 汇总最近 1 小时每个目标的延迟和丢包
+            const grouped: Record<string, { latency: number[]; max: number; loss: number[] }> = {};
+            for (const metric of metricsRes.data.metrics || []) {
+                const item = grouped[metric.targetId] || (grouped[metric.targetId] = {latency: [], max: 0, loss: []});
+                if (metric.packetLoss < 100) {
+                    item.latency.push(metric.avgLatency);
+                }
+                item.max = Math.max(item.max, metric.maxLatency);
+                item.loss.push(metric.packetLoss);
+            }
+            const result: Record<string, PingSummary> = {};
+            const avg = (values: number[]) => values.length ? values.reduce((a, b) => a + b, 0) / values.length : 0;
+            Object.entries(grouped).forEach(([targetId, item]) => {
+                result[targetId] = {
+                    avgLatency: avg(item.latency),
+                    maxLatency: item.max,
+                    packetLoss: avg(item.loss),
+                };
+            });
+            setSummaries(result);
+        } catch (error) {
+            message.error(getErrorMessage(error, '获取 Ping 目标失败'));
+        } finally {
+            setLoading(false);
+        }
+    };
+
+    useEffect(() => {
+        loadData();
+    }, [agentId]);
+
+    const openModal = (target?: PingTarget) => {
+        setEditing(target || null);
+        form.setFieldsValue(target
+            ? {name: target.name, target: target.target, enabled: target.enabled}
+            : {name: '', target: '', enabled: true});
+        setModalOpen(true);
+    };
+
+    const handleSubmit = async () => {
+        const values = await form.validateFields();
+        try {
+            if (editing) {
+                await updatePingTarget(agentId, editing.id, values);
+            } else {
+                await createPingTarget(agentId, values);
+            }
+            message.success('保存成功');
+            setModalOpen(false);
+            loadData();
+        } catch (error) {
+            message.error(getErrorMessage(error, '保存失败'));
+        }
+    };
+
+    const handleDelete = async (target: PingTarget) => {
+        try {
+            await deletePingTarget(agentId, target.id);
+            message.success('删除成功');
+            loadData();
+        } catch (error) {
+            message.error(getErrorMessage(error, '删除失败'));
+        }
+    };
+
+    const columns: ColumnsType<PingTarget> = [
+        {title: '名称', dataIndex: 'name'},
+        {title: '目标地址', dataIndex: 'target'},
+        {
+            title: '状态',
+            dataIndex: 'enabled',
+            render: (enabled: boolean) => enabled ? <Tag color="green">启用</Tag> : <Tag>停用</Tag>,
+        },
+        {
+            title: '平均延迟 (1h)',
+            render: (_, record) => {
+                const summary = summaries[record.id];
+                return summary ? `${summary.avgLatency.toFixed(1)} ms` : '-';
+            },
+        },
+        {
+            title: '最大延迟 (1h)',
+            render: (_, record) => {
+                const summary = summaries[record.id];
+                return summary ? `${summary.maxLatency.toFixed(1)} ms` : '-';
+            },
+        },
+        {
+            title: '丢包率 (1h)',
+            render: (_, record) => {
+                const summary = summaries[record.id];
+                if (!summary) {
+                    return '-';
+                }
+                const color = summary.packetLoss >= 10 ? 'red' : summary.packetLoss > 0 ? 'orange' : 'green';
+                return <Tag color={color}>{summary.packetLoss.toFixed(1)}%</Tag>;
+            },
+        },
+        {
+            title: '操作',
+            render: (_, record) => (
+                <Space>
+                    <Button type="link" size="small" onClick={() => openModal(record)}>编辑</Button>
+                    <Popconfirm title="确定删除该 Ping 目标吗？" onConfirm={() => handleDelete(record)}>
+                        <Button type="link" size="small" danger>删除</Button>
+                    </Popconfirm>
+                </Space>
+            ),
+        },
+    ];
+
+    return (
+        <div className="space-y-4">
+            <div className="flex items-center justify-between">
+                <div className="text-sm text-gray-500">
+                    探针每分钟 Ping 一次以下目标（网关、上游 DNS、关键服务等），记录延迟和丢包
+                </div>
+                <Space>
+                    <Button icon={<RefreshCw size={14}/>} onClick={loadData}>刷新</Button>
+                    <Button type="primary" icon={<Plus size={14}/>} onClick={() => openModal()}>添加目标</Button>
+                </Space>
+            </div>
+
+            <Table
+                rowKey="id"
+                size="small"
+                loading={loading}
+                columns={columns}
+                dataSource={targets}
+                pagination={false}
+            />
+
+            <Modal
+                title={editing ? '编辑 Ping 目标' : '添加 Ping 目标'}
+                open={modalOpen}
+                onOk={handleSubmit}
+                onCancel={() => setModalOpen(false)}
+                destroyOnClose
+            >
+                <Form form={form} layout="vertical">
+                    <Form.Item label="名称" name="name">
+                        <Input placeholder="例如：网关"/>
+                    </Form.Item>
+                    <Form.Item
+                        label="目标地址"
+                        name="target"
+                        rules={[{required: true, message: '请输入目标地址'}]}
+                    >
+                        <Input placeholder="IP 或域名，例如 192.168.1.1"/>
+                    </Form.Item>
+                    <Form.Item label="启用" name="enabled" valuePropName="checked">
+                        <Switch/>
+                    </Form.Item>
+                </Form>
+            </Modal>
+        </div>
+    );
+};
+
+export default PingTargets;
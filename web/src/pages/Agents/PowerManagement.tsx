@@ -0,0 +1,198 @@
+import React, {useEffect, useState} from 'react';
+import {Alert, App, Button, Card, DatePicker, Form, Input, Popconfirm, Space, Table, Tag} from 'antd';
+import type {ColumnsType} from 'antd/es/table';
+import {RefreshCw} from 'lucide-react';
+import type {Dayjs} from 'dayjs';
+import dayjs from 'dayjs';
+import {
+    cancelPowerTask,
+    confirmPowerTask,
+    listPowerTasks,
+    type PowerTask,
+    scheduleReboot,
+    wakeOnLAN
+} from '@/api/agent.ts';
+import {getErrorMessage} from '@/lib/utils';
+
+interface PowerManagementProps {
+    agentId: string;
+}
+
+const statusMap: Record<PowerTask['status'], { color: string; text: string }> = {
+    pending: {color: 'gold', text: '待确认'},
+    scheduled: {color: 'blue', text: '等待执行'},
+    running: {color: 'processing', text: '执行中'},
+    success: {color: 'green', text: '成功'},
+    error: {color: 'red', text: '失败'},
+    cancelled: {color: 'default', text: '已取消'},
+};
+
+const PowerManagement: React.FC<PowerManagementProps> = ({agentId}) => {
+    const {message} = App.useApp();
+    const [rebootForm] = Form.useForm<{ scheduledAt?: Dayjs }>();
+    const [wolForm] = Form.useForm<{ mac: string; broadcast?: string }>();
+    const [tasks, setTasks] = useState<PowerTask[]>([]);
+    const [loading, setLoading] = useState(false);
+
+    const loadData = async () => {
+        setLoading(true);
+        try {
+            const res = await listPowerTasks(agentId);
+            setTasks(res.data || []);
+        } catch (error) {
+            message.error(getErrorMessage(error, '获取电源操作任务失败'));
+        } finally {
+            setLoading(false);
+        }
+    };
+
+    useEffect(() => {
+        loadData();
+    }, [agentId]);
+
+    const handleReboot = async () => {
+        const values = await rebootForm.validateFields();
+        try {
+            await scheduleReboot(agentId, values.scheduledAt?.valueOf());
+            message.success('重启任务已创建，请在下方列表中确认');
+            rebootForm.resetFields();
+            loadData();
+        } catch (error) {
+            message.error(getErrorMessage(error, '创建重启任务失败'));
+        }
+    };
+
+    const handleWakeOnLAN = async () => {
+        const values = await wolForm.validateFields();
+        try {
+            await wakeOnLAN(agentId, values.mac, values.broadcast);
+            message.success('网络唤醒指令已下发');
+            loadData();
+        } catch (error) {
+            message.error(getErrorMessage(error, '下发网络唤醒失败'));
+        }
+    };
+
+    const handleConfirm = async (task: PowerTask) => {
+        try {
+            await confirmPowerTask(task.id);
+            message.success('重启任务已确认');
+            loadData();
+        } catch (error) {
+            message.error(getErrorMessage(error, '确认失败'));
+        }
+    };
+
+    const handleCancel = async (task: PowerTask) => {
+        try {
+            await cancelPowerTask(task.id);
+            message.success('任务已取消');
+            loadData();
+        } catch (error) {
+            message.error(getErrorMessage(error, '取消失败'));
+        }
+    };
+
+    const columns: ColumnsType<PowerTask> = [
+        {
+            title: '操作类型',
+            dataIndex: 'action',
+            render: (action: PowerTask['action'], record) => action === 'reboot'
+                ? '重启'
+                : `网络唤醒 ${record.targetMac}`,
+        },
+        {
+            title: '计划时间',
+            dataIndex: 'scheduledAt',
+            render: (value: number) => dayjs(value).format('YYYY-MM-DD HH:mm'),
+        },
+        {
+            title: '状态',
+            dataIndex: 'status',
+            render: (status: PowerTask['status']) => {
+                const item = statusMap[status];
+                return item ? <Tag color={item.color}>{item.text}</Tag> : status;
+            },
+        },
+        {
+            title: '结果',
+            render: (_, record) => record.error || record.output || '-',
+        },
+        {
+            title: '操作',
+            render: (_, record) => (
+                <Space>
+                    {record.status === 'pending' && (
+                        <Popconfirm title="确认后将在计划时间重启该服务器，确定吗？" onConfirm={() => handleConfirm(record)}>
+                            <Button type="link" size="small" danger>确认重启</Button>
+                        </Popconfirm>
+                    )}
+                    {(record.status === 'pending' || record.status === 'scheduled') && (
+                        <Button type="link" size="small" onClick={() => handleCancel(record)}>取消</Button>
+                    )}
+                </Space>
+            ),
+        },
+    ];
+
+    return (
+        <div className="space-y-4">
+            <Alert
+                type="info"
+                showIcon
+                message="探针需要在配置文件中开启 power.allow_reboot / power.allow_wake_on_lan 才会执行对应指令。启用维护窗口后，重启任务只会在窗口内执行。"
+            />
+
+            <div className="grid gap-4 lg:grid-cols-2">
+                <Card size="small" title="计划重启">
+                    <Form form={rebootForm} layout="vertical">
+                        <Form.Item label="执行时间" name="scheduledAt" extra="留空表示确认后尽快执行">
+                            <DatePicker
+                                showTime={{format: 'HH:mm'}}
+                                format="YYYY-MM-DD HH:mm"
+                                disabledDate={(current) => current && current < dayjs().startOf('day')}
+                                style={{width: '100%'}}
+                            />
+                        </Form.Item>
+                        <Button danger onClick={handleReboot}>创建重启任务</Button>
+                    </Form>
+                </Card>
+
+                <Card size="small" title="网络唤醒（以本机作为局域网中继）">
+                    <Form form={wolForm} layout="vertical">
+                        <Form.Item
+                            label="目标 MAC 地址"
+                            name="mac"
+                            rules={[
+                                {required: true, message: '请输入 MAC 地址'},
+                                {pattern: /^([0-9A-Fa-f]{2}[:-]){5}[0-9A-Fa-f]{2}$/, message: 'MAC 地址格式错误'},
+                            ]}
+                        >
+                            <Input placeholder="例如 00:11:22:33:44:55"/>
+                        </Form.Item>
+                        <Form.Item label="广播地址" name="broadcast" extra="默认 255.255.255.255:9">
+                            <Input placeholder="例如 192.168.1.255:9"/>
+                        </Form.Item>
+                        <Button type="primary" onClick={handleWakeOnLAN}>发送唤醒</Button>
+                    </Form>
+                </Card>
+            </div>
+
+            <div className="flex items-center justify-between">
+                <div className="text-sm text-gray-500">最近的电源操作任务</div>
+                <Button icon={<RefreshCw size={14}/>} onClick={loadData}>刷新</Button>
+            </div>
+
+            <Table
+                rowKey="id"
+                size="small"
+                loading={loading}
+                columns={columns}
+                dataSource={tasks}
+                pagination={false}
+            />
+        </div>
+    );
+};
+
+export default PowerManagement;
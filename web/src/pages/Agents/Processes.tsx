@@ -0,0 +1,332 @@
+import React, {useEffect, useRef, useState} from 'react';
+import {Alert, App, Button, Card, Input, Popconfirm, Space, Table, Tag} from 'antd';
+import type {ColumnsType} from 'antd/es/table';
+import {RefreshCw} from 'lucide-react';
+import dayjs from 'dayjs';
+import {
+    createProcessTicket,
+    getProcessWebSocketURL,
+    killProcess,
+    listProcessKills,
+    type ProcessInfo,
+    type ProcessKillRecord,
+    type ProcessSnapshot,
+} from '@/api/agent.ts';
+import {getErrorMessage} from '@/lib/utils';
+
+interface ProcessesProps {
+    agentId: string;
+}
+
+const PAGE_SIZE = 10;
+// 每类（CPU、内存）推送的进程数量
+const PROCESS_LIMIT = 100;
+// 进程列表刷新间隔（秒）
+const REFRESH_INTERVAL = 3;
+// 有执行中的记录时刷新结束进程记录的间隔
+const POLL_INTERVAL = 3000;
+
+const statusMap: Record<ProcessKillRecord['status'], { color: string; text: string }> = {
+    running: {color: 'processing', text: '执行中'},
+    success: {color: 'green', text: '成功'},
+    error: {color: 'red', text: '失败'},
+};
+
+const Processes: React.FC<ProcessesProps> = ({agentId}) => {
+    const {message} = App.useApp();
+    const [connecting, setConnecting] = useState(false);
+    const [connected, setConnected] = useState(false);
+    const [closedReason, setClosedReason] = useState('');
+    const [snapshot, setSnapshot] = useState<ProcessSnapshot | null>(null);
+    const [keyword, setKeyword] = useState('');
+    const [records, setRecords] = useState<ProcessKillRecord[]>([]);
+    const [total, setTotal] = useState(0);
+    const [pageIndex, setPageIndex] = useState(1);
+    const [loading, setLoading] = useState(false);
+
+    const socketRef = useRef<WebSocket | null>(null);
+
+    const loadRecords = async (page: number = pageIndex, silent: boolean = false) => {
+        if (!silent) {
+            setLoading(true);
+        }
+        try {
+            const res = await listProcessKills(agentId, page, PAGE_SIZE);
+            setRecords(res.data.items || []);
+            setTotal(res.data.total || 0);
+            setPageIndex(page);
+        } catch (error) {
+            if (!silent) {
+                message.error(getErrorMessage(error, '获取结束进程记录失败'));
+            }
+        } finally {
+            setLoading(false);
+        }
+    };
+
+    useEffect(() => {
+        loadRecords(1);
+        return () => socketRef.current?.close();
+    }, [agentId]);
+
+    const hasRunning = records.some((item) => item.status === 'running');
+    useEffect(() => {
+        if (!hasRunning) {
+            return;
+        }
+        const timer = window.setInterval(() => loadRecords(pageIndex, true), POLL_INTERVAL);
+        return () => window.clearInterval(timer);
+    }, [hasRunning, pageIndex]);
+
+    const handleOpen = async () => {
+        setConnecting(true);
+        try {
+            const res = await createProcessTicket(agentId);
+            const socket = new WebSocket(getProcessWebSocketURL(res.data.ticket, PROCESS_LIMIT, REFRESH_INTERVAL));
+            let reason = '';
+
+            socket.onopen = () => {
+                setConnecting(false);
+                setConnected(true);
+                setClosedReason('');
+            };
+            socket.onmessage = (event) => {
+                const msg = JSON.parse(event.data as string) as {
+                    type: string;
+                    snapshot?: ProcessSnapshot;
+                    reason?: string
+                };
+                if (msg.type === 'snapshot' && msg.snapshot) {
+                    setSnapshot(msg.snapshot);
+                } else if (msg.type === 'closed') {
+                    reason = msg.reason || '';
+                }
+            };
+            socket.onclose = () => {
+                socketRef.current = null;
+                setConnecting(false);
+                setConnected(false);
+                setClosedReason(reason || '连接已断开');
+            };
+            socketRef.current = socket;
+        } catch (error) {
+            setConnecting(false);
+            message.error(getErrorMessage(error, '获取进程列表失败'));
+        }
+    };
+
+    const handleKill = async (process: ProcessInfo, signal: 'TERM' | 'KILL') => {
+        try {
+            await killProcess(agentId, {
+                pid: process.pid,
+                createTime: process.createTime,
+                signal,
+                name: process.name,
+                cmdline: process.cmdline,
+            });
+            message.success('结束进程指令已下发');
+            loadRecords(1);
+        } catch (error) {
+            message.error(getErrorMessage(error, '结束进程失败'));
+        }
+    };
+
+    const processes = (snapshot?.processes || []).filter((item) => {
+        if (!keyword) {
+            return true;
+        }
+        const value = keyword.toLowerCase();
+        return item.name.toLowerCase().includes(value)
+            || (item.cmdline || '').toLowerCase().includes(value)
+            || String(item.pid) === keyword;
+    });
+
+    const processColumns: ColumnsType<ProcessInfo> = [
+        {
+            title: 'PID',
+            dataIndex: 'pid',
+            width: 90,
+        },
+        {
+            title: '进程名',
+            dataIndex: 'name',
+            width: 160,
+            ellipsis: true,
+        },
+        {
+            title: '用户',
+            dataIndex: 'username',
+            width: 110,
+            ellipsis: true,
+            render: (value?: string) => value || '-',
+        },
+        {
+            title: 'CPU',
+            dataIndex: 'cpuPercent',
+            width: 100,
+            defaultSortOrder: 'descend',
+            sorter: (a, b) => a.cpuPercent - b.cpuPercent,
+            render: (value: number) => `${value.toFixed(1)}%`,
+        },
+        {
+            title: '内存',
+            dataIndex: 'memPercent',
+            width: 140,
+            sorter: (a, b) => a.memPercent - b.memPercent,
+            render: (value: number, record) => `${value.toFixed(1)}% (${record.memoryMb} MB)`,
+        },
+        {
+            title: '命令行',
+            dataIndex: 'cmdline',
+            ellipsis: true,
+            render: (value?: string) => value || '-',
+        },
+        {
+            title: '操作',
+            key: 'action',
+            width: 150,
+            render: (_, record) => (
+                <Space size="small">
+                    <Popconfirm
+                        title={`确定向进程 ${record.pid} (${record.name}) 发送 TERM 信号吗？`}
+                        onConfirm={() => handleKill(record, 'TERM')}
+                    >
+                        <Button type="link" size="small" danger>结束</Button>
+                    </Popconfirm>
+                    <Popconfirm
+                        title={`确定强制结束进程 ${record.pid} (${record.name}) 吗？`}
+                        onConfirm={() => handleKill(record, 'KILL')}
+                    >
+                        <Button type="link" size="small" danger>强制结束</Button>
+                    </Popconfirm>
+                </Space>
+            ),
+        },
+    ];
+
+    const recordColumns: ColumnsType<ProcessKillRecord> = [
+        {
+            title: '操作时间',
+            dataIndex: 'createdAt',
+            width: 180,
+            render: (value: number) => dayjs(value).format('YYYY-MM-DD HH:mm:ss'),
+        },
+        {
+            title: 'PID',
+            dataIndex: 'pid',
+            width: 90,
+        },
+        {
+            title: '进程名',
+            dataIndex: 'name',
+            width: 140,
+            ellipsis: true,
+        },
+        {
+            title: '信号',
+            dataIndex: 'signal',
+            width: 80,
+        },
+        {
+            title: '状态',
+            dataIndex: 'status',
+            width: 90,
+            render: (status: ProcessKillRecord['status']) => {
+                const item = statusMap[status];
+                return item ? <Tag color={item.color}>{item.text}</Tag> : status;
+            },
+        },
+        {
+            title: '用户',
+            dataIndex: 'username',
+            width: 120,
+        },
+        {
+            title: 'IP',
+            dataIndex: 'clientIp',
+            width: 140,
+        },
+        {
+            title: '错误信息',
+            dataIndex: 'error',
+            ellipsis: true,
+            render: (value: string) => value || '-',
+        },
+    ];
+
+    return (
+        <Space direction="vertical" size="large" className="w-full">
+            <Card
+                title="进程列表"
+                extra={
+                    <Space>
+                        <Input.Search
+                            allowClear
+                            placeholder="进程名、命令行或 PID"
+                            style={{width: 220}}
+                            onSearch={setKeyword}
+                        />
+                        {connected ? (
+                            <Button onClick={() => socketRef.current?.close()}>停止</Button>
+                        ) : (
+                            <Button type="primary" loading={connecting} onClick={handleOpen}>
+                                开始查看
+                            </Button>
+                        )}
+                    </Space>
+                }
+            >
+                <Alert
+                    className="mb-4"
+                    type="info"
+                    showIcon
+                    message={`每 ${REFRESH_INTERVAL} 秒刷新一次，只显示 CPU 和内存占用各自最高的 ${PROCESS_LIMIT} 个进程。结束进程需要服务端开启 processes.allow_kill 且探针配置文件中开启 processes.allow_kill，每次操作都会记录在下方的结束进程记录中。`}
+                />
+                {closedReason && !connected && (
+                    <Alert className="mb-4" type="warning" showIcon message={`进程列表已停止：${closedReason}`}/>
+                )}
+                <Table
+                    columns={processColumns}
+                    dataSource={processes}
+                    rowKey="pid"
+                    size="small"
+                    pagination={{pageSize: 20, showSizeChanger: false}}
+                    footer={snapshot ? () => `共 ${snapshot.total} 个进程，更新于 ${dayjs(snapshot.timestamp).format('HH:mm:ss')}` : undefined}
+                    scroll={{x: 900}}
+                />
+            </Card>
+
+            <Card
+                title="结束进程记录"
+                extra={
+                    <Button icon={<RefreshCw size={14}/>} onClick={() => loadRecords()}>
+                        刷新
+                    </Button>
+                }
+            >
+                <Table
+                    columns={recordColumns}
+                    dataSource={records}
+                    rowKey="id"
+                    loading={loading}
+                    expandable={{
+                        rowExpandable: (record) => !!record.cmdline,
+                        expandedRowRender: (record) => (
+                            <pre className="m-0 whitespace-pre-wrap break-all text-xs">{record.cmdline}</pre>
+                        ),
+                    }}
+                    pagination={{
+                        current: pageIndex,
+                        pageSize: PAGE_SIZE,
+                        total,
+                        showSizeChanger: false,
+                        onChange: (page) => loadRecords(page),
+                    }}
+                    scroll={{x: 900}}
+                />
+            </Card>
+        </Space>
+    );
+};
+
+export default Processes;
@@ -0,0 +1,247 @@
+import React, {useEffect, useState} from 'react';
+import {Alert, App, Button, Card, Form, Input, InputNumber, Popconfirm, Select, Space, Table, Tag} from 'antd';
+import type {ColumnsType} from 'antd/es/table';
+import {RefreshCw} from 'lucide-react';
+import dayjs from 'dayjs';
+import {
+    type CommandExecution,
+    executeRemoteCommand,
+    getRemoteCommandOptions,
+    listCommandExecutions,
+    type RemoteCommandOption,
+} from '@/api/agent.ts';
+import {getErrorMessage} from '@/lib/utils';
+
+interface RemoteCommandsProps {
+    agentId: string;
+}
+
+interface CommandFormValues {
+    name: string;
+    target: string;
+    timeout?: number;
+}
+
+const PAGE_SIZE = 10;
+// 有执行中的记录时刷新执行记录的间隔
+const POLL_INTERVAL = 3000;
+
+const statusMap: Record<CommandExecution['status'], { color: string; text: string }> = {
+    running: {color: 'processing', text: '执行中'},
+    success: {color: 'green', text: '成功'},
+    error: {color: 'red', text: '失败'},
+    timeout: {color: 'orange', text: '超时'},
+};
+
+const RemoteCommands: React.FC<RemoteCommandsProps> = ({agentId}) => {
+    const {message} = App.useApp();
+    const [form] = Form.useForm<CommandFormValues>();
+    const [options, setOptions] = useState<RemoteCommandOption[]>([]);
+    const [records, setRecords] = useState<CommandExecution[]>([]);
+    const [total, setTotal] = useState(0);
+    const [pageIndex, setPageIndex] = useState(1);
+    const [loading, setLoading] = useState(false);
+    const [submitting, setSubmitting] = useState(false);
+    const [loadError, setLoadError] = useState('');
+
+    const selectedName = Form.useWatch('name', form);
+    const selected = options.find((item) => item.name === selectedName);
+    const labelOf = (name: string) => options.find((item) => item.name === name)?.label || name;
+
+    const loadRecords = async (page: number = pageIndex, silent: boolean = false) => {
+        if (!silent) {
+            setLoading(true);
+        }
+        try {
+            const res = await listCommandExecutions(agentId, page, PAGE_SIZE);
+            setRecords(res.data.items || []);
+            setTotal(res.data.total || 0);
+            setPageIndex(page);
+        } catch (error) {
+            if (!silent) {
+                message.error(getErrorMessage(error, '获取执行记录失败'));
+            }
+        } finally {
+            setLoading(false);
+        }
+    };
+
+    useEffect(() => {
+        const init = async () => {
+            try {
+                const res = await getRemoteCommandOptions();
+                setOptions(res.data || []);
+                setLoadError('');
+                loadRecords(1);
+            } catch (error) {
+                // 服务端未启用远程命令或当前用户没有权限
+                setLoadError(getErrorMessage(error, '获取远程命令失败'));
+            }
+        };
+        init();
+    }, [agentId]);
+
+    const hasRunning = records.some((item) => item.status === 'running');
+    useEffect(() => {
+        if (!hasRunning) {
+            return;
+        }
+        const timer = window.setInterval(() => loadRecords(pageIndex, true), POLL_INTERVAL);
+        return () => window.clearInterval(timer);
+    }, [hasRunning, pageIndex]);
+
+    const handleExecute = async () => {
+        const values = await form.validateFields();
+        setSubmitting(true);
+        try {
+            await executeRemoteCommand(agentId, values);
+            message.success('命令已下发');
+            loadRecords(1);
+        } catch (error) {
+            message.error(getErrorMessage(error, '下发命令失败'));
+        } finally {
+            setSubmitting(false);
+        }
+    };
+
+    const columns: ColumnsType<CommandExecution> = [
+        {
+            title: '下发时间',
+            dataIndex: 'createdAt',
+            width: 180,
+            render: (value: number) => dayjs(value).format('YYYY-MM-DD HH:mm:ss'),
+        },
+        {
+            title: '命令',
+            dataIndex: 'name',
+            width: 120,
+            render: (name: string) => labelOf(name),
+        },
+        {
+            title: '目标',
+            dataIndex: 'target',
+            ellipsis: true,
+        },
+        {
+            title: '状态',
+            dataIndex: 'status',
+            width: 90,
+            render: (status: CommandExecution['status']) => {
+                const item = statusMap[status];
+                return item ? <Tag color={item.color}>{item.text}</Tag> : status;
+            },
+        },
+        {
+            title: '耗时',
+            dataIndex: 'duration',
+            width: 100,
+            render: (value: number, record) => record.status === 'running' || !value ? '-' : `${(value / 1000).toFixed(1)} 秒`,
+        },
+        {
+            title: '用户',
+            dataIndex: 'username',
+            width: 120,
+        },
+        {
+            title: 'IP',
+            dataIndex: 'clientIp',
+            width: 140,
+        },
+        {
+            title: '错误信息',
+            dataIndex: 'error',
+            ellipsis: true,
+            render: (value: string) => value || '-',
+        },
+    ];
+
+    if (loadError) {
+        return <Alert type="warning" showIcon message="无法使用远程命令" description={loadError}/>;
+    }
+
+    return (
+        <Space direction="vertical" size="large" className="w-full">
+            <Card title="下发命令">
+                <Alert
+                    className="mb-4"
+                    type="info"
+                    showIcon
+                    message="只能下发服务端配置的白名单命令，探针也需要在配置文件中开启 commands.enabled 并配置对应的服务、缓存目录或脚本。每次执行都会记录在下方的执行记录中。"
+                />
+                {options.length === 0 ? (
+                    <Alert type="warning" showIcon message="服务端未配置允许下发的命令"/>
+                ) : (
+                    <Form form={form} layout="inline" className="gap-y-3">
+                        <Form.Item name="name" rules={[{required: true, message: '请选择命令'}]}>
+                            <Select
+                                placeholder="选择命令"
+                                style={{width: 160}}
+                                options={options.map((item) => ({label: item.label, value: item.name}))}
+                                onChange={() => form.setFieldsValue({target: undefined, timeout: undefined})}
+                            />
+                        </Form.Item>
+                        <Form.Item name="target" rules={[{required: true, message: '请输入目标'}]}>
+                            {selected?.targets?.length ? (
+                                <Select
+                                    placeholder="选择目标"
+                                    style={{width: 240}}
+                                    options={selected.targets.map((target) => ({label: target, value: target}))}
+                                />
+                            ) : (
+                                <Input placeholder="服务名、目录或脚本名称" style={{width: 240}}/>
+                            )}
+                        </Form.Item>
+                        <Form.Item name="timeout">
+                            <InputNumber
+                                min={1}
+                                max={selected?.timeout}
+                                placeholder={selected ? `超时 ${selected.timeout} 秒` : '超时（秒）'}
+                                style={{width: 140}}
+                            />
+                        </Form.Item>
+                        <Form.Item>
+                            <Popconfirm title="确定在该探针上执行此命令吗？" onConfirm={handleExecute}>
+                                <Button type="primary" danger loading={submitting}>执行</Button>
+                            </Popconfirm>
+                        </Form.Item>
+                    </Form>
+                )}
+            </Card>
+
+            <Card
+                title="执行记录"
+                extra={
+                    <Button icon={<RefreshCw size={14}/>} onClick={() => loadRecords()}>
+                        刷新
+                    </Button>
+                }
+            >
+                <Table
+                    columns={columns}
+                    dataSource={records}
+                    rowKey="id"
+                    loading={loading}
+                    expandable={{
+                        rowExpandable: (record) => !!record.output,
+                        expandedRowRender: (record) => (
+                            <pre className="m-0 max-h-80 overflow-auto whitespace-pre-wrap break-all text-xs">
+                                {record.output}
+                                {record.name === 'run_script' && record.status !== 'running' && `\n退出码: ${record.exitCode}`}
+                            </pre>
+                        ),
+                    }}
+                    pagination={{
+                        current: pageIndex,
+                        pageSize: PAGE_SIZE,
+                        total,
+                        showSizeChanger: false,
+                        onChange: (page) => loadRecords(page),
+                    }}
+                    scroll={{x: 900}}
+                />
+            </Card>
+        </Space>
+    );
+};
+
+export default RemoteCommands;
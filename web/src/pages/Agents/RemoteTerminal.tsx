@@ -0,0 +1,311 @@
+import React, {useEffect, useRef, useState} from 'react';
+import {Alert, App, Button, Card, Space, Table, Tag} from 'antd';
+import type {ColumnsType} from 'antd/es/table';
+import {Download, RefreshCw} from 'lucide-react';
+import dayjs from 'dayjs';
+import {
+    createTerminalTicket,
+    downloadTerminalRecording,
+    getTerminalWebSocketURL,
+    listTerminalSessions,
+    type TerminalSession,
+} from '@/api/agent.ts';
+import {getErrorMessage} from '@/lib/utils';
+import {keyToInput, TerminalScreen} from './terminalScreen.ts';
+
+interface RemoteTerminalProps {
+    agentId: string;
+}
+
+const PAGE_SIZE = 10;
+// 终端字符的近似尺寸（13px 等宽字体），用于根据容器大小计算行列数
+const CHAR_WIDTH = 7.8;
+const LINE_HEIGHT = 18;
+
+const formatBytes = (bytes: number): string => {
+    if (!bytes || bytes <= 0) return '0 B';
+    const k = 1024;
+    const sizes = ['B', 'KB', 'MB', 'GB'];
+    const i = Math.min(Math.floor(Math.log(bytes) / Math.log(k)), sizes.length - 1);
+    return `${(bytes / Math.pow(k, i)).toFixed(2)} ${sizes[i]}`;
+};
+
+const RemoteTerminal: React.FC<RemoteTerminalProps> = ({agentId}) => {
+    const {message} = App.useApp();
+    const [connecting, setConnecting] = useState(false);
+    const [connected, setConnected] = useState(false);
+    const [closedReason, setClosedReason] = useState('');
+    const [output, setOutput] = useState('');
+    const [sessions, setSessions] = useState<TerminalSession[]>([]);
+    const [total, setTotal] = useState(0);
+    const [pageIndex, setPageIndex] = useState(1);
+    const [loading, setLoading] = useState(false);
+    const [loadError, setLoadError] = useState('');
+
+    const socketRef = useRef<WebSocket | null>(null);
+    const screenRef = useRef<HTMLPreElement | null>(null);
+
+    const loadSessions = async (page: number = pageIndex) => {
+        setLoading(true);
+        try {
+            const res = await listTerminalSessions(agentId, page, PAGE_SIZE);
+            setSessions(res.data.items || []);
+            setTotal(res.data.total || 0);
+            setPageIndex(page);
+            setLoadError('');
+        } catch (error) {
+            // 服务端未启用远程终端或当前用户没有权限
+            setLoadError(getErrorMessage(error, '获取终端会话记录失败'));
+        } finally {
+            setLoading(false);
+        }
+    };
+
+    useEffect(() => {
+        loadSessions(1);
+        return () => socketRef.current?.close();
+    }, [agentId]);
+
+    useEffect(() => {
+        const screen = screenRef.current;
+        if (screen) {
+            screen.scrollTop = screen.scrollHeight;
+        }
+    }, [output]);
+
+    const terminalSize = () => {
+        const screen = screenRef.current;
+        if (!screen) {
+            return {cols: 120, rows: 30};
+        }
+        return {
+            cols: Math.max(Math.floor((screen.clientWidth - 24) / CHAR_WIDTH), 20),
+            rows: Math.max(Math.floor((screen.clientHeight - 24) / LINE_HEIGHT), 5),
+        };
+    };
+
+    useEffect(() => {
+        if (!connected) {
+            return;
+        }
+        const handleResize = () => {
+            const socket = socketRef.current;
+            if (socket?.readyState === WebSocket.OPEN) {
+                socket.send(JSON.stringify({type: 'resize', ...terminalSize()}));
+            }
+        };
+        window.addEventListener('resize', handleResize);
+        return () => window.removeEventListener('resize', handleResize);
+    }, [connected]);
+
+    const send = (data: string) => {
+        const socket = socketRef.current;
+        if (data && socket?.readyState === WebSocket.OPEN) {
+            socket.send(JSON.stringify({type: 'input', data}));
+        }
+    };
+
+    const handleOpen = async () => {
+        setConnecting(true);
+        try {
+            const res = await createTerminalTicket(agentId);
+            const {cols, rows} = terminalSize();
+            // 浏览器端只能显示纯文本，使用 dumb 终端让 shell 尽量不输出颜色和光标控制序列
+            const socket = new WebSocket(getTerminalWebSocketURL(res.data.ticket, cols, rows, 'dumb'));
+            socket.binaryType = 'arraybuffer';
+            const screen = new TerminalScreen();
+            const decoder = new TextDecoder();
+            let reason = '';
+
+            socket.onopen = () => {
+                setConnecting(false);
+                setConnected(true);
+                setClosedReason('');
+                setOutput('');
+                screenRef.current?.focus();
+            };
+            socket.onmessage = (event) => {
+                if (typeof event.data === 'string') {
+                    const msg = JSON.parse(event.data) as { type: string; reason: string };
+                    if (msg.type === 'closed') {
+                        reason = msg.reason;
+                    }
+                    return;
+                }
+                screen.write(decoder.decode(event.data as ArrayBuffer, {stream: true}));
+                setOutput(screen.text());
+            };
+            socket.onclose = () => {
+                socketRef.current = null;
+                setConnecting(false);
+                setConnected(false);
+                setClosedReason(reason || '连接已断开');
+                loadSessions(1);
+            };
+            socketRef.current = socket;
+        } catch (error) {
+            setConnecting(false);
+            message.error(getErrorMessage(error, '打开终端失败'));
+        }
+    };
+
+    const handleKeyDown = (event: React.KeyboardEvent<HTMLPreElement>) => {
+        // 保留浏览器的复制快捷键
+        if ((event.ctrlKey || event.metaKey) && event.key.toLowerCase() === 'c' && window.getSelection()?.toString()) {
+            return;
+        }
+        if (event.metaKey) {
+            return;
+        }
+        const data = keyToInput(event);
+        if (data) {
+            event.preventDefault();
+            send(data);
+        }
+    };
+
+    const handlePaste = (event: React.ClipboardEvent<HTMLPreElement>) => {
+        event.preventDefault();
+        send(event.clipboardData.getData('text').replace(/\r?\n/g, '\r'));
+    };
+
+    const handleDownload = async (session: TerminalSession) => {
+        try {
+            const res = await downloadTerminalRecording(session.id);
+            const blob = new Blob([res.data], {type: 'application/x-asciicast'});
+            const url = URL.createObjectURL(blob);
+            const link = document.createElement('a');
+            link.href = url;
+            link.download = `terminal-${session.id}.cast`;
+            link.click();
+            URL.revokeObjectURL(url);
+        } catch (error) {
+            message.error(getErrorMessage(error, '下载录像失败'));
+        }
+    };
+
+    const columns: ColumnsType<TerminalSession> = [
+        {
+            title: '开始时间',
+            dataIndex: 'startedAt',
+            key: 'startedAt',
+            width: 180,
+            render: (value: number) => dayjs(value).format('YYYY-MM-DD HH:mm:ss'),
+        },
+        {
+            title: '时长',
+            key: 'duration',
+            width: 100,
+            render: (_, record) => record.endedAt
+                ? `${Math.max(Math.round((record.endedAt - record.startedAt) / 1000), 0)} 秒`
+                : '-',
+        },
+        {
+            title: '用户',
+            dataIndex: 'username',
+            key: 'username',
+            width: 120,
+        },
+        {
+            title: 'IP',
+            dataIndex: 'clientIp',
+            key: 'clientIp',
+            width: 140,
+        },
+        {
+            title: '状态',
+            dataIndex: 'status',
+            key: 'status',
+            width: 90,
+            render: (status: TerminalSession['status']) => status === 'running'
+                ? <Tag color="processing">进行中</Tag>
+                : <Tag>已结束</Tag>,
+        },
+        {
+            title: '结束原因',
+            dataIndex: 'closeReason',
+            key: 'closeReason',
+            ellipsis: true,
+        },
+        {
+            title: '录像',
+            key: 'recording',
+            width: 200,
+            render: (_, record) => (
+                <Space>
+                    <span>{formatBytes(record.recordSize)}</span>
+                    {record.recordTruncated && <Tag color="warning">不完整</Tag>}
+                    {record.status === 'closed' && (
+                        <Button type="link" size="small" icon={<Download size={14}/>} onClick={() => handleDownload(record)}>
+                            下载
+                        </Button>
+                    )}
+                </Space>
+            ),
+        },
+    ];
+
+    if (loadError) {
+        return <Alert type="warning" showIcon message="无法使用远程终端" description={loadError}/>;
+    }
+
+    return (
+        <Space direction="vertical" size="large" className="w-full">
+            <Card
+                title="远程终端"
+                extra={
+                    connected ? (
+                        <Button danger onClick={() => socketRef.current?.close()}>断开</Button>
+                    ) : (
+                        <Button type="primary" loading={connecting} onClick={handleOpen}>打开终端</Button>
+                    )
+                }
+            >
+                <Alert
+                    className="mb-4"
+                    type="info"
+                    showIcon
+                    message="终端的输入和输出会被完整录像，可在下方会话记录中下载（asciicast 格式，使用 asciinema play 回放）。浏览器端仅显示纯文本，不支持 vim、top 等全屏程序。"
+                />
+                {closedReason && (
+                    <Alert className="mb-4" type="warning" showIcon message={`终端已关闭：${closedReason}`}/>
+                )}
+                <pre
+                    ref={screenRef}
+                    tabIndex={0}
+                    onKeyDown={handleKeyDown}
+                    onPaste={handlePaste}
+                    className="m-0 h-[480px] overflow-auto whitespace-pre-wrap break-all rounded bg-black p-3 font-mono text-[13px] leading-[18px] text-gray-100 outline-none"
+                >
+                    {output || (connected ? '' : '点击「打开终端」连接到探针')}
+                </pre>
+            </Card>
+
+            <Card
+                title="终端会话记录"
+                extra={
+                    <Button icon={<RefreshCw size={14}/>} onClick={() => loadSessions()}>
+                        刷新
+                    </Button>
+                }
+            >
+                <Table
+                    columns={columns}
+                    dataSource={sessions}
+                    rowKey="id"
+                    loading={loading}
+                    pagination={{
+                        current: pageIndex,
+                        pageSize: PAGE_SIZE,
+                        total,
+                        showSizeChanger: false,
+                        onChange: (page) => loadSessions(page),
+                    }}
+                    scroll={{x: 900}}
+                />
+            </Card>
+        </Space>
+    );
+};
+
+export default RemoteTerminal;
@@ -0,0 +1,105 @@
+// 简易终端屏幕：以 dumb 终端方式显示 shell 输出，忽略颜色和光标定位等控制序列
+// 只处理换行、回车和退格，适合执行命令排查问题，不支持 vim、top 等全屏程序
+
+const MAX_LINES = 2000;
+
+// CSI 序列（如颜色 \x1b[31m）、OSC 序列（如窗口标题 \x1b]0;title\x07）、字符集切换（如 \x1b(B）和其他两字节转义序列
+const ESCAPE_PATTERN = /\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)|\x1b[()*+].|\x1b[@-Z\\^_]/g;
+
+interface KeyInput {
+    key: string;
+    ctrlKey: boolean;
+    altKey: boolean;
+    metaKey: boolean;
+}
+
+export class TerminalScreen {
+    private lines: string[] = [''];
+    private col = 0;
+    // 上一段输出末尾不完整的转义序列，与下一段输出拼接后再处理
+    private pending = '';
+
+    write(text: string) {
+        text = this.pending + text;
+        this.pending = '';
+        const lastEscape = text.lastIndexOf('\x1b');
+        if (lastEscape >= 0 && lastEscape > text.length - 32) {
+            const rest = text.slice(lastEscape);
+            if (rest.replace(ESCAPE_PATTERN, '') === rest) {
+                this.pending = rest;
+                text = text.slice(0, lastEscape);
+            }
+        }
+
+        for (const ch of text.replace(ESCAPE_PATTERN, '')) {
+            switch (ch) {
+                case '\n':
+                    this.lines.push('');
+                    this.col = 0;
+                    break;
+                case '\r':
+                    this.col = 0;
+                    break;
+                case '\b':
+                    this.col = Math.max(this.col - 1, 0);
+                    break;
+                case '\x07':
+                    break;
+                default: {
+                    if (ch < ' ' && ch !== '\t') {
+                        break;
+                    }
+                    const index = this.lines.length - 1;
+                    const line = this.lines[index].padEnd(this.col, ' ');
+                    this.lines[index] = line.slice(0, this.col) + ch + line.slice(this.col + 1);
+                    this.col++;
+                }
+            }
+        }
+        if (this.lines.length > MAX_LINES) {
+            this.lines = this.lines.slice(this.lines.length - MAX_LINES);
+        }
+    }
+
+    text() {
+        return this.lines.join('\n');
+    }
+}
+
+// 将键盘事件转换为发送给 shell 的字节，无法识别的按键返回空
+export const keyToInput = (event: KeyInput): string => {
+    if (event.ctrlKey && !event.altKey && event.key.length === 1) {
+        const code = event.key.toUpperCase().charCodeAt(0);
+        if (code >= 64 && code <= 95) {
+            return String.fromCharCode(code - 64);
+        }
+    }
+    switch (event.key) {
+        case 'Enter':
+            return '\r';
+        case 'Backspace':
+            return '\x7f';
+        case 'Tab':
+            return '\t';
+        case 'Escape':
+            return '\x1b';
+        case 'ArrowUp':
+            return '\x1b[A';
+        case 'ArrowDown':
+            return '\x1b[B';
+        case 'ArrowRight':
+            return '\x1b[C';
+        case 'ArrowLeft':
+            return '\x1b[D';
+        case 'Home':
+            return '\x1b[H';
+        case 'End':
+            return '\x1b[F';
+        case 'Delete':
+            return '\x1b[3~';
+    }
+    if (event.key.length === 1 && !event.ctrlKey && !event.metaKey) {
+        return event.altKey ? '\x1b' + event.key : event.key;
+    }
+    return '';
+};
@@ -34,6 +34,16 @@ const AlertRecordList = () => {
         cert: 'HTTPS证书',
         service: '服务下线',
         agent_offline: '探针离线',
+        wireguard: 'WireGuard握手',
+        mount: '挂载点异常',
+        inode: 'inode使用率',
+        fd: '文件描述符',
+        steal: 'CPU steal',
+        clock: '时钟偏差',
+        port: '非预期端口',
+        kernel: '内核事件',
+        monitor_group: '监控分组',
+        cert_chain: '证书链',
     };
 
     // 告警级别映射
@@ -110,6 +120,9 @@ const AlertRecordList = () => {
             dataIndex: 'message',
             ellipsis: true,
             search: false,
+            render: (_, record) => record.refiredAfter
+                ? `${record.message}（恢复 ${record.refiredAfter} 秒后再次触发）`
+                : record.message,
         },
         {
             title: '阈值',
@@ -122,7 +135,13 @@ const AlertRecordList = () => {
                 if (record.alertType === 'cert') {
                     return `${record.threshold.toFixed(0)} 天`;
                 }
-                if (record.alertType === 'service' || record.alertType === 'agent_offline') {
+                if (record.alertType === 'clock') {
+                    return `${record.threshold.toFixed(0)} ms`;
+                }
+                if (record.alertType === 'monitor_group') {
+                    return `${record.threshold.toFixed(0)} 个`;
+                }
+                if (record.alertType === 'service' || record.alertType === 'agent_offline' || record.alertType === 'wireguard' || record.alertType === 'mount') {
                     return `${record.threshold.toFixed(0)} 秒`;
                 }
                 return `${record.threshold.toFixed(2)}%`;
@@ -140,7 +159,13 @@ const AlertRecordList = () => {
                 if (record.alertType === 'cert') {
                     return `${record.actualValue.toFixed(0)} 天`;
                 }
-                if (record.alertType === 'service' || record.alertType === 'agent_offline') {
+                if (record.alertType === 'clock') {
+                    return `${record.actualValue.toFixed(0)} ms`;
+                }
+                if (record.alertType === 'monitor_group') {
+                    return `${record.actualValue.toFixed(0)} 个`;
+                }
+                if (record.alertType === 'service' || record.alertType === 'agent_offline' || record.alertType === 'wireguard' || record.alertType === 'mount') {
                     return `${record.actualValue.toFixed(0)} 秒`;
                 }
                 return `${record.actualValue.toFixed(2)}%`;
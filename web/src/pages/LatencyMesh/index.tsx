@@ -0,0 +1,213 @@
+import {useEffect, useMemo, useState} from 'react';
+import {App, Button, Card, Empty, Form, InputNumber, Select, Space, Spin, Switch, Tooltip} from 'antd';
+import {RefreshCw} from 'lucide-react';
+import dayjs from 'dayjs';
+import {PageHeader} from '@/components';
+import {
+    getAgentPaging,
+    getLatencyMeshConfig,
+    getLatencyMeshMatrix,
+    type LatencyMeshCell,
+    type LatencyMeshConfig,
+    type LatencyMeshMatrix,
+    saveLatencyMeshConfig,
+} from '@/api/agent.ts';
+import type {Agent} from '@/types';
+import {getErrorMessage} from '@/lib/utils';
+
+// 根据延迟和丢包给单元格着色
+const cellClassName = (cell?: LatencyMeshCell) => {
+    if (!cell) {
+        return 'bg-gray-50 text-gray-400 dark:bg-gray-800';
+    }
+    if (cell.packetLoss >= 100) {
+        return 'bg-red-100 text-red-700 dark:bg-red-900/40 dark:text-red-300';
+    }
+    if (cell.packetLoss > 0 || cell.avgRtt >= 200) {
+        return 'bg-orange-100 text-orange-700 dark:bg-orange-900/40 dark:text-orange-300';
+    }
+    if (cell.avgRtt >= 80) {
+        return 'bg-yellow-100 text-yellow-700 dark:bg-yellow-900/40 dark:text-yellow-300';
+    }
+    return 'bg-green-100 text-green-700 dark:bg-green-900/40 dark:text-green-300';
+};
+
+const LatencyMeshPage = () => {
+    const {message} = App.useApp();
+    const [form] = Form.useForm<LatencyMeshConfig>();
+    const mode = Form.useWatch('mode', form);
+    const [agents, setAgents] = useState<Agent[]>([]);
+    const [matrix, setMatrix] = useState<LatencyMeshMatrix>();
+    const [loading, setLoading] = useState(false);
+    const [saving, setSaving] = useState(false);
+
+    const loadMatrix = async () => {
+        setLoading(true);
+        try {
+            const res = await getLatencyMeshMatrix();
+            setMatrix(res.data);
+        } catch (error: unknown) {
+            message.error(getErrorMessage(error, '获取延迟矩阵失败'));
+        } finally {
+            setLoading(false);
+        }
+    };
+
+    useEffect(() => {
+        const init = async () => {
+            try {
+                const [configRes, agentsRes] = await Promise.all([
+                    getLatencyMeshConfig(),
+                    getAgentPaging(1, 1000),
+                ]);
+                form.setFieldsValue(configRes.data);
+                setAgents(agentsRes.data.items || []);
+            } catch (error: unknown) {
+                message.error(getErrorMessage(error, '获取延迟网格配置失败'));
+            }
+        };
+        init();
+        loadMatrix();
+    }, []);
+
+    const handleSave = async () => {
+        const values = await form.validateFields();
+        setSaving(true);
+        try {
+            await saveLatencyMeshConfig(values);
+            message.success('保存成功，探针将在下一轮探测后上报结果');
+            loadMatrix();
+        } catch (error: unknown) {
+            message.error(getErrorMessage(error, '保存失败'));
+        } finally {
+            setSaving(false);
+        }
+    };
+
+    const cells = useMemo(() => {
+        const result: Record<string, LatencyMeshCell> = {};
+        for (const cell of matrix?.cells || []) {
+            result[`${cell.from}:${cell.to}`] = cell;
+        }
+        return result;
+    }, [matrix]);
+
+    const renderCell = (fromId: string, toId: string) => {
+        if (fromId === toId) {
+            return <td key={toId} className="border border-gray-200 bg-gray-100 dark:border-gray-700 dark:bg-gray-900"/>;
+        }
+        const cell = cells[`${fromId}:${toId}`];
+        const title = cell ? (
+            <div className="text-xs">
+                <div>最小/平均/最大：{cell.minRtt.toFixed(1)} / {cell.avgRtt.toFixed(1)} / {cell.maxRtt.toFixed(1)} ms</div>
+                <div>丢包率：{cell.packetLoss.toFixed(0)}%</div>
+                {cell.error && <div>错误：{cell.error}</div>}
+                <div>更新时间：{dayjs(cell.updatedAt).format('YYYY-MM-DD HH:mm:ss')}</div>
+            </div>
+        ) : '暂无数据';
+        return (
+            <td key={toId} className="border border-gray-200 p-0 dark:border-gray-700">
+                <Tooltip title={title}>
+                    <div className={`px-3 py-2 text-center text-xs font-medium ${cellClassName(cell)}`}>
+                        {!cell ? '-' : cell.packetLoss >= 100 ? '不可达' : `${cell.avgRtt.toFixed(1)} ms`}
+                        {cell && cell.packetLoss > 0 && cell.packetLoss < 100 && (
+                            <div className="text-[10px]">丢包 {cell.packetLoss.toFixed(0)}%</div>
+                        )}
+                    </div>
+                </Tooltip>
+            </td>
+        );
+    };
+
+    return (
+        <div className="space-y-6">
+            <PageHeader
+                title="延迟网格"
+                description="成员探针之间互相探测延迟和丢包，用于观察多地域节点之间的网络质量"
+                actions={[
+                    {
+                        key: 'refresh',
+                        label: '刷新',
+                        icon: <RefreshCw size={16}/>,
+                        onClick: loadMatrix,
+                    },
+                ]}
+            />
+
+            <Card title="网格配置">
+                <Form form={form} layout="vertical" initialValues={{enabled: false, agentIds: [], mode: 'icmp', port: 22}}>
+                    <Form.Item label="启用" name="enabled" valuePropName="checked">
+                        <Switch/>
+                    </Form.Item>
+                    <Form.Item
+                        label="成员探针"
+                        name="agentIds"
+                        extra="每个成员会探测其余成员上报的 IP，成员数为 N 时每轮共 N×(N-1) 条探测"
+                    >
+                        <Select
+                            mode="multiple"
+                            placeholder="选择参与网格的探针"
+                            optionFilterProp="label"
+                            options={agents.map(agent => ({label: `${agent.name} (${agent.ip})`, value: agent.id}))}
+                        />
+                    </Form.Item>
+                    <Space size="large" align="start">
+                        <Form.Item label="探测方式" name="mode">
+                            <Select
+                                style={{width: 160}}
+                                options={[
+                                    {label: 'ICMP', value: 'icmp'},
+                                    {label: 'TCP 建连', value: 'tcp'},
+                                ]}
+                            />
+                        </Form.Item>
+                        {mode === 'tcp' && (
+                            <Form.Item label="TCP 端口" name="port" rules={[{required: true, message: '请输入端口'}]}>
+                                <InputNumber min={1} max={65535} style={{width: 160}}/>
+                            </Form.Item>
+                        )}
+                    </Space>
+                    <Button type="primary" loading={saving} onClick={handleSave}>
+                        保存
+                    </Button>
+                </Form>
+            </Card>
+
+            <Card title="延迟矩阵（行：源探针，列：目标探针）">
+                <Spin spinning={loading}>
+                    {!matrix || matrix.agents.length < 2 ? (
+                        <Empty description="启用网格并选择至少 2 个探针后显示"/>
+                    ) : (
+                        <div className="overflow-x-auto">
+                            <table className="border-collapse text-sm">
+                                <thead>
+                                <tr>
+                                    <th className="border border-gray-200 px-3 py-2 dark:border-gray-700"/>
+                                    {matrix.agents.map(agent => (
+                                        <th key={agent.id}
+                                            className="whitespace-nowrap border border-gray-200 px-3 py-2 font-medium dark:border-gray-700">
+                                            {agent.name}
+                                        </th>
+                                    ))}
+                                </tr>
+                                </thead>
+                                <tbody>
+                                {matrix.agents.map(from => (
+                                    <tr key={from.id}>
+                                        <th className="whitespace-nowrap border border-gray-200 px-3 py-2 text-left font-medium dark:border-gray-700">
+                                            <span className={from.online ? '' : 'text-gray-400'}>{from.name}</span>
+                                        </th>
+                                        {matrix.agents.map(to => renderCell(from.id, to.id))}
+                                    </tr>
+                                ))}
+                                </tbody>
+                            </table>
+                        </div>
+                    )}
+                </Spin>
+            </Card>
+        </div>
+    );
+};
+
+export default LatencyMeshPage;
@@ -1,17 +1,43 @@
 import {useCallback, useEffect, useMemo, useRef, useState} from 'react';
 import type {ActionType, ProColumns} from '@ant-design/pro-components';
 import {ProTable} from '@ant-design/pro-components';
-import {App, Button, Divider, Form, Input, InputNumber, Modal, Select, Space, Switch, Tag,} from 'antd';
+import {App, AutoComplete, Button, Divider, Form, Input, InputNumber, Modal, Select, Space, Switch, Tag, Typography,} from 'antd';
 import {PageHeader} from '@/components';
-import {Edit, MinusCircle, Plus, PlusCircle, RefreshCw, Trash2} from 'lucide-react';
+import {Edit, Gauge, History, MinusCircle, Plus, PlusCircle, RefreshCw, Trash2} from 'lucide-react';
 import dayjs from 'dayjs';
 import {getAgentPaging, getTags} from '@/api/agent.ts';
-import type {Agent, MonitorTask, MonitorTaskRequest} from '@/types';
-import {createMonitor, deleteMonitor, listMonitors, updateMonitor} from '@/api/monitor.ts';
+import type {Agent, MonitorHttpAssertion, MonitorTask, MonitorTaskRequest} from '@/types';
+import {createMonitor, deleteMonitor, getMonitorGroups, listMonitors, updateMonitor} from '@/api/monitor.ts';
 import {getErrorMessage} from '@/lib/utils';
+import TransactionSteps, {fromStepFormValues, toStepFormValues} from './components/TransactionSteps';
+import RecordsDrawer from './components/RecordsDrawer';
+import SLAReportModal from './components/SLAReportModal';
+import MaintenanceWindows, {fromMaintenanceFormValues, toMaintenanceFormValues} from './components/MaintenanceWindows';
 
 const HTTP_METHODS = ['GET', 'POST', 'PUT', 'DELETE', 'PATCH', 'HEAD', 'OPTIONS'];
 
+// 支持通过出口代理检测的监控类型
+const PROXY_TYPES = ['http', 'https', 'transaction', 'tcp'];
+
+const ASSERTION_TYPES = [
+    {label: '包含', value: 'contains'},
+    {label: '不包含', value: 'not_contains'},
+    {label: '匹配正则', value: 'regex'},
+    {label: '不匹配正则', value: 'not_regex'},
+    {label: 'JSONPath', value: 'jsonpath'},
+];
+
+const ASSERTION_OPERATORS = [
+    {label: '等于', value: 'equals'},
+    {label: '不等于', value: 'not_equals'},
+    {label: '包含', value: 'contains'},
+    {label: '匹配正则', value: 'regex'},
+    {label: '存在', value: 'exists'},
+    {label: '不存在', value: 'not_exists'},
+    {label: '大于', value: 'gt'},
+    {label: '小于', value: 'lt'},
+];
+
 const MonitorList = () => {
     const {message, modal} = App.useApp();
     const actionRef = useRef<ActionType>(null);
@@ -20,10 +46,13 @@ const MonitorList = () => {
     const [modalVisible, setModalVisible] = useState(false);
     const [submitting, setSubmitting] = useState(false);
     const [editingMonitor, setEditingMonitor] = useState<MonitorTask | null>(null);
+    const [recordsMonitor, setRecordsMonitor] = useState<MonitorTask | null>(null);
+    const [slaMonitor, setSlaMonitor] = useState<MonitorTask | null>(null);
     const [agents, setAgents] = useState<Agent[]>([]);
     const [loadingAgents, setLoadingAgents] = useState(false);
     const [keyword, setKeyword] = useState('');
     const [existingTags, setExistingTags] = useState<string[]>([]);
+    const [existingGroups, setExistingGroups] = useState<string[]>([]);
 
     const loadAgents = useCallback(async () => {
         try {
@@ -54,6 +83,19 @@ const MonitorList = () => {
         void loadTags();
     }, []);
 
+    // 加载已有的监控分组
+    useEffect(() => {
+        const loadGroups = async () => {
+            try {
+                const response = await getMonitorGroups();
+                setExistingGroups((response.data || []).map((group) => group.name));
+            } catch (error) {
+                console.error('加载监控分组失败:', error);
+            }
+        };
+        void loadGroups();
+    }, []);
+
     const agentOptions = useMemo(
         () =>
             agents.map((agent) => ({
@@ -78,14 +120,43 @@ const MonitorList = () => {
             interval: 60,
             agentIds: [],
             tags: [],
+            group: '',
+            downQuorum: 0,
+            slaTarget: undefined,
+            retries: 0,
             httpMethod: 'GET',
             httpTimeout: 60,
             httpExpectedStatusCode: 200,
+            httpAcceptedStatusCodes: [],
+            httpFollowRedirects: true,
+            httpMaxRedirects: 10,
             httpHeaders: [],
+            httpAssertions: [],
             httpBody: '',
-            tcpTimeout: 5,
+            httpAuthType: '',
+            httpAuthUsername: '',
+            httpAuthPassword: '',
+            httpAuthToken: '',
+            socketTimeout: 5,
+            socketSend: '',
+            socketExpect: '',
+            socketExpectMode: 'contains',
             icmpTimeout: 5,
             icmpCount: 4,
+            transactionTimeout: 60,
+            transactionSteps: [{method: 'GET'}],
+            mailTimeout: 10,
+            mailTLSMode: 'starttls',
+            mailUsername: '',
+            mailPassword: '',
+            proxyUrl: '',
+            proxyUsername: '',
+            proxyPassword: '',
+            pushGracePeriod: 0,
+            maintenanceWindows: [],
+            webhookEnabled: false,
+            webhookUrl: '',
+            webhookSecret: '',
         });
     };
 
@@ -93,6 +164,9 @@ const MonitorList = () => {
         setEditingMonitor(monitor);
         setModalVisible(true);
 
+        // TCP 和 UDP 共用发送内容和期望响应的表单项
+        const socketConfig = monitor.type === 'udp' ? monitor.udpConfig : monitor.tcpConfig;
+
         const headers = Object.entries(monitor.httpConfig?.headers || {}).map(([key, value]) => ({
             key,
             value,
@@ -109,15 +183,45 @@ const MonitorList = () => {
             interval: monitor.interval || 60,
             agentIds: monitor.agentIds || [],
             tags: monitor.tags || [],
+            group: monitor.group || '',
+            downQuorum: monitor.downQuorum || 0,
+            slaTarget: monitor.slaTarget || undefined,
+            retries: monitor.retries || 0,
             httpMethod: monitor.httpConfig?.method || 'GET',
             httpTimeout: monitor.httpConfig?.timeout || 60,
             httpExpectedStatusCode: monitor.httpConfig?.expectedStatusCode || 200,
+            httpAcceptedStatusCodes: monitor.httpConfig?.acceptedStatusCodes || [],
+            httpFollowRedirects: monitor.httpConfig?.followRedirects ?? true,
+            httpMaxRedirects: monitor.httpConfig?.maxRedirects || 10,
             httpExpectedContent: monitor.httpConfig?.expectedContent,
             httpHeaders: headers.length > 0 ? headers : [{key: '', value: ''}],
+            httpAssertions: monitor.httpConfig?.assertions || [],
             httpBody: monitor.httpConfig?.body,
-            tcpTimeout: monitor.tcpConfig?.timeout || 5,
+            // 密码和令牌不会返回，留空保存时沿用已保存的值
+            httpAuthType: monitor.httpConfig?.auth?.type || '',
+            httpAuthUsername: monitor.httpConfig?.auth?.username || '',
+            httpAuthPassword: '',
+            httpAuthToken: '',
+            socketTimeout: socketConfig?.timeout || 5,
+            socketSend: socketConfig?.send || '',
+            socketExpect: socketConfig?.expect || '',
+            socketExpectMode: socketConfig?.expectMode || 'contains',
             icmpTimeout: monitor.icmpConfig?.timeout || 5,
             icmpCount: monitor.icmpConfig?.count || 4,
+            transactionTimeout: monitor.transactionConfig?.timeout || 60,
+            transactionSteps: toStepFormValues(monitor.transactionConfig),
+            mailTimeout: monitor.mailConfig?.timeout || 10,
+            mailTLSMode: monitor.mailConfig?.tlsMode || 'starttls',
+            mailUsername: monitor.mailConfig?.username || '',
+            mailPassword: '',
+            proxyUrl: monitor.proxy?.url || '',
+            proxyUsername: monitor.proxy?.username || '',
+            proxyPassword: '',
+            pushGracePeriod: monitor.pushGracePeriod || 0,
+            maintenanceWindows: toMaintenanceFormValues(monitor.maintenanceWindows),
+            webhookEnabled: monitor.webhook?.enabled ?? false,
+            webhookUrl: monitor.webhook?.url || '',
+            webhookSecret: monitor.webhook?.secret || '',
         });
     };
 
@@ -154,12 +258,45 @@ const MonitorList = () => {
                 interval: values.interval || 60,
                 agentIds: values.agentIds || [],
                 tags: values.tags || [],
+                group: values.group?.trim() || '',
+                downQuorum: values.downQuorum || 0,
+                slaTarget: values.slaTarget || 0,
+                retries: values.retries || 0,
+                maintenanceWindows: fromMaintenanceFormValues(values.maintenanceWindows),
+                webhook: {
+                    enabled: values.webhookEnabled ?? false,
+                    url: values.webhookUrl?.trim() || '',
+                    secret: values.webhookSecret?.trim() || undefined,
+                },
             };
 
-            if (values.type === 'tcp') {
-                payload.tcpConfig = {
-                    timeout: values.tcpTimeout || 5,
+            if (values.type === 'tcp' || values.type === 'udp') {
+                const socketConfig = {
+                    timeout: values.socketTimeout || 5,
+                    send: values.socketSend || undefined,
+                    expect: values.socketExpect || undefined,
+                    expectMode: values.socketExpect ? values.socketExpectMode : undefined,
+                };
+                if (values.type === 'udp') {
+                    payload.udpConfig = socketConfig;
+                } else {
+                    payload.tcpConfig = socketConfig;
+                }
+            } else if (values.type === 'transaction') {
+                payload.transactionConfig = {
+                    timeout: values.transactionTimeout || 60,
+                    steps: fromStepFormValues(values.transactionSteps),
                 };
+            } else if (values.type === 'smtp' || values.type === 'imap' || values.type === 'pop3') {
+                const username = values.mailTLSMode === 'none' ? '' : values.mailUsername?.trim() || '';
+                payload.mailConfig = {
+                    timeout: values.mailTimeout || 10,
+                    tlsMode: values.mailTLSMode || 'starttls',
+                    username: username || undefined,
+                    password: username ? values.mailPassword || undefined : undefined,
+                };
+            } else if (values.type === 'push') {
+                payload.pushGracePeriod = values.pushGracePeriod || 0;
             } else if (values.type === 'icmp' || values.type === 'ping') {
                 payload.icmpConfig = {
                     timeout: values.icmpTimeout || 5,
@@ -174,13 +311,45 @@ const MonitorList = () => {
                     }
                 });
 
+                const assertions: MonitorHttpAssertion[] = (values.httpAssertions || [])
+                    .filter((assertion: MonitorHttpAssertion) => assertion?.type)
+                    .map((assertion: MonitorHttpAssertion) => assertion.type === 'jsonpath' ? {
+                        type: assertion.type,
+                        path: assertion.path?.trim(),
+                        operator: assertion.operator || 'equals',
+                        value: assertion.operator === 'exists' || assertion.operator === 'not_exists' ? undefined : assertion.value,
+                    } : {
+                        type: assertion.type,
+                        value: assertion.value,
+                    });
+
                 payload.httpConfig = {
                     method: values.httpMethod || 'GET',
                     timeout: values.httpTimeout || 60,
                     expectedStatusCode: values.httpExpectedStatusCode || 200,
+                    acceptedStatusCodes: values.httpAcceptedStatusCodes?.length ? values.httpAcceptedStatusCodes : undefined,
+                    followRedirects: values.httpFollowRedirects ?? true,
+                    maxRedirects: values.httpFollowRedirects === false ? undefined : values.httpMaxRedirects || 10,
                     expectedContent: values.httpExpectedContent?.trim(),
                     headers: Object.keys(headers).length > 0 ? headers : undefined,
-                    body: values.httpBody,
+                    assertions: assertions.length > 0 ? assertions : undefined,
+                    body: values.httpMethod === 'HEAD' ? undefined : values.httpBody,
+                    auth: values.httpAuthType ? {
+                        type: values.httpAuthType,
+                        username: values.httpAuthType === 'basic' ? values.httpAuthUsername?.trim() : undefined,
+                        password: values.httpAuthType === 'basic' ? values.httpAuthPassword || undefined : undefined,
+                        token: values.httpAuthType === 'bearer' ? values.httpAuthToken?.trim() || undefined : undefined,
+                    } : undefined,
+                };
+            }
+
+            const proxyUrl = values.proxyUrl?.trim() || '';
+            if (proxyUrl && PROXY_TYPES.includes(values.type)) {
+                const username = values.proxyUsername?.trim() || '';
+                payload.proxy = {
+                    url: proxyUrl,
+                    username: username || undefined,
+                    password: username ? values.proxyPassword || undefined : undefined,
                 };
             }
 
@@ -207,6 +376,11 @@ const MonitorList = () => {
     };
 
     const watchType = Form.useWatch('type', form) || 'http';
+    const watchWebhookEnabled = Form.useWatch('webhookEnabled', form);
+    const watchHttpMethod = Form.useWatch('httpMethod', form);
+    const watchHttpAuthType = Form.useWatch('httpAuthType', form);
+    const watchHttpFollowRedirects = Form.useWatch('httpFollowRedirects', form);
+    const watchMailTLSMode = Form.useWatch('mailTLSMode', form);
 
     const columns: ProColumns<MonitorTask>[] = [
         {
@@ -214,7 +388,10 @@ const MonitorList = () => {
             dataIndex: 'name',
             render: (_, record) => (
                 <div className="flex flex-col">
-                    <span className="font-medium text-gray-900 dark:text-white">{record.name}</span>
+                    <span className="font-medium text-gray-900 dark:text-white">
+                        {record.name}
+                        {record.group ? <Tag className="ml-2">{record.group}</Tag> : null}
+                    </span>
                     {record.description ? (
                         <span className="text-xs text-gray-500 dark:text-gray-400">{record.description}</span>
                     ) : null}
@@ -228,7 +405,11 @@ const MonitorList = () => {
             render: (type) => {
                 let color = 'green';
                 if (type === 'tcp') color = 'blue';
+                else if (type === 'udp') color = 'cyan';
                 else if (type === 'icmp' || type === 'ping') color = 'purple';
+                else if (type === 'transaction') color = 'orange';
+                else if (type === 'smtp' || type === 'imap' || type === 'pop3') color = 'gold';
+                else if (type === 'push') color = 'magenta';
 
                 return (
                     <Tag color={color} className="uppercase">
@@ -302,8 +483,26 @@ const MonitorList = () => {
         {
             title: '操作',
             valueType: 'option',
-            width: 180,
+            width: 300,
             render: (_, record) => [
+                <Button
+                    key="records"
+                    type="link"
+                    size="small"
+                    icon={<History size={14}/>}
+                    onClick={() => setRecordsMonitor(record)}
+                >
+                    记录
+                </Button>,
+                <Button
+                    key="sla"
+                    type="link"
+                    size="small"
+                    icon={<Gauge size={14}/>}
+                    onClick={() => setSlaMonitor(record)}
+                >
+                    SLA
+                </Button>,
                 <Button
                     key="edit"
                     type="link"
@@ -417,6 +616,22 @@ const MonitorList = () => {
                         <Input placeholder="可选，帮助识别监控用途"/>
                     </Form.Item>
 
+                    <Form.Item
+                        label="分组"
+                        name="group"
+                        extra="同一分组的监控项汇总展示可用率，可在告警设置中配置分组内离线数量的告警"
+                        rules={[{max: 64, message: '分组不能超过 64 个字符'}]}
+                    >
+                        <AutoComplete
+                            allowClear
+                            placeholder="可选，例如：API、Edge"
+                            options={existingGroups.map((group) => ({label: group, value: group}))}
+                            filterOption={(input, option) =>
+                                (option?.value as string).toLowerCase().includes(input.toLowerCase())
+                            }
+                        />
+                    </Form.Item>
+
                     <Form.Item
                         label="类型"
                         name="type"
@@ -426,7 +641,13 @@ const MonitorList = () => {
                             options={[
                                 {label: 'HTTP / HTTPS', value: 'http'},
                                 {label: 'TCP', value: 'tcp'},
+                                {label: 'UDP', value: 'udp'},
                                 {label: 'ICMP (Ping)', value: 'icmp'},
+                                {label: '多步骤事务', value: 'transaction'},
+                                {label: 'SMTP', value: 'smtp'},
+                                {label: 'IMAP', value: 'imap'},
+                                {label: 'POP3', value: 'pop3'},
+                                {label: '被动心跳', value: 'push'},
                             ]}
                         />
                     </Form.Item>
@@ -435,37 +656,70 @@ const MonitorList = () => {
                         label="目标地址"
                         name="target"
                         rules={[{required: true, message: '请输入目标地址'}]}
+                        extra={watchType === 'transaction'
+                            ? '用于列表和公开页面展示，实际请求地址在各步骤中配置'
+                            : watchType === 'push'
+                                ? '用于列表和公开页面展示，外部任务通过推送地址上报心跳'
+                                : undefined}
                     >
                         <Input placeholder={
                             watchType === 'icmp'
                                 ? "ICMP示例：8.8.8.8 或 google.com"
                                 : watchType === 'tcp'
                                     ? "TCP示例：example.com:3306"
+                                    : watchType === 'udp'
+                                        ? "UDP示例：8.8.8.8:53 或 game.example.com:27015"
+                                    : watchType === 'transaction'
+                                        ? "事务示例：https://example.com 登录流程"
+                                    : watchType === 'smtp' || watchType === 'imap' || watchType === 'pop3'
+                                        ? "邮件服务示例：smtp.example.com:587、imap.example.com:993"
+                                    : watchType === 'push'
+                                        ? "心跳示例：每日数据库备份"
                                     : "HTTP示例：https://example.com/health"
                         }/>
                     </Form.Item>
 
-                    <Form.Item label="探针范围" name="agentIds" extra="选择特定探针节点执行此监控">
-                        <Select
-                            mode="multiple"
-                            placeholder="选择探针节点（可多选）"
-                            options={agentOptions}
-                            loading={loadingAgents}
-                            allowClear
-                        />
-                    </Form.Item>
+                    {watchType !== 'push' && (
+                        <>
+                            <Form.Item label="探针范围" name="agentIds" extra="选择特定探针节点执行此监控">
+                                <Select
+                                    mode="multiple"
+                                    placeholder="选择探针节点（可多选）"
+                                    options={agentOptions}
+                                    loading={loadingAgents}
+                                    allowClear
+                                />
+                            </Form.Item>
+
+                            <Form.Item
+                                label="探针标签"
+                                name="tags"
+                                extra="选择标签后，拥有这些标签的探针都会执行此监控。若同时选择探针和标签，则两者取并集（自动去重）。若都不选择，则所有探针都会执行"
+                            >
+                                <Select
+                                    mode="multiple"
+                                    placeholder="选择标签（可多选）"
+                                    options={existingTags.map(tag => ({label: tag, value: tag}))}
+                                    allowClear
+                                />
+                            </Form.Item>
+
+                            <Form.Item
+                                label="离线判定探针数量"
+                                name="downQuorum"
+                                extra="至少多少个探针检测离线才视为离线，避免单个探针的网络故障导致误报；0 表示所有探针都离线才视为离线"
+                            >
+                                <InputNumber min={0} max={100} style={{width: '100%'}}/>
+                            </Form.Item>
+                        </>
+                    )}
 
                     <Form.Item
-                        label="探针标签"
-                        name="tags"
-                        extra="选择标签后，拥有这些标签的探针都会执行此监控。若同时选择探针和标签，则两者取并集（自动去重）。若都不选择，则所有探针都会执行"
+                        label="SLA 目标 (%)"
+                        name="slaTarget"
+                        extra="可选，如 99.9；设置后 SLA 报告按该目标计算每月的达成情况和剩余错误预算"
                     >
-                        <Select
-                            mode="multiple"
-                            placeholder="选择标签（可多选）"
-                            options={existingTags.map(tag => ({label: tag, value: tag}))}
-                            allowClear
-                        />
+                        <InputNumber min={0} max={100} step={0.01} placeholder="未设置" style={{width: '100%'}}/>
                     </Form.Item>
 
                     <Form.Item
@@ -473,11 +727,23 @@ const MonitorList = () => {
                         name="interval"
                         initialValue={60}
                         rules={[{required: true, message: '请输入检测频率'}]}
-                        extra="设置多久执行一次检测，建议不低于 30 秒"
+                        extra={watchType === 'push'
+                            ? '外部任务的执行周期，超过检测频率加宽限时间未收到心跳视为离线'
+                            : '设置多久执行一次检测，建议不低于 30 秒'}
                     >
-                        <InputNumber min={10} max={3600} style={{width: '100%'}}/>
+                        <InputNumber min={10} max={watchType === 'push' ? 604800 : 3600} style={{width: '100%'}}/>
                     </Form.Item>
 
+                    {watchType !== 'push' && (
+                        <Form.Item
+                            label="失败重试次数"
+                            name="retries"
+                            extra="检测失败后间隔 2 秒重新检测，全部失败才上报离线，可减少网络抖动导致的误报；重试不会超过下一次检测的时间"
+                        >
+                            <InputNumber min={0} max={5} style={{width: '100%'}}/>
+                        </Form.Item>
+                    )}
+
                     <Form.Item label="启用状态" name="enabled" valuePropName="checked">
                         <Switch checkedChildren="启用" unCheckedChildren="停用"/>
                     </Form.Item>
@@ -506,10 +772,107 @@ const MonitorList = () => {
                         />
                     </Form.Item>
 
-                    {watchType === 'tcp' ? (
-                        <Form.Item label="连接超时 (秒)" name="tcpTimeout" initialValue={5}>
-                            <InputNumber min={1} max={120} style={{width: '100%'}}/>
-                        </Form.Item>
+                    {watchType === 'tcp' || watchType === 'udp' ? (
+                        <>
+                            <Form.Item label={watchType === 'udp' ? '应答超时 (秒)' : '连接超时 (秒)'} name="socketTimeout" initialValue={5}>
+                                <InputNumber min={1} max={120} style={{width: '100%'}}/>
+                            </Form.Item>
+
+                            <Form.Item
+                                label="发送内容"
+                                name="socketSend"
+                                extra={watchType === 'udp'
+                                    ? "发送的数据报，支持 \\r\\n、\\x00 等转义，二进制协议（如 DNS 查询、游戏服务器查询）可用 \\x 转义构造"
+                                    : "可选，连接后发送的数据，支持 \\r\\n、\\x00 等转义，例如 Redis 填写 PING\\r\\n"}
+                            >
+                                <Input placeholder={watchType === 'udp' ? '例如：\\xff\\xff\\xff\\xffTSource Engine Query\\x00' : '可选'}/>
+                            </Form.Item>
+
+                            <Form.Item
+                                label="期望响应"
+                                extra={watchType === 'udp'
+                                    ? "可选，为空时在超时时间内收到任意应答即视为正常"
+                                    : "可选，为空时只检查端口能否连接，例如 SMTP 填写 220、Redis 填写 +PONG"}
+                            >
+                                <Space.Compact style={{width: '100%'}}>
+                                    <Form.Item name="socketExpectMode" noStyle initialValue="contains">
+                                        <Select
+                                            style={{width: 120}}
+                                            options={[
+                                                {label: '包含', value: 'contains'},
+                                                {label: '前缀', value: 'prefix'},
+                                                {label: '正则', value: 'regex'},
+                                            ]}
+                                        />
+                                    </Form.Item>
+                                    <Form.Item name="socketExpect" noStyle>
+                                        <Input placeholder="可选"/>
+                                    </Form.Item>
+                                </Space.Compact>
+                            </Form.Item>
+                        </>
+                    ) : watchType === 'transaction' ? (
+                        <TransactionSteps/>
+                    ) : watchType === 'push' ? (
+                        <>
+                            <Form.Item
+                                label="宽限时间 (秒)"
+                                name="pushGracePeriod"
+                                initialValue={0}
+                                extra="允许外部任务延迟上报的时间，适用于执行时长不固定的任务"
+                            >
+                                <InputNumber min={0} max={604800} style={{width: '100%'}}/>
+                            </Form.Item>
+
+                            <Form.Item
+                                label="推送地址"
+                                extra="任务完成后以 GET 或 POST 请求该地址，可附带 status=down 上报失败、msg 附带说明、ping 上报耗时（毫秒）"
+                            >
+                                {editingMonitor?.pushToken ? (
+                                    <Typography.Paragraph copyable style={{marginBottom: 0}}>
+                                        {`${window.location.origin}/api/push/${editingMonitor.pushToken}`}
+                                    </Typography.Paragraph>
+                                ) : (
+                                    <Typography.Text type="secondary">保存后生成推送地址</Typography.Text>
+                                )}
+                            </Form.Item>
+                        </>
+                    ) : watchType === 'smtp' || watchType === 'imap' || watchType === 'pop3' ? (
+                        <>
+                            <Form.Item label="超时 (秒)" name="mailTimeout" initialValue={10}>
+                                <InputNumber min={1} max={120} style={{width: '100%'}}/>
+                            </Form.Item>
+
+                            <Form.Item
+                                label="加密方式"
+                                name="mailTLSMode"
+                                initialValue="starttls"
+                                extra="STARTTLS 常用于 587、143、110 端口，SSL/TLS 常用于 465、993、995 端口，加密连接会上报证书到期时间"
+                            >
+                                <Select
+                                    options={[
+                                        {label: 'STARTTLS', value: 'starttls'},
+                                        {label: 'SSL/TLS', value: 'tls'},
+                                        {label: '不加密', value: 'none'},
+                                    ]}
+                                />
+                            </Form.Item>
+
+                            {watchMailTLSMode !== 'none' && (
+                                <>
+                                    <Form.Item label="用户名" name="mailUsername" extra="可选，填写后检查能否登录">
+                                        <Input placeholder="可选" autoComplete="off"/>
+                                    </Form.Item>
+
+                                    <Form.Item label="密码" name="mailPassword">
+                                        <Input.Password
+                                            autoComplete="new-password"
+                                            placeholder={editingMonitor ? '留空保持不变' : undefined}
+                                        />
+                                    </Form.Item>
+                                </>
+                            )}
+                        </>
                     ) : watchType === 'icmp' ? (
                         <>
                             <Form.Item label="Ping 超时 (秒)" name="icmpTimeout" initialValue={5}>
@@ -535,10 +898,95 @@ const MonitorList = () => {
                                 <InputNumber min={100} max={599} style={{width: '100%'}}/>
                             </Form.Item>
 
+                            <Form.Item
+                                label="可接受的状态码"
+                                name="httpAcceptedStatusCodes"
+                                extra="可选，设置后代替期望状态码，支持 401、200-299、2xx，例如认证保护的地址可接受 401"
+                            >
+                                <Select mode="tags" tokenSeparators={[',', ' ']} placeholder="如 2xx, 401" open={false}/>
+                            </Form.Item>
+
+                            <Form.Item
+                                label="跟随重定向"
+                                name="httpFollowRedirects"
+                                valuePropName="checked"
+                                initialValue={true}
+                                extra="关闭后以重定向响应本身（3xx）的状态码判断"
+                            >
+                                <Switch/>
+                            </Form.Item>
+
+                            {watchHttpFollowRedirects !== false && (
+                                <Form.Item label="最大重定向次数" name="httpMaxRedirects" initialValue={10}>
+                                    <InputNumber min={1} max={30} style={{width: '100%'}}/>
+                                </Form.Item>
+                            )}
+
                             <Form.Item label="期望响应内容" name="httpExpectedContent">
                                 <Input placeholder="可选，匹配关键字"/>
                             </Form.Item>
 
+                            <Form.Item
+                                label="内容断言"
+                                extra="全部通过才视为正常，逐条结果记录在检测详情中；JSONPath 支持 $.a.b、$.items[0]、$['key'] 写法"
+                            >
+                                <Form.List name="httpAssertions">
+                                    {(fields, {add, remove}) => (
+                                        <div className="space-y-2">
+                                            {fields.map(({key, name, ...restField}) => (
+                                                <Space key={key} align="baseline" className="flex" wrap>
+                                                    <Form.Item {...restField} name={[name, 'type']} initialValue="contains">
+                                                        <Select style={{width: 120}} options={ASSERTION_TYPES}/>
+                                                    </Form.Item>
+                                                    <Form.Item noStyle shouldUpdate>
+                                                        {() => {
+                                                            const assertion = form.getFieldValue(['httpAssertions', name]) || {};
+                                                            if (assertion.type !== 'jsonpath') {
+                                                                return (
+                                                                    <Form.Item {...restField} name={[name, 'value']} rules={[{required: true, message: '请输入内容'}]}>
+                                                                        <Input placeholder={assertion.type?.includes('regex') ? '正则表达式' : '匹配内容'}/>
+                                                                    </Form.Item>
+                                                                );
+                                                            }
+                                                            const noValue = assertion.operator === 'exists' || assertion.operator === 'not_exists';
+                                                            return (
+                                                                <>
+                                                                    <Form.Item {...restField} name={[name, 'path']} rules={[{required: true, message: '请输入 JSONPath'}]}>
+                                                                        <Input placeholder="$.data.status" style={{width: 160}}/>
+                                                                    </Form.Item>
+                                                                    <Form.Item {...restField} name={[name, 'operator']} initialValue="equals">
+                                                                        <Select style={{width: 100}} options={ASSERTION_OPERATORS}/>
+                                                                    </Form.Item>
+                                                                    {!noValue && (
+                                                                        <Form.Item {...restField} name={[name, 'value']}>
+                                                                            <Input placeholder="期望值" style={{width: 120}}/>
+                                                                        </Form.Item>
+                                                                    )}
+                                                                </>
+                                                            );
+                                                        }}
+                                                    </Form.Item>
+                                                    <Button
+                                                        type="text"
+                                                        danger
+                                                        icon={<MinusCircle size={16}/>}
+                                                        onClick={() => remove(name)}
+                                                    />
+                                                </Space>
+                                            ))}
+                                            <Button
+                                                type="dashed"
+                                                block
+                                                icon={<PlusCircle size={16}/>}
+                                                onClick={() => add({type: 'contains', value: ''})}
+                                            >
+                                                添加断言
+                                            </Button>
+                                        </div>
+                                    )}
+                                </Form.List>
+                            </Form.Item>
+
                             <Form.Item label="请求头">
                                 <Form.List name="httpHeaders">
                                     {(fields, {add, remove}) => (
@@ -582,13 +1030,138 @@ const MonitorList = () => {
                                 </Form.List>
                             </Form.Item>
 
-                            <Form.Item label="请求体" name="httpBody">
-                                <Input.TextArea rows={4} placeholder="可选，发送自定义请求体"/>
+                            <Form.Item
+                                label="请求体"
+                                name="httpBody"
+                                extra="未设置 Content-Type 请求头时，JSON 格式的请求体按 application/json 发送；HEAD 请求不能携带请求体"
+                            >
+                                <Input.TextArea rows={4} placeholder="可选，发送自定义请求体" disabled={watchHttpMethod === 'HEAD'}/>
+                            </Form.Item>
+
+                            <Form.Item
+                                label="认证方式"
+                                name="httpAuthType"
+                                initialValue=""
+                                extra="密码和令牌加密保存，仅在下发给探针时解密；请求头中已设置 Authorization 时以请求头为准"
+                            >
+                                <Select
+                                    options={[
+                                        {label: '无', value: ''},
+                                        {label: 'Basic 认证', value: 'basic'},
+                                        {label: 'Bearer 令牌', value: 'bearer'},
+                                    ]}
+                                />
+                            </Form.Item>
+
+                            {watchHttpAuthType === 'basic' && (
+                                <>
+                                    <Form.Item
+                                        label="用户名"
+                                        name="httpAuthUsername"
+                                        rules={[{required: true, message: '请输入用户名'}]}
+                                    >
+                                        <Input autoComplete="off"/>
+                                    </Form.Item>
+                                    <Form.Item label="密码" name="httpAuthPassword">
+                                        <Input.Password
+                                            autoComplete="new-password"
+                                            placeholder={editingMonitor ? '留空保持不变' : undefined}
+                                        />
+                                    </Form.Item>
+                                </>
+                            )}
+
+                            {watchHttpAuthType === 'bearer' && (
+                                <Form.Item label="令牌" name="httpAuthToken">
+                                    <Input.Password
+                                        autoComplete="new-password"
+                                        placeholder={editingMonitor ? '留空保持不变' : undefined}
+                                    />
+                                </Form.Item>
+                            )}
+                        </>
+                    )}
+
+                    {PROXY_TYPES.includes(watchType) && (
+                        <>
+                            <Form.Item
+                                label="代理地址"
+                                name="proxyUrl"
+                                extra="可选，探针通过该代理执行检测，支持 http://、https://、socks5://；认证信息请填写在下方"
+                            >
+                                <Input placeholder="socks5://10.0.0.1:1080" autoComplete="off"/>
+                            </Form.Item>
+
+                            <Form.Item noStyle shouldUpdate={(prev, next) => prev.proxyUrl !== next.proxyUrl}>
+                                {({getFieldValue}) => getFieldValue('proxyUrl')?.trim() ? (
+                                    <>
+                                        <Form.Item label="代理用户名" name="proxyUsername">
+                                            <Input placeholder="可选" autoComplete="off"/>
+                                        </Form.Item>
+
+                                        <Form.Item label="代理密码" name="proxyPassword">
+                                            <Input.Password
+                                                autoComplete="new-password"
+                                                placeholder={editingMonitor ? '留空保持不变' : undefined}
+                                            />
+                                        </Form.Item>
+                                    </>
+                                ) : null}
+                            </Form.Item>
+                        </>
+                    )}
+
+                    <MaintenanceWindows/>
+
+                    <Form.Item
+                        label="状态变化回调"
+                        name="webhookEnabled"
+                        valuePropName="checked"
+                        extra="检测状态在正常和异常之间切换时，以 JSON 格式推送本次检测结果"
+                    >
+                        <Switch checkedChildren="启用" unCheckedChildren="停用"/>
+                    </Form.Item>
+
+                    {watchWebhookEnabled && (
+                        <>
+                            <Form.Item
+                                label="回调地址"
+                                name="webhookUrl"
+                                rules={[
+                                    {required: true, message: '请输入回调地址'},
+                                    {type: 'url', message: '请输入有效的 URL'},
+                                ]}
+                            >
+                                <Input placeholder="https://example.com/hooks/failover"/>
+                            </Form.Item>
+
+                            <Form.Item
+                                label="签名密钥"
+                                name="webhookSecret"
+                                extra="可选，配置后请求头 X-Pika-Signature 携带请求体的 HMAC-SHA256 签名"
+                            >
+                                <Input.Password placeholder="可选"/>
                             </Form.Item>
                         </>
                     )}
                 </Form>
             </Modal>
+
+            {recordsMonitor && (
+                <RecordsDrawer
+                    open={!!recordsMonitor}
+                    monitor={recordsMonitor}
+                    onClose={() => setRecordsMonitor(null)}
+                />
+            )}
+
+            {slaMonitor && (
+                <SLAReportModal
+                    open={!!slaMonitor}
+                    monitor={slaMonitor}
+                    onClose={() => setSlaMonitor(null)}
+                />
+            )}
         </div>
     );
 };
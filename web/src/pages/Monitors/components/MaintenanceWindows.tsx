@@ -0,0 +1,146 @@
+import {Button, Card, DatePicker, Form, Input, Select, Space} from 'antd';
+import {MinusCircle, PlusCircle} from 'lucide-react';
+import dayjs, {type Dayjs} from 'dayjs';
+import type {MonitorMaintenanceWindow} from '@/types';
+
+const WINDOW_TYPES = [
+    {label: '一次性', value: 'once'},
+    {label: '每天', value: 'daily'},
+    {label: '每周', value: 'weekly'},
+];
+
+const WEEKDAYS = ['周日', '周一', '周二', '周三', '周四', '周五', '周六'].map((label, value) => ({label, value}));
+
+const CLOCK_PATTERN = /^([01]\d|2[0-3]):[0-5]\d$/;
+
+// 表单中的维护时间窗口，一次性窗口以时间范围编辑
+export interface MaintenanceWindowFormValue {
+    name?: string;
+    type?: 'once' | 'daily' | 'weekly';
+    range?: [Dayjs, Dayjs];
+    weekdays?: number[];
+    startTime?: string;
+    endTime?: string;
+    timezone?: string;
+}
+
+// toMaintenanceFormValues 将维护时间窗口转换为表单值
+export const toMaintenanceFormValues = (windows?: MonitorMaintenanceWindow[] | null): MaintenanceWindowFormValue[] =>
+    (windows || []).map((window) => ({
+        name: window.name,
+        type: window.type,
+        range: window.type === 'once' && window.startAt && window.endAt
+            ? [dayjs(window.startAt), dayjs(window.endAt)]
+            : undefined,
+        weekdays: window.weekdays || [],
+        startTime: window.startTime,
+        endTime: window.endTime,
+        timezone: window.timezone,
+    }));
+
+// fromMaintenanceFormValues 将表单值转换为维护时间窗口
+export const fromMaintenanceFormValues = (values: MaintenanceWindowFormValue[] = []): MonitorMaintenanceWindow[] =>
+    values.filter((window) => window?.type).map((window) => {
+        if (window.type === 'once') {
+            return {
+                name: window.name?.trim() || undefined,
+                type: 'once',
+                startAt: window.range?.[0]?.valueOf(),
+                endAt: window.range?.[1]?.valueOf(),
+            };
+        }
+        return {
+            name: window.name?.trim() || undefined,
+            type: window.type!,
+            weekdays: window.type === 'weekly' ? window.weekdays : undefined,
+            startTime: window.startTime?.trim(),
+            endTime: window.endTime?.trim(),
+            timezone: window.timezone?.trim() || undefined,
+        };
+    });
+
+const MaintenanceWindows = () => (
+    <Form.Item
+        label="维护时间窗口"
+        extra="维护期间的检测结果照常记录，但离线结果不计入可用率，也不触发服务下线告警；结束时间早于开始时间表示跨天"
+    >
+        <Form.List name="maintenanceWindows">
+            {(fields, {add, remove}) => (
+                <div className="space-y-3">
+                    {fields.map(({key, name, ...restField}, index) => (
+                        <Card
+                            key={key}
+                            size="small"
+                            title={`窗口 ${index + 1}`}
+                            extra={<Button type="text" danger icon={<MinusCircle size={16}/>} onClick={() => remove(name)}/>}
+                        >
+                            <Space.Compact style={{width: '100%'}}>
+                                <Form.Item {...restField} name={[name, 'type']} noStyle initialValue="daily">
+                                    <Select style={{width: 110}} options={WINDOW_TYPES}/>
+                                </Form.Item>
+                                <Form.Item {...restField} name={[name, 'name']} noStyle>
+                                    <Input placeholder="名称，如 数据库备份"/>
+                                </Form.Item>
+                            </Space.Compact>
+                            <Form.Item noStyle dependencies={[['maintenanceWindows', name, 'type']]}>
+                                {({getFieldValue}) => {
+                                    const type = getFieldValue(['maintenanceWindows', name, 'type']);
+                                    if (type === 'once') {
+                                        return (
+                                            <Form.Item
+                                                {...restField}
+                                                name={[name, 'range']}
+                                                className="!mt-3 !mb-0"
+                                                rules={[{required: true, message: '请选择维护时间'}]}
+                                            >
+                                                <DatePicker.RangePicker showTime className="w-full"/>
+                                            </Form.Item>
+                                        );
+                                    }
+                                    return (
+                                        <>
+                                            {type === 'weekly' && (
+                                                <Form.Item
+                                                    {...restField}
+                                                    name={[name, 'weekdays']}
+                                                    className="!mt-3"
+                                                    rules={[{required: true, message: '请选择维护日'}]}
+                                                >
+                                                    <Select mode="multiple" placeholder="维护日（跨天的窗口以开始时间所在的日期为准）" options={WEEKDAYS}/>
+                                                </Form.Item>
+                                            )}
+                                            <Space align="baseline" className={type === 'weekly' ? 'flex' : 'flex !mt-3'}>
+                                                <Form.Item
+                                                    {...restField}
+                                                    name={[name, 'startTime']}
+                                                    rules={[{required: true, pattern: CLOCK_PATTERN, message: 'HH:MM'}]}
+                                                >
+                                                    <Input placeholder="开始 02:00" style={{width: 110}}/>
+                                                </Form.Item>
+                                                <Form.Item
+                                                    {...restField}
+                                                    name={[name, 'endTime']}
+                                                    rules={[{required: true, pattern: CLOCK_PATTERN, message: 'HH:MM'}]}
+                                                >
+                                                    <Input placeholder="结束 04:00" style={{width: 110}}/>
+                                                </Form.Item>
+                                                <Form.Item {...restField} name={[name, 'timezone']}>
+                                                    <Input placeholder="时区，默认服务端时区" style={{width: 180}}/>
+                                                </Form.Item>
+                                            </Space>
+                                        </>
+                                    );
+                                }}
+                            </Form.Item>
+                        </Card>
+                    ))}
+                    <Button type="dashed" block icon={<PlusCircle size={16}/>} onClick={() => add({type: 'daily'})}>
+                        添加维护时间窗口
+                    </Button>
+                </div>
+            )}
+        </Form.List>
+    </Form.Item>
+);
+
+export default MaintenanceWindows;
@@ -0,0 +1,186 @@
+import {useCallback, useEffect, useState} from 'react';
+import {App, Button, DatePicker, Drawer, Select, Space, Table, Tag} from 'antd';
+import type {ColumnType} from 'antd/es/table';
+import type {Dayjs} from 'dayjs';
+import dayjs from 'dayjs';
+import {Download} from 'lucide-react';
+import {exportMonitorRecords, listMonitorRecords, type MonitorRecordParams} from '@/api/monitor.ts';
+import type {MonitorMetric, MonitorTask} from '@/types';
+import {getErrorMessage} from '@/lib/utils';
+
+interface RecordsDrawerProps {
+    open: boolean;
+    monitor: MonitorTask;
+    onClose: () => void;
+}
+
+const PAGE_SIZE = 50;
+
+// 原始检测记录，状态不变的连续检测按采样间隔合并为一条，可导出作为 SLA 证据
+const RecordsDrawer = ({open, monitor, onClose}: RecordsDrawerProps) => {
+    const {message} = App.useApp();
+    const [loading, setLoading] = useState(false);
+    const [exporting, setExporting] = useState(false);
+    const [records, setRecords] = useState<MonitorMetric[]>([]);
+    const [total, setTotal] = useState(0);
+    const [pageIndex, setPageIndex] = useState(1);
+    const [range, setRange] = useState<[Dayjs, Dayjs]>([dayjs().subtract(1, 'day'), dayjs()]);
+    const [status, setStatus] = useState<MonitorRecordParams['status']>();
+
+    const params: MonitorRecordParams = {
+        from: range[0].valueOf(),
+        to: range[1].valueOf(),
+        status,
+    };
+
+    const loadRecords = useCallback(async (page: number) => {
+        setLoading(true);
+        try {
+            const res = await listMonitorRecords(String(monitor.id), params, page, PAGE_SIZE);
+            setRecords(res.data.items || []);
+            setTotal(res.data.total || 0);
+            setPageIndex(page);
+        } catch (error) {
+            message.error(getErrorMessage(error, '加载检测记录失败'));
+        } finally {
+            setLoading(false);
+        }
+        // eslint-disable-next-line react-hooks/exhaustive-deps
+    }, [monitor.id, range, status]);
+
+    useEffect(() => {
+        if (open) {
+            loadRecords(1);
+        }
+    }, [open, loadRecords]);
+
+    const handleExport = async (format: 'csv' | 'json') => {
+        setExporting(true);
+        try {
+            const res = await exportMonitorRecords(String(monitor.id), params, format);
+            const isCSV = format === 'csv';
+            const blob = isCSV
+                ? new Blob(['\uFEFF', res.data], {type: 'text/csv;charset=utf-8'})
+                : new Blob([res.data], {type: 'application/x-ndjson'});
+            const url = URL.createObjectURL(blob);
+            const link = document.createElement('a');
+            link.href = url;
+            link.download = `monitor-${monitor.id}-records-${dayjs().format('YYYYMMDDHHmmss')}.${isCSV ? 'csv' : 'jsonl'}`;
+            link.click();
+            URL.revokeObjectURL(url);
+        } catch (error) {
+            message.error(getErrorMessage(error, '导出失败'));
+        } finally {
+            setExporting(false);
+        }
+    };
+
+    const columns: ColumnType<MonitorMetric>[] = [
+        {
+            title: '检测时间',
+            dataIndex: 'timestamp',
+            key: 'timestamp',
+            width: 180,
+            render: (timestamp: number) => dayjs(timestamp).format('YYYY-MM-DD HH:mm:ss'),
+        },
+        {
+            title: '探针',
+            dataIndex: 'agentId',
+            key: 'agentId',
+            width: 220,
+            ellipsis: true,
+        },
+        {
+            title: '状态',
+            dataIndex: 'status',
+            key: 'status',
+            width: 80,
+            render: (value: string) => (
+                <Tag color={value === 'up' ? 'success' : 'error'}>{value === 'up' ? '正常' : '异常'}</Tag>
+            ),
+        },
+        {
+            title: '响应时间',
+            dataIndex: 'responseTime',
+            key: 'responseTime',
+            width: 100,
+            render: (value: number) => `${value} ms`,
+        },
+        {
+            title: '检测次数',
+            dataIndex: 'checks',
+            key: 'checks',
+            width: 90,
+            render: (value: number) => value || 1,
+        },
+        {
+            title: '错误信息',
+            dataIndex: 'error',
+            key: 'error',
+            ellipsis: true,
+            render: (value: string) => value && <span className="text-red-500">{value}</span>,
+        },
+    ];
+
+    return (
+        <Drawer
+            title={`检测记录 - ${monitor.name}`}
+            open={open}
+            onClose={onClose}
+            width={1100}
+            destroyOnHidden={true}
+        >
+            <div className="mb-4 flex flex-wrap items-center justify-between gap-3">
+                <Space wrap>
+                    <DatePicker.RangePicker
+                        showTime
+                        value={range}
+                        allowClear={false}
+                        onChange={(value) => {
+                            if (value?.[0] && value?.[1]) {
+                                setRange([value[0], value[1]]);
+                            }
+                        }}
+                    />
+                    <Select
+                        allowClear
+                        placeholder="全部状态"
+                        style={{width: 120}}
+                        value={status}
+                        onChange={setStatus}
+                        options={[
+                            {label: '正常', value: 'up'},
+                            {label: '异常', value: 'down'},
+                        ]}
+                    />
+                </Space>
+                <Space>
+                    <Button icon={<Download size={14}/>} loading={exporting} onClick={() => handleExport('csv')}>
+                        导出 CSV
+                    </Button>
+                    <Button loading={exporting} onClick={() => handleExport('json')}>
+                        导出 JSON Lines
+                    </Button>
+                </Space>
+            </div>
+
+            <Table
+                columns={columns}
+                dataSource={records}
+                rowKey={(record) => `${record.agentId}-${record.timestamp}-${record.id}`}
+                loading={loading}
+                pagination={{
+                    current: pageIndex,
+                    pageSize: PAGE_SIZE,
+                    total,
+                    showSizeChanger: false,
+                    showTotal: (value) => `共 ${value} 条记录`,
+                    onChange: loadRecords,
+                }}
+                scroll={{x: 900}}
+            />
+        </Drawer>
+    );
+};
+
+export default RecordsDrawer;
@@ -0,0 +1,119 @@
+import {useEffect, useState} from 'react';
+import {Alert, App, Col, DatePicker, Modal, Row, Statistic, Table, Tag} from 'antd';
+import type {ColumnType} from 'antd/es/table';
+import type {Dayjs} from 'dayjs';
+import dayjs from 'dayjs';
+import {getMonitorSLAReport} from '@/api/monitor.ts';
+import type {MonitorSLAAgent, MonitorSLAReport, MonitorTask} from '@/types';
+import {getErrorMessage} from '@/lib/utils';
+
+interface SLAReportModalProps {
+    open: boolean;
+    monitor: MonitorTask;
+    onClose: () => void;
+}
+
+const SLAReportModal = ({open, monitor, onClose}: SLAReportModalProps) => {
+    const {message} = App.useApp();
+    const [month, setMonth] = useState<Dayjs>(dayjs());
+    const [loading, setLoading] = useState(false);
+    const [report, setReport] = useState<MonitorSLAReport | null>(null);
+
+    useEffect(() => {
+        if (!open) {
+            return;
+        }
+        setLoading(true);
+        getMonitorSLAReport(String(monitor.id), month.format('YYYY-MM'))
+            .then((res) => setReport(res.data))
+            .catch((error) => message.error(getErrorMessage(error, '获取 SLA 报告失败')))
+            .finally(() => setLoading(false));
+    }, [open, monitor.id, month, message]);
+
+    const columns: ColumnType<MonitorSLAAgent>[] = [
+        {
+            title: '探针',
+            dataIndex: 'agentName',
+            key: 'agentName',
+            render: (name: string, record) => name || record.agentId,
+        },
+        {
+            title: '可用率',
+            dataIndex: 'uptime',
+            key: 'uptime',
+            width: 120,
+            render: (value: number) => `${value.toFixed(3)}%`,
+        },
+        {
+            title: '成功 / 检测次数',
+            key: 'checks',
+            width: 160,
+            render: (_, record) => `${record.successChecks} / ${record.totalChecks}`,
+        },
+    ];
+
+    const hasTarget = (report?.target ?? 0) > 0;
+
+    return (
+        <Modal
+            title={`SLA 报告 - ${monitor.name}`}
+            open={open}
+            onCancel={onClose}
+            footer={null}
+            width={760}
+            destroyOnClose
+        >
+            <div className="mb-4 flex items-center justify-between gap-3">
+                <DatePicker
+                    picker="month"
+                    value={month}
+                    allowClear={false}
+                    disabledDate={(date) => date.isAfter(dayjs(), 'month')}
+                    onChange={(value) => value && setMonth(value)}
+                />
+                {report && hasTarget && (
+                    <Tag color={report.compliant ? 'success' : 'error'}>
+                        {report.compliant ? '达到 SLA 目标' : '未达到 SLA 目标'}
+                    </Tag>
+                )}
+            </div>
+
+            {report && !hasTarget && (
+                <Alert className="mb-4" type="info" showIcon message="该监控项未设置 SLA 目标，可在编辑监控项时设置"/>
+            )}
+
+            <Row gutter={16} className="mb-4">
+                <Col span={6}>
+                    <Statistic title="可用率" value={report?.uptime ?? 0} precision={3} suffix="%" loading={loading}/>
+                </Col>
+                <Col span={6}>
+                    <Statistic title="SLA 目标" value={hasTarget ? report?.target : '-'} suffix={hasTarget ? '%' : undefined} loading={loading}/>
+                </Col>
+                <Col span={6}>
+                    <Statistic title="不可用时长" value={report?.downtimeMinutes ?? 0} precision={1} suffix="分钟" loading={loading}/>
+                </Col>
+                <Col span={6}>
+                    <Statistic
+                        title="剩余错误预算"
+                        value={hasTarget ? report?.errorBudgetRemaining : '-'}
+                        precision={1}
+                        suffix={hasTarget ? `/ ${report?.errorBudgetMinutes.toFixed(1)} 分钟` : undefined}
+                        valueStyle={hasTarget && (report?.errorBudgetRemaining ?? 0) < 0 ? {color: '#cf1322'} : undefined}
+                        loading={loading}
+                    />
+                </Col>
+            </Row>
+
+            <Table
+                columns={columns}
+                dataSource={report?.agents || []}
+                rowKey="agentId"
+                loading={loading}
+                pagination={false}
+                size="small"
+            />
+        </Modal>
+    );
+};
+
+export default SLAReportModal;
@@ -0,0 +1,152 @@
+import {Button, Card, Form, Input, InputNumber, Select, Space} from 'antd';
+import {MinusCircle, PlusCircle} from 'lucide-react';
+import type {MonitorTransactionConfig, MonitorTransactionStep} from '@/types';
+
+const STEP_METHODS = ['GET', 'POST', 'PUT', 'DELETE', 'PATCH', 'HEAD', 'OPTIONS'];
+
+const EXTRACT_SOURCES = [
+    {label: 'JSONPath', value: 'jsonpath'},
+    {label: '正则', value: 'regex'},
+    {label: '响应头', value: 'header'},
+];
+
+// 表单中的步骤，请求头以「每行一个 Key: Value」编辑
+export interface TransactionStepFormValue {
+    name?: string;
+    method?: string;
+    url?: string;
+    headersText?: string;
+    body?: string;
+    acceptedStatusCodes?: string[];
+    expectedContent?: string;
+    extract?: { name?: string; source?: string; expression?: string }[];
+}
+
+// toStepFormValues 将事务配置转换为表单值
+export const toStepFormValues = (config?: MonitorTransactionConfig | null): TransactionStepFormValue[] =>
+    (config?.steps || []).map((step) => ({
+        name: step.name,
+        method: step.method || 'GET',
+        url: step.url,
+        headersText: Object.entries(step.headers || {}).map(([key, value]) => `${key}: ${value}`).join('\n'),
+        body: step.body,
+        acceptedStatusCodes: step.acceptedStatusCodes || (step.expectedStatusCode ? [String(step.expectedStatusCode)] : []),
+        expectedContent: step.expectedContent,
+        extract: step.extract || [],
+    }));
+
+// fromStepFormValues 将表单值转换为事务步骤
+export const fromStepFormValues = (values: TransactionStepFormValue[] = []): MonitorTransactionStep[] =>
+    values.filter((step) => step?.url?.trim()).map((step) => {
+        const headers: Record<string, string> = {};
+        (step.headersText || '').split('\n').forEach((line) => {
+            const index = line.indexOf(':');
+            const key = index > 0 ? line.slice(0, index).trim() : '';
+            if (key) {
+                headers[key] = line.slice(index + 1).trim();
+            }
+        });
+        const method = step.method || 'GET';
+        return {
+            name: step.name?.trim() || undefined,
+            url: step.url!.trim(),
+            method,
+            headers: Object.keys(headers).length > 0 ? headers : undefined,
+            body: method === 'HEAD' ? undefined : step.body || undefined,
+            acceptedStatusCodes: step.acceptedStatusCodes?.length ? step.acceptedStatusCodes : undefined,
+            expectedContent: step.expectedContent?.trim() || undefined,
+            extract: (step.extract || [])
+                .filter((item) => item?.name?.trim())
+                .map((item) => ({
+                    name: item.name!.trim(),
+                    source: (item.source || 'jsonpath') as 'jsonpath' | 'regex' | 'header',
+                    expression: item.expression?.trim() || '',
+                })),
+        };
+    });
+
+const TransactionSteps = () => (
+    <>
+        <Form.Item label="事务超时 (秒)" name="transactionTimeout" initialValue={60} extra="所有步骤的总超时时间">
+            <InputNumber min={1} max={300} style={{width: '100%'}}/>
+        </Form.Item>
+
+        <Form.Item
+            label="步骤"
+            required
+            extra="按顺序执行，各步骤共享 Cookie；前面步骤提取的变量可在后续步骤的地址、请求头、请求体和期望内容中以 {{变量名}} 引用"
+        >
+            <Form.List name="transactionSteps">
+                {(fields, {add, remove}) => (
+                    <div className="space-y-3">
+                        {fields.map(({key, name, ...restField}, index) => (
+                            <Card
+                                key={key}
+                                size="small"
+                                title={`步骤 ${index + 1}`}
+                                extra={<Button type="text" danger icon={<MinusCircle size={16}/>} onClick={() => remove(name)}/>}
+                            >
+                                <Form.Item {...restField} name={[name, 'name']}>
+                                    <Input placeholder="步骤名称，如 登录"/>
+                                </Form.Item>
+                                <Space.Compact style={{width: '100%'}}>
+                                    <Form.Item {...restField} name={[name, 'method']} noStyle initialValue="GET">
+                                        <Select style={{width: 110}} options={STEP_METHODS.map((method) => ({label: method, value: method}))}/>
+                                    </Form.Item>
+                                    <Form.Item
+                                        {...restField}
+                                        name={[name, 'url']}
+                                        noStyle
+                                        rules={[{required: true, message: '请输入请求地址'}]}
+                                    >
+                                        <Input placeholder="https://example.com/api/users/{{uid}}"/>
+                                    </Form.Item>
+                                </Space.Compact>
+                                <Form.Item {...restField} name={[name, 'headersText']} className="!mt-3">
+                                    <Input.TextArea rows={2} placeholder={'请求头，每行一个，如\nAuthorization: Bearer {{token}}'}/>
+                                </Form.Item>
+                                <Form.Item {...restField} name={[name, 'body']}>
+                                    <Input.TextArea rows={2} placeholder='请求体，如 {"username": "monitor"}'/>
+                                </Form.Item>
+                                <Form.Item {...restField} name={[name, 'acceptedStatusCodes']}>
+                                    <Select mode="tags" tokenSeparators={[',', ' ']} open={false} placeholder="可接受的状态码，默认 200，支持 2xx、200-299"/>
+                                </Form.Item>
+                                <Form.Item {...restField} name={[name, 'expectedContent']}>
+                                    <Input placeholder="期望响应内容，可选"/>
+                                </Form.Item>
+                                <Form.List name={[name, 'extract']}>
+                                    {(extractFields, {add: addExtract, remove: removeExtract}) => (
+                                        <div className="space-y-2">
+                                            {extractFields.map(({key: extractKey, name: extractName, ...extractRest}) => (
+                                                <Space key={extractKey} align="baseline" className="flex">
+                                                    <Form.Item {...extractRest} name={[extractName, 'name']} rules={[{required: true, message: '变量名'}]}>
+                                                        <Input placeholder="变量名" style={{width: 100}}/>
+                                                    </Form.Item>
+                                                    <Form.Item {...extractRest} name={[extractName, 'source']} initialValue="jsonpath">
+                                                        <Select style={{width: 100}} options={EXTRACT_SOURCES}/>
+                                                    </Form.Item>
+                                                    <Form.Item {...extractRest} name={[extractName, 'expression']} rules={[{required: true, message: '提取表达式'}]}>
+                                                        <Input placeholder="$.data.token"/>
+                                                    </Form.Item>
+                                                    <Button type="text" danger icon={<MinusCircle size={16}/>} onClick={() => removeExtract(extractName)}/>
+                                                </Space>
+                                            ))}
+                                            <Button type="dashed" size="small" icon={<PlusCircle size={14}/>} onClick={() => addExtract({source: 'jsonpath'})}>
+                                                提取变量
+                                            </Button>
+                                        </div>
+                                    )}
+                                </Form.List>
+                            </Card>
+                        ))}
+                        <Button type="dashed" block icon={<PlusCircle size={16}/>} onClick={() => add({method: 'GET'})}>
+                            添加步骤
+                        </Button>
+                    </div>
+                )}
+            </Form.List>
+        </Form.Item>
+    </>
+);
+
+export default TransactionSteps;
@@ -18,10 +18,12 @@ import {Area, AreaChart, CartesianGrid, Legend, ResponsiveContainer, Tooltip, XA
 import {
     type AggregatedMonitorMetric,
     getMonitorAgentStats,
+    getMonitorCerts,
     getMonitorHistory,
+    getMonitorTraces,
     getMonitorStatsById
 } from '@/api/monitor.ts';
-import type {MonitorStats, PublicMonitor} from '@/types';
+import type {MonitorCert, MonitorStats, MonitorTrace, PublicMonitor} from '@/types';
 import {cn} from '@/lib/utils';
 
 const formatTime = (ms: number): string => {
@@ -368,6 +370,30 @@ const MonitorDetail = () => {
         enabled: !!id,
     });
 
+    // 获取各探针检测到的证书详情
+    const {data: monitorCerts = []} = useQuery<MonitorCert[]>({
+        queryKey: ['monitorCerts', id],
+        queryFn: async () => {
+            if (!id) return [];
+            const response = await getMonitorCerts(id);
+            return response.data || [];
+        },
+        refetchInterval: 60000,
+        enabled: !!id,
+    });
+
+    // 获取最近的离线路径追踪
+    const {data: monitorTraces = []} = useQuery<MonitorTrace[]>({
+        queryKey: ['monitorTraces', id],
+        queryFn: async () => {
+            if (!id) return [];
+            const response = await getMonitorTraces(id);
+            return response.data || [];
+        },
+        refetchInterval: 60000,
+        enabled: !!id,
+    });
+
     // 获取历史数据
     const {data: historyData = []} = useQuery<AggregatedMonitorMetric[]>({
         queryKey: ['monitorHistory', id, timeRange],
@@ -440,6 +466,9 @@ const MonitorDetail = () => {
     const certExpired = hasCert && monitorDetail.certExpiryDays < 0;
     const certExpiringSoon = hasCert && monitorDetail.certExpiryDays >= 0 && monitorDetail.certExpiryDays < 30;
 
+    // 探针都未设置地区时不展示地区统计
+    const regions = (monitorDetail.regions || []).some((region) => region.region !== '') ? monitorDetail.regions || [] : [];
+
     const heroStats = [
         {label: '监控类型', value: monitorDetail.type.toUpperCase()},
         {label: '探针数量', value: `${monitorDetail.agentCount} 个`},
@@ -539,6 +568,27 @@ const MonitorDetail = () => {
                             />
                         </div>
 
+                        {/* 响应时间分位数，多个探针时取最慢的探针 */}
+                        <div className="mt-6 grid gap-4 sm:grid-cols-2">
+                            {[
+                                {label: '24h', p50: monitorDetail.p50Response24h, p95: monitorDetail.p95Response24h, p99: monitorDetail.p99Response24h},
+                                {label: '30d', p50: monitorDetail.p50Response30d, p95: monitorDetail.p95Response30d, p99: monitorDetail.p99Response30d},
+                            ].map((item) => (
+                                <div key={item.label}
+                                     className="rounded-2xl border border-slate-200 dark:border-slate-700 p-4">
+                                    <p className="text-xs text-slate-500 dark:text-slate-400">{item.label} 响应时间分位数</p>
+                                    <div className="mt-2 grid grid-cols-3 gap-2 text-sm">
+                                        {(['p50', 'p95', 'p99'] as const).map((key) => (
+                                            <div key={key}>
+                                                <span className="text-xs uppercase text-slate-500 dark:text-slate-400">{key}</span>
+                                                <p className="font-semibold text-slate-900 dark:text-white">{formatTime(item[key])}</p>
+                                            </div>
+                                        ))}
+                                    </div>
+                                </div>
+                            ))}
+                        </div>
+
                         {/* 当前错误信息 */}
                         {monitorDetail.lastCheckStatus === 'down' && monitorDetail.lastCheckError && (
                             <div
@@ -720,6 +770,143 @@ const MonitorDetail = () => {
                         )}
                     </Card>
 
+                    {/* 按探针地区统计 */}
+                    {regions.length > 0 && (
+                        <Card title="地区响应时间" description="按探针所在地区汇总的响应时间和在线率">
+                            <div className="grid gap-4 sm:grid-cols-2 lg:grid-cols-4">
+                                {regions.map((region) => (
+                                    <div key={region.region}
+                                         className="rounded-2xl border border-slate-200 dark:border-slate-700 p-4 space-y-2">
+                                        <div className="flex items-center justify-between">
+                                            <span className="font-medium text-slate-900 dark:text-white">
+                                                {region.region || '未设置地区'}
+                                            </span>
+                                            <span className="text-xs text-slate-500 dark:text-slate-400">
+                                                {region.upCount}/{region.agentCount} 正常
+                                            </span>
+                                        </div>
+                                        <div className="flex justify-between text-xs text-slate-500 dark:text-slate-400">
+                                            <span>当前响应</span>
+                                            <span className="font-medium text-slate-900 dark:text-white">{formatTime(region.currentResponse)}</span>
+                                        </div>
+                                        <div className="flex justify-between text-xs text-slate-500 dark:text-slate-400">
+                                            <span>24h 平均响应</span>
+                                            <span className="font-medium text-slate-900 dark:text-white">{formatTime(region.avgResponse24h)}</span>
+                                        </div>
+                                        <div className="flex justify-between text-xs text-slate-500 dark:text-slate-400">
+                                            <span>24h 在线率</span>
+                                            <span className="font-medium text-slate-900 dark:text-white">{formatPercentValue(region.uptime24h)}%</span>
+                                        </div>
+                                    </div>
+                                ))}
+                            </div>
+                        </Card>
+                    )}
+
+                    {/* 证书详情 */}
+                    {monitorCerts.length > 0 && (
+                        <Card title="证书详情" description="各探针检测到的证书信息和证书链校验结果">
+                            <div className="grid gap-4 lg:grid-cols-2">
+                                {monitorCerts.map((cert) => (
+                                    <div key={cert.id}
+                                         className="rounded-2xl border border-slate-200 dark:border-slate-700 p-4 space-y-2 text-xs text-slate-500 dark:text-slate-400">
+                                        <div className="flex items-center justify-between">
+                                            <span className="font-medium text-sm text-slate-900 dark:text-white">
+                                                {cert.agentName || cert.agentId}
+                                            </span>
+                                            {cert.chainValid ? (
+                                                <span className="text-emerald-600 dark:text-emerald-400">证书链有效</span>
+                                            ) : (
+                                                <span className="text-red-600 dark:text-red-400">证书链无效</span>
+                                            )}
+                                        </div>
+                                        {!cert.chainValid && cert.chainError && (
+                                            <div className="text-red-600 dark:text-red-400 break-all">{cert.chainError}</div>
+                                        )}
+                                        <div className="flex justify-between gap-4">
+                                            <span className="whitespace-nowrap">使用者</span>
+                                            <span className="text-right break-all text-slate-900 dark:text-white">{cert.subject}</span>
+                                        </div>
+                                        <div className="flex justify-between gap-4">
+                                            <span className="whitespace-nowrap">签发者</span>
+                                            <span className="text-right break-all text-slate-900 dark:text-white">{cert.issuer}</span>
+                                        </div>
+                                        <div className="flex justify-between gap-4">
+                                            <span className="whitespace-nowrap">备用名称</span>
+                                            <span className="text-right break-all text-slate-900 dark:text-white">{(cert.sans || []).join(', ') || '-'}</span>
+                                        </div>
+                                        <div className="flex justify-between gap-4">
+                                            <span className="whitespace-nowrap">签名算法</span>
+                                            <span className="text-slate-900 dark:text-white">{cert.signatureAlgorithm}</span>
+                                        </div>
+                                        <div className="flex justify-between gap-4">
+                                            <span className="whitespace-nowrap">有效期</span>
+                                            <span className="text-right text-slate-900 dark:text-white">
+                                                {formatDate(cert.notBefore)} ~ {formatDate(cert.notAfter)}
+                                            </span>
+                                        </div>
+                                        <div className="flex justify-between gap-4">
+                                            <span className="whitespace-nowrap">证书链长度</span>
+                                            <span className="text-slate-900 dark:text-white">{cert.chainLength}</span>
+                                        </div>
+                                        <div className="flex justify-between gap-4">
+                                            <span className="whitespace-nowrap">SHA-256 指纹</span>
+                                            <span className="text-right break-all font-mono text-slate-900 dark:text-white">{cert.fingerprint}</span>
+                                        </div>
+                                    </div>
+                                ))}
+                            </div>
+                        </Card>
+                    )}
+
+                    {/* 离线路径追踪 */}
+                    {monitorTraces.length > 0 && (
+                        <Card title="离线路径追踪" description="监控项变为离线时探针对目标执行的 mtr / traceroute，用于区分网络故障和服务故障">
+                            <div className="space-y-4">
+                                {monitorTraces.slice(0, 10).map((trace) => (
+                                    <div key={trace.id}
+                                         className="rounded-2xl border border-slate-200 dark:border-slate-700 p-4 space-y-2 text-xs text-slate-500 dark:text-slate-400">
+                                        <div className="flex flex-wrap items-center justify-between gap-2">
+                                            <span className="font-medium text-sm text-slate-900 dark:text-white">
+                                                {trace.agentName || trace.agentId} → {trace.host}
+                                            </span>
+                                            <span>{formatDateTime(trace.checkedAt)}{trace.tool && ` · ${trace.tool}`}</span>
+                                        </div>
+                                        {trace.checkError && (
+                                            <div className="text-red-600 dark:text-red-400 break-all">{trace.checkError}</div>
+                                        )}
+                                        {trace.error ? (
+                                            <div>追踪失败: {trace.error}</div>
+                                        ) : (
+                                            <table className="min-w-full">
+                                                <thead>
+                                                <tr className="text-left">
+                                                    <th className="py-1 pr-4 font-medium">跳</th>
+                                                    <th className="py-1 pr-4 font-medium">地址</th>
+                                                    <th className="py-1 pr-4 font-medium">丢包率</th>
+                                                    <th className="py-1 font-medium">平均延迟</th>
+                                                </tr>
+                                                </thead>
+                                                <tbody>
+                                                {(trace.hops || []).map((hop) => (
+                                                    <tr key={hop.hop} className="text-slate-900 dark:text-white">
+                                                        <td className="py-1 pr-4">{hop.hop}</td>
+                                                        <td className="py-1 pr-4 font-mono">{hop.host || '*'}</td>
+                                                        <td className={cn('py-1 pr-4', hop.loss > 0 && 'text-red-600 dark:text-red-400')}>
+                                                            {hop.loss.toFixed(1)}%
+                                                        </td>
+                                                        <td className="py-1">{hop.avgRtt ? `${hop.avgRtt.toFixed(1)} ms` : '-'}</td>
+                                                    </tr>
+                                                ))}
+                                                </tbody>
+                                            </table>
+                                        )}
+                                    </div>
+                                ))}
+                            </div>
+                        </Card>
+                    )}
+
                     {/* 各探针详细数据 */}
                     <Card title="探针监控详情" description="各探针的当前状态和统计数据">
                         <div className="overflow-x-auto -mx-6 sm:mx-0">
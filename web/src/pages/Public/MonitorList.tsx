@@ -2,8 +2,8 @@ import {useEffect} from 'react';
 import {useNavigate} from 'react-router-dom';
 import {useQuery} from '@tanstack/react-query';
 import {AlertCircle, CheckCircle2, Clock, Loader2, Shield} from 'lucide-react';
-import {getPublicMonitors} from '@/api/monitor.ts';
-import type {PublicMonitor} from '@/types';
+import {getMonitorGroups, getPublicMonitors} from '@/api/monitor.ts';
+import type {MonitorGroup, PublicMonitor} from '@/types';
 import {usePublicLayout} from '../PublicLayout';
 import {cn} from '@/lib/utils';
 
@@ -75,6 +75,36 @@ const UptimeBar = ({uptime}: { uptime: number }) => {
     );
 };
 
+const groupStatusClass: Record<MonitorGroup['status'], string> = {
+    up: 'bg-emerald-500',
+    degraded: 'bg-yellow-500',
+    down: 'bg-red-500',
+    unknown: 'bg-slate-400',
+};
+
+// 监控分组概要：组内在线数量和平均可用率
+const GroupSummary = ({groups}: { groups: MonitorGroup[] }) => (
+    <div className="grid grid-cols-1 gap-3 sm:grid-cols-2 lg:grid-cols-4">
+        {groups.map((group) => (
+            <div key={group.name}
+                 className="flex flex-col gap-2 rounded-2xl border border-slate-200 dark:border-slate-700 p-4">
+                <div className="flex items-center gap-2">
+                    <span className={cn("h-2.5 w-2.5 rounded-full", groupStatusClass[group.status])}/>
+                    <span className="font-medium text-slate-900 dark:text-white truncate">{group.name}</span>
+                    <span className="ml-auto text-xs text-slate-500 dark:text-slate-400">
+                        {group.upCount}/{group.monitorCount} 正常
+                    </span>
+                </div>
+                <div className="flex items-center justify-between text-xs text-slate-500 dark:text-slate-400">
+                    <span>24h 可用率</span>
+                    <span className="font-medium text-slate-900 dark:text-white">{formatPercentValue(group.uptime24h)}%</span>
+                </div>
+                <UptimeBar uptime={group.uptime24h}/>
+            </div>
+        ))}
+    </div>
+);
+
 const EmptyState = () => (
     <div className="flex min-h-[400px] flex-col items-center justify-center text-slate-500 dark:text-slate-400">
         <Shield className="mb-4 h-16 w-16 opacity-20"/>
@@ -102,6 +132,15 @@ const MonitorList = () => {
         refetchInterval: 30000, // 30秒刷新一次
     });
 
+    const {data: groups = []} = useQuery<MonitorGroup[]>({
+        queryKey: ['publicMonitorGroups'],
+        queryFn: async () => {
+            const response = await getMonitorGroups();
+            return response.data || [];
+        },
+        refetchInterval: 30000,
+    });
+
     const monitorSummaries = monitors;
 
     const renderGridView = () => (
@@ -395,6 +434,7 @@ const MonitorList = () => {
 
     return (
         <div className="mx-auto max-w-7xl px-4 sm:px-6 lg:px-8 py-8 space-y-6">
+            {groups.length > 0 && <GroupSummary groups={groups}/>}
             {monitorSummaries.length === 0 ? (
                 <EmptyState/>
             ) : viewMode === 'grid' ? (
@@ -35,7 +35,10 @@ import {
 import {type TimeRangeOption} from '@/api/property.ts';
 import type {
     Agent,
+    AggregatedCPUCoreMetric,
     AggregatedCPUMetric,
+    AggregatedCPUTimesMetric,
+    AggregatedPressureMetric,
     AggregatedDiskIOMetric,
     AggregatedGPUMetric,
     AggregatedMemoryMetric,
@@ -254,6 +257,9 @@ type MetricsState = {
     diskIO: AggregatedDiskIOMetric[];
     gpu: AggregatedGPUMetric[];
     temperature: AggregatedTemperatureMetric[];
+    cpuCore: AggregatedCPUCoreMetric[];
+    cpuTimes: AggregatedCPUTimesMetric[];
+    pressure: AggregatedPressureMetric[];
 };
 
 const createEmptyMetricsState = (): MetricsState => ({
@@ -265,6 +271,9 @@ const createEmptyMetricsState = (): MetricsState => ({
     diskIO: [],
     gpu: [],
     temperature: [],
+    cpuCore: [],
+    cpuTimes: [],
+    pressure: [],
 });
 
 const metricRequestConfig: Array<{ key: keyof MetricsState; type: GetAgentMetricsRequest['type'] }> = [
@@ -276,6 +285,9 @@ const metricRequestConfig: Array<{ key: keyof MetricsState; type: GetAgentMetric
     {key: 'diskIO', type: 'disk_io'},
     {key: 'gpu', type: 'gpu'},
     {key: 'temperature', type: 'temperature'},
+    {key: 'cpuCore', type: 'cpu_core'},
+    {key: 'cpuTimes', type: 'cpu_times'},
+    {key: 'pressure', type: 'pressure'},
 ];
 
 const useAgentOverview = (agentId?: string) => {
@@ -504,6 +516,7 @@ const timeRangeOptions = [
     {label: '1天', value: '1d'},
     {label: '3天', value: '3d'},
     {label: '7天', value: '7d'},
+    {label: '30天', value: '30d'},
 ]
 
 const ServerDetail = () => {
@@ -723,6 +736,68 @@ const ServerDetail = () => {
         }
     }, [temperatureTypes, selectedTempType]);
 
+    // 每核 CPU 图表数据（每个核心一条线）
+    const cpuCoreChartData = useMemo(() => {
+        const aggregated: Record<string, any> = {};
+
+        metricsData.cpuCore.forEach((item) => {
+            const time = new Date(item.timestamp).toLocaleTimeString('zh-CN', {
+                hour: '2-digit',
+                minute: '2-digit',
+            });
+
+            if (!aggregated[time]) {
+                aggregated[time] = {time, timestamp: item.timestamp};
+            }
+            aggregated[time][`core${item.core}`] = Number(item.maxUsage.toFixed(2));
+        });
+
+        return Object.values(aggregated);
+    }, [metricsData.cpuCore]);
+
+    // 提取所有核心编号
+    const cpuCores = useMemo(() => {
+        const cores = new Set<number>();
+        metricsData.cpuCore.forEach((item) => cores.add(item.core));
+        return Array.from(cores).sort((a, b) => a - b);
+    }, [metricsData.cpuCore]);
+
+    // CPU 时间分布图表数据（各状态的平均占比）
+    const cpuTimesChartData = useMemo(
+        () =>
+            metricsData.cpuTimes.map((item) => ({
+                time: new Date(item.timestamp).toLocaleTimeString('zh-CN', {
+                    hour: '2-digit',
+                    minute: '2-digit',
+                }),
+                user: Number(item.avgUser.toFixed(2)),
+                system: Number(item.avgSystem.toFixed(2)),
+                iowait: Number(item.avgIowait.toFixed(2)),
+                steal: Number(item.avgSteal.toFixed(2)),
+                timestamp: item.timestamp,
+            })),
+        [metricsData.cpuTimes]
+    );
+
+    // PSI 压力图表数据（每种资源一条线，取 some 的最大值）
+    const pressureChartData = useMemo(() => {
+        const aggregated: Record<string, any> = {};
+
+        metricsData.pressure.forEach((item) => {
+            const time = new Date(item.timestamp).toLocaleTimeString('zh-CN', {
+                hour: '2-digit',
+                minute: '2-digit',
+            });
+
+            if (!aggregated[time]) {
+                aggregated[time] = {time, timestamp: item.timestamp};
+            }
+            aggregated[time][item.resource] = Number(item.maxSome.toFixed(2));
+        });
+
+        return Object.values(aggregated);
+    }, [metricsData.pressure]);
+
     // 温度类型颜色映射
     const temperatureColors: Record<string, string> = {
         'CPU': '#f97316',      // 橙色
@@ -1440,6 +1515,158 @@ const ServerDetail = () => {
                                 </section>
                             )}
 
+                            {cpuCoreChartData.length > 0 && cpuCores.length > 0 && (
+                                <section>
+                                    <div className="mb-3 flex items-center justify-between">
+                                        <h3 className="flex items-center gap-2 text-sm font-semibold text-slate-700 dark:text-white">
+                                            <span
+                                                className="flex h-8 w-8 items-center justify-center rounded-lg  text-slate-700 dark:text-slate-300">
+                                                <Cpu className="h-4 w-4"/>
+                                            </span>
+                                            每核 CPU 使用率
+                                        </h3>
+                                    </div>
+                                    <ResponsiveContainer width="100%" height={220}>
+                                        <LineChart data={cpuCoreChartData}>
+                                            <CartesianGrid stroke="currentColor" strokeDasharray="4 4"
+                                                           className="stroke-slate-200 dark:stroke-slate-600"/>
+                                            <XAxis
+                                                dataKey="time"
+                                                stroke="currentColor"
+                                                className="stroke-slate-400 dark:stroke-slate-500"
+                                                style={{fontSize: '12px'}}
+                                            />
+                                            <YAxis
+                                                domain={[0, 100]}
+                                                stroke="currentColor"
+                                                className="stroke-slate-400 dark:stroke-slate-500"
+                                                style={{fontSize: '12px'}}
+                                                tickFormatter={(value) => `${value}%`}
+                                            />
+                                            <Tooltip content={<CustomTooltip unit="%"/>}/>
+                                            <Legend/>
+                                            {cpuCores.map((core, index) => (
+                                                <Line
+                                                    key={core}
+                                                    type="monotone"
+                                                    dataKey={`core${core}`}
+                                                    name={`CPU ${core}`}
+                                                    stroke={`hsl(${(index * 47) % 360}, 70%, 50%)`}
+                                                    strokeWidth={1.5}
+                                                    dot={false}
+                                                    activeDot={{r: 3}}
+                                                    connectNulls
+                                                />
+                                            ))}
+                                        </LineChart>
+                                    </ResponsiveContainer>
+                                </section>
+                            )}
+
+                            {cpuTimesChartData.length > 0 && (
+                                <section>
+                                    <div className="mb-3 flex items-center justify-between">
+                                        <h3 className="flex items-center gap-2 text-sm font-semibold text-slate-700 dark:text-white">
+                                            <span
+                                                className="flex h-8 w-8 items-center justify-center rounded-lg  text-slate-700 dark:text-slate-300">
+                                                <Cpu className="h-4 w-4"/>
+                                            </span>
+                                            CPU 时间分布
+                                        </h3>
+                                    </div>
+                                    <ResponsiveContainer width="100%" height={220}>
+                                        <AreaChart data={cpuTimesChartData}>
+                                            <CartesianGrid stroke="currentColor" strokeDasharray="4 4"
+                                                           className="stroke-slate-200 dark:stroke-slate-600"/>
+                                            <XAxis
+                                                dataKey="time"
+                                                stroke="currentColor"
+                                                className="stroke-slate-400 dark:stroke-slate-500"
+                                                style={{fontSize: '12px'}}
+                                            />
+                                            <YAxis
+                                                domain={[0, 100]}
+                                                stroke="currentColor"
+                                                className="stroke-slate-400 dark:stroke-slate-500"
+                                                style={{fontSize: '12px'}}
+                                                tickFormatter={(value) => `${value}%`}
+                                            />
+                                            <Tooltip content={<CustomTooltip unit="%"/>}/>
+                                            <Legend/>
+                                            {([
+                                                ['user', 'user', '#2563eb'],
+                                                ['system', 'system', '#16a34a'],
+                                                ['iowait', 'iowait', '#f97316'],
+                                                ['steal', 'steal', '#dc2626'],
+                                            ] as const).map(([key, name, color]) => (
+                                                <Area
+                                                    key={key}
+                                                    type="monotone"
+                                                    dataKey={key}
+                                                    name={name}
+                                                    stackId="cpuTimes"
+                                                    stroke={color}
+                                                    fill={color}
+                                                    fillOpacity={0.3}
+                                                    activeDot={{r: 3}}
+                                                />
+                                            ))}
+                                        </AreaChart>
+                                    </ResponsiveContainer>
+                                </section>
+                            )}
+
+                            {pressureChartData.length > 0 && (
+                                <section>
+                                    <div className="mb-3 flex items-center justify-between">
+                                        <h3 className="flex items-center gap-2 text-sm font-semibold text-slate-700 dark:text-white">
+                                            <span
+                                                className="flex h-8 w-8 items-center justify-center rounded-lg  text-slate-700 dark:text-slate-300">
+                                                <Cpu className="h-4 w-4"/>
+                                            </span>
+                                            PSI 压力（some）
+                                        </h3>
+                                    </div>
+                                    <ResponsiveContainer width="100%" height={220}>
+                                        <LineChart data={pressureChartData}>
+                                            <CartesianGrid stroke="currentColor" strokeDasharray="4 4"
+                                                           className="stroke-slate-200 dark:stroke-slate-600"/>
+                                            <XAxis
+                                                dataKey="time"
+                                                stroke="currentColor"
+                                                className="stroke-slate-400 dark:stroke-slate-500"
+                                                style={{fontSize: '12px'}}
+                                            />
+                                            <YAxis
+                                                stroke="currentColor"
+                                                className="stroke-slate-400 dark:stroke-slate-500"
+                                                style={{fontSize: '12px'}}
+                                                tickFormatter={(value) => `${value}%`}
+                                            />
+                                            <Tooltip content={<CustomTooltip unit="%"/>}/>
+                                            <Legend/>
+                                            {([
+                                                ['cpu', 'CPU', '#2563eb'],
+                                                ['memory', '内存', '#16a34a'],
+                                                ['io', 'IO', '#f97316'],
+                                            ] as const).map(([key, name, color]) => (
+                                                <Line
+                                                    key={key}
+                                                    type="monotone"
+                                                    dataKey={key}
+                                                    name={name}
+                                                    stroke={color}
+                                                    strokeWidth={1.5}
+                                                    dot={false}
+                                                    activeDot={{r: 3}}
+                                                    connectNulls
+                                                />
+                                            ))}
+                                        </LineChart>
+                                    </ResponsiveContainer>
+                                </section>
+                            )}
+
                             {temperatureChartData.length > 0 && temperatureTypes.length > 0 && (
                                 <section>
                                     <div className="mb-3 flex items-center justify-between">
@@ -1,10 +1,35 @@
 import {useEffect} from 'react';
-import {App, Button, Card, Form, InputNumber, Space, Switch} from 'antd';
+import {App, Button, Card, Form, InputNumber, Select, Space, Switch} from 'antd';
+import {MinusCircle, PlusCircle} from 'lucide-react';
 import {useMutation, useQuery, useQueryClient} from '@tanstack/react-query';
 import type {AlertConfig} from '@/api/property';
 import {getAlertConfig, saveAlertConfig} from '@/api/property';
+import {getTags} from '@/api/agent.ts';
+import {getMonitorGroups} from '@/api/monitor.ts';
 import {getErrorMessage} from '@/lib/utils';
 
+// 支持配置恢复冷却期的告警类型
+const cooldownTypes = [
+    {key: 'cpu', label: 'CPU'},
+    {key: 'memory', label: '内存'},
+    {key: 'disk', label: '磁盘'},
+    {key: 'network', label: '网速'},
+    {key: 'cert', label: 'HTTPS 证书'},
+    {key: 'cert_chain', label: '证书链'},
+    {key: 'service', label: '服务下线'},
+    {key: 'agent_offline', label: '探针离线'},
+    {key: 'wireguard', label: 'WireGuard'},
+    {key: 'mount', label: '挂载点'},
+    {key: 'inode', label: 'inode'},
+    {key: 'fd', label: '文件描述符'},
+    {key: 'steal', label: 'CPU steal'},
+    {key: 'clock', label: '时钟偏差'},
+    {key: 'port', label: '监听端口'},
+    {key: 'kernel', label: '内核事件'},
+    {key: 'group', label: '分组告警'},
+    {key: 'monitor_group', label: '监控分组告警'},
+];
+
 const AlertSettings = () => {
     const [form] = Form.useForm();
     const {message: messageApi} = App.useApp();
@@ -16,6 +41,18 @@ const AlertSettings = () => {
         queryFn: getAlertConfig,
     });
 
+    // 分组告警规则使用探针标签作为分组
+    const {data: tags = []} = useQuery({
+        queryKey: ['agentTags'],
+        queryFn: async () => (await getTags()).data.tags || [],
+    });
+
+    // 监控分组告警规则使用监控项的分组
+    const {data: monitorGroups = []} = useQuery({
+        queryKey: ['monitorGroups'],
+        queryFn: async () => ((await getMonitorGroups()).data || []).map((group) => group.name),
+    });
+
     // 设置表单默认值
     useEffect(() => {
         if (configData) {
@@ -37,7 +74,8 @@ const AlertSettings = () => {
 
     const handleSubmit = async () => {
         const values = await form.validateFields();
-        saveMutation.mutate(values as AlertConfig);
+        // 保留表单中未展示的配置项（如修复动作）
+        saveMutation.mutate({...configData, ...values} as AlertConfig);
     };
 
     return (
@@ -57,6 +95,10 @@ const AlertSettings = () => {
                         {key: 'memory', title: '内存告警规则', thresholdLabel: '内存使用率阈值 (%)', max: 100},
                         {key: 'disk', title: '磁盘告警规则', thresholdLabel: '磁盘使用率阈值 (%)', max: 100},
                         {key: 'network', title: '网速告警规则', thresholdLabel: '网速阈值 (MB/s)', max: 10000},
+                        {key: 'inode', title: 'inode 告警规则', thresholdLabel: 'inode 使用率阈值 (%)', max: 100},
+                        {key: 'fd', title: '文件描述符告警规则', thresholdLabel: '文件描述符使用率阈值 (%)', max: 100},
+                        {key: 'steal', title: 'CPU steal 告警规则', thresholdLabel: 'steal 占比阈值 (%)', max: 100},
+                        {key: 'clock', title: '时钟偏差告警规则', thresholdLabel: '时钟偏差阈值 (ms)', max: 3600000},
                     ].map((rule) => (
                         <Card key={rule.key} title={rule.title} type="inner">
                             <Form.Item noStyle shouldUpdate>
@@ -113,6 +155,15 @@ const AlertSettings = () => {
                                         >
                                             <Switch/>
                                         </Form.Item>
+                                        <Form.Item
+                                            label="证书链校验失败"
+                                            name={['rules', 'certChainEnabled']}
+                                            valuePropName="checked"
+                                            className="mb-0"
+                                            tooltip="证书不受信任、缺少中间证书或域名不匹配时告警，证书未过期也会触发"
+                                        >
+                                            <Switch/>
+                                        </Form.Item>
                                         <Form.Item
                                             label="证书剩余天数阈值（天）"
                                             name={['rules', 'certThreshold']}
@@ -198,6 +249,313 @@ const AlertSettings = () => {
                         </Form.Item>
                     </Card>
 
+                    <Card title="WireGuard 告警规则" type="inner">
+                        <Form.Item noStyle shouldUpdate>
+                            {({getFieldValue}) => {
+                                const enabled = getFieldValue(['rules', 'wireGuardEnabled']);
+                                return (
+                                    <div className="flex items-center gap-8">
+                                        <Form.Item
+                                            label="开关"
+                                            name={['rules', 'wireGuardEnabled']}
+                                            valuePropName="checked"
+                                            className="mb-0"
+                                        >
+                                            <Switch/>
+                                        </Form.Item>
+                                        <Form.Item
+                                            label="握手超时（秒）"
+                                            name={['rules', 'wireGuardHandshakeThreshold']}
+                                            className="mb-0"
+                                            tooltip="WireGuard 对端超过多久未完成握手后触发告警，正常通信的对端约每 2 分钟握手一次"
+                                        >
+                                            <InputNumber
+                                                min={180}
+                                                max={86400}
+                                                style={{width: '100%'}}
+                                                disabled={!enabled}
+                                            />
+                                        </Form.Item>
+                                    </div>
+                                );
+                            }}
+                        </Form.Item>
+                    </Card>
+
+                    <Card title="挂载点告警规则" type="inner">
+                        <Form.Item noStyle shouldUpdate>
+                            {({getFieldValue}) => {
+                                const enabled = getFieldValue(['rules', 'mountEnabled']);
+                                return (
+                                    <div className="flex items-center gap-8">
+                                        <Form.Item
+                                            label="开关"
+                                            name={['rules', 'mountEnabled']}
+                                            valuePropName="checked"
+                                            className="mb-0"
+                                        >
+                                            <Switch/>
+                                        </Form.Item>
+                                        <Form.Item
+                                            label="持续时间（秒）"
+                                            name={['rules', 'mountDuration']}
+                                            className="mb-0"
+                                            tooltip="探针配置的网络挂载点（collector.mounts）持续挂起、句柄失效或未挂载多久后触发告警"
+                                        >
+                                            <InputNumber
+                                                min={10}
+                                                max={3600}
+                                                style={{width: '100%'}}
+                                                disabled={!enabled}
+                                            />
+                                        </Form.Item>
+                                    </div>
+                                );
+                            }}
+                        </Form.Item>
+                    </Card>
+
+                    <Card title="监听端口告警规则" type="inner">
+                        <Form.Item noStyle shouldUpdate>
+                            {({getFieldValue}) => {
+                                const enabled = getFieldValue(['rules', 'portEnabled']);
+                                return (
+                                    <div className="flex flex-wrap items-center gap-8">
+                                        <Form.Item
+                                            label="开关"
+                                            name={['rules', 'portEnabled']}
+                                            valuePropName="checked"
+                                            className="mb-0"
+                                        >
+                                            <Switch/>
+                                        </Form.Item>
+                                        <Form.Item
+                                            label="持续时间（秒）"
+                                            name={['rules', 'portDuration']}
+                                            className="mb-0"
+                                            tooltip="端口持续监听多久后触发告警，避免临时端口误报"
+                                        >
+                                            <InputNumber
+                                                min={0}
+                                                max={86400}
+                                                style={{width: '100%'}}
+                                                disabled={!enabled}
+                                            />
+                                        </Form.Item>
+                                        <Form.Item
+                                            label="允许的端口"
+                                            name={['rules', 'portAllowed']}
+                                            className="mb-0 min-w-[320px] flex-1"
+                                            tooltip="非回环地址上不在列表中的监听端口会触发告警，格式为 22、tcp/443 或 udp/53，不带协议时 TCP 和 UDP 都允许"
+                                        >
+                                            <Select
+                                                mode="tags"
+                                                tokenSeparators={[',', ' ']}
+                                                placeholder="tcp/22"
+                                                disabled={!enabled}
+                                            />
+                                        </Form.Item>
+                                    </div>
+                                );
+                            }}
+                        </Form.Item>
+                    </Card>
+
+                    <Card title="内核事件告警规则" type="inner">
+                        <Form.Item noStyle shouldUpdate>
+                            {({getFieldValue}) => {
+                                const enabled = getFieldValue(['rules', 'kernelEnabled']);
+                                return (
+                                    <div className="flex flex-wrap items-center gap-8">
+                                        <Form.Item
+                                            label="开关"
+                                            name={['rules', 'kernelEnabled']}
+                                            valuePropName="checked"
+                                            className="mb-0"
+                                            tooltip="需要探针开启 collector.kernel_log"
+                                        >
+                                            <Switch/>
+                                        </Form.Item>
+                                        <Form.Item
+                                            label="事件分类"
+                                            name={['rules', 'kernelCategories']}
+                                            className="mb-0 min-w-[320px] flex-1"
+                                            tooltip="为空时所有分类都会触发告警"
+                                        >
+                                            <Select
+                                                mode="multiple"
+                                                options={[
+                                                    {label: 'OOM', value: 'oom'},
+                                                    {label: 'I/O 错误', value: 'io'},
+                                                    {label: '硬件故障', value: 'hardware'},
+                                                ]}
+                                                disabled={!enabled}
+                                            />
+                                        </Form.Item>
+                                        <Form.Item
+                                            label="恢复时间（秒）"
+                                            name={['rules', 'kernelRecovery']}
+                                            className="mb-0"
+                                            tooltip="同类事件在该时间内不再出现时告警恢复"
+                                        >
+                                            <InputNumber
+                                                min={60}
+                                                max={86400}
+                                                style={{width: '100%'}}
+                                                disabled={!enabled}
+                                            />
+                                        </Form.Item>
+                                    </div>
+                                );
+                            }}
+                        </Form.Item>
+                    </Card>
+
+                    <Card title="恢复冷却期" type="inner">
+                        <p className="mb-4 text-sm text-gray-500">
+                            告警恢复后在冷却期内再次触发时，合并到上一条告警记录而不是新建记录，减少阈值附近抖动的指标产生的记录；再次触发时仍会发送通知（注明再次触发）并执行修复动作。0 表示不合并
+                        </p>
+                        <div className="grid grid-cols-2 gap-x-8 md:grid-cols-3">
+                            {cooldownTypes.map((item) => (
+                                <Form.Item
+                                    key={item.key}
+                                    label={`${item.label}（秒）`}
+                                    name={['rules', 'cooldowns', item.key]}
+                                >
+                                    <InputNumber min={0} max={86400} style={{width: '100%'}} placeholder="0"/>
+                                </Form.Item>
+                            ))}
+                        </div>
+                    </Card>
+
+                    <Card title="分组告警规则" type="inner">
+                        <Form.List name="groupRules">
+                            {(fields, {add, remove}) => (
+                                <div className="space-y-2">
+                                    {fields.map(({key, name, ...restField}) => (
+                                        <Space key={key} align="baseline" wrap>
+                                            <Form.Item {...restField} name={[name, 'enabled']} valuePropName="checked" className="mb-0">
+                                                <Switch/>
+                                            </Form.Item>
+                                            <Form.Item
+                                                {...restField}
+                                                name={[name, 'tag']}
+                                                className="mb-0"
+                                                rules={[{required: true, message: '请选择分组'}]}
+                                            >
+                                                <Select
+                                                    placeholder="分组标签"
+                                                    style={{width: 160}}
+                                                    options={tags.map((tag) => ({label: tag, value: tag}))}
+                                                />
+                                            </Form.Item>
+                                            <Form.Item {...restField} name={[name, 'aggregation']} className="mb-0">
+                                                <Select
+                                                    style={{width: 100}}
+                                                    options={[
+                                                        {label: '平均', value: 'avg'},
+                                                        {label: '最高', value: 'max'},
+                                                        {label: '最低', value: 'min'},
+                                                        {label: '合计', value: 'sum'},
+                                                    ]}
+                                                />
+                                            </Form.Item>
+                                            <Form.Item {...restField} name={[name, 'metric']} className="mb-0">
+                                                <Select
+                                                    style={{width: 140}}
+                                                    options={[
+                                                        {label: 'CPU 使用率 (%)', value: 'cpu'},
+                                                        {label: '内存使用率 (%)', value: 'memory'},
+                                                        {label: '磁盘使用率 (%)', value: 'disk'},
+                                                        {label: '网速 (MB/s)', value: 'network'},
+                                                    ]}
+                                                />
+                                            </Form.Item>
+                                            <Form.Item {...restField} name={[name, 'threshold']} label="阈值" className="mb-0">
+                                                <InputNumber min={0} max={100000}/>
+                                            </Form.Item>
+                                            <Form.Item {...restField} name={[name, 'duration']} label="持续（秒）" className="mb-0">
+                                                <InputNumber min={0} max={86400}/>
+                                            </Form.Item>
+                                            <Button
+                                                type="text"
+                                                danger
+                                                icon={<MinusCircle size={16}/>}
+                                                onClick={() => remove(name)}
+                                            />
+                                        </Space>
+                                    ))}
+                                    <Button
+                                        type="dashed"
+                                        block
+                                        icon={<PlusCircle size={16}/>}
+                                        onClick={() => add({
+                                            enabled: true,
+                                            aggregation: 'avg',
+                                            metric: 'cpu',
+                                            threshold: 80,
+                                            duration: 300,
+                                        })}
+                                    >
+                                        添加分组规则
+                                    </Button>
+                                </div>
+                            )}
+                        </Form.List>
+                    </Card>
+
+                    <Card title="监控分组告警规则" type="inner">
+                        <Form.List name="monitorGroupRules">
+                            {(fields, {add, remove}) => (
+                                <div className="space-y-2">
+                                    {fields.map(({key, name, ...restField}) => (
+                                        <Space key={key} align="baseline" wrap>
+                                            <Form.Item {...restField} name={[name, 'enabled']} valuePropName="checked" className="mb-0">
+                                                <Switch/>
+                                            </Form.Item>
+                                            <Form.Item
+                                                {...restField}
+                                                name={[name, 'group']}
+                                                className="mb-0"
+                                                rules={[{required: true, message: '请选择监控分组'}]}
+                                            >
+                                                <Select
+                                                    placeholder="监控分组"
+                                                    style={{width: 160}}
+                                                    options={monitorGroups.map((group) => ({label: group, value: group}))}
+                                                />
+                                            </Form.Item>
+                                            <Form.Item {...restField} name={[name, 'maxDown']} label="离线超过（个）" className="mb-0">
+                                                <InputNumber min={0} max={1000}/>
+                                            </Form.Item>
+                                            <Form.Item {...restField} name={[name, 'duration']} label="持续（秒）" className="mb-0">
+                                                <InputNumber min={0} max={86400}/>
+                                            </Form.Item>
+                                            <Button
+                                                type="text"
+                                                danger
+                                                icon={<MinusCircle size={16}/>}
+                                                onClick={() => remove(name)}
+                                            />
+                                        </Space>
+                                    ))}
+                                    <Button
+                                        type="dashed"
+                                        block
+                                        icon={<PlusCircle size={16}/>}
+                                        onClick={() => add({
+                                            enabled: true,
+                                            maxDown: 0,
+                                            duration: 60,
+                                        })}
+                                    >
+                                        添加监控分组规则
+                                    </Button>
+                                </div>
+                            )}
+                        </Form.List>
+                    </Card>
+
                     <Button
                         type="primary"
                         loading={saveMutation.isPending}
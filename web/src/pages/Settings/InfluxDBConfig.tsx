@@ -0,0 +1,110 @@
+import {useEffect} from 'react';
+import {App, Button, Card, Form, Input, Space, Spin, Switch} from 'antd';
+import {DatabaseZap} from 'lucide-react';
+import {useMutation, useQuery, useQueryClient} from '@tanstack/react-query';
+import type {InfluxDBConfig as InfluxDBConfigType} from '@/api/property.ts';
+import {getInfluxDBConfig, saveInfluxDBConfig} from '@/api/property.ts';
+import {getErrorMessage} from '@/lib/utils';
+
+const InfluxDBConfig = () => {
+    const [form] = Form.useForm<InfluxDBConfigType>();
+    const {message: messageApi} = App.useApp();
+    const queryClient = useQueryClient();
+
+    const {data: config, isLoading} = useQuery({
+        queryKey: ['influxDBConfig'],
+        queryFn: getInfluxDBConfig,
+    });
+
+    const saveMutation = useMutation({
+        mutationFn: saveInfluxDBConfig,
+        onSuccess: () => {
+            messageApi.success('配置保存成功');
+            queryClient.invalidateQueries({queryKey: ['influxDBConfig']});
+        },
+        onError: (error: unknown) => {
+            messageApi.error(getErrorMessage(error, '保存配置失败'));
+        },
+    });
+
+    const resetForm = () => {
+        if (config) {
+            form.setFieldsValue(config);
+        }
+    };
+
+    useEffect(() => {
+        resetForm();
+    }, [config, form]);
+
+    const handleSave = async () => {
+        try {
+            const values = await form.validateFields();
+            saveMutation.mutate(values);
+        } catch (error) {
+            // 表单验证失败
+        }
+    };
+
+    if (isLoading) {
+        return (
+            <div className="flex justify-center items-center py-20">
+                <Spin/>
+            </div>
+        );
+    }
+
+    return (
+        <div>
+            <div className="mb-6">
+                <h2 className="text-xl font-bold flex items-center gap-2">
+                    <DatabaseZap size={20}/>
+                    InfluxDB 导出
+                </h2>
+                <p className="text-gray-500 mt-2">启用后，探针上报的指标会以行协议批量写入 InfluxDB v2，写入失败不影响本地存储</p>
+            </div>
+
+            <Form form={form} layout="vertical" onFinish={handleSave}>
+                <Space direction="vertical" className="w-full">
+                    <Card type="inner">
+                        <Form.Item label="启用导出" name="enabled" valuePropName="checked">
+                            <Switch/>
+                        </Form.Item>
+                        <Form.Item
+                            label="InfluxDB 地址"
+                            name="url"
+                            rules={[{type: 'url', message: '请输入正确的地址'}]}
+                        >
+                            <Input placeholder="http://influxdb:8086"/>
+                        </Form.Item>
+                        <Form.Item label="组织" name="org" tooltip="组织名称或 ID">
+                            <Input placeholder="my-org"/>
+                        </Form.Item>
+                        <Form.Item label="Bucket" name="bucket">
+                            <Input placeholder="pika"/>
+                        </Form.Item>
+                        <Form.Item label="API Token" name="token" tooltip="需要有目标 bucket 的写权限">
+                            <Input.Password placeholder="请输入 API Token"/>
+                        </Form.Item>
+                        <div className="text-sm text-gray-500">
+                            写入的 measurement 为 pika_cpu、pika_memory、pika_disk、pika_network、pika_network_connections，并带有 agent_id 标签
+                        </div>
+                    </Card>
+
+                    <Form.Item>
+                        <Space>
+                            <Button type="primary" htmlType="submit" loading={saveMutation.isPending}>
+                                保存配置
+                            </Button>
+                            <Button onClick={resetForm}>
+                                重置
+                            </Button>
+                        </Space>
+                    </Form.Item>
+                </Space>
+            </Form>
+        </div>
+    );
+};
+
+export default InfluxDBConfig;
@@ -0,0 +1,137 @@
+import {useEffect} from 'react';
+import {App, Button, Card, Checkbox, Form, Space, Spin, Switch, TimePicker} from 'antd';
+import {CalendarClock} from 'lucide-react';
+import {useMutation, useQuery, useQueryClient} from '@tanstack/react-query';
+import dayjs from 'dayjs';
+import type {MaintenanceWindowConfig} from '@/api/property.ts';
+import {getMaintenanceWindowConfig, saveMaintenanceWindowConfig} from '@/api/property.ts';
+import {getErrorMessage} from '@/lib/utils';
+
+const weekdayOptions = [
+    {label: '周一', value: 1},
+    {label: '周二', value: 2},
+    {label: '周三', value: 3},
+    {label: '周四', value: 4},
+    {label: '周五', value: 5},
+    {label: '周六', value: 6},
+    {label: '周日', value: 0},
+];
+
+const MaintenanceWindow = () => {
+    const [form] = Form.useForm();
+    const {message: messageApi} = App.useApp();
+    const queryClient = useQueryClient();
+
+    const {data: config, isLoading} = useQuery({
+        queryKey: ['maintenanceWindowConfig'],
+        queryFn: getMaintenanceWindowConfig,
+    });
+
+    const saveMutation = useMutation({
+        mutationFn: saveMaintenanceWindowConfig,
+        onSuccess: () => {
+            messageApi.success('配置保存成功');
+            queryClient.invalidateQueries({queryKey: ['maintenanceWindowConfig']});
+        },
+        onError: (error: unknown) => {
+            messageApi.error(getErrorMessage(error, '保存配置失败'));
+        },
+    });
+
+    const resetForm = () => {
+        if (config) {
+            form.setFieldsValue({
+                enabled: config.enabled,
+                weekdays: config.weekdays || [],
+                startTime: dayjs(config.startTime || '02:00', 'HH:mm'),
+                endTime: dayjs(config.endTime || '05:00', 'HH:mm'),
+            });
+        }
+    };
+
+    useEffect(() => {
+        resetForm();
+    }, [config, form]);
+
+    const handleSave = async () => {
+        try {
+            const values = await form.validateFields();
+            saveMutation.mutate({
+                enabled: values.enabled,
+                weekdays: values.weekdays || [],
+                startTime: values.startTime.format('HH:mm'),
+                endTime: values.endTime.format('HH:mm'),
+            } as MaintenanceWindowConfig);
+        } catch (error) {
+            // 表单验证失败
+        }
+    };
+
+    if (isLoading) {
+        return (
+            <div className="flex justify-center items-center py-20">
+                <Spin/>
+            </div>
+        );
+    }
+
+    return (
+        <div>
+            <div className="mb-6">
+                <h2 className="text-xl font-bold flex items-center gap-2">
+                    <CalendarClock size={20}/>
+                    维护窗口
+                </h2>
+                <p className="text-gray-500 mt-2">启用后，计划重启任务只会在维护窗口内执行，窗口外到期的任务会顺延到下一个窗口开始时</p>
+            </div>
+
+            <Form form={form} layout="vertical" onFinish={handleSave}>
+                <Space direction="vertical" className="w-full">
+                    <Card type="inner">
+                        <Form.Item label="启用维护窗口" name="enabled" valuePropName="checked">
+                            <Switch/>
+                        </Form.Item>
+                        <Form.Item
+                            label="维护日"
+                            name="weekdays"
+                            tooltip="不选择表示每天；跨天的窗口以开始当天为准"
+                        >
+                            <Checkbox.Group options={weekdayOptions}/>
+                        </Form.Item>
+                        <Space size="large">
+                            <Form.Item
+                                label="开始时间"
+                                name="startTime"
+                                rules={[{required: true, message: '请选择开始时间'}]}
+                            >
+                                <TimePicker format="HH:mm" allowClear={false}/>
+                            </Form.Item>
+                            <Form.Item
+                                label="结束时间"
+                                name="endTime"
+                                rules={[{required: true, message: '请选择结束时间'}]}
+                                tooltip="早于开始时间表示跨天，例如 23:00 - 02:00"
+                            >
+                                <TimePicker format="HH:mm" allowClear={false}/>
+                            </Form.Item>
+                        </Space>
+                        <div className="text-sm text-gray-500">时间以服务端所在时区为准</div>
+                    </Card>
+
+                    <Form.Item>
+                        <Space>
+                            <Button type="primary" htmlType="submit" loading={saveMutation.isPending}>
+                                保存配置
+                            </Button>
+                            <Button onClick={resetForm}>
+                                重置
+                            </Button>
+                        </Space>
+                    </Form.Item>
+                </Space>
+            </Form>
+        </div>
+    );
+};
+
+export default MaintenanceWindow;
@@ -1,11 +1,35 @@
 import {useEffect} from 'react';
-import {App, Button, Card, Form, InputNumber, Space, Spin} from 'antd';
-import {Database, Clock, BarChart3} from 'lucide-react';
+import {App, Button, Card, Form, InputNumber, Space, Spin, Switch} from 'antd';
+import {Database, Clock, BarChart3, Cpu} from 'lucide-react';
 import {useMutation, useQuery, useQueryClient} from '@tanstack/react-query';
 import type {MetricsConfig} from '@/api/property.ts';
 import {getMetricsConfig, saveMetricsConfig} from '@/api/property.ts';
 import {getErrorMessage} from '@/lib/utils';
 
+// 支持单独配置采集间隔的指标
+const collectIntervalItems = [
+    {key: 'cpu', label: 'CPU'},
+    {key: 'memory', label: '内存'},
+    {key: 'disk', label: '磁盘'},
+    {key: 'disk_io', label: '磁盘 IO'},
+    {key: 'network', label: '网络'},
+    {key: 'network_connection', label: '网络连接'},
+    {key: 'host', label: '主机信息'},
+    {key: 'gpu', label: 'GPU'},
+    {key: 'temperature', label: '温度'},
+];
+
+// 去掉未填写的间隔，未配置的指标使用探针的采集间隔
+const compactIntervals = (intervals?: Record<string, number | null>) => {
+    const result: Record<string, number> = {};
+    Object.entries(intervals || {}).forEach(([key, value]) => {
+        if (value && value > 0) {
+            result[key] = value;
+        }
+    });
+    return result;
+};
+
 const MetricsConfigComponent = () => {
     const [form] = Form.useForm();
     const {message: messageApi} = App.useApp();
@@ -34,6 +58,10 @@ const MetricsConfigComponent = () => {
         if (metricsConfig) {
             form.setFieldsValue({
                 retentionHours: metricsConfig.retentionHours,
+                rollupRetentionHours: metricsConfig.rollupRetentionHours || 2160,
+                monitorSampleSeconds: metricsConfig.monitorSampleSeconds || 300,
+                perCoreCpu: metricsConfig.perCoreCpu || false,
+                collectIntervals: metricsConfig.collectIntervals || {},
                 maxQueryPoints: metricsConfig.maxQueryPoints,
             });
         }
@@ -44,6 +72,10 @@ const MetricsConfigComponent = () => {
             const values = await form.validateFields();
             saveMutation.mutate({
                 retentionHours: values.retentionHours,
+                rollupRetentionHours: values.rollupRetentionHours,
+                monitorSampleSeconds: values.monitorSampleSeconds,
+                perCoreCpu: values.perCoreCpu,
+                collectIntervals: compactIntervals(values.collectIntervals),
                 maxQueryPoints: values.maxQueryPoints,
             } as MetricsConfig);
         } catch (error) {
@@ -55,6 +87,10 @@ const MetricsConfigComponent = () => {
         if (metricsConfig) {
             form.setFieldsValue({
                 retentionHours: metricsConfig.retentionHours,
+                rollupRetentionHours: metricsConfig.rollupRetentionHours || 2160,
+                monitorSampleSeconds: metricsConfig.monitorSampleSeconds || 300,
+                perCoreCpu: metricsConfig.perCoreCpu || false,
+                collectIntervals: metricsConfig.collectIntervals || {},
                 maxQueryPoints: metricsConfig.maxQueryPoints,
             });
         }
@@ -96,7 +132,7 @@ const MetricsConfigComponent = () => {
                         type="inner"
                     >
                         <Form.Item
-                            label="数据保留时长"
+                            label="原始数据保留时长"
                             name="retentionHours"
                             rules={[
                                 {required: true, message: '请输入数据保留时长'},
@@ -107,7 +143,7 @@ const MetricsConfigComponent = () => {
                                     message: '保留时长必须在 24-720 小时之间（1-30天）'
                                 },
                             ]}
-                            tooltip="原始指标数据和聚合数据的保留时长，超过此时长的数据将被自动清理"
+                            tooltip="原始指标数据的保留时长，超过此时长的数据将被自动清理"
                         >
                             <InputNumber
                                 min={24}
@@ -131,14 +167,41 @@ const MetricsConfigComponent = () => {
                             }}
                         </Form.Item>
 
+                        <Form.Item
+                            label="聚合数据保留时长"
+                            name="rollupRetentionHours"
+                            dependencies={['retentionHours']}
+                            rules={[
+                                {required: true, message: '请输入聚合数据保留时长'},
+                                ({getFieldValue}) => ({
+                                    validator(_, value) {
+                                        if (!value || value >= getFieldValue('retentionHours')) {
+                                            return Promise.resolve();
+                                        }
+                                        return Promise.reject(new Error('聚合数据保留时长不能短于原始数据'));
+                                    },
+                                }),
+                            ]}
+                            tooltip="聚合数据（平均值、最小值、最大值）的保留时长，长时间范围的图表读取聚合数据"
+                        >
+                            <InputNumber
+                                min={24}
+                                max={8760}
+                                step={24}
+                                addonAfter="小时"
+                                style={{width: 200}}
+                                placeholder="2160"
+                            />
+                        </Form.Item>
+
                         <div className="p-4 bg-blue-50 dark:bg-blue-950/20 rounded-lg border border-blue-200 dark:border-blue-800">
                             <div className="text-sm text-blue-800 dark:text-blue-300 space-y-2">
                                 <div className="font-semibold flex items-center gap-2">
                                     💡 保留策略说明
                                 </div>
                                 <ul className="list-disc list-inside space-y-1.5 ml-2">
-                                    <li>系统会保留<strong>原始数据</strong>和<strong>多种粒度的聚合数据</strong>（1分钟、5分钟、1小时）</li>
-                                    <li>聚合数据自动生成，用于优化长时间范围的查询性能</li>
+                                    <li>系统会保留<strong>原始数据</strong>和<strong>多种粒度的聚合数据</strong>（1分钟、5分钟、1小时等，包含平均值、最小值、最大值）</li>
+                                    <li>聚合数据自动生成，用于优化长时间范围的查询性能，原始数据过期后仍可查看历史趋势</li>
                                     <li>较短的保留时长可以<strong>节省存储空间</strong>，减少数据库压力</li>
                                     <li>修改后立即生效，下次清理任务时应用新策略（每小时执行一次）</li>
                                 </ul>
@@ -146,6 +209,80 @@ const MetricsConfigComponent = () => {
                         </div>
                     </Card>
 
+                    {/* 服务监控采样 */}
+                    <Card
+                        title={
+                            <div className="flex items-center gap-2">
+                                <BarChart3 size={18}/>
+                                <span>服务监控采样</span>
+                            </div>
+                        }
+                        type="inner"
+                    >
+                        <Form.Item
+                            label="状态不变时的保存间隔"
+                            name="monitorSampleSeconds"
+                            rules={[
+                                {required: true, message: '请输入保存间隔'},
+                                {type: 'number', min: 1, max: 3600, message: '保存间隔必须在 1-3600 秒之间'},
+                            ]}
+                            tooltip="服务状态变化时立即保存检测结果，状态不变时每隔该时长合并保存一条，可用率和平均响应时间按检测次数加权计算；设置为 1 秒则保存每次检测结果"
+                        >
+                            <InputNumber
+                                min={1}
+                                max={3600}
+                                step={60}
+                                addonAfter="秒"
+                                style={{width: 200}}
+                                placeholder="300"
+                            />
+                        </Form.Item>
+                    </Card>
+
+                    {/* 探针采集 */}
+                    <Card
+                        title={
+                            <div className="flex items-center gap-2">
+                                <Cpu size={18}/>
+                                <span>探针采集</span>
+                            </div>
+                        }
+                        type="inner"
+                    >
+                        <Form.Item
+                            label="采集每核 CPU 使用率"
+                            name="perCoreCpu"
+                            valuePropName="checked"
+                            tooltip="开启后探针会上报每个逻辑核心的使用率，用于发现单核打满等负载不均问题；只保留最新数据并在探针详情和 Prometheus 导出中展示，不写入历史。保存后立即下发到在线探针"
+                        >
+                            <Switch/>
+                        </Form.Item>
+
+                        <Form.Item
+                            label="各指标采集间隔"
+                            tooltip="为各类指标单独设置采集间隔，如 CPU 每 5 秒、磁盘每 60 秒、主机信息每 10 分钟；留空使用探针配置的采集间隔。精简模式的探针不会低于自身的采集间隔。保存后立即下发到在线探针"
+                        >
+                            <div className="grid grid-cols-1 md:grid-cols-3 gap-x-4">
+                                {collectIntervalItems.map(item => (
+                                    <Form.Item
+                                        key={item.key}
+                                        label={item.label}
+                                        name={['collectIntervals', item.key]}
+                                        rules={[{type: 'number', min: 1, max: 86400, message: '间隔必须在 1-86400 秒之间'}]}
+                                    >
+                                        <InputNumber
+                                            min={1}
+                                            max={86400}
+                                            addonAfter="秒"
+                                            style={{width: 160}}
+                                            placeholder="默认"
+                                        />
+                                    </Form.Item>
+                                ))}
+                            </div>
+                        </Form.Item>
+                    </Card>
+
                     {/* 保存按钮 */}
                     <Form.Item>
                         <Space>
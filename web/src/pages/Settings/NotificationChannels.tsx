@@ -1,5 +1,5 @@
 import {useEffect} from 'react';
-import {App, Button, Card, Collapse, Form, Input, Select, Space, Spin, Switch} from 'antd';
+import {App, Button, Card, Checkbox, Collapse, Form, Input, InputNumber, Select, Space, Spin, Switch} from 'antd';
 import {TestTube} from 'lucide-react';
 import {useMutation, useQuery, useQueryClient} from '@tanstack/react-query';
 import {
@@ -10,6 +10,21 @@ import {
 } from '@/api/property.ts';
 import {getErrorMessage} from '@/lib/utils';
 
+// 通知语言选项
+const languageOptions = [
+    {label: '简体中文', value: 'zh'},
+    {label: 'English', value: 'en'},
+];
+
+// 通知事件选项
+const eventOptions = [
+    {label: '告警', value: 'alert'},
+    {label: 'DDNS 更新', value: 'ddns'},
+    {label: '防篡改', value: 'tamper'},
+    {label: '安全审计', value: 'audit'},
+    {label: '探针注册', value: 'agent_register'},
+];
+
 const NotificationChannels = () => {
     const [form] = Form.useForm();
     const {message: messageApi} = App.useApp();
@@ -36,8 +51,14 @@ const NotificationChannels = () => {
     // 测试 mutation
     const testMutation = useMutation({
         mutationFn: testNotificationChannel,
-        onSuccess: () => {
-            messageApi.success('测试通知已发送');
+        onSuccess: (result) => {
+            const last = result.responses[result.responses.length - 1];
+            const detail = last ? `（HTTP ${last.statusCode}${last.body ? `：${last.body.slice(0, 200)}` : ''}）` : '';
+            if (result.success) {
+                messageApi.success(`测试通知已发送，耗时 ${result.latencyMs}ms${detail}`);
+            } else {
+                messageApi.error(`测试失败：${result.error}${detail}`);
+            }
         },
         onError: (error: unknown) => {
             messageApi.error(getErrorMessage(error, '测试失败'));
@@ -50,6 +71,8 @@ const NotificationChannels = () => {
             const formValues: Record<string, any> = {};
 
             channels.forEach((channel) => {
+                formValues[`${channel.type}Language`] = channel.config?.language || 'zh';
+                formValues[`${channel.type}Events`] = channel.events?.length ? channel.events : ['alert'];
                 if (channel.type === 'dingtalk') {
                     formValues.dingtalkEnabled = channel.enabled;
                     formValues.dingtalkSecretKey = channel.config?.secretKey || '';
@@ -57,16 +80,50 @@ const NotificationChannels = () => {
                 } else if (channel.type === 'wecom') {
                     formValues.wecomEnabled = channel.enabled;
                     formValues.wecomSecretKey = channel.config?.secretKey || '';
+                    formValues.wecomMsgType = channel.config?.msgType || 'text';
+                    formValues.wecomMentionedList = channel.config?.mentionedList || [];
+                    formValues.wecomMentionedMobileList = channel.config?.mentionedMobileList || [];
                 } else if (channel.type === 'feishu') {
                     formValues.feishuEnabled = channel.enabled;
                     formValues.feishuSecretKey = channel.config?.secretKey || '';
                     formValues.feishuSignSecret = channel.config?.signSecret || '';
+                    formValues.feishuDashboardUrl = channel.config?.dashboardUrl || '';
+                } else if (channel.type === 'ntfy') {
+                    formValues.ntfyEnabled = channel.enabled;
+                    formValues.ntfyServerUrl = channel.config?.serverUrl || '';
+                    formValues.ntfyTopic = channel.config?.topic || '';
+                    formValues.ntfyToken = channel.config?.token || '';
+                } else if (channel.type === 'gotify') {
+                    formValues.gotifyEnabled = channel.enabled;
+                    formValues.gotifyServerUrl = channel.config?.serverUrl || '';
+                    formValues.gotifyAppToken = channel.config?.appToken || '';
+                } else if (channel.type === 'pushover') {
+                    formValues.pushoverEnabled = channel.enabled;
+                    formValues.pushoverAppToken = channel.config?.appToken || '';
+                    formValues.pushoverUserKey = channel.config?.userKey || '';
+                    formValues.pushoverDevice = channel.config?.device || '';
+                    formValues.pushoverRetry = channel.config?.retry || 60;
+                    formValues.pushoverExpire = channel.config?.expire || 3600;
+                } else if (channel.type === 'matrix') {
+                    formValues.matrixEnabled = channel.enabled;
+                    formValues.matrixHomeserverUrl = channel.config?.homeserverUrl || '';
+                    formValues.matrixAccessToken = channel.config?.accessToken || '';
+                    formValues.matrixRoomId = channel.config?.roomId || '';
+                } else if (channel.type === 'twilio') {
+                    formValues.twilioEnabled = channel.enabled;
+                    formValues.twilioAccountSid = channel.config?.accountSid || '';
+                    formValues.twilioAuthToken = channel.config?.authToken || '';
+                    formValues.twilioFrom = channel.config?.from || '';
+                    formValues.twilioTo = channel.config?.to || [];
+                    formValues.twilioLevels = channel.config?.levels || ['critical'];
                 } else if (channel.type === 'webhook') {
                     formValues.webhookEnabled = channel.enabled;
                     formValues.webhookUrl = channel.config?.url || '';
                     formValues.webhookMethod = channel.config?.method || 'POST';
                     formValues.webhookBodyTemplate = channel.config?.bodyTemplate || 'json';
                     formValues.webhookCustomBody = channel.config?.customBody || '';
+                    formValues.webhookSignSecret = channel.config?.signSecret || '';
+                    formValues.webhookSignatureHeader = channel.config?.signatureHeader || '';
 
                     // 解析 headers 为数组形式方便编辑
                     const headers = channel.config?.headers || {};
@@ -106,6 +163,9 @@ const NotificationChannels = () => {
                     enabled: values.wecomEnabled || false,
                     config: {
                         secretKey: values.wecomSecretKey || '',
+                        msgType: values.wecomMsgType || 'text',
+                        mentionedList: values.wecomMentionedList || [],
+                        mentionedMobileList: values.wecomMentionedMobileList || [],
                     },
                 });
             }
@@ -118,6 +178,75 @@ const NotificationChannels = () => {
                     config: {
                         secretKey: values.feishuSecretKey || '',
                         signSecret: values.feishuSignSecret || '',
+                        dashboardUrl: values.feishuDashboardUrl || '',
+                    },
+                });
+            }
+
+            // ntfy
+            if (values.ntfyEnabled || values.ntfyTopic) {
+                newChannels.push({
+                    type: 'ntfy',
+                    enabled: values.ntfyEnabled || false,
+                    config: {
+                        serverUrl: values.ntfyServerUrl || '',
+                        topic: values.ntfyTopic || '',
+                        token: values.ntfyToken || '',
+                    },
+                });
+            }
+
+            // Gotify
+            if (values.gotifyEnabled || values.gotifyServerUrl) {
+                newChannels.push({
+                    type: 'gotify',
+                    enabled: values.gotifyEnabled || false,
+                    config: {
+                        serverUrl: values.gotifyServerUrl || '',
+                        appToken: values.gotifyAppToken || '',
+                    },
+                });
+            }
+
+            // Pushover
+            if (values.pushoverEnabled || values.pushoverUserKey) {
+                newChannels.push({
+                    type: 'pushover',
+                    enabled: values.pushoverEnabled || false,
+                    config: {
+                        appToken: values.pushoverAppToken || '',
+                        userKey: values.pushoverUserKey || '',
+                        device: values.pushoverDevice || '',
+                        retry: values.pushoverRetry || 60,
+                        expire: values.pushoverExpire || 3600,
+                    },
+                });
+            }
+
+            // Matrix
+            if (values.matrixEnabled || values.matrixRoomId) {
+                newChannels.push({
+                    type: 'matrix',
+                    enabled: values.matrixEnabled || false,
+                    config: {
+                        homeserverUrl: values.matrixHomeserverUrl || '',
+                        accessToken: values.matrixAccessToken || '',
+                        roomId: values.matrixRoomId || '',
+                    },
+                });
+            }
+
+            // Twilio 短信
+            if (values.twilioEnabled || values.twilioAccountSid) {
+                newChannels.push({
+                    type: 'twilio',
+                    enabled: values.twilioEnabled || false,
+                    config: {
+                        accountSid: values.twilioAccountSid || '',
+                        authToken: values.twilioAuthToken || '',
+                        from: values.twilioFrom || '',
+                        to: values.twilioTo || [],
+                        levels: values.twilioLevels?.length ? values.twilioLevels : ['critical'],
                     },
                 });
             }
@@ -142,11 +271,19 @@ const NotificationChannels = () => {
                         method: values.webhookMethod || 'POST',
                         bodyTemplate: values.webhookBodyTemplate || 'json',
                         customBody: values.webhookCustomBody || '',
+                        signSecret: values.webhookSignSecret || '',
+                        signatureHeader: values.webhookSignatureHeader || '',
                         headers: Object.keys(headersObj).length > 0 ? headersObj : undefined,
                     },
                 });
             }
 
+            // 通知语言和订阅事件
+            newChannels.forEach((channel) => {
+                channel.config.language = values[`${channel.type}Language`] || 'zh';
+                channel.events = values[`${channel.type}Events`] || ['alert'];
+            });
+
             saveMutation.mutate(newChannels);
         } catch (error) {
             // 表单验证失败
@@ -157,6 +294,23 @@ const NotificationChannels = () => {
         testMutation.mutate(type);
     };
 
+    const renderLanguageItem = (type: string) => (
+        <Form.Item label="通知语言" name={`${type}Language`} tooltip="告警通知内容使用的语言">
+            <Select options={languageOptions}/>
+        </Form.Item>
+    );
+
+    const renderEventsItem = (type: string) => (
+        <Form.Item
+            label="通知事件"
+            name={`${type}Events`}
+            tooltip="该渠道接收的事件类型，DDNS、防篡改等事件可以发送到与告警不同的渠道"
+            rules={[{required: true, message: '请至少选择一种事件'}]}
+        >
+            <Checkbox.Group options={eventOptions}/>
+        </Form.Item>
+    );
+
     if (isLoading) {
         return (
             <div className="flex justify-center items-center py-20">
@@ -169,7 +323,7 @@ const NotificationChannels = () => {
         <div>
             <div className="mb-4">
                 <h2 className="text-xl font-bold">通知渠道管理</h2>
-                <p className="text-gray-500 mt-2">配置钉钉、企业微信、飞书和自定义Webhook通知渠道</p>
+                <p className="text-gray-500 mt-2">配置钉钉、企业微信、飞书、ntfy、Gotify、Pushover、Matrix、Twilio 短信和自定义Webhook通知渠道</p>
             </div>
 
             <Form form={form} layout="vertical" onFinish={handleSave}>
@@ -229,6 +383,8 @@ const NotificationChannels = () => {
                                         >
                                             <Input.Password placeholder="SEC 开头的加签密钥"/>
                                         </Form.Item>
+                                        {renderLanguageItem('dingtalk')}
+                                        {renderEventsItem('dingtalk')}
                                     </>
                                 ) : null
                             }
@@ -272,14 +428,40 @@ const NotificationChannels = () => {
                         >
                             {({getFieldValue}) =>
                                 getFieldValue('wecomEnabled') ? (
-                                    <Form.Item
-                                        label="Webhook Key"
-                                        name="wecomSecretKey"
-                                        rules={[{required: true, message: '请输入 Webhook Key'}]}
-                                        tooltip="企业微信群机器人的 Webhook Key"
-                                    >
-                                        <Input placeholder="输入 Webhook Key"/>
-                                    </Form.Item>
+                                    <>
+                                        <Form.Item
+                                            label="Webhook Key"
+                                            name="wecomSecretKey"
+                                            rules={[{required: true, message: '请输入 Webhook Key'}]}
+                                            tooltip="企业微信群机器人的 Webhook Key"
+                                        >
+                                            <Input placeholder="输入 Webhook Key"/>
+                                        </Form.Item>
+                                        <Form.Item label="消息格式" name="wecomMsgType">
+                                            <Select
+                                                options={[
+                                                    {label: '文本 (默认)', value: 'text'},
+                                                    {label: 'Markdown', value: 'markdown'},
+                                                ]}
+                                            />
+                                        </Form.Item>
+                                        <Form.Item
+                                            label="严重告警 @成员（可选）"
+                                            name="wecomMentionedList"
+                                            tooltip="严重级别告警触发时 @ 的成员 userid，填写 @all 表示所有人"
+                                        >
+                                            <Select mode="tags" placeholder="输入成员 userid 后回车"/>
+                                        </Form.Item>
+                                        <Form.Item
+                                            label="严重告警 @手机号（可选）"
+                                            name="wecomMentionedMobileList"
+                                            tooltip="严重级别告警触发时 @ 的成员手机号，仅文本消息支持"
+                                        >
+                                            <Select mode="tags" placeholder="输入手机号后回车"/>
+                                        </Form.Item>
+                                        {renderLanguageItem('wecom')}
+                                        {renderEventsItem('wecom')}
+                                    </>
                                 ) : null
                             }
                         </Form.Item>
@@ -342,6 +524,391 @@ const NotificationChannels = () => {
                                         >
                                             <Input.Password placeholder="输入签名密钥"/>
                                         </Form.Item>
+                                        <Form.Item
+                                            label="控制台地址（可选）"
+                                            name="feishuDashboardUrl"
+                                            tooltip="填写后告警卡片中会显示跳转到探针详情的按钮"
+                                        >
+                                            <Input placeholder="例如: https://pika.example.com"/>
+                                        </Form.Item>
+                                        {renderLanguageItem('feishu')}
+                                        {renderEventsItem('feishu')}
+                                    </>
+                                ) : null
+                            }
+                        </Form.Item>
+                    </Card>
+
+                    {/* ntfy 通知 */}
+                    <Card
+                        title={
+                            <div className={'flex items-center gap-2'}>
+                                <div>ntfy 通知</div>
+                                <div className={'text-xs font-normal'}>
+                                    了解更多：<a href="https://docs.ntfy.sh/publish/"
+                                                target="_blank"
+                                                rel="noopener noreferrer">https://docs.ntfy.sh/publish/</a>
+                                </div>
+                            </div>
+                        }
+                        type="inner"
+                        className="mb-4"
+                        extra={
+                            <Button
+                                type="link"
+                                size="small"
+                                icon={<TestTube size={14}/>}
+                                onClick={() => handleTest('ntfy')}
+                                loading={testMutation.isPending}
+                                disabled={!form.getFieldValue('ntfyEnabled')}
+                            >
+                                测试
+                            </Button>
+                        }
+                    >
+                        <Form.Item label="启用 ntfy 通知" name="ntfyEnabled" valuePropName="checked">
+                            <Switch/>
+                        </Form.Item>
+
+                        <Form.Item
+                            noStyle
+                            shouldUpdate={(prevValues, currentValues) =>
+                                prevValues.ntfyEnabled !== currentValues.ntfyEnabled
+                            }
+                        >
+                            {({getFieldValue}) =>
+                                getFieldValue('ntfyEnabled') ? (
+                                    <>
+                                        <Form.Item
+                                            label="服务地址（可选）"
+                                            name="ntfyServerUrl"
+                                            tooltip="自建 ntfy 服务的地址，留空使用 https://ntfy.sh"
+                                        >
+                                            <Input placeholder="https://ntfy.sh"/>
+                                        </Form.Item>
+                                        <Form.Item
+                                            label="主题 (Topic)"
+                                            name="ntfyTopic"
+                                            rules={[{required: true, message: '请输入主题'}]}
+                                            tooltip="在 ntfy App 中订阅同名主题即可收到推送，公共服务上请使用不易猜测的主题名"
+                                        >
+                                            <Input placeholder="例如: pika-alerts-8f3k2"/>
+                                        </Form.Item>
+                                        <Form.Item
+                                            label="访问令牌（可选）"
+                                            name="ntfyToken"
+                                            tooltip="主题开启访问控制时填写，以 tk_ 开头"
+                                        >
+                                            <Input.Password placeholder="tk_ 开头的访问令牌"/>
+                                        </Form.Item>
+                                        {renderLanguageItem('ntfy')}
+                                        {renderEventsItem('ntfy')}
+                                    </>
+                                ) : null
+                            }
+                        </Form.Item>
+                    </Card>
+
+                    {/* Gotify 通知 */}
+                    <Card
+                        title={
+                            <div className={'flex items-center gap-2'}>
+                                <div>Gotify 通知</div>
+                                <div className={'text-xs font-normal'}>
+                                    了解更多：<a href="https://gotify.net/docs/pushmsg"
+                                                target="_blank"
+                                                rel="noopener noreferrer">https://gotify.net/docs/pushmsg</a>
+                                </div>
+                            </div>
+                        }
+                        type="inner"
+                        className="mb-4"
+                        extra={
+                            <Button
+                                type="link"
+                                size="small"
+                                icon={<TestTube size={14}/>}
+                                onClick={() => handleTest('gotify')}
+                                loading={testMutation.isPending}
+                                disabled={!form.getFieldValue('gotifyEnabled')}
+                            >
+                                测试
+                            </Button>
+                        }
+                    >
+                        <Form.Item label="启用 Gotify 通知" name="gotifyEnabled" valuePropName="checked">
+                            <Switch/>
+                        </Form.Item>
+
+                        <Form.Item
+                            noStyle
+                            shouldUpdate={(prevValues, currentValues) =>
+                                prevValues.gotifyEnabled !== currentValues.gotifyEnabled
+                            }
+                        >
+                            {({getFieldValue}) =>
+                                getFieldValue('gotifyEnabled') ? (
+                                    <>
+                                        <Form.Item
+                                            label="服务地址"
+                                            name="gotifyServerUrl"
+                                            rules={[{required: true, message: '请输入 Gotify 服务地址'}]}
+                                        >
+                                            <Input placeholder="例如: https://gotify.example.com"/>
+                                        </Form.Item>
+                                        <Form.Item
+                                            label="应用令牌 (App Token)"
+                                            name="gotifyAppToken"
+                                            rules={[{required: true, message: '请输入应用令牌'}]}
+                                            tooltip="在 Gotify 控制台的 Apps 页面创建应用后获取"
+                                        >
+                                            <Input.Password placeholder="输入应用令牌"/>
+                                        </Form.Item>
+                                        {renderLanguageItem('gotify')}
+                                        {renderEventsItem('gotify')}
+                                    </>
+                                ) : null
+                            }
+                        </Form.Item>
+                    </Card>
+
+                    {/* Pushover 通知 */}
+                    <Card
+                        title={
+                            <div className={'flex items-center gap-2'}>
+                                <div>Pushover 通知</div>
+                                <div className={'text-xs font-normal'}>
+                                    了解更多：<a href="https://pushover.net/api"
+                                                target="_blank"
+                                                rel="noopener noreferrer">https://pushover.net/api</a>
+                                </div>
+                            </div>
+                        }
+                        type="inner"
+                        className="mb-4"
+                        extra={
+                            <Button
+                                type="link"
+                                size="small"
+                                icon={<TestTube size={14}/>}
+                                onClick={() => handleTest('pushover')}
+                                loading={testMutation.isPending}
+                                disabled={!form.getFieldValue('pushoverEnabled')}
+                            >
+                                测试
+                            </Button>
+                        }
+                    >
+                        <Form.Item label="启用 Pushover 通知" name="pushoverEnabled" valuePropName="checked">
+                            <Switch/>
+                        </Form.Item>
+
+                        <Form.Item
+                            noStyle
+                            shouldUpdate={(prevValues, currentValues) =>
+                                prevValues.pushoverEnabled !== currentValues.pushoverEnabled
+                            }
+                        >
+                            {({getFieldValue}) =>
+                                getFieldValue('pushoverEnabled') ? (
+                                    <>
+                                        <Form.Item
+                                            label="应用令牌 (API Token)"
+                                            name="pushoverAppToken"
+                                            rules={[{required: true, message: '请输入应用令牌'}]}
+                                        >
+                                            <Input.Password placeholder="输入应用令牌"/>
+                                        </Form.Item>
+                                        <Form.Item
+                                            label="用户密钥 (User Key)"
+                                            name="pushoverUserKey"
+                                            rules={[{required: true, message: '请输入用户密钥'}]}
+                                        >
+                                            <Input placeholder="输入用户或群组密钥"/>
+                                        </Form.Item>
+                                        <Form.Item label="设备名称（可选）" name="pushoverDevice">
+                                            <Input placeholder="留空推送到所有设备"/>
+                                        </Form.Item>
+                                        <Form.Item
+                                            label="紧急通知重复间隔（秒）"
+                                            name="pushoverRetry"
+                                            tooltip="严重告警以紧急优先级发送，确认前按此间隔重复提醒，最小 30 秒"
+                                        >
+                                            <InputNumber min={30} className="w-full"/>
+                                        </Form.Item>
+                                        <Form.Item
+                                            label="紧急通知持续时长（秒）"
+                                            name="pushoverExpire"
+                                            tooltip="超过该时长后停止重复提醒，最大 10800 秒；告警恢复时也会自动停止"
+                                        >
+                                            <InputNumber min={30} max={10800} className="w-full"/>
+                                        </Form.Item>
+                                        {renderLanguageItem('pushover')}
+                                        {renderEventsItem('pushover')}
+                                    </>
+                                ) : null
+                            }
+                        </Form.Item>
+                    </Card>
+
+                    {/* Matrix 通知 */}
+                    <Card
+                        title={
+                            <div className={'flex items-center gap-2'}>
+                                <div>Matrix 通知</div>
+                                <div className={'text-xs font-normal'}>
+                                    了解更多：<a href="https://spec.matrix.org/latest/client-server-api/#sending-events-to-a-room"
+                                                target="_blank"
+                                                rel="noopener noreferrer">Matrix Client-Server API</a>
+                                </div>
+                            </div>
+                        }
+                        type="inner"
+                        className="mb-4"
+                        extra={
+                            <Button
+                                type="link"
+                                size="small"
+                                icon={<TestTube size={14}/>}
+                                onClick={() => handleTest('matrix')}
+                                loading={testMutation.isPending}
+                                disabled={!form.getFieldValue('matrixEnabled')}
+                            >
+                                测试
+                            </Button>
+                        }
+                    >
+                        <Form.Item label="启用 Matrix 通知" name="matrixEnabled" valuePropName="checked">
+                            <Switch/>
+                        </Form.Item>
+
+                        <Form.Item
+                            noStyle
+                            shouldUpdate={(prevValues, currentValues) =>
+                                prevValues.matrixEnabled !== currentValues.matrixEnabled
+                            }
+                        >
+                            {({getFieldValue}) =>
+                                getFieldValue('matrixEnabled') ? (
+                                    <>
+                                        <Form.Item
+                                            label="Homeserver 地址"
+                                            name="matrixHomeserverUrl"
+                                            rules={[{required: true, message: '请输入 Homeserver 地址'}]}
+                                        >
+                                            <Input placeholder="例如: https://matrix.org"/>
+                                        </Form.Item>
+                                        <Form.Item
+                                            label="访问令牌 (Access Token)"
+                                            name="matrixAccessToken"
+                                            rules={[{required: true, message: '请输入访问令牌'}]}
+                                            tooltip="建议使用单独的机器人账号，在 Element 的 设置 - 帮助与关于 中可以查看访问令牌"
+                                        >
+                                            <Input.Password placeholder="输入访问令牌"/>
+                                        </Form.Item>
+                                        <Form.Item
+                                            label="房间 ID"
+                                            name="matrixRoomId"
+                                            rules={[{required: true, message: '请输入房间 ID'}]}
+                                            tooltip="在房间设置 - 高级中查看，机器人账号需先加入该房间"
+                                        >
+                                            <Input placeholder="例如: !abcdefg:matrix.org"/>
+                                        </Form.Item>
+                                        {renderLanguageItem('matrix')}
+                                        {renderEventsItem('matrix')}
+                                    </>
+                                ) : null
+                            }
+                        </Form.Item>
+                    </Card>
+
+                    {/* Twilio 短信通知 */}
+                    <Card
+                        title={
+                            <div className={'flex items-center gap-2'}>
+                                <div>Twilio 短信通知</div>
+                                <div className={'text-xs font-normal'}>
+                                    了解更多：<a href="https://www.twilio.com/docs/messaging/api/message-resource"
+                                                target="_blank"
+                                                rel="noopener noreferrer">Twilio Messaging API</a>
+                                </div>
+                            </div>
+                        }
+                        type="inner"
+                        className="mb-4"
+                        extra={
+                            <Button
+                                type="link"
+                                size="small"
+                                icon={<TestTube size={14}/>}
+                                onClick={() => handleTest('twilio')}
+                                loading={testMutation.isPending}
+                                disabled={!form.getFieldValue('twilioEnabled')}
+                            >
+                                测试
+                            </Button>
+                        }
+                    >
+                        <Form.Item label="启用 Twilio 短信通知" name="twilioEnabled" valuePropName="checked">
+                            <Switch/>
+                        </Form.Item>
+
+                        <Form.Item
+                            noStyle
+                            shouldUpdate={(prevValues, currentValues) =>
+                                prevValues.twilioEnabled !== currentValues.twilioEnabled
+                            }
+                        >
+                            {({getFieldValue}) =>
+                                getFieldValue('twilioEnabled') ? (
+                                    <>
+                                        <Form.Item
+                                            label="Account SID"
+                                            name="twilioAccountSid"
+                                            rules={[{required: true, message: '请输入 Account SID'}]}
+                                        >
+                                            <Input placeholder="例如: ACxxxxxxxxxxxxxxxx"/>
+                                        </Form.Item>
+                                        <Form.Item
+                                            label="Auth Token"
+                                            name="twilioAuthToken"
+                                            rules={[{required: true, message: '请输入 Auth Token'}]}
+                                        >
+                                            <Input.Password placeholder="输入 Auth Token"/>
+                                        </Form.Item>
+                                        <Form.Item
+                                            label="发送号码"
+                                            name="twilioFrom"
+                                            rules={[{required: true, message: '请输入发送号码'}]}
+                                            tooltip="在 Twilio 控制台购买的号码，E.164 格式"
+                                        >
+                                            <Input placeholder="例如: +15005550006"/>
+                                        </Form.Item>
+                                        <Form.Item
+                                            label="接收号码"
+                                            name="twilioTo"
+                                            rules={[{required: true, message: '请输入接收号码'}]}
+                                            tooltip="值班人员手机号，E.164 格式，可填写多个"
+                                        >
+                                            <Select mode="tags" placeholder="输入手机号后回车，例如: +8613800000000"/>
+                                        </Form.Item>
+                                        <Form.Item
+                                            label="告警级别"
+                                            name="twilioLevels"
+                                            initialValue={['critical']}
+                                            tooltip="短信按条计费，默认只发送严重告警；恢复通知跟随原告警级别"
+                                        >
+                                            <Select
+                                                mode="multiple"
+                                                options={[
+                                                    {label: '信息 (info)', value: 'info'},
+                                                    {label: '警告 (warning)', value: 'warning'},
+                                                    {label: '严重 (critical)', value: 'critical'},
+                                                ]}
+                                            />
+                                        </Form.Item>
+                                        {renderLanguageItem('twilio')}
+                                        {renderEventsItem('twilio')}
                                     </>
                                 ) : null
                             }
@@ -462,6 +1029,22 @@ const NotificationChannels = () => {
                                                                     </Form.Item>
                                                                 )}
 
+                                                                {/* 签名 */}
+                                                                <Form.Item
+                                                                    label="签名密钥（可选）"
+                                                                    name="webhookSignSecret"
+                                                                    tooltip="设置后使用 HMAC-SHA256 对请求体签名，签名以 sha256=<hex> 格式放在签名请求头中，接收方可用相同密钥校验请求来源"
+                                                                >
+                                                                    <Input.Password placeholder="输入签名密钥"/>
+                                                                </Form.Item>
+                                                                <Form.Item
+                                                                    label="签名请求头"
+                                                                    name="webhookSignatureHeader"
+                                                                    tooltip="存放签名的请求头名称，默认 X-Pika-Signature"
+                                                                >
+                                                                    <Input placeholder="X-Pika-Signature"/>
+                                                                </Form.Item>
+
                                                                 {/* 自定义请求头 */}
                                                                 <Form.Item label="自定义请求头"
                                                                            tooltip="添加自定义 HTTP 请求头">
@@ -535,6 +1118,8 @@ const NotificationChannels = () => {
                                                 ]}
                                             />
                                         </div>
+                                        {renderLanguageItem('webhook')}
+                                        {renderEventsItem('webhook')}
                                     </>
                                 ) : null
                             }
@@ -1,9 +1,11 @@
 import {Tabs} from 'antd';
-import {Bell, Database, MessageSquare, Settings2} from 'lucide-react';
+import {Bell, CalendarClock, Database, DatabaseZap, MessageSquare, Settings2} from 'lucide-react';
 import AlertSettings from './AlertSettings';
 import NotificationChannels from './NotificationChannels';
 import SystemConfig from './SystemConfig';
 import MetricsConfig from './MetricsConfig';
+import MaintenanceWindow from './MaintenanceWindow';
+import InfluxDBConfig from './InfluxDBConfig';
 import {PageHeader} from "@/components";
 import {useSearchParams} from "react-router-dom";
 
@@ -52,6 +54,26 @@ const Settings = () => {
             ),
             children: <AlertSettings/>,
         },
+        {
+            key: 'maintenance',
+            label: (
+                <span className="flex items-center gap-2">
+                    <CalendarClock size={16}/>
+                    维护窗口
+                </span>
+            ),
+            children: <MaintenanceWindow/>,
+        },
+        {
+            key: 'influxdb',
+            label: (
+                <span className="flex items-center gap-2">
+                    <DatabaseZap size={16}/>
+                    InfluxDB 导出
+                </span>
+            ),
+            children: <InfluxDBConfig/>,
+        },
     ];
 
     return (
@@ -1,6 +1,7 @@
 import {createBrowserRouter, Navigate} from 'react-router-dom';
 import {type ComponentType, lazy, type LazyExoticComponent, Suspense} from 'react';
 import PrivateRoute from '../components/PrivateRoute';
+import {basePath} from '@/lib/utils';
 
 const LoginPage = lazy(() => import('../pages/Login'));
 const GitHubCallbackPage = lazy(() => import('../pages/Login/GitHubCallback'));
@@ -19,6 +20,7 @@ const PublicMonitorDetailPage = lazy(() => import('../pages/Public/MonitorDetail
 const MonitorListPage = lazy(() => import('../pages/Monitors/MonitorList'));
 const DDNSPage = lazy(() => import('../pages/DDNS'));
 const AlertRecordListPage = lazy(() => import('../pages/AlertRecords'));
+const LatencyMeshPage = lazy(() => import('../pages/LatencyMesh'));
 
 const LoadingFallback = () => (
     <div className="flex min-h-[200px] w-full items-center justify-center text-gray-500">
@@ -109,12 +111,18 @@ const router = createBrowserRouter([
                 path: 'alert-records',
                 element: lazyLoad(AlertRecordListPage),
             },
+            {
+                path: 'latency-mesh',
+                element: lazyLoad(LatencyMeshPage),
+            },
             {
                 path: 'settings',
                 element: lazyLoad(SettingsPage),
             },
         ],
     },
-]);
+], {
+    basename: basePath || undefined,
+});
 
 export default router;
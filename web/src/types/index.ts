@@ -23,6 +23,7 @@ export interface Agent {
     arch: string;
     version: string;
     tags?: string[];         // 标签
+    region?: string;         // 地区，服务监控按地区统计响应时间
     expireTime?: number;     // 到期时间（时间戳毫秒）
     status: number;
     visibility?: string;     // 可见性: public-匿名可见, private-登录可见
@@ -75,6 +76,32 @@ export interface AggregatedGPUMetric {
     maxPowerDraw: number;
 }
 
+export interface AggregatedCPUCoreMetric {
+    timestamp: number;
+    core: number;
+    maxUsage: number;
+    avgUsage: number;
+}
+
+export interface AggregatedCPUTimesMetric {
+    timestamp: number;
+    avgUser: number;
+    avgSystem: number;
+    avgIowait: number;
+    avgSteal: number;
+    maxSteal: number;
+    avgIdle: number;
+}
+
+export interface AggregatedPressureMetric {
+    timestamp: number;
+    resource: string;
+    maxSome: number;
+    avgSome: number;
+    maxFull: number;
+    avgFull: number;
+}
+
 export interface AggregatedTemperatureMetric {
     timestamp: number;
     sensorKey: string;
@@ -218,10 +245,41 @@ export interface MonitorHttpConfig {
     timeout?: number;
     headers?: Record<string, string>;
     body?: string;
+    auth?: MonitorHttpAuthConfig | null;
+    followRedirects?: boolean;      // 未设置时跟随重定向
+    maxRedirects?: number;          // 默认 10
+    acceptedStatusCodes?: string[]; // 支持 401、200-299、2xx，设置后代替 expectedStatusCode
+    assertions?: MonitorHttpAssertion[]; // 响应内容断言，全部通过才视为正常
+}
+
+// HTTP 响应内容断言
+export interface MonitorHttpAssertion {
+    type: 'contains' | 'not_contains' | 'regex' | 'not_regex' | 'jsonpath';
+    path?: string;        // JSONPath，如 $.data.status，仅 jsonpath 类型使用
+    operator?: 'equals' | 'not_equals' | 'contains' | 'regex' | 'exists' | 'not_exists' | 'gt' | 'lt';
+    value?: string;
+}
+
+// HTTP 监控认证配置，接口不返回密码和令牌，留空保存时沿用已保存的值
+export interface MonitorHttpAuthConfig {
+    type: 'basic' | 'bearer';
+    username?: string;
+    password?: string;
+    token?: string;
 }
 
 export interface MonitorTcpConfig {
     timeout?: number;
+    send?: string;        // 连接后发送的数据，支持 \r\n 等转义
+    expect?: string;      // 期望的响应内容
+    expectMode?: 'contains' | 'prefix' | 'regex'; // 匹配方式
+}
+
+export interface MonitorUdpConfig {
+    timeout?: number;
+    send?: string;        // 发送的数据，支持 \r\n、\x00 等转义
+    expect?: string;      // 期望的应答内容，为空时收到任意应答即视为正常
+    expectMode?: 'contains' | 'prefix' | 'regex'; // 匹配方式
 }
 
 export interface MonitorIcmpConfig {
@@ -229,10 +287,63 @@ export interface MonitorIcmpConfig {
     count?: number;
 }
 
+// 多步骤事务监控配置，前面步骤提取的变量可在后续步骤中以 {{name}} 引用
+export interface MonitorTransactionConfig {
+    timeout?: number;     // 整个事务的超时时间（秒）
+    steps: MonitorTransactionStep[];
+}
+
+export interface MonitorTransactionStep extends MonitorHttpConfig {
+    name?: string;
+    url: string;
+    extract?: MonitorTransactionExtract[];
+}
+
+export interface MonitorTransactionExtract {
+    name: string;
+    source: 'jsonpath' | 'regex' | 'header';
+    expression: string;   // JSONPath、正则表达式（有分组时取第一个分组）或响应头名称
+}
+
+// 邮件服务监控配置，密码不会返回，编辑时留空沿用已保存的值
+export interface MonitorMailConfig {
+    timeout?: number;
+    tlsMode?: 'starttls' | 'tls' | 'none'; // 加密方式，默认 STARTTLS
+    username?: string;                     // 填写后检查登录认证
+    password?: string;
+}
+
+// 维护时间窗口，期间的离线结果不计入可用率且不触发服务下线告警
+export interface MonitorMaintenanceWindow {
+    name?: string;
+    type: 'once' | 'daily' | 'weekly';
+    startAt?: number;      // 一次性窗口的开始时间（毫秒）
+    endAt?: number;        // 一次性窗口的结束时间（毫秒）
+    weekdays?: number[];   // 每周窗口的开始日，0 表示周日
+    startTime?: string;    // 每天和每周窗口的开始时间 HH:MM，结束时间早于开始时间表示跨天
+    endTime?: string;
+    timezone?: string;     // 默认服务端时区
+}
+
+// 检测时使用的出口代理，仅用于 HTTP、事务和 TCP 监控；密码不会返回，编辑时留空沿用已保存的值
+export interface MonitorProxyConfig {
+    url: string;           // http://、https:// 或 socks5:// 开头
+    username?: string;
+    password?: string;
+}
+
+export interface MonitorWebhookConfig {
+    enabled: boolean;
+    url: string;
+    method?: string;
+    headers?: Record<string, string>;
+    secret?: string;        // 配置后请求头 X-Pika-Signature 携带 HMAC-SHA256 签名
+}
+
 export interface MonitorTask {
     id: number;
     name: string;
-    type: 'http' | 'https' | 'tcp' | 'icmp' | 'ping';
+    type: 'http' | 'https' | 'tcp' | 'udp' | 'icmp' | 'ping' | 'transaction' | 'smtp' | 'imap' | 'pop3' | 'push';
     target: string;
     description?: string;
     enabled: boolean;
@@ -241,17 +352,30 @@ export interface MonitorTask {
     interval: number;
     httpConfig?: MonitorHttpConfig | null;
     tcpConfig?: MonitorTcpConfig | null;
+    udpConfig?: MonitorUdpConfig | null;
     icmpConfig?: MonitorIcmpConfig | null;
+    transactionConfig?: MonitorTransactionConfig | null;
+    mailConfig?: MonitorMailConfig | null;
+    proxy?: MonitorProxyConfig | null;   // 出口代理
+    pushToken?: string;        // 被动心跳监控的推送令牌，推送地址为 /api/push/{pushToken}
+    pushGracePeriod?: number;  // 被动心跳监控的宽限时间（秒）
+    lastPushAt?: number;       // 最后一次收到心跳的时间
+    maintenanceWindows?: MonitorMaintenanceWindow[] | null;
+    webhook?: MonitorWebhookConfig | null;   // 状态变化回调
     agentIds?: string[];
     agentNames?: string[];
     tags?: string[];       // 标签列表，拥有这些标签的探针都会执行此监控
+    group?: string;        // 监控分组，如 API、Edge
+    downQuorum?: number;   // 至少多少个探针检测离线才视为离线，0 表示所有探针都离线才视为离线
+    slaTarget?: number;    // SLA 目标可用率（百分比），0 表示未设置
+    retries?: number;      // 检测失败后的重试次数，全部失败才视为离线
     createdAt: number;
     updatedAt: number;
 }
 
 export interface MonitorTaskRequest {
     name: string;
-    type: 'http' | 'https' | 'tcp' | 'icmp' | 'ping';
+    type: 'http' | 'https' | 'tcp' | 'udp' | 'icmp' | 'ping' | 'transaction' | 'smtp' | 'imap' | 'pop3' | 'push';
     target: string;
     description?: string;
     enabled?: boolean;
@@ -260,9 +384,20 @@ export interface MonitorTaskRequest {
     interval: number;
     httpConfig?: MonitorHttpConfig | null;
     tcpConfig?: MonitorTcpConfig | null;
+    udpConfig?: MonitorUdpConfig | null;
     icmpConfig?: MonitorIcmpConfig | null;
+    transactionConfig?: MonitorTransactionConfig | null;
+    mailConfig?: MonitorMailConfig | null;
+    proxy?: MonitorProxyConfig | null;   // 出口代理
+    pushGracePeriod?: number;  // 被动心跳监控的宽限时间（秒）
+    maintenanceWindows?: MonitorMaintenanceWindow[];
+    webhook?: MonitorWebhookConfig | null;   // 状态变化回调
     agentIds?: string[];
     tags?: string[];       // 标签列表
+    group?: string;        // 监控分组
+    downQuorum?: number;   // 至少多少个探针检测离线才视为离线
+    slaTarget?: number;    // SLA 目标可用率（百分比）
+    retries?: number;      // 检测失败后的重试次数
 }
 
 export interface MonitorListResponse {
@@ -276,23 +411,55 @@ export interface MonitorListResponse {
 export interface PublicMonitor {
     id: string;
     name: string;
-    type: 'http' | 'https' | 'tcp' | 'icmp' | 'ping';
+    type: 'http' | 'https' | 'tcp' | 'udp' | 'icmp' | 'ping' | 'transaction' | 'smtp' | 'imap' | 'pop3' | 'push';
     target: string;
     showTargetPublic: boolean;
     description?: string;
     enabled: boolean;
     interval: number;
+    group?: string;          // 监控分组
     agentIds: string[];
     agentCount: number;
     lastCheckStatus: string;
     lastCheckError?: string;
     currentResponse: number;
     avgResponse24h: number;
+    p50Response24h: number;   // 响应时间分位数(ms)，多个探针时取最慢的探针
+    p95Response24h: number;
+    p99Response24h: number;
+    p50Response30d: number;
+    p95Response30d: number;
+    p99Response30d: number;
     uptime24h: number;
     uptime7d: number;
     certExpiryDate: number;
     certExpiryDays: number;
     lastCheckTime: number;
+    downQuorum?: number;
+    regions?: MonitorRegionStats[];   // 按探针地区统计的响应时间
+}
+
+// 监控项在某个地区的统计，地区取自执行检测的探针
+export interface MonitorRegionStats {
+    region: string;           // 探针未设置地区时为空
+    agentCount: number;
+    upCount: number;
+    downCount: number;
+    currentResponse: number;
+    avgResponse24h: number;   // 按成功次数加权
+    uptime24h: number;
+}
+
+// 监控分组概要，可用率取组内有检测数据的监控项的平均值
+export interface MonitorGroup {
+    name: string;
+    status: 'up' | 'degraded' | 'down' | 'unknown';
+    monitorCount: number;
+    upCount: number;
+    downCount: number;
+    uptime24h: number;
+    uptime7d: number;
+    monitorIds: string[];
 }
 
 // 监控统计数据
@@ -300,12 +467,19 @@ export interface MonitorStats {
     id: number;
     agentId: string;
     agentName?: string;           // 探针名称
+    region?: string;              // 探针所在地区
     monitorId: string;            // 监控项ID
     name: string;                 // 监控项名称
     type: string;
     target: string;
     currentResponse: number;      // 当前响应时间(ms)
     avgResponse24h: number;       // 24小时平均响应时间(ms)
+    p50Response24h: number;       // 24小时响应时间分位数(ms)
+    p95Response24h: number;
+    p99Response24h: number;
+    p50Response30d: number;       // 30天响应时间分位数(ms)
+    p95Response30d: number;
+    p99Response30d: number;
     uptime24h: number;            // 24小时在线率(百分比)
     uptime7d: number;             // 7天在线率(百分比)
     certExpiryDate: number;       // 证书过期时间(毫秒时间戳)
@@ -320,10 +494,80 @@ export interface MonitorStats {
     updatedAt: number;            // 更新时间
 }
 
+// 监控目标的证书详情（各探针分别检测）
+export interface MonitorCert {
+    id: string;
+    agentId: string;
+    agentName?: string;           // 探针名称
+    monitorId: string;
+    subject: string;              // 使用者
+    issuer: string;               // 签发者
+    sans: string[];               // 备用名称
+    signatureAlgorithm: string;   // 签名算法
+    notBefore: number;            // 生效时间(毫秒时间戳)
+    notAfter: number;             // 过期时间(毫秒时间戳)
+    fingerprint: string;          // SHA-256 指纹
+    chainLength: number;          // 服务器返回的证书数量
+    chainValid: boolean;          // 证书链是否校验通过
+    chainError: string;           // 证书链校验失败原因
+    updatedAt: number;
+}
+
+// 路径追踪的一跳
+export interface TraceHop {
+    hop: number;
+    host?: string;                // 无响应时为空
+    loss: number;                 // 丢包率(百分比)
+    avgRtt?: number;              // 平均往返时间(ms)
+}
+
+// 监控项变为离线时探针执行的路径追踪
+export interface MonitorTrace {
+    id: number;
+    agentId: string;
+    agentName?: string;
+    monitorId: string;
+    host: string;                 // 追踪的目标主机
+    tool: string;                 // mtr 或 traceroute
+    checkedAt: number;            // 离线检测的时间
+    checkError: string;           // 离线检测的错误信息
+    hops: TraceHop[];
+    error: string;                // 追踪失败原因
+    createdAt: number;
+}
+
+// 单个探针在统计月份内的可用率
+export interface MonitorSLAAgent {
+    agentId: string;
+    agentName: string;
+    uptime: number;
+    totalChecks: number;
+    successChecks: number;
+}
+
+// 监控项某个自然月的 SLA 达成情况，按检测次数计算，维护期间的离线结果不计入
+export interface MonitorSLAReport {
+    monitorId: string;
+    month: string;                // 统计月份，如 2025-03
+    start: number;
+    end: number;                  // 当月统计到当前时间
+    target: number;               // SLA 目标(百分比)，0 表示未设置
+    uptime: number;
+    totalChecks: number;
+    successChecks: number;
+    compliant: boolean;
+    downtimeMinutes: number;      // 不可用时长（分钟）
+    errorBudgetMinutes: number;   // 整月允许的不可用时长（分钟）
+    errorBudgetRemaining: number; // 剩余错误预算（分钟），超出时为负数
+    errorBudgetPercent: number;   // 剩余错误预算的比例(百分比)
+    agents: MonitorSLAAgent[];
+}
+
 // 监控指标（原始数据，用于图表）
 export interface MonitorMetric {
     id: number;
     agentId: string;
+    monitorId: string;
     name: string;
     type: string;
     target: string;
@@ -333,8 +577,10 @@ export interface MonitorMetric {
     error: string;
     message: string;
     contentMatch: boolean;
+    contentDetail: string;        // 内容断言的逐条结果
     certExpiryTime: number;
     certDaysLeft: number;
+    checks: number;               // 合并的连续检测次数，状态不变时多次检测合并保存
     timestamp: number;
 }
 
@@ -377,6 +623,8 @@ export interface NetworkConnectionMetric {
 
 export interface LatestMetrics {
     cpu?: CPUMetric;
+    cpuPerCore?: number[];    // 每核 CPU 使用率（需在指标配置中开启）
+    cpuTimes?: CPUTimes;      // CPU 时间分布
     memory?: MemoryMetric;
     disk?: DiskSummary;       // 改为汇总数据
     network?: NetworkSummary; // 改为汇总数据
@@ -384,6 +632,112 @@ export interface LatestMetrics {
     host?: HostMetric;        // 主机信息
     gpu?: GPUMetric[];        // GPU 列表
     temperature?: TemperatureMetric[];  // 温度传感器列表
+    collectors?: CollectorHealth[];     // 采集器健康状态
+    mounts?: MountStatus[];             // 网络挂载点状态
+    fileUsage?: FileUsage;              // 文件描述符和 inode 使用情况
+    timeSync?: TimeSync;                // 时钟偏差
+    listeningPorts?: ListeningPort[];   // 监听端口
+    custom?: CustomMetric[];            // 自定义指标
+    pressure?: Pressure;                // PSI 压力（仅 Linux）
+}
+
+// PSI 停顿时间占比（%）
+export interface PressureAvg {
+    avg10: number;
+    avg60: number;
+    avg300: number;
+    total: number;      // 累计停顿时间（微秒）
+}
+
+export interface PressureStat {
+    some: PressureAvg;
+    full?: PressureAvg;
+}
+
+// PSI 压力（Linux /proc/pressure）
+export interface Pressure {
+    cpu?: PressureStat;
+    memory?: PressureStat;
+    io?: PressureStat;
+}
+
+// 自定义指标（探针配置的命令或文本文件）
+export interface CustomMetric {
+    name: string;
+    labels?: Record<string, string>;
+    value: number;
+    source: string;     // 命令名称或文本文件名
+}
+
+// 监听端口
+export interface ListeningPort {
+    protocol: 'tcp' | 'udp';
+    address: string;
+    port: number;
+    processPid: number;
+    processName?: string;
+    isPublic: boolean;  // 是否监听在非回环地址上
+}
+
+// CPU 时间分布（两次采集之间各状态占比，%）
+export interface CPUTimes {
+    user: number;
+    system: number;
+    iowait: number;
+    steal: number;
+    idle: number;
+}
+
+// 时钟偏差（探针查询 NTP 服务器得到）
+export interface TimeSync {
+    server: string;
+    offset: number;     // 本机时钟偏差（毫秒），正数表示本机慢
+    rtt: number;        // 往返时延（毫秒）
+    stratum: number;
+    error?: string;
+    checkedAt: number;
+}
+
+// 文件描述符和 inode 使用情况
+export interface FileUsage {
+    fileDescriptors?: {
+        allocated: number;
+        max: number;
+        usagePercent: number;
+    };
+    inodes?: InodeUsage[];
+}
+
+export interface InodeUsage {
+    mountPoint: string;
+    device: string;
+    fstype: string;
+    total: number;
+    used: number;
+    free: number;
+    usagePercent: number;
+}
+
+// 网络挂载点（NFS/SMB 等）健康状态
+export interface MountStatus {
+    mountPoint: string;
+    device?: string;
+    fstype?: string;
+    status: 'ok' | 'hung' | 'stale' | 'unmounted' | 'error';
+    latency: number;    // 探测耗时（毫秒）
+    error?: string;
+}
+
+// 探针采集器健康状态
+export interface CollectorHealth {
+    name: string;
+    status: 'ok' | 'error' | 'stuck';
+    lastDuration: number;   // 最近一次采集耗时（毫秒）
+    lastSuccessAt?: number;
+    lastError?: string;
+    stuckSince?: number;    // 卡住的采集开始时间
+    timeouts: number;
+    restarts: number;
 }
 
 // API Key 相关
@@ -421,16 +775,56 @@ export interface AlertRules {
     networkDuration: number;
     certEnabled: boolean;      // HTTPS 证书告警开关
     certThreshold: number;     // 证书剩余天数阈值（天）
+    certChainEnabled: boolean; // 证书链校验失败告警开关
     serviceEnabled: boolean;   // 服务下线告警开关
     serviceDuration: number;   // 服务下线持续时间（秒）
     agentOfflineEnabled: boolean;   // 探针离线告警开关
     agentOfflineDuration: number;   // 探针离线持续时间（秒）
+    wireGuardEnabled: boolean;   // WireGuard 告警开关
+    wireGuardHandshakeThreshold: number;   // WireGuard 对端未握手时长阈值（秒）
+    mountEnabled: boolean;   // 网络挂载点告警开关
+    mountDuration: number;   // 挂载点异常持续时间（秒）
+    inodeEnabled: boolean;   // inode 使用率告警开关
+    inodeThreshold: number;  // inode 使用率阈值（%）
+    inodeDuration: number;
+    fdEnabled: boolean;      // 文件描述符使用率告警开关
+    fdThreshold: number;     // 文件描述符使用率阈值（%）
+    fdDuration: number;
+    stealEnabled: boolean;   // CPU steal 告警开关
+    stealThreshold: number;  // steal 占比阈值（%）
+    stealDuration: number;
+    clockEnabled: boolean;   // 时钟偏差告警开关
+    clockThreshold: number;  // 时钟偏差阈值（毫秒）
+    clockDuration: number;
+    portEnabled: boolean;    // 非预期监听端口告警开关
+    portAllowed: string[];   // 允许的端口，如 22、tcp/443、udp/53
+    portDuration: number;    // 端口持续监听多久后触发告警（秒）
+    cooldowns?: Record<string, number>;   // 各告警类型恢复后的冷却期（秒）
 }
 
 // 全局告警配置（现在存储在 Property 中）
+export interface GroupAlertRule {
+    enabled: boolean;
+    tag: string;
+    metric: 'cpu' | 'memory' | 'disk' | 'network';
+    aggregation: 'avg' | 'max' | 'min' | 'sum';
+    threshold: number;
+    duration: number;
+}
+
+// 监控分组告警规则：组内离线的监控项超过允许数量时告警
+export interface MonitorGroupAlertRule {
+    enabled: boolean;
+    group: string;
+    maxDown: number;     // 允许同时离线的监控项数量
+    duration: number;
+}
+
 export interface AlertConfig {
     enabled: boolean;  // 全局告警开关
     rules: AlertRules;
+    groupRules?: GroupAlertRule[];
+    monitorGroupRules?: MonitorGroupAlertRule[];
 }
 
 export interface AlertRecord {
@@ -447,6 +841,7 @@ export interface AlertRecord {
     status: string;
     firedAt: number;
     resolvedAt?: number;
+    refiredAfter?: number;  // 冷却期内再次触发时距上次恢复的秒数
     createdAt: number;
     updatedAt: number;
 }
@@ -5,6 +5,8 @@ import path from "path";
 
 // https://vite.dev/config/
 export default defineConfig({
+    // 使用相对路径构建，部署在子路径下时由服务端改写 index.html 中的资源地址
+    base: './',
     plugins: [
         react(),
         tailwindcss(),
@@ -20,6 +22,11 @@ export default defineConfig({
                 target: 'http://localhost:18888/',
                 changeOrigin: true,
             },
+            // 远程终端的 WebSocket，保留 Host 以通过服务端的同源校验
+            '/ws/': {
+                target: 'http://localhost:18888/',
+                ws: true,
+            },
         },
     },
 })